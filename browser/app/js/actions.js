@@ -110,7 +110,7 @@ export const shareObject = (object, days, hours, minutes) => (dispatch, getState
   }
 
   let expiry = days * 24 * 60 * 60 + hours * 60 * 60 + minutes * 60
-  web.PresignedGet({
+  web.CreateShareLink({
     host,
     bucket,
     object,
@@ -93,6 +93,15 @@ export default class Web {
   PresignedGet(args) {
     return this.makeCall('PresignedGet', args)
   }
+  CreateShareLink(args) {
+    return this.makeCall('CreateShareLink', args)
+  }
+  ListShareLinks(args) {
+    return this.makeCall('ListShareLinks', args)
+  }
+  RevokeShareLink(args) {
+    return this.makeCall('RevokeShareLink', args)
+  }
   PutObjectURL(args) {
     return this.makeCall('PutObjectURL', args)
   }
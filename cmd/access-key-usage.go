@@ -0,0 +1,424 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// New networks are reported only for access keys in use for
+	// at least this long, until then networks are learned silently.
+	accessKeyLearningPeriod = 24 * time.Hour
+
+	// Deletes made with an access key in a minute are reported as a
+	// spike when they exceed both the minimum and the factor times
+	// the moving average of deletes per minute.
+	accessKeyDeleteSpikeMin    = 100
+	accessKeyDeleteSpikeFactor = 10
+
+	// Weight of the last minute in the moving average of deletes.
+	accessKeyDeleteAvgWeight = 0.1
+
+	// Maximum number of source IPs kept per access key.
+	maxAccessKeySourceIPs = 100
+
+	// Maximum number of networks learned per access key, new
+	// networks are not reported once the limit is reached.
+	maxAccessKeyNetworks = 4096
+
+	// Maximum number of most recent alerts kept on a node.
+	maxAccessKeyAlerts = 100
+)
+
+// Types of access key alerts.
+const (
+	accessKeyAlertNewNetwork  = "new-network"
+	accessKeyAlertDeleteSpike = "delete-spike"
+)
+
+// errAccessKeyAnomaly - logged along with every access key alert.
+var errAccessKeyAnomaly = errors.New("unusual access key usage detected")
+
+// accessKeyStats - requests made with an access key on a node since
+// the server started.
+type accessKeyStats struct {
+	// Number of requests indexed by S3 API name.
+	Requests map[string]int64 `json:"requests"`
+	// Number of requests which failed with a 4xx or 5xx status.
+	Errors int64 `json:"errors"`
+	// Number of requests indexed by source IP.
+	SourceIPs map[string]int64 `json:"sourceIPs"`
+	FirstSeen time.Time        `json:"firstSeen"`
+	LastSeen  time.Time        `json:"lastSeen"`
+}
+
+// merge - adds stats of the same access key from another node.
+func (s *accessKeyStats) merge(other accessKeyStats) {
+	if s.Requests == nil {
+		s.Requests = make(map[string]int64)
+	}
+	if s.SourceIPs == nil {
+		s.SourceIPs = make(map[string]int64)
+	}
+	for api, count := range other.Requests {
+		s.Requests[api] += count
+	}
+	for ip, count := range other.SourceIPs {
+		s.SourceIPs[ip] += count
+	}
+	s.Errors += other.Errors
+	if s.FirstSeen.IsZero() || (!other.FirstSeen.IsZero() && other.FirstSeen.Before(s.FirstSeen)) {
+		s.FirstSeen = other.FirstSeen
+	}
+	if other.LastSeen.After(s.LastSeen) {
+		s.LastSeen = other.LastSeen
+	}
+}
+
+// accessKeyAlert - unusual usage of an access key.
+type accessKeyAlert struct {
+	Time      time.Time `json:"time"`
+	Node      string    `json:"node,omitempty"`
+	AccessKey string    `json:"accessKey"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+}
+
+// accessKeyAlerts - sorts alerts by time.
+type accessKeyAlerts []accessKeyAlert
+
+func (a accessKeyAlerts) Len() int           { return len(a) }
+func (a accessKeyAlerts) Less(i, j int) bool { return a[i].Time.Before(a[j].Time) }
+func (a accessKeyAlerts) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// accessKeyUsageInfo - stats of all access keys along with the most
+// recent alerts.
+type accessKeyUsageInfo struct {
+	Keys   map[string]accessKeyStats `json:"keys"`
+	Alerts []accessKeyAlert          `json:"alerts"`
+}
+
+// merge - adds usage of another node, alerts are kept sorted by time.
+func (u *accessKeyUsageInfo) merge(other accessKeyUsageInfo) {
+	if u.Keys == nil {
+		u.Keys = make(map[string]accessKeyStats)
+	}
+	for accessKey, stats := range other.Keys {
+		merged := u.Keys[accessKey]
+		merged.merge(stats)
+		u.Keys[accessKey] = merged
+	}
+	u.Alerts = append(u.Alerts, other.Alerts...)
+	sort.Sort(accessKeyAlerts(u.Alerts))
+}
+
+// accessKeyActivity - stats of an access key along with the state
+// needed to detect anomalies.
+type accessKeyActivity struct {
+	stats accessKeyStats
+
+	// Networks the access key was used from.
+	networks map[string]struct{}
+
+	// Deletes made in the current minute, the moving average of
+	// previous minutes and the resulting spike limit.
+	deleteMinute int64
+	deletes      int64
+	deleteAvg    float64
+	deleteLimit  int64
+}
+
+// rotateDeletes - folds deletes of elapsed minutes into the moving
+// average and recomputes the spike limit.
+func (a *accessKeyActivity) rotateDeletes(now time.Time) {
+	minute := now.Unix() / 60
+	if minute == a.deleteMinute {
+		return
+	}
+	elapsed := minute - a.deleteMinute
+	a.deleteAvg += accessKeyDeleteAvgWeight * (float64(a.deletes) - a.deleteAvg)
+	// Minutes without any request count as minutes without deletes,
+	// a few hours of inactivity are enough to forget the average.
+	for i := int64(1); i < elapsed && i < 24*60; i++ {
+		a.deleteAvg *= 1 - accessKeyDeleteAvgWeight
+	}
+	a.deleteMinute = minute
+	a.deletes = 0
+	a.deleteLimit = int64(a.deleteAvg * accessKeyDeleteSpikeFactor)
+	if a.deleteLimit < accessKeyDeleteSpikeMin {
+		a.deleteLimit = accessKeyDeleteSpikeMin
+	}
+}
+
+// accessKeyTracker - keeps usage of access keys on this node.
+type accessKeyTracker struct {
+	mu     sync.Mutex
+	keys   map[string]*accessKeyActivity
+	alerts []accessKeyAlert
+}
+
+// newAccessKeyTracker - initialize a new access key tracker.
+func newAccessKeyTracker() *accessKeyTracker {
+	return &accessKeyTracker{
+		keys: make(map[string]*accessKeyActivity),
+	}
+}
+
+// Global access key tracker.
+var globalAccessKeyTracker = newAccessKeyTracker()
+
+// getIPNetwork - returns the /24 network of an IPv4 address or the
+// /64 network of an IPv6 address, empty if ip is not valid.
+func getIPNetwork(ip string) string {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return ""
+	}
+	if ipv4 := parsedIP.To4(); ipv4 != nil {
+		return (&net.IPNet{IP: ipv4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsedIP.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// isDeleteAPI - returns true for APIs removing objects.
+func isDeleteAPI(api string) bool {
+	return api == "DeleteObject" || api == "DeleteMultipleObjects"
+}
+
+// record - accounts a request made with an access key, returns the
+// alerts raised by the request when detect is true.
+func (t *accessKeyTracker) record(accessKey, api, sourceIP string, failed, detect bool, now time.Time) []accessKeyAlert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	activity, ok := t.keys[accessKey]
+	if !ok {
+		activity = &accessKeyActivity{
+			stats: accessKeyStats{
+				Requests:  make(map[string]int64),
+				SourceIPs: make(map[string]int64),
+				FirstSeen: now,
+			},
+			networks: make(map[string]struct{}),
+		}
+		t.keys[accessKey] = activity
+	}
+
+	stats := &activity.stats
+	stats.Requests[api]++
+	if failed {
+		stats.Errors++
+	}
+	if _, ok = stats.SourceIPs[sourceIP]; ok || len(stats.SourceIPs) < maxAccessKeySourceIPs {
+		stats.SourceIPs[sourceIP]++
+	}
+	stats.LastSeen = now
+
+	var alerts []accessKeyAlert
+	if network := getIPNetwork(sourceIP); network != "" {
+		if _, ok = activity.networks[network]; !ok && len(activity.networks) < maxAccessKeyNetworks {
+			activity.networks[network] = struct{}{}
+			if now.Sub(stats.FirstSeen) >= accessKeyLearningPeriod {
+				alerts = append(alerts, accessKeyAlert{
+					Time:      now,
+					AccessKey: accessKey,
+					Type:      accessKeyAlertNewNetwork,
+					Message:   fmt.Sprintf("First request from network %s (%s)", network, sourceIP),
+				})
+			}
+		}
+	}
+
+	activity.rotateDeletes(now)
+	if isDeleteAPI(api) {
+		activity.deletes++
+		// Reported once, when the limit is first exceeded in a minute.
+		if activity.deletes == activity.deleteLimit+1 {
+			alerts = append(alerts, accessKeyAlert{
+				Time:      now,
+				AccessKey: accessKey,
+				Type:      accessKeyAlertDeleteSpike,
+				Message:   fmt.Sprintf("More than %d delete requests in a minute", activity.deleteLimit),
+			})
+		}
+	}
+
+	if !detect {
+		return nil
+	}
+	t.alerts = append(t.alerts, alerts...)
+	if len(t.alerts) > maxAccessKeyAlerts {
+		t.alerts = t.alerts[len(t.alerts)-maxAccessKeyAlerts:]
+	}
+	return alerts
+}
+
+// getUsage - returns stats of all access keys used on this node
+// along with the most recent alerts.
+func (t *accessKeyTracker) getUsage() accessKeyUsageInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := accessKeyUsageInfo{
+		Keys:   make(map[string]accessKeyStats, len(t.keys)),
+		Alerts: append([]accessKeyAlert{}, t.alerts...),
+	}
+	for accessKey, activity := range t.keys {
+		stats := activity.stats
+		stats.Requests = make(map[string]int64, len(activity.stats.Requests))
+		for api, count := range activity.stats.Requests {
+			stats.Requests[api] = count
+		}
+		stats.SourceIPs = make(map[string]int64, len(activity.stats.SourceIPs))
+		for ip, count := range activity.stats.SourceIPs {
+			stats.SourceIPs[ip] = count
+		}
+		usage.Keys[accessKey] = stats
+	}
+	return usage
+}
+
+// hasQuery - returns true if query parameter is present in request.
+func hasQuery(r *http.Request, name string) bool {
+	_, ok := r.URL.Query()[name]
+	return ok
+}
+
+// getS3APIName - returns the name of the S3 API called by a request.
+func getS3APIName(r *http.Request) string {
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	isCopy := r.Header.Get("X-Amz-Copy-Source") != ""
+	switch {
+	case bucket == "":
+		if r.Method == httpGET {
+			return "ListBuckets"
+		}
+	case object == "":
+		switch r.Method {
+		case httpGET:
+			switch {
+			case hasQuery(r, "location"):
+				return "GetBucketLocation"
+			case hasQuery(r, "policy"):
+				return "GetBucketPolicy"
+			case hasQuery(r, "notification"):
+				return "GetBucketNotification"
+			case hasQuery(r, "uploads"):
+				return "ListMultipartUploads"
+			case r.URL.Query().Get("list-type") == "2":
+				return "ListObjectsV2"
+			}
+			return "ListObjects"
+		case httpHEAD:
+			return "HeadBucket"
+		case httpPUT:
+			switch {
+			case hasQuery(r, "policy"):
+				return "PutBucketPolicy"
+			case hasQuery(r, "notification"):
+				return "PutBucketNotification"
+			}
+			return "PutBucket"
+		case httpPOST:
+			if hasQuery(r, "delete") {
+				return "DeleteMultipleObjects"
+			}
+			return "PostPolicy"
+		case httpDELETE:
+			if hasQuery(r, "policy") {
+				return "DeleteBucketPolicy"
+			}
+			return "DeleteBucket"
+		}
+	default:
+		switch r.Method {
+		case httpGET:
+			if hasQuery(r, "uploadId") {
+				return "ListObjectParts"
+			}
+			return "GetObject"
+		case httpHEAD:
+			return "HeadObject"
+		case httpPUT:
+			switch {
+			case hasQuery(r, "uploadId") && isCopy:
+				return "CopyObjectPart"
+			case hasQuery(r, "uploadId"):
+				return "PutObjectPart"
+			case isCopy:
+				return "CopyObject"
+			}
+			return "PutObject"
+		case httpPOST:
+			if hasQuery(r, "uploads") {
+				return "NewMultipartUpload"
+			}
+			return "CompleteMultipartUpload"
+		case httpDELETE:
+			if hasQuery(r, "uploadId") {
+				return "AbortMultipartUpload"
+			}
+			return "DeleteObject"
+		}
+	}
+	return "Unknown"
+}
+
+// accessKeyUsageHandler records usage of access keys signing S3
+// requests and reports anomalies when enabled.
+type accessKeyUsageHandler struct {
+	handler http.Handler
+	tracker *accessKeyTracker
+}
+
+// setAccessKeyUsageHandler - tracks APIs, source IPs and errors of
+// requests per access key.
+func setAccessKeyUsageHandler(h http.Handler) http.Handler {
+	return accessKeyUsageHandler{handler: h, tracker: globalAccessKeyTracker}
+}
+
+func (h accessKeyUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketName, _ := urlPath2BucketObjectName(r.URL)
+	// Admin, browser and inter-node requests are not tracked.
+	if r.Header.Get(minioAdminOpHeader) != "" || isMinioReservedBucket(bucketName) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	accessKey := getRequestAccessKey(r)
+	if accessKey == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	ww := &httpResponseRecorder{ResponseWriter: w}
+	h.handler.ServeHTTP(ww, r)
+
+	sourceIP := getSourceIP(r)
+	failed := ww.respStatusCode >= http.StatusBadRequest
+	alerts := h.tracker.record(accessKey, getS3APIName(r), sourceIP, failed, globalIsAccessKeyAlertsEnabled, UTCNow())
+	for _, alert := range alerts {
+		errorIf(errAccessKeyAnomaly, "Access key %s: %s.", alert.AccessKey, alert.Message)
+	}
+}
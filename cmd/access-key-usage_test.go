@@ -0,0 +1,285 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetIPNetwork(t *testing.T) {
+	testCases := []struct {
+		ip      string
+		network string
+	}{
+		{"192.168.1.10", "192.168.1.0/24"},
+		{"10.0.0.255", "10.0.0.0/24"},
+		{"2001:db8:1:2:3:4:5:6", "2001:db8:1:2::/64"},
+		{"invalid", ""},
+		{"", ""},
+	}
+	for i, testCase := range testCases {
+		if network := getIPNetwork(testCase.ip); network != testCase.network {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.network, network)
+		}
+	}
+}
+
+func TestGetS3APIName(t *testing.T) {
+	testCases := []struct {
+		method string
+		url    string
+		copy   bool
+		api    string
+	}{
+		{"GET", "/", false, "ListBuckets"},
+		{"GET", "/bucket", false, "ListObjects"},
+		{"GET", "/bucket?list-type=2", false, "ListObjectsV2"},
+		{"GET", "/bucket?location", false, "GetBucketLocation"},
+		{"GET", "/bucket?uploads", false, "ListMultipartUploads"},
+		{"PUT", "/bucket", false, "PutBucket"},
+		{"PUT", "/bucket?policy", false, "PutBucketPolicy"},
+		{"POST", "/bucket?delete", false, "DeleteMultipleObjects"},
+		{"DELETE", "/bucket", false, "DeleteBucket"},
+		{"HEAD", "/bucket", false, "HeadBucket"},
+		{"GET", "/bucket/object", false, "GetObject"},
+		{"HEAD", "/bucket/object", false, "HeadObject"},
+		{"PUT", "/bucket/object", false, "PutObject"},
+		{"PUT", "/bucket/object", true, "CopyObject"},
+		{"PUT", "/bucket/object?uploadId=id&partNumber=1", false, "PutObjectPart"},
+		{"PUT", "/bucket/object?uploadId=id&partNumber=1", true, "CopyObjectPart"},
+		{"POST", "/bucket/object?uploads", false, "NewMultipartUpload"},
+		{"POST", "/bucket/object?uploadId=id", false, "CompleteMultipartUpload"},
+		{"DELETE", "/bucket/object", false, "DeleteObject"},
+		{"DELETE", "/bucket/object?uploadId=id", false, "AbortMultipartUpload"},
+		{"PUT", "/", false, "Unknown"},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.copy {
+			req.Header.Set("X-Amz-Copy-Source", "/src/object")
+		}
+		if api := getS3APIName(req); api != testCase.api {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.api, api)
+		}
+	}
+}
+
+func TestAccessKeyTrackerStats(t *testing.T) {
+	tracker := newAccessKeyTracker()
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	tracker.record("key1", "GetObject", "10.0.0.1", false, true, now)
+	tracker.record("key1", "GetObject", "10.0.0.2", true, true, now.Add(time.Second))
+	tracker.record("key1", "PutObject", "10.0.0.1", false, true, now.Add(2*time.Second))
+	tracker.record("key2", "ListBuckets", "10.0.1.1", false, true, now)
+
+	usage := tracker.getUsage()
+	stats := usage.Keys["key1"]
+	if stats.Requests["GetObject"] != 2 || stats.Requests["PutObject"] != 1 {
+		t.Fatalf("Unexpected requests %v", stats.Requests)
+	}
+	if stats.Errors != 1 {
+		t.Fatalf("Expected 1 error, got %d", stats.Errors)
+	}
+	if stats.SourceIPs["10.0.0.1"] != 2 || stats.SourceIPs["10.0.0.2"] != 1 {
+		t.Fatalf("Unexpected source IPs %v", stats.SourceIPs)
+	}
+	if !stats.FirstSeen.Equal(now) || !stats.LastSeen.Equal(now.Add(2*time.Second)) {
+		t.Fatalf("Unexpected first/last seen %s/%s", stats.FirstSeen, stats.LastSeen)
+	}
+	if len(usage.Keys) != 2 || len(usage.Alerts) != 0 {
+		t.Fatalf("Unexpected usage %#v", usage)
+	}
+
+	// Returned stats must not change with later requests.
+	tracker.record("key1", "GetObject", "10.0.0.1", false, true, now)
+	if stats.Requests["GetObject"] != 2 {
+		t.Fatal("Returned stats are shared with the tracker")
+	}
+}
+
+func TestAccessKeyTrackerNewNetwork(t *testing.T) {
+	tracker := newAccessKeyTracker()
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Networks are learned silently during the learning period.
+	if alerts := tracker.record("key", "GetObject", "10.0.0.1", false, true, now); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	if alerts := tracker.record("key", "GetObject", "10.0.1.1", false, true, now.Add(time.Hour)); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+
+	later := now.Add(accessKeyLearningPeriod)
+	// Known networks are not reported.
+	if alerts := tracker.record("key", "GetObject", "10.0.1.200", false, true, later); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	alerts := tracker.record("key", "GetObject", "172.16.0.1", false, true, later)
+	if len(alerts) != 1 || alerts[0].Type != accessKeyAlertNewNetwork || alerts[0].AccessKey != "key" {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	// Reported only once.
+	if alerts = tracker.record("key", "GetObject", "172.16.0.2", false, true, later); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	// Not reported when alerts are disabled.
+	if alerts = tracker.record("key", "GetObject", "172.16.1.1", false, false, later); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	if usage := tracker.getUsage(); len(usage.Alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(usage.Alerts))
+	}
+}
+
+func TestAccessKeyTrackerDeleteSpike(t *testing.T) {
+	tracker := newAccessKeyTracker()
+	now := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	deleteN := func(n int, at time.Time) (alerts []accessKeyAlert) {
+		for i := 0; i < n; i++ {
+			alerts = append(alerts, tracker.record("key", "DeleteObject", "10.0.0.1", false, true, at)...)
+		}
+		return alerts
+	}
+
+	// Up to the minimum deletes per minute are never reported.
+	if alerts := deleteN(accessKeyDeleteSpikeMin, now); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	// Exceeding it is reported once per minute.
+	alerts := deleteN(10, now)
+	if len(alerts) != 1 || alerts[0].Type != accessKeyAlertDeleteSpike {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+
+	// A steady high rate raises the limit over time.
+	at := now
+	for i := 0; i < 60; i++ {
+		at = at.Add(time.Minute)
+		deleteN(150, at)
+	}
+	if alerts = deleteN(150, at.Add(time.Minute)); len(alerts) != 0 {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	if alerts = deleteN(2000, at.Add(2*time.Minute)); len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %v", alerts)
+	}
+}
+
+func TestAccessKeyUsageInfoMerge(t *testing.T) {
+	t1 := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	var usage accessKeyUsageInfo
+	usage.merge(accessKeyUsageInfo{
+		Keys: map[string]accessKeyStats{
+			"key": {Requests: map[string]int64{"GetObject": 1}, Errors: 1, SourceIPs: map[string]int64{"10.0.0.1": 1}, FirstSeen: t2, LastSeen: t2},
+		},
+		Alerts: []accessKeyAlert{{Time: t2, AccessKey: "key"}},
+	})
+	usage.merge(accessKeyUsageInfo{
+		Keys: map[string]accessKeyStats{
+			"key": {Requests: map[string]int64{"GetObject": 2, "PutObject": 1}, SourceIPs: map[string]int64{"10.0.0.1": 3}, FirstSeen: t1, LastSeen: t1},
+		},
+		Alerts: []accessKeyAlert{{Time: t1, AccessKey: "key"}},
+	})
+
+	stats := usage.Keys["key"]
+	if stats.Requests["GetObject"] != 3 || stats.Requests["PutObject"] != 1 || stats.Errors != 1 || stats.SourceIPs["10.0.0.1"] != 4 {
+		t.Fatalf("Unexpected stats %#v", stats)
+	}
+	if !stats.FirstSeen.Equal(t1) || !stats.LastSeen.Equal(t2) {
+		t.Fatalf("Unexpected first/last seen %s/%s", stats.FirstSeen, stats.LastSeen)
+	}
+	if len(usage.Alerts) != 2 || !usage.Alerts[0].Time.Equal(t1) {
+		t.Fatalf("Alerts are not sorted by time %v", usage.Alerts)
+	}
+}
+
+func TestAccessKeyUsageHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() { globalAccessKeyTracker = newAccessKeyTracker() }()
+
+	globalAccessKeyTracker = newAccessKeyTracker()
+	initGlobalAdminPeers(mustGetNewEndpointList("http://127.0.0.1:9000/d1"))
+
+	cred := serverConfig.GetCredential()
+	handler := setAccessKeyUsageHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == httpDELETE {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("data"))
+	}))
+
+	testCases := []struct {
+		method string
+		url    string
+		signed bool
+	}{
+		{"GET", "http://localhost:9000/bucket/object", true},
+		{"DELETE", "http://localhost:9000/bucket/object", true},
+		// Anonymous and browser requests are not tracked.
+		{"GET", "http://localhost:9000/bucket/object", false},
+		{"GET", "http://localhost:9000" + minioReservedBucketPath + "/index.html", true},
+	}
+	for i, testCase := range testCases {
+		var req *http.Request
+		if testCase.signed {
+			req, err = newTestSignedRequestV4(testCase.method, testCase.url, 0, nil, cred.AccessKey, cred.SecretKey)
+		} else {
+			req, err = newTestRequest(testCase.method, testCase.url, 0, nil)
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		req.RemoteAddr = "10.0.0.1:1234"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req, err := newTestSignedRequestV4("GET", "http://localhost:9000/?access-key", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	adminAPIHandlers{}.AccessKeyUsageHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var usage accessKeyUsageInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &usage); err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := usage.Keys[cred.AccessKey]
+	if !ok || len(usage.Keys) != 1 {
+		t.Fatalf("Unexpected access key usage %#v", usage)
+	}
+	if stats.Requests["GetObject"] != 1 || stats.Requests["DeleteObject"] != 1 || stats.Errors != 1 || stats.SourceIPs["10.0.0.1"] != 2 {
+		t.Fatalf("Unexpected stats %#v", stats)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -52,6 +53,8 @@ const (
 	mgmtUploadIDMarker mgmtQueryKey = "upload-id-marker"
 	mgmtMaxUploads     mgmtQueryKey = "max-uploads"
 	mgmtUploadID       mgmtQueryKey = "upload-id"
+	mgmtSource         mgmtQueryKey = "source"
+	mgmtProfileType    mgmtQueryKey = "type"
 )
 
 // ServerVersion - server version
@@ -62,17 +65,21 @@ type ServerVersion struct {
 
 // ServerStatus - contains the response of service status API
 type ServerStatus struct {
-	ServerVersion ServerVersion `json:"serverVersion"`
-	Uptime        time.Duration `json:"uptime"`
+	ServerVersion ServerVersion       `json:"serverVersion"`
+	Uptime        time.Duration       `json:"uptime"`
+	Versions      []ServerNodeVersion `json:"versions"`
+	APIStats      map[string]APIStats `json:"apiStats"`
 }
 
 // ServiceStatusHandler - GET /?service
 // HTTP header x-minio-operation: status
 // ----------
 // Fetches server status information like total disk space available
-// to use, online disks, offline disks and quorum threshold.
+// to use, online disks, offline disks and quorum threshold, along with
+// latency histograms and in-flight counts of the S3 APIs served by
+// the replying server.
 func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -90,10 +97,13 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	// Create API response
+	// Create API response along with versions of all servers,
+	// which differ in mixed version clusters.
 	serverStatus := ServerStatus{
 		ServerVersion: serverVersion,
 		Uptime:        uptime,
+		Versions:      getPeerVersions(globalAdminPeers),
+		APIStats:      globalAPIStats.toAPIStats(),
 	}
 
 	// Marshal API response
@@ -114,7 +124,7 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 // Restarts minio server gracefully. In a distributed setup,  restarts
 // all the servers in the cluster.
 func (adminAPI adminAPIHandlers) ServiceRestartHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -126,6 +136,24 @@ func (adminAPI adminAPIHandlers) ServiceRestartHandler(w http.ResponseWriter, r
 	sendServiceCmd(globalAdminPeers, serviceRestart)
 }
 
+// ServiceStopHandler - POST /?service
+// HTTP header x-minio-operation: stop
+// ----------
+// Shuts down minio server gracefully. In a distributed setup, shuts
+// down all the servers in the cluster.
+func (adminAPI adminAPIHandlers) ServiceStopHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	// Reply to the client before shutting down minio server.
+	writeSuccessResponseHeadersOnly(w)
+
+	sendServiceCmd(globalAdminPeers, serviceStop)
+}
+
 // setCredsReq request
 type setCredsReq struct {
 	Username string `xml:"username"`
@@ -139,7 +167,7 @@ type setCredsReq struct {
 // in the cluster.
 func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter, r *http.Request) {
 	// Authenticate request
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -199,6 +227,10 @@ type ServerProperties struct {
 	CommitID string        `json:"commitID"`
 	Region   string        `json:"region"`
 	SQSARN   []string      `json:"sqsARN"`
+	Mode     string        `json:"mode"`
+
+	// Disks missing since startup while writes are rejected.
+	MissingDisks []string `json:"missingDisks,omitempty"`
 }
 
 // ServerConnStats holds transferred bytes from/to the server
@@ -230,6 +262,15 @@ type ServerHTTPStats struct {
 	SuccessDELETEStats ServerHTTPMethodStats `json:"successDELETEs"`
 }
 
+// ServerCertInfo holds expiry information of a certificate
+// served by the server.
+type ServerCertInfo struct {
+	Subject      string    `json:"subject"`
+	NotAfter     time.Time `json:"notAfter"`
+	DaysToExpiry int64     `json:"daysToExpiry"`
+	Expiring     bool      `json:"expiring"`
+}
+
 // ServerInfoData holds storage, connections and other
 // information of a given server.
 type ServerInfoData struct {
@@ -237,6 +278,9 @@ type ServerInfoData struct {
 	ConnStats   ServerConnStats  `json:"network"`
 	HTTPStats   ServerHTTPStats  `json:"http"`
 	Properties  ServerProperties `json:"server"`
+	CertsInfo   []ServerCertInfo `json:"certs,omitempty"`
+
+	MultipartCleanup ServerMultipartCleanupStats `json:"multipartCleanup"`
 }
 
 // ServerInfo holds server information result of one node
@@ -251,7 +295,7 @@ type ServerInfo struct {
 // Get server information
 func (adminAPI adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Request) {
 	// Authenticate request
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -335,7 +379,7 @@ func validateLockQueryParams(vars url.Values) (string, string, time.Duration, AP
 // ---------
 // Lists locks held on a given bucket, prefix and duration it was held for.
 func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -377,7 +421,7 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 // ---------
 // Clear locks held on a given bucket, prefix and duration it was held for.
 func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *http.Request) {
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -431,7 +475,7 @@ func (adminAPI adminAPIHandlers) ListUploadsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -514,7 +558,7 @@ func (adminAPI adminAPIHandlers) ListObjectsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -550,7 +594,7 @@ func (adminAPI adminAPIHandlers) ListBucketsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -581,7 +625,7 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -670,7 +714,7 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -728,7 +772,7 @@ func (adminAPI adminAPIHandlers) HealUploadHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -803,7 +847,7 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -866,7 +910,7 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 // Get config.json of this minio setup.
 func (adminAPI adminAPIHandlers) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -959,7 +1003,7 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	}
 
 	// Validate request signature.
-	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	adminAPIErr := checkAdminRequestAuthType(r, "")
 	if adminAPIErr != ErrNone {
 		writeErrorResponse(w, adminAPIErr, r.URL)
 		return
@@ -1009,3 +1053,815 @@ func (adminAPI adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http
 	// Restart all node for the modified config to take effect.
 	sendServiceCmd(globalAdminPeers, serviceRestart)
 }
+
+// BucketUsageAlertInfo - contains the response of get bucket usage
+// alert API, thresholds along with the current usage.
+type BucketUsageAlertInfo struct {
+	MaxSize    int64       `json:"maxSize"`
+	MaxObjects int64       `json:"maxObjects"`
+	Usage      bucketUsage `json:"usage"`
+}
+
+// validateAdminBucketQuery - validates bucket name in query params
+// of bucket admin APIs and verifies that the bucket exists.
+func validateAdminBucketQuery(vars url.Values, objectAPI ObjectLayer) (string, APIErrorCode) {
+	bucket := vars.Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		return "", ErrInvalidBucketName
+	}
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		return "", toAPIErrorCode(err)
+	}
+	return bucket, ErrNone
+}
+
+// GetBucketUsageAlertHandler - GET /?usage-alert&bucket=mybucket
+// - x-minio-operation = get
+// Get usage alert thresholds and current usage of a bucket.
+func (adminAPI adminAPIHandlers) GetBucketUsageAlertHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	alert, err := loadBucketUsageAlert(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	usage, err := getBucketUsage(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(BucketUsageAlertInfo{
+		MaxSize:    alert.MaxSize,
+		MaxObjects: alert.MaxObjects,
+		Usage:      usage,
+	})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket usage alert into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetBucketUsageAlertHandler - PUT /?usage-alert&bucket=mybucket
+// - x-minio-operation = set
+// Set usage alert thresholds of a bucket, crossing a threshold sends
+// s3:BucketUsage:ThresholdExceeded event to bucket notification targets.
+func (adminAPI adminAPIHandlers) SetBucketUsageAlertHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	var alert bucketUsageAlert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil || !alert.isValid() {
+		writeErrorResponse(w, ErrAdminInvalidUsageAlert, r.URL)
+		return
+	}
+
+	if err := persistBucketUsageAlert(bucket, alert, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// BucketWormInfo - contains the response of get bucket WORM API.
+type BucketWormInfo struct {
+	// WORM mode is enabled for this bucket.
+	Enabled bool `json:"enabled"`
+	// WORM mode is enabled for all buckets in server config.
+	ServerWide bool `json:"serverWide"`
+}
+
+// GetBucketWormHandler - GET /?worm&bucket=mybucket
+// - x-minio-operation = get
+// Get WORM mode of a bucket.
+func (adminAPI adminAPIHandlers) GetBucketWormHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	enabled, err := loadBucketWorm(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(BucketWormInfo{
+		Enabled:    enabled,
+		ServerWide: serverConfig.GetWorm(),
+	})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket WORM mode into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// EnableBucketWormHandler - PUT /?worm&bucket=mybucket
+// - x-minio-operation = enable
+// Enable WORM mode of a bucket, existing objects of the bucket can
+// then neither be overwritten nor deleted. It can't be disabled.
+func (adminAPI adminAPIHandlers) EnableBucketWormHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	if err := persistBucketWorm(bucket, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketWormConfig)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// BucketKeyInfo - contains the response of bucket key APIs.
+type BucketKeyInfo struct {
+	// ID of the KMS master key the bucket key is derived from.
+	KeyID string `json:"keyId"`
+	// Current version of the bucket key.
+	Version int `json:"version"`
+	// Time the current version was created, zero if never rotated.
+	Rotated time.Time `json:"rotated"`
+}
+
+// writeBucketKeyInfo - writes json encoded key info of a bucket.
+func writeBucketKeyInfo(w http.ResponseWriter, r *http.Request, info bucketKeyInfo) {
+	jsonBytes, err := json.Marshal(BucketKeyInfo(info))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket key info into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// GetBucketKeyHandler - GET /?bucket-key&bucket=mybucket
+// - x-minio-operation = get
+// Get the KMS key ID and the current version of the key of a bucket,
+// a bucket never rotated reports version 1. Does not modify the bucket.
+func (adminAPI adminAPIHandlers) GetBucketKeyHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	info, err := getBucketKeyInfo(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeBucketKeyInfo(w, r, info)
+}
+
+// RotateBucketKeyHandler - POST /?bucket-key&bucket=mybucket
+// - x-minio-operation = rotate
+// Move the key of a bucket to its next version.
+func (adminAPI adminAPIHandlers) RotateBucketKeyHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	info, err := rotateBucketKey(bucket, objectAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeBucketKeyInfo(w, r, info)
+}
+
+// LifecycleDryRunHandler - POST /?lifecycle&bucket=mybucket
+// - x-minio-operation = dry-run
+// Reports objects of a bucket which lifecycle rules would transition
+// now, without transitioning them. Rules are read from the request
+// body if any, otherwise the lifecycle of the bucket is evaluated.
+func (adminAPI adminAPIHandlers) LifecycleDryRunHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket, apiErr := validateAdminBucketQuery(r.URL.Query(), objectAPI)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	var lc lifecycle
+	if r.ContentLength > 0 {
+		lifecycleBytes, s3Error := readLifecycleBody(r)
+		if s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if lc, s3Error = parseLifecycle(lifecycleBytes); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	} else {
+		saved, err := loadBucketLifecycle(bucket, objectAPI)
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		lc = *saved
+	}
+
+	result, err := dryRunLifecycle(objectAPI, bucket, lc, UTCNow())
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal lifecycle dry-run result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// StartBucketCloneHandler - POST /?bucket-clone&bucket=mybucket&source=srcbucket
+// - x-minio-operation = start
+// Creates a bucket and clones objects of the source bucket into it in
+// background, object data is shared with the source bucket where the
+// backend allows it and copied otherwise.
+func (adminAPI adminAPIHandlers) StartBucketCloneHandler(w http.ResponseWriter, r *http.Request) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	bucket, source := vars.Get(string(mgmtBucket)), vars.Get(string(mgmtSource))
+	if !IsValidBucketName(bucket) || !IsValidBucketName(source) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+
+	status, err := globalBucketClones.start(objectAPI, source, bucket)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket clone status into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BucketCloneStatusHandler - GET /?bucket-clone&bucket=mybucket
+// - x-minio-operation = status
+// Get progress of cloning into a bucket, started on this server.
+func (adminAPI adminAPIHandlers) BucketCloneStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+
+	status, err := globalBucketClones.get(bucket)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket clone status into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// RebalanceStatusHandler - GET /?rebalance
+// - x-minio-operation = status
+// Get progress of rebalancing objects across disks added to the
+// setup, reported by the first server.
+func (adminAPI adminAPIHandlers) RebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	status, err := globalRebalancer.get()
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal rebalance status into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// DebugSignatureHandler - POST /?signature
+// - x-minio-operation = debug
+// Reports the canonical request and string to sign the server computes
+// for the request dumped in the body, and whether its signature
+// matches, to troubleshoot SignatureDoesNotMatch errors.
+func (adminAPI adminAPIHandlers) DebugSignatureHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	dumpedReq, err := parseRequestDump(r.Body)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	info := debugSignatureV4(dumpedReq, serverConfig.GetRegion())
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal signature debug info into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ValidateBucketPolicyHandler - POST /?policy
+// - x-minio-operation = validate
+// Validates the bucket policy in the body against the supported
+// statement, action and condition grammar and the maximum policy
+// size, and reports all the errors found with their position.
+func (adminAPI adminAPIHandlers) ValidateBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	// Read one byte more than the maximum size, so that oversized
+	// policies are reported without reading them entirely.
+	maxSize := serverConfig.GetPolicy().getMaxSize()
+	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(validateBucketPolicy(policyBytes, maxSize))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal policy validation result into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ListSlowRequestsHandler - GET /?slow-request
+// - x-minio-operation = list
+// Lists the most recent requests served by this server which took
+// longer than MINIO_SLOW_REQUEST_THRESHOLD, along with the time they
+// waited on namespace locks and the operations holding them.
+func (adminAPI adminAPIHandlers) ListSlowRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalSlowRequests.list())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal slow requests into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// WorkerPoolsStatusHandler - GET /?worker-pool
+// - x-minio-operation = status
+// Reports the number of workers and queued tasks of the worker pools
+// of background subsystems on this server, along with the tasks
+// rejected because their queue was full.
+func (adminAPI adminAPIHandlers) WorkerPoolsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(getWorkerPoolsStats())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal worker pools status into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetWorkerPoolsHandler - PUT /?worker-pool
+// - x-minio-operation = set
+// Resizes the worker pools of background subsystems on this server
+// until it restarts, zero values keep the current size. Sizes kept
+// across restarts are set in the workers section of config.
+func (adminAPI adminAPIHandlers) SetWorkerPoolsHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	var cfg workersConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil || cfg.Validate() != nil {
+		writeErrorResponse(w, ErrAdminInvalidWorkerPools, r.URL)
+		return
+	}
+
+	resizeWorkerPools(cfg)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// StartProfilingHandler - POST /?profile&type=cpu
+// - x-minio-operation = start
+// Starts recording a profile of the given type on this server, one of
+// cpu, mem, block, mutex, goroutine or trace.
+func (adminAPI adminAPIHandlers) StartProfilingHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	profileType := r.URL.Query().Get(string(mgmtProfileType))
+	if err := startGlobalProfiler(profileType); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DownloadProfilingHandler - POST /?profile
+// - x-minio-operation = stop
+// Stops recording the current profile and streams it back in pprof
+// format, or execution trace format for trace profiles.
+func (adminAPI adminAPIHandlers) DownloadProfilingHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	// Profile is recorded into a buffer first so that errors can still
+	// be reported before any data is written.
+	var buf bytes.Buffer
+	profileType, err := stopGlobalProfiler(&buf)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.pprof\"", profileType))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// QuotaUsageInfo - contains the response of quota usage API, quota
+// limits of an access key along with its cluster wide usage.
+type QuotaUsageInfo struct {
+	Limit quotaLimit `json:"limit"`
+	Usage quotaUsage `json:"usage"`
+}
+
+// QuotaUsageHandler - GET /?quota
+// - x-minio-operation = usage
+// Get quota limits and current usage of all the access keys with a quota.
+func (adminAPI adminAPIHandlers) QuotaUsageHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	usage := globalQuotaTracker.getUsage(UTCNow())
+	quotaUsageInfo := make(map[string]QuotaUsageInfo)
+	for accessKey, limit := range serverConfig.GetQuota() {
+		quotaUsageInfo[accessKey] = QuotaUsageInfo{
+			Limit: limit,
+			Usage: usage[accessKey],
+		}
+	}
+
+	jsonBytes, err := json.Marshal(quotaUsageInfo)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal quota usage into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AccessKeyUsageHandler - GET /?access-key
+// - x-minio-operation = usage
+// Get requests per API, source IPs and errors of all the access keys
+// across all servers along with the most recent usage alerts.
+func (adminAPI adminAPIHandlers) AccessKeyUsageHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	peerUsage := make([]accessKeyUsageInfo, len(globalAdminPeers))
+	var wg sync.WaitGroup
+	for i, p := range globalAdminPeers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			usage, err := peer.cmdRunner.AccessKeyUsage()
+			if err != nil {
+				errorIf(err, "Unable to get access key usage from %s.", peer.addr)
+				return
+			}
+			for i := range usage.Alerts {
+				usage.Alerts[i].Node = peer.addr
+			}
+			peerUsage[idx] = usage
+		}(i, p)
+	}
+	wg.Wait()
+
+	usage := accessKeyUsageInfo{
+		Keys:   make(map[string]accessKeyStats),
+		Alerts: []accessKeyAlert{},
+	}
+	for _, nodeUsage := range peerUsage {
+		usage.merge(nodeUsage)
+	}
+
+	jsonBytes, err := json.Marshal(usage)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal access key usage into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// BucketAnalyticsReportHandler - GET /?analytics&bucket=mybucket
+// - x-minio-operation = report
+// Get objects stored per storage class and age group, and reads
+// counted by all servers, for each analytics configuration of the
+// bucket.
+func (adminAPI adminAPIHandlers) BucketAnalyticsReportHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	bucket := r.URL.Query().Get(string(mgmtBucket))
+	if !IsValidBucketName(bucket) {
+		writeErrorResponse(w, ErrInvalidBucketName, r.URL)
+		return
+	}
+	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	report, err := getBucketAnalyticsReport(objectAPI, bucket, UTCNow())
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal bucket analytics report into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// ConfirmMissingDisksHandler - POST /?missing-disks
+// - x-minio-operation = confirm
+// Accept writes on all servers which started with disks missing,
+// returns the disks which were missing indexed by server.
+func (adminAPI adminAPIHandlers) ConfirmMissingDisksHandler(w http.ResponseWriter, r *http.Request) {
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	peerDisks := make([][]string, len(globalAdminPeers))
+	errs := make([]error, len(globalAdminPeers))
+	var wg sync.WaitGroup
+	for i, p := range globalAdminPeers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			peerDisks[idx], errs[idx] = peer.cmdRunner.ConfirmMissingDisks()
+			errorIf(errs[idx], "Unable to confirm missing disks on %s.", peer.addr)
+		}(i, p)
+	}
+	wg.Wait()
+
+	// Servers which couldn't be reached keep rejecting writes, the
+	// confirmation has to be sent again.
+	for _, err := range errs {
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	missing := make(map[string][]string)
+	for i, disks := range peerDisks {
+		if len(disks) > 0 {
+			missing[globalAdminPeers[i].addr] = disks
+		}
+	}
+
+	jsonBytes, err := json.Marshal(missing)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal missing disks into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
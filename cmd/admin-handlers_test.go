@@ -27,6 +27,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -208,6 +209,7 @@ type cmdType int
 const (
 	statusCmd cmdType = iota
 	restartCmd
+	stopCmd
 	setCreds
 )
 
@@ -218,6 +220,8 @@ func (c cmdType) String() string {
 		return "status"
 	case restartCmd:
 		return "restart"
+	case stopCmd:
+		return "stop"
 	case setCreds:
 		return "set-credentials"
 	}
@@ -232,6 +236,8 @@ func (c cmdType) apiMethod() string {
 		return "GET"
 	case restartCmd:
 		return "POST"
+	case stopCmd:
+		return "POST"
 	case setCreds:
 		return "POST"
 	}
@@ -246,6 +252,8 @@ func (c cmdType) toServiceSignal() serviceSignal {
 		return serviceStatus
 	case restartCmd:
 		return serviceRestart
+	case stopCmd:
+		return serviceStop
 	}
 	return serviceStatus
 }
@@ -301,7 +309,7 @@ func testServicesCmdHandler(cmd cmdType, t *testing.T) {
 
 	// Setting up a go routine to simulate ServerMux's
 	// handleServiceSignals for stop and restart commands.
-	if cmd == restartCmd {
+	if cmd == restartCmd || cmd == stopCmd {
 		go testServiceSignalReceiver(cmd, t)
 	}
 	credentials := serverConfig.GetCredential()
@@ -326,6 +334,10 @@ func testServicesCmdHandler(cmd cmdType, t *testing.T) {
 		if expectedInfo.ServerVersion != receivedInfo.ServerVersion {
 			t.Errorf("Expected storage info and received storage info differ, %v %v", expectedInfo, receivedInfo)
 		}
+		expectedVersions := []ServerNodeVersion{{Addr: globalAdminPeers[0].addr, Version: Version, CommitID: CommitID}}
+		if !reflect.DeepEqual(receivedInfo.Versions, expectedVersions) {
+			t.Errorf("Expected versions %v, got %v", expectedVersions, receivedInfo.Versions)
+		}
 	}
 
 	if rec.Code != http.StatusOK {
@@ -345,6 +357,11 @@ func TestServiceRestartHandler(t *testing.T) {
 	testServicesCmdHandler(restartCmd, t)
 }
 
+// Test for service stop management REST API.
+func TestServiceStopHandler(t *testing.T) {
+	testServicesCmdHandler(stopCmd, t)
+}
+
 // Test for service set creds management REST API.
 func TestServiceSetCreds(t *testing.T) {
 	adminTestBed, err := prepareAdminXLTestBed()
@@ -1624,3 +1641,565 @@ func TestNewHealResult(t *testing.T) {
 		}
 	}
 }
+
+// TestBucketUsageAlertHandlers - test for Set/GetBucketUsageAlertHandler.
+func TestBucketUsageAlertHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucket := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucket); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+	}
+
+	testCases := []struct {
+		bucket     string
+		opHdr      string
+		method     string
+		body       string
+		statusCode int
+	}{
+		// 1. Invalid bucket name.
+		{"my\\bucket", "get", http.MethodGet, "", http.StatusBadRequest},
+		// 2. Bucket does not exist.
+		{"nosuchbucket", "get", http.MethodGet, "", http.StatusNotFound},
+		// 3. Usage alert not set.
+		{bucket, "get", http.MethodGet, "", http.StatusNotFound},
+		// 4. Invalid usage alert thresholds.
+		{bucket, "set", http.MethodPut, `{"maxSize":-1}`, http.StatusBadRequest},
+		// 5. Malformed usage alert.
+		{bucket, "set", http.MethodPut, `{maxSize`, http.StatusBadRequest},
+		// 6. Valid usage alert.
+		{bucket, "set", http.MethodPut, `{"maxSize":1024,"maxObjects":10}`, http.StatusOK},
+		// 7. Usage alert is set.
+		{bucket, "get", http.MethodGet, "", http.StatusOK},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("usage-alert", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+
+		body := []byte(test.body)
+		req, err := buildAdminRequest(queryVal, test.opHdr, test.method, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct usage-alert request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+	}
+}
+
+// Tests getting and enabling WORM mode of buckets.
+func TestBucketWormHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	bucket := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucket); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+	}
+
+	testCases := []struct {
+		bucket     string
+		opHdr      string
+		method     string
+		statusCode int
+		enabled    bool
+	}{
+		// 1. Invalid bucket name.
+		{"my\\bucket", "get", http.MethodGet, http.StatusBadRequest, false},
+		// 2. Bucket does not exist.
+		{"nosuchbucket", "enable", http.MethodPut, http.StatusNotFound, false},
+		// 3. WORM mode not enabled.
+		{bucket, "get", http.MethodGet, http.StatusOK, false},
+		// 4. Enable WORM mode.
+		{bucket, "enable", http.MethodPut, http.StatusOK, false},
+		// 5. WORM mode enabled.
+		{bucket, "get", http.MethodGet, http.StatusOK, true},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("worm", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+
+		req, err := buildAdminRequest(queryVal, test.opHdr, test.method, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct worm request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK || test.opHdr != "get" {
+			continue
+		}
+
+		var info BucketWormInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+			t.Fatalf("Test %d - Failed to unmarshal bucket WORM info - %v", i+1, err)
+		}
+		if info.Enabled != test.enabled || info.ServerWide {
+			t.Errorf("Test %d - Expected WORM mode %t but received %+v", i+1, test.enabled, info)
+		}
+	}
+
+	enabled, err := getCachedBucketWorm(bucket, adminTestBed.objLayer)
+	if err != nil || !enabled {
+		t.Fatalf("Expected cached WORM mode to be enabled, got %t, %v", enabled, err)
+	}
+}
+
+// Tests getting and rotating keys of buckets.
+func TestBucketKeyHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	defer func(k *kms) { globalKMS = k }(globalKMS)
+	globalKMS = nil
+
+	bucket := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucket); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+	}
+
+	masterKey := "my-key:" + strings.Repeat("ab", kmsKeySize)
+	otherMasterKey := "other-key:" + strings.Repeat("cd", kmsKeySize)
+	testCases := []struct {
+		masterKey  string
+		bucket     string
+		opHdr      string
+		method     string
+		statusCode int
+		version    int
+	}{
+		// 1. No KMS master key.
+		{"", bucket, "get", http.MethodGet, http.StatusNotImplemented, 0},
+		// 2. Invalid bucket name.
+		{masterKey, "my\\bucket", "get", http.MethodGet, http.StatusBadRequest, 0},
+		// 3. Bucket does not exist.
+		{masterKey, "nosuchbucket", "rotate", http.MethodPost, http.StatusNotFound, 0},
+		// 4. Bucket key never rotated.
+		{masterKey, bucket, "get", http.MethodGet, http.StatusOK, 1},
+		{masterKey, bucket, "get", http.MethodGet, http.StatusOK, 1},
+		// 6. Rotate bucket key.
+		{masterKey, bucket, "rotate", http.MethodPost, http.StatusOK, 2},
+		{masterKey, bucket, "get", http.MethodGet, http.StatusOK, 2},
+		// 8. Bucket key derived from another master key.
+		{otherMasterKey, bucket, "rotate", http.MethodPost, http.StatusConflict, 0},
+	}
+
+	for i, test := range testCases {
+		globalKMS = nil
+		if test.masterKey != "" {
+			if globalKMS, err = parseKMSMasterKey(test.masterKey); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		queryVal := url.Values{}
+		queryVal.Set("bucket-key", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+
+		req, err := buildAdminRequest(queryVal, test.opHdr, test.method, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct bucket key request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var info BucketKeyInfo
+		if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+			t.Fatalf("Test %d - Failed to unmarshal bucket key info - %v", i+1, err)
+		}
+		if info.KeyID != "my-key" || info.Version != test.version {
+			t.Errorf("Test %d - Expected version %d of my-key but received %+v", i+1, test.version, info)
+		}
+
+		// Getting key info must not modify the bucket.
+		if test.opHdr == "get" && test.version == 1 {
+			kPath := pathJoin(bucketConfigPrefix, bucket, bucketKeyConfig)
+			if _, err = adminTestBed.objLayer.GetObjectInfo(minioMetaBucket, kPath); !isErrObjectNotFound(err) {
+				t.Errorf("Test %d - Expected no key info to be recorded, got %v", i+1, err)
+			}
+		}
+	}
+}
+
+// Tests evaluating lifecycle rules of buckets.
+func TestLifecycleDryRunHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucket := "mybucket"
+	if err = adminTestBed.objLayer.MakeBucket(bucket); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", bucket, err)
+	}
+
+	lifecycleXML := "<LifecycleConfiguration><Rule><ID>logs</ID><Prefix>logs/</Prefix><Status>Disabled</Status>" +
+		"<Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule></LifecycleConfiguration>"
+	testCases := []struct {
+		bucket     string
+		body       string
+		statusCode int
+	}{
+		// 1. Invalid bucket name.
+		{"my\\bucket", lifecycleXML, http.StatusBadRequest},
+		// 2. Bucket does not exist.
+		{"nosuchbucket", lifecycleXML, http.StatusNotFound},
+		// 3. Lifecycle not set on the bucket.
+		{bucket, "", http.StatusNotFound},
+		// 4. Malformed lifecycle.
+		{bucket, "<LifecycleConfiguration>", http.StatusBadRequest},
+		// 5. Valid lifecycle.
+		{bucket, lifecycleXML, http.StatusOK},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("lifecycle", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+
+		body := []byte(test.body)
+		req, err := buildAdminRequest(queryVal, "dry-run", http.MethodPost, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct lifecycle dry-run request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var result lifecycleDryRunResult
+		if err = json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Test %d - Failed to unmarshal lifecycle dry-run result - %v", i+1, err)
+		}
+		if result.Bucket != bucket || len(result.Rules) != 1 || result.Rules[0].ID != "logs" {
+			t.Errorf("Test %d - Unexpected lifecycle dry-run result %v", i+1, result)
+		}
+	}
+}
+
+// Tests starting and getting progress of bucket clones.
+func TestBucketCloneHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	defer func(clones *bucketClones) { globalBucketClones = clones }(globalBucketClones)
+	globalBucketClones = newBucketClones()
+
+	source := "production"
+	if err = adminTestBed.objLayer.MakeBucket(source); err != nil {
+		t.Fatalf("Failed to make bucket %s - %v", source, err)
+	}
+
+	testCases := []struct {
+		bucket     string
+		source     string
+		opHdr      string
+		method     string
+		statusCode int
+	}{
+		// 1. Invalid bucket name.
+		{"my\\bucket", source, "start", http.MethodPost, http.StatusBadRequest},
+		// 2. Invalid source bucket name.
+		{"staging", "my\\bucket", "start", http.MethodPost, http.StatusBadRequest},
+		// 3. Source bucket does not exist.
+		{"staging", "nosuchbucket", "start", http.MethodPost, http.StatusNotFound},
+		// 4. Target bucket exists.
+		{source, source, "start", http.MethodPost, http.StatusConflict},
+		// 5. No clone started.
+		{"staging", "", "status", http.MethodGet, http.StatusNotFound},
+		// 6. Valid clone.
+		{"staging", source, "start", http.MethodPost, http.StatusOK},
+		// 7. Clone started.
+		{"staging", "", "status", http.MethodGet, http.StatusOK},
+		// 8. Clone already started.
+		{"staging", source, "start", http.MethodPost, http.StatusConflict},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("bucket-clone", "")
+		queryVal.Set(string(mgmtBucket), test.bucket)
+		if test.source != "" {
+			queryVal.Set(string(mgmtSource), test.source)
+		}
+
+		req, err := buildAdminRequest(queryVal, test.opHdr, test.method, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct bucket-clone request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var status bucketCloneStatus
+		if err = json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Test %d - Failed to unmarshal bucket clone status - %v", i+1, err)
+		}
+		if status.Source != source || status.Target != test.bucket || status.Method != bucketCloneMethodCopy {
+			t.Errorf("Test %d - Unexpected bucket clone status %+v", i+1, status)
+		}
+	}
+}
+
+// Test for profiling handlers.
+func TestProfilingHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	testCases := []struct {
+		profileType string
+		opHdr       string
+		statusCode  int
+	}{
+		// 1. No profile being recorded.
+		{"", "stop", http.StatusBadRequest},
+		// 2. Invalid profile type.
+		{"invalid", "start", http.StatusBadRequest},
+		// 3. Valid profile type.
+		{profileGoroutine, "start", http.StatusOK},
+		// 4. Profile already being recorded.
+		{profileCPU, "start", http.StatusConflict},
+		// 5. Download profile.
+		{"", "stop", http.StatusOK},
+		// 6. Profile already downloaded.
+		{"", "stop", http.StatusBadRequest},
+	}
+
+	for i, test := range testCases {
+		queryVal := url.Values{}
+		queryVal.Set("profile", "")
+		if test.profileType != "" {
+			queryVal.Set(string(mgmtProfileType), test.profileType)
+		}
+
+		req, err := buildAdminRequest(queryVal, test.opHdr, http.MethodPost, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct profile request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != test.statusCode {
+			t.Errorf("Test %d - Expected status code %d but received %d", i+1, test.statusCode, rec.Code)
+		}
+		if rec.Code != http.StatusOK || test.opHdr != "stop" {
+			continue
+		}
+
+		if disposition := rec.Header().Get("Content-Disposition"); !strings.Contains(disposition, "goroutine.pprof") {
+			t.Errorf("Test %d - Unexpected Content-Disposition %s", i+1, disposition)
+		}
+		if rec.Body.Len() == 0 {
+			t.Errorf("Test %d - Expected profile data", i+1)
+		}
+	}
+}
+
+// Test for slow requests handler.
+func TestListSlowRequestsHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	defer func(threshold time.Duration, slow *slowRequests) {
+		globalSlowRequestThreshold = threshold
+		globalSlowRequests = slow
+	}(globalSlowRequestThreshold, globalSlowRequests)
+	globalSlowRequestThreshold = time.Second
+	globalSlowRequests = newSlowRequests()
+	globalSlowRequests.end(globalSlowRequests.begin(), SlowRequestInfo{Method: "GET", Path: "/bucket/object", Duration: 2 * time.Second})
+
+	queryVal := url.Values{}
+	queryVal.Set("slow-request", "")
+	req, err := buildAdminRequest(queryVal, "list", http.MethodGet, 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct slow-request request - %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var reports []SlowRequestInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &reports); err != nil {
+		t.Fatalf("Failed to unmarshal slow requests - %v", err)
+	}
+	if len(reports) != 1 || reports[0].Path != "/bucket/object" || reports[0].Duration != 2*time.Second {
+		t.Fatalf("Unexpected slow requests %+v", reports)
+	}
+}
+
+// Test for worker pool handlers.
+func TestWorkerPoolsHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	defer func(notification, heal, lifecycle *workerPool) {
+		globalNotificationPool = notification
+		globalHealPool = heal
+		globalLifecyclePool = lifecycle
+	}(globalNotificationPool, globalHealPool, globalLifecyclePool)
+	globalNotificationPool = newWorkerPool(1, 1)
+	globalHealPool = newWorkerPool(1, 1)
+	globalLifecyclePool = newWorkerPool(1, 1)
+
+	queryVal := url.Values{}
+	queryVal.Set("worker-pool", "")
+
+	testCases := []struct {
+		body               string
+		expectedStatusCode int
+	}{
+		// Invalid json.
+		{`{"heal":`, http.StatusBadRequest},
+		// Negative number of workers.
+		{`{"heal": {"workers": -1}}`, http.StatusBadRequest},
+		// Valid resize of a pool.
+		{`{"notification": {"workers": 4, "queueSize": 16}}`, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		body := []byte(testCase.body)
+		req, err := buildAdminRequest(queryVal, "set", http.MethodPut, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct worker-pool request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatusCode {
+			t.Errorf("Test %d: Expected status code %d but received %d", i+1, testCase.expectedStatusCode, rec.Code)
+		}
+	}
+
+	req, err := buildAdminRequest(queryVal, "status", http.MethodGet, 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct worker-pool request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but received %d", http.StatusOK, rec.Code)
+	}
+
+	var pools map[string]workerPoolStats
+	if err = json.Unmarshal(rec.Body.Bytes(), &pools); err != nil {
+		t.Fatalf("Failed to unmarshal worker pools status - %v", err)
+	}
+	if pool := pools[notificationPoolName]; pool.Workers != 4 || pool.QueueSize != 16 {
+		t.Errorf("Expected notification pool resized to 4 workers and a queue of 16, got %+v", pool)
+	}
+	if pool := pools[healPoolName]; pool.Workers != 1 || pool.QueueSize != 1 {
+		t.Errorf("Expected heal pool to keep its size, got %+v", pool)
+	}
+	if len(pools) != 3 {
+		t.Errorf("Expected 3 worker pools, got %d", len(pools))
+	}
+}
+
+// Tests validation of bucket policies through the admin API.
+func TestValidateBucketPolicyHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	queryVal := url.Values{}
+	queryVal.Set("policy", "")
+
+	validPolicy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`
+	testCases := []struct {
+		body          string
+		valid         bool
+		expectedLines []int
+	}{
+		// Test 1: valid policy.
+		{validPolicy, true, nil},
+		// Test 2: invalid action.
+		{"{\"Version\": \"2012-10-17\",\n\"Statement\": [\n{\"Effect\": \"Allow\", \"Principal\": \"*\", \"Action\": [\"s3:PutBucketPolicy\"], \"Resource\": [\"arn:aws:s3:::mybucket/*\"]}]}", false, []int{3}},
+		// Test 3: policy larger than the default maximum size.
+		{validPolicy + strings.Repeat(" ", defaultMaxAccessPolicySize), false, []int{1}},
+	}
+	for i, testCase := range testCases {
+		body := []byte(testCase.body)
+		req, err := buildAdminRequest(queryVal, "validate", http.MethodPost, int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to construct policy validation request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: Expected status code %d but received %d", i+1, http.StatusOK, rec.Code)
+		}
+
+		var result PolicyValidationResult
+		if err = json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Test %d: Failed to unmarshal policy validation result - %v", i+1, err)
+		}
+		if result.Valid != testCase.valid {
+			t.Errorf("Test %d: Expected valid to be %v, got %v", i+1, testCase.valid, result.Valid)
+		}
+		var lines []int
+		for _, perr := range result.Errors {
+			lines = append(lines, perr.Line)
+		}
+		if !reflect.DeepEqual(lines, testCase.expectedLines) {
+			t.Errorf("Test %d: Expected errors at lines %v, got %v", i+1, testCase.expectedLines, result.Errors)
+		}
+	}
+}
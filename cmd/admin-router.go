@@ -36,6 +36,8 @@ func registerAdminRouter(mux *router.Router) {
 
 	// Service restart
 	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "restart").HandlerFunc(adminAPI.ServiceRestartHandler)
+	// Service stop
+	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "stop").HandlerFunc(adminAPI.ServiceStopHandler)
 	// Service update credentials
 	adminRouter.Methods("POST").Queries("service", "").Headers(minioAdminOpHeader, "set-credentials").HandlerFunc(adminAPI.ServiceCredentialsHandler)
 
@@ -73,4 +75,91 @@ func registerAdminRouter(mux *router.Router) {
 	adminRouter.Methods("GET").Queries("config", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetConfigHandler)
 	// Set Config
 	adminRouter.Methods("PUT").Queries("config", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetConfigHandler)
+
+	/// Bucket usage alert operations
+
+	// Get bucket usage alert
+	adminRouter.Methods("GET").Queries("usage-alert", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetBucketUsageAlertHandler)
+	// Set bucket usage alert
+	adminRouter.Methods("PUT").Queries("usage-alert", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetBucketUsageAlertHandler)
+
+	/// Bucket WORM operations
+
+	// Get WORM mode of a bucket
+	adminRouter.Methods("GET").Queries("worm", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetBucketWormHandler)
+	// Enable WORM mode of a bucket
+	adminRouter.Methods("PUT").Queries("worm", "").Headers(minioAdminOpHeader, "enable").HandlerFunc(adminAPI.EnableBucketWormHandler)
+
+	/// Bucket key operations
+
+	// Get key info of a bucket
+	adminRouter.Methods("GET").Queries("bucket-key", "").Headers(minioAdminOpHeader, "get").HandlerFunc(adminAPI.GetBucketKeyHandler)
+	// Rotate key of a bucket
+	adminRouter.Methods("POST").Queries("bucket-key", "").Headers(minioAdminOpHeader, "rotate").HandlerFunc(adminAPI.RotateBucketKeyHandler)
+
+	/// Bucket lifecycle operations
+
+	// Report objects lifecycle rules would act on
+	adminRouter.Methods("POST").Queries("lifecycle", "").Headers(minioAdminOpHeader, "dry-run").HandlerFunc(adminAPI.LifecycleDryRunHandler)
+
+	/// Bucket clone operations
+
+	// Start cloning a bucket
+	adminRouter.Methods("POST").Queries("bucket-clone", "").Headers(minioAdminOpHeader, "start").HandlerFunc(adminAPI.StartBucketCloneHandler)
+	// Get progress of cloning a bucket
+	adminRouter.Methods("GET").Queries("bucket-clone", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.BucketCloneStatusHandler)
+
+	/// Rebalance operations
+
+	// Get progress of rebalancing objects across added disks
+	adminRouter.Methods("GET").Queries("rebalance", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.RebalanceStatusHandler)
+
+	/// Signature operations
+
+	// Show how the server computes the signature of a request
+	adminRouter.Methods("POST").Queries("signature", "").Headers(minioAdminOpHeader, "debug").HandlerFunc(adminAPI.DebugSignatureHandler)
+
+	/// Bucket policy operations
+
+	// Validate a bucket policy
+	adminRouter.Methods("POST").Queries("policy", "").Headers(minioAdminOpHeader, "validate").HandlerFunc(adminAPI.ValidateBucketPolicyHandler)
+
+	/// Slow request operations
+
+	// List recent slow requests
+	adminRouter.Methods("GET").Queries("slow-request", "").Headers(minioAdminOpHeader, "list").HandlerFunc(adminAPI.ListSlowRequestsHandler)
+
+	/// Worker pool operations
+
+	// Get size and saturation of worker pools
+	adminRouter.Methods("GET").Queries("worker-pool", "").Headers(minioAdminOpHeader, "status").HandlerFunc(adminAPI.WorkerPoolsStatusHandler)
+	// Resize worker pools
+	adminRouter.Methods("PUT").Queries("worker-pool", "").Headers(minioAdminOpHeader, "set").HandlerFunc(adminAPI.SetWorkerPoolsHandler)
+
+	/// Profiling operations
+
+	// Start recording a profile
+	adminRouter.Methods("POST").Queries("profile", "").Headers(minioAdminOpHeader, "start").HandlerFunc(adminAPI.StartProfilingHandler)
+	// Stop recording and download the profile
+	adminRouter.Methods("POST").Queries("profile", "").Headers(minioAdminOpHeader, "stop").HandlerFunc(adminAPI.DownloadProfilingHandler)
+
+	/// Quota operations
+
+	// Get quota usage of access keys
+	adminRouter.Methods("GET").Queries("quota", "").Headers(minioAdminOpHeader, "usage").HandlerFunc(adminAPI.QuotaUsageHandler)
+
+	/// Access key operations
+
+	// Get usage analytics and alerts of access keys
+	adminRouter.Methods("GET").Queries("access-key", "").Headers(minioAdminOpHeader, "usage").HandlerFunc(adminAPI.AccessKeyUsageHandler)
+
+	/// Missing disks operations
+
+	// Accept writes on servers started with disks missing
+	adminRouter.Methods("POST").Queries("missing-disks", "").Headers(minioAdminOpHeader, "confirm").HandlerFunc(adminAPI.ConfirmMissingDisksHandler)
+
+	/// Bucket analytics operations
+
+	// Get objects stored and reads per analytics configuration of a bucket
+	adminRouter.Methods("GET").Queries("analytics", "").Headers(minioAdminOpHeader, "report").HandlerFunc(adminAPI.BucketAnalyticsReportHandler)
 }
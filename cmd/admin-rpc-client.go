@@ -34,13 +34,18 @@ import (
 
 const (
 	// Admin service names
-	serviceRestartRPC = "Admin.Restart"
-	listLocksRPC      = "Admin.ListLocks"
-	reInitDisksRPC    = "Admin.ReInitDisks"
-	serverInfoDataRPC = "Admin.ServerInfoData"
-	getConfigRPC      = "Admin.GetConfig"
-	writeTmpConfigRPC = "Admin.WriteTmpConfig"
-	commitConfigRPC   = "Admin.CommitConfig"
+	serviceRestartRPC  = "Admin.Restart"
+	serviceStopRPC     = "Admin.Stop"
+	listLocksRPC       = "Admin.ListLocks"
+	reInitDisksRPC     = "Admin.ReInitDisks"
+	serverInfoDataRPC  = "Admin.ServerInfoData"
+	getConfigRPC       = "Admin.GetConfig"
+	writeTmpConfigRPC  = "Admin.WriteTmpConfig"
+	commitConfigRPC    = "Admin.CommitConfig"
+	accessKeyUsageRPC  = "Admin.AccessKeyUsage"
+	bucketAnalyticsRPC = "Admin.BucketAnalytics"
+	missingDisksRPC    = "Admin.ConfirmMissingDisks"
+	clusterPeersRPC    = "Admin.ClusterPeers"
 )
 
 // localAdminClient - represents admin operation to be executed locally.
@@ -57,12 +62,17 @@ type remoteAdminClient struct {
 // commands like service stop and service restart.
 type adminCmdRunner interface {
 	Restart() error
+	Stop() error
 	ListLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error)
 	ReInitDisks() error
 	ServerInfoData() (ServerInfoData, error)
 	GetConfig() ([]byte, error)
 	WriteTmpConfig(tmpFileName string, configBytes []byte) error
 	CommitConfig(tmpFileName string) error
+	AccessKeyUsage() (accessKeyUsageInfo, error)
+	BucketAnalytics(bucket string) ([]analyticsUsageEntry, error)
+	ConfirmMissingDisks() ([]string, error)
+	ClusterPeers() ([]string, error)
 }
 
 // Restart - Sends a message over channel to the go-routine
@@ -72,6 +82,13 @@ func (lc localAdminClient) Restart() error {
 	return nil
 }
 
+// Stop - Sends a message over channel to the go-routine
+// responsible for shutting down the process.
+func (lc localAdminClient) Stop() error {
+	globalServiceSignalCh <- serviceStop
+	return nil
+}
+
 // ListLocks - Fetches lock information from local lock instrumentation.
 func (lc localAdminClient) ListLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error) {
 	return listLocksInfo(bucket, prefix, duration), nil
@@ -84,6 +101,13 @@ func (rc remoteAdminClient) Restart() error {
 	return rc.Call(serviceRestartRPC, &args, &reply)
 }
 
+// Stop - Sends stop command to remote server via RPC.
+func (rc remoteAdminClient) Stop() error {
+	args := AuthRPCArgs{}
+	reply := AuthRPCReply{}
+	return rc.Call(serviceStopRPC, &args, &reply)
+}
+
 // ListLocks - Sends list locks command to remote server via RPC.
 func (rc remoteAdminClient) ListLocks(bucket, prefix string, duration time.Duration) ([]VolumeLockInfo, error) {
 	listArgs := ListLocksQuery{
@@ -140,7 +164,12 @@ func (lc localAdminClient) ServerInfoData() (ServerInfoData, error) {
 			CommitID: CommitID,
 			SQSARN:   arns,
 			Region:   serverConfig.GetRegion(),
+			Mode:     getServerMode(),
+
+			MissingDisks: globalMissingDisks.getDisks(),
 		},
+		CertsInfo:        getCertsInfo(UTCNow()),
+		MultipartCleanup: globalMultipartJanitor.getStats(),
 	}, nil
 }
 
@@ -225,6 +254,74 @@ func (rc remoteAdminClient) CommitConfig(tmpFileName string) error {
 	return nil
 }
 
+// AccessKeyUsage - returns usage of access keys on this server.
+func (lc localAdminClient) AccessKeyUsage() (accessKeyUsageInfo, error) {
+	return globalAccessKeyTracker.getUsage(), nil
+}
+
+// AccessKeyUsage - returns usage of access keys on the server to
+// which the RPC call is made.
+func (rc remoteAdminClient) AccessKeyUsage() (accessKeyUsageInfo, error) {
+	args := AuthRPCArgs{}
+	reply := AccessKeyUsageReply{}
+	if err := rc.Call(accessKeyUsageRPC, &args, &reply); err != nil {
+		return accessKeyUsageInfo{}, err
+	}
+	return reply.Usage, nil
+}
+
+// BucketAnalytics - returns reads of objects of bucket selected by
+// its analytics configurations, counted on this server.
+func (lc localAdminClient) BucketAnalytics(bucket string) ([]analyticsUsageEntry, error) {
+	return globalAnalyticsTracker.getUsage(bucket), nil
+}
+
+// BucketAnalytics - returns reads of objects of bucket selected by
+// its analytics configurations, counted on the server to which the
+// RPC call is made.
+func (rc remoteAdminClient) BucketAnalytics(bucket string) ([]analyticsUsageEntry, error) {
+	args := BucketAnalyticsArgs{Bucket: bucket}
+	reply := BucketAnalyticsReply{}
+	if err := rc.Call(bucketAnalyticsRPC, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Usage, nil
+}
+
+// ConfirmMissingDisks - accepts writes on this server with disks
+// missing since startup, returns the disks which were missing.
+func (lc localAdminClient) ConfirmMissingDisks() ([]string, error) {
+	return globalMissingDisks.confirm(), nil
+}
+
+// ConfirmMissingDisks - accepts writes with disks missing since
+// startup on the server to which the RPC call is made, returns the
+// disks which were missing.
+func (rc remoteAdminClient) ConfirmMissingDisks() ([]string, error) {
+	args := AuthRPCArgs{}
+	reply := ConfirmMissingDisksReply{}
+	if err := rc.Call(missingDisksRPC, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Disks, nil
+}
+
+// ClusterPeers - returns addresses of the other servers of the setup.
+func (lc localAdminClient) ClusterPeers() ([]string, error) {
+	return getRemotePeerAddrs(globalAdminPeers), nil
+}
+
+// ClusterPeers - returns addresses of the other servers of the setup
+// known to the server to which the RPC call is made.
+func (rc remoteAdminClient) ClusterPeers() ([]string, error) {
+	args := AuthRPCArgs{}
+	reply := ClusterPeersReply{}
+	if err := rc.Call(clusterPeersRPC, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Peers, nil
+}
+
 // adminPeer - represents an entity that implements Restart methods.
 type adminPeer struct {
 	addr      string
@@ -269,16 +366,28 @@ func makeAdminPeers(endpoints EndpointList) (adminPeerList adminPeers) {
 	return adminPeerList
 }
 
+// getRemotePeerAddrs - returns addresses of the remote peers, the
+// local peer comes first in peers.
+func getRemotePeerAddrs(peers adminPeers) []string {
+	addrs := []string{}
+	for i := 1; i < len(peers); i++ {
+		addrs = append(addrs, peers[i].addr)
+	}
+	return addrs
+}
+
 // Initialize global adminPeer collection.
 func initGlobalAdminPeers(endpoints EndpointList) {
 	globalAdminPeers = makeAdminPeers(endpoints)
 }
 
-// invokeServiceCmd - Invoke Restart command.
+// invokeServiceCmd - Invoke Restart or Stop command.
 func invokeServiceCmd(cp adminPeer, cmd serviceSignal) (err error) {
 	switch cmd {
 	case serviceRestart:
 		err = cp.cmdRunner.Restart()
+	case serviceStop:
+		err = cp.cmdRunner.Stop()
 	}
 	return err
 }
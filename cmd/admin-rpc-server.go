@@ -65,6 +65,40 @@ type ConfigReply struct {
 	Config []byte // json-marshalled bytes of serverConfigV13
 }
 
+// AccessKeyUsageReply - wraps access key usage of a server over RPC.
+type AccessKeyUsageReply struct {
+	AuthRPCReply
+	Usage accessKeyUsageInfo
+}
+
+// ConfirmMissingDisksReply - wraps disks missing since startup of a
+// server over RPC.
+type ConfirmMissingDisksReply struct {
+	AuthRPCReply
+	Disks []string
+}
+
+// BucketAnalyticsArgs - wraps the bucket whose analytics are requested
+// over RPC.
+type BucketAnalyticsArgs struct {
+	AuthRPCArgs
+	Bucket string
+}
+
+// BucketAnalyticsReply - wraps reads counted for bucket analytics by a
+// server over RPC.
+type BucketAnalyticsReply struct {
+	AuthRPCReply
+	Usage []analyticsUsageEntry
+}
+
+// ClusterPeersReply - wraps the addresses of the other servers of the
+// setup.
+type ClusterPeersReply struct {
+	AuthRPCReply
+	Peers []string
+}
+
 // Restart - Restart this instance of minio server.
 func (s *adminCmd) Restart(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -75,6 +109,16 @@ func (s *adminCmd) Restart(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	return nil
 }
 
+// Stop - Shut down this instance of minio server.
+func (s *adminCmd) Stop(args *AuthRPCArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	globalServiceSignalCh <- serviceStop
+	return nil
+}
+
 // ListLocks - lists locks held by requests handled by this server instance.
 func (s *adminCmd) ListLocks(query *ListLocksQuery, reply *ListLocksReply) error {
 	if err := query.IsAuthenticated(); err != nil {
@@ -151,10 +195,16 @@ func (s *adminCmd) ServerInfoData(args *AuthRPCArgs, reply *ServerInfoDataReply)
 			CommitID: CommitID,
 			Region:   serverConfig.GetRegion(),
 			SQSARN:   arns,
+			Mode:     getServerMode(),
+
+			MissingDisks: globalMissingDisks.getDisks(),
 		},
 		StorageInfo: storageInfo,
 		ConnStats:   globalConnStats.toServerConnStats(),
 		HTTPStats:   globalHTTPStats.toServerHTTPStats(),
+
+		CertsInfo:        getCertsInfo(UTCNow()),
+		MultipartCleanup: globalMultipartJanitor.getStats(),
 	}
 
 	return nil
@@ -179,6 +229,48 @@ func (s *adminCmd) GetConfig(args *AuthRPCArgs, reply *ConfigReply) error {
 	return nil
 }
 
+// AccessKeyUsage - returns usage of access keys on this server.
+func (s *adminCmd) AccessKeyUsage(args *AuthRPCArgs, reply *AccessKeyUsageReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Usage = globalAccessKeyTracker.getUsage()
+	return nil
+}
+
+// BucketAnalytics - returns reads of objects of a bucket selected by
+// its analytics configurations, counted on this server.
+func (s *adminCmd) BucketAnalytics(args *BucketAnalyticsArgs, reply *BucketAnalyticsReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Usage = globalAnalyticsTracker.getUsage(args.Bucket)
+	return nil
+}
+
+// ConfirmMissingDisks - accepts writes on this server with disks
+// missing since startup.
+func (s *adminCmd) ConfirmMissingDisks(args *AuthRPCArgs, reply *ConfirmMissingDisksReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Disks = globalMissingDisks.confirm()
+	return nil
+}
+
+// ClusterPeers - returns addresses of the other servers of the setup.
+func (s *adminCmd) ClusterPeers(args *AuthRPCArgs, reply *ClusterPeersReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	reply.Peers = getRemotePeerAddrs(globalAdminPeers)
+	return nil
+}
+
 // WriteConfigArgs - wraps the bytes to be written and temporary file name.
 type WriteConfigArgs struct {
 	AuthRPCArgs
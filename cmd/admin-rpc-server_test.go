@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -58,6 +59,10 @@ func testAdminCmd(cmd cmdType, t *testing.T) {
 		if err = adminServer.Restart(&ga, &genReply); err != nil {
 			t.Errorf("restartCmd: Expected: <nil>, got: %v", err)
 		}
+	case stopCmd:
+		if err = adminServer.Stop(&ga, &genReply); err != nil {
+			t.Errorf("stopCmd: Expected: <nil>, got: %v", err)
+		}
 	}
 }
 
@@ -66,6 +71,11 @@ func TestAdminRestart(t *testing.T) {
 	testAdminCmd(restartCmd, t)
 }
 
+// TestAdminStop - test for Admin.Stop RPC service.
+func TestAdminStop(t *testing.T) {
+	testAdminCmd(stopCmd, t)
+}
+
 // TestReInitDisks - test for Admin.ReInitDisks RPC service.
 func TestReInitDisks(t *testing.T) {
 	// Reset global variables to start afresh.
@@ -242,3 +252,48 @@ func TestWriteAndCommitConfig(t *testing.T) {
 		t.Fatalf("Failed to commit config file %v", err)
 	}
 }
+
+// TestClusterPeers - test for Admin.ClusterPeers RPC handler.
+func TestClusterPeers(t *testing.T) {
+	// Reset global variables to start afresh.
+	resetTestGlobals()
+
+	rootPath, err := newTestConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	defer func(peers adminPeers) { globalAdminPeers = peers }(globalAdminPeers)
+	globalAdminPeers = adminPeers{
+		{"localhost:9000", localAdminClient{}},
+		{"node2:9000", nil},
+		{"node3:9000", nil},
+	}
+
+	adminServer := adminCmd{}
+	creds := serverConfig.GetCredential()
+	args := LoginRPCArgs{
+		Username:    creds.AccessKey,
+		Password:    creds.SecretKey,
+		Version:     Version,
+		RequestTime: UTCNow(),
+	}
+	reply := LoginRPCReply{}
+	if err = adminServer.Login(&args, &reply); err != nil {
+		t.Fatalf("Failed to login to admin server - %v", err)
+	}
+
+	peersReply := ClusterPeersReply{}
+	if err = adminServer.ClusterPeers(&AuthRPCArgs{AuthToken: reply.AuthToken}, &peersReply); err != nil {
+		t.Fatalf("Expected ClusterPeers to pass but failed with %v", err)
+	}
+	if !reflect.DeepEqual(peersReply.Peers, []string{"node2:9000", "node3:9000"}) {
+		t.Errorf("Unexpected peers %v", peersReply.Peers)
+	}
+
+	// Unauthenticated calls are refused.
+	if err = adminServer.ClusterPeers(&AuthRPCArgs{}, &peersReply); err == nil {
+		t.Error("Expected ClusterPeers to fail without authentication")
+	}
+}
@@ -23,6 +23,8 @@ import (
 const (
 	// Response request id.
 	responseRequestIDKey = "x-amz-request-id"
+	// Response host id, identifies the server which handled the request.
+	responseHostIDKey = "x-amz-id-2"
 )
 
 // ObjectIdentifier carries key name for the object to delete.
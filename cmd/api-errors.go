@@ -19,6 +19,7 @@ package cmd
 import (
 	"encoding/xml"
 	"net/http"
+	"reflect"
 )
 
 // APIError structure
@@ -36,6 +37,7 @@ type APIErrorResponse struct {
 	Key        string
 	BucketName string
 	Resource   string
+	Region     string `xml:",omitempty"`
 	RequestID  string `xml:"RequestId"`
 	HostID     string `xml:"HostId"`
 }
@@ -108,6 +110,9 @@ const (
 	ErrAuthHeaderEmpty
 	ErrExpiredPresignRequest
 	ErrRequestNotReadyYet
+	ErrPresignedURLAlreadyUsed
+	ErrMalformedPresignedCondition
+	ErrPresignedContentTypeNotAllowed
 	ErrUnsignedHeaders
 	ErrMissingDateHeader
 	ErrInvalidQuerySignatureAlgo
@@ -115,6 +120,37 @@ const (
 	ErrBucketAlreadyOwnedByYou
 	ErrInvalidDuration
 	ErrNotSupported
+	ErrSlowDown
+	ErrInvalidTag
+	ErrNoSuchTagSet
+	ErrInvalidObjectSize
+	ErrInvalidLifecycle
+	ErrNoSuchLifecycleConfiguration
+	ErrInvalidAnalyticsConfiguration
+	ErrNoSuchAnalyticsConfiguration
+	ErrTooManyAnalyticsConfigurations
+	ErrInvalidDefaultMetadata
+	ErrNoSuchDefaultMetadata
+	ErrInvalidWebsiteConfiguration
+	ErrNoSuchWebsiteConfiguration
+	ErrInvalidObjectLockConfiguration
+	ErrNoSuchObjectLockConfiguration
+	ErrObjectLockNotEnabled
+	ErrInvalidObjectRetention
+	ErrInvalidObjectLegalHold
+	ErrNoSuchObjectLock
+	ErrObjectLocked
+	ErrInvalidToken
+	ErrExpiredToken
+	ErrMalformedPolicyDocument
+	ErrPackedPolicyTooLarge
+	ErrInvalidContinuationToken
+	ErrInvalidEncodingMethod
+	ErrInvalidListFields
+	ErrInvalidComposeSources
+	ErrInvalidStorageClass
+	ErrMalformedACL
+	ErrUnsupportedACL
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -142,6 +178,9 @@ const (
 	ErrInvalidObjectName
 	ErrInvalidResourceName
 	ErrServerNotInitialized
+	ErrOperationTimedOut
+	ErrServerDegraded
+	ErrServerMissingDisks
 	// Add new extended error codes here.
 	// Please open a https://github.com/minio/minio/issues before adding
 	// new error codes here.
@@ -149,6 +188,18 @@ const (
 	ErrAdminInvalidAccessKey
 	ErrAdminInvalidSecretKey
 	ErrAdminConfigNoQuorum
+	ErrAdminNoSuchUsageAlert
+	ErrAdminInvalidUsageAlert
+	ErrAdminNoSuchBucketClone
+	ErrAdminNoRebalance
+	ErrAdminInvalidRequestDump
+	ErrAdminInvalidProfileType
+	ErrAdminProfilerNotSupported
+	ErrAdminProfilerRunning
+	ErrAdminProfilerNotRunning
+	ErrAdminInvalidWorkerPools
+	ErrAdminKMSNotConfigured
+	ErrAdminBucketKeyMismatch
 )
 
 // error code to APIError structure, these fields carry respective
@@ -331,7 +382,7 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 	},
 	ErrAuthorizationHeaderMalformed: {
 		Code:           "AuthorizationHeaderMalformed",
-		Description:    "The authorization header is malformed; the region is wrong; expecting 'us-east-1'.",
+		Description:    "The authorization header is malformed; the region is wrong.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
 	ErrMalformedPOSTRequest: {
@@ -476,6 +527,21 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Request is not valid yet",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrPresignedURLAlreadyUsed: {
+		Code:           "AccessDenied",
+		Description:    "Request has already been used",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrMalformedPresignedCondition: {
+		Code:           "AuthorizationQueryParametersError",
+		Description:    "X-Minio-Content-Length-Range must be of the form \"<min>,<max>\" with 0 <= min <= max.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrPresignedContentTypeNotAllowed: {
+		Code:           "AccessDenied",
+		Description:    "Content-Type is not allowed by the presigned URL",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	// FIXME: Actual XML error response also contains the header which missed in list of signed header parameters.
 	ErrUnsignedHeaders: {
 		Code:           "AccessDenied",
@@ -487,6 +553,161 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Query-string authentication version 4 requires the X-Amz-Algorithm, X-Amz-Credential, X-Amz-Signature, X-Amz-Date, X-Amz-SignedHeaders, and X-Amz-Expires parameters.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrSlowDown: {
+		Code:           "SlowDown",
+		Description:    "Please reduce your request rate.",
+		HTTPStatusCode: http.StatusTooManyRequests,
+	},
+	ErrInvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The TagSet you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchTagSet: {
+		Code:           "NoSuchTagSet",
+		Description:    "The TagSet does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidObjectSize: {
+		Code:           "InvalidArgument",
+		Description:    "Argument object-size must be an integer between 0 and 9223372036854775807",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLifecycle: {
+		Code:           "InvalidArgument",
+		Description:    "The lifecycle configuration you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidAnalyticsConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The analytics configuration you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchAnalyticsConfiguration: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrTooManyAnalyticsConfigurations: {
+		Code:           "TooManyConfigurations",
+		Description:    "You are attempting to create a new configuration but have already reached the 1,000-configuration limit.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidDefaultMetadata: {
+		Code:           "InvalidArgument",
+		Description:    "The default metadata configuration you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchDefaultMetadata: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The default metadata configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidWebsiteConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The website configuration you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchWebsiteConfiguration: {
+		Code:           "NoSuchWebsiteConfiguration",
+		Description:    "The specified bucket does not have a website configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidObjectLockConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The object lock configuration you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchObjectLockConfiguration: {
+		Code:           "ObjectLockConfigurationNotFoundError",
+		Description:    "Object Lock configuration does not exist for this bucket",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrObjectLockNotEnabled: {
+		Code:           "InvalidRequest",
+		Description:    "Bucket is missing Object Lock Configuration",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectRetention: {
+		Code:           "InvalidArgument",
+		Description:    "The retention you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectLegalHold: {
+		Code:           "InvalidArgument",
+		Description:    "The legal hold you have provided is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchObjectLock: {
+		Code:           "NoSuchObjectLockConfiguration",
+		Description:    "The specified object does not have a ObjectLock configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrObjectLocked: {
+		Code:           "AccessDenied",
+		Description:    "Access Denied because object protected by object lock.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidToken: {
+		Code:           "InvalidToken",
+		Description:    "The provided token is malformed or otherwise invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrExpiredToken: {
+		Code:           "ExpiredToken",
+		Description:    "The provided token has expired.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMalformedPolicyDocument: {
+		Code:           "MalformedPolicyDocument",
+		Description:    "The policy document is malformed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrPackedPolicyTooLarge: {
+		Code:           "PackedPolicyTooLarge",
+		Description:    "The policy document is too large.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidContinuationToken: {
+		Code:           "InvalidArgument",
+		Description:    "The continuation token provided is incorrect",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidEncodingMethod: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid Encoding Method specified in Request",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidListFields: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid fields specified in Request, only 'key' or 'key,size' are supported",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidComposeSources: {
+		Code:           "InvalidArgument",
+		Description:    "Between 1 and 32 source objects can be composed",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidStorageClass: {
+		Code:           "InvalidStorageClass",
+		Description:    "The storage class you specified is not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMalformedACL: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedACL: {
+		Code:           "InvalidArgument",
+		Description:    "Only private, public-read and public-read-write ACLs granting permissions to the owner and AllUsers group are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrBucketAlreadyOwnedByYou: {
 		Code:           "BucketAlreadyOwnedByYou",
 		Description:    "Your previous request to create the named bucket succeeded and you already own it.",
@@ -603,6 +824,21 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Server not initialized, please try again.",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrOperationTimedOut: {
+		Code:           "XMinioServerTimedOut",
+		Description:    "A timeout occurred while trying to lock a resource, please try again.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrServerDegraded: {
+		Code:           "XMinioServerDegraded",
+		Description:    "Server is in degraded read-only mode, not enough disks are online for write operations.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
+	ErrServerMissingDisks: {
+		Code:           "XMinioServerMissingDisks",
+		Description:    "Server started with disks missing, write operations are disabled until they are online or the start is confirmed.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
 	ErrAdminInvalidAccessKey: {
 		Code:           "XMinioAdminInvalidAccessKey",
 		Description:    "The access key is invalid.",
@@ -618,100 +854,130 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Configuration update failed because server quorum was not met",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrAdminNoSuchUsageAlert: {
+		Code:           "XMinioAdminNoSuchUsageAlert",
+		Description:    "The specified bucket does not have a usage alert.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidUsageAlert: {
+		Code:           "XMinioAdminInvalidUsageAlert",
+		Description:    "Usage alert thresholds must be non-negative with at least one threshold set.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminNoSuchBucketClone: {
+		Code:           "XMinioAdminNoSuchBucketClone",
+		Description:    "No clone into the specified bucket was started on this server.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoRebalance: {
+		Code:           "XMinioAdminNoRebalance",
+		Description:    "No rebalance was started on this server.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidRequestDump: {
+		Code:           "XMinioAdminInvalidRequestDump",
+		Description:    "Request dump is not a valid HTTP request.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidWorkerPools: {
+		Code:           "XMinioAdminInvalidWorkerPools",
+		Description:    "Worker pool sizes must not be negative.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidProfileType: {
+		Code:           "XMinioAdminInvalidProfileType",
+		Description:    "Profile type must be one of cpu, mem, block, mutex, goroutine or trace.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminProfilerNotSupported: {
+		Code:           "XMinioAdminProfilerNotSupported",
+		Description:    "Profile type is not supported by the Go version the server is built with.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminProfilerRunning: {
+		Code:           "XMinioAdminProfilerRunning",
+		Description:    "A profile is already being recorded on this server.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrAdminProfilerNotRunning: {
+		Code:           "XMinioAdminProfilerNotRunning",
+		Description:    "No profile is being recorded on this server.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminKMSNotConfigured: {
+		Code:           "XMinioAdminKMSNotConfigured",
+		Description:    "No KMS master key is configured, set MINIO_KMS_MASTER_KEY.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminBucketKeyMismatch: {
+		Code:           "XMinioAdminBucketKeyMismatch",
+		Description:    "The bucket key was derived from another KMS master key than the configured one.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 
 	// Add your error structure here.
 }
 
+// s3Error - errors reported to clients with the S3 error response of
+// their code, whose HTTP status and message are in errorCodeResponse.
+type s3Error interface {
+	error
+	APIErrorCode() APIErrorCode
+}
+
+// apiErrorCodes - S3 error codes of sentinel errors returned by
+// handlers and underlying layers, typed errors implement s3Error.
+var apiErrorCodes = map[error]APIErrorCode{
+	errSignatureMismatch:              ErrSignatureDoesNotMatch,
+	errContentSHA256Mismatch:          ErrContentSHA256Mismatch,
+	errDataTooLarge:                   ErrEntityTooLarge,
+	errDataTooSmall:                   ErrEntityTooSmall,
+	errInvalidAccessKeyLength:         ErrAdminInvalidAccessKey,
+	errInvalidSecretKeyLength:         ErrAdminInvalidSecretKey,
+	errNoSuchUsageAlert:               ErrAdminNoSuchUsageAlert,
+	errNoSuchBucketClone:              ErrAdminNoSuchBucketClone,
+	errNoRebalance:                    ErrAdminNoRebalance,
+	errInvalidRequestDump:             ErrAdminInvalidRequestDump,
+	errInvalidProfileType:             ErrAdminInvalidProfileType,
+	errProfilerNotSupported:           ErrAdminProfilerNotSupported,
+	errProfilerRunning:                ErrAdminProfilerRunning,
+	errProfilerNotRunning:             ErrAdminProfilerNotRunning,
+	errKMSNotConfigured:               ErrAdminKMSNotConfigured,
+	errBucketKeyMismatch:              ErrAdminBucketKeyMismatch,
+	errNoSuchTagSet:                   ErrNoSuchTagSet,
+	errNoSuchLifecycleConfiguration:   ErrNoSuchLifecycleConfiguration,
+	errNoSuchAnalyticsConfiguration:   ErrNoSuchAnalyticsConfiguration,
+	errTooManyAnalyticsConfigurations: ErrTooManyAnalyticsConfigurations,
+	errNoSuchDefaultMetadata:          ErrNoSuchDefaultMetadata,
+	errNoSuchWebsiteConfiguration:     ErrNoSuchWebsiteConfiguration,
+	errLockTimedOut:                   ErrOperationTimedOut,
+	errServerDegraded:                 ErrServerDegraded,
+	errServerMissingDisks:             ErrServerMissingDisks,
+	errObjectWormProtected:            ErrMethodNotAllowed,
+	errNoSuchObjectLockConfiguration:  ErrNoSuchObjectLockConfiguration,
+	errObjectLockNotEnabled:           ErrObjectLockNotEnabled,
+	errObjectLocked:                   ErrObjectLocked,
+}
+
 // toAPIErrorCode - Converts embedded errors. Convenience
 // function written to handle all cases where we have known types of
 // errors returned by underlying layers.
-func toAPIErrorCode(err error) (apiErr APIErrorCode) {
+func toAPIErrorCode(err error) APIErrorCode {
 	if err == nil {
 		return ErrNone
 	}
 
 	err = errorCause(err)
-	// Verify if the underlying error is signature mismatch.
-	switch err {
-	case errSignatureMismatch:
-		apiErr = ErrSignatureDoesNotMatch
-	case errContentSHA256Mismatch:
-		apiErr = ErrContentSHA256Mismatch
-	case errDataTooLarge:
-		apiErr = ErrEntityTooLarge
-	case errDataTooSmall:
-		apiErr = ErrEntityTooSmall
-	case errInvalidAccessKeyLength:
-		apiErr = ErrAdminInvalidAccessKey
-	case errInvalidSecretKeyLength:
-		apiErr = ErrAdminInvalidSecretKey
+	if e, ok := err.(s3Error); ok {
+		return e.APIErrorCode()
 	}
-
-	if apiErr != ErrNone {
-		// If there was a match in the above switch case.
-		return apiErr
+	// Errors of non comparable types can't be looked up.
+	if reflect.TypeOf(err).Comparable() {
+		if apiErr, ok := apiErrorCodes[err]; ok {
+			return apiErr
+		}
 	}
-
-	switch err.(type) {
-	case StorageFull:
-		apiErr = ErrStorageFull
-	case BadDigest:
-		apiErr = ErrBadDigest
-	case IncompleteBody:
-		apiErr = ErrIncompleteBody
-	case ObjectExistsAsDirectory:
-		apiErr = ErrObjectExistsAsDirectory
-	case PrefixAccessDenied:
-		apiErr = ErrAccessDenied
-	case BucketNameInvalid:
-		apiErr = ErrInvalidBucketName
-	case BucketNotFound:
-		apiErr = ErrNoSuchBucket
-	case BucketAlreadyOwnedByYou:
-		apiErr = ErrBucketAlreadyOwnedByYou
-	case BucketNotEmpty:
-		apiErr = ErrBucketNotEmpty
-	case BucketExists:
-		apiErr = ErrBucketAlreadyOwnedByYou
-	case ObjectNotFound:
-		apiErr = ErrNoSuchKey
-	case ObjectNameInvalid:
-		apiErr = ErrInvalidObjectName
-	case InvalidUploadID:
-		apiErr = ErrNoSuchUpload
-	case InvalidPart:
-		apiErr = ErrInvalidPart
-	case InsufficientWriteQuorum:
-		apiErr = ErrWriteQuorum
-	case InsufficientReadQuorum:
-		apiErr = ErrReadQuorum
-	case UnsupportedDelimiter:
-		apiErr = ErrNotImplemented
-	case InvalidMarkerPrefixCombination:
-		apiErr = ErrNotImplemented
-	case InvalidUploadIDKeyCombination:
-		apiErr = ErrNotImplemented
-	case MalformedUploadID:
-		apiErr = ErrNoSuchUpload
-	case PartTooSmall:
-		apiErr = ErrEntityTooSmall
-	case SHA256Mismatch:
-		apiErr = ErrContentSHA256Mismatch
-	case ObjectTooLarge:
-		apiErr = ErrEntityTooLarge
-	case ObjectTooSmall:
-		apiErr = ErrEntityTooSmall
-	case NotSupported:
-		apiErr = ErrNotSupported
-	case NotImplemented:
-		apiErr = ErrNotImplemented
-	case PolicyNotFound:
-		apiErr = ErrNoSuchBucketPolicy
-	default:
-		apiErr = ErrInternalError
-	}
-
-	return apiErr
+	return ErrInternalError
 }
 
 // getAPIError provides API Error for input API error code.
@@ -720,13 +986,12 @@ func getAPIError(code APIErrorCode) APIError {
 }
 
 // getErrorResponse gets in standard error and resource value and
-// provides a encodable populated response values
+// provides a encodable populated response values, request and host
+// IDs are filled in by the caller from the response headers.
 func getAPIErrorResponse(err APIError, resource string) APIErrorResponse {
 	return APIErrorResponse{
-		Code:      err.Code,
-		Message:   err.Description,
-		Resource:  resource,
-		RequestID: "3L137",
-		HostID:    "3L137",
+		Code:     err.Code,
+		Message:  err.Description,
+		Resource: resource,
 	}
 }
@@ -21,6 +21,15 @@ import (
 	"testing"
 )
 
+// uncomparableError - error which can't be used as a map key.
+type uncomparableError struct {
+	causes []error
+}
+
+func (e uncomparableError) Error() string {
+	return "uncomparable error"
+}
+
 func TestAPIErrCode(t *testing.T) {
 	testCases := []struct {
 		err     error
@@ -118,6 +127,18 @@ func TestAPIErrCode(t *testing.T) {
 		{
 			errContentSHA256Mismatch,
 			ErrContentSHA256Mismatch,
+		},
+		{
+			PolicyNesting{},
+			ErrPolicyNesting,
+		},
+		{
+			traceError(InvalidUploadID{UploadID: "abc"}),
+			ErrNoSuchUpload,
+		},
+		{
+			traceError(errLockTimedOut),
+			ErrOperationTimedOut,
 		}, // End of all valid cases.
 
 		// Case where err is nil.
@@ -131,6 +152,12 @@ func TestAPIErrCode(t *testing.T) {
 			errors.New("Custom error"),
 			ErrInternalError,
 		},
+
+		// Case where err type is unknown and not comparable.
+		{
+			uncomparableError{},
+			ErrInternalError,
+		},
 	}
 
 	// Validate all the errors with their API error codes.
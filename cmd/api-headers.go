@@ -33,8 +33,11 @@ func mustGetRequestID(t time.Time) string {
 
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
-	// Set unique request ID for each reply.
-	w.Header().Set(responseRequestIDKey, mustGetRequestID(UTCNow()))
+	// Set unique request ID for each reply, unless already set
+	// when the request was received.
+	if w.Header().Get(responseRequestIDKey) == "" {
+		w.Header().Set(responseRequestIDKey, mustGetRequestID(UTCNow()))
+	}
 	w.Header().Set("Server", globalServerUserAgent)
 	w.Header().Set("X-Amz-Bucket-Region", serverConfig.GetRegion())
 	w.Header().Set("Accept-Ranges", "bytes")
@@ -68,9 +71,18 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 
 	// Set all other user defined metadata.
 	for k, v := range objInfo.UserDefined {
+		// Tags are only sent on GetObjectTagging.
+		if k == objectTaggingKey {
+			continue
+		}
 		w.Header().Set(k, v)
 	}
 
+	// Set number of tags on the object.
+	if count := len(getObjectTagging(objInfo.UserDefined).TagSet); count > 0 {
+		w.Header().Set(objectTaggingCountHeader, strconv.Itoa(count))
+	}
+
 	// for providing ranged content
 	if contentRange != nil && contentRange.offsetBegin > -1 {
 		// Override content-length
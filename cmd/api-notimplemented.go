@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "net/http"
+
+// notImplementedAPI - S3 API which is not implemented, identified by
+// its method and sub-resource query.
+type notImplementedAPI struct {
+	name     string
+	method   string
+	resource string
+}
+
+// List of not implemented bucket APIs.
+var notImplementedBucketAPIs = []notImplementedAPI{
+	{"GetBucketAccelerateConfiguration", httpGET, "accelerate"},
+	{"PutBucketAccelerateConfiguration", httpPUT, "accelerate"},
+	{"GetBucketCors", httpGET, "cors"},
+	{"PutBucketCors", httpPUT, "cors"},
+	{"DeleteBucketCors", httpDELETE, "cors"},
+	{"GetBucketEncryption", httpGET, "encryption"},
+	{"PutBucketEncryption", httpPUT, "encryption"},
+	{"DeleteBucketEncryption", httpDELETE, "encryption"},
+	{"GetBucketInventoryConfiguration", httpGET, "inventory"},
+	{"PutBucketInventoryConfiguration", httpPUT, "inventory"},
+	{"DeleteBucketInventoryConfiguration", httpDELETE, "inventory"},
+	{"GetBucketLogging", httpGET, "logging"},
+	{"PutBucketLogging", httpPUT, "logging"},
+	{"GetBucketMetricsConfiguration", httpGET, "metrics"},
+	{"PutBucketMetricsConfiguration", httpPUT, "metrics"},
+	{"DeleteBucketMetricsConfiguration", httpDELETE, "metrics"},
+	{"GetPublicAccessBlock", httpGET, "publicAccessBlock"},
+	{"PutPublicAccessBlock", httpPUT, "publicAccessBlock"},
+	{"DeletePublicAccessBlock", httpDELETE, "publicAccessBlock"},
+	{"GetBucketReplication", httpGET, "replication"},
+	{"PutBucketReplication", httpPUT, "replication"},
+	{"DeleteBucketReplication", httpDELETE, "replication"},
+	{"GetBucketRequestPayment", httpGET, "requestPayment"},
+	{"PutBucketRequestPayment", httpPUT, "requestPayment"},
+	{"GetBucketVersioning", httpGET, "versioning"},
+	{"PutBucketVersioning", httpPUT, "versioning"},
+	{"ListObjectVersions", httpGET, "versions"},
+}
+
+// List of not implemented object APIs.
+var notImplementedObjectAPIs = []notImplementedAPI{
+	{"GetObjectAcl", httpGET, "acl"},
+	{"PutObjectAcl", httpPUT, "acl"},
+	// Policies are set on buckets only.
+	{"GetObjectPolicy", httpGET, "policy"},
+	{"PutObjectPolicy", httpPUT, "policy"},
+	{"DeleteObjectPolicy", httpDELETE, "policy"},
+	{"RestoreObject", httpPOST, "restore"},
+	{"SelectObjectContent", httpPOST, "select"},
+	{"GetObjectTorrent", httpGET, "torrent"},
+}
+
+// getNotImplementedAPI - returns the name of the not implemented API a
+// request is for. Requests with another method on the sub-resource of
+// a not implemented API are not implemented either, they are returned
+// without name.
+func getNotImplementedAPI(r *http.Request) (name string, notImplemented bool) {
+	bucketName, objectName := urlPath2BucketObjectName(r.URL)
+	var apis []notImplementedAPI
+	switch {
+	case bucketName == "":
+		return "", false
+	case objectName == "":
+		apis = notImplementedBucketAPIs
+	default:
+		apis = notImplementedObjectAPIs
+	}
+
+	query := r.URL.Query()
+	for _, api := range apis {
+		if _, ok := query[api.resource]; !ok {
+			continue
+		}
+		if api.method == r.Method {
+			return api.name, true
+		}
+		notImplemented = true
+	}
+	return "", notImplemented
+}
+
+// notImplementedHandler - answers requests of not implemented APIs with
+// a NotImplemented error.
+func notImplementedHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, ErrNotImplemented, r.URL)
+}
+
+// notFoundHandler - answers requests not matching any route. S3
+// requests get a NotImplemented error, as SDKs expect an S3 error to
+// not retry, others like RPC and admin requests get a plain 404.
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	if bucketName, _ := urlPath2BucketObjectName(r.URL); isMinioReservedBucket(bucketName) {
+		http.NotFound(w, r)
+		return
+	}
+	collectAPIStats("NotImplemented", notImplementedHandler)(w, r)
+}
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests requests are matched with not implemented APIs.
+func TestGetNotImplementedAPI(t *testing.T) {
+	testCases := []struct {
+		method         string
+		path           string
+		name           string
+		notImplemented bool
+	}{
+		// Test 1: bucket API.
+		{httpGET, "/bucket?cors", "GetBucketCors", true},
+		// Test 2: bucket API with other queries.
+		{httpPUT, "/bucket?versioning&x-id=PutBucketVersioning", "PutBucketVersioning", true},
+		// Test 3: another method on a not implemented resource.
+		{httpPOST, "/bucket?cors", "", true},
+		// Test 4: object API.
+		{httpGET, "/bucket/object?torrent", "GetObjectTorrent", true},
+		// Test 5: object API with a POST method.
+		{httpPOST, "/bucket/object?select&select-type=2", "SelectObjectContent", true},
+		// Test 6: bucket resource on an object is S3 API of the object.
+		{httpGET, "/bucket/object?cors", "", false},
+		// Test 7: implemented bucket API.
+		{httpGET, "/bucket?acl", "", false},
+		// Test 8: implemented object API.
+		{httpGET, "/bucket/object?tagging", "", false},
+		// Test 9: plain object request.
+		{httpGET, "/bucket/object", "", false},
+		// Test 10: root path.
+		{httpGET, "/?cors", "", false},
+	}
+	for i, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.path, nil)
+		name, notImplemented := getNotImplementedAPI(req)
+		if name != testCase.name || notImplemented != testCase.notImplemented {
+			t.Errorf("Test %d: expected %q, %v, got %q, %v", i+1, testCase.name, testCase.notImplemented, name, notImplemented)
+		}
+	}
+}
+
+// Tests not implemented APIs and unmatched S3 requests get a
+// NotImplemented error with resource and request ID.
+func TestNotImplementedResponse(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	mux := router.NewRouter().SkipClean(true)
+	registerHealthCheckRouter(mux)
+	registerAPIRouter(mux, newGlobalServerContext())
+	handler := setRequestIDHandler(setIgnoreResourcesHandler(mux))
+
+	testCases := []struct {
+		method       string
+		path         string
+		expectedCode int
+		resource     string
+	}{
+		// Test 1: not implemented bucket API.
+		{httpDELETE, "/bucket?replication", http.StatusNotImplemented, "/bucket"},
+		// Test 2: not implemented object API.
+		{httpPOST, "/bucket/object?restore", http.StatusNotImplemented, "/bucket/object"},
+		// Test 3: no route for the method on the root path.
+		{httpDELETE, "/", http.StatusNotImplemented, "/"},
+		// Test 4: unknown method.
+		{"PATCH", "/bucket/object", http.StatusNotImplemented, "/bucket/object"},
+		// Test 5: unmatched reserved paths are not S3 requests.
+		{"PATCH", healthCheckLivenessPath, http.StatusNotFound, ""},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(testCase.method, testCase.path, nil))
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d", i+1, testCase.expectedCode, rec.Code)
+		}
+		if testCase.expectedCode != http.StatusNotImplemented {
+			continue
+		}
+		var errResp APIErrorResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if errResp.Code != "NotImplemented" || errResp.Resource != testCase.resource {
+			t.Errorf("Test %d: unexpected error %#v", i+1, errResp)
+		}
+		if errResp.RequestID == "" || errResp.RequestID != rec.Header().Get(responseRequestIDKey) {
+			t.Errorf("Test %d: expected request ID %q, got %q", i+1, rec.Header().Get(responseRequestIDKey), errResp.RequestID)
+		}
+	}
+}
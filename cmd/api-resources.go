@@ -17,19 +17,57 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"net/url"
 	"strconv"
 )
 
+// Only supported encoding type of listings, object names are then
+// URL encoded in responses.
+const urlEncodingType = "url"
+
+// Query parameter restricting the fields of listed objects, to shrink
+// responses of large listings.
+const listObjectsFieldsQueryKey = "x-minio-fields"
+
+// listObjectsFields - fields of listed objects in responses.
+type listObjectsFields int
+
+const (
+	// All fields, S3 compatible.
+	listObjectsFieldsAll listObjectsFields = iota
+	// Key only, x-minio-fields=key
+	listObjectsFieldsKey
+	// Key and size, x-minio-fields=key,size
+	listObjectsFieldsKeySize
+)
+
+// getListObjectsFields - parses fields of listed objects requested
+// through x-minio-fields, in any order.
+func getListObjectsFields(values url.Values) (listObjectsFields, APIErrorCode) {
+	if _, ok := values[listObjectsFieldsQueryKey]; !ok {
+		return listObjectsFieldsAll, ErrNone
+	}
+	switch values.Get(listObjectsFieldsQueryKey) {
+	case "key":
+		return listObjectsFieldsKey, ErrNone
+	case "key,size", "size,key":
+		return listObjectsFieldsKeySize, ErrNone
+	}
+	return listObjectsFieldsAll, ErrInvalidListFields
+}
+
 // Parse bucket url queries
 func getListObjectsV1Args(values url.Values) (prefix, marker, delimiter string, maxkeys int, encodingType string) {
 	prefix = values.Get("prefix")
 	marker = values.Get("marker")
 	delimiter = values.Get("delimiter")
+	maxkeys = getListMaxKeys()
 	if values.Get("max-keys") != "" {
-		maxkeys, _ = strconv.Atoi(values.Get("max-keys"))
-	} else {
-		maxkeys = maxObjectList
+		// Keys beyond the configured limit are listed by further calls.
+		if requested, _ := strconv.Atoi(values.Get("max-keys")); requested < maxkeys {
+			maxkeys = requested
+		}
 	}
 	encodingType = values.Get("encoding-type")
 	return
@@ -41,16 +79,34 @@ func getListObjectsV2Args(values url.Values) (prefix, token, startAfter, delimit
 	token = values.Get("continuation-token")
 	startAfter = values.Get("start-after")
 	delimiter = values.Get("delimiter")
+	maxkeys = getListMaxKeys()
 	if values.Get("max-keys") != "" {
-		maxkeys, _ = strconv.Atoi(values.Get("max-keys"))
-	} else {
-		maxkeys = maxObjectList
+		// Keys beyond the configured limit are listed by further calls.
+		if requested, _ := strconv.Atoi(values.Get("max-keys")); requested < maxkeys {
+			maxkeys = requested
+		}
 	}
 	fetchOwner = values.Get("fetch-owner") == "true"
 	encodingType = values.Get("encoding-type")
 	return
 }
 
+// encodeContinuationToken - returns the continuation token of a
+// ListObjectsV2 listing going on after marker, tokens are opaque to
+// clients.
+func encodeContinuationToken(marker string) string {
+	return base64.StdEncoding.EncodeToString([]byte(marker))
+}
+
+// decodeContinuationToken - returns the marker of a continuation token.
+func decodeContinuationToken(token string) (string, error) {
+	marker, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	return string(marker), nil
+}
+
 // Parse bucket url queries for ?uploads
 func getBucketMultipartResources(values url.Values) (prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int, encodingType string) {
 	prefix = values.Get("prefix")
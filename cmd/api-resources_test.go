@@ -224,3 +224,42 @@ func TestValidateFilterValues(t *testing.T) {
 		}
 	}
 }
+
+// Tests continuation tokens of ListObjectsV2 carry the marker.
+func TestContinuationToken(t *testing.T) {
+	for _, marker := range []string{"", "object", "dir/object", "a b+c/ü"} {
+		decoded, err := decodeContinuationToken(encodeContinuationToken(marker))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != marker {
+			t.Errorf("Expected %s, got %s", marker, decoded)
+		}
+	}
+	if _, err := decodeContinuationToken("not a token"); err == nil {
+		t.Error("Expected malformed token to be rejected")
+	}
+}
+
+// Tests parsing fields of listed objects.
+func TestGetListObjectsFields(t *testing.T) {
+	testCases := []struct {
+		values   url.Values
+		expected listObjectsFields
+		errCode  APIErrorCode
+	}{
+		{url.Values{}, listObjectsFieldsAll, ErrNone},
+		{url.Values{"x-minio-fields": []string{"key"}}, listObjectsFieldsKey, ErrNone},
+		{url.Values{"x-minio-fields": []string{"key,size"}}, listObjectsFieldsKeySize, ErrNone},
+		{url.Values{"x-minio-fields": []string{"size,key"}}, listObjectsFieldsKeySize, ErrNone},
+		{url.Values{"x-minio-fields": []string{""}}, listObjectsFieldsAll, ErrInvalidListFields},
+		{url.Values{"x-minio-fields": []string{"size"}}, listObjectsFieldsAll, ErrInvalidListFields},
+		{url.Values{"x-minio-fields": []string{"key,etag"}}, listObjectsFieldsAll, ErrInvalidListFields},
+	}
+	for i, testCase := range testCases {
+		fields, errCode := getListObjectsFields(testCase.values)
+		if fields != testCase.expected || errCode != testCase.errCode {
+			t.Errorf("Test %d: Expected (%d, %d), got (%d, %d)", i+1, testCase.expected, testCase.errCode, fields, errCode)
+		}
+	}
+}
@@ -16,26 +16,32 @@
 
 package cmd
 
-import "net/http"
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"sync"
+	"time"
+)
 
 // Represents additional fields necessary for ErrPartTooSmall S3 error.
 type completeMultipartAPIError struct {
 	// Proposed size represents uploaded size of the part.
 	ProposedSize int64
-	// Minimum size allowed epresents the minimum size allowed per
-	// part. Defaults to 5MB.
+	// Minimum size allowed represents the minimum size allowed per
+	// part. Defaults to 5MiB, configurable in config.
 	MinSizeAllowed int64
 	// Part number of the part which is incorrect.
 	PartNumber int
 	// ETag of the part which is incorrect.
-	PartETag string
+	PartETag string `xml:"ETag"`
 	// Other default XML error responses.
 	APIErrorResponse
 }
 
 // writeErrorResponsePartTooSmall - function is used specifically to
 // construct a proper error response during CompleteMultipartUpload
-// when one of the parts is < 5MB.
+// when one of the parts is smaller than minimum part size.
 // The requirement comes due to the fact that generic ErrorResponse
 // XML doesn't carry the additional fields required to send this
 // error. So we construct a new type which lies well within the scope
@@ -45,7 +51,9 @@ func writePartSmallErrorResponse(w http.ResponseWriter, r *http.Request, err Par
 	apiError := getAPIError(toAPIErrorCode(err))
 	// Generate complete multipart error response.
 	errorResponse := getAPIErrorResponse(apiError, r.URL.Path)
-	cmpErrResp := completeMultipartAPIError{err.PartSize, int64(5242880), err.PartNumber, err.PartETag, errorResponse}
+	errorResponse.RequestID = w.Header().Get(responseRequestIDKey)
+	errorResponse.HostID = w.Header().Get(responseHostIDKey)
+	cmpErrResp := completeMultipartAPIError{err.PartSize, err.MinSizeAllowed, err.PartNumber, err.PartETag, errorResponse}
 	encodedErrorResponse := encodeResponse(cmpErrResp)
 
 	// respond with 400 bad request.
@@ -54,3 +62,91 @@ func writePartSmallErrorResponse(w http.ResponseWriter, r *http.Request, err Par
 	w.Write(encodedErrorResponse)
 	w.(http.Flusher).Flush()
 }
+
+// keepAliveWriter - sends whitespace keep-alives to the client while
+// a long running CompleteMultipartUpload is in progress, so that the
+// connection is not cut off by client or load balancer timeouts.
+// Once the first keep-alive is sent the response is a 200 OK, the
+// final response, success or error, is then written to the body
+// only, the same way AWS S3 does it. Clients are expected to parse
+// the body for an <Error> element, such error responses are never
+// remembered for Idempotency-Key retries.
+type keepAliveWriter struct {
+	http.ResponseWriter
+
+	r       *http.Request
+	started bool
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// startKeepAlive - sends a keep-alive every interval until stop is
+// called on the returned writer.
+func startKeepAlive(w http.ResponseWriter, r *http.Request, interval time.Duration) *keepAliveWriter {
+	kw := &keepAliveWriter{ResponseWriter: w, r: r, doneCh: make(chan struct{})}
+	kw.wg.Add(1)
+	go func() {
+		defer kw.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				kw.sendKeepAlive()
+			case <-kw.doneCh:
+				return
+			}
+		}
+	}()
+	return kw
+}
+
+// sendKeepAlive - writes the response headers and the XML header on
+// the first call, and a whitespace on every call.
+func (kw *keepAliveWriter) sendKeepAlive() {
+	if !kw.started {
+		kw.started = true
+		setCommonHeaders(kw.ResponseWriter)
+		kw.ResponseWriter.Header().Set("Content-Type", string(mimeXML))
+		kw.ResponseWriter.WriteHeader(http.StatusOK)
+		kw.ResponseWriter.Write([]byte(xml.Header))
+	}
+	kw.ResponseWriter.Write([]byte(" "))
+	kw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// stop - stops sending keep-alives, the writer is then used to write
+// the final response.
+func (kw *keepAliveWriter) stop() {
+	close(kw.doneCh)
+	kw.wg.Wait()
+}
+
+// WriteHeader - the status is already sent if keep-alives were sent,
+// an error is then only reported in the body.
+func (kw *keepAliveWriter) WriteHeader(statusCode int) {
+	if kw.started {
+		if statusCode < 200 || statusCode > 299 {
+			markResponseUncacheable(kw.r)
+		}
+		return
+	}
+	kw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write - the XML header is already sent if keep-alives were sent.
+func (kw *keepAliveWriter) Write(p []byte) (int, error) {
+	if kw.started {
+		n, err := kw.ResponseWriter.Write(bytes.TrimPrefix(p, []byte(xml.Header)))
+		if err != nil {
+			return n, err
+		}
+		return len(p), nil
+	}
+	return kw.ResponseWriter.Write(p)
+}
+
+// Flush - flushes the buffered response to the client.
+func (kw *keepAliveWriter) Flush() {
+	kw.ResponseWriter.(http.Flusher).Flush()
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests the final response written after keep-alives.
+func TestKeepAliveWriter(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed: %s", err)
+	}
+	defer removeAll(rootPath)
+
+	reqURL := &url.URL{Path: "/bucket/object"}
+	testCases := []struct {
+		interval     time.Duration
+		wait         time.Duration
+		expectedCode int
+		keepAlive    bool
+	}{
+		// Test 1: completes before the first keep-alive, the error
+		// status is sent as is.
+		{time.Hour, 0, http.StatusBadRequest, false},
+		// Test 2: keep-alives are sent, the error is written after
+		// them with a 200 OK.
+		{time.Millisecond, 50 * time.Millisecond, http.StatusOK, true},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		kw := startKeepAlive(rec, &http.Request{URL: reqURL}, testCase.interval)
+		time.Sleep(testCase.wait)
+		kw.stop()
+		writeErrorResponse(kw, ErrInvalidPart, reqURL)
+
+		if rec.Code != testCase.expectedCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectedCode, rec.Code)
+		}
+		body := rec.Body.String()
+		if !strings.HasPrefix(body, xml.Header) {
+			t.Errorf("Test %d: Expected the body to start with the XML header, got %q", i+1, body)
+		}
+		if strings.Count(body, xml.Header) != 1 {
+			t.Errorf("Test %d: Expected one XML header, got %q", i+1, body)
+		}
+		if keepAlive := strings.HasPrefix(body, xml.Header+" "); keepAlive != testCase.keepAlive {
+			t.Errorf("Test %d: Expected keep-alives to be %v, got %q", i+1, testCase.keepAlive, body)
+		}
+		errResp := APIErrorResponse{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("Test %d: Unable to parse the response: %s", i+1, err)
+		}
+		if errResp.Code != "InvalidPart" {
+			t.Errorf("Test %d: Expected error code InvalidPart, got %s", i+1, errResp.Code)
+		}
+	}
+}
+
+// Tests an error written after keep-alives is not remembered for
+// Idempotency-Key retries even though it is sent with a 200 OK.
+func TestKeepAliveWriterIdempotency(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed: %s", err)
+	}
+	defer removeAll(rootPath)
+	cred := serverConfig.GetCredential()
+
+	calls := 0
+	handler := idempotencyHandler{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			kw := startKeepAlive(w, r, time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			kw.stop()
+			writeErrorResponse(kw, ErrInvalidPart, r.URL)
+		}),
+		cache: newIdempotencyCache(time.Minute, maxIdempotencyEntries),
+	}
+
+	for i := 1; i <= 2; i++ {
+		req, err := newTestSignedRequestV4("POST", "http://localhost:9000/bucket/object?uploadId=abc", 0,
+			nil, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i, err)
+		}
+		req.Header.Set(idempotencyKeyHeader, "key")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: Expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "<Code>InvalidPart</Code>") {
+			t.Fatalf("Test %d: Expected InvalidPart error in the body, got %q", i, rec.Body.String())
+		}
+		if rec.Header().Get(idempotentReplayedHeader) != "" {
+			t.Fatalf("Test %d: Expected the error not to be replayed", i)
+		}
+		if calls != i {
+			t.Fatalf("Test %d: Expected %d calls, got %d", i, i, calls)
+		}
+	}
+}
@@ -18,9 +18,11 @@ package cmd
 
 import (
 	"encoding/xml"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 )
 
@@ -194,12 +196,40 @@ type Object struct {
 	ETag         string
 	Size         int64
 
-	// Owner of the object.
-	Owner Owner
+	// Owner of the object, omitted by ListObjectsV2 unless
+	// fetch-owner is set.
+	Owner *Owner `xml:"Owner,omitempty"`
 
 	// The class of storage used to store the object.
 	StorageClass   string
 	HealObjectInfo *HealObjectInfo `xml:"HealObjectInfo,omitempty"`
+
+	// Fields serialized in listings, all of them by default.
+	fields listObjectsFields
+}
+
+// MarshalXML - serializes only the fields of an object requested
+// through x-minio-fields.
+func (o Object) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch o.fields {
+	case listObjectsFieldsKey:
+		return e.EncodeElement(struct{ Key string }{o.Key}, start)
+	case listObjectsFieldsKeySize:
+		return e.EncodeElement(struct {
+			Key  string
+			Size int64
+		}{o.Key, o.Size}, start)
+	}
+	// Conversion drops this method, avoiding recursion.
+	type object Object
+	return e.EncodeElement(object(o), start)
+}
+
+// setListObjectsFields - restricts serialized fields of listed objects.
+func setListObjectsFields(contents []Object, fields listObjectsFields) {
+	for i := range contents {
+		contents[i].fields = fields
+	}
 }
 
 // CopyObjectResponse container returns ETag and LastModified of the successfully copied object
@@ -319,14 +349,18 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 		if object.Name == "" {
 			continue
 		}
+		// Report transitioned objects as they were before transition.
+		if isObjectTransitioned(object) {
+			object = getTransitionedObjectInfo(object)
+		}
 		content.Key = object.Name
 		content.LastModified = object.ModTime.UTC().Format(timeFormatAMZLong)
 		if object.MD5Sum != "" {
 			content.ETag = "\"" + object.MD5Sum + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = globalMinioDefaultStorageClass
-		content.Owner = owner
+		content.StorageClass = getObjectStorageClass(object)
+		content.Owner = &owner
 		// object.HealObjectInfo is non-empty only when resp is constructed in ListObjectsHeal.
 		content.HealObjectInfo = object.HealObjectInfo
 		contents = append(contents, content)
@@ -351,16 +385,27 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 	return data
 }
 
+// encodeListName - returns name of a listing encoded as requested
+// by encodingType, names are only encoded for 'url'.
+func encodeListName(name, encodingType string) string {
+	if strings.EqualFold(encodingType, urlEncodingType) {
+		return getURLEncodedName(name)
+	}
+	return name
+}
+
 // generates an ListObjectsV2 response for the said bucket with other enumerated options.
-func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter string, fetchOwner bool, maxKeys int, resp ListObjectsInfo) ListObjectsV2Response {
+func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, encodingType string, fetchOwner bool, maxKeys int, resp ListObjectsInfo) ListObjectsV2Response {
 	var contents []Object
 	var prefixes []CommonPrefix
-	var owner = Owner{}
+	var owner *Owner
 	var data = ListObjectsV2Response{}
 
 	if fetchOwner {
-		owner.ID = globalMinioDefaultOwnerID
-		owner.DisplayName = globalMinioDefaultOwnerID
+		owner = &Owner{
+			ID:          globalMinioDefaultOwnerID,
+			DisplayName: globalMinioDefaultOwnerID,
+		}
 	}
 
 	for _, object := range resp.Objects {
@@ -368,30 +413,36 @@ func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter
 		if object.Name == "" {
 			continue
 		}
-		content.Key = object.Name
+		// Report transitioned objects as they were before transition.
+		if isObjectTransitioned(object) {
+			object = getTransitionedObjectInfo(object)
+		}
+		content.Key = encodeListName(object.Name, encodingType)
 		content.LastModified = object.ModTime.UTC().Format(timeFormatAMZLong)
 		if object.MD5Sum != "" {
 			content.ETag = "\"" + object.MD5Sum + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = globalMinioDefaultStorageClass
+		content.StorageClass = getObjectStorageClass(object)
 		content.Owner = owner
 		contents = append(contents, content)
 	}
-	// TODO - support EncodingType in xml decoding
 	data.Name = bucket
 	data.Contents = contents
 
-	data.StartAfter = startAfter
-	data.Delimiter = delimiter
-	data.Prefix = prefix
+	data.EncodingType = encodingType
+	data.StartAfter = encodeListName(startAfter, encodingType)
+	data.Delimiter = encodeListName(delimiter, encodingType)
+	data.Prefix = encodeListName(prefix, encodingType)
 	data.MaxKeys = maxKeys
 	data.ContinuationToken = token
-	data.NextContinuationToken = resp.NextMarker
 	data.IsTruncated = resp.IsTruncated
+	if resp.IsTruncated {
+		data.NextContinuationToken = encodeContinuationToken(resp.NextMarker)
+	}
 	for _, prefix := range resp.Prefixes {
 		var prefixItem = CommonPrefix{}
-		prefixItem.Prefix = prefix
+		prefixItem.Prefix = encodeListName(prefix, encodingType)
 		prefixes = append(prefixes, prefixItem)
 	}
 	data.CommonPrefixes = prefixes
@@ -561,6 +612,14 @@ func writeErrorResponse(w http.ResponseWriter, errorCode APIErrorCode, reqURL *u
 	apiError := getAPIError(errorCode)
 	// Generate error response.
 	errorResponse := getAPIErrorResponse(apiError, reqURL.Path)
+	if errorCode == ErrAuthorizationHeaderMalformed {
+		// Let the client know the region it should sign with.
+		region := serverConfig.GetRegion()
+		errorResponse.Message = fmt.Sprintf("The authorization header is malformed; the region is wrong; expecting '%s'.", region)
+		errorResponse.Region = region
+	}
+	errorResponse.RequestID = w.Header().Get(responseRequestIDKey)
+	errorResponse.HostID = w.Header().Get(responseHostIDKey)
 	encodedErrorResponse := encodeResponse(errorResponse)
 	writeResponse(w, apiError.HTTPStatusCode, encodedErrorResponse, mimeXML)
 }
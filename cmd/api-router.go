@@ -16,19 +16,21 @@
 
 package cmd
 
-import router "github.com/gorilla/mux"
+import (
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
 
 // objectAPIHandler implements and provides http handlers for S3 API.
 type objectAPIHandlers struct {
-	ObjectAPI func() ObjectLayer
+	serverContext
 }
 
 // registerAPIRouter - registers S3 compatible APIs.
-func registerAPIRouter(mux *router.Router) {
+func registerAPIRouter(mux *router.Router, ctx serverContext) {
 	// Initialize API.
-	api := objectAPIHandlers{
-		ObjectAPI: newObjectLayerFn,
-	}
+	api := objectAPIHandlers{ctx}
 
 	// API Router
 	apiRouter := mux.NewRoute().PathPrefix("/").Subrouter()
@@ -39,63 +41,130 @@ func registerAPIRouter(mux *router.Router) {
 	/// Object operations
 
 	// HeadObject
-	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(api.HeadObjectHandler)
+	bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(collectAPIStats("HeadObject", api.HeadObjectHandler))
 	// CopyObjectPart
-	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
+	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(collectAPIStats("CopyObjectPart", api.CopyObjectPartHandler)).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 	// PutObjectPart
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(collectAPIStats("PutObjectPart", api.PutObjectPartHandler)).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
+	// GetObjectTagging
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("GetObjectTagging", api.GetObjectTaggingHandler)).Queries("tagging", "")
+	// PutObjectTagging
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(collectAPIStats("PutObjectTagging", api.PutObjectTaggingHandler)).Queries("tagging", "")
+	// DeleteObjectTagging
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(collectAPIStats("DeleteObjectTagging", api.DeleteObjectTaggingHandler)).Queries("tagging", "")
+	// GetObjectRetention
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("GetObjectRetention", api.GetObjectRetentionHandler)).Queries("retention", "")
+	// PutObjectRetention
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(collectAPIStats("PutObjectRetention", api.PutObjectRetentionHandler)).Queries("retention", "")
+	// GetObjectLegalHold
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("GetObjectLegalHold", api.GetObjectLegalHoldHandler)).Queries("legal-hold", "")
+	// PutObjectLegalHold
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(collectAPIStats("PutObjectLegalHold", api.PutObjectLegalHoldHandler)).Queries("legal-hold", "")
 	// ListObjectPxarts
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.ListObjectPartsHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("ListObjectParts", api.ListObjectPartsHandler)).Queries("uploadId", "{uploadId:.*}")
 	// CompleteMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(collectAPIStats("CompleteMultipartUpload", api.CompleteMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
 	// NewMultipartUpload
-	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(collectAPIStats("NewMultipartUpload", api.NewMultipartUploadHandler)).Queries("uploads", "")
 	// AbortMultipartUpload
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.AbortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(collectAPIStats("AbortMultipartUpload", api.AbortMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
+	// PartSizeHint (Minio extension)
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("PartSizeHint", api.PartSizeHintHandler)).Queries(partSizeHintQuery, "")
+	// GetObjectValidators (Minio extension)
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("GetObjectValidators", api.GetObjectValidatorsHandler)).Queries(objectValidatorsQuery, "")
+	// ComposeObject (Minio extension)
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(collectAPIStats("ComposeObject", api.ComposeObjectHandler)).Queries(composeQuery, "")
+	// MoveObject (Minio extension)
+	bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(collectAPIStats("MoveObject", api.MoveObjectHandler)).Queries(moveQuery, "")
 	// GetObject
-	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+	bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(collectAPIStats("GetObject", api.GetObjectHandler))
 	// CopyObject
-	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectHandler)
+	bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(collectAPIStats("CopyObject", api.CopyObjectHandler))
 	// PutObject
-	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(collectAPIStats("PutObject", api.PutObjectHandler))
 	// DeleteObject
-	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectHandler)
+	bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(collectAPIStats("DeleteObject", api.DeleteObjectHandler))
 
 	/// Bucket operations
 
 	// GetBucketLocation
-	bucket.Methods("GET").HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketLocation", api.GetBucketLocationHandler)).Queries("location", "")
 	// GetBucketPolicy
-	bucket.Methods("GET").HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketPolicy", api.GetBucketPolicyHandler)).Queries("policy", "")
+	// GetBucketACL
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketACL", api.GetBucketACLHandler)).Queries("acl", "")
+	// GetBucketTagging
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketTagging", api.GetBucketTaggingHandler)).Queries("tagging", "")
+	// GetBucketLifecycle
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketLifecycle", api.GetBucketLifecycleHandler)).Queries("lifecycle", "")
+	// GetBucketDefaultMetadata (Minio extension)
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketDefaultMetadata", api.GetBucketDefaultMetadataHandler)).Queries(defaultMetadataQuery, "")
+	// GetBucketWebsite
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketWebsite", api.GetBucketWebsiteHandler)).Queries("website", "")
+	// GetObjectLockConfiguration
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetObjectLockConfiguration", api.GetBucketObjectLockConfigHandler)).Queries("object-lock", "")
+	// GetBucketAnalytics
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketAnalytics", api.GetBucketAnalyticsHandler)).Queries("analytics", "", "id", "{id:.+}")
+	// ListBucketAnalytics
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("ListBucketAnalytics", api.ListBucketAnalyticsHandler)).Queries("analytics", "")
 	// GetBucketNotification
-	bucket.Methods("GET").HandlerFunc(api.GetBucketNotificationHandler).Queries("notification", "")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("GetBucketNotification", api.GetBucketNotificationHandler)).Queries("notification", "")
 	// ListenBucketNotification
-	bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("ListenBucketNotification", api.ListenBucketNotificationHandler)).Queries("events", "{events:.*}")
 	// ListMultipartUploads
-	bucket.Methods("GET").HandlerFunc(api.ListMultipartUploadsHandler).Queries("uploads", "")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("ListMultipartUploads", api.ListMultipartUploadsHandler)).Queries("uploads", "")
 	// ListObjectsV2
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("ListObjectsV2", api.ListObjectsV2Handler)).Queries("list-type", "2")
 	// ListObjectsV1 (Legacy)
-	bucket.Methods("GET").HandlerFunc(api.ListObjectsV1Handler)
+	bucket.Methods("GET").HandlerFunc(collectAPIStats("ListObjectsV1", api.ListObjectsV1Handler))
 	// PutBucketPolicy
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketPolicyHandler).Queries("policy", "")
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketPolicy", api.PutBucketPolicyHandler)).Queries("policy", "")
+	// PutBucketACL
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketACL", api.PutBucketACLHandler)).Queries("acl", "")
+	// PutBucketTagging
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketTagging", api.PutBucketTaggingHandler)).Queries("tagging", "")
+	// PutBucketLifecycle
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketLifecycle", api.PutBucketLifecycleHandler)).Queries("lifecycle", "")
+	// PutBucketDefaultMetadata (Minio extension)
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketDefaultMetadata", api.PutBucketDefaultMetadataHandler)).Queries(defaultMetadataQuery, "")
+	// PutBucketWebsite
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketWebsite", api.PutBucketWebsiteHandler)).Queries("website", "")
+	// PutObjectLockConfiguration
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutObjectLockConfiguration", api.PutBucketObjectLockConfigHandler)).Queries("object-lock", "")
+	// PutBucketAnalytics
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketAnalytics", api.PutBucketAnalyticsHandler)).Queries("analytics", "")
 	// PutBucketNotification
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketNotificationHandler).Queries("notification", "")
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucketNotification", api.PutBucketNotificationHandler)).Queries("notification", "")
 	// PutBucket
-	bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
+	bucket.Methods("PUT").HandlerFunc(collectAPIStats("PutBucket", api.PutBucketHandler))
 	// HeadBucket
-	bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
+	bucket.Methods("HEAD").HandlerFunc(collectAPIStats("HeadBucket", api.HeadBucketHandler))
 	// PostPolicy
-	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(api.PostPolicyBucketHandler)
+	bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(collectAPIStats("PostPolicyBucket", api.PostPolicyBucketHandler))
 	// DeleteMultipleObjects
-	bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler)
+	bucket.Methods("POST").HandlerFunc(collectAPIStats("DeleteMultipleObjects", api.DeleteMultipleObjectsHandler))
 	// DeleteBucketPolicy
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketPolicyHandler).Queries("policy", "")
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketPolicy", api.DeleteBucketPolicyHandler)).Queries("policy", "")
+	// DeleteBucketTagging
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketTagging", api.DeleteBucketTaggingHandler)).Queries("tagging", "")
+	// DeleteBucketLifecycle
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketLifecycle", api.DeleteBucketLifecycleHandler)).Queries("lifecycle", "")
+	// DeleteBucketDefaultMetadata (Minio extension)
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketDefaultMetadata", api.DeleteBucketDefaultMetadataHandler)).Queries(defaultMetadataQuery, "")
+	// DeleteBucketWebsite
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketWebsite", api.DeleteBucketWebsiteHandler)).Queries("website", "")
+	// DeleteBucketAnalytics
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucketAnalytics", api.DeleteBucketAnalyticsHandler)).Queries("analytics", "")
 	// DeleteBucket
-	bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketHandler)
+	bucket.Methods("DELETE").HandlerFunc(collectAPIStats("DeleteBucket", api.DeleteBucketHandler))
 
 	/// Root operation
 
 	// ListBuckets
-	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
+	apiRouter.Methods("GET").HandlerFunc(collectAPIStats("ListBuckets", api.ListBucketsHandler))
+
+	/// Not implemented APIs
+
+	// Requests not matching any route.
+	mux.NotFoundHandler = http.HandlerFunc(notFoundHandler)
 }
@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// Upper bounds of the latency histogram buckets of S3 APIs, requests
+// slower than the last bound are counted in an additional bucket.
+var apiLatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// apiStats - latency histogram and in-flight count of one S3 API.
+type apiStats struct {
+	inFlight atomic.Int64
+	count    atomic.Uint64
+	duration atomic.Float64
+	buckets  []atomic.Uint64
+}
+
+// newAPIStats - returns empty stats, with a bucket per latency
+// bound and one for slower requests.
+func newAPIStats() *apiStats {
+	return &apiStats{buckets: make([]atomic.Uint64, len(apiLatencyBuckets)+1)}
+}
+
+// observe - records a served request which took duration.
+func (s *apiStats) observe(duration time.Duration) {
+	i := 0
+	for i < len(apiLatencyBuckets) && duration > apiLatencyBuckets[i] {
+		i++
+	}
+	s.buckets[i].Inc()
+	s.count.Inc()
+	s.duration.Add(duration.Seconds())
+}
+
+// APILatencyBucket - number of requests served within the bucket
+// bound and above the bound of the previous bucket, the bound of
+// the last bucket is "+Inf".
+type APILatencyBucket struct {
+	LE    string `json:"le"`
+	Count uint64 `json:"count"`
+}
+
+// APIStats - stats of an S3 API reported in service status.
+type APIStats struct {
+	InFlight    int64              `json:"inFlight"`
+	Count       uint64             `json:"count"`
+	AvgDuration string             `json:"avgDuration"`
+	Histogram   []APILatencyBucket `json:"histogram"`
+}
+
+// toAPIStats - converts the stats to be sent back to the client.
+func (s *apiStats) toAPIStats() APIStats {
+	stats := APIStats{
+		InFlight:    s.inFlight.Load(),
+		Count:       s.count.Load(),
+		AvgDuration: durationStr(s.duration.Load(), float64(s.count.Load())),
+	}
+	for i := range s.buckets {
+		le := "+Inf"
+		if i < len(apiLatencyBuckets) {
+			le = apiLatencyBuckets[i].String()
+		}
+		stats.Histogram = append(stats.Histogram, APILatencyBucket{LE: le, Count: s.buckets[i].Load()})
+	}
+	return stats
+}
+
+// apiStatsCollector - stats of the S3 APIs served by this server,
+// indexed by API name.
+type apiStatsCollector struct {
+	sync.RWMutex
+	stats map[string]*apiStats
+}
+
+// newAPIStatsCollector - returns a collector without any stats.
+func newAPIStatsCollector() *apiStatsCollector {
+	return &apiStatsCollector{stats: make(map[string]*apiStats)}
+}
+
+// get - returns the stats of an API, created on first use.
+func (c *apiStatsCollector) get(api string) *apiStats {
+	c.RLock()
+	s, ok := c.stats[api]
+	c.RUnlock()
+	if ok {
+		return s
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if s, ok = c.stats[api]; !ok {
+		s = newAPIStats()
+		c.stats[api] = s
+	}
+	return s
+}
+
+// toAPIStats - converts the stats of all APIs served at least once,
+// or being served, to be sent back to the client.
+func (c *apiStatsCollector) toAPIStats() map[string]APIStats {
+	c.RLock()
+	defer c.RUnlock()
+	stats := make(map[string]APIStats)
+	for api, s := range c.stats {
+		if s.count.Load() == 0 && s.inFlight.Load() == 0 {
+			continue
+		}
+		stats[api] = s.toAPIStats()
+	}
+	return stats
+}
+
+// collectAPIStats - wraps the handler of an S3 API to count its
+// in-flight requests and record their latency.
+func collectAPIStats(api string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := globalAPIStats.get(api)
+		s.inFlight.Inc()
+		defer s.inFlight.Dec()
+
+		start := UTCNow()
+		f(w, r)
+		s.observe(UTCNow().Sub(start))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests the latency bucket requests are counted in.
+func TestAPIStatsObserve(t *testing.T) {
+	testCases := []struct {
+		duration   time.Duration
+		expectedLE string
+	}{
+		// Test 1: below the first bound.
+		{time.Millisecond, "10ms"},
+		// Test 2: bounds are inclusive.
+		{10 * time.Millisecond, "10ms"},
+		// Test 3: between two bounds.
+		{200 * time.Millisecond, "250ms"},
+		// Test 4: slower than the last bound.
+		{time.Hour, "+Inf"},
+	}
+	for i, testCase := range testCases {
+		s := newAPIStats()
+		s.observe(testCase.duration)
+		stats := s.toAPIStats()
+		if stats.Count != 1 {
+			t.Errorf("Test %d: Expected count 1, got %d", i+1, stats.Count)
+		}
+		if len(stats.Histogram) != len(apiLatencyBuckets)+1 {
+			t.Fatalf("Test %d: Expected %d buckets, got %d", i+1, len(apiLatencyBuckets)+1, len(stats.Histogram))
+		}
+		for _, bucket := range stats.Histogram {
+			expectedCount := uint64(0)
+			if bucket.LE == testCase.expectedLE {
+				expectedCount = 1
+			}
+			if bucket.Count != expectedCount {
+				t.Errorf("Test %d: Expected %d requests in bucket %s, got %d", i+1, expectedCount, bucket.LE, bucket.Count)
+			}
+		}
+	}
+}
+
+// Tests in-flight and served requests are counted per API.
+func TestCollectAPIStats(t *testing.T) {
+	defer func(stats *apiStatsCollector) { globalAPIStats = stats }(globalAPIStats)
+	globalAPIStats = newAPIStatsCollector()
+
+	var inFlight int64
+	handler := collectAPIStats("GetObject", func(w http.ResponseWriter, r *http.Request) {
+		inFlight = globalAPIStats.toAPIStats()["GetObject"].InFlight
+	})
+	for i := 0; i < 3; i++ {
+		handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/bucket/object", nil))
+	}
+	collectAPIStats("PutObject", func(w http.ResponseWriter, r *http.Request) {})
+
+	stats := globalAPIStats.toAPIStats()
+	if inFlight != 1 {
+		t.Errorf("Expected 1 request in flight while serving, got %d", inFlight)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("Expected stats of 1 API, got %v", stats)
+	}
+	if stats["GetObject"].Count != 3 || stats["GetObject"].InFlight != 0 {
+		t.Errorf("Expected 3 served and no in-flight requests, got %v", stats["GetObject"])
+	}
+}
@@ -105,7 +105,11 @@ func getRequestAuthType(r *http.Request) authType {
 	return authTypeUnknown
 }
 
-func checkRequestAuthType(r *http.Request, bucket, policyAction, region string) APIErrorCode {
+// checkRequestAuthType - checks the signature of a request, anonymous
+// requests are checked against the policy of a bucket in the object
+// layer of the server context, and requests signed with temporary
+// credentials against their session policy.
+func (ctx serverContext) checkRequestAuthType(r *http.Request, bucket, policyAction, region string) APIErrorCode {
 	reqAuthType := getRequestAuthType(r)
 
 	switch reqAuthType {
@@ -120,20 +124,38 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 		s3Error := isReqAuthenticated(r, region)
 		if s3Error != ErrNone {
 			errorIf(errSignatureMismatch, dumpRequest(r))
+			return s3Error
+		}
+		if s3Error = checkSessionPolicy(r, policyAction, r.URL.Path); s3Error != ErrNone {
+			return s3Error
+		}
+		if reqAuthType == authTypePresigned {
+			// Deny URLs of revoked share links.
+			return checkShareLink(r, bucket)
 		}
 		return s3Error
 	}
 
-	if reqAuthType == authTypeAnonymous && policyAction != "" {
+	if reqAuthType == authTypeAnonymous && bucket != "" && policyAction != "" {
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		return enforceBucketPolicy(bucket, policyAction, r.URL.Path,
-			r.Referer(), r.URL.Query())
+		return enforceBucketPolicy(ctx.ObjectAPI(), bucket, policyAction, r.URL.Path,
+			r.Referer(), getSourceIP(r), r.URL.Query())
 	}
 
 	// By default return ErrAccessDenied
 	return ErrAccessDenied
 }
 
+// checkAdminRequestAuthType - like checkRequestAuthType, except it
+// rejects temporary credentials which can't be used for admin APIs.
+func checkAdminRequestAuthType(r *http.Request, region string) APIErrorCode {
+	s3Err := newGlobalServerContext().checkRequestAuthType(r, "", "", region)
+	if s3Err == ErrNone && isRequestTemporaryCredential(r) {
+		return ErrAccessDenied
+	}
+	return s3Err
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -0,0 +1,48 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// authProvider - validates the password of a user.
+type authProvider interface {
+	// Authenticate - returns nil if password of the user is valid,
+	// errAuthentication if it is not.
+	Authenticate(username, password string) error
+}
+
+// staticAuthProvider - accepts only the credential of the server,
+// with its access key as username and secret key as password.
+type staticAuthProvider struct{}
+
+// Authenticate - validates access key and secret key against server
+// credential.
+func (staticAuthProvider) Authenticate(accessKey, secretKey string) error {
+	passedCredential, err := createCredential(accessKey, secretKey)
+	if err != nil {
+		return err
+	}
+
+	serverCred := serverConfig.GetCredential()
+
+	if serverCred.AccessKey != passedCredential.AccessKey {
+		return errInvalidAccessKeyID
+	}
+
+	if !serverCred.Equal(passedCredential) {
+		return errAuthentication
+	}
+	return nil
+}
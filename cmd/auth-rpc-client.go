@@ -93,6 +93,7 @@ func (authClient *AuthRPCClient) Login() (err error) {
 		Username:    authClient.config.accessKey,
 		Password:    authClient.config.secretKey,
 		Version:     Version,
+		CommitID:    CommitID,
 		RequestTime: UTCNow(),
 	}
 
@@ -54,6 +54,17 @@ func TestLogin(t *testing.T) {
 			skewTime:    0,
 			expectedErr: errServerVersionMismatch,
 		},
+		// Valid username, password, request time and version, not commit ID.
+		{
+			args: LoginRPCArgs{
+				Username: creds.AccessKey,
+				Password: creds.SecretKey,
+				Version:  Version,
+				CommitID: "INVALID-" + CommitID,
+			},
+			skewTime:    0,
+			expectedErr: errServerVersionMismatch,
+		},
 		// Valid username, password and version, not request time
 		{
 			args: LoginRPCArgs{
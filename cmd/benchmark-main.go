@@ -0,0 +1,326 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	minio "github.com/minio/minio-go"
+)
+
+var benchmarkFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bucket",
+		Value: "minio-benchmark",
+		Usage: "Bucket to run benchmarks in, created and removed if it does not exist.",
+	},
+	cli.StringFlag{
+		Name:  "object-size",
+		Value: "1MiB",
+		Usage: "Size of each object.",
+	},
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 100,
+		Usage: "Number of objects used by each benchmark.",
+	},
+	cli.IntFlag{
+		Name:  "concurrency",
+		Value: 8,
+		Usage: "Number of concurrent requests.",
+	},
+}
+
+var benchmarkCmd = cli.Command{
+	Name:   "benchmark",
+	Usage:  "Run standardized benchmarks against a server.",
+	Action: mainBenchmark,
+	Flags:  benchmarkFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} {{if .VisibleFlags}}[FLAGS] {{end}}[ENDPOINT]
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+ENDPOINT:
+  Server to run benchmarks against, defaults to http://localhost:9000
+
+ENVIRONMENT VARIABLES:
+  ACCESS:
+     MINIO_ACCESS_KEY: Username or access key of the server.
+     MINIO_SECRET_KEY: Password or secret key of the server.
+
+EXAMPLES:
+  1. Run benchmarks against a local server, results are printed in JSON.
+      $ export MINIO_ACCESS_KEY=minio
+      $ export MINIO_SECRET_KEY=miniostorage
+      $ {{.HelpName}}
+
+  2. Run benchmarks with 64MiB objects and 32 concurrent requests against a remote server.
+      $ {{.HelpName}} --object-size 64MiB --concurrency 32 https://play.minio.io:9000
+`,
+}
+
+// Benchmarked operations.
+const (
+	benchmarkOpPut    = "PUT"
+	benchmarkOpGet    = "GET"
+	benchmarkOpList   = "LIST"
+	benchmarkOpDelete = "DELETE"
+)
+
+// BenchmarkResult - result of a single benchmarked operation.
+type BenchmarkResult struct {
+	Operation   string  `json:"operation"`
+	Count       int     `json:"count"`
+	Errors      int     `json:"errors"`
+	Bytes       int64   `json:"bytes"`
+	DurationSec float64 `json:"durationSec"`
+	OpsPerSec   float64 `json:"opsPerSec"`
+	BytesPerSec float64 `json:"bytesPerSec"`
+
+	// Latencies of successful requests in milliseconds.
+	LatencyAvgMs float64 `json:"latencyAvgMs"`
+	LatencyP50Ms float64 `json:"latencyP50Ms"`
+	LatencyP99Ms float64 `json:"latencyP99Ms"`
+	LatencyMaxMs float64 `json:"latencyMaxMs"`
+}
+
+// BenchmarkReport - benchmark settings along with results of all
+// benchmarked operations.
+type BenchmarkReport struct {
+	Version     string            `json:"version"`
+	Endpoint    string            `json:"endpoint"`
+	ObjectSize  int64             `json:"objectSize"`
+	Objects     int               `json:"objects"`
+	Concurrency int               `json:"concurrency"`
+	Results     []BenchmarkResult `json:"results"`
+}
+
+// benchmarkConfig - settings of a benchmark run.
+type benchmarkConfig struct {
+	endpoint    string
+	accessKey   string
+	secretKey   string
+	secure      bool
+	bucket      string
+	objectSize  int64
+	objects     int
+	concurrency int
+}
+
+// Returns name of the i'th object used by benchmarks.
+func getBenchmarkObjectName(i int) string {
+	return fmt.Sprintf("benchmark/object-%06d", i)
+}
+
+// benchmarkLatencies - sorts latencies in ascending order.
+type benchmarkLatencies []time.Duration
+
+func (l benchmarkLatencies) Len() int           { return len(l) }
+func (l benchmarkLatencies) Less(i, j int) bool { return l[i] < l[j] }
+func (l benchmarkLatencies) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+
+// runBenchmarkOp - runs fn count times with given concurrency, fn
+// returns the number of bytes transferred. Returns the throughput and
+// latency distribution of successful calls.
+func runBenchmarkOp(operation string, count, concurrency int, fn func(i int) (int64, error)) BenchmarkResult {
+	indexCh := make(chan int)
+	go func() {
+		for i := 0; i < count; i++ {
+			indexCh <- i
+		}
+		close(indexCh)
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var latencies []time.Duration
+	result := BenchmarkResult{Operation: operation}
+
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				opStart := time.Now()
+				n, err := fn(i)
+				latency := time.Since(opStart)
+
+				mu.Lock()
+				if err != nil {
+					result.Errors++
+				} else {
+					result.Count++
+					result.Bytes += n
+					latencies = append(latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	duration := time.Since(start)
+
+	result.DurationSec = duration.Seconds()
+	if result.DurationSec > 0 {
+		result.OpsPerSec = float64(result.Count) / result.DurationSec
+		result.BytesPerSec = float64(result.Bytes) / result.DurationSec
+	}
+
+	if len(latencies) > 0 {
+		sort.Sort(benchmarkLatencies(latencies))
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+		toMs := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+		result.LatencyAvgMs = toMs(total / time.Duration(len(latencies)))
+		result.LatencyP50Ms = toMs(latencies[(len(latencies)-1)*50/100])
+		result.LatencyP99Ms = toMs(latencies[(len(latencies)-1)*99/100])
+		result.LatencyMaxMs = toMs(latencies[len(latencies)-1])
+	}
+	return result
+}
+
+// runBenchmark - runs PUT, GET, LIST and DELETE benchmarks in that
+// order, objects uploaded by PUT are used by the rest. The bucket is
+// created if needed and removed at the end if it was created.
+func runBenchmark(config benchmarkConfig) (report BenchmarkReport, err error) {
+	client, err := minio.New(config.endpoint, config.accessKey, config.secretKey, config.secure)
+	if err != nil {
+		return report, err
+	}
+
+	found, err := client.BucketExists(config.bucket)
+	if err != nil {
+		return report, err
+	}
+	if !found {
+		if err = client.MakeBucket(config.bucket, ""); err != nil {
+			return report, err
+		}
+		defer client.RemoveBucket(config.bucket)
+	}
+
+	report = BenchmarkReport{
+		Version:     Version,
+		Endpoint:    config.endpoint,
+		ObjectSize:  config.objectSize,
+		Objects:     config.objects,
+		Concurrency: config.concurrency,
+	}
+
+	// Same random data is uploaded for all objects.
+	data := make([]byte, config.objectSize)
+	rand.New(rand.NewSource(time.Now().UnixNano())).Read(data)
+
+	putResult := runBenchmarkOp(benchmarkOpPut, config.objects, config.concurrency, func(i int) (int64, error) {
+		return client.PutObject(config.bucket, getBenchmarkObjectName(i), bytes.NewReader(data), "application/octet-stream")
+	})
+
+	getResult := runBenchmarkOp(benchmarkOpGet, config.objects, config.concurrency, func(i int) (int64, error) {
+		object, err := client.GetObject(config.bucket, getBenchmarkObjectName(i))
+		if err != nil {
+			return 0, err
+		}
+		defer object.Close()
+		return io.Copy(ioutil.Discard, object)
+	})
+
+	// Every LIST request lists all benchmark objects.
+	listResult := runBenchmarkOp(benchmarkOpList, config.concurrency, config.concurrency, func(i int) (int64, error) {
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+		listed := 0
+		for objInfo := range client.ListObjectsV2(config.bucket, "benchmark/", true, doneCh) {
+			if objInfo.Err != nil {
+				return 0, objInfo.Err
+			}
+			listed++
+		}
+		if listed < putResult.Count {
+			return 0, fmt.Errorf("listed %d objects, expected %d", listed, putResult.Count)
+		}
+		return 0, nil
+	})
+
+	deleteResult := runBenchmarkOp(benchmarkOpDelete, config.objects, config.concurrency, func(i int) (int64, error) {
+		return 0, client.RemoveObject(config.bucket, getBenchmarkObjectName(i))
+	})
+
+	report.Results = []BenchmarkResult{putResult, getResult, listResult, deleteResult}
+	return report, nil
+}
+
+// Handler for 'minio benchmark'.
+func mainBenchmark(ctx *cli.Context) {
+	if len(ctx.Args()) > 1 || ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, "benchmark", 1)
+	}
+
+	endpointArg := "http://localhost:9000"
+	if ctx.Args().Present() {
+		endpointArg = ctx.Args().First()
+	}
+	endpoint, secure, err := parseGatewayEndpoint(endpointArg)
+	fatalIf(err, "Unable to parse endpoint %s", endpointArg)
+
+	accessKey := os.Getenv("MINIO_ACCESS_KEY")
+	secretKey := os.Getenv("MINIO_SECRET_KEY")
+	if accessKey == "" || secretKey == "" {
+		fatalIf(errors.New("Missing credentials"), "Access and secret keys are mandatory to run benchmarks.")
+	}
+
+	objectSize, err := humanize.ParseBytes(ctx.String("object-size"))
+	fatalIf(err, "Invalid object size %s", ctx.String("object-size"))
+	if ctx.Int("objects") <= 0 || ctx.Int("concurrency") <= 0 {
+		fatalIf(errors.New("invalid value"), "Number of objects and concurrency must be greater than zero.")
+	}
+
+	report, err := runBenchmark(benchmarkConfig{
+		endpoint:    endpoint,
+		accessKey:   accessKey,
+		secretKey:   secretKey,
+		secure:      secure,
+		bucket:      ctx.String("bucket"),
+		objectSize:  int64(objectSize),
+		objects:     ctx.Int("objects"),
+		concurrency: ctx.Int("concurrency"),
+	})
+	fatalIf(err, "Unable to run benchmarks against %s", endpointArg)
+
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	fatalIf(err, "Unable to marshal benchmark results")
+	fmt.Println(string(reportBytes))
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests throughput and latency calculations of a benchmarked operation.
+func TestRunBenchmarkOp(t *testing.T) {
+	testCases := []struct {
+		count          int
+		concurrency    int
+		failEvery      int
+		expectedCount  int
+		expectedErrors int
+		expectedBytes  int64
+	}{
+		// Test case - 1, all calls succeed.
+		{10, 2, 0, 10, 0, 100},
+		// Test case - 2, every third call fails.
+		{9, 3, 3, 6, 3, 60},
+		// Test case - 3, more workers than calls.
+		{2, 8, 0, 2, 0, 20},
+	}
+
+	for i, testCase := range testCases {
+		result := runBenchmarkOp(benchmarkOpPut, testCase.count, testCase.concurrency, func(i int) (int64, error) {
+			time.Sleep(time.Millisecond)
+			if testCase.failEvery > 0 && i%testCase.failEvery == 0 {
+				return 0, errors.New("failed")
+			}
+			return 10, nil
+		})
+		if result.Operation != benchmarkOpPut {
+			t.Errorf("Test %d: expected operation %s, got %s", i+1, benchmarkOpPut, result.Operation)
+		}
+		if result.Count != testCase.expectedCount || result.Errors != testCase.expectedErrors || result.Bytes != testCase.expectedBytes {
+			t.Errorf("Test %d: expected %d ok, %d errors, %d bytes, got %d ok, %d errors, %d bytes", i+1,
+				testCase.expectedCount, testCase.expectedErrors, testCase.expectedBytes,
+				result.Count, result.Errors, result.Bytes)
+		}
+		if result.OpsPerSec <= 0 || result.BytesPerSec <= 0 {
+			t.Errorf("Test %d: expected positive throughput, got %f ops/sec, %f bytes/sec", i+1, result.OpsPerSec, result.BytesPerSec)
+		}
+		if result.LatencyP50Ms < 1 || result.LatencyP50Ms > result.LatencyP99Ms || result.LatencyP99Ms > result.LatencyMaxMs {
+			t.Errorf("Test %d: unexpected latencies p50 %f, p99 %f, max %f", i+1, result.LatencyP50Ms, result.LatencyP99Ms, result.LatencyMaxMs)
+		}
+	}
+}
+
+// Tests running all benchmarks against a test server.
+func TestRunBenchmark(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	initNSLock(false)
+
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	u, err := url.Parse(testServer.Server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := runBenchmark(benchmarkConfig{
+		endpoint:    u.Host,
+		accessKey:   testServer.AccessKey,
+		secretKey:   testServer.SecretKey,
+		bucket:      "benchmark-bucket",
+		objectSize:  64 * humanize.KiByte,
+		objects:     10,
+		concurrency: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOps := []struct {
+		operation string
+		count     int
+		bytes     int64
+	}{
+		{benchmarkOpPut, 10, 10 * 64 * humanize.KiByte},
+		{benchmarkOpGet, 10, 10 * 64 * humanize.KiByte},
+		{benchmarkOpList, 4, 0},
+		{benchmarkOpDelete, 10, 0},
+	}
+	if len(report.Results) != len(expectedOps) {
+		t.Fatalf("Expected %d results, got %d", len(expectedOps), len(report.Results))
+	}
+	for i, expected := range expectedOps {
+		result := report.Results[i]
+		if result.Operation != expected.operation || result.Count != expected.count ||
+			result.Errors != 0 || result.Bytes != expected.bytes {
+			t.Errorf("Test %d: expected %s with %d ok and %d bytes, got %+v", i+1, expected.operation, expected.count, expected.bytes, result)
+		}
+	}
+
+	// Bucket created by benchmark is removed.
+	if _, err = testServer.Obj.GetBucketInfo("benchmark-bucket"); err == nil {
+		t.Fatal("Expected benchmark bucket to be removed")
+	}
+}
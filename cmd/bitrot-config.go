@@ -0,0 +1,80 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "fmt"
+
+// Bit-rot verification modes of shards read by GetObject.
+const (
+	// Shards are verified, corrupted shards are reconstructed from
+	// the others.
+	bitrotVerifyOn = "on"
+	// Shards are verified, objects with corrupted shards are healed
+	// in background after being read.
+	bitrotVerifyHeal = "heal"
+	// Shards are not verified, bit-rot is only found by healing.
+	bitrotVerifyOff = "off"
+)
+
+// bitrotConfig - bit-rot protection of XL shards, zero values use
+// the default checksum algorithm of the architecture and verify
+// shards on read.
+type bitrotConfig struct {
+	// Checksum algorithm of shards written from now on, one of
+	// "blake2b", "sha256" or "highwayhash". Existing shards keep
+	// the algorithm recorded in their xl.json.
+	Algorithm string `json:"algorithm"`
+	// Verification of shards read by GetObject, one of "on",
+	// "heal" or "off".
+	Verify string `json:"verify"`
+}
+
+// Validate - validates bitrot config.
+func (b bitrotConfig) Validate() error {
+	switch b.Algorithm {
+	case "", blake2bAlgo, sha256Algo, highwayHashAlgo:
+	default:
+		return fmt.Errorf("Invalid bitrot algorithm ‘%s’, must be one of %s, %s or %s", b.Algorithm, blake2bAlgo, sha256Algo, highwayHashAlgo)
+	}
+	switch b.Verify {
+	case "", bitrotVerifyOn, bitrotVerifyHeal, bitrotVerifyOff:
+	default:
+		return fmt.Errorf("Invalid bitrot verify mode ‘%s’, must be one of %s, %s or %s", b.Verify, bitrotVerifyOn, bitrotVerifyHeal, bitrotVerifyOff)
+	}
+	return nil
+}
+
+// getBitRotAlgo - returns the checksum algorithm of new shards.
+func getBitRotAlgo() string {
+	if serverConfig != nil {
+		if algo := serverConfig.GetBitrot().Algorithm; algo != "" {
+			return algo
+		}
+	}
+	return bitRotAlgo
+}
+
+// getBitRotVerify - returns the verification mode of shards read by
+// GetObject.
+func getBitRotVerify() string {
+	if serverConfig != nil {
+		if verify := serverConfig.GetBitrot().Verify; verify != "" {
+			return verify
+		}
+	}
+	return bitrotVerifyOn
+}
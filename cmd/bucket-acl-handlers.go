@@ -0,0 +1,172 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// GetBucketACLHandler - GET Bucket acl
+// -----------------
+// Returns the ACL equivalent to the bucket-wide policy of the bucket,
+// for legacy tools not supporting bucket policies.
+func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	policyInfo, err := readBucketAccessPolicy(objAPI, bucket)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to read bucket policy.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	acl := newAccessControlPolicy(policy.GetPolicy(policyInfo.Statements, bucket, ""))
+	aclBytes, err := xml.Marshal(acl)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to marshal ACL into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, aclBytes)
+}
+
+// PutBucketACLHandler - PUT Bucket acl
+// -----------------
+// Sets the bucket-wide policy of the bucket equivalent to the canned
+// ACL in x-amz-acl header, or to the ACL in request body, for legacy
+// tools not supporting bucket policies.
+func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var bucketPolicy policy.BucketPolicy
+	var ok bool
+	if cannedACL := r.Header.Get("x-amz-acl"); cannedACL != "" {
+		bucketPolicy, ok = cannedACLToBucketPolicy(cannedACL)
+	} else {
+		// ACL in request body always needs a Content-Length.
+		if r.ContentLength == -1 || r.ContentLength == 0 {
+			writeErrorResponse(w, ErrMissingContentLength, r.URL)
+			return
+		}
+		if r.ContentLength > maxACLBodySize {
+			writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+			return
+		}
+
+		var buffer bytes.Buffer
+		if _, err = io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+			reqErrorIf(r, err, "Unable to read incoming body.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		var acl accessControlPolicy
+		if err = xml.Unmarshal(buffer.Bytes(), &acl); err != nil {
+			writeErrorResponse(w, ErrMalformedACL, r.URL)
+			return
+		}
+		bucketPolicy, ok = acl.toBucketPolicy()
+	}
+	if !ok {
+		writeErrorResponse(w, ErrUnsupportedACL, r.URL)
+		return
+	}
+
+	// Replace the bucket-wide statements of the bucket policy,
+	// statements of prefixes are preserved.
+	policyInfo, err := readBucketAccessPolicy(objAPI, bucket)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to read bucket policy.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	policyInfo.Statements = policy.SetPolicy(policyInfo.Statements, bucketPolicy, bucket, "")
+	if len(policyInfo.Statements) == 0 {
+		err = persistAndNotifyBucketPolicyChange(bucket, policyChange{true, nil}, objAPI)
+		if _, ok = err.(BucketPolicyNotFound); err != nil && !ok {
+			reqErrorIf(r, err, "Unable to remove bucket policy.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	policyBytes, err := json.Marshal(policyInfo)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to marshal bucket policy.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Parse validate and save bucket policy.
+	if s3Error := parseAndPersistBucketPolicy(bucket, policyBytes, objAPI); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
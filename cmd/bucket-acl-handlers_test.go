@@ -0,0 +1,123 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests PUT and GET bucket acl along with anonymous access they grant.
+func TestBucketACLHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketACLHandlers, []string{"GetBucketACL", "PutBucketACL", "GetObject"})
+}
+
+func testBucketACLHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "object"
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	publicReadACL := `<AccessControlPolicy><AccessControlList><Grant><Grantee><URI>` + aclAllUsersURI +
+		`</URI></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`
+	testCases := []struct {
+		method             string
+		bucketName         string
+		cannedACL          string
+		body               string
+		accessKey          string
+		expectedRespStatus int
+		expectedGrants     int
+		expectedAnonStatus int
+	}{
+		// Test 1: private by default.
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, 1, http.StatusForbidden},
+		// Test 2: public-read canned ACL.
+		{"PUT", bucketName, "public-read", "", credentials.AccessKey, http.StatusOK, 0, http.StatusOK},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, 2, http.StatusOK},
+		// Test 4: public-read-write canned ACL.
+		{"PUT", bucketName, "public-read-write", "", credentials.AccessKey, http.StatusOK, 0, http.StatusOK},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, 3, http.StatusOK},
+		// Test 6: private canned ACL.
+		{"PUT", bucketName, "private", "", credentials.AccessKey, http.StatusOK, 0, http.StatusForbidden},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, 1, http.StatusForbidden},
+		// Test 8: private again without any bucket policy.
+		{"PUT", bucketName, "private", "", credentials.AccessKey, http.StatusOK, 0, http.StatusForbidden},
+		// Test 9: ACL in request body.
+		{"PUT", bucketName, "", publicReadACL, credentials.AccessKey, http.StatusOK, 0, http.StatusOK},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, 2, http.StatusOK},
+		// Test 11: unsupported canned ACL.
+		{"PUT", bucketName, "authenticated-read", "", credentials.AccessKey, http.StatusBadRequest, 0, http.StatusOK},
+		// Test 12: malformed ACL.
+		{"PUT", bucketName, "", "<AccessControlPolicy>", credentials.AccessKey, http.StatusBadRequest, 0, http.StatusOK},
+		// Test 13: missing ACL.
+		{"PUT", bucketName, "", "", credentials.AccessKey, http.StatusLengthRequired, 0, http.StatusOK},
+		// Test 14: non-existent bucket.
+		{"PUT", "non-existent-bucket", "private", "", credentials.AccessKey, http.StatusNotFound, 0, http.StatusOK},
+		// Test 15: invalid credentials.
+		{"PUT", bucketName, "private", "", "abcd1234", http.StatusForbidden, 0, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest(testCase.method, getBucketACLURL("", testCase.bucketName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)))
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.cannedACL != "" {
+			req.Header.Set("x-amz-acl", testCase.cannedACL)
+		}
+		if err = signRequestV4(req, testCase.accessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" {
+			var acl accessControlPolicy
+			if err = xml.Unmarshal(rec.Body.Bytes(), &acl); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if len(acl.Grants) != testCase.expectedGrants {
+				t.Fatalf("Test %d: %s: Expected %d grants, got %d", i+1, instanceType, testCase.expectedGrants, len(acl.Grants))
+			}
+		}
+
+		// Anonymous access to objects of the bucket follows the ACL,
+		// peers are not notified of bucket policy changes in tests.
+		if err = initBucketPolicies(obj); err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		rec = httptest.NewRecorder()
+		req, err = newTestRequest("GET", getGetObjectURL("", bucketName, objectName), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedAnonStatus {
+			t.Fatalf("Test %d: %s: Expected anonymous access status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedAnonStatus, rec.Code)
+		}
+	}
+}
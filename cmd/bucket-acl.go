@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+const (
+	// Canned ACLs supported on buckets, sent in x-amz-acl header.
+	cannedACLPrivate         = "private"
+	cannedACLPublicRead      = "public-read"
+	cannedACLPublicReadWrite = "public-read-write"
+
+	// Permissions of ACL grants.
+	aclPermissionRead        = "READ"
+	aclPermissionWrite       = "WRITE"
+	aclPermissionFullControl = "FULL_CONTROL"
+
+	// Grantee group of everyone, anonymous requests included.
+	aclAllUsersURI = "http://acs.amazonaws.com/groups/global/AllUsers"
+
+	// Maximum size of an ACL sent in request body.
+	maxACLBodySize = 1 * 1024 * 1024
+)
+
+// aclGrantee - grantee of an ACL grant, either the owner or
+// the AllUsers group.
+type aclGrantee struct {
+	XMLNSXSI    string `xml:"xmlns:xsi,attr,omitempty"`
+	Type        string `xml:"xsi:type,attr,omitempty"`
+	ID          string `xml:"ID,omitempty"`
+	DisplayName string `xml:"DisplayName,omitempty"`
+	URI         string `xml:"URI,omitempty"`
+}
+
+// aclGrant - a permission granted to a grantee.
+type aclGrant struct {
+	Grantee    aclGrantee `xml:"Grantee"`
+	Permission string     `xml:"Permission"`
+}
+
+// accessControlPolicy - ACL of a bucket, sent and received as
+//
+//	<AccessControlPolicy><Owner>...</Owner><AccessControlList><Grant>...</Grant></AccessControlList></AccessControlPolicy>
+type accessControlPolicy struct {
+	XMLName xml.Name   `xml:"AccessControlPolicy"`
+	Owner   Owner      `xml:"Owner"`
+	Grants  []aclGrant `xml:"AccessControlList>Grant"`
+}
+
+// ACLs are translated into bucket-wide policies of anonymous requests,
+// the owner always has full control.
+
+// cannedACLToBucketPolicy - returns the bucket policy equivalent to
+// a canned ACL, false if the canned ACL is not supported.
+func cannedACLToBucketPolicy(cannedACL string) (policy.BucketPolicy, bool) {
+	switch cannedACL {
+	case cannedACLPrivate:
+		return policy.BucketPolicyNone, true
+	case cannedACLPublicRead:
+		return policy.BucketPolicyReadOnly, true
+	case cannedACLPublicReadWrite:
+		return policy.BucketPolicyReadWrite, true
+	}
+	return "", false
+}
+
+// newAccessControlPolicy - returns the ACL equivalent to the bucket
+// policy of a bucket.
+func newAccessControlPolicy(bucketPolicy policy.BucketPolicy) accessControlPolicy {
+	owner := Owner{ID: globalMinioDefaultOwnerID, DisplayName: globalMinioDefaultOwnerID}
+	acl := accessControlPolicy{
+		Owner: owner,
+		Grants: []aclGrant{{
+			Grantee: aclGrantee{
+				XMLNSXSI:    "http://www.w3.org/2001/XMLSchema-instance",
+				Type:        "CanonicalUser",
+				ID:          owner.ID,
+				DisplayName: owner.DisplayName,
+			},
+			Permission: aclPermissionFullControl,
+		}},
+	}
+
+	var permissions []string
+	switch bucketPolicy {
+	case policy.BucketPolicyReadOnly:
+		permissions = []string{aclPermissionRead}
+	case policy.BucketPolicyWriteOnly:
+		permissions = []string{aclPermissionWrite}
+	case policy.BucketPolicyReadWrite:
+		permissions = []string{aclPermissionRead, aclPermissionWrite}
+	}
+	for _, permission := range permissions {
+		acl.Grants = append(acl.Grants, aclGrant{
+			Grantee: aclGrantee{
+				XMLNSXSI: "http://www.w3.org/2001/XMLSchema-instance",
+				Type:     "Group",
+				URI:      aclAllUsersURI,
+			},
+			Permission: permission,
+		})
+	}
+	return acl
+}
+
+// toBucketPolicy - returns the bucket policy equivalent to the ACL,
+// false if the ACL grants permissions which cannot be translated,
+// i.e to grantees other than the owner and the AllUsers group.
+func (acl accessControlPolicy) toBucketPolicy() (policy.BucketPolicy, bool) {
+	var read, write bool
+	for _, grant := range acl.Grants {
+		switch {
+		case grant.Grantee.URI == aclAllUsersURI:
+			switch grant.Permission {
+			case aclPermissionRead:
+				read = true
+			case aclPermissionWrite:
+				write = true
+			case aclPermissionFullControl:
+				read, write = true, true
+			default:
+				return "", false
+			}
+		case grant.Grantee.ID == globalMinioDefaultOwnerID && grant.Permission == aclPermissionFullControl:
+			// Owner always has full control.
+		default:
+			return "", false
+		}
+	}
+
+	switch {
+	case read && write:
+		return policy.BucketPolicyReadWrite, true
+	case read:
+		return policy.BucketPolicyReadOnly, true
+	case write:
+		return policy.BucketPolicyWriteOnly, true
+	}
+	return policy.BucketPolicyNone, true
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// Tests translating ACLs into bucket policies and back.
+func TestAccessControlPolicy(t *testing.T) {
+	testCases := []struct {
+		bucketPolicy policy.BucketPolicy
+		grants       int
+	}{
+		// Test 1: private.
+		{policy.BucketPolicyNone, 1},
+		// Test 2: public-read.
+		{policy.BucketPolicyReadOnly, 2},
+		// Test 3: write only.
+		{policy.BucketPolicyWriteOnly, 2},
+		// Test 4: public-read-write.
+		{policy.BucketPolicyReadWrite, 3},
+	}
+	for i, testCase := range testCases {
+		acl := newAccessControlPolicy(testCase.bucketPolicy)
+		if len(acl.Grants) != testCase.grants {
+			t.Fatalf("Test %d: expected %d grants, got %d", i+1, testCase.grants, len(acl.Grants))
+		}
+
+		// ACL sent back by clients is translated to the same policy.
+		aclBytes, err := xml.Marshal(acl)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		var parsedACL accessControlPolicy
+		if err = xml.Unmarshal(aclBytes, &parsedACL); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		bucketPolicy, ok := parsedACL.toBucketPolicy()
+		if !ok || bucketPolicy != testCase.bucketPolicy {
+			t.Errorf("Test %d: expected %s, got %s, %v", i+1, testCase.bucketPolicy, bucketPolicy, ok)
+		}
+	}
+}
+
+// Tests ACLs which cannot be translated into bucket policies.
+func TestAccessControlPolicyUnsupported(t *testing.T) {
+	testCases := []string{
+		// Test 1: grant to another user.
+		`<AccessControlPolicy><AccessControlList><Grant><Grantee><ID>other</ID></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`,
+		// Test 2: grant to authenticated users.
+		`<AccessControlPolicy><AccessControlList><Grant><Grantee><URI>http://acs.amazonaws.com/groups/global/AuthenticatedUsers</URI></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`,
+		// Test 3: unsupported permission of everyone.
+		`<AccessControlPolicy><AccessControlList><Grant><Grantee><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ_ACP</Permission></Grant></AccessControlList></AccessControlPolicy>`,
+		// Test 4: owner with partial permissions.
+		`<AccessControlPolicy><AccessControlList><Grant><Grantee><ID>minio</ID></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`,
+	}
+	for i, testCase := range testCases {
+		var acl accessControlPolicy
+		if err := xml.Unmarshal([]byte(testCase), &acl); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if _, ok := acl.toBucketPolicy(); ok {
+			t.Errorf("Test %d: expected ACL to be unsupported", i+1)
+		}
+	}
+}
+
+// Tests canned ACLs.
+func TestCannedACLToBucketPolicy(t *testing.T) {
+	testCases := []struct {
+		cannedACL    string
+		bucketPolicy policy.BucketPolicy
+		ok           bool
+	}{
+		// Test 1: private.
+		{"private", policy.BucketPolicyNone, true},
+		// Test 2: public-read.
+		{"public-read", policy.BucketPolicyReadOnly, true},
+		// Test 3: public-read-write.
+		{"public-read-write", policy.BucketPolicyReadWrite, true},
+		// Test 4: unsupported canned ACL.
+		{"authenticated-read", "", false},
+	}
+	for i, testCase := range testCases {
+		bucketPolicy, ok := cannedACLToBucketPolicy(testCase.cannedACL)
+		if ok != testCase.ok || bucketPolicy != testCase.bucketPolicy {
+			t.Errorf("Test %d: expected %s, %v, got %s, %v", i+1, testCase.bucketPolicy, testCase.ok, bucketPolicy, ok)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Interval between two exports of bucket analytics, reports of the
+// previous day are written once.
+const analyticsExportInterval = time.Hour
+
+// Columns of exported analytics reports, a subset of those written
+// by S3.
+var analyticsCSVHeader = []string{
+	"Date", "ConfigId", "Filter", "StorageClass", "ObjectAge",
+	"ObjectCount", "Storage_MB", "DataRetrieved_MB", "GetRequestCount",
+}
+
+// analyticsStorageEntry - objects selected by an analytics
+// configuration, of the same storage class and age group.
+type analyticsStorageEntry struct {
+	ConfigID     string `json:"configId"`
+	StorageClass string `json:"storageClass"`
+	ObjectAge    string `json:"objectAge"`
+	ObjectCount  int64  `json:"objectCount"`
+	Size         int64  `json:"size"`
+}
+
+// bucketAnalyticsReport - objects stored and reads of a bucket per
+// analytics configuration.
+type bucketAnalyticsReport struct {
+	Bucket  string                  `json:"bucket"`
+	Time    time.Time               `json:"time"`
+	Storage []analyticsStorageEntry `json:"storage"`
+	Usage   []analyticsUsageEntry   `json:"usage"`
+}
+
+// getAnalyticsStorage - returns objects selected by an analytics
+// configuration per storage class and age group at now.
+func getAnalyticsStorage(objAPI ObjectLayer, bucket string, config analyticsConfiguration, now time.Time) ([]analyticsStorageEntry, error) {
+	type storageKey struct {
+		storageClass, objectAge string
+	}
+	storage := make(map[storageKey]analyticsStorageEntry)
+
+	marker := ""
+	for {
+		objects, err := objAPI.ListObjects(bucket, config.getPrefix(), marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects.Objects {
+			key := storageKey{getObjectStorageClass(object), getAnalyticsObjectAge(object.ModTime, now)}
+			entry, ok := storage[key]
+			if !ok {
+				entry = analyticsStorageEntry{
+					ConfigID:     config.ID,
+					StorageClass: key.storageClass,
+					ObjectAge:    key.objectAge,
+				}
+			}
+			entry.ObjectCount++
+			entry.Size += object.Size
+			storage[key] = entry
+		}
+		if !objects.IsTruncated {
+			break
+		}
+		marker = objects.NextMarker
+	}
+
+	result := make([]analyticsStorageEntry, 0, len(storage))
+	for _, entry := range storage {
+		result = append(result, entry)
+	}
+	sort.Sort(analyticsStorageEntries(result))
+	return result, nil
+}
+
+// analyticsStorageEntries - sorts objects stored by configuration,
+// storage class and age group.
+type analyticsStorageEntries []analyticsStorageEntry
+
+func (e analyticsStorageEntries) Len() int      { return len(e) }
+func (e analyticsStorageEntries) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e analyticsStorageEntries) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	if a.ConfigID != b.ConfigID {
+		return a.ConfigID < b.ConfigID
+	}
+	if a.StorageClass != b.StorageClass {
+		return a.StorageClass < b.StorageClass
+	}
+	return a.ObjectAge < b.ObjectAge
+}
+
+// getBucketAnalyticsReport - returns objects stored at now and reads
+// counted by all servers for all analytics configurations of bucket.
+func getBucketAnalyticsReport(objAPI ObjectLayer, bucket string, now time.Time) (bucketAnalyticsReport, error) {
+	report := bucketAnalyticsReport{
+		Bucket:  bucket,
+		Time:    now,
+		Storage: []analyticsStorageEntry{},
+	}
+	configs, err := loadBucketAnalytics(bucket, objAPI)
+	if err != nil {
+		return report, err
+	}
+	for _, config := range configs {
+		storage, err := getAnalyticsStorage(objAPI, bucket, config, now)
+		if err != nil {
+			return report, err
+		}
+		report.Storage = append(report.Storage, storage...)
+	}
+	report.Usage = getClusterAnalyticsUsage(bucket)
+	return report, nil
+}
+
+// toMB - formats size in megabytes as written in analytics reports.
+func toMB(size uint64) string {
+	return strconv.FormatFloat(float64(size)/humanize.MiByte, 'f', 6, 64)
+}
+
+// formatAnalyticsCSV - returns the report of an analytics
+// configuration for date, one line per storage class and age group
+// with objects stored or read.
+func formatAnalyticsCSV(config analyticsConfiguration, date string, storage []analyticsStorageEntry, usage []analyticsUsageEntry) ([]byte, error) {
+	type rowKey struct {
+		storageClass, objectAge string
+	}
+	type row struct {
+		storage analyticsStorageEntry
+		usage   analyticsUsageEntry
+	}
+	rows := make(map[rowKey]*row)
+	getRow := func(key rowKey) *row {
+		r, ok := rows[key]
+		if !ok {
+			r = &row{}
+			rows[key] = r
+		}
+		return r
+	}
+	for _, entry := range storage {
+		if entry.ConfigID == config.ID {
+			getRow(rowKey{entry.StorageClass, entry.ObjectAge}).storage = entry
+		}
+	}
+	for _, entry := range usage {
+		if entry.ConfigID == config.ID && entry.Date == date {
+			getRow(rowKey{entry.StorageClass, entry.ObjectAge}).usage = entry
+		}
+	}
+
+	keys := make([]string, 0, len(rows))
+	keyOf := make(map[string]rowKey, len(rows))
+	for key := range rows {
+		k := key.storageClass + "/" + key.objectAge
+		keys = append(keys, k)
+		keyOf[k] = key
+	}
+	sort.Strings(keys)
+
+	filter := ""
+	if prefix := config.getPrefix(); prefix != "" {
+		filter = "prefix=" + prefix
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(analyticsCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		key, r := keyOf[k], rows[keyOf[k]]
+		record := []string{
+			date, config.ID, filter, key.storageClass, key.objectAge,
+			strconv.FormatInt(r.storage.ObjectCount, 10),
+			toMB(uint64(r.storage.Size)),
+			toMB(r.usage.BytesRetrieved),
+			strconv.FormatUint(r.usage.GetRequests, 10),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// getAnalyticsExportPath - returns the name of the report of an
+// analytics configuration of bucket for date in the destination
+// bucket.
+func getAnalyticsExportPath(prefix, bucket, configID, date string) string {
+	return path.Join(prefix, bucket, configID, date+".csv")
+}
+
+// analyticsExporter - writes daily reports of analytics
+// configurations to their destination buckets.
+type analyticsExporter struct {
+	objAPI   func() ObjectLayer
+	usage    func(bucket string) []analyticsUsageEntry
+	interval time.Duration
+}
+
+// newAnalyticsExporter - initialize a new analytics exporter.
+func newAnalyticsExporter(objAPI func() ObjectLayer, usage func(bucket string) []analyticsUsageEntry, interval time.Duration) *analyticsExporter {
+	return &analyticsExporter{
+		objAPI:   objAPI,
+		usage:    usage,
+		interval: interval,
+	}
+}
+
+// export - writes reports of the day before now for all analytics
+// configurations exporting data, reports already written are left
+// as they are.
+func (e *analyticsExporter) export(now time.Time) {
+	objAPI := e.objAPI()
+	if objAPI == nil {
+		return
+	}
+
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets for analytics export.")
+		return
+	}
+
+	date := now.AddDate(0, 0, -1).Format(analyticsDateFormat)
+	for _, bucket := range buckets {
+		configs, err := loadBucketAnalytics(bucket.Name, objAPI)
+		if err != nil || len(configs) == 0 {
+			continue
+		}
+		var usage []analyticsUsageEntry
+		for _, config := range configs {
+			dstBucket, dstPrefix, ok := config.getDestination()
+			if !ok {
+				continue
+			}
+			object := getAnalyticsExportPath(dstPrefix, bucket.Name, config.ID, date)
+			if _, err = objAPI.GetObjectInfo(dstBucket, object); err == nil {
+				continue
+			}
+			if usage == nil {
+				usage = e.usage(bucket.Name)
+			}
+			if err = e.exportConfig(objAPI, bucket.Name, config, date, usage, now); err != nil {
+				errorIf(err, "Unable to export analytics %s of bucket %s.", config.ID, bucket.Name)
+			}
+		}
+	}
+}
+
+// exportConfig - writes the report of an analytics configuration for
+// date to its destination bucket.
+func (e *analyticsExporter) exportConfig(objAPI ObjectLayer, bucket string, config analyticsConfiguration, date string, usage []analyticsUsageEntry, now time.Time) error {
+	storage, err := getAnalyticsStorage(objAPI, bucket, config, now)
+	if err != nil {
+		return err
+	}
+	data, err := formatAnalyticsCSV(config, date, storage, usage)
+	if err != nil {
+		return err
+	}
+
+	dstBucket, dstPrefix, _ := config.getDestination()
+	object := getAnalyticsExportPath(dstPrefix, bucket, config.ID, date)
+	metadata := map[string]string{"content-type": "text/csv"}
+	_, err = objAPI.PutObject(dstBucket, object, int64(len(data)), bytes.NewReader(data), metadata, getSHA256Hash(data))
+	if err != nil {
+		return fmt.Errorf("unable to write %s/%s: %v", dstBucket, object, errorCause(err))
+	}
+	return nil
+}
+
+// run - exports at every interval until doneCh is closed.
+func (e *analyticsExporter) run(doneCh <-chan struct{}) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.export(UTCNow())
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// startAnalyticsExporter - starts exporting bucket analytics in
+// background, only one server in a distributed setup writes reports.
+func startAnalyticsExporter(endpoints EndpointList, doneCh <-chan struct{}) {
+	if len(endpoints) == 0 || !endpoints[0].IsLocal {
+		return
+	}
+	exporter := newAnalyticsExporter(newObjectLayerFn, getClusterAnalyticsUsage, analyticsExportInterval)
+	go exporter.run(doneCh)
+}
@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// listBucketAnalyticsResult - container for ListBucketAnalyticsConfigurations
+// response.
+type listBucketAnalyticsResult struct {
+	XMLName               xml.Name                 `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketAnalyticsConfigurationResult"`
+	Configs               []analyticsConfiguration `xml:"AnalyticsConfiguration"`
+	IsTruncated           bool                     `xml:"IsTruncated"`
+	ContinuationToken     string                   `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string                   `xml:"NextContinuationToken,omitempty"`
+}
+
+// readAnalyticsBody - reads analytics configuration XML from request
+// body.
+func readAnalyticsBody(r *http.Request) ([]byte, APIErrorCode) {
+	// Analytics configuration always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxAnalyticsBodySize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+	return buffer.Bytes(), ErrNone
+}
+
+// GetBucketAnalyticsHandler - GET Bucket analytics
+// -----------------
+// Returns the analytics configuration of the bucket with the given id.
+func (api objectAPIHandlers) GetBucketAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket, id := vars["bucket"], r.URL.Query().Get("id")
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	config, err := getBucketAnalytics(bucket, id, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, err := xml.Marshal(config)
+	if err != nil {
+		errorIf(err, "Unable to marshal analytics configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, configBytes)
+}
+
+// ListBucketAnalyticsHandler - GET Bucket analytics
+// -----------------
+// Lists analytics configurations of the bucket sorted by id, up to
+// 100 at a time.
+func (api objectAPIHandlers) ListBucketAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configs, err := loadBucketAnalytics(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Continuation token is the id of the last configuration listed.
+	token := r.URL.Query().Get("continuation-token")
+	result := listBucketAnalyticsResult{
+		ContinuationToken: token,
+		Configs:           []analyticsConfiguration{},
+	}
+	for _, config := range configs {
+		if config.ID <= token {
+			continue
+		}
+		if len(result.Configs) == maxAnalyticsListResults {
+			result.IsTruncated = true
+			result.NextContinuationToken = result.Configs[len(result.Configs)-1].ID
+			break
+		}
+		result.Configs = append(result.Configs, config)
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, encodeResponse(result))
+}
+
+// PutBucketAnalyticsHandler - PUT Bucket analytics
+// -----------------
+// Adds or replaces the analytics configuration of the bucket with
+// the given id.
+func (api objectAPIHandlers) PutBucketAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket, id := vars["bucket"], r.URL.Query().Get("id")
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, s3Error := readAnalyticsBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	config, s3Error := parseAnalyticsConfiguration(configBytes, id)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Reports can only be written to an existing bucket.
+	if dstBucket, _, ok := config.getDestination(); ok {
+		if _, err = objAPI.GetBucketInfo(dstBucket); err != nil {
+			writeErrorResponse(w, ErrInvalidAnalyticsConfiguration, r.URL)
+			return
+		}
+	}
+
+	if err = persistBucketAnalytics(bucket, config, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteBucketAnalyticsHandler - DELETE Bucket analytics
+// -----------------
+// Removes the analytics configuration of the bucket with the given
+// id, reports already exported are left as they are.
+func (api objectAPIHandlers) DeleteBucketAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket, id := vars["bucket"], r.URL.Query().Get("id")
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if err = removeBucketAnalytics(bucket, id, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
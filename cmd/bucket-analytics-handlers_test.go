@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests PUT, GET, list and DELETE bucket analytics.
+func TestBucketAnalyticsHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketAnalyticsHandlers, []string{"GetBucketAnalytics", "ListBucketAnalytics", "PutBucketAnalytics", "DeleteBucketAnalytics"})
+}
+
+func testBucketAnalyticsHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	analytics := func(id string) string {
+		return fmt.Sprintf(`<AnalyticsConfiguration><Id>%s</Id><Filter><Prefix>logs/</Prefix></Filter><StorageClassAnalysis/></AnalyticsConfiguration>`, id)
+	}
+	exportTo := func(bucket string) string {
+		return `<AnalyticsConfiguration><Id>export</Id><StorageClassAnalysis><DataExport><OutputSchemaVersion>V_1</OutputSchemaVersion>` +
+			`<Destination><S3BucketDestination><Format>CSV</Format><Bucket>arn:aws:s3:::` + bucket + `</Bucket></S3BucketDestination>` +
+			`</Destination></DataExport></StorageClassAnalysis></AnalyticsConfiguration>`
+	}
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		id                 string
+		body               string
+		accessKey          string
+		expectedRespStatus int
+		expectedIDs        []string
+	}{
+		// No configuration set yet.
+		{"GET", bucketName, "logs", "", credentials.AccessKey, http.StatusNotFound, nil},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, []string{}},
+		// Malformed XML.
+		{"PUT", bucketName, "logs", "<AnalyticsConfiguration>", credentials.AccessKey, http.StatusBadRequest, nil},
+		// Id doesn't match or is missing.
+		{"PUT", bucketName, "other", analytics("logs"), credentials.AccessKey, http.StatusBadRequest, nil},
+		{"PUT", bucketName, "", analytics("logs"), credentials.AccessKey, http.StatusBadRequest, nil},
+		// Destination bucket doesn't exist.
+		{"PUT", bucketName, "export", exportTo("non-existent-bucket"), credentials.AccessKey, http.StatusBadRequest, nil},
+		// Non-existent bucket.
+		{"PUT", "non-existent-bucket", "logs", analytics("logs"), credentials.AccessKey, http.StatusNotFound, nil},
+		// Invalid credentials.
+		{"PUT", bucketName, "logs", analytics("logs"), "abcd1234", http.StatusForbidden, nil},
+		// Valid configurations.
+		{"PUT", bucketName, "logs", analytics("logs"), credentials.AccessKey, http.StatusOK, nil},
+		{"PUT", bucketName, "export", exportTo(bucketName), credentials.AccessKey, http.StatusOK, nil},
+		{"PUT", bucketName, "all", analytics("all"), credentials.AccessKey, http.StatusOK, nil},
+		// Replacing a configuration keeps a single one.
+		{"PUT", bucketName, "all", analytics("all"), credentials.AccessKey, http.StatusOK, nil},
+		{"GET", bucketName, "logs", "", credentials.AccessKey, http.StatusOK, []string{"logs"}},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, []string{"all", "export", "logs"}},
+		// Remove a configuration.
+		{"DELETE", bucketName, "logs", "", credentials.AccessKey, http.StatusNoContent, nil},
+		{"GET", bucketName, "logs", "", credentials.AccessKey, http.StatusNotFound, nil},
+		{"DELETE", bucketName, "logs", "", credentials.AccessKey, http.StatusNotFound, nil},
+		{"GET", bucketName, "", "", credentials.AccessKey, http.StatusOK, []string{"all", "export"}},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getAnalyticsURL("", testCase.bucketName, testCase.id),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedIDs == nil {
+			continue
+		}
+
+		var ids []string
+		if testCase.id != "" {
+			var config analyticsConfiguration
+			if err = xml.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			ids = append(ids, config.ID)
+		} else {
+			var result listBucketAnalyticsResult
+			if err = xml.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			for _, config := range result.Configs {
+				ids = append(ids, config.ID)
+			}
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(testCase.expectedIDs) {
+			t.Fatalf("Test %d: %s: expected configurations %v, got %v", i+1, instanceType, testCase.expectedIDs, ids)
+		}
+	}
+}
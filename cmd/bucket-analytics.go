@@ -0,0 +1,540 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket analytics config file name, saved alongside other
+	// bucket configs in minioMetaBucket.
+	bucketAnalyticsConfig = "analytics.xml"
+
+	// Limits on analytics configurations as documented by S3.
+	maxAnalyticsConfigs     = 1000
+	maxAnalyticsBodySize    = 1 * 1024 * 1024
+	maxAnalyticsListResults = 100
+
+	// Only supported export format and schema.
+	analyticsExportFormatCSV = "CSV"
+	analyticsSchemaV1        = "V_1"
+
+	// Destination buckets are given as S3 ARNs.
+	analyticsBucketARNPrefix = "arn:aws:s3:::"
+
+	// Number of days accesses are kept for, by every server.
+	analyticsRetentionDays = 31
+
+	// Dates accesses are counted for, in UTC.
+	analyticsDateFormat = "2006-01-02"
+)
+
+var (
+	// Internal error used to signal an analytics configuration is not set.
+	errNoSuchAnalyticsConfiguration = errors.New("The specified configuration does not exist")
+
+	// Internal error used to signal a bucket has the maximum number of
+	// analytics configurations.
+	errTooManyAnalyticsConfigurations = errors.New("Too many analytics configurations")
+
+	// Valid analytics configuration ids.
+	validAnalyticsID = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+)
+
+// Age groups objects are counted in by storage class analysis, named
+// after the number of days since they were last modified.
+var analyticsObjectAges = []struct {
+	name    string
+	maxDays int
+}{
+	{"000-014", 15},
+	{"015-029", 30},
+	{"030-044", 45},
+	{"045-059", 60},
+	{"060-089", 90},
+	{"090-119", 120},
+	{"120-179", 180},
+	{"180-364", 365},
+	{"365+", 0},
+}
+
+// getAnalyticsObjectAge - returns the age group of an object last
+// modified at modTime.
+func getAnalyticsObjectAge(modTime, now time.Time) string {
+	days := int(now.Sub(modTime) / (24 * time.Hour))
+	for _, age := range analyticsObjectAges {
+		if days < age.maxDays {
+			return age.name
+		}
+	}
+	return analyticsObjectAges[len(analyticsObjectAges)-1].name
+}
+
+// analyticsTag - tag filters are not supported, only parsed to be
+// rejected.
+type analyticsTag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// analyticsAnd - prefix and tags objects must all match.
+type analyticsAnd struct {
+	Prefix string         `xml:"Prefix,omitempty"`
+	Tags   []analyticsTag `xml:"Tag,omitempty"`
+}
+
+// analyticsFilter - selects objects analyzed by a configuration, all
+// objects of the bucket when not set.
+type analyticsFilter struct {
+	Prefix string        `xml:"Prefix,omitempty"`
+	Tag    *analyticsTag `xml:"Tag,omitempty"`
+	And    *analyticsAnd `xml:"And,omitempty"`
+}
+
+// analyticsS3BucketDestination - bucket daily reports are written to.
+type analyticsS3BucketDestination struct {
+	Format          string `xml:"Format"`
+	BucketAccountID string `xml:"BucketAccountId,omitempty"`
+	Bucket          string `xml:"Bucket"`
+	Prefix          string `xml:"Prefix,omitempty"`
+}
+
+// analyticsDataExport - daily reports of a configuration.
+type analyticsDataExport struct {
+	OutputSchemaVersion string                       `xml:"OutputSchemaVersion"`
+	S3BucketDestination analyticsS3BucketDestination `xml:"Destination>S3BucketDestination"`
+}
+
+// analyticsStorageClassAnalysis - reports are only written when
+// DataExport is set.
+type analyticsStorageClassAnalysis struct {
+	DataExport *analyticsDataExport `xml:"DataExport,omitempty"`
+}
+
+// analyticsConfiguration - storage class analysis of a bucket, sent
+// and received as
+//
+//	<AnalyticsConfiguration><Id>...</Id>...</AnalyticsConfiguration>
+type analyticsConfiguration struct {
+	XMLName              xml.Name                      `xml:"AnalyticsConfiguration"`
+	ID                   string                        `xml:"Id"`
+	Filter               *analyticsFilter              `xml:"Filter,omitempty"`
+	StorageClassAnalysis analyticsStorageClassAnalysis `xml:"StorageClassAnalysis"`
+}
+
+// getPrefix - returns object name prefix selected by the configuration.
+func (c analyticsConfiguration) getPrefix() string {
+	if c.Filter == nil {
+		return ""
+	}
+	if c.Filter.And != nil {
+		return c.Filter.And.Prefix
+	}
+	return c.Filter.Prefix
+}
+
+// getDestination - returns destination bucket and prefix of daily
+// reports, ok is false if reports are not exported.
+func (c analyticsConfiguration) getDestination() (bucket, prefix string, ok bool) {
+	export := c.StorageClassAnalysis.DataExport
+	if export == nil {
+		return "", "", false
+	}
+	dst := export.S3BucketDestination
+	return strings.TrimPrefix(dst.Bucket, analyticsBucketARNPrefix), dst.Prefix, true
+}
+
+// parseAnalyticsConfiguration - parses and validates analytics
+// configuration XML of the given id, returns ErrNone on success.
+func parseAnalyticsConfiguration(data []byte, id string) (analyticsConfiguration, APIErrorCode) {
+	var config analyticsConfiguration
+	if err := xml.Unmarshal(data, &config); err != nil {
+		return config, ErrMalformedXML
+	}
+	if !validAnalyticsID.MatchString(config.ID) || config.ID != id {
+		return config, ErrInvalidAnalyticsConfiguration
+	}
+	if filter := config.Filter; filter != nil {
+		// Tag filters are not supported yet.
+		if filter.Tag != nil || (filter.And != nil && len(filter.And.Tags) > 0) {
+			return config, ErrNotImplemented
+		}
+		if filter.And != nil && filter.Prefix != "" {
+			return config, ErrInvalidAnalyticsConfiguration
+		}
+	}
+	if export := config.StorageClassAnalysis.DataExport; export != nil {
+		dst := export.S3BucketDestination
+		if export.OutputSchemaVersion != analyticsSchemaV1 || dst.Format != analyticsExportFormatCSV {
+			return config, ErrInvalidAnalyticsConfiguration
+		}
+		if !strings.HasPrefix(dst.Bucket, analyticsBucketARNPrefix) {
+			return config, ErrInvalidAnalyticsConfiguration
+		}
+		if !IsValidBucketName(strings.TrimPrefix(dst.Bucket, analyticsBucketARNPrefix)) {
+			return config, ErrInvalidAnalyticsConfiguration
+		}
+	}
+	return config, ErrNone
+}
+
+// bucketAnalyticsConfigs - all analytics configurations of a bucket,
+// as saved in minioMetaBucket.
+type bucketAnalyticsConfigs struct {
+	XMLName xml.Name                 `xml:"AnalyticsConfigurations"`
+	Configs []analyticsConfiguration `xml:"AnalyticsConfiguration"`
+}
+
+// readBucketAnalytics - reads analytics configurations of a bucket,
+// the caller holds a lock on the config file.
+func readBucketAnalytics(bucket string, objAPI ObjectLayer) ([]analyticsConfiguration, error) {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, configPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, nil
+		}
+		errorIf(err, "Unable to load analytics for bucket %s", bucket)
+		return nil, err
+	}
+
+	var configs bucketAnalyticsConfigs
+	if err = xml.Unmarshal(buffer.Bytes(), &configs); err != nil {
+		return nil, err
+	}
+	return configs.Configs, nil
+}
+
+// writeBucketAnalytics - writes analytics configurations of a bucket,
+// the config file is removed when there are none left. The caller
+// holds a write lock on the config file.
+func writeBucketAnalytics(bucket string, configs []analyticsConfiguration, objAPI ObjectLayer) error {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+	if len(configs) == 0 {
+		return objAPI.DeleteObject(minioMetaBucket, configPath)
+	}
+
+	buf, err := xml.Marshal(bucketAnalyticsConfigs{Configs: configs})
+	if err != nil {
+		return err
+	}
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, configPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write analytics for bucket %s", bucket)
+	}
+	return err
+}
+
+// loadBucketAnalytics - loads analytics configurations of a bucket
+// sorted by id, nil if none is set.
+func loadBucketAnalytics(bucket string, objAPI ObjectLayer) ([]analyticsConfiguration, error) {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+
+	// Acquire a read lock on analytics config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	return readBucketAnalytics(bucket, objAPI)
+}
+
+// getBucketAnalytics - returns analytics configuration of a bucket
+// with the given id.
+func getBucketAnalytics(bucket, id string, objAPI ObjectLayer) (analyticsConfiguration, error) {
+	configs, err := loadBucketAnalytics(bucket, objAPI)
+	if err != nil {
+		return analyticsConfiguration{}, err
+	}
+	for _, config := range configs {
+		if config.ID == id {
+			return config, nil
+		}
+	}
+	return analyticsConfiguration{}, traceError(errNoSuchAnalyticsConfiguration)
+}
+
+// persistBucketAnalytics - adds or replaces an analytics configuration
+// of a bucket.
+func persistBucketAnalytics(bucket string, config analyticsConfiguration, objAPI ObjectLayer) error {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+
+	// Acquire a write lock on analytics config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	configs, err := readBucketAnalytics(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	i := sort.Search(len(configs), func(i int) bool { return configs[i].ID >= config.ID })
+	if i < len(configs) && configs[i].ID == config.ID {
+		configs[i] = config
+	} else {
+		if len(configs) >= maxAnalyticsConfigs {
+			return traceError(errTooManyAnalyticsConfigurations)
+		}
+		configs = append(configs, analyticsConfiguration{})
+		copy(configs[i+1:], configs[i:])
+		configs[i] = config
+	}
+	if err = writeBucketAnalytics(bucket, configs, objAPI); err != nil {
+		return err
+	}
+	notifyBucketConfigChange(bucket, bucketAnalyticsConfig)
+	return nil
+}
+
+// removeBucketAnalytics - removes an analytics configuration of a
+// bucket.
+func removeBucketAnalytics(bucket, id string, objAPI ObjectLayer) error {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+
+	// Acquire a write lock on analytics config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	configs, err := readBucketAnalytics(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	for i, config := range configs {
+		if config.ID != id {
+			continue
+		}
+		configs = append(configs[:i], configs[i+1:]...)
+		if err = writeBucketAnalytics(bucket, configs, objAPI); err != nil {
+			return err
+		}
+		notifyBucketConfigChange(bucket, bucketAnalyticsConfig)
+		return nil
+	}
+	return traceError(errNoSuchAnalyticsConfiguration)
+}
+
+// removeAllBucketAnalytics - removes all analytics configurations of
+// a deleted bucket.
+func removeAllBucketAnalytics(bucket string, objAPI ObjectLayer) error {
+	configPath := path.Join(bucketConfigPrefix, bucket, bucketAnalyticsConfig)
+
+	// Acquire a write lock on analytics config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, configPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, configPath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketAnalytics - returns analytics configurations of
+// bucket, cached in globalBucketConfigCache.
+func getCachedBucketAnalytics(bucket string, objAPI ObjectLayer) ([]analyticsConfiguration, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketAnalyticsConfig, func() (interface{}, error) {
+		return loadBucketAnalytics(bucket, objAPI)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]analyticsConfiguration), nil
+}
+
+// analyticsUsageEntry - reads of objects selected by an analytics
+// configuration, of the same storage class and age group, on a day.
+type analyticsUsageEntry struct {
+	ConfigID       string `json:"configId"`
+	Date           string `json:"date"`
+	StorageClass   string `json:"storageClass"`
+	ObjectAge      string `json:"objectAge"`
+	GetRequests    uint64 `json:"getRequests"`
+	BytesRetrieved uint64 `json:"bytesRetrieved"`
+}
+
+// analyticsKey - reads counted together in an analyticsUsageEntry.
+type analyticsKey struct {
+	bucket       string
+	configID     string
+	date         string
+	storageClass string
+	objectAge    string
+}
+
+// analyticsReads - number of reads and bytes read.
+type analyticsReads struct {
+	requests uint64
+	bytes    uint64
+}
+
+// analyticsTracker - counts reads of objects selected by analytics
+// configurations on this server, for analyticsRetentionDays.
+type analyticsTracker struct {
+	mu     sync.Mutex
+	reads  map[analyticsKey]analyticsReads
+	pruned string
+}
+
+// newAnalyticsTracker - returns a tracker with no reads.
+func newAnalyticsTracker() *analyticsTracker {
+	return &analyticsTracker{reads: make(map[analyticsKey]analyticsReads)}
+}
+
+// record - counts a read of size bytes of an object for all
+// configurations selecting it.
+func (t *analyticsTracker) record(bucket string, configs []analyticsConfiguration, objInfo ObjectInfo, size int64, now time.Time) {
+	date := now.Format(analyticsDateFormat)
+	storageClass := getObjectStorageClass(objInfo)
+	objectAge := getAnalyticsObjectAge(objInfo.ModTime, now)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Forget reads past retention once a day.
+	if t.pruned != date {
+		oldest := now.AddDate(0, 0, -analyticsRetentionDays).Format(analyticsDateFormat)
+		for key := range t.reads {
+			if key.date < oldest {
+				delete(t.reads, key)
+			}
+		}
+		t.pruned = date
+	}
+
+	for _, config := range configs {
+		if !hasPrefix(objInfo.Name, config.getPrefix()) {
+			continue
+		}
+		key := analyticsKey{bucket, config.ID, date, storageClass, objectAge}
+		reads := t.reads[key]
+		reads.requests++
+		reads.bytes += uint64(size)
+		t.reads[key] = reads
+	}
+}
+
+// getUsage - returns reads of objects of bucket counted on this
+// server.
+func (t *analyticsTracker) getUsage(bucket string) []analyticsUsageEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := []analyticsUsageEntry{}
+	for key, reads := range t.reads {
+		if key.bucket != bucket {
+			continue
+		}
+		usage = append(usage, analyticsUsageEntry{
+			ConfigID:       key.configID,
+			Date:           key.date,
+			StorageClass:   key.storageClass,
+			ObjectAge:      key.objectAge,
+			GetRequests:    reads.requests,
+			BytesRetrieved: reads.bytes,
+		})
+	}
+	return mergeAnalyticsUsage(usage)
+}
+
+// mergeAnalyticsUsage - sums up reads counted by all servers, sorted
+// by configuration, date, storage class and age group.
+func mergeAnalyticsUsage(usages ...[]analyticsUsageEntry) []analyticsUsageEntry {
+	type usageKey struct {
+		configID, date, storageClass, objectAge string
+	}
+	merged := make(map[usageKey]analyticsUsageEntry)
+	for _, usage := range usages {
+		for _, entry := range usage {
+			key := usageKey{entry.ConfigID, entry.Date, entry.StorageClass, entry.ObjectAge}
+			sum, ok := merged[key]
+			if !ok {
+				sum = entry
+			} else {
+				sum.GetRequests += entry.GetRequests
+				sum.BytesRetrieved += entry.BytesRetrieved
+			}
+			merged[key] = sum
+		}
+	}
+
+	result := make([]analyticsUsageEntry, 0, len(merged))
+	for _, entry := range merged {
+		result = append(result, entry)
+	}
+	sort.Sort(analyticsUsageEntries(result))
+	return result
+}
+
+// analyticsUsageEntries - sorts reads by configuration, date, storage
+// class and age group.
+type analyticsUsageEntries []analyticsUsageEntry
+
+func (e analyticsUsageEntries) Len() int      { return len(e) }
+func (e analyticsUsageEntries) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e analyticsUsageEntries) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	if a.ConfigID != b.ConfigID {
+		return a.ConfigID < b.ConfigID
+	}
+	if a.Date != b.Date {
+		return a.Date < b.Date
+	}
+	if a.StorageClass != b.StorageClass {
+		return a.StorageClass < b.StorageClass
+	}
+	return a.ObjectAge < b.ObjectAge
+}
+
+// recordAnalyticsRead - counts a read of size bytes of an object for
+// analytics configurations of its bucket.
+func recordAnalyticsRead(objAPI ObjectLayer, bucket string, objInfo ObjectInfo, size int64) {
+	configs, err := getCachedBucketAnalytics(bucket, objAPI)
+	if err != nil || len(configs) == 0 {
+		return
+	}
+	globalAnalyticsTracker.record(bucket, configs, objInfo, size, UTCNow())
+}
+
+// getClusterAnalyticsUsage - returns reads of objects of bucket
+// counted by all servers.
+func getClusterAnalyticsUsage(bucket string) []analyticsUsageEntry {
+	peerUsage := make([][]analyticsUsageEntry, len(globalAdminPeers))
+	var wg sync.WaitGroup
+	for i, p := range globalAdminPeers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			usage, err := peer.cmdRunner.BucketAnalytics(bucket)
+			if err != nil {
+				errorIf(err, "Unable to get bucket analytics from %s.", peer.addr)
+				return
+			}
+			peerUsage[idx] = usage
+		}(i, p)
+	}
+	wg.Wait()
+	return mergeAnalyticsUsage(peerUsage...)
+}
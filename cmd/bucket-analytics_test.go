@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests parsing and validation of analytics configurations.
+func TestParseAnalyticsConfiguration(t *testing.T) {
+	export := `<StorageClassAnalysis><DataExport><OutputSchemaVersion>V_1</OutputSchemaVersion><Destination><S3BucketDestination>` +
+		`<Format>CSV</Format><Bucket>arn:aws:s3:::reports</Bucket><Prefix>daily</Prefix></S3BucketDestination></Destination></DataExport></StorageClassAnalysis>`
+
+	testCases := []struct {
+		config         string
+		id             string
+		expectedErr    APIErrorCode
+		expectedPrefix string
+	}{
+		// Whole bucket, no export.
+		{`<AnalyticsConfiguration><Id>all</Id><StorageClassAnalysis/></AnalyticsConfiguration>`, "all", ErrNone, ""},
+		// Prefix filter with export.
+		{`<AnalyticsConfiguration><Id>logs</Id><Filter><Prefix>logs/</Prefix></Filter>` + export + `</AnalyticsConfiguration>`, "logs", ErrNone, "logs/"},
+		// Prefix inside And.
+		{`<AnalyticsConfiguration><Id>logs</Id><Filter><And><Prefix>logs/</Prefix></And></Filter><StorageClassAnalysis/></AnalyticsConfiguration>`, "logs", ErrNone, "logs/"},
+		// Malformed XML.
+		{`<AnalyticsConfiguration>`, "logs", ErrMalformedXML, ""},
+		// Id doesn't match the request.
+		{`<AnalyticsConfiguration><Id>logs</Id><StorageClassAnalysis/></AnalyticsConfiguration>`, "other", ErrInvalidAnalyticsConfiguration, ""},
+		// Missing and invalid ids.
+		{`<AnalyticsConfiguration><StorageClassAnalysis/></AnalyticsConfiguration>`, "", ErrInvalidAnalyticsConfiguration, ""},
+		{`<AnalyticsConfiguration><Id>a/b</Id><StorageClassAnalysis/></AnalyticsConfiguration>`, "a/b", ErrInvalidAnalyticsConfiguration, ""},
+		// Tag filters are not supported.
+		{`<AnalyticsConfiguration><Id>logs</Id><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><StorageClassAnalysis/></AnalyticsConfiguration>`, "logs", ErrNotImplemented, ""},
+		{`<AnalyticsConfiguration><Id>logs</Id><Filter><And><Prefix>logs/</Prefix><Tag><Key>k</Key><Value>v</Value></Tag></And></Filter><StorageClassAnalysis/></AnalyticsConfiguration>`, "logs", ErrNotImplemented, ""},
+		// Unsupported export format and invalid destination.
+		{`<AnalyticsConfiguration><Id>logs</Id>` + strings.Replace(export, "CSV", "ORC", 1) + `</AnalyticsConfiguration>`, "logs", ErrInvalidAnalyticsConfiguration, ""},
+		{`<AnalyticsConfiguration><Id>logs</Id>` + strings.Replace(export, "arn:aws:s3:::", "", 1) + `</AnalyticsConfiguration>`, "logs", ErrInvalidAnalyticsConfiguration, ""},
+		{`<AnalyticsConfiguration><Id>logs</Id>` + strings.Replace(export, "reports", "r", 1) + `</AnalyticsConfiguration>`, "logs", ErrInvalidAnalyticsConfiguration, ""},
+	}
+
+	for i, testCase := range testCases {
+		config, s3Error := parseAnalyticsConfiguration([]byte(testCase.config), testCase.id)
+		if s3Error != testCase.expectedErr {
+			t.Fatalf("Test %d: expected error %d, got %d", i+1, testCase.expectedErr, s3Error)
+		}
+		if s3Error == ErrNone && config.getPrefix() != testCase.expectedPrefix {
+			t.Fatalf("Test %d: expected prefix %q, got %q", i+1, testCase.expectedPrefix, config.getPrefix())
+		}
+	}
+}
+
+// Tests age groups of objects.
+func TestGetAnalyticsObjectAge(t *testing.T) {
+	now := time.Date(2017, time.September, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{0, "000-014"},
+		{14*24*time.Hour + time.Hour, "000-014"},
+		{15 * 24 * time.Hour, "015-029"},
+		{44 * 24 * time.Hour, "030-044"},
+		{100 * 24 * time.Hour, "090-119"},
+		{364 * 24 * time.Hour, "180-364"},
+		{365 * 24 * time.Hour, "365+"},
+		{1000 * 24 * time.Hour, "365+"},
+	}
+	for i, testCase := range testCases {
+		if age := getAnalyticsObjectAge(now.Add(-testCase.age), now); age != testCase.expected {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.expected, age)
+		}
+	}
+}
+
+// Tests counting reads and merging reads of several servers.
+func TestAnalyticsTracker(t *testing.T) {
+	now := time.Date(2017, time.September, 1, 12, 0, 0, 0, time.UTC)
+	configs := []analyticsConfiguration{
+		{ID: "all"},
+		{ID: "logs", Filter: &analyticsFilter{Prefix: "logs/"}},
+	}
+	tracker := newAnalyticsTracker()
+
+	// Read a month ago, forgotten once reads of today are counted.
+	tracker.record("bucket", configs, ObjectInfo{Name: "data/old", ModTime: now.AddDate(0, -2, 0)}, 1, now.AddDate(0, 0, -32))
+	tracker.record("bucket", configs, ObjectInfo{Name: "logs/a", ModTime: now}, 10, now)
+	tracker.record("bucket", configs, ObjectInfo{Name: "logs/b", ModTime: now.Add(-time.Hour)}, 5, now)
+	tracker.record("bucket", configs, ObjectInfo{Name: "data/c", ModTime: now.AddDate(0, 0, -20)}, 7, now)
+	tracker.record("other", configs, ObjectInfo{Name: "logs/a", ModTime: now}, 100, now)
+
+	expected := []analyticsUsageEntry{
+		{"all", "2017-09-01", "STANDARD", "000-014", 2, 15},
+		{"all", "2017-09-01", "STANDARD", "015-029", 1, 7},
+		{"logs", "2017-09-01", "STANDARD", "000-014", 2, 15},
+	}
+	usage := tracker.getUsage("bucket")
+	if len(usage) != len(expected) {
+		t.Fatalf("Expected %d entries, got %+v", len(expected), usage)
+	}
+	for i := range expected {
+		if usage[i] != expected[i] {
+			t.Fatalf("Entry %d: expected %+v, got %+v", i, expected[i], usage[i])
+		}
+	}
+
+	// Reads of the same objects on another server are summed up.
+	merged := mergeAnalyticsUsage(usage, []analyticsUsageEntry{
+		{"logs", "2017-09-01", "STANDARD", "000-014", 1, 1},
+		{"logs", "2017-08-31", "STANDARD", "000-014", 1, 1},
+	})
+	if len(merged) != 4 || merged[2].Date != "2017-08-31" || merged[3].GetRequests != 3 || merged[3].BytesRetrieved != 16 {
+		t.Fatalf("Unexpected merged usage %+v", merged)
+	}
+}
+
+// Tests CSV reports of analytics configurations.
+func TestFormatAnalyticsCSV(t *testing.T) {
+	config := analyticsConfiguration{ID: "logs", Filter: &analyticsFilter{Prefix: "logs/"}}
+	storage := []analyticsStorageEntry{
+		{"logs", "STANDARD", "000-014", 2, 2 * 1024 * 1024},
+		{"other", "STANDARD", "000-014", 1, 1},
+	}
+	usage := []analyticsUsageEntry{
+		{"logs", "2017-09-01", "STANDARD", "000-014", 3, 1024 * 1024},
+		{"logs", "2017-09-01", "GLACIER", "365+", 1, 512 * 1024},
+		{"logs", "2017-08-31", "STANDARD", "000-014", 9, 9},
+	}
+	data, err := formatAnalyticsCSV(config, "2017-09-01", storage, usage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Date,ConfigId,Filter,StorageClass,ObjectAge,ObjectCount,Storage_MB,DataRetrieved_MB,GetRequestCount\n" +
+		"2017-09-01,logs,prefix=logs/,GLACIER,365+,0,0.000000,0.500000,1\n" +
+		"2017-09-01,logs,prefix=logs/,STANDARD,000-014,2,2.000000,1.000000,3\n"
+	if string(data) != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, data)
+	}
+}
+
+// Wrapper for calling analytics export tests for both XL and FS.
+func TestAnalyticsExporter(t *testing.T) {
+	ExecObjectLayerTest(t, testAnalyticsExporter)
+}
+
+func testAnalyticsExporter(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket, dstBucket := "bucket", "reports"
+	for _, b := range []string{bucket, dstBucket} {
+		if err := obj.MakeBucket(b); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	data := []byte("hello, world")
+	for _, object := range []string{"logs/a", "logs/b", "data/c"} {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	config, s3Error := parseAnalyticsConfiguration([]byte(`<AnalyticsConfiguration><Id>logs</Id><Filter><Prefix>logs/</Prefix></Filter>`+
+		`<StorageClassAnalysis><DataExport><OutputSchemaVersion>V_1</OutputSchemaVersion><Destination><S3BucketDestination>`+
+		`<Format>CSV</Format><Bucket>arn:aws:s3:::reports</Bucket><Prefix>daily</Prefix></S3BucketDestination></Destination></DataExport>`+
+		`</StorageClassAnalysis></AnalyticsConfiguration>`), "logs")
+	if s3Error != ErrNone {
+		t.Fatalf("%s: unexpected error %d", instanceType, s3Error)
+	}
+	if err := persistBucketAnalytics(bucket, config, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, err := getBucketAnalytics(bucket, "other", obj); errorCause(err) != errNoSuchAnalyticsConfiguration {
+		t.Fatalf("%s: expected errNoSuchAnalyticsConfiguration, got %v", instanceType, err)
+	}
+
+	now := UTCNow()
+	yesterday := now.AddDate(0, 0, -1).Format(analyticsDateFormat)
+	usage := []analyticsUsageEntry{{"logs", yesterday, "STANDARD", "000-014", 4, 48}}
+	exporter := newAnalyticsExporter(func() ObjectLayer { return obj },
+		func(string) []analyticsUsageEntry { return usage }, time.Hour)
+	exporter.export(now)
+
+	reportPath := getAnalyticsExportPath("daily", bucket, "logs", yesterday)
+	var buffer bytes.Buffer
+	if err := obj.GetObject(dstBucket, reportPath, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	expected := "Date,ConfigId,Filter,StorageClass,ObjectAge,ObjectCount,Storage_MB,DataRetrieved_MB,GetRequestCount\n" +
+		yesterday + ",logs,prefix=logs/,STANDARD,000-014,2,0.000023,0.000046,4\n"
+	if buffer.String() != expected {
+		t.Fatalf("%s: expected report\n%s\ngot\n%s", instanceType, expected, buffer.String())
+	}
+
+	// Reports already written are left as they are.
+	usage = []analyticsUsageEntry{}
+	exporter.export(now)
+	buffer.Reset()
+	if err := obj.GetObject(dstBucket, reportPath, 0, -1, &buffer); err != nil || buffer.String() != expected {
+		t.Fatalf("%s: report overwritten, %v", instanceType, err)
+	}
+
+	if err := removeBucketAnalytics(bucket, "logs", obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if configs, err := loadBucketAnalytics(bucket, obj); err != nil || len(configs) != 0 {
+		t.Fatalf("%s: expected no configurations, got %v, %v", instanceType, configs, err)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errNoSuchBucketClone - returned when no clone into a bucket was
+// started on this server.
+var errNoSuchBucketClone = errors.New("No clone into the specified bucket was started on this server")
+
+// Methods of cloning a bucket.
+const (
+	// Object data is shared with the source bucket until either
+	// object is overwritten.
+	bucketCloneMethodLink = "link"
+	// Object data is copied.
+	bucketCloneMethodCopy = "copy"
+)
+
+// States of cloning a bucket.
+const (
+	bucketCloneRunning = "running"
+	bucketCloneDone    = "done"
+)
+
+// objectLinker is implemented by object layers able to clone objects
+// cheaply, sharing data with the source object until either object
+// is overwritten.
+type objectLinker interface {
+	LinkObject(srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, error)
+}
+
+// bucketCloneStatus - progress of cloning a bucket, reported by
+// admin API.
+type bucketCloneStatus struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Method string `json:"method"`
+	State  string `json:"state"`
+
+	// Cloned objects and their total size.
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+	// Objects which could not be cloned, along with the last error.
+	// Transitioned objects are never cloned, their remote copy can't
+	// be shared.
+	Failed    int64  `json:"failed"`
+	LastError string `json:"lastError,omitempty"`
+
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// bucketClones - clones of buckets started on this server, by
+// target bucket. Objects are cloned in background, the target
+// bucket is usable right away and fills up as cloning progresses.
+type bucketClones struct {
+	mu     sync.Mutex
+	clones map[string]*bucketCloneStatus
+}
+
+// newBucketClones - returns a new bucketClones.
+func newBucketClones() *bucketClones {
+	return &bucketClones{
+		clones: make(map[string]*bucketCloneStatus),
+	}
+}
+
+// start - creates target bucket and starts cloning objects of source
+// bucket into it, objects are linked when the object layer supports
+// it and copied otherwise.
+func (c *bucketClones) start(objAPI ObjectLayer, source, target string) (bucketCloneStatus, error) {
+	if _, err := objAPI.GetBucketInfo(source); err != nil {
+		return bucketCloneStatus{}, err
+	}
+	if err := objAPI.MakeBucket(target); err != nil {
+		return bucketCloneStatus{}, err
+	}
+
+	status := &bucketCloneStatus{
+		Source:  source,
+		Target:  target,
+		Method:  bucketCloneMethodCopy,
+		State:   bucketCloneRunning,
+		Started: UTCNow(),
+	}
+	if _, ok := objAPI.(objectLinker); ok {
+		status.Method = bucketCloneMethodLink
+	}
+
+	c.mu.Lock()
+	c.clones[target] = status
+	snapshot := *status
+	c.mu.Unlock()
+
+	go c.run(objAPI, status)
+	return snapshot, nil
+}
+
+// get - returns progress of cloning into target bucket.
+func (c *bucketClones) get(target string) (bucketCloneStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status, ok := c.clones[target]
+	if !ok {
+		return bucketCloneStatus{}, errNoSuchBucketClone
+	}
+	return *status, nil
+}
+
+// run - clones all objects of the source bucket, objects created or
+// removed meanwhile may or may not be cloned.
+func (c *bucketClones) run(objAPI ObjectLayer, status *bucketCloneStatus) {
+	defer func() {
+		c.mu.Lock()
+		finished := UTCNow()
+		status.State = bucketCloneDone
+		status.Finished = &finished
+		c.mu.Unlock()
+	}()
+
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(status.Source, "", marker, "", maxObjectList)
+		if err != nil {
+			c.mu.Lock()
+			status.LastError = err.Error()
+			c.mu.Unlock()
+			errorIf(err, "Unable to list objects of %s to clone into %s.", status.Source, status.Target)
+			return
+		}
+
+		for _, objInfo := range result.Objects {
+			size, err := cloneObject(objAPI, status.Source, status.Target, objInfo.Name)
+			c.mu.Lock()
+			if err != nil {
+				status.Failed++
+				status.LastError = err.Error()
+			} else if size >= 0 {
+				status.Objects++
+				status.Size += size
+			}
+			c.mu.Unlock()
+		}
+
+		if !result.IsTruncated {
+			return
+		}
+		marker = result.NextMarker
+	}
+}
+
+// cloneObject - clones an object into target bucket, by linking when
+// supported and copying otherwise. Returns size of the cloned object,
+// -1 if the object was removed since listed.
+func cloneObject(objAPI ObjectLayer, source, target, object string) (int64, error) {
+	srcLock := globalNSMutex.NewNSLock(source, object)
+	srcLock.RLock()
+	defer srcLock.RUnlock()
+
+	dstLock := globalNSMutex.NewNSLock(target, object)
+	dstLock.Lock()
+	defer dstLock.Unlock()
+
+	// Object may have changed since listed.
+	srcInfo, err := objAPI.GetObjectInfo(source, object)
+	if _, ok := errorCause(err).(ObjectNotFound); ok {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if isObjectTransitioned(srcInfo) {
+		return 0, errors.New("transitioned object " + object + " can't be cloned")
+	}
+
+	if linker, ok := objAPI.(objectLinker); ok {
+		var dstInfo ObjectInfo
+		if dstInfo, err = linker.LinkObject(source, object, target, object); err == nil {
+			return dstInfo.Size, nil
+		}
+		// Linking is not supported by every filesystem, fall back
+		// to copying.
+		errorIf(err, "Unable to link %s/%s into %s, copying it instead.", source, object, target)
+	}
+
+	dstInfo, err := objAPI.CopyObject(source, object, target, object, srcInfo.UserDefined)
+	if err != nil {
+		return 0, err
+	}
+	return dstInfo.Size, nil
+}
@@ -0,0 +1,199 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Returns true if err is BucketNotFound.
+func isBucketNotFound(err error) bool {
+	_, ok := errorCause(err).(BucketNotFound)
+	return ok
+}
+
+// Waits for cloning into target bucket to finish.
+func waitBucketClone(t *testing.T, clones *bucketClones, target string) bucketCloneStatus {
+	for i := 0; i < 500; i++ {
+		status, err := clones.get(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.State == bucketCloneDone {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Cloning into %s did not finish in time", target)
+	return bucketCloneStatus{}
+}
+
+// Tests cloning buckets on FS and XL backends.
+func TestBucketClone(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketClone)
+}
+
+func testBucketClone(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	source, target := "production", "staging"
+	if err := obj.MakeBucket(source); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string]string{
+		"a":       "hello",
+		"b/c":     "world",
+		"b/d/e.f": "",
+	}
+	for object, data := range objects {
+		metadata := map[string]string{"content-type": "text/plain"}
+		if _, err := obj.PutObject(source, object, int64(len(data)), bytes.NewReader([]byte(data)), metadata, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	transitioned := map[string]string{transitionStorageClassKey: "GLACIER"}
+	if _, err := obj.PutObject(source, "transitioned", 0, bytes.NewReader(nil), transitioned, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	clones := newBucketClones()
+	if _, err := clones.get(target); err != errNoSuchBucketClone {
+		t.Fatalf("%s: Expected %s, got %v", instanceType, errNoSuchBucketClone, err)
+	}
+	if _, err := clones.start(obj, "nosuchbucket", target); !isBucketNotFound(err) {
+		t.Fatalf("%s: Expected BucketNotFound, got %v", instanceType, err)
+	}
+	if _, err := clones.start(obj, source, source); err == nil {
+		t.Fatalf("%s: Expected cloning into an existing bucket to fail", instanceType)
+	}
+
+	status, err := clones.start(obj, source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMethod := bucketCloneMethodCopy
+	if instanceType == FSTestStr {
+		expectedMethod = bucketCloneMethodLink
+	}
+	if status.Method != expectedMethod {
+		t.Errorf("%s: Expected method %s, got %s", instanceType, expectedMethod, status.Method)
+	}
+
+	status = waitBucketClone(t.(*testing.T), clones, target)
+	if status.Objects != int64(len(objects)) || status.Size != 10 || status.Failed != 1 || status.Finished == nil {
+		t.Errorf("%s: Unexpected status %+v", instanceType, status)
+	}
+
+	// Overwriting or deleting source objects leaves clones untouched.
+	if _, err = obj.PutObject(source, "a", 3, bytes.NewReader([]byte("bye")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.DeleteObject(source, "b/c"); err != nil {
+		t.Fatal(err)
+	}
+	for object, data := range objects {
+		var buffer bytes.Buffer
+		if err = obj.GetObject(target, object, 0, int64(len(data)), &buffer); err != nil {
+			t.Fatalf("%s: Unable to read clone of %s: %v", instanceType, object, err)
+		}
+		if buffer.String() != data {
+			t.Errorf("%s: Expected clone of %s to be %q, got %q", instanceType, object, data, buffer.String())
+		}
+		objInfo, err := obj.GetObjectInfo(target, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.ContentType != "text/plain" {
+			t.Errorf("%s: Expected metadata of %s to be cloned, got %v", instanceType, object, objInfo.UserDefined)
+		}
+	}
+	if _, err = obj.GetObjectInfo(target, "transitioned"); !isErrObjectNotFound(err) {
+		t.Errorf("%s: Expected transitioned object not to be cloned, got %v", instanceType, err)
+	}
+}
+
+// Tests linked objects share data until either is overwritten.
+func TestFSLinkObject(t *testing.T) {
+	obj, disk, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{disk})
+	fs := obj.(*fsObjects)
+
+	for _, bucket := range []string{"src", "dst"} {
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metadata := map[string]string{"content-type": "text/plain", "X-Amz-Meta-Color": "blue"}
+	srcInfo, err := obj.PutObject("src", "dir/object", 5, bytes.NewReader([]byte("hello")), metadata, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = fs.LinkObject("src", "nosuchobject", "dst", "object"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+	if _, err = fs.LinkObject("src", "dir/object", "nosuchbucket", "object"); !isBucketNotFound(err) {
+		t.Fatalf("Expected BucketNotFound, got %v", err)
+	}
+
+	dstInfo, err := fs.LinkObject("src", "dir/object", "dst", "dir/object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dstInfo.Bucket != "dst" || dstInfo.Size != srcInfo.Size || dstInfo.MD5Sum != srcInfo.MD5Sum ||
+		!reflect.DeepEqual(dstInfo.UserDefined, srcInfo.UserDefined) {
+		t.Fatalf("Expected %+v, got %+v", srcInfo, dstInfo)
+	}
+
+	srcFI, err := os.Stat(pathJoin(disk, "src", "dir/object"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstFI, err := os.Stat(pathJoin(disk, "dst", "dir/object"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(srcFI, dstFI) {
+		t.Fatal("Expected linked object to share data")
+	}
+
+	// Metadata is not shared.
+	if _, err = obj.CopyObject("src", "dir/object", "src", "dir/object", map[string]string{"content-type": "image/png"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject("src", "dir/object", 3, bytes.NewReader([]byte("bye")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject("dst", "dir/object", 0, 5, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != "hello" {
+		t.Fatalf("Expected linked object to be unchanged, got %q", buffer.String())
+	}
+	if dstInfo, err = obj.GetObjectInfo("dst", "dir/object"); err != nil {
+		t.Fatal(err)
+	}
+	if dstInfo.ContentType != "text/plain" || dstInfo.UserDefined["X-Amz-Meta-Color"] != "blue" {
+		t.Fatalf("Expected linked object metadata to be unchanged, got %v", dstInfo.UserDefined)
+	}
+}
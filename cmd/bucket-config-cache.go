@@ -0,0 +1,129 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// Duration for which a configuration of a bucket is cached. Servers
+// changing a configuration notify their peers, so this only bounds
+// how long a server missing the notification serves a stale one.
+const bucketConfigCacheTTL = 10 * time.Second
+
+// bucketConfigKey - a configuration of a bucket, named after its file
+// in bucketConfigs.
+type bucketConfigKey struct {
+	bucket string
+	config string
+}
+
+// bucketConfigEntry - cached configuration of a bucket, nil if it is
+// not set.
+type bucketConfigEntry struct {
+	value  interface{}
+	loaded time.Time
+}
+
+// bucketConfigCache - caches configurations of buckets which are read
+// on every request, so that they aren't loaded from minioMetaBucket
+// each time.
+type bucketConfigCache struct {
+	mu      sync.Mutex
+	entries map[bucketConfigKey]bucketConfigEntry
+}
+
+// newBucketConfigCache - returns an empty cache of bucket configurations.
+func newBucketConfigCache() *bucketConfigCache {
+	return &bucketConfigCache{entries: make(map[bucketConfigKey]bucketConfigEntry)}
+}
+
+// get - returns the cached configuration of bucket, calling load if
+// it isn't cached or it expired.
+func (c *bucketConfigCache) get(bucket, config string, load func() (interface{}, error)) (interface{}, error) {
+	key := bucketConfigKey{bucket, config}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && UTCNow().Sub(entry.loaded) < bucketConfigCacheTTL {
+		return entry.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[key] = bucketConfigEntry{value: value, loaded: UTCNow()}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// remove - forgets a configuration of bucket, all of them if config
+// is empty. It is loaded again on next use.
+func (c *bucketConfigCache) remove(bucket, config string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if config != "" {
+		delete(c.entries, bucketConfigKey{bucket, config})
+		return
+	}
+	for key := range c.entries {
+		if key.bucket == bucket {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// BucketConfigPeerArgs - Arguments collection to BucketConfigPeer RPC
+// call.
+type BucketConfigPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	Bucket string
+
+	// Name of the changed configuration, empty if the bucket was
+	// deleted.
+	Config string
+}
+
+// BucketUpdate - notifies a peer of a changed bucket configuration.
+func (s *BucketConfigPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.UpdateBucketConfig(s)
+}
+
+// notifyBucketConfigChange - forgets a cached configuration of bucket
+// on this server and all the other servers, all configurations of the
+// bucket if config is empty. Called once a configuration is saved or
+// removed, and once a bucket is deleted.
+func notifyBucketConfigChange(bucket, config string) {
+	globalBucketConfigCache.remove(bucket, config)
+
+	// First peer is always the local node.
+	if len(globalS3Peers) > 1 {
+		peerIndex := make([]int, 0, len(globalS3Peers)-1)
+		for idx := 1; idx < len(globalS3Peers); idx++ {
+			peerIndex = append(peerIndex, idx)
+		}
+		args := &BucketConfigPeerArgs{Bucket: bucket, Config: config}
+		for idx, err := range globalS3Peers.SendUpdate(peerIndex, args) {
+			errorIf(err, "Unable to send bucket config change of %s to %s", bucket, globalS3Peers[idx].addr)
+		}
+	}
+}
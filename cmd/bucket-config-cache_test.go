@@ -0,0 +1,82 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+// Tests configurations are cached per bucket and config name until
+// they are removed.
+func TestBucketConfigCache(t *testing.T) {
+	cache := newBucketConfigCache()
+	loads := 0
+	get := func(bucket, config string) (interface{}, error) {
+		return cache.get(bucket, config, func() (interface{}, error) {
+			loads++
+			return bucket + "/" + config, nil
+		})
+	}
+
+	testCases := []struct {
+		bucket, config string
+		loads          int
+	}{
+		{"bucket1", bucketWebsiteConfig, 1},
+		// Cached.
+		{"bucket1", bucketWebsiteConfig, 1},
+		// Other config and other bucket.
+		{"bucket1", bucketLifecycleConfig, 2},
+		{"bucket2", bucketWebsiteConfig, 3},
+	}
+	for i, testCase := range testCases {
+		value, err := get(testCase.bucket, testCase.config)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if value != testCase.bucket+"/"+testCase.config || loads != testCase.loads {
+			t.Errorf("Test %d: unexpected value %v after %d loads", i+1, value, loads)
+		}
+	}
+
+	// Removing a config of a bucket keeps the others.
+	cache.remove("bucket1", bucketWebsiteConfig)
+	get("bucket1", bucketWebsiteConfig)
+	get("bucket1", bucketLifecycleConfig)
+	if loads != 4 {
+		t.Errorf("Expected only the removed config to be loaded again, got %d loads", loads)
+	}
+
+	// Removing all configs of a bucket keeps other buckets.
+	cache.remove("bucket1", "")
+	get("bucket1", bucketWebsiteConfig)
+	get("bucket1", bucketLifecycleConfig)
+	get("bucket2", bucketWebsiteConfig)
+	if loads != 6 {
+		t.Errorf("Expected configs of the bucket to be loaded again, got %d loads", loads)
+	}
+
+	// Failed loads are not cached.
+	errLoad := errors.New("load failed")
+	if _, err := cache.get("bucket3", bucketWebsiteConfig, func() (interface{}, error) { return nil, errLoad }); err != errLoad {
+		t.Fatalf("Expected %v, got %v", errLoad, err)
+	}
+	if value, _ := get("bucket3", bucketWebsiteConfig); value != "bucket3/"+bucketWebsiteConfig {
+		t.Errorf("Expected failed load not to be cached, got %v", value)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// bucketConfigs - names of configuration files saved for a bucket
+// under bucketConfigPrefix in minioMetaBucket. A feature adding a
+// bucket configuration registers its name here, so that tools working
+// on all bucket configurations, e.g. migration, don't miss it.
+// Listeners are registered by servers running at the time and are not
+// part of the bucket configuration.
+var bucketConfigs = []string{
+	bucketPolicyConfig,
+	bucketNotificationConfig,
+	bucketLifecycleConfig,
+	bucketWormConfig,
+	bucketUsageAlertConfig,
+	bucketTaggingConfig,
+	bucketAnalyticsConfig,
+	bucketShareLinksConfig,
+	bucketDefaultMetadataConfig,
+	bucketWebsiteConfig,
+	bucketKeyConfig,
+	bucketObjectLockConfig,
+}
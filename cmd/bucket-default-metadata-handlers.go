@@ -0,0 +1,175 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// readDefaultMetadataBody - reads default metadata XML from request
+// body.
+func readDefaultMetadataBody(r *http.Request) ([]byte, APIErrorCode) {
+	// Default metadata always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxDefaultMetadataBodySize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+	return buffer.Bytes(), ErrNone
+}
+
+// GetBucketDefaultMetadataHandler - GET Bucket default metadata
+// -----------------
+// Returns the default metadata rules of the bucket, Minio extension.
+func (api objectAPIHandlers) GetBucketDefaultMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	dm, err := loadBucketDefaultMetadata(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	dmBytes, err := xml.Marshal(dm)
+	if err != nil {
+		errorIf(err, "Unable to marshal default metadata into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, dmBytes)
+}
+
+// PutBucketDefaultMetadataHandler - PUT Bucket default metadata
+// -----------------
+// Replaces the default metadata rules of the bucket, Minio extension.
+// Objects already written are not modified.
+func (api objectAPIHandlers) PutBucketDefaultMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	dmBytes, s3Error := readDefaultMetadataBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	dm, s3Error := parseDefaultMetadata(dmBytes)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = persistBucketDefaultMetadata(bucket, dm, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketDefaultMetadataConfig)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteBucketDefaultMetadataHandler - DELETE Bucket default metadata
+// -----------------
+// Removes the default metadata rules of the bucket, Minio extension.
+func (api objectAPIHandlers) DeleteBucketDefaultMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing default metadata which is not set is not an error.
+	if err = removeBucketDefaultMetadata(bucket, objAPI); err != nil && !isErrObjectNotFound(err) {
+		errorIf(err, "Unable to remove bucket default metadata.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketDefaultMetadataConfig)
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,179 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests PUT, GET and DELETE bucket default metadata.
+func TestBucketDefaultMetadataHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketDefaultMetadataHandlers, []string{"GetBucketDefaultMetadata", "PutBucketDefaultMetadata", "DeleteBucketDefaultMetadata"})
+}
+
+func testBucketDefaultMetadataHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	validDefaultMetadata := `<DefaultMetadataConfiguration><Rule><ID>site</ID><Prefix>site/</Prefix>` +
+		`<Metadata><Name>Cache-Control</Name><Value>max-age=3600</Value></Metadata></Rule></DefaultMetadataConfiguration>`
+	invalidDefaultMetadata := `<DefaultMetadataConfiguration><Rule><Prefix>site/</Prefix>` +
+		`<Metadata><Name>Expires</Name><Value>0</Value></Metadata></Rule></DefaultMetadataConfiguration>`
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		body               string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+	}{
+		// No default metadata set yet.
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Header not saved with objects.
+		{"PUT", bucketName, invalidDefaultMetadata, credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Malformed XML.
+		{"PUT", bucketName, "<DefaultMetadataConfiguration>", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Non-existent bucket.
+		{"PUT", "non-existent-bucket", validDefaultMetadata, credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid credentials.
+		{"PUT", bucketName, validDefaultMetadata, "abcd1234", credentials.SecretKey, http.StatusForbidden},
+		// Valid default metadata.
+		{"PUT", bucketName, validDefaultMetadata, credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Remove default metadata.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Removing again is not an error.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getDefaultMetadataURL("", testCase.bucketName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" && rec.Code == http.StatusOK {
+			var dm defaultMetadata
+			if err = xml.Unmarshal(rec.Body.Bytes(), &dm); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if len(dm.Rules) != 1 || dm.Rules[0].ID != "site" || dm.Rules[0].Prefix != "site/" || len(dm.Rules[0].Metadata) != 1 {
+				t.Fatalf("Test %d: %s: unexpected default metadata %+v", i+1, instanceType, dm.Rules)
+			}
+		}
+	}
+}
+
+// Tests default metadata is saved with objects written under the
+// prefix of a rule, unless sent by the client.
+func TestDefaultMetadataPutObject(t *testing.T) {
+	ExecObjectLayerAPITest(t, testDefaultMetadataPutObject, []string{"PutBucketDefaultMetadata", "PutObject", "HeadObject", "NewMultipart"})
+}
+
+func testDefaultMetadataPutObject(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	// Sends a signed request, returns its response.
+	send := func(method, urlStr string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: %s %s: expected status 200, got %d", instanceType, method, urlStr, rec.Code)
+		}
+		return rec
+	}
+
+	send("PUT", getDefaultMetadataURL("", bucketName), []byte(`<DefaultMetadataConfiguration>`+
+		`<Rule><Prefix>site/</Prefix><Suffix>.html</Suffix>`+
+		`<Metadata><Name>Content-Type</Name><Value>text/html; charset=utf-8</Value></Metadata>`+
+		`<Metadata><Name>Cache-Control</Name><Value>no-cache</Value></Metadata></Rule>`+
+		`<Rule><Prefix>site/</Prefix>`+
+		`<Metadata><Name>Cache-Control</Name><Value>max-age=3600</Value></Metadata>`+
+		`<Metadata><Name>x-amz-meta-site</Name><Value>www</Value></Metadata></Rule>`+
+		`</DefaultMetadataConfiguration>`), nil)
+
+	data := []byte("hello, world")
+	testCases := []struct {
+		object       string
+		header       http.Header
+		contentType  string
+		cacheControl string
+		site         string
+	}{
+		// Test 1: both rules, the first one wins.
+		{"site/index.html", nil, "text/html; charset=utf-8", "no-cache", "www"},
+		// Test 2: second rule only, content type is guessed.
+		{"site/app.js", nil, "application/javascript", "max-age=3600", "www"},
+		// Test 3: headers sent by the client are kept.
+		{"site/a.html", http.Header{"Cache-Control": []string{"private"}, "X-Amz-Meta-Site": []string{"dev"}},
+			"text/html; charset=utf-8", "private", "dev"},
+		// Test 4: object selected by no rule.
+		{"data/a.html", nil, "text/html", "", ""},
+	}
+	for i, testCase := range testCases {
+		send("PUT", getPutObjectURL("", bucketName, testCase.object), data, testCase.header)
+		rec := send("HEAD", getHeadObjectURL("", bucketName, testCase.object), nil, nil)
+		if contentType := rec.Header().Get("Content-Type"); contentType != testCase.contentType {
+			t.Errorf("Test %d: %s: expected Content-Type %q, got %q", i+1, instanceType, testCase.contentType, contentType)
+		}
+		if cacheControl := rec.Header().Get("Cache-Control"); cacheControl != testCase.cacheControl {
+			t.Errorf("Test %d: %s: expected Cache-Control %q, got %q", i+1, instanceType, testCase.cacheControl, cacheControl)
+		}
+		if site := rec.Header().Get("X-Amz-Meta-Site"); site != testCase.site {
+			t.Errorf("Test %d: %s: expected X-Amz-Meta-Site %q, got %q", i+1, instanceType, testCase.site, site)
+		}
+	}
+
+	// Multipart uploads get default metadata when initiated.
+	send("POST", getNewMultipartURL("", bucketName, "site/big.html"), nil, nil)
+	result, err := obj.ListMultipartUploads(bucketName, "site/big.html", "", "", "", 1)
+	if err != nil || len(result.Uploads) != 1 {
+		t.Fatalf("%s: expected one upload, got %v, %v", instanceType, result.Uploads, err)
+	}
+	uploadID := result.Uploads[0].UploadID
+	part, err := obj.PutObjectPart(bucketName, "site/big.html", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	objInfo, err := obj.CompleteMultipartUpload(bucketName, "site/big.html", uploadID, []CompletePart{{PartNumber: 1, ETag: part.ETag}})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.UserDefined["cache-control"] != "no-cache" || objInfo.ContentType != "text/html; charset=utf-8" {
+		t.Fatalf("%s: unexpected metadata %v", instanceType, objInfo.UserDefined)
+	}
+}
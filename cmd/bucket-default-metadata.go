@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+const (
+	// Default metadata config file name, saved alongside other bucket
+	// configs in minioMetaBucket.
+	bucketDefaultMetadataConfig = "default-metadata.xml"
+
+	// Query of the Minio specific default metadata extension.
+	defaultMetadataQuery = "default-metadata"
+
+	// Limits on default metadata configuration.
+	maxDefaultMetadataRules    = 100
+	maxDefaultMetadataRuleID   = 255
+	maxDefaultMetadataHeaders  = 20
+	maxDefaultMetadataBodySize = 256 * 1024
+)
+
+// Internal error used to signal default metadata is not set.
+var errNoSuchDefaultMetadata = errors.New("The default metadata configuration does not exist")
+
+// defaultMetadataHeader - header saved with objects selected by a rule
+// when it is not sent by the client.
+type defaultMetadataHeader struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+// defaultMetadataRule - headers applied to objects of which name has
+// the rule Prefix and Suffix, both optional.
+type defaultMetadataRule struct {
+	ID       string                  `xml:"ID,omitempty"`
+	Prefix   string                  `xml:"Prefix,omitempty"`
+	Suffix   string                  `xml:"Suffix,omitempty"`
+	Metadata []defaultMetadataHeader `xml:"Metadata"`
+}
+
+// matches - returns true if object is selected by the rule.
+func (r defaultMetadataRule) matches(object string) bool {
+	return hasPrefix(object, r.Prefix) && hasSuffix(object, r.Suffix)
+}
+
+// defaultMetadata - default metadata configuration of a bucket, sent
+// and received as
+//
+//	<DefaultMetadataConfiguration><Rule>...</Rule></DefaultMetadataConfiguration>
+type defaultMetadata struct {
+	XMLName xml.Name              `xml:"DefaultMetadataConfiguration"`
+	Rules   []defaultMetadataRule `xml:"Rule"`
+}
+
+// getDefaultMetadataKey - returns the key under which a header is saved
+// in object metadata, ok is false if it is not saved with objects.
+func getDefaultMetadataKey(name string) (key string, ok bool) {
+	for _, supportedHeader := range supportedHeaders {
+		if strings.EqualFold(name, supportedHeader) {
+			return supportedHeader, true
+		}
+	}
+	key = http.CanonicalHeaderKey(name)
+	if strings.HasPrefix(key, "X-Amz-Meta-") && len(key) > len("X-Amz-Meta-") {
+		return key, true
+	}
+	return "", false
+}
+
+// parseDefaultMetadata - parses and validates default metadata XML,
+// returns ErrNone on success.
+func parseDefaultMetadata(data []byte) (defaultMetadata, APIErrorCode) {
+	var dm defaultMetadata
+	if err := xml.Unmarshal(data, &dm); err != nil {
+		return dm, ErrMalformedXML
+	}
+	if len(dm.Rules) == 0 || len(dm.Rules) > maxDefaultMetadataRules {
+		return dm, ErrInvalidDefaultMetadata
+	}
+	ids := set.NewStringSet()
+	for _, rule := range dm.Rules {
+		if len(rule.ID) > maxDefaultMetadataRuleID || (rule.ID != "" && ids.Contains(rule.ID)) {
+			return dm, ErrInvalidDefaultMetadata
+		}
+		if len(rule.Metadata) == 0 || len(rule.Metadata) > maxDefaultMetadataHeaders {
+			return dm, ErrInvalidDefaultMetadata
+		}
+		for _, header := range rule.Metadata {
+			if _, ok := getDefaultMetadataKey(header.Name); !ok || header.Value == "" {
+				return dm, ErrInvalidDefaultMetadata
+			}
+		}
+		if rule.ID != "" {
+			ids.Add(rule.ID)
+		}
+	}
+	return dm, ErrNone
+}
+
+// loadBucketDefaultMetadata - loads default metadata of a bucket,
+// returns errNoSuchDefaultMetadata if none is set.
+func loadBucketDefaultMetadata(bucket string, objAPI ObjectLayer) (*defaultMetadata, error) {
+	dmPath := path.Join(bucketConfigPrefix, bucket, bucketDefaultMetadataConfig)
+
+	// Acquire a read lock on default metadata config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, dmPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, dmPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchDefaultMetadata
+		}
+		errorIf(err, "Unable to load default metadata for bucket %s", bucket)
+		return nil, err
+	}
+
+	dm := &defaultMetadata{}
+	if err = xml.Unmarshal(buffer.Bytes(), dm); err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
+
+// persistBucketDefaultMetadata - saves default metadata of a bucket.
+func persistBucketDefaultMetadata(bucket string, dm defaultMetadata, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(dm)
+	if err != nil {
+		return err
+	}
+
+	dmPath := path.Join(bucketConfigPrefix, bucket, bucketDefaultMetadataConfig)
+
+	// Acquire a write lock on default metadata config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, dmPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, dmPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write default metadata for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketDefaultMetadata - removes default metadata of a bucket.
+func removeBucketDefaultMetadata(bucket string, objAPI ObjectLayer) error {
+	dmPath := path.Join(bucketConfigPrefix, bucket, bucketDefaultMetadataConfig)
+
+	// Acquire a write lock on default metadata config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, dmPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, dmPath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketDefaultMetadata - returns default metadata of bucket,
+// nil if none is set. Default metadata is cached in
+// globalBucketConfigCache.
+func getCachedBucketDefaultMetadata(bucket string, objAPI ObjectLayer) (*defaultMetadata, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketDefaultMetadataConfig, func() (interface{}, error) {
+		dm, err := loadBucketDefaultMetadata(bucket, objAPI)
+		if err == errNoSuchDefaultMetadata {
+			return (*defaultMetadata)(nil), nil
+		}
+		return dm, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*defaultMetadata), nil
+}
+
+// applyDefaultMetadata - adds headers of the rules selecting the object
+// to its metadata, headers sent by the client are kept. Rules are
+// applied in order, the first one setting a header wins.
+func applyDefaultMetadata(objAPI ObjectLayer, bucket, object string, metadata map[string]string) {
+	dm, err := getCachedBucketDefaultMetadata(bucket, objAPI)
+	if err != nil || dm == nil {
+		return
+	}
+	for _, rule := range dm.Rules {
+		if !rule.matches(object) {
+			continue
+		}
+		for _, header := range rule.Metadata {
+			key, ok := getDefaultMetadataKey(header.Name)
+			if !ok {
+				continue
+			}
+			if metadata[key] == "" {
+				metadata[key] = header.Value
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Tests validation of default metadata configurations.
+func TestParseDefaultMetadata(t *testing.T) {
+	rule := func(id, name, value string) string {
+		return fmt.Sprintf(`<Rule><ID>%s</ID><Prefix>site/</Prefix><Metadata><Name>%s</Name><Value>%s</Value></Metadata></Rule>`, id, name, value)
+	}
+	config := func(rules ...string) string {
+		return "<DefaultMetadataConfiguration>" + strings.Join(rules, "") + "</DefaultMetadataConfiguration>"
+	}
+
+	testCases := []struct {
+		data     string
+		expected APIErrorCode
+	}{
+		// Test 1: standard header.
+		{config(rule("a", "Cache-Control", "no-cache")), ErrNone},
+		// Test 2: header names are case insensitive.
+		{config(rule("a", "content-disposition", "inline")), ErrNone},
+		// Test 3: user metadata.
+		{config(rule("a", "X-Amz-Meta-Owner", "web")), ErrNone},
+		// Test 4: several rules.
+		{config(rule("a", "Content-Type", "text/html"), rule("b", "Content-Encoding", "gzip")), ErrNone},
+		// Test 5: malformed XML.
+		{"<DefaultMetadataConfiguration>", ErrMalformedXML},
+		// Test 6: no rule.
+		{config(), ErrInvalidDefaultMetadata},
+		// Test 7: header not saved with objects.
+		{config(rule("a", "Expires", "0")), ErrInvalidDefaultMetadata},
+		// Test 8: user metadata without name.
+		{config(rule("a", "X-Amz-Meta-", "web")), ErrInvalidDefaultMetadata},
+		// Test 9: empty value.
+		{config(rule("a", "Cache-Control", "")), ErrInvalidDefaultMetadata},
+		// Test 10: duplicate rule IDs.
+		{config(rule("a", "Cache-Control", "no-cache"), rule("a", "Content-Type", "text/html")), ErrInvalidDefaultMetadata},
+		// Test 11: rule without metadata.
+		{config(`<Rule><Prefix>site/</Prefix></Rule>`), ErrInvalidDefaultMetadata},
+	}
+	for i, testCase := range testCases {
+		if _, s3Error := parseDefaultMetadata([]byte(testCase.data)); s3Error != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests objects are selected by prefix and suffix of rules.
+func TestDefaultMetadataRuleMatches(t *testing.T) {
+	testCases := []struct {
+		rule     defaultMetadataRule
+		object   string
+		expected bool
+	}{
+		// Test 1: rule of all objects.
+		{defaultMetadataRule{}, "a/b.html", true},
+		// Test 2: prefix.
+		{defaultMetadataRule{Prefix: "a/"}, "a/b.html", true},
+		{defaultMetadataRule{Prefix: "b/"}, "a/b.html", false},
+		// Test 4: suffix.
+		{defaultMetadataRule{Suffix: ".html"}, "a/b.html", true},
+		{defaultMetadataRule{Suffix: ".css"}, "a/b.html", false},
+		// Test 6: both.
+		{defaultMetadataRule{Prefix: "a/", Suffix: ".html"}, "a/b.html", true},
+		{defaultMetadataRule{Prefix: "a/", Suffix: ".html"}, "b/b.html", false},
+	}
+	for i, testCase := range testCases {
+		if matches := testCase.rule.matches(testCase.object); matches != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, matches)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -69,38 +70,65 @@ func (api objectAPIHandlers) ListObjectsV2Handler(w http.ResponseWriter, r *http
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:ListBucket", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
 	// Extract all the listObjectsV2 query params to their native values.
-	prefix, token, startAfter, delimiter, fetchOwner, maxKeys, _ := getListObjectsV2Args(r.URL.Query())
+	prefix, token, startAfter, delimiter, fetchOwner, maxKeys, encodingType := getListObjectsV2Args(r.URL.Query())
 
-	// In ListObjectsV2 'continuation-token' is the marker.
-	marker := token
-	// Check if 'continuation-token' is empty.
-	if token == "" {
-		// Then we need to use 'start-after' as marker instead.
-		marker = startAfter
+	// Only 'url' encoding of object names is supported.
+	if encodingType != "" && !strings.EqualFold(encodingType, urlEncodingType) {
+		writeErrorResponse(w, ErrInvalidEncodingMethod, r.URL)
+		return
 	}
+
+	fields, s3Error := getListObjectsFields(r.URL.Query())
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// In ListObjectsV2 'continuation-token' carries the marker, if
+	// empty 'start-after' is used as marker instead.
+	marker := startAfter
+	if token != "" {
+		var err error
+		if marker, err = decodeContinuationToken(token); err != nil {
+			writeErrorResponse(w, ErrInvalidContinuationToken, r.URL)
+			return
+		}
+	}
+
 	// Validate the query params before beginning to serve the request.
 	// fetch-owner is not validated since it is a boolean
-	if s3Error := validateListObjectsArgs(prefix, marker, delimiter, maxKeys); s3Error != ErrNone {
+	if s3Error := validateListObjectsArgs(prefix, "", delimiter, maxKeys); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
-	// Inititate a list objects operation based on the input params.
-	// On success would return back ListObjectsInfo object to be
-	// marshalled into S3 compatible XML header.
-	listObjectsInfo, err := objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
-	if err != nil {
-		errorIf(err, "Unable to list objects.")
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-		return
+
+	// A 'start-after' not common with prefix is either before or
+	// after all the keys with prefix.
+	var listObjectsInfo ListObjectsInfo
+	if !hasPrefix(marker, prefix) && marker < prefix {
+		marker = ""
+	}
+	if marker == "" || hasPrefix(marker, prefix) {
+		// Inititate a list objects operation based on the input params.
+		// On success would return back ListObjectsInfo object to be
+		// marshalled into S3 compatible XML header.
+		var err error
+		listObjectsInfo, err = objectAPI.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+		if err != nil {
+			errorIf(err, "Unable to list objects.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
 	}
 
-	response := generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, fetchOwner, maxKeys, listObjectsInfo)
+	response := generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter, encodingType, fetchOwner, maxKeys, listObjectsInfo)
+	setListObjectsFields(response.Contents, fields)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodeResponse(response))
@@ -122,7 +150,7 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:ListBucket", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -130,6 +158,12 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	// Extract all the litsObjectsV1 query params to their native values.
 	prefix, marker, delimiter, maxKeys, _ := getListObjectsV1Args(r.URL.Query())
 
+	fields, s3Error := getListObjectsFields(r.URL.Query())
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Validate all the query params before beginning to serve the request.
 	if s3Error := validateListObjectsArgs(prefix, marker, delimiter, maxKeys); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
@@ -146,6 +180,7 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 		return
 	}
 	response := generateListObjectsV1Response(bucket, prefix, marker, delimiter, maxKeys, listObjectsInfo)
+	setListObjectsFields(response.Contents, fields)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodeResponse(response))
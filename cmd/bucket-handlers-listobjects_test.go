@@ -0,0 +1,231 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling ListObjectsV2 HTTP handler tests for both XL multiple disks and single node setup.
+func TestListObjectsV2Handler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testListObjectsV2Handler, []string{"ListObjectsV2"})
+}
+
+func testListObjectsV2Handler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objects := []string{"a b", "a+b", "dir/x", "dir/y", "z"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucketName, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	listObjectsV2 := func(params map[string]string) (*httptest.ResponseRecorder, ListObjectsV2Response) {
+		queryValue := url.Values{}
+		queryValue.Set("list-type", "2")
+		for key, value := range params {
+			queryValue.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", makeTestTargetURL("", bucketName, "", queryValue),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for ListObjectsV2Handler: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		var response ListObjectsV2Response
+		if rec.Code == http.StatusOK {
+			if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("%s: %s", instanceType, err)
+			}
+		}
+		return rec, response
+	}
+	keysOf := func(response ListObjectsV2Response) (keys []string) {
+		for _, object := range response.Contents {
+			keys = append(keys, object.Key)
+		}
+		for _, prefix := range response.CommonPrefixes {
+			keys = append(keys, prefix.Prefix)
+		}
+		return keys
+	}
+
+	// Listing page by page with continuation tokens.
+	var keys []string
+	token := ""
+	for {
+		params := map[string]string{"max-keys": "2"}
+		if token != "" {
+			params["continuation-token"] = token
+		}
+		rec, response := listObjectsV2(params)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		if response.ContinuationToken != token || response.KeyCount != len(response.Contents) {
+			t.Fatalf("%s: Unexpected response %+v", instanceType, response)
+		}
+		keys = append(keys, keysOf(response)...)
+		if !response.IsTruncated {
+			if response.NextContinuationToken != "" {
+				t.Fatalf("%s: Expected no continuation token on last page, got %s", instanceType, response.NextContinuationToken)
+			}
+			break
+		}
+		token = response.NextContinuationToken
+	}
+	if !reflect.DeepEqual(keys, objects) {
+		t.Fatalf("%s: Expected %v, got %v", instanceType, objects, keys)
+	}
+
+	testCases := []struct {
+		params             map[string]string
+		expectedRespStatus int
+		expectedKeys       []string
+		expectedOwner      bool
+		expectedElements   []string
+		omittedElements    []string
+	}{
+		// Test case - 1.
+		// Names are URL encoded on request.
+		{
+			params:             map[string]string{"encoding-type": "url", "delimiter": "/"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"a%20b", "a%2Bb", "z", "dir/"},
+		},
+		// Test case - 2.
+		// Unsupported encoding type.
+		{
+			params:             map[string]string{"encoding-type": "gzip"},
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 3.
+		// Malformed continuation token.
+		{
+			params:             map[string]string{"continuation-token": "!!"},
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 4.
+		// Listing starts after start-after.
+		{
+			params:             map[string]string{"start-after": "a+b"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"dir/x", "dir/y", "z"},
+		},
+		// Test case - 5.
+		// Continuation token takes precedence over start-after.
+		{
+			params:             map[string]string{"start-after": "a+b", "continuation-token": encodeContinuationToken("dir/y")},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"z"},
+		},
+		// Test case - 6.
+		// start-after before all the keys with prefix.
+		{
+			params:             map[string]string{"prefix": "dir/", "start-after": "b"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"dir/x", "dir/y"},
+		},
+		// Test case - 7.
+		// start-after after all the keys with prefix.
+		{
+			params:             map[string]string{"prefix": "dir/", "start-after": "e"},
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 8.
+		// Owner is only returned with fetch-owner.
+		{
+			params:             map[string]string{"prefix": "z", "fetch-owner": "true"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"z"},
+			expectedOwner:      true,
+		},
+		// Test case - 9.
+		// Only keys are returned.
+		{
+			params:             map[string]string{"prefix": "dir/", "x-minio-fields": "key"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"dir/x", "dir/y"},
+			expectedElements:   []string{"<Key>dir/x</Key>"},
+			omittedElements:    []string{"<Size>", "<LastModified>", "<ETag>", "<StorageClass>"},
+		},
+		// Test case - 10.
+		// Only keys and sizes are returned, even along with fetch-owner.
+		{
+			params:             map[string]string{"prefix": "dir/", "x-minio-fields": "key,size", "fetch-owner": "true"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"dir/x", "dir/y"},
+			expectedElements:   []string{"<Key>dir/x</Key><Size>1</Size>"},
+			omittedElements:    []string{"<LastModified>", "<ETag>", "<StorageClass>", "<Owner>"},
+		},
+		// Test case - 11.
+		// All fields are returned by default.
+		{
+			params:             map[string]string{"prefix": "z"},
+			expectedRespStatus: http.StatusOK,
+			expectedKeys:       []string{"z"},
+			expectedElements:   []string{"<Key>z</Key>", "<Size>1</Size>", "<LastModified>", "<ETag>", "<StorageClass>"},
+		},
+		// Test case - 12.
+		// Unsupported fields.
+		{
+			params:             map[string]string{"x-minio-fields": "key,etag"},
+			expectedRespStatus: http.StatusBadRequest,
+		},
+	}
+
+	for i, testCase := range testCases {
+		rec, response := listObjectsV2(testCase.params)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		if keys := keysOf(response); !reflect.DeepEqual(keys, testCase.expectedKeys) {
+			t.Errorf("Test %d: %s: Expected keys %v, got %v", i+1, instanceType, testCase.expectedKeys, keys)
+		}
+		if response.EncodingType != testCase.params["encoding-type"] {
+			t.Errorf("Test %d: %s: Expected encoding type %s, got %s", i+1, instanceType, testCase.params["encoding-type"], response.EncodingType)
+		}
+		for _, object := range response.Contents {
+			if (object.Owner != nil) != testCase.expectedOwner {
+				t.Errorf("Test %d: %s: Expected owner %v, got %v", i+1, instanceType, testCase.expectedOwner, object.Owner)
+			}
+		}
+		for _, element := range testCase.expectedElements {
+			if !strings.Contains(rec.Body.String(), element) {
+				t.Errorf("Test %d: %s: Expected %s in response", i+1, instanceType, element)
+			}
+		}
+		for _, element := range testCase.omittedElements {
+			if strings.Contains(rec.Body.String(), element) {
+				t.Errorf("Test %d: %s: Expected no %s in response", i+1, instanceType, element)
+			}
+		}
+	}
+}
@@ -33,9 +33,9 @@ import (
 
 // http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
 // Enforces bucket policies for a bucket for a given tatusaction.
-func enforceBucketPolicy(bucket, action, resource, referer string, queryParams url.Values) (s3Error APIErrorCode) {
+func enforceBucketPolicy(objAPI ObjectLayer, bucket, action, resource, referer, sourceIP string, queryParams url.Values) (s3Error APIErrorCode) {
 	// Verify if bucket actually exists
-	if err := checkBucketExist(bucket, newObjectLayerFn()); err != nil {
+	if err := checkBucketExist(bucket, objAPI); err != nil {
 		err = errorCause(err)
 		switch err.(type) {
 		case BucketNameInvalid:
@@ -69,10 +69,30 @@ func enforceBucketPolicy(bucket, action, resource, referer string, queryParams u
 		conditionKeyMap[queryParam] = set.CreateStringSet(queryParams.Get(queryParam))
 	}
 
-	// Add request referer to conditionKeyMap if present.
+	// Existing object tags are only taken from the object itself,
+	// and only fetched when the policy has conditions on them.
+	for conditionKey := range conditionKeyMap {
+		if strings.HasPrefix(conditionKey, existingObjectTagKeyPrefix) {
+			delete(conditionKeyMap, conditionKey)
+		}
+	}
+	object := strings.TrimPrefix(strings.TrimPrefix(resource, "/"+bucket), "/")
+	if object != "" && hasObjectTagConditions(policy) {
+		for conditionKey, tagValue := range getObjectTagConditions(bucket, object, objAPI) {
+			conditionKeyMap[conditionKey] = tagValue
+		}
+	}
+
+	// Add request referer and source IP to conditionKeyMap if
+	// present, they are never taken from query params.
+	delete(conditionKeyMap, "referer")
 	if referer != "" {
 		conditionKeyMap["referer"] = set.CreateStringSet(referer)
 	}
+	delete(conditionKeyMap, "SourceIp")
+	if sourceIP != "" {
+		conditionKeyMap["SourceIp"] = set.CreateStringSet(sourceIP)
+	}
 
 	// Validate action, resource and conditions with current policy statements.
 	if !bucketPolicyEvalStatements(action, arn, conditionKeyMap, policy.Statements) {
@@ -106,10 +126,10 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketLocation", globalMinioDefaultRegion)
-	if s3Error == ErrInvalidRegion {
+	s3Error := api.checkRequestAuthType(r, bucket, "s3:GetBucketLocation", globalMinioDefaultRegion)
+	if isErrInvalidRegion(s3Error) {
 		// Clients like boto3 send getBucketLocation() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "s3:GetBucketLocation", serverConfig.GetRegion())
+		s3Error = api.checkRequestAuthType(r, "", "s3:GetBucketLocation", api.Config().GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
@@ -117,7 +137,7 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 	}
 
 	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+		reqErrorIf(r, err, "Unable to fetch bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -125,7 +145,7 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 	// Generate response.
 	encodedSuccessResponse := encodeResponse(LocationResponse{})
 	// Get current region.
-	region := serverConfig.GetRegion()
+	region := api.Config().GetRegion()
 	if region != globalMinioDefaultRegion {
 		encodedSuccessResponse = encodeResponse(LocationResponse{
 			Location: region,
@@ -154,7 +174,7 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucketMultipartUploads", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:ListBucketMultipartUploads", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -174,7 +194,7 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 
 	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
-		errorIf(err, "Unable to list multipart uploads.")
+		reqErrorIf(r, err, "Unable to list multipart uploads.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -197,11 +217,12 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// ListBuckets does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
-	if s3Error == ErrInvalidRegion {
+	// ListBuckets does not have any bucket, its action is only
+	// checked against session policies of temporary credentials.
+	s3Error := api.checkRequestAuthType(r, "", "s3:ListAllMyBuckets", globalMinioDefaultRegion)
+	if isErrInvalidRegion(s3Error) {
 		// Clients like boto3 send listBuckets() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = api.checkRequestAuthType(r, "", "s3:ListAllMyBuckets", api.Config().GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
@@ -210,7 +231,7 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 	// Invoke the list buckets.
 	bucketsInfo, err := objectAPI.ListBuckets()
 	if err != nil {
-		errorIf(err, "Unable to list buckets.")
+		reqErrorIf(r, err, "Unable to list buckets.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -234,7 +255,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:DeleteObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -258,7 +279,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	// Read incoming body XML bytes.
 	if _, err := io.ReadFull(r.Body, deleteXMLBytes); err != nil {
-		errorIf(err, "Unable to read HTTP body.")
+		reqErrorIf(r, err, "Unable to read HTTP body.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -266,7 +287,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	// Unmarshal list of keys to be deleted.
 	deleteObjects := &DeleteObjectsRequest{}
 	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
-		errorIf(err, "Unable to unmarshal delete objects request XML.")
+		reqErrorIf(r, err, "Unable to unmarshal delete objects request XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -278,10 +299,20 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	for index, object := range deleteObjects.Objects {
 		wg.Add(1)
 		go func(i int, obj ObjectIdentifier) {
-			objectLock := globalNSMutex.NewNSLock(bucket, obj.ObjectName)
-			objectLock.Lock()
-			defer objectLock.Unlock()
 			defer wg.Done()
+			objectLock := api.NSMutex().NewRequestNSLock(r, bucket, obj.ObjectName)
+			if err := objectLock.GetLock(getRequestDeadline(r)); err != nil {
+				dErrs[i] = err
+				return
+			}
+			defer objectLock.Unlock()
+
+			// Objects of WORM buckets and locked objects can't be
+			// deleted.
+			if wErr := checkObjectOverwrite(objectAPI, bucket, obj.ObjectName, isGovernanceBypassed(r)); wErr != nil {
+				dErrs[i] = wErr
+				return
+			}
 
 			dErr := objectAPI.DeleteObject(bucket, obj.ObjectName)
 			if dErr != nil {
@@ -307,7 +338,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			deletedObjects = append(deletedObjects, object)
 			continue
 		}
-		errorIf(err, "Unable to delete object. %s", object.ObjectName)
+		reqErrorIf(r, err, "Unable to delete object. %s", object.ObjectName)
 		// Error during delete should be collected separately.
 		deleteErrors = append(deleteErrors, DeleteError{
 			Code:    errorCodeResponse[toAPIErrorCode(err)].Code,
@@ -357,10 +388,10 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	// PutBucket does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
-	if s3Error == ErrInvalidRegion {
+	s3Error := api.checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
+	if isErrInvalidRegion(s3Error) {
 		// Clients like boto3 send putBucket() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = api.checkRequestAuthType(r, "", "", api.Config().GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
@@ -384,18 +415,32 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.Lock()
+	bucketLock := api.NSMutex().NewRequestNSLock(r, bucket, "")
+	if err := bucketLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer bucketLock.Unlock()
 
 	// Proceed to creating a bucket.
 	err := objectAPI.MakeBucket(bucket)
 	if err != nil {
-		errorIf(err, "Unable to create a bucket.")
+		reqErrorIf(r, err, "Unable to create a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Object lock is enabled along with creating the bucket, without
+	// default retention.
+	if strings.EqualFold(r.Header.Get(amzBucketObjectLockEnabled), "true") {
+		config := objectLockConfig{ObjectLockEnabled: objectLockEnabled}
+		if err = persistBucketObjectLock(bucket, config, objectAPI); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		notifyBucketConfigChange(bucket, bucketObjectLockConfig)
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set("Location", getLocation(r))
 
@@ -424,7 +469,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// be loaded in memory, the remaining being put in temporary files.
 	reader, err := r.MultipartReader()
 	if err != nil {
-		errorIf(err, "Unable to initialize multipart reader.")
+		reqErrorIf(r, err, "Unable to initialize multipart reader.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
@@ -432,7 +477,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// Read multipart data and save in memory and in the disk if needed
 	form, err := reader.ReadForm(maxFormMemory)
 	if err != nil {
-		errorIf(err, "Unable to initialize multipart reader.")
+		reqErrorIf(r, err, "Unable to initialize multipart reader.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
@@ -443,7 +488,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// Extract all form fields
 	fileBody, fileName, fileSize, formValues, err := extractPostPolicyFormValues(form)
 	if err != nil {
-		errorIf(err, "Unable to parse form values.")
+		reqErrorIf(r, err, "Unable to parse form values.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
@@ -508,13 +553,13 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	lengthRange := postPolicyForm.Conditions.ContentLengthRange
 	if lengthRange.Valid {
 		if fileSize < lengthRange.Min {
-			errorIf(err, "Unable to create object.")
+			reqErrorIf(r, err, "Unable to create object.")
 			writeErrorResponse(w, toAPIErrorCode(errDataTooSmall), r.URL)
 			return
 		}
 
 		if fileSize > lengthRange.Max || isMaxObjectSize(fileSize) {
-			errorIf(err, "Unable to create object.")
+			reqErrorIf(r, err, "Unable to create object.")
 			writeErrorResponse(w, toAPIErrorCode(errDataTooLarge), r.URL)
 			return
 		}
@@ -522,15 +567,29 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 
 	// Extract metadata to be saved from received Form.
 	metadata := extractMetadataFromForm(formValues)
+	applyDefaultMetadata(objectAPI, bucket, object, metadata)
+	if s3Error := setObjectLockMetadata(objectAPI, bucket, formValues, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 	sha256sum := ""
 
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.Unlock()
 
+	// Objects of WORM buckets can't be overwritten.
+	if err = checkWormOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	objInfo, err := objectAPI.PutObject(bucket, object, fileSize, fileBody, metadata, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object.")
+		reqErrorIf(r, err, "Unable to create object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -595,17 +654,20 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:ListBucket", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:ListBucket", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponseHeadersOnly(w, s3Error)
 		return
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.RLock()
+	bucketLock := api.NSMutex().NewRequestNSLock(r, bucket, "")
+	if err := bucketLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
+		return
+	}
 	defer bucketLock.RUnlock()
 
 	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+		reqErrorIf(r, err, "Unable to fetch bucket info.")
 		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
 		return
 	}
@@ -622,7 +684,7 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	}
 
 	// DeleteBucket does not have any bucket action.
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -630,13 +692,16 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	bucketLock := globalNSMutex.NewNSLock(bucket, "")
-	bucketLock.Lock()
+	bucketLock := api.NSMutex().NewRequestNSLock(r, bucket, "")
+	if err := bucketLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer bucketLock.Unlock()
 
 	// Attempt to delete bucket.
 	if err := objectAPI.DeleteBucket(bucket); err != nil {
-		errorIf(err, "Unable to delete a bucket.")
+		reqErrorIf(r, err, "Unable to delete a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -650,6 +715,40 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	// Delete listener config, if present - ignore any errors.
 	_ = removeListenerConfig(bucket, objectAPI)
 
+	// Delete usage alert config, if present - ignore any errors.
+	_ = removeBucketUsageAlert(bucket, objectAPI)
+
+	// Delete bucket tagging, if present - ignore any errors.
+	_ = removeBucketTagging(bucket, objectAPI)
+
+	// Delete share links, if present - ignore any errors.
+	_ = removeBucketShareLinks(bucket, objectAPI)
+
+	// Delete bucket lifecycle, if present - ignore any errors.
+	_ = removeBucketLifecycle(bucket, objectAPI)
+
+	// Delete default metadata, if present - ignore any errors.
+	_ = removeBucketDefaultMetadata(bucket, objectAPI)
+
+	// Delete website configuration, if present - ignore any errors.
+	_ = removeBucketWebsite(bucket, objectAPI)
+
+	// Delete analytics configurations, if present - ignore any errors.
+	_ = removeAllBucketAnalytics(bucket, objectAPI)
+
+	// Delete WORM config, if present - ignore any errors. A bucket
+	// with objects can't be deleted, so an empty WORM bucket may go.
+	_ = removeBucketWorm(bucket, objectAPI)
+
+	// Delete bucket key info, if present - ignore any errors.
+	_ = removeBucketKeyInfo(bucket, objectAPI)
+
+	// Delete object lock configuration, if present - ignore any errors.
+	_ = removeBucketObjectLock(bucket, objectAPI)
+
+	// Forget cached configurations of the bucket on all servers.
+	notifyBucketConfigChange(bucket, "")
+
 	// Write success response.
 	writeSuccessNoContent(w)
 }
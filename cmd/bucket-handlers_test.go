@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -171,6 +172,81 @@ func testGetBucketLocationHandler(obj ObjectLayer, instanceType, bucketName stri
 	ExecObjectLayerAPINilTest(t, nilBucket, "", instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling GetBucketLocation HTTP handler tests with a configured region.
+func TestGetBucketLocationRegion(t *testing.T) {
+	ExecObjectLayerAPITest(t, testGetBucketLocationRegion, []string{"GetBucketLocation"})
+}
+
+func testGetBucketLocationRegion(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer serverConfig.SetRegion(serverConfig.GetRegion())
+
+	testCases := []struct {
+		// region requests are signed with.
+		signRegion string
+		// region server is configured with.
+		serverRegion string
+		// expected output.
+		expectedRespStatus int
+		expectedLocation   string
+		expectedCode       string
+		expectedRegion     string
+	}{
+		// Test case - 1.
+		// Default region is returned as an empty location.
+		{globalMinioDefaultRegion, globalMinioDefaultRegion, http.StatusOK, "", "", ""},
+		// Test case - 2.
+		// Configured region is returned as location.
+		{"us-west-2", "us-west-2", http.StatusOK, "us-west-2", "", ""},
+		// Test case - 3.
+		// Request signed with default region is accepted for location.
+		{globalMinioDefaultRegion, "us-west-2", http.StatusOK, "us-west-2", "", ""},
+		// Test case - 4.
+		// Request signed with another region is rejected with the expected region.
+		{"eu-central-1", "us-west-2", http.StatusBadRequest, "", "AuthorizationHeaderMalformed", "us-west-2"},
+	}
+
+	for i, testCase := range testCases {
+		serverConfig.SetRegion(testCase.signRegion)
+		req, err := newTestSignedRequestV4("GET", getBucketLocationURL("", bucketName), 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for GetBucketLocationHandler: <ERROR> %v", i+1, instanceType, err)
+		}
+		serverConfig.SetRegion(testCase.serverRegion)
+
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.expectedRespStatus == http.StatusOK {
+			locationResponse := LocationResponse{}
+			if err = xml.Unmarshal(rec.Body.Bytes(), &locationResponse); err != nil {
+				t.Fatalf("Test %d: %s: Unable to unmarshal response body %s", i+1, instanceType, rec.Body.String())
+			}
+			if locationResponse.Location != testCase.expectedLocation {
+				t.Errorf("Test %d: %s: Expected location `%s`, but instead found `%s`", i+1, instanceType, testCase.expectedLocation, locationResponse.Location)
+			}
+			continue
+		}
+
+		errorResponse := APIErrorResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+			t.Fatalf("Test %d: %s: Unable to unmarshal response body %s", i+1, instanceType, rec.Body.String())
+		}
+		if errorResponse.Code != testCase.expectedCode {
+			t.Errorf("Test %d: %s: Expected the error code to be `%s`, but instead found `%s`", i+1, instanceType, testCase.expectedCode, errorResponse.Code)
+		}
+		if errorResponse.Region != testCase.expectedRegion {
+			t.Errorf("Test %d: %s: Expected the error region to be `%s`, but instead found `%s`", i+1, instanceType, testCase.expectedRegion, errorResponse.Region)
+		}
+		if !strings.Contains(errorResponse.Message, "expecting '"+testCase.expectedRegion+"'") {
+			t.Errorf("Test %d: %s: Expected the error message to contain the expected region, found `%s`", i+1, instanceType, errorResponse.Message)
+		}
+	}
+}
+
 // Wrapper for calling HeadBucket HTTP handler tests for both XL multiple disks and single node setup.
 func TestHeadBucketHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testHeadBucketHandler, []string{"HeadBucket"})
@@ -0,0 +1,153 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path"
+	"time"
+)
+
+// Bucket key config file name, saved alongside other bucket configs
+// in minioMetaBucket.
+const bucketKeyConfig = "encryption-key.json"
+
+// errBucketKeyMismatch - returned when the key of a bucket was derived
+// from another KMS master key than the configured one.
+var errBucketKeyMismatch = errors.New("Bucket key was derived from another KMS master key")
+
+// bucketKeyInfo - key ID of the master key the bucket key is derived
+// from and the current version of the bucket key, recorded in bucket
+// metadata.
+type bucketKeyInfo struct {
+	KeyID   string    `json:"keyId"`
+	Version int       `json:"version"`
+	Rotated time.Time `json:"rotated"`
+}
+
+// loadBucketKeyInfo - loads key info of a bucket, nil if the bucket
+// has no key yet.
+func loadBucketKeyInfo(bucket string, objAPI ObjectLayer) (*bucketKeyInfo, error) {
+	kPath := path.Join(bucketConfigPrefix, bucket, bucketKeyConfig)
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, kPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, nil
+		}
+		errorIf(err, "Unable to load key info for bucket %s", bucket)
+		return nil, err
+	}
+
+	info := &bucketKeyInfo{}
+	if err = json.Unmarshal(buffer.Bytes(), info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// persistBucketKeyInfo - saves key info of a bucket.
+func persistBucketKeyInfo(bucket string, info bucketKeyInfo, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	kPath := path.Join(bucketConfigPrefix, bucket, bucketKeyConfig)
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, kPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write key info for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketKeyInfo - removes key info of a bucket, only used
+// during DeleteBucket.
+func removeBucketKeyInfo(bucket string, objAPI ObjectLayer) error {
+	kPath := path.Join(bucketConfigPrefix, bucket, bucketKeyConfig)
+
+	// Acquire a write lock on key info before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, kPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, kPath)
+	objLock.Unlock()
+	return err
+}
+
+// getBucketKeyInfo - returns key info of a bucket. A bucket without
+// recorded key info uses the first version of its key, nothing is
+// written until the key is rotated.
+func getBucketKeyInfo(bucket string, objAPI ObjectLayer) (bucketKeyInfo, error) {
+	if globalKMS == nil {
+		return bucketKeyInfo{}, errKMSNotConfigured
+	}
+
+	kPath := path.Join(bucketConfigPrefix, bucket, bucketKeyConfig)
+
+	// Acquire a read lock on key info before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, kPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	return readBucketKeyInfo(bucket, objAPI)
+}
+
+// readBucketKeyInfo - returns recorded key info of a bucket or the
+// first version of its key, the caller holds the key info lock.
+func readBucketKeyInfo(bucket string, objAPI ObjectLayer) (bucketKeyInfo, error) {
+	info, err := loadBucketKeyInfo(bucket, objAPI)
+	if err != nil {
+		return bucketKeyInfo{}, err
+	}
+	if info == nil {
+		return bucketKeyInfo{KeyID: globalKMS.keyID, Version: 1}, nil
+	}
+	if info.KeyID != globalKMS.keyID {
+		return bucketKeyInfo{}, errBucketKeyMismatch
+	}
+	return *info, nil
+}
+
+// rotateBucketKey - moves the key of a bucket to its next version and
+// records it in bucket metadata.
+func rotateBucketKey(bucket string, objAPI ObjectLayer) (bucketKeyInfo, error) {
+	if globalKMS == nil {
+		return bucketKeyInfo{}, errKMSNotConfigured
+	}
+
+	kPath := path.Join(bucketConfigPrefix, bucket, bucketKeyConfig)
+
+	// Acquire a write lock on key info before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, kPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	info, err := readBucketKeyInfo(bucket, objAPI)
+	if err != nil {
+		return bucketKeyInfo{}, err
+	}
+	info.Version++
+	info.Rotated = UTCNow()
+	if err = persistBucketKeyInfo(bucket, info, objAPI); err != nil {
+		return bucketKeyInfo{}, err
+	}
+	return info, nil
+}
@@ -32,6 +32,15 @@ type BucketMetaState interface {
 
 	// Sends event
 	SendEvent(args *EventArgs) error
+
+	// Sends quota usage
+	SendQuotaUsage(args *QuotaUsagePeerArgs) error
+
+	// Sends used nonce of a presigned URL
+	SendPresignNonce(args *PresignNoncePeerArgs) error
+
+	// Updates cached bucket configuration
+	UpdateBucketConfig(args *BucketConfigPeerArgs) error
 }
 
 // BucketUpdater - Interface implementer calls one of BucketMetaState's methods.
@@ -100,6 +109,27 @@ func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
 	return globalEventNotifier.SendListenerEvent(args.Arn, args.Event)
 }
 
+// localBucketMetaState.SendQuotaUsage - saves quota usage of a peer in
+// `globalQuotaTracker`
+func (lc *localBucketMetaState) SendQuotaUsage(args *QuotaUsagePeerArgs) error {
+	globalQuotaTracker.setRemoteUsage(args.NodeID, args.Snapshot)
+	return nil
+}
+
+// localBucketMetaState.SendPresignNonce - records a nonce of a
+// presigned URL used through a peer in `globalPresignNonces`
+func (lc *localBucketMetaState) SendPresignNonce(args *PresignNoncePeerArgs) error {
+	globalPresignNonces.record(args.Key, args.Expiry, UTCNow())
+	return nil
+}
+
+// localBucketMetaState.UpdateBucketConfig - forgets a changed bucket
+// configuration cached in `globalBucketConfigCache`
+func (lc *localBucketMetaState) UpdateBucketConfig(args *BucketConfigPeerArgs) error {
+	globalBucketConfigCache.remove(args.Bucket, args.Config)
+	return nil
+}
+
 // Type that implements BucketMetaState for remote node.
 type remoteBucketMetaState struct {
 	*AuthRPCClient
@@ -132,3 +162,24 @@ func (rc *remoteBucketMetaState) SendEvent(args *EventArgs) error {
 	reply := AuthRPCReply{}
 	return rc.Call("S3.Event", args, &reply)
 }
+
+// remoteBucketMetaState.SendQuotaUsage - sends quota usage of this node
+// to remote peer via RPC call.
+func (rc *remoteBucketMetaState) SendQuotaUsage(args *QuotaUsagePeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.QuotaUsagePeer", args, &reply)
+}
+
+// remoteBucketMetaState.SendPresignNonce - sends nonce of a presigned
+// URL used through this node to remote peer via RPC call.
+func (rc *remoteBucketMetaState) SendPresignNonce(args *PresignNoncePeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.PresignNoncePeer", args, &reply)
+}
+
+// remoteBucketMetaState.UpdateBucketConfig - sends bucket configuration
+// change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) UpdateBucketConfig(args *BucketConfigPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.BucketConfigPeer", args, &reply)
+}
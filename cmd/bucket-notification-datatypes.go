@@ -49,7 +49,8 @@ type queueConfig struct {
 	QueueARN string `xml:"Queue"`
 }
 
-// Topic SNS configuration, this is a compliance field not used by minio yet.
+// Topic SNS configuration, topics publish to the targets configured
+// for queues of the same id and type. Also used by live listeners.
 type topicConfig struct {
 	ServiceConfig
 	TopicARN string `xml:"Topic" json:"Topic"`
@@ -65,6 +66,7 @@ type lambdaConfig struct {
 // notification configuration of buckets.
 type notificationConfig struct {
 	XMLName       xml.Name       `xml:"NotificationConfiguration"`
+	TopicConfigs  []topicConfig  `xml:"TopicConfiguration"`
 	QueueConfigs  []queueConfig  `xml:"QueueConfiguration"`
 	LambdaConfigs []lambdaConfig `xml:"CloudFunctionConfiguration"`
 }
@@ -98,6 +100,8 @@ const (
 	ObjectAccessedGet
 	// ObjectAccessedHead is s3:ObjectAccessed:Head
 	ObjectAccessedHead
+	// BucketUsageThresholdExceeded is s3:BucketUsage:ThresholdExceeded
+	BucketUsageThresholdExceeded
 )
 
 // Stringer interface for event name.
@@ -117,6 +121,8 @@ func (eventName EventName) String() string {
 		return "s3:ObjectAccessed:Get"
 	case ObjectAccessedHead:
 		return "s3:ObjectAccessed:Head"
+	case BucketUsageThresholdExceeded:
+		return "s3:BucketUsage:ThresholdExceeded"
 	default:
 		return "s3:Unknown"
 	}
@@ -158,6 +164,10 @@ const (
 const (
 	// Response element origin endpoint key.
 	responseOriginEndpointKey = "x-minio-origin-endpoint"
+
+	// Response element key of the hybrid logical clock of events,
+	// comparable across all servers.
+	responseEventClockKey = "x-minio-event-clock"
 )
 
 // Notification event server specific metadata.
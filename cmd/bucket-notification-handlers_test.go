@@ -286,9 +286,15 @@ func testRemoveNotificationConfig(obj ObjectLayer, instanceType, bucketName stri
 	// get random bucket name.
 	randBucket := bucketName
 
+	// Topics publish to configured targets, enable one.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	serverConfig.Notify.SetWebhookByID("1474332374", webhookNotify{Enable: true, Endpoint: ts.URL})
+	defer serverConfig.Notify.SetWebhookByID("1474332374", webhookNotify{})
+
 	sampleNotificationBytes := []byte("<NotificationConfiguration><TopicConfiguration>" +
 		"<Event>s3:ObjectCreated:*</Event><Event>s3:ObjectRemoved:*</Event><Filter>" +
-		"<S3Key></S3Key></Filter><Id></Id><Topic>arn:minio:sns:us-east-1:1474332374:listen</Topic>" +
+		"<S3Key></S3Key></Filter><Id></Id><Topic>arn:minio:sns:us-east-1:1474332374:webhook</Topic>" +
 		"</TopicConfiguration></NotificationConfiguration>")
 
 	// Set sample bucket notification on randBucket.
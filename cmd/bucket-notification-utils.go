@@ -36,6 +36,9 @@ var suppportedEventTypes = map[string]struct{}{
 	"s3:ObjectAccessed:Get":   {},
 	"s3:ObjectAccessed:Head":  {},
 	"s3:ObjectAccessed:*":     {},
+	// Bucket usage event types.
+	"s3:BucketUsage:*":                 {},
+	"s3:BucketUsage:ThresholdExceeded": {},
 }
 
 // checkEvent - checks if an event is supported.
@@ -133,6 +136,18 @@ func checkQueueARN(queueARN string) APIErrorCode {
 	return checkARN(queueARN, minioSqs)
 }
 
+// checkTopicARN - check if the topic arn is valid.
+func checkTopicARN(topicARN string) APIErrorCode {
+	return checkARN(topicARN, minioTopic)
+}
+
+// topicToQueueARN - returns the ARN of the target a topic publishes
+// to, e.g. "arn:minio:sns:us-east-1:1:webhook" publishes to
+// "arn:minio:sqs:us-east-1:1:webhook".
+func topicToQueueARN(topicARN string) string {
+	return minioSqs + strings.TrimPrefix(topicARN, minioTopic)
+}
+
 // Validates account id for input queue ARN.
 func isValidQueueID(queueARN string) bool {
 	// Unmarshals QueueARN into structured object.
@@ -196,6 +211,62 @@ func checkQueueConfig(qConfig queueConfig) APIErrorCode {
 	return ErrNone
 }
 
+// Check - validates topic configuration and returns error if any.
+func checkTopicConfig(tConfig topicConfig) APIErrorCode {
+	// Check topic arn is valid.
+	if s3Error := checkTopicARN(tConfig.TopicARN); s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Validate if a target is configured for the topic, listeners
+	// are only registered through ListenBucketNotification.
+	if !isValidQueueID(topicToQueueARN(tConfig.TopicARN)) {
+		return ErrARNNotification
+	}
+
+	// Check if valid events are set in topic config.
+	if s3Error := checkEvents(tConfig.Events); s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Check if valid filters are set in topic config.
+	if s3Error := checkFilterRules(tConfig.Filter.Key.FilterRules); s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Success.
+	return ErrNone
+}
+
+// Validates all incoming topic configs, if validation fails bucket
+// notifications are not enabled.
+func validateTopicConfigs(topicConfigs []topicConfig) APIErrorCode {
+	for _, tConfig := range topicConfigs {
+		if s3Error := checkTopicConfig(tConfig); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+	// Success.
+	return ErrNone
+}
+
+// Check all the topic configs for any duplicates.
+func checkDuplicateTopicConfigs(configs []topicConfig) APIErrorCode {
+	topicConfigARNS := set.NewStringSet()
+
+	// Navigate through each configs and count the entries.
+	for _, config := range configs {
+		topicConfigARNS.Add(config.TopicARN)
+	}
+
+	if len(topicConfigARNS) != len(configs) {
+		return ErrOverlappingConfigs
+	}
+
+	// Success.
+	return ErrNone
+}
+
 // Validates all incoming queue configs, checkQueueConfig validates if the
 // input fields for each queues is not malformed and has valid configuration
 // information.  If validation fails bucket notifications are not enabled.
@@ -242,6 +313,18 @@ func validateNotificationConfig(nConfig notificationConfig) APIErrorCode {
 		}
 	}
 
+	// Validate all topic configs.
+	if s3Error := validateTopicConfigs(nConfig.TopicConfigs); s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Check for duplicate topic configs.
+	if len(nConfig.TopicConfigs) > 1 {
+		if s3Error := checkDuplicateTopicConfigs(nConfig.TopicConfigs); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+
 	// Add validation for other configurations.
 	return ErrNone
 }
@@ -362,3 +362,101 @@ func TestUnmarshalSQSARN(t *testing.T) {
 	}
 
 }
+
+// Tests validating topic configs.
+func TestCheckTopicConfig(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("unable initialize config file, %s", err)
+	}
+	defer removeAll(rootPath)
+
+	serverConfig.Notify.SetWebhookByID("1", webhookNotify{Enable: true, Endpoint: "http://127.0.0.1:80"})
+	defer serverConfig.Notify.SetWebhookByID("1", webhookNotify{})
+
+	testCases := []struct {
+		tConfig topicConfig
+		errCode APIErrorCode
+	}{
+		// Valid topic publishing to an enabled webhook.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}},
+				TopicARN:      "arn:minio:sns:us-east-1:1:webhook",
+			},
+			errCode: ErrNone,
+		},
+		// Invalid queue arn used as topic arn.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}},
+				TopicARN:      "arn:minio:sqs:us-east-1:1:webhook",
+			},
+			errCode: ErrARNNotification,
+		},
+		// Invalid region 'us-west-1' in topic arn.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}},
+				TopicARN:      "arn:minio:sns:us-west-1:1:webhook",
+			},
+			errCode: ErrRegionNotification,
+		},
+		// Invalid topic without a configured target.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}},
+				TopicARN:      "arn:minio:sns:us-east-1:1:redis",
+			},
+			errCode: ErrARNNotification,
+		},
+		// Invalid listener topic, listeners are not configured this way.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:*"}},
+				TopicARN:      "arn:minio:sns:us-east-1:1:listen",
+			},
+			errCode: ErrARNNotification,
+		},
+		// Invalid event name.
+		{
+			tConfig: topicConfig{
+				ServiceConfig: ServiceConfig{Events: []string{"s3:ObjectCreated:Invalid"}},
+				TopicARN:      "arn:minio:sns:us-east-1:1:webhook",
+			},
+			errCode: ErrEventNotification,
+		},
+	}
+
+	for i, testCase := range testCases {
+		errCode := checkTopicConfig(testCase.tConfig)
+		if testCase.errCode != errCode {
+			t.Errorf("Test %d: Expected \"%d\", got \"%d\"", i+1, testCase.errCode, errCode)
+		}
+	}
+
+	// Duplicate topic configs are rejected.
+	dupConfigs := []topicConfig{testCases[0].tConfig, testCases[0].tConfig}
+	if errCode := checkDuplicateTopicConfigs(dupConfigs); errCode != ErrOverlappingConfigs {
+		t.Errorf("Expected \"%d\", got \"%d\"", ErrOverlappingConfigs, errCode)
+	}
+	if errCode := validateNotificationConfig(notificationConfig{TopicConfigs: dupConfigs}); errCode != ErrOverlappingConfigs {
+		t.Errorf("Expected \"%d\", got \"%d\"", ErrOverlappingConfigs, errCode)
+	}
+}
+
+// Tests topic arn to target arn mapping.
+func TestTopicToQueueARN(t *testing.T) {
+	testCases := []struct {
+		topicARN string
+		queueARN string
+	}{
+		{"arn:minio:sns:us-east-1:1:webhook", "arn:minio:sqs:us-east-1:1:webhook"},
+		{"arn:minio:sns:us-east-1:2:amqp", "arn:minio:sqs:us-east-1:2:amqp"},
+	}
+	for i, testCase := range testCases {
+		if queueARN := topicToQueueARN(testCase.topicARN); queueARN != testCase.queueARN {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.queueARN, queueARN)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Default maximum size of bucket policies, like on AWS S3.
+	defaultMaxAccessPolicySize = 20 * humanize.KiByte
+
+	// Limit of the configurable maximum size of bucket policies,
+	// all policies are held in memory.
+	globalMaxAccessPolicySize = 1 * humanize.MiByte
+)
+
+// policyConfig - bucket policy limits.
+type policyConfig struct {
+	// Maximum size of bucket policies in bytes, defaults to 20 KiB
+	// when 0.
+	MaxSize int64 `json:"maxSize"`
+}
+
+// Validate - validates bucket policy config.
+func (p policyConfig) Validate() error {
+	if p.MaxSize < 0 || p.MaxSize > globalMaxAccessPolicySize {
+		return fmt.Errorf("Invalid policy maxSize value ‘%d’, must be between 0 and %d", p.MaxSize, int64(globalMaxAccessPolicySize))
+	}
+	return nil
+}
+
+// getMaxSize - returns the configured maximum size of bucket
+// policies.
+func (p policyConfig) getMaxSize() int64 {
+	if p.MaxSize == 0 {
+		return defaultMaxAccessPolicySize
+	}
+	return p.MaxSize
+}
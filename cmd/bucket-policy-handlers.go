@@ -20,19 +20,16 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"runtime"
 	"strings"
 
-	humanize "github.com/dustin/go-humanize"
 	mux "github.com/gorilla/mux"
 	"github.com/minio/minio-go/pkg/set"
 	"github.com/minio/minio/pkg/wildcard"
 )
 
-// maximum supported access policy size.
-const maxAccessPolicySize = 20 * humanize.KiByte
-
 // Verify if a given action is valid for the url path based on the
 // existing bucket access policy.
 func bucketPolicyEvalStatements(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) bool {
@@ -77,8 +74,33 @@ func actionMatch(pattern, action string) bool {
 	return wildcard.MatchSimple(pattern, action)
 }
 
-func refererMatch(pattern, referer string) bool {
-	return wildcard.MatchSimple(pattern, referer)
+// stringLikeMatch - returns true if any of the values matches any of
+// the wild card patterns.
+func stringLikeMatch(patterns, values set.StringSet) bool {
+	for value := range values {
+		if !patterns.FuncMatch(wildcard.MatchSimple, value).IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// ipAddressMatch - returns true if any of the IP addresses is within
+// any of the ranges in CIDR notation.
+func ipAddressMatch(cidrs, ips set.StringSet) bool {
+	for cidr := range cidrs {
+		// Ranges are validated while parsing the policy.
+		ipNet, err := parseSourceIPCondition(cidr)
+		if err != nil {
+			continue
+		}
+		for ip := range ips {
+			if parsedIP := net.ParseIP(ip); parsedIP != nil && ipNet.Contains(parsedIP) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // Verify if given resource matches with policy statement.
@@ -97,16 +119,25 @@ func bucketPolicyConditionMatch(conditions map[string]set.StringSet, statement p
 	// - StringNotEquals
 	// - StringLike
 	// - StringNotLike
+	// - IpAddress
+	// - NotIpAddress
 	//
 	// Supported applicable condition keys for each conditions.
 	// - s3:prefix
 	// - s3:max-keys
 	// - s3:aws-Referer
+	// - aws:SourceIp
+	// - s3:ExistingObjectTag/<key>
 
 	// The following loop evaluates the logical AND of all the
 	// conditions in the statement. Note: we can break out of the
 	// loop if and only if a condition evaluates to false.
 	for condition, conditionKeyVal := range statement.Conditions {
+		// Conditions on existing object tags apply to all condition types.
+		if !objectTagConditionMatch(condition, conditionKeyVal, conditions) {
+			return false
+		}
+
 		prefixConditon := conditionKeyVal["s3:prefix"]
 		maxKeyCondition := conditionKeyVal["s3:max-keys"]
 		if condition == "StringEquals" {
@@ -128,36 +159,36 @@ func bucketPolicyConditionMatch(conditions map[string]set.StringSet, statement p
 				return false
 			}
 		} else if condition == "StringLike" {
-			awsReferers := conditionKeyVal["aws:Referer"]
-			// Skip empty condition, it is trivially satisfied.
-			if awsReferers.IsEmpty() {
-				continue
-			}
-			// wildcard match of referer in statement was not empty.
-			// StringLike has a match, i.e, condition evaluates to true.
-			refererFound := false
-			for referer := range conditions["referer"] {
-				if !awsReferers.FuncMatch(refererMatch, referer).IsEmpty() {
-					refererFound = true
-					break
-				}
+			// Skip empty conditions, they are trivially satisfied.
+			// Otherwise, wildcard match of request prefix or referer
+			// must be found for the condition to evaluate to true.
+			if !prefixConditon.IsEmpty() && !stringLikeMatch(prefixConditon, conditions["prefix"]) {
+				return false
 			}
-			// No matching referer found, so the condition is false.
-			if !refererFound {
+			awsReferers := conditionKeyVal["aws:Referer"]
+			if !awsReferers.IsEmpty() && !stringLikeMatch(awsReferers, conditions["referer"]) {
 				return false
 			}
 		} else if condition == "StringNotLike" {
+			// Wildcard match of request prefix or referer evaluates
+			// the condition to false.
+			if !prefixConditon.IsEmpty() && stringLikeMatch(prefixConditon, conditions["prefix"]) {
+				return false
+			}
 			awsReferers := conditionKeyVal["aws:Referer"]
-			// Skip empty condition, it is trivially satisfied.
-			if awsReferers.IsEmpty() {
-				continue
+			if !awsReferers.IsEmpty() && stringLikeMatch(awsReferers, conditions["referer"]) {
+				return false
 			}
-			// wildcard match of referer in statement was not empty.
-			// StringNotLike has a match, i.e, condition evaluates to false.
-			for referer := range conditions["referer"] {
-				if !awsReferers.FuncMatch(refererMatch, referer).IsEmpty() {
-					return false
-				}
+		} else if condition == "IpAddress" {
+			// Request source IP must be within one of the ranges.
+			if !ipAddressMatch(conditionKeyVal["aws:SourceIp"], conditions["SourceIp"]) {
+				return false
+			}
+		} else if condition == "NotIpAddress" {
+			// Request source IP within one of the ranges evaluates
+			// the condition to false.
+			if ipAddressMatch(conditionKeyVal["aws:SourceIp"], conditions["SourceIp"]) {
+				return false
 			}
 		}
 	}
@@ -176,7 +207,7 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -187,7 +218,7 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 	// Before proceeding validate if bucket exists.
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		reqErrorIf(r, err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -199,17 +230,18 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	// If Content-Length is greater than maximum allowed policy size.
-	if r.ContentLength > maxAccessPolicySize {
+	maxPolicySize := api.Config().GetPolicy().getMaxSize()
+	if r.ContentLength > maxPolicySize {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
 		return
 	}
 
-	// Read access policy up to maxAccessPolicySize.
+	// Read access policy up to maxPolicySize.
 	// http://docs.aws.amazon.com/AmazonS3/latest/dev/access-policy-language-overview.html
-	// bucket policies are limited to 20KB in size, using a limit reader.
-	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAccessPolicySize))
+	// bucket policies are limited to 20KB in size by default, using a limit reader.
+	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPolicySize))
 	if err != nil {
-		errorIf(err, "Unable to read from client.")
+		reqErrorIf(r, err, "Unable to read from client.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -235,7 +267,7 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -246,7 +278,7 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 	// Before proceeding validate if bucket exists.
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		reqErrorIf(r, err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -278,7 +310,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -289,7 +321,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 	// Before proceeding validate if bucket exists.
 	_, err := objAPI.GetBucketInfo(bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		reqErrorIf(r, err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -297,7 +329,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 	// Read bucket access policy.
 	policy, err := readBucketPolicy(bucket, objAPI)
 	if err != nil {
-		errorIf(err, "Unable to read bucket policy.")
+		reqErrorIf(r, err, "Unable to read bucket policy.")
 		switch err.(type) {
 		case BucketPolicyNotFound:
 			writeErrorResponse(w, ErrNoSuchBucketPolicy, r.URL)
@@ -288,7 +288,7 @@ func testPutBucketPolicyHandler(obj ObjectLayer, instanceType, bucketName string
 			bucketName:         bucketName,
 			bucketPolicyReader: bytes.NewReader([]byte(fmt.Sprintf(bucketPolicyTemplate, bucketName, bucketName))),
 
-			policyLen:          maxAccessPolicySize + 1,
+			policyLen:          defaultMaxAccessPolicySize + 1,
 			accessKey:          credentials.AccessKey,
 			secretKey:          credentials.SecretKey,
 			expectedRespStatus: http.StatusBadRequest,
@@ -970,6 +970,90 @@ func TestBucketPolicyConditionMatch(t *testing.T) {
 			condition:          getInnerMap("referer", "http://somethingelse.com/"),
 			expectedMatch:      true,
 		},
+		// Test case - 13.
+		// StringLike condition on prefix matches.
+		{
+			statementCondition: getStatementWithCondition("StringLike", "s3:prefix", "home/*"),
+			condition:          getInnerMap("prefix", "home/minio/"),
+			expectedMatch:      true,
+		},
+		// Test case - 14.
+		// StringLike condition on prefix doesn't match.
+		{
+			statementCondition: getStatementWithCondition("StringLike", "s3:prefix", "home/*"),
+			condition:          getInnerMap("prefix", "private/"),
+			expectedMatch:      false,
+		},
+		// Test case - 15.
+		// StringLike condition on prefix without prefix in request.
+		{
+			statementCondition: getStatementWithCondition("StringLike", "s3:prefix", "home/*"),
+			condition:          map[string]set.StringSet{},
+			expectedMatch:      false,
+		},
+		// Test case - 16.
+		// StringNotLike condition on prefix evaluates to false.
+		{
+			statementCondition: getStatementWithCondition("StringNotLike", "s3:prefix", "private/*"),
+			condition:          getInnerMap("prefix", "private/keys/"),
+			expectedMatch:      false,
+		},
+		// Test case - 17.
+		// StringNotLike condition on prefix evaluates to true.
+		{
+			statementCondition: getStatementWithCondition("StringNotLike", "s3:prefix", "private/*"),
+			condition:          getInnerMap("prefix", "home/"),
+			expectedMatch:      true,
+		},
+		// Test case - 18.
+		// IpAddress condition matches a range.
+		{
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "192.168.1.0/24"),
+			condition:          getInnerMap("SourceIp", "192.168.1.10"),
+			expectedMatch:      true,
+		},
+		// Test case - 19.
+		// IpAddress condition matches a single address.
+		{
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "192.168.1.10"),
+			condition:          getInnerMap("SourceIp", "192.168.1.10"),
+			expectedMatch:      true,
+		},
+		// Test case - 20.
+		// IpAddress condition doesn't match.
+		{
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "192.168.1.0/24"),
+			condition:          getInnerMap("SourceIp", "10.0.0.1"),
+			expectedMatch:      false,
+		},
+		// Test case - 21.
+		// IpAddress condition without source IP.
+		{
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "192.168.1.0/24"),
+			condition:          map[string]set.StringSet{},
+			expectedMatch:      false,
+		},
+		// Test case - 22.
+		// IpAddress condition matches an IPv6 range.
+		{
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "2001:db8::/32"),
+			condition:          getInnerMap("SourceIp", "2001:db8::1"),
+			expectedMatch:      true,
+		},
+		// Test case - 23.
+		// NotIpAddress condition evaluates to false.
+		{
+			statementCondition: getStatementWithCondition("NotIpAddress", "aws:SourceIp", "192.168.1.0/24"),
+			condition:          getInnerMap("SourceIp", "192.168.1.10"),
+			expectedMatch:      false,
+		},
+		// Test case - 24.
+		// NotIpAddress condition evaluates to true.
+		{
+			statementCondition: getStatementWithCondition("NotIpAddress", "aws:SourceIp", "192.168.1.0/24"),
+			condition:          getInnerMap("SourceIp", "10.0.0.1"),
+			expectedMatch:      true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -983,3 +1067,52 @@ func TestBucketPolicyConditionMatch(t *testing.T) {
 		})
 	}
 }
+
+// Tests anonymous requests are allowed only from source IPs
+// permitted by the bucket policy.
+func TestBucketPolicySourceIPCondition(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketPolicySourceIPCondition, []string{"GetObject"})
+}
+
+func testBucketPolicySourceIPCondition(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "object"
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	statement := getReadOnlyObjectStatement(bucketName, "")
+	statement.Conditions = map[string]map[string]set.StringSet{
+		"IpAddress": {"aws:SourceIp": set.CreateStringSet("192.168.1.0/24")},
+	}
+	policy := &bucketPolicy{Version: "1.0", Statements: []policyStatement{statement}}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	testCases := []struct {
+		remoteAddr         string
+		query              string
+		expectedRespStatus int
+	}{
+		// Source IP within the range.
+		{"192.168.1.10:9000", "", http.StatusOK},
+		// Source IP outside the range.
+		{"10.0.0.1:9000", "", http.StatusForbidden},
+		// Source IP can't be set through query params.
+		{"10.0.0.1:9000", "?SourceIp=192.168.1.10", http.StatusForbidden},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, objectName)+testCase.query, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.RemoteAddr = testCase.remoteAddr
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+}
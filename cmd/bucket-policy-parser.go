@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"sort"
 	"strings"
 
@@ -34,17 +35,27 @@ var conditionKeyActionMap = map[string]set.StringSet{
 	"s3:max-keys": set.CreateStringSet("s3:ListBucket"),
 }
 
+// objectTagConditionActions - actions compatible with
+// "s3:ExistingObjectTag/<key>" condition keys.
+var objectTagConditionActions = set.CreateStringSet("s3:GetObject",
+	"s3:GetObjectTagging", "s3:PutObjectTagging", "s3:DeleteObjectTagging")
+
 // supportedActionMap - lists all the actions supported by minio.
 var supportedActionMap = set.CreateStringSet("*", "s3:*", "s3:GetObject",
 	"s3:ListBucket", "s3:PutObject", "s3:GetBucketLocation", "s3:DeleteObject",
-	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts")
+	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts",
+	"s3:GetObjectTagging", "s3:PutObjectTagging", "s3:DeleteObjectTagging")
 
 // supported Conditions type.
-var supportedConditionsType = set.CreateStringSet("StringEquals", "StringNotEquals", "StringLike", "StringNotLike")
+var supportedConditionsType = set.CreateStringSet("StringEquals", "StringNotEquals", "StringLike", "StringNotLike",
+	"IpAddress", "NotIpAddress")
 
 // Validate s3:prefix, s3:max-keys are present if not
 // supported keys for the conditions.
-var supportedConditionsKey = set.CreateStringSet("s3:prefix", "s3:max-keys", "aws:Referer")
+var supportedConditionsKey = set.CreateStringSet("s3:prefix", "s3:max-keys", "aws:Referer", "aws:SourceIp")
+
+// IP address conditions, only applicable to "aws:SourceIp" key.
+var ipConditionsType = set.CreateStringSet("IpAddress", "NotIpAddress")
 
 // supportedEffectMap - supported effects.
 var supportedEffectMap = set.CreateStringSet("Allow", "Deny")
@@ -196,12 +207,28 @@ func isValidConditions(actions set.StringSet, conditions map[string]map[string]s
 			return err
 		}
 		for key, value := range conditions[conditionType] {
-			if !supportedConditionsKey.Contains(key) {
+			if !supportedConditionsKey.Contains(key) && !isObjectTagConditionKey(key) {
 				err = fmt.Errorf("Unsupported condition key '%s', please validate your policy document", conditionType)
 				return err
 			}
 
+			// IP address conditions and "aws:SourceIp" key go together.
+			if ipConditionsType.Contains(conditionType) != (key == "aws:SourceIp") {
+				err = fmt.Errorf("Unsupported condition key '%s' for condition type '%s', please validate your policy document", key, conditionType)
+				return err
+			}
+			if key == "aws:SourceIp" {
+				for cidr := range value {
+					if _, err = parseSourceIPCondition(cidr); err != nil {
+						return err
+					}
+				}
+			}
+
 			compatibleActions := conditionKeyActionMap[key]
+			if isObjectTagConditionKey(key) {
+				compatibleActions = objectTagConditionActions
+			}
 			if !compatibleActions.IsEmpty() &&
 				compatibleActions.Intersection(actions).IsEmpty() {
 				err = fmt.Errorf("Unsupported condition key %s for the given actions %s, "+
@@ -220,6 +247,26 @@ func isValidConditions(actions set.StringSet, conditions map[string]map[string]s
 	return nil
 }
 
+// parseSourceIPCondition - parses "aws:SourceIp" condition value in
+// CIDR notation, a single IP address is treated as /32 or /128.
+func parseSourceIPCondition(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("Invalid IP address '%s' for 'aws:SourceIp', please validate your policy document", value)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CIDR '%s' for 'aws:SourceIp', please validate your policy document", value)
+	}
+	return ipNet, nil
+}
+
 // List of actions for which prefixes are not allowed.
 var invalidPrefixActions = set.StringSet{
 	"s3:GetBucketLocation":          {},
@@ -292,6 +339,31 @@ func checkBucketPolicyResources(bucket string, bucketPolicy *bucketPolicy) APIEr
 	return ErrNone
 }
 
+// validate - returns all the errors found in the statement entries.
+func (statement policyStatement) validate() (errs []error) {
+	// Statement effect should be valid.
+	if err := isValidEffect(statement.Effect); err != nil {
+		errs = append(errs, err)
+	}
+	// Statement principal should be supported format.
+	if err := isValidPrincipals(statement.Principal); err != nil {
+		errs = append(errs, err)
+	}
+	// Statement actions should be valid.
+	if err := isValidActions(statement.Actions); err != nil {
+		errs = append(errs, err)
+	}
+	// Statement resources should be valid.
+	if err := isValidResources(statement.Resources); err != nil {
+		errs = append(errs, err)
+	}
+	// Statement conditions should be valid.
+	if err := isValidConditions(statement.Actions, statement.Conditions); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 // parseBucketPolicy - parses and validates if bucket policy is of
 // proper JSON and follows allowed restrictions with policy standards.
 func parseBucketPolicy(bucketPolicyReader io.Reader, policy *bucketPolicy) (err error) {
@@ -315,25 +387,8 @@ func parseBucketPolicy(bucketPolicyReader io.Reader, policy *bucketPolicy) (err
 
 	// Loop through all policy statements and validate entries.
 	for _, statement := range policy.Statements {
-		// Statement effect should be valid.
-		if err := isValidEffect(statement.Effect); err != nil {
-			return err
-		}
-		// Statement principal should be supported format.
-		if err := isValidPrincipals(statement.Principal); err != nil {
-			return err
-		}
-		// Statement actions should be valid.
-		if err := isValidActions(statement.Actions); err != nil {
-			return err
-		}
-		// Statement resources should be valid.
-		if err := isValidResources(statement.Resources); err != nil {
-			return err
-		}
-		// Statement conditions should be valid.
-		if err := isValidConditions(statement.Actions, statement.Conditions); err != nil {
-			return err
+		if errs := statement.validate(); len(errs) > 0 {
+			return errs[0]
 		}
 	}
 
@@ -431,6 +431,12 @@ func TestIsValidConditions(t *testing.T) {
 		generateConditions("StringEquals", "s3:max-keys", "100"),
 		generateConditions("StringNotEquals", "s3:prefix", "Asia/"),
 		generateConditions("StringNotEquals", "s3:max-keys", "100"),
+		generateConditions("IpAddress", "aws:SourceIp", "192.168.1.0/24"),
+		generateConditions("NotIpAddress", "aws:SourceIp", "10.0.0.1"),
+		generateConditions("IpAddress", "aws:SourceIp", "192.168.1.0/33"),
+		generateConditions("IpAddress", "s3:prefix", "Asia/"),
+		generateConditions("StringEquals", "aws:SourceIp", "10.0.0.1"),
+		generateConditions("StringLike", "s3:prefix", "Asia/*"),
 	}
 
 	getObjectActionSet := set.CreateStringSet("s3:GetObject")
@@ -483,6 +489,26 @@ func TestIsValidConditions(t *testing.T) {
 		{roBucketActionSet, testConditions[11], nil, true},
 		// Test case - 13.
 		{getObjectActionSet, testConditions[11], maxKeysConditionErr, false},
+		// Test case - 14.
+		// IP address conditions on source IP.
+		{getObjectActionSet, testConditions[14], nil, true},
+		// Test case - 15.
+		{getObjectActionSet, testConditions[15], nil, true},
+		// Test case - 16.
+		// Invalid CIDR.
+		{getObjectActionSet, testConditions[16], fmt.Errorf("Invalid CIDR '192.168.1.0/33' for 'aws:SourceIp', " +
+			"please validate your policy document"), false},
+		// Test case - 17.
+		// IP address condition on a key other than source IP.
+		{roBucketActionSet, testConditions[17], fmt.Errorf("Unsupported condition key 's3:prefix' for condition type " +
+			"'IpAddress', please validate your policy document"), false},
+		// Test case - 18.
+		// String condition on source IP.
+		{getObjectActionSet, testConditions[18], fmt.Errorf("Unsupported condition key 'aws:SourceIp' for condition type " +
+			"'StringEquals', please validate your policy document"), false},
+		// Test case - 19.
+		// StringLike condition on prefix.
+		{roBucketActionSet, testConditions[19], nil, true},
 	}
 	for i, testCase := range testCases {
 		actualErr := isValidConditions(testCase.inputActions, testCase.inputCondition)
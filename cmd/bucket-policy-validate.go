@@ -0,0 +1,171 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PolicyValidationError - error found in a bucket policy, along
+// with the position in the policy document it relates to.
+type PolicyValidationError struct {
+	// Line and column, starting at 1, of the syntax error or of the
+	// statement the error is found in.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Index of the statement starting at 1, 0 when the error is not
+	// specific to a statement.
+	Statement int    `json:"statement,omitempty"`
+	Message   string `json:"message"`
+}
+
+// PolicyValidationResult - response of the policy validation API.
+type PolicyValidationResult struct {
+	Valid  bool                    `json:"valid"`
+	Errors []PolicyValidationError `json:"errors,omitempty"`
+}
+
+// offsetToPosition - returns line and column, starting at 1, of the
+// byte at offset in data.
+func offsetToPosition(data []byte, offset int64) (line, column int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - (bytes.LastIndex(before, []byte("\n")) + 1) + 1
+	return line, column
+}
+
+// policyStatementOffsets - returns the offsets of the elements of the
+// top level "Statement" array of a policy document, the document is
+// expected to be valid JSON.
+func policyStatementOffsets(data []byte) (offsets []int64) {
+	depth := 0
+	inString, escaped := false, false
+	stringStart := 0
+	lastString, key := "", ""
+	inStatements := false
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				lastString = string(data[stringStart:i])
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+			stringStart = i + 1
+		case ':':
+			if depth == 1 {
+				key = lastString
+			}
+		case ',':
+			if depth == 1 {
+				key = ""
+			}
+		case 'n':
+			// A null statement decodes to an empty one.
+			if inStatements && depth == 2 {
+				offsets = append(offsets, int64(i))
+			}
+		case '{':
+			if inStatements && depth == 2 {
+				offsets = append(offsets, int64(i))
+			}
+			depth++
+		case '[':
+			// Policy keys are matched case insensitively on decoding.
+			if depth == 1 && strings.EqualFold(key, "Statement") {
+				inStatements = true
+			}
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 1 {
+				inStatements = false
+			}
+		}
+	}
+	return offsets
+}
+
+// validateBucketPolicy - validates a bucket policy document against
+// the supported grammar and limits, reporting all the errors found
+// instead of the first one like parseBucketPolicy.
+func validateBucketPolicy(data []byte, maxSize int64) PolicyValidationResult {
+	var errs []PolicyValidationError
+	addError := func(offset int64, statement int, message string) {
+		line, column := offsetToPosition(data, offset)
+		errs = append(errs, PolicyValidationError{line, column, statement, message})
+	}
+
+	// Oversized policies may be truncated, they are not parsed.
+	if int64(len(data)) > maxSize {
+		addError(maxSize, 0, fmt.Sprintf("Policy size exceeds the maximum size %d", maxSize))
+		return PolicyValidationResult{Errors: errs}
+	}
+
+	// Decode the same way as parseBucketPolicy.
+	var policy bucketPolicy
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&policy); err != nil {
+		// Offsets of JSON errors are past the byte the error is
+		// found at.
+		switch jerr := err.(type) {
+		case *json.SyntaxError:
+			addError(jerr.Offset-1, 0, jerr.Error())
+		case *json.UnmarshalTypeError:
+			addError(jerr.Offset-1, 0, jerr.Error())
+		default:
+			addError(0, 0, err.Error())
+		}
+		return PolicyValidationResult{Errors: errs}
+	}
+
+	if len(policy.Version) == 0 {
+		addError(0, 0, "Policy version cannot be empty")
+	}
+	if len(policy.Statements) == 0 {
+		addError(0, 0, "Policy statement cannot be empty")
+	}
+
+	offsets := policyStatementOffsets(data)
+	for i, statement := range policy.Statements {
+		var offset int64
+		if i < len(offsets) {
+			offset = offsets[i]
+		}
+		for _, err := range statement.validate() {
+			addError(offset, i+1, err.Error())
+		}
+	}
+
+	return PolicyValidationResult{Valid: len(errs) == 0, Errors: errs}
+}
@@ -0,0 +1,72 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests validation of bucket policies and positions of the errors.
+func TestValidateBucketPolicy(t *testing.T) {
+	validStatement := `{"Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}`
+	testCases := []struct {
+		policy         string
+		maxSize        int64
+		expectedErrors []PolicyValidationError
+	}{
+		// Test 1: valid policy.
+		{`{"Version": "2012-10-17", "Statement": [` + validStatement + `]}`, 1024, nil},
+		// Test 2: syntax error.
+		{"{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [\n  }", 1024, []PolicyValidationError{
+			{Line: 4, Column: 3, Message: "invalid character '}' looking for beginning of value"},
+		}},
+		// Test 3: policy larger than the maximum size.
+		{`{"Version": "2012-10-17", "Statement": [` + validStatement + `]}`, 10, []PolicyValidationError{
+			{Line: 1, Column: 11, Message: "Policy size exceeds the maximum size 10"},
+		}},
+		// Test 4: empty version and statements.
+		{`{"Statement": []}`, 1024, []PolicyValidationError{
+			{Line: 1, Column: 1, Message: "Policy version cannot be empty"},
+			{Line: 1, Column: 1, Message: "Policy statement cannot be empty"},
+		}},
+		// Test 5: all the errors of all the statements are reported
+		// at the position of their statement.
+		{"{\n\"Version\": \"2012-10-17\",\n\"Statement\": [\n  " + validStatement + ",\n  " +
+			`{"Effect": "Permit", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["mybucket"]}` + "\n]}", 1024, []PolicyValidationError{
+			{Line: 5, Column: 3, Statement: 2, Message: "Unsupported Effect found: ‘Permit’, please validate your policy document"},
+			{Line: 5, Column: 3, Statement: 2, Message: "Unsupported resource style found: ‘mybucket’, please validate your policy document"},
+		}},
+		// Test 6: statements key is matched case insensitively, nested
+		// arrays and strings with brackets do not shift positions.
+		{`{"statement": [{"Sid": "[{", "Effect": "Allow", "Principal": {"AWS": ["*"]}, "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}, null], "Version": "2012-10-17"}`, 1024, []PolicyValidationError{
+			{Line: 1, Column: 148, Statement: 2, Message: "Policy effect cannot be empty"},
+			{Line: 1, Column: 148, Statement: 2, Message: "Principal cannot be empty"},
+			{Line: 1, Column: 148, Statement: 2, Message: "Action list cannot be empty"},
+			{Line: 1, Column: 148, Statement: 2, Message: "Resource list cannot be empty"},
+		}},
+	}
+	for i, testCase := range testCases {
+		result := validateBucketPolicy([]byte(testCase.policy), testCase.maxSize)
+		if result.Valid != (len(testCase.expectedErrors) == 0) {
+			t.Errorf("Test %d: Expected valid to be %v, got %v", i+1, len(testCase.expectedErrors) == 0, result.Valid)
+		}
+		if !reflect.DeepEqual(result.Errors, testCase.expectedErrors) {
+			t.Errorf("Test %d: Expected errors %v, got %v", i+1, testCase.expectedErrors, result.Errors)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket usage alert config file name, saved alongside
+	// other bucket configs in minioMetaBucket.
+	bucketUsageAlertConfig = "usage-alert.json"
+
+	// Default interval between two usage crawls.
+	defaultUsageCrawlInterval = 1 * time.Hour
+)
+
+// Internal error used to signal usage alert is not set.
+var errNoSuchUsageAlert = errors.New("The specified bucket does not have a usage alert")
+
+// bucketUsageAlert - usage thresholds configured on a bucket, a
+// threshold with zero value is disabled.
+type bucketUsageAlert struct {
+	MaxSize    int64 `json:"maxSize"`
+	MaxObjects int64 `json:"maxObjects"`
+}
+
+// isValid - returns true if at least one threshold is set and
+// none of them are negative.
+func (a bucketUsageAlert) isValid() bool {
+	if a.MaxSize < 0 || a.MaxObjects < 0 {
+		return false
+	}
+	return a.MaxSize > 0 || a.MaxObjects > 0
+}
+
+// bucketUsage - total size and number of objects in a bucket.
+type bucketUsage struct {
+	Size    int64 `json:"size"`
+	Objects int64 `json:"objects"`
+}
+
+// isExceeded - returns true if usage crosses any of the thresholds.
+func (a bucketUsageAlert) isExceeded(usage bucketUsage) bool {
+	if a.MaxSize > 0 && usage.Size > a.MaxSize {
+		return true
+	}
+	return a.MaxObjects > 0 && usage.Objects > a.MaxObjects
+}
+
+// loadBucketUsageAlert - loads usage alert config of a bucket, returns
+// errNoSuchUsageAlert if none is configured.
+func loadBucketUsageAlert(bucket string, objAPI ObjectLayer) (*bucketUsageAlert, error) {
+	uaPath := path.Join(bucketConfigPrefix, bucket, bucketUsageAlertConfig)
+
+	// Acquire a read lock on usage alert config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, uaPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, uaPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchUsageAlert
+		}
+		errorIf(err, "Unable to load usage alert for bucket %s", bucket)
+		return nil, err
+	}
+
+	alert := &bucketUsageAlert{}
+	if err = json.Unmarshal(buffer.Bytes(), alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// persistBucketUsageAlert - saves usage alert config of a bucket.
+func persistBucketUsageAlert(bucket string, alert bucketUsageAlert, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	uaPath := path.Join(bucketConfigPrefix, bucket, bucketUsageAlertConfig)
+
+	// Acquire a write lock on usage alert config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, uaPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, uaPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write usage alert for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketUsageAlert - removes usage alert config of a bucket,
+// only used during DeleteBucket.
+func removeBucketUsageAlert(bucket string, objAPI ObjectLayer) error {
+	uaPath := path.Join(bucketConfigPrefix, bucket, bucketUsageAlertConfig)
+
+	// Acquire a write lock on usage alert config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, uaPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, uaPath)
+	objLock.Unlock()
+	return err
+}
+
+// getBucketUsage - walks all the objects in a bucket and returns its
+// total size and number of objects.
+func getBucketUsage(bucket string, objAPI ObjectLayer) (usage bucketUsage, err error) {
+	marker := ""
+	for {
+		var lo ListObjectsInfo
+		lo, err = objAPI.ListObjects(bucket, "", marker, "", maxObjectList)
+		if err != nil {
+			return bucketUsage{}, err
+		}
+		for _, obj := range lo.Objects {
+			usage.Size += obj.Size
+			usage.Objects++
+		}
+		if !lo.IsTruncated {
+			return usage, nil
+		}
+		marker = lo.NextMarker
+	}
+}
+
+// usageCrawler periodically computes usage of buckets with usage
+// alerts configured and notifies when a threshold is crossed.
+type usageCrawler struct {
+	objAPI   func() ObjectLayer
+	interval time.Duration
+
+	// Buckets whose usage is currently above their thresholds,
+	// alert is sent only once per crossing.
+	mu       sync.Mutex
+	exceeded map[string]bool
+}
+
+// newUsageCrawler - initialize a new usage crawler.
+func newUsageCrawler(objAPI func() ObjectLayer, interval time.Duration) *usageCrawler {
+	return &usageCrawler{
+		objAPI:   objAPI,
+		interval: interval,
+		exceeded: make(map[string]bool),
+	}
+}
+
+// crawl - runs a single pass over all buckets.
+func (c *usageCrawler) crawl() {
+	objAPI := c.objAPI()
+	if objAPI == nil {
+		return
+	}
+
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets for usage crawl.")
+		return
+	}
+
+	for _, bucket := range buckets {
+		alert, err := loadBucketUsageAlert(bucket.Name, objAPI)
+		if err != nil {
+			c.reset(bucket.Name)
+			continue
+		}
+
+		usage, err := getBucketUsage(bucket.Name, objAPI)
+		if err != nil {
+			errorIf(err, "Unable to compute usage of bucket %s", bucket.Name)
+			continue
+		}
+
+		if !alert.isExceeded(usage) {
+			c.reset(bucket.Name)
+			continue
+		}
+
+		c.mu.Lock()
+		alreadyExceeded := c.exceeded[bucket.Name]
+		c.exceeded[bucket.Name] = true
+		c.mu.Unlock()
+
+		if !alreadyExceeded {
+			sendBucketUsageAlert(bucket.Name, *alert, usage)
+		}
+	}
+}
+
+// reset - clears the crossed state of a bucket.
+func (c *usageCrawler) reset(bucket string) {
+	c.mu.Lock()
+	delete(c.exceeded, bucket)
+	c.mu.Unlock()
+}
+
+// run - crawls at every interval until doneCh is closed.
+func (c *usageCrawler) run(doneCh <-chan struct{}) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.crawl()
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// sendBucketUsageAlert - emits usage alert event to the configured
+// notification targets of a bucket.
+func sendBucketUsageAlert(bucket string, alert bucketUsageAlert, usage bucketUsage) {
+	eventNotify(eventData{
+		Type:   BucketUsageThresholdExceeded,
+		Bucket: bucket,
+		ReqParams: map[string]string{
+			"usageSize":        strconv.FormatInt(usage.Size, 10),
+			"usageObjects":     strconv.FormatInt(usage.Objects, 10),
+			"thresholdSize":    strconv.FormatInt(alert.MaxSize, 10),
+			"thresholdObjects": strconv.FormatInt(alert.MaxObjects, 10),
+		},
+	})
+}
+
+// startUsageCrawler - starts the usage crawler in background, only
+// one server in a distributed setup crawls to avoid duplicate alerts.
+func startUsageCrawler(endpoints EndpointList, doneCh <-chan struct{}) {
+	if len(endpoints) == 0 || !endpoints[0].IsLocal {
+		return
+	}
+	crawler := newUsageCrawler(newObjectLayerFn, globalUsageCrawlInterval)
+	go crawler.run(doneCh)
+}
@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests usage alert threshold validation and crossing.
+func TestBucketUsageAlertThresholds(t *testing.T) {
+	testCases := []struct {
+		alert    bucketUsageAlert
+		usage    bucketUsage
+		valid    bool
+		exceeded bool
+	}{
+		// No thresholds set.
+		{bucketUsageAlert{}, bucketUsage{Size: 10, Objects: 1}, false, false},
+		// Negative thresholds.
+		{bucketUsageAlert{MaxSize: -1}, bucketUsage{}, false, false},
+		// Size threshold not crossed.
+		{bucketUsageAlert{MaxSize: 100}, bucketUsage{Size: 100, Objects: 10}, true, false},
+		// Size threshold crossed.
+		{bucketUsageAlert{MaxSize: 100}, bucketUsage{Size: 101, Objects: 1}, true, true},
+		// Objects threshold crossed.
+		{bucketUsageAlert{MaxObjects: 2}, bucketUsage{Size: 1, Objects: 3}, true, true},
+		// Both thresholds set, only objects crossed.
+		{bucketUsageAlert{MaxSize: 100, MaxObjects: 2}, bucketUsage{Size: 1, Objects: 3}, true, true},
+	}
+
+	for i, testCase := range testCases {
+		if valid := testCase.alert.isValid(); valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t", i+1, testCase.valid, valid)
+		}
+		if exceeded := testCase.alert.isExceeded(testCase.usage); exceeded != testCase.exceeded {
+			t.Errorf("Test %d: expected exceeded %t, got %t", i+1, testCase.exceeded, exceeded)
+		}
+	}
+}
+
+// Tests persisting, loading and removing bucket usage alerts.
+func TestBucketUsageAlertConfig(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testBucketUsageAlertConfig)
+}
+
+func testBucketUsageAlertConfig(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "usage-alert-bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	if _, err := loadBucketUsageAlert(bucket, obj); err != errNoSuchUsageAlert {
+		t.Fatalf("%s: expected %s, got %s", instanceType, errNoSuchUsageAlert, err)
+	}
+
+	alert := bucketUsageAlert{MaxSize: 1024, MaxObjects: 10}
+	if err := persistBucketUsageAlert(bucket, alert, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	savedAlert, err := loadBucketUsageAlert(bucket, obj)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if *savedAlert != alert {
+		t.Fatalf("%s: expected %v, got %v", instanceType, alert, *savedAlert)
+	}
+
+	if err = removeBucketUsageAlert(bucket, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, err = loadBucketUsageAlert(bucket, obj); err != errNoSuchUsageAlert {
+		t.Fatalf("%s: expected %s, got %s", instanceType, errNoSuchUsageAlert, err)
+	}
+}
+
+// Tests usage crawler tracks threshold crossings.
+func TestUsageCrawler(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testUsageCrawler)
+}
+
+func testUsageCrawler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "usage-crawler-bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	data := []byte("hello, world")
+	for _, object := range []string{"a", "b", "dir/c"} {
+		_, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, "")
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	usage, err := getBucketUsage(bucket, obj)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if usage.Objects != 3 || usage.Size != int64(3*len(data)) {
+		t.Fatalf("%s: unexpected usage %v", instanceType, usage)
+	}
+
+	crawler := newUsageCrawler(func() ObjectLayer { return obj }, defaultUsageCrawlInterval)
+
+	// Usage below thresholds.
+	if err = persistBucketUsageAlert(bucket, bucketUsageAlert{MaxObjects: 3}, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	crawler.crawl()
+	if crawler.exceeded[bucket] {
+		t.Fatalf("%s: bucket usage should not be exceeded", instanceType)
+	}
+
+	// Usage crosses object count threshold.
+	if err = persistBucketUsageAlert(bucket, bucketUsageAlert{MaxObjects: 2}, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	crawler.crawl()
+	if !crawler.exceeded[bucket] {
+		t.Fatalf("%s: bucket usage should be exceeded", instanceType)
+	}
+
+	// Usage back below thresholds after removing an object.
+	if err = obj.DeleteObject(bucket, "a"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	crawler.crawl()
+	if crawler.exceeded[bucket] {
+		t.Fatalf("%s: bucket usage should not be exceeded", instanceType)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests PUT, GET and DELETE bucket website.
+func TestBucketWebsiteHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketWebsiteHandlers, []string{"GetBucketWebsite", "PutBucketWebsite", "DeleteBucketWebsite"})
+}
+
+func testBucketWebsiteHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	validWebsite := `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument>` +
+		`<ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`
+	invalidWebsite := `<WebsiteConfiguration><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		body               string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+	}{
+		// No website configuration set yet.
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Index document missing.
+		{"PUT", bucketName, invalidWebsite, credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Malformed XML.
+		{"PUT", bucketName, "<WebsiteConfiguration>", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Non-existent bucket.
+		{"PUT", "non-existent-bucket", validWebsite, credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid credentials.
+		{"PUT", bucketName, validWebsite, "abcd1234", credentials.SecretKey, http.StatusForbidden},
+		// Valid website configuration.
+		{"PUT", bucketName, validWebsite, credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Remove website configuration.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Removing again is not an error.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getBucketWebsiteURL("", testCase.bucketName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" && rec.Code == http.StatusOK {
+			var website websiteConfig
+			if err = xml.Unmarshal(rec.Body.Bytes(), &website); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if website.IndexDocument == nil || website.IndexDocument.Suffix != "index.html" ||
+				website.ErrorDocument == nil || website.ErrorDocument.Key != "error.html" {
+				t.Fatalf("Test %d: %s: unexpected website configuration %+v", i+1, instanceType, website)
+			}
+		}
+	}
+}
+
+// Tests buckets are served as websites.
+func TestWebsiteHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testWebsiteHandler, []string{"PutBucketWebsite"})
+}
+
+func testWebsiteHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	for object, data := range map[string]string{
+		"index.html":      "home",
+		"docs/index.html": "docs",
+		"error.html":      "oops",
+	} {
+		if _, err := obj.PutObject(bucketName, object, int64(len(data)), bytes.NewReader([]byte(data)), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	website := `<WebsiteConfiguration>` +
+		`<IndexDocument><Suffix>index.html</Suffix></IndexDocument>` +
+		`<ErrorDocument><Key>error.html</Key></ErrorDocument>` +
+		`<RoutingRules>` +
+		`<RoutingRule><Condition><KeyPrefixEquals>old/</KeyPrefixEquals></Condition>` +
+		`<Redirect><ReplaceKeyPrefixWith>docs/</ReplaceKeyPrefixWith><HttpRedirectCode>302</HttpRedirectCode></Redirect></RoutingRule>` +
+		`<RoutingRule><Condition><KeyPrefixEquals>moved/</KeyPrefixEquals><HttpErrorCodeReturnedEquals>404</HttpErrorCodeReturnedEquals></Condition>` +
+		`<Redirect><Protocol>https</Protocol><HostName>example.com</HostName></Redirect></RoutingRule>` +
+		`</RoutingRules></WebsiteConfiguration>`
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getBucketWebsiteURL("", bucketName), int64(len(website)),
+		bytes.NewReader([]byte(website)), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected status 200 setting website, got %d", instanceType, rec.Code)
+	}
+
+	policy := &bucketPolicy{Version: "1.0", Statements: []policyStatement{getReadOnlyObjectStatement(bucketName, "")}}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	handler := configureWebsiteHandler(true)
+	testCases := []struct {
+		method           string
+		host             string
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		// Test 1: index document of the website.
+		{"GET", bucketName, "/", http.StatusOK, "home", ""},
+		// Test 2: index document of a directory.
+		{"GET", bucketName, "/docs/", http.StatusOK, "docs", ""},
+		// Test 3: directory without trailing slash.
+		{"GET", bucketName, "/docs", http.StatusFound, "", "/docs/"},
+		// Test 4: object.
+		{"GET", bucketName, "/error.html", http.StatusOK, "oops", ""},
+		// Test 5: missing object is sent the error document.
+		{"GET", bucketName, "/missing.html", http.StatusNotFound, "oops", ""},
+		// Test 6: routing rule of a prefix.
+		{"GET", bucketName, "/old/index.html", http.StatusFound, "", "http://" + bucketName + "/docs/index.html"},
+		// Test 7: routing rule of an error.
+		{"GET", bucketName, "/moved/page.html", http.StatusMovedPermanently, "", "https://example.com/moved/page.html"},
+		// Test 8: HEAD.
+		{"HEAD", bucketName, "/", http.StatusOK, "", ""},
+		// Test 9: websites are read-only.
+		{"PUT", bucketName, "/index.html", http.StatusMethodNotAllowed, "", ""},
+		// Test 10: non-existent bucket.
+		{"GET", "non-existent-bucket", "/", http.StatusNotFound, "", ""},
+		// Test 11: host not naming a bucket.
+		{"GET", "127.0.0.1:9000", "/", http.StatusNotFound, "", ""},
+	}
+	for i, testCase := range testCases {
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(testCase.method, "http://"+testCase.host+testCase.path, nil)
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Fatalf("Test %d: %s: expected status %d, got %d", i+1, instanceType, testCase.expectedStatus, rec.Code)
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: %s: expected body %q, got %q", i+1, instanceType, testCase.expectedBody, rec.Body.String())
+		}
+		if location := rec.Header().Get("Location"); location != testCase.expectedLocation {
+			t.Errorf("Test %d: %s: expected location %q, got %q", i+1, instanceType, testCase.expectedLocation, location)
+		}
+	}
+
+	// Objects not readable by anonymous clients are denied.
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "http://"+bucketName+"/", nil))
+	if rec.Code != http.StatusForbidden || !bytes.Contains(rec.Body.Bytes(), []byte("AccessDenied")) {
+		t.Fatalf("%s: expected AccessDenied page, got %d %s", instanceType, rec.Code, rec.Body.String())
+	}
+}
+
+// Tests requests to the website domain are served as websites.
+func TestWebsiteHostHandler(t *testing.T) {
+	globalWebsiteDomain = "web.example.com"
+	defer func() { globalWebsiteDomain = "" }()
+
+	// Website requests fail without object layer.
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = nil
+	globalObjLayerMutex.Unlock()
+
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := setWebsiteHostHandler(api)
+
+	testCases := []struct {
+		host           string
+		expectedStatus int
+	}{
+		// Test 1: S3 API request.
+		{"minio.example.com", http.StatusTeapot},
+		// Test 2: website request.
+		{"bucket.web.example.com", http.StatusServiceUnavailable},
+		// Test 3: the website domain itself names no bucket.
+		{"web.example.com:9000", http.StatusTeapot},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "http://"+testCase.host+"/", nil))
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Website config file name, saved alongside other bucket configs
+	// in minioMetaBucket.
+	bucketWebsiteConfig = "website.xml"
+
+	// Limits on website configuration, same as AWS S3.
+	maxWebsiteRoutingRules = 50
+	maxWebsiteBodySize     = 256 * 1024
+)
+
+var errNoSuchWebsiteConfiguration = errors.New("The specified bucket does not have a website configuration")
+
+// websiteIndexDocument - object served for requests of a directory,
+// e.g. "index.html" for "/" and "/docs/".
+type websiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// websiteErrorDocument - object served along with the status of
+// requests failing with a 4XX error.
+type websiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// websiteRedirectAllRequestsTo - host all requests are redirected to.
+type websiteRedirectAllRequestsTo struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+// websiteCondition - requests a routing rule applies to.
+type websiteCondition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// websiteRedirect - redirect of requests selected by a routing rule.
+type websiteRedirect struct {
+	HostName             string `xml:"HostName,omitempty"`
+	HTTPRedirectCode     string `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+}
+
+// websiteRoutingRule - redirects requests matching its condition.
+type websiteRoutingRule struct {
+	Condition *websiteCondition `xml:"Condition,omitempty"`
+	Redirect  websiteRedirect   `xml:"Redirect"`
+}
+
+// websiteConfig - website configuration of a bucket, compatible with
+// AWS S3 PutBucketWebsite.
+type websiteConfig struct {
+	XMLName               xml.Name                      `xml:"WebsiteConfiguration"`
+	RedirectAllRequestsTo *websiteRedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *websiteIndexDocument         `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *websiteErrorDocument         `xml:"ErrorDocument,omitempty"`
+	RoutingRules          []websiteRoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+// isValidWebsiteProtocol - returns true if protocol of a redirect is
+// empty, i.e the protocol of the request, or http or https.
+func isValidWebsiteProtocol(protocol string) bool {
+	return protocol == "" || protocol == httpScheme || protocol == httpsScheme
+}
+
+// parseBucketWebsite - parses and validates website configuration.
+func parseBucketWebsite(data []byte) (websiteConfig, APIErrorCode) {
+	var website websiteConfig
+	if err := xml.Unmarshal(data, &website); err != nil {
+		return website, ErrMalformedXML
+	}
+
+	// Redirecting all requests excludes all other settings.
+	if redirect := website.RedirectAllRequestsTo; redirect != nil {
+		if redirect.HostName == "" || !isValidWebsiteProtocol(redirect.Protocol) {
+			return website, ErrInvalidWebsiteConfiguration
+		}
+		if website.IndexDocument != nil || website.ErrorDocument != nil || len(website.RoutingRules) > 0 {
+			return website, ErrInvalidWebsiteConfiguration
+		}
+		return website, ErrNone
+	}
+
+	if website.IndexDocument == nil || website.IndexDocument.Suffix == "" ||
+		strings.Contains(website.IndexDocument.Suffix, slashSeparator) {
+		return website, ErrInvalidWebsiteConfiguration
+	}
+	if website.ErrorDocument != nil && website.ErrorDocument.Key == "" {
+		return website, ErrInvalidWebsiteConfiguration
+	}
+	if len(website.RoutingRules) > maxWebsiteRoutingRules {
+		return website, ErrInvalidWebsiteConfiguration
+	}
+	for _, rule := range website.RoutingRules {
+		if condition := rule.Condition; condition != nil && condition.HTTPErrorCodeReturnedEquals != "" {
+			code, err := strconv.Atoi(condition.HTTPErrorCodeReturnedEquals)
+			if err != nil || code < 400 || code > 599 {
+				return website, ErrInvalidWebsiteConfiguration
+			}
+		}
+		redirect := rule.Redirect
+		if redirect == (websiteRedirect{}) || !isValidWebsiteProtocol(redirect.Protocol) {
+			return website, ErrInvalidWebsiteConfiguration
+		}
+		if redirect.ReplaceKeyPrefixWith != "" && redirect.ReplaceKeyWith != "" {
+			return website, ErrInvalidWebsiteConfiguration
+		}
+		if redirect.HTTPRedirectCode != "" {
+			code, err := strconv.Atoi(redirect.HTTPRedirectCode)
+			if err != nil || code < 300 || code > 399 {
+				return website, ErrInvalidWebsiteConfiguration
+			}
+		}
+	}
+	return website, ErrNone
+}
+
+// matches - returns true if the rule applies to a request of key,
+// before the object is looked up if status is 0 or after the lookup
+// failed with status otherwise.
+func (rule websiteRoutingRule) matches(key string, status int) bool {
+	condition := rule.Condition
+	if condition == nil {
+		return status == 0
+	}
+	if !hasPrefix(key, condition.KeyPrefixEquals) {
+		return false
+	}
+	if condition.HTTPErrorCodeReturnedEquals == "" {
+		return status == 0
+	}
+	return condition.HTTPErrorCodeReturnedEquals == strconv.Itoa(status)
+}
+
+// getRedirect - returns the location and status code requests of key
+// are redirected with.
+func (rule websiteRoutingRule) getRedirect(r *http.Request, key string) (location string, code int) {
+	redirect := rule.Redirect
+
+	scheme := redirect.Protocol
+	if scheme == "" {
+		scheme = httpScheme
+		if r.TLS != nil {
+			scheme = httpsScheme
+		}
+	}
+	host := redirect.HostName
+	if host == "" {
+		host = r.Host
+	}
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != "":
+		var prefix string
+		if rule.Condition != nil {
+			prefix = rule.Condition.KeyPrefixEquals
+		}
+		key = redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+
+	code = http.StatusMovedPermanently
+	if redirect.HTTPRedirectCode != "" {
+		code, _ = strconv.Atoi(redirect.HTTPRedirectCode)
+	}
+	u := url.URL{Scheme: scheme, Host: host, Path: slashSeparator + key}
+	return u.String(), code
+}
+
+// getRoutingRule - returns the first routing rule applying to a
+// request of key, nil if none does.
+func (website websiteConfig) getRoutingRule(key string, status int) *websiteRoutingRule {
+	for i := range website.RoutingRules {
+		if website.RoutingRules[i].matches(key, status) {
+			return &website.RoutingRules[i]
+		}
+	}
+	return nil
+}
+
+// loadBucketWebsite - loads website configuration of a bucket,
+// returns errNoSuchWebsiteConfiguration if none is set.
+func loadBucketWebsite(bucket string, objAPI ObjectLayer) (*websiteConfig, error) {
+	websitePath := path.Join(bucketConfigPrefix, bucket, bucketWebsiteConfig)
+
+	// Acquire a read lock on website config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, websitePath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, websitePath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchWebsiteConfiguration
+		}
+		errorIf(err, "Unable to load website configuration for bucket %s", bucket)
+		return nil, err
+	}
+
+	website := &websiteConfig{}
+	if err = xml.Unmarshal(buffer.Bytes(), website); err != nil {
+		return nil, err
+	}
+	return website, nil
+}
+
+// persistBucketWebsite - saves website configuration of a bucket.
+func persistBucketWebsite(bucket string, website websiteConfig, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(website)
+	if err != nil {
+		return err
+	}
+
+	websitePath := path.Join(bucketConfigPrefix, bucket, bucketWebsiteConfig)
+
+	// Acquire a write lock on website config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, websitePath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, websitePath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write website configuration for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketWebsite - removes website configuration of a bucket.
+func removeBucketWebsite(bucket string, objAPI ObjectLayer) error {
+	websitePath := path.Join(bucketConfigPrefix, bucket, bucketWebsiteConfig)
+
+	// Acquire a write lock on website config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, websitePath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, websitePath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketWebsite - returns website configuration of bucket,
+// nil if none is set. Configurations are cached in
+// globalBucketConfigCache.
+func getCachedBucketWebsite(bucket string, objAPI ObjectLayer) (*websiteConfig, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketWebsiteConfig, func() (interface{}, error) {
+		website, err := loadBucketWebsite(bucket, objAPI)
+		if err == errNoSuchWebsiteConfiguration {
+			return (*websiteConfig)(nil), nil
+		}
+		return website, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*websiteConfig), nil
+}
@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validation of website configurations.
+func TestParseBucketWebsite(t *testing.T) {
+	config := func(settings string) string {
+		return "<WebsiteConfiguration>" + settings + "</WebsiteConfiguration>"
+	}
+	index := "<IndexDocument><Suffix>index.html</Suffix></IndexDocument>"
+	rule := func(condition, redirect string) string {
+		return "<RoutingRules><RoutingRule>" + condition + "<Redirect>" + redirect + "</Redirect></RoutingRule></RoutingRules>"
+	}
+
+	testCases := []struct {
+		data     string
+		expected APIErrorCode
+	}{
+		// Test 1: index document only.
+		{config(index), ErrNone},
+		// Test 2: index and error documents.
+		{config(index + "<ErrorDocument><Key>404.html</Key></ErrorDocument>"), ErrNone},
+		// Test 3: redirect of all requests.
+		{config("<RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>https</Protocol></RedirectAllRequestsTo>"), ErrNone},
+		// Test 4: routing rule.
+		{config(index + rule("<Condition><KeyPrefixEquals>a/</KeyPrefixEquals></Condition>", "<ReplaceKeyPrefixWith>b/</ReplaceKeyPrefixWith>")), ErrNone},
+		// Test 5: malformed XML.
+		{"<WebsiteConfiguration>", ErrMalformedXML},
+		// Test 6: no index document.
+		{config(""), ErrInvalidWebsiteConfiguration},
+		// Test 7: index document with a slash.
+		{config("<IndexDocument><Suffix>a/index.html</Suffix></IndexDocument>"), ErrInvalidWebsiteConfiguration},
+		// Test 8: redirect of all requests along with other settings.
+		{config(index + "<RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo>"), ErrInvalidWebsiteConfiguration},
+		// Test 9: unknown protocol.
+		{config("<RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo>"), ErrInvalidWebsiteConfiguration},
+		// Test 10: empty redirect.
+		{config(index + rule("", "")), ErrInvalidWebsiteConfiguration},
+		// Test 11: key and key prefix replaced together.
+		{config(index + rule("", "<ReplaceKeyPrefixWith>b/</ReplaceKeyPrefixWith><ReplaceKeyWith>c</ReplaceKeyWith>")), ErrInvalidWebsiteConfiguration},
+		// Test 12: redirect code not 3XX.
+		{config(index + rule("", "<HttpRedirectCode>200</HttpRedirectCode>")), ErrInvalidWebsiteConfiguration},
+		// Test 13: error code not 4XX or 5XX.
+		{config(index + rule("<Condition><HttpErrorCodeReturnedEquals>200</HttpErrorCodeReturnedEquals></Condition>", "<HostName>example.com</HostName>")), ErrInvalidWebsiteConfiguration},
+	}
+	for i, testCase := range testCases {
+		if _, s3Error := parseBucketWebsite([]byte(testCase.data)); s3Error != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests routing rules apply to requests and redirect them.
+func TestWebsiteRoutingRule(t *testing.T) {
+	testCases := []struct {
+		rule             websiteRoutingRule
+		key              string
+		status           int
+		expectedMatch    bool
+		expectedLocation string
+		expectedCode     int
+	}{
+		// Test 1: rule without condition applies to all requests.
+		{websiteRoutingRule{Redirect: websiteRedirect{HostName: "example.com"}}, "a.html", 0, true, "http://example.com/a.html", 301},
+		// Test 2: but not to errors.
+		{websiteRoutingRule{Redirect: websiteRedirect{HostName: "example.com"}}, "a.html", 404, false, "", 0},
+		// Test 3: key prefix replaced.
+		{websiteRoutingRule{
+			Condition: &websiteCondition{KeyPrefixEquals: "docs/"},
+			Redirect:  websiteRedirect{ReplaceKeyPrefixWith: "documents/", HTTPRedirectCode: "302"},
+		}, "docs/a.html", 0, true, "http://bucket/documents/a.html", 302},
+		// Test 4: other prefix.
+		{websiteRoutingRule{Condition: &websiteCondition{KeyPrefixEquals: "docs/"}}, "a.html", 0, false, "", 0},
+		// Test 5: key replaced on error.
+		{websiteRoutingRule{
+			Condition: &websiteCondition{HTTPErrorCodeReturnedEquals: "404"},
+			Redirect:  websiteRedirect{Protocol: "https", ReplaceKeyWith: "404.html"},
+		}, "a.html", 404, true, "https://bucket/404.html", 301},
+		// Test 6: not before lookup.
+		{websiteRoutingRule{Condition: &websiteCondition{HTTPErrorCodeReturnedEquals: "404"}}, "a.html", 0, false, "", 0},
+		// Test 7: nor on other errors.
+		{websiteRoutingRule{Condition: &websiteCondition{HTTPErrorCodeReturnedEquals: "404"}}, "a.html", 403, false, "", 0},
+	}
+	for i, testCase := range testCases {
+		if match := testCase.rule.matches(testCase.key, testCase.status); match != testCase.expectedMatch {
+			t.Fatalf("Test %d: expected match %v, got %v", i+1, testCase.expectedMatch, match)
+		}
+		if !testCase.expectedMatch {
+			continue
+		}
+		location, code := testCase.rule.getRedirect(httptest.NewRequest("GET", "http://bucket/"+testCase.key, nil), testCase.key)
+		if location != testCase.expectedLocation || code != testCase.expectedCode {
+			t.Errorf("Test %d: expected redirect %d %s, got %d %s", i+1, testCase.expectedCode, testCase.expectedLocation, code, location)
+		}
+	}
+}
+
+// Tests buckets of website requests are named by their host.
+func TestGetWebsiteBucket(t *testing.T) {
+	globalWebsiteDomain = "web.example.com"
+	defer func() { globalWebsiteDomain = "" }()
+
+	testCases := []struct {
+		host     string
+		anyHost  bool
+		expected string
+	}{
+		// Test 1: bucket of the website domain.
+		{"bucket.web.example.com", false, "bucket"},
+		{"bucket.web.example.com:9000", true, "bucket"},
+		// Test 3: bucket named like the host.
+		{"www.example.com", false, ""},
+		{"www.example.com:9001", true, "www.example.com"},
+		// Test 5: host not naming a bucket.
+		{"127.0.0.1", true, ""},
+		{"minio", true, ""},
+	}
+	for i, testCase := range testCases {
+		if bucket := getWebsiteBucket(testCase.host, testCase.anyHost); bucket != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, bucket)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	router "github.com/gorilla/mux"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// Let's Encrypt production directory, used unless
+	// MINIO_ACME_DIRECTORY is set.
+	defaultACMEDirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+
+	// ACME account key file, saved in the certs directory.
+	acmeAccountKeyFile = "acme-account.key"
+
+	// Certificates are renewed when expiring within this duration.
+	acmeRenewBefore = 30 * 24 * time.Hour
+
+	// Interval between two checks for renewal, failed attempts are
+	// retried after acmeRetryInterval.
+	acmeCheckInterval = 12 * time.Hour
+	acmeRetryInterval = time.Hour
+
+	// Maximum time to obtain a certificate.
+	acmeObtainTimeout = 5 * time.Minute
+
+	// Path of HTTP-01 challenges, served over plain HTTP on port 80.
+	acmeChallengePathPrefix = "/.well-known/acme-challenge/"
+)
+
+// errNoHTTP01Challenge - returned if the CA offers no HTTP-01
+// challenge for the domain.
+var errNoHTTP01Challenge = errors.New("No HTTP-01 challenge offered by the ACME server")
+
+// acmeManager - obtains a certificate for the domain of the server
+// from an ACME CA such as Let's Encrypt and renews it before expiry.
+// Certificate and key are saved as public.crt and private.key in the
+// certs directory, from where certsManager loads them.
+type acmeManager struct {
+	directoryURL   string
+	email          string
+	domain         string
+	certFile       string
+	keyFile        string
+	accountKeyFile string
+
+	// Set once the account is registered with the CA.
+	registered bool
+
+	// Key authorizations of pending HTTP-01 challenges by token.
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// newACMEManager - initialize a new ACME manager, certificate files
+// are in certsDir.
+func newACMEManager(directoryURL, email, domain, certsDir string) *acmeManager {
+	return &acmeManager{
+		directoryURL:   directoryURL,
+		email:          email,
+		domain:         domain,
+		certFile:       filepath.Join(certsDir, publicCertFile),
+		keyFile:        filepath.Join(certsDir, privateKeyFile),
+		accountKeyFile: filepath.Join(certsDir, acmeAccountKeyFile),
+		tokens:         make(map[string]string),
+	}
+}
+
+// loadAccountKey - loads the ACME account key, a new one is generated
+// and saved on first use.
+func (m *acmeManager) loadAccountKey() (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(m.accountKeyFile)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("Invalid ACME account key %s", m.accountKeyFile)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err = writePEMFile(m.accountKeyFile, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// writePEMFile - saves PEM encoded EC private key to file, replacing
+// it at once.
+func writePEMFile(file string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+}
+
+// writeFileAtomic - writes data to a temporary file renamed to file.
+func writeFileAtomic(file string, data []byte, perm os.FileMode) error {
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// isRenewalDue - returns true if there is no certificate for the
+// domain or it expires within acmeRenewBefore from now.
+func (m *acmeManager) isRenewalDue(now time.Time) bool {
+	certs, err := parsePublicCertFile(m.certFile)
+	if err != nil || len(certs) == 0 {
+		return true
+	}
+	if certs[0].VerifyHostname(m.domain) != nil {
+		return true
+	}
+	return certs[0].NotAfter.Before(now.Add(acmeRenewBefore))
+}
+
+// getKeyAuthorization - returns key authorization of a pending
+// HTTP-01 challenge token.
+func (m *acmeManager) getKeyAuthorization(token string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keyAuth, ok := m.tokens[token]
+	return keyAuth, ok
+}
+
+// setKeyAuthorization - serves key authorization of token until it
+// is removed.
+func (m *acmeManager) setKeyAuthorization(token, keyAuth string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[token] = keyAuth
+}
+
+// removeKeyAuthorization - stops serving key authorization of token.
+func (m *acmeManager) removeKeyAuthorization(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, token)
+}
+
+// authorize - completes the HTTP-01 challenge of a pending
+// authorization.
+func (m *acmeManager) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return errNoHTTP01Challenge
+	}
+
+	keyAuth, err := client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+	m.setKeyAuthorization(challenge.Token, keyAuth)
+	defer m.removeKeyAuthorization(challenge.Token)
+
+	if _, err = client.Accept(ctx, challenge); err != nil {
+		return err
+	}
+	_, err = client.WaitAuthorization(ctx, authz.URI)
+	return err
+}
+
+// obtain - obtains a new certificate for the domain and saves it,
+// along with its private key, in place of the current one.
+func (m *acmeManager) obtain(ctx context.Context) error {
+	accountKey, err := m.loadAccountKey()
+	if err != nil {
+		return err
+	}
+	client := &acme.Client{Key: accountKey, DirectoryURL: m.directoryURL}
+
+	if !m.registered {
+		account := &acme.Account{}
+		if m.email != "" {
+			account.Contact = []string{"mailto:" + m.email}
+		}
+		if _, err = client.Register(ctx, account, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+			return err
+		}
+		m.registered = true
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(m.domain))
+	if err != nil {
+		return err
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err = m.authorize(ctx, client, authzURL); err != nil {
+			return err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: m.domain},
+		DNSNames: []string{m.domain},
+	}, key)
+	if err != nil {
+		return err
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return err
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	// Key is replaced first, certsManager keeps serving the current
+	// certificate until both files match.
+	if err = writePEMFile(m.keyFile, key); err != nil {
+		return err
+	}
+	return writeFileAtomic(m.certFile, certPEM, 0644)
+}
+
+// renew - obtains a certificate if renewal is due, returns true if
+// a new certificate was saved.
+func (m *acmeManager) renew(now time.Time) (bool, error) {
+	if !m.isRenewalDue(now) {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), acmeObtainTimeout)
+	defer cancel()
+	if err := m.obtain(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// run - renews the certificate when due, checked at every interval
+// until doneCh is closed. New certificates are loaded by certs right
+// away.
+func (m *acmeManager) run(certs *certsManager, doneCh <-chan struct{}) {
+	for {
+		interval := acmeCheckInterval
+		renewed, err := m.renew(UTCNow())
+		if err != nil {
+			errorIf(err, "Unable to obtain certificate for %s from %s.", m.domain, m.directoryURL)
+			interval = acmeRetryInterval
+		} else if renewed && certs != nil {
+			_, err = certs.reload()
+			errorIf(err, "Unable to load certificate %s.", m.certFile)
+		}
+
+		select {
+		case <-doneCh:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// initACME - initializes globalACMEManager for the domain of the
+// server. TLS is enabled even if no certificate was obtained yet,
+// handshakes fail until the first one is.
+func initACME() error {
+	if globalIsDistXL {
+		return errors.New("ACME is not supported in distributed setups")
+	}
+	if globalDomainName == "" {
+		return errors.New("ACME needs the domain of the server, set through MINIO_DOMAIN or config")
+	}
+	globalACMEManager = newACMEManager(globalACMEDirectoryURL, globalACMEEmail, globalDomainName, filepath.Dir(getPublicCertFile()))
+	if globalIsSSL {
+		return nil
+	}
+
+	rootCAs, err := getRootCAs(getCADir())
+	if err != nil {
+		return err
+	}
+	globalRootCAs = rootCAs
+	globalIsSSL = true
+	globalServerNetConfig.CertFile, globalServerNetConfig.KeyFile = getPublicCertFile(), getPrivateKeyFile()
+	globalCertsManager = newPendingCertsManager(getPublicCertFile(), getPrivateKeyFile())
+	go globalCertsManager.watch(certsReloadInterval, nil)
+	return nil
+}
+
+// isACMEChallengeRequest - returns true if request is an HTTP-01
+// challenge of the ACME CA.
+func isACMEChallengeRequest(r *http.Request) bool {
+	return r.Method == httpGET && hasPrefix(r.URL.Path, acmeChallengePathPrefix)
+}
+
+// registerACMERouter - registers HTTP-01 challenge endpoint.
+func registerACMERouter(mux *router.Router, m *acmeManager) {
+	mux.Methods("GET").PathPrefix(acmeChallengePathPrefix).HandlerFunc(m.ChallengeHandler)
+}
+
+// ChallengeHandler - GET /.well-known/acme-challenge/<token>
+// ----------
+// Returns key authorization of a pending HTTP-01 challenge.
+func (m *acmeManager) ChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	keyAuth, ok := m.getKeyAuthorization(strings.TrimPrefix(r.URL.Path, acmeChallengePathPrefix))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(keyAuth))
+}
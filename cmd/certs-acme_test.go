@@ -0,0 +1,323 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// testACMEServer - minimal RFC 8555 CA issuing certificates once the
+// HTTP-01 challenge is answered by the challenge handler.
+type testACMEServer struct {
+	*httptest.Server
+	handler http.HandlerFunc
+
+	mu          sync.Mutex
+	orderStatus string
+	certPEM     []byte
+	accounts    int
+	orders      int
+}
+
+func newTestACMEServer(t *testing.T, domain string, challengeHandler http.HandlerFunc) *testACMEServer {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Minio Test CA"},
+		NotBefore:             UTCNow().Add(-time.Hour),
+		NotAfter:              UTCNow().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	s := &testACMEServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+		order := func(status int) {
+			w.Header().Set("Location", s.URL+"/order/1")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":         s.orderStatus,
+				"identifiers":    []map[string]string{{"type": "dns", "value": domain}},
+				"authorizations": []string{s.URL + "/authz/1"},
+				"finalize":       s.URL + "/finalize/1",
+				"certificate":    s.URL + "/cert/1",
+			})
+		}
+		authzStatus := "pending"
+		if s.orderStatus != "pending" {
+			authzStatus = "valid"
+		}
+
+		switch r.URL.Path {
+		case "/directory":
+			json.NewEncoder(w).Encode(map[string]string{
+				"newNonce":   s.URL + "/nonce",
+				"newAccount": s.URL + "/account",
+				"newOrder":   s.URL + "/order",
+			})
+		case "/nonce":
+		case "/account":
+			// Account of the same key is created once.
+			w.Header().Set("Location", s.URL+"/account/1")
+			if s.accounts == 0 {
+				w.WriteHeader(http.StatusCreated)
+			}
+			s.accounts++
+			w.Write([]byte(`{"status":"valid"}`))
+		case "/order":
+			s.orders++
+			s.orderStatus = "pending"
+			order(http.StatusCreated)
+		case "/order/1":
+			order(http.StatusOK)
+		case "/authz/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"identifier": map[string]string{"type": "dns", "value": domain},
+				"status":     authzStatus,
+				"challenges": []map[string]string{{
+					"type":   "http-01",
+					"url":    s.URL + "/challenge/1",
+					"token":  "token1",
+					"status": authzStatus,
+				}},
+			})
+		case "/challenge/1":
+			// Validate key authorization served by the challenge handler.
+			rec := httptest.NewRecorder()
+			challengeHandler(rec, httptest.NewRequest(httpGET, acmeChallengePathPrefix+"token1", nil))
+			if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "token1.") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			s.orderStatus = "ready"
+			json.NewEncoder(w).Encode(map[string]string{
+				"type":   "http-01",
+				"url":    s.URL + "/challenge/1",
+				"token":  "token1",
+				"status": "valid",
+			})
+		case "/finalize/1":
+			var jws struct{ Payload string }
+			var req struct{ CSR string }
+			json.NewDecoder(r.Body).Decode(&jws)
+			payload, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+			json.Unmarshal(payload, &req)
+			der, _ := base64.RawURLEncoding.DecodeString(req.CSR)
+			csr, err := x509.ParseCertificateRequest(der)
+			if err != nil || s.orderStatus != "ready" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(int64(s.orders + 1)),
+				Subject:      csr.Subject,
+				DNSNames:     csr.DNSNames,
+				NotBefore:    UTCNow().Add(-time.Hour),
+				NotAfter:     UTCNow().Add(90 * 24 * time.Hour),
+				KeyUsage:     x509.KeyUsageDigitalSignature,
+				ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}
+			der, err = x509.CreateCertificate(rand.Reader, template, caTemplate, csr.PublicKey, caKey)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			s.certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+			s.orderStatus = "valid"
+			order(http.StatusOK)
+		case "/cert/1":
+			w.Header().Set("Content-Type", "application/pem-certificate-chain")
+			w.Write(s.certPEM)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+// Tests certificate is obtained from the CA and renewed when due.
+func TestACMEManagerRenew(t *testing.T) {
+	certsDir, err := ioutil.TempDir(globalTestTmpDir, "minio-acme-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(certsDir)
+
+	domain := "minio.example.com"
+	var m *acmeManager
+	server := newTestACMEServer(t, domain, func(w http.ResponseWriter, r *http.Request) {
+		m.ChallengeHandler(w, r)
+	})
+	defer server.Close()
+	m = newACMEManager(server.URL+"/directory", "admin@example.com", domain, certsDir)
+
+	// No certificate yet.
+	now := UTCNow()
+	if !m.isRenewalDue(now) {
+		t.Fatal("Expected renewal to be due without certificate")
+	}
+	renewed, err := m.renew(now)
+	if err != nil || !renewed {
+		t.Fatalf("Expected certificate to be obtained, got %v, %v", renewed, err)
+	}
+	certs, err := parsePublicCertFile(m.certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = certs[0].VerifyHostname(domain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(certsDir, acmeAccountKeyFile)); err != nil {
+		t.Fatal(err)
+	}
+	// Pending challenges are not served anymore.
+	if _, ok := m.getKeyAuthorization("token1"); ok {
+		t.Fatal("Expected challenge token to be removed")
+	}
+
+	// Pending certs manager loads the obtained certificate.
+	certsManager := newPendingCertsManager(m.certFile, m.keyFile)
+	if reloaded, err := certsManager.reload(); err != nil || !reloaded {
+		t.Fatalf("Expected certificate to be loaded, got %v, %v", reloaded, err)
+	}
+
+	// Not renewed before the renewal window.
+	if renewed, err = m.renew(now); err != nil || renewed {
+		t.Fatalf("Expected no renewal, got %v, %v", renewed, err)
+	}
+	// Renewed within the renewal window, with the same account key.
+	later := now.Add(70 * 24 * time.Hour)
+	if renewed, err = m.renew(later); err != nil || !renewed {
+		t.Fatalf("Expected renewal, got %v, %v", renewed, err)
+	}
+	if server.orders != 2 {
+		t.Fatalf("Expected 2 orders, got %d", server.orders)
+	}
+	newCerts, err := parsePublicCertFile(m.certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCerts[0].SerialNumber.Cmp(certs[0].SerialNumber) == 0 {
+		t.Fatal("Expected new certificate to be saved")
+	}
+}
+
+// Tests certificate of another domain is renewed.
+func TestACMEManagerIsRenewalDue(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	if err = createConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	// Test certificate is valid for a minute.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := UTCNow()
+	testCases := []struct {
+		domain   string
+		expected bool
+	}{
+		// Test 1: certificate for the domain expiring soon.
+		{"127.0.0.1", true},
+		// Test 2: certificate for another domain.
+		{"minio.example.com", true},
+	}
+	for i, testCase := range testCases {
+		m := newACMEManager(defaultACMEDirectoryURL, "", testCase.domain, filepath.Dir(getPublicCertFile()))
+		if due := m.isRenewalDue(now); due != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, due)
+		}
+	}
+}
+
+// Tests HTTP-01 challenges are answered only for pending tokens.
+func TestACMEChallengeHandler(t *testing.T) {
+	m := newACMEManager(defaultACMEDirectoryURL, "", "minio.example.com", os.TempDir())
+	m.setKeyAuthorization("token1", "token1.thumbprint")
+
+	testCases := []struct {
+		method       string
+		path         string
+		isChallenge  bool
+		expectedCode int
+		expectedBody string
+	}{
+		// Test 1: pending token.
+		{httpGET, acmeChallengePathPrefix + "token1", true, http.StatusOK, "token1.thumbprint"},
+		// Test 2: unknown token.
+		{httpGET, acmeChallengePathPrefix + "token2", true, http.StatusNotFound, ""},
+		// Test 3: not a challenge request.
+		{httpGET, "/bucket/object", false, http.StatusNotFound, ""},
+		// Test 4: challenges are only fetched with GET.
+		{httpPUT, acmeChallengePathPrefix + "token1", false, http.StatusNotFound, ""},
+	}
+	for i, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.path, nil)
+		if isChallenge := isACMEChallengeRequest(req); isChallenge != testCase.isChallenge {
+			t.Errorf("Test %d: expected challenge request %v, got %v", i+1, testCase.isChallenge, isChallenge)
+		}
+		mux := router.NewRouter()
+		registerACMERouter(mux, m)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedCode, rec.Code)
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: expected body %q, got %q", i+1, testCase.expectedBody, rec.Body.String())
+		}
+	}
+
+	m.removeKeyAuthorization("token1")
+	if _, ok := m.getKeyAuthorization("token1"); ok {
+		t.Fatal("Expected token to be removed")
+	}
+}
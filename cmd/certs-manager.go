@@ -0,0 +1,210 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+)
+
+// certsReloadInterval - interval at which certs directory is checked
+// for updated certificate files and certificates for nearing expiry.
+const certsReloadInterval = time.Minute
+
+// certsManager - serves the server certificate and reloads it whenever
+// public.crt or private.key is updated, without a restart.
+type certsManager struct {
+	sync.RWMutex
+	certFile    string
+	keyFile     string
+	cert        *tls.Certificate
+	publicCerts []*x509.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+
+	// Last time an expiry alert was logged per certificate.
+	alertedAt map[string]time.Time
+
+	// Set while waiting for certificate files to be written, e.g. by
+	// ACME, missing files are then not an error.
+	pending bool
+}
+
+// newCertsManager - loads certificate and key, returns error if they
+// cannot be loaded.
+func newCertsManager(certFile, keyFile string) (*certsManager, error) {
+	m := &certsManager{
+		certFile:  certFile,
+		keyFile:   keyFile,
+		alertedAt: make(map[string]time.Time),
+	}
+	if _, err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// newPendingCertsManager - returns a manager without certificate,
+// which is loaded once certificate and key files are written.
+func newPendingCertsManager(certFile, keyFile string) *certsManager {
+	return &certsManager{
+		certFile:  certFile,
+		keyFile:   keyFile,
+		alertedAt: make(map[string]time.Time),
+		pending:   true,
+	}
+}
+
+// reload - loads certificate files if they were modified since they
+// were last loaded, returns true if certificate was reloaded. An
+// invalid update is reported without replacing the current certificate,
+// this also happens while the files are being replaced one by one.
+func (m *certsManager) reload() (bool, error) {
+	m.RLock()
+	pending := m.pending
+	m.RUnlock()
+
+	certFi, err := os.Stat(m.certFile)
+	if err != nil {
+		if pending && os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	keyFi, err := os.Stat(m.keyFile)
+	if err != nil {
+		if pending && os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	m.RLock()
+	modified := m.cert == nil || !certFi.ModTime().Equal(m.certModTime) || !keyFi.ModTime().Equal(m.keyModTime)
+	m.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+	if err != nil {
+		return false, err
+	}
+	publicCerts, err := parsePublicCertFile(m.certFile)
+	if err != nil {
+		return false, err
+	}
+
+	m.Lock()
+	m.cert = &cert
+	m.publicCerts = publicCerts
+	m.certModTime = certFi.ModTime()
+	m.keyModTime = keyFi.ModTime()
+	m.pending = false
+	m.Unlock()
+	return true, nil
+}
+
+// GetCertificate - returns current server certificate, satisfies
+// tls.Config.GetCertificate.
+func (m *certsManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.cert, nil
+}
+
+// GetClientCertificate - returns current certificate to authenticate
+// with other nodes, satisfies tls.Config.GetClientCertificate.
+func (m *certsManager) GetClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.cert, nil
+}
+
+// GetPublicCerts - returns currently loaded public certificate chain.
+func (m *certsManager) GetPublicCerts() []*x509.Certificate {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.publicCerts
+}
+
+// expiringCerts - returns certificates in the chain expiring within
+// the given duration which were not reported in the last day.
+func (m *certsManager) expiringCerts(within time.Duration, now time.Time) (certs []*x509.Certificate) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, cert := range m.publicCerts {
+		if cert.NotAfter.After(now.Add(within)) {
+			continue
+		}
+		key := cert.SerialNumber.String()
+		if alertedAt, ok := m.alertedAt[key]; ok && now.Sub(alertedAt) < 24*time.Hour {
+			continue
+		}
+		m.alertedAt[key] = now
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+// watch - periodically reloads updated certificate files and reports
+// certificates expiring within globalCertExpiryWarn, until doneCh is
+// closed.
+func (m *certsManager) watch(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := m.reload()
+		errorIf(err, "Unable to reload certificate %s.", m.certFile)
+		for _, cert := range m.expiringCerts(globalCertExpiryWarn, UTCNow()) {
+			errorIf(errCertExpiring, "Certificate %s expires on %s.", cert.Subject.CommonName, cert.NotAfter)
+		}
+
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// getCertsInfo - returns expiry information of currently served
+// certificates.
+func getCertsInfo(now time.Time) []ServerCertInfo {
+	if globalCertsManager == nil {
+		return nil
+	}
+
+	var certsInfo []ServerCertInfo
+	for _, cert := range globalCertsManager.GetPublicCerts() {
+		certsInfo = append(certsInfo, ServerCertInfo{
+			Subject:      cert.Subject.CommonName,
+			NotAfter:     cert.NotAfter,
+			DaysToExpiry: int64(cert.NotAfter.Sub(now) / (24 * time.Hour)),
+			Expiring:     cert.NotAfter.Before(now.Add(globalCertExpiryWarn)),
+		})
+	}
+	return certsInfo
+}
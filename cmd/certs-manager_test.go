@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests certificate is reloaded when certificate files are updated.
+func TestCertsManagerReload(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	if err = createConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := newCertsManager(getPublicCertFile(), getPrivateKeyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := m.GetCertificate(nil)
+	serial := m.GetPublicCerts()[0].SerialNumber
+
+	// Files are not modified.
+	if reloaded, err := m.reload(); err != nil || reloaded {
+		t.Fatalf("Expected no reload, got %v, %v", reloaded, err)
+	}
+
+	// Files are replaced with a new certificate.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	modTime := UTCNow().Add(time.Minute)
+	os.Chtimes(getPublicCertFile(), modTime, modTime)
+	os.Chtimes(getPrivateKeyFile(), modTime, modTime)
+	if reloaded, err := m.reload(); err != nil || !reloaded {
+		t.Fatalf("Expected reload, got %v, %v", reloaded, err)
+	}
+	if newCert, _ := m.GetCertificate(nil); newCert == cert {
+		t.Fatal("Expected new certificate to be served")
+	}
+	if newSerial := m.GetPublicCerts()[0].SerialNumber; newSerial.Cmp(serial) == 0 {
+		t.Fatal("Expected new public certificate")
+	}
+	cert, _ = m.GetCertificate(nil)
+
+	// Invalid certificate keeps current certificate.
+	if err = ioutil.WriteFile(getPublicCertFile(), []byte("invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime = modTime.Add(time.Minute)
+	os.Chtimes(getPublicCertFile(), modTime, modTime)
+	if _, err = m.reload(); err == nil {
+		t.Fatal("Expected invalid certificate to fail")
+	}
+	if clientCert, _ := m.GetClientCertificate(nil); clientCert != cert {
+		t.Fatal("Expected current certificate to be served")
+	}
+
+	// Missing files cannot be loaded.
+	if _, err = newCertsManager(getPublicCertFile()+".missing", getPrivateKeyFile()); err == nil {
+		t.Fatal("Expected missing certificate to fail")
+	}
+}
+
+// Tests certificates nearing expiry are reported once a day.
+func TestCertsManagerExpiringCerts(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() { globalCertsManager = nil }()
+
+	if err = createConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	// Test certificate expires in a minute.
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := newCertsManager(getPublicCertFile(), getPrivateKeyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := UTCNow()
+	testCases := []struct {
+		within   time.Duration
+		now      time.Time
+		expected int
+	}{
+		// Not within warning window.
+		{time.Second, now, 0},
+		// Within warning window.
+		{time.Hour, now, 1},
+		// Already reported.
+		{time.Hour, now.Add(time.Hour), 0},
+		// Reported again after a day.
+		{time.Hour, now.Add(25 * time.Hour), 1},
+	}
+	for i, testCase := range testCases {
+		if certs := m.expiringCerts(testCase.within, testCase.now); len(certs) != testCase.expected {
+			t.Errorf("Test %d: expected %d certificates, got %d", i+1, testCase.expected, len(certs))
+		}
+	}
+
+	// Expiry information is reported through server info.
+	if certsInfo := getCertsInfo(now); certsInfo != nil {
+		t.Fatalf("Expected no certificates, got %v", certsInfo)
+	}
+	globalCertsManager = m
+	certsInfo := getCertsInfo(now)
+	if len(certsInfo) != 1 || !certsInfo[0].Expiring || certsInfo[0].DaysToExpiry != 0 {
+		t.Fatalf("Unexpected certificates info %#v", certsInfo)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// Default interval between two evaluations of the cluster state.
+const defaultHealthEvalInterval = 1 * time.Minute
+
+// clusterState - a small set of gauges describing the state of the
+// cluster as seen by this server, kept simple so that alert rules
+// can be written directly on them.
+type clusterState struct {
+	// Set if enough disks are online for writes and server is not
+	// in read-only mode.
+	Writeable bool
+	// Set if enough disks are online for reads.
+	QuorumOK bool
+	// Number of disks which are offline.
+	DisksOffline int
+	// Number of buckets and objects which need healing.
+	HealBacklog int
+}
+
+// getClusterState - evaluates the current state of the cluster.
+func getClusterState(objAPI ObjectLayer) clusterState {
+	storageInfo := objAPI.StorageInfo()
+	if storageInfo.Backend.Type != Erasure {
+		// FS is always in quorum when it is initialized.
+		return clusterState{
+			Writeable: !globalIsReadOnly,
+			QuorumOK:  true,
+		}
+	}
+
+	onlineDisks := storageInfo.Backend.OnlineDisks
+	return clusterState{
+		Writeable:    onlineDisks >= storageInfo.Backend.WriteQuorum && !globalIsReadOnly,
+		QuorumOK:     onlineDisks >= storageInfo.Backend.ReadQuorum,
+		DisksOffline: storageInfo.Backend.OfflineDisks,
+		HealBacklog:  getHealBacklog(objAPI),
+	}
+}
+
+// getHealBacklog - returns number of buckets and objects which need
+// healing, errors are logged and whatever was counted is returned.
+func getHealBacklog(objAPI ObjectLayer) (backlog int) {
+	healBuckets, err := objAPI.ListBucketsHeal()
+	if err != nil {
+		errorIf(err, "Unable to list buckets needing heal.")
+		return 0
+	}
+	backlog += len(healBuckets)
+
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets.")
+		return backlog
+	}
+	for _, bucket := range buckets {
+		marker := ""
+		for {
+			lo, err := objAPI.ListObjectsHeal(bucket.Name, "", marker, "", maxObjectList)
+			if err != nil {
+				errorIf(err, "Unable to list objects needing heal in bucket %s.", bucket.Name)
+				break
+			}
+			backlog += len(lo.Objects)
+			if !lo.IsTruncated {
+				break
+			}
+			marker = lo.NextMarker
+		}
+	}
+	return backlog
+}
+
+// healthEvaluator periodically evaluates the cluster state, the last
+// evaluated state is served to metrics scrapers.
+type healthEvaluator struct {
+	objAPI   func() ObjectLayer
+	interval time.Duration
+
+	mu        sync.RWMutex
+	state     clusterState
+	evaluated bool
+}
+
+// newHealthEvaluator - initialize a new health evaluator.
+func newHealthEvaluator(objAPI func() ObjectLayer, interval time.Duration) *healthEvaluator {
+	return &healthEvaluator{
+		objAPI:   objAPI,
+		interval: interval,
+	}
+}
+
+// evaluate - evaluates and saves the cluster state.
+func (h *healthEvaluator) evaluate() {
+	objAPI := h.objAPI()
+	if objAPI == nil {
+		return
+	}
+
+	state := getClusterState(objAPI)
+
+	h.mu.Lock()
+	h.state = state
+	h.evaluated = true
+	h.mu.Unlock()
+}
+
+// getState - returns the last evaluated cluster state, returns false
+// if the cluster state was not evaluated yet.
+func (h *healthEvaluator) getState() (clusterState, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.state, h.evaluated
+}
+
+// run - evaluates at every interval until doneCh is closed.
+func (h *healthEvaluator) run(doneCh <-chan struct{}) {
+	h.evaluate()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.evaluate()
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// startHealthEvaluator - starts evaluating the cluster state in background.
+func startHealthEvaluator(doneCh <-chan struct{}) {
+	go globalHealthEvaluator.run(doneCh)
+}
@@ -0,0 +1,146 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests cluster state evaluation of XL.
+func TestGetClusterStateXL(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range []string{"object1", "object2"} {
+		if _, err = obj.PutObject("bucket", object, 5, bytes.NewReader([]byte("hello")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Healthy cluster.
+	state := getClusterState(obj)
+	expected := clusterState{Writeable: true, QuorumOK: true}
+	if state != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, state)
+	}
+
+	// Object missing on a disk needs healing.
+	if err = os.RemoveAll(filepath.Join(fsDirs[0], "bucket", "object1")); err != nil {
+		t.Fatal(err)
+	}
+	state = getClusterState(obj)
+	expected = clusterState{Writeable: true, QuorumOK: true, HealBacklog: 1}
+	if state != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, state)
+	}
+
+	// Server in read-only mode isn't writeable.
+	globalIsReadOnly = true
+	state = getClusterState(obj)
+	globalIsReadOnly = false
+	if state.Writeable {
+		t.Fatalf("Expected read-only cluster to not be writeable, got %+v", state)
+	}
+
+	// Offline disks, write quorum is lost before read quorum.
+	for i := 0; i < len(xl.storageDisks)/2; i++ {
+		xl.storageDisks[i] = nil
+	}
+	state = getClusterState(obj)
+	if state.Writeable || !state.QuorumOK || state.DisksOffline != len(xl.storageDisks)/2 {
+		t.Fatalf("Expected cluster in read quorum only with %d disks offline, got %+v", len(xl.storageDisks)/2, state)
+	}
+
+	for i := range xl.storageDisks {
+		xl.storageDisks[i] = nil
+	}
+	state = getClusterState(obj)
+	if state.Writeable || state.QuorumOK || state.DisksOffline != len(xl.storageDisks) {
+		t.Fatalf("Expected cluster out of quorum with all disks offline, got %+v", state)
+	}
+}
+
+// Tests cluster state evaluation of FS.
+func TestGetClusterStateFS(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	state := getClusterState(obj)
+	expected := clusterState{Writeable: true, QuorumOK: true}
+	if state != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, state)
+	}
+}
+
+// Tests that health evaluator saves the evaluated state.
+func TestHealthEvaluator(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	var objAPI ObjectLayer
+	evaluator := newHealthEvaluator(func() ObjectLayer { return objAPI }, defaultHealthEvalInterval)
+
+	// Nothing is evaluated before object layer is initialized.
+	evaluator.evaluate()
+	if _, ok := evaluator.getState(); ok {
+		t.Fatal("Expected cluster state to not be evaluated")
+	}
+
+	objAPI = obj
+	evaluator.evaluate()
+	state, ok := evaluator.getState()
+	if !ok {
+		t.Fatal("Expected cluster state to be evaluated")
+	}
+	if !state.Writeable || !state.QuorumOK {
+		t.Fatalf("Expected healthy cluster state, got %+v", state)
+	}
+}
@@ -93,6 +93,78 @@ func migrateConfig() error {
 	if err := migrateV17ToV18(); err != nil {
 		return err
 	}
+	// Migration version '18' to '19'.
+	if err := migrateV18ToV19(); err != nil {
+		return err
+	}
+	// Migration version '19' to '20'.
+	if err := migrateV19ToV20(); err != nil {
+		return err
+	}
+	// Migration version '20' to '21'.
+	if err := migrateV20ToV21(); err != nil {
+		return err
+	}
+	// Migration version '21' to '22'.
+	if err := migrateV21ToV22(); err != nil {
+		return err
+	}
+	// Migration version '22' to '23'.
+	if err := migrateV22ToV23(); err != nil {
+		return err
+	}
+	// Migration version '23' to '24'.
+	if err := migrateV23ToV24(); err != nil {
+		return err
+	}
+	// Migration version '24' to '25'.
+	if err := migrateV24ToV25(); err != nil {
+		return err
+	}
+	// Migration version '25' to '26'.
+	if err := migrateV25ToV26(); err != nil {
+		return err
+	}
+	// Migration version '26' to '27'.
+	if err := migrateV26ToV27(); err != nil {
+		return err
+	}
+	// Migration version '27' to '28'.
+	if err := migrateV27ToV28(); err != nil {
+		return err
+	}
+	// Migration version '28' to '29'.
+	if err := migrateV28ToV29(); err != nil {
+		return err
+	}
+	// Migration version '29' to '30'.
+	if err := migrateV29ToV30(); err != nil {
+		return err
+	}
+	// Migration version '30' to '31'.
+	if err := migrateV30ToV31(); err != nil {
+		return err
+	}
+	// Migration version '31' to '32'.
+	if err := migrateV31ToV32(); err != nil {
+		return err
+	}
+	// Migration version '32' to '33'.
+	if err := migrateV32ToV33(); err != nil {
+		return err
+	}
+	// Migration version '33' to '34'.
+	if err := migrateV33ToV34(); err != nil {
+		return err
+	}
+	// Migration version '34' to '35'.
+	if err := migrateV34ToV35(); err != nil {
+		return err
+	}
+	// Migration version '35' to '36'.
+	if err := migrateV35ToV36(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1315,3 +1387,1165 @@ func migrateV17ToV18() error {
 	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv17.Version, srvConfig.Version)
 	return nil
 }
+
+// Version '18' to '19' migration. Adds "lock" configuration
+// parameters to tune distributed locking.
+func migrateV18ToV19() error {
+	configFile := getConfigFile()
+
+	cv18 := &serverConfigV18{}
+	_, err := quick.Load(configFile, cv18)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘18’. %v", err)
+	}
+	if cv18.Version != "18" {
+		return nil
+	}
+
+	// Copy over fields from V18 into V19 config struct
+	srvConfig := &serverConfigV19{
+		Logger: cv18.Logger,
+		Notify: cv18.Notify,
+	}
+	srvConfig.Version = "19"
+	srvConfig.Credential = cv18.Credential
+	srvConfig.Region = cv18.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv18.Browser
+
+	// New lock parameters default to built-in defaults when
+	// empty, so nothing to explicitly migrate here.
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv18.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv18.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '19' to '20' migration. Adds "quota" configuration
+// parameters to limit requests and bandwidth per access key.
+func migrateV19ToV20() error {
+	configFile := getConfigFile()
+
+	cv19 := &serverConfigV19{}
+	_, err := quick.Load(configFile, cv19)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘19’. %v", err)
+	}
+	if cv19.Version != "19" {
+		return nil
+	}
+
+	// Copy over fields from V19 into V20 config struct
+	srvConfig := &serverConfigV20{
+		Logger: cv19.Logger,
+		Notify: cv19.Notify,
+	}
+	srvConfig.Version = "20"
+	srvConfig.Credential = cv19.Credential
+	srvConfig.Region = cv19.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv19.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv19.DistLock
+
+	// No quotas are enforced by default, so nothing to
+	// explicitly migrate here.
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv19.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv19.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '20' to '21' migration. Adds "rpc" configuration
+// parameters to enable mutual TLS for inter-node RPC.
+func migrateV20ToV21() error {
+	configFile := getConfigFile()
+
+	cv20 := &serverConfigV20{}
+	_, err := quick.Load(configFile, cv20)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘20’. %v", err)
+	}
+	if cv20.Version != "20" {
+		return nil
+	}
+
+	// Copy over fields from V20 into V21 config struct
+	srvConfig := &serverConfigV21{
+		Logger: cv20.Logger,
+		Notify: cv20.Notify,
+	}
+	srvConfig.Version = "21"
+	srvConfig.Credential = cv20.Credential
+	srvConfig.Region = cv20.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv20.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv20.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv20.Quota
+
+	// Mutual TLS is disabled by default, so nothing to
+	// explicitly migrate here.
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv20.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv20.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '21' to '22' adds support for routing log streams, default
+// routing is used for all streams after migration.
+func migrateV21ToV22() error {
+	configFile := getConfigFile()
+
+	cv21 := &serverConfigV21{}
+	_, err := quick.Load(configFile, cv21)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘21’. %v", err)
+	}
+	if cv21.Version != "21" {
+		return nil
+	}
+
+	// Copy over fields from V21 into V22 config struct
+	srvConfig := &serverConfigV22{
+		Logger: cv21.Logger,
+		Notify: cv21.Notify,
+	}
+	srvConfig.Version = "22"
+	srvConfig.Credential = cv21.Credential
+	srvConfig.Region = cv21.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv21.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv21.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv21.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv21.RPC
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv21.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv21.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '22' to '23' adds support for multipart upload limits,
+// default limits are used after migration.
+func migrateV22ToV23() error {
+	configFile := getConfigFile()
+
+	cv22 := &serverConfigV22{}
+	_, err := quick.Load(configFile, cv22)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘22’. %v", err)
+	}
+	if cv22.Version != "22" {
+		return nil
+	}
+
+	// Copy over fields from V22 into V23 config struct
+	srvConfig := &serverConfigV23{
+		Logger: cv22.Logger,
+		Notify: cv22.Notify,
+	}
+	srvConfig.Version = "23"
+	srvConfig.Credential = cv22.Credential
+	srvConfig.Region = cv22.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv22.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv22.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv22.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv22.RPC
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv22.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv22.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '23' to '24' adds support for a remote tier, which is
+// disabled after migration.
+func migrateV23ToV24() error {
+	configFile := getConfigFile()
+
+	cv23 := &serverConfigV23{}
+	_, err := quick.Load(configFile, cv23)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘23’. %v", err)
+	}
+	if cv23.Version != "23" {
+		return nil
+	}
+
+	// Copy over fields from V23 into V24 config struct
+	srvConfig := &serverConfigV24{
+		Logger: cv23.Logger,
+		Notify: cv23.Notify,
+	}
+	srvConfig.Version = "24"
+	srvConfig.Credential = cv23.Credential
+	srvConfig.Region = cv23.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv23.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv23.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv23.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv23.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv23.Multipart
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv23.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv23.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '24' to '25' adds support for an LDAP identity provider,
+// which is disabled after migration.
+func migrateV24ToV25() error {
+	configFile := getConfigFile()
+
+	cv24 := &serverConfigV24{}
+	_, err := quick.Load(configFile, cv24)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘24’. %v", err)
+	}
+	if cv24.Version != "24" {
+		return nil
+	}
+
+	// Copy over fields from V24 into V25 config struct
+	srvConfig := &serverConfigV25{
+		Logger: cv24.Logger,
+		Notify: cv24.Notify,
+	}
+	srvConfig.Version = "25"
+	srvConfig.Credential = cv24.Credential
+	srvConfig.Region = cv24.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv24.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv24.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv24.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv24.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv24.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv24.Tier
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv24.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv24.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '25' to '26' adds support for ListObjects limits, which use
+// the defaults after migration.
+func migrateV25ToV26() error {
+	configFile := getConfigFile()
+
+	cv25 := &serverConfigV25{}
+	_, err := quick.Load(configFile, cv25)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘25’. %v", err)
+	}
+	if cv25.Version != "25" {
+		return nil
+	}
+
+	// Copy over fields from V25 into V26 config struct
+	srvConfig := &serverConfigV26{
+		Logger: cv25.Logger,
+		Notify: cv25.Notify,
+	}
+	srvConfig.Version = "26"
+	srvConfig.Credential = cv25.Credential
+	srvConfig.Region = cv25.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv25.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv25.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv25.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv25.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv25.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv25.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv25.LDAP
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv25.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv25.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '26' to '27' adds support for bitrot protection settings,
+// which use the defaults after migration.
+func migrateV26ToV27() error {
+	configFile := getConfigFile()
+
+	cv26 := &serverConfigV26{}
+	_, err := quick.Load(configFile, cv26)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘26’. %v", err)
+	}
+	if cv26.Version != "26" {
+		return nil
+	}
+
+	// Copy over fields from V26 into V27 config struct
+	srvConfig := &serverConfigV27{
+		Logger: cv26.Logger,
+		Notify: cv26.Notify,
+	}
+	srvConfig.Version = "27"
+	srvConfig.Credential = cv26.Credential
+	srvConfig.Region = cv26.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv26.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv26.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv26.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv26.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv26.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv26.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv26.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv26.List
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv26.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv26.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '27' to '28' adds support for WORM mode, which is disabled
+// after migration.
+func migrateV27ToV28() error {
+	configFile := getConfigFile()
+
+	cv27 := &serverConfigV27{}
+	_, err := quick.Load(configFile, cv27)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘27’. %v", err)
+	}
+	if cv27.Version != "27" {
+		return nil
+	}
+
+	// Copy over fields from V27 into V28 config struct
+	srvConfig := &serverConfigV28{
+		Logger: cv27.Logger,
+		Notify: cv27.Notify,
+	}
+	srvConfig.Version = "28"
+	srvConfig.Credential = cv27.Credential
+	srvConfig.Region = cv27.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv27.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv27.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv27.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv27.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv27.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv27.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv27.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv27.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv27.Bitrot
+
+	// WORM mode is disabled.
+	srvConfig.Worm = wormOff
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv27.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv27.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '28' to '29' adds support for placement of buckets on groups
+// of XL disks, no bucket is pinned after migration.
+func migrateV28ToV29() error {
+	configFile := getConfigFile()
+
+	cv28 := &serverConfigV28{}
+	_, err := quick.Load(configFile, cv28)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘28’. %v", err)
+	}
+	if cv28.Version != "28" {
+		return nil
+	}
+
+	// Copy over fields from V28 into V29 config struct
+	srvConfig := &serverConfigV29{
+		Logger: cv28.Logger,
+		Notify: cv28.Notify,
+	}
+	srvConfig.Version = "29"
+	srvConfig.Credential = cv28.Credential
+	srvConfig.Region = cv28.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv28.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv28.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv28.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv28.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv28.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv28.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv28.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv28.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv28.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv28.Worm
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv28.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv28.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '29' to '30' adds support for storage classes on XL backend,
+// objects of every storage class keep the default parity after
+// migration.
+func migrateV29ToV30() error {
+	configFile := getConfigFile()
+
+	cv29 := &serverConfigV29{}
+	_, err := quick.Load(configFile, cv29)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘29’. %v", err)
+	}
+	if cv29.Version != "29" {
+		return nil
+	}
+
+	// Copy over fields from V29 into V30 config struct
+	srvConfig := &serverConfigV30{
+		Logger: cv29.Logger,
+		Notify: cv29.Notify,
+	}
+	srvConfig.Version = "30"
+	srvConfig.Credential = cv29.Credential
+	srvConfig.Region = cv29.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv29.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv29.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv29.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv29.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv29.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv29.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv29.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv29.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv29.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv29.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv29.Placement
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv29.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv29.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '30' to '31' adds support for worker pools of background
+// subsystems, pools keep their default size after migration.
+func migrateV30ToV31() error {
+	configFile := getConfigFile()
+
+	cv30 := &serverConfigV30{}
+	_, err := quick.Load(configFile, cv30)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘30’. %v", err)
+	}
+	if cv30.Version != "30" {
+		return nil
+	}
+
+	// Copy over fields from V30 into V31 config struct
+	srvConfig := &serverConfigV31{
+		Logger: cv30.Logger,
+		Notify: cv30.Notify,
+	}
+	srvConfig.Version = "31"
+	srvConfig.Credential = cv30.Credential
+	srvConfig.Region = cv30.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv30.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv30.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv30.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv30.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv30.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv30.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv30.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv30.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv30.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv30.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv30.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv30.StorageClass
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv30.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv30.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '31' to '32' adds support for federation of servers, no
+// bucket is federated after migration.
+func migrateV31ToV32() error {
+	configFile := getConfigFile()
+
+	cv31 := &serverConfigV31{}
+	_, err := quick.Load(configFile, cv31)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘31’. %v", err)
+	}
+	if cv31.Version != "31" {
+		return nil
+	}
+
+	// Copy over fields from V31 into V32 config struct
+	srvConfig := &serverConfigV32{
+		Logger: cv31.Logger,
+		Notify: cv31.Notify,
+	}
+	srvConfig.Version = "32"
+	srvConfig.Credential = cv31.Credential
+	srvConfig.Region = cv31.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv31.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv31.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv31.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv31.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv31.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv31.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv31.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv31.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv31.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv31.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv31.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv31.StorageClass
+
+	// Load workers config from existing config in the file.
+	srvConfig.Workers = cv31.Workers
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv31.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv31.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '32' to '33' adds support for requiring Content-MD5 on
+// uploads, it remains optional after migration.
+func migrateV32ToV33() error {
+	configFile := getConfigFile()
+
+	cv32 := &serverConfigV32{}
+	_, err := quick.Load(configFile, cv32)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘32’. %v", err)
+	}
+	if cv32.Version != "32" {
+		return nil
+	}
+
+	// Copy over fields from V32 into V33 config struct
+	srvConfig := &serverConfigV33{
+		Logger: cv32.Logger,
+		Notify: cv32.Notify,
+	}
+	srvConfig.Version = "33"
+	srvConfig.Credential = cv32.Credential
+	srvConfig.Region = cv32.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv32.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv32.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv32.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv32.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv32.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv32.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv32.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv32.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv32.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv32.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv32.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv32.StorageClass
+
+	// Load workers config from existing config in the file.
+	srvConfig.Workers = cv32.Workers
+
+	// Load federation config from existing config in the file.
+	srvConfig.Federation = cv32.Federation
+
+	// Uploads without Content-MD5 remain accepted.
+	srvConfig.ContentMD5 = contentMD5Optional
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv32.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv32.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '33' to '34' adds support for policy, raising the maximum
+// size of bucket policies.
+func migrateV33ToV34() error {
+	configFile := getConfigFile()
+
+	cv33 := &serverConfigV33{}
+	_, err := quick.Load(configFile, cv33)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘33’. %v", err)
+	}
+	if cv33.Version != "33" {
+		return nil
+	}
+
+	// Copy over fields from V33 into V34 config struct
+	srvConfig := &serverConfigV34{
+		Logger: cv33.Logger,
+		Notify: cv33.Notify,
+	}
+	srvConfig.Version = "34"
+	srvConfig.Credential = cv33.Credential
+	srvConfig.Region = cv33.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv33.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv33.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv33.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv33.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv33.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv33.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv33.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv33.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv33.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv33.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv33.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv33.StorageClass
+
+	// Load workers config from existing config in the file.
+	srvConfig.Workers = cv33.Workers
+
+	// Load federation config from existing config in the file.
+	srvConfig.Federation = cv33.Federation
+
+	// Load contentMD5 config from existing config in the file.
+	srvConfig.ContentMD5 = cv33.ContentMD5
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv33.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv33.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '34' to '35' adds support for a domain name for
+// virtual-host style bucket requests.
+func migrateV34ToV35() error {
+	configFile := getConfigFile()
+
+	cv34 := &serverConfigV34{}
+	_, err := quick.Load(configFile, cv34)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘34’. %v", err)
+	}
+	if cv34.Version != "34" {
+		return nil
+	}
+
+	// Copy over fields from V34 into V35 config struct
+	srvConfig := &serverConfigV35{
+		Logger: cv34.Logger,
+		Notify: cv34.Notify,
+	}
+	srvConfig.Version = "35"
+	srvConfig.Credential = cv34.Credential
+	srvConfig.Region = cv34.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv34.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv34.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv34.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv34.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv34.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv34.Tier
+
+	// Load ldap config from existing config in the file.
+	srvConfig.LDAP = cv34.LDAP
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv34.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv34.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv34.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv34.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv34.StorageClass
+
+	// Load workers config from existing config in the file.
+	srvConfig.Workers = cv34.Workers
+
+	// Load federation config from existing config in the file.
+	srvConfig.Federation = cv34.Federation
+
+	// Load contentMD5 config from existing config in the file.
+	srvConfig.ContentMD5 = cv34.ContentMD5
+
+	// Load policy config from existing config in the file.
+	srvConfig.Policy = cv34.Policy
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv34.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv34.Version, srvConfig.Version)
+	return nil
+}
+
+// Version '35' to '36' requires TLS to the LDAP server by default and
+// maps groups of LDAP users to session policies.
+func migrateV35ToV36() error {
+	configFile := getConfigFile()
+
+	cv35 := &serverConfigV35{}
+	_, err := quick.Load(configFile, cv35)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘35’. %v", err)
+	}
+	if cv35.Version != "35" {
+		return nil
+	}
+
+	// Copy over fields from V35 into V36 config struct
+	srvConfig := &serverConfigV36{
+		Logger: cv35.Logger,
+		Notify: cv35.Notify,
+	}
+	srvConfig.Version = "36"
+	srvConfig.Credential = cv35.Credential
+	srvConfig.Region = cv35.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Domain = cv35.Domain
+
+	// Load browser config from existing config in the file.
+	srvConfig.Browser = cv35.Browser
+
+	// Load lock config from existing config in the file.
+	srvConfig.DistLock = cv35.DistLock
+
+	// Load quota config from existing config in the file.
+	srvConfig.Quota = cv35.Quota
+
+	// Load rpc config from existing config in the file.
+	srvConfig.RPC = cv35.RPC
+
+	// Load multipart config from existing config in the file.
+	srvConfig.Multipart = cv35.Multipart
+
+	// Load tier config from existing config in the file.
+	srvConfig.Tier = cv35.Tier
+
+	// Convert ldap config, enabled servers connected to without TLS
+	// are still connected to without TLS, but explicitly. No group
+	// is mapped to a session policy, LDAP users get no temporary
+	// credentials until groups are added.
+	srvConfig.LDAP = ldapConfig{
+		Enable:           cv35.LDAP.Enable,
+		ServerAddr:       cv35.LDAP.ServerAddr,
+		UserDNFormat:     cv35.LDAP.UserDNFormat,
+		CredentialExpiry: cv35.LDAP.CredentialExpiry,
+	}
+	if cv35.LDAP.Enable && !cv35.LDAP.Secure {
+		srvConfig.LDAP.TLS = ldapTLSOff
+	}
+
+	// Load list config from existing config in the file.
+	srvConfig.List = cv35.List
+
+	// Load bitrot config from existing config in the file.
+	srvConfig.Bitrot = cv35.Bitrot
+
+	// Load worm config from existing config in the file.
+	srvConfig.Worm = cv35.Worm
+
+	// Load placement config from existing config in the file.
+	srvConfig.Placement = cv35.Placement
+
+	// Load storageclass config from existing config in the file.
+	srvConfig.StorageClass = cv35.StorageClass
+
+	// Load workers config from existing config in the file.
+	srvConfig.Workers = cv35.Workers
+
+	// Load federation config from existing config in the file.
+	srvConfig.Federation = cv35.Federation
+
+	// Load contentMD5 config from existing config in the file.
+	srvConfig.ContentMD5 = cv35.ContentMD5
+
+	// Load policy config from existing config in the file.
+	srvConfig.Policy = cv35.Policy
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv35.Version, srvConfig.Version, err)
+	}
+
+	log.Printf("Migration from version ‘%s’ to ‘%s’ completed successfully.\n", cv35.Version, srvConfig.Version)
+	return nil
+}
@@ -121,11 +121,65 @@ func TestServerConfigMigrateInexistentConfig(t *testing.T) {
 	if err := migrateV17ToV18(); err != nil {
 		t.Fatal("migrate v17 to v18 should succeed when no config file is found")
 	}
+	if err := migrateV18ToV19(); err != nil {
+		t.Fatal("migrate v18 to v19 should succeed when no config file is found")
+	}
+	if err := migrateV19ToV20(); err != nil {
+		t.Fatal("migrate v19 to v20 should succeed when no config file is found")
+	}
+	if err := migrateV20ToV21(); err != nil {
+		t.Fatal("migrate v20 to v21 should succeed when no config file is found")
+	}
+	if err := migrateV21ToV22(); err != nil {
+		t.Fatal("migrate v21 to v22 should succeed when no config file is found")
+	}
+	if err := migrateV22ToV23(); err != nil {
+		t.Fatal("migrate v22 to v23 should succeed when no config file is found")
+	}
+	if err := migrateV23ToV24(); err != nil {
+		t.Fatal("migrate v23 to v24 should succeed when no config file is found")
+	}
+	if err := migrateV24ToV25(); err != nil {
+		t.Fatal("migrate v24 to v25 should succeed when no config file is found")
+	}
+	if err := migrateV25ToV26(); err != nil {
+		t.Fatal("migrate v25 to v26 should succeed when no config file is found")
+	}
+	if err := migrateV26ToV27(); err != nil {
+		t.Fatal("migrate v26 to v27 should succeed when no config file is found")
+	}
+	if err := migrateV27ToV28(); err != nil {
+		t.Fatal("migrate v27 to v28 should succeed when no config file is found")
+	}
+	if err := migrateV28ToV29(); err != nil {
+		t.Fatal("migrate v28 to v29 should succeed when no config file is found")
+	}
+	if err := migrateV29ToV30(); err != nil {
+		t.Fatal("migrate v29 to v30 should succeed when no config file is found")
+	}
+	if err := migrateV30ToV31(); err != nil {
+		t.Fatal("migrate v30 to v31 should succeed when no config file is found")
+	}
+	if err := migrateV31ToV32(); err != nil {
+		t.Fatal("migrate v31 to v32 should succeed when no config file is found")
+	}
+	if err := migrateV32ToV33(); err != nil {
+		t.Fatal("migrate v32 to v33 should succeed when no config file is found")
+	}
+	if err := migrateV33ToV34(); err != nil {
+		t.Fatal("migrate v33 to v34 should succeed when no config file is found")
+	}
+	if err := migrateV34ToV35(); err != nil {
+		t.Fatal("migrate v34 to v35 should succeed when no config file is found")
+	}
+	if err := migrateV35ToV36(); err != nil {
+		t.Fatal("migrate v35 to v36 should succeed when no config file is found")
+	}
 
 }
 
-// Test if a config migration from v2 to v18 is successfully done
-func TestServerConfigMigrateV2toV18(t *testing.T) {
+// Test if a config migration from v2 to v36 is successfully done
+func TestServerConfigMigrateV2toV36(t *testing.T) {
 	rootPath, err := newTestConfig(globalMinioDefaultRegion)
 	if err != nil {
 		t.Fatalf("Init Test config failed")
@@ -164,7 +218,7 @@ func TestServerConfigMigrateV2toV18(t *testing.T) {
 	}
 
 	// Check the version number in the upgraded config file
-	expectedVersion := v18
+	expectedVersion := v36
 	if serverConfig.Version != expectedVersion {
 		t.Fatalf("Expect version "+expectedVersion+", found: %v", serverConfig.Version)
 	}
@@ -244,4 +298,58 @@ func TestServerConfigMigrateFaultyConfig(t *testing.T) {
 	if err := migrateV17ToV18(); err == nil {
 		t.Fatal("migrateConfigV17ToV18() should fail with a corrupted json")
 	}
+	if err := migrateV18ToV19(); err == nil {
+		t.Fatal("migrateConfigV18ToV19() should fail with a corrupted json")
+	}
+	if err := migrateV19ToV20(); err == nil {
+		t.Fatal("migrateConfigV19ToV20() should fail with a corrupted json")
+	}
+	if err := migrateV20ToV21(); err == nil {
+		t.Fatal("migrateConfigV20ToV21() should fail with a corrupted json")
+	}
+	if err := migrateV21ToV22(); err == nil {
+		t.Fatal("migrateConfigV21ToV22() should fail with a corrupted json")
+	}
+	if err := migrateV22ToV23(); err == nil {
+		t.Fatal("migrateConfigV22ToV23() should fail with a corrupted json")
+	}
+	if err := migrateV23ToV24(); err == nil {
+		t.Fatal("migrateConfigV23ToV24() should fail with a corrupted json")
+	}
+	if err := migrateV24ToV25(); err == nil {
+		t.Fatal("migrateConfigV24ToV25() should fail with a corrupted json")
+	}
+	if err := migrateV25ToV26(); err == nil {
+		t.Fatal("migrateConfigV25ToV26() should fail with a corrupted json")
+	}
+	if err := migrateV26ToV27(); err == nil {
+		t.Fatal("migrateConfigV26ToV27() should fail with a corrupted json")
+	}
+	if err := migrateV27ToV28(); err == nil {
+		t.Fatal("migrateConfigV27ToV28() should fail with a corrupted json")
+	}
+	if err := migrateV28ToV29(); err == nil {
+		t.Fatal("migrateConfigV28ToV29() should fail with a corrupted json")
+	}
+	if err := migrateV29ToV30(); err == nil {
+		t.Fatal("migrateConfigV29ToV30() should fail with a corrupted json")
+	}
+	if err := migrateV30ToV31(); err == nil {
+		t.Fatal("migrateConfigV30ToV31() should fail with a corrupted json")
+	}
+	if err := migrateV31ToV32(); err == nil {
+		t.Fatal("migrateConfigV31ToV32() should fail with a corrupted json")
+	}
+	if err := migrateV32ToV33(); err == nil {
+		t.Fatal("migrateConfigV32ToV33() should fail with a corrupted json")
+	}
+	if err := migrateV33ToV34(); err == nil {
+		t.Fatal("migrateConfigV33ToV34() should fail with a corrupted json")
+	}
+	if err := migrateV34ToV35(); err == nil {
+		t.Fatal("migrateConfigV34ToV35() should fail with a corrupted json")
+	}
+	if err := migrateV35ToV36(); err == nil {
+		t.Fatal("migrateConfigV35ToV36() should fail with a corrupted json")
+	}
 }
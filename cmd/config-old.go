@@ -449,3 +449,770 @@ type serverConfigV17 struct {
 	// Notification queue configuration.
 	Notify *notifier `json:"notify"`
 }
+
+// serverConfigV18 server configuration version '18' which is like
+// version '17' except it adds support for "deliveryMode" parameter in
+// the AMQP notification target.
+type serverConfigV18 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+}
+
+// serverConfigV19 server configuration version '19' which is like
+// version '18' except it adds support for "lock" parameters to tune
+// distributed locking.
+type serverConfigV19 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+}
+
+// serverConfigV20 server configuration version '20' which is like
+// version '19' except it adds support for "quota" limits per access
+// key.
+type serverConfigV20 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+}
+
+// serverConfigV21 server configuration version '21' which is like
+// version '20' except it adds support for "rpc" parameters to enable
+// mutual TLS between distributed nodes.
+type serverConfigV21 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+}
+
+// serverConfigV22 server configuration version '22' which is like
+// version '21' except it adds support for "streams" in "logger" to
+// route startup, warning, audit and debug log streams independently.
+type serverConfigV22 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+}
+
+// serverConfigV23 server configuration version '23' which is like
+// version '22' except it adds support for "multipart" parameters to
+// configure minimum part size and maximum parts per upload.
+type serverConfigV23 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+}
+
+// serverConfigV24 server configuration version '24' which is like
+// version '23' except it adds support for "tier" parameters to
+// configure a remote tier objects are transitioned to.
+type serverConfigV24 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+}
+
+// serverConfigV25 server configuration version '25' which is like
+// version '24' except it adds support for "ldap" parameters to
+// exchange LDAP credentials for temporary access credentials.
+type serverConfigV25 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+}
+
+// serverConfigV26 server configuration version '26' which is like
+// version '25' except it adds support for "list" parameters to limit
+// ListObjects page size and concurrent directory reads.
+type serverConfigV26 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+}
+
+// serverConfigV27 server configuration version '27' which is like
+// version '26' except it adds support for "bitrot" parameters to
+// choose the checksum algorithm of XL shards and their verification
+// on read.
+type serverConfigV27 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+}
+
+// serverConfigV28 server configuration version '28' which is like
+// version '27' except it adds support for "worm" mode, rejecting
+// overwrites and deletes of existing objects.
+type serverConfigV28 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+}
+
+// serverConfigV29 server configuration version '29' which is like
+// version '28' except it adds support for "placement" of buckets on
+// groups of XL disks.
+type serverConfigV29 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+}
+
+// serverConfigV30 server configuration version '30' which is like
+// version '29' except it adds support for "storageclass", the parity
+// of objects of every storage class on XL backend.
+type serverConfigV30 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+}
+
+// serverConfigV31 server configuration version '31' which is like
+// version '30' except it adds support for "workers", the size of the
+// worker pools of background subsystems.
+type serverConfigV31 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+}
+
+// serverConfigV32 server configuration version '32' which is like
+// version '31' except it adds support for "federation", the buckets
+// served by other servers sharing one namespace with this server.
+type serverConfigV32 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+
+	// Buckets served by other servers of the federation.
+	Federation federationConfig `json:"federation"`
+}
+
+// serverConfigV33 server configuration version '33' which is like
+// version '32' except it adds support for "contentMD5", requiring
+// Content-MD5 on all uploads.
+type serverConfigV33 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+
+	// Buckets served by other servers of the federation.
+	Federation federationConfig `json:"federation"`
+
+	// Content-MD5 requirement of uploads.
+	ContentMD5 contentMD5Flag `json:"contentMD5"`
+}
+
+// serverConfigV34 server configuration version '34' which is like
+// version '33' except it adds support for "policy", raising the
+// maximum size of bucket policies.
+type serverConfigV34 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+
+	// Buckets served by other servers of the federation.
+	Federation federationConfig `json:"federation"`
+
+	// Content-MD5 requirement of uploads.
+	ContentMD5 contentMD5Flag `json:"contentMD5"`
+
+	// Bucket policy limits.
+	Policy policyConfig `json:"policy"`
+}
+
+// ldapConfigV1 - structure was valid until config V 35
+type ldapConfigV1 struct {
+	Enable           bool   `json:"enable"`
+	ServerAddr       string `json:"serverAddr"`
+	Secure           bool   `json:"secure"`
+	UserDNFormat     string `json:"userDNFormat"`
+	CredentialExpiry string `json:"credentialExpiry"`
+}
+
+// serverConfigV35 server configuration version '35' which is like
+// version '34' except it adds support for "domain", enabling
+// virtual-host style bucket requests.
+type serverConfigV35 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential  `json:"credential"`
+	Region     string      `json:"region"`
+	Domain     string      `json:"domain"`
+	Browser    BrowserFlag `json:"browser"`
+
+	// Additional error logging configuration.
+	Logger *loggers `json:"logger"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfigV1 `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+
+	// Buckets served by other servers of the federation.
+	Federation federationConfig `json:"federation"`
+
+	// Content-MD5 requirement of uploads.
+	ContentMD5 contentMD5Flag `json:"contentMD5"`
+
+	// Bucket policy limits.
+	Policy policyConfig `json:"policy"`
+}
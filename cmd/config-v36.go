@@ -27,24 +27,25 @@ import (
 )
 
 // Config version
-const v18 = "18"
+const v36 = "36"
 
 var (
 	// serverConfig server config.
-	serverConfig   *serverConfigV18
+	serverConfig   *serverConfigV36
 	serverConfigMu sync.RWMutex
 )
 
-// serverConfigV18 server configuration version '18' which is like
-// version '17' except it adds support for "deliveryMode" parameter in
-// the AMQP notification target.
-type serverConfigV18 struct {
+// serverConfigV36 server configuration version '36' which is like
+// version '35' except "ldap" requires TLS by default and maps groups
+// of LDAP users to session policies.
+type serverConfigV36 struct {
 	sync.RWMutex
 	Version string `json:"version"`
 
 	// S3 API configuration.
 	Credential credential  `json:"credential"`
 	Region     string      `json:"region"`
+	Domain     string      `json:"domain"`
 	Browser    BrowserFlag `json:"browser"`
 
 	// Additional error logging configuration.
@@ -52,10 +53,55 @@ type serverConfigV18 struct {
 
 	// Notification queue configuration.
 	Notify *notifier `json:"notify"`
+
+	// Distributed locking configuration.
+	DistLock lockConfig `json:"lock"`
+
+	// Request and bandwidth quotas per access key.
+	Quota quotaConfig `json:"quota"`
+
+	// Inter-node RPC configuration.
+	RPC rpcConfig `json:"rpc"`
+
+	// Multipart upload limits.
+	Multipart multipartConfig `json:"multipart"`
+
+	// Remote tier for lifecycle transitions.
+	Tier tierConfig `json:"tier"`
+
+	// LDAP identity provider of temporary credentials.
+	LDAP ldapConfig `json:"ldap"`
+
+	// ListObjects limits.
+	List listConfig `json:"list"`
+
+	// Bit-rot protection of XL shards.
+	Bitrot bitrotConfig `json:"bitrot"`
+
+	// Write-Once-Read-Many mode of all buckets.
+	Worm wormFlag `json:"worm"`
+
+	// Buckets pinned to groups of XL disks.
+	Placement placementConfig `json:"placement"`
+
+	// Parity of objects of every storage class on XL backend.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Worker pools of background subsystems.
+	Workers workersConfig `json:"workers"`
+
+	// Buckets served by other servers of the federation.
+	Federation federationConfig `json:"federation"`
+
+	// Content-MD5 requirement of uploads.
+	ContentMD5 contentMD5Flag `json:"contentMD5"`
+
+	// Bucket policy limits.
+	Policy policyConfig `json:"policy"`
 }
 
 // GetVersion get current config version.
-func (s *serverConfigV18) GetVersion() string {
+func (s *serverConfigV36) GetVersion() string {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -63,7 +109,7 @@ func (s *serverConfigV18) GetVersion() string {
 }
 
 // SetRegion set new region.
-func (s *serverConfigV18) SetRegion(region string) {
+func (s *serverConfigV36) SetRegion(region string) {
 	s.Lock()
 	defer s.Unlock()
 
@@ -71,15 +117,31 @@ func (s *serverConfigV18) SetRegion(region string) {
 }
 
 // GetRegion get current region.
-func (s *serverConfigV18) GetRegion() string {
+func (s *serverConfigV36) GetRegion() string {
 	s.RLock()
 	defer s.RUnlock()
 
 	return s.Region
 }
 
+// SetDomain set new domain name.
+func (s *serverConfigV36) SetDomain(domain string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.Domain = domain
+}
+
+// GetDomain get current domain name.
+func (s *serverConfigV36) GetDomain() string {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Domain
+}
+
 // SetCredentials set new credentials.
-func (s *serverConfigV18) SetCredential(creds credential) {
+func (s *serverConfigV36) SetCredential(creds credential) {
 	s.Lock()
 	defer s.Unlock()
 
@@ -88,7 +150,7 @@ func (s *serverConfigV18) SetCredential(creds credential) {
 }
 
 // GetCredentials get current credentials.
-func (s *serverConfigV18) GetCredential() credential {
+func (s *serverConfigV36) GetCredential() credential {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -96,7 +158,7 @@ func (s *serverConfigV18) GetCredential() credential {
 }
 
 // SetBrowser set if browser is enabled.
-func (s *serverConfigV18) SetBrowser(b bool) {
+func (s *serverConfigV36) SetBrowser(b bool) {
 	s.Lock()
 	defer s.Unlock()
 
@@ -105,15 +167,135 @@ func (s *serverConfigV18) SetBrowser(b bool) {
 }
 
 // GetCredentials get current credentials.
-func (s *serverConfigV18) GetBrowser() bool {
+func (s *serverConfigV36) GetBrowser() bool {
 	s.RLock()
 	defer s.RUnlock()
 
 	return bool(s.Browser)
 }
 
+// GetDistLock get current distributed locking config.
+func (s *serverConfigV36) GetDistLock() lockConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.DistLock
+}
+
+// GetQuota get current quota config.
+func (s *serverConfigV36) GetQuota() quotaConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Quota
+}
+
+// GetRPC get current inter-node RPC config.
+func (s *serverConfigV36) GetRPC() rpcConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.RPC
+}
+
+// GetMultipart get current multipart upload limits.
+func (s *serverConfigV36) GetMultipart() multipartConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Multipart
+}
+
+// GetTier get current remote tier config.
+func (s *serverConfigV36) GetTier() tierConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Tier
+}
+
+// GetLDAP get current LDAP identity provider config.
+func (s *serverConfigV36) GetLDAP() ldapConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.LDAP
+}
+
+// GetList get current ListObjects limits.
+func (s *serverConfigV36) GetList() listConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.List
+}
+
+// GetBitrot get current bit-rot protection config.
+func (s *serverConfigV36) GetBitrot() bitrotConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Bitrot
+}
+
+// GetWorm get if WORM mode is enabled for all buckets.
+func (s *serverConfigV36) GetWorm() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Worm == wormOn
+}
+
+// GetPlacement get current bucket placement config.
+func (s *serverConfigV36) GetPlacement() placementConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Placement
+}
+
+// GetStorageClass get current storage class config.
+func (s *serverConfigV36) GetStorageClass() storageClassConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.StorageClass
+}
+
+// GetWorkers get current worker pools config.
+func (s *serverConfigV36) GetWorkers() workersConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Workers
+}
+
+// GetFederation get current federation config.
+func (s *serverConfigV36) GetFederation() federationConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Federation
+}
+
+// IsContentMD5Required get if uploads must carry Content-MD5.
+func (s *serverConfigV36) IsContentMD5Required() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.ContentMD5 == contentMD5Required
+}
+
+// GetPolicy get current bucket policy config.
+func (s *serverConfigV36) GetPolicy() policyConfig {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.Policy
+}
+
 // Save config.
-func (s *serverConfigV18) Save() error {
+func (s *serverConfigV36) Save() error {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -121,12 +303,14 @@ func (s *serverConfigV18) Save() error {
 	return quick.Save(getConfigFile(), s)
 }
 
-func newServerConfigV18() *serverConfigV18 {
-	srvCfg := &serverConfigV18{
-		Version:    v18,
+func newServerConfigV36() *serverConfigV36 {
+	srvCfg := &serverConfigV36{
+		Version:    v36,
 		Credential: mustGetNewCredential(),
 		Region:     globalMinioDefaultRegion,
 		Browser:    true,
+		Worm:       wormOff,
+		ContentMD5: contentMD5Optional,
 		Logger:     &loggers{},
 		Notify:     &notifier{},
 	}
@@ -159,7 +343,7 @@ func newServerConfigV18() *serverConfigV18 {
 // found, otherwise use default parameters
 func newConfig() error {
 	// Initialize server config.
-	srvCfg := newServerConfigV18()
+	srvCfg := newServerConfigV36()
 
 	// If env is set override the credentials from config file.
 	if globalIsEnvCreds {
@@ -174,6 +358,10 @@ func newConfig() error {
 		srvCfg.SetRegion(globalServerRegion)
 	}
 
+	if globalIsEnvDomain {
+		srvCfg.SetDomain(globalDomainName)
+	}
+
 	// hold the mutex lock before a new config is assigned.
 	// Save the new config globally.
 	// unlock the mutex.
@@ -237,8 +425,8 @@ func checkDupJSONKeys(json string) error {
 }
 
 // getValidConfig - returns valid server configuration
-func getValidConfig() (*serverConfigV18, error) {
-	srvCfg := &serverConfigV18{
+func getValidConfig() (*serverConfigV36, error) {
+	srvCfg := &serverConfigV36{
 		Region:  globalMinioDefaultRegion,
 		Browser: true,
 	}
@@ -248,8 +436,8 @@ func getValidConfig() (*serverConfigV18, error) {
 		return nil, err
 	}
 
-	if srvCfg.Version != v18 {
-		return nil, fmt.Errorf("configuration version mismatch. Expected: ‘%s’, Got: ‘%s’", v18, srvCfg.Version)
+	if srvCfg.Version != v36 {
+		return nil, fmt.Errorf("configuration version mismatch. Expected: ‘%s’, Got: ‘%s’", v36, srvCfg.Version)
 	}
 
 	// Load config file json and check for duplication json keys
@@ -266,6 +454,11 @@ func getValidConfig() (*serverConfigV18, error) {
 		return nil, errors.New("Region config value cannot be empty")
 	}
 
+	// Validate domain field, empty when not configured.
+	if srvCfg.Domain != "" && !isValidDomain(srvCfg.Domain) {
+		return nil, fmt.Errorf("Invalid domain name ‘%s’ in config", srvCfg.Domain)
+	}
+
 	// Validate credential fields only when
 	// they are not set via the environment
 
@@ -284,6 +477,76 @@ func getValidConfig() (*serverConfigV18, error) {
 		return nil, err
 	}
 
+	// Validate lock field
+	if err = srvCfg.DistLock.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate quota field
+	if err = srvCfg.Quota.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate multipart field
+	if err = srvCfg.Multipart.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate tier field
+	if err = srvCfg.Tier.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate ldap field
+	if err = srvCfg.LDAP.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate list field
+	if err = srvCfg.List.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate bitrot field
+	if err = srvCfg.Bitrot.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate worm field
+	if err = srvCfg.Worm.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate placement field
+	if err = srvCfg.Placement.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate storageclass field
+	if err = srvCfg.StorageClass.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate workers field
+	if err = srvCfg.Workers.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate federation field
+	if err = srvCfg.Federation.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate contentMD5 field
+	if err = srvCfg.ContentMD5.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate policy field
+	if err = srvCfg.Policy.Validate(); err != nil {
+		return nil, err
+	}
+
 	return srvCfg, nil
 }
 
@@ -308,6 +571,10 @@ func loadConfig() error {
 		srvCfg.SetRegion(globalServerRegion)
 	}
 
+	if globalIsEnvDomain {
+		srvCfg.SetDomain(globalDomainName)
+	}
+
 	// hold the mutex lock before a new config is assigned.
 	serverConfigMu.Lock()
 	serverConfig = srvCfg
@@ -320,6 +587,9 @@ func loadConfig() error {
 	if !globalIsEnvRegion {
 		globalServerRegion = serverConfig.GetRegion()
 	}
+	if !globalIsEnvDomain {
+		globalDomainName = serverConfig.GetDomain()
+	}
 	serverConfigMu.Unlock()
 
 	return nil
@@ -109,8 +109,8 @@ func TestServerConfig(t *testing.T) {
 	serverConfig.Logger.SetFile(fileLogger)
 
 	// Match version.
-	if serverConfig.GetVersion() != v18 {
-		t.Errorf("Expecting version %s found %s", serverConfig.GetVersion(), v18)
+	if serverConfig.GetVersion() != v36 {
+		t.Errorf("Expecting version %s found %s", serverConfig.GetVersion(), v36)
 	}
 
 	// Attempt to save.
@@ -223,7 +223,7 @@ func TestValidateConfig(t *testing.T) {
 
 	configPath := filepath.Join(rootPath, minioConfigFile)
 
-	v := v18
+	v := v36
 
 	testCases := []struct {
 		configData string
@@ -309,6 +309,117 @@ func TestValidateConfig(t *testing.T) {
 
 		// Test 28 - Test valid Format for Redis
 		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "notify": { "redis": { "1": { "enable": true, "format": "namespace", "address": "example.com:80", "password": "xxx", "key": "key1" } }}}`, true},
+
+		// Test 29 - Test invalid lock acquire timeout
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "lock": { "acquireTimeout": "1" }}`, false},
+
+		// Test 30 - Test valid lock config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "lock": { "acquireTimeout": "2s", "retryUnit": "500ms", "retryCap": "5s", "quorum": 3 }}`, true},
+
+		// Test 31 - Test enabled tier without bucket
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "tier": { "enable": true, "endpoint": "s3.amazonaws.com" }}`, false},
+
+		// Test 32 - Test invalid tier getMode
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "tier": { "enable": true, "endpoint": "s3.amazonaws.com", "bucket": "archive", "getMode": "proxy" }}`, false},
+
+		// Test 33 - Test valid tier config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "tier": { "enable": true, "endpoint": "s3.amazonaws.com", "accessKey": "access", "secretKey": "secret", "secure": true, "bucket": "archive", "getMode": "redirect" }}`, true},
+
+		// Test 34 - Test enabled LDAP without userDNFormat
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:389" }}`, false},
+
+		// Test 35 - Test invalid LDAP credentialExpiry
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:389", "userDNFormat": "uid=%s,dc=example,dc=com", "credentialExpiry": "1y" }}`, false},
+
+		// Test 36 - Test valid LDAP config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:636", "tls": "on", "userDNFormat": "uid=%s,dc=example,dc=com", "credentialExpiry": "12h" }}`, true},
+
+		// Test 37 - Test list maxKeys above the S3 limit
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "list": { "maxKeys": 1001 }}`, false},
+
+		// Test 38 - Test negative list maxConcurrentDirReads
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "list": { "maxConcurrentDirReads": -1 }}`, false},
+
+		// Test 39 - Test valid list config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "list": { "maxKeys": 100, "maxConcurrentDirReads": 16 }}`, true},
+
+		// Test 40 - Test invalid bitrot algorithm
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "bitrot": { "algorithm": "md5" }}`, false},
+
+		// Test 41 - Test invalid bitrot verify mode
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "bitrot": { "verify": "always" }}`, false},
+
+		// Test 42 - Test valid bitrot config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "bitrot": { "algorithm": "highwayhash", "verify": "heal" }}`, true},
+
+		// Test 43 - Test invalid worm flag
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "worm": "yes"}`, false},
+
+		// Test 44 - Test valid worm flag
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "worm": "on"}`, true},
+
+		// Test 45 - Test placement with unknown group
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "placement": {"groups": {"ssd": ["/mnt/ssd1", "/mnt/ssd2", "/mnt/ssd3", "/mnt/ssd4"]}, "buckets": {"hot": "hdd"}}}`, false},
+
+		// Test 46 - Test valid placement
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "placement": {"groups": {"ssd": ["/mnt/ssd1", "/mnt/ssd2", "/mnt/ssd3", "/mnt/ssd4"]}, "buckets": {"hot": "ssd"}}}`, true},
+
+		// Test 47 - Test invalid storage class parity
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "storageclass": {"standard": "EC:1"}}`, false},
+
+		// Test 48 - Test reduced redundancy parity above standard parity
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "storageclass": {"standard": "EC:4", "rrs": "EC:6"}}`, false},
+
+		// Test 49 - Test valid storage class config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "storageclass": {"standard": "EC:8", "rrs": "EC:4"}}`, true},
+
+		// Test 50 - Test negative number of workers
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "workers": {"heal": {"workers": -1}}}`, false},
+
+		// Test 51 - Test negative queue size
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "workers": {"notification": {"queueSize": -1}}}`, false},
+
+		// Test 52 - Test valid workers config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "workers": {"notification": {"workers": 16, "queueSize": 50000}, "lifecycle": {"workers": 1}}}`, true},
+
+		// Test 53 - Test invalid federation mode
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "federation": {"mode": "forward"}}`, false},
+
+		// Test 54 - Test invalid owner of federated bucket
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "federation": {"buckets": {"photos": "10.0.0.2:9000"}}}`, false},
+
+		// Test 55 - Test valid federation config
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "federation": {"mode": "redirect", "buckets": {"photos": "http://10.0.0.2:9000"}}}`, true},
+
+		// Test 56 - Test invalid contentMD5 flag
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "contentMD5": "on"}`, false},
+
+		// Test 57 - Test valid contentMD5 flag
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "contentMD5": "required"}`, true},
+
+		// Test 58 - Test policy maximum size above the limit
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "policy": {"maxSize": 1073741824}}`, false},
+
+		// Test 59 - Test negative policy maximum size
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "policy": {"maxSize": -1}}`, false},
+
+		// Test 60 - Test valid policy maximum size
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "policy": {"maxSize": 102400}}`, true},
+
+		// Test 61 - Test invalid domain name
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "domain": "http://minio.example.com"}`, false},
+
+		// Test 62 - Test valid domain name
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "domain": "minio.example.com"}`, true},
+
+		// Test 63 - Test invalid LDAP tls
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:389", "tls": "true", "userDNFormat": "uid=%s,dc=example,dc=com" }}`, false},
+
+		// Test 64 - Test invalid LDAP group policy
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:636", "userDNFormat": "uid=%s,dc=example,dc=com", "groupPolicies": { "cn=admins,dc=example,dc=com": { "Version": "2012-10-17", "Statement": [ { "Effect": "Allow", "Principal": "*", "Action": [ "s3:*" ], "Resource": [ "arn:aws:s3:::*" ] } ] } } }}`, false},
+
+		// Test 65 - Test valid LDAP group policy
+		{`{"version": "` + v + `", "credential": { "accessKey": "minio", "secretKey": "minio123" }, "region": "us-east-1", "browser": "on", "ldap": { "enable": true, "serverAddr": "ldap.example.com:389", "tls": "starttls", "userDNFormat": "uid=%s,dc=example,dc=com", "groupMemberAttribute": "uniqueMember", "groupPolicies": { "cn=admins,dc=example,dc=com": { "Version": "2012-10-17", "Statement": [ { "Effect": "Allow", "Action": [ "s3:*" ], "Resource": [ "arn:aws:s3:::*" ] } ] } } }}`, true},
 	}
 
 	for i, testCase := range testCases {
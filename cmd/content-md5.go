@@ -0,0 +1,59 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// contentMD5Flag - whether PutObject and PutObjectPart requests must
+// carry a Content-MD5 header, "required" or "optional".
+type contentMD5Flag string
+
+const (
+	contentMD5Required contentMD5Flag = "required"
+	contentMD5Optional contentMD5Flag = "optional"
+)
+
+// Validate - validates contentMD5 flag.
+func (f contentMD5Flag) Validate() error {
+	switch f {
+	case "", contentMD5Required, contentMD5Optional:
+		return nil
+	}
+	return fmt.Errorf("Invalid contentMD5 value ‘%s’, must be %s or %s", f, contentMD5Required, contentMD5Optional)
+}
+
+// getContentMD5 - returns Content-MD5 of an upload request, nil when
+// the request has none. Uploads without Content-MD5 are rejected when
+// the server requires it.
+func getContentMD5(r *http.Request) ([]byte, APIErrorCode) {
+	values, ok := r.Header["Content-Md5"]
+	if !ok {
+		if serverConfig.IsContentMD5Required() {
+			return nil, ErrMissingContentMD5
+		}
+		return nil, ErrNone
+	}
+	md5Bytes, err := checkValidMD5(values[0])
+	if err != nil {
+		reqErrorIf(r, err, "Unable to validate content-md5 format.")
+		return nil, ErrInvalidDigest
+	}
+	return md5Bytes, ErrNone
+}
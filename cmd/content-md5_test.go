@@ -0,0 +1,76 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+// Tests Content-MD5 of upload requests.
+func TestGetContentMD5(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	// MD5 of "hello".
+	helloMD5 := []byte{0x5d, 0x41, 0x40, 0x2a, 0xbc, 0x4b, 0x2a, 0x76, 0xb9, 0x71, 0x9d, 0x91, 0x10, 0x17, 0xc5, 0x92}
+
+	testCases := []struct {
+		contentMD5   []string
+		required     bool
+		expectedMD5  []byte
+		expectedCode APIErrorCode
+	}{
+		// Test 1 - no Content-MD5.
+		{nil, false, nil, ErrNone},
+		// Test 2 - no Content-MD5 when it is required.
+		{nil, true, nil, ErrMissingContentMD5},
+		// Test 3 - valid Content-MD5.
+		{[]string{"XUFAKrxLKna5cZ2REBfFkg=="}, true, helloMD5, ErrNone},
+		// Test 4 - empty Content-MD5.
+		{[]string{""}, false, nil, ErrInvalidDigest},
+		// Test 5 - Content-MD5 not base64 encoded.
+		{[]string{"5d41402abc4b2a76b9719d911017c592"}, false, nil, ErrInvalidDigest},
+		// Test 6 - Content-MD5 of wrong size.
+		{[]string{"aGVsbG8="}, false, nil, ErrInvalidDigest},
+	}
+
+	for i, testCase := range testCases {
+		serverConfig.ContentMD5 = contentMD5Optional
+		if testCase.required {
+			serverConfig.ContentMD5 = contentMD5Required
+		}
+		req, err := http.NewRequest("PUT", "http://127.0.0.1:9000/bucket/object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.contentMD5 != nil {
+			req.Header["Content-Md5"] = testCase.contentMD5
+		}
+		md5Bytes, errCode := getContentMD5(req)
+		if errCode != testCase.expectedCode {
+			t.Errorf("Test %d: expected error %d, got %d", i+1, testCase.expectedCode, errCode)
+		}
+		if !bytes.Equal(md5Bytes, testCase.expectedMD5) {
+			t.Errorf("Test %d: expected md5 %x, got %x", i+1, testCase.expectedMD5, md5Bytes)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Help template shared by control sub-commands.
+const controlCmdHelpTemplate = `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [ENDPOINT]
+
+ENDPOINT:
+  Server to send the command to, defaults to http://localhost:9000
+
+ENVIRONMENT VARIABLES:
+  ACCESS:
+     MINIO_ACCESS_KEY: Username or access key of the server.
+     MINIO_SECRET_KEY: Password or secret key of the server.
+
+EXAMPLES:
+  1. Send the command to a local server.
+      $ export MINIO_ACCESS_KEY=minio
+      $ export MINIO_SECRET_KEY=miniostorage
+      $ {{.HelpName}}
+
+  2. Send the command to a remote server, in a distributed setup all servers receive it.
+      $ {{.HelpName}} https://play.minio.io:9000
+`
+
+var controlCmd = cli.Command{
+	Name:  "control",
+	Usage: "Control a server or migrate its data.",
+	Subcommands: []cli.Command{
+		{
+			Name:               "shutdown",
+			Usage:              "Gracefully shut down a running server.",
+			Action:             mainControlShutdown,
+			CustomHelpTemplate: controlCmdHelpTemplate,
+		},
+		{
+			Name:               "restart",
+			Usage:              "Gracefully restart a running server.",
+			Action:             mainControlRestart,
+			CustomHelpTemplate: controlCmdHelpTemplate,
+		},
+		controlMigrateCmd,
+	},
+}
+
+// newControlAdminClient - returns admin client of the server passed
+// to a control sub-command, along with the endpoint argument.
+func newControlAdminClient(ctx *cli.Context, name string) (*madmin.AdminClient, string) {
+	if len(ctx.Args()) > 1 || ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, name, 1)
+	}
+
+	endpointArg := "http://localhost:9000"
+	if ctx.Args().Present() {
+		endpointArg = ctx.Args().First()
+	}
+	endpoint, secure, err := parseGatewayEndpoint(endpointArg)
+	fatalIf(err, "Unable to parse endpoint %s", endpointArg)
+
+	accessKey := os.Getenv("MINIO_ACCESS_KEY")
+	secretKey := os.Getenv("MINIO_SECRET_KEY")
+	if accessKey == "" || secretKey == "" {
+		fatalIf(errors.New("Missing credentials"), "Access and secret keys are mandatory to control a server.")
+	}
+
+	client, err := madmin.New(endpoint, accessKey, secretKey, secure)
+	fatalIf(err, "Unable to initialize admin client for %s", endpointArg)
+	return client, endpointArg
+}
+
+// Handler for 'minio control shutdown'.
+func mainControlShutdown(ctx *cli.Context) {
+	client, endpointArg := newControlAdminClient(ctx, "shutdown")
+	fatalIf(client.ServiceStop(), "Unable to shut down %s", endpointArg)
+	fmt.Printf("Shutdown of %s requested.\n", endpointArg)
+}
+
+// Handler for 'minio control restart'.
+func mainControlRestart(ctx *cli.Context) {
+	client, endpointArg := newControlAdminClient(ctx, "restart")
+	fatalIf(client.ServiceRestart(), "Unable to restart %s", endpointArg)
+	fmt.Printf("Restart of %s requested.\n", endpointArg)
+}
@@ -0,0 +1,292 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+)
+
+var controlMigrateCmd = cli.Command{
+	Name:  "migrate",
+	Usage: "Copy data of a stopped server to another backend.",
+	Subcommands: []cli.Command{
+		{
+			Name:   "fs-to-xl",
+			Usage:  "Copy all buckets and objects of an FS backend to a new XL backend.",
+			Action: mainControlMigrateFSToXL,
+			CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} FS-DIR DIR1 DIR2 [DIR3...]
+
+FS-DIR:
+  Directory of the FS backend, the server using it must be stopped. It is
+  only read, data is copied and not converted in place.
+
+DIR:
+  Directories of the XL backend, 4 to 16 local directories as for minio server,
+  none of them can be FS-DIR.
+
+EXAMPLES:
+  1. Migrate /mnt/data to a new XL backend on 4 disks, then start the server on them.
+      $ {{.HelpName}} /mnt/data /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/
+      $ minio server /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/
+`,
+		},
+	},
+}
+
+const (
+	// Progress of migrating an FS backend, kept on the XL backend
+	// so that an interrupted migration resumes where it stopped.
+	fsToXLMigrationConfig = "fs-to-xl-migration.json"
+
+	// Number of objects migrated between saving progress.
+	fsToXLMigrationSaveInterval = 100
+)
+
+// fsToXLMigration - progress of migrating an FS backend to XL,
+// buckets and objects are migrated in lexical order.
+type fsToXLMigration struct {
+	// Bucket being migrated and the last object of it migrated.
+	Bucket string `json:"bucket"`
+	Marker string `json:"marker"`
+	Done   bool   `json:"done"`
+
+	// Migrated objects and their total size.
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+// readFSToXLMigration - reads progress of the migration from the XL
+// backend, a new migration has no progress yet.
+func readFSToXLMigration(xl ObjectLayer) (m fsToXLMigration, err error) {
+	var buffer bytes.Buffer
+	if err = xl.GetObject(minioMetaBucket, fsToXLMigrationConfig, 0, -1, &buffer); err != nil {
+		if isErrObjectNotFound(err) {
+			return m, nil
+		}
+		return m, err
+	}
+	err = json.Unmarshal(buffer.Bytes(), &m)
+	return m, err
+}
+
+// saveFSToXLMigration - saves progress of the migration to the XL backend.
+func saveFSToXLMigration(xl ObjectLayer, m fsToXLMigration) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = xl.PutObject(minioMetaBucket, fsToXLMigrationConfig, int64(len(data)), bytes.NewReader(data), nil, "")
+	return err
+}
+
+// isContentMD5 - returns true if the ETag is the md5sum of object
+// content, which is not the case for objects uploaded in parts.
+func isContentMD5(etag string) bool {
+	md5Bytes, err := hex.DecodeString(etag)
+	return err == nil && len(md5Bytes) == md5.Size
+}
+
+// migrateBucketConfigs - copies all configurations of a bucket.
+func migrateBucketConfigs(fs, xl ObjectLayer, bucket string) error {
+	for _, config := range bucketConfigs {
+		configPath := path.Join(bucketConfigPrefix, bucket, config)
+		var buffer bytes.Buffer
+		if err := fs.GetObject(minioMetaBucket, configPath, 0, -1, &buffer); err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if _, err := xl.PutObject(minioMetaBucket, configPath, int64(buffer.Len()), &buffer, nil, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateObject - streams an object from FS to XL along with its
+// metadata, and verifies the data written against the data read.
+// Returns size of the migrated object.
+func migrateObject(fs, xl ObjectLayer, bucket, object string) (int64, error) {
+	srcInfo, err := fs.GetObjectInfo(bucket, object)
+	if err != nil {
+		return 0, err
+	}
+
+	metadata := make(map[string]string)
+	for k, v := range srcInfo.UserDefined {
+		metadata[k] = v
+	}
+	// ETag is kept and verified by XL when it is a content md5sum.
+	if isContentMD5(srcInfo.MD5Sum) {
+		metadata["md5Sum"] = srcInfo.MD5Sum
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(fs.GetObject(bucket, object, 0, srcInfo.Size, pipeWriter))
+	}()
+	defer pipeReader.Close()
+
+	md5Writer := md5.New()
+	dstInfo, err := xl.PutObject(bucket, object, srcInfo.Size, io.TeeReader(pipeReader, md5Writer), metadata, "")
+	if err != nil {
+		return 0, err
+	}
+
+	readMD5 := hex.EncodeToString(md5Writer.Sum(nil))
+	if dstInfo.Size != srcInfo.Size || dstInfo.MD5Sum != readMD5 {
+		return 0, traceError(BadDigest{ExpectedMD5: readMD5, CalculatedMD5: dstInfo.MD5Sum})
+	}
+	return dstInfo.Size, nil
+}
+
+// migrateFSToXL - migrates all buckets and objects of an FS backend
+// to an XL backend, resuming a previous migration if any. Progress
+// is saved periodically, objects migrated since are migrated again.
+func migrateFSToXL(fs, xl ObjectLayer) (m fsToXLMigration, err error) {
+	if m, err = readFSToXLMigration(xl); err != nil || m.Done {
+		return m, err
+	}
+
+	buckets, err := fs.ListBuckets()
+	if err != nil {
+		return m, err
+	}
+
+	unsaved := 0
+	for _, bucket := range buckets {
+		if bucket.Name < m.Bucket {
+			continue
+		}
+		if bucket.Name != m.Bucket {
+			m.Bucket, m.Marker = bucket.Name, ""
+		}
+
+		if err = xl.MakeBucket(bucket.Name); err != nil {
+			if _, ok := errorCause(err).(BucketExists); !ok {
+				return m, err
+			}
+		}
+		if err = migrateBucketConfigs(fs, xl, bucket.Name); err != nil {
+			errorIf(err, "Unable to migrate configuration of %s.", bucket.Name)
+			return m, err
+		}
+
+		for {
+			result, err := fs.ListObjects(bucket.Name, "", m.Marker, "", maxObjectList)
+			if err != nil {
+				return m, err
+			}
+
+			for _, objInfo := range result.Objects {
+				if objInfo.IsDir {
+					continue
+				}
+				size, err := migrateObject(fs, xl, bucket.Name, objInfo.Name)
+				if err != nil {
+					errorIf(err, "Unable to migrate %s/%s.", bucket.Name, objInfo.Name)
+					errorIf(saveFSToXLMigration(xl, m), "Unable to save progress of the migration.")
+					return m, err
+				}
+				m.Marker = objInfo.Name
+				m.Objects++
+				m.Size += size
+
+				if unsaved++; unsaved == fsToXLMigrationSaveInterval {
+					if err = saveFSToXLMigration(xl, m); err != nil {
+						return m, err
+					}
+					unsaved = 0
+				}
+			}
+
+			if !result.IsTruncated {
+				break
+			}
+			m.Marker = result.NextMarker
+		}
+	}
+
+	m.Bucket, m.Marker = "", ""
+	m.Done = true
+	return m, saveFSToXLMigration(xl, m)
+}
+
+// Handler for 'minio control migrate fs-to-xl'.
+func mainControlMigrateFSToXL(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 || ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, "fs-to-xl", 1)
+	}
+
+	fsPath, err := filepath.Abs(ctx.Args().First())
+	fatalIf(err, "Unable to fetch absolute path for %s", ctx.Args().First())
+
+	addr, endpoints, setupType, err := CreateEndpoints(defaultServerAddress, ctx.Args().Tail()...)
+	fatalIf(err, "Invalid command line arguments")
+	if setupType != XLSetupType {
+		fatalIf(errors.New("invalid arguments"), "Migration is only supported to an XL backend of local directories.")
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Path == fsPath {
+			fatalIf(errInvalidArgument, "Directory %s can't be both the FS and an XL directory.", fsPath)
+		}
+	}
+
+	// Set configuration directory.
+	if ctx.GlobalIsSet("config-dir") {
+		configDirAbs, err := filepath.Abs(ctx.GlobalString("config-dir"))
+		fatalIf(err, "Unable to fetch absolute path for config directory %s", ctx.GlobalString("config-dir"))
+		setConfigDir(configDirAbs)
+	}
+	fatalIf(createConfigDir(), "Unable to create configuration directories.")
+	initConfig()
+	initError()
+	setGlobalEndpoints(serverNetConfig{Address: addr, DrainTimeout: defaultShutdownDrainTimeout}, endpoints, setupType)
+	initNSLock(false)
+
+	fs, err := newFSObjectLayer(fsPath)
+	fatalIf(err, "Unable to initialize FS backend on %s", fsPath)
+	defer fs.Shutdown()
+
+	xl, err := newObjectLayer(endpoints)
+	fatalIf(err, "Unable to initialize XL backend")
+	defer xl.Shutdown()
+
+	m, err := migrateFSToXL(fs, xl)
+	if err != nil {
+		fmt.Printf("Migrated %d objects (%s), %s/%s is the last one migrated.\n",
+			m.Objects, humanize.IBytes(uint64(m.Size)), m.Bucket, m.Marker)
+	}
+	fatalIf(err, "Unable to complete the migration, run the command again to resume it.")
+	fmt.Printf("Migration complete, %d objects (%s) migrated.\n", m.Objects, humanize.IBytes(uint64(m.Size)))
+}
@@ -0,0 +1,177 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"path"
+	"reflect"
+	"testing"
+)
+
+// Tests migrating buckets, objects and bucket configurations from FS
+// to XL, and resuming an interrupted migration.
+func TestMigrateFSToXL(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	initNSLock(false)
+
+	fs, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	objects := []struct {
+		bucket, object string
+		data           []byte
+	}{
+		{"bucket1", "dir/object2", []byte("hello, world")},
+		{"bucket1", "object1", []byte("hello")},
+		{"bucket2", "multipart", []byte("uploaded in parts")},
+		{"bucket2", "object3", []byte{}},
+		{"bucket2", "with-content", []byte("content type and metadata")},
+	}
+	metadata := map[string]string{"content-type": "text/plain", "X-Amz-Meta-Color": "blue"}
+	for _, bucket := range []string{"bucket1", "bucket2"} {
+		if err = fs.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, o := range objects {
+		bucket, object, data := o.bucket, o.object, o.data
+		switch object {
+		case "multipart":
+			uploadID, err := fs.NewMultipartUpload(bucket, object, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			part, err := fs.PutObjectPart(bucket, object, uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = fs.CompleteMultipartUpload(bucket, object, uploadID, []CompletePart{{PartNumber: 1, ETag: part.ETag}}); err != nil {
+				t.Fatal(err)
+			}
+		case "with-content":
+			if _, err = fs.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), metadata, ""); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			if _, err = fs.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Configurations are copied as is, whatever their content.
+	for _, config := range bucketConfigs {
+		configPath := path.Join(bucketConfigPrefix, "bucket1", config)
+		configData := []byte("config " + config)
+		if _, err = fs.PutObject(minioMetaBucket, configPath, int64(len(configData)), bytes.NewReader(configData), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	xl, xlDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(xlDirs)
+
+	m, err := migrateFSToXL(fs, xl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Done || m.Objects != int64(len(objects)) {
+		t.Fatalf("Unexpected progress %+v", m)
+	}
+
+	for _, o := range objects {
+		bucket, object, data := o.bucket, o.object, o.data
+		name := path.Join(bucket, object)
+		var buffer bytes.Buffer
+		if err = xl.GetObject(bucket, object, 0, -1, &buffer); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data) {
+			t.Errorf("%s: expected data %q, got %q", name, data, buffer.Bytes())
+		}
+		srcInfo, _ := fs.GetObjectInfo(bucket, object)
+		dstInfo, _ := xl.GetObjectInfo(bucket, object)
+		if object != "multipart" && srcInfo.MD5Sum != dstInfo.MD5Sum {
+			t.Errorf("%s: expected ETag %s, got %s", name, srcInfo.MD5Sum, dstInfo.MD5Sum)
+		}
+		if object == "with-content" && (dstInfo.ContentType != "text/plain" || dstInfo.UserDefined["X-Amz-Meta-Color"] != "blue") {
+			t.Errorf("%s: metadata not migrated, got %v", name, dstInfo.UserDefined)
+		}
+	}
+
+	for _, config := range bucketConfigs {
+		var buffer bytes.Buffer
+		if err = xl.GetObject(minioMetaBucket, path.Join(bucketConfigPrefix, "bucket1", config), 0, -1, &buffer); err != nil {
+			t.Fatalf("%s: %s", config, err)
+		}
+		if buffer.String() != "config "+config {
+			t.Errorf("%s: expected config to be migrated, got %q", config, buffer.String())
+		}
+	}
+
+	// Completed migration is not run again.
+	m2, err := migrateFSToXL(fs, xl)
+	if err != nil || !reflect.DeepEqual(m, m2) {
+		t.Errorf("Expected %+v, got %+v, %v", m, m2, err)
+	}
+
+	// Interrupted migration resumes after the last migrated object.
+	resumed := fsToXLMigration{Bucket: "bucket2", Marker: "multipart", Objects: 3, Size: 17}
+	if err = saveFSToXLMigration(xl, resumed); err != nil {
+		t.Fatal(err)
+	}
+	if err = xl.DeleteObject("bucket1", "object1"); err != nil {
+		t.Fatal(err)
+	}
+	if m, err = migrateFSToXL(fs, xl); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Done || m.Objects != 5 {
+		t.Errorf("Unexpected progress %+v", m)
+	}
+	if _, err = xl.GetObjectInfo("bucket1", "object1"); !isErrObjectNotFound(err) {
+		t.Errorf("Expected objects before the marker to be skipped, got %v", err)
+	}
+}
+
+// Tests detection of ETags which are md5sum of object content.
+func TestIsContentMD5(t *testing.T) {
+	testCases := []struct {
+		etag     string
+		expected bool
+	}{
+		{"d41d8cd98f00b204e9800998ecf8427e", true},
+		{"d41d8cd98f00b204e9800998ecf8427e-2", false},
+		{"d41d8cd98f00b204e9800998ecf842", false},
+		{"", false},
+	}
+	for i, testCase := range testCases {
+		if actual := isContentMD5(testCase.etag); actual != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	router "github.com/gorilla/mux"
+)
+
+// Debug endpoints, only registered when lock debugging is enabled.
+const (
+	debugLocksPath        = minioReservedBucketPath + "/debug/locks"
+	debugVarsPath         = minioReservedBucketPath + "/debug/vars"
+	debugBlockedLocksPath = minioReservedBucketPath + "/debug/pprof/blocked-locks"
+)
+
+// debugLocksInfo - state of all the namespace locks on this server
+// along with suspected deadlocks found by the last detection run.
+type debugLocksInfo struct {
+	LockState SystemLockState     `json:"lockState"`
+	Deadlocks [][]DeadlockOpsInfo `json:"deadlocks"`
+}
+
+// registerDebugRouter - registers debug endpoints.
+func registerDebugRouter(mux *router.Router) {
+	mux.Methods("GET").Path(debugLocksPath).HandlerFunc(debugLocksHandler)
+	mux.Methods("GET").Path(debugVarsPath).HandlerFunc(debugVarsHandler)
+	mux.Methods("GET").Path(debugBlockedLocksPath).HandlerFunc(debugBlockedLocksHandler)
+}
+
+// debugLocksHandler - GET /minio/debug/locks
+// ----------
+// Returns the lock state of this server and suspected deadlock chains.
+func debugLocksHandler(w http.ResponseWriter, r *http.Request) {
+	apiErr := checkAdminRequestAuthType(r, "")
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	lockState, err := getSystemLockState()
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to fetch lock state.")
+		return
+	}
+
+	globalNSMutex.deadlocksMutex.Lock()
+	deadlocks := globalNSMutex.deadlocks
+	globalNSMutex.deadlocksMutex.Unlock()
+
+	jsonBytes, err := json.Marshal(debugLocksInfo{
+		LockState: lockState,
+		Deadlocks: deadlocks,
+	})
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal lock state into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// debugVarsHandler - GET /minio/debug/vars
+// ----------
+// Returns all expvar variables, including the namespace lock counters
+// of this server, in the format of the expvar /debug/vars handler.
+func debugVarsHandler(w http.ResponseWriter, r *http.Request) {
+	apiErr := checkAdminRequestAuthType(r, "")
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := getExpvarsJSON()
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIf(err, "Failed to marshal expvar variables into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// debugBlockedLocksHandler - GET /minio/debug/pprof/blocked-locks?debug=<n>
+// ----------
+// Returns the pprof profile of lock acquisitions currently blocked,
+// by the stacks which asked for the locks, to be analyzed with `go tool
+// pprof`. debug=1 returns it in text format instead.
+func debugBlockedLocksHandler(w http.ResponseWriter, r *http.Request) {
+	apiErr := checkAdminRequestAuthType(r, "")
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+	if debug > 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"blocked-locks.pprof\"")
+	}
+	if err := blockedLocksProfile.WriteTo(w, debug); err != nil {
+		errorIf(err, "Failed to write blocked locks profile.")
+	}
+}
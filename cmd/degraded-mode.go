@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// Interval between two checks of write quorum in degraded read-only
+// mode.
+const degradedModeCheckInterval = 10 * time.Second
+
+// Server modes reported by admin ServerInfo.
+const (
+	serverModeReadWrite        = "read-write"
+	serverModeReadOnly         = "read-only"
+	serverModeDegradedReadOnly = "degraded-read-only"
+	serverModeMissingDisks     = "missing-disks-read-only"
+)
+
+// degradedMode - enabled with MINIO_DEGRADED_READ_ONLY, tracks if an
+// erasure coded server has enough disks online for reads but not for
+// writes. Writes are rejected while degraded instead of failing on
+// the disks, reads are served as usual.
+type degradedMode struct {
+	mu       sync.RWMutex
+	degraded bool
+	since    time.Time
+}
+
+// isDegraded - returns true if writes are rejected for lack of write
+// quorum.
+func (d *degradedMode) isDegraded() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.degraded
+}
+
+// check - checks write quorum of the object layer and updates the
+// mode, entering and leaving degraded mode is logged.
+func (d *degradedMode) check(objAPI ObjectLayer) {
+	storageInfo := objAPI.StorageInfo()
+	if storageInfo.Backend.Type != Erasure {
+		return
+	}
+	degraded := storageInfo.Backend.OnlineDisks < storageInfo.Backend.WriteQuorum
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if degraded == d.degraded {
+		return
+	}
+	d.degraded = degraded
+	if degraded {
+		d.since = UTCNow()
+		errorIf(errServerDegraded, "Only %d disks are online, %d are needed for writes",
+			storageInfo.Backend.OnlineDisks, storageInfo.Backend.WriteQuorum)
+		return
+	}
+	log.Printf("Write quorum is back after %s, leaving degraded read-only mode.\n", UTCNow().Sub(d.since))
+}
+
+// run - checks write quorum at every interval until doneCh is closed.
+func (d *degradedMode) run(objAPI func() ObjectLayer, interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if objLayer := objAPI(); objLayer != nil {
+				d.check(objLayer)
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// checkServerWriteable - returns an error if write operations are
+// disabled, either by read-only mode, by degraded read-only mode or
+// by disks missing since startup.
+func checkServerWriteable() error {
+	if globalIsReadOnly {
+		return errServerReadOnly
+	}
+	if globalDegradedMode.isDegraded() {
+		return errServerDegraded
+	}
+	if globalMissingDisks.isLocked() {
+		return errServerMissingDisks
+	}
+	return nil
+}
+
+// getServerMode - returns the mode the server is serving requests in.
+func getServerMode() string {
+	switch checkServerWriteable() {
+	case errServerReadOnly:
+		return serverModeReadOnly
+	case errServerDegraded:
+		return serverModeDegradedReadOnly
+	case errServerMissingDisks:
+		return serverModeMissingDisks
+	}
+	return serverModeReadWrite
+}
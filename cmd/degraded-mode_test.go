@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Tests degraded read-only mode follows write quorum of XL.
+func TestDegradedModeCheck(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject("bucket", "object", 5, bytes.NewReader([]byte("hello")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(mode *degradedMode) { globalDegradedMode = mode }(globalDegradedMode)
+	globalDegradedMode = &degradedMode{}
+
+	// All disks online.
+	globalDegradedMode.check(obj)
+	if err = checkServerWriteable(); err != nil {
+		t.Fatalf("Expected server to be writeable, got %s", err)
+	}
+	if mode := getServerMode(); mode != serverModeReadWrite {
+		t.Fatalf("Expected mode %s, got %s", serverModeReadWrite, mode)
+	}
+
+	// Only read quorum of disks online, reads are still served.
+	disks := append([]StorageAPI(nil), xl.storageDisks...)
+	for i := 0; i < len(xl.storageDisks)/2; i++ {
+		xl.storageDisks[i] = nil
+	}
+	globalDegradedMode.check(obj)
+	if err = checkServerWriteable(); err != errServerDegraded {
+		t.Fatalf("Expected %s, got %v", errServerDegraded, err)
+	}
+	if mode := getServerMode(); mode != serverModeDegradedReadOnly {
+		t.Fatalf("Expected mode %s, got %s", serverModeDegradedReadOnly, mode)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject("bucket", "object", 0, 5, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "hello" {
+		t.Fatalf("Expected hello, got %s", buf.String())
+	}
+
+	// Read-only mode takes precedence.
+	globalIsReadOnly = true
+	mode := getServerMode()
+	globalIsReadOnly = false
+	if mode != serverModeReadOnly {
+		t.Fatalf("Expected mode %s, got %s", serverModeReadOnly, mode)
+	}
+
+	// Write quorum is back.
+	copy(xl.storageDisks, disks)
+	globalDegradedMode.check(obj)
+	if err = checkServerWriteable(); err != nil {
+		t.Fatalf("Expected server to be writeable again, got %s", err)
+	}
+}
+
+// Tests degraded read-only mode is never entered on FS.
+func TestDegradedModeCheckFS(t *testing.T) {
+	obj, disk, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(disk)
+
+	d := &degradedMode{}
+	d.check(obj)
+	if d.isDegraded() {
+		t.Fatal("Expected FS to never be degraded")
+	}
+}
@@ -213,7 +213,7 @@ func NewEndpointList(args ...string) (endpoints EndpointList, err error) {
 
 		arg = endpoint.String()
 		if uniqueArgs.Contains(arg) {
-			return nil, fmt.Errorf("duplicate endpoints found")
+			return nil, fmt.Errorf("duplicate endpoint '%s' found", arg)
 		}
 		uniqueArgs.Add(arg)
 
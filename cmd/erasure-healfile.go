@@ -24,7 +24,7 @@ func erasureHealFile(latestDisks []StorageAPI, outDatedDisks []StorageAPI, volum
 	remainingSize := size
 
 	// Hash for bitrot protection.
-	hashWriters := newHashWriters(len(outDatedDisks), bitRotAlgo)
+	hashWriters := newHashWriters(len(outDatedDisks), algo)
 
 	for remainingSize > 0 {
 		curBlockSize := blockSize
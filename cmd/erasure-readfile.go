@@ -142,6 +142,10 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 
 			_, err = readDisks[index].ReadFile(volume, path, blockOffset, buf)
 			if err != nil {
+				// Remaining blocks are read from the other disks.
+				if isReadFailoverErr(err) {
+					globalReadFailovers.record(readDisks[index], volume, path, err)
+				}
 				orderedDisks[index] = nil
 				return
 			}
@@ -157,18 +161,24 @@ func parallelRead(volume, path string, readDisks []StorageAPI, orderedDisks []St
 // Erasure coded files are read block by block as per given erasureInfo and data chunks
 // are decoded into a data block. Data block is trimmed for given offset and length,
 // then written to given writer. This function also supports bit-rot detection by
-// verifying checksum of individual block's checksum.
-func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, error) {
+// verifying checksum of individual block's checksum, unless checkSums is nil.
+// Returns true along with the bytes written if a file with mismatching checksum
+// was found, its data is reconstructed from the other disks.
+func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path string, offset int64, length int64, totalLength int64, blockSize int64, dataBlocks int, parityBlocks int, checkSums []string, algo string, pool *bpool.BytePool) (int64, bool, error) {
 	// Offset and length cannot be negative.
 	if offset < 0 || length < 0 {
-		return 0, traceError(errUnexpected)
+		return 0, false, traceError(errUnexpected)
 	}
 
 	// Can't request more data than what is available.
 	if offset+length > totalLength {
-		return 0, traceError(errUnexpected)
+		return 0, false, traceError(errUnexpected)
 	}
 
+	// Set when a file on any disk has bit-rot.
+	var bitRotMu sync.Mutex
+	var bitRotDetected bool
+
 	// chunkSize is the amount of data that needs to be read from each disk at a time.
 	chunkSize := getChunkSize(blockSize, dataBlocks)
 
@@ -180,14 +190,21 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		// not recalculate the hash on it every time the function is
 		// called for the same disk.
 		return func(diskIndex int) bool {
-			if verified[diskIndex] {
-				// Already validated.
+			if verified[diskIndex] || checkSums == nil {
+				// Already validated or verification is disabled.
 				return true
 			}
 			// Is this a valid block?
-			isValid := isValidBlock(disks[diskIndex], volume, path, checkSums[diskIndex], algo)
-			verified[diskIndex] = isValid
-			return isValid
+			err := verifyBlock(disks[diskIndex], volume, path, checkSums[diskIndex], algo)
+			if err == errBitRotHashMismatch {
+				bitRotMu.Lock()
+				bitRotDetected = true
+				bitRotMu.Unlock()
+			} else if isReadFailoverErr(err) {
+				globalReadFailovers.record(disks[diskIndex], volume, path, err)
+			}
+			verified[diskIndex] = err == nil
+			return verified[diskIndex]
 		}
 	}()
 
@@ -238,7 +255,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 			// get readable disks slice from which we can read parallelly.
 			readDisks, nextIndex, err = getReadDisks(disks, nextIndex, dataBlocks)
 			if err != nil {
-				return bytesWritten, err
+				return bytesWritten, bitRotDetected, err
 			}
 			// Issue a parallel read across the disks specified in readDisks.
 			parallelRead(volume, path, readDisks, disks, enBlocks, blockOffset, curChunkSize, bitRotVerify, pool)
@@ -248,7 +265,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 			}
 			if nextIndex == len(disks) {
 				// No more disks to read from.
-				return bytesWritten, traceError(errXLReadQuorum)
+				return bytesWritten, bitRotDetected, traceError(errXLReadQuorum)
 			}
 			// We do not have enough enough data blocks to reconstruct the data
 			// hence continue the for-loop till we have enough data blocks.
@@ -258,7 +275,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		if !isSuccessDataBlocks(enBlocks, dataBlocks) {
 			// Reconstruct the missing data blocks.
 			if err := decodeData(enBlocks, dataBlocks, parityBlocks); err != nil {
-				return bytesWritten, err
+				return bytesWritten, bitRotDetected, err
 			}
 		}
 
@@ -283,7 +300,7 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 		// Write data blocks.
 		n, err := writeDataBlocks(writer, enBlocks, dataBlocks, enBlocksOffset, enBlocksLength)
 		if err != nil {
-			return bytesWritten, err
+			return bytesWritten, bitRotDetected, err
 		}
 
 		// Update total bytes written.
@@ -296,28 +313,31 @@ func erasureReadFile(writer io.Writer, disks []StorageAPI, volume string, path s
 	}
 
 	// Success.
-	return bytesWritten, nil
+	return bytesWritten, bitRotDetected, nil
 }
 
-// isValidBlock - calculates the checksum hash for the block and
-// validates if its correct returns true for valid cases, false otherwise.
-func isValidBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string) (ok bool) {
+// verifyBlock - calculates the checksum hash for the block and
+// validates if its correct, returns errBitRotHashMismatch if it is not.
+func verifyBlock(disk StorageAPI, volume, path, checkSum, checkSumAlgo string) error {
 	// Disk is not available, not a valid block.
 	if disk == nil {
-		return false
+		return errDiskNotFound
 	}
 	// Checksum not available, not a valid block.
 	if checkSum == "" {
-		return false
+		return errFileNotFound
 	}
 	// Read everything for a given block and calculate hash.
 	hashWriter := newHash(checkSumAlgo)
 	hashBytes, err := hashSum(disk, volume, path, hashWriter)
 	if err != nil {
 		errorIf(err, "Unable to calculate checksum %s/%s", volume, path)
-		return false
+		return err
 	}
-	return hex.EncodeToString(hashBytes) == checkSum
+	if hex.EncodeToString(hashBytes) != checkSum {
+		return errBitRotHashMismatch
+	}
+	return nil
 }
 
 // decodeData - decode encoded blocks.
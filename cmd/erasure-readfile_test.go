@@ -19,6 +19,8 @@ package cmd
 import (
 	"bytes"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"reflect"
@@ -250,7 +252,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	pool := bpool.NewBytePool(chunkSize, len(disks))
 
 	buf := &bytes.Buffer{}
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -263,7 +265,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[5] = ReadDiskDown{disks[5].(*posix)}
 
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -278,7 +280,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[11] = ReadDiskDown{disks[11].(*posix)}
 
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 	if err != nil {
 		t.Error(err)
 	}
@@ -290,7 +292,7 @@ func TestErasureReadFileDiskFail(t *testing.T) {
 	disks[12] = ReadDiskDown{disks[12].(*posix)}
 	disks[13] = ReadDiskDown{disks[13].(*posix)}
 	buf.Reset()
-	_, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+	_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 	if errorCause(err) != errXLReadQuorum {
 		t.Fatal("expected errXLReadQuorum error")
 	}
@@ -355,7 +357,7 @@ func TestErasureReadFileOffsetLength(t *testing.T) {
 	for i, testCase := range testCases {
 		expected := data[testCase.offset:(testCase.offset + testCase.length)]
 		buf := &bytes.Buffer{}
-		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", testCase.offset, testCase.length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+		_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", testCase.offset, testCase.length, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 		if err != nil {
 			t.Error(err)
 			continue
@@ -423,7 +425,7 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 
 		expected := data[offset : offset+readLen]
 
-		_, err = erasureReadFile(buf, disks, "testbucket", "testobject", offset, readLen, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
+		_, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", offset, readLen, length, blockSize, dataBlocks, parityBlocks, checkSums, bitRotAlgo, pool)
 		if err != nil {
 			t.Fatal(err, offset, readLen)
 		}
@@ -434,3 +436,83 @@ func TestErasureReadFileRandomOffsetLength(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+// Tests bit-rot is detected and corrected by erasureReadFile, for
+// every supported checksum algorithm.
+func TestErasureReadFileBitRot(t *testing.T) {
+	dataBlocks := 4
+	parityBlocks := 4
+	blockSize := int64(blockSizeV1)
+	for _, algo := range []string{blake2bAlgo, sha256Algo, highwayHashAlgo} {
+		setup, err := newErasureTestSetup(dataBlocks, parityBlocks, blockSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer setup.Remove()
+		disks := setup.disks
+
+		data := make([]byte, 256*humanize.KiByte)
+		if _, err = rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+		length := int64(len(data))
+		_, checkSums, err := erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), true, blockSize, dataBlocks, parityBlocks, algo, dataBlocks+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool := bpool.NewBytePool(getChunkSize(blockSize, dataBlocks), len(disks))
+		// erasureReadFile drops failed disks from the slice it is given.
+		copyDisks := func() []StorageAPI {
+			return append([]StorageAPI(nil), disks...)
+		}
+
+		buf := &bytes.Buffer{}
+		_, bitRot, err := erasureReadFile(buf, copyDisks(), "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, algo, pool)
+		if err != nil {
+			t.Fatalf("%s: %v", algo, err)
+		}
+		if bitRot {
+			t.Errorf("%s: Expected no bit-rot to be detected", algo)
+		}
+
+		// Flip the first byte of a data shard.
+		f, err := os.OpenFile(filepath.Join(setup.diskPaths[0], "testbucket", "testobject"), os.O_RDWR, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := make([]byte, 1)
+		if _, err = f.ReadAt(b, 0); err != nil {
+			t.Fatal(err)
+		}
+		b[0] ^= 0xff
+		if _, err = f.WriteAt(b, 0); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		buf.Reset()
+		_, bitRot, err = erasureReadFile(buf, copyDisks(), "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, checkSums, algo, pool)
+		if err != nil {
+			t.Fatalf("%s: %v", algo, err)
+		}
+		if !bitRot {
+			t.Errorf("%s: Expected bit-rot to be detected", algo)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("%s: Expected corrupted shard to be reconstructed", algo)
+		}
+
+		// Without checksums the corrupted shard is read as is.
+		buf.Reset()
+		_, bitRot, err = erasureReadFile(buf, copyDisks(), "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, nil, algo, pool)
+		if err != nil {
+			t.Fatalf("%s: %v", algo, err)
+		}
+		if bitRot {
+			t.Errorf("%s: Expected verification to be skipped", algo)
+		}
+		if bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("%s: Expected corrupted shard to be read", algo)
+		}
+	}
+}
@@ -24,10 +24,18 @@ import (
 	"sync"
 
 	"github.com/klauspost/reedsolomon"
+	"github.com/minio/highwayhash"
 	"github.com/minio/sha256-simd"
 	"golang.org/x/crypto/blake2b"
 )
 
+// Key of highwayhash checksums, a random but fixed value. Must never
+// change, checksums of existing shards are computed with it.
+var highwayHashKey = []byte{
+	0x4b, 0xe7, 0x34, 0xfa, 0x8e, 0x23, 0x8a, 0xcd, 0x26, 0x3e, 0x83, 0xe6, 0xbb, 0x96, 0x85, 0x52,
+	0x04, 0x0f, 0x93, 0x5d, 0xa3, 0x9f, 0x44, 0x14, 0x97, 0xe0, 0x9d, 0x13, 0x22, 0xde, 0x36, 0xa0,
+}
+
 // newHashWriters - inititialize a slice of hashes for the disk count.
 func newHashWriters(diskCount int, algo string) []hash.Hash {
 	hashWriters := make([]hash.Hash, diskCount)
@@ -49,6 +57,9 @@ func newHash(algo string) (h hash.Hash) {
 		// New512 only returns a non-nil error, if the length of the passed
 		// key > 64 bytes - but we use blake2b as hash function (no key)
 		h, _ = blake2b.New512(nil)
+	case highwayHashAlgo:
+		// ignore the error, because New never fails with a 32 byte key.
+		h, _ = highwayhash.New(highwayHashKey)
 	// Add new hashes here.
 	default:
 		// Default to blake2b.
@@ -33,6 +33,25 @@ func TestNewHashWriters(t *testing.T) {
 
 }
 
+// Tests hashes of every supported bit-rot algorithm.
+func TestNewHash(t *testing.T) {
+	testCases := []struct {
+		algo string
+		size int
+	}{
+		{blake2bAlgo, 64},
+		{sha256Algo, 32},
+		{highwayHashAlgo, 32},
+	}
+	for i, testCase := range testCases {
+		h := newHash(testCase.algo)
+		h.Write([]byte("minio"))
+		if sum := h.Sum(nil); len(sum) != testCase.size {
+			t.Errorf("Test %d: Expected %d bytes hash, got %d", i+1, testCase.size, len(sum))
+		}
+	}
+}
+
 // Tests validate the output of getChunkSize.
 func TestGetChunkSize(t *testing.T) {
 	// Refer to comments on getChunkSize() for details.
@@ -55,6 +55,11 @@ func (e Error) Error() string {
 	return e.e.Error()
 }
 
+// Cause - returns the underlying cause error.
+func (e Error) Cause() error {
+	return e.e
+}
+
 // Trace - returns stack trace.
 func (e Error) Trace() []string {
 	var traceArr []string
@@ -95,6 +95,10 @@ type eventData struct {
 // input request metadata which completed successfully.
 func newNotificationEvent(event eventData) NotificationEvent {
 	getResponseOriginEndpointKey := func() string {
+		if globalServerNetConfig.AdvertiseAddress != "" {
+			return globalServerNetConfig.getAPIEndpoints()[0]
+		}
+
 		host := globalMinioHost
 		if host == "" {
 			// FIXME: Send FQDN or hostname of this machine than sending IP address.
@@ -115,8 +119,10 @@ func newNotificationEvent(event eventData) NotificationEvent {
 	// Fetch the credentials.
 	creds := serverConfig.GetCredential()
 
-	// Time when Minio finished processing the request.
-	eventTime := UTCNow()
+	// Time when Minio finished processing the request, ordered
+	// with events of all other servers.
+	eventClock := globalHLC.Now()
+	eventTime := eventClock.Time()
 
 	// Fetch a hexadecimal representation of event time in nano seconds.
 	uniqueID := mustGetRequestID(eventTime)
@@ -139,6 +145,9 @@ func newNotificationEvent(event eventData) NotificationEvent {
 			// Following is a custom response element to indicate
 			// event origin server endpoint.
 			responseOriginEndpointKey: getResponseOriginEndpointKey(),
+			// Following is a custom response element to order
+			// events of all servers.
+			responseEventClockKey: eventClock.String(),
 		},
 		S3: eventMeta{
 			SchemaVersion:   eventSchemaVersion,
@@ -285,8 +294,8 @@ func (en *eventNotifier) SetBucketListenerConfig(bucket string, lcfg []listenerC
 	return nil
 }
 
-func eventNotifyForBucketNotifications(eventType, objectName, bucketName string, nEvent []NotificationEvent) {
-	nConfig := globalEventNotifier.GetBucketNotificationConfig(bucketName)
+func eventNotifyForBucketNotifications(en *eventNotifier, eventType, objectName, bucketName string, nEvent []NotificationEvent) {
+	nConfig := en.GetBucketNotificationConfig(bucketName)
 	if nConfig == nil {
 		return
 	}
@@ -295,7 +304,22 @@ func eventNotifyForBucketNotifications(eventType, objectName, bucketName string,
 		eventMatch := eventMatch(eventType, qConfig.Events)
 		ruleMatch := filterRuleMatch(objectName, qConfig.Filter.Key.FilterRules)
 		if eventMatch && ruleMatch {
-			targetLog := globalEventNotifier.GetExternalTarget(qConfig.QueueARN)
+			targetLog := en.GetExternalTarget(qConfig.QueueARN)
+			if targetLog != nil {
+				targetLog.WithFields(logrus.Fields{
+					"Key":       path.Join(bucketName, objectName),
+					"EventType": eventType,
+					"Records":   nEvent,
+				}).Info()
+			}
+		}
+	}
+	// Topics publish to the targets of the same id and type.
+	for _, tConfig := range nConfig.TopicConfigs {
+		eventMatch := eventMatch(eventType, tConfig.Events)
+		ruleMatch := filterRuleMatch(objectName, tConfig.Filter.Key.FilterRules)
+		if eventMatch && ruleMatch {
+			targetLog := en.GetExternalTarget(topicToQueueARN(tConfig.TopicARN))
 			if targetLog != nil {
 				targetLog.WithFields(logrus.Fields{
 					"Key":       path.Join(bucketName, objectName),
@@ -307,9 +331,9 @@ func eventNotifyForBucketNotifications(eventType, objectName, bucketName string,
 	}
 }
 
-func eventNotifyForBucketListeners(eventType, objectName, bucketName string,
+func eventNotifyForBucketListeners(en *eventNotifier, eventType, objectName, bucketName string,
 	nEvent []NotificationEvent) {
-	lCfgs := globalEventNotifier.GetBucketListenerConfig(bucketName)
+	lCfgs := en.GetBucketListenerConfig(bucketName)
 	if lCfgs == nil {
 		return
 	}
@@ -318,7 +342,7 @@ func eventNotifyForBucketListeners(eventType, objectName, bucketName string,
 		ruleMatch := filterRuleMatch(objectName, lcfg.TopicConfig.Filter.Key.FilterRules)
 		eventMatch := eventMatch(eventType, lcfg.TopicConfig.Events)
 		if eventMatch && ruleMatch {
-			targetLog := globalEventNotifier.GetInternalTarget(
+			targetLog := en.GetInternalTarget(
 				lcfg.TopicConfig.TopicARN)
 			if targetLog != nil && targetLog.log != nil {
 				targetLog.log.WithFields(logrus.Fields{
@@ -335,7 +359,10 @@ func eventNotifyForBucketListeners(eventType, objectName, bucketName string,
 // eventNotify notifies an event to relevant targets based on their
 // bucket configuration (notifications and listeners).
 func eventNotify(event eventData) {
-	if globalEventNotifier == nil {
+	// Events are delivered in background, by the notifier set when
+	// they happened.
+	en := globalEventNotifier
+	if en == nil {
 		return
 	}
 	// Notifies a new event.
@@ -345,6 +372,7 @@ func eventNotify(event eventData) {
 	//  - s3:ObjectCreated:Copy
 	//  - s3:ObjectCreated:CompleteMultipartUpload
 	//  - s3:ObjectRemoved:Delete
+	//  - s3:BucketUsage:ThresholdExceeded
 
 	// Event type.
 	eventType := event.Type.String()
@@ -355,11 +383,19 @@ func eventNotify(event eventData) {
 	// Save the notification event to be sent.
 	notificationEvent := []NotificationEvent{newNotificationEvent(event)}
 
-	// Notify external targets.
-	eventNotifyForBucketNotifications(eventType, objectName, event.Bucket, notificationEvent)
+	deliver := func() {
+		// Notify external targets.
+		eventNotifyForBucketNotifications(en, eventType, objectName, event.Bucket, notificationEvent)
 
-	// Notify internal targets.
-	eventNotifyForBucketListeners(eventType, objectName, event.Bucket, notificationEvent)
+		// Notify internal targets.
+		eventNotifyForBucketListeners(en, eventType, objectName, event.Bucket, notificationEvent)
+	}
+
+	// Deliver the event in the caller when the queue is full
+	// rather than dropping it.
+	if !globalNotificationPool.submit(deliver) {
+		deliver()
+	}
 }
 
 // loads notification config if any for a given bucket, returns
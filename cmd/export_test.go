@@ -0,0 +1,41 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "path/filepath"
+
+// Exported for tests of package cmd_test, run against object layers
+// of this package.
+
+// NewTestConfig - initializes server config for tests.
+var NewTestConfig = newTestConfig
+
+// NewFSTestObjectLayer - returns a new FS object layer under dir.
+func NewFSTestObjectLayer(dir string) (ObjectLayer, error) {
+	return newFSObjectLayer(dir)
+}
+
+// NewXLTestObjectLayer - returns a new XL object layer over 16 disks
+// under dir.
+func NewXLTestObjectLayer(dir string) (ObjectLayer, error) {
+	var disks []string
+	for i := 0; i < 16; i++ {
+		disks = append(disks, filepath.Join(dir, "disk"+string('a'+rune(i))))
+	}
+	objLayer, _, err := initObjectLayer(mustGetNewEndpointList(disks...))
+	return objLayer, err
+}
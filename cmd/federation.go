@@ -0,0 +1,224 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// Modes of serving requests of buckets owned by another server.
+const (
+	// Forward the request to the owner and stream back its response.
+	federationModeProxy = "proxy"
+	// Redirect the client to the owner with 307 Temporary Redirect.
+	federationModeRedirect = "redirect"
+)
+
+// Set on requests proxied to the owner of a bucket, such requests are
+// always served locally so that servers disagreeing on the owner of
+// a bucket never proxy a request back and forth.
+const federationProxiedHeader = "X-Minio-Federated"
+
+// federationConfig - servers sharing one namespace of buckets, every
+// server of the federation is configured with the same map of buckets
+// to the server owning them. Buckets missing from the map are served
+// locally.
+type federationConfig struct {
+	// "proxy" (default) or "redirect".
+	Mode string `json:"mode"`
+
+	// Bucket name to the URL of its owner, e.g. "http://10.0.0.2:9000".
+	Buckets map[string]string `json:"buckets"`
+}
+
+// Validate - validates federation config.
+func (c federationConfig) Validate() error {
+	switch c.Mode {
+	case "", federationModeProxy, federationModeRedirect:
+	default:
+		return fmt.Errorf("Invalid federation mode ‘%s’", c.Mode)
+	}
+	for bucket, owner := range c.Buckets {
+		if !IsValidBucketName(bucket) {
+			return fmt.Errorf("Invalid bucket name ‘%s’ in federation", bucket)
+		}
+		if _, err := parseFederationURL(owner); err != nil {
+			return fmt.Errorf("Invalid owner ‘%s’ of bucket ‘%s’ in federation. %v", owner, bucket, err)
+		}
+	}
+	return nil
+}
+
+// parseFederationURL - parses URL of a server of the federation, only
+// scheme, host and port are allowed.
+func parseFederationURL(owner string) (*url.URL, error) {
+	u, err := url.Parse(owner)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("scheme should be http or https")
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("host cannot be empty")
+	}
+	if (u.Path != "" && u.Path != slashSeparator) || u.RawQuery != "" || u.User != nil {
+		return nil, fmt.Errorf("only scheme, host and port are allowed")
+	}
+	u.Path = ""
+	return u, nil
+}
+
+// isLocalFederationURL - returns true when URL of a server of the
+// federation points to this server.
+func isLocalFederationURL(u *url.URL) (bool, error) {
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host = u.Host
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	if port != globalMinioPort {
+		return false, nil
+	}
+	hostIPs, err := getHostIP4(host)
+	if err != nil {
+		return false, err
+	}
+	return !localIP4.Intersection(hostIPs).IsEmpty(), nil
+}
+
+// federation - owners of buckets served by other servers.
+type federation struct {
+	mode    string
+	owners  map[string]*url.URL
+	proxies map[string]*httputil.ReverseProxy
+}
+
+// newFederation - resolves owners of federated buckets, buckets owned
+// by this server, as told by isLocal, are left out.
+func newFederation(config federationConfig, isLocal func(*url.URL) (bool, error)) (*federation, error) {
+	f := &federation{
+		mode:    config.Mode,
+		owners:  make(map[string]*url.URL),
+		proxies: make(map[string]*httputil.ReverseProxy),
+	}
+	if f.mode == "" {
+		f.mode = federationModeProxy
+	}
+	for bucket, owner := range config.Buckets {
+		u, err := parseFederationURL(owner)
+		if err != nil {
+			return nil, err
+		}
+		local, err := isLocal(u)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to resolve owner ‘%s’ of bucket ‘%s’. %v", owner, bucket, err)
+		}
+		if local {
+			continue
+		}
+		f.owners[bucket] = u
+		if _, ok := f.proxies[u.String()]; !ok {
+			f.proxies[u.String()] = newFederationProxy(u)
+		}
+	}
+	return f, nil
+}
+
+// newFederationProxy - returns a reverse proxy to the owner. Host header
+// of the request is kept as is, signatures of requests signed for this
+// server remain valid on the owner.
+func newFederationProxy(owner *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = owner.Scheme
+			r.URL.Host = owner.Host
+			r.Header.Set(federationProxiedHeader, "true")
+		},
+	}
+}
+
+// getOwner - returns URL of the server owning the bucket, nil when the
+// bucket is served locally.
+func (f *federation) getOwner(bucket string) *url.URL {
+	return f.owners[bucket]
+}
+
+// serve - proxies or redirects the request to the owner of its bucket.
+func (f *federation) serve(w http.ResponseWriter, r *http.Request, owner *url.URL) {
+	if f.mode == federationModeRedirect {
+		location := *r.URL
+		location.Scheme = owner.Scheme
+		location.Host = owner.Host
+		http.Redirect(w, r, location.String(), http.StatusTemporaryRedirect)
+		return
+	}
+	f.proxies[owner.String()].ServeHTTP(w, r)
+}
+
+// initFederation - sets globalFederation from config, remains nil when
+// no bucket is federated.
+func initFederation() error {
+	config := serverConfig.GetFederation()
+	if len(config.Buckets) == 0 {
+		return nil
+	}
+	f, err := newFederation(config, isLocalFederationURL)
+	if err != nil {
+		return err
+	}
+	globalFederation = f
+	return nil
+}
+
+// federationHandler proxies or redirects requests of buckets owned by
+// other servers of the federation.
+type federationHandler struct {
+	handler http.Handler
+}
+
+// setFederationHandler - serves requests of buckets owned by other
+// servers from their owner.
+func setFederationHandler(h http.Handler) http.Handler {
+	return federationHandler{handler: h}
+}
+
+func (h federationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f := globalFederation
+	if f == nil || r.Header.Get(federationProxiedHeader) != "" || r.Header.Get(minioAdminOpHeader) != "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	if bucket == "" || isMinioReservedBucket(bucket) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	owner := f.getOwner(bucket)
+	if owner == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	f.serve(w, r, owner)
+}
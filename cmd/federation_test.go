@@ -0,0 +1,162 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Tests validation of federation config.
+func TestFederationConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config     federationConfig
+		shouldPass bool
+	}{
+		// Test 1 - empty config.
+		{federationConfig{}, true},
+		// Test 2 - valid config.
+		{federationConfig{Mode: "redirect", Buckets: map[string]string{"photos": "https://10.0.0.2:9000"}}, true},
+		// Test 3 - trailing slash of owner.
+		{federationConfig{Buckets: map[string]string{"photos": "http://10.0.0.2:9000/"}}, true},
+		// Test 4 - invalid mode.
+		{federationConfig{Mode: "forward"}, false},
+		// Test 5 - invalid bucket name.
+		{federationConfig{Buckets: map[string]string{"ph": "http://10.0.0.2:9000"}}, false},
+		// Test 6 - owner without scheme.
+		{federationConfig{Buckets: map[string]string{"photos": "10.0.0.2:9000"}}, false},
+		// Test 7 - owner with unsupported scheme.
+		{federationConfig{Buckets: map[string]string{"photos": "ftp://10.0.0.2:9000"}}, false},
+		// Test 8 - owner with a path.
+		{federationConfig{Buckets: map[string]string{"photos": "http://10.0.0.2:9000/minio"}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d, should pass but it failed with err = %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d, should fail but it succeed.", i+1)
+		}
+	}
+}
+
+// Tests that buckets owned by this server are left out of federation.
+func TestNewFederation(t *testing.T) {
+	config := federationConfig{
+		Buckets: map[string]string{
+			"photos": "http://10.0.0.1:9000",
+			"videos": "http://10.0.0.2:9000",
+			"music":  "http://10.0.0.2:9000",
+		},
+	}
+	isLocal := func(u *url.URL) (bool, error) {
+		return u.Host == "10.0.0.1:9000", nil
+	}
+	f, err := newFederation(config, isLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.mode != federationModeProxy {
+		t.Errorf("Expected mode %s, got %s", federationModeProxy, f.mode)
+	}
+	if owner := f.getOwner("photos"); owner != nil {
+		t.Errorf("Expected local bucket photos, got owner %s", owner)
+	}
+	for _, bucket := range []string{"videos", "music"} {
+		if owner := f.getOwner(bucket); owner == nil || owner.String() != "http://10.0.0.2:9000" {
+			t.Errorf("Expected owner http://10.0.0.2:9000 of %s, got %v", bucket, owner)
+		}
+	}
+	if len(f.proxies) != 1 {
+		t.Errorf("Expected 1 proxy, got %d", len(f.proxies))
+	}
+}
+
+// Tests serving requests of federated buckets.
+func TestFederationHandler(t *testing.T) {
+	defer func() { globalFederation = nil }()
+
+	// Owner of bucket "videos" answers with the host it got.
+	ownerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(federationProxiedHeader) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(r.Host + r.URL.RequestURI()))
+	}))
+	defer ownerServer.Close()
+
+	localHandler := setFederationHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		mode         string
+		path         string
+		proxied      bool
+		expectedCode int
+		expectedBody string
+	}{
+		// Test 1 - bucket served locally.
+		{federationModeProxy, "/photos/a.jpg", false, http.StatusOK, ""},
+		// Test 2 - ListBuckets served locally.
+		{federationModeProxy, "/", false, http.StatusOK, ""},
+		// Test 3 - request proxied to the owner, host is kept.
+		{federationModeProxy, "/videos/a.mp4?versionId=1", false, http.StatusAccepted, "localhost:9000/videos/a.mp4?versionId=1"},
+		// Test 4 - request already proxied is served locally.
+		{federationModeProxy, "/videos/a.mp4", true, http.StatusOK, ""},
+		// Test 5 - client redirected to the owner.
+		{federationModeRedirect, "/videos/a.mp4?versionId=1", false, http.StatusTemporaryRedirect, ""},
+	}
+
+	for i, testCase := range testCases {
+		f, err := newFederation(federationConfig{
+			Mode:    testCase.mode,
+			Buckets: map[string]string{"photos": "http://localhost:9000", "videos": ownerServer.URL},
+		}, func(u *url.URL) (bool, error) { return u.Host == "localhost:9000", nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		globalFederation = f
+
+		req, err := http.NewRequest("GET", "http://localhost:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.proxied {
+			req.Header.Set(federationProxiedHeader, "true")
+		}
+		rec := httptest.NewRecorder()
+		localHandler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedCode, rec.Code)
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: expected body %s, got %s", i+1, testCase.expectedBody, rec.Body.String())
+		}
+		if testCase.mode == federationModeRedirect {
+			if location := rec.Header().Get("Location"); location != ownerServer.URL+testCase.path {
+				t.Errorf("Test %d: expected location %s, got %s", i+1, ownerServer.URL+testCase.path, location)
+			}
+		}
+	}
+}
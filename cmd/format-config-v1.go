@@ -36,6 +36,10 @@ type xlFormat struct {
 	// JBOD field carries the input disk order generated the first
 	// time when fresh disks were supplied.
 	JBOD []string `json:"jbod"`
+	// ExpandedFrom carries the number of disks of the setup before
+	// fresh disks were added to it, until objects are rebalanced
+	// across all disks.
+	ExpandedFrom int `json:"expandedFrom,omitempty"`
 }
 
 // formatConfigV1 - structure holds format config version '1'.
@@ -323,6 +327,77 @@ func checkJBODConsistency(formatConfigs []*formatConfigV1) error {
 	return nil
 }
 
+// errDuplicateDisk - returned when the same disk is reachable through
+// more than one endpoint.
+func errDuplicateDisk(disk1, disk2 StorageAPI) error {
+	return fmt.Errorf("%s and %s refer to the same disk, each disk should be listed only once", disk1, disk2)
+}
+
+// checkDuplicateDisks - validates that no two formatted disks carry the
+// same disk UUID, which is the case when the same disk is listed twice
+// under different endpoints or claimed by two nodes.
+func checkDuplicateDisks(storageDisks []StorageAPI, formatConfigs []*formatConfigV1) error {
+	diskIndexes := make(map[string]int)
+	for index, format := range formatConfigs {
+		if format == nil || format.XL == nil {
+			continue
+		}
+		if prevIndex, ok := diskIndexes[format.XL.Disk]; ok {
+			return errDuplicateDisk(storageDisks[prevIndex], storageDisks[index])
+		}
+		diskIndexes[format.XL.Disk] = index
+	}
+	return nil
+}
+
+// handshakeDisks - detects fresh disks reachable through more than one
+// endpoint, before they are formatted. Every disk saves a file named
+// after a unique id, a disk which lists files saved by any other disk
+// is the same disk as the other one.
+func handshakeDisks(storageDisks []StorageAPI) error {
+	handshakeDir := mustGetUUID()
+	handshakeIDs := make([]string, len(storageDisks))
+	defer func() {
+		// Purge handshake files, okay to ignore errors here.
+		for index, disk := range storageDisks {
+			if disk == nil || handshakeIDs[index] == "" {
+				continue
+			}
+			disk.DeleteFile(minioMetaTmpBucket, pathJoin(handshakeDir, handshakeIDs[index]))
+		}
+	}()
+
+	for index, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		handshakeID := mustGetUUID()
+		if err := disk.AppendFile(minioMetaTmpBucket, pathJoin(handshakeDir, handshakeID), []byte(handshakeID)); err != nil {
+			return err
+		}
+		handshakeIDs[index] = handshakeID
+	}
+
+	for index, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(minioMetaTmpBucket, handshakeDir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if otherIndex := findDiskIndex(entry, handshakeIDs); otherIndex != -1 && otherIndex != index {
+				if otherIndex > index {
+					return errDuplicateDisk(disk, storageDisks[otherIndex])
+				}
+				return errDuplicateDisk(storageDisks[otherIndex], disk)
+			}
+		}
+	}
+	return nil
+}
+
 // findDiskIndex returns position of disk in JBOD.
 func findDiskIndex(disk string, jbod []string) int {
 	for index, uuid := range jbod {
@@ -496,9 +571,10 @@ func healFormatXLFreshDisks(storageDisks []StorageAPI) error {
 			Version: referenceConfig.Version,
 			Format:  referenceConfig.Format,
 			XL: &xlFormat{
-				Version: referenceConfig.XL.Version,
-				Disk:    newJBOD[index],
-				JBOD:    newJBOD,
+				Version:      referenceConfig.XL.Version,
+				Disk:         newJBOD[index],
+				JBOD:         newJBOD,
+				ExpandedFrom: referenceConfig.XL.ExpandedFrom,
 			},
 		}
 		newFormatConfigs[index] = config
@@ -704,9 +780,10 @@ func healFormatXLCorruptedDisks(storageDisks []StorageAPI) error {
 			Version: referenceConfig.Version,
 			Format:  referenceConfig.Format,
 			XL: &xlFormat{
-				Version: referenceConfig.XL.Version,
-				Disk:    newJBOD[index],
-				JBOD:    newJBOD,
+				Version:      referenceConfig.XL.Version,
+				Disk:         newJBOD[index],
+				JBOD:         newJBOD,
+				ExpandedFrom: referenceConfig.XL.ExpandedFrom,
 			},
 		}
 		newFormatConfigs[index] = config
@@ -899,6 +976,111 @@ func initFormatXL(storageDisks []StorageAPI) (err error) {
 		return fmt.Errorf("Unable to initialize '.minio.sys' meta volume, %s", err)
 	}
 
+	// Fail before formatting if the same disk is listed more than once.
+	if err := handshakeDisks(storageDisks); err != nil {
+		return err
+	}
+
 	// Save formats `format.json` across all disks.
 	return saveFormatXL(storageDisks, formats)
 }
+
+// isFormatXLExpansion - returns true if the setup has more disks than
+// its backend format, i.e. fresh disks were added to the setup.
+func isFormatXLExpansion(formatConfigs []*formatConfigV1) bool {
+	found := false
+	for _, formatXL := range formatConfigs {
+		if formatXL == nil {
+			continue
+		}
+		if formatXL.XL == nil || len(formatXL.XL.JBOD) >= len(formatConfigs) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// expandFormatXL - adds fresh disks to a formatted setup, their uuids
+// are appended to the JBOD so that existing disks keep their order.
+// Objects stay on the previous disks until they are rebalanced, all
+// previous disks have to be online.
+func expandFormatXL(storageDisks []StorageAPI, formatConfigs []*formatConfigV1, sErrs []error) error {
+	for _, formatXL := range formatConfigs {
+		if formatXL == nil {
+			continue
+		}
+		if err := checkFormatXLValue(formatXL); err != nil {
+			return err
+		}
+	}
+	if err := checkJBODConsistency(formatConfigs); err != nil {
+		return err
+	}
+	if err := checkDisksConsistency(formatConfigs); err != nil {
+		return err
+	}
+
+	var referenceConfig *formatConfigV1
+	formatted := 0
+	for index, formatXL := range formatConfigs {
+		if formatXL != nil {
+			referenceConfig = formatXL
+			formatted++
+			continue
+		}
+		if sErrs[index] != errUnformattedDisk {
+			return fmt.Errorf("Disk %s is neither a disk of this setup nor a fresh disk, unable to add disks", storageDisks[index])
+		}
+	}
+	jbod := referenceConfig.XL.JBOD
+	if referenceConfig.XL.ExpandedFrom != 0 {
+		return fmt.Errorf("Disks added to this setup before are not rebalanced yet, unable to add disks")
+	}
+	if formatted != len(jbod) {
+		return fmt.Errorf("Only %d of %d disks of this setup were found, unable to add disks", formatted, len(jbod))
+	}
+
+	// Disks of the setup keep their position, fresh disks are
+	// appended in the order of the command line.
+	newJBOD := append([]string{}, jbod...)
+	orderedDisks := make([]StorageAPI, len(storageDisks))
+	for index, formatXL := range formatConfigs {
+		if formatXL != nil {
+			orderedDisks[findDiskIndex(formatXL.XL.Disk, jbod)] = storageDisks[index]
+		}
+	}
+	for index, formatXL := range formatConfigs {
+		if formatXL == nil {
+			orderedDisks[len(newJBOD)] = storageDisks[index]
+			newJBOD = append(newJBOD, mustGetUUID())
+		}
+	}
+
+	newFormatConfigs := make([]*formatConfigV1, len(orderedDisks))
+	for index := range orderedDisks {
+		newFormatConfigs[index] = &formatConfigV1{
+			Version: referenceConfig.Version,
+			Format:  referenceConfig.Format,
+			XL: &xlFormat{
+				Version:      referenceConfig.XL.Version,
+				Disk:         newJBOD[index],
+				JBOD:         newJBOD,
+				ExpandedFrom: len(jbod),
+			},
+		}
+	}
+
+	// Initialize meta volume, if volume already exists ignores it.
+	if err := initMetaVolume(orderedDisks); err != nil {
+		return fmt.Errorf("Unable to initialize '.minio.sys' meta volume, %s", err)
+	}
+
+	// Fail before formatting if the same disk is listed more than once.
+	if err := handshakeDisks(orderedDisks); err != nil {
+		return err
+	}
+
+	// Save new `format.json` across all disks, in JBOD order.
+	return saveFormatXL(orderedDisks, newFormatConfigs)
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -1037,3 +1038,90 @@ func TestIsFormatNotFound(t *testing.T) {
 		t.Fatal("isFormatFound() should not return false")
 	}
 }
+
+// Tests detecting formatted disks listed more than once.
+func TestCheckDuplicateDisks(t *testing.T) {
+	storageDisks := make([]StorageAPI, 8)
+	for index := range storageDisks {
+		storageDisks[index] = &posix{diskPath: fmt.Sprintf("/mnt/disk%d", index+1)}
+	}
+
+	// Valid formats.
+	formatConfigs := genFormatXLValid()
+	if err := checkDuplicateDisks(storageDisks, formatConfigs); err != nil {
+		t.Fatalf("Expected success, got %s", err)
+	}
+
+	// Formats missing on some disks.
+	formatConfigs[2] = nil
+	formatConfigs[5] = nil
+	if err := checkDuplicateDisks(storageDisks, formatConfigs); err != nil {
+		t.Fatalf("Expected success, got %s", err)
+	}
+
+	// Disk 7 carries the same format as disk 4.
+	formatConfigs[6] = formatConfigs[3]
+	expectedErr := errDuplicateDisk(storageDisks[3], storageDisks[6])
+	if err := checkDuplicateDisks(storageDisks, formatConfigs); err == nil || err.Error() != expectedErr.Error() {
+		t.Fatalf("Expected %s, got %v", expectedErr, err)
+	}
+}
+
+// Tests detecting fresh disks listed more than once.
+func TestHandshakeDisks(t *testing.T) {
+	fsDirs, err := getRandomDisks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	testCases := []struct {
+		diskPaths   []string
+		expectedErr error
+	}{
+		// Test case - 1, all disks are unique.
+		{fsDirs, nil},
+		// Test case - 2, first disk listed twice.
+		{[]string{fsDirs[0], fsDirs[1], fsDirs[0], fsDirs[3]}, fmt.Errorf("%s and %s refer to the same disk, each disk should be listed only once", fsDirs[0], fsDirs[0])},
+		// Test case - 3, last two disks are the same.
+		{[]string{fsDirs[0], fsDirs[1], fsDirs[2], fsDirs[2]}, fmt.Errorf("%s and %s refer to the same disk, each disk should be listed only once", fsDirs[2], fsDirs[2])},
+	}
+
+	for i, testCase := range testCases {
+		storageDisks := make([]StorageAPI, len(testCase.diskPaths))
+		for index, diskPath := range testCase.diskPaths {
+			if storageDisks[index], err = newPosix(diskPath); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = initMetaVolume(storageDisks); err != nil {
+			t.Fatal(err)
+		}
+
+		err = handshakeDisks(storageDisks)
+		if testCase.expectedErr == nil && err != nil {
+			t.Fatalf("Test %d: expected success, got %s", i+1, err)
+		}
+		if testCase.expectedErr != nil && (err == nil || err.Error() != testCase.expectedErr.Error()) {
+			t.Fatalf("Test %d: expected %s, got %v", i+1, testCase.expectedErr, err)
+		}
+
+		// Formatting must fail the same way.
+		if testCase.expectedErr != nil {
+			if err = initFormatXL(storageDisks); err == nil || err.Error() != testCase.expectedErr.Error() {
+				t.Fatalf("Test %d: expected %s, got %v", i+1, testCase.expectedErr, err)
+			}
+		}
+
+		// Handshake files must be purged.
+		for _, disk := range storageDisks {
+			entries, err := disk.ListDir(minioMetaTmpBucket, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Fatalf("Test %d: expected no leftover handshake files, found %v", i+1, entries)
+			}
+		}
+	}
+}
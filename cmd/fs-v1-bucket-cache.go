@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Duration for which a known bucket is trusted without looking at
+// the disk, buckets removed from the disk by other means than
+// DeleteBucket are noticed within it.
+const fsBucketCacheTTL = 10 * time.Second
+
+// fsBucketCacheEntry - cached stat of a bucket directory.
+type fsBucketCacheEntry struct {
+	fi     os.FileInfo
+	loaded time.Time
+}
+
+// fsBucketCache - caches known buckets of FS backend so that bucket
+// existence checks done through GetBucketInfo, such as HEAD Bucket,
+// don't stat the bucket directory each time. Buckets are refreshed on
+// MakeBucket, DeleteBucket and ListBuckets, unknown buckets are always
+// looked up on the disk.
+type fsBucketCache struct {
+	sync.RWMutex
+	ttl     time.Duration
+	buckets map[string]fsBucketCacheEntry
+}
+
+// newFSBucketCache - returns an empty bucket cache whose entries
+// expire after ttl.
+func newFSBucketCache(ttl time.Duration) *fsBucketCache {
+	return &fsBucketCache{
+		ttl:     ttl,
+		buckets: make(map[string]fsBucketCacheEntry),
+	}
+}
+
+// get - returns stat of a known bucket, false if the bucket is
+// unknown or its entry expired.
+func (c *fsBucketCache) get(bucket string) (os.FileInfo, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.RLock()
+	entry, ok := c.buckets[bucket]
+	c.RUnlock()
+	if !ok || UTCNow().Sub(entry.loaded) >= c.ttl {
+		return nil, false
+	}
+	return entry.fi, true
+}
+
+// set - caches stat of an existing bucket.
+func (c *fsBucketCache) set(bucket string, fi os.FileInfo) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.buckets[bucket] = fsBucketCacheEntry{fi: fi, loaded: UTCNow()}
+}
+
+// remove - forgets a deleted bucket.
+func (c *fsBucketCache) remove(bucket string) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	delete(c.buckets, bucket)
+}
+
+// reset - replaces all known buckets with the given ones, as found
+// by a full listing of the disk.
+func (c *fsBucketCache) reset(buckets map[string]os.FileInfo) {
+	if c == nil {
+		return
+	}
+	now := UTCNow()
+	entries := make(map[string]fsBucketCacheEntry, len(buckets))
+	for bucket, fi := range buckets {
+		entries[bucket] = fsBucketCacheEntry{fi: fi, loaded: now}
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.buckets = entries
+}
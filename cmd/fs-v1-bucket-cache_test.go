@@ -0,0 +1,124 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests caching, expiring and forgetting buckets.
+func TestFSBucketCache(t *testing.T) {
+	fi, err := os.Stat(globalTestTmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newFSBucketCache(time.Hour)
+	if _, ok := c.get("bucket"); ok {
+		t.Fatal("Expected unknown bucket")
+	}
+	c.set("bucket", fi)
+	if st, ok := c.get("bucket"); !ok || st != fi {
+		t.Fatal("Expected known bucket")
+	}
+	c.remove("bucket")
+	if _, ok := c.get("bucket"); ok {
+		t.Fatal("Expected removed bucket to be unknown")
+	}
+
+	c.set("bucket", fi)
+	c.reset(map[string]os.FileInfo{"other": fi})
+	if _, ok := c.get("bucket"); ok {
+		t.Fatal("Expected bucket to be unknown after reset")
+	}
+	if _, ok := c.get("other"); !ok {
+		t.Fatal("Expected bucket to be known after reset")
+	}
+
+	// Expired buckets are unknown.
+	c = newFSBucketCache(0)
+	c.set("bucket", fi)
+	if _, ok := c.get("bucket"); ok {
+		t.Fatal("Expected expired bucket to be unknown")
+	}
+
+	// Nil cache knows no bucket.
+	var nilCache *fsBucketCache
+	nilCache.set("bucket", fi)
+	if _, ok := nilCache.get("bucket"); ok {
+		t.Fatal("Expected nil cache to know no bucket")
+	}
+}
+
+// Tests buckets of FS backend are refreshed on make, delete and list.
+func TestFSBucketCacheRefresh(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(*fsObjects)
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fs.bucketCache.get(bucket); !ok {
+		t.Fatal("Expected new bucket to be known")
+	}
+	if err := obj.DeleteBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fs.bucketCache.get(bucket); ok {
+		t.Fatal("Expected deleted bucket to be unknown")
+	}
+	if _, err := obj.GetBucketInfo(bucket); !isSameType(errorCause(err), BucketNotFound{}) {
+		t.Fatalf("Expected BucketNotFound, got %v", err)
+	}
+
+	// Buckets made on the disk are looked up and cached.
+	if err := os.Mkdir(filepath.Join(disk, bucket), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.GetBucketInfo(bucket); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fs.bucketCache.get(bucket); !ok {
+		t.Fatal("Expected looked up bucket to be known")
+	}
+
+	// Buckets removed from the disk are forgotten by listings and
+	// object operations.
+	if err := os.Remove(filepath.Join(disk, bucket)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.ListBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fs.bucketCache.get(bucket); ok {
+		t.Fatal("Expected bucket to be forgotten by listing")
+	}
+	fs.bucketCache.set(bucket, nil)
+	if _, err := obj.GetObjectInfo(bucket, "object"); !isSameType(errorCause(err), BucketNotFound{}) {
+		t.Fatalf("Expected BucketNotFound, got %v", err)
+	}
+	if _, ok := fs.bucketCache.get(bucket); ok {
+		t.Fatal("Expected bucket to be forgotten by object operation")
+	}
+}
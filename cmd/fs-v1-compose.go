@@ -0,0 +1,44 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "io"
+
+// ComposeObject - composes an object from objects of the same bucket.
+// Data of the sources is read from their files directly, without
+// taking the read lock on their `fs.json`, so that the object can
+// be one of its own sources while PutObject holds the write lock on
+// its `fs.json`.
+func (fs fsObjects) ComposeObject(bucket string, srcObjects []string, object string, metadata map[string]string) (ObjectInfo, error) {
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+
+	var size int64
+	readers := make([]io.Reader, len(srcObjects))
+	for i, srcObject := range srcObjects {
+		reader, srcSize, err := fsOpenFile(pathJoin(fs.fsPath, bucket, srcObject), 0)
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, srcObject)
+		}
+		defer reader.Close()
+		readers[i] = reader
+		size += srcSize
+	}
+
+	return fs.PutObject(bucket, object, size, io.MultiReader(readers...), metadata, "")
+}
@@ -0,0 +1,530 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Directory in minioMetaBucket indexes of buckets are saved in,
+	// as '.minio.sys/index/bucket/objects.idx'.
+	fsIndexDir = "index"
+
+	// Index of object names of a bucket.
+	fsIndexObjectsFile = "objects.idx"
+
+	// Index of names of objects with multipart uploads of a bucket.
+	fsIndexUploadsFile = "uploads.idx"
+
+	// Present in fsIndexDir while indexes are in use, indexes are
+	// rebuilt on start if the server didn't shut down cleanly.
+	fsIndexDirtyFile = "dirty"
+
+	// First line of saved indexes.
+	fsIndexHeader = "minio-fs-index 1"
+
+	// Names are kept in chunks of up to twice this size, so that
+	// adding and removing names only moves names of a single chunk.
+	fsIndexChunkSize = 512
+)
+
+// Internal error used to signal a saved index can't be used.
+var errFSIndexCorrupted = errors.New("Corrupted FS index")
+
+// fsIndex - sorted set of names of a bucket, either object names or
+// names of objects with multipart uploads.
+type fsIndex struct {
+	mu     sync.RWMutex
+	chunks [][]string
+
+	// Set once the index has all the names of the backend, listings
+	// walk the backend until then.
+	ready bool
+
+	// Names removed while the index is rebuilt, not to be added back
+	// by the rebuild.
+	removed map[string]struct{}
+}
+
+// newFSIndex - returns an index of sorted names.
+func newFSIndex(names []string) *fsIndex {
+	idx := &fsIndex{}
+	for len(names) > 0 {
+		n := fsIndexChunkSize
+		if n > len(names) {
+			n = len(names)
+		}
+		idx.chunks = append(idx.chunks, names[:n:n])
+		names = names[n:]
+	}
+	return idx
+}
+
+// isReady - returns true if the index can be used for listings.
+func (idx *fsIndex) isReady() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.ready
+}
+
+// insert - adds name to the index, must be called with mu locked.
+func (idx *fsIndex) insert(name string) {
+	// Pick the first chunk which has a name not below name, or the
+	// last chunk if name is above all of them.
+	i := sort.Search(len(idx.chunks), func(i int) bool {
+		chunk := idx.chunks[i]
+		return chunk[len(chunk)-1] >= name
+	})
+	if i == len(idx.chunks) {
+		if i == 0 {
+			idx.chunks = [][]string{{name}}
+			return
+		}
+		i--
+	}
+
+	chunk := idx.chunks[i]
+	j := sort.SearchStrings(chunk, name)
+	if j < len(chunk) && chunk[j] == name {
+		return
+	}
+	chunk = append(chunk, "")
+	copy(chunk[j+1:], chunk[j:])
+	chunk[j] = name
+	if len(chunk) <= 2*fsIndexChunkSize {
+		idx.chunks[i] = chunk
+		return
+	}
+
+	// Split full chunks in two.
+	left := append([]string(nil), chunk[:fsIndexChunkSize]...)
+	right := append([]string(nil), chunk[fsIndexChunkSize:]...)
+	idx.chunks = append(idx.chunks, nil)
+	copy(idx.chunks[i+2:], idx.chunks[i+1:])
+	idx.chunks[i] = left
+	idx.chunks[i+1] = right
+}
+
+// add - adds name to the index.
+func (idx *fsIndex) add(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.removed != nil {
+		delete(idx.removed, name)
+	}
+	idx.insert(name)
+}
+
+// addRebuilt - adds name found by the rebuild of the index, unless
+// it was removed in the meantime.
+func (idx *fsIndex) addRebuilt(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.removed[name]; !ok {
+		idx.insert(name)
+	}
+}
+
+// remove - removes name from the index.
+func (idx *fsIndex) remove(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.removed != nil {
+		idx.removed[name] = struct{}{}
+	}
+	i := sort.Search(len(idx.chunks), func(i int) bool {
+		chunk := idx.chunks[i]
+		return chunk[len(chunk)-1] >= name
+	})
+	if i == len(idx.chunks) {
+		return
+	}
+	chunk := idx.chunks[i]
+	j := sort.SearchStrings(chunk, name)
+	if j == len(chunk) || chunk[j] != name {
+		return
+	}
+	if len(chunk) == 1 {
+		idx.chunks = append(idx.chunks[:i], idx.chunks[i+1:]...)
+		return
+	}
+	idx.chunks[i] = append(chunk[:j], chunk[j+1:]...)
+}
+
+// ceiling - returns the first name not below key, or above key if
+// orEqual is false. Returns false if there is no such name.
+func (idx *fsIndex) ceiling(key string, orEqual bool) (string, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	above := func(name string) bool {
+		if orEqual {
+			return name >= key
+		}
+		return name > key
+	}
+	i := sort.Search(len(idx.chunks), func(i int) bool {
+		chunk := idx.chunks[i]
+		return above(chunk[len(chunk)-1])
+	})
+	if i == len(idx.chunks) {
+		return "", false
+	}
+	chunk := idx.chunks[i]
+	j := sort.Search(len(chunk), func(j int) bool {
+		return above(chunk[j])
+	})
+	return chunk[j], true
+}
+
+// names - returns all the names of the index in order.
+func (idx *fsIndex) names() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var names []string
+	for _, chunk := range idx.chunks {
+		names = append(names, chunk...)
+	}
+	return names
+}
+
+// startWalk - like startTreeWalk, streams names of the index after
+// marker which have the given prefix. Non recursive walks fold names
+// under a directory of the prefix into the directory, with a
+// trailing slash. Streamed entries are prefixed with entryPrefix.
+func (idx *fsIndex) startWalk(entryPrefix, prefix, marker string, recursive bool, endWalkCh chan struct{}) chan treeWalkResult {
+	resultCh := make(chan treeWalkResult, maxObjectList)
+
+	key, orEqual := prefix, true
+	if marker >= prefix {
+		key, orEqual = marker, false
+	}
+	// next - returns the entry after the previous one.
+	next := func() (string, bool) {
+		for {
+			name, ok := idx.ceiling(key, orEqual)
+			if !ok || !hasPrefix(name, prefix) {
+				return "", false
+			}
+			key, orEqual = name, false
+			if recursive {
+				return name, true
+			}
+			i := strings.Index(name[len(prefix):], slashSeparator)
+			if i == -1 {
+				return name, true
+			}
+			// Names with the directory as prefix are sorted
+			// before the directory with '/' replaced by '0'.
+			dir := name[:len(prefix)+i+1]
+			key, orEqual = dir[:len(dir)-1]+"0", true
+			// Directories up to the marker were already listed.
+			if dir > marker {
+				return dir, true
+			}
+		}
+	}
+
+	go func() {
+		defer close(resultCh)
+		entry, ok := next()
+		for ok {
+			var nextEntry string
+			nextEntry, ok = next()
+			select {
+			case <-endWalkCh:
+				return
+			case resultCh <- treeWalkResult{entry: entryPrefix + entry, end: !ok}:
+			}
+			entry = nextEntry
+		}
+	}()
+	return resultCh
+}
+
+// save - writes names of the index to the given file.
+func (idx *fsIndex) save(filePath, tmpPath string) error {
+	names := idx.names()
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	writer.WriteString(fsIndexHeader + "\n")
+	for _, name := range names {
+		writer.WriteString(strconv.Quote(name) + "\n")
+	}
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// loadFSIndex - reads an index written by save.
+func loadFSIndex(filePath string) (*fsIndex, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024)
+	if !scanner.Scan() || scanner.Text() != fsIndexHeader {
+		return nil, errFSIndexCorrupted
+	}
+	for scanner.Scan() {
+		name, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			return nil, errFSIndexCorrupted
+		}
+		// Names are saved in order, anything else is corrupted.
+		if len(names) > 0 && names[len(names)-1] >= name {
+			return nil, errFSIndexCorrupted
+		}
+		names = append(names, name)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newFSIndex(names), nil
+}
+
+// fsBucketIndex - indexes of a bucket.
+type fsBucketIndex struct {
+	objects *fsIndex
+	uploads *fsIndex
+}
+
+// fsIndexes - sorted indexes of object names and names of objects with
+// multipart uploads of the buckets of FS backend, used by listings
+// instead of walking the backend. Indexes are kept in memory, updated
+// along with the backend and saved on shutdown.
+type fsIndexes struct {
+	sync.Mutex
+	fs      *fsObjects
+	buckets map[string]*fsBucketIndex
+}
+
+// newFSIndexes - loads indexes of all the buckets, indexes which
+// can't be loaded are rebuilt in background.
+func newFSIndexes(fs *fsObjects) (*fsIndexes, error) {
+	f := &fsIndexes{
+		fs:      fs,
+		buckets: make(map[string]*fsBucketIndex),
+	}
+
+	indexDir := pathJoin(fs.fsPath, minioMetaBucket, fsIndexDir)
+	if err := mkdirAll(indexDir, 0777); err != nil {
+		return nil, err
+	}
+
+	// Indexes are only valid if they were saved on a clean shutdown.
+	dirtyPath := pathJoin(indexDir, fsIndexDirtyFile)
+	_, err := os.Stat(dirtyPath)
+	isDirty := err == nil
+
+	buckets, err := fs.ListBuckets()
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		bucketIndex := &fsBucketIndex{}
+		bucketDir := pathJoin(indexDir, bucket.Name)
+		if !isDirty {
+			bucketIndex.objects, _ = loadFSIndex(pathJoin(bucketDir, fsIndexObjectsFile))
+			bucketIndex.uploads, _ = loadFSIndex(pathJoin(bucketDir, fsIndexUploadsFile))
+		}
+		if bucketIndex.objects == nil || bucketIndex.uploads == nil {
+			bucketIndex.objects = &fsIndex{removed: make(map[string]struct{})}
+			bucketIndex.uploads = &fsIndex{removed: make(map[string]struct{})}
+			go f.rebuild(bucket.Name, bucketIndex)
+		} else {
+			bucketIndex.objects.ready = true
+			bucketIndex.uploads.ready = true
+		}
+		f.buckets[bucket.Name] = bucketIndex
+	}
+
+	// Saved indexes are out of date once objects are modified.
+	dirtyFile, err := os.Create(dirtyPath)
+	if err != nil {
+		return nil, err
+	}
+	dirtyFile.Close()
+	return f, nil
+}
+
+// rebuild - walks objects and multipart uploads of a bucket to fill
+// its indexes, which are ready once done.
+func (f *fsIndexes) rebuild(bucket string, bucketIndex *fsBucketIndex) {
+	rebuildIndex := func(idx *fsIndex, walkBucket, prefix string, isLeaf isLeafFunc) error {
+		endWalkCh := make(chan struct{})
+		defer close(endWalkCh)
+		walkResultCh := startTreeWalk(walkBucket, prefix, "", true, f.fs.listDirFactory(isLeaf), isLeaf, endWalkCh)
+		for walkResult := range walkResultCh {
+			if walkResult.err != nil {
+				if isErrIgnored(walkResult.err, fsTreeWalkIgnoredErrs...) {
+					break
+				}
+				return walkResult.err
+			}
+			idx.addRebuilt(strings.TrimPrefix(walkResult.entry, prefix))
+		}
+		idx.mu.Lock()
+		idx.ready = true
+		idx.removed = nil
+		idx.mu.Unlock()
+		return nil
+	}
+
+	isObject := func(bucket, object string) bool {
+		return !hasSuffix(object, slashSeparator)
+	}
+	err := rebuildIndex(bucketIndex.objects, bucket, "", isObject)
+	if err == nil {
+		err = rebuildIndex(bucketIndex.uploads, minioMetaMultipartBucket, retainSlash(bucket), f.fs.isMultipartUpload)
+	}
+	errorIf(err, "Unable to rebuild index of bucket %s", bucket)
+}
+
+// get - returns indexes of a bucket, nil if the bucket is not indexed.
+func (f *fsIndexes) get(bucket string) *fsBucketIndex {
+	if f == nil {
+		return nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	return f.buckets[bucket]
+}
+
+// getReady - returns the objects or uploads index of a bucket if it
+// can be used for listings, nil otherwise.
+func (f *fsIndexes) getReady(bucket string, uploads bool) *fsIndex {
+	bucketIndex := f.get(bucket)
+	if bucketIndex == nil {
+		return nil
+	}
+	idx := bucketIndex.objects
+	if uploads {
+		idx = bucketIndex.uploads
+	}
+	if !idx.isReady() {
+		return nil
+	}
+	return idx
+}
+
+// addObject - indexes a new object.
+func (f *fsIndexes) addObject(bucket, object string) {
+	if bucketIndex := f.get(bucket); bucketIndex != nil {
+		bucketIndex.objects.add(object)
+	}
+}
+
+// removeObject - removes a deleted object from the index.
+func (f *fsIndexes) removeObject(bucket, object string) {
+	if bucketIndex := f.get(bucket); bucketIndex != nil {
+		bucketIndex.objects.remove(object)
+	}
+}
+
+// addUpload - indexes an object with multipart uploads.
+func (f *fsIndexes) addUpload(bucket, object string) {
+	if bucketIndex := f.get(bucket); bucketIndex != nil {
+		bucketIndex.uploads.add(object)
+	}
+}
+
+// removeUpload - removes an object without multipart uploads left
+// from the index.
+func (f *fsIndexes) removeUpload(bucket, object string) {
+	if bucketIndex := f.get(bucket); bucketIndex != nil {
+		bucketIndex.uploads.remove(object)
+	}
+}
+
+// makeBucket - starts empty indexes of a new bucket.
+func (f *fsIndexes) makeBucket(bucket string) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	f.buckets[bucket] = &fsBucketIndex{
+		objects: &fsIndex{ready: true},
+		uploads: &fsIndex{ready: true},
+	}
+}
+
+// deleteBucket - removes indexes of a deleted bucket.
+func (f *fsIndexes) deleteBucket(bucket string) {
+	if f == nil {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	delete(f.buckets, bucket)
+	fsRemoveAll(pathJoin(f.fs.fsPath, minioMetaBucket, fsIndexDir, bucket))
+}
+
+// save - saves indexes of all the buckets, saved indexes are used on
+// next start if all of them are ready.
+func (f *fsIndexes) save() error {
+	if f == nil {
+		return nil
+	}
+	f.Lock()
+	defer f.Unlock()
+
+	indexDir := pathJoin(f.fs.fsPath, minioMetaBucket, fsIndexDir)
+	tmpPath := pathJoin(f.fs.fsPath, minioMetaTmpBucket, f.fs.fsUUID, fsIndexDir)
+	for bucket, bucketIndex := range f.buckets {
+		if !bucketIndex.objects.isReady() || !bucketIndex.uploads.isReady() {
+			// Rebuild is not done yet, rebuild again on next start.
+			return nil
+		}
+		bucketDir := pathJoin(indexDir, bucket)
+		if err := mkdirAll(bucketDir, 0777); err != nil {
+			return err
+		}
+		if err := bucketIndex.objects.save(pathJoin(bucketDir, fsIndexObjectsFile), tmpPath); err != nil {
+			return err
+		}
+		if err := bucketIndex.uploads.save(pathJoin(bucketDir, fsIndexUploadsFile), tmpPath); err != nil {
+			return err
+		}
+	}
+	return os.Remove(pathJoin(indexDir, fsIndexDirtyFile))
+}
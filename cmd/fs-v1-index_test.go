@@ -0,0 +1,324 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Tests adding and removing names of an index.
+func TestFSIndexAddRemove(t *testing.T) {
+	idx := newFSIndex(nil)
+	expected := make(map[string]struct{})
+
+	// Enough names to split chunks a few times.
+	for _, i := range rand.Perm(5 * fsIndexChunkSize) {
+		name := fmt.Sprintf("dir%d/object%d", i%7, i)
+		idx.add(name)
+		expected[name] = struct{}{}
+	}
+	for i := 0; i < 5*fsIndexChunkSize; i += 3 {
+		name := fmt.Sprintf("dir%d/object%d", i%7, i)
+		idx.remove(name)
+		delete(expected, name)
+	}
+	// Removing and adding again is harmless.
+	idx.remove("not-indexed")
+	idx.add("dir0/object0")
+	idx.add("dir0/object0")
+	expected["dir0/object0"] = struct{}{}
+
+	var expectedNames []string
+	for name := range expected {
+		expectedNames = append(expectedNames, name)
+	}
+	sort.Strings(expectedNames)
+	if names := idx.names(); !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected %d sorted names, got %d", len(expectedNames), len(names))
+	}
+	for _, chunk := range idx.chunks {
+		if len(chunk) == 0 || len(chunk) > 2*fsIndexChunkSize {
+			t.Fatalf("Unexpected chunk size %d", len(chunk))
+		}
+	}
+}
+
+// Tests ceiling lookups of an index.
+func TestFSIndexCeiling(t *testing.T) {
+	idx := newFSIndex([]string{"a", "b/c", "b/d", "e"})
+
+	testCases := []struct {
+		key      string
+		orEqual  bool
+		expected string
+		found    bool
+	}{
+		{"", true, "a", true},
+		{"a", true, "a", true},
+		{"a", false, "b/c", true},
+		{"b/", true, "b/c", true},
+		{"b/d", false, "e", true},
+		{"e", true, "e", true},
+		{"e", false, "", false},
+		{"f", true, "", false},
+	}
+	for i, testCase := range testCases {
+		name, found := idx.ceiling(testCase.key, testCase.orEqual)
+		if name != testCase.expected || found != testCase.found {
+			t.Errorf("Test %d: Expected (%s, %v), got (%s, %v)", i+1, testCase.expected, testCase.found, name, found)
+		}
+	}
+}
+
+// Tests walking an index.
+func TestFSIndexStartWalk(t *testing.T) {
+	idx := newFSIndex([]string{"a", "b/c", "b/d/e", "b0", "c/d", "c/e/f"})
+
+	testCases := []struct {
+		prefix    string
+		marker    string
+		recursive bool
+		expected  []string
+	}{
+		{"", "", true, []string{"a", "b/c", "b/d/e", "b0", "c/d", "c/e/f"}},
+		{"", "", false, []string{"a", "b/", "b0", "c/"}},
+		{"b/", "", false, []string{"b/c", "b/d/"}},
+		{"b", "", false, []string{"b/", "b0"}},
+		{"", "b/", false, []string{"b0", "c/"}},
+		{"", "b/c", false, []string{"b0", "c/"}},
+		{"", "b/c", true, []string{"b/d/e", "b0", "c/d", "c/e/f"}},
+		{"c/", "a", true, []string{"c/d", "c/e/f"}},
+		{"d", "", true, nil},
+	}
+	for i, testCase := range testCases {
+		var entries []string
+		var end bool
+		for result := range idx.startWalk("", testCase.prefix, testCase.marker, testCase.recursive, make(chan struct{})) {
+			entries = append(entries, result.entry)
+			end = result.end
+		}
+		if !reflect.DeepEqual(entries, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, entries)
+		}
+		if len(entries) > 0 && !end {
+			t.Errorf("Test %d: Expected last entry to end the walk", i+1)
+		}
+	}
+}
+
+// Tests saving and loading an index.
+func TestFSIndexSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	names := []string{"a", "b\nc", "d/\"e\"", "f/g"}
+	indexPath := filepath.Join(dir, fsIndexObjectsFile)
+	if err = newFSIndex(names).save(indexPath, filepath.Join(dir, "tmp")); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := loadFSIndex(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(idx.names(), names) {
+		t.Fatalf("Expected %v, got %v", names, idx.names())
+	}
+
+	// Unsorted or malformed indexes can't be loaded.
+	corrupted := []string{
+		"",
+		"minio-fs-index 2\n",
+		fsIndexHeader + "\n\"b\"\n\"a\"\n",
+		fsIndexHeader + "\na\n",
+	}
+	for i, data := range corrupted {
+		if err = ioutil.WriteFile(indexPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = loadFSIndex(indexPath); err != errFSIndexCorrupted {
+			t.Errorf("Test %d: Expected %s, got %v", i+1, errFSIndexCorrupted, err)
+		}
+	}
+}
+
+// Tests listings of FS backend are the same with and without index.
+func TestFSIndexListings(t *testing.T) {
+	defer func(enabled bool) { globalIsFSIndexEnabled = enabled }(globalIsFSIndexEnabled)
+	globalIsFSIndexEnabled = true
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+	fs := obj.(*fsObjects)
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	objects := []string{"a/b", "a/c/d", "a/e", "a0", "b/c/d/e", "b/c0", "c", "d/e"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := obj.NewMultipartUpload(bucket, object, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := obj.DeleteObject(bucket, "a/e"); err != nil {
+		t.Fatal(err)
+	}
+	if idx := fs.index.getReady(bucket, false); idx == nil {
+		t.Fatal("Expected index of a new bucket to be ready")
+	}
+
+	listObjects := func(prefix, marker, delimiter string, maxKeys int) (result []ListObjectsInfo) {
+		for {
+			info, err := obj.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = append(result, info)
+			if !info.IsTruncated {
+				return result
+			}
+			marker = info.NextMarker
+		}
+	}
+	listUploads := func(prefix, keyMarker, delimiter string, maxUploads int) (result []ListMultipartsInfo) {
+		for {
+			info, err := obj.ListMultipartUploads(bucket, prefix, keyMarker, "", delimiter, maxUploads)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range info.Uploads {
+				info.Uploads[i].Initiated = time.Time{}
+			}
+			result = append(result, info)
+			if !info.IsTruncated {
+				return result
+			}
+			keyMarker = info.NextKeyMarker
+		}
+	}
+
+	testCases := []struct {
+		prefix    string
+		marker    string
+		delimiter string
+		maxKeys   int
+	}{
+		{"", "", "", 1000},
+		{"", "", "/", 1000},
+		{"", "", "", 2},
+		{"", "", "/", 1},
+		{"a", "", "/", 1000},
+		{"a/", "", "/", 1},
+		{"b/", "", "", 1},
+		{"", "a/b", "/", 1000},
+		{"", "a0", "", 2},
+		{"z", "", "/", 1000},
+	}
+	index := fs.index
+	for i, testCase := range testCases {
+		fs.index = index
+		indexedObjects := listObjects(testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		indexedUploads := listUploads(testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		fs.index = nil
+		walkedObjects := listObjects(testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		walkedUploads := listUploads(testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+
+		if !reflect.DeepEqual(indexedObjects, walkedObjects) {
+			t.Errorf("Test %d: Expected objects %v, got %v", i+1, walkedObjects, indexedObjects)
+		}
+		if !reflect.DeepEqual(indexedUploads, walkedUploads) {
+			t.Errorf("Test %d: Expected uploads %v, got %v", i+1, walkedUploads, indexedUploads)
+		}
+	}
+}
+
+// Tests indexes are saved on shutdown and rebuilt after an unclean
+// shutdown.
+func TestFSIndexRebuild(t *testing.T) {
+	defer func(enabled bool) { globalIsFSIndexEnabled = enabled }(globalIsFSIndexEnabled)
+	globalIsFSIndexEnabled = true
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+	obj := initFSObjects(disk, t)
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+	objects := []string{"a", "b/c", "b/d/e"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := obj.NewMultipartUpload(bucket, "b/c", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dirtyPath := pathJoin(disk, minioMetaBucket, fsIndexDir, fsIndexDirtyFile)
+	if err := obj.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dirtyPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected dirty marker to be removed on shutdown, got %v", err)
+	}
+
+	// Saved indexes are used right away.
+	fs := initFSObjects(disk, t).(*fsObjects)
+	if idx := fs.index.getReady(bucket, false); idx == nil || !reflect.DeepEqual(idx.names(), objects) {
+		t.Fatalf("Expected saved index with %v", objects)
+	}
+	if idx := fs.index.getReady(bucket, true); idx == nil || !reflect.DeepEqual(idx.names(), []string{"b/c"}) {
+		t.Fatal("Expected saved index with b/c")
+	}
+
+	// Dirty marker is left by an unclean shutdown, indexes are
+	// rebuilt from the backend.
+	if _, err := os.Stat(dirtyPath); err != nil {
+		t.Fatal(err)
+	}
+	fs = initFSObjects(disk, t).(*fsObjects)
+	for i := 0; fs.index.getReady(bucket, false) == nil || fs.index.getReady(bucket, true) == nil; i++ {
+		if i == 100 {
+			t.Fatal("Indexes were not rebuilt in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if names := fs.index.getReady(bucket, false).names(); !reflect.DeepEqual(names, objects) {
+		t.Fatalf("Expected rebuilt index with %v, got %v", objects, names)
+	}
+	if names := fs.index.getReady(bucket, true).names(); !reflect.DeepEqual(names, []string{"b/c"}) {
+		t.Fatalf("Expected rebuilt index with b/c, got %v", names)
+	}
+}
@@ -292,7 +292,7 @@ func saveFormatFS(formatPath string, fsFormat *formatConfigV1) error {
 }
 
 // Return if the part info in uploadedParts and completeParts are same.
-func isPartsSame(uploadedParts []objectPartInfo, completeParts []completePart) bool {
+func isPartsSame(uploadedParts []objectPartInfo, completeParts []CompletePart) bool {
 	if len(uploadedParts) != len(completeParts) {
 		return false
 	}
@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"os"
+)
+
+// MoveObject - moves an object by hard linking its data at the
+// destination and unlinking the source, so that data is never copied.
+// Steps are ordered so that the source object stays intact until the
+// destination is complete, a crash leaves at worst both objects
+// behind. Data which can't be linked, e.g. across devices, is
+// reported with errObjectNotMovable.
+func (fs fsObjects) MoveObject(srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, error) {
+	if _, err := fs.statBucketDir(srcBucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, srcBucket)
+	}
+	if _, err := fs.statBucketDir(dstBucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket)
+	}
+
+	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
+	fsMeta := newFSMetaV1()
+	srcMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, srcBucket, srcObject, fsMetaJSONFile)
+	rwlk, err := fs.rwPool.Write(srcMetaPath)
+	if err == nil {
+		// This close will allow for fs locks to be synchronized on `fs.json`.
+		defer rwlk.Close()
+		if _, err = fsMeta.ReadFrom(rwlk); err != nil && errorCause(err) != io.EOF {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+	} else if err != errFileNotFound {
+		// Ignore if `fs.json` is not available, this is true for pre-existing data.
+		return ObjectInfo{}, toObjectErr(traceError(err), srcBucket, srcObject)
+	}
+
+	// Link at a temporary location first, an existing object is then
+	// replaced atomically.
+	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, mustGetUUID())
+	if err = os.Link(preparePath(pathJoin(fs.fsPath, srcBucket, srcObject)), preparePath(fsTmpObjPath)); err != nil {
+		switch {
+		case os.IsNotExist(err):
+			return ObjectInfo{}, toObjectErr(traceError(errFileNotFound), srcBucket, srcObject)
+		case isSysErrCrossDevice(err), os.IsPermission(err), isSysErrOpNotSupported(err):
+			// Filesystem can't link data from source to destination.
+			return ObjectInfo{}, traceError(errObjectNotMovable)
+		}
+		return ObjectInfo{}, toObjectErr(traceError(err), dstBucket, dstObject)
+	}
+	defer fsRemoveFile(fsTmpObjPath)
+
+	dstMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, dstBucket, dstObject, fsMetaJSONFile)
+	wlk, err := fs.rwPool.Create(dstMetaPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), dstBucket, dstObject)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	// Data of the destination is replaced before its metadata, like
+	// PutObject does.
+	fsNSObjPath := pathJoin(fs.fsPath, dstBucket, dstObject)
+	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+	fs.index.addObject(dstBucket, dstObject)
+
+	fi, err := fsStatFile(fsNSObjPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+
+	// Destination is complete, the source is unlinked last.
+	if err = fsDeleteFile(pathJoin(fs.fsPath, srcBucket), pathJoin(fs.fsPath, srcBucket, srcObject)); err != nil {
+		return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+	}
+	if err = fsDeleteFile(minioMetaBucketDir, srcMetaPath); err != nil && errorCause(err) != errFileNotFound {
+		return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+	}
+	fs.index.removeObject(srcBucket, srcObject)
+
+	return fsMeta.ToObjectInfo(dstBucket, dstObject, fi), nil
+}
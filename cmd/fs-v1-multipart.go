@@ -53,7 +53,12 @@ func (fs fsObjects) deleteUploadsJSON(bucket, object, uploadID string) error {
 
 	tmpDir := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID)
 
-	return fsRemoveMeta(multipartBucketPath, uploadsMetaPath, tmpDir)
+	if err := fsRemoveMeta(multipartBucketPath, uploadsMetaPath, tmpDir); err != nil {
+		return err
+	}
+
+	fs.index.removeUpload(bucket, object)
+	return nil
 }
 
 // Removes the uploadID, called either by CompleteMultipart of AbortMultipart. If the resuling uploads
@@ -105,8 +110,8 @@ func (fs fsObjects) addUploadID(bucket, object, uploadID string, initiated time.
 }
 
 // listMultipartUploadIDs - list all the upload ids from a marker up to 'count'.
-func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int) ([]uploadMetadata, bool, error) {
-	var uploads []uploadMetadata
+func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int) ([]UploadMetadata, bool, error) {
+	var uploads []UploadMetadata
 
 	// Hold the lock so that two parallel complete-multipart-uploads
 	// do not leave a stale uploads.json behind.
@@ -142,7 +147,7 @@ func (fs fsObjects) listMultipartUploadIDs(bucketName, objectName, uploadIDMarke
 	}
 
 	for index < len(uploadIDs.Uploads) {
-		uploads = append(uploads, uploadMetadata{
+		uploads = append(uploads, UploadMetadata{
 			Object:    objectName,
 			UploadID:  uploadIDs.Uploads[index].UploadID,
 			Initiated: uploadIDs.Uploads[index].Initiated,
@@ -184,7 +189,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		multipartMarkerPath = pathJoin(bucket, keyMarker)
 	}
 
-	var uploads []uploadMetadata
+	var uploads []UploadMetadata
 	var err error
 	var eof bool
 
@@ -210,10 +215,14 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 		walkResultCh, endWalkCh = fs.listPool.Release(listPrms)
 		if walkResultCh == nil {
 			endWalkCh = make(chan struct{})
-			isLeaf := fs.isMultipartUpload
-			listDir := fs.listDirFactory(isLeaf)
-			walkResultCh = startTreeWalk(minioMetaMultipartBucket, multipartPrefixPath,
-				multipartMarkerPath, recursive, listDir, isLeaf, endWalkCh)
+			if idx := fs.index.getReady(bucket, true); idx != nil {
+				walkResultCh = idx.startWalk(retainSlash(bucket), prefix, keyMarker, recursive, endWalkCh)
+			} else {
+				isLeaf := fs.isMultipartUpload
+				listDir := fs.listDirFactory(isLeaf)
+				walkResultCh = startTreeWalk(minioMetaMultipartBucket, multipartPrefixPath,
+					multipartMarkerPath, recursive, listDir, isLeaf, endWalkCh)
+			}
 		}
 
 		// List until maxUploads requested.
@@ -237,7 +246,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 
 			entry := strings.TrimPrefix(walkResult.entry, retainSlash(bucket))
 			if hasSuffix(walkResult.entry, slashSeparator) {
-				uploads = append(uploads, uploadMetadata{
+				uploads = append(uploads, UploadMetadata{
 					Object: entry,
 				})
 				maxUploads--
@@ -250,7 +259,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 				continue
 			}
 
-			var tmpUploads []uploadMetadata
+			var tmpUploads []UploadMetadata
 			var end bool
 			uploadIDMarker = ""
 
@@ -288,8 +297,9 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 
 	if !eof {
 		// Save the go-routine state in the pool so that it can continue from where it left off on
-		// the next request.
-		fs.listPool.Set(listParams{bucket, recursive, result.NextKeyMarker, prefix, heal}, walkResultCh, endWalkCh)
+		// the next request, with the same params it is looked up with.
+		listPrms := listParams{minioMetaMultipartBucket, recursive, pathJoin(bucket, result.NextKeyMarker), multipartPrefixPath, heal}
+		fs.listPool.Set(listPrms, walkResultCh, endWalkCh)
 	}
 
 	result.IsTruncated = !eof
@@ -366,6 +376,8 @@ func (fs fsObjects) newMultipartUpload(bucket string, object string, meta map[st
 		return "", toObjectErr(err, bucket, object)
 	}
 
+	fs.index.addUpload(bucket, object)
+
 	// Return success.
 	return uploadID, nil
 }
@@ -453,11 +465,11 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		return PartInfo{}, toObjectErr(err, bucket)
 	}
 
-	// Hold the lock so that two parallel complete-multipart-uploads
-	// do not leave a stale uploads.json behind.
+	// Hold the read lock so that parts are uploaded in parallel while
+	// abort and complete multipart operations wait for them.
 	objectMPartPathLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, pathJoin(bucket, object))
-	objectMPartPathLock.Lock()
-	defer objectMPartPathLock.Unlock()
+	objectMPartPathLock.RLock()
+	defer objectMPartPathLock.RUnlock()
 
 	// Disallow any parallel abort or complete multipart operations.
 	uploadsPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, bucket, object, uploadsJSONFile)
@@ -473,19 +485,11 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 
 	// Just check if the uploadID exists to avoid copy if it doesn't.
 	fsMetaPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, fsMetaJSONFile)
-	rwlk, err := fs.rwPool.Write(fsMetaPath)
-	if err != nil {
-		if err == errFileNotFound || err == errFileAccessDenied {
+	if _, err := fsStatFile(fsMetaPath); err != nil {
+		if errorCause(err) == errFileNotFound || errorCause(err) == errFileAccessDenied {
 			return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
 		}
-		return PartInfo{}, toObjectErr(traceError(err), bucket, object)
-	}
-	defer rwlk.Close()
-
-	fsMeta := fsMetaV1{}
-	_, err = fsMeta.ReadFrom(rwlk)
-	if err != nil {
-		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPath)
+		return PartInfo{}, toObjectErr(err, bucket, object)
 	}
 
 	partSuffix := fmt.Sprintf("object%d", partID)
@@ -555,20 +559,20 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	partPath := pathJoin(bucket, object, uploadID, partSuffix)
 	// Lock the part so that another part upload with same part-number gets blocked
 	// while the part is getting appended in the background.
-	partLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, partPath)
+	partLock := globalNSMutex.NewPartLock(bucket, object, uploadID, partID)
 	partLock.Lock()
 
 	fsNSPartPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, partPath)
-	if err = fsRenameFile(fsPartPath, fsNSPartPath); err != nil {
+	if err := fsRenameFile(fsPartPath, fsNSPartPath); err != nil {
 		partLock.Unlock()
 		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, partPath)
 	}
 
 	// Save the object part info in `fs.json`.
-	fsMeta.AddObjectPart(partID, partSuffix, newMD5Hex, size)
-	if _, err = fsMeta.WriteTo(rwlk); err != nil {
+	fsMeta, err := fs.addObjectPart(bucket, object, uploadID, partID, partSuffix, newMD5Hex, size)
+	if err != nil {
 		partLock.Unlock()
-		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, uploadIDPath)
+		return PartInfo{}, err
 	}
 
 	partNamePath := pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, partSuffix)
@@ -596,6 +600,37 @@ func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	}, nil
 }
 
+// addObjectPart - saves the info of an uploaded part in `fs.json`
+// of the multipart upload and returns the updated metadata. Only
+// this update is serialized between parallel part uploads.
+func (fs fsObjects) addObjectPart(bucket, object, uploadID string, partID int, partSuffix, md5Hex string, size int64) (fsMetaV1, error) {
+	uploadIDLock := globalNSMutex.NewUploadIDLock(bucket, object, uploadID)
+	uploadIDLock.Lock()
+	defer uploadIDLock.Unlock()
+
+	uploadIDPath := pathJoin(bucket, object, uploadID)
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaMultipartBucket, uploadIDPath, fsMetaJSONFile)
+	rwlk, err := fs.rwPool.Write(fsMetaPath)
+	if err != nil {
+		if err == errFileNotFound || err == errFileAccessDenied {
+			return fsMetaV1{}, traceError(InvalidUploadID{UploadID: uploadID})
+		}
+		return fsMetaV1{}, toObjectErr(traceError(err), bucket, object)
+	}
+	defer rwlk.Close()
+
+	fsMeta := fsMetaV1{}
+	if _, err = fsMeta.ReadFrom(rwlk); err != nil {
+		return fsMetaV1{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPath)
+	}
+
+	fsMeta.AddObjectPart(partID, partSuffix, md5Hex, size)
+	if _, err = fsMeta.WriteTo(rwlk); err != nil {
+		return fsMetaV1{}, toObjectErr(err, minioMetaMultipartBucket, uploadIDPath)
+	}
+	return fsMeta, nil
+}
+
 // listObjectParts - wrapper scanning through
 // '.minio.sys/multipart/bucket/object/UPLOADID'. Lists all the parts
 // saved inside '.minio.sys/multipart/bucket/object/UPLOADID'.
@@ -701,7 +736,7 @@ func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []CompletePart) (ObjectInfo, error) {
 	if err := checkCompleteMultipartArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -769,6 +804,21 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 	appendFallback := true // In case background-append did not append the required parts.
 
 	if isPartsSame(fsMeta.Parts, parts) {
+		// Parts are appended in background without validating
+		// their size, all parts except the last part has to be
+		// atleast minimum part size.
+		for i, part := range fsMeta.Parts[:len(fsMeta.Parts)-1] {
+			if !isMinAllowedPartSize(part.Size) {
+				fs.rwPool.Close(fsMetaPathMultipart)
+				return ObjectInfo{}, traceError(PartTooSmall{
+					PartNumber:     part.Number,
+					PartSize:       part.Size,
+					PartETag:       parts[i].ETag,
+					MinSizeAllowed: getMinPartSize(),
+				})
+			}
+		}
+
 		err = fs.complete(bucket, object, uploadID, fsMeta)
 		if err == nil {
 			appendFallback = false
@@ -806,13 +856,14 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 				return ObjectInfo{}, traceError(BadDigest{})
 			}
 
-			// All parts except the last part has to be atleast 5MB.
+			// All parts except the last part has to be atleast minimum part size.
 			if (i < len(parts)-1) && !isMinAllowedPartSize(fsMeta.Parts[partIdx].Size) {
 				fs.rwPool.Close(fsMetaPathMultipart)
 				return ObjectInfo{}, traceError(PartTooSmall{
-					PartNumber: part.PartNumber,
-					PartSize:   fsMeta.Parts[partIdx].Size,
-					PartETag:   part.ETag,
+					PartNumber:     part.PartNumber,
+					PartSize:       fsMeta.Parts[partIdx].Size,
+					PartETag:       part.ETag,
+					MinSizeAllowed: getMinPartSize(),
 				})
 			}
 
@@ -859,6 +910,9 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		}
 	}
 
+	// Object is in place, list it from now on.
+	fs.index.addObject(bucket, object)
+
 	// No need to save part info, since we have concatenated all parts.
 	fsMeta.Parts = nil
 
@@ -47,6 +47,15 @@ type fsObjects struct {
 	// ListObjects pool management.
 	listPool *treeWalkPool
 
+	// Limits directories read at once by listings, nil if unlimited.
+	listDirSem chan struct{}
+
+	// Sorted indexes used by listings, nil if disabled.
+	index *fsIndexes
+
+	// Known buckets, saves bucket existence checks a stat.
+	bucketCache *fsBucketCache
+
 	// To manage the appendRoutine go0routines
 	bgAppend *backgroundAppend
 }
@@ -135,11 +144,22 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		rwPool: &fsIOPool{
 			readersMap: make(map[string]*lock.RLockedFile),
 		},
-		listPool: newTreeWalkPool(globalLookupTimeout),
+		listPool:    newTreeWalkPool(globalLookupTimeout),
+		bucketCache: newFSBucketCache(fsBucketCacheTTL),
 		bgAppend: &backgroundAppend{
 			infoMap: make(map[string]bgAppendPartsInfo),
 		},
 	}
+	if maxDirReads := getListMaxConcurrentDirReads(); maxDirReads > 0 {
+		fs.listDirSem = make(chan struct{}, maxDirReads)
+	}
+
+	// Load listing indexes if enabled.
+	if globalIsFSIndexEnabled {
+		if fs.index, err = newFSIndexes(fs); err != nil {
+			return nil, fmt.Errorf("Unable to load listing indexes. %s", err)
+		}
+	}
 
 	// Initialize and load bucket policies.
 	err = initBucketPolicies(fs)
@@ -159,6 +179,9 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 
 // Should be called when process shuts down.
 func (fs fsObjects) Shutdown() error {
+	// Save listing indexes for the next start.
+	errorIf(fs.index.save(), "Unable to save listing indexes")
+
 	// Cleanup and delete tmp uuid.
 	return fsRemoveAll(pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID))
 }
@@ -197,6 +220,10 @@ func (fs fsObjects) statBucketDir(bucket string) (os.FileInfo, error) {
 	}
 	st, err := fsStatDir(bucketDir)
 	if err != nil {
+		// Forget a bucket removed from the disk by other means.
+		if errorCause(err) == errVolumeNotFound {
+			fs.bucketCache.remove(bucket)
+		}
 		return nil, err
 	}
 	return st, nil
@@ -214,14 +241,25 @@ func (fs fsObjects) MakeBucket(bucket string) error {
 		return toObjectErr(err, bucket)
 	}
 
+	// Cache the new bucket, a failed stat leaves it to be looked up.
+	if st, serr := fsStatDir(bucketDir); serr == nil {
+		fs.bucketCache.set(bucket, st)
+	}
+
+	fs.index.makeBucket(bucket)
 	return nil
 }
 
-// GetBucketInfo - fetch bucket metadata info.
+// GetBucketInfo - fetch bucket metadata info, known buckets are
+// served from the bucket cache.
 func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
-	st, err := fs.statBucketDir(bucket)
-	if err != nil {
-		return BucketInfo{}, toObjectErr(err, bucket)
+	st, ok := fs.bucketCache.get(bucket)
+	if !ok {
+		var err error
+		if st, err = fs.statBucketDir(bucket); err != nil {
+			return BucketInfo{}, toObjectErr(err, bucket)
+		}
+		fs.bucketCache.set(bucket, st)
 	}
 
 	// As os.Stat() doesn't carry other than ModTime(), use ModTime() as CreatedTime.
@@ -238,6 +276,7 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 		return nil, traceError(err)
 	}
 	var bucketInfos []BucketInfo
+	buckets := make(map[string]os.FileInfo)
 	entries, err := readDir(preparePath(fs.fsPath))
 	if err != nil {
 		return nil, toObjectErr(traceError(errDiskNotFound))
@@ -266,8 +305,12 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 			// As os.Stat() doesnt carry CreatedTime, use ModTime() as CreatedTime.
 			Created: fi.ModTime(),
 		})
+		buckets[fi.Name()] = fi
 	}
 
+	// Refresh known buckets with the ones found on the disk.
+	fs.bucketCache.reset(buckets)
+
 	// Sort bucket infos by bucket name.
 	sort.Sort(byBucketName(bucketInfos))
 
@@ -283,8 +326,13 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 		return toObjectErr(err, bucket)
 	}
 
-	// Attempt to delete regular bucket.
-	if err = fsRemoveDir(bucketDir); err != nil {
+	// Attempt to delete regular bucket, it is forgotten as well
+	// if it was already removed from the disk.
+	err = fsRemoveDir(bucketDir)
+	if err == nil || errorCause(err) == errVolumeNotFound {
+		fs.bucketCache.remove(bucket)
+	}
+	if err != nil {
 		return toObjectErr(err, bucket)
 	}
 
@@ -300,6 +348,7 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 		return toObjectErr(err, bucket)
 	}
 
+	fs.index.deleteBucket(bucket)
 	return nil
 }
 
@@ -369,6 +418,70 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	return objInfo, nil
 }
 
+// LinkObject - clones an object by hard linking its data, objects are
+// always replaced by renaming new data over them so that later changes
+// to either object never show in the other. `fs.json` is updated in
+// place and is copied instead.
+func (fs fsObjects) LinkObject(srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, error) {
+	if _, err := fs.statBucketDir(srcBucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, srcBucket)
+	}
+	if _, err := fs.statBucketDir(dstBucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket)
+	}
+
+	fsMeta := newFSMetaV1()
+	srcMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, srcBucket, srcObject, fsMetaJSONFile)
+	rlk, err := fs.rwPool.Open(srcMetaPath)
+	if err == nil {
+		_, err = fsMeta.ReadFrom(rlk.LockedFile)
+		fs.rwPool.Close(srcMetaPath)
+		if err != nil && errorCause(err) != io.EOF {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+	} else if err != errFileNotFound {
+		// Ignore if `fs.json` is not available, this is true for pre-existing data.
+		return ObjectInfo{}, toObjectErr(traceError(err), srcBucket, srcObject)
+	}
+
+	dstMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, dstBucket, dstObject, fsMetaJSONFile)
+	wlk, err := fs.rwPool.Create(dstMetaPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(traceError(err), dstBucket, dstObject)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	// Link at a temporary location first, an existing object is then
+	// replaced atomically.
+	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, mustGetUUID())
+	if err = os.Link(preparePath(pathJoin(fs.fsPath, srcBucket, srcObject)), preparePath(fsTmpObjPath)); err != nil {
+		fsRemoveMeta(pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix), dstMetaPath, pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID))
+		if os.IsNotExist(err) {
+			return ObjectInfo{}, toObjectErr(traceError(errFileNotFound), srcBucket, srcObject)
+		}
+		return ObjectInfo{}, toObjectErr(traceError(err), dstBucket, dstObject)
+	}
+	defer fsRemoveFile(fsTmpObjPath)
+
+	fsNSObjPath := pathJoin(fs.fsPath, dstBucket, dstObject)
+	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+
+	fs.index.addObject(dstBucket, dstObject)
+
+	fi, err := fsStatFile(fsNSObjPath)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
+	}
+	return fsMeta.ToObjectInfo(dstBucket, dstObject, fi), nil
+}
+
 // GetObject - reads an object from the disk.
 // Supports additional parameters like offset and length
 // which are synonymous with HTTP Range requests.
@@ -426,6 +539,13 @@ func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64,
 		return traceError(InvalidRange{offset, length, size})
 	}
 
+	// Writers implementing io.ReaderFrom, i.e the client connection,
+	// may send the file without copying it through user space.
+	if rf, ok := writer.(io.ReaderFrom); ok {
+		_, err = rf.ReadFrom(io.LimitReader(reader, length))
+		return toObjectErr(traceError(err), bucket, object)
+	}
+
 	// Allocate a staging buffer.
 	buf := make([]byte, int(bufSize))
 
@@ -625,6 +745,8 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
+	fs.index.addObject(bucket, object)
+
 	// Stat the file to fetch timestamp, size.
 	fi, err := fsStatFile(pathJoin(fs.fsPath, bucket, object))
 	if err != nil {
@@ -671,6 +793,8 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 			return toObjectErr(err, bucket, object)
 		}
 	}
+
+	fs.index.removeObject(bucket, object)
 	return nil
 }
 
@@ -686,7 +810,14 @@ var fsTreeWalkIgnoredErrs = append(baseIgnoredErrs, []error{
 func (fs fsObjects) listDirFactory(isLeaf isLeafFunc) listDirFunc {
 	// listDir - lists all the entries at a given prefix and given entry in the prefix.
 	listDir := func(bucket, prefixDir, prefixEntry string) (entries []string, delayIsLeaf bool, err error) {
+		if fs.listDirSem != nil {
+			// Wait for a free slot, released once the directory is read.
+			fs.listDirSem <- struct{}{}
+		}
 		entries, err = readDir(pathJoin(fs.fsPath, bucket, prefixDir))
+		if fs.listDirSem != nil {
+			<-fs.listDirSem
+		}
 		if err != nil {
 			return nil, false, err
 		}
@@ -816,8 +947,12 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 			// object string does not end with "/".
 			return !hasSuffix(object, slashSeparator)
 		}
-		listDir := fs.listDirFactory(isLeaf)
-		walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
+		if idx := fs.index.getReady(bucket, false); idx != nil {
+			walkResultCh = idx.startWalk("", prefix, marker, recursive, endWalkCh)
+		} else {
+			listDir := fs.listDirFactory(isLeaf)
+			walkResultCh = startTreeWalk(bucket, prefix, marker, recursive, listDir, isLeaf, endWalkCh)
+		}
 	}
 
 	var objInfos []ObjectInfo
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -153,14 +154,91 @@ func TestFSGetBucketInfo(t *testing.T) {
 		t.Fatal("BucketNameInvalid error not returned")
 	}
 
-	// Check for buckets and should get disk not found.
+	// Known bucket is served from the bucket cache after disk is
+	// removed.
 	removeAll(disk)
+	if _, err = fs.GetBucketInfo(bucketName); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check for buckets and should get disk not found once the
+	// cached bucket expired.
+	fs.bucketCache = newFSBucketCache(0)
 	_, err = fs.GetBucketInfo(bucketName)
 	if !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("BucketNotFound error not returned")
 	}
 }
 
+// readerFromBuffer - bytes.Buffer recording calls to ReadFrom.
+type readerFromBuffer struct {
+	bytes.Buffer
+	readFromCalled bool
+}
+
+func (b *readerFromBuffer) ReadFrom(r io.Reader) (int64, error) {
+	b.readFromCalled = true
+	return b.Buffer.ReadFrom(r)
+}
+
+// TestFSGetObjectReaderFrom - tests if FS sends objects through the
+// io.ReaderFrom of the writer when available.
+func TestFSGetObjectReaderFrom(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer removeAll(disk)
+
+	obj := initFSObjects(disk, t)
+	bucketName := "bucket"
+	objectName := "object"
+	data := []byte("hello, world")
+
+	if err := obj.MakeBucket(bucketName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		offset   int64
+		length   int64
+		expected []byte
+	}{
+		// Test 1: whole object.
+		{0, -1, data},
+		// Test 2: range at the beginning of the object.
+		{0, 5, data[:5]},
+		// Test 3: range in the middle of the object.
+		{7, 3, data[7:10]},
+		// Test 4: range at the end of the object.
+		{7, 5, data[7:]},
+		// Test 5: empty range.
+		{3, 0, []byte{}},
+	}
+	for i, testCase := range testCases {
+		writer := &readerFromBuffer{}
+		if err := obj.GetObject(bucketName, objectName, testCase.offset, testCase.length, writer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !writer.readFromCalled {
+			t.Errorf("Test %d: expected object to be sent through ReadFrom", i+1)
+		}
+		if !bytes.Equal(writer.Bytes(), testCase.expected) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, writer.Bytes())
+		}
+	}
+
+	// Invalid range is rejected before anything is sent.
+	writer := &readerFromBuffer{}
+	err := obj.GetObject(bucketName, objectName, 7, 10, writer)
+	if !isSameType(errorCause(err), InvalidRange{}) {
+		t.Fatalf("expected InvalidRange, got %v", err)
+	}
+	if writer.readFromCalled {
+		t.Fatal("expected ReadFrom not to be called for invalid range")
+	}
+}
+
 // TestFSDeleteObject - test fs.DeleteObject() with healthy and corrupted disks
 func TestFSDeleteObject(t *testing.T) {
 	// Prepare for tests
@@ -372,7 +372,7 @@ func (a AzureObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMa
 		// NewMultipartUpload.
 		return result, nil
 	}
-	result.Uploads = []uploadMetadata{{prefix, prefix, UTCNow(), "", nil}}
+	result.Uploads = []UploadMetadata{{prefix, prefix, UTCNow(), "", nil}}
 	return result, nil
 }
 
@@ -508,7 +508,7 @@ func (a AzureObjects) AbortMultipartUpload(bucket, object, uploadID string) erro
 }
 
 // CompleteMultipartUpload - Use Azure equivalent PutBlockList.
-func (a AzureObjects) CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []completePart) (objInfo ObjectInfo, err error) {
+func (a AzureObjects) CompleteMultipartUpload(bucket, object, uploadID string, uploadedParts []CompletePart) (objInfo ObjectInfo, err error) {
 	meta := a.metaInfo.get(uploadID)
 	if meta == nil {
 		return objInfo, traceError(InvalidUploadID{uploadID})
@@ -107,7 +107,7 @@ func newGatewayLayer(backendType, endpoint, accessKey, secretKey string, secure
 // only used in memory.
 func newGatewayConfig(accessKey, secretKey, region string) error {
 	// Initialize server config.
-	srvCfg := newServerConfigV18()
+	srvCfg := newServerConfigV36()
 
 	// If env is set for a fresh start, save them to config file.
 	srvCfg.SetCredential(credential{
@@ -193,6 +193,8 @@ func gatewayMain(ctx *cli.Context) {
 	registerGatewayAPIRouter(router, newObject)
 
 	var handlerFns = []HandlerFunc{
+		// Compresses listings and JSON/XML API responses.
+		setGzipHandler,
 		// Validate all the incoming paths.
 		setPathValidityHandler,
 		// Limits all requests size to a maximum fixed limit
@@ -49,10 +49,8 @@ type gatewayAPIHandlers struct {
 func registerGatewayAPIRouter(mux *router.Router, gw GatewayLayer) {
 	// Initialize API.
 	api := gatewayAPIHandlers{
-		ObjectAPI: func() GatewayLayer { return gw },
-		objectAPIHandlers: objectAPIHandlers{
-			ObjectAPI: newObjectLayerFn,
-		},
+		ObjectAPI:         func() GatewayLayer { return gw },
+		objectAPIHandlers: objectAPIHandlers{newGlobalServerContext()},
 	}
 
 	// API Router
@@ -369,9 +369,9 @@ func (l *s3Gateway) DeleteObject(bucket string, object string) error {
 	return nil
 }
 
-// fromMinioClientUploadMetadata converts ObjectMultipartInfo to uploadMetadata
-func fromMinioClientUploadMetadata(omi minio.ObjectMultipartInfo) uploadMetadata {
-	return uploadMetadata{
+// fromMinioClientUploadMetadata converts ObjectMultipartInfo to UploadMetadata
+func fromMinioClientUploadMetadata(omi minio.ObjectMultipartInfo) UploadMetadata {
+	return UploadMetadata{
 		Object:    omi.Key,
 		UploadID:  omi.UploadID,
 		Initiated: omi.Initiated,
@@ -380,7 +380,7 @@ func fromMinioClientUploadMetadata(omi minio.ObjectMultipartInfo) uploadMetadata
 
 // fromMinioClientListMultipartsInfo converts minio ListMultipartUploadsResult to ListMultipartsInfo
 func fromMinioClientListMultipartsInfo(lmur minio.ListMultipartUploadsResult) ListMultipartsInfo {
-	uploads := make([]uploadMetadata, len(lmur.Uploads))
+	uploads := make([]UploadMetadata, len(lmur.Uploads))
 
 	for i, um := range lmur.Uploads {
 		uploads[i] = fromMinioClientUploadMetadata(um)
@@ -516,16 +516,16 @@ func (l *s3Gateway) AbortMultipartUpload(bucket string, object string, uploadID
 	return l.Client.AbortMultipartUpload(bucket, object, uploadID)
 }
 
-// toMinioClientCompletePart converts completePart to minio CompletePart
-func toMinioClientCompletePart(part completePart) minio.CompletePart {
+// toMinioClientCompletePart converts CompletePart to minio CompletePart
+func toMinioClientCompletePart(part CompletePart) minio.CompletePart {
 	return minio.CompletePart{
 		ETag:       part.ETag,
 		PartNumber: part.PartNumber,
 	}
 }
 
-// toMinioClientCompleteParts converts []completePart to minio []CompletePart
-func toMinioClientCompleteParts(parts []completePart) []minio.CompletePart {
+// toMinioClientCompleteParts converts []CompletePart to minio []CompletePart
+func toMinioClientCompleteParts(parts []CompletePart) []minio.CompletePart {
 	mparts := make([]minio.CompletePart, len(parts))
 	for i, part := range parts {
 		mparts[i] = toMinioClientCompletePart(part)
@@ -534,7 +534,7 @@ func toMinioClientCompleteParts(parts []completePart) []minio.CompletePart {
 }
 
 // CompleteMultipartUpload completes ongoing multipart upload and finalizes object
-func (l *s3Gateway) CompleteMultipartUpload(bucket string, object string, uploadID string, uploadedParts []completePart) (ObjectInfo, error) {
+func (l *s3Gateway) CompleteMultipartUpload(bucket string, object string, uploadID string, uploadedParts []CompletePart) (ObjectInfo, error) {
 	err := l.Client.CompleteMultipartUpload(bucket, object, uploadID, toMinioClientCompleteParts(uploadedParts))
 	if err != nil {
 		return ObjectInfo{}, s3ToObjectError(traceError(err), bucket, object)
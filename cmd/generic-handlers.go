@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"io"
 	"net"
 	"net/http"
 	"strings"
@@ -291,64 +292,16 @@ func setIgnoreResourcesHandler(h http.Handler) http.Handler {
 	return resourceHandler{h}
 }
 
-// Checks requests for not implemented Bucket resources
-func ignoreNotImplementedBucketResources(req *http.Request) bool {
-	for name := range req.URL.Query() {
-		if notimplementedBucketResourceNames[name] {
-			return true
-		}
-	}
-	return false
-}
-
-// Checks requests for not implemented Object resources
-func ignoreNotImplementedObjectResources(req *http.Request) bool {
-	for name := range req.URL.Query() {
-		if notimplementedObjectResourceNames[name] {
-			return true
-		}
-	}
-	return false
-}
-
-// List of not implemented bucket queries
-var notimplementedBucketResourceNames = map[string]bool{
-	"acl":            true,
-	"cors":           true,
-	"lifecycle":      true,
-	"logging":        true,
-	"replication":    true,
-	"tagging":        true,
-	"versions":       true,
-	"requestPayment": true,
-	"versioning":     true,
-	"website":        true,
-}
-
-// List of not implemented object queries
-var notimplementedObjectResourceNames = map[string]bool{
-	"torrent": true,
-	"acl":     true,
-	"policy":  true,
-}
-
 // Resource handler ServeHTTP() wrapper
 func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	bucketName, objectName := urlPath2BucketObjectName(r.URL)
-
-	// If bucketName is present and not objectName check for bucket level resource queries.
-	if bucketName != "" && objectName == "" {
-		if ignoreNotImplementedBucketResources(r) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
-			return
-		}
-	}
-	// If bucketName and objectName are present check for its resource queries.
-	if bucketName != "" && objectName != "" {
-		if ignoreNotImplementedObjectResources(r) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
-			return
+	// Check bucket and object resource queries for not implemented APIs.
+	if api, ok := getNotImplementedAPI(r); ok {
+		if api == "" {
+			notImplementedHandler(w, r)
+		} else {
+			collectAPIStats(api, notImplementedHandler)(w, r)
 		}
+		return
 	}
 	// A put method on path "/" doesn't make sense, ignore it.
 	if r.Method == httpPUT && r.URL.Path == "/" && r.Header.Get(minioAdminOpHeader) == "" {
@@ -388,6 +341,21 @@ func (rww *httpResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return rww.ResponseWriter.(http.Hijacker).Hijack()
 }
 
+// Wraps ResponseWriter's ReadFrom()
+func (rww *httpResponseRecorder) ReadFrom(r io.Reader) (int64, error) {
+	return copyToResponseWriter(rww.ResponseWriter, r)
+}
+
+// copyToResponseWriter - copies r to w, through w's io.ReaderFrom
+// when available so that net/http sends regular files to the
+// client with zero-copy sendfile() on platforms supporting it.
+func copyToResponseWriter(w http.ResponseWriter, r io.Reader) (int64, error) {
+	if rf, ok := w.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	return io.Copy(w, r)
+}
+
 // httpStatsHandler definition: gather HTTP statistics
 type httpStatsHandler struct {
 	handler http.Handler
@@ -406,7 +374,9 @@ func (h httpStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	tBefore := UTCNow()
 
 	// Execute the request
+	globalHTTPStats.currentRequests.Inc()
 	h.handler.ServeHTTP(ww, r)
+	globalHTTPStats.currentRequests.Dec()
 
 	// Time after call has completed.
 	tAfter := UTCNow()
@@ -469,3 +439,50 @@ func (h pathValidityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	h.handler.ServeHTTP(w, r)
 }
+
+// readOnlyHandler rejects all mutating S3 API requests when the
+// server is started in read-only mode or is in degraded read-only
+// mode.
+type readOnlyHandler struct {
+	handler http.Handler
+}
+
+func setReadOnlyHandler(h http.Handler) http.Handler {
+	return readOnlyHandler{handler: h}
+}
+
+// isMutatingAPIRequest - returns true if the incoming request is an
+// S3 API request which modifies buckets or objects. Admin API and
+// internal RPC requests are not considered as mutating, client
+// supplied headers are never trusted for this decision.
+func isMutatingAPIRequest(r *http.Request) bool {
+	switch r.Method {
+	case httpPUT, httpPOST, httpDELETE:
+	default:
+		return false
+	}
+	if isSTSRequest(r) {
+		return false
+	}
+	// Admin API is served on the service root, which has no
+	// mutating S3 API, internal RPC on the reserved bucket.
+	bucketName, _ := urlPath2BucketObjectName(r.URL)
+	return bucketName != "" && !isMinioReservedBucket(bucketName)
+}
+
+func (h readOnlyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isMutatingAPIRequest(r) {
+		switch checkServerWriteable() {
+		case errServerReadOnly:
+			writeErrorResponse(w, ErrAccessDenied, r.URL)
+			return
+		case errServerDegraded:
+			writeErrorResponse(w, ErrServerDegraded, r.URL)
+			return
+		case errServerMissingDisks:
+			writeErrorResponse(w, ErrServerMissingDisks, r.URL)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -88,3 +89,89 @@ func TestGuessIsBrowser(t *testing.T) {
 		t.Fatal("Test shouldn't report as browser for a non browser request.")
 	}
 }
+
+// Tests read-only handler rejects only mutating S3 API requests.
+func TestReadOnlyHandler(t *testing.T) {
+	testCases := []struct {
+		method     string
+		url        string
+		adminOp    string
+		statusCode int
+	}{
+		// GET, HEAD requests are allowed.
+		{httpGET, "/bucket/object", "", http.StatusOK},
+		{httpHEAD, "/bucket/object", "", http.StatusOK},
+		{httpGET, "/bucket", "", http.StatusOK},
+		// PUT, POST, DELETE requests are rejected.
+		{httpPUT, "/bucket/object", "", http.StatusForbidden},
+		{httpPUT, "/bucket", "", http.StatusForbidden},
+		{httpPOST, "/bucket?delete", "", http.StatusForbidden},
+		{httpDELETE, "/bucket/object", "", http.StatusForbidden},
+		// Admin operation header does not exempt S3 API requests.
+		{httpPUT, "/bucket/object", "restart", http.StatusForbidden},
+		{httpDELETE, "/bucket", "stop", http.StatusForbidden},
+		// Admin API and internal RPC requests are allowed.
+		{httpPOST, "/?service", "restart", http.StatusOK},
+		{httpPOST, minioReservedBucketPath + "/webrpc", "", http.StatusOK},
+	}
+
+	handler := setReadOnlyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	defer func(readOnly bool) { globalIsReadOnly = readOnly }(globalIsReadOnly)
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if testCase.adminOp != "" {
+			req.Header.Set(minioAdminOpHeader, testCase.adminOp)
+		}
+
+		// All requests pass through when read-only mode is disabled.
+		globalIsReadOnly = false
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Test %d: expected %d, got %d", i+1, http.StatusOK, rec.Code)
+		}
+
+		globalIsReadOnly = true
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.statusCode, rec.Code)
+		}
+
+		// Same requests are rejected as unavailable in degraded
+		// read-only mode.
+		globalIsReadOnly = false
+		globalDegradedMode = &degradedMode{degraded: true}
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		globalDegradedMode = &degradedMode{}
+		expectedCode := testCase.statusCode
+		if expectedCode == http.StatusForbidden {
+			expectedCode = http.StatusServiceUnavailable
+		}
+		if rec.Code != expectedCode {
+			t.Errorf("Test %d: expected %d in degraded mode, got %d", i+1, expectedCode, rec.Code)
+		}
+
+		// And while disks missing at startup are not confirmed.
+		globalMissingDisks = &missingDisks{locked: true}
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		globalMissingDisks = &missingDisks{}
+		if rec.Code != expectedCode {
+			t.Errorf("Test %d: expected %d with disks missing, got %d", i+1, expectedCode, rec.Code)
+		}
+	}
+}
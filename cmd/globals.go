@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"runtime"
 	"time"
@@ -53,6 +54,10 @@ const (
 
 	// The maximum allowed difference between the request generation time and the server processing time
 	globalMaxSkewTime = 15 * time.Minute
+
+	// Default size of the object cache disk tier, when not set
+	// through MINIO_CACHE_DISK_SIZE.
+	globalDefaultCacheDiskSize = 10 * humanize.GiByte
 )
 
 var (
@@ -75,6 +80,17 @@ var (
 	// This flag is set to 'us-east-1' by default
 	globalServerRegion = globalMinioDefaultRegion
 
+	// This flag is set to 'true' when MINIO_DOMAIN env is set.
+	globalIsEnvDomain = false
+	// Domain name of virtual-host style bucket requests, empty if
+	// only path-style requests are served.
+	globalDomainName = ""
+
+	// Domain name of bucket websites, requests to "bucket.<domain>"
+	// are served as the website of the bucket. Empty if websites are
+	// only served on the website address.
+	globalWebsiteDomain = ""
+
 	// Maximum size of internal objects parts
 	globalPutPartSize = int64(64 * 1024 * 1024)
 
@@ -84,6 +100,8 @@ var (
 	globalMinioPort = "9000"
 	// Holds the host that was passed using --address
 	globalMinioHost = ""
+	// Addresses, TLS and listener options of the server.
+	globalServerNetConfig serverNetConfig
 
 	// Peer communication struct
 	globalS3Peers = s3Peers{}
@@ -94,6 +112,31 @@ var (
 	// IsSSL indicates if the server is configured with SSL.
 	globalIsSSL bool
 
+	// Set to true when "rpc.mutualTLS" is enabled in config, inter-node
+	// RPC connections are then authenticated with client certificates.
+	globalIsRPCMutualTLS bool
+
+	// Client certificate presented to other nodes when mutual TLS is
+	// enabled, along with the CAs trusted to verify their certificates.
+	globalRPCClientCert *tls.Certificate
+	globalRPCClientCAs  *x509.CertPool
+
+	// Serves server certificate and reloads it when updated, only
+	// set when the server is configured with SSL.
+	globalCertsManager *certsManager
+
+	// Obtains and renews the server certificate from an ACME CA when
+	// MINIO_ACME is "on", from MINIO_ACME_DIRECTORY with the contact
+	// MINIO_ACME_EMAIL.
+	globalIsACMEEnabled    bool
+	globalACMEDirectoryURL = defaultACMEDirectoryURL
+	globalACMEEmail        string
+	globalACMEManager      *acmeManager
+
+	// Certificates expiring within this duration are reported, can
+	// be changed through MINIO_CERT_EXPIRY_WARN_DAYS.
+	globalCertExpiryWarn = globalMinioCertExpireWarnDays
+
 	// List of admin peers.
 	globalAdminPeers = adminPeers{}
 
@@ -108,12 +151,182 @@ var (
 	// Global HTTP request statisitics
 	globalHTTPStats = newHTTPStats()
 
+	// Global latency and in-flight statistics of S3 APIs
+	globalAPIStats = newAPIStatsCollector()
+
 	// Time when object layer was initialized on start up.
 	globalBootTime time.Time
 
 	globalActiveCred         credential
 	globalPublicCerts        []*x509.Certificate
 	globalXLObjCacheDisabled bool
+
+	// Objects matching any of these "bucket/object" patterns never
+	// enter the object cache, set through MINIO_CACHE_EXCLUDE.
+	globalObjCacheExcludes []string
+
+	// Directory and size of the object cache disk tier, objects evicted
+	// from memory are saved here. Set through MINIO_CACHE_DISK and
+	// MINIO_CACHE_DISK_SIZE, disk tier is disabled if directory is empty.
+	globalCacheDiskDir     string
+	globalCacheDiskMaxSize uint64
+
+	// Percentage of free memory the object cache sizes itself to,
+	// shrinking under memory pressure. Set through
+	// MINIO_CACHE_MEMORY_PERCENT, the cache is sized statically to
+	// half of RAM on servers with at least 24GiB when 0.
+	globalObjCacheMemoryPercent int
+
+	// Objects up to this size are stored inline in `xl.json` on XL
+	// backend, set through MINIO_XL_INLINE_THRESHOLD. Disabled when 0.
+	globalXLInlineThreshold int64
+
+	// Throughput caps of rebalancing objects across disks added to an
+	// XL setup, in bytes and objects per second, set through
+	// MINIO_REBALANCE_BANDWIDTH and MINIO_REBALANCE_OBJECTS. No cap
+	// when 0.
+	globalRebalanceBandwidth int64 = defaultRebalanceBandwidth
+	globalRebalanceObjects   int64 = defaultRebalanceObjects
+
+	// Set to true when MINIO_COMPRESS_OBJECTS is "on", enables gzip
+	// compression of object data for clients accepting it.
+	globalIsObjectCompressionEnabled = false
+
+	// Set to true when MINIO_FS_INDEX is "on", FS backend then keeps
+	// sorted indexes of objects and uploads to serve listings.
+	globalIsFSIndexEnabled = false
+
+	// Set to true when server is started with --read-only or when
+	// MINIO_READ_ONLY is "on", all mutating S3 APIs are rejected.
+	globalIsReadOnly = false
+
+	// Set to true when MINIO_DEGRADED_READ_ONLY is "on", erasure coded
+	// servers then reject writes while write quorum is lost.
+	globalIsDegradedReadOnlyEnabled = false
+
+	// Tracks write quorum in degraded read-only mode.
+	globalDegradedMode = &degradedMode{}
+
+	// Set to true when server is started with --force-degraded, writes
+	// are then accepted even if disks are missing at startup.
+	globalIsForceDegraded = false
+
+	// Disks missing at startup, writes are rejected until they are
+	// online or the start is confirmed through admin API.
+	globalMissingDisks = &missingDisks{}
+
+	// Interval between two bucket usage crawls, can be changed
+	// through MINIO_USAGE_CRAWL_INTERVAL.
+	globalUsageCrawlInterval = defaultUsageCrawlInterval
+
+	// Swift TempURL key set through MINIO_SWIFT_TEMPURL_KEY, Swift
+	// TempURL access is disabled when empty.
+	globalSwiftTempURLKey = ""
+
+	// KMS with the master key set through MINIO_KMS_MASTER_KEY, bucket
+	// keys are not available when nil.
+	globalKMS *kms
+
+	// Set to true when MINIO_ACCESS_KEY_ALERTS is "on", anomalies in
+	// the usage of access keys are logged as errors.
+	globalIsAccessKeyAlertsEnabled = false
+
+	// Locks held or blocked for longer than this are reported as
+	// stale, can be changed through MINIO_LOCK_STALE_THRESHOLD.
+	globalStaleLockThreshold = defaultStaleLockThreshold
+
+	// Time a request waits for locks in a distributed setup before
+	// failing, can be changed through MINIO_LOCK_REQUEST_DEADLINE.
+	globalRequestLockDeadline = defaultRequestLockDeadline
+
+	// Order in which waiters are admitted to acquire namespace
+	// locks, can be changed through MINIO_LOCK_FAIRNESS.
+	globalLockFairness = lockFairnessNone
+
+	// Requests taking longer than this are logged and reported by
+	// the admin API along with their lock waits, set through
+	// MINIO_SLOW_REQUEST_THRESHOLD. Disabled when zero.
+	globalSlowRequestThreshold time.Duration
+
+	// Lock waits of requests being served and the most recent slow
+	// requests.
+	globalSlowRequests = newSlowRequests()
+
+	// Handling of peers running a different version and window of
+	// release times within which they are compatible, set through
+	// MINIO_PEER_VERSION_CHECK and MINIO_PEER_VERSION_WINDOW.
+	globalPeerVersionCheck  = peerVersionCheckRefuse
+	globalPeerVersionWindow time.Duration
+
+	// Set to true when MINIO_NET_BUFFER_TUNING is "on", socket buffers
+	// of client connections are then tuned to their bandwidth-delay
+	// product within MINIO_NET_BUFFER_MIN and MINIO_NET_BUFFER_MAX.
+	globalIsNetBufferTuningEnabled = false
+	globalNetBufferMin             = defaultNetBufferMin
+	globalNetBufferMax             = defaultNetBufferMax
+
+	// Set to true when MINIO_DEBUG includes "lock", enables deadlock
+	// detection and the /minio/debug/locks endpoint.
+	globalIsLockDebug = false
+
+	// Aborts stale multipart uploads as configured.
+	globalMultipartJanitor = newMultipartJanitor()
+
+	// Clones of buckets started through admin API on this server.
+	globalBucketClones = newBucketClones()
+
+	// Rebalancing of objects across disks added to the setup, run by
+	// the first server.
+	globalRebalancer = &xlRebalancer{}
+
+	// Configurations of buckets read on every request, e.g. WORM,
+	// object lock, lifecycle, default metadata and website.
+	globalBucketConfigCache = newBucketConfigCache()
+
+	// Nonces of single-use presigned URLs already used, on this
+	// server or any other.
+	globalPresignNonces = newPresignNonces()
+
+	// Hybrid logical clock ordering events of all servers, moved
+	// forward by inter-node RPC calls.
+	globalHLC = newHLC()
+
+	// Time to wait for in-flight uploads to finish during shutdown,
+	// can be changed through MINIO_SHUTDOWN_DRAIN_TIMEOUT.
+	globalShutdownDrainTimeout = defaultShutdownDrainTimeout
+
+	// Evaluates the cluster state served at the metrics endpoint.
+	globalHealthEvaluator = newHealthEvaluator(newObjectLayerFn, defaultHealthEvalInterval)
+
+	// Set to true when MINIO_PROMETHEUS_AUTH_TYPE is "public", metrics
+	// endpoint is then served without a JWT bearer token.
+	globalIsPrometheusPublic = false
+
+	// Remote tier of lifecycle transitions, nil unless enabled in config.
+	globalRemoteTier remoteTier
+
+	// Owners of buckets served by other servers, nil unless some
+	// bucket is federated in config.
+	globalFederation *federation
+
+	// Validates LDAP users exchanging their password for temporary
+	// credentials, nil unless enabled in config.
+	globalLDAPProvider *ldapAuthProvider
+
+	// Worker pools of background subsystems, resized as set in the
+	// workers section of config or through admin API.
+	globalNotificationPool = newWorkerPool(defaultNotificationWorkers, defaultNotificationQueueSize)
+	globalHealPool         = newWorkerPool(defaultHealWorkers, defaultHealQueueSize)
+	globalLifecyclePool    = newWorkerPool(defaultLifecycleWorkers, defaultLifecycleQueueSize)
+
+	// Reads of erasure coded files which failed over from a disk to
+	// the remaining ones, the server is reported degraded for a while
+	// afterwards.
+	globalReadFailovers = &readFailovers{}
+
+	// Reads of objects selected by analytics configurations, counted
+	// on this server.
+	globalAnalyticsTracker = newAnalyticsTracker()
 	// Add new variable global values here.
 )
 
@@ -121,6 +334,10 @@ var (
 	// Keeps the connection active by waiting for following amount of time.
 	// Primarily used in ListenBucketNotification.
 	globalSNSConnAlive = 5 * time.Second
+
+	// Interval between the whitespace keep-alives sent to the client
+	// while CompleteMultipartUpload assembles the parts.
+	globalCompleteMultipartKeepAlive = 10 * time.Second
 )
 
 // global colors.
@@ -0,0 +1,179 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Content encoding token for gzip.
+const gzipEncoding = "gzip"
+
+// Pool of gzip writers, re-used across responses to avoid
+// allocating compression state for every request.
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		gw, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return gw
+	},
+}
+
+// acceptsGzip - returns true if the client has advertised gzip
+// in its Accept-Encoding header with a non-zero quality value.
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		tokens := strings.Split(encoding, ";")
+		if !strings.EqualFold(strings.TrimSpace(tokens[0]), gzipEncoding) {
+			continue
+		}
+		for _, param := range tokens[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil || q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isCompressibleRequest - returns true if the response for the
+// incoming request is an API response (listings, policies, admin
+// JSON) eligible for compression. Object data is compressed only
+// when explicitly enabled with MINIO_COMPRESS_OBJECTS, and never
+// for range requests since Content-Range refers to the raw bytes.
+func isCompressibleRequest(r *http.Request) bool {
+	if r.Method != httpGET {
+		return false
+	}
+
+	// All admin API responses are JSON/XML documents.
+	if r.Header.Get(minioAdminOpHeader) != "" {
+		return true
+	}
+
+	bucketName, objectName := urlPath2BucketObjectName(r.URL)
+	// Browser assets and web RPC are served separately.
+	if isMinioReservedBucket(bucketName) {
+		return false
+	}
+
+	if objectName == "" {
+		// ListenBucketNotification is a long lived stream of
+		// events, leave it untouched.
+		_, ok := r.URL.Query()["events"]
+		return !ok
+	}
+
+	// ListObjectParts response.
+	if _, ok := r.URL.Query()["uploadId"]; ok {
+		return true
+	}
+
+	return globalIsObjectCompressionEnabled && r.Header.Get("Range") == ""
+}
+
+// gzipResponseWriter compresses the response body on the fly,
+// response is streamed to the client as it is being written.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gw          *gzip.Writer
+	wroteHeader bool
+}
+
+// Wraps ResponseWriter's WriteHeader(), compression is only
+// enabled for responses which carry a body.
+func (g *gzipResponseWriter) WriteHeader(httpCode int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	h := g.ResponseWriter.Header()
+	h.Add("Vary", "Accept-Encoding")
+	if httpCode != http.StatusNoContent && httpCode != http.StatusNotModified &&
+		h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", gzipEncoding)
+		// Length of the compressed stream is not known in advance.
+		h.Del("Content-Length")
+		g.gw = gzipWriterPool.Get().(*gzip.Writer)
+		g.gw.Reset(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(httpCode)
+}
+
+// Wraps ResponseWriter's Write()
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.gw == nil {
+		return g.ResponseWriter.Write(b)
+	}
+	return g.gw.Write(b)
+}
+
+// Wraps ResponseWriter's Flush(), pending compressed data
+// is flushed before flushing the underlying writer.
+func (g *gzipResponseWriter) Flush() {
+	if g.gw != nil {
+		g.gw.Flush()
+	}
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// close writes the gzip footer and releases the gzip writer.
+func (g *gzipResponseWriter) close() {
+	if g.gw == nil {
+		return
+	}
+	g.gw.Close()
+	gzipWriterPool.Put(g.gw)
+	g.gw = nil
+}
+
+// gzipHandler compresses eligible responses for clients which
+// support gzip content encoding.
+type gzipHandler struct {
+	handler http.Handler
+}
+
+// setGzipHandler - reduces bandwidth for large listings and
+// JSON/XML API responses over slow links.
+func setGzipHandler(h http.Handler) http.Handler {
+	return gzipHandler{handler: h}
+}
+
+func (h gzipHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isCompressibleRequest(r) || !acceptsGzip(r) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	gw := &gzipResponseWriter{ResponseWriter: w}
+	defer gw.close()
+	h.handler.ServeHTTP(gw, r)
+}
@@ -0,0 +1,139 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests parsing of Accept-Encoding header values.
+func TestAcceptsGzip(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		expected       bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"deflate, gzip", true},
+		{"gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"deflate, br", false},
+		{"identity", false},
+	}
+
+	for i, testCase := range testCases {
+		r, _ := http.NewRequest("GET", "http://localhost:9000/bucket", nil)
+		r.Header.Set("Accept-Encoding", testCase.acceptEncoding)
+		if got := acceptsGzip(r); got != testCase.expected {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// Tests requests eligible for compression.
+func TestIsCompressibleRequest(t *testing.T) {
+	testCases := []struct {
+		method     string
+		url        string
+		header     http.Header
+		compress   bool
+		compressed bool
+	}{
+		// ListBuckets.
+		{"GET", "/", nil, false, true},
+		// ListObjects.
+		{"GET", "/bucket", nil, false, true},
+		// GetBucketPolicy.
+		{"GET", "/bucket?policy", nil, false, true},
+		// ListenBucketNotification.
+		{"GET", "/bucket?events=s3:ObjectCreated:*", nil, false, false},
+		// ListObjectParts.
+		{"GET", "/bucket/object?uploadId=abc", nil, false, true},
+		// GetObject.
+		{"GET", "/bucket/object", nil, false, false},
+		// GetObject with object compression enabled.
+		{"GET", "/bucket/object", nil, true, true},
+		// Range GetObject with object compression enabled.
+		{"GET", "/bucket/object", http.Header{"Range": []string{"bytes=0-10"}}, true, false},
+		// HeadObject.
+		{"HEAD", "/bucket/object", nil, true, false},
+		// PutObject.
+		{"PUT", "/bucket/object", nil, true, false},
+		// Browser requests.
+		{"GET", minioReservedBucketPath + "/index.html", nil, false, false},
+		// Admin requests.
+		{"GET", "/?lock", http.Header{minioAdminOpHeader: []string{"list"}}, false, true},
+	}
+
+	defer func(enabled bool) { globalIsObjectCompressionEnabled = enabled }(globalIsObjectCompressionEnabled)
+	for i, testCase := range testCases {
+		globalIsObjectCompressionEnabled = testCase.compress
+		r, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		for k, v := range testCase.header {
+			r.Header[k] = v
+		}
+		if got := isCompressibleRequest(r); got != testCase.compressed {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.compressed, got)
+		}
+	}
+}
+
+// Tests the gzip handler compresses the response body.
+func TestGzipHandler(t *testing.T) {
+	body := []byte("<ListBucketResult></ListBucketResult>")
+	handler := setGzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSuccessResponseXML(w, body)
+	}))
+
+	// Client without gzip support receives plain response.
+	r, _ := http.NewRequest("GET", "http://localhost:9000/bucket", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("Unexpected Content-Encoding %s", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Body.String() != string(body) {
+		t.Fatalf("Expected %s, got %s", body, rec.Body.String())
+	}
+
+	// Client with gzip support receives compressed response.
+	r.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	if rec.Header().Get("Content-Encoding") != gzipEncoding {
+		t.Fatalf("Expected Content-Encoding %s, got %s", gzipEncoding, rec.Header().Get("Content-Encoding"))
+	}
+	gr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(body) {
+		t.Fatalf("Expected %s, got %s", body, data)
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -123,6 +124,11 @@ func extractMetadataFromHeader(header http.Header) map[string]string {
 			metadata[cKey] = header.Get(key)
 		}
 	}
+	// Only objects of other storage classes than the default one
+	// are marked.
+	if header.Get(amzStorageClassHeader) == reducedRedundancyStorageClass {
+		metadata[amzStorageClassHeader] = reducedRedundancyStorageClass
+	}
 
 	// Success.
 	return metadata
@@ -151,6 +157,16 @@ func extractReqParams(r *http.Request) map[string]string {
 	}
 }
 
+// getSourceIP - returns IP address of the client connection, proxy
+// headers such as X-Forwarded-For are not trusted.
+func getSourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // Trims away `aws-chunked` from the content-encoding header if present.
 // Streaming signature clients can have custom content-encoding such as
 // `aws-chunked,gzip` here we need to only save `gzip`.
@@ -0,0 +1,82 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	healthCheckPathPrefix = minioReservedBucketPath + "/health"
+
+	// Liveness probe endpoint, answers as long as the server serves
+	// HTTP.
+	healthCheckLivenessPath = healthCheckPathPrefix + "/live"
+
+	// Readiness probe endpoint, answers once the server is ready to
+	// serve S3 requests.
+	healthCheckReadinessPath = healthCheckPathPrefix + "/ready"
+)
+
+// healthCheckHandlers - liveness and readiness probes, meant for
+// orchestrators such as Kubernetes. Probes are not authenticated.
+type healthCheckHandlers struct {
+	ObjectAPI func() ObjectLayer
+}
+
+// registerHealthCheckRouter - registers health check endpoints.
+func registerHealthCheckRouter(mux *router.Router) {
+	health := healthCheckHandlers{ObjectAPI: newObjectLayerFn}
+	mux.Methods("GET", "HEAD").Path(healthCheckLivenessPath).HandlerFunc(health.LivenessCheckHandler)
+	mux.Methods("GET", "HEAD").Path(healthCheckReadinessPath).HandlerFunc(health.ReadinessCheckHandler)
+}
+
+// getServerReadiness - returns ErrNone if the object layer is
+// initialized and enough disks are online for reads.
+func getServerReadiness(objAPI ObjectLayer) APIErrorCode {
+	if objAPI == nil {
+		return ErrServerNotInitialized
+	}
+	storageInfo := objAPI.StorageInfo()
+	if storageInfo.Backend.Type == Erasure && storageInfo.Backend.OnlineDisks < storageInfo.Backend.ReadQuorum {
+		return ErrReadQuorum
+	}
+	return ErrNone
+}
+
+// LivenessCheckHandler - GET /minio/health/live
+// ----------
+// Returns 200 OK, a server not answering is to be restarted.
+func (h healthCheckHandlers) LivenessCheckHandler(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ReadinessCheckHandler - GET /minio/health/ready
+// ----------
+// Returns 200 OK once the object layer is initialized and as long as
+// enough disks are online for reads, 503 Service Unavailable
+// otherwise. Requests are not to be sent to a server which isn't
+// ready.
+func (h healthCheckHandlers) ReadinessCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Error := getServerReadiness(h.ObjectAPI()); s3Error != ErrNone {
+		writeErrorResponseHeadersOnly(w, s3Error)
+		return
+	}
+	writeSuccessResponseHeadersOnly(w)
+}
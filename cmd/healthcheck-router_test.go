@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests liveness and readiness of a server before and after its
+// object layer is initialized and when disks go offline.
+func TestHealthCheckHandlers(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	fsObj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	xlObj, xlDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(xlDirs)
+
+	// XL with all but one disk offline.
+	offlineObj, offlineDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(offlineDirs)
+	offlineXL := offlineObj.(*xlObjects)
+	for i := 1; i < len(offlineXL.storageDisks); i++ {
+		offlineXL.storageDisks[i] = nil
+	}
+
+	testCases := []struct {
+		objAPI             ObjectLayer
+		method             string
+		path               string
+		expectedRespStatus int
+	}{
+		// Test 1: server is live before being initialized.
+		{nil, "GET", healthCheckLivenessPath, http.StatusOK},
+		// Test 2: but not ready.
+		{nil, "GET", healthCheckReadinessPath, http.StatusServiceUnavailable},
+		// Test 3: FS is ready once initialized.
+		{fsObj, "GET", healthCheckReadinessPath, http.StatusOK},
+		// Test 4: XL is ready in quorum.
+		{xlObj, "GET", healthCheckReadinessPath, http.StatusOK},
+		{xlObj, "HEAD", healthCheckReadinessPath, http.StatusOK},
+		// Test 6: XL out of quorum is live but not ready.
+		{offlineObj, "GET", healthCheckLivenessPath, http.StatusOK},
+		{offlineObj, "GET", healthCheckReadinessPath, http.StatusServiceUnavailable},
+		// Test 8: only GET and HEAD are served.
+		{xlObj, "POST", healthCheckReadinessPath, http.StatusNotFound},
+	}
+
+	for i, testCase := range testCases {
+		objAPI := testCase.objAPI
+		health := healthCheckHandlers{ObjectAPI: func() ObjectLayer { return objAPI }}
+		mux := router.NewRouter()
+		mux.Methods("GET", "HEAD").Path(healthCheckLivenessPath).HandlerFunc(health.LivenessCheckHandler)
+		mux.Methods("GET", "HEAD").Path(healthCheckReadinessPath).HandlerFunc(health.ReadinessCheckHandler)
+
+		req, err := newTestRequest(testCase.method, testCase.path, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+}
+
+// Tests probes go through the generic handlers without credentials.
+func TestHealthCheckRouter(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	// Object layer is not initialized.
+	globalObjLayerMutex.Lock()
+	savedObjectAPI := globalObjectAPI
+	globalObjectAPI = nil
+	globalObjLayerMutex.Unlock()
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = savedObjectAPI
+		globalObjLayerMutex.Unlock()
+	}()
+
+	mux := router.NewRouter().SkipClean(true)
+	registerHealthCheckRouter(mux)
+	handler := registerHandlers(mux, serverHandlerFns...)
+
+	testCases := []struct {
+		path               string
+		expectedRespStatus int
+	}{
+		// Test 1: server is live.
+		{healthCheckLivenessPath, http.StatusOK},
+		// Test 2: but not ready.
+		{healthCheckReadinessPath, http.StatusServiceUnavailable},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("GET", testCase.path, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+}
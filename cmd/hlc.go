@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock of a remote server ahead of this one by more than this is
+// not followed, so that one server with a wrong clock can't push the
+// clock of all others.
+const hlcMaxOffset = rpcSkewTimeAllowed
+
+// hlcTimestamp - timestamp of a hybrid logical clock, the physical
+// time in nanoseconds along with a counter ordering timestamps of the
+// same physical time.
+type hlcTimestamp struct {
+	Wall    int64
+	Logical uint32
+}
+
+// Time - returns physical time of the timestamp.
+func (ts hlcTimestamp) Time() time.Time {
+	return time.Unix(0, ts.Wall).UTC()
+}
+
+// Before - returns true if ts is before other.
+func (ts hlcTimestamp) Before(other hlcTimestamp) bool {
+	return ts.Wall < other.Wall || (ts.Wall == other.Wall && ts.Logical < other.Logical)
+}
+
+// String - returns timestamp in a fixed width form, timestamps are
+// ordered like their strings.
+func (ts hlcTimestamp) String() string {
+	return fmt.Sprintf("%019d.%010d", ts.Wall, ts.Logical)
+}
+
+// hlc - hybrid logical clock. Timestamps of a server are strictly
+// increasing, and a timestamp taken after receiving a message from
+// another server is after any timestamp that server took before
+// sending it, even if their physical clocks drift.
+type hlc struct {
+	sync.Mutex
+	last hlcTimestamp
+	now  func() time.Time
+}
+
+// newHLC - returns hybrid logical clock following physical time.
+func newHLC() *hlc {
+	return &hlc{now: UTCNow}
+}
+
+// Now - returns a new timestamp of the clock, to send along with a
+// message or to order a local event.
+func (c *hlc) Now() hlcTimestamp {
+	wall := c.now().UnixNano()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if wall > c.last.Wall {
+		c.last = hlcTimestamp{Wall: wall}
+	} else {
+		c.last.Logical++
+	}
+	return c.last
+}
+
+// Update - moves the clock past the timestamp of a message received
+// from another server.
+func (c *hlc) Update(remote hlcTimestamp) {
+	wall := c.now().UnixNano()
+	if remote.Wall-wall > int64(hlcMaxOffset) {
+		errorIf(errServerTimeMismatch, "Clock of a remote server is ahead by %s, not following it.",
+			time.Duration(remote.Wall-wall))
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	switch {
+	case wall > c.last.Wall && wall > remote.Wall:
+		c.last = hlcTimestamp{Wall: wall}
+	case remote.Wall > c.last.Wall:
+		c.last = hlcTimestamp{Wall: remote.Wall, Logical: remote.Logical + 1}
+	case remote.Wall == c.last.Wall && remote.Logical > c.last.Logical:
+		c.last.Logical = remote.Logical + 1
+	default:
+		c.last.Logical++
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests timestamps of a hybrid logical clock are strictly increasing
+// and follow timestamps of remote servers.
+func TestHLC(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+	clock := &hlc{now: func() time.Time { return now }}
+
+	// Timestamps of the same physical time are ordered by counter.
+	ts1 := clock.Now()
+	ts2 := clock.Now()
+	if !ts1.Before(ts2) || ts1.String() >= ts2.String() {
+		t.Fatalf("Expected %s before %s", ts1, ts2)
+	}
+	if !ts1.Time().Equal(now) {
+		t.Fatalf("Expected physical time %s, got %s", now, ts1.Time())
+	}
+
+	// A physical clock going backwards doesn't go back in time.
+	now = now.Add(-time.Second)
+	if ts3 := clock.Now(); !ts2.Before(ts3) {
+		t.Fatalf("Expected %s before %s", ts2, ts3)
+	}
+
+	testCases := []struct {
+		remote hlcTimestamp
+		// Whether timestamps taken afterwards follow remote.
+		followed bool
+	}{
+		// Remote clock behind.
+		{hlcTimestamp{Wall: now.Add(-time.Minute).UnixNano(), Logical: 5}, true},
+		// Remote clock slightly ahead.
+		{hlcTimestamp{Wall: now.Add(2 * time.Second).UnixNano(), Logical: 7}, true},
+		// Same wall time with a greater counter.
+		{hlcTimestamp{Wall: now.Add(2 * time.Second).UnixNano(), Logical: 100}, true},
+		// Remote clock too far ahead is not followed.
+		{hlcTimestamp{Wall: now.Add(time.Hour).UnixNano()}, false},
+	}
+	for i, testCase := range testCases {
+		clock.Update(testCase.remote)
+		ts := clock.Now()
+		if testCase.remote.Before(ts) != testCase.followed {
+			t.Errorf("Test %d: Expected %s after %s to be %v", i+1, ts, testCase.remote, testCase.followed)
+		}
+	}
+
+	// Physical time catching up resets the counter.
+	now = now.Add(time.Minute)
+	if ts := clock.Now(); ts.Wall != now.UnixNano() || ts.Logical != 0 {
+		t.Errorf("Expected timestamp of physical time %s, got %s", now, ts)
+	}
+}
+
+// Tests authenticated RPC calls carry the clock of the caller and
+// move the clock of the callee.
+func TestAuthRPCArgsClock(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	defer func(clock *hlc) { globalHLC = clock }(globalHLC)
+	globalHLC = newHLC()
+
+	creds := serverConfig.GetCredential()
+	token, err := authenticateNode(creds.AccessKey, creds.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := AuthRPCArgs{}
+	args.SetAuthToken(token)
+	if args.Clock.Wall == 0 {
+		t.Fatal("Expected clock to be sent along with the token")
+	}
+
+	// Caller slightly ahead.
+	args.Clock.Wall += int64(time.Second)
+	if err = args.IsAuthenticated(); err != nil {
+		t.Fatal(err)
+	}
+	if ts := globalHLC.Now(); !args.Clock.Before(ts) {
+		t.Fatalf("Expected %s before %s", args.Clock, ts)
+	}
+
+	// Unauthenticated calls don't move the clock.
+	args = AuthRPCArgs{AuthToken: "invalid", Clock: hlcTimestamp{Wall: UTCNow().Add(2 * time.Second).UnixNano()}}
+	if err = args.IsAuthenticated(); err != errInvalidToken {
+		t.Fatalf("Expected %s, got %v", errInvalidToken, err)
+	}
+	if ts := globalHLC.Now(); !ts.Before(args.Clock) {
+		t.Fatalf("Expected %s before %s", ts, args.Clock)
+	}
+}
@@ -94,6 +94,14 @@ type HTTPStats struct {
 	// DELETE request stats.
 	totalDELETEs   HTTPMethodStats
 	successDELETEs HTTPMethodStats
+
+	// Requests being served currently.
+	currentRequests atomic.Int64
+}
+
+// Return number of requests being served currently.
+func (st *HTTPStats) getCurrentRequests() int64 {
+	return st.currentRequests.Load()
 }
 
 func durationStr(totalDuration, totalCount float64) string {
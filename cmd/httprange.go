@@ -25,6 +25,10 @@ import (
 
 const (
 	byteRangePrefix = "bytes="
+
+	// Maximum number of ranges accepted in a single Range header,
+	// requests with more ranges are served the whole object.
+	maxRequestRanges = 100
 )
 
 // Valid byte position regexp
@@ -132,3 +136,33 @@ func parseRequestRange(rangeString string, resourceSize int64) (hrange *httpRang
 
 	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
 }
+
+// parseRequestRanges - parses a Range header with one or more comma
+// separated ranges. Unsatisfiable ranges are skipped as long as at
+// least one of the ranges is satisfiable, as described in RFC 7233.
+func parseRequestRanges(rangeString string, resourceSize int64) (hranges []*httpRange, err error) {
+	if !strings.HasPrefix(rangeString, byteRangePrefix) {
+		return nil, fmt.Errorf("'%s' does not start with '%s'", rangeString, byteRangePrefix)
+	}
+
+	rangeSpecs := strings.Split(strings.TrimPrefix(rangeString, byteRangePrefix), ",")
+	if len(rangeSpecs) > maxRequestRanges {
+		return nil, fmt.Errorf("'%s' has more than %d ranges", rangeString, maxRequestRanges)
+	}
+
+	for _, rangeSpec := range rangeSpecs {
+		hrange, err := parseRequestRange(byteRangePrefix+strings.TrimSpace(rangeSpec), resourceSize)
+		if err == errInvalidRange {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("'%s' does not have a valid range value", rangeString)
+		}
+		hranges = append(hranges, hrange)
+	}
+
+	if len(hranges) == 0 {
+		return nil, errInvalidRange
+	}
+	return hranges, nil
+}
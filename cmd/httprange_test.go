@@ -16,7 +16,10 @@
 
 package cmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // Test parseRequestRange()
 func TestParseRequestRange(t *testing.T) {
@@ -88,3 +91,48 @@ func TestParseRequestRange(t *testing.T) {
 		}
 	}
 }
+
+// Test parseRequestRanges()
+func TestParseRequestRanges(t *testing.T) {
+	testCases := []struct {
+		rangeString string
+		ranges      []string
+		err         error
+	}{
+		{"bytes=2-5", []string{"bytes 2-5/10"}, nil},
+		{"bytes=0-1,4-5", []string{"bytes 0-1/10", "bytes 4-5/10"}, nil},
+		{"bytes=0-1, -2", []string{"bytes 0-1/10", "bytes 8-9/10"}, nil},
+		// Unsatisfiable ranges are skipped.
+		{"bytes=0-1,20-30", []string{"bytes 0-1/10"}, nil},
+		// All ranges unsatisfiable.
+		{"bytes=10-11,20-30", nil, errInvalidRange},
+	}
+	for i, testCase := range testCases {
+		hranges, err := parseRequestRanges(testCase.rangeString, 10)
+		if err != testCase.err {
+			t.Fatalf("Test %d: expected: %v, got: %v", i+1, testCase.err, err)
+		}
+		if len(hranges) != len(testCase.ranges) {
+			t.Fatalf("Test %d: expected %d ranges, got %d", i+1, len(testCase.ranges), len(hranges))
+		}
+		for j, hrange := range hranges {
+			if hrange.String() != testCase.ranges[j] {
+				t.Fatalf("Test %d: expected: %s, got: %s", i+1, testCase.ranges[j], hrange)
+			}
+		}
+	}
+
+	// Syntax errors in any of the ranges.
+	invalidRangeStrings := []string{
+		"bytes=0-1,5-2",
+		"bytes=0-1,",
+		"bytes=0-1,abc",
+		"0-1,2-3",
+		"bytes=" + strings.Repeat("0-1,", maxRequestRanges) + "0-1",
+	}
+	for _, rangeString := range invalidRangeStrings {
+		if _, err := parseRequestRanges(rangeString, 10); err == nil || err == errInvalidRange {
+			t.Fatalf("%s: expected a syntax error, got: %v", rangeString, err)
+		}
+	}
+}
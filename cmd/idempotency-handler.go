@@ -0,0 +1,297 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Header carrying the client generated idempotency key.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// Header set on responses replayed from the idempotency cache.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// Duration for which outcome of a request is remembered.
+	defaultIdempotencyWindow = 10 * time.Minute
+	// Maximum number of outcomes remembered at any time.
+	maxIdempotencyEntries = 10000
+	// Maximum size of a response body that can be remembered,
+	// responses of supported APIs are small XML documents.
+	maxIdempotentResponseSize = 64 * 1024
+)
+
+// isIdempotencyKeyRequest - returns true for the APIs supporting
+// Idempotency-Key header, i.e PutObject, DeleteObject and
+// CompleteMultipartUpload.
+func isIdempotencyKeyRequest(r *http.Request) bool {
+	bucketName, objectName := urlPath2BucketObjectName(r.URL)
+	if objectName == "" || isMinioReservedBucket(bucketName) {
+		return false
+	}
+	_, isMultipart := r.URL.Query()["uploadId"]
+	switch r.Method {
+	case httpPUT:
+		_, isCopy := r.Header["X-Amz-Copy-Source"]
+		return !isMultipart && !isCopy
+	case httpDELETE:
+		return !isMultipart
+	case httpPOST:
+		return isMultipart
+	}
+	return false
+}
+
+// isIdempotencyKeyAuthenticated - validates signature of the request
+// before it can be answered from the cache, only signed requests
+// are served from the cache. Payload is verified later by the API
+// handler when the request is not a retry.
+func isIdempotencyKeyAuthenticated(r *http.Request) bool {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypePresigned, authTypeStreamingSigned:
+		return reqSignatureV4Verify(r, serverConfig.GetRegion()) == ErrNone
+	case authTypeSignedV2, authTypePresignedV2:
+		return isReqAuthenticatedV2(r) == ErrNone
+	}
+	return false
+}
+
+// idempotentResponse - response recorded for a request.
+type idempotentResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// idempotencyEntry - outcome of a request with an idempotency key,
+// response is nil until the request finishes successfully.
+type idempotencyEntry struct {
+	doneCh   chan struct{}
+	done     bool
+	response *idempotentResponse
+	expiry   time.Time
+}
+
+// isExpired - entries of requests in progress never expire.
+func (e *idempotencyEntry) isExpired(now time.Time) bool {
+	return e.done && !now.Before(e.expiry)
+}
+
+// idempotencyCache - remembers successful responses of requests with
+// idempotency keys for a bounded window.
+type idempotencyCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	entries    map[string]*idempotencyEntry
+}
+
+// newIdempotencyCache - initialize a new idempotency cache.
+func newIdempotencyCache(window time.Duration, maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*idempotencyEntry),
+	}
+}
+
+// begin - returns the entry for key and true if the caller is the
+// first one to use the key and should execute the request. Returns
+// nil if the cache is full.
+func (c *idempotencyCache) begin(key string, now time.Time) (*idempotencyEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		if !entry.isExpired(now) {
+			return entry, false
+		}
+		delete(c.entries, key)
+	}
+
+	if len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if entry.isExpired(now) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return nil, false
+		}
+	}
+
+	entry := &idempotencyEntry{doneCh: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// finish - records outcome of the request which started with begin,
+// failed requests are forgotten so that they can be retried.
+func (c *idempotencyCache) finish(key string, entry *idempotencyEntry, response *idempotentResponse, now time.Time) {
+	c.mu.Lock()
+	entry.done = true
+	if response == nil {
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+	} else {
+		entry.response = response
+		entry.expiry = now.Add(c.window)
+	}
+	c.mu.Unlock()
+	close(entry.doneCh)
+}
+
+// idempotencyResponseWriter records the response while writing it
+// to the client.
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	header     http.Header
+	body       []byte
+	overflow   bool
+	// Set when an error is written after a success status
+	// was already sent.
+	uncacheable bool
+}
+
+// Wraps ResponseWriter's WriteHeader(), saves a copy of the headers.
+func (w *idempotencyResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode != 0 {
+		return
+	}
+	w.statusCode = statusCode
+	w.header = make(http.Header)
+	for k, v := range w.ResponseWriter.Header() {
+		w.header[k] = append([]string(nil), v...)
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Wraps ResponseWriter's Write(), saves a copy of the body.
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if len(w.body)+len(b) > maxIdempotentResponseSize {
+		w.overflow = true
+	} else if !w.overflow {
+		w.body = append(w.body, b...)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Wraps ResponseWriter's Flush()
+func (w *idempotencyResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// response - returns the recorded response, nil if the request did
+// not succeed or the response can't be remembered.
+func (w *idempotencyResponseWriter) response() *idempotentResponse {
+	if w.statusCode < 200 || w.statusCode > 299 || w.overflow || w.uncacheable {
+		return nil
+	}
+	return &idempotentResponse{
+		statusCode: w.statusCode,
+		header:     w.header,
+		body:       w.body,
+	}
+}
+
+// idempotencyContextKey - context key of the response writer
+// recording the response of a request.
+type idempotencyContextKey struct{}
+
+// markResponseUncacheable - prevents the response of the request from
+// being remembered, used when an error is reported in the body of a
+// response whose status is already sent as 200 OK.
+func markResponseUncacheable(r *http.Request) {
+	if rw, ok := r.Context().Value(idempotencyContextKey{}).(*idempotencyResponseWriter); ok {
+		rw.uncacheable = true
+	}
+}
+
+// writeIdempotentResponse - replays a recorded response.
+func writeIdempotentResponse(w http.ResponseWriter, response *idempotentResponse) {
+	for k, v := range response.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(response.statusCode)
+	w.Write(response.body)
+}
+
+// Global cache of idempotent responses.
+var globalIdempotencyCache = newIdempotencyCache(defaultIdempotencyWindow, maxIdempotencyEntries)
+
+// idempotencyHandler answers client retries of PutObject, DeleteObject
+// and CompleteMultipartUpload carrying the same Idempotency-Key with
+// the original response instead of executing them again.
+type idempotencyHandler struct {
+	handler http.Handler
+	cache   *idempotencyCache
+}
+
+// setIdempotencyHandler - prevents duplicate effects of client retry
+// storms on mutating object APIs.
+func setIdempotencyHandler(h http.Handler) http.Handler {
+	return idempotencyHandler{handler: h, cache: globalIdempotencyCache}
+}
+
+func (h idempotencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if idempotencyKey == "" || !isIdempotencyKeyRequest(r) || !isIdempotencyKeyAuthenticated(r) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Keys are scoped to the credential, the API and the resource so
+	// that a response is never replayed to another user. A retry may
+	// be signed again with a different date so query is not part of it.
+	key := getRequestAccessKey(r) + "\n" + r.Method + " " + r.URL.Path +
+		"?uploadId=" + r.URL.Query().Get("uploadId") + "\n" + idempotencyKey
+	for {
+		entry, isFirst := h.cache.begin(key, UTCNow())
+		if entry == nil {
+			// Cache is full, serve without idempotency.
+			h.handler.ServeHTTP(w, r)
+			return
+		}
+		if isFirst {
+			rw := &idempotencyResponseWriter{ResponseWriter: w}
+			defer func() {
+				h.cache.finish(key, entry, rw.response(), UTCNow())
+			}()
+			h.handler.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), idempotencyContextKey{}, rw)))
+			return
+		}
+
+		// Wait for the original request to finish, if it failed
+		// try to execute this one.
+		<-entry.doneCh
+		if entry.response != nil {
+			writeIdempotentResponse(w, entry.response)
+			return
+		}
+	}
+}
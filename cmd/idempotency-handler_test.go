@@ -0,0 +1,212 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests APIs supporting Idempotency-Key header.
+func TestIsIdempotencyKeyRequest(t *testing.T) {
+	testCases := []struct {
+		method   string
+		url      string
+		header   http.Header
+		expected bool
+	}{
+		// PutObject.
+		{"PUT", "/bucket/object", nil, true},
+		// CopyObject.
+		{"PUT", "/bucket/object", http.Header{"X-Amz-Copy-Source": []string{"/bucket/src"}}, false},
+		// PutObjectPart.
+		{"PUT", "/bucket/object?uploadId=abc&partNumber=1", nil, false},
+		// DeleteObject.
+		{"DELETE", "/bucket/object", nil, true},
+		// AbortMultipartUpload.
+		{"DELETE", "/bucket/object?uploadId=abc", nil, false},
+		// CompleteMultipartUpload.
+		{"POST", "/bucket/object?uploadId=abc", nil, true},
+		// NewMultipartUpload.
+		{"POST", "/bucket/object?uploads", nil, false},
+		// MakeBucket.
+		{"PUT", "/bucket", nil, false},
+		// GetObject.
+		{"GET", "/bucket/object", nil, false},
+		// Browser upload.
+		{"PUT", minioReservedBucketPath + "/upload/bucket/object", nil, false},
+	}
+
+	for i, testCase := range testCases {
+		r, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		for k, v := range testCase.header {
+			r.Header[k] = v
+		}
+		if got := isIdempotencyKeyRequest(r); got != testCase.expected {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// Tests recording and expiry of idempotency cache entries.
+func TestIdempotencyCache(t *testing.T) {
+	now := UTCNow()
+	cache := newIdempotencyCache(time.Minute, 2)
+
+	entry, isFirst := cache.begin("key1", now)
+	if entry == nil || !isFirst {
+		t.Fatal("Expected first use of the key")
+	}
+	// Entries in progress don't expire.
+	if _, isFirst = cache.begin("key1", now.Add(time.Hour)); isFirst {
+		t.Fatal("Expected entry in progress to be returned")
+	}
+
+	// Failed requests are forgotten.
+	cache.finish("key1", entry, nil, now)
+	if entry, isFirst = cache.begin("key1", now); !isFirst {
+		t.Fatal("Expected key of failed request to be usable again")
+	}
+	response := &idempotentResponse{statusCode: http.StatusOK}
+	cache.finish("key1", entry, response, now)
+	if entry, isFirst = cache.begin("key1", now.Add(time.Second)); isFirst || entry.response != response {
+		t.Fatal("Expected recorded response to be returned")
+	}
+
+	// Cache is full.
+	entry, _ = cache.begin("key2", now)
+	cache.finish("key2", entry, response, now)
+	if entry, _ = cache.begin("key3", now); entry != nil {
+		t.Fatal("Expected no entry when cache is full")
+	}
+
+	// Expired entries make room for new ones.
+	if entry, isFirst = cache.begin("key3", now.Add(2*time.Minute)); entry == nil || !isFirst {
+		t.Fatal("Expected expired entries to be removed")
+	}
+	if _, isFirst = cache.begin("key3", now.Add(time.Hour)); isFirst {
+		t.Fatal("Expected key3 to be in progress")
+	}
+}
+
+// Tests retries with the same Idempotency-Key are answered from cache.
+func TestIdempotencyHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	cred := serverConfig.GetCredential()
+
+	var mu sync.Mutex
+	calls := 0
+	statusCode := http.StatusInternalServerError
+	handler := idempotencyHandler{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			calls++
+			code := statusCode
+			mu.Unlock()
+			w.Header().Set("ETag", "\"abc\"")
+			w.WriteHeader(code)
+		}),
+		cache: newIdempotencyCache(time.Minute, maxIdempotencyEntries),
+	}
+	tempCred, sessionToken, _, err := newTemporaryCredential("alice", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRequest := func(idempotencyKey string, signed, temporary bool) *http.Request {
+		var r *http.Request
+		switch {
+		case temporary:
+			r, err = newTestSignedRequestV4("PUT", "http://localhost:9000/bucket/object", 4,
+				bytes.NewReader([]byte("data")), tempCred.AccessKey, tempCred.SecretKey)
+			if err == nil {
+				r.Header.Set(amzSecurityToken, sessionToken)
+			}
+		case signed:
+			r, err = newTestSignedRequestV4("PUT", "http://localhost:9000/bucket/object", 4,
+				bytes.NewReader([]byte("data")), cred.AccessKey, cred.SecretKey)
+		default:
+			r, err = newTestRequest("PUT", "http://localhost:9000/bucket/object", 4, bytes.NewReader([]byte("data")))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if idempotencyKey != "" {
+			r.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+		return r
+	}
+
+	testCases := []struct {
+		idempotencyKey string
+		signed         bool
+		temporary      bool
+		statusCode     int
+		expectedStatus int
+		expectedCalls  int
+		replayed       bool
+	}{
+		// Failed request is not remembered.
+		{"key1", true, false, http.StatusInternalServerError, http.StatusInternalServerError, 1, false},
+		// Retry after failure is executed.
+		{"key1", true, false, http.StatusOK, http.StatusOK, 2, false},
+		// Retry after success is replayed.
+		{"key1", true, false, http.StatusInternalServerError, http.StatusOK, 2, true},
+		// Different key is executed.
+		{"key2", true, false, http.StatusOK, http.StatusOK, 3, false},
+		// Request without key is executed.
+		{"", true, false, http.StatusOK, http.StatusOK, 4, false},
+		// Anonymous request is never replayed.
+		{"key1", false, false, http.StatusOK, http.StatusOK, 5, false},
+		// Same key from another credential is executed.
+		{"key1", true, true, http.StatusInternalServerError, http.StatusInternalServerError, 6, false},
+		{"key1", true, true, http.StatusOK, http.StatusOK, 7, false},
+		// And replayed only to that credential.
+		{"key1", true, true, http.StatusInternalServerError, http.StatusOK, 7, true},
+	}
+
+	for i, testCase := range testCases {
+		mu.Lock()
+		statusCode = testCase.statusCode
+		mu.Unlock()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest(testCase.idempotencyKey, testCase.signed, testCase.temporary))
+		if rec.Code != testCase.expectedStatus {
+			t.Fatalf("Test %d: expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+		if calls != testCase.expectedCalls {
+			t.Fatalf("Test %d: expected %d calls, got %d", i+1, testCase.expectedCalls, calls)
+		}
+		if replayed := rec.Header().Get(idempotentReplayedHeader) == "true"; replayed != testCase.replayed {
+			t.Fatalf("Test %d: expected replayed %t, got %t", i+1, testCase.replayed, replayed)
+		}
+		if rec.Header().Get("ETag") != "\"abc\"" {
+			t.Fatalf("Test %d: expected ETag to be set", i+1)
+		}
+	}
+}
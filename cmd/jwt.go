@@ -44,21 +44,12 @@ var (
 )
 
 func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string, error) {
-	passedCredential, err := createCredential(accessKey, secretKey)
-	if err != nil {
+	if err := (staticAuthProvider{}).Authenticate(accessKey, secretKey); err != nil {
 		return "", err
 	}
 
 	serverCred := serverConfig.GetCredential()
 
-	if serverCred.AccessKey != passedCredential.AccessKey {
-		return "", errInvalidAccessKeyID
-	}
-
-	if !serverCred.Equal(passedCredential) {
-		return "", errAuthentication
-	}
-
 	utcNow := UTCNow()
 	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
 		"exp": utcNow.Add(expiry).Unix(),
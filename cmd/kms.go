@@ -0,0 +1,71 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Size of master and bucket keys, 256 bits.
+const kmsKeySize = 32
+
+// errKMSNotConfigured - returned by bucket key operations when no KMS
+// master key is set.
+var errKMSNotConfigured = errors.New("No KMS master key is configured")
+
+// kms - key management with a single master key set through
+// MINIO_KMS_MASTER_KEY. Every bucket has its own keys derived from the
+// master key, so that key material of one bucket does not expose the
+// keys of other buckets.
+type kms struct {
+	keyID     string
+	masterKey []byte
+}
+
+// parseKMSMasterKey - parses a master key in the form
+// "<key-id>:<hex encoded 256 bit key>".
+func parseKMSMasterKey(value string) (*kms, error) {
+	i := strings.LastIndex(value, ":")
+	if i <= 0 {
+		return nil, fmt.Errorf("KMS master key must be in the form <key-id>:<hex-key>")
+	}
+	masterKey, err := hex.DecodeString(value[i+1:])
+	if err != nil || len(masterKey) != kmsKeySize {
+		return nil, fmt.Errorf("KMS master key must be %d hex encoded bytes", kmsKeySize)
+	}
+	return &kms{keyID: value[:i], masterKey: masterKey}, nil
+}
+
+// bucketKey - derives the key of a bucket for the given key version,
+// a rotated bucket key is the key of the next version. Derivation is
+// one-way, a bucket key reveals neither the master key nor the keys
+// of other buckets or versions.
+func (k *kms) bucketKey(bucket string, version int) []byte {
+	mac := hmac.New(sha256.New, k.masterKey)
+	mac.Write([]byte("minio-bucket-key\x00"))
+	mac.Write([]byte(bucket))
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(version))
+	mac.Write(v[:])
+	return mac.Sum(nil)
+}
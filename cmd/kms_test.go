@@ -0,0 +1,82 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Tests parsing of KMS master key.
+func TestParseKMSMasterKey(t *testing.T) {
+	testCases := []struct {
+		value      string
+		keyID      string
+		shouldPass bool
+	}{
+		{"my-key:" + strings.Repeat("ab", kmsKeySize), "my-key", true},
+		// Key ID may contain colons.
+		{"arn:my-key:" + strings.Repeat("ab", kmsKeySize), "arn:my-key", true},
+		// Missing key ID.
+		{":" + strings.Repeat("ab", kmsKeySize), "", false},
+		{strings.Repeat("ab", kmsKeySize), "", false},
+		// Invalid hex.
+		{"my-key:" + strings.Repeat("zz", kmsKeySize), "", false},
+		// Short key.
+		{"my-key:" + strings.Repeat("ab", 16), "", false},
+	}
+	for i, testCase := range testCases {
+		k, err := parseKMSMasterKey(testCase.value)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+			continue
+		}
+		if !testCase.shouldPass {
+			if err == nil {
+				t.Errorf("Test %d: expected to fail", i+1)
+			}
+			continue
+		}
+		if k.keyID != testCase.keyID {
+			t.Errorf("Test %d: expected key ID %s, got %s", i+1, testCase.keyID, k.keyID)
+		}
+	}
+}
+
+// Tests bucket keys are distinct per bucket and key version.
+func TestKMSBucketKeys(t *testing.T) {
+	k, err := parseKMSMasterKey("my-key:" + strings.Repeat("ab", kmsKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := [][]byte{k.bucketKey("bucket1", 1), k.bucketKey("bucket2", 1), k.bucketKey("bucket1", 2)}
+	for i := range keys {
+		if len(keys[i]) != kmsKeySize {
+			t.Fatalf("Expected %d byte bucket key, got %d", kmsKeySize, len(keys[i]))
+		}
+		for j := i + 1; j < len(keys); j++ {
+			if bytes.Equal(keys[i], keys[j]) {
+				t.Fatalf("Expected bucket keys %d and %d to differ", i+1, j+1)
+			}
+		}
+	}
+	if !bytes.Equal(keys[0], k.bucketKey("bucket1", 1)) {
+		t.Fatal("Expected bucket key derivation to be deterministic")
+	}
+}
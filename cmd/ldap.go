@@ -0,0 +1,511 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validity of temporary credentials issued for LDAP users when not
+// set in config.
+const defaultLDAPCredentialExpiry = time.Hour
+
+// Timeout of connecting to and binding with the LDAP server.
+const ldapTimeout = 30 * time.Second
+
+// TLS of connections to the LDAP server.
+const (
+	// Connect over TLS, LDAPS.
+	ldapTLSOn = "on"
+	// Connect in clear text and upgrade the connection to TLS with
+	// StartTLS.
+	ldapTLSStartTLS = "starttls"
+	// Connect in clear text, passwords are sent in clear text.
+	ldapTLSOff = "off"
+)
+
+// Attribute of group entries listing DNs of their members when not
+// set in config.
+const defaultLDAPGroupMemberAttribute = "member"
+
+// ldapConfig - LDAP server which validates usernames and passwords
+// exchanged for temporary credentials, disabled by default.
+type ldapConfig struct {
+	Enable bool `json:"enable"`
+	// Address of the LDAP server as host:port.
+	ServerAddr string `json:"serverAddr"`
+	// TLS of connections, ldapTLSOn when empty. Certificates of the
+	// server are verified with globalRootCAs.
+	TLS string `json:"tls"`
+	// DN users bind with, "%s" is replaced with the username, e.g.
+	// "uid=%s,ou=people,dc=example,dc=com".
+	UserDNFormat string `json:"userDNFormat"`
+	// Attribute of group entries listing DNs of their members,
+	// "member" when empty.
+	GroupMemberAttribute string `json:"groupMemberAttribute"`
+	// Session policies of temporary credentials of members of groups,
+	// by DN of the group. Users who aren't members of any of these
+	// groups get no temporary credentials.
+	GroupPolicies map[string]json.RawMessage `json:"groupPolicies"`
+	// Validity of temporary credentials, e.g. "12h". Defaults to an
+	// hour when empty.
+	CredentialExpiry string `json:"credentialExpiry"`
+}
+
+// Validate - validates LDAP config.
+func (l ldapConfig) Validate() error {
+	if !l.Enable {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(l.ServerAddr); err != nil {
+		return fmt.Errorf("Invalid LDAP serverAddr value ‘%s’", l.ServerAddr)
+	}
+	switch l.TLS {
+	case "", ldapTLSOn, ldapTLSStartTLS, ldapTLSOff:
+	default:
+		return fmt.Errorf("Invalid LDAP tls value ‘%s’, it should be ‘%s’, ‘%s’ or ‘%s’",
+			l.TLS, ldapTLSOn, ldapTLSStartTLS, ldapTLSOff)
+	}
+	if strings.Count(l.UserDNFormat, "%s") != 1 || strings.Count(l.UserDNFormat, "%") != 1 {
+		return fmt.Errorf("Invalid LDAP userDNFormat value ‘%s’, it should contain ‘%%s’ once", l.UserDNFormat)
+	}
+	for groupDN, policy := range l.GroupPolicies {
+		if groupDN == "" {
+			return errors.New("Invalid LDAP groupPolicies, group DN cannot be empty")
+		}
+		if _, err := parseSessionPolicy(string(policy)); err != nil {
+			return fmt.Errorf("Invalid LDAP policy of group ‘%s’. %v", groupDN, err)
+		}
+	}
+	if _, err := l.getCredentialExpiry(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getCredentialExpiry - returns configured validity of temporary
+// credentials.
+func (l ldapConfig) getCredentialExpiry() (time.Duration, error) {
+	if l.CredentialExpiry == "" {
+		return defaultLDAPCredentialExpiry, nil
+	}
+	expiry, err := time.ParseDuration(l.CredentialExpiry)
+	if err != nil || expiry < minSTSDuration || expiry > maxSTSDuration {
+		return 0, fmt.Errorf("Invalid LDAP credentialExpiry value ‘%s’, it should be between %s and %s",
+			l.CredentialExpiry, minSTSDuration, maxSTSDuration)
+	}
+	return expiry, nil
+}
+
+// getGroupMemberAttribute - returns configured attribute of group
+// entries listing DNs of their members.
+func (l ldapConfig) getGroupMemberAttribute() string {
+	if l.GroupMemberAttribute == "" {
+		return defaultLDAPGroupMemberAttribute
+	}
+	return l.GroupMemberAttribute
+}
+
+// getGroupsPolicy - returns the session policy of members of groups,
+// with statements of the policies of all of them. Policies are
+// validated with config.
+func (l ldapConfig) getGroupsPolicy(groups []string) (string, error) {
+	groupsPolicy := bucketPolicy{Version: "2012-10-17"}
+	for _, groupDN := range groups {
+		policy, err := parseSessionPolicy(string(l.GroupPolicies[groupDN]))
+		if err != nil {
+			return "", err
+		}
+		groupsPolicy.Statements = append(groupsPolicy.Statements, policy.Statements...)
+	}
+	policyBytes, err := json.Marshal(groupsPolicy)
+	if err != nil {
+		return "", err
+	}
+	return string(policyBytes), nil
+}
+
+// BER tags of the LDAP messages used, see RFC 4511.
+const (
+	berTagInteger              = 0x02
+	berTagOctetString          = 0x04
+	berTagEnumerated           = 0x0a
+	berTagSequence             = 0x30
+	ldapTagBindRequest         = 0x60 // [APPLICATION 0], constructed.
+	ldapTagBindResponse        = 0x61 // [APPLICATION 1], constructed.
+	ldapTagUnbindRequest       = 0x42 // [APPLICATION 2], primitive.
+	ldapTagCompareRequest      = 0x6e // [APPLICATION 14], constructed.
+	ldapTagCompareResponse     = 0x6f // [APPLICATION 15], constructed.
+	ldapTagExtendedRequest     = 0x77 // [APPLICATION 23], constructed.
+	ldapTagExtendedResponse    = 0x78 // [APPLICATION 24], constructed.
+	ldapTagSimpleAuth          = 0x80 // [0], primitive.
+	ldapTagExtendedRequestName = 0x80 // [0], primitive.
+)
+
+// LDAP result codes.
+const (
+	ldapResultSuccess            = 0
+	ldapResultCompareFalse       = 5
+	ldapResultCompareTrue        = 6
+	ldapResultNoSuchAttribute    = 16
+	ldapResultNoSuchObject       = 32
+	ldapResultInvalidCredentials = 49
+)
+
+const (
+	// Only LDAPv3 is supported.
+	ldapProtocolVersion = 3
+	// Name of the StartTLS extended operation, see RFC 4511.
+	ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+	// Larger messages are rejected as malformed.
+	ldapMaxMessageSize = 1 << 20
+)
+
+var errMalformedLDAPMessage = errors.New("Malformed LDAP message")
+
+// berEncode - encodes a BER element with definite length.
+func berEncode(tag byte, value []byte) []byte {
+	var length []byte
+	switch n := len(value); {
+	case n < 0x80:
+		length = []byte{byte(n)}
+	case n <= 0xff:
+		length = []byte{0x81, byte(n)}
+	case n <= 0xffff:
+		length = []byte{0x82, byte(n >> 8), byte(n)}
+	default:
+		length = []byte{0x83, byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+	b := append([]byte{tag}, length...)
+	return append(b, value...)
+}
+
+// berEncodeInt - encodes a non negative integer BER element.
+func berEncodeInt(tag byte, v int) []byte {
+	value := []byte{byte(v)}
+	for v >>= 8; v > 0; v >>= 8 {
+		value = append([]byte{byte(v)}, value...)
+	}
+	// Keep the integer positive in two's complement.
+	if value[0]&0x80 != 0 {
+		value = append([]byte{0}, value...)
+	}
+	return berEncode(tag, value)
+}
+
+// berReadElement - reads a BER element with definite length.
+func berReadElement(r io.Reader) (tag byte, value []byte, err error) {
+	header := make([]byte, 2)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	tag = header[0]
+	length := int(header[1])
+	if length&0x80 != 0 {
+		numBytes := length & 0x7f
+		if numBytes == 0 || numBytes > 3 {
+			return 0, nil, errMalformedLDAPMessage
+		}
+		lengthBytes := make([]byte, numBytes)
+		if _, err = io.ReadFull(r, lengthBytes); err != nil {
+			return 0, nil, err
+		}
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > ldapMaxMessageSize {
+		return 0, nil, errMalformedLDAPMessage
+	}
+	value = make([]byte, length)
+	if _, err = io.ReadFull(r, value); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return tag, value, nil
+}
+
+// berParseElement - parses a BER element of the expected tag at the
+// beginning of b, returns its value and the remaining bytes.
+func berParseElement(b []byte, expectedTag byte) (value, rest []byte, err error) {
+	r := bytes.NewReader(b)
+	tag, value, err := berReadElement(r)
+	if err != nil || tag != expectedTag {
+		return nil, nil, errMalformedLDAPMessage
+	}
+	return value, b[len(b)-r.Len():], nil
+}
+
+// berParseInt - parses a non negative integer BER element at the
+// beginning of b.
+func berParseInt(b []byte, expectedTag byte) (v int, rest []byte, err error) {
+	value, rest, err := berParseElement(b, expectedTag)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(value) == 0 || len(value) > 4 || value[0]&0x80 != 0 {
+		return 0, nil, errMalformedLDAPMessage
+	}
+	for _, c := range value {
+		v = v<<8 | int(c)
+	}
+	return v, rest, nil
+}
+
+// newLDAPMessage - returns a message of the request or response.
+func newLDAPMessage(messageID int, protocolOp []byte) []byte {
+	message := berEncodeInt(berTagInteger, messageID)
+	message = append(message, protocolOp...)
+	return berEncode(berTagSequence, message)
+}
+
+// newLDAPBindRequest - returns a simple bind request.
+func newLDAPBindRequest(dn, password string) []byte {
+	bindRequest := berEncodeInt(berTagInteger, ldapProtocolVersion)
+	bindRequest = append(bindRequest, berEncode(berTagOctetString, []byte(dn))...)
+	bindRequest = append(bindRequest, berEncode(ldapTagSimpleAuth, []byte(password))...)
+	return berEncode(ldapTagBindRequest, bindRequest)
+}
+
+// newLDAPUnbindRequest - returns an unbind request.
+func newLDAPUnbindRequest() []byte {
+	return berEncode(ldapTagUnbindRequest, nil)
+}
+
+// newLDAPCompareRequest - returns a request comparing an attribute of
+// the entry of dn with value.
+func newLDAPCompareRequest(dn, attribute, value string) []byte {
+	assertion := berEncode(berTagOctetString, []byte(attribute))
+	assertion = append(assertion, berEncode(berTagOctetString, []byte(value))...)
+	compareRequest := berEncode(berTagOctetString, []byte(dn))
+	compareRequest = append(compareRequest, berEncode(berTagSequence, assertion)...)
+	return berEncode(ldapTagCompareRequest, compareRequest)
+}
+
+// newLDAPStartTLSRequest - returns a StartTLS extended request.
+func newLDAPStartTLSRequest() []byte {
+	return berEncode(ldapTagExtendedRequest, berEncode(ldapTagExtendedRequestName, []byte(ldapStartTLSOID)))
+}
+
+// parseLDAPResponse - returns result code and diagnostic message of a
+// response message of the expected tag. Fields following them, only
+// in some responses, are ignored.
+func parseLDAPResponse(message []byte, expectedMessageID int, expectedTag byte) (resultCode int, diagnostic string, err error) {
+	messageID, rest, err := berParseInt(message, berTagInteger)
+	if err != nil {
+		return 0, "", err
+	}
+	if messageID != expectedMessageID {
+		return 0, "", errMalformedLDAPMessage
+	}
+	response, _, err := berParseElement(rest, expectedTag)
+	if err != nil {
+		return 0, "", err
+	}
+	resultCode, rest, err = berParseInt(response, berTagEnumerated)
+	if err != nil {
+		return 0, "", err
+	}
+	// Skip matched DN.
+	if _, rest, err = berParseElement(rest, berTagOctetString); err != nil {
+		return 0, "", err
+	}
+	diagnosticBytes, _, err := berParseElement(rest, berTagOctetString)
+	if err != nil {
+		return 0, "", err
+	}
+	return resultCode, string(diagnosticBytes), nil
+}
+
+// escapeLDAPDN - escapes special characters of an attribute value
+// of a DN, see RFC 4514.
+func escapeLDAPDN(value string) string {
+	var escaped []byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == 0:
+			escaped = append(escaped, `\00`...)
+			continue
+		case strings.IndexByte(`"+,;<>\=`, c) >= 0,
+			c == '#' && i == 0,
+			c == ' ' && (i == 0 || i == len(value)-1):
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, c)
+	}
+	return string(escaped)
+}
+
+// ldapConn - connection to the LDAP server, numbering the messages
+// sent on it.
+type ldapConn struct {
+	net.Conn
+	messageID int
+}
+
+// send - sends a request without waiting for a response.
+func (c *ldapConn) send(protocolOp []byte) error {
+	c.messageID++
+	_, err := c.Write(newLDAPMessage(c.messageID, protocolOp))
+	return err
+}
+
+// request - sends a request and returns the result code and diagnostic
+// message of its response of the expected tag.
+func (c *ldapConn) request(protocolOp []byte, responseTag byte) (resultCode int, diagnostic string, err error) {
+	if err = c.send(protocolOp); err != nil {
+		return 0, "", err
+	}
+	tag, message, err := berReadElement(c)
+	if err != nil {
+		return 0, "", err
+	}
+	if tag != berTagSequence {
+		return 0, "", errMalformedLDAPMessage
+	}
+	return parseLDAPResponse(message, c.messageID, responseTag)
+}
+
+// ldapAuthProvider - validates passwords of users with a simple bind
+// to an LDAP server, and looks up the groups of users with policies.
+type ldapAuthProvider struct {
+	config ldapConfig
+}
+
+// dial - connects to the LDAP server, over TLS unless disabled in
+// config.
+func (l ldapAuthProvider) dial() (*ldapConn, error) {
+	host, _, err := net.SplitHostPort(l.config.ServerAddr)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{ServerName: host, RootCAs: globalRootCAs}
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+
+	var conn net.Conn
+	switch l.config.TLS {
+	case ldapTLSStartTLS, ldapTLSOff:
+		conn, err = dialer.Dial("tcp", l.config.ServerAddr)
+	default:
+		conn, err = tls.DialWithDialer(dialer, "tcp", l.config.ServerAddr, tlsConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &ldapConn{Conn: conn}
+	if err = conn.SetDeadline(time.Now().Add(ldapTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if l.config.TLS != ldapTLSStartTLS {
+		return c, nil
+	}
+
+	resultCode, diagnostic, err := c.request(newLDAPStartTLSRequest(), ldapTagExtendedResponse)
+	if err == nil && resultCode != ldapResultSuccess {
+		err = fmt.Errorf("LDAP StartTLS failed with result code %d: %s", resultCode, diagnostic)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err = tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.Conn = tlsConn
+	return c, nil
+}
+
+// Authenticate - binds to the LDAP server as the user, and returns
+// the DNs of groups in config the user is a member of. Groups are
+// compared as the user, their entries must be readable by their
+// members.
+func (l ldapAuthProvider) Authenticate(username, password string) (groups []string, err error) {
+	// LDAP servers accept binds without a password as
+	// unauthenticated, never let them through.
+	if username == "" || password == "" {
+		return nil, errAuthentication
+	}
+
+	conn, err := l.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	dn := fmt.Sprintf(l.config.UserDNFormat, escapeLDAPDN(username))
+	resultCode, diagnostic, err := conn.request(newLDAPBindRequest(dn, password), ldapTagBindResponse)
+	if err != nil {
+		return nil, err
+	}
+	switch resultCode {
+	case ldapResultSuccess:
+	case ldapResultInvalidCredentials:
+		return nil, errAuthentication
+	default:
+		return nil, fmt.Errorf("LDAP bind failed with result code %d: %s", resultCode, diagnostic)
+	}
+
+	memberAttribute := l.config.getGroupMemberAttribute()
+	for groupDN := range l.config.GroupPolicies {
+		resultCode, diagnostic, err = conn.request(newLDAPCompareRequest(groupDN, memberAttribute, dn), ldapTagCompareResponse)
+		if err != nil {
+			return nil, err
+		}
+		switch resultCode {
+		case ldapResultCompareTrue:
+			groups = append(groups, groupDN)
+		case ldapResultCompareFalse, ldapResultNoSuchAttribute, ldapResultNoSuchObject:
+		default:
+			return nil, fmt.Errorf("LDAP compare of group %s failed with result code %d: %s", groupDN, resultCode, diagnostic)
+		}
+	}
+
+	// Connection is closed right after, failure to unbind is harmless.
+	conn.send(newLDAPUnbindRequest())
+
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// initLDAPProvider - sets globalLDAPProvider from config, remains nil
+// when LDAP is disabled.
+func initLDAPProvider() {
+	cfg := serverConfig.GetLDAP()
+	if !cfg.Enable {
+		return
+	}
+	if cfg.TLS == ldapTLSOff {
+		log.Printf("LDAP passwords are sent to %s in clear text, set ldap tls to ‘%s’ or ‘%s’ in config.\n", cfg.ServerAddr, ldapTLSOn, ldapTLSStartTLS)
+	}
+	globalLDAPProvider = &ldapAuthProvider{config: cfg}
+}
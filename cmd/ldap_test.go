@@ -0,0 +1,514 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Tests LDAP config validation.
+func TestLDAPConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config         ldapConfig
+		shouldPass     bool
+		expectedExpiry time.Duration
+	}{
+		// Test case - 1.
+		// Disabled config is not validated.
+		{ldapConfig{ServerAddr: "invalid"}, true, defaultLDAPCredentialExpiry},
+		// Test case - 2.
+		// Valid config with default expiry.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "uid=%s,dc=example,dc=com"}, true, defaultLDAPCredentialExpiry},
+		// Test case - 3.
+		// Valid config with expiry.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:636", TLS: ldapTLSOn, UserDNFormat: "uid=%s,dc=example,dc=com", CredentialExpiry: "12h"}, true, 12 * time.Hour},
+		// Test case - 4.
+		// Server address without port.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com", UserDNFormat: "uid=%s,dc=example,dc=com"}, false, 0},
+		// Test case - 5.
+		// DN format without username.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "dc=example,dc=com"}, false, 0},
+		// Test case - 6.
+		// DN format with another verb.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "uid=%s,ou=%d,dc=example,dc=com"}, false, 0},
+		// Test case - 7.
+		// Invalid expiry.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "uid=%s,dc=example,dc=com", CredentialExpiry: "1"}, false, 0},
+		// Test case - 8.
+		// Expiry too short.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "uid=%s,dc=example,dc=com", CredentialExpiry: "1m"}, false, 0},
+		// Test case - 9.
+		// Expiry too long.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", UserDNFormat: "uid=%s,dc=example,dc=com", CredentialExpiry: "24h"}, false, 0},
+		// Test case - 10.
+		// StartTLS and clear text.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", TLS: ldapTLSStartTLS, UserDNFormat: "uid=%s,dc=example,dc=com"}, true, defaultLDAPCredentialExpiry},
+		// Test case - 11.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", TLS: ldapTLSOff, UserDNFormat: "uid=%s,dc=example,dc=com"}, true, defaultLDAPCredentialExpiry},
+		// Test case - 12.
+		// Invalid TLS.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:389", TLS: "true", UserDNFormat: "uid=%s,dc=example,dc=com"}, false, 0},
+		// Test case - 13.
+		// Valid group policy.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:636", UserDNFormat: "uid=%s,dc=example,dc=com", GroupPolicies: map[string]json.RawMessage{
+			"cn=admins,dc=example,dc=com": json.RawMessage(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`),
+		}}, true, defaultLDAPCredentialExpiry},
+		// Test case - 14.
+		// Invalid group policy.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:636", UserDNFormat: "uid=%s,dc=example,dc=com", GroupPolicies: map[string]json.RawMessage{
+			"cn=admins,dc=example,dc=com": json.RawMessage(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["*"]}]}`),
+		}}, false, 0},
+		// Test case - 15.
+		// Empty group DN.
+		{ldapConfig{Enable: true, ServerAddr: "ldap.example.com:636", UserDNFormat: "uid=%s,dc=example,dc=com", GroupPolicies: map[string]json.RawMessage{
+			"": json.RawMessage(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`),
+		}}, false, 0},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, but failed with: <ERROR> %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if !testCase.shouldPass {
+			continue
+		}
+		if expiry, _ := testCase.config.getCredentialExpiry(); expiry != testCase.expectedExpiry {
+			t.Errorf("Test %d: Expected expiry %s, found %s", i+1, testCase.expectedExpiry, expiry)
+		}
+	}
+}
+
+// Tests escaping of DN attribute values.
+func TestEscapeLDAPDN(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"alice", "alice"},
+		{"doe, john", `doe\, john`},
+		{"a+b=c", `a\+b\=c`},
+		{`"quoted"`, `\"quoted\"`},
+		{`back\slash`, `back\\slash`},
+		{"<x>;", `\<x\>\;`},
+		{"#hash#", `\#hash#`},
+		{" padded ", `\ padded\ `},
+		{"in ner", "in ner"},
+		{"nul\x00", `nul\00`},
+	}
+
+	for i, testCase := range testCases {
+		if escaped := escapeLDAPDN(testCase.value); escaped != testCase.expected {
+			t.Errorf("Test %d: Expected %s, found %s", i+1, testCase.expected, escaped)
+		}
+	}
+}
+
+// Tests BER encoding and parsing of integers and long values.
+func TestBEREncoding(t *testing.T) {
+	for i, v := range []int{0, 1, 127, 128, 255, 256, 65535, 1 << 24} {
+		v2, rest, err := berParseInt(berEncodeInt(berTagInteger, v), berTagInteger)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to parse integer: <ERROR> %v", i+1, err)
+		}
+		if v2 != v || len(rest) != 0 {
+			t.Errorf("Test %d: Expected %d, found %d with %d remaining bytes", i+1, v, v2, len(rest))
+		}
+	}
+
+	for i, n := range []int{0, 127, 128, 255, 256, 70000} {
+		value := bytes.Repeat([]byte("a"), n)
+		parsed, rest, err := berParseElement(append(berEncode(berTagOctetString, value), 0xff), berTagOctetString)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to parse element: <ERROR> %v", i+1, err)
+		}
+		if !bytes.Equal(parsed, value) || !bytes.Equal(rest, []byte{0xff}) {
+			t.Errorf("Test %d: Unexpected value of %d bytes with %d remaining bytes", i+1, len(parsed), len(rest))
+		}
+	}
+
+	// Wrong tag and truncated elements are rejected.
+	if _, _, err := berParseElement(berEncode(berTagOctetString, []byte("a")), berTagInteger); err == nil {
+		t.Error("Expected element with unexpected tag to be rejected")
+	}
+	if _, _, err := berParseElement([]byte{berTagOctetString, 2, 'a'}, berTagOctetString); err == nil {
+		t.Error("Expected truncated element to be rejected")
+	}
+}
+
+// testLDAPServer - LDAP server answering simple binds and compares of
+// group members.
+type testLDAPServer struct {
+	// Passwords of users by DN.
+	users map[string]string
+	// DNs of members of groups by DN of the group, in member
+	// attribute.
+	groups map[string][]string
+	// TLS of connections, as in ldapConfig, and certificate of the
+	// server.
+	tls       string
+	tlsConfig *tls.Config
+	// DNs of bind requests are sent to binds when set.
+	binds chan<- string
+}
+
+// startTestLDAPServer - starts the LDAP server on a local port.
+func startTestLDAPServer(t *testing.T, server testLDAPServer) (addr string, stop func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	respond := func(conn net.Conn, messageID int, tag byte, resultCode int) error {
+		response := berEncodeInt(berTagEnumerated, resultCode)
+		response = append(response, berEncode(berTagOctetString, nil)...)
+		response = append(response, berEncode(berTagOctetString, []byte("diagnostic"))...)
+		_, err := conn.Write(newLDAPMessage(messageID, berEncode(tag, response)))
+		return err
+	}
+
+	bind := func(request []byte) int {
+		_, rest, err := berParseInt(request, berTagInteger)
+		if err != nil {
+			return ldapResultInvalidCredentials
+		}
+		dn, rest, err := berParseElement(rest, berTagOctetString)
+		if err != nil {
+			return ldapResultInvalidCredentials
+		}
+		password, _, err := berParseElement(rest, ldapTagSimpleAuth)
+		if err != nil {
+			return ldapResultInvalidCredentials
+		}
+		if server.binds != nil {
+			server.binds <- string(dn)
+		}
+		if expected, ok := server.users[string(dn)]; ok && expected == string(password) {
+			return ldapResultSuccess
+		}
+		return ldapResultInvalidCredentials
+	}
+
+	compare := func(request []byte) int {
+		dn, rest, err := berParseElement(request, berTagOctetString)
+		if err != nil {
+			return ldapResultNoSuchObject
+		}
+		assertion, _, err := berParseElement(rest, berTagSequence)
+		if err != nil {
+			return ldapResultNoSuchObject
+		}
+		attribute, rest, err := berParseElement(assertion, berTagOctetString)
+		if err != nil {
+			return ldapResultNoSuchAttribute
+		}
+		value, _, err := berParseElement(rest, berTagOctetString)
+		if err != nil {
+			return ldapResultNoSuchAttribute
+		}
+		members, ok := server.groups[string(dn)]
+		if !ok {
+			return ldapResultNoSuchObject
+		}
+		if string(attribute) != defaultLDAPGroupMemberAttribute {
+			return ldapResultNoSuchAttribute
+		}
+		for _, member := range members {
+			if member == string(value) {
+				return ldapResultCompareTrue
+			}
+		}
+		return ldapResultCompareFalse
+	}
+
+	serve := func(conn net.Conn) {
+		defer func() { conn.Close() }()
+		if server.tls == ldapTLSOn {
+			conn = tls.Server(conn, server.tlsConfig)
+		}
+		for {
+			tag, message, err := berReadElement(conn)
+			if err != nil || tag != berTagSequence {
+				return
+			}
+			messageID, rest, err := berParseInt(message, berTagInteger)
+			if err != nil {
+				return
+			}
+			opTag, request, err := berReadElement(bytes.NewReader(rest))
+			if err != nil {
+				return
+			}
+			switch opTag {
+			case ldapTagBindRequest:
+				err = respond(conn, messageID, ldapTagBindResponse, bind(request))
+			case ldapTagCompareRequest:
+				err = respond(conn, messageID, ldapTagCompareResponse, compare(request))
+			case ldapTagExtendedRequest:
+				// Protocol error unless StartTLS is expected.
+				if server.tls != ldapTLSStartTLS {
+					err = respond(conn, messageID, ldapTagExtendedResponse, 2)
+					break
+				}
+				if err = respond(conn, messageID, ldapTagExtendedResponse, ldapResultSuccess); err == nil {
+					conn = tls.Server(conn, server.tlsConfig)
+				}
+			default:
+				// Unbind.
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return listener.Addr().String(), func() { listener.Close() }
+}
+
+// newTestLDAPTLSConfig - returns the TLS config of a test LDAP server
+// with a self-signed certificate of 127.0.0.1, and a pool trusting it.
+func newTestLDAPTLSConfig(t *testing.T) (*tls.Config, *x509.CertPool) {
+	certPEM, keyPEM, err := generateTLSCertKey("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(certPEM) {
+		t.Fatal("Unable to add test certificate to pool")
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, rootCAs
+}
+
+// Tests LDAP simple bind authentication.
+func TestLDAPAuthProvider(t *testing.T) {
+	users := map[string]string{
+		"uid=alice,ou=people,dc=example,dc=com":      "alice123",
+		`uid=doe\, john,ou=people,dc=example,dc=com`: "john123",
+	}
+	binds := make(chan string, 10)
+	addr, stop := startTestLDAPServer(t, testLDAPServer{users: users, tls: ldapTLSOff, binds: binds})
+	defer stop()
+
+	provider := ldapAuthProvider{config: ldapConfig{
+		Enable:       true,
+		ServerAddr:   addr,
+		TLS:          ldapTLSOff,
+		UserDNFormat: "uid=%s,ou=people,dc=example,dc=com",
+	}}
+
+	testCases := []struct {
+		username    string
+		password    string
+		expectedErr error
+		isBound     bool
+	}{
+		// Test case - 1.
+		// Valid password.
+		{"alice", "alice123", nil, true},
+		// Test case - 2.
+		// Invalid password.
+		{"alice", "bob123", errAuthentication, true},
+		// Test case - 3.
+		// Unknown user.
+		{"bob", "bob123", errAuthentication, true},
+		// Test case - 4.
+		// Username with special characters is escaped.
+		{"doe, john", "john123", nil, true},
+		// Test case - 5.
+		// Empty password is rejected without an unauthenticated bind.
+		{"alice", "", errAuthentication, false},
+		// Test case - 6.
+		// Empty username is rejected.
+		{"", "alice123", errAuthentication, false},
+	}
+
+	for i, testCase := range testCases {
+		_, err := provider.Authenticate(testCase.username, testCase.password)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, found %v", i+1, testCase.expectedErr, err)
+		}
+		select {
+		case <-binds:
+			if !testCase.isBound {
+				t.Errorf("Test %d: Expected no bind to the LDAP server", i+1)
+			}
+		default:
+			if testCase.isBound {
+				t.Errorf("Test %d: Expected a bind to the LDAP server", i+1)
+			}
+		}
+	}
+
+	// Unreachable server fails with an error other than
+	// errAuthentication.
+	stop()
+	if _, err := provider.Authenticate("alice", "alice123"); err == nil || err == errAuthentication {
+		t.Errorf("Expected connection error from stopped LDAP server, found %v", err)
+	}
+}
+
+// Tests connections to the LDAP server are verified over TLS.
+func TestLDAPAuthProviderTLS(t *testing.T) {
+	defer func() { globalRootCAs = nil }()
+	tlsConfig, rootCAs := newTestLDAPTLSConfig(t)
+	_, otherRootCAs := newTestLDAPTLSConfig(t)
+	users := map[string]string{"uid=alice,ou=people,dc=example,dc=com": "alice123"}
+
+	testCases := []struct {
+		serverTLS  string
+		clientTLS  string
+		rootCAs    *x509.CertPool
+		shouldPass bool
+	}{
+		// Test case - 1.
+		// LDAPS by default.
+		{ldapTLSOn, "", rootCAs, true},
+		// Test case - 2.
+		{ldapTLSOn, ldapTLSOn, rootCAs, true},
+		// Test case - 3.
+		// StartTLS.
+		{ldapTLSStartTLS, ldapTLSStartTLS, rootCAs, true},
+		// Test case - 4.
+		// Untrusted certificate is rejected.
+		{ldapTLSOn, ldapTLSOn, otherRootCAs, false},
+		// Test case - 5.
+		{ldapTLSStartTLS, ldapTLSStartTLS, otherRootCAs, false},
+		// Test case - 6.
+		// Server without TLS.
+		{ldapTLSOff, "", rootCAs, false},
+		// Test case - 7.
+		{ldapTLSOff, ldapTLSStartTLS, rootCAs, false},
+	}
+
+	for i, testCase := range testCases {
+		addr, stop := startTestLDAPServer(t, testLDAPServer{users: users, tls: testCase.serverTLS, tlsConfig: tlsConfig})
+		globalRootCAs = testCase.rootCAs
+		provider := ldapAuthProvider{config: ldapConfig{
+			Enable:       true,
+			ServerAddr:   addr,
+			TLS:          testCase.clientTLS,
+			UserDNFormat: "uid=%s,ou=people,dc=example,dc=com",
+		}}
+		_, err := provider.Authenticate("alice", "alice123")
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, but failed with: <ERROR> %v", i+1, err)
+		}
+		if !testCase.shouldPass && (err == nil || err == errAuthentication) {
+			t.Errorf("Test %d: Expected connection error, found %v", i+1, err)
+		}
+		stop()
+	}
+}
+
+// Tests lookup of groups of LDAP users and their policies.
+func TestLDAPAuthProviderGroups(t *testing.T) {
+	alice := "uid=alice,ou=people,dc=example,dc=com"
+	bob := "uid=bob,ou=people,dc=example,dc=com"
+	carol := "uid=carol,ou=people,dc=example,dc=com"
+	admins := "cn=admins,ou=groups,dc=example,dc=com"
+	readers := "cn=readers,ou=groups,dc=example,dc=com"
+	adminsPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`
+	readersPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`
+
+	addr, stop := startTestLDAPServer(t, testLDAPServer{
+		users:  map[string]string{alice: "alice123", bob: "bob123", carol: "carol123"},
+		groups: map[string][]string{admins: {alice}, readers: {alice, bob}},
+		tls:    ldapTLSOff,
+	})
+	defer stop()
+
+	config := ldapConfig{
+		Enable:       true,
+		ServerAddr:   addr,
+		TLS:          ldapTLSOff,
+		UserDNFormat: "uid=%s,ou=people,dc=example,dc=com",
+		GroupPolicies: map[string]json.RawMessage{
+			admins:  json.RawMessage(adminsPolicy),
+			readers: json.RawMessage(readersPolicy),
+			// Missing group is ignored.
+			"cn=missing,ou=groups,dc=example,dc=com": json.RawMessage(adminsPolicy),
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	provider := ldapAuthProvider{config: config}
+
+	testCases := []struct {
+		username           string
+		password           string
+		expectedGroups     []string
+		expectedStatements int
+	}{
+		// Test case - 1.
+		// Member of both groups.
+		{"alice", "alice123", []string{admins, readers}, 2},
+		// Test case - 2.
+		{"bob", "bob123", []string{readers}, 1},
+		// Test case - 3.
+		// Member of no group.
+		{"carol", "carol123", nil, 0},
+	}
+
+	for i, testCase := range testCases {
+		groups, err := provider.Authenticate(testCase.username, testCase.password)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to authenticate: <ERROR> %v", i+1, err)
+		}
+		if !reflect.DeepEqual(groups, testCase.expectedGroups) {
+			t.Errorf("Test %d: Expected groups %v, found %v", i+1, testCase.expectedGroups, groups)
+		}
+		groupsPolicy, err := config.getGroupsPolicy(groups)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to get policy: <ERROR> %v", i+1, err)
+		}
+		if len(groups) == 0 {
+			continue
+		}
+		policy, err := parseSessionPolicy(groupsPolicy)
+		if err != nil {
+			t.Fatalf("Test %d: Invalid policy %s: <ERROR> %v", i+1, groupsPolicy, err)
+		}
+		if len(policy.Statements) != testCase.expectedStatements {
+			t.Errorf("Test %d: Expected %d statements, found %d", i+1, testCase.expectedStatements, len(policy.Statements))
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// readLifecycleBody - reads lifecycle XML from request body.
+func readLifecycleBody(r *http.Request) ([]byte, APIErrorCode) {
+	// Lifecycle always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxLifecycleBodySize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+	return buffer.Bytes(), ErrNone
+}
+
+// GetBucketLifecycleHandler - GET Bucket lifecycle
+// -----------------
+// Returns the lifecycle configuration of the bucket.
+func (api objectAPIHandlers) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lc, err := loadBucketLifecycle(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lifecycleBytes, err := xml.Marshal(lc)
+	if err != nil {
+		errorIf(err, "Unable to marshal lifecycle into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, lifecycleBytes)
+}
+
+// PutBucketLifecycleHandler - PUT Bucket lifecycle
+// -----------------
+// Replaces the lifecycle configuration of the bucket, transition and
+// expiration rules are supported.
+func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lifecycleBytes, s3Error := readLifecycleBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	lc, s3Error := parseLifecycle(lifecycleBytes)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = persistBucketLifecycle(bucket, lc, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketLifecycleConfig)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteBucketLifecycleHandler - DELETE Bucket lifecycle
+// -----------------
+// Removes the lifecycle configuration of the bucket, objects already
+// transitioned stay in the remote tier.
+func (api objectAPIHandlers) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing lifecycle which is not set is not an error.
+	if err = removeBucketLifecycle(bucket, objAPI); err != nil && !isErrObjectNotFound(err) {
+		errorIf(err, "Unable to remove bucket lifecycle.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketLifecycleConfig)
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,168 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests PUT, GET and DELETE bucket lifecycle.
+func TestBucketLifecycleHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketLifecycleHandlers, []string{"GetBucketLifecycle", "PutBucketLifecycle", "DeleteBucketLifecycle"})
+}
+
+func testBucketLifecycleHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	validLifecycle := `<LifecycleConfiguration><Rule><ID>archive</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status>` +
+		`<Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition></Rule></LifecycleConfiguration>`
+	invalidLifecycle := `<LifecycleConfiguration><Rule><Status>Enabled</Status></Rule></LifecycleConfiguration>`
+	expirationLifecycle := `<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration>` +
+		`<Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition></Rule></LifecycleConfiguration>`
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		body               string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+	}{
+		// No lifecycle set yet.
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid lifecycle.
+		{"PUT", bucketName, invalidLifecycle, credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Malformed XML.
+		{"PUT", bucketName, "<LifecycleConfiguration>", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Expiration along with transition.
+		{"PUT", bucketName, expirationLifecycle, credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Non-existent bucket.
+		{"PUT", "non-existent-bucket", validLifecycle, credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid credentials.
+		{"PUT", bucketName, validLifecycle, "abcd1234", credentials.SecretKey, http.StatusForbidden},
+		// Valid lifecycle.
+		{"PUT", bucketName, validLifecycle, credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Remove lifecycle.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Removing again is not an error.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getLifecycleURL("", testCase.bucketName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" && rec.Code == http.StatusOK {
+			var lc lifecycle
+			if err = xml.Unmarshal(rec.Body.Bytes(), &lc); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if len(lc.Rules) != 1 || lc.Rules[0].ID != "archive" || lc.Rules[0].getPrefix() != "logs/" ||
+				lc.Rules[0].Transition == nil || lc.Rules[0].Transition.Days != 30 {
+				t.Fatalf("Test %d: %s: unexpected lifecycle %+v", i+1, instanceType, lc.Rules)
+			}
+		}
+	}
+}
+
+// Tests x-amz-expiration is reported on PUT, GET and HEAD object.
+func TestObjectExpirationHeader(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectExpirationHeader, []string{"PutBucketLifecycle", "PutObject", "GetObject", "HeadObject"})
+}
+
+func testObjectExpirationHeader(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	data := []byte("hello, world")
+	// Sends a signed request, returns its response.
+	send := func(method, urlStr string, body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: %s %s: expected status 200, got %d", instanceType, method, urlStr, rec.Code)
+		}
+		return rec
+	}
+
+	// No lifecycle set yet.
+	if rec := send("PUT", getPutObjectURL("", bucketName, "tmp/a"), data); rec.Header().Get(amzExpirationHeader) != "" {
+		t.Fatalf("%s: unexpected expiration %s", instanceType, rec.Header().Get(amzExpirationHeader))
+	}
+
+	send("PUT", getLifecycleURL("", bucketName), []byte(`<LifecycleConfiguration><Rule><ID>cleanup</ID><Prefix>tmp/</Prefix>`+
+		`<Status>Enabled</Status><Expiration><Days>7</Days></Expiration></Rule></LifecycleConfiguration>`))
+
+	testCases := []struct {
+		method   string
+		urlStr   string
+		body     []byte
+		expected bool
+	}{
+		// Test 1: PUT of an object of the rule.
+		{"PUT", getPutObjectURL("", bucketName, "tmp/b"), data, true},
+		// Test 2: GET of an object of the rule.
+		{"GET", getGetObjectURL("", bucketName, "tmp/a"), nil, true},
+		// Test 3: HEAD of an object of the rule.
+		{"HEAD", getHeadObjectURL("", bucketName, "tmp/a"), nil, true},
+		// Test 4: object of no rule.
+		{"PUT", getPutObjectURL("", bucketName, "data/c"), data, false},
+	}
+	for i, testCase := range testCases {
+		rec := send(testCase.method, testCase.urlStr, testCase.body)
+		header := rec.Header().Get(amzExpirationHeader)
+		if !testCase.expected {
+			if header != "" {
+				t.Fatalf("Test %d: %s: unexpected expiration %s", i+1, instanceType, header)
+			}
+			continue
+		}
+		modTime, err := time.Parse(http.TimeFormat, rec.Header().Get("Last-Modified"))
+		if testCase.method == "PUT" {
+			modTime, err = UTCNow(), nil
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		// Object expires at midnight UTC, 7 days after creation.
+		expiry := modTime.UTC().Add(7 * 24 * time.Hour).Truncate(24 * time.Hour).Add(24 * time.Hour)
+		expected := `expiry-date="` + expiry.Format(http.TimeFormat) + `", rule-id="cleanup"`
+		if header != expected {
+			t.Fatalf("Test %d: %s: expected expiration %s, got %s", i+1, instanceType, expected, header)
+		}
+	}
+}
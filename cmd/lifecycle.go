@@ -0,0 +1,542 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+const (
+	// Bucket lifecycle config file name, saved alongside other
+	// bucket configs in minioMetaBucket.
+	bucketLifecycleConfig = "lifecycle.xml"
+
+	// Limits on lifecycle configuration as documented by S3.
+	maxLifecycleRules    = 1000
+	maxLifecycleRuleID   = 255
+	maxLifecycleBodySize = 1 * 1024 * 1024
+
+	// Rule status values.
+	lifecycleRuleEnabled  = "Enabled"
+	lifecycleRuleDisabled = "Disabled"
+
+	// Interval between two lifecycle transition passes.
+	lifecycleTransitionInterval = time.Hour
+
+	// Response header reporting when an object expires and the rule
+	// expiring it.
+	amzExpirationHeader = "X-Amz-Expiration"
+
+	// Maximum number of objects reported by a lifecycle dry-run,
+	// objects beyond it are only counted.
+	lifecycleDryRunMaxObjects = 1000
+)
+
+// Internal error used to signal lifecycle is not set.
+var errNoSuchLifecycleConfiguration = errors.New("The lifecycle configuration does not exist")
+
+// lifecycleTransition - moves objects to the storage class of the
+// remote tier, Days after their creation.
+type lifecycleTransition struct {
+	Days         int    `xml:"Days"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// lifecycleExpiration - deletes objects Days after their creation.
+type lifecycleExpiration struct {
+	Days int `xml:"Days,omitempty"`
+}
+
+// lifecycleRule - a single lifecycle rule, objects are selected by
+// either the legacy Prefix or Filter>Prefix element.
+type lifecycleRule struct {
+	ID           string               `xml:"ID,omitempty"`
+	Prefix       string               `xml:"Prefix,omitempty"`
+	FilterPrefix string               `xml:"Filter>Prefix,omitempty"`
+	Status       string               `xml:"Status"`
+	Transition   *lifecycleTransition `xml:"Transition,omitempty"`
+	Expiration   *lifecycleExpiration `xml:"Expiration,omitempty"`
+}
+
+// getPrefix - returns object name prefix selected by the rule.
+func (r lifecycleRule) getPrefix() string {
+	if r.FilterPrefix != "" {
+		return r.FilterPrefix
+	}
+	return r.Prefix
+}
+
+// getTransitionOlderThan - returns the time before which objects
+// selected by the rule were last modified to be transitioned at now.
+func (r lifecycleRule) getTransitionOlderThan(now time.Time) time.Time {
+	return now.Add(-time.Duration(r.Transition.Days) * 24 * time.Hour)
+}
+
+// getExpiryDate - returns when an object last modified at modTime
+// expires by the rule, Days after modTime rounded up to the next
+// midnight UTC as S3 does.
+func (r lifecycleRule) getExpiryDate(modTime time.Time) time.Time {
+	expiry := modTime.UTC().Add(time.Duration(r.Expiration.Days) * 24 * time.Hour)
+	return expiry.Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// isTransitionDue - returns true if object is to be transitioned,
+// stubs of transitioned objects are empty, so are objects with nothing
+// to transition.
+func isTransitionDue(objInfo ObjectInfo, olderThan time.Time) bool {
+	return objInfo.Size > 0 && !isObjectTransitioned(objInfo) && objInfo.ModTime.Before(olderThan)
+}
+
+// lifecycle - lifecycle configuration of a bucket, sent and received as
+//
+//	<LifecycleConfiguration><Rule>...</Rule></LifecycleConfiguration>
+type lifecycle struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+// parseLifecycle - parses and validates lifecycle XML, returns ErrNone
+// on success.
+func parseLifecycle(data []byte) (lifecycle, APIErrorCode) {
+	var lc lifecycle
+	if err := xml.Unmarshal(data, &lc); err != nil {
+		return lc, ErrMalformedXML
+	}
+	if len(lc.Rules) == 0 || len(lc.Rules) > maxLifecycleRules {
+		return lc, ErrInvalidLifecycle
+	}
+	ids := set.NewStringSet()
+	for _, rule := range lc.Rules {
+		if len(rule.ID) > maxLifecycleRuleID || (rule.ID != "" && ids.Contains(rule.ID)) {
+			return lc, ErrInvalidLifecycle
+		}
+		if rule.Status != lifecycleRuleEnabled && rule.Status != lifecycleRuleDisabled {
+			return lc, ErrInvalidLifecycle
+		}
+		// A rule either transitions or expires objects, or both.
+		if rule.Transition == nil && rule.Expiration == nil {
+			return lc, ErrInvalidLifecycle
+		}
+		if rule.Transition != nil {
+			if rule.Transition.Days <= 0 {
+				return lc, ErrInvalidLifecycle
+			}
+			if rule.Transition.StorageClass == "" || rule.Transition.StorageClass == globalMinioDefaultStorageClass {
+				return lc, ErrInvalidLifecycle
+			}
+		}
+		if rule.Expiration != nil && rule.Expiration.Days <= 0 {
+			return lc, ErrInvalidLifecycle
+		}
+		if rule.ID != "" {
+			ids.Add(rule.ID)
+		}
+	}
+	return lc, ErrNone
+}
+
+// loadBucketLifecycle - loads lifecycle of a bucket, returns
+// errNoSuchLifecycleConfiguration if none is set.
+func loadBucketLifecycle(bucket string, objAPI ObjectLayer) (*lifecycle, error) {
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+
+	// Acquire a read lock on lifecycle config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, lcPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchLifecycleConfiguration
+		}
+		errorIf(err, "Unable to load lifecycle for bucket %s", bucket)
+		return nil, err
+	}
+
+	lc := &lifecycle{}
+	if err = xml.Unmarshal(buffer.Bytes(), lc); err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
+
+// persistBucketLifecycle - saves lifecycle of a bucket.
+func persistBucketLifecycle(bucket string, lc lifecycle, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(lc)
+	if err != nil {
+		return err
+	}
+
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+
+	// Acquire a write lock on lifecycle config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, lcPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write lifecycle for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketLifecycle - removes lifecycle of a bucket.
+func removeBucketLifecycle(bucket string, objAPI ObjectLayer) error {
+	lcPath := path.Join(bucketConfigPrefix, bucket, bucketLifecycleConfig)
+
+	// Acquire a write lock on lifecycle config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lcPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, lcPath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketLifecycle - returns lifecycle of bucket, nil if none
+// is set. Lifecycles are cached in globalBucketConfigCache.
+func getCachedBucketLifecycle(bucket string, objAPI ObjectLayer) (*lifecycle, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketLifecycleConfig, func() (interface{}, error) {
+		lc, err := loadBucketLifecycle(bucket, objAPI)
+		if err == errNoSuchLifecycleConfiguration {
+			return (*lifecycle)(nil), nil
+		}
+		return lc, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*lifecycle), nil
+}
+
+// getObjectExpiration - returns the enabled rule expiring the object
+// first and when it does, ok is false if no rule expires it. Objects
+// of WORM buckets never expire.
+func getObjectExpiration(objAPI ObjectLayer, bucket string, objInfo ObjectInfo) (rule lifecycleRule, expiry time.Time, ok bool) {
+	lc, err := getCachedBucketLifecycle(bucket, objAPI)
+	if err != nil || lc == nil {
+		return rule, expiry, false
+	}
+	if worm, werr := isWormEnabled(bucket, objAPI); werr != nil || worm {
+		return rule, expiry, false
+	}
+	for _, r := range lc.Rules {
+		if r.Status != lifecycleRuleEnabled || r.Expiration == nil || !hasPrefix(objInfo.Name, r.getPrefix()) {
+			continue
+		}
+		if date := r.getExpiryDate(objInfo.ModTime); !ok || date.Before(expiry) {
+			rule, expiry, ok = r, date, true
+		}
+	}
+	return rule, expiry, ok
+}
+
+// setObjectExpirationHeader - sets x-amz-expiration to the expiry date
+// and rule ID of an object expired by the bucket lifecycle, as in
+//
+//	expiry-date="Fri, 23 Dec 2012 00:00:00 GMT", rule-id="logs"
+func setObjectExpirationHeader(w http.ResponseWriter, objAPI ObjectLayer, bucket string, objInfo ObjectInfo) {
+	rule, expiry, ok := getObjectExpiration(objAPI, bucket, objInfo)
+	if !ok {
+		return
+	}
+	w.Header().Set(amzExpirationHeader, fmt.Sprintf(`expiry-date="%s", rule-id="%s"`,
+		expiry.Format(http.TimeFormat), rule.ID))
+}
+
+// expireObject - deletes object if rule expires it at now, along with
+// its remote copy if transitioned. Transitioned objects expire after
+// their original modification time.
+func expireObject(objAPI ObjectLayer, tier remoteTier, bucket, object string, rule lifecycleRule, now time.Time) error {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+	transitioned := isObjectTransitioned(objInfo)
+	if transitioned {
+		objInfo = getTransitionedObjectInfo(objInfo)
+	}
+	// Overwritten since listed.
+	if rule.getExpiryDate(objInfo.ModTime).After(now) {
+		return nil
+	}
+	// Locked objects don't expire.
+	if checkObjectLock(objInfo, false) != nil {
+		return nil
+	}
+
+	if err = objAPI.DeleteObject(bucket, object); err != nil {
+		return err
+	}
+	if transitioned && tier != nil {
+		removeTransitionedObject(tier, bucket, object)
+	}
+
+	// Notify object deleted event.
+	eventNotify(eventData{
+		Type:   ObjectRemovedDelete,
+		Bucket: bucket,
+		ObjInfo: ObjectInfo{
+			Name: object,
+		},
+	})
+	return nil
+}
+
+// lifecycleTransitioner - moves objects matching enabled lifecycle
+// transition rules to the remote tier and deletes objects matching
+// enabled expiration rules.
+type lifecycleTransitioner struct {
+	objAPI   func() ObjectLayer
+	tier     func() remoteTier
+	interval time.Duration
+}
+
+// newLifecycleTransitioner - initialize a new lifecycle transitioner.
+func newLifecycleTransitioner(objAPI func() ObjectLayer, tier func() remoteTier, interval time.Duration) *lifecycleTransitioner {
+	return &lifecycleTransitioner{
+		objAPI:   objAPI,
+		tier:     tier,
+		interval: interval,
+	}
+}
+
+// transition - runs a single pass over all buckets with a lifecycle,
+// objects are expired first and only transitioned if a remote tier is
+// configured.
+func (t *lifecycleTransitioner) transition(now time.Time) {
+	objAPI := t.objAPI()
+	tier := t.tier()
+	if objAPI == nil {
+		return
+	}
+
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets for lifecycle transitions.")
+		return
+	}
+
+	for _, bucket := range buckets {
+		lc, err := loadBucketLifecycle(bucket.Name, objAPI)
+		if err != nil {
+			continue
+		}
+		// Objects of WORM buckets never expire.
+		worm, err := isWormEnabled(bucket.Name, objAPI)
+		if err != nil {
+			continue
+		}
+		for _, rule := range lc.Rules {
+			if rule.Status != lifecycleRuleEnabled {
+				continue
+			}
+			if rule.Expiration != nil && !worm {
+				t.expirePrefix(objAPI, tier, bucket.Name, rule, now)
+			}
+			if rule.Transition != nil && tier != nil {
+				t.transitionPrefix(objAPI, tier, bucket.Name, rule.getPrefix(), rule.Transition.StorageClass, rule.getTransitionOlderThan(now))
+			}
+		}
+	}
+}
+
+// expirePrefix - deletes all objects selected by rule which expire at
+// now.
+func (t *lifecycleTransitioner) expirePrefix(objAPI ObjectLayer, tier remoteTier, bucket string, rule lifecycleRule, now time.Time) {
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(bucket, rule.getPrefix(), marker, "", maxObjectList)
+		if err != nil {
+			errorIf(err, "Unable to list objects of %s for lifecycle expiration.", bucket)
+			return
+		}
+		for _, object := range result.Objects {
+			if rule.getExpiryDate(object.ModTime).After(now) {
+				continue
+			}
+			if err = expireObject(objAPI, tier, bucket, object.Name, rule, now); err != nil {
+				errorIf(err, "Unable to expire %s/%s.", bucket, object.Name)
+			}
+		}
+		if !result.IsTruncated {
+			return
+		}
+		marker = result.NextMarker
+	}
+}
+
+// transitionPrefix - transitions all objects under prefix last
+// modified before olderThan.
+func (t *lifecycleTransitioner) transitionPrefix(objAPI ObjectLayer, tier remoteTier, bucket, prefix, storageClass string, olderThan time.Time) {
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			errorIf(err, "Unable to list objects of %s for lifecycle transitions.", bucket)
+			return
+		}
+		for _, object := range result.Objects {
+			if !isTransitionDue(object, olderThan) {
+				continue
+			}
+			if err = transitionObject(objAPI, tier, bucket, object.Name, storageClass, olderThan); err != nil {
+				errorIf(err, "Unable to transition %s/%s to the remote tier.", bucket, object.Name)
+			}
+		}
+		if !result.IsTruncated {
+			return
+		}
+		marker = result.NextMarker
+	}
+}
+
+// run - transitions at every interval until doneCh is closed.
+func (t *lifecycleTransitioner) run(doneCh <-chan struct{}) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.transition(UTCNow())
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// startLifecycleTransitioner - starts lifecycle transitions and
+// expirations in background, only one server in a distributed setup
+// acts on objects.
+func startLifecycleTransitioner(endpoints EndpointList, doneCh <-chan struct{}) {
+	if len(endpoints) == 0 || !endpoints[0].IsLocal {
+		return
+	}
+	transitioner := newLifecycleTransitioner(newObjectLayerFn, func() remoteTier {
+		return globalRemoteTier
+	}, lifecycleTransitionInterval)
+	go transitioner.run(doneCh)
+}
+
+// lifecycleDryRunRule - objects a lifecycle rule would act on.
+type lifecycleDryRunRule struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// lifecycleDryRunObject - an object a lifecycle rule would act on.
+type lifecycleDryRunObject struct {
+	Rule         string    `json:"rule"`
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"modTime"`
+	StorageClass string    `json:"storageClass"`
+}
+
+// lifecycleDryRunResult - result of evaluating a lifecycle against
+// the objects of a bucket, without acting on them.
+type lifecycleDryRunResult struct {
+	Bucket      string                  `json:"bucket"`
+	Time        time.Time               `json:"time"`
+	Rules       []lifecycleDryRunRule   `json:"rules"`
+	Transitions []lifecycleDryRunObject `json:"transitions"`
+	IsTruncated bool                    `json:"isTruncated"`
+}
+
+// dryRunLifecycle - reports objects of bucket which would be
+// transitioned at now by lifecycle rules, disabled rules are
+// evaluated as if they were enabled. An object selected by several
+// rules is reported under the first one, as transitions would do.
+func dryRunLifecycle(objAPI ObjectLayer, bucket string, lc lifecycle, now time.Time) (lifecycleDryRunResult, error) {
+	result := lifecycleDryRunResult{
+		Bucket:      bucket,
+		Time:        now,
+		Rules:       make([]lifecycleDryRunRule, len(lc.Rules)),
+		Transitions: []lifecycleDryRunObject{},
+	}
+
+	// Returns true if object is due for a rule before the i-th one.
+	dueEarlier := func(i int, object ObjectInfo) bool {
+		for _, rule := range lc.Rules[:i] {
+			if rule.Transition != nil && hasPrefix(object.Name, rule.getPrefix()) &&
+				isTransitionDue(object, rule.getTransitionOlderThan(now)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, rule := range lc.Rules {
+		result.Rules[i] = lifecycleDryRunRule{
+			ID:     rule.ID,
+			Status: rule.Status,
+			Prefix: rule.getPrefix(),
+		}
+		if rule.Transition == nil {
+			continue
+		}
+		olderThan := rule.getTransitionOlderThan(now)
+		marker := ""
+		for {
+			objects, err := objAPI.ListObjects(bucket, rule.getPrefix(), marker, "", maxObjectList)
+			if err != nil {
+				return result, err
+			}
+			for _, object := range objects.Objects {
+				if !isTransitionDue(object, olderThan) || dueEarlier(i, object) {
+					continue
+				}
+				result.Rules[i].Objects++
+				result.Rules[i].Size += object.Size
+				if len(result.Transitions) == lifecycleDryRunMaxObjects {
+					result.IsTruncated = true
+					continue
+				}
+				result.Transitions = append(result.Transitions, lifecycleDryRunObject{
+					Rule:         rule.ID,
+					Name:         object.Name,
+					Size:         object.Size,
+					ModTime:      object.ModTime,
+					StorageClass: rule.Transition.StorageClass,
+				})
+			}
+			if !objects.IsTruncated {
+				break
+			}
+			marker = objects.NextMarker
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,304 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Tests parsing and validating lifecycle configuration.
+func TestParseLifecycle(t *testing.T) {
+	rule := func(body string) string {
+		return "<LifecycleConfiguration><Rule>" + body + "</Rule></LifecycleConfiguration>"
+	}
+	transition := "<Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition>"
+	testCases := []struct {
+		data           string
+		expectedErr    APIErrorCode
+		expectedPrefix string
+	}{
+		// Legacy prefix.
+		{rule("<ID>logs</ID><Prefix>logs/</Prefix><Status>Enabled</Status>" + transition), ErrNone, "logs/"},
+		// Filter prefix.
+		{rule("<Filter><Prefix>logs/</Prefix></Filter><Status>Disabled</Status>" + transition), ErrNone, "logs/"},
+		// All objects.
+		{rule("<Status>Enabled</Status>" + transition), ErrNone, ""},
+		// Malformed XML.
+		{"<LifecycleConfiguration>", ErrMalformedXML, ""},
+		// No rules.
+		{"<LifecycleConfiguration></LifecycleConfiguration>", ErrInvalidLifecycle, ""},
+		// Invalid status.
+		{rule("<Status>On</Status>" + transition), ErrInvalidLifecycle, ""},
+		// Missing transition and expiration.
+		{rule("<Status>Enabled</Status>"), ErrInvalidLifecycle, ""},
+		// Invalid days.
+		{rule("<Status>Enabled</Status><Transition><Days>0</Days><StorageClass>GLACIER</StorageClass></Transition>"), ErrInvalidLifecycle, ""},
+		// Missing or standard storage class.
+		{rule("<Status>Enabled</Status><Transition><Days>30</Days></Transition>"), ErrInvalidLifecycle, ""},
+		{rule("<Status>Enabled</Status><Transition><Days>30</Days><StorageClass>STANDARD</StorageClass></Transition>"), ErrInvalidLifecycle, ""},
+		// Expiration along with or without transition.
+		{rule("<Status>Enabled</Status>" + transition + "<Expiration><Days>60</Days></Expiration>"), ErrNone, ""},
+		{rule("<Prefix>tmp/</Prefix><Status>Enabled</Status><Expiration><Days>1</Days></Expiration>"), ErrNone, "tmp/"},
+		// Invalid expiration days.
+		{rule("<Status>Enabled</Status><Expiration><Days>0</Days></Expiration>"), ErrInvalidLifecycle, ""},
+		// Duplicate rule IDs.
+		{"<LifecycleConfiguration><Rule><ID>a</ID><Status>Enabled</Status>" + transition + "</Rule>" +
+			"<Rule><ID>a</ID><Status>Enabled</Status>" + transition + "</Rule></LifecycleConfiguration>", ErrInvalidLifecycle, ""},
+	}
+
+	for i, testCase := range testCases {
+		lc, s3Error := parseLifecycle([]byte(testCase.data))
+		if s3Error != testCase.expectedErr {
+			t.Errorf("Test %d: expected error %d, got %d", i+1, testCase.expectedErr, s3Error)
+			continue
+		}
+		if s3Error == ErrNone && lc.Rules[0].getPrefix() != testCase.expectedPrefix {
+			t.Errorf("Test %d: expected prefix %s, got %s", i+1, testCase.expectedPrefix, lc.Rules[0].getPrefix())
+		}
+	}
+}
+
+// Wrapper for calling lifecycle transitioner tests for both XL and FS.
+func TestLifecycleTransitioner(t *testing.T) {
+	ExecObjectLayerTest(t, testLifecycleTransitioner)
+}
+
+func testLifecycleTransitioner(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := []byte("hello, world")
+	objects := []string{"logs/a", "logs/b", "data/c"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	if _, err := loadBucketLifecycle(bucket, obj); err != errNoSuchLifecycleConfiguration {
+		t.Fatalf("%s: expected errNoSuchLifecycleConfiguration, got %v", instanceType, err)
+	}
+	lc, s3Error := parseLifecycle([]byte("<LifecycleConfiguration><Rule><Prefix>logs/</Prefix><Status>Enabled</Status>" +
+		"<Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule>" +
+		"<Rule><Prefix>data/</Prefix><Status>Disabled</Status>" +
+		"<Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule></LifecycleConfiguration>"))
+	if s3Error != ErrNone {
+		t.Fatalf("%s: unexpected error %d", instanceType, s3Error)
+	}
+	if err := persistBucketLifecycle(bucket, lc, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if loaded, err := loadBucketLifecycle(bucket, obj); err != nil || len(loaded.Rules) != 2 {
+		t.Fatalf("%s: unexpected lifecycle %v, %v", instanceType, loaded, err)
+	}
+
+	tier := newMemTier()
+	transitioner := newLifecycleTransitioner(func() ObjectLayer { return obj },
+		func() remoteTier { return tier }, time.Hour)
+
+	// Objects are not old enough yet.
+	transitioner.transition(UTCNow())
+	if len(tier.objects) != 0 {
+		t.Fatalf("%s: expected no transitions, got %d", instanceType, len(tier.objects))
+	}
+
+	transitioner.transition(UTCNow().Add(48 * time.Hour))
+	for _, object := range objects {
+		objInfo, err := obj.GetObjectInfo(bucket, object)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		_, inTier := tier.get(getTransitionKey(bucket, object))
+		// Only objects of the enabled rule are transitioned.
+		expected := object != "data/c"
+		if isObjectTransitioned(objInfo) != expected || inTier != expected {
+			t.Fatalf("%s: %s expected transitioned %v, got %v", instanceType, object, expected, isObjectTransitioned(objInfo))
+		}
+	}
+
+	if err := removeBucketLifecycle(bucket, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, err := loadBucketLifecycle(bucket, obj); err != errNoSuchLifecycleConfiguration {
+		t.Fatalf("%s: expected errNoSuchLifecycleConfiguration, got %v", instanceType, err)
+	}
+}
+
+// Wrapper for calling lifecycle dry-run tests for both XL and FS.
+func TestDryRunLifecycle(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testDryRunLifecycle)
+}
+
+func testDryRunLifecycle(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := []byte("hello, world")
+	for _, object := range []string{"logs/a", "logs/b", "data/c"} {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	// Empty objects have nothing to transition.
+	if _, err := obj.PutObject(bucket, "logs/empty", 0, bytes.NewReader(nil), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	lc, s3Error := parseLifecycle([]byte("<LifecycleConfiguration><Rule><ID>logs</ID><Prefix>logs/</Prefix><Status>Disabled</Status>" +
+		"<Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule>" +
+		"<Rule><ID>all</ID><Status>Enabled</Status>" +
+		"<Transition><Days>3</Days><StorageClass>DEEP</StorageClass></Transition></Rule></LifecycleConfiguration>"))
+	if s3Error != ErrNone {
+		t.Fatalf("%s: unexpected error %d", instanceType, s3Error)
+	}
+
+	testCases := []struct {
+		now          time.Time
+		ruleObjects  []int64
+		transitions  []string
+		storageClass []string
+	}{
+		// Objects are not old enough yet.
+		{UTCNow(), []int64{0, 0}, nil, nil},
+		// Only objects of the first rule are old enough.
+		{UTCNow().Add(48 * time.Hour), []int64{2, 0}, []string{"logs/a", "logs/b"}, []string{"GLACIER", "GLACIER"}},
+		// Objects selected by both rules are reported under the first.
+		{UTCNow().Add(96 * time.Hour), []int64{2, 1}, []string{"logs/a", "logs/b", "data/c"}, []string{"GLACIER", "GLACIER", "DEEP"}},
+	}
+
+	for i, testCase := range testCases {
+		result, err := dryRunLifecycle(obj, bucket, lc, testCase.now)
+		if err != nil {
+			t.Fatalf("%s: Test %d: %s", instanceType, i+1, err)
+		}
+		if result.IsTruncated || len(result.Rules) != 2 || len(result.Transitions) != len(testCase.transitions) {
+			t.Fatalf("%s: Test %d: unexpected result %v", instanceType, i+1, result)
+		}
+		for j, rule := range result.Rules {
+			if rule.Objects != testCase.ruleObjects[j] || rule.Size != testCase.ruleObjects[j]*int64(len(data)) {
+				t.Errorf("%s: Test %d: expected %d objects for rule %s, got %d", instanceType, i+1, testCase.ruleObjects[j], rule.ID, rule.Objects)
+			}
+		}
+		for j, object := range result.Transitions {
+			if object.Name != testCase.transitions[j] || object.StorageClass != testCase.storageClass[j] {
+				t.Errorf("%s: Test %d: expected %s in %s, got %s in %s", instanceType, i+1,
+					testCase.transitions[j], testCase.storageClass[j], object.Name, object.StorageClass)
+			}
+		}
+	}
+
+	// Nothing is transitioned.
+	objInfo, err := obj.GetObjectInfo(bucket, "logs/a")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if isObjectTransitioned(objInfo) {
+		t.Fatalf("%s: expected object not to be transitioned by a dry-run", instanceType)
+	}
+}
+
+// Tests expiry dates are rounded up to the next midnight UTC.
+func TestLifecycleExpiryDate(t *testing.T) {
+	rule := lifecycleRule{Expiration: &lifecycleExpiration{Days: 2}}
+	testCases := []struct {
+		modTime  time.Time
+		expected time.Time
+	}{
+		// Test 1: during the day.
+		{time.Date(2017, 3, 1, 13, 45, 0, 0, time.UTC), time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC)},
+		// Test 2: at midnight.
+		{time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC)},
+		// Test 3: non UTC time.
+		{time.Date(2017, 3, 1, 23, 0, 0, 0, time.FixedZone("UTC-2", -2*3600)), time.Date(2017, 3, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, testCase := range testCases {
+		if expiry := rule.getExpiryDate(testCase.modTime); !expiry.Equal(testCase.expected) {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, expiry)
+		}
+	}
+}
+
+// Wrapper for calling lifecycle expiration tests for both XL and FS.
+func TestLifecycleExpiration(t *testing.T) {
+	ExecObjectLayerTest(t, testLifecycleExpiration)
+}
+
+func testLifecycleExpiration(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := []byte("hello, world")
+	objects := []string{"tmp/a", "tmp/b", "data/c"}
+	for _, object := range objects {
+		if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	lc, s3Error := parseLifecycle([]byte("<LifecycleConfiguration><Rule><ID>tmp</ID><Prefix>tmp/</Prefix><Status>Enabled</Status>" +
+		"<Expiration><Days>1</Days></Expiration></Rule>" +
+		"<Rule><ID>data</ID><Prefix>data/</Prefix><Status>Disabled</Status>" +
+		"<Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>"))
+	if s3Error != ErrNone {
+		t.Fatalf("%s: unexpected error %d", instanceType, s3Error)
+	}
+	if err := persistBucketLifecycle(bucket, lc, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	objInfo, err := obj.GetObjectInfo(bucket, "tmp/a")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	rule, expiry, ok := getObjectExpiration(obj, bucket, objInfo)
+	if !ok || rule.ID != "tmp" || !expiry.Equal(rule.getExpiryDate(objInfo.ModTime)) {
+		t.Fatalf("%s: unexpected expiration %v, %s, %v", instanceType, ok, rule.ID, expiry)
+	}
+	if _, _, ok = getObjectExpiration(obj, bucket, ObjectInfo{Name: "data/c", ModTime: objInfo.ModTime}); ok {
+		t.Fatalf("%s: expected no expiration for objects of a disabled rule", instanceType)
+	}
+
+	// Expirations run without a remote tier.
+	transitioner := newLifecycleTransitioner(func() ObjectLayer { return obj },
+		func() remoteTier { return nil }, time.Hour)
+
+	// Objects have not expired yet.
+	transitioner.transition(UTCNow())
+	for _, object := range objects {
+		if _, err = obj.GetObjectInfo(bucket, object); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	transitioner.transition(UTCNow().Add(72 * time.Hour))
+	for _, object := range objects {
+		_, err = obj.GetObjectInfo(bucket, object)
+		// Only objects of the enabled rule are expired.
+		if expected := object != "data/c"; isErrObjectNotFound(err) != expected {
+			t.Fatalf("%s: %s expected expired %v, got %v", instanceType, object, expected, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "fmt"
+
+// listConfig - ListObjects limits, zero values use the S3 default
+// of 1000 keys per call and don't cap directory reads.
+type listConfig struct {
+	// Maximum number of keys returned per ListObjects call, also
+	// used when max-keys is not set. Can only be lowered from 1000.
+	MaxKeys int `json:"maxKeys"`
+	// Maximum number of directories read at once by all listings
+	// of the FS backend, further reads wait for a free slot.
+	MaxConcurrentDirReads int `json:"maxConcurrentDirReads"`
+}
+
+// Validate - validates list config.
+func (l listConfig) Validate() error {
+	if l.MaxKeys < 0 || l.MaxKeys > maxObjectList {
+		return fmt.Errorf("Invalid list maxKeys value ‘%d’, must be between 0 and %d", l.MaxKeys, maxObjectList)
+	}
+	if l.MaxConcurrentDirReads < 0 {
+		return fmt.Errorf("Invalid list maxConcurrentDirReads value ‘%d’, must not be negative", l.MaxConcurrentDirReads)
+	}
+	return nil
+}
+
+// getListMaxKeys - returns configured maximum number of keys per
+// ListObjects call.
+func getListMaxKeys() int {
+	if serverConfig != nil {
+		if maxKeys := serverConfig.GetList().MaxKeys; maxKeys > 0 {
+			return maxKeys
+		}
+	}
+	return maxObjectList
+}
+
+// getListMaxConcurrentDirReads - returns configured maximum number of
+// directories read at once, 0 if unlimited.
+func getListMaxConcurrentDirReads() int {
+	if serverConfig != nil {
+		return serverConfig.GetList().MaxConcurrentDirReads
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests validating list config.
+func TestListConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config     listConfig
+		shouldPass bool
+	}{
+		{listConfig{}, true},
+		{listConfig{MaxKeys: 100, MaxConcurrentDirReads: 16}, true},
+		{listConfig{MaxKeys: maxObjectList}, true},
+		{listConfig{MaxKeys: -1}, false},
+		{listConfig{MaxKeys: maxObjectList + 1}, false},
+		{listConfig{MaxConcurrentDirReads: -1}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// Tests configured max keys limit ListObjects calls.
+func TestListConfigMaxKeys(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	serverConfig.List = listConfig{MaxKeys: 100}
+	testCases := []struct {
+		values  url.Values
+		maxKeys int
+	}{
+		{url.Values{}, 100},
+		{url.Values{"max-keys": []string{"10"}}, 10},
+		{url.Values{"max-keys": []string{"100"}}, 100},
+		{url.Values{"max-keys": []string{"5000"}}, 100},
+		// Negative values are rejected by validateListObjectsArgs.
+		{url.Values{"max-keys": []string{"-1"}}, -1},
+	}
+	for i, testCase := range testCases {
+		if _, _, _, maxKeys, _ := getListObjectsV1Args(testCase.values); maxKeys != testCase.maxKeys {
+			t.Errorf("Test %d: Expected %d max keys for V1, got %d", i+1, testCase.maxKeys, maxKeys)
+		}
+		if _, _, _, _, _, maxKeys, _ := getListObjectsV2Args(testCase.values); maxKeys != testCase.maxKeys {
+			t.Errorf("Test %d: Expected %d max keys for V2, got %d", i+1, testCase.maxKeys, maxKeys)
+		}
+	}
+}
+
+// Tests directory reads of FS listings wait for a free slot.
+func TestListConfigMaxConcurrentDirReads(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	serverConfig.List = listConfig{MaxConcurrentDirReads: 1}
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	fs := obj.(*fsObjects)
+	if cap(fs.listDirSem) != 1 {
+		t.Fatalf("Expected 1 concurrent directory read, got %d", cap(fs.listDirSem))
+	}
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the only slot, listing waits until it is released.
+	fs.listDirSem <- struct{}{}
+	doneCh := make(chan error, 1)
+	go func() {
+		_, lerr := obj.ListObjects("bucket", "", "", "", 10)
+		doneCh <- lerr
+	}()
+	select {
+	case <-doneCh:
+		t.Fatal("Expected listing to wait for a free directory read slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+	<-fs.listDirSem
+	if err = <-doneCh; err != nil {
+		t.Fatal(err)
+	}
+}
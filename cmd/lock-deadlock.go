@@ -0,0 +1,189 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval between two runs of deadlock detection.
+const defaultDeadlockCheckInterval = 30 * time.Second
+
+// DeadlockOpsInfo - an operation in a suspected deadlock chain, it is
+// blocked on the lock of Bucket, Object held by the next operation in
+// the chain, the last operation waits on the first one.
+type DeadlockOpsInfo struct {
+	Bucket      string   `json:"bucket"`
+	Object      string   `json:"object"`
+	OperationID string   `json:"id"`
+	RequestID   string   `json:"requestID,omitempty"`
+	LockSource  string   `json:"source"`
+	LockType    lockType `json:"type"`
+}
+
+// deadlockError - represents a suspected deadlock chain.
+type deadlockError []DeadlockOpsInfo
+
+func (d deadlockError) Error() string {
+	chain := make([]string, len(d))
+	for i, ops := range d {
+		chain[i] = fmt.Sprintf("%s on <volume> %s, <path> %s at \"%s\"", ops.LockType, ops.Bucket, ops.Object, ops.LockSource)
+	}
+	return "Suspected deadlock, operations waiting on each other: " + strings.Join(chain, " -> ")
+}
+
+// getGoroutineID - returns ID of the calling goroutine, parsed from
+// the first line of its stack trace "goroutine <id> [running]:".
+func getGoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+	id, err := strconv.ParseUint(string(buf), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// waitsOn - returns true if blocked lock request waiter has to wait
+// for lock holder on the same (volume, path) to be released. Blocked
+// read locks also wait on write locks requested before them, as a
+// pending write lock blocks new readers.
+func waitsOn(waiter, holder debugLockInfo) bool {
+	if holder.status == runningStatus {
+		return waiter.lType == debugWLockStr || holder.lType == debugWLockStr
+	}
+	return waiter.lType == debugRLockStr && holder.lType == debugWLockStr &&
+		holder.since.Before(waiter.since)
+}
+
+// findDeadlocks - builds the wait graph of goroutines blocked on
+// namespace locks and returns all cycles found in it. Only works when
+// lock debugging is enabled since goroutine IDs are needed to relate
+// held and blocked locks of the same operation.
+func (n *nsLockMap) findDeadlocks() [][]DeadlockOpsInfo {
+	// All shards are locked together, in order, for a consistent wait
+	// graph. Other users of the shards lock one shard at a time.
+	for _, shard := range n.debugLockShards {
+		shard.mutex.Lock()
+	}
+	defer func() {
+		for _, shard := range n.debugLockShards {
+			shard.mutex.Unlock()
+		}
+	}()
+
+	// A goroutine can be blocked on at most one lock at a time.
+	blocked := make(map[uint64]DeadlockOpsInfo)
+	// Goroutines each blocked goroutine is waiting on.
+	waitGraph := make(map[uint64][]uint64)
+	for _, shard := range n.debugLockShards {
+		for param, debugLock := range shard.lockInfo {
+			for opsID, waiter := range debugLock.lockInfo {
+				if waiter.status != blockedStatus || waiter.goroutineID == 0 {
+					continue
+				}
+				blocked[waiter.goroutineID] = DeadlockOpsInfo{
+					Bucket:      param.volume,
+					Object:      param.path,
+					OperationID: opsID,
+					RequestID:   waiter.requestID,
+					LockSource:  waiter.lockSource,
+					LockType:    waiter.lType,
+				}
+				for holderOpsID, holder := range debugLock.lockInfo {
+					if holderOpsID == opsID || holder.goroutineID == 0 || !waitsOn(waiter, holder) {
+						continue
+					}
+					waitGraph[waiter.goroutineID] = append(waitGraph[waiter.goroutineID], holder.goroutineID)
+				}
+			}
+		}
+	}
+
+	// Depth first search for cycles, goroutines on the current path
+	// are grey and fully explored goroutines are black.
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make(map[uint64]int)
+	var path []uint64
+	var deadlocks [][]DeadlockOpsInfo
+
+	var visit func(g uint64)
+	visit = func(g uint64) {
+		color[g] = grey
+		path = append(path, g)
+		for _, next := range waitGraph[g] {
+			switch color[next] {
+			case white:
+				visit(next)
+			case grey:
+				// Back edge, goroutines from next till the end
+				// of the current path form a cycle.
+				var chain []DeadlockOpsInfo
+				for i := len(path) - 1; i >= 0; i-- {
+					chain = append([]DeadlockOpsInfo{blocked[path[i]]}, chain...)
+					if path[i] == next {
+						break
+					}
+				}
+				deadlocks = append(deadlocks, chain)
+			}
+		}
+		path = path[:len(path)-1]
+		color[g] = black
+	}
+	for g := range waitGraph {
+		if color[g] == white {
+			visit(g)
+		}
+	}
+	return deadlocks
+}
+
+// watchDeadlocks - periodically runs deadlock detection, logs suspected
+// deadlock chains and saves them for the debug locks endpoint, runs
+// until doneCh is closed.
+func (n *nsLockMap) watchDeadlocks(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deadlocks := n.findDeadlocks()
+			for _, chain := range deadlocks {
+				errorIf(deadlockError(chain), "Deadlock detection found a suspected deadlock")
+			}
+			n.deadlocksMutex.Lock()
+			n.deadlocks = deadlocks
+			n.deadlocksMutex.Unlock()
+		case <-doneCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests goroutine ID of the caller is parsed.
+func TestGetGoroutineID(t *testing.T) {
+	id := getGoroutineID()
+	if id == 0 {
+		t.Fatal("Expected non-zero goroutine ID")
+	}
+	if id != getGoroutineID() {
+		t.Fatal("Expected same goroutine ID for the same goroutine")
+	}
+
+	idCh := make(chan uint64)
+	go func() { idCh <- getGoroutineID() }()
+	if otherID := <-idCh; otherID == id || otherID == 0 {
+		t.Fatalf("Expected a different goroutine ID, got %d", otherID)
+	}
+}
+
+// Tests detection of cycles in the lock wait graph.
+func TestFindDeadlocks(t *testing.T) {
+	now := UTCNow()
+	lockInfo := func(lType lockType, status statusType, goroutineID uint64, since time.Time) debugLockInfo {
+		return debugLockInfo{
+			lType:       lType,
+			lockSource:  "[test]",
+			status:      status,
+			since:       since,
+			goroutineID: goroutineID,
+		}
+	}
+	setLocks := func(locks map[nsParam]map[string]debugLockInfo) {
+		initNSLock(false)
+		for param, lockInfo := range locks {
+			volumePathLocks := newDebugLockInfoPerVolumePath()
+			volumePathLocks.lockInfo = lockInfo
+			setDebugLockInfo(param, volumePathLocks)
+		}
+	}
+	objA, objB := nsParam{"bucket", "a"}, nsParam{"bucket", "b"}
+
+	testCases := []struct {
+		locks          map[nsParam]map[string]debugLockInfo
+		deadlockChains int
+		chainLength    int
+	}{
+		// No locks.
+		{map[nsParam]map[string]debugLockInfo{}, 0, 0},
+		// Goroutine 2 waits on goroutine 1, no cycle.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugWLockStr, runningStatus, 1, now),
+				"op2": lockInfo(debugWLockStr, blockedStatus, 2, now),
+			},
+		}, 0, 0},
+		// Concurrent read locks don't wait on each other.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugRLockStr, runningStatus, 1, now),
+				"op2": lockInfo(debugRLockStr, blockedStatus, 2, now),
+			},
+			objB: {
+				"op3": lockInfo(debugRLockStr, runningStatus, 2, now),
+				"op4": lockInfo(debugRLockStr, blockedStatus, 1, now),
+			},
+		}, 0, 0},
+		// Goroutines 1 and 2 hold a and b, each waits on the other.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugWLockStr, runningStatus, 1, now),
+				"op2": lockInfo(debugWLockStr, blockedStatus, 2, now),
+			},
+			objB: {
+				"op3": lockInfo(debugWLockStr, runningStatus, 2, now),
+				"op4": lockInfo(debugRLockStr, blockedStatus, 1, now),
+			},
+		}, 1, 2},
+		// Goroutine 1 waits to re-acquire a write lock it holds.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugWLockStr, runningStatus, 1, now),
+				"op2": lockInfo(debugWLockStr, blockedStatus, 1, now),
+			},
+		}, 1, 1},
+		// Goroutine 1 holds a read lock and waits on a read lock
+		// queued behind a pending write lock of goroutine 2 which
+		// waits on goroutine 1.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugRLockStr, runningStatus, 1, now),
+				"op2": lockInfo(debugWLockStr, blockedStatus, 2, now),
+				"op3": lockInfo(debugRLockStr, blockedStatus, 1, now.Add(time.Second)),
+			},
+		}, 1, 2},
+		// Lock debugging was disabled, goroutine IDs are not known.
+		{map[nsParam]map[string]debugLockInfo{
+			objA: {
+				"op1": lockInfo(debugWLockStr, runningStatus, 0, now),
+				"op2": lockInfo(debugWLockStr, blockedStatus, 0, now),
+			},
+		}, 0, 0},
+	}
+
+	for i, testCase := range testCases {
+		setLocks(testCase.locks)
+		deadlocks := globalNSMutex.findDeadlocks()
+		if len(deadlocks) != testCase.deadlockChains {
+			t.Fatalf("Test %d: expected %d deadlocks, got %d", i+1, testCase.deadlockChains, len(deadlocks))
+		}
+		for _, chain := range deadlocks {
+			if len(chain) != testCase.chainLength {
+				t.Fatalf("Test %d: expected chain of length %d, got %v", i+1, testCase.chainLength, chain)
+			}
+		}
+	}
+}
+
+// Tests the debug locks endpoint.
+func TestDebugLocksHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	initNSLock(false)
+	globalNSMutex.deadlocks = [][]DeadlockOpsInfo{{{Bucket: "bucket", Object: "a", LockType: debugWLockStr}}}
+	globalNSMutex.Lock("bucket", "object", "op1")
+	defer globalNSMutex.Unlock("bucket", "object", "op1")
+
+	mux := router.NewRouter()
+	registerDebugRouter(mux)
+
+	// Unsigned request is rejected.
+	req, err := http.NewRequest("GET", debugLocksPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	cred := serverConfig.GetCredential()
+	req, err = newTestSignedRequestV4("GET", debugLocksPath, 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var info debugLocksInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.LockState.TotalAcquiredLocks != 1 || len(info.Deadlocks) != 1 {
+		t.Fatalf("Unexpected debug locks info %#v", info)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockFairness - policy by which waiters of a namespace lock are
+// admitted to acquire it, set through MINIO_LOCK_FAIRNESS.
+type lockFairness string
+
+const (
+	// Waiters race for the lock, writers may starve under
+	// sustained reads on distributed locks.
+	lockFairnessNone lockFairness = "none"
+	// New read locks wait while a write lock is waiting.
+	lockFairnessWriter lockFairness = "writer"
+	// Waiters acquire the lock in their order of arrival.
+	lockFairnessFIFO lockFairness = "fifo"
+)
+
+// parseLockFairness - parses a lock fairness policy, case insensitive.
+func parseLockFairness(s string) (lockFairness, error) {
+	switch fairness := lockFairness(strings.ToLower(s)); fairness {
+	case lockFairnessNone, lockFairnessWriter, lockFairnessFIFO:
+		return fairness, nil
+	}
+	return "", fmt.Errorf("Unknown lock fairness ‘%s’", s)
+}
+
+// lockWaiter - an operation waiting in the queue of a namespace lock.
+type lockWaiter struct {
+	readLock bool
+}
+
+// lockQueue - operations waiting for a namespace lock, admitted to
+// acquire it by the fairness policy. An operation leaves the queue
+// once it acquired the lock, so that read locks admitted one after
+// another are still held concurrently.
+type lockQueue struct {
+	mutex   sync.Mutex
+	waiters []*lockWaiter
+	// Number of write locks in waiters.
+	writers int
+	// Closed and replaced whenever a waiter leaves the queue.
+	changed chan struct{}
+}
+
+// isAdmitted - returns if the waiter may acquire the lock, must be
+// called with mutex held.
+func (q *lockQueue) isAdmitted(fairness lockFairness, w *lockWaiter) bool {
+	switch fairness {
+	case lockFairnessWriter:
+		return !w.readLock || q.writers == 0
+	case lockFairnessFIFO:
+		return q.waiters[0] == w
+	}
+	return true
+}
+
+// enter - queues an operation and blocks until it is admitted or the
+// deadline passes, a zero deadline waits forever. Returns the waiter
+// to leave the queue with once the lock is acquired, nil when not
+// admitted before the deadline.
+func (q *lockQueue) enter(fairness lockFairness, readLock bool, deadline time.Time) *lockWaiter {
+	w := &lockWaiter{readLock}
+
+	q.mutex.Lock()
+	q.waiters = append(q.waiters, w)
+	if !readLock {
+		q.writers++
+	}
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(deadline.Sub(UTCNow()))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for !q.isAdmitted(fairness, w) {
+		if q.changed == nil {
+			q.changed = make(chan struct{})
+		}
+		changed := q.changed
+		q.mutex.Unlock()
+
+		select {
+		case <-changed:
+			q.mutex.Lock()
+		case <-timeout:
+			q.mutex.Lock()
+			if !q.isAdmitted(fairness, w) {
+				q.remove(w)
+				q.mutex.Unlock()
+				return nil
+			}
+		}
+	}
+	q.mutex.Unlock()
+	return w
+}
+
+// leave - removes an admitted operation from the queue.
+func (q *lockQueue) leave(w *lockWaiter) {
+	q.mutex.Lock()
+	q.remove(w)
+	q.mutex.Unlock()
+}
+
+// remove - removes the waiter and wakes up the others, must be called
+// with mutex held.
+func (q *lockQueue) remove(w *lockWaiter) {
+	for i, waiter := range q.waiters {
+		if waiter == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			break
+		}
+	}
+	if !w.readLock {
+		q.writers--
+	}
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests parsing of lock fairness policies.
+func TestParseLockFairness(t *testing.T) {
+	testCases := []struct {
+		value        string
+		fairness     lockFairness
+		expectedFail bool
+	}{
+		// Test 1 - no fairness.
+		{"none", lockFairnessNone, false},
+		// Test 2 - writer preference, case insensitive.
+		{"Writer", lockFairnessWriter, false},
+		// Test 3 - FIFO ticketing.
+		{"fifo", lockFairnessFIFO, false},
+		// Test 4 - unknown policy.
+		{"reader", "", true},
+	}
+
+	for i, testCase := range testCases {
+		fairness, err := parseLockFairness(testCase.value)
+		if testCase.expectedFail {
+			if err == nil {
+				t.Errorf("Test %d: Expected to fail", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if fairness != testCase.fairness {
+			t.Errorf("Test %d: Expected fairness %s, got %s", i+1, testCase.fairness, fairness)
+		}
+	}
+}
+
+// Tests admission of waiters by the fairness policies.
+func TestLockQueue(t *testing.T) {
+	testCases := []struct {
+		fairness lockFairness
+		// Waiters queued first, all of them are admitted
+		// but don't leave the queue.
+		queued []bool
+		// Read lock of the waiter queued last.
+		readLock bool
+		admitted bool
+	}{
+		// Test 1 - read lock waits for a queued write lock.
+		{lockFairnessWriter, []bool{false}, true, false},
+		// Test 2 - write lock is admitted before queued read locks.
+		{lockFairnessWriter, []bool{true, true}, false, true},
+		// Test 3 - read locks are admitted together.
+		{lockFairnessWriter, []bool{true}, true, true},
+		// Test 4 - read lock waits for an earlier read lock.
+		{lockFairnessFIFO, []bool{true}, true, false},
+		// Test 5 - write lock waits for an earlier write lock.
+		{lockFairnessFIFO, []bool{false}, false, false},
+		// Test 6 - first waiter is admitted.
+		{lockFairnessFIFO, nil, false, true},
+	}
+
+	for i, testCase := range testCases {
+		q := &lockQueue{}
+		var waiters []*lockWaiter
+		for _, readLock := range testCase.queued {
+			waiter := q.enter(testCase.fairness, readLock, time.Time{})
+			if waiter == nil {
+				t.Fatalf("Test %d: Expected queued waiter to be admitted", i+1)
+			}
+			waiters = append(waiters, waiter)
+		}
+
+		deadline := UTCNow().Add(10 * time.Millisecond)
+		waiter := q.enter(testCase.fairness, testCase.readLock, deadline)
+		if (waiter != nil) != testCase.admitted {
+			t.Errorf("Test %d: Expected admitted %t, got %t", i+1, testCase.admitted, waiter != nil)
+		}
+		if waiter != nil {
+			q.leave(waiter)
+		}
+		if len(q.waiters) != len(waiters) {
+			t.Errorf("Test %d: Expected %d waiters left in queue, got %d", i+1, len(waiters), len(q.waiters))
+		}
+
+		// Waiter is admitted once the queued ones leave.
+		for _, waiter = range waiters {
+			q.leave(waiter)
+		}
+		if waiter = q.enter(testCase.fairness, testCase.readLock, deadline); waiter == nil {
+			t.Errorf("Test %d: Expected waiter to be admitted by empty queue", i+1)
+		}
+	}
+}
+
+// readPreferringMutex - read/write mutex granting read locks while a
+// write lock waits, like distributed locks, so that writers starve
+// under sustained reads.
+type readPreferringMutex struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	readers int
+	writer  bool
+}
+
+func newReadPreferringMutex() *readPreferringMutex {
+	m := &readPreferringMutex{}
+	m.cond = sync.NewCond(&m.mutex)
+	return m
+}
+
+func (m *readPreferringMutex) Lock() {
+	m.mutex.Lock()
+	for m.writer || m.readers > 0 {
+		m.cond.Wait()
+	}
+	m.writer = true
+	m.mutex.Unlock()
+}
+
+func (m *readPreferringMutex) Unlock() {
+	m.mutex.Lock()
+	m.writer = false
+	m.cond.Broadcast()
+	m.mutex.Unlock()
+}
+
+func (m *readPreferringMutex) RLock() {
+	m.mutex.Lock()
+	for m.writer {
+		m.cond.Wait()
+	}
+	m.readers++
+	m.mutex.Unlock()
+}
+
+func (m *readPreferringMutex) RUnlock() {
+	m.mutex.Lock()
+	m.readers--
+	m.cond.Broadcast()
+	m.mutex.Unlock()
+}
+
+// Tests that the wait of a write lock is bounded under sustained
+// overlapping reads with fairness enabled.
+func TestLockFairnessWriterWait(t *testing.T) {
+	const maxWriterWait = 5 * time.Second
+
+	for i, fairness := range []lockFairness{lockFairnessWriter, lockFairnessFIFO} {
+		n := newNSLockMap(false)
+		n.fairness = fairness
+
+		// Back the lock by a read preferring mutex, pinned in
+		// the map by an extra reference.
+		n.lockMap[nsParam{"bucket", "object"}] = &nsLock{rwMutex: newReadPreferringMutex(), ref: 1}
+
+		// Readers holding read locks in turns, at least one of
+		// them holds a read lock at any time without fairness.
+		stopCh := make(chan struct{})
+		var wg sync.WaitGroup
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stopCh:
+						return
+					default:
+					}
+					lk := n.NewNSLock("bucket", "object")
+					lk.RLock()
+					time.Sleep(2 * time.Millisecond)
+					lk.RUnlock()
+				}
+			}()
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		start := UTCNow()
+		lockedCh := make(chan struct{})
+		go func() {
+			lk := n.NewNSLock("bucket", "object")
+			lk.Lock()
+			close(lockedCh)
+			lk.Unlock()
+		}()
+
+		select {
+		case <-lockedCh:
+		case <-time.After(maxWriterWait):
+			t.Errorf("Test %d: Write lock not acquired within %s under sustained reads with %s fairness", i+1, maxWriterWait, fairness)
+		}
+		close(stopCh)
+		wg.Wait()
+		if t.Failed() {
+			// Let the starved writer through.
+			<-lockedCh
+		}
+		t.Logf("Test %d: Write lock acquired after %s with %s fairness", i+1, UTCNow().Sub(start), fairness)
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"crypto/rand"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -45,8 +46,16 @@ type debugLockInfo struct {
 	lockSource string
 	// Status can be running/blocked.
 	status statusType
+	// ID of the client request which took the lock, empty for
+	// locks not taken on behalf of a request.
+	requestID string
 	// Time of last status update.
 	since time.Time
+	// Set once the lock watchdog has reported this lock as stale.
+	stale bool
+	// Goroutine which called (r)lock, only set when lock debugging
+	// is enabled, used to build the wait graph for deadlock detection.
+	goroutineID uint64
 }
 
 // debugLockInfoPerVolumePath - lock state information on all locks held on (volume, path).
@@ -100,9 +109,58 @@ func (l LockInfoStateNotBlocked) Error() string {
 	return fmt.Sprintf("Lock state should be \"Blocked\" for <volume> %s, <path> %s, <opsID> %s", l.volume, l.path, l.opsID)
 }
 
-// Initialize lock info for given (volume, path).
-func (n *nsLockMap) initLockInfoForVolumePath(param nsParam) {
-	n.debugLockMap[param] = &debugLockInfoPerVolumePath{
+// Number of shards of the lock instrumentation, instrumentation of
+// (volume, path) pairs in different shards is updated concurrently.
+const debugLockShardCount = 64
+
+// debugLockShard - lock state of all (volume, path) pairs hashed to
+// the shard. counters sum up the counters of these pairs, they are
+// updated atomically and aggregated lazily by getLockStat without
+// holding mutex.
+type debugLockShard struct {
+	counters lockStat
+	mutex    sync.Mutex
+	lockInfo map[nsParam]*debugLockInfoPerVolumePath
+}
+
+// newDebugLockShards - returns empty shards of lock instrumentation.
+func newDebugLockShards() (shards [debugLockShardCount]*debugLockShard) {
+	for i := range shards {
+		// Allocated separately, so that counters are 64-bit aligned
+		// for atomic updates on 32-bit platforms.
+		shards[i] = &debugLockShard{
+			lockInfo: make(map[nsParam]*debugLockInfoPerVolumePath),
+		}
+	}
+	return shards
+}
+
+// getDebugLockShard - returns the shard of lock instrumentation of
+// (volume, path), by FNV-1a hash of both.
+func (n *nsLockMap) getDebugLockShard(param nsParam) *debugLockShard {
+	hash := uint32(2166136261)
+	for _, s := range [2]string{param.volume, param.path} {
+		for i := 0; i < len(s); i++ {
+			hash ^= uint32(s[i])
+			hash *= 16777619
+		}
+	}
+	return n.debugLockShards[hash%debugLockShardCount]
+}
+
+// getLockStat - returns lock counts of all (volume, path) pairs,
+// summed up from all shards without locking them.
+func (n *nsLockMap) getLockStat() (stat lockStat) {
+	for _, shard := range n.debugLockShards {
+		stat.add(shard.counters.load())
+	}
+	return stat
+}
+
+// newDebugLockInfoPerVolumePath - returns empty lock info of a
+// (volume, path) pair.
+func newDebugLockInfoPerVolumePath() *debugLockInfoPerVolumePath {
+	return &debugLockInfoPerVolumePath{
 		lockInfo: make(map[string]debugLockInfo),
 		counters: &lockStat{},
 	}
@@ -110,38 +168,41 @@ func (n *nsLockMap) initLockInfoForVolumePath(param nsParam) {
 
 // Change the state of the lock from Blocked to Running.
 func (n *nsLockMap) statusBlockedToRunning(param nsParam, lockSource, opsID string, readLock bool) error {
-	// This function is called outside nsLockMap.mutex.Lock(), so must be held explicitly.
-	n.lockMapMutex.Lock()
-	defer n.lockMapMutex.Unlock()
+	lockInfo := newDebugLockInfo(lockSource, runningStatus, readLock)
+
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 
 	// Check whether the lock info entry for <volume, path> pair already exists.
-	_, ok := n.debugLockMap[param]
+	volumePathLocks, ok := shard.lockInfo[param]
 	if !ok {
 		return traceError(LockInfoVolPathMissing{param.volume, param.path})
 	}
 
 	// Check whether lock info entry for the given `opsID` exists.
-	lockInfo, ok := n.debugLockMap[param].lockInfo[opsID]
+	blockedLockInfo, ok := volumePathLocks.lockInfo[opsID]
 	if !ok {
 		return traceError(LockInfoOpsIDNotFound{param.volume, param.path, opsID})
 	}
 
 	// Check whether lockSource is same.
-	if lockInfo.lockSource != lockSource {
+	if blockedLockInfo.lockSource != lockSource {
 		return traceError(LockInfoOriginMismatch{param.volume, param.path, opsID, lockSource})
 	}
 
 	// Status of the lock should be set to "Blocked".
-	if lockInfo.status != blockedStatus {
+	if blockedLockInfo.status != blockedStatus {
 		return traceError(LockInfoStateNotBlocked{param.volume, param.path, opsID})
 	}
 	// Change lock status to running and update the time.
-	n.debugLockMap[param].lockInfo[opsID] = newDebugLockInfo(lockSource, runningStatus, readLock)
+	lockInfo.requestID = blockedLockInfo.requestID
+	volumePathLocks.lockInfo[opsID] = lockInfo
 
-	// Update global lock stats.
-	n.counters.lockGranted()
+	// Update shard lock stats.
+	shard.counters.lockGranted()
 	// Update (volume, pair) lock stats.
-	n.debugLockMap[param].counters.lockGranted()
+	volumePathLocks.counters.lockGranted()
 	return nil
 }
 
@@ -153,50 +214,67 @@ func newDebugLockInfo(lockSource string, status statusType, readLock bool) debug
 	} else {
 		lType = debugWLockStr
 	}
+	var goroutineID uint64
+	if globalIsLockDebug {
+		goroutineID = getGoroutineID()
+	}
 	return debugLockInfo{
-		lockSource: lockSource,
-		lType:      lType,
-		status:     status,
-		since:      UTCNow(),
+		lockSource:  lockSource,
+		lType:       lType,
+		status:      status,
+		since:       UTCNow(),
+		goroutineID: goroutineID,
 	}
 }
 
 // Change the state of the lock to Blocked.
-func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID string, readLock bool) error {
-	_, ok := n.debugLockMap[param]
+func (n *nsLockMap) statusNoneToBlocked(param nsParam, lockSource, opsID, requestID string, readLock bool) error {
+	lockInfo := newDebugLockInfo(lockSource, blockedStatus, readLock)
+	lockInfo.requestID = requestID
+
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	volumePathLocks, ok := shard.lockInfo[param]
 	if !ok {
 		// Lock info entry for (volume, pair) doesn't exist, initialize it.
-		n.initLockInfoForVolumePath(param)
+		volumePathLocks = newDebugLockInfoPerVolumePath()
+		shard.lockInfo[param] = volumePathLocks
 	}
 
 	// Mark lock status blocked for given opsID.
-	n.debugLockMap[param].lockInfo[opsID] = newDebugLockInfo(lockSource, blockedStatus, readLock)
-	// Update global lock stats.
-	n.counters.lockWaiting()
+	volumePathLocks.lockInfo[opsID] = lockInfo
+	// Update shard lock stats.
+	shard.counters.lockWaiting()
 	// Update (volume, path) lock stats.
-	n.debugLockMap[param].counters.lockWaiting()
+	volumePathLocks.counters.lockWaiting()
 	return nil
 }
 
 // deleteLockInfoEntry - Deletes the lock information for given (volume, path).
 // Called when nsLk.ref count is 0.
 func (n *nsLockMap) deleteLockInfoEntryForVolumePath(param nsParam) error {
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
 	// delete the lock info for the given operation.
-	if _, found := n.debugLockMap[param]; !found {
+	volumePathLocks, found := shard.lockInfo[param]
+	if !found {
 		return traceError(LockInfoVolPathMissing{param.volume, param.path})
 	}
 
 	// The following stats update is relevant only in case of a
 	// ForceUnlock. In case of the last unlock on a (volume,
 	// path), this would be a no-op.
-	volumePathLocks := n.debugLockMap[param]
 	for _, lockInfo := range volumePathLocks.lockInfo {
 		granted := lockInfo.status == runningStatus
-		// Update global and (volume, path) stats.
-		n.counters.lockRemoved(granted)
+		// Update shard and (volume, path) stats.
+		shard.counters.lockRemoved(granted)
 		volumePathLocks.counters.lockRemoved(granted)
 	}
-	delete(n.debugLockMap, param)
+	delete(shard.lockInfo, param)
 	return nil
 }
 
@@ -204,8 +282,12 @@ func (n *nsLockMap) deleteLockInfoEntryForVolumePath(param nsParam) error {
 // Called when the nsLk ref count for the given (volume, path) is
 // not 0.
 func (n *nsLockMap) deleteLockInfoEntryForOps(param nsParam, opsID string) error {
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
 	// delete the lock info for the given operation.
-	infoMap, found := n.debugLockMap[param]
+	infoMap, found := shard.lockInfo[param]
 	if !found {
 		return traceError(LockInfoVolPathMissing{param.volume, param.path})
 	}
@@ -216,14 +298,35 @@ func (n *nsLockMap) deleteLockInfoEntryForOps(param nsParam, opsID string) error
 		// Unlock request with invalid operation ID not accepted.
 		return traceError(LockInfoOpsIDNotFound{param.volume, param.path, opsID})
 	}
-	// Update global and (volume, path) lock status.
+	// Update shard and (volume, path) lock status.
 	granted := opsIDLock.status == runningStatus
-	n.counters.lockRemoved(granted)
+	shard.counters.lockRemoved(granted)
 	infoMap.counters.lockRemoved(granted)
 	delete(infoMap.lockInfo, opsID)
 	return nil
 }
 
+// deleteLockInfo - deletes lock info entry for given opsID once its
+// namespace lock is released, along with the entry for (volume, path)
+// if no other operation holds or waits for the lock. Unlike
+// deleteLockInfoEntryForVolumePath it doesn't depend on the reference
+// count of the namespace lock, so it is called without lockMapMutex
+// held and never drops entries of operations which just started
+// waiting for a new lock on the same (volume, path).
+func (n *nsLockMap) deleteLockInfo(param nsParam, opsID string) {
+	if err := n.deleteLockInfoEntryForOps(param, opsID); err != nil {
+		errorIf(err, "Failed to delete lock info entry")
+		return
+	}
+
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	if volumePathLocks, found := shard.lockInfo[param]; found && len(volumePathLocks.lockInfo) == 0 {
+		delete(shard.lockInfo, param)
+	}
+	shard.mutex.Unlock()
+}
+
 // Return randomly generated string ID
 func getOpsID() string {
 	const opsIDLen = 16
@@ -16,7 +16,12 @@
 
 package cmd
 
-import "testing"
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
 
 type lockStateCase struct {
 	volume      string
@@ -116,58 +121,41 @@ func verifyRPCLockInfoResponse(l lockStateCase, rpcLockInfoMap map[string]*Syste
 	}
 }
 
-// Read entire state of the locks in the system and return.
-func getSystemLockState() (SystemLockState, error) {
-	globalNSMutex.lockMapMutex.Lock()
-	defer globalNSMutex.lockMapMutex.Unlock()
-
-	lockState := SystemLockState{}
-
-	lockState.TotalBlockedLocks = globalNSMutex.counters.blocked
-	lockState.TotalLocks = globalNSMutex.counters.total
-	lockState.TotalAcquiredLocks = globalNSMutex.counters.granted
-
-	for param, debugLock := range globalNSMutex.debugLockMap {
-		volLockInfo := VolumeLockInfo{}
-		volLockInfo.Bucket = param.volume
-		volLockInfo.Object = param.path
-		volLockInfo.LocksOnObject = debugLock.counters.total
-		volLockInfo.TotalBlockedLocks = debugLock.counters.blocked
-		volLockInfo.LocksAcquiredOnObject = debugLock.counters.granted
-		for opsID, lockInfo := range debugLock.lockInfo {
-			volLockInfo.LockDetailsOnObject = append(volLockInfo.LockDetailsOnObject, OpsLockState{
-				OperationID: opsID,
-				LockSource:  lockInfo.lockSource,
-				LockType:    lockInfo.lType,
-				Status:      lockInfo.status,
-				Since:       lockInfo.since,
-			})
-		}
-		lockState.LocksInfoPerObject = append(lockState.LocksInfoPerObject, volLockInfo)
-	}
-	return lockState, nil
+// getDebugLockInfo - returns lock info of (volume, path) from its
+// shard of lock instrumentation, nil if there is none.
+func getDebugLockInfo(param nsParam) *debugLockInfoPerVolumePath {
+	shard := globalNSMutex.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	return shard.lockInfo[param]
+}
+
+// setDebugLockInfo - sets lock info of (volume, path) in its shard of
+// lock instrumentation.
+func setDebugLockInfo(param nsParam, lockInfo *debugLockInfoPerVolumePath) {
+	shard := globalNSMutex.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	shard.lockInfo[param] = lockInfo
 }
 
 // Asserts the lock counter from the global globalNSMutex inmemory lock with the expected one.
 func verifyGlobalLockStats(l lockStateCase, t *testing.T, testNum int) {
-	globalNSMutex.lockMapMutex.Lock()
-
 	// Verifying the lock stats.
-	if globalNSMutex.counters.total != int64(l.expectedGlobalLockCount) {
+	if globalNSMutex.getLockStat().total != int64(l.expectedGlobalLockCount) {
 		t.Errorf("Test %d: Expected the global lock counter to be %v, but got %v", testNum, int64(l.expectedGlobalLockCount),
-			globalNSMutex.counters.total)
+			globalNSMutex.getLockStat().total)
 	}
 	// verify the count for total blocked locks.
-	if globalNSMutex.counters.blocked != int64(l.expectedBlockedLockCount) {
+	if globalNSMutex.getLockStat().blocked != int64(l.expectedBlockedLockCount) {
 		t.Errorf("Test %d: Expected the total blocked lock counter to be %v, but got %v", testNum, int64(l.expectedBlockedLockCount),
-			globalNSMutex.counters.blocked)
+			globalNSMutex.getLockStat().blocked)
 	}
 	// verify the count for total running locks.
-	if globalNSMutex.counters.granted != int64(l.expectedRunningLockCount) {
+	if globalNSMutex.getLockStat().granted != int64(l.expectedRunningLockCount) {
 		t.Errorf("Test %d: Expected the total running lock counter to be %v, but got %v", testNum, int64(l.expectedRunningLockCount),
-			globalNSMutex.counters.granted)
+			globalNSMutex.getLockStat().granted)
 	}
-	globalNSMutex.lockMapMutex.Unlock()
 	// Verifying again with the JSON response of the lock info.
 	// Verifying the lock stats.
 	sysLockState, err := getSystemLockState()
@@ -193,24 +181,22 @@ func verifyGlobalLockStats(l lockStateCase, t *testing.T, testNum int) {
 
 // Verify the lock counter for entries of given <volume, path> pair.
 func verifyLockStats(l lockStateCase, t *testing.T, testNum int) {
-	globalNSMutex.lockMapMutex.Lock()
-	defer globalNSMutex.lockMapMutex.Unlock()
 	param := nsParam{l.volume, l.path}
 
 	// Verify the total locks (blocked+running) for given <vol,path> pair.
-	if globalNSMutex.debugLockMap[param].counters.total != int64(l.expectedVolPathLockCount) {
+	if getDebugLockInfo(param).counters.total != int64(l.expectedVolPathLockCount) {
 		t.Errorf("Test %d: Expected the total lock count for volume: \"%s\", path: \"%s\" to be %v, but got %v", testNum,
-			param.volume, param.path, int64(l.expectedVolPathLockCount), globalNSMutex.debugLockMap[param].counters.total)
+			param.volume, param.path, int64(l.expectedVolPathLockCount), getDebugLockInfo(param).counters.total)
 	}
 	// Verify the total running locks for given <volume, path> pair.
-	if globalNSMutex.debugLockMap[param].counters.granted != int64(l.expectedVolPathRunningCount) {
+	if getDebugLockInfo(param).counters.granted != int64(l.expectedVolPathRunningCount) {
 		t.Errorf("Test %d: Expected the total running locks for volume: \"%s\", path: \"%s\" to be %v, but got %v", testNum, param.volume, param.path,
-			int64(l.expectedVolPathRunningCount), globalNSMutex.debugLockMap[param].counters.granted)
+			int64(l.expectedVolPathRunningCount), getDebugLockInfo(param).counters.granted)
 	}
 	// Verify the total blocked locks for givne <volume, path> pair.
-	if globalNSMutex.debugLockMap[param].counters.blocked != int64(l.expectedVolPathBlockCount) {
+	if getDebugLockInfo(param).counters.blocked != int64(l.expectedVolPathBlockCount) {
 		t.Errorf("Test %d:  Expected the total blocked locks for volume: \"%s\", path: \"%s\"  to be %v, but got %v", testNum, param.volume, param.path,
-			int64(l.expectedVolPathBlockCount), globalNSMutex.debugLockMap[param].counters.blocked)
+			int64(l.expectedVolPathBlockCount), getDebugLockInfo(param).counters.blocked)
 	}
 }
 
@@ -219,9 +205,8 @@ func verifyLockState(l lockStateCase, t *testing.T, testNum int) {
 	param := nsParam{l.volume, l.path}
 
 	verifyGlobalLockStats(l, t, testNum)
-	globalNSMutex.lockMapMutex.Lock()
 	// Verifying the lock statuS fields.
-	if debugLockMap, ok := globalNSMutex.debugLockMap[param]; ok {
+	if debugLockMap := getDebugLockInfo(param); debugLockMap != nil {
 		if lockInfo, ok := debugLockMap.lockInfo[l.opsID]; ok {
 			// Validating the lock type filed in the debug lock information.
 			if l.readLock {
@@ -250,9 +235,6 @@ func verifyLockState(l lockStateCase, t *testing.T, testNum int) {
 		// To change the status the entry for given <volume, path> should exist in the lock info struct.
 		t.Errorf("Test case %d: Debug lock entry for volume: %s, path: %s doesn't exist", testNum, param.volume, param.path)
 	}
-	// verifyLockStats holds its own lock.
-	globalNSMutex.lockMapMutex.Unlock()
-
 	// verify the lock count.
 	verifyLockStats(l, t, testNum)
 }
@@ -378,17 +360,13 @@ func TestNsLockMapStatusBlockedToRunning(t *testing.T) {
 		t.Fatalf("Errors mismatch: Expected \"%s\", got \"%s\"", expectedErr, actualErr)
 	}
 
-	globalNSMutex = &nsLockMap{
-		// entries of <volume,path> -> stateInfo of locks, for instrumentation purpose.
-		debugLockMap: make(map[nsParam]*debugLockInfoPerVolumePath),
-		lockMap:      make(map[nsParam]*nsLock),
-	}
+	globalNSMutex = newNSLockMap(false)
 
 	// Setting the lock info the be `nil`.
-	globalNSMutex.debugLockMap[param] = &debugLockInfoPerVolumePath{
+	setDebugLockInfo(param, &debugLockInfoPerVolumePath{
 		lockInfo: nil, // setting the lockinfo to nil.
 		counters: &lockStat{},
-	}
+	})
 
 	actualErr = globalNSMutex.statusBlockedToRunning(param, testCases[0].lockSource,
 		testCases[0].opsID, testCases[0].readLock)
@@ -400,14 +378,11 @@ func TestNsLockMapStatusBlockedToRunning(t *testing.T) {
 
 	// Next case: ase whether an attempt to change the state of the lock to "Running" done,
 	// but the initial state if already "Running". Such an attempt should fail
-	globalNSMutex.debugLockMap[param] = &debugLockInfoPerVolumePath{
-		lockInfo: make(map[string]debugLockInfo),
-		counters: &lockStat{},
-	}
+	setDebugLockInfo(param, newDebugLockInfoPerVolumePath())
 
 	// Setting the status of the lock to be "Running".
 	// The initial state of the lock should set to "Blocked", otherwise its not possible to change the state from "Blocked" -> "Running".
-	globalNSMutex.debugLockMap[param].lockInfo[testCases[0].opsID] = debugLockInfo{
+	getDebugLockInfo(param).lockInfo[testCases[0].opsID] = debugLockInfo{
 		lockSource: "/home/vadmeste/work/go/src/github.com/minio/minio/xl-v1-object.go:683 +0x2a",
 		status:     "Running", // State set to "Running". Should fail with `LockInfoStateNotBlocked`.
 		since:      UTCNow(),
@@ -430,7 +405,7 @@ func TestNsLockMapStatusBlockedToRunning(t *testing.T) {
 		// status of the lock to be set to "Blocked", before setting Blocked->Running.
 		if testCase.setBlocked {
 			globalNSMutex.lockMapMutex.Lock()
-			err := globalNSMutex.statusNoneToBlocked(param, testCase.lockSource, testCase.opsID, testCase.readLock)
+			err := globalNSMutex.statusNoneToBlocked(param, testCase.lockSource, testCase.opsID, "", testCase.readLock)
 			if err != nil {
 				t.Fatalf("Test %d: Initializing the initial state to Blocked failed <ERROR> %s", i+1, err)
 			}
@@ -444,7 +419,7 @@ func TestNsLockMapStatusBlockedToRunning(t *testing.T) {
 		// In case of no error proceed with validating the lock state information.
 		if actualErr == nil {
 			// debug entry for given <volume, path> pair should exist.
-			if debugLockMap, ok := globalNSMutex.debugLockMap[param]; ok {
+			if debugLockMap := getDebugLockInfo(param); debugLockMap != nil {
 				if lockInfo, ok := debugLockMap.lockInfo[testCase.opsID]; ok {
 					// Validating the lock type filed in the debug lock information.
 					if testCase.readLock {
@@ -565,7 +540,7 @@ func TestNsLockMapStatusNoneToBlocked(t *testing.T) {
 	for i, testCase := range testCases {
 		globalNSMutex.lockMapMutex.Lock()
 		param := nsParam{testCase.volume, testCase.path}
-		actualErr := globalNSMutex.statusNoneToBlocked(param, testCase.lockSource, testCase.opsID, testCase.readLock)
+		actualErr := globalNSMutex.statusNoneToBlocked(param, testCase.lockSource, testCase.opsID, "", testCase.readLock)
 		if actualErr != testCase.expectedErr {
 			t.Fatalf("Test %d: Errors mismatch: Expected: \"%s\", got: \"%s\"", i+1, testCase.expectedErr, actualErr)
 		}
@@ -608,7 +583,7 @@ func TestNsLockMapDeleteLockInfoEntryForOps(t *testing.T) {
 	// Case - 2.
 	// Lock state is set to Running and then an attempt to delete the info for non-existent opsID done.
 	globalNSMutex.lockMapMutex.Lock()
-	err := globalNSMutex.statusNoneToBlocked(param, testCases[0].lockSource, testCases[0].opsID, testCases[0].readLock)
+	err := globalNSMutex.statusNoneToBlocked(param, testCases[0].lockSource, testCases[0].opsID, "", testCases[0].readLock)
 	if err != nil {
 		t.Fatalf("Setting lock status to Blocked failed: <ERROR> %s", err)
 	}
@@ -628,7 +603,7 @@ func TestNsLockMapDeleteLockInfoEntryForOps(t *testing.T) {
 	// All metrics should be 0 after deleting the entry.
 
 	// Verify that the entry the opsID exists.
-	if debugLockMap, ok := globalNSMutex.debugLockMap[param]; ok {
+	if debugLockMap := getDebugLockInfo(param); debugLockMap != nil {
 		if _, ok := debugLockMap.lockInfo[testCases[0].opsID]; !ok {
 			t.Fatalf("Entry for OpsID \"%s\" in <volume> %s, <path> %s should have existed. ", testCases[0].opsID, param.volume, param.path)
 		}
@@ -642,21 +617,21 @@ func TestNsLockMapDeleteLockInfoEntryForOps(t *testing.T) {
 	}
 
 	// Verify that the entry for the opsId doesn't exists.
-	if debugLockMap, ok := globalNSMutex.debugLockMap[param]; ok {
+	if debugLockMap := getDebugLockInfo(param); debugLockMap != nil {
 		if _, ok := debugLockMap.lockInfo[testCases[0].opsID]; ok {
 			t.Fatalf("The entry for opsID \"%s\" should have been deleted", testCases[0].opsID)
 		}
 	} else {
 		t.Fatalf("Entry for <volume> %s, <path> %s should have existed. ", param.volume, param.path)
 	}
-	if globalNSMutex.counters.granted != 0 {
-		t.Errorf("Expected the count of total running locks to be %v, but got %v", 0, globalNSMutex.counters.granted)
+	if globalNSMutex.getLockStat().granted != 0 {
+		t.Errorf("Expected the count of total running locks to be %v, but got %v", 0, globalNSMutex.getLockStat().granted)
 	}
-	if globalNSMutex.counters.blocked != 0 {
-		t.Errorf("Expected the count of total blocked locks to be %v, but got %v", 0, globalNSMutex.counters.blocked)
+	if globalNSMutex.getLockStat().blocked != 0 {
+		t.Errorf("Expected the count of total blocked locks to be %v, but got %v", 0, globalNSMutex.getLockStat().blocked)
 	}
-	if globalNSMutex.counters.total != 0 {
-		t.Errorf("Expected the count of all locks to be %v, but got %v", 0, globalNSMutex.counters.total)
+	if globalNSMutex.getLockStat().total != 0 {
+		t.Errorf("Expected the count of all locks to be %v, but got %v", 0, globalNSMutex.getLockStat().total)
 	}
 }
 
@@ -694,7 +669,7 @@ func TestNsLockMapDeleteLockInfoEntryForVolumePath(t *testing.T) {
 
 	// Registering the entry first.
 	globalNSMutex.lockMapMutex.Lock()
-	err := globalNSMutex.statusNoneToBlocked(param, testCases[0].lockSource, testCases[0].opsID, testCases[0].readLock)
+	err := globalNSMutex.statusNoneToBlocked(param, testCases[0].lockSource, testCases[0].opsID, "", testCases[0].readLock)
 	if err != nil {
 		t.Fatalf("Setting lock status to Blocked failed: <ERROR> %s", err)
 	}
@@ -704,7 +679,7 @@ func TestNsLockMapDeleteLockInfoEntryForVolumePath(t *testing.T) {
 		t.Fatalf("Setting lock status to Running failed: <ERROR> %s", err)
 	}
 	// Verify that the entry the for given <volume, path> exists.
-	if _, ok := globalNSMutex.debugLockMap[param]; !ok {
+	if getDebugLockInfo(param) == nil {
 		t.Fatalf("Entry for <volume> %s, <path> %s should have existed.", param.volume, param.path)
 	}
 	// first delete the entry for the operation ID.
@@ -715,17 +690,74 @@ func TestNsLockMapDeleteLockInfoEntryForVolumePath(t *testing.T) {
 	}
 
 	// Verify that the entry for the opsId doesn't exists.
-	if _, ok := globalNSMutex.debugLockMap[param]; ok {
+	if getDebugLockInfo(param) != nil {
 		t.Fatalf("Entry for <volume> %s, <path> %s should have been deleted. ", param.volume, param.path)
 	}
 	// The lock count values should be 0.
-	if globalNSMutex.counters.granted != 0 {
-		t.Errorf("Expected the count of total running locks to be %v, but got %v", 0, globalNSMutex.counters.granted)
+	if globalNSMutex.getLockStat().granted != 0 {
+		t.Errorf("Expected the count of total running locks to be %v, but got %v", 0, globalNSMutex.getLockStat().granted)
+	}
+	if globalNSMutex.getLockStat().blocked != 0 {
+		t.Errorf("Expected the count of total blocked locks to be %v, but got %v", 0, globalNSMutex.getLockStat().blocked)
 	}
-	if globalNSMutex.counters.blocked != 0 {
-		t.Errorf("Expected the count of total blocked locks to be %v, but got %v", 0, globalNSMutex.counters.blocked)
+	if globalNSMutex.getLockStat().total != 0 {
+		t.Errorf("Expected the count of all locks to be %v, but got %v", 0, globalNSMutex.getLockStat().total)
 	}
-	if globalNSMutex.counters.total != 0 {
-		t.Errorf("Expected the count of all locks to be %v, but got %v", 0, globalNSMutex.counters.total)
+}
+
+// Tests locks on many (volume, path) pairs are instrumented
+// concurrently in their shards, and counters of all shards add up.
+func TestDebugLockShards(t *testing.T) {
+	initNSLock(false)
+
+	// Shard of a (volume, path) pair never changes.
+	param := nsParam{"bucket", "object"}
+	if globalNSMutex.getDebugLockShard(param) != globalNSMutex.getDebugLockShard(param) {
+		t.Fatal("Expected the same shard for the same (volume, path)")
+	}
+
+	const objects = 256
+	shards := make(map[*debugLockShard]struct{})
+	for i := 0; i < objects; i++ {
+		shards[globalNSMutex.getDebugLockShard(nsParam{"bucket", fmt.Sprintf("object-%d", i)})] = struct{}{}
+	}
+	if len(shards) < debugLockShardCount/2 {
+		t.Fatalf("Expected objects spread over at least %d shards, got %d", debugLockShardCount/2, len(shards))
+	}
+
+	var wg sync.WaitGroup
+	lockedCh := make(chan struct{})
+	for i := 0; i < objects; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			object := fmt.Sprintf("object-%d", i)
+			opsID := getOpsID()
+			globalNSMutex.Lock("bucket", object, opsID)
+			<-lockedCh
+			globalNSMutex.Unlock("bucket", object, opsID)
+		}(i)
+	}
+
+	// Wait for all locks to be granted.
+	for i := 0; globalNSMutex.getLockStat().granted != objects; i++ {
+		if i == 100 {
+			t.Fatalf("Expected %d granted locks, got %#v", objects, globalNSMutex.getLockStat())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	lockState, err := getSystemLockState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockState.TotalAcquiredLocks != objects || len(lockState.LocksInfoPerObject) != objects {
+		t.Fatalf("Expected %d locks in system lock state, got %d on %d objects", objects,
+			lockState.TotalAcquiredLocks, len(lockState.LocksInfoPerObject))
+	}
+
+	close(lockedCh)
+	wg.Wait()
+	if stat := globalNSMutex.getLockStat(); stat != (lockStat{}) {
+		t.Fatalf("Expected no locks after unlocking, got %#v", stat)
 	}
 }
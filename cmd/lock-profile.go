@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"expvar"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// Name of the expvar variable holding namespace lock counters.
+const nsLocksVarName = "minio.nsLocks"
+
+// blockedLocksProfile - pprof profile of lock acquisitions currently
+// blocked, by the stacks which asked for the locks. Samples are only
+// recorded when lock debugging is enabled.
+var blockedLocksProfile = pprof.NewProfile("github.com/minio/minio.blockedLocks")
+
+func init() {
+	expvar.Publish(nsLocksVarName, expvar.Func(getNSLockVars))
+}
+
+// nsLockVars - namespace lock counters of this server published as
+// expvar variable.
+type nsLockVars struct {
+	Total    int64 `json:"total"`
+	Blocked  int64 `json:"blocked"`
+	Granted  int64 `json:"granted"`
+	Stale    int64 `json:"stale"`
+	TimedOut int64 `json:"timedOut"`
+}
+
+// getNSLockVars - returns namespace lock counters, zero before the
+// namespace lock is initialized.
+func getNSLockVars() interface{} {
+	var vars nsLockVars
+	if globalNSMutex == nil {
+		return vars
+	}
+	stat := globalNSMutex.getLockStat()
+	vars.Total = stat.total
+	vars.Blocked = stat.blocked
+	vars.Granted = stat.granted
+	vars.Stale = atomic.LoadInt64(&globalNSMutex.staleLocks)
+	vars.TimedOut = atomic.LoadInt64(&globalNSMutex.timedOutLocks)
+	return vars
+}
+
+// blockedLockKey - identifies a blocked lock acquisition in
+// blockedLocksProfile.
+type blockedLockKey struct {
+	param nsParam
+	opsID string
+}
+
+// addBlockedLockSample - records a blocked lock acquisition, called
+// by nsLockMap.lock so that the sample's stack begins at the function
+// asking for the lock, followed by the origin of the lock.
+func addBlockedLockSample(param nsParam, opsID string) {
+	if globalIsLockDebug {
+		key := blockedLockKey{param, opsID}
+		// Add panics for a value already in the profile.
+		blockedLocksProfile.Remove(key)
+		blockedLocksProfile.Add(key, 2)
+	}
+}
+
+// removeBlockedLockSample - forgets a lock acquisition once granted,
+// timed out or unlocked, no-op if it wasn't recorded.
+func removeBlockedLockSample(param nsParam, opsID string) {
+	if globalIsLockDebug {
+		blockedLocksProfile.Remove(blockedLockKey{param, opsID})
+	}
+}
+
+// getExpvarsJSON - returns all expvar variables as a JSON object, like
+// the /debug/vars handler of expvar.
+func getExpvarsJSON() ([]byte, error) {
+	vars := make(map[string]json.RawMessage)
+	expvar.Do(func(kv expvar.KeyValue) {
+		vars[kv.Key] = json.RawMessage(kv.Value.String())
+	})
+	return json.Marshal(vars)
+}
@@ -0,0 +1,178 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// waitForBlockedLocks - waits until count lock acquisitions are
+// blocked in blockedLocksProfile.
+func waitForBlockedLocks(t *testing.T, count int) {
+	for i := 0; blockedLocksProfile.Count() != count; i++ {
+		if i == 100 {
+			t.Fatalf("Expected %d blocked locks in profile, got %d", count, blockedLocksProfile.Count())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Tests namespace lock counters published as expvar variable.
+func TestNSLockVars(t *testing.T) {
+	initNSLock(false)
+	globalNSMutex.Lock("bucket", "object", "op1")
+	globalNSMutex.RLock("bucket", "other", "op2")
+
+	vars := getNSLockVars().(nsLockVars)
+	if vars.Total != 2 || vars.Granted != 2 || vars.Blocked != 0 {
+		t.Fatalf("Unexpected namespace lock counters %#v", vars)
+	}
+
+	globalNSMutex.Unlock("bucket", "object", "op1")
+	globalNSMutex.RUnlock("bucket", "other", "op2")
+	if vars = getNSLockVars().(nsLockVars); vars.Total != 0 {
+		t.Fatalf("Unexpected namespace lock counters %#v", vars)
+	}
+}
+
+// Tests blocked lock acquisitions are sampled in blockedLocksProfile
+// only when lock debugging is enabled.
+func TestBlockedLocksProfile(t *testing.T) {
+	defer func(lockDebug bool) { globalIsLockDebug = lockDebug }(globalIsLockDebug)
+	initNSLock(false)
+
+	for _, lockDebug := range []bool{false, true} {
+		globalIsLockDebug = lockDebug
+		globalNSMutex.Lock("bucket", "object", "op1")
+
+		doneCh := make(chan struct{})
+		go func() {
+			globalNSMutex.Lock("bucket", "object", "op2")
+			globalNSMutex.Unlock("bucket", "object", "op2")
+			close(doneCh)
+		}()
+
+		// Wait for the second lock to block.
+		for i := 0; getNSLockVars().(nsLockVars).Blocked != 1; i++ {
+			if i == 100 {
+				t.Fatal("Expected second lock to block")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		expected := 0
+		if lockDebug {
+			expected = 1
+		}
+		waitForBlockedLocks(t, expected)
+
+		globalNSMutex.Unlock("bucket", "object", "op1")
+		<-doneCh
+		waitForBlockedLocks(t, 0)
+	}
+}
+
+// Tests the debug vars and blocked locks profile endpoints.
+func TestDebugLockProfileHandlers(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	defer func(lockDebug bool) { globalIsLockDebug = lockDebug }(globalIsLockDebug)
+	globalIsLockDebug = true
+	initNSLock(false)
+
+	globalNSMutex.Lock("bucket", "object", "op1")
+	doneCh := make(chan struct{})
+	go func() {
+		globalNSMutex.Lock("bucket", "object", "op2")
+		globalNSMutex.Unlock("bucket", "object", "op2")
+		close(doneCh)
+	}()
+	waitForBlockedLocks(t, 1)
+	defer func() {
+		globalNSMutex.Unlock("bucket", "object", "op1")
+		<-doneCh
+	}()
+
+	mux := router.NewRouter()
+	registerDebugRouter(mux)
+	cred := serverConfig.GetCredential()
+
+	testCases := []struct {
+		path           string
+		signed         bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		// Test 1: unsigned request for debug vars.
+		{debugVarsPath, false, http.StatusForbidden, ""},
+		// Test 2: debug vars include namespace lock counters.
+		{debugVarsPath, true, http.StatusOK, nsLocksVarName},
+		// Test 3: unsigned request for blocked locks profile.
+		{debugBlockedLocksPath, false, http.StatusForbidden, ""},
+		// Test 4: blocked locks profile in text format, with the stack
+		// of the blocked lock.
+		{debugBlockedLocksPath + "?debug=1", true, http.StatusOK, "TestDebugLockProfileHandlers"},
+	}
+	for i, testCase := range testCases {
+		var req *http.Request
+		if testCase.signed {
+			req, err = newTestSignedRequestV4("GET", testCase.path, 0, nil, cred.AccessKey, cred.SecretKey)
+		} else {
+			req, err = http.NewRequest("GET", testCase.path, nil)
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Fatalf("Test %d: expected %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), testCase.expectedBody) {
+			t.Fatalf("Test %d: expected %q in body %s", i+1, testCase.expectedBody, rec.Body.String())
+		}
+	}
+
+	// Lock counters are valid JSON of the expvar variable.
+	req, err := newTestSignedRequestV4("GET", debugVarsPath, 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	var vars map[string]json.RawMessage
+	if err = json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatal(err)
+	}
+	var lockVars nsLockVars
+	if err = json.Unmarshal(vars[nsLocksVarName], &lockVars); err != nil {
+		t.Fatal(err)
+	}
+	if lockVars.Granted != 1 || lockVars.Blocked != 1 {
+		t.Fatalf("Unexpected namespace lock counters %#v", lockVars)
+	}
+}
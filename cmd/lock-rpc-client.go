@@ -16,9 +16,7 @@
 
 package cmd
 
-import "github.com/minio/dsync"
-
-// LockRPCClient is authenticable lock RPC client compatible to dsync.NetLocker
+// LockRPCClient is authenticable lock RPC client compatible to NetLocker
 type LockRPCClient struct {
 	*AuthRPCClient
 }
@@ -29,42 +27,42 @@ func newLockRPCClient(config authConfig) *LockRPCClient {
 }
 
 // RLock calls read lock RPC.
-func (lockRPCClient *LockRPCClient) RLock(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) RLock(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.RLock", &lockArgs, &reply)
 	return reply, err
 }
 
 // Lock calls write lock RPC.
-func (lockRPCClient *LockRPCClient) Lock(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) Lock(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.Lock", &lockArgs, &reply)
 	return reply, err
 }
 
 // RUnlock calls read unlock RPC.
-func (lockRPCClient *LockRPCClient) RUnlock(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) RUnlock(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.RUnlock", &lockArgs, &reply)
 	return reply, err
 }
 
 // Unlock calls write unlock RPC.
-func (lockRPCClient *LockRPCClient) Unlock(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) Unlock(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.Unlock", &lockArgs, &reply)
 	return reply, err
 }
 
 // ForceUnlock calls force unlock RPC.
-func (lockRPCClient *LockRPCClient) ForceUnlock(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) ForceUnlock(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.ForceUnlock", &lockArgs, &reply)
 	return reply, err
 }
 
 // Expired calls expired RPC.
-func (lockRPCClient *LockRPCClient) Expired(args dsync.LockArgs) (reply bool, err error) {
+func (lockRPCClient *LockRPCClient) Expired(args NetLockArgs) (reply bool, err error) {
 	lockArgs := newLockArgs(args)
 	err = lockRPCClient.AuthRPCClient.Call("Dsync.Expired", &lockArgs, &reply)
 	return reply, err
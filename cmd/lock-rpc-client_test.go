@@ -19,8 +19,6 @@ package cmd
 import (
 	"fmt"
 	"testing"
-
-	"github.com/minio/dsync"
 )
 
 // Tests lock rpc client.
@@ -35,32 +33,32 @@ func TestLockRPCClient(t *testing.T) {
 	})
 
 	// Attempt all calls.
-	_, err := lkClient.RLock(dsync.LockArgs{})
+	_, err := lkClient.RLock(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for Rlock to fail")
 	}
 
-	_, err = lkClient.Lock(dsync.LockArgs{})
+	_, err = lkClient.Lock(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for Lock to fail")
 	}
 
-	_, err = lkClient.RUnlock(dsync.LockArgs{})
+	_, err = lkClient.RUnlock(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for RUnlock to fail")
 	}
 
-	_, err = lkClient.Unlock(dsync.LockArgs{})
+	_, err = lkClient.Unlock(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for Unlock to fail")
 	}
 
-	_, err = lkClient.ForceUnlock(dsync.LockArgs{})
+	_, err = lkClient.ForceUnlock(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for ForceUnlock to fail")
 	}
 
-	_, err = lkClient.Expired(dsync.LockArgs{})
+	_, err = lkClient.Expired(NetLockArgs{})
 	if err == nil {
 		t.Fatal("Expected for Expired to fail")
 	}
@@ -25,7 +25,6 @@ import (
 	"time"
 
 	router "github.com/gorilla/mux"
-	"github.com/minio/dsync"
 )
 
 const (
@@ -125,7 +124,7 @@ func registerStorageLockers(mux *router.Router, lockServers []*lockServer) error
 			return traceError(err)
 		}
 		lockRouter := mux.PathPrefix(minioReservedBucketPath).Subrouter()
-		lockRouter.Path(path.Join(lockServicePath, lockServer.serviceEndpoint)).Handler(lockRPCServer)
+		lockRouter.Path(path.Join(lockServicePath, lockServer.serviceEndpoint)).Handler(setRPCMutualTLSHandler(lockRPCServer))
 	}
 	return nil
 }
@@ -296,7 +295,7 @@ func (l *lockServer) lockMaintenance(interval time.Duration) {
 		})
 
 		// Call back to original server verify whether the lock is still active (based on name & uid)
-		expired, _ := c.Expired(dsync.LockArgs{
+		expired, _ := c.Expired(NetLockArgs{
 			UID:      nlrip.lri.uid,
 			Resource: nlrip.name,
 		})
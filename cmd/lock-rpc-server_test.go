@@ -20,8 +20,6 @@ import (
 	"runtime"
 	"sync"
 	"testing"
-
-	"github.com/minio/dsync"
 )
 
 // Helper function to test equality of locks (without taking timing info into account)
@@ -76,7 +74,7 @@ func TestLockRpcServerLock(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -109,7 +107,7 @@ func TestLockRpcServerLock(t *testing.T) {
 	}
 
 	// Try to claim same lock again (will fail)
-	la2 := newLockArgs(dsync.LockArgs{
+	la2 := newLockArgs(NetLockArgs{
 		UID:             "89ab-cdef",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -132,7 +130,7 @@ func TestLockRpcServerUnlock(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -177,7 +175,7 @@ func TestLockRpcServerRLock(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -210,7 +208,7 @@ func TestLockRpcServerRLock(t *testing.T) {
 	}
 
 	// Try to claim same again (will succeed)
-	la2 := newLockArgs(dsync.LockArgs{
+	la2 := newLockArgs(NetLockArgs{
 		UID:             "89ab-cdef",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -233,7 +231,7 @@ func TestLockRpcServerRUnlock(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -257,7 +255,7 @@ func TestLockRpcServerRUnlock(t *testing.T) {
 	}
 
 	// Try to claim same again (will succeed)
-	la2 := newLockArgs(dsync.LockArgs{
+	la2 := newLockArgs(NetLockArgs{
 		UID:             "89ab-cdef",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -319,7 +317,7 @@ func TestLockRpcServerForceUnlock(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	laForce := newLockArgs(dsync.LockArgs{
+	laForce := newLockArgs(NetLockArgs{
 		UID:             "1234-5678",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -341,7 +339,7 @@ func TestLockRpcServerForceUnlock(t *testing.T) {
 		t.Errorf("Expected no error, got %#v", err)
 	}
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -383,7 +381,7 @@ func TestLockRpcServerExpired(t *testing.T) {
 	testPath, locker, token := createLockTestServer(t)
 	defer removeAll(testPath)
 
-	la := newLockArgs(dsync.LockArgs{
+	la := newLockArgs(NetLockArgs{
 		UID:             "0123-4567",
 		Resource:        "name",
 		ServerAddr:      "node",
@@ -0,0 +1,394 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Default distributed lock acquisition tunables.
+const (
+	defaultLockAcquireTimeout = time.Second
+	defaultLockRetryUnit      = time.Second
+	defaultLockRetryCap       = time.Second
+)
+
+// Global distributed locker, set only in distributed setup.
+var globalDistLocker *distLocker
+
+// NetLockArgs - arguments of a lock operation on a lock server.
+type NetLockArgs struct {
+	// Unique ID of lock/unlock request.
+	UID string
+
+	// Resource contains a entity to be locked/unlocked.
+	Resource string
+
+	// ServerAddr contains the address of the server who requested lock/unlock of the above resource.
+	ServerAddr string
+
+	// ServiceEndpoint contains the network path of above server to do lock/unlock.
+	ServiceEndpoint string
+}
+
+// NetLocker - client of a lock server, each operation returns whether
+// it succeeded and an error if the request failed.
+type NetLocker interface {
+	RLock(args NetLockArgs) (bool, error)
+	Lock(args NetLockArgs) (bool, error)
+	RUnlock(args NetLockArgs) (bool, error)
+	Unlock(args NetLockArgs) (bool, error)
+
+	// Unlocks read and write locks forcefully.
+	ForceUnlock(args NetLockArgs) (bool, error)
+
+	// Address and service endpoint of the lock server.
+	ServerAddr() string
+	ServiceEndpoint() string
+}
+
+// distLocker - lock servers participating in distributed locking, a
+// lock is held once a quorum of the lock servers granted it. Lock
+// acquisition tunables are configurable.
+type distLocker struct {
+	clnts   []NetLocker
+	ownNode int
+
+	// Number of grants needed for write and read locks.
+	quorum      int
+	quorumReads int
+
+	// Time to wait for a quorum of lock grants in one attempt.
+	acquireTimeout time.Duration
+	// Base and maximum back-off time between two lock attempts.
+	retryUnit time.Duration
+	retryCap  time.Duration
+}
+
+// newDistLocker - validates the lock servers and settings and returns
+// a new distributed locker, zero settings use the defaults.
+func newDistLocker(clnts []NetLocker, ownNode int, settings lockSettings) (*distLocker, error) {
+	nodes := len(clnts)
+	if nodes < 4 {
+		return nil, errors.New("Distributed locking is not designed for less than 4 nodes")
+	} else if nodes > 16 {
+		return nil, errors.New("Distributed locking is not designed for more than 16 nodes")
+	} else if nodes%2 != 0 {
+		return nil, errors.New("Distributed locking is not designed for an uneven number of nodes")
+	}
+	if ownNode < 0 || ownNode >= nodes {
+		return nil, errors.New("Index for own node is out of range")
+	}
+	if settings.quorum != 0 && (settings.quorum <= nodes/2 || settings.quorum > nodes) {
+		return nil, errors.New("Lock quorum should be a majority of the nodes")
+	}
+
+	dl := &distLocker{
+		clnts:          append([]NetLocker(nil), clnts...),
+		ownNode:        ownNode,
+		quorum:         nodes/2 + 1,
+		acquireTimeout: defaultLockAcquireTimeout,
+		retryUnit:      defaultLockRetryUnit,
+		retryCap:       defaultLockRetryCap,
+	}
+	if settings.quorum != 0 {
+		dl.quorum = settings.quorum
+	}
+	// Any read quorum overlaps with any write quorum.
+	dl.quorumReads = nodes - dl.quorum + 1
+	if settings.acquireTimeout != 0 {
+		dl.acquireTimeout = settings.acquireTimeout
+	}
+	if settings.retryUnit != 0 {
+		dl.retryUnit = settings.retryUnit
+	}
+	if settings.retryCap != 0 {
+		dl.retryCap = settings.retryCap
+	}
+	return dl, nil
+}
+
+// newRWMutex - returns a new distributed read/write mutex on name.
+func (dl *distLocker) newRWMutex(name string) *distRWMutex {
+	return &distRWMutex{
+		name:       name,
+		locker:     dl,
+		writeLocks: make([]string, len(dl.clnts)),
+	}
+}
+
+// distGrant - reply of a lock server to a lock request, lockUID is
+// empty if the lock was not granted.
+type distGrant struct {
+	index   int
+	lockUID string
+}
+
+// lock - tries to acquire the lock on a quorum of lock servers once,
+// granted lock UIDs are stored in locks. Waits at most acquireTimeout
+// for replies of the lock servers.
+func (dl *distLocker) lock(locks []string, name string, isReadLock bool, acquireTimeout time.Duration) bool {
+	nodes := len(dl.clnts)
+
+	// Buffered so that late replies never block.
+	ch := make(chan distGrant, nodes)
+	for index, c := range dl.clnts {
+		go func(index int, c NetLocker) {
+			args := dl.newLockArgs(name, mustGetUUID())
+			var locked bool
+			// Failed RPCs are counted as not granted.
+			if isReadLock {
+				locked, _ = c.RLock(args)
+			} else {
+				locked, _ = c.Lock(args)
+			}
+			g := distGrant{index: index}
+			if locked {
+				g.lockUID = args.UID
+			}
+			ch <- g
+		}(index, c)
+	}
+
+	required := dl.quorum
+	if isReadLock {
+		required = dl.quorumReads
+	}
+
+	// Wait until all servers replied, quorum is not possible anymore
+	// or the acquire timeout passed.
+	i, locksFailed := 0, 0
+	timeout := time.After(acquireTimeout)
+wait:
+	for ; i < nodes; i++ {
+		select {
+		case grant := <-ch:
+			if grant.lockUID != "" {
+				locks[grant.index] = grant.lockUID
+			} else if locksFailed++; locksFailed > nodes-required {
+				// Not going to get the lock anymore.
+				i++
+				break wait
+			}
+		case <-timeout:
+			// Maybe one of the servers is slow, decide on the
+			// grants received so far.
+			break wait
+		}
+	}
+
+	// Release grants received after the decision, they are not
+	// added to locks since the mutex may already be unlocked again.
+	go func(pending int) {
+		for ; pending > 0; pending-- {
+			if grant := <-ch; grant.lockUID != "" {
+				dl.sendRelease(dl.clnts[grant.index], name, grant.lockUID, isReadLock)
+			}
+		}
+	}(nodes - i)
+
+	// Lock maintenance relies on the local server participating in
+	// every lock, release and try again later if it didn't.
+	if countGrantedLocks(locks) < required || locks[dl.ownNode] == "" {
+		dl.releaseAll(locks, name, isReadLock)
+		return false
+	}
+	return true
+}
+
+// countGrantedLocks - returns the number of lock servers which
+// granted the lock.
+func countGrantedLocks(locks []string) (count int) {
+	for _, uid := range locks {
+		if uid != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// releaseAll - releases all granted locks and clears them in locks.
+func (dl *distLocker) releaseAll(locks []string, name string, isReadLock bool) {
+	for index, uid := range locks {
+		if uid != "" {
+			dl.sendRelease(dl.clnts[index], name, uid, isReadLock)
+			locks[index] = ""
+		}
+	}
+}
+
+// newLockArgs - returns arguments of a lock RPC issued by this server.
+func (dl *distLocker) newLockArgs(name, uid string) NetLockArgs {
+	return NetLockArgs{
+		UID:             uid,
+		Resource:        name,
+		ServerAddr:      dl.clnts[dl.ownNode].ServerAddr(),
+		ServiceEndpoint: dl.clnts[dl.ownNode].ServiceEndpoint(),
+	}
+}
+
+// sendRelease - releases a lock granted by a lock server, an empty uid
+// forcefully releases all locks on name. Failures are not retried, a
+// stale lock is cleared by the lock maintenance of the lock server.
+func (dl *distLocker) sendRelease(c NetLocker, name, uid string, isReadLock bool) {
+	args := dl.newLockArgs(name, uid)
+	if uid == "" {
+		c.ForceUnlock(args)
+	} else if isReadLock {
+		c.RUnlock(args)
+	} else {
+		c.Unlock(args)
+	}
+}
+
+// distRWMutex - distributed read/write mutex, granted by a quorum of
+// the lock servers.
+type distRWMutex struct {
+	name   string
+	locker *distLocker
+
+	m            sync.Mutex // Protects locks of this node.
+	writeLocks   []string   // Lock UIDs of the servers which granted the write lock.
+	readersLocks [][]string // Lock UIDs of the servers which granted each read lock.
+}
+
+// Lock - holds a write lock, blocks until it is available.
+func (dm *distRWMutex) Lock() {
+	dm.lockBlocking(0, false)
+}
+
+// GetLock - tries to get a write lock before the timeout elapses,
+// returns false if it couldn't.
+func (dm *distRWMutex) GetLock(timeout time.Duration) bool {
+	return dm.lockBlocking(timeout, false)
+}
+
+// RLock - holds a read lock, blocks until it is available.
+func (dm *distRWMutex) RLock() {
+	dm.lockBlocking(0, true)
+}
+
+// GetRLock - tries to get a read lock before the timeout elapses,
+// returns false if it couldn't.
+func (dm *distRWMutex) GetRLock(timeout time.Duration) bool {
+	return dm.lockBlocking(timeout, true)
+}
+
+// lockBlocking - retries to acquire the lock with randomized
+// exponential back-off until it is granted, or until the timeout
+// elapses when it is non-zero. The deadline is checked before every
+// attempt and back-off never sleeps past it.
+func (dm *distRWMutex) lockBlocking(timeout time.Duration, isReadLock bool) bool {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = UTCNow().Add(timeout)
+	}
+	for attempt := 0; ; attempt++ {
+		if !deadline.IsZero() && !UTCNow().Before(deadline) {
+			return false
+		}
+		// An attempt doesn't wait for lock servers past the deadline.
+		acquireTimeout := dm.locker.acquireTimeout
+		if !deadline.IsZero() {
+			if remaining := deadline.Sub(UTCNow()); remaining < acquireTimeout {
+				acquireTimeout = remaining
+			}
+		}
+		locks := make([]string, len(dm.locker.clnts))
+		if dm.locker.lock(locks, dm.name, isReadLock, acquireTimeout) {
+			dm.m.Lock()
+			if isReadLock {
+				dm.readersLocks = append(dm.readersLocks, locks)
+			} else {
+				dm.writeLocks = locks
+			}
+			dm.m.Unlock()
+			return true
+		}
+
+		wait := dm.locker.retryWait(attempt)
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(UTCNow())
+			if remaining <= 0 {
+				return false
+			}
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+		time.Sleep(wait)
+	}
+}
+
+// retryWait - returns the randomized back-off time after a failed lock
+// attempt, see https://www.awsarchitectureblog.com/2015/03/backoff.html
+func (dl *distLocker) retryWait(attempt int) time.Duration {
+	// 1<<uint(attempt) below could overflow, so limit the value of attempt
+	if attempt > 30 {
+		attempt = 30
+	}
+	wait := dl.retryUnit * time.Duration(1<<uint(attempt))
+	if wait > dl.retryCap || wait <= 0 {
+		wait = dl.retryCap
+	}
+	return wait - time.Duration(globalRandomSource.Float64()*float64(wait))
+}
+
+// Unlock - releases the write lock, it is a run-time error if no
+// write lock is held.
+func (dm *distRWMutex) Unlock() {
+	dm.m.Lock()
+	locks := dm.writeLocks
+	if countGrantedLocks(locks) == 0 {
+		dm.m.Unlock()
+		panic("Trying to Unlock() while no Lock() is active")
+	}
+	dm.writeLocks = make([]string, len(dm.locker.clnts))
+	dm.m.Unlock()
+
+	dm.locker.releaseAll(locks, dm.name, false)
+}
+
+// RUnlock - releases the oldest read lock, it is a run-time error if
+// no read lock is held.
+func (dm *distRWMutex) RUnlock() {
+	dm.m.Lock()
+	if len(dm.readersLocks) == 0 {
+		dm.m.Unlock()
+		panic("Trying to RUnlock() while no RLock() is active")
+	}
+	locks := dm.readersLocks[0]
+	dm.readersLocks = dm.readersLocks[1:]
+	dm.m.Unlock()
+
+	dm.locker.releaseAll(locks, dm.name, true)
+}
+
+// ForceUnlock - forcefully clears all read and write locks on all
+// lock servers.
+func (dm *distRWMutex) ForceUnlock() {
+	dm.m.Lock()
+	dm.writeLocks = make([]string, len(dm.locker.clnts))
+	dm.readersLocks = nil
+	dm.m.Unlock()
+
+	for _, c := range dm.locker.clnts {
+		dm.locker.sendRelease(c, dm.name, "", false)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memNetLocker - in memory lock server, a down server fails all
+// requests.
+type memNetLocker struct {
+	mu     sync.Mutex
+	down   bool
+	writer map[string]string
+	reader map[string]int
+}
+
+func newMemNetLocker() *memNetLocker {
+	return &memNetLocker{writer: make(map[string]string), reader: make(map[string]int)}
+}
+
+func (l *memNetLocker) RLock(args NetLockArgs) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.down {
+		return false, errors.New("server down")
+	}
+	if l.writer[args.Resource] != "" {
+		return false, nil
+	}
+	l.reader[args.Resource]++
+	return true, nil
+}
+
+func (l *memNetLocker) Lock(args NetLockArgs) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.down {
+		return false, errors.New("server down")
+	}
+	if l.writer[args.Resource] != "" || l.reader[args.Resource] > 0 {
+		return false, nil
+	}
+	l.writer[args.Resource] = args.UID
+	return true, nil
+}
+
+func (l *memNetLocker) RUnlock(args NetLockArgs) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.reader[args.Resource] > 0 {
+		l.reader[args.Resource]--
+	}
+	return true, nil
+}
+
+func (l *memNetLocker) Unlock(args NetLockArgs) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.writer[args.Resource] == args.UID {
+		delete(l.writer, args.Resource)
+	}
+	return true, nil
+}
+
+func (l *memNetLocker) ForceUnlock(args NetLockArgs) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.writer, args.Resource)
+	delete(l.reader, args.Resource)
+	return true, nil
+}
+
+func (l *memNetLocker) ServerAddr() string      { return "localhost:9000" }
+func (l *memNetLocker) ServiceEndpoint() string { return "/lock" }
+
+// Tests validation of lock servers and settings.
+func TestNewDistLocker(t *testing.T) {
+	newClnts := func(n int) (clnts []NetLocker) {
+		for i := 0; i < n; i++ {
+			clnts = append(clnts, newMemNetLocker())
+		}
+		return clnts
+	}
+
+	testCases := []struct {
+		nodes      int
+		ownNode    int
+		settings   lockSettings
+		shouldPass bool
+	}{
+		{4, 0, lockSettings{}, true},
+		{16, 15, lockSettings{quorum: 16}, true},
+		// Too few, too many and uneven number of nodes.
+		{2, 0, lockSettings{}, false},
+		{18, 0, lockSettings{}, false},
+		{5, 0, lockSettings{}, false},
+		// Own node out of range.
+		{4, 4, lockSettings{}, false},
+		// Quorum is not a majority.
+		{4, 0, lockSettings{quorum: 2}, false},
+		{4, 0, lockSettings{quorum: 5}, false},
+	}
+	for i, testCase := range testCases {
+		_, err := newDistLocker(newClnts(testCase.nodes), testCase.ownNode, testCase.settings)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+
+	dl, err := newDistLocker(newClnts(8), 0, lockSettings{quorum: 6, retryUnit: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.quorum != 6 || dl.quorumReads != 3 {
+		t.Errorf("Expected quorums 6 and 3, got %d and %d", dl.quorum, dl.quorumReads)
+	}
+	if dl.acquireTimeout != defaultLockAcquireTimeout || dl.retryUnit != time.Millisecond || dl.retryCap != defaultLockRetryCap {
+		t.Errorf("Unexpected lock settings %#v", dl)
+	}
+}
+
+// Tests distributed locking honors quorum and lock timeouts.
+func TestDistRWMutex(t *testing.T) {
+	lockers := []*memNetLocker{newMemNetLocker(), newMemNetLocker(), newMemNetLocker(), newMemNetLocker()}
+	var clnts []NetLocker
+	for _, l := range lockers {
+		clnts = append(clnts, l)
+	}
+	dl, err := newDistLocker(clnts, 0, lockSettings{
+		acquireTimeout: 100 * time.Millisecond,
+		retryUnit:      time.Millisecond,
+		retryCap:       10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dm := dl.newRWMutex("bucket/object")
+	dm.Lock()
+	// Write lock is exclusive.
+	if dl.newRWMutex("bucket/object").GetRLock(50 * time.Millisecond) {
+		t.Fatal("Expected read lock not to be granted while write locked")
+	}
+	dm.Unlock()
+
+	// Read locks are shared.
+	dm.RLock()
+	if !dm.GetRLock(50 * time.Millisecond) {
+		t.Fatal("Expected read lock to be granted while read locked")
+	}
+	if dl.newRWMutex("bucket/object").GetLock(50 * time.Millisecond) {
+		t.Fatal("Expected write lock not to be granted while read locked")
+	}
+	dm.RUnlock()
+	dm.RUnlock()
+
+	// Locks are granted with one server down, a quorum of 3 is left.
+	lockers[3].mu.Lock()
+	lockers[3].down = true
+	lockers[3].mu.Unlock()
+	if !dm.GetLock(time.Second) {
+		t.Fatal("Expected write lock to be granted with a quorum of servers")
+	}
+	dm.Unlock()
+
+	// Not without the local server.
+	lockers[0].mu.Lock()
+	lockers[0].down = true
+	lockers[0].mu.Unlock()
+	if dm.GetRLock(50 * time.Millisecond) {
+		t.Fatal("Expected read lock not to be granted without the local server")
+	}
+
+	// ForceUnlock clears locks on all servers.
+	lockers[0].mu.Lock()
+	lockers[0].down = false
+	lockers[0].mu.Unlock()
+	dm.Lock()
+	dl.newRWMutex("bucket/object").ForceUnlock()
+	if !dl.newRWMutex("bucket/object").GetLock(time.Second) {
+		t.Fatal("Expected write lock to be granted after ForceUnlock")
+	}
+}
+
+// Tests lock timeouts are not overshot by the back-off between lock
+// attempts.
+func TestDistRWMutexTimeout(t *testing.T) {
+	var clnts []NetLocker
+	for i := 0; i < 4; i++ {
+		clnts = append(clnts, newMemNetLocker())
+	}
+	dl, err := newDistLocker(clnts, 0, lockSettings{
+		retryUnit: 5 * time.Second,
+		retryCap:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dm := dl.newRWMutex("bucket/object")
+	dm.Lock()
+	defer dm.Unlock()
+
+	start := UTCNow()
+	if dl.newRWMutex("bucket/object").GetLock(100 * time.Millisecond) {
+		t.Fatal("Expected write lock not to be granted while write locked")
+	}
+	if elapsed := UTCNow().Sub(start); elapsed > time.Second {
+		t.Fatalf("Expected lock attempts to stop at the timeout, took %s", elapsed)
+	}
+}
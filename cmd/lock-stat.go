@@ -16,7 +16,11 @@
 
 package cmd
 
-// lockStat - encapsulates total, blocked and granted lock counts.
+import "sync/atomic"
+
+// lockStat - encapsulates total, blocked and granted lock counts,
+// updated atomically so that they can be read without holding the
+// lock of the instrumentation they belong to.
 type lockStat struct {
 	total   int64
 	blocked int64
@@ -25,25 +29,39 @@ type lockStat struct {
 
 // lockWaiting - updates lock stat when a lock becomes blocked.
 func (ls *lockStat) lockWaiting() {
-	ls.blocked++
-	ls.total++
+	atomic.AddInt64(&ls.blocked, 1)
+	atomic.AddInt64(&ls.total, 1)
 }
 
 // lockGranted - updates lock stat when a lock is granted.
 func (ls *lockStat) lockGranted() {
-	ls.blocked--
-	ls.granted++
+	atomic.AddInt64(&ls.blocked, -1)
+	atomic.AddInt64(&ls.granted, 1)
 }
 
 // lockRemoved - updates lock stat when a lock is removed, by Unlock
 // or ForceUnlock.
 func (ls *lockStat) lockRemoved(granted bool) {
 	if granted {
-		ls.granted--
-		ls.total--
-
+		atomic.AddInt64(&ls.granted, -1)
 	} else {
-		ls.blocked--
-		ls.total--
+		atomic.AddInt64(&ls.blocked, -1)
 	}
+	atomic.AddInt64(&ls.total, -1)
+}
+
+// load - returns the lock counts, each read atomically.
+func (ls *lockStat) load() lockStat {
+	return lockStat{
+		total:   atomic.LoadInt64(&ls.total),
+		blocked: atomic.LoadInt64(&ls.blocked),
+		granted: atomic.LoadInt64(&ls.granted),
+	}
+}
+
+// add - adds the lock counts of other to ls, not atomically.
+func (ls *lockStat) add(other lockStat) {
+	ls.total += other.total
+	ls.blocked += other.blocked
+	ls.granted += other.granted
 }
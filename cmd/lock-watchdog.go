@@ -0,0 +1,101 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Default duration after which a held or blocked lock is
+// reported as stale.
+const defaultStaleLockThreshold = 5 * time.Minute
+
+// staleLockError - represents a lock held or blocked on (volume, path)
+// for longer than the stale lock threshold.
+type staleLockError struct {
+	volume     string
+	path       string
+	opsID      string
+	requestID  string
+	lockSource string
+	status     statusType
+	elapsed    time.Duration
+}
+
+func (s staleLockError) Error() string {
+	msg := fmt.Sprintf("Possible stale lock originated at \"%s\", in state %s for %s, <volume> %s, <path> %s, <opsID> %s",
+		s.lockSource, s.status, s.elapsed, s.volume, s.path, s.opsID)
+	if s.requestID != "" {
+		msg += fmt.Sprintf(", <requestID> %s", s.requestID)
+	}
+	return msg
+}
+
+// findStaleLocks - returns all the locks held or blocked for longer than
+// threshold which are not reported yet, and marks them as reported.
+func (n *nsLockMap) findStaleLocks(threshold time.Duration, timeNow time.Time) []staleLockError {
+	var staleLocks []staleLockError
+	for _, shard := range n.debugLockShards {
+		shard.mutex.Lock()
+		for param, debugLock := range shard.lockInfo {
+			for opsID, lockInfo := range debugLock.lockInfo {
+				elapsed := timeNow.Sub(lockInfo.since)
+				if lockInfo.stale || elapsed < threshold {
+					continue
+				}
+				// Report every lock only once.
+				lockInfo.stale = true
+				debugLock.lockInfo[opsID] = lockInfo
+				atomic.AddInt64(&n.staleLocks, 1)
+
+				staleLocks = append(staleLocks, staleLockError{
+					volume:     param.volume,
+					path:       param.path,
+					opsID:      opsID,
+					requestID:  lockInfo.requestID,
+					lockSource: lockInfo.lockSource,
+					status:     lockInfo.status,
+					elapsed:    elapsed,
+				})
+			}
+		}
+		shard.mutex.Unlock()
+	}
+	return staleLocks
+}
+
+// watchStaleLocks - periodically looks for locks held or blocked for
+// longer than threshold and logs a warning with their origin, runs
+// until doneCh is closed.
+func (n *nsLockMap) watchStaleLocks(threshold time.Duration, doneCh <-chan struct{}) {
+	// Check twice per threshold so that a lock is reported at
+	// most threshold/2 after turning stale.
+	ticker := time.NewTicker(threshold / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, staleLock := range n.findStaleLocks(threshold, UTCNow()) {
+				errorIf(staleLock, "Lock watchdog detected a stale lock")
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
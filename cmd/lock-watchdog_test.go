@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests detection of stale locks by the lock watchdog.
+func TestFindStaleLocks(t *testing.T) {
+	initNSLock(false)
+	threshold := time.Minute
+
+	globalNSMutex.Lock("bucket", "object", "op1")
+	defer globalNSMutex.Unlock("bucket", "object", "op1")
+	globalNSMutex.RLock("bucket", "other", "op2")
+	defer globalNSMutex.RUnlock("bucket", "other", "op2")
+
+	// No lock is stale before the threshold.
+	if staleLocks := globalNSMutex.findStaleLocks(threshold, UTCNow()); len(staleLocks) != 0 {
+		t.Fatalf("Expected no stale locks, got %d", len(staleLocks))
+	}
+
+	// Both locks are stale after the threshold.
+	staleLocks := globalNSMutex.findStaleLocks(threshold, UTCNow().Add(2*threshold))
+	if len(staleLocks) != 2 {
+		t.Fatalf("Expected 2 stale locks, got %d", len(staleLocks))
+	}
+	for _, staleLock := range staleLocks {
+		if staleLock.status != runningStatus {
+			t.Errorf("Expected status %s, got %s", runningStatus, staleLock.status)
+		}
+		if staleLock.lockSource == "" {
+			t.Errorf("Expected lock source to be set for %s", staleLock.path)
+		}
+	}
+
+	// Stale locks are reported only once.
+	if staleLocks = globalNSMutex.findStaleLocks(threshold, UTCNow().Add(3*threshold)); len(staleLocks) != 0 {
+		t.Fatalf("Expected no new stale locks, got %d", len(staleLocks))
+	}
+
+	lockState, _ := getSystemLockState()
+	if lockState.TotalStaleLocks != 2 {
+		t.Fatalf("Expected 2 stale locks in system lock state, got %d", lockState.TotalStaleLocks)
+	}
+	if lockState.TotalAcquiredLocks != 2 || len(lockState.LocksInfoPerObject) != 2 {
+		t.Fatalf("Unexpected system lock state %#v", lockState)
+	}
+}
+
+// Tests lock watchdog stops when done channel is closed.
+func TestWatchStaleLocks(t *testing.T) {
+	initNSLock(false)
+	globalNSMutex.Lock("bucket", "object", "op1")
+	defer globalNSMutex.Unlock("bucket", "object", "op1")
+
+	doneCh := make(chan struct{})
+	exitCh := make(chan struct{})
+	go func() {
+		globalNSMutex.watchStaleLocks(10*time.Millisecond, doneCh)
+		close(exitCh)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	close(doneCh)
+	<-exitCh
+
+	if lockState, _ := getSystemLockState(); lockState.TotalStaleLocks != 1 {
+		t.Fatalf("Expected 1 stale lock, got %d", lockState.TotalStaleLocks)
+	}
+}
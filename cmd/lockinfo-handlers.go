@@ -16,7 +16,10 @@
 
 package cmd
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
 // SystemLockState - Structure to fill the lock state of entire object storage.
 // That is the total locks held, total calls blocked on locks and state of all the locks for the entire system.
@@ -27,10 +30,57 @@ type SystemLockState struct {
 	TotalBlockedLocks int64 `json:"totalBlockedLocks"`
 	// Count of operations which has successfully acquired the lock but
 	// hasn't unlocked yet (operation in progress).
-	TotalAcquiredLocks int64            `json:"totalAcquiredLocks"`
+	TotalAcquiredLocks int64 `json:"totalAcquiredLocks"`
+	// Count of locks reported stale by the lock watchdog since
+	// server start.
+	TotalStaleLocks int64 `json:"totalStaleLocks"`
+	// Count of locks not acquired before the deadline of their
+	// request since server start.
+	TotalTimedOutLocks int64            `json:"totalTimedOutLocks"`
 	LocksInfoPerObject []VolumeLockInfo `json:"locksInfoPerObject"`
 }
 
+// Read entire state of the locks in the system and return. Counters
+// are aggregated from the shards of lock instrumentation, which are
+// locked one at a time, so locks changing state meanwhile may be off
+// by one between counters and lock details.
+func getSystemLockState() (SystemLockState, error) {
+	lockState := SystemLockState{}
+
+	stat := globalNSMutex.getLockStat()
+	lockState.TotalBlockedLocks = stat.blocked
+	lockState.TotalLocks = stat.total
+	lockState.TotalAcquiredLocks = stat.granted
+	lockState.TotalStaleLocks = atomic.LoadInt64(&globalNSMutex.staleLocks)
+	lockState.TotalTimedOutLocks = atomic.LoadInt64(&globalNSMutex.timedOutLocks)
+
+	for _, shard := range globalNSMutex.debugLockShards {
+		shard.mutex.Lock()
+		for param, debugLock := range shard.lockInfo {
+			counters := debugLock.counters.load()
+			volLockInfo := VolumeLockInfo{}
+			volLockInfo.Bucket = param.volume
+			volLockInfo.Object = param.path
+			volLockInfo.LocksOnObject = counters.total
+			volLockInfo.TotalBlockedLocks = counters.blocked
+			volLockInfo.LocksAcquiredOnObject = counters.granted
+			for opsID, lockInfo := range debugLock.lockInfo {
+				volLockInfo.LockDetailsOnObject = append(volLockInfo.LockDetailsOnObject, OpsLockState{
+					OperationID: opsID,
+					RequestID:   lockInfo.requestID,
+					LockSource:  lockInfo.lockSource,
+					LockType:    lockInfo.lType,
+					Status:      lockInfo.status,
+					Since:       lockInfo.since,
+				})
+			}
+			lockState.LocksInfoPerObject = append(lockState.LocksInfoPerObject, volLockInfo)
+		}
+		shard.mutex.Unlock()
+	}
+	return lockState, nil
+}
+
 // VolumeLockInfo - Structure to contain the lock state info for volume, path pair.
 type VolumeLockInfo struct {
 	Bucket string `json:"bucket"`
@@ -57,56 +107,60 @@ type VolumeLockInfo struct {
 // OpsLockState - structure to fill in state information of the lock.
 // structure to fill in status information for each operation with given operation ID.
 type OpsLockState struct {
-	OperationID string     `json:"id"`     // String containing operation ID.
-	LockSource  string     `json:"source"` // Operation type (GetObject, PutObject...)
-	LockType    lockType   `json:"type"`   // Lock type (RLock, WLock)
-	Status      statusType `json:"status"` // Status can be Running/Ready/Blocked.
-	Since       time.Time  `json:"since"`  // Time when the lock was initially held.
+	OperationID string     `json:"id"`                  // String containing operation ID.
+	RequestID   string     `json:"requestID,omitempty"` // ID of the client request holding the lock.
+	LockSource  string     `json:"source"`              // Operation type (GetObject, PutObject...)
+	LockType    lockType   `json:"type"`                // Lock type (RLock, WLock)
+	Status      statusType `json:"status"`              // Status can be Running/Ready/Blocked.
+	Since       time.Time  `json:"since"`               // Time when the lock was initially held.
 }
 
 // listLocksInfo - Fetches locks held on bucket, matching prefix held for longer than duration.
 func listLocksInfo(bucket, prefix string, duration time.Duration) []VolumeLockInfo {
-	globalNSMutex.lockMapMutex.Lock()
-	defer globalNSMutex.lockMapMutex.Unlock()
-
 	// Fetch current time once instead of fetching system time for every lock.
 	timeNow := UTCNow()
 	volumeLocks := []VolumeLockInfo{}
 
-	for param, debugLock := range globalNSMutex.debugLockMap {
-		if param.volume != bucket {
-			continue
-		}
-		// N B empty prefix matches all param.path.
-		if !hasPrefix(param.path, prefix) {
-			continue
-		}
-
-		volLockInfo := VolumeLockInfo{
-			Bucket:                param.volume,
-			Object:                param.path,
-			LocksOnObject:         debugLock.counters.total,
-			TotalBlockedLocks:     debugLock.counters.blocked,
-			LocksAcquiredOnObject: debugLock.counters.granted,
-		}
-		// Filter locks that are held on bucket, prefix.
-		for opsID, lockInfo := range debugLock.lockInfo {
-			// filter locks that were held for longer than duration.
-			elapsed := timeNow.Sub(lockInfo.since)
-			if elapsed < duration {
+	for _, shard := range globalNSMutex.debugLockShards {
+		shard.mutex.Lock()
+		for param, debugLock := range shard.lockInfo {
+			if param.volume != bucket {
 				continue
 			}
-			// Add locks that are held for longer than duration.
-			volLockInfo.LockDetailsOnObject = append(volLockInfo.LockDetailsOnObject,
-				OpsLockState{
-					OperationID: opsID,
-					LockSource:  lockInfo.lockSource,
-					LockType:    lockInfo.lType,
-					Status:      lockInfo.status,
-					Since:       lockInfo.since,
-				})
-			volumeLocks = append(volumeLocks, volLockInfo)
+			// N B empty prefix matches all param.path.
+			if !hasPrefix(param.path, prefix) {
+				continue
+			}
+
+			counters := debugLock.counters.load()
+			volLockInfo := VolumeLockInfo{
+				Bucket:                param.volume,
+				Object:                param.path,
+				LocksOnObject:         counters.total,
+				TotalBlockedLocks:     counters.blocked,
+				LocksAcquiredOnObject: counters.granted,
+			}
+			// Filter locks that are held on bucket, prefix.
+			for opsID, lockInfo := range debugLock.lockInfo {
+				// filter locks that were held for longer than duration.
+				elapsed := timeNow.Sub(lockInfo.since)
+				if elapsed < duration {
+					continue
+				}
+				// Add locks that are held for longer than duration.
+				volLockInfo.LockDetailsOnObject = append(volLockInfo.LockDetailsOnObject,
+					OpsLockState{
+						OperationID: opsID,
+						RequestID:   lockInfo.requestID,
+						LockSource:  lockInfo.lockSource,
+						LockType:    lockInfo.lType,
+						Status:      lockInfo.status,
+						Since:       lockInfo.since,
+					})
+				volumeLocks = append(volumeLocks, volLockInfo)
+			}
 		}
+		shard.mutex.Unlock()
 	}
 	return volumeLocks
 }
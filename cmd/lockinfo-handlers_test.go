@@ -17,7 +17,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -79,3 +82,57 @@ func TestListLocksInfo(t *testing.T) {
 		}
 	}
 }
+
+// TestListLocksRequestID - Test that locks taken on behalf of a client
+// request are reported with the request ID.
+func TestListLocksRequestID(t *testing.T) {
+	isDistXL := false
+	initNSLock(isDistXL)
+
+	req, err := http.NewRequest("GET", "http://127.0.0.1:9000/bucket1/obj1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), requestIDContextKey{}, "14F2D1C8A5B3E0010001"))
+
+	// Take a write lock for the request, and a read lock not
+	// taken on behalf of any request.
+	wrLk := globalNSMutex.NewRequestNSLock(req, "bucket1", "obj1")
+	wrLk.Lock()
+	defer wrLk.Unlock()
+	readLk := globalNSMutex.NewNSLock("bucket1", "obj2")
+	readLk.RLock()
+	defer readLk.RUnlock()
+
+	testCases := []struct {
+		object    string
+		requestID string
+	}{
+		// Test 1 - Lock taken for the request.
+		{"obj1", "14F2D1C8A5B3E0010001"},
+		// Test 2 - Lock taken outside of a request.
+		{"obj2", ""},
+	}
+
+	for i, testCase := range testCases {
+		locks := listLocksInfo("bucket1", testCase.object, 0)
+		if len(locks) != 1 || len(locks[0].LockDetailsOnObject) != 1 {
+			t.Fatalf("Test %d: Expected a single lock on %s, got %v", i+1, testCase.object, locks)
+		}
+		if requestID := locks[0].LockDetailsOnObject[0].RequestID; requestID != testCase.requestID {
+			t.Errorf("Test %d: Expected request ID \"%s\", got \"%s\"", i+1, testCase.requestID, requestID)
+		}
+	}
+
+	// Stale lock reports carry the request ID as well.
+	staleLocks := globalNSMutex.findStaleLocks(0, UTCNow().Add(time.Second))
+	var found bool
+	for _, staleLock := range staleLocks {
+		if staleLock.path == "obj1" {
+			found = strings.Contains(staleLock.Error(), "<requestID> 14F2D1C8A5B3E0010001")
+		}
+	}
+	if !found {
+		t.Errorf("Expected stale lock report with request ID, got %v", staleLocks)
+	}
+}
@@ -0,0 +1,282 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Log streams, each kind of message emitted by the server belongs to
+// exactly one stream and every stream can be routed independently.
+const (
+	// Startup banner and other informational messages.
+	logStreamStartup = "startup"
+	// Operational warnings and errors.
+	logStreamWarning = "warning"
+	// One entry per API request, disabled unless configured.
+	logStreamAudit = "audit"
+	// Debugging messages.
+	logStreamDebug = "debug"
+)
+
+// Supported log stream targets.
+const (
+	logStreamTargetOff    = "off"
+	logStreamTargetStdout = "stdout"
+	logStreamTargetStderr = "stderr"
+	logStreamTargetFile   = "file"
+	logStreamTargetSyslog = "syslog"
+)
+
+// logStreamField - entry field carrying the stream name of an entry
+// logged explicitly to a stream.
+const logStreamField = "stream"
+
+// logStreamConfig - routing configuration of a log stream.
+type logStreamConfig struct {
+	// One of "off", "stdout", "stderr", "file" or "syslog".
+	Target string `json:"target"`
+	// Minimum level of entries to log, defaults to the natural
+	// level of the stream when empty.
+	Level string `json:"level,omitempty"`
+	// Filename for "file" target.
+	Filename string `json:"filename,omitempty"`
+	// Network ("udp", "tcp", "unix" or "unixgram"), address and
+	// facility for "syslog" target.
+	Network  string `json:"network,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Facility string `json:"facility,omitempty"`
+}
+
+// Validate - validates log stream configuration.
+func (c logStreamConfig) Validate() error {
+	switch c.Target {
+	case logStreamTargetOff, logStreamTargetStdout, logStreamTargetStderr:
+	case logStreamTargetFile:
+		if c.Filename == "" {
+			return fmt.Errorf("Missing filename for file target")
+		}
+	case logStreamTargetSyslog:
+		if err := validateSyslogConfig(c.Network, c.Address, c.Facility); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unknown target ‘%s’", c.Target)
+	}
+	if c.Level != "" {
+		if _, err := logrus.ParseLevel(c.Level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logStreams - routing configuration of log streams indexed by stream
+// name, streams not present here keep their default routing.
+type logStreams map[string]logStreamConfig
+
+// Validate - validates configuration of all log streams.
+func (s logStreams) Validate() error {
+	for name, cfg := range s {
+		switch name {
+		case logStreamStartup, logStreamWarning, logStreamAudit, logStreamDebug:
+		default:
+			return fmt.Errorf("Unknown log stream ‘%s’", name)
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("Invalid log stream ‘%s’: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// getLogStreamDefaultLevel - returns the level used for a stream
+// configured without an explicit level.
+func getLogStreamDefaultLevel(name string) logrus.Level {
+	switch name {
+	case logStreamWarning:
+		return logrus.WarnLevel
+	case logStreamDebug:
+		return logrus.DebugLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// logStream - log stream routed to a single output.
+type logStream struct {
+	sync.Mutex
+	target    string
+	level     logrus.Level
+	formatter logrus.Formatter
+	out       io.Writer
+}
+
+// newLogStream - initializes a log stream from its configuration,
+// opens the log file for "file" target.
+func newLogStream(name string, cfg logStreamConfig) (*logStream, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	stream := &logStream{
+		target:    cfg.Target,
+		level:     getLogStreamDefaultLevel(name),
+		formatter: new(logrus.TextFormatter),
+	}
+	if cfg.Level != "" {
+		stream.level, _ = logrus.ParseLevel(cfg.Level)
+	}
+
+	switch cfg.Target {
+	case logStreamTargetOff:
+		stream.out = ioutil.Discard
+	case logStreamTargetStdout:
+		stream.out = os.Stdout
+	case logStreamTargetStderr:
+		stream.out = os.Stderr
+	case logStreamTargetFile:
+		file, err := os.OpenFile(cfg.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0664)
+		if err != nil {
+			return nil, err
+		}
+		stream.formatter = new(logrus.JSONFormatter)
+		stream.out = file
+	case logStreamTargetSyslog:
+		stream.formatter = newSyslogFormatter(cfg.Facility)
+		stream.out = newSyslogWriter(cfg.Network, cfg.Address)
+	}
+
+	return stream, nil
+}
+
+// Fire - writes entry to the stream output, entries less severe than
+// the stream level are dropped.
+func (s *logStream) Fire(entry *logrus.Entry) error {
+	if entry.Level > s.level || s.target == logStreamTargetOff {
+		return nil
+	}
+
+	msgBytes, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	_, err = s.out.Write(msgBytes)
+	return err
+}
+
+// Print - writes unformatted text to the stream output, text is sent
+// as message of an informational entry to syslog target.
+func (s *logStream) Print(text string) {
+	if s.target == logStreamTargetOff {
+		return
+	}
+
+	if s.target == logStreamTargetSyslog {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		s.Fire(&logrus.Entry{
+			Time:    UTCNow(),
+			Level:   logrus.InfoLevel,
+			Message: strings.TrimSpace(text),
+			Data:    logrus.Fields{logStreamField: logStreamStartup},
+		})
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	io.WriteString(s.out, text)
+}
+
+// String - represents log stream as string.
+func (s *logStream) String() string {
+	return fmt.Sprintf("stream:%s:%s", s.target, s.level)
+}
+
+// getEntryStream - returns the stream an entry belongs to.
+func getEntryStream(entry *logrus.Entry) string {
+	if name, ok := entry.Data[logStreamField].(string); ok {
+		return name
+	}
+	if entry.Level == logrus.DebugLevel {
+		return logStreamDebug
+	}
+	return logStreamWarning
+}
+
+// auditLog - logs an entry to the audit stream, entries are only
+// generated when the audit stream is routed to a target.
+func auditLog(fields logrus.Fields, msg string, data ...interface{}) {
+	if !log.isStreamEnabled(logStreamAudit) {
+		return
+	}
+
+	fields[logStreamField] = logStreamAudit
+	log.logger.WithFields(fields).Infof(msg, data...)
+}
+
+// auditHandler logs every API request to the audit stream.
+type auditHandler struct {
+	handler http.Handler
+}
+
+// setAuditHandler sets the audit log handler.
+func setAuditHandler(h http.Handler) http.Handler {
+	return auditHandler{handler: h}
+}
+
+func (h auditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !log.isStreamEnabled(logStreamAudit) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	ww := &httpResponseRecorder{ResponseWriter: w}
+	tBefore := UTCNow()
+	h.handler.ServeHTTP(ww, r)
+
+	statusCode := ww.respStatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	auditLog(logrus.Fields{
+		"api":        r.Method,
+		"bucket":     bucket,
+		"object":     object,
+		"requestID":  w.Header().Get(responseRequestIDKey),
+		"accessKey":  getRequestAccessKey(r),
+		"remoteHost": r.RemoteAddr,
+		"status":     statusCode,
+		"duration":   UTCNow().Sub(tBefore).String(),
+		"clock":      globalHLC.Now().String(),
+	}, "%s %s", r.Method, r.URL.Path)
+}
@@ -0,0 +1,215 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests validating log streams configuration.
+func TestLogStreamsValidate(t *testing.T) {
+	testCases := []struct {
+		streams    logStreams
+		shouldPass bool
+	}{
+		{nil, true},
+		{logStreams{logStreamStartup: {Target: logStreamTargetOff}}, true},
+		{logStreams{logStreamWarning: {Target: logStreamTargetStderr, Level: "error"}}, true},
+		{logStreams{logStreamAudit: {Target: logStreamTargetFile, Filename: "/tmp/audit.log"}}, true},
+		{logStreams{logStreamDebug: {Target: logStreamTargetStdout, Level: "debug"}}, true},
+		// Unknown stream.
+		{logStreams{"access": {Target: logStreamTargetStdout}}, false},
+		// Unknown target.
+		{logStreams{logStreamAudit: {Target: "network"}}, false},
+		// Missing filename.
+		{logStreams{logStreamAudit: {Target: logStreamTargetFile}}, false},
+		// Unknown level.
+		{logStreams{logStreamWarning: {Target: logStreamTargetStderr, Level: "verbose"}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.streams.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// Tests entries are dispatched to the stream they belong to.
+func TestGetEntryStream(t *testing.T) {
+	testCases := []struct {
+		entry          *logrus.Entry
+		expectedStream string
+	}{
+		{&logrus.Entry{Level: logrus.ErrorLevel, Data: logrus.Fields{}}, logStreamWarning},
+		{&logrus.Entry{Level: logrus.FatalLevel, Data: logrus.Fields{}}, logStreamWarning},
+		{&logrus.Entry{Level: logrus.DebugLevel, Data: logrus.Fields{}}, logStreamDebug},
+		{&logrus.Entry{Level: logrus.InfoLevel, Data: logrus.Fields{logStreamField: logStreamAudit}}, logStreamAudit},
+	}
+
+	for i, testCase := range testCases {
+		if stream := getEntryStream(testCase.entry); stream != testCase.expectedStream {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expectedStream, stream)
+		}
+	}
+}
+
+// Tests routing log streams to files and levels filtering.
+func TestLoggerStreams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-log-streams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	startupFile := filepath.Join(dir, "startup.log")
+	warningFile := filepath.Join(dir, "warning.log")
+	auditFile := filepath.Join(dir, "audit.log")
+
+	l := NewLogger()
+	if l.isStreamEnabled(logStreamAudit) {
+		t.Fatal("audit stream should be disabled by default")
+	}
+	if !l.isStreamEnabled(logStreamStartup) || !l.isStreamEnabled(logStreamWarning) {
+		t.Fatal("startup and warning streams should be enabled by default")
+	}
+
+	if err = l.SetStreams(logStreams{
+		logStreamStartup: {Target: logStreamTargetFile, Filename: startupFile},
+		logStreamWarning: {Target: logStreamTargetFile, Filename: warningFile, Level: "error"},
+		logStreamAudit:   {Target: logStreamTargetFile, Filename: auditFile},
+		logStreamDebug:   {Target: logStreamTargetOff},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !l.isStreamEnabled(logStreamAudit) {
+		t.Fatal("audit stream should be enabled")
+	}
+	if l.isStreamEnabled(logStreamDebug) {
+		t.Fatal("debug stream should be disabled")
+	}
+
+	l.Println("Endpoint: http://127.0.0.1:9000")
+	l.logger.WithField("cause", "disk full").Error("Unable to write object")
+	l.logger.WithField("cause", "slow disk").Warn("Disk is slow")
+	l.logger.WithField("cause", "lock").Debug("Lock acquired")
+	l.logger.WithField(logStreamField, logStreamAudit).Info("GET /bucket/object")
+
+	readFile := func(name string) string {
+		data, rerr := ioutil.ReadFile(name)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		return string(data)
+	}
+
+	if data := readFile(startupFile); data != "Endpoint: http://127.0.0.1:9000\n" {
+		t.Errorf("Unexpected startup stream content %q", data)
+	}
+	warnings := readFile(warningFile)
+	if !strings.Contains(warnings, "Unable to write object") {
+		t.Errorf("Expected error in warning stream, got %q", warnings)
+	}
+	if strings.Contains(warnings, "Disk is slow") || strings.Contains(warnings, "Lock acquired") {
+		t.Errorf("Unexpected entries below stream level in warning stream %q", warnings)
+	}
+	audit := readFile(auditFile)
+	if !strings.Contains(audit, "GET /bucket/object") || strings.Contains(audit, "Unable to write object") {
+		t.Errorf("Unexpected audit stream content %q", audit)
+	}
+
+	// Invalid stream configuration is rejected.
+	if err = l.SetStreams(logStreams{logStreamAudit: {Target: logStreamTargetFile}}); err == nil {
+		t.Fatal("Expected invalid stream configuration to fail")
+	}
+}
+
+// Tests quiet option turns off startup stream.
+func TestLoggerQuiet(t *testing.T) {
+	l := NewLogger()
+	l.EnableQuiet()
+	if l.isStreamEnabled(logStreamStartup) {
+		t.Fatal("startup stream should be disabled in quiet mode")
+	}
+
+	// Quiet option takes precedence over configuration.
+	if err := l.SetStreams(logStreams{logStreamStartup: {Target: logStreamTargetStdout}}); err != nil {
+		t.Fatal(err)
+	}
+	if l.isStreamEnabled(logStreamStartup) {
+		t.Fatal("startup stream should stay disabled in quiet mode")
+	}
+}
+
+// Tests audit handler logs API requests to the audit stream.
+func TestAuditHandler(t *testing.T) {
+	auditBuf := &bytes.Buffer{}
+	savedStreams, savedHooks := log.streams, log.logger.Hooks
+	defer func() {
+		log.streams, log.logger.Hooks = savedStreams, savedHooks
+	}()
+
+	// Hooks are disabled for all tests, enable only this logger.
+	log.logger.Hooks = make(logrus.LevelHooks)
+	log.logger.Hooks.Add(log)
+
+	h := setAuditHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(responseRequestIDKey, "3L137")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	// Audit stream is disabled, no entries are logged.
+	log.streams = map[string]*logStream{}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/bucket/object", nil))
+
+	log.streams = map[string]*logStream{
+		logStreamAudit: {
+			target:    logStreamTargetStdout,
+			level:     logrus.InfoLevel,
+			formatter: new(logrus.JSONFormatter),
+			out:       auditBuf,
+		},
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/bucket/object", nil))
+
+	entry := auditBuf.String()
+	for _, expected := range []string{`"bucket":"bucket"`, `"object":"object"`, `"requestID":"3L137"`, `"status":404`, `"api":"GET"`} {
+		if !strings.Contains(entry, expected) {
+			t.Errorf("Expected %s in audit entry %s", expected, entry)
+		}
+	}
+	if strings.Count(entry, "\n") != 1 {
+		t.Errorf("Expected a single audit entry, got %s", entry)
+	}
+
+	// auditLog is a no-op for disabled audit stream.
+	log.streams = map[string]*logStream{}
+	auditLog(map[string]interface{}{}, "%s", errors.New("ignored"))
+}
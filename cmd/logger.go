@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"os"
 	"path"
 	"runtime"
 	"strings"
@@ -35,6 +37,9 @@ type loggers struct {
 	sync.RWMutex
 	Console ConsoleLogger `json:"console"`
 	File    FileLogger    `json:"file"`
+
+	// Routing of individual log streams.
+	Streams logStreams `json:"streams,omitempty"`
 }
 
 // Validate - Check whether loggers are valid or not.
@@ -43,6 +48,8 @@ func (l *loggers) Validate() (err error) {
 		fileLogger := l.GetFile()
 		if fileLogger.Enable && fileLogger.Filename == "" {
 			err = errors.New("Missing filename for enabled file logger")
+		} else {
+			err = l.GetStreams().Validate()
 		}
 	}
 
@@ -77,6 +84,20 @@ func (l *loggers) GetConsole() ConsoleLogger {
 	return l.Console
 }
 
+// SetStreams set new log streams routing.
+func (l *loggers) SetStreams(streams logStreams) {
+	l.Lock()
+	defer l.Unlock()
+	l.Streams = streams
+}
+
+// GetStreams get current log streams routing.
+func (l *loggers) GetStreams() logStreams {
+	l.RLock()
+	defer l.RUnlock()
+	return l.Streams
+}
+
 // LogTarget - interface for log target.
 type LogTarget interface {
 	Fire(entry *logrus.Entry) error
@@ -95,6 +116,11 @@ type Logger struct {
 	consoleTarget ConsoleLogger
 	targets       []LogTarget
 	quiet         bool
+
+	// Log streams routed explicitly, streams absent here keep
+	// their default routing.
+	streamsMu sync.RWMutex
+	streams   map[string]*logStream
 }
 
 // AddTarget - add logger to this hook.
@@ -107,8 +133,60 @@ func (log *Logger) SetConsoleTarget(consoleTarget ConsoleLogger) {
 	log.consoleTarget = consoleTarget
 }
 
+// SetStreams - routes log streams as configured, startup stream stays
+// off when quiet option is set.
+func (log *Logger) SetStreams(cfg logStreams) error {
+	streams := make(map[string]*logStream)
+	for name, streamCfg := range cfg {
+		stream, err := newLogStream(name, streamCfg)
+		if err != nil {
+			return fmt.Errorf("Invalid log stream ‘%s’: %s", name, err)
+		}
+		streams[name] = stream
+	}
+	if log.quiet {
+		streams[logStreamStartup] = &logStream{target: logStreamTargetOff}
+	}
+
+	log.streamsMu.Lock()
+	log.streams = streams
+	log.streamsMu.Unlock()
+	return nil
+}
+
+// getStream - returns the log stream if it is routed explicitly.
+func (log *Logger) getStream(name string) (*logStream, bool) {
+	log.streamsMu.RLock()
+	defer log.streamsMu.RUnlock()
+	stream, ok := log.streams[name]
+	return stream, ok
+}
+
+// isStreamEnabled - returns if entries of a stream are written
+// anywhere, audit stream is disabled by default.
+func (log *Logger) isStreamEnabled(name string) bool {
+	stream, ok := log.getStream(name)
+	if !ok {
+		return name != logStreamAudit
+	}
+	return stream.target != logStreamTargetOff
+}
+
 // Fire - log entry handler to save logs.
 func (log *Logger) Fire(entry *logrus.Entry) (err error) {
+	name := getEntryStream(entry)
+	if stream, ok := log.getStream(name); ok {
+		if err = stream.Fire(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to log to %s stream. %s\n", name, err)
+		}
+		return err
+	}
+
+	// Audit entries are never logged to default targets.
+	if name == logStreamAudit {
+		return nil
+	}
+
 	if err = log.consoleTarget.Fire(entry); err != nil {
 		log.Printf("Unable to log to console target. %s\n", err)
 	}
@@ -134,23 +212,36 @@ func (log *Logger) Levels() []logrus.Level {
 	}
 }
 
-// EnableQuiet - sets quiet option.
+// EnableQuiet - sets quiet option, turns off the startup stream.
 func (log *Logger) EnableQuiet() {
 	log.quiet = true
+
+	log.streamsMu.Lock()
+	defer log.streamsMu.Unlock()
+	if log.streams == nil {
+		log.streams = make(map[string]*logStream)
+	}
+	log.streams[logStreamStartup] = &logStream{target: logStreamTargetOff}
 }
 
-// Println - wrapper to console.Println() with quiet flag.
+// Println - prints to the startup stream, wrapper to console.Println()
+// unless the startup stream is routed explicitly.
 func (log *Logger) Println(args ...interface{}) {
-	if !log.quiet {
-		console.Println(args...)
+	if stream, ok := log.getStream(logStreamStartup); ok {
+		stream.Print(fmt.Sprintln(args...))
+		return
 	}
+	console.Println(args...)
 }
 
-// Printf - wrapper to console.Printf() with quiet flag.
+// Printf - prints to the startup stream, wrapper to console.Printf()
+// unless the startup stream is routed explicitly.
 func (log *Logger) Printf(format string, args ...interface{}) {
-	if !log.quiet {
-		console.Printf(format, args...)
+	if stream, ok := log.getStream(logStreamStartup); ok {
+		stream.Print(fmt.Sprintf(format, args...))
+		return
 	}
+	console.Printf(format, args...)
 }
 
 // NewLogger - returns a new initialized logger.
@@ -184,7 +275,7 @@ func getSource() string {
 	return fmt.Sprintf("[%s:%d:%s()]", filename, lineNum, funcName)
 }
 
-func logIf(level logrus.Level, source string, err error, msg string, data ...interface{}) {
+func logIf(level logrus.Level, source, requestID string, err error, msg string, data ...interface{}) {
 	isErrIgnored := func(err error) (ok bool) {
 		err = errorCause(err)
 		switch err.(type) {
@@ -205,6 +296,9 @@ func logIf(level logrus.Level, source string, err error, msg string, data ...int
 		"source": source,
 		"cause":  err.Error(),
 	}
+	if requestID != "" {
+		fields["requestID"] = requestID
+	}
 
 	if terr, ok := err.(*Error); ok {
 		fields["stack"] = strings.Join(terr.Trace(), " ")
@@ -227,9 +321,15 @@ func logIf(level logrus.Level, source string, err error, msg string, data ...int
 }
 
 func errorIf(err error, msg string, data ...interface{}) {
-	logIf(logrus.ErrorLevel, getSource(), err, msg, data...)
+	logIf(logrus.ErrorLevel, getSource(), "", err, msg, data...)
+}
+
+// reqErrorIf - similar to errorIf, logs the ID of the request which
+// failed along with the error.
+func reqErrorIf(r *http.Request, err error, msg string, data ...interface{}) {
+	logIf(logrus.ErrorLevel, getSource(), getRequestID(r), err, msg, data...)
 }
 
 func fatalIf(err error, msg string, data ...interface{}) {
-	logIf(logrus.FatalLevel, getSource(), err, msg, data...)
+	logIf(logrus.FatalLevel, getSource(), "", err, msg, data...)
 }
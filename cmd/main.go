@@ -100,6 +100,8 @@ func newApp() *cli.App {
 	registerCommand(versionCmd)
 	registerCommand(updateCmd)
 	registerCommand(gatewayCmd)
+	registerCommand(benchmarkCmd)
+	registerCommand(controlCmd)
 
 	// Set up app.
 	cli.HelpFlag = cli.BoolFlag{
@@ -0,0 +1,123 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+
+	router "github.com/gorilla/mux"
+)
+
+// Prometheus metrics endpoint.
+const prometheusMetricsPath = minioReservedBucketPath + "/prometheus/metrics"
+
+// registerMetricsRouter - registers metrics endpoints.
+func registerMetricsRouter(mux *router.Router) {
+	mux.Methods("GET").Path(prometheusMetricsPath).HandlerFunc(prometheusMetricsHandler)
+}
+
+// boolToGauge - returns gauge value of a boolean state.
+func boolToGauge(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writePrometheusGauge - writes a gauge in Prometheus text format.
+func writePrometheusGauge(buf *bytes.Buffer, name, help string, value int) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(buf, "%s %d\n", name, value)
+}
+
+// writePrometheusCounter - writes a counter in Prometheus text format.
+func writePrometheusCounter(buf *bytes.Buffer, name, help string, value uint64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", name)
+	fmt.Fprintf(buf, "%s %d\n", name, value)
+}
+
+// encodeClusterState - encodes cluster state gauges in Prometheus
+// text format.
+func encodeClusterState(state clusterState) []byte {
+	var buf bytes.Buffer
+	writePrometheusGauge(&buf, "minio_cluster_writeable",
+		"Set to 1 if the cluster accepts writes, 0 otherwise.", boolToGauge(state.Writeable))
+	writePrometheusGauge(&buf, "minio_quorum_ok",
+		"Set to 1 if enough disks are online for reads, 0 otherwise.", boolToGauge(state.QuorumOK))
+	writePrometheusGauge(&buf, "minio_disks_offline_count",
+		"Number of disks which are offline.", state.DisksOffline)
+	writePrometheusGauge(&buf, "minio_heal_backlog",
+		"Number of buckets and objects which need healing.", state.HealBacklog)
+	return buf.Bytes()
+}
+
+// encodeServerCounters - encodes counters of this server in
+// Prometheus text format.
+func encodeServerCounters() []byte {
+	var buf bytes.Buffer
+	writePrometheusCounter(&buf, "minio_panics_total",
+		"Number of panics recovered while serving requests.", globalPanicCount.Load())
+	writePrometheusCounter(&buf, "minio_lock_timeouts_total",
+		"Number of locks not acquired before the deadline of their request.", uint64(getTimedOutLocks()))
+	writePrometheusCounter(&buf, "minio_read_failovers_total",
+		"Number of reads which failed on a disk and were served from the remaining disks.", globalReadFailovers.count.Load())
+	poolsStats := getWorkerPoolsStats()
+	var names []string
+	for name := range poolsStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stats := poolsStats[name]
+		writePrometheusGauge(&buf, "minio_"+name+"_workers_busy",
+			"Number of "+name+" workers running a task.", int(stats.Busy))
+		writePrometheusGauge(&buf, "minio_"+name+"_queue_length",
+			"Number of "+name+" tasks waiting for a free worker.", stats.Queued)
+		writePrometheusCounter(&buf, "minio_"+name+"_tasks_rejected_total",
+			"Number of "+name+" tasks not queued because the queue was full.", stats.Rejected)
+	}
+	return buf.Bytes()
+}
+
+// prometheusMetricsHandler - GET /minio/prometheus/metrics
+// ----------
+// Returns the last evaluated cluster state gauges along with counters
+// of this server in Prometheus text format. Requests need a JWT bearer
+// token signed with the server credentials unless
+// MINIO_PROMETHEUS_AUTH_TYPE is "public".
+func prometheusMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !globalIsPrometheusPublic && !isHTTPRequestValid(r) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
+	state, ok := globalHealthEvaluator.getState()
+	if !ok {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write(encodeClusterState(state))
+	w.Write(encodeServerCounters())
+}
@@ -0,0 +1,130 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests encoding of cluster state gauges.
+func TestEncodeClusterState(t *testing.T) {
+	state := clusterState{Writeable: false, QuorumOK: true, DisksOffline: 3, HealBacklog: 42}
+	expected := []string{
+		"# TYPE minio_cluster_writeable gauge\nminio_cluster_writeable 0\n",
+		"# TYPE minio_quorum_ok gauge\nminio_quorum_ok 1\n",
+		"# TYPE minio_disks_offline_count gauge\nminio_disks_offline_count 3\n",
+		"# TYPE minio_heal_backlog gauge\nminio_heal_backlog 42\n",
+	}
+	encoded := string(encodeClusterState(state))
+	for _, gauge := range expected {
+		if !strings.Contains(encoded, gauge) {
+			t.Errorf("Expected %q in encoded cluster state:\n%s", gauge, encoded)
+		}
+	}
+}
+
+// Tests encoding of server counters.
+func TestEncodeServerCounters(t *testing.T) {
+	expected := fmt.Sprintf("# TYPE minio_panics_total counter\nminio_panics_total %d\n", globalPanicCount.Load())
+	if encoded := string(encodeServerCounters()); !strings.Contains(encoded, expected) {
+		t.Errorf("Expected %q in encoded server counters:\n%s", expected, encoded)
+	}
+}
+
+// Tests authentication and response of the metrics endpoint.
+func TestPrometheusMetricsHandler(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(fsDir)
+
+	savedEvaluator := globalHealthEvaluator
+	defer func() {
+		globalHealthEvaluator = savedEvaluator
+		globalIsPrometheusPublic = false
+	}()
+
+	mux := router.NewRouter()
+	registerMetricsRouter(mux)
+
+	creds := serverConfig.GetCredential()
+	token, err := authenticateNode(creds.AccessKey, creds.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		token              string
+		isPublic           bool
+		isEvaluated        bool
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Unauthenticated request is rejected.
+		{"", false, true, http.StatusForbidden},
+		// Test case - 2.
+		// Invalid token is rejected.
+		{"invalid", false, true, http.StatusForbidden},
+		// Test case - 3.
+		// Valid token is accepted.
+		{token, false, true, http.StatusOK},
+		// Test case - 4.
+		// Unauthenticated request is accepted when public.
+		{"", true, true, http.StatusOK},
+		// Test case - 5.
+		// Cluster state not evaluated yet.
+		{token, false, false, http.StatusServiceUnavailable},
+	}
+
+	for i, testCase := range testCases {
+		globalIsPrometheusPublic = testCase.isPublic
+		globalHealthEvaluator = newHealthEvaluator(func() ObjectLayer { return obj }, defaultHealthEvalInterval)
+		if testCase.isEvaluated {
+			globalHealthEvaluator.evaluate()
+		}
+
+		req, err := newTestRequest("GET", prometheusMetricsPath, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code == http.StatusOK && !strings.Contains(rec.Body.String(), "minio_cluster_writeable 1\n") {
+			t.Errorf("Test %d: Expected writeable cluster in response, found %s", i+1, rec.Body.String())
+		}
+	}
+}
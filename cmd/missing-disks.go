@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interval between two checks of disks missing at startup.
+const missingDisksCheckInterval = 10 * time.Second
+
+// missingDisks - set when an erasure coded server starts while some
+// of its formatted disks are unreachable. Writes are rejected until
+// the disks are back or the start is confirmed, so that two halves of
+// a cluster started apart can't both accept writes and diverge. Not
+// set when the server is started with --force-degraded.
+type missingDisks struct {
+	mu     sync.RWMutex
+	disks  []string
+	locked bool
+}
+
+// isLocked - returns true if writes are rejected for disks missing
+// since startup.
+func (m *missingDisks) isLocked() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.locked
+}
+
+// getDisks - returns disks missing at startup while writes are
+// rejected, nil otherwise.
+func (m *missingDisks) getDisks() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.locked {
+		return nil
+	}
+	return append([]string(nil), m.disks...)
+}
+
+// lock - rejects writes until disks are back or the start is
+// confirmed.
+func (m *missingDisks) lock(disks []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disks = disks
+	m.locked = true
+}
+
+// confirm - accepts writes with disks missing, returns the disks
+// which were missing, nil if writes were already accepted.
+func (m *missingDisks) confirm() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.locked {
+		return nil
+	}
+	m.locked = false
+	log.Printf("Start with %d missing disks confirmed, write operations are enabled.\n", len(m.disks))
+	return m.disks
+}
+
+// check - accepts writes again once all disks of the object layer are
+// online.
+func (m *missingDisks) check(objAPI ObjectLayer) {
+	if !m.isLocked() {
+		return
+	}
+	storageInfo := objAPI.StorageInfo()
+	if storageInfo.Backend.Type != Erasure || storageInfo.Backend.OfflineDisks > 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked {
+		m.locked = false
+		log.Println("All disks missing at startup are online, write operations are enabled.")
+	}
+}
+
+// run - checks disks at every interval until they are all online or
+// doneCh is closed.
+func (m *missingDisks) run(objAPI func() ObjectLayer, interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for m.isLocked() {
+		select {
+		case <-ticker.C:
+			if objLayer := objAPI(); objLayer != nil {
+				m.check(objLayer)
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// getMissingDisks - returns endpoints of disks which were unreachable
+// while loading their format.
+func getMissingDisks(endpoints EndpointList, sErrs []error) []string {
+	var disks []string
+	for i, sErr := range sErrs {
+		switch errorCause(sErr) {
+		case errDiskNotFound, errFaultyDisk, errFaultyRemoteDisk:
+			disks = append(disks, endpoints[i].String())
+		}
+	}
+	return disks
+}
+
+// getMissingDisksMsg - returns the message printed when a server
+// starts with disks missing.
+func getMissingDisksMsg(disks []string) string {
+	msg := fmt.Sprintf("%d disk(s) are missing, write operations are disabled until they are online:\n", len(disks))
+	for _, disk := range disks {
+		msg += fmt.Sprintf("  %s\n", disk)
+	}
+	msg += "If these disks are gone for good, make sure no other servers are serving the same setup without them, " +
+		"then restart with --force-degraded or confirm the start with the ConfirmMissingDisks admin API."
+	return strings.TrimSpace(msg)
+}
+
+// checkMissingDisksAtStart - rejects writes if formatted disks are
+// missing when the server starts, unless started with
+// --force-degraded.
+func checkMissingDisksAtStart(endpoints EndpointList, storageDisks []StorageAPI) {
+	_, sErrs := loadAllFormats(storageDisks)
+	disks := getMissingDisks(endpoints, sErrs)
+	if len(disks) == 0 {
+		return
+	}
+	if globalIsForceDegraded {
+		log.Printf("Starting with %d missing disk(s) as forced: %s\n", len(disks), strings.Join(disks, ", "))
+		return
+	}
+	globalMissingDisks.lock(disks)
+	log.Println(colorBlue(getMissingDisksMsg(disks)))
+}
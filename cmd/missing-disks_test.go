@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+)
+
+// Tests writes are rejected when disks are missing at startup until
+// they are online or the start is confirmed.
+func TestMissingDisksAtStart(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints := mustGetNewEndpointList(fsDirs...)
+	storageDisks, err := initStorageDisks(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(m *missingDisks) { globalMissingDisks = m }(globalMissingDisks)
+	defer func() { globalIsForceDegraded = false }()
+
+	testCases := []struct {
+		forceDegraded bool
+		offline       []int
+		expectedDisks []string
+	}{
+		// All disks online.
+		{false, nil, nil},
+		// Two disks missing.
+		{false, []int{1, 5}, []string{endpoints[1].String(), endpoints[5].String()}},
+		// Two disks missing, forced.
+		{true, []int{1, 5}, nil},
+	}
+
+	for i, testCase := range testCases {
+		globalMissingDisks = &missingDisks{}
+		globalIsForceDegraded = testCase.forceDegraded
+
+		disks := append([]StorageAPI(nil), storageDisks...)
+		for _, index := range testCase.offline {
+			disks[index] = nil
+		}
+		checkMissingDisksAtStart(endpoints, disks)
+
+		missing := globalMissingDisks.getDisks()
+		if len(missing) != len(testCase.expectedDisks) {
+			t.Fatalf("Test %d: expected missing disks %v, got %v", i+1, testCase.expectedDisks, missing)
+		}
+		for j := range missing {
+			if missing[j] != testCase.expectedDisks[j] {
+				t.Fatalf("Test %d: expected missing disks %v, got %v", i+1, testCase.expectedDisks, missing)
+			}
+		}
+
+		expectedErr, expectedMode := error(nil), serverModeReadWrite
+		if len(testCase.expectedDisks) > 0 {
+			expectedErr, expectedMode = errServerMissingDisks, serverModeMissingDisks
+		}
+		if err = checkServerWriteable(); err != expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, expectedErr, err)
+		}
+		if mode := getServerMode(); mode != expectedMode {
+			t.Fatalf("Test %d: expected mode %s, got %s", i+1, expectedMode, mode)
+		}
+
+		// All disks of the object layer are online, writes are
+		// accepted again.
+		globalMissingDisks.check(obj)
+		if err = checkServerWriteable(); err != nil {
+			t.Fatalf("Test %d: expected server to be writeable, got %s", i+1, err)
+		}
+	}
+}
+
+// Tests confirming a start with disks missing.
+func TestMissingDisksConfirm(t *testing.T) {
+	m := &missingDisks{}
+	if disks := m.confirm(); disks != nil {
+		t.Fatalf("Expected nothing to confirm, got %v", disks)
+	}
+
+	m.lock([]string{"http://server2:9000/export"})
+	if !m.isLocked() {
+		t.Fatal("Expected writes to be rejected")
+	}
+	if disks := m.confirm(); len(disks) != 1 || disks[0] != "http://server2:9000/export" {
+		t.Fatalf("Unexpected disks %v", disks)
+	}
+	if m.isLocked() || m.getDisks() != nil {
+		t.Fatal("Expected writes to be accepted")
+	}
+	// Confirming again is a no-op.
+	if disks := m.confirm(); disks != nil {
+		t.Fatalf("Expected nothing to confirm, got %v", disks)
+	}
+}
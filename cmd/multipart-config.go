@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// multipartConfig - multipart upload limits, zero values use the S3
+// defaults of 5MiB minimum part size and 10000 parts per upload.
+type multipartConfig struct {
+	// Minimum size of all parts except the last one, in bytes.
+	MinPartSize int64 `json:"minPartSize"`
+	// Maximum number of parts per upload, part numbers range from
+	// 1 to this value inclusive.
+	MaxParts int `json:"maxParts"`
+	// Cleanup of stale multipart uploads.
+	Cleanup multipartCleanupConfig `json:"cleanup"`
+}
+
+// multipartCleanupConfig - stale multipart uploads cleanup tunables,
+// cleanup is enabled by default.
+type multipartCleanupConfig struct {
+	// Disables aborting stale uploads.
+	Disable bool `json:"disable"`
+	// Age after which an upload is stale, in Go duration format
+	// e.g "72h". Defaults to 7 days when empty.
+	Expiry string `json:"expiry"`
+	// Only reports stale uploads without aborting them.
+	DryRun bool `json:"dryRun"`
+}
+
+// getExpiry - returns configured age of stale uploads.
+func (c multipartCleanupConfig) getExpiry() (time.Duration, error) {
+	if c.Expiry == "" {
+		return defaultStaleUploadExpiry, nil
+	}
+	expiry, err := time.ParseDuration(c.Expiry)
+	if err != nil || expiry <= 0 {
+		return 0, fmt.Errorf("Invalid multipart cleanup expiry value ‘%s’", c.Expiry)
+	}
+	return expiry, nil
+}
+
+// Validate - validates multipart config.
+func (m multipartConfig) Validate() error {
+	if m.MinPartSize < 0 || m.MinPartSize > globalMaxPartSize {
+		return fmt.Errorf("Invalid multipart minPartSize value ‘%d’, must be between 0 and %d", m.MinPartSize, int64(globalMaxPartSize))
+	}
+	if m.MaxParts < 0 || m.MaxParts > globalMaxPartID {
+		return fmt.Errorf("Invalid multipart maxParts value ‘%d’, must be between 0 and %d", m.MaxParts, globalMaxPartID)
+	}
+	_, err := m.Cleanup.getExpiry()
+	return err
+}
+
+// getMinPartSize - returns configured minimum part size.
+func getMinPartSize() int64 {
+	if serverConfig != nil {
+		if minPartSize := serverConfig.GetMultipart().MinPartSize; minPartSize > 0 {
+			return minPartSize
+		}
+	}
+	return globalMinPartSize
+}
+
+// getMaxParts - returns configured maximum number of parts.
+func getMaxParts() int {
+	if serverConfig != nil {
+		if maxParts := serverConfig.GetMultipart().MaxParts; maxParts > 0 {
+			return maxParts
+		}
+	}
+	return globalMaxPartID
+}
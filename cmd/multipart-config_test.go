@@ -0,0 +1,164 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Tests validating multipart config.
+func TestMultipartConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config     multipartConfig
+		shouldPass bool
+	}{
+		{multipartConfig{}, true},
+		{multipartConfig{MinPartSize: 1024, MaxParts: 100}, true},
+		{multipartConfig{MinPartSize: globalMaxPartSize, MaxParts: globalMaxPartID}, true},
+		{multipartConfig{MinPartSize: -1}, false},
+		{multipartConfig{MinPartSize: globalMaxPartSize + 1}, false},
+		{multipartConfig{MaxParts: -1}, false},
+		{multipartConfig{MaxParts: globalMaxPartID + 1}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// Tests configured multipart limits are used instead of defaults.
+func TestMultipartConfigLimits(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if getMinPartSize() != globalMinPartSize || getMaxParts() != globalMaxPartID {
+		t.Fatalf("Expected default limits, got %d, %d", getMinPartSize(), getMaxParts())
+	}
+
+	serverConfig.Multipart = multipartConfig{MinPartSize: 1024, MaxParts: 10}
+	if getMinPartSize() != 1024 || getMaxParts() != 10 {
+		t.Fatalf("Expected configured limits, got %d, %d", getMinPartSize(), getMaxParts())
+	}
+	if !isMinAllowedPartSize(1024) || isMinAllowedPartSize(1023) {
+		t.Error("Unexpected minimum part size check with configured limit")
+	}
+	if isMaxPartID(10) || !isMaxPartID(11) {
+		t.Error("Unexpected maximum part ID check with configured limit")
+	}
+}
+
+// Wrapper for calling multipart limits handler tests for both XL and FS.
+func TestAPIMultipartConfigLimits(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIMultipartConfigLimits, []string{"PutObjectPart", "CompleteMultipart"})
+}
+
+func testAPIMultipartConfigLimits(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	sizes := []int64{2048, 512}
+
+	// Uploads parts smaller than default minimum part size.
+	newUpload := func() (string, []CompletePart) {
+		uploadID, err := obj.NewMultipartUpload(bucketName, objectName, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		var parts []CompletePart
+		for i, size := range sizes {
+			partInfo, err := obj.PutObjectPart(bucketName, objectName, uploadID, i+1, size,
+				bytes.NewReader(bytes.Repeat([]byte("a"), int(size))), "", "")
+			if err != nil {
+				t.Fatalf("%s: %s", instanceType, err)
+			}
+			parts = append(parts, CompletePart{PartNumber: i + 1, ETag: partInfo.ETag})
+		}
+		return uploadID, parts
+	}
+
+	completeUpload := func(uploadID string, parts []CompletePart) *httptest.ResponseRecorder {
+		completeBytes, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getCompleteMultipartUploadURL("", bucketName, objectName, uploadID),
+			int64(len(completeBytes)), bytes.NewReader(completeBytes), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Lowered minimum part size accepts small parts.
+	serverConfig.Multipart = multipartConfig{MinPartSize: 1024}
+	uploadID, parts := newUpload()
+	if rec := completeUpload(uploadID, parts); rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected %d, got %d: %s", instanceType, http.StatusOK, rec.Code, rec.Body)
+	}
+
+	// Raised minimum part size rejects them with EntityTooSmall.
+	serverConfig.Multipart = multipartConfig{MinPartSize: 4096}
+	uploadID, parts = newUpload()
+	rec := completeUpload(uploadID, parts)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: expected %d, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	errResp := completeMultipartAPIError{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatal(err)
+	}
+	if errResp.Code != "EntityTooSmall" || errResp.ProposedSize != 2048 || errResp.MinSizeAllowed != 4096 ||
+		errResp.PartNumber != 1 || errResp.PartETag != parts[0].ETag {
+		t.Errorf("%s: unexpected error response %+v", instanceType, errResp)
+	}
+
+	// Lowered maximum parts rejects part numbers above it.
+	serverConfig.Multipart = multipartConfig{MinPartSize: 1024, MaxParts: 1}
+	rec = httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "2"),
+		4, bytes.NewReader([]byte("abcd")), credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: expected %d for part upload, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if rec = completeUpload(uploadID, parts); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: expected %d for complete upload, got %d", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(fmt.Sprintf("<Code>%s</Code>", getAPIError(ErrInvalidMaxParts).Code))) {
+		t.Errorf("%s: unexpected error response %s", instanceType, rec.Body)
+	}
+	serverConfig.Multipart = multipartConfig{}
+}
@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Default age after which a multipart upload is stale.
+	defaultStaleUploadExpiry = 7 * 24 * time.Hour
+
+	// Interval between two stale multipart uploads cleanups.
+	staleUploadsCleanupInterval = time.Hour
+
+	// Maximum uploads listed at once during cleanup.
+	staleUploadsListLimit = 1000
+)
+
+// ServerMultipartCleanupStats - stale multipart uploads cleanup
+// statistics of a server.
+type ServerMultipartCleanupStats struct {
+	LastRun time.Time `json:"lastRun"`
+	// Uploads aborted since server start.
+	Aborted int64 `json:"aborted"`
+	// Stale uploads found in dry-run mode since server start.
+	DryRun int64 `json:"dryRun"`
+	// Uploads which failed to be aborted since server start.
+	Failed int64 `json:"failed"`
+}
+
+// multipartJanitor - aborts multipart uploads older than the
+// configured expiry, releasing their temporary part files.
+type multipartJanitor struct {
+	sync.Mutex
+	stats ServerMultipartCleanupStats
+}
+
+// newMultipartJanitor - returns a new multipart janitor.
+func newMultipartJanitor() *multipartJanitor {
+	return &multipartJanitor{}
+}
+
+// getStats - returns cleanup statistics.
+func (j *multipartJanitor) getStats() ServerMultipartCleanupStats {
+	j.Lock()
+	defer j.Unlock()
+	return j.stats
+}
+
+// listStaleUploads - returns all uploads of bucket initiated before
+// the given time.
+func listStaleUploads(objAPI ObjectLayer, bucket string, initiatedBefore time.Time) ([]UploadMetadata, error) {
+	var staleUploads []UploadMetadata
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := objAPI.ListMultipartUploads(bucket, "", keyMarker, uploadIDMarker, "", staleUploadsListLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, upload := range result.Uploads {
+			if upload.Initiated.Before(initiatedBefore) {
+				staleUploads = append(staleUploads, upload)
+			}
+		}
+		if !result.IsTruncated {
+			return staleUploads, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// cleanup - aborts all uploads older than expiry, in dry-run mode
+// stale uploads are only reported.
+func (j *multipartJanitor) cleanup(objAPI ObjectLayer, expiry time.Duration, dryRun bool, now time.Time) {
+	buckets, err := objAPI.ListBuckets()
+	if err != nil {
+		errorIf(err, "Unable to list buckets for stale multipart uploads cleanup.")
+		return
+	}
+
+	var aborted, found, failed int64
+	for _, bucket := range buckets {
+		staleUploads, err := listStaleUploads(objAPI, bucket.Name, now.Add(-expiry))
+		if err != nil {
+			errorIf(err, "Unable to list multipart uploads of %s for cleanup.", bucket.Name)
+			continue
+		}
+
+		for _, upload := range staleUploads {
+			if dryRun {
+				log.Printf("Stale multipart upload %s of %s/%s initiated at %s would be aborted.\n",
+					upload.UploadID, bucket.Name, upload.Object, upload.Initiated)
+				found++
+				continue
+			}
+
+			err = objAPI.AbortMultipartUpload(bucket.Name, upload.Object, upload.UploadID)
+			switch errorCause(err).(type) {
+			case nil:
+				aborted++
+			case InvalidUploadID:
+				// Already aborted or completed concurrently,
+				// possibly by another server.
+			default:
+				errorIf(err, "Unable to abort stale multipart upload %s of %s/%s.", upload.UploadID, bucket.Name, upload.Object)
+				failed++
+			}
+		}
+	}
+
+	j.Lock()
+	defer j.Unlock()
+	j.stats.LastRun = now
+	j.stats.Aborted += aborted
+	j.stats.DryRun += found
+	j.stats.Failed += failed
+}
+
+// run - periodically cleans up stale multipart uploads as configured,
+// runs until doneCh is closed.
+func (j *multipartJanitor) run(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cfg := serverConfig.GetMultipart().Cleanup
+			if cfg.Disable {
+				continue
+			}
+			objAPI := newObjectLayerFn()
+			if objAPI == nil {
+				continue
+			}
+			// Expiry is validated while loading config.
+			expiry, _ := cfg.getExpiry()
+			j.cleanup(objAPI, expiry, cfg.DryRun, UTCNow())
+		case <-doneCh:
+			return
+		}
+	}
+}
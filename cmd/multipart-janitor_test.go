@@ -0,0 +1,116 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests validating multipart cleanup config.
+func TestMultipartCleanupConfigExpiry(t *testing.T) {
+	testCases := []struct {
+		config         multipartCleanupConfig
+		expectedExpiry time.Duration
+		shouldPass     bool
+	}{
+		{multipartCleanupConfig{}, defaultStaleUploadExpiry, true},
+		{multipartCleanupConfig{Expiry: "72h"}, 72 * time.Hour, true},
+		{multipartCleanupConfig{Expiry: "72h", DryRun: true}, 72 * time.Hour, true},
+		{multipartCleanupConfig{Expiry: "3 days"}, 0, false},
+		{multipartCleanupConfig{Expiry: "-1h"}, 0, false},
+		{multipartCleanupConfig{Expiry: "0s"}, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		expiry, err := testCase.config.getExpiry()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+		if expiry != testCase.expectedExpiry {
+			t.Errorf("Test %d: expected expiry %s, got %s", i+1, testCase.expectedExpiry, expiry)
+		}
+		if err = (multipartConfig{Cleanup: testCase.config}).Validate(); testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: unexpected multipart config validation result %v", i+1, err)
+		}
+	}
+}
+
+// Wrapper for calling stale multipart uploads cleanup tests for both XL and FS.
+func TestMultipartJanitorCleanup(t *testing.T) {
+	ExecObjectLayerTest(t, testMultipartJanitorCleanup)
+}
+
+func testMultipartJanitorCleanup(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	var uploadIDs []string
+	for _, object := range []string{"a", "b/c", "b/d"} {
+		uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		uploadIDs = append(uploadIDs, uploadID)
+	}
+
+	countUploads := func() int {
+		result, err := obj.ListMultipartUploads(bucket, "", "", "", "", 1000)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		return len(result.Uploads)
+	}
+
+	j := newMultipartJanitor()
+	now := UTCNow()
+
+	// Uploads younger than expiry are kept.
+	j.cleanup(obj, time.Hour, false, now)
+	if n := countUploads(); n != len(uploadIDs) {
+		t.Fatalf("%s: expected %d uploads, got %d", instanceType, len(uploadIDs), n)
+	}
+
+	// Dry run reports stale uploads without aborting them.
+	j.cleanup(obj, time.Hour, true, now.Add(2*time.Hour))
+	if n := countUploads(); n != len(uploadIDs) {
+		t.Fatalf("%s: expected %d uploads after dry run, got %d", instanceType, len(uploadIDs), n)
+	}
+	if stats := j.getStats(); stats.DryRun != int64(len(uploadIDs)) || stats.Aborted != 0 {
+		t.Fatalf("%s: unexpected stats after dry run %+v", instanceType, stats)
+	}
+
+	// Stale uploads are aborted.
+	j.cleanup(obj, time.Hour, false, now.Add(2*time.Hour))
+	if n := countUploads(); n != 0 {
+		t.Fatalf("%s: expected no uploads, got %d", instanceType, n)
+	}
+	stats := j.getStats()
+	if stats.Aborted != int64(len(uploadIDs)) || stats.Failed != 0 || !stats.LastRun.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("%s: unexpected stats %+v", instanceType, stats)
+	}
+	for i, uploadID := range uploadIDs {
+		if _, err := obj.ListObjectParts(bucket, []string{"a", "b/c", "b/d"}[i], uploadID, 0, 1000); err == nil {
+			t.Errorf("%s: expected upload %s to be aborted", instanceType, uploadID)
+		}
+	}
+}
@@ -18,29 +18,45 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	pathutil "path"
 	"sync"
-
-	"github.com/minio/dsync"
+	"sync/atomic"
+	"time"
 )
 
 // Global name space lock.
 var globalNSMutex *nsLockMap
 
 // RWLocker - locker interface extends sync.Locker
-// to introduce RLock, RUnlock.
+// to introduce RLock, RUnlock and locking before a deadline.
 type RWLocker interface {
 	sync.Locker
 	RLock()
 	RUnlock()
+	GetLock(deadline time.Time) error
+	GetRLock(deadline time.Time) error
+}
+
+// rwMutex - read/write mutex backing a namespace lock, a
+// sync.RWMutex or a distRWMutex in distributed setup.
+type rwMutex interface {
+	sync.Locker
+	RLock()
+	RUnlock()
 }
 
+// errLockTimedOut - returned when a lock can't be acquired before
+// the deadline of the request.
+var errLockTimedOut = errors.New("Timed out while waiting for a lock")
+
 // Initialize distributed locking only in case of distributed setup.
 // Returns if the setup is distributed or not on success.
 func initDsyncNodes() error {
 	cred := serverConfig.GetCredential()
 	// Initialize rpc lock client information only if this instance is a distributed setup.
-	clnts := make([]dsync.NetLocker, len(globalEndpoints))
+	clnts := make([]NetLocker, len(globalEndpoints))
 	myNode := -1
 	for index, endpoint := range globalEndpoints {
 		clnts[index] = newLockRPCClient(authConfig{
@@ -56,20 +72,88 @@ func initDsyncNodes() error {
 		}
 	}
 
-	return dsync.Init(clnts, myNode)
+	settings, err := serverConfig.GetDistLock().settings()
+	if err != nil {
+		return err
+	}
+	globalDistLocker, err = newDistLocker(clnts, myNode, settings)
+	return err
+}
+
+// lockConfig - distributed locking tunables, empty values use the
+// defaults. Durations are in Go duration format e.g "2s".
+type lockConfig struct {
+	// Time to wait for a quorum of lock grants in one attempt.
+	AcquireTimeout string `json:"acquireTimeout"`
+	// Base and maximum back-off time between two lock attempts.
+	RetryUnit string `json:"retryUnit"`
+	RetryCap  string `json:"retryCap"`
+	// Number of nodes which need to grant a write lock, must be
+	// a majority of the nodes.
+	Quorum int `json:"quorum"`
+}
+
+// parseLockDuration - parses an optional positive duration.
+func parseLockDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("Invalid lock %s value ‘%s’", name, value)
+	}
+	return d, nil
+}
+
+// lockSettings - parsed distributed locking tunables, zero values
+// use the defaults.
+type lockSettings struct {
+	acquireTimeout time.Duration
+	retryUnit      time.Duration
+	retryCap       time.Duration
+	quorum         int
+}
+
+// settings - parses lock config.
+func (l lockConfig) settings() (settings lockSettings, err error) {
+	if settings.acquireTimeout, err = parseLockDuration("acquireTimeout", l.AcquireTimeout); err != nil {
+		return settings, err
+	}
+	if settings.retryUnit, err = parseLockDuration("retryUnit", l.RetryUnit); err != nil {
+		return settings, err
+	}
+	if settings.retryCap, err = parseLockDuration("retryCap", l.RetryCap); err != nil {
+		return settings, err
+	}
+	if l.Quorum < 0 {
+		return settings, fmt.Errorf("Invalid lock quorum value ‘%d’", l.Quorum)
+	}
+	settings.quorum = l.Quorum
+	return settings, nil
+}
+
+// Validate - validates lock config, quorum is validated against the
+// number of nodes during distributed locking initialization.
+func (l lockConfig) Validate() error {
+	_, err := l.settings()
+	return err
 }
 
 // initNSLock - initialize name space lock map.
 func initNSLock(isDistXL bool) {
-	globalNSMutex = &nsLockMap{
+	globalNSMutex = newNSLockMap(isDistXL)
+}
+
+// newNSLockMap - returns an empty name space lock map.
+func newNSLockMap(isDistXL bool) *nsLockMap {
+	return &nsLockMap{
 		isDistXL: isDistXL,
+		fairness: globalLockFairness,
 		lockMap:  make(map[nsParam]*nsLock),
-		counters: &lockStat{},
+		// Initialize nsLockMap with shards for instrumentation information.
+		// Entries of <volume,path> -> stateInfo of locks
+		debugLockShards: newDebugLockShards(),
 	}
-
-	// Initialize nsLockMap with entry for instrumentation information.
-	// Entries of <volume,path> -> stateInfo of locks
-	globalNSMutex.debugLockMap = make(map[nsParam]*debugLockInfoPerVolumePath)
 }
 
 // nsParam - carries name space resource.
@@ -80,35 +164,68 @@ type nsParam struct {
 
 // nsLock - provides primitives for locking critical namespace regions.
 type nsLock struct {
-	RWLocker
+	rwMutex
 	ref uint
+	// Operations waiting for the lock, unused without fairness.
+	queue lockQueue
 }
 
 // nsLockMap - namespace lock map, provides primitives to Lock,
 // Unlock, RLock and RUnlock.
 type nsLockMap struct {
-	// Lock counter used for lock debugging.
-	counters     *lockStat
-	debugLockMap map[nsParam]*debugLockInfoPerVolumePath // Info for instrumentation on locks.
+	// Count of locks reported stale by the lock watchdog, updated
+	// atomically, kept first for 64-bit alignment.
+	staleLocks int64
+	// Count of locks not acquired before their deadline, updated
+	// atomically.
+	timedOutLocks int64
+
+	// Info for instrumentation on locks, sharded by (volume, path)
+	// so that it is updated without holding lockMapMutex.
+	debugLockShards [debugLockShardCount]*debugLockShard
+	// Deadlock chains found by the last run of deadlock detection.
+	deadlocks      [][]DeadlockOpsInfo
+	deadlocksMutex sync.Mutex
 
 	// Indicates if namespace is part of a distributed setup.
-	isDistXL     bool
+	isDistXL bool
+	// Order in which waiters are admitted to acquire locks.
+	fairness     lockFairness
 	lockMap      map[nsParam]*nsLock
 	lockMapMutex sync.Mutex
 }
 
-// Lock the namespace resource.
-func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock bool) {
+// Lock the namespace resource, distributed locks give up once the
+// deadline passes unless it is zero. requestID is the ID of the
+// client request the operation serves, if any. Returns false if the
+// lock was not acquired.
+func (n *nsLockMap) lock(volume, path string, lockSource, opsID, requestID string, readLock bool, deadline time.Time) (locked bool) {
+	param := nsParam{volume, path}
+
+	// Change the state of the lock to be blocked for the given
+	// pair of <volume, path> and <OperationID> till the lock
+	// unblocks. Lock instrumentation is guarded by its own shard
+	// mutex, so it is updated before taking lockMapMutex.
+	if err := n.statusNoneToBlocked(param, lockSource, opsID, requestID, readLock); err != nil {
+		errorIf(err, "Failed to set lock state to blocked")
+	}
+	addBlockedLockSample(param, opsID)
+
+	// Remember what the lock is waiting for, to report slow requests.
+	var holders []string
+	trackWait := globalSlowRequests.isEnabled()
+	if trackWait {
+		holders = n.lockHolders(param, opsID)
+	}
+
 	var nsLk *nsLock
 	n.lockMapMutex.Lock()
-
-	param := nsParam{volume, path}
 	nsLk, found := n.lockMap[param]
 	if !found {
 		nsLk = &nsLock{
-			RWLocker: func() RWLocker {
+			rwMutex: func() rwMutex {
 				if n.isDistXL {
-					return dsync.NewDRWMutex(pathJoin(volume, path))
+					return globalDistLocker.newRWMutex(pathJoin(volume, path))
 				}
 				return &sync.RWMutex{}
 			}(),
@@ -118,23 +235,74 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	}
 	nsLk.ref++ // Update ref count here to avoid multiple races.
 
-	// Change the state of the lock to be blocked for the given
-	// pair of <volume, path> and <OperationID> till the lock
-	// unblocks. The lock for accessing `globalNSMutex` is held inside
-	// the function itself.
-	if err := n.statusNoneToBlocked(param, lockSource, opsID, readLock); err != nil {
-		errorIf(err, "Failed to set lock state to blocked")
-	}
-
 	// Unlock map before Locking NS which might block.
 	n.lockMapMutex.Unlock()
 
+	waitStart := UTCNow()
+
+	// Wait for the turn of the operation by the fairness policy,
+	// only distributed locks honor the deadline.
+	var waiter *lockWaiter
+	if n.fairness != lockFairnessNone {
+		var queueDeadline time.Time
+		if n.isDistXL {
+			queueDeadline = deadline
+		}
+		waiter = nsLk.queue.enter(n.fairness, readLock, queueDeadline)
+	}
+
 	// Locking here can block.
-	if readLock {
+	locked = true
+	if n.fairness != lockFairnessNone && waiter == nil {
+		// Not admitted before the deadline.
+		locked = false
+	} else if dm, ok := nsLk.rwMutex.(*distRWMutex); ok && !deadline.IsZero() {
+		if timeout := deadline.Sub(UTCNow()); timeout <= 0 {
+			locked = false
+		} else if readLock {
+			locked = dm.GetRLock(timeout)
+		} else {
+			locked = dm.GetLock(timeout)
+		}
+	} else if readLock {
 		nsLk.RLock()
 	} else {
 		nsLk.Lock()
 	}
+	if waiter != nil {
+		nsLk.queue.leave(waiter)
+	}
+
+	if trackWait {
+		lType := debugWLockStr
+		if readLock {
+			lType = debugRLockStr
+		}
+		globalSlowRequests.addLockWait(LockWaitInfo{
+			Bucket:   volume,
+			Object:   path,
+			LockType: string(lType),
+			Waited:   UTCNow().Sub(waitStart),
+			HeldBy:   holders,
+		})
+	}
+
+	removeBlockedLockSample(param, opsID)
+
+	if !locked {
+		// Forget the blocked lock, it is not going to be unlocked.
+		atomic.AddInt64(&n.timedOutLocks, 1)
+		released := false
+		n.lockMapMutex.Lock()
+		if curLk, found := n.lockMap[param]; found && curLk == nsLk {
+			released = n.releaseNSLock(param, nsLk)
+		}
+		n.lockMapMutex.Unlock()
+		if released {
+			n.deleteLockInfo(param, opsID)
+		}
+		return false
+	}
 
 	// Changing the status of the operation from blocked to
 	// running.  change the state of the lock to be running (from
@@ -142,47 +310,56 @@ func (n *nsLockMap) lock(volume, path string, lockSource, opsID string, readLock
 	if err := n.statusBlockedToRunning(param, lockSource, opsID, readLock); err != nil {
 		errorIf(err, "Failed to set the lock state to running")
 	}
+	return true
+}
+
+// getTimedOutLocks - returns the count of locks not acquired before
+// their deadline since server start.
+func getTimedOutLocks() int64 {
+	if globalNSMutex == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&globalNSMutex.timedOutLocks)
 }
 
 // Unlock the namespace resource.
 func (n *nsLockMap) unlock(volume, path, opsID string, readLock bool) {
-	// nsLk.Unlock() will not block, hence locking the map for the
-	// entire function is fine.
-	n.lockMapMutex.Lock()
-	defer n.lockMapMutex.Unlock()
-
+	// nsLk.Unlock() will not block, hence locking the map for it
+	// is fine.
+	released := false
 	param := nsParam{volume, path}
+	n.lockMapMutex.Lock()
 	if nsLk, found := n.lockMap[param]; found {
 		if readLock {
 			nsLk.RUnlock()
 		} else {
 			nsLk.Unlock()
 		}
-		if nsLk.ref == 0 {
-			errorIf(errors.New("Namespace reference count cannot be 0"),
-				"Invalid reference count detected")
-		}
-		if nsLk.ref != 0 {
-			nsLk.ref--
-
-			// delete the lock state entry for given operation ID.
-			err := n.deleteLockInfoEntryForOps(param, opsID)
-			if err != nil {
-				errorIf(err, "Failed to delete lock info entry")
-			}
-		}
-		if nsLk.ref == 0 {
-			// Remove from the map if there are no more references.
-			delete(n.lockMap, param)
-
-			// delete the lock state entry for given
-			// <volume, path> pair.
-			err := n.deleteLockInfoEntryForVolumePath(param)
-			if err != nil {
-				errorIf(err, "Failed to delete lock info entry")
-			}
-		}
+		released = n.releaseNSLock(param, nsLk)
 	}
+	n.lockMapMutex.Unlock()
+
+	if released {
+		n.deleteLockInfo(param, opsID)
+	}
+}
+
+// releaseNSLock - drops the reference of an operation to a namespace
+// lock, must be called with lockMapMutex held. Returns true if the
+// reference was dropped, its lock info is then to be deleted by the
+// caller after releasing lockMapMutex.
+func (n *nsLockMap) releaseNSLock(param nsParam, nsLk *nsLock) bool {
+	if nsLk.ref == 0 {
+		errorIf(errors.New("Namespace reference count cannot be 0"),
+			"Invalid reference count detected")
+		return false
+	}
+	nsLk.ref--
+	if nsLk.ref == 0 {
+		// Remove from the map if there are no more references.
+		delete(n.lockMap, param)
+	}
+	return true
 }
 
 // Lock - locks the given resource for writes, using a previously
@@ -191,7 +368,7 @@ func (n *nsLockMap) Lock(volume, path, opsID string) {
 	readLock := false // This is a write lock.
 
 	lockSource := getSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, "", readLock, time.Time{})
 }
 
 // Unlock - unlocks any previously acquired write locks.
@@ -205,7 +382,7 @@ func (n *nsLockMap) RLock(volume, path, opsID string) {
 	readLock := true
 
 	lockSource := getSource() // Useful for debugging
-	n.lock(volume, path, lockSource, opsID, readLock)
+	n.lock(volume, path, lockSource, opsID, "", readLock, time.Time{})
 }
 
 // RUnlock - unlocks any previously acquired read locks.
@@ -228,12 +405,12 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 	//
 	// - In case of Distributed setup (using dsync), there is no need to call
 	//   ForceUnlock on the server where the lock was acquired and is presumably
-	//   'stuck'. Instead dsync.ForceUnlock() will release the underlying locks
+	//   'stuck'. Instead distRWMutex.ForceUnlock() will release the underlying locks
 	//   that participated in granting the lock. Any pending dsync locks that
 	//   are blocking can now proceed as normal and any new locks will also
 	//   participate normally.
 	if n.isDistXL { // For distributed mode, broadcast ForceUnlock message.
-		dsync.NewDRWMutex(pathJoin(volume, path)).ForceUnlock()
+		globalDistLocker.newRWMutex(pathJoin(volume, path)).ForceUnlock()
 	}
 
 	param := nsParam{volume, path}
@@ -254,20 +431,43 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 type lockInstance struct {
 	ns                  *nsLockMap
 	volume, path, opsID string
+	requestID           string
 }
 
 // NewNSLock - returns a lock instance for a given volume and
 // path. The returned lockInstance object encapsulates the nsLockMap,
 // volume, path and operation ID.
 func (n *nsLockMap) NewNSLock(volume, path string) RWLocker {
-	return &lockInstance{n, volume, path, getOpsID()}
+	return &lockInstance{n, volume, path, getOpsID(), ""}
+}
+
+// NewRequestNSLock - returns a lock instance for a given volume and
+// path taken on behalf of the client request r, its lock state is
+// reported along with the request ID, to correlate /debug/locks
+// entries with the request and its log lines.
+func (n *nsLockMap) NewRequestNSLock(r *http.Request, volume, path string) RWLocker {
+	return &lockInstance{n, volume, path, getOpsID(), getRequestID(r)}
+}
+
+// NewUploadIDLock - returns a lock instance for a given multipart
+// upload. Parts of an upload are uploaded in parallel under its read
+// lock, its write lock serializes the updates of the upload metadata.
+func (n *nsLockMap) NewUploadIDLock(bucket, object, uploadID string) RWLocker {
+	return n.NewNSLock(minioMetaMultipartBucket, pathutil.Join(bucket, object, uploadID))
+}
+
+// NewPartLock - returns a lock instance for a single part of a
+// multipart upload, so that parallel uploads of the same part
+// number are serialized without blocking the other parts.
+func (n *nsLockMap) NewPartLock(bucket, object, uploadID string, partID int) RWLocker {
+	return n.NewNSLock(minioMetaMultipartBucket, pathutil.Join(bucket, object, uploadID, fmt.Sprintf("part.%d", partID)))
 }
 
 // Lock - block until write lock is taken.
 func (li *lockInstance) Lock() {
 	lockSource := getSource()
 	readLock := false
-	li.ns.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.requestID, readLock, time.Time{})
 }
 
 // Unlock - block until write lock is released.
@@ -280,7 +480,7 @@ func (li *lockInstance) Unlock() {
 func (li *lockInstance) RLock() {
 	lockSource := getSource()
 	readLock := true
-	li.ns.lock(li.volume, li.path, lockSource, li.opsID, readLock)
+	li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.requestID, readLock, time.Time{})
 }
 
 // RUnlock - block until read lock is released.
@@ -288,3 +488,27 @@ func (li *lockInstance) RUnlock() {
 	readLock := true
 	li.ns.unlock(li.volume, li.path, li.opsID, readLock)
 }
+
+// GetLock - block until write lock is taken or the deadline passes,
+// a zero deadline waits forever. Only distributed locks honor the
+// deadline.
+func (li *lockInstance) GetLock(deadline time.Time) error {
+	lockSource := getSource()
+	readLock := false
+	if !li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.requestID, readLock, deadline) {
+		return errLockTimedOut
+	}
+	return nil
+}
+
+// GetRLock - block until read lock is taken or the deadline passes,
+// a zero deadline waits forever. Only distributed locks honor the
+// deadline.
+func (li *lockInstance) GetRLock(deadline time.Time) error {
+	lockSource := getSource()
+	readLock := true
+	if !li.ns.lock(li.volume, li.path, lockSource, li.opsID, li.requestID, readLock, deadline) {
+		return errLockTimedOut
+	}
+	return nil
+}
@@ -414,3 +414,89 @@ func TestNamespaceForceUnlockTest(t *testing.T) {
 	// Clean up lock.
 	globalNSMutex.ForceUnlock("bucket", "object")
 }
+
+// Tests lock info of an operation waiting for a lock survives the
+// release of the last reference to the lock held before it.
+func TestNamespaceLockInfoHandover(t *testing.T) {
+	initNSLock(false)
+	param := nsParam{"bucket", "object"}
+
+	holder := globalNSMutex.NewNSLock(param.volume, param.path)
+	holder.Lock()
+
+	waiter := globalNSMutex.NewNSLock(param.volume, param.path).(*lockInstance)
+	lockedCh := make(chan struct{})
+	go func() {
+		waiter.Lock()
+		close(lockedCh)
+	}()
+
+	// Wait for the waiter to be blocked on the lock.
+	for i := 0; ; i++ {
+		if globalNSMutex.getLockStat().blocked == 1 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected the lock to be blocked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	holder.Unlock()
+	<-lockedCh
+
+	volumePathLocks := getDebugLockInfo(param)
+	if volumePathLocks == nil {
+		t.Fatalf("Entry for <volume> %s, <path> %s should have existed.", param.volume, param.path)
+	}
+	if lockInfo, ok := volumePathLocks.lockInfo[waiter.opsID]; !ok || lockInfo.status != runningStatus {
+		t.Fatalf("Expected the lock of the waiter to be running, got %v", lockInfo)
+	}
+
+	waiter.Unlock()
+	if getDebugLockInfo(param) != nil {
+		t.Fatalf("Entry for <volume> %s, <path> %s should have been deleted.", param.volume, param.path)
+	}
+	if stat := globalNSMutex.getLockStat(); stat.total != 0 || stat.granted != 0 || stat.blocked != 0 {
+		t.Fatalf("Expected no locks, got %+v", stat)
+	}
+}
+
+// Tests validation of distributed locking config.
+func TestLockConfig(t *testing.T) {
+	testCases := []struct {
+		config     lockConfig
+		shouldPass bool
+	}{
+		// Empty config uses the defaults.
+		{lockConfig{}, true},
+		// All values set.
+		{lockConfig{AcquireTimeout: "2s", RetryUnit: "500ms", RetryCap: "5s", Quorum: 3}, true},
+		// Invalid duration.
+		{lockConfig{AcquireTimeout: "2"}, false},
+		// Negative duration.
+		{lockConfig{RetryUnit: "-1s"}, false},
+		// Zero duration.
+		{lockConfig{RetryCap: "0s"}, false},
+		// Negative quorum.
+		{lockConfig{Quorum: -1}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+
+	settings, err := lockConfig{AcquireTimeout: "2s", Quorum: 5}.settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.acquireTimeout != 2*time.Second || settings.retryUnit != 0 || settings.quorum != 5 {
+		t.Fatalf("Unexpected lock settings %#v", settings)
+	}
+}
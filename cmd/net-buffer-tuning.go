@@ -0,0 +1,160 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// Default bounds of tuned socket buffers, can be changed
+	// through MINIO_NET_BUFFER_MIN and MINIO_NET_BUFFER_MAX.
+	defaultNetBufferMin = 64 * humanize.KiByte
+	defaultNetBufferMax = 4 * humanize.MiByte
+
+	// Largest socket buffer which can be configured.
+	maxNetBufferSize = 1 * humanize.GiByte
+
+	// Transfers are sampled over windows of at least this long and
+	// this many bytes before buffers are tuned.
+	netBufferSampleDuration = 100 * time.Millisecond
+	netBufferSampleSize     = 256 * humanize.KiByte
+
+	// Pauses between reads or writes longer than this, e.g. between
+	// requests on a keep-alive connection, restart the window.
+	netBufferIdleTimeout = time.Second
+)
+
+// bufferTuner - tunes a socket buffer of a connection to the
+// bandwidth-delay product observed on it, so that clients far away
+// get large buffers to fill their network path while clients on the
+// local network don't hold more memory than they need.
+type bufferTuner struct {
+	mu sync.Mutex
+
+	// Bounds of the buffer size.
+	min, max int
+
+	// Returns round trip time of the connection, false when unknown.
+	rtt func() (time.Duration, bool)
+
+	// Sets the buffer size of the connection.
+	setBuffer func(int) error
+
+	// Current buffer size, zero until tuned for the first time.
+	size int
+
+	// Sampling window, bytes transferred since start.
+	start, last time.Time
+	bytes       int64
+}
+
+// newBufferTuner - returns a tuner of a socket buffer within given
+// bounds.
+func newBufferTuner(min, max int, rtt func() (time.Duration, bool), setBuffer func(int) error) *bufferTuner {
+	return &bufferTuner{
+		min:       min,
+		max:       max,
+		rtt:       rtt,
+		setBuffer: setBuffer,
+	}
+}
+
+// observe - records n bytes read or written at given time, the buffer
+// is tuned at the end of every sampling window.
+func (b *bufferTuner) observe(n int, now time.Time) {
+	if n <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Bytes of the first transfer were on their way before the
+	// window started, they are not counted.
+	if b.start.IsZero() || now.Sub(b.last) > netBufferIdleTimeout {
+		b.start, b.last, b.bytes = now, now, 0
+		return
+	}
+	b.last = now
+	b.bytes += int64(n)
+
+	elapsed := now.Sub(b.start)
+	if elapsed < netBufferSampleDuration || b.bytes < netBufferSampleSize {
+		return
+	}
+	bandwidth := float64(b.bytes) / elapsed.Seconds()
+	b.start, b.bytes = now, 0
+
+	rtt, ok := b.rtt()
+	if !ok {
+		return
+	}
+	size := bufferSizeForBDP(bandwidth, rtt, b.min, b.max)
+
+	// Avoid resizing on small fluctuations.
+	if b.size != 0 && size > b.size*3/4 && size < b.size*5/4 {
+		return
+	}
+	if err := b.setBuffer(size); err != nil {
+		errorIf(err, "Unable to set socket buffer size to %d", size)
+		return
+	}
+	b.size = size
+}
+
+// bufferSizeForBDP - returns twice the bandwidth-delay product of given
+// bandwidth in bytes per second and round trip time within given
+// bounds. The extra room lets throughput grow when it is limited by
+// the current buffer size, until the network path is filled.
+func bufferSizeForBDP(bandwidth float64, rtt time.Duration, min, max int) int {
+	size := 2 * bandwidth * rtt.Seconds()
+	if size < float64(min) {
+		return min
+	}
+	if size > float64(max) {
+		return max
+	}
+	return int(size)
+}
+
+// connBufferTuners - tuners of the read and write buffers of a
+// connection.
+type connBufferTuners struct {
+	read, write *bufferTuner
+}
+
+// newConnBufferTuners - returns tuners of the socket buffers of given
+// connection within given bounds, nil when the connection is not a TCP
+// connection.
+func newConnBufferTuners(conn net.Conn, min, max int) *connBufferTuners {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+	rtt := func() (time.Duration, bool) {
+		return getTCPRTT(tcpConn)
+	}
+	return &connBufferTuners{
+		read:  newBufferTuner(min, max, rtt, tcpConn.SetReadBuffer),
+		write: newBufferTuner(min, max, rtt, tcpConn.SetWriteBuffer),
+	}
+}
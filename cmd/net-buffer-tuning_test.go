@@ -0,0 +1,112 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests buffer sizes computed from bandwidth-delay products.
+func TestBufferSizeForBDP(t *testing.T) {
+	testCases := []struct {
+		bandwidth float64
+		rtt       time.Duration
+		expected  int
+	}{
+		// LAN client, 1Gbit/s with 0.2ms RTT.
+		{125 * humanize.MByte, 200 * time.Microsecond, defaultNetBufferMin},
+		// WAN client, 100Mbit/s with 50ms RTT.
+		{12.5 * humanize.MByte, 50 * time.Millisecond, 1250 * humanize.KByte},
+		// Long fat network.
+		{125 * humanize.MByte, 100 * time.Millisecond, defaultNetBufferMax},
+		{0, time.Second, defaultNetBufferMin},
+	}
+	for i, testCase := range testCases {
+		size := bufferSizeForBDP(testCase.bandwidth, testCase.rtt, defaultNetBufferMin, defaultNetBufferMax)
+		if size != testCase.expected {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expected, size)
+		}
+	}
+}
+
+// Tests buffers are tuned from transfers observed on a connection.
+func TestBufferTuner(t *testing.T) {
+	rtt := 50 * time.Millisecond
+	rttOK := true
+	var sizes []int
+	var setErr error
+	tuner := newBufferTuner(defaultNetBufferMin, defaultNetBufferMax,
+		func() (time.Duration, bool) { return rtt, rttOK },
+		func(size int) error {
+			if setErr != nil {
+				return setErr
+			}
+			sizes = append(sizes, size)
+			return nil
+		})
+
+	// Transfers 1MiB per 100ms, i.e. 10MiB/s, in 64KiB chunks.
+	now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	transfer := func(d time.Duration) {
+		chunks := 16
+		for i := 0; i < chunks; i++ {
+			now = now.Add(d / time.Duration(chunks))
+			tuner.observe(64*humanize.KiByte, now)
+		}
+	}
+
+	testCases := []struct {
+		rtt      time.Duration
+		rttOK    bool
+		setErr   error
+		idle     time.Duration
+		expected []int
+	}{
+		// First window starts at the first transfer, 15 chunks
+		// in 93.75ms are too short to tune.
+		{50 * time.Millisecond, true, nil, 0, nil},
+		// 2 * 10MiB/s * 50ms.
+		{50 * time.Millisecond, true, nil, 0, []int{1 * humanize.MiByte}},
+		// Small fluctuations don't resize.
+		{55 * time.Millisecond, true, nil, 0, []int{1 * humanize.MiByte}},
+		// Unknown RTT doesn't resize.
+		{time.Millisecond, false, nil, 0, []int{1 * humanize.MiByte}},
+		// Failures to resize are retried.
+		{time.Millisecond, true, errors.New("failed"), 0, []int{1 * humanize.MiByte}},
+		{time.Millisecond, true, nil, 0, []int{1 * humanize.MiByte, defaultNetBufferMin}},
+		// An idle connection restarts the window.
+		{50 * time.Millisecond, true, nil, 2 * time.Second, []int{1 * humanize.MiByte, defaultNetBufferMin}},
+		{50 * time.Millisecond, true, nil, 0, []int{1 * humanize.MiByte, defaultNetBufferMin, 1 * humanize.MiByte}},
+	}
+	for i, testCase := range testCases {
+		rtt, rttOK, setErr = testCase.rtt, testCase.rttOK, testCase.setErr
+		now = now.Add(testCase.idle)
+		transfer(100 * time.Millisecond)
+		if len(sizes) != len(testCase.expected) {
+			t.Fatalf("Test %d: Expected sizes %v, got %v", i+1, testCase.expected, sizes)
+		}
+		for j := range sizes {
+			if sizes[j] != testCase.expected[j] {
+				t.Fatalf("Test %d: Expected sizes %v, got %v", i+1, testCase.expected, sizes)
+			}
+		}
+	}
+}
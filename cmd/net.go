@@ -186,6 +186,17 @@ func checkPortAvailability(port string) (err error) {
 	return nil
 }
 
+// checkPortNumber - checks if port is a valid port number.
+func checkPortNumber(port string) error {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("invalid port number")
+	} else if p < 1 || p > 65535 {
+		return fmt.Errorf("port number must be between 1 to 65535")
+	}
+	return nil
+}
+
 // CheckLocalServerAddr - checks if serverAddr is valid and local host.
 func CheckLocalServerAddr(serverAddr string) error {
 	host, port, err := net.SplitHostPort(serverAddr)
@@ -194,11 +205,8 @@ func CheckLocalServerAddr(serverAddr string) error {
 	}
 
 	// Check whether port is a valid port number.
-	p, err := strconv.Atoi(port)
-	if err != nil {
-		return fmt.Errorf("invalid port number")
-	} else if p < 1 || p > 65535 {
-		return fmt.Errorf("port number must be between 1 to 65535")
+	if err = checkPortNumber(port); err != nil {
+		return err
 	}
 
 	if host != "" {
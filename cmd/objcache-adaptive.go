@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/pkg/objcache"
+)
+
+const (
+	// Interval between two samplings of the memory used by the
+	// server to resize the object cache in adaptive mode.
+	objCacheSampleInterval = 10 * time.Second
+
+	// Percentage of the memory limit above which the server is under
+	// memory pressure, the object cache is then halved on every
+	// sampling until memory use falls below.
+	objCacheMemoryPressure = 90
+)
+
+// parseObjCacheMemoryPercent - parses the percentage of free memory
+// the object cache sizes itself to in adaptive mode.
+func parseObjCacheMemoryPercent(value string) (int, error) {
+	percent, err := strconv.Atoi(value)
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("Invalid cache memory percentage ‘%s’, must be between 1 and 100", value)
+	}
+	return percent, nil
+}
+
+// getMemoryInUse - returns the memory obtained from the OS by the
+// server and not returned to it yet.
+func getMemoryInUse() uint64 {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.Sys - memStats.HeapReleased
+}
+
+// adaptiveObjCacheSize - returns the size of the object cache for the
+// given memory limit and memory in use by the server, of which
+// cacheSize bytes are used by the cache. The cache sizes itself to
+// percent of the memory not used by the rest of the server, and is
+// halved under memory pressure.
+func adaptiveObjCacheSize(limit, inUse, cacheSize uint64, percent int) uint64 {
+	var otherInUse uint64
+	if inUse > cacheSize {
+		otherInUse = inUse - cacheSize
+	}
+	if otherInUse >= limit {
+		return 0
+	}
+	size := (limit - otherInUse) * uint64(percent) / 100
+	if inUse > limit*objCacheMemoryPressure/100 && size > cacheSize/2 {
+		size = cacheSize / 2
+	}
+	return size
+}
+
+// getAdaptiveObjCacheSize - returns the size of the object cache
+// using cacheSize bytes in adaptive mode.
+func getAdaptiveObjCacheSize(cacheSize uint64) (uint64, error) {
+	curLimit, _, err := getMemoryLimits()
+	if err != nil {
+		return 0, err
+	}
+	return adaptiveObjCacheSize(curLimit, getMemoryInUse(), cacheSize, globalObjCacheMemoryPercent), nil
+}
+
+// resizeObjCache - samples memory used by the server and resizes the
+// object cache accordingly until the server stops.
+func resizeObjCache(cache *objcache.Cache) {
+	ticker := time.NewTicker(objCacheSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			size, err := getAdaptiveObjCacheSize(cache.Size())
+			if err != nil {
+				errorIf(err, "Unable to get memory limit to resize object cache")
+				continue
+			}
+			cache.SetMaxSize(size)
+		case <-globalServiceDoneCh:
+			return
+		}
+	}
+}
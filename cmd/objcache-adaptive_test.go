@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests parsing of the cache memory percentage.
+func TestParseObjCacheMemoryPercent(t *testing.T) {
+	testCases := []struct {
+		value           string
+		expectedPercent int
+		expectErr       bool
+	}{
+		// Test 1: valid percentage.
+		{"50", 50, false},
+		// Test 2: whole memory.
+		{"100", 100, false},
+		// Test 3: zero.
+		{"0", 0, true},
+		// Test 4: above 100.
+		{"101", 0, true},
+		// Test 5: not a number.
+		{"50%", 0, true},
+	}
+	for i, testCase := range testCases {
+		percent, err := parseObjCacheMemoryPercent(testCase.value)
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+		if percent != testCase.expectedPercent {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedPercent, percent)
+		}
+	}
+}
+
+// Tests sizing of the object cache in adaptive mode.
+func TestAdaptiveObjCacheSize(t *testing.T) {
+	testCases := []struct {
+		limit, inUse, cacheSize uint64
+		percent                 int
+		expectedSize            uint64
+	}{
+		// Test 1: empty cache, half of the free memory.
+		{4 * humanize.GiByte, 1 * humanize.GiByte, 0, 50, 1536 * humanize.MiByte},
+		// Test 2: memory used by the cache counts as free.
+		{4 * humanize.GiByte, 2 * humanize.GiByte, 1 * humanize.GiByte, 50, 1536 * humanize.MiByte},
+		// Test 3: under memory pressure the cache is halved.
+		{4 * humanize.GiByte, 3900 * humanize.MiByte, 2 * humanize.GiByte, 100, 1 * humanize.GiByte},
+		// Test 4: under memory pressure a smaller target is kept.
+		{4 * humanize.GiByte, 3900 * humanize.MiByte, 100 * humanize.MiByte, 10, 296 * humanize.MiByte / 10},
+		// Test 5: rest of the server uses all the memory.
+		{4 * humanize.GiByte, 5 * humanize.GiByte, 0, 50, 0},
+	}
+	for i, testCase := range testCases {
+		size := adaptiveObjCacheSize(testCase.limit, testCase.inUse, testCase.cacheSize, testCase.percent)
+		if size != testCase.expectedSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedSize, size)
+		}
+	}
+}
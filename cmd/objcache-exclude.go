@@ -0,0 +1,49 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// parseObjCacheExcludes - parses semicolon separated list of
+// "bucket/object" glob patterns, '*' and '?' wildcards match any
+// character including '/'.
+func parseObjCacheExcludes(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ";") {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), slashSeparator)
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// isObjCacheExcluded - returns true if object should never enter the
+// object cache.
+func isObjCacheExcluded(bucket, object string) bool {
+	name := pathJoin(bucket, object)
+	for _, pattern := range globalObjCacheExcludes {
+		if wildcard.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
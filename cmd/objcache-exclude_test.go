@@ -0,0 +1,134 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/objcache"
+)
+
+// Tests parsing object cache exclude patterns.
+func TestParseObjCacheExcludes(t *testing.T) {
+	testCases := []struct {
+		value            string
+		expectedPatterns []string
+	}{
+		{"", nil},
+		{";", nil},
+		{"videos/*", []string{"videos/*"}},
+		{"videos/*; *.iso ;/backups/2017/*", []string{"videos/*", "*.iso", "backups/2017/*"}},
+	}
+
+	for i, testCase := range testCases {
+		patterns := parseObjCacheExcludes(testCase.value)
+		if !reflect.DeepEqual(patterns, testCase.expectedPatterns) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedPatterns, patterns)
+		}
+	}
+}
+
+// Tests matching objects against object cache exclude patterns.
+func TestIsObjCacheExcluded(t *testing.T) {
+	savedExcludes := globalObjCacheExcludes
+	defer func() {
+		globalObjCacheExcludes = savedExcludes
+	}()
+
+	globalObjCacheExcludes = parseObjCacheExcludes("videos/*;*.iso;backups/2017-??/*")
+	testCases := []struct {
+		bucket, object string
+		excluded       bool
+	}{
+		{"videos", "movie.mp4", true},
+		{"videos", "2017/movie.mp4", true},
+		{"images", "disk.iso", true},
+		{"images", "a/b/disk.iso", true},
+		{"backups", "2017-01/db.tar", true},
+		{"backups", "2017/db.tar", false},
+		{"videosbucket", "movie.mp4", false},
+		{"images", "photo.jpg", false},
+	}
+
+	for i, testCase := range testCases {
+		if excluded := isObjCacheExcluded(testCase.bucket, testCase.object); excluded != testCase.excluded {
+			t.Errorf("Test %d: expected %t for %s/%s, got %t", i+1, testCase.excluded,
+				testCase.bucket, testCase.object, excluded)
+		}
+	}
+
+	// Nothing is excluded without patterns.
+	globalObjCacheExcludes = nil
+	if isObjCacheExcluded("videos", "movie.mp4") {
+		t.Error("Expected no object to be excluded without patterns")
+	}
+}
+
+// Tests excluded objects never enter XL object cache.
+func TestXLObjCacheExclude(t *testing.T) {
+	savedExcludes := globalObjCacheExcludes
+	defer func() {
+		globalObjCacheExcludes = savedExcludes
+	}()
+	globalObjCacheExcludes = parseObjCacheExcludes("videos/*")
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+	if xl.objCache, err = objcache.New(humanize.MiByte, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	xl.objCacheEnabled = true
+
+	data := []byte("hello, world")
+	for _, bucket := range []string{"videos", "images"} {
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+		objInfo, err := obj.PutObject(bucket, "object", int64(len(data)), bytes.NewReader(data), nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = obj.GetObject(bucket, "object", 0, objInfo.Size, ioutil.Discard); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = xl.objCache.Open(pathJoin(bucket, "object"), objInfo.ModTime)
+		if bucket == "videos" && err != objcache.ErrKeyNotFoundInCache {
+			t.Errorf("Expected excluded object not to be cached, got %v", err)
+		}
+		if bucket == "images" && err != nil {
+			t.Errorf("Expected object to be cached, got %v", err)
+		}
+	}
+}
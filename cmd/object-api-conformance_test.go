@@ -0,0 +1,41 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/objecttest"
+)
+
+// Runs the object layer conformance suite against FS and XL.
+func TestObjectLayerConformance(t *testing.T) {
+	rootPath, err := cmd.NewTestConfig("us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	t.Run("FS", func(t *testing.T) {
+		objecttest.Run(t, cmd.NewFSTestObjectLayer)
+	})
+	t.Run("XL", func(t *testing.T) {
+		objecttest.Run(t, cmd.NewXLTestObjectLayer)
+	})
+}
@@ -47,6 +47,10 @@ type StorageInfo struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+
+		// Set when disks are offline or reads recently failed on
+		// some disks, objects are then read from the remaining disks.
+		Degraded bool
 	}
 }
 
@@ -181,7 +185,7 @@ type ListMultipartsInfo struct {
 	IsTruncated bool
 
 	// List of all pending uploads.
-	Uploads []uploadMetadata
+	Uploads []UploadMetadata
 
 	// When a prefix is provided in the request, The result contains only keys
 	// starting with the specified prefix.
@@ -261,8 +265,8 @@ type PartInfo struct {
 	Size int64
 }
 
-// uploadMetadata - represents metadata in progress multipart upload.
-type uploadMetadata struct {
+// UploadMetadata - represents metadata in progress multipart upload.
+type UploadMetadata struct {
 	// Object name for which the multipart upload was initiated.
 	Object string
 
@@ -277,8 +281,8 @@ type uploadMetadata struct {
 	HealUploadInfo *HealObjectInfo `xml:"HealUploadInfo,omitempty"`
 }
 
-// completePart - completed part container.
-type completePart struct {
+// CompletePart - completed part container.
+type CompletePart struct {
 	// Part number identifying the part. This is a positive integer between 1 and
 	// 10,000
 	PartNumber int
@@ -288,7 +292,7 @@ type completePart struct {
 }
 
 // completedParts - is a collection satisfying sort.Interface.
-type completedParts []completePart
+type completedParts []CompletePart
 
 func (a completedParts) Len() int           { return len(a) }
 func (a completedParts) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
@@ -296,5 +300,5 @@ func (a completedParts) Less(i, j int) bool { return a[i].PartNumber < a[j].Part
 
 // completeMultipartUpload - represents input fields for completing multipart upload.
 type completeMultipartUpload struct {
-	Parts []completePart `xml:"Part"`
+	Parts []CompletePart `xml:"Part"`
 }
@@ -110,6 +110,10 @@ func (e SHA256Mismatch) Error() string {
 	return "sha256 computed does not match with what is expected"
 }
 
+func (e SHA256Mismatch) APIErrorCode() APIErrorCode {
+	return ErrContentSHA256Mismatch
+}
+
 // StorageFull storage ran out of space.
 type StorageFull struct{}
 
@@ -117,6 +121,10 @@ func (e StorageFull) Error() string {
 	return "Storage reached its minimum free disk threshold."
 }
 
+func (e StorageFull) APIErrorCode() APIErrorCode {
+	return ErrStorageFull
+}
+
 // InsufficientReadQuorum storage cannot satisfy quorum for read operation.
 type InsufficientReadQuorum struct{}
 
@@ -124,6 +132,10 @@ func (e InsufficientReadQuorum) Error() string {
 	return "Storage resources are insufficient for the read operation."
 }
 
+func (e InsufficientReadQuorum) APIErrorCode() APIErrorCode {
+	return ErrReadQuorum
+}
+
 // InsufficientWriteQuorum storage cannot satisfy quorum for write operation.
 type InsufficientWriteQuorum struct{}
 
@@ -131,6 +143,10 @@ func (e InsufficientWriteQuorum) Error() string {
 	return "Storage resources are insufficient for the write operation."
 }
 
+func (e InsufficientWriteQuorum) APIErrorCode() APIErrorCode {
+	return ErrWriteQuorum
+}
+
 // GenericError - generic object layer error.
 type GenericError struct {
 	Bucket string
@@ -144,6 +160,10 @@ func (e BucketNotFound) Error() string {
 	return "Bucket not found: " + e.Bucket
 }
 
+func (e BucketNotFound) APIErrorCode() APIErrorCode {
+	return ErrNoSuchBucket
+}
+
 // BucketAlreadyOwnedByYou already owned by you.
 type BucketAlreadyOwnedByYou GenericError
 
@@ -151,6 +171,10 @@ func (e BucketAlreadyOwnedByYou) Error() string {
 	return "Bucket already owned by you: " + e.Bucket
 }
 
+func (e BucketAlreadyOwnedByYou) APIErrorCode() APIErrorCode {
+	return ErrBucketAlreadyOwnedByYou
+}
+
 // BucketNotEmpty bucket is not empty.
 type BucketNotEmpty GenericError
 
@@ -158,6 +182,10 @@ func (e BucketNotEmpty) Error() string {
 	return "Bucket not empty: " + e.Bucket
 }
 
+func (e BucketNotEmpty) APIErrorCode() APIErrorCode {
+	return ErrBucketNotEmpty
+}
+
 // ObjectNotFound object does not exist.
 type ObjectNotFound GenericError
 
@@ -165,6 +193,10 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Bucket + "#" + e.Object
 }
 
+func (e ObjectNotFound) APIErrorCode() APIErrorCode {
+	return ErrNoSuchKey
+}
+
 // ObjectExistsAsDirectory object already exists as a directory.
 type ObjectExistsAsDirectory GenericError
 
@@ -172,6 +204,10 @@ func (e ObjectExistsAsDirectory) Error() string {
 	return "Object exists on : " + e.Bucket + " as directory " + e.Object
 }
 
+func (e ObjectExistsAsDirectory) APIErrorCode() APIErrorCode {
+	return ErrObjectExistsAsDirectory
+}
+
 //PrefixAccessDenied object access is denied.
 type PrefixAccessDenied GenericError
 
@@ -179,6 +215,10 @@ func (e PrefixAccessDenied) Error() string {
 	return "Prefix access is denied: " + e.Bucket + "/" + e.Object
 }
 
+func (e PrefixAccessDenied) APIErrorCode() APIErrorCode {
+	return ErrAccessDenied
+}
+
 // BucketExists bucket exists.
 type BucketExists GenericError
 
@@ -186,6 +226,10 @@ func (e BucketExists) Error() string {
 	return "Bucket exists: " + e.Bucket
 }
 
+func (e BucketExists) APIErrorCode() APIErrorCode {
+	return ErrBucketAlreadyOwnedByYou
+}
+
 // BadDigest - Content-MD5 you specified did not match what we received.
 type BadDigest struct {
 	ExpectedMD5   string
@@ -196,6 +240,10 @@ func (e BadDigest) Error() string {
 	return "Bad digest: Expected " + e.ExpectedMD5 + " is not valid with what we calculated " + e.CalculatedMD5
 }
 
+func (e BadDigest) APIErrorCode() APIErrorCode {
+	return ErrBadDigest
+}
+
 // UnsupportedDelimiter - unsupported delimiter.
 type UnsupportedDelimiter struct {
 	Delimiter string
@@ -205,6 +253,10 @@ func (e UnsupportedDelimiter) Error() string {
 	return fmt.Sprintf("delimiter '%s' is not supported. Only '/' is supported", e.Delimiter)
 }
 
+func (e UnsupportedDelimiter) APIErrorCode() APIErrorCode {
+	return ErrNotImplemented
+}
+
 // InvalidUploadIDKeyCombination - invalid upload id and key marker combination.
 type InvalidUploadIDKeyCombination struct {
 	UploadIDMarker, KeyMarker string
@@ -214,6 +266,10 @@ func (e InvalidUploadIDKeyCombination) Error() string {
 	return fmt.Sprintf("Invalid combination of uploadID marker '%s' and marker '%s'", e.UploadIDMarker, e.KeyMarker)
 }
 
+func (e InvalidUploadIDKeyCombination) APIErrorCode() APIErrorCode {
+	return ErrNotImplemented
+}
+
 // InvalidMarkerPrefixCombination - invalid marker and prefix combination.
 type InvalidMarkerPrefixCombination struct {
 	Marker, Prefix string
@@ -223,6 +279,10 @@ func (e InvalidMarkerPrefixCombination) Error() string {
 	return fmt.Sprintf("Invalid combination of marker '%s' and prefix '%s'", e.Marker, e.Prefix)
 }
 
+func (e InvalidMarkerPrefixCombination) APIErrorCode() APIErrorCode {
+	return ErrNotImplemented
+}
+
 // BucketPolicyNotFound - no bucket policy found.
 type BucketPolicyNotFound GenericError
 
@@ -240,6 +300,10 @@ func (e BucketNameInvalid) Error() string {
 	return "Bucket name invalid: " + e.Bucket
 }
 
+func (e BucketNameInvalid) APIErrorCode() APIErrorCode {
+	return ErrInvalidBucketName
+}
+
 /// Object related errors.
 
 // ObjectNameInvalid - object name provided is invalid.
@@ -250,6 +314,10 @@ func (e ObjectNameInvalid) Error() string {
 	return "Object name invalid: " + e.Bucket + "#" + e.Object
 }
 
+func (e ObjectNameInvalid) APIErrorCode() APIErrorCode {
+	return ErrInvalidObjectName
+}
+
 // IncompleteBody You did not provide the number of bytes specified by the Content-Length HTTP header.
 type IncompleteBody GenericError
 
@@ -258,6 +326,10 @@ func (e IncompleteBody) Error() string {
 	return e.Bucket + "#" + e.Object + "has incomplete body"
 }
 
+func (e IncompleteBody) APIErrorCode() APIErrorCode {
+	return ErrIncompleteBody
+}
+
 // InvalidRange - invalid range typed error.
 type InvalidRange struct {
 	offsetBegin  int64
@@ -276,6 +348,22 @@ func (e ObjectTooLarge) Error() string {
 	return "size of the object greater than what is allowed(5G)"
 }
 
+func (e ObjectTooLarge) APIErrorCode() APIErrorCode {
+	return ErrEntityTooLarge
+}
+
+// PreconditionFailed error returned when the object does not meet the
+// preconditions of a conditional request.
+type PreconditionFailed GenericError
+
+func (e PreconditionFailed) Error() string {
+	return "At least one of the preconditions you specified did not hold for " + e.Bucket + "#" + e.Object
+}
+
+func (e PreconditionFailed) APIErrorCode() APIErrorCode {
+	return ErrPreconditionFailed
+}
+
 // ObjectTooSmall error returned when the size of the object < what is expected.
 type ObjectTooSmall GenericError
 
@@ -283,6 +371,10 @@ func (e ObjectTooSmall) Error() string {
 	return "size of the object less than what is expected"
 }
 
+func (e ObjectTooSmall) APIErrorCode() APIErrorCode {
+	return ErrEntityTooSmall
+}
+
 /// Multipart related errors.
 
 // MalformedUploadID malformed upload id.
@@ -294,6 +386,10 @@ func (e MalformedUploadID) Error() string {
 	return "Malformed upload id " + e.UploadID
 }
 
+func (e MalformedUploadID) APIErrorCode() APIErrorCode {
+	return ErrNoSuchUpload
+}
+
 // InvalidUploadID invalid upload id.
 type InvalidUploadID struct {
 	UploadID string
@@ -303,6 +399,10 @@ func (e InvalidUploadID) Error() string {
 	return "Invalid upload id " + e.UploadID
 }
 
+func (e InvalidUploadID) APIErrorCode() APIErrorCode {
+	return ErrNoSuchUpload
+}
+
 // InvalidPart One or more of the specified parts could not be found
 type InvalidPart struct{}
 
@@ -310,15 +410,24 @@ func (e InvalidPart) Error() string {
 	return "One or more of the specified parts could not be found"
 }
 
-// PartTooSmall - error if part size is less than 5MB.
+func (e InvalidPart) APIErrorCode() APIErrorCode {
+	return ErrInvalidPart
+}
+
+// PartTooSmall - error if part size is less than minimum part size.
 type PartTooSmall struct {
-	PartSize   int64
-	PartNumber int
-	PartETag   string
+	PartSize       int64
+	PartNumber     int
+	PartETag       string
+	MinSizeAllowed int64
 }
 
 func (e PartTooSmall) Error() string {
-	return fmt.Sprintf("Part size for %d should be atleast 5MB", e.PartNumber)
+	return fmt.Sprintf("Part size for %d should be atleast %d bytes", e.PartNumber, e.MinSizeAllowed)
+}
+
+func (e PartTooSmall) APIErrorCode() APIErrorCode {
+	return ErrEntityTooSmall
 }
 
 // NotImplemented If a feature is not implemented
@@ -328,6 +437,10 @@ func (e NotImplemented) Error() string {
 	return "Not Implemented"
 }
 
+func (e NotImplemented) APIErrorCode() APIErrorCode {
+	return ErrNotImplemented
+}
+
 // NotSupported If a feature is not supported
 type NotSupported struct{}
 
@@ -335,6 +448,10 @@ func (e NotSupported) Error() string {
 	return "Not Supported"
 }
 
+func (e NotSupported) APIErrorCode() APIErrorCode {
+	return ErrNotSupported
+}
+
 // PolicyNesting - policy nesting conflict.
 type PolicyNesting struct{}
 
@@ -342,6 +459,10 @@ func (e PolicyNesting) Error() string {
 	return "New bucket policy conflicts with an existing policy. Please try again with new prefix."
 }
 
+func (e PolicyNesting) APIErrorCode() APIErrorCode {
+	return ErrPolicyNesting
+}
+
 // PolicyNotFound - policy not found
 type PolicyNotFound GenericError
 
@@ -349,6 +470,10 @@ func (e PolicyNotFound) Error() string {
 	return "Policy not found"
 }
 
+func (e PolicyNotFound) APIErrorCode() APIErrorCode {
+	return ErrNoSuchBucketPolicy
+}
+
 // Check if error type is IncompleteBody.
 func isErrIncompleteBody(err error) bool {
 	err = errorCause(err)
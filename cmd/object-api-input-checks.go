@@ -87,10 +87,7 @@ func checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter
 			})
 		}
 		id, err := uuid.Parse(uploadIDMarker)
-		if err != nil {
-			return traceError(err)
-		}
-		if id.IsZero() {
+		if err != nil || id.IsZero() {
 			return traceError(MalformedUploadID{
 				UploadID: uploadIDMarker,
 			})
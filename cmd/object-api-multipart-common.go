@@ -161,8 +161,8 @@ func writeUploadJSON(u *uploadsV1, uploadsPath, tmpPath string, disk StorageAPI)
 }
 
 // listMultipartUploadIDs - list all the upload ids from a marker up to 'count'.
-func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int, disk StorageAPI) ([]uploadMetadata, bool, error) {
-	var uploads []uploadMetadata
+func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count int, disk StorageAPI) ([]UploadMetadata, bool, error) {
+	var uploads []UploadMetadata
 	// Read `uploads.json`.
 	uploadsJSON, err := readUploadsJSON(bucketName, objectName, disk)
 	if err != nil {
@@ -179,7 +179,7 @@ func listMultipartUploadIDs(bucketName, objectName, uploadIDMarker string, count
 		}
 	}
 	for index < len(uploadsJSON.Uploads) {
-		uploads = append(uploads, uploadMetadata{
+		uploads = append(uploads, UploadMetadata{
 			Object:    objectName,
 			UploadID:  uploadsJSON.Uploads[index].UploadID,
 			Initiated: uploadsJSON.Uploads[index].Initiated,
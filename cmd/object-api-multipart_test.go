@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	humanize "github.com/dustin/go-humanize"
@@ -290,41 +291,41 @@ func testObjectAPIPutObjectPart(obj ObjectLayer, instanceType string, t TestErrH
 	}{
 		// Test case  1-4.
 		// Cases with invalid bucket name.
-		{".test", "obj", "", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Bucket name invalid: .test")},
-		{"------", "obj", "", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Bucket name invalid: ------")},
+		{".test", "obj", "", 1, "", "", "", 0, false, "", BucketNameInvalid{Bucket: ".test"}},
+		{"------", "obj", "", 1, "", "", "", 0, false, "", BucketNameInvalid{Bucket: "------"}},
 		{"$this-is-not-valid-too", "obj", "", 1, "", "", "", 0, false, "",
-			fmt.Errorf("%s", "Bucket name invalid: $this-is-not-valid-too")},
-		{"a", "obj", "", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Bucket name invalid: a")},
+			BucketNameInvalid{Bucket: "$this-is-not-valid-too"}},
+		{"a", "obj", "", 1, "", "", "", 0, false, "", BucketNameInvalid{Bucket: "a"}},
 		// Test case - 5.
 		// Case with invalid object names.
-		{bucket, "", "", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Object name invalid: minio-bucket#")},
+		{bucket, "", "", 1, "", "", "", 0, false, "", ObjectNameInvalid{Bucket: bucket}},
 		// Test case - 6.
 		// Valid object and bucket names but non-existent bucket.
-		{"abc", "def", "", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Bucket not found: abc")},
+		{"abc", "def", "", 1, "", "", "", 0, false, "", BucketNotFound{Bucket: "abc"}},
 		// Test Case - 7.
 		// Existing bucket, but using a bucket on which NewMultipartUpload is not Initiated.
-		{"unused-bucket", "def", "xyz", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Invalid upload id xyz")},
+		{"unused-bucket", "def", "xyz", 1, "", "", "", 0, false, "", InvalidUploadID{UploadID: "xyz"}},
 		// Test Case - 8.
 		// Existing bucket, object name different from which NewMultipartUpload is constructed from.
 		// Expecting "Invalid upload id".
-		{bucket, "def", "xyz", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Invalid upload id xyz")},
+		{bucket, "def", "xyz", 1, "", "", "", 0, false, "", InvalidUploadID{UploadID: "xyz"}},
 		// Test Case - 9.
 		// Existing bucket, bucket and object name are the ones from which NewMultipartUpload is constructed from.
 		// But the uploadID is invalid.
 		// Expecting "Invalid upload id".
-		{bucket, object, "xyz", 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Invalid upload id xyz")},
+		{bucket, object, "xyz", 1, "", "", "", 0, false, "", InvalidUploadID{UploadID: "xyz"}},
 		// Test Case - 10.
 		// Case with valid UploadID, existing bucket name.
 		// But using the bucket name from which NewMultipartUpload is not constructed from.
-		{"unused-bucket", object, uploadID, 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Invalid upload id "+uploadID)},
+		{"unused-bucket", object, uploadID, 1, "", "", "", 0, false, "", InvalidUploadID{UploadID: uploadID}},
 		// Test Case - 11.
 		// Case with valid UploadID, existing bucket name.
 		// But using the object name from which NewMultipartUpload is not constructed from.
-		{bucket, "none-object", uploadID, 1, "", "", "", 0, false, "", fmt.Errorf("%s", "Invalid upload id "+uploadID)},
+		{bucket, "none-object", uploadID, 1, "", "", "", 0, false, "", InvalidUploadID{UploadID: uploadID}},
 		// Test case - 12.
 		// Input to replicate Md5 mismatch.
 		{bucket, object, uploadID, 1, "", "a35", "", 0, false, "",
-			fmt.Errorf("%s", "Bad digest: Expected a35 is not valid with what we calculated "+"d41d8cd98f00b204e9800998ecf8427e")},
+			BadDigest{ExpectedMD5: "a35", CalculatedMD5: "d41d8cd98f00b204e9800998ecf8427e"}},
 		// Test case - 13.
 		// When incorrect sha256 is provided.
 		{bucket, object, uploadID, 1, "", "", "incorrect-sha256", 0, false, "", SHA256Mismatch{}},
@@ -334,7 +335,7 @@ func testObjectAPIPutObjectPart(obj ObjectLayer, instanceType string, t TestErrH
 		// Test case - 15.
 		// Input with size less than the size of actual data inside the reader.
 		{bucket, object, uploadID, 1, "abcd", "a35", "", int64(len("abcd") - 1), false, "",
-			fmt.Errorf("%s", "Bad digest: Expected a35 is not valid with what we calculated 900150983cd24fb0d6963f7d28e17f72")},
+			BadDigest{ExpectedMD5: "a35", CalculatedMD5: "900150983cd24fb0d6963f7d28e17f72"}},
 
 		// Test case - 16-19.
 		// Validating for success cases.
@@ -370,6 +371,62 @@ func testObjectAPIPutObjectPart(obj ObjectLayer, instanceType string, t TestErrH
 	}
 }
 
+// Wrapper for calling PutObjectPart parallel tests for both XL multiple disks and single node setup.
+func TestObjectAPIPutObjectPartParallel(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectAPIPutObjectPartParallel)
+}
+
+// Tests validate parallel uploads of the parts of a multipart upload.
+func testObjectAPIPutObjectPartParallel(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "minio-bucket"
+	object := "minio-object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	uploadID, err := obj.NewMultipartUpload(bucket, object, nil)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	// Upload the parts in parallel, the same way as the AWS SDKs do,
+	// with every part number also uploaded twice at the same time.
+	const partCount = 10
+	var wg sync.WaitGroup
+	errs := make([]error, 2*partCount)
+	for i := 0; i < 2*partCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			partID := i%partCount + 1
+			data := fmt.Sprintf("part-%d", partID)
+			_, errs[i] = obj.PutObjectPart(bucket, object, uploadID, partID, int64(len(data)), bytes.NewBufferString(data), getMD5Hash([]byte(data)), "")
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("%s: Upload %d failed with: %s", instanceType, i+1, err)
+		}
+	}
+
+	listInfo, err := obj.ListObjectParts(bucket, object, uploadID, 0, partCount+1)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+	if len(listInfo.Parts) != partCount {
+		t.Fatalf("%s: Expected %d parts, but found %d", instanceType, partCount, len(listInfo.Parts))
+	}
+	for i, part := range listInfo.Parts {
+		data := fmt.Sprintf("part-%d", i+1)
+		if part.PartNumber != i+1 {
+			t.Errorf("%s: Expected part number %d, but found %d", instanceType, i+1, part.PartNumber)
+		}
+		if part.ETag != getMD5Hash([]byte(data)) {
+			t.Errorf("%s: Part %d: Expected ETag %s, but found %s", instanceType, i+1, getMD5Hash([]byte(data)), part.ETag)
+		}
+	}
+}
+
 // Wrapper for calling TestListMultipartUploads tests for both XL multiple disks and single node setup.
 func TestListMultipartUploads(t *testing.T) {
 	ExecObjectLayerTest(t, testListMultipartUploads)
@@ -494,7 +551,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// ListMultipartUploads doesn't list the parts.
 		{
 			MaxUploads: 100,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -510,7 +567,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			KeyMarker:  "minio-object-1.txt",
 		},
 		// listMultipartResults - 3.
-		// `KeyMarker` is set, no uploadMetadata expected.
+		// `KeyMarker` is set, no UploadMetadata expected.
 		// ListMultipartUploads doesn't list the parts.
 		// `Maxupload` value is asserted.
 		{
@@ -518,7 +575,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			KeyMarker:  "orange",
 		},
 		// listMultipartResults - 4.
-		// `KeyMarker` is set, no uploadMetadata expected.
+		// `KeyMarker` is set, no UploadMetadata expected.
 		// Maxupload value is asserted.
 		{
 			MaxUploads: 1,
@@ -526,12 +583,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		},
 		// listMultipartResults - 5.
 		// `KeyMarker` is set. It contains part of the objectname as `KeyPrefix`.
-		// Expecting the result to contain one uploadMetadata entry and Istruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and Istruncated to be false.
 		{
 			MaxUploads:  10,
 			KeyMarker:   "min",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -541,12 +598,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 6.
 		// `KeyMarker` is set. It contains part of the objectname as `KeyPrefix`.
 		// `MaxUploads` is set equal to the number of meta data entries in the result, the result contains only one entry.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  1,
 			KeyMarker:   "min",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -556,7 +613,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 7.
 		// `KeyMarker` is set. It contains part of the objectname as `KeyPrefix`.
 		// Testing for the case with `MaxUploads` set to 0.
-		// Expecting the result to contain no uploadMetadata entry since `MaxUploads` is set to 0.
+		// Expecting the result to contain no UploadMetadata entry since `MaxUploads` is set to 0.
 		// Expecting `IsTruncated` to be true.
 		{
 			MaxUploads:  0,
@@ -566,7 +623,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 8.
 		// `KeyMarker` is set. It contains part of the objectname as KeyPrefix.
 		// Testing for the case with `MaxUploads` set to 0.
-		// Expecting the result to contain no uploadMetadata entry since `MaxUploads` is set to 0.
+		// Expecting the result to contain no UploadMetadata entry since `MaxUploads` is set to 0.
 		// Expecting `isTruncated` to be true.
 		{
 			MaxUploads:  0,
@@ -576,12 +633,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 9.
 		// `KeyMarker` is set. It contains part of the objectname as KeyPrefix.
 		// `KeyMarker` is set equal to the object name in the result.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  2,
 			KeyMarker:   "minio-object",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -591,12 +648,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 10.
 		// Prefix is set. It is set equal to the object name.
 		// MaxUploads is set more than number of meta data entries in the result.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  2,
 			Prefix:      "minio-object-1.txt",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -606,12 +663,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 11.
 		// Setting `Prefix` to contain the object name as its prefix.
 		// MaxUploads is set more than number of meta data entries in the result.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  2,
 			Prefix:      "min",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -621,12 +678,12 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 12.
 		// Setting `Prefix` to contain the object name as its prefix.
 		// MaxUploads is set equal to number of meta data entries in the result.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  1,
 			Prefix:      "min",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -654,13 +711,13 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// listMultipartResults - 15.
 		// Setting `Delimiter`.
 		// MaxUploads is set more than number of meta data entries in the result.
-		// Expecting the result to contain one uploadMetadata entry and IsTruncated to be false.
+		// Expecting the result to contain one UploadMetadata entry and IsTruncated to be false.
 		{
 			MaxUploads:  2,
 			Delimiter:   "/",
 			Prefix:      "",
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[0],
@@ -672,7 +729,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		// Will be used to list on bucketNames[1].
 		{
 			MaxUploads: 100,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[1],
@@ -698,7 +755,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			KeyMarker:      "minio-object-1.txt",
 			UploadIDMarker: uploadIDs[1],
 			IsTruncated:    false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[2],
@@ -720,7 +777,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			KeyMarker:      "minio-object-1.txt",
 			UploadIDMarker: uploadIDs[2],
 			IsTruncated:    false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[3],
@@ -729,7 +786,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		},
 		// listMultipartResults - 19.
 		// Testing for listing of 3 uploadID's for a given object, setting maxKeys to be 2.
-		// There are 3 uploadMetadata in the result (uploadIDs[1-3]), it should be truncated to 2.
+		// There are 3 UploadMetadata in the result (uploadIDs[1-3]), it should be truncated to 2.
 		// Since there is only single object for bucketNames[1], the NextKeyMarker is set to its name.
 		// The last entry in the result, uploadIDs[2], that is should be set as NextUploadIDMarker.
 		// Will be used to list on bucketNames[1].
@@ -738,7 +795,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			IsTruncated:        true,
 			NextKeyMarker:      objectNames[0],
 			NextUploadIDMarker: uploadIDs[2],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[1],
@@ -751,7 +808,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		},
 		// listMultipartResults - 20.
 		// Testing for listing of 3 uploadID's for a given object, setting maxKeys to be 1.
-		// There are 3 uploadMetadata in the result (uploadIDs[1-3]), it should be truncated to 1.
+		// There are 3 UploadMetadata in the result (uploadIDs[1-3]), it should be truncated to 1.
 		// The last entry in the result, uploadIDs[1], that is should be set as NextUploadIDMarker.
 		// Will be used to list on bucketNames[1].
 		{
@@ -759,7 +816,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			IsTruncated:        true,
 			NextKeyMarker:      objectNames[0],
 			NextUploadIDMarker: uploadIDs[1],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[1],
@@ -768,13 +825,13 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		},
 		// listMultipartResults - 21.
 		// Testing for listing of 3 uploadID's for a given object, setting maxKeys to be 3.
-		// There are 3 uploadMetadata in the result (uploadIDs[1-3]), hence no truncation is expected.
-		// Since all the uploadMetadata is listed, expecting no values for NextUploadIDMarker and NextKeyMarker.
+		// There are 3 UploadMetadata in the result (uploadIDs[1-3]), hence no truncation is expected.
+		// Since all the UploadMetadata is listed, expecting no values for NextUploadIDMarker and NextKeyMarker.
 		// Will be used to list on bucketNames[1].
 		{
 			MaxUploads:  3,
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[1],
@@ -796,7 +853,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  10,
 			IsTruncated: false,
 			Prefix:      "min",
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[1],
@@ -839,7 +896,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			IsTruncated:    false,
 			Prefix:         "min",
 			UploadIDMarker: uploadIDs[1],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[2],
@@ -858,7 +915,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  100,
 			IsTruncated: false,
 
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[4],
@@ -891,7 +948,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  100,
 			IsTruncated: false,
 			Prefix:      "min",
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[4],
@@ -908,7 +965,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  100,
 			IsTruncated: false,
 			Prefix:      "ney",
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[2],
 					UploadID: uploadIDs[6],
@@ -925,7 +982,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  100,
 			IsTruncated: false,
 			Prefix:      "parrot",
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[4],
 					UploadID: uploadIDs[8],
@@ -943,7 +1000,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  100,
 			IsTruncated: false,
 			Prefix:      "neymar.jpeg",
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[3],
 					UploadID: uploadIDs[7],
@@ -960,7 +1017,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			IsTruncated:        true,
 			NextUploadIDMarker: uploadIDs[6],
 			NextKeyMarker:      objectNames[2],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[4],
@@ -982,7 +1039,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		{
 			MaxUploads:  6,
 			IsTruncated: false,
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[0],
 					UploadID: uploadIDs[4],
@@ -1015,7 +1072,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:     10,
 			IsTruncated:    false,
 			UploadIDMarker: uploadIDs[6],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[3],
 					UploadID: uploadIDs[7],
@@ -1036,7 +1093,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			MaxUploads:  10,
 			IsTruncated: false,
 			KeyMarker:   objectNames[3],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[4],
 					UploadID: uploadIDs[8],
@@ -1049,7 +1106,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		},
 		// listMultipartResults - 35.
 		// Checking listing with `Prefix` and `KeyMarker`.
-		// No upload uploadMetadata in the result expected since KeyMarker is set to last Key in the result.
+		// No upload UploadMetadata in the result expected since KeyMarker is set to last Key in the result.
 		{
 			MaxUploads:  10,
 			IsTruncated: false,
@@ -1063,7 +1120,7 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 			IsTruncated:    false,
 			Prefix:         globalMinioDefaultOwnerID,
 			UploadIDMarker: uploadIDs[4],
-			Uploads: []uploadMetadata{
+			Uploads: []UploadMetadata{
 				{
 					Object:   objectNames[1],
 					UploadID: uploadIDs[5],
@@ -1107,17 +1164,17 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		{"volatile-bucket-3", "", "", "", "", 0, ListMultipartsInfo{}, BucketNotFound{Bucket: "volatile-bucket-3"}, false},
 		// Valid, existing bucket, but sending invalid delimeter values (Test number 8-9).
 		// Empty string < "" > and forward slash < / > are the ony two valid arguments for delimeter.
-		{bucketNames[0], "", "", "", "*", 0, ListMultipartsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "*"), false},
-		{bucketNames[0], "", "", "", "-", 0, ListMultipartsInfo{}, fmt.Errorf("delimiter '%s' is not supported", "-"), false},
+		{bucketNames[0], "", "", "", "*", 0, ListMultipartsInfo{}, UnsupportedDelimiter{Delimiter: "*"}, false},
+		{bucketNames[0], "", "", "", "-", 0, ListMultipartsInfo{}, UnsupportedDelimiter{Delimiter: "-"}, false},
 		// Testing for failure cases with both perfix and marker (Test number 10).
 		// The prefix and marker combination to be valid it should satisfy strings.HasPrefix(marker, prefix).
 		{bucketNames[0], "asia", "europe-object", "", "", 0, ListMultipartsInfo{},
-			fmt.Errorf("Invalid combination of marker '%s' and prefix '%s'", "europe-object", "asia"), false},
+			InvalidMarkerPrefixCombination{Marker: "europe-object", Prefix: "asia"}, false},
 		// Setting an invalid combination of uploadIDMarker and Marker (Test number 11-12).
 		{bucketNames[0], "asia", "asia/europe/", "abc", "", 0, ListMultipartsInfo{},
-			fmt.Errorf("Invalid combination of uploadID marker '%s' and marker '%s'", "abc", "asia/europe/"), false},
+			InvalidUploadIDKeyCombination{UploadIDMarker: "abc", KeyMarker: "asia/europe/"}, false},
 		{bucketNames[0], "asia", "asia/europe", "abc", "", 0, ListMultipartsInfo{},
-			fmt.Errorf("unknown UUID string %s", "abc"), false},
+			MalformedUploadID{UploadID: "abc"}, false},
 
 		// Setting up valid case of ListMultiPartUploads.
 		// Test case with multiple parts for a single uploadID (Test number 13).
@@ -1155,11 +1212,11 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 		{bucketNames[1], "", "minio-object-1.txt", uploadIDs[1], "", 100, listMultipartResults[16], nil, true},
 		{bucketNames[1], "", "minio-object-1.txt", uploadIDs[2], "", 100, listMultipartResults[17], nil, true},
 		// Test cases with multiple uploadID listing for a given object (Test number 31-32).
-		// MaxKeys set to values lesser than the number of entries in the uploadMetadata.
+		// MaxKeys set to values lesser than the number of entries in the UploadMetadata.
 		// IsTruncated is expected to be true.
 		{bucketNames[1], "", "", "", "", 2, listMultipartResults[18], nil, true},
 		{bucketNames[1], "", "", "", "", 1, listMultipartResults[19], nil, true},
-		// MaxKeys set to the value which is equal to no of entries in the uploadMetadata (Test number 33).
+		// MaxKeys set to the value which is equal to no of entries in the UploadMetadata (Test number 33).
 		// In case of bucketNames[1], there are 3 entries.
 		// Since all available entries are listed, IsTruncated is expected to be false
 		// and NextMarkers are expected to empty.
@@ -1817,19 +1874,19 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 	}
 	// Parts to be sent as input for CompleteMultipartUpload.
 	inputParts := []struct {
-		parts []completePart
+		parts []CompletePart
 	}{
 		// inputParts - 0.
 		// Case for replicating ETag mismatch.
 		{
-			[]completePart{
+			[]CompletePart{
 				{ETag: "abcd", PartNumber: 1},
 			},
 		},
 		// inputParts - 1.
 		// should error out with part too small.
 		{
-			[]completePart{
+			[]CompletePart{
 				{ETag: "e2fc714c4727ee9395f324cd2e7f331f", PartNumber: 1},
 				{ETag: "1f7690ebdd9b4caf8fab49ca1757bf27", PartNumber: 2},
 			},
@@ -1837,7 +1894,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		// inputParts - 2.
 		// Case with invalid Part number.
 		{
-			[]completePart{
+			[]CompletePart{
 				{ETag: "e2fc714c4727ee9395f324cd2e7f331f", PartNumber: 10},
 			},
 		},
@@ -1845,7 +1902,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		// Case with valid part.
 		// Part size greater than 5MB.
 		{
-			[]completePart{
+			[]CompletePart{
 				{ETag: validPartMD5, PartNumber: 5},
 			},
 		},
@@ -1853,7 +1910,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		// Used to verify that the other remaining parts are deleted after
 		// a successful call to CompleteMultipartUpload.
 		{
-			[]completePart{
+			[]CompletePart{
 				{ETag: validPartMD5, PartNumber: 6},
 			},
 		},
@@ -1868,7 +1925,7 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		bucket   string
 		object   string
 		uploadID string
-		parts    []completePart
+		parts    []CompletePart
 		// Expected output of CompleteMultipartUpload.
 		expectedS3MD5 string
 		expectedErr   error
@@ -1876,36 +1933,36 @@ func testObjectCompleteMultipartUpload(obj ObjectLayer, instanceType string, t T
 		shouldPass bool
 	}{
 		// Test cases with invalid bucket names (Test number 1-4).
-		{".test", "", "", []completePart{}, "", BucketNameInvalid{Bucket: ".test"}, false},
-		{"Test", "", "", []completePart{}, "", BucketNameInvalid{Bucket: "Test"}, false},
-		{"---", "", "", []completePart{}, "", BucketNameInvalid{Bucket: "---"}, false},
-		{"ad", "", "", []completePart{}, "", BucketNameInvalid{Bucket: "ad"}, false},
+		{".test", "", "", []CompletePart{}, "", BucketNameInvalid{Bucket: ".test"}, false},
+		{"Test", "", "", []CompletePart{}, "", BucketNameInvalid{Bucket: "Test"}, false},
+		{"---", "", "", []CompletePart{}, "", BucketNameInvalid{Bucket: "---"}, false},
+		{"ad", "", "", []CompletePart{}, "", BucketNameInvalid{Bucket: "ad"}, false},
 		// Test cases for listing uploadID with single part.
 		// Valid bucket names, but they donot exist (Test number 5-7).
-		{"volatile-bucket-1", "", "", []completePart{}, "", BucketNotFound{Bucket: "volatile-bucket-1"}, false},
-		{"volatile-bucket-2", "", "", []completePart{}, "", BucketNotFound{Bucket: "volatile-bucket-2"}, false},
-		{"volatile-bucket-3", "", "", []completePart{}, "", BucketNotFound{Bucket: "volatile-bucket-3"}, false},
+		{"volatile-bucket-1", "", "", []CompletePart{}, "", BucketNotFound{Bucket: "volatile-bucket-1"}, false},
+		{"volatile-bucket-2", "", "", []CompletePart{}, "", BucketNotFound{Bucket: "volatile-bucket-2"}, false},
+		{"volatile-bucket-3", "", "", []CompletePart{}, "", BucketNotFound{Bucket: "volatile-bucket-3"}, false},
 		// Test case for Asserting for invalid objectName (Test number 8).
-		{bucketNames[0], "", "", []completePart{}, "", ObjectNameInvalid{Bucket: bucketNames[0]}, false},
+		{bucketNames[0], "", "", []CompletePart{}, "", ObjectNameInvalid{Bucket: bucketNames[0]}, false},
 		// Asserting for Invalid UploadID (Test number 9).
-		{bucketNames[0], objectNames[0], "abc", []completePart{}, "", InvalidUploadID{UploadID: "abc"}, false},
+		{bucketNames[0], objectNames[0], "abc", []CompletePart{}, "", InvalidUploadID{UploadID: "abc"}, false},
 		// Test case with invalid Part Etag (Test number 10-11).
-		{bucketNames[0], objectNames[0], uploadIDs[0], []completePart{{ETag: "abc"}}, "", fmt.Errorf("encoding/hex: odd length hex string"), false},
-		{bucketNames[0], objectNames[0], uploadIDs[0], []completePart{{ETag: "abcz"}}, "", fmt.Errorf("encoding/hex: invalid byte: U+007A 'z'"), false},
+		{bucketNames[0], objectNames[0], uploadIDs[0], []CompletePart{{ETag: "abc"}}, "", InvalidPart{}, false},
+		{bucketNames[0], objectNames[0], uploadIDs[0], []CompletePart{{ETag: "abcz"}}, "", InvalidPart{}, false},
 		// Part number 0 doesn't exist, expecting InvalidPart error (Test number 12).
-		{bucketNames[0], objectNames[0], uploadIDs[0], []completePart{{ETag: "abcd", PartNumber: 0}}, "", InvalidPart{}, false},
+		{bucketNames[0], objectNames[0], uploadIDs[0], []CompletePart{{ETag: "abcd", PartNumber: 0}}, "", InvalidPart{}, false},
 		// // Upload and PartNumber exists, But a deliberate ETag mismatch is introduced (Test number 13).
 		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[0].parts, "", BadDigest{}, false},
 		// Test case with non existent object name (Test number 14).
-		{bucketNames[0], "my-object", uploadIDs[0], []completePart{{ETag: "abcd", PartNumber: 1}}, "", InvalidUploadID{UploadID: uploadIDs[0]}, false},
+		{bucketNames[0], "my-object", uploadIDs[0], []CompletePart{{ETag: "abcd", PartNumber: 1}}, "", InvalidUploadID{UploadID: uploadIDs[0]}, false},
 		// Testing for Part being too small (Test number 15).
-		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[1].parts, "", PartTooSmall{PartNumber: 1}, false},
+		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[1].parts, "", PartTooSmall{PartNumber: 1, MinSizeAllowed: globalMinPartSize}, false},
 		// TestCase with invalid Part Number (Test number 16).
 		// Should error with Invalid Part .
 		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[2].parts, "", InvalidPart{}, false},
 		// Test case with unsorted parts (Test number 17).
 		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[3].parts, s3MD5, nil, true},
-		// The other parts will be flushed after a successful completePart (Test number 18).
+		// The other parts will be flushed after a successful CompletePart (Test number 18).
 		// the case above successfully completes CompleteMultipartUpload, the remaining Parts will be flushed.
 		// Expecting to fail with Invalid UploadID.
 		{bucketNames[0], objectNames[0], uploadIDs[0], inputParts[4].parts, "", InvalidUploadID{UploadID: uploadIDs[0]}, false},
@@ -162,18 +162,18 @@ func TestRangeReader(t *testing.T) {
 // Tests getCompleteMultipartMD5
 func TestGetCompleteMultipartMD5(t *testing.T) {
 	testCases := []struct {
-		parts          []completePart
+		parts          []CompletePart
 		expectedResult string
 		expectedErr    string
 	}{
 		// Wrong MD5 hash string
-		{[]completePart{{ETag: "wrong-md5-hash-string"}}, "", "encoding/hex: odd length hex string"},
+		{[]CompletePart{{ETag: "wrong-md5-hash-string"}}, "", InvalidPart{}.Error()},
 
-		// Single completePart with valid MD5 hash string.
-		{[]completePart{{ETag: "cf1f738a5924e645913c984e0fe3d708"}}, "10dc1617fbcf0bd0858048cb96e6bd77-1", ""},
+		// Single CompletePart with valid MD5 hash string.
+		{[]CompletePart{{ETag: "cf1f738a5924e645913c984e0fe3d708"}}, "10dc1617fbcf0bd0858048cb96e6bd77-1", ""},
 
-		// Multiple completePart with valid MD5 hash string.
-		{[]completePart{{ETag: "cf1f738a5924e645913c984e0fe3d708"}, {ETag: "9ccbc9a80eee7fb6fdd22441db2aedbd"}}, "0239a86b5266bb624f0ac60ba2aed6c8-2", ""},
+		// Multiple CompletePart with valid MD5 hash string.
+		{[]CompletePart{{ETag: "cf1f738a5924e645913c984e0fe3d708"}, {ETag: "9ccbc9a80eee7fb6fdd22441db2aedbd"}}, "0239a86b5266bb624f0ac60ba2aed6c8-2", ""},
 	}
 
 	for i, test := range testCases {
@@ -0,0 +1,281 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	router "github.com/gorilla/mux"
+)
+
+// Compose is a Minio extension creating an object from existing
+// objects of the same bucket, concatenated in the given order
+//
+//	POST /bucket/object?compose
+//
+//	<ComposeObject>
+//	  <Source><Key>logs/part1</Key></Source>
+//	  <Source><Key>logs/part2</Key></Source>
+//	</ComposeObject>
+const (
+	composeQuery = "compose"
+
+	// Maximum number of sources of a composed object.
+	maxComposeSources = 32
+
+	// Maximum size of the XML body listing the sources.
+	maxComposeRequestSize = 1 << 20
+)
+
+// Internal error used to signal sources of an object can't be
+// composed by an object layer without copying their data.
+var errObjectNotComposable = errors.New("Sources can't be composed without copying their data")
+
+// objectComposer is implemented by object layers able to compose an
+// object from the data of its sources as it is stored.
+type objectComposer interface {
+	ComposeObject(bucket string, srcObjects []string, object string, metadata map[string]string) (ObjectInfo, error)
+}
+
+// ComposeSource - a source object of a compose request.
+type ComposeSource struct {
+	Key string
+}
+
+// ComposeObjectRequest - format of compose request body.
+type ComposeObjectRequest struct {
+	XMLName xml.Name        `xml:"ComposeObject" json:"-"`
+	Sources []ComposeSource `xml:"Source"`
+}
+
+// ComposeObjectResponse - format for compose response.
+type ComposeObjectResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ComposeObjectResult" json:"-"`
+	LastModified string   // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string
+	Size         int64
+}
+
+// getComposedObjectMD5 - returns the ETag of an object composed from
+// sources with the given ETags without reading their data, computed
+// like multipart ETags from the md5sum of the source ETags.
+func getComposedObjectMD5(etags []string) (string, error) {
+	var md5Bytes []byte
+	for _, etag := range etags {
+		// ETags of multipart objects end with the parts count.
+		if i := strings.Index(etag, "-"); i >= 0 {
+			etag = etag[:i]
+		}
+		sum, err := hex.DecodeString(etag)
+		if err != nil {
+			return "", traceError(err)
+		}
+		md5Bytes = append(md5Bytes, sum...)
+	}
+	return fmt.Sprintf("%s-%d", getMD5Hash(md5Bytes), len(etags)), nil
+}
+
+// composeObjectByCopy - composes an object by streaming the data of
+// sources into a new object.
+func composeObjectByCopy(objAPI ObjectLayer, bucket string, sources []ObjectInfo, object string, metadata map[string]string) (ObjectInfo, error) {
+	var size int64
+	for _, source := range sources {
+		size += source.Size
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		for _, source := range sources {
+			if err := objAPI.GetObject(bucket, source.Name, 0, source.Size, pipeWriter); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+		pipeWriter.Close()
+	}()
+	defer pipeReader.Close()
+
+	return objAPI.PutObject(bucket, object, size, pipeReader, metadata, "")
+}
+
+// composeObject - composes an object from sources, without copying
+// their data if the object layer supports it.
+func composeObject(objAPI ObjectLayer, bucket string, sources []ObjectInfo, object string, metadata map[string]string) (ObjectInfo, error) {
+	if composer, ok := objAPI.(objectComposer); ok {
+		srcObjects := make([]string, len(sources))
+		for i, source := range sources {
+			srcObjects[i] = source.Name
+		}
+		objInfo, err := composer.ComposeObject(bucket, srcObjects, object, metadata)
+		if errorCause(err) != errObjectNotComposable {
+			return objInfo, err
+		}
+	}
+	return composeObjectByCopy(objAPI, bucket, sources, object, metadata)
+}
+
+// ComposeObjectHandler - POST /bucket/object?compose
+// ----------
+// Creates an object from the concatenated data of objects of the same
+// bucket, metadata of the object is taken from request headers.
+func (api objectAPIHandlers) ComposeObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := router.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	composeXML, err := ioutil.ReadAll(io.LimitReader(r.Body, maxComposeRequestSize))
+	if err != nil {
+		reqErrorIf(r, err, "Unable to read compose request body.")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	var composeReq ComposeObjectRequest
+	if err = xml.Unmarshal(composeXML, &composeReq); err != nil {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if len(composeReq.Sources) == 0 || len(composeReq.Sources) > maxComposeSources {
+		writeErrorResponse(w, ErrInvalidComposeSources, r.URL)
+		return
+	}
+	for _, source := range composeReq.Sources {
+		if s3Error := checkSessionPolicy(r, "s3:GetObject", pathJoin(bucket, source.Key)); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Only standard and reduced redundancy storage classes are supported.
+	if !isValidStorageClassHeader(r.Header) {
+		writeErrorResponse(w, ErrInvalidStorageClass, r.URL)
+		return
+	}
+
+	// Hold write lock on the object, and read locks on sources
+	// other than the object itself, each of them once.
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err = objectLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.Unlock()
+
+	var srcObjects []string
+	for _, source := range composeReq.Sources {
+		if source.Key != object {
+			srcObjects = append(srcObjects, source.Key)
+		}
+	}
+	sort.Strings(srcObjects)
+	for i, srcObject := range srcObjects {
+		if i > 0 && srcObject == srcObjects[i-1] {
+			continue
+		}
+		srcLock := api.NSMutex().NewRequestNSLock(r, bucket, srcObject)
+		if err = srcLock.GetRLock(getRequestDeadline(r)); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		defer srcLock.RUnlock()
+	}
+
+	var size int64
+	sources := make([]ObjectInfo, len(composeReq.Sources))
+	for i, source := range composeReq.Sources {
+		if sources[i], err = objectAPI.GetObjectInfo(bucket, source.Key); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		// Data of transitioned objects is in the remote tier.
+		if isObjectTransitioned(sources[i]) {
+			writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+			return
+		}
+		size += sources[i].Size
+	}
+	if isMaxObjectSize(size) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Objects of WORM buckets can't be overwritten.
+	if err = checkWormOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	metadata := extractMetadataFromHeader(r.Header)
+	if metadata["content-type"] == "" && sources[0].ContentType != "" {
+		metadata["content-type"] = sources[0].ContentType
+	}
+	applyDefaultMetadata(objectAPI, bucket, object, metadata)
+	if s3Error := setObjectLockMetadata(objectAPI, bucket, r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objInfo, err := composeObject(objectAPI, bucket, sources, object, metadata)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to compose object %s/%s.", bucket, object)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := ComposeObjectResponse{
+		LastModified: objInfo.ModTime.UTC().Format(timeFormatAMZLong),
+		ETag:         "\"" + objInfo.MD5Sum + "\"",
+		Size:         objInfo.Size,
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host, port = "", ""
+	}
+
+	// Notify object created event.
+	eventNotify(eventData{
+		Type:      ObjectCreatedPut,
+		Bucket:    bucket,
+		ObjInfo:   objInfo,
+		ReqParams: extractReqParams(r),
+		UserAgent: r.UserAgent(),
+		Host:      host,
+		Port:      port,
+	})
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests computing ETags of composed objects.
+func TestGetComposedObjectMD5(t *testing.T) {
+	testCases := []struct {
+		etags    []string
+		expected string
+		success  bool
+	}{
+		{[]string{"d41d8cd98f00b204e9800998ecf8427e"}, "59adb24ef3cdbe0297f05b395827453f-1", true},
+		{[]string{"d41d8cd98f00b204e9800998ecf8427e", "d41d8cd98f00b204e9800998ecf8427e-3"}, "", true},
+		{[]string{"d41d8cd98f00b204e9800998ecf8427e", "not-hex"}, "", false},
+	}
+	for i, testCase := range testCases {
+		md5Sum, err := getComposedObjectMD5(testCase.etags)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: Expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if testCase.expected != "" && md5Sum != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, md5Sum)
+		}
+	}
+}
+
+// Wrapper for calling Compose object HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIComposeObjectHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIComposeObjectHandler, []string{"ComposeObject"})
+}
+
+func testAPIComposeObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatal("Notifier initialization failed.")
+	}
+
+	objects := map[string][]byte{
+		"part1": bytes.Repeat([]byte("a"), 6*humanize.MiByte),
+		"part2": bytes.Repeat([]byte("b"), 1*humanize.KiByte),
+		"empty": nil,
+	}
+	for name, data := range objects {
+		_, err := obj.PutObject(bucketName, name, int64(len(data)), bytes.NewReader(data), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Unable to create object %s: %s", instanceType, name, err)
+		}
+	}
+
+	composeXML := func(keys ...string) []byte {
+		composeReq := ComposeObjectRequest{}
+		for _, key := range keys {
+			composeReq.Sources = append(composeReq.Sources, ComposeSource{Key: key})
+		}
+		data, err := xml.Marshal(composeReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	var tooManySources []string
+	for i := 0; i <= maxComposeSources; i++ {
+		tooManySources = append(tooManySources, "part2")
+	}
+
+	testCases := []struct {
+		objectName         string
+		body               []byte
+		expectedData       []byte
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Composing objects in the given order.
+		{
+			objectName:         "composed",
+			body:               composeXML("part1", "empty", "part2", "part1"),
+			expectedData:       append(append(append([]byte{}, objects["part1"]...), objects["part2"]...), objects["part1"]...),
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 2.
+		// Appending to an object, which is one of the sources.
+		{
+			objectName:         "composed",
+			body:               composeXML("composed", "part2"),
+			expectedData:       append(append(append(append([]byte{}, objects["part1"]...), objects["part2"]...), objects["part1"]...), objects["part2"]...),
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 3.
+		// Composing empty objects.
+		{
+			objectName:         "composed-empty",
+			body:               composeXML("empty", "empty"),
+			expectedData:       []byte{},
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 4.
+		// Source doesn't exist.
+		{
+			objectName:         "composed-missing",
+			body:               composeXML("part1", "missing"),
+			expectedRespStatus: http.StatusNotFound,
+		},
+		// Test case - 5.
+		// No source.
+		{
+			objectName:         "composed-none",
+			body:               composeXML(),
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 6.
+		// Too many sources.
+		{
+			objectName:         "composed-too-many",
+			body:               composeXML(tooManySources...),
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 7.
+		// Malformed request body.
+		{
+			objectName:         "composed-malformed",
+			body:               []byte("<ComposeObject><Source>"),
+			expectedRespStatus: http.StatusBadRequest,
+		},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", makeTestTargetURL("", bucketName, testCase.objectName, url.Values{composeQuery: []string{""}}),
+			int64(len(testCase.body)), bytes.NewReader(testCase.body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code, rec.Body.String())
+		}
+		if testCase.expectedRespStatus != http.StatusOK {
+			if _, err = obj.GetObjectInfo(bucketName, testCase.objectName); err == nil {
+				t.Errorf("Test %d: %s: Expected object not to be created", i+1, instanceType)
+			}
+			continue
+		}
+
+		var composeResp ComposeObjectResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &composeResp); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse response: %s", i+1, instanceType, err)
+		}
+		if composeResp.Size != int64(len(testCase.expectedData)) {
+			t.Errorf("Test %d: %s: Expected size %d, got %d", i+1, instanceType, len(testCase.expectedData), composeResp.Size)
+		}
+		objInfo, err := obj.GetObjectInfo(bucketName, testCase.objectName)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if composeResp.ETag != fmt.Sprintf("\"%s\"", objInfo.MD5Sum) {
+			t.Errorf("Test %d: %s: Expected ETag \"%s\", got %s", i+1, instanceType, objInfo.MD5Sum, composeResp.ETag)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(bucketName, testCase.objectName, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), testCase.expectedData) {
+			t.Errorf("Test %d: %s: Composed object doesn't have the data of its sources", i+1, instanceType)
+		}
+	}
+}
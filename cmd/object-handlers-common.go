@@ -17,8 +17,11 @@
 package cmd
 
 import (
+	"mime/multipart"
 	"net"
 	"net/http"
+	"net/textproto"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -129,12 +132,6 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 	if r.Method != "GET" && r.Method != "HEAD" {
 		return false
 	}
-	// If the object doesn't have a modtime (IsZero), or the modtime
-	// is obviously garbage (Unix time == 0), then ignore modtimes
-	// and don't process the If-Modified-Since header.
-	if objInfo.ModTime.IsZero() || objInfo.ModTime.Equal(time.Unix(0, 0)) {
-		return false
-	}
 
 	// Headers to be set of object content is not going to be written to the client.
 	writeHeaders := func() {
@@ -142,64 +139,166 @@ func checkPreconditions(w http.ResponseWriter, r *http.Request, objInfo ObjectIn
 		setCommonHeaders(w)
 
 		// set object-related metadata headers
-		w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
+		if !objInfo.ModTime.IsZero() {
+			w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
+		}
 
 		if objInfo.MD5Sum != "" {
 			w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
 		}
 	}
-	// If-Modified-Since : Return the object only if it has been modified since the specified time,
-	// otherwise return a 304 (not modified).
-	ifModifiedSinceHeader := r.Header.Get("If-Modified-Since")
-	if ifModifiedSinceHeader != "" {
-		if givenTime, err := time.Parse(http.TimeFormat, ifModifiedSinceHeader); err == nil {
-			if !ifModifiedSince(objInfo.ModTime, givenTime) {
-				// If the object is not modified since the specified time.
-				writeHeaders()
-				w.WriteHeader(http.StatusNotModified)
-				return true
-			}
+
+	switch evalPreconditions(r, objInfo, true) {
+	case http.StatusNotModified:
+		writeHeaders()
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	case http.StatusPreconditionFailed:
+		writeHeaders()
+		writeErrorResponse(w, ErrPreconditionFailed, r.URL)
+		return true
+	}
+
+	// Object content should be written to http.ResponseWriter
+	return false
+}
+
+// Validates the preconditions of PUT against the existing object, returns
+// PreconditionFailed if PUT operation should not proceed. Preconditions
+// supported are:
+//  If-Unmodified-Since
+//  If-Match
+//  If-None-Match, "*" creates the object only if it does not exist.
+func checkPutObjectPreconditions(objAPI ObjectLayer, bucket, object string, r *http.Request) error {
+	if r.Header.Get("If-Match") == "" && r.Header.Get("If-None-Match") == "" &&
+		r.Header.Get("If-Unmodified-Since") == "" {
+		return nil
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		if _, ok := errorCause(err).(ObjectNotFound); !ok {
+			return err
 		}
 	}
 
-	// If-Unmodified-Since : Return the object only if it has not been modified since the specified
-	// time, otherwise return a 412 (precondition failed).
-	ifUnmodifiedSinceHeader := r.Header.Get("If-Unmodified-Since")
-	if ifUnmodifiedSinceHeader != "" {
+	if evalPreconditions(r, objInfo, err == nil) != 0 {
+		return traceError(PreconditionFailed{Bucket: bucket, Object: object})
+	}
+	return nil
+}
+
+// evalPreconditions - evaluates conditional request headers in the order
+// defined in RFC 7232 section 6, returns the status code to be replied
+// instead of performing the request or 0 if the request should proceed.
+// exists is false when the object does not exist yet, which is only
+// the case for PUT.
+func evalPreconditions(r *http.Request, objInfo ObjectInfo, exists bool) int {
+	isGetOrHead := r.Method == "GET" || r.Method == "HEAD"
+
+	// If the object doesn't have a modtime (IsZero), or the modtime
+	// is obviously garbage (Unix time == 0), then ignore modtimes
+	// and don't process the date based headers.
+	hasModTime := exists && !objInfo.ModTime.IsZero() && !objInfo.ModTime.Equal(time.Unix(0, 0))
+
+	// If-Match : Proceed only if object's entity tag (ETag) is one of the
+	// specified, otherwise return a 412 (precondition failed). When
+	// present If-Unmodified-Since is ignored.
+	if ifMatchETagHeader := r.Header.Get("If-Match"); ifMatchETagHeader != "" {
+		if !isETagMatch(ifMatchETagHeader, objInfo.MD5Sum, exists, false) {
+			return http.StatusPreconditionFailed
+		}
+	} else if ifUnmodifiedSinceHeader := r.Header.Get("If-Unmodified-Since"); ifUnmodifiedSinceHeader != "" && hasModTime {
+		// If-Unmodified-Since : Proceed only if the object has not been modified since
+		// the specified time, otherwise return a 412 (precondition failed).
 		if givenTime, err := time.Parse(http.TimeFormat, ifUnmodifiedSinceHeader); err == nil {
 			if ifModifiedSince(objInfo.ModTime, givenTime) {
-				// If the object is modified since the specified time.
-				writeHeaders()
-				writeErrorResponse(w, ErrPreconditionFailed, r.URL)
-				return true
+				return http.StatusPreconditionFailed
 			}
 		}
 	}
 
-	// If-Match : Return the object only if its entity tag (ETag) is the same as the one specified;
-	// otherwise return a 412 (precondition failed).
-	ifMatchETagHeader := r.Header.Get("If-Match")
-	if ifMatchETagHeader != "" {
-		if !isETagEqual(objInfo.MD5Sum, ifMatchETagHeader) {
-			// If the object ETag does not match with the specified ETag.
-			writeHeaders()
-			writeErrorResponse(w, ErrPreconditionFailed, r.URL)
-			return true
+	// If-None-Match : Proceed only if object's entity tag (ETag) is none of the
+	// specified, otherwise return a 304 (not modified) for GET and HEAD and a
+	// 412 (precondition failed) for others. When present If-Modified-Since is
+	// ignored.
+	if ifNoneMatchETagHeader := r.Header.Get("If-None-Match"); ifNoneMatchETagHeader != "" {
+		if isETagMatch(ifNoneMatchETagHeader, objInfo.MD5Sum, exists, true) {
+			if isGetOrHead {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if ifModifiedSinceHeader := r.Header.Get("If-Modified-Since"); ifModifiedSinceHeader != "" && isGetOrHead && hasModTime {
+		// If-Modified-Since : Return the object only if it has been modified since
+		// the specified time, otherwise return a 304 (not modified).
+		if givenTime, err := time.Parse(http.TimeFormat, ifModifiedSinceHeader); err == nil {
+			if !ifModifiedSince(objInfo.ModTime, givenTime) {
+				return http.StatusNotModified
+			}
 		}
 	}
 
-	// If-None-Match : Return the object only if its entity tag (ETag) is different from the
-	// one specified otherwise, return a 304 (not modified).
-	ifNoneMatchETagHeader := r.Header.Get("If-None-Match")
-	if ifNoneMatchETagHeader != "" {
-		if isETagEqual(objInfo.MD5Sum, ifNoneMatchETagHeader) {
-			// If the object ETag matches with the specified ETag.
-			writeHeaders()
-			w.WriteHeader(http.StatusNotModified)
+	return 0
+}
+
+// isIfRangeMatch - returns true if the If-Range header, if any,
+// matches the object as per RFC 7233 section 3.2. An entity tag must
+// be equal to the object ETag using strong comparison, a date must be
+// exactly the object modification time, so that a resumed download
+// never mixes bytes of different versions of an object.
+func isIfRangeMatch(r *http.Request, objInfo ObjectInfo) bool {
+	ifRangeHeader := strings.TrimSpace(r.Header.Get("If-Range"))
+	if ifRangeHeader == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRangeHeader, "W/") {
+		// Weak entity tags never match.
+		return false
+	}
+	if strings.HasPrefix(ifRangeHeader, "\"") {
+		return objInfo.MD5Sum != "" && isETagEqual(objInfo.MD5Sum, ifRangeHeader)
+	}
+	givenTime, err := time.Parse(http.TimeFormat, ifRangeHeader)
+	if err != nil || objInfo.ModTime.IsZero() {
+		return false
+	}
+	// The Last-Modified header truncates sub-second precision.
+	return objInfo.ModTime.UTC().Truncate(time.Second).Equal(givenTime)
+}
+
+// getRangeHeader - returns the Range header of a GET request, or an
+// empty string when If-Range does not match the object, in which
+// case the entire object is served.
+func getRangeHeader(r *http.Request, objInfo ObjectInfo) string {
+	if !isIfRangeMatch(r, objInfo) {
+		return ""
+	}
+	return r.Header.Get("Range")
+}
+
+// isETagMatch - returns true if any of the comma separated entity tags in
+// header matches etag, "*" matches any existing object. Weak entity tags
+// (W/"...") only match when weak comparison is allowed.
+func isETagMatch(header, etag string, exists, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !exists || tag == "" {
+			continue
+		}
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if etag != "" && isETagEqual(etag, tag) {
 			return true
 		}
 	}
-	// Object content should be written to http.ResponseWriter
 	return false
 }
 
@@ -229,17 +328,37 @@ func isETagEqual(left, right string) bool {
 // deleteObject is a convenient wrapper to delete an object, this
 // is a common function to be called from object handlers and
 // web handlers.
-func deleteObject(obj ObjectLayer, bucket, object string, r *http.Request) (err error) {
+func deleteObject(obj ObjectLayer, nsMutex *nsLockMap, bucket, object string, r *http.Request) (err error) {
 	// Acquire a write lock before deleting the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
+	objectLock := nsMutex.NewRequestNSLock(r, bucket, object)
+	if err = objectLock.GetLock(getRequestDeadline(r)); err != nil {
+		return err
+	}
 	defer objectLock.Unlock()
 
+	// Objects of WORM buckets and locked objects can't be deleted.
+	if err = checkObjectOverwrite(obj, bucket, object, isGovernanceBypassed(r)); err != nil {
+		return err
+	}
+
+	// Remote copy of a transitioned object is removed as well.
+	tier := globalRemoteTier
+	transitioned := false
+	if tier != nil {
+		if objInfo, oerr := obj.GetObjectInfo(bucket, object); oerr == nil {
+			transitioned = isObjectTransitioned(objInfo)
+		}
+	}
+
 	// Proceed to delete the object.
 	if err = obj.DeleteObject(bucket, object); err != nil {
 		return err
 	}
 
+	if transitioned {
+		removeTransitionedObject(tier, bucket, object)
+	}
+
 	// Get host and port from Request.RemoteAddr.
 	host, port, _ := net.SplitHostPort(r.RemoteAddr)
 
@@ -258,3 +377,61 @@ func deleteObject(obj ObjectLayer, bucket, object string, r *http.Request) (err
 
 	return nil
 }
+
+// byteCounter - io.Writer counting bytes written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// writeObjectRanges - writes multiple ranges of an object as a single
+// multipart/byteranges response, each part carries its Content-Range.
+func writeObjectRanges(w http.ResponseWriter, r *http.Request, objectAPI ObjectLayer, bucket, object string, objInfo ObjectInfo, hranges []*httpRange) error {
+	// Set standard object headers and any additional requested
+	// response headers, then replace content type and length.
+	setObjectHeaders(w, objInfo, nil)
+	setGetRespHeaders(w, r.URL.Query())
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	partHeader := func(hrange *httpRange) textproto.MIMEHeader {
+		return textproto.MIMEHeader{
+			"Content-Type":  {contentType},
+			"Content-Range": {hrange.String()},
+		}
+	}
+
+	mw := multipart.NewWriter(w)
+
+	// Content length is the size of multipart framing, computed by
+	// writing it alone, plus the size of all the ranges.
+	var size byteCounter
+	sizeWriter := multipart.NewWriter(&size)
+	sizeWriter.SetBoundary(mw.Boundary())
+	for _, hrange := range hranges {
+		if _, err := sizeWriter.CreatePart(partHeader(hrange)); err != nil {
+			return err
+		}
+		size += byteCounter(hrange.getLength())
+	}
+	sizeWriter.Close()
+
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set("Content-Length", strconv.FormatInt(int64(size), 10))
+	w.WriteHeader(http.StatusPartialContent)
+
+	for _, hrange := range hranges {
+		part, err := mw.CreatePart(partHeader(hrange))
+		if err != nil {
+			return err
+		}
+		if err = objectAPI.GetObject(bucket, object, hrange.offsetBegin, hrange.getLength(), part); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
@@ -0,0 +1,302 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Tests matching of entity tags in conditional headers.
+func TestIsETagMatch(t *testing.T) {
+	etag := "d41d8cd98f00b204e9800998ecf8427e"
+	testCases := []struct {
+		header   string
+		exists   bool
+		weak     bool
+		expected bool
+	}{
+		{`"d41d8cd98f00b204e9800998ecf8427e"`, true, false, true},
+		{`d41d8cd98f00b204e9800998ecf8427e`, true, false, true},
+		{`"abc", "d41d8cd98f00b204e9800998ecf8427e"`, true, false, true},
+		{`"abc","def"`, true, false, false},
+		{`*`, true, false, true},
+		{`*`, false, false, false},
+		{`"d41d8cd98f00b204e9800998ecf8427e"`, false, false, false},
+		// Weak entity tags match only with weak comparison.
+		{`W/"d41d8cd98f00b204e9800998ecf8427e"`, true, false, false},
+		{`W/"d41d8cd98f00b204e9800998ecf8427e"`, true, true, true},
+		{``, true, true, false},
+	}
+	for i, testCase := range testCases {
+		if actual := isETagMatch(testCase.header, etag, testCase.exists, testCase.weak); actual != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, actual)
+		}
+	}
+}
+
+// Tests evaluation of conditional headers as per RFC 7232.
+func TestEvalPreconditions(t *testing.T) {
+	modTime := time.Date(2017, time.May, 10, 10, 0, 0, 0, time.UTC)
+	objInfo := ObjectInfo{
+		MD5Sum:  "d41d8cd98f00b204e9800998ecf8427e",
+		ModTime: modTime,
+	}
+	etag := `"d41d8cd98f00b204e9800998ecf8427e"`
+	before := modTime.Add(-time.Hour).Format(http.TimeFormat)
+	after := modTime.Add(time.Hour).Format(http.TimeFormat)
+
+	testCases := []struct {
+		method   string
+		headers  map[string]string
+		exists   bool
+		expected int
+	}{
+		// Test case - 1, no conditional headers.
+		{"GET", nil, true, 0},
+		// Test case - 2, ETag matches.
+		{"GET", map[string]string{"If-Match": etag}, true, 0},
+		// Test case - 3, ETag does not match.
+		{"GET", map[string]string{"If-Match": `"abc"`}, true, http.StatusPreconditionFailed},
+		// Test case - 4, If-Unmodified-Since is ignored when If-Match holds.
+		{"GET", map[string]string{"If-Match": etag, "If-Unmodified-Since": before}, true, 0},
+		// Test case - 5, object modified since.
+		{"HEAD", map[string]string{"If-Unmodified-Since": before}, true, http.StatusPreconditionFailed},
+		// Test case - 6, object not modified since.
+		{"GET", map[string]string{"If-Unmodified-Since": after}, true, 0},
+		// Test case - 7, ETag is one of If-None-Match.
+		{"GET", map[string]string{"If-None-Match": `"abc", ` + etag}, true, http.StatusNotModified},
+		// Test case - 8, weak ETag matches for If-None-Match.
+		{"HEAD", map[string]string{"If-None-Match": "W/" + etag}, true, http.StatusNotModified},
+		// Test case - 9, If-Modified-Since is ignored when If-None-Match is present.
+		{"GET", map[string]string{"If-None-Match": `"abc"`, "If-Modified-Since": after}, true, 0},
+		// Test case - 10, object not modified since.
+		{"GET", map[string]string{"If-Modified-Since": after}, true, http.StatusNotModified},
+		// Test case - 11, object modified since.
+		{"GET", map[string]string{"If-Modified-Since": before}, true, 0},
+		// Test case - 12, invalid dates are ignored.
+		{"GET", map[string]string{"If-Modified-Since": "yesterday"}, true, 0},
+		// Test case - 13, If-Match takes precedence over If-None-Match.
+		{"GET", map[string]string{"If-Match": `"abc"`, "If-None-Match": etag}, true, http.StatusPreconditionFailed},
+		// Test case - 14, PUT creating only if object does not exist.
+		{"PUT", map[string]string{"If-None-Match": "*"}, true, http.StatusPreconditionFailed},
+		// Test case - 15, PUT creating object which does not exist.
+		{"PUT", map[string]string{"If-None-Match": "*"}, false, 0},
+		// Test case - 16, PUT replacing object which does not exist.
+		{"PUT", map[string]string{"If-Match": "*"}, false, http.StatusPreconditionFailed},
+		// Test case - 17, PUT replacing object with matching ETag.
+		{"PUT", map[string]string{"If-Match": etag}, true, 0},
+		// Test case - 18, If-Modified-Since is ignored for PUT.
+		{"PUT", map[string]string{"If-Modified-Since": after}, true, 0},
+		// Test case - 19, PUT with If-Unmodified-Since on a missing object.
+		{"PUT", map[string]string{"If-Unmodified-Since": before}, false, 0},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost/bucket/object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range testCase.headers {
+			req.Header.Set(k, v)
+		}
+		info := objInfo
+		if !testCase.exists {
+			info = ObjectInfo{}
+		}
+		if actual := evalPreconditions(req, info, testCase.exists); actual != testCase.expected {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expected, actual)
+		}
+	}
+}
+
+// Tests matching of If-Range header as per RFC 7233.
+func TestIsIfRangeMatch(t *testing.T) {
+	modTime := time.Date(2017, 9, 1, 10, 20, 30, 400, time.UTC)
+	objInfo := ObjectInfo{MD5Sum: "d41d8cd98f00b204e9800998ecf8427e", ModTime: modTime}
+	testCases := []struct {
+		header   string
+		expected bool
+	}{
+		// No If-Range, Range is always honored.
+		{``, true},
+		{`"d41d8cd98f00b204e9800998ecf8427e"`, true},
+		{`"abc"`, false},
+		// Weak entity tags never match.
+		{`W/"d41d8cd98f00b204e9800998ecf8427e"`, false},
+		// Dates must be exactly the modification time.
+		{modTime.Format(http.TimeFormat), true},
+		{modTime.Add(time.Second).Format(http.TimeFormat), false},
+		{modTime.Add(-time.Second).Format(http.TimeFormat), false},
+		{`invalid`, false},
+	}
+	for i, testCase := range testCases {
+		req := &http.Request{Header: http.Header{}}
+		if testCase.header != "" {
+			req.Header.Set("If-Range", testCase.header)
+		}
+		if actual := isIfRangeMatch(req, objInfo); actual != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, actual)
+		}
+	}
+}
+
+// Tests conditional GET and PUT requests end to end.
+func TestAPIConditionalRequests(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIConditionalRequests, []string{"GetObject", "HeadObject", "PutObject"})
+}
+
+func testAPIConditionalRequests(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	data := []byte("hello, world")
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	etag := `"` + objInfo.MD5Sum + `"`
+
+	testCases := []struct {
+		method             string
+		objectName         string
+		headers            map[string]string
+		expectedRespStatus int
+	}{
+		// Test case - 1, unconditional GET.
+		{"GET", objectName, nil, http.StatusOK},
+		// Test case - 2, cached copy is still valid.
+		{"GET", objectName, map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		// Test case - 3, cached copy is stale.
+		{"GET", objectName, map[string]string{"If-Match": `"abc"`}, http.StatusPreconditionFailed},
+		// Test case - 4, HEAD validating cached copy.
+		{"HEAD", objectName, map[string]string{"If-None-Match": `"abc", ` + etag}, http.StatusNotModified},
+		// Test case - 5, create only if object does not exist.
+		{"PUT", objectName, map[string]string{"If-None-Match": "*"}, http.StatusPreconditionFailed},
+		// Test case - 6, create only if object does not exist.
+		{"PUT", "new-object", map[string]string{"If-None-Match": "*"}, http.StatusOK},
+		// Test case - 7, replace only if object was not changed.
+		{"PUT", objectName, map[string]string{"If-Match": `"abc"`}, http.StatusPreconditionFailed},
+		// Test case - 8, replace only if object was not changed.
+		{"PUT", objectName, map[string]string{"If-Match": etag}, http.StatusOK},
+		// Test case - 9, replace only if object exists.
+		{"PUT", "missing-object", map[string]string{"If-Match": "*"}, http.StatusPreconditionFailed},
+		// Test case - 10, resume download of unchanged object.
+		{"GET", objectName, map[string]string{"Range": "bytes=2-", "If-Range": etag}, http.StatusPartialContent},
+		// Test case - 11, object changed, entire object is sent.
+		{"GET", objectName, map[string]string{"Range": "bytes=2-", "If-Range": `"abc"`}, http.StatusOK},
+		// Test case - 12, weak entity tags are not used for ranges.
+		{"GET", objectName, map[string]string{"Range": "bytes=2-", "If-Range": "W/" + etag}, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		var body []byte
+		if testCase.method == "PUT" {
+			body = data
+		}
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		for k, v := range testCase.headers {
+			req.Header.Set(k, v)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Rejected conditional PUT must not have created the object.
+	if _, err = obj.GetObjectInfo(bucketName, "missing-object"); err == nil {
+		t.Errorf("%s: Expected missing-object not to be created", instanceType)
+	}
+}
+
+func TestAPIGetObjectMultipleRanges(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIGetObjectMultipleRanges, []string{"GetObject"})
+}
+
+func testAPIGetObjectMultipleRanges(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	data := []byte("0123456789abcdefghij")
+	metadata := map[string]string{"content-type": "text/plain"}
+	if _, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), metadata, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("Range", "bytes=0-3,10-12,-2")
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusPartialContent, rec.Code)
+	}
+	if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(rec.Body.Len()) {
+		t.Fatalf("%s: Expected Content-Length %d, got %s", instanceType, rec.Body.Len(), contentLength)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/byteranges" {
+		t.Fatalf("%s: Unexpected Content-Type %s", instanceType, rec.Header().Get("Content-Type"))
+	}
+
+	expectedParts := []struct {
+		contentRange string
+		data         string
+	}{
+		{"bytes 0-3/20", "0123"},
+		{"bytes 10-12/20", "abc"},
+		{"bytes 18-19/20", "ij"},
+	}
+	mr := multipart.NewReader(rec.Body, params["boundary"])
+	for i, expectedPart := range expectedParts {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("%s: Part %d: %s", instanceType, i+1, err)
+		}
+		if part.Header.Get("Content-Type") != "text/plain" || part.Header.Get("Content-Range") != expectedPart.contentRange {
+			t.Fatalf("%s: Part %d: unexpected headers %v", instanceType, i+1, part.Header)
+		}
+		partData, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatalf("%s: Part %d: %s", instanceType, i+1, err)
+		}
+		if string(partData) != expectedPart.data {
+			t.Fatalf("%s: Part %d: expected %s, got %s", instanceType, i+1, expectedPart.data, partData)
+		}
+	}
+	if _, err = mr.NextPart(); err != io.EOF {
+		t.Fatalf("%s: Expected end of parts, got %v", instanceType, err)
+	}
+}
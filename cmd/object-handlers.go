@@ -19,6 +19,7 @@ package cmd
 import (
 	"encoding/hex"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -52,12 +53,12 @@ func setGetRespHeaders(w http.ResponseWriter, reqParams url.Values) {
 // this is in keeping with the permissions sections of the docs of both:
 //   HEAD Object: http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectHEAD.html
 //   GET Object: http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectGET.html
-func errAllowableObjectNotFound(bucket string, r *http.Request) APIErrorCode {
+func errAllowableObjectNotFound(objAPI ObjectLayer, bucket string, r *http.Request) APIErrorCode {
 	if getRequestAuthType(r) == authTypeAnonymous {
 		//we care about the bucket as a whole, not a particular resource
 		resource := "/" + bucket
-		if s3Error := enforceBucketPolicy(bucket, "s3:ListBucket", resource,
-			r.Referer(), r.URL.Query()); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(objAPI, bucket, "s3:ListBucket", resource,
+			r.Referer(), getSourceIP(r), r.URL.Query()); s3Error != ErrNone {
 			return ErrAccessDenied
 		}
 	}
@@ -71,6 +72,83 @@ func (f funcToWriter) Write(p []byte) (int, error) {
 	return f(p)
 }
 
+// objectResponseWriter - writes object data to the client, setting
+// the response headers before any data is sent. Implements
+// io.ReaderFrom so that object layers can send regular files to
+// the client with zero-copy sendfile() where supported.
+type objectResponseWriter struct {
+	w           http.ResponseWriter
+	setHeaders  func()
+	dataWritten bool
+}
+
+func (ow *objectResponseWriter) writeHeaders() {
+	if !ow.dataWritten {
+		ow.setHeaders()
+		ow.dataWritten = true
+	}
+}
+
+func (ow *objectResponseWriter) Write(p []byte) (int, error) {
+	ow.writeHeaders()
+	return ow.w.Write(p)
+}
+
+func (ow *objectResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	ow.writeHeaders()
+	return copyToResponseWriter(ow.w, r)
+}
+
+// getObject - writes the whole object or a single range of it,
+// error response is written if no data was sent to the client yet.
+func getObject(w http.ResponseWriter, r *http.Request, objectAPI ObjectLayer, bucket, object string, objInfo ObjectInfo, hranges []*httpRange) error {
+	var hrange *httpRange
+	if len(hranges) == 1 {
+		hrange = hranges[0]
+	}
+
+	// Get the object.
+	var startOffset int64
+	length := objInfo.Size
+	if hrange != nil {
+		startOffset = hrange.offsetBegin
+		length = hrange.getLength()
+	}
+
+	// io.Writer type which keeps track if any data was written.
+	writer := &objectResponseWriter{
+		w: w,
+		// Set headers on the first write.
+		setHeaders: func() {
+			// Set standard object headers.
+			setObjectHeaders(w, objInfo, hrange)
+
+			// Set any additional requested response headers.
+			setGetRespHeaders(w, r.URL.Query())
+		},
+	}
+
+	// Reads the object at startOffset and writes to mw.
+	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
+		reqErrorIf(r, err, "Unable to write to client.")
+		if !writer.dataWritten {
+			// Error response only if no data has been written to client yet. i.e if
+			// partial data has already been written before an error
+			// occurred then no point in setting StatusCode and
+			// sending error XML.
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		}
+		return err
+	}
+	if !writer.dataWritten {
+		// If ObjectAPI.GetObject did not return error and no data has
+		// been written it would mean that it is a 0-byte object.
+		// call wrter.Write(nil) to set appropriate headers.
+		writer.Write(nil)
+	}
+	return nil
+}
+
 // GetObjectHandler - GET Object
 // ----------
 // This implementation of the GET operation retrieves object. To use GET,
@@ -88,32 +166,44 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.RUnlock()
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		reqErrorIf(r, err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
-			apiErr = errAllowableObjectNotFound(bucket, r)
+			apiErr = errAllowableObjectNotFound(objectAPI, bucket, r)
 		}
 		writeErrorResponse(w, apiErr, r.URL)
 		return
 	}
 
-	// Get request range.
-	var hrange *httpRange
-	rangeHeader := r.Header.Get("Range")
+	// Data of transitioned objects is in the remote tier.
+	if isObjectTransitioned(objInfo) {
+		getTransitionedObject(w, r, objectAPI, bucket, object, objInfo)
+		return
+	}
+
+	// Report when the bucket lifecycle expires the object.
+	setObjectExpirationHeader(w, objectAPI, bucket, objInfo)
+
+	// Get request ranges.
+	var hranges []*httpRange
+	rangeHeader := getRangeHeader(r, objInfo)
 	if rangeHeader != "" {
-		if hrange, err = parseRequestRange(rangeHeader, objInfo.Size); err != nil {
+		if hranges, err = parseRequestRanges(rangeHeader, objInfo.Size); err != nil {
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
 			if err == errInvalidRange {
@@ -122,7 +212,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			}
 
 			// log the error.
-			errorIf(err, "Invalid request range")
+			reqErrorIf(r, err, "Invalid request range")
 		}
 	}
 
@@ -131,49 +221,26 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Get the object.
-	var startOffset int64
-	length := objInfo.Size
-	if hrange != nil {
-		startOffset = hrange.offsetBegin
-		length = hrange.getLength()
-	}
-
-	// Indicates if any data was written to the http.ResponseWriter
-	dataWritten := false
-	// io.Writer type which keeps track if any data was written.
-	writer := funcToWriter(func(p []byte) (int, error) {
-		if !dataWritten {
-			// Set headers on the first write.
-			// Set standard object headers.
-			setObjectHeaders(w, objInfo, hrange)
-
-			// Set any additional requested response headers.
-			setGetRespHeaders(w, r.URL.Query())
-
-			dataWritten = true
-		}
-		return w.Write(p)
-	})
-
-	// Reads the object at startOffset and writes to mw.
-	if err = objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
-		errorIf(err, "Unable to write to client.")
-		if !dataWritten {
-			// Error response only if no data has been written to client yet. i.e if
-			// partial data has already been written before an error
-			// occurred then no point in setting StatusCode and
-			// sending error XML.
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+	// Multiple ranges are sent as a multipart/byteranges response.
+	if len(hranges) > 1 {
+		if err = writeObjectRanges(w, r, objectAPI, bucket, object, objInfo, hranges); err != nil {
+			// Headers are already written, no need to write error response.
+			reqErrorIf(r, err, "Unable to write to client.")
+			return
 		}
+	} else if err = getObject(w, r, objectAPI, bucket, object, objInfo, hranges); err != nil {
 		return
 	}
-	if !dataWritten {
-		// If ObjectAPI.GetObject did not return error and no data has
-		// been written it would mean that it is a 0-byte object.
-		// call wrter.Write(nil) to set appropriate headers.
-		writer.Write(nil)
+
+	// Count the read for analytics of the bucket.
+	size := objInfo.Size
+	if len(hranges) > 0 {
+		size = 0
+		for _, hrange := range hranges {
+			size += hrange.getLength()
+		}
 	}
+	recordAnalyticsRead(objectAPI, bucket, objInfo, size)
 
 	// Get host and port from Request.RemoteAddr.
 	host, port, err := net.SplitHostPort(r.RemoteAddr)
@@ -208,27 +275,35 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponseHeadersOnly(w, s3Error)
 		return
 	}
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.RLock()
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
+		return
+	}
 	defer objectLock.RUnlock()
 
 	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		reqErrorIf(r, err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
 		if apiErr == ErrNoSuchKey {
-			apiErr = errAllowableObjectNotFound(bucket, r)
+			apiErr = errAllowableObjectNotFound(objectAPI, bucket, r)
 		}
 		writeErrorResponseHeadersOnly(w, apiErr)
 		return
 	}
 
+	// Report transitioned objects as they were before transition.
+	if isObjectTransitioned(objInfo) {
+		objInfo = getTransitionedObjectInfo(objInfo)
+	}
+
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
@@ -237,6 +312,9 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	// Set standard object headers.
 	setObjectHeaders(w, objInfo, nil)
 
+	// Report when the bucket lifecycle expires the object.
+	setObjectExpirationHeader(w, objectAPI, bucket, objInfo)
+
 	// Successful response.
 	w.WriteHeader(http.StatusOK)
 
@@ -292,7 +370,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, dstBucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -312,6 +390,10 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
 		return
 	}
+	if s3Error := checkSessionPolicy(r, "s3:GetObject", pathJoin(srcBucket, srcObject)); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	// Check if metadata directive is valid.
 	if !isMetadataDirectiveValid(r.Header) {
@@ -319,13 +401,22 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Only standard and reduced redundancy storage classes are supported.
+	if !isValidStorageClassHeader(r.Header) {
+		writeErrorResponse(w, ErrInvalidStorageClass, r.URL)
+		return
+	}
+
 	cpSrcDstSame := srcBucket == dstBucket && srcObject == dstObject
 	// Hold write lock on destination since in both cases
 	// - if source and destination are same
 	// - if source and destination are different
 	// it is the sole mutating state.
-	objectDWLock := globalNSMutex.NewNSLock(dstBucket, dstObject)
-	objectDWLock.Lock()
+	objectDWLock := api.NSMutex().NewRequestNSLock(r, dstBucket, dstObject)
+	if err := objectDWLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectDWLock.Unlock()
 
 	// if source and destination are different, we have to hold
@@ -334,24 +425,40 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	if !cpSrcDstSame {
 		// Hold read locks on source object only if we are
 		// going to read data from source object.
-		objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
-		objectSRLock.RLock()
+		objectSRLock := api.NSMutex().NewRequestNSLock(r, srcBucket, srcObject)
+		if err := objectSRLock.GetRLock(getRequestDeadline(r)); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
 		defer objectSRLock.RUnlock()
 
 	}
 
 	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		reqErrorIf(r, err, "Unable to fetch object info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Data of transitioned objects is in the remote tier.
+	if isObjectTransitioned(objInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
 	// Verify before x-amz-copy-source preconditions before continuing with CopyObject.
 	if checkCopyObjectPreconditions(w, r, objInfo) {
 		return
 	}
 
+	// Objects of WORM buckets can't be overwritten, not even their
+	// metadata.
+	if err = checkWormOverwrite(objectAPI, dstBucket, dstObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	/// maximum Upload size for object in a single CopyObject operation.
 	if isMaxObjectSize(objInfo.Size) {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
@@ -366,6 +473,17 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	delete(defaultMeta, "md5Sum")
 
 	newMetadata := getCpObjMetadataFromHeader(r.Header, defaultMeta)
+	// Replaced metadata gets default metadata of the bucket, as for
+	// new objects.
+	if isMetadataReplace(r.Header) {
+		applyDefaultMetadata(objectAPI, dstBucket, dstObject, newMetadata)
+	}
+	// Object lock of the source isn't copied, the copy is locked as
+	// a new object.
+	if s3Error := setObjectLockMetadata(objectAPI, dstBucket, r.Header, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 	// Check if x-amz-metadata-directive was not set to REPLACE and source,
 	// desination are same objects.
 	if !isMetadataReplace(r.Header) && cpSrcDstSame {
@@ -429,13 +547,13 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	object := vars["object"]
 
 	// Get Content-Md5 sent by client and verify if valid
-	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
-	if err != nil {
-		errorIf(err, "Unable to validate content-md5 format.")
-		writeErrorResponse(w, ErrInvalidDigest, r.URL)
+	md5Bytes, errCode := getContentMD5(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
+	var err error
 	/// if Content-Length is unknown/missing, deny the request
 	size := r.ContentLength
 	rAuthType := getRequestAuthType(r)
@@ -443,7 +561,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			reqErrorIf(r, err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
@@ -459,6 +577,12 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Only standard and reduced redundancy storage classes are supported.
+	if !isValidStorageClassHeader(r.Header) {
+		writeErrorResponse(w, ErrInvalidStorageClass, r.URL)
+		return
+	}
+
 	// Extract metadata to be saved from incoming HTTP header.
 	metadata := extractMetadataFromHeader(r.Header)
 	if rAuthType == authTypeStreamingSigned {
@@ -481,16 +605,39 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Add default metadata of the bucket not sent by the client.
+	applyDefaultMetadata(objectAPI, bucket, object, metadata)
+	if s3Error := setObjectLockMetadata(objectAPI, bucket, r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Make sure we hex encode md5sum here.
 	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
 
 	sha256sum := ""
 
 	// Lock the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
-	objectLock.Lock()
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectLock.Unlock()
 
+	// Create object only if preconditions hold, preconditions are
+	// evaluated after the request is authenticated.
+	putObject := func(reader io.Reader) (ObjectInfo, error) {
+		if err := checkPutObjectPreconditions(objectAPI, bucket, object, r); err != nil {
+			return ObjectInfo{}, err
+		}
+		// Objects of WORM buckets can't be overwritten.
+		if err := checkWormOverwrite(objectAPI, bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+		return objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
+	}
+
 	var objInfo ObjectInfo
 	switch rAuthType {
 	default:
@@ -499,33 +646,41 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL.Path,
-			r.Referer(), r.URL.Query()); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(objectAPI, bucket, "s3:PutObject", r.URL.Path,
+			r.Referer(), getSourceIP(r), r.URL.Query()); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObject(r.Body)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
+		if s3Error = checkSessionPolicy(r, "s3:PutObject", r.URL.Path); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		objInfo, err = putObject(reader)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObject(r.Body)
 	case authTypePresigned, authTypeSigned:
-		if s3Error := reqSignatureV4Verify(r, serverConfig.GetRegion()); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+		if s3Error := reqSignatureV4Verify(r, api.Config().GetRegion()); s3Error != ErrNone {
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkSessionPolicy(r, "s3:PutObject", r.URL.Path); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -533,14 +688,17 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
 		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		objInfo, err = putObject(r.Body)
 	}
 	if err != nil {
-		errorIf(err, "Unable to create an object. %s", r.URL.Path)
+		if _, ok := errorCause(err).(PreconditionFailed); !ok {
+			reqErrorIf(r, err, "Unable to create an object. %s", r.URL.Path)
+		}
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	setObjectExpirationHeader(w, objectAPI, bucket, objInfo)
 	writeSuccessResponseHeadersOnly(w)
 
 	// Get host and port from Request.RemoteAddr.
@@ -576,17 +734,35 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
+	// Objects of WORM buckets can't be overwritten, uploads are
+	// checked again when completed.
+	if err := checkWormOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Only standard and reduced redundancy storage classes are supported.
+	if !isValidStorageClassHeader(r.Header) {
+		writeErrorResponse(w, ErrInvalidStorageClass, r.URL)
+		return
+	}
+
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
+	applyDefaultMetadata(objectAPI, bucket, object, metadata)
+	if s3Error := setObjectLockMetadata(objectAPI, bucket, r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
 	if err != nil {
-		errorIf(err, "Unable to initiate new multipart upload id.")
+		reqErrorIf(r, err, "Unable to initiate new multipart upload id.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -610,7 +786,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, dstBucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -628,6 +804,10 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
 		return
 	}
+	if s3Error := checkSessionPolicy(r, "s3:GetObject", pathJoin(srcBucket, srcObject)); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	uploadID := r.URL.Query().Get("uploadId")
 	partIDString := r.URL.Query().Get("partNumber")
@@ -646,17 +826,26 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 
 	// Hold read locks on source object only if we are
 	// going to read data from source object.
-	objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
-	objectSRLock.RLock()
+	objectSRLock := api.NSMutex().NewRequestNSLock(r, srcBucket, srcObject)
+	if err := objectSRLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer objectSRLock.RUnlock()
 
 	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		reqErrorIf(r, err, "Unable to fetch object info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Data of transitioned objects is in the remote tier.
+	if isObjectTransitioned(objInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
 	// Get request range.
 	var hrange *httpRange
 	rangeHeader := r.Header.Get("x-amz-copy-source-range")
@@ -664,7 +853,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		if hrange, err = parseCopyPartRange(rangeHeader, objInfo.Size); err != nil {
 			// Handle only errInvalidRange
 			// Ignore other parse error and treat it as regular Get request like Amazon S3.
-			errorIf(err, "Unable to extract range %s", rangeHeader)
+			reqErrorIf(r, err, "Unable to extract range %s", rangeHeader)
 			writeCopyPartErr(w, err, r.URL)
 			return
 		}
@@ -693,7 +882,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 	// object is same then only metadata is updated.
 	partInfo, err := objectAPI.CopyObjectPart(srcBucket, srcObject, dstBucket, dstObject, uploadID, partID, startOffset, length)
 	if err != nil {
-		errorIf(err, "Unable to perform CopyObjectPart %s/%s", srcBucket, srcObject)
+		reqErrorIf(r, err, "Unable to perform CopyObjectPart %s/%s", srcBucket, srcObject)
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -724,12 +913,13 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	}
 
 	// get Content-Md5 sent by client and verify if valid
-	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
-	if err != nil {
-		writeErrorResponse(w, ErrInvalidDigest, r.URL)
+	md5Bytes, errCode := getContentMD5(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
+	var err error
 	/// if Content-Length is unknown/missing, throw away
 	size := r.ContentLength
 
@@ -739,7 +929,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			reqErrorIf(r, err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
@@ -780,8 +970,8 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL.Path,
-			r.Referer(), r.URL.Query()); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(objectAPI, bucket, "s3:PutObject", r.URL.Path,
+			r.Referer(), getSourceIP(r), r.URL.Query()); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -791,7 +981,11 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		// Initialize stream signature verifier.
 		reader, s3Error := newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error = checkSessionPolicy(r, "s3:PutObject", r.URL.Path); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -799,14 +993,18 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
-		if s3Error := reqSignatureV4Verify(r, serverConfig.GetRegion()); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+		if s3Error := reqSignatureV4Verify(r, api.Config().GetRegion()); s3Error != ErrNone {
+			reqErrorIf(r, errSignatureMismatch, dumpRequest(r))
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if s3Error := checkSessionPolicy(r, "s3:PutObject", r.URL.Path); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -817,7 +1015,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		partInfo, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	}
 	if err != nil {
-		errorIf(err, "Unable to create object part.")
+		reqErrorIf(r, err, "Unable to create object part.")
 		// Verify if the underlying error is signature mismatch.
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -841,14 +1039,14 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:AbortMultipartUpload", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:AbortMultipartUpload", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
 	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
-		errorIf(err, "Unable to abort multipart upload.")
+		reqErrorIf(r, err, "Unable to abort multipart upload.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -867,7 +1065,7 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:ListMultipartUploadParts", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:ListMultipartUploadParts", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -883,7 +1081,7 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 	}
 	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
-		errorIf(err, "Unable to list uploaded parts.")
+		reqErrorIf(r, err, "Unable to list uploaded parts.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -906,7 +1104,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -916,13 +1114,13 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 	completeMultipartBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		reqErrorIf(r, err, "Unable to complete multipart upload.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 	complMultipartUpload := &completeMultipartUpload{}
 	if err = xml.Unmarshal(completeMultipartBytes, complMultipartUpload); err != nil {
-		errorIf(err, "Unable to parse complete multipart upload XML.")
+		reqErrorIf(r, err, "Unable to parse complete multipart upload XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -934,22 +1132,42 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		writeErrorResponse(w, ErrInvalidPartOrder, r.URL)
 		return
 	}
+	// Parts are sorted, checking the last part number is enough.
+	lastPart := complMultipartUpload.Parts[len(complMultipartUpload.Parts)-1]
+	if isMaxPartID(lastPart.PartNumber) {
+		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		return
+	}
 
 	// Complete parts.
-	var completeParts []completePart
+	var completeParts []CompletePart
 	for _, part := range complMultipartUpload.Parts {
 		part.ETag = canonicalizeETag(part.ETag)
 		completeParts = append(completeParts, part)
 	}
 
 	// Hold write lock on the object.
-	destLock := globalNSMutex.NewNSLock(bucket, object)
-	destLock.Lock()
+	destLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := destLock.GetLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
 	defer destLock.Unlock()
 
+	// Objects of WORM buckets can't be overwritten.
+	if err = checkWormOverwrite(objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Send whitespace keep-alives while the parts are assembled, if
+	// any is sent the final response is written after them.
+	kw := startKeepAlive(w, r, globalCompleteMultipartKeepAlive)
 	objInfo, err := objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
+	kw.stop()
+	w = kw
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		reqErrorIf(r, err, "Unable to complete multipart upload.")
 		err = errorCause(err)
 		switch oErr := err.(type) {
 		case PartTooSmall:
@@ -968,7 +1186,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	response := generateCompleteMultpartUploadResponse(bucket, object, location, objInfo.MD5Sum)
 	encodedSuccessResponse := encodeResponse(response)
 	if err != nil {
-		errorIf(err, "Unable to parse CompleteMultipartUpload response")
+		reqErrorIf(r, err, "Unable to parse CompleteMultipartUpload response")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -1011,7 +1229,7 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:DeleteObject", api.Config().GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -1019,9 +1237,14 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	// Ignore delete object errors while replying to client, since we are
 	// suppposed to reply only 204. Additionally log the error for
-	// investigation.
-	if err := deleteObject(objectAPI, bucket, object, r); err != nil {
-		errorIf(err, "Unable to delete an object %s", pathJoin(bucket, object))
+	// investigation. Objects of WORM buckets and locked objects are
+	// not deleted, which is replied.
+	if err := deleteObject(objectAPI, api.NSMutex(), bucket, object, r); err != nil {
+		if errorCause(err) == errObjectWormProtected || errorCause(err) == errObjectLocked {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		reqErrorIf(r, err, "Unable to delete an object %s", pathJoin(bucket, object))
 	}
 	writeSuccessNoContent(w)
 }
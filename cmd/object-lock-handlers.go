@@ -0,0 +1,386 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// readObjectLockBody - reads object lock, retention or legal hold XML
+// from request body.
+func readObjectLockBody(r *http.Request) ([]byte, APIErrorCode) {
+	// Object lock settings always need a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxObjectLockBodySize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+	return buffer.Bytes(), ErrNone
+}
+
+// updateObjectLock - replaces retention or legal hold saved in object
+// metadata by update, which returns an error to leave it unchanged.
+func updateObjectLock(r *http.Request, bucket, object string, objAPI ObjectLayer, nsMutex *nsLockMap, update func(objInfo ObjectInfo, metadata map[string]string) error) error {
+	// Objects can only be locked in buckets with object lock enabled.
+	config, err := getCachedBucketObjectLock(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return traceError(errObjectLockNotEnabled)
+	}
+
+	// Hold write lock on the object, metadata is replaced.
+	objectLock := nsMutex.NewRequestNSLock(r, bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined)+3)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	// Preserve ETag of the object.
+	metadata["md5Sum"] = objInfo.MD5Sum
+	if err = update(objInfo, metadata); err != nil {
+		return err
+	}
+
+	// Source and destination are same, only metadata is updated.
+	_, err = objAPI.CopyObject(bucket, object, bucket, object, metadata)
+	return err
+}
+
+// GetBucketObjectLockConfigHandler - GET Bucket object lock
+// -----------------
+// Returns the object lock configuration of the bucket.
+func (api objectAPIHandlers) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	config, err := loadBucketObjectLock(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, err := xml.Marshal(config)
+	if err != nil {
+		errorIf(err, "Unable to marshal object lock configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, configBytes)
+}
+
+// PutBucketObjectLockConfigHandler - PUT Bucket object lock
+// -----------------
+// Enables object lock of the bucket and sets the default retention of
+// new objects. Object lock can't be disabled once enabled.
+func (api objectAPIHandlers) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, s3Error := readObjectLockBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	config, s3Error := parseObjectLockConfig(configBytes)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = persistBucketObjectLock(bucket, config, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	notifyBucketConfigChange(bucket, bucketObjectLockConfig)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectRetentionHandler - GET Object retention
+// -----------------
+// Returns the retention of the object.
+func (api objectAPIHandlers) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObjectRetention", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIf(err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	mode, until := getObjectRetention(objInfo.UserDefined)
+	if mode == "" {
+		writeErrorResponse(w, ErrNoSuchObjectLock, r.URL)
+		return
+	}
+
+	retentionBytes, err := xml.Marshal(objectRetention{Mode: mode, RetainUntilDate: formatRetainUntilDate(until)})
+	if err != nil {
+		errorIf(err, "Unable to marshal retention into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, retentionBytes)
+}
+
+// PutObjectRetentionHandler - PUT Object retention
+// -----------------
+// Sets the retention of the object. Retention may be extended, it is
+// otherwise only changed or removed for governance retention along
+// with x-amz-bypass-governance-retention.
+func (api objectAPIHandlers) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObjectRetention", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	retentionBytes, s3Error := readObjectLockBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	retention, s3Error := parseObjectRetention(retentionBytes)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	bypassGovernance := isGovernanceBypassed(r)
+	err := updateObjectLock(r, bucket, object, objAPI, api.NSMutex(), func(objInfo ObjectInfo, metadata map[string]string) error {
+		mode, until := getObjectRetention(objInfo.UserDefined)
+		if mode != "" && until.After(UTCNow()) {
+			newUntil, _ := parseRetainUntilDate(retention.RetainUntilDate)
+			// Compliance retention is only extended, governance
+			// retention may become compliance retention.
+			extended := retention.Mode != "" && !newUntil.Before(until) &&
+				(mode == objectLockGovernance || retention.Mode == objectLockCompliance)
+			if !extended && !(mode == objectLockGovernance && bypassGovernance) {
+				return traceError(errObjectLocked)
+			}
+		}
+		if retention.Mode == "" {
+			delete(metadata, amzObjectLockMode)
+			delete(metadata, amzObjectLockRetainUntilDate)
+		} else {
+			metadata[amzObjectLockMode] = retention.Mode
+			metadata[amzObjectLockRetainUntilDate] = retention.RetainUntilDate
+		}
+		return nil
+	})
+	if err != nil {
+		errorIf(err, "Unable to update object retention.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectLegalHoldHandler - GET Object legal hold
+// -----------------
+// Returns the legal hold status of the object.
+func (api objectAPIHandlers) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObjectLegalHold", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIf(err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	status := objInfo.UserDefined[amzObjectLockLegalHold]
+	if status == "" {
+		writeErrorResponse(w, ErrNoSuchObjectLock, r.URL)
+		return
+	}
+
+	legalHoldBytes, err := xml.Marshal(objectLegalHold{Status: status})
+	if err != nil {
+		errorIf(err, "Unable to marshal legal hold into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, legalHoldBytes)
+}
+
+// PutObjectLegalHoldHandler - PUT Object legal hold
+// -----------------
+// Places or removes the legal hold of the object.
+func (api objectAPIHandlers) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObjectLegalHold", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	legalHoldBytes, s3Error := readObjectLockBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	legalHold, s3Error := parseObjectLegalHold(legalHoldBytes)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	err := updateObjectLock(r, bucket, object, objAPI, api.NSMutex(), func(objInfo ObjectInfo, metadata map[string]string) error {
+		metadata[amzObjectLockLegalHold] = legalHold.Status
+		return nil
+	})
+	if err != nil {
+		errorIf(err, "Unable to update object legal hold.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests object lock configuration, retention and legal hold handlers,
+// and that locked objects can't be overwritten or deleted.
+func TestObjectLockHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectLockHandlers, []string{
+		"GetObjectRetention", "PutObjectRetention", "GetObjectLegalHold", "PutObjectLegalHold",
+		"PutObject", "DeleteObject", "GetObjectLockConfiguration", "PutObjectLockConfiguration",
+	})
+}
+
+func testObjectLockHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	config := `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>` +
+		`<Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>1</Days></DefaultRetention></Rule></ObjectLockConfiguration>`
+	retention := func(mode string, until time.Time) string {
+		return "<Retention><Mode>" + mode + "</Mode><RetainUntilDate>" + until.Format(time.RFC3339) + "</RetainUntilDate></Retention>"
+	}
+	legalHold := func(status string) string {
+		return "<LegalHold><Status>" + status + "</Status></LegalHold>"
+	}
+	bypass := map[string]string{amzBypassGovernanceRetention: "true"}
+	now := UTCNow()
+
+	testCases := []struct {
+		method             string
+		url                string
+		header             map[string]string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test 1: object lock not enabled yet.
+		{"GET", getObjectLockURL("", bucketName, "", "object-lock"), nil, "", http.StatusNotFound},
+		// Test 2: object lock headers need object lock.
+		{"PUT", getPutObjectURL("", bucketName, "object"), map[string]string{amzObjectLockLegalHold: legalHoldOn}, "data", http.StatusBadRequest},
+		// Test 3: invalid object lock configuration.
+		{"PUT", getObjectLockURL("", bucketName, "", "object-lock"), nil, "<ObjectLockConfiguration></ObjectLockConfiguration>", http.StatusBadRequest},
+		// Test 4: enable object lock with default governance retention.
+		{"PUT", getObjectLockURL("", bucketName, "", "object-lock"), nil, config, http.StatusOK},
+		{"GET", getObjectLockURL("", bucketName, "", "object-lock"), nil, "", http.StatusOK},
+		// Test 6: new objects get default retention.
+		{"PUT", getPutObjectURL("", bucketName, "object"), nil, "data", http.StatusOK},
+		{"GET", getObjectLockURL("", bucketName, "object", "retention"), nil, "", http.StatusOK},
+		// Test 8: retained objects can't be overwritten or deleted.
+		{"PUT", getPutObjectURL("", bucketName, "object"), nil, "data", http.StatusForbidden},
+		{"DELETE", getDeleteObjectURL("", bucketName, "object"), nil, "", http.StatusForbidden},
+		// Test 10: retention can't be shortened without bypassing governance.
+		{"PUT", getObjectLockURL("", bucketName, "object", "retention"), nil, retention(objectLockGovernance, now.Add(time.Hour)), http.StatusForbidden},
+		// Test 11: retention is extended and becomes compliance retention.
+		{"PUT", getObjectLockURL("", bucketName, "object", "retention"), nil, retention(objectLockCompliance, now.Add(48*time.Hour)), http.StatusOK},
+		// Test 12: compliance retention can't be bypassed.
+		{"PUT", getObjectLockURL("", bucketName, "object", "retention"), bypass, "<Retention></Retention>", http.StatusForbidden},
+		{"DELETE", getDeleteObjectURL("", bucketName, "object"), bypass, "", http.StatusForbidden},
+		// Test 14: governance retention is removed by bypassing it.
+		{"PUT", getPutObjectURL("", bucketName, "held"), nil, "data", http.StatusOK},
+		{"PUT", getObjectLockURL("", bucketName, "held", "retention"), bypass, "<Retention></Retention>", http.StatusOK},
+		{"GET", getObjectLockURL("", bucketName, "held", "retention"), nil, "", http.StatusNotFound},
+		// Test 17: objects under legal hold can't be deleted.
+		{"GET", getObjectLockURL("", bucketName, "held", "legal-hold"), nil, "", http.StatusNotFound},
+		{"PUT", getObjectLockURL("", bucketName, "held", "legal-hold"), nil, legalHold("MAYBE"), http.StatusBadRequest},
+		{"PUT", getObjectLockURL("", bucketName, "held", "legal-hold"), nil, legalHold(legalHoldOn), http.StatusOK},
+		{"GET", getObjectLockURL("", bucketName, "held", "legal-hold"), nil, "", http.StatusOK},
+		{"DELETE", getDeleteObjectURL("", bucketName, "held"), bypass, "", http.StatusForbidden},
+		// Test 22: objects are deleted once legal hold is removed.
+		{"PUT", getObjectLockURL("", bucketName, "held", "legal-hold"), nil, legalHold(legalHoldOff), http.StatusOK},
+		{"DELETE", getDeleteObjectURL("", bucketName, "held"), nil, "", http.StatusNoContent},
+		// Test 24: retention of a missing object.
+		{"GET", getObjectLockURL("", bucketName, "held", "retention"), nil, "", http.StatusNotFound},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest(testCase.method, testCase.url, int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)))
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		for k, v := range testCase.header {
+			req.Header.Set(k, v)
+		}
+		if err = signRequestV4(req, credentials.AccessKey, credentials.SecretKey); err != nil {
+			t.Fatalf("Test %d: %s: Failed to sign HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Retention of the object is the extended compliance retention.
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("GET", getObjectLockURL("", bucketName, "object", "retention"), 0, nil,
+		credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	var objRetention objectRetention
+	if err = xml.Unmarshal(rec.Body.Bytes(), &objRetention); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objRetention.Mode != objectLockCompliance {
+		t.Fatalf("%s: Expected retention mode %s, got %s", instanceType, objectLockCompliance, objRetention.Mode)
+	}
+}
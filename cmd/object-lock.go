@@ -0,0 +1,360 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// Object lock config file name, saved alongside other bucket
+	// configs in minioMetaBucket.
+	bucketObjectLockConfig = "object-lock.xml"
+
+	// Maximum size of object lock, retention and legal hold XML.
+	maxObjectLockBodySize = 256 * 1024
+)
+
+// Object lock headers, sent by clients and saved in object metadata.
+const (
+	amzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	amzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	amzObjectLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+
+	// Header of deletes and retention changes of objects under
+	// governance retention.
+	amzBypassGovernanceRetention = "X-Amz-Bypass-Governance-Retention"
+
+	// Header of PutBucket enabling object lock of the new bucket.
+	amzBucketObjectLockEnabled = "X-Amz-Bucket-Object-Lock-Enabled"
+)
+
+// Retention modes, objects under governance retention may be deleted
+// by requests bypassing it, objects under compliance retention can't
+// be deleted by anyone until their retention expires.
+const (
+	objectLockGovernance = "GOVERNANCE"
+	objectLockCompliance = "COMPLIANCE"
+)
+
+// Legal hold status, objects under legal hold can't be deleted until
+// it is removed.
+const (
+	legalHoldOn  = "ON"
+	legalHoldOff = "OFF"
+)
+
+// Value of ObjectLockEnabled, object lock can't be disabled once
+// enabled.
+const objectLockEnabled = "Enabled"
+
+var (
+	errNoSuchObjectLockConfiguration = errors.New("Object Lock configuration does not exist for this bucket")
+	errObjectLockNotEnabled          = errors.New("Bucket is missing Object Lock Configuration")
+	errObjectLocked                  = errors.New("Access Denied because object protected by object lock")
+)
+
+// objectLockDefaultRetention - retention of new objects not sending
+// their own, for either Days or Years.
+type objectLockDefaultRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+// objectLockRule - default retention rule of a bucket.
+type objectLockRule struct {
+	DefaultRetention objectLockDefaultRetention `xml:"DefaultRetention"`
+}
+
+// objectLockConfig - object lock configuration of a bucket,
+// compatible with AWS S3 PutObjectLockConfiguration.
+type objectLockConfig struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+// objectRetention - retention of an object, empty if it has none.
+type objectRetention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Mode            string   `xml:"Mode,omitempty"`
+	RetainUntilDate string   `xml:"RetainUntilDate,omitempty"`
+}
+
+// objectLegalHold - legal hold of an object.
+type objectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+// isValidRetentionMode - returns true for known retention modes.
+func isValidRetentionMode(mode string) bool {
+	return mode == objectLockGovernance || mode == objectLockCompliance
+}
+
+// parseObjectLockConfig - parses and validates object lock
+// configuration of a bucket.
+func parseObjectLockConfig(data []byte) (objectLockConfig, APIErrorCode) {
+	var config objectLockConfig
+	if err := xml.Unmarshal(data, &config); err != nil {
+		return config, ErrMalformedXML
+	}
+	if config.ObjectLockEnabled != objectLockEnabled {
+		return config, ErrInvalidObjectLockConfiguration
+	}
+	if config.Rule != nil {
+		retention := config.Rule.DefaultRetention
+		if !isValidRetentionMode(retention.Mode) {
+			return config, ErrInvalidObjectLockConfiguration
+		}
+		// Exactly one of days and years.
+		if (retention.Days > 0) == (retention.Years > 0) || retention.Days < 0 || retention.Years < 0 {
+			return config, ErrInvalidObjectLockConfiguration
+		}
+	}
+	return config, ErrNone
+}
+
+// getDefaultRetainUntilDate - returns until when new objects written
+// at now are retained by default, zero if they aren't.
+func (config objectLockConfig) getDefaultRetainUntilDate(now time.Time) (mode string, until time.Time) {
+	if config.Rule == nil {
+		return "", until
+	}
+	retention := config.Rule.DefaultRetention
+	return retention.Mode, now.AddDate(retention.Years, 0, retention.Days)
+}
+
+// parseRetainUntilDate - parses a retain until date, ISO 8601 like
+// "2030-01-01T00:00:00Z" or with milliseconds.
+func parseRetainUntilDate(date string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, date)
+}
+
+// formatRetainUntilDate - formats a retain until date like AWS S3.
+func formatRetainUntilDate(until time.Time) string {
+	return until.UTC().Format(timeFormatAMZLong)
+}
+
+// parseObjectRetention - parses and validates retention of an object,
+// both mode and date are empty to remove it.
+func parseObjectRetention(data []byte) (objectRetention, APIErrorCode) {
+	var retention objectRetention
+	if err := xml.Unmarshal(data, &retention); err != nil {
+		return retention, ErrMalformedXML
+	}
+	if retention.Mode == "" && retention.RetainUntilDate == "" {
+		return retention, ErrNone
+	}
+	if !isValidRetentionMode(retention.Mode) {
+		return retention, ErrInvalidObjectRetention
+	}
+	until, err := parseRetainUntilDate(retention.RetainUntilDate)
+	if err != nil || !until.After(UTCNow()) {
+		return retention, ErrInvalidObjectRetention
+	}
+	retention.RetainUntilDate = formatRetainUntilDate(until)
+	return retention, ErrNone
+}
+
+// parseObjectLegalHold - parses and validates legal hold of an object.
+func parseObjectLegalHold(data []byte) (objectLegalHold, APIErrorCode) {
+	var legalHold objectLegalHold
+	if err := xml.Unmarshal(data, &legalHold); err != nil {
+		return legalHold, ErrMalformedXML
+	}
+	if legalHold.Status != legalHoldOn && legalHold.Status != legalHoldOff {
+		return legalHold, ErrInvalidObjectLegalHold
+	}
+	return legalHold, ErrNone
+}
+
+// getObjectRetention - returns retention saved in object metadata,
+// empty mode if the object has none.
+func getObjectRetention(metadata map[string]string) (mode string, until time.Time) {
+	mode = metadata[amzObjectLockMode]
+	if mode == "" {
+		return "", until
+	}
+	until, err := parseRetainUntilDate(metadata[amzObjectLockRetainUntilDate])
+	if err != nil {
+		return "", until
+	}
+	return mode, until
+}
+
+// checkObjectLock - returns errObjectLocked if an object can't be
+// overwritten or deleted, because it is under legal hold or under
+// retention. Governance retention is bypassed if bypassGovernance is
+// set.
+func checkObjectLock(objInfo ObjectInfo, bypassGovernance bool) error {
+	if objInfo.UserDefined[amzObjectLockLegalHold] == legalHoldOn {
+		return traceError(errObjectLocked)
+	}
+	mode, until := getObjectRetention(objInfo.UserDefined)
+	if mode == "" || !until.After(UTCNow()) {
+		return nil
+	}
+	if mode == objectLockGovernance && bypassGovernance {
+		return nil
+	}
+	return traceError(errObjectLocked)
+}
+
+// isGovernanceBypassed - returns true if the request bypasses
+// governance retention.
+func isGovernanceBypassed(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get(amzBypassGovernanceRetention), "true")
+}
+
+// setObjectLockMetadata - saves retention and legal hold of a new
+// object of bucket in metadata, from request headers or the default
+// retention of the bucket. Object lock of copied metadata is not
+// kept.
+func setObjectLockMetadata(objAPI ObjectLayer, bucket string, header http.Header, metadata map[string]string) APIErrorCode {
+	delete(metadata, amzObjectLockMode)
+	delete(metadata, amzObjectLockRetainUntilDate)
+	delete(metadata, amzObjectLockLegalHold)
+
+	mode := header.Get(amzObjectLockMode)
+	date := header.Get(amzObjectLockRetainUntilDate)
+	legalHold := header.Get(amzObjectLockLegalHold)
+
+	config, err := getCachedBucketObjectLock(bucket, objAPI)
+	if err != nil {
+		return toAPIErrorCode(err)
+	}
+	if config == nil {
+		if mode != "" || date != "" || legalHold != "" {
+			return ErrObjectLockNotEnabled
+		}
+		return ErrNone
+	}
+
+	var until time.Time
+	switch {
+	case mode == "" && date == "":
+		mode, until = config.getDefaultRetainUntilDate(UTCNow())
+	case !isValidRetentionMode(mode):
+		return ErrInvalidObjectRetention
+	default:
+		if until, err = parseRetainUntilDate(date); err != nil || !until.After(UTCNow()) {
+			return ErrInvalidObjectRetention
+		}
+	}
+	if mode != "" {
+		metadata[amzObjectLockMode] = mode
+		metadata[amzObjectLockRetainUntilDate] = formatRetainUntilDate(until)
+	}
+
+	switch legalHold {
+	case "":
+	case legalHoldOn, legalHoldOff:
+		metadata[amzObjectLockLegalHold] = legalHold
+	default:
+		return ErrInvalidObjectLegalHold
+	}
+	return ErrNone
+}
+
+// loadBucketObjectLock - loads object lock configuration of a bucket,
+// returns errNoSuchObjectLockConfiguration if object lock is not
+// enabled.
+func loadBucketObjectLock(bucket string, objAPI ObjectLayer) (*objectLockConfig, error) {
+	lockPath := path.Join(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+
+	// Acquire a read lock on object lock config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lockPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, lockPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchObjectLockConfiguration
+		}
+		errorIf(err, "Unable to load object lock configuration for bucket %s", bucket)
+		return nil, err
+	}
+
+	config := &objectLockConfig{}
+	if err = xml.Unmarshal(buffer.Bytes(), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// persistBucketObjectLock - saves object lock configuration of a
+// bucket.
+func persistBucketObjectLock(bucket string, config objectLockConfig, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	lockPath := path.Join(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+
+	// Acquire a write lock on object lock config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lockPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, lockPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write object lock configuration for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketObjectLock - removes object lock configuration of a
+// bucket, only used during DeleteBucket.
+func removeBucketObjectLock(bucket string, objAPI ObjectLayer) error {
+	lockPath := path.Join(bucketConfigPrefix, bucket, bucketObjectLockConfig)
+
+	// Acquire a write lock on object lock config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, lockPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, lockPath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketObjectLock - returns object lock configuration of
+// bucket, nil if object lock is not enabled. Configurations are cached
+// in globalBucketConfigCache.
+func getCachedBucketObjectLock(bucket string, objAPI ObjectLayer) (*objectLockConfig, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketObjectLockConfig, func() (interface{}, error) {
+		config, err := loadBucketObjectLock(bucket, objAPI)
+		if err == errNoSuchObjectLockConfiguration {
+			return (*objectLockConfig)(nil), nil
+		}
+		return config, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*objectLockConfig), nil
+}
@@ -0,0 +1,214 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Tests validation of object lock configurations.
+func TestParseObjectLockConfig(t *testing.T) {
+	config := func(settings string) string {
+		return "<ObjectLockConfiguration>" + settings + "</ObjectLockConfiguration>"
+	}
+	rule := func(retention string) string {
+		return "<ObjectLockEnabled>Enabled</ObjectLockEnabled><Rule><DefaultRetention>" + retention + "</DefaultRetention></Rule>"
+	}
+
+	testCases := []struct {
+		data     string
+		expected APIErrorCode
+	}{
+		// Test 1: enabled without default retention.
+		{config("<ObjectLockEnabled>Enabled</ObjectLockEnabled>"), ErrNone},
+		// Test 2: default retention in days.
+		{config(rule("<Mode>GOVERNANCE</Mode><Days>30</Days>")), ErrNone},
+		// Test 3: default retention in years.
+		{config(rule("<Mode>COMPLIANCE</Mode><Years>7</Years>")), ErrNone},
+		// Test 4: malformed XML.
+		{"<ObjectLockConfiguration>", ErrMalformedXML},
+		// Test 5: object lock not enabled.
+		{config(""), ErrInvalidObjectLockConfiguration},
+		// Test 6: unknown mode.
+		{config(rule("<Mode>STRICT</Mode><Days>30</Days>")), ErrInvalidObjectLockConfiguration},
+		// Test 7: both days and years.
+		{config(rule("<Mode>GOVERNANCE</Mode><Days>30</Days><Years>1</Years>")), ErrInvalidObjectLockConfiguration},
+		// Test 8: neither days nor years.
+		{config(rule("<Mode>GOVERNANCE</Mode>")), ErrInvalidObjectLockConfiguration},
+		// Test 9: negative days.
+		{config(rule("<Mode>GOVERNANCE</Mode><Days>-1</Days>")), ErrInvalidObjectLockConfiguration},
+	}
+	for i, testCase := range testCases {
+		if _, s3Error := parseObjectLockConfig([]byte(testCase.data)); s3Error != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests validation of object retention and legal hold.
+func TestParseObjectRetention(t *testing.T) {
+	future := UTCNow().Add(time.Hour).Format(time.RFC3339)
+	past := UTCNow().Add(-time.Hour).Format(time.RFC3339)
+
+	testCases := []struct {
+		data     string
+		expected APIErrorCode
+	}{
+		// Test 1: governance retention.
+		{"<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>" + future + "</RetainUntilDate></Retention>", ErrNone},
+		// Test 2: empty retention removes it.
+		{"<Retention></Retention>", ErrNone},
+		// Test 3: malformed XML.
+		{"<Retention>", ErrMalformedXML},
+		// Test 4: unknown mode.
+		{"<Retention><Mode>STRICT</Mode><RetainUntilDate>" + future + "</RetainUntilDate></Retention>", ErrInvalidObjectRetention},
+		// Test 5: mode without date.
+		{"<Retention><Mode>COMPLIANCE</Mode></Retention>", ErrInvalidObjectRetention},
+		// Test 6: date in the past.
+		{"<Retention><Mode>COMPLIANCE</Mode><RetainUntilDate>" + past + "</RetainUntilDate></Retention>", ErrInvalidObjectRetention},
+		// Test 7: invalid date.
+		{"<Retention><Mode>COMPLIANCE</Mode><RetainUntilDate>tomorrow</RetainUntilDate></Retention>", ErrInvalidObjectRetention},
+	}
+	for i, testCase := range testCases {
+		if _, s3Error := parseObjectRetention([]byte(testCase.data)); s3Error != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+
+	legalHoldCases := []struct {
+		data     string
+		expected APIErrorCode
+	}{
+		// Test 1: legal hold on.
+		{"<LegalHold><Status>ON</Status></LegalHold>", ErrNone},
+		// Test 2: legal hold off.
+		{"<LegalHold><Status>OFF</Status></LegalHold>", ErrNone},
+		// Test 3: unknown status.
+		{"<LegalHold><Status>MAYBE</Status></LegalHold>", ErrInvalidObjectLegalHold},
+		// Test 4: malformed XML.
+		{"<LegalHold>", ErrMalformedXML},
+	}
+	for i, testCase := range legalHoldCases {
+		if _, s3Error := parseObjectLegalHold([]byte(testCase.data)); s3Error != testCase.expected {
+			t.Errorf("Legal hold test %d: expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests locked objects can't be overwritten or deleted.
+func TestCheckObjectLock(t *testing.T) {
+	future := formatRetainUntilDate(UTCNow().Add(time.Hour))
+	past := formatRetainUntilDate(UTCNow().Add(-time.Hour))
+
+	testCases := []struct {
+		metadata         map[string]string
+		bypassGovernance bool
+		locked           bool
+	}{
+		// Test 1: no object lock.
+		{map[string]string{}, false, false},
+		// Test 2: legal hold.
+		{map[string]string{amzObjectLockLegalHold: legalHoldOn}, true, true},
+		// Test 3: legal hold removed.
+		{map[string]string{amzObjectLockLegalHold: legalHoldOff}, false, false},
+		// Test 4: governance retention.
+		{map[string]string{amzObjectLockMode: objectLockGovernance, amzObjectLockRetainUntilDate: future}, false, true},
+		// Test 5: governance retention bypassed.
+		{map[string]string{amzObjectLockMode: objectLockGovernance, amzObjectLockRetainUntilDate: future}, true, false},
+		// Test 6: compliance retention can't be bypassed.
+		{map[string]string{amzObjectLockMode: objectLockCompliance, amzObjectLockRetainUntilDate: future}, true, true},
+		// Test 7: expired retention.
+		{map[string]string{amzObjectLockMode: objectLockCompliance, amzObjectLockRetainUntilDate: past}, false, false},
+	}
+	for i, testCase := range testCases {
+		err := checkObjectLock(ObjectInfo{UserDefined: testCase.metadata}, testCase.bypassGovernance)
+		if locked := errorCause(err) == errObjectLocked; locked != testCase.locked {
+			t.Errorf("Test %d: expected locked %v, got %v", i+1, testCase.locked, locked)
+		}
+	}
+}
+
+// Tests object lock headers and default retention are saved in
+// metadata of new objects.
+func TestSetObjectLockMetadata(t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+	for bucket, config := range map[string]*objectLockConfig{
+		"unlocked": nil,
+		"locked":   {ObjectLockEnabled: objectLockEnabled},
+		"default": {
+			ObjectLockEnabled: objectLockEnabled,
+			Rule:              &objectLockRule{DefaultRetention: objectLockDefaultRetention{Mode: objectLockCompliance, Days: 1}},
+		},
+	} {
+		config := config
+		globalBucketConfigCache.get(bucket, bucketObjectLockConfig, func() (interface{}, error) { return config, nil })
+	}
+	future := UTCNow().Add(time.Hour).Format(time.RFC3339)
+
+	testCases := []struct {
+		bucket       string
+		header       http.Header
+		expectedErr  APIErrorCode
+		expectedMode string
+		expectedHold string
+	}{
+		// Test 1: bucket without object lock.
+		{"unlocked", http.Header{}, ErrNone, "", ""},
+		// Test 2: object lock headers need object lock.
+		{"unlocked", http.Header{amzObjectLockLegalHold: []string{legalHoldOn}}, ErrObjectLockNotEnabled, "", ""},
+		// Test 3: no retention by default.
+		{"locked", http.Header{}, ErrNone, "", ""},
+		// Test 4: retention and legal hold of the object.
+		{"locked", http.Header{
+			amzObjectLockMode:            []string{objectLockGovernance},
+			amzObjectLockRetainUntilDate: []string{future},
+			amzObjectLockLegalHold:       []string{legalHoldOn},
+		}, ErrNone, objectLockGovernance, legalHoldOn},
+		// Test 5: default retention of the bucket.
+		{"default", http.Header{}, ErrNone, objectLockCompliance, ""},
+		// Test 6: retention of the object overrides default retention.
+		{"default", http.Header{
+			amzObjectLockMode:            []string{objectLockGovernance},
+			amzObjectLockRetainUntilDate: []string{future},
+		}, ErrNone, objectLockGovernance, ""},
+		// Test 7: mode without date.
+		{"locked", http.Header{amzObjectLockMode: []string{objectLockGovernance}}, ErrInvalidObjectRetention, "", ""},
+		// Test 8: unknown legal hold status.
+		{"locked", http.Header{amzObjectLockLegalHold: []string{"MAYBE"}}, ErrInvalidObjectLegalHold, "", ""},
+	}
+	for i, testCase := range testCases {
+		// Lock metadata of the client is never trusted.
+		metadata := map[string]string{amzObjectLockMode: objectLockCompliance}
+		s3Error := setObjectLockMetadata(nil, testCase.bucket, testCase.header, metadata)
+		if s3Error != testCase.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, s3Error)
+			continue
+		}
+		if s3Error != ErrNone {
+			continue
+		}
+		if mode := metadata[amzObjectLockMode]; mode != testCase.expectedMode {
+			t.Errorf("Test %d: expected mode %q, got %q", i+1, testCase.expectedMode, mode)
+		}
+		if hold := metadata[amzObjectLockLegalHold]; hold != testCase.expectedHold {
+			t.Errorf("Test %d: expected legal hold %q, got %q", i+1, testCase.expectedHold, hold)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	router "github.com/gorilla/mux"
+)
+
+// Move is a Minio extension moving an object to a new name, in the
+// same bucket or another one
+//
+//	POST /bucket/object?move
+//	X-Minio-Move-Source: /srcbucket/srcobject
+const (
+	moveQuery = "move"
+
+	// Header naming the object to move.
+	minioMoveSource = "X-Minio-Move-Source"
+)
+
+// Internal error used to signal an object can't be moved by an
+// object layer without copying its data.
+var errObjectNotMovable = errors.New("Object can't be moved without copying its data")
+
+// objectMover is implemented by object layers able to move an object
+// without copying its data.
+type objectMover interface {
+	MoveObject(srcBucket, srcObject, dstBucket, dstObject string) (ObjectInfo, error)
+}
+
+// moveObjectByCopy - moves an object by copying it to destination
+// and deleting the source.
+func moveObjectByCopy(objAPI ObjectLayer, srcInfo ObjectInfo, dstBucket, dstObject string) (ObjectInfo, error) {
+	metadata := make(map[string]string)
+	for k, v := range srcInfo.UserDefined {
+		metadata[k] = v
+	}
+	// Let CopyObject calculate md5sum of objects uploaded as multipart.
+	delete(metadata, "md5Sum")
+
+	objInfo, err := objAPI.CopyObject(srcInfo.Bucket, srcInfo.Name, dstBucket, dstObject, metadata)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err = objAPI.DeleteObject(srcInfo.Bucket, srcInfo.Name); err != nil {
+		return ObjectInfo{}, err
+	}
+	return objInfo, nil
+}
+
+// moveObject - moves an object, without copying its data if the
+// object layer supports it.
+func moveObject(objAPI ObjectLayer, srcInfo ObjectInfo, dstBucket, dstObject string) (ObjectInfo, error) {
+	if mover, ok := objAPI.(objectMover); ok {
+		objInfo, err := mover.MoveObject(srcInfo.Bucket, srcInfo.Name, dstBucket, dstObject)
+		if errorCause(err) != errObjectNotMovable {
+			return objInfo, err
+		}
+	}
+	return moveObjectByCopy(objAPI, srcInfo, dstBucket, dstObject)
+}
+
+// MoveObjectHandler - POST /bucket/object?move
+// ----------
+// Moves the object named by X-Minio-Move-Source header to the object
+// of the request, along with its metadata.
+func (api objectAPIHandlers) MoveObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := router.Vars(r)
+	dstBucket := vars["bucket"]
+	dstObject := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, dstBucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Move source path.
+	mvSrcPath, err := url.QueryUnescape(r.Header.Get(minioMoveSource))
+	if err != nil {
+		// Save unescaped string as is.
+		mvSrcPath = r.Header.Get(minioMoveSource)
+	}
+	srcBucket, srcObject := path2BucketAndObject(mvSrcPath)
+	if srcObject == "" || srcBucket == "" {
+		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		return
+	}
+	if srcBucket == dstBucket && srcObject == dstObject {
+		writeErrorResponse(w, ErrInvalidCopyDest, r.URL)
+		return
+	}
+
+	// Source is deleted as well.
+	if s3Error := api.checkRequestAuthType(r, srcBucket, "s3:DeleteObject", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	for _, action := range []string{"s3:GetObject", "s3:DeleteObject"} {
+		if s3Error := checkSessionPolicy(r, action, pathJoin(srcBucket, srcObject)); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Hold write locks on both objects, in the same order for all
+	// requests.
+	lockPaths := [][2]string{{srcBucket, srcObject}, {dstBucket, dstObject}}
+	if pathJoin(dstBucket, dstObject) < pathJoin(srcBucket, srcObject) {
+		lockPaths[0], lockPaths[1] = lockPaths[1], lockPaths[0]
+	}
+	for _, lockPath := range lockPaths {
+		objectLock := api.NSMutex().NewRequestNSLock(r, lockPath[0], lockPath[1])
+		if err = objectLock.GetLock(getRequestDeadline(r)); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		defer objectLock.Unlock()
+	}
+
+	srcInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Data of transitioned objects is in the remote tier, stored
+	// under their name.
+	if isObjectTransitioned(srcInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
+	// Objects of WORM buckets can neither be overwritten nor deleted.
+	if err = checkWormOverwrite(objectAPI, srcBucket, srcObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	if err = checkWormOverwrite(objectAPI, dstBucket, dstObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	objInfo, err := moveObject(objectAPI, srcInfo, dstBucket, dstObject)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to move object %s/%s.", srcBucket, srcObject)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := generateCopyObjectResponse(objInfo.MD5Sum, objInfo.ModTime)
+	writeSuccessResponseXML(w, encodeResponse(response))
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host, port = "", ""
+	}
+
+	// Notify object created event, and object deleted event of the
+	// source.
+	eventNotify(eventData{
+		Type:      ObjectCreatedCopy,
+		Bucket:    dstBucket,
+		ObjInfo:   objInfo,
+		ReqParams: extractReqParams(r),
+		UserAgent: r.UserAgent(),
+		Host:      host,
+		Port:      port,
+	})
+	eventNotify(eventData{
+		Type:   ObjectRemovedDelete,
+		Bucket: srcBucket,
+		ObjInfo: ObjectInfo{
+			Name: srcObject,
+		},
+		ReqParams: extractReqParams(r),
+		UserAgent: r.UserAgent(),
+		Host:      host,
+		Port:      port,
+	})
+}
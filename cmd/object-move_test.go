@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Tests moving objects on FS links their data.
+func TestFSMoveObject(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	obj, disk, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{disk})
+	fs := obj.(*fsObjects)
+
+	for _, bucket := range []string{"src", "dst"} {
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metadata := map[string]string{"content-type": "text/plain", "X-Amz-Meta-Color": "blue"}
+	srcInfo, err := obj.PutObject("src", "dir/object", 5, bytes.NewReader([]byte("hello")), metadata, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject("dst", "dir/object", 3, bytes.NewReader([]byte("old")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	srcFI, err := os.Stat(pathJoin(disk, "src", "dir/object"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = fs.MoveObject("src", "nosuchobject", "dst", "object"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+	if _, err = fs.MoveObject("src", "dir/object", "nosuchbucket", "object"); !isBucketNotFound(err) {
+		t.Fatalf("Expected BucketNotFound, got %v", err)
+	}
+
+	// Existing destination is replaced.
+	dstInfo, err := fs.MoveObject("src", "dir/object", "dst", "dir/object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dstInfo.Bucket != "dst" || dstInfo.Size != srcInfo.Size || dstInfo.MD5Sum != srcInfo.MD5Sum ||
+		!reflect.DeepEqual(dstInfo.UserDefined, srcInfo.UserDefined) {
+		t.Fatalf("Expected %+v, got %+v", srcInfo, dstInfo)
+	}
+	dstFI, err := os.Stat(pathJoin(disk, "dst", "dir/object"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(srcFI, dstFI) {
+		t.Fatal("Expected moved object data to be linked")
+	}
+	if dstInfo, err = obj.GetObjectInfo("dst", "dir/object"); err != nil {
+		t.Fatal(err)
+	}
+	if dstInfo.ContentType != "text/plain" || dstInfo.UserDefined["X-Amz-Meta-Color"] != "blue" {
+		t.Fatalf("Expected moved object metadata, got %v", dstInfo.UserDefined)
+	}
+
+	// Source is gone, along with its metadata.
+	if _, err = obj.GetObjectInfo("src", "dir/object"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+	if _, err = os.Stat(pathJoin(disk, minioMetaBucket, bucketMetaPrefix, "src", "dir/object", fsMetaJSONFile)); !os.IsNotExist(err) {
+		t.Fatalf("Expected source fs.json to be removed, got %v", err)
+	}
+	result, err := obj.ListObjects("src", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 0 || len(result.Prefixes) != 0 {
+		t.Fatalf("Expected no source objects, got %+v", result)
+	}
+}
+
+// Wrapper for calling Move object HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIMoveObjectHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIMoveObjectHandler, []string{"MoveObject"})
+}
+
+func testAPIMoveObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatal("Notifier initialization failed.")
+	}
+
+	metadata := map[string]string{"content-type": "text/plain"}
+	for _, name := range []string{"object1", "object2"} {
+		if _, err := obj.PutObject(bucketName, name, 5, bytes.NewReader([]byte(name[:5])), metadata, ""); err != nil {
+			t.Fatalf("%s: Unable to create object %s: %s", instanceType, name, err)
+		}
+	}
+
+	testCases := []struct {
+		source             string
+		objectName         string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Moving an object to a new name.
+		{
+			source:             pathJoin(bucketName, "object1"),
+			objectName:         "dir/moved",
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 2.
+		// Moving an object over another one.
+		{
+			source:             "/" + pathJoin(bucketName, "object2"),
+			objectName:         "dir/moved",
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 3.
+		// Source doesn't exist.
+		{
+			source:             pathJoin(bucketName, "object1"),
+			objectName:         "missing",
+			expectedRespStatus: http.StatusNotFound,
+		},
+		// Test case - 4.
+		// Moving an object to itself.
+		{
+			source:             pathJoin(bucketName, "dir/moved"),
+			objectName:         "dir/moved",
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 5.
+		// No source.
+		{
+			source:             "",
+			objectName:         "missing",
+			expectedRespStatus: http.StatusBadRequest,
+		},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", makeTestTargetURL("", bucketName, testCase.objectName, url.Values{moveQuery: []string{""}}),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		req.Header.Set(minioMoveSource, url.QueryEscape(testCase.source))
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code, rec.Body.String())
+		}
+		if testCase.expectedRespStatus != http.StatusOK {
+			continue
+		}
+
+		srcBucket, srcObject := path2BucketAndObject(testCase.source)
+		if _, err = obj.GetObjectInfo(srcBucket, srcObject); !isErrObjectNotFound(err) {
+			t.Fatalf("Test %d: %s: Expected source to be removed, got %v", i+1, instanceType, err)
+		}
+		objInfo, err := obj.GetObjectInfo(bucketName, testCase.objectName)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if objInfo.ContentType != "text/plain" {
+			t.Errorf("Test %d: %s: Expected metadata to be moved, got %v", i+1, instanceType, objInfo.UserDefined)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject(bucketName, testCase.objectName, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if buffer.String() != srcObject[:5] {
+			t.Errorf("Test %d: %s: Expected data %q, got %q", i+1, instanceType, srcObject[:5], buffer.String())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	router "github.com/gorilla/mux"
+)
+
+// Object validators is a Minio extension answering
+//
+//	GET /bucket/object?validators
+//
+// with the strong validators of the current version of an object, for
+// download managers to resume downloads with If-Range.
+const (
+	objectValidatorsQuery = "validators"
+
+	// Algorithm of the reported object checksum.
+	objectChecksumMD5 = "MD5"
+)
+
+// ObjectValidatorsResponse - format for object validators response.
+type ObjectValidatorsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ObjectValidatorsResult" json:"-"`
+
+	ETag         string
+	Size         int64
+	LastModified string
+	// Base64 encoded checksum of the object content, only known for
+	// objects not uploaded in parts.
+	Checksum          string `xml:",omitempty"`
+	ChecksumAlgorithm string `xml:",omitempty"`
+	// Changes whenever the object is overwritten, even with the same
+	// content.
+	Generation int64
+}
+
+// getObjectChecksum - returns base64 encoded MD5 sum of the object
+// content, ETag of objects uploaded in parts is not a content checksum.
+func getObjectChecksum(objInfo ObjectInfo) string {
+	if objInfo.MD5Sum == "" || strings.Contains(objInfo.MD5Sum, "-") {
+		return ""
+	}
+	md5Bytes, err := hex.DecodeString(objInfo.MD5Sum)
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(md5Bytes)
+}
+
+// getObjectValidators - returns validators of an object.
+func getObjectValidators(objInfo ObjectInfo) ObjectValidatorsResponse {
+	response := ObjectValidatorsResponse{
+		ETag:         "\"" + objInfo.MD5Sum + "\"",
+		Size:         objInfo.Size,
+		LastModified: objInfo.ModTime.UTC().Format(timeFormatAMZLong),
+		Checksum:     getObjectChecksum(objInfo),
+		Generation:   objInfo.ModTime.UnixNano(),
+	}
+	if response.Checksum != "" {
+		response.ChecksumAlgorithm = objectChecksumMD5
+	}
+	return response
+}
+
+// GetObjectValidatorsHandler - GET /bucket/object?validators
+// ----------
+// Returns ETag, size, checksum and generation of an object. ETag and
+// Last-Modified headers are set as for GET, to be sent in If-Range.
+func (api objectAPIHandlers) GetObjectValidatorsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := router.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObject", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		apiErr := toAPIErrorCode(err)
+		if apiErr == ErrNoSuchKey {
+			apiErr = errAllowableObjectNotFound(objectAPI, bucket, r)
+		}
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	// Validators of transitioned objects are those of the remote copy.
+	if isObjectTransitioned(objInfo) {
+		objInfo = getTransitionedObjectInfo(objInfo)
+	}
+
+	if !objInfo.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", objInfo.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if objInfo.MD5Sum != "" {
+		w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	}
+	writeSuccessResponseXML(w, encodeResponse(getObjectValidators(objInfo)))
+}
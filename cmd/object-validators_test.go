@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Tests validators reported for objects.
+func TestGetObjectValidators(t *testing.T) {
+	modTime := time.Date(2017, 9, 1, 10, 20, 30, 400, time.UTC)
+	testCases := []struct {
+		objInfo          ObjectInfo
+		expectedChecksum string
+	}{
+		// MD5 sum of empty content.
+		{ObjectInfo{MD5Sum: "d41d8cd98f00b204e9800998ecf8427e", Size: 0, ModTime: modTime}, "1B2M2Y8AsgTpgAmY7PhCfg=="},
+		// ETag of objects uploaded in parts is not a checksum.
+		{ObjectInfo{MD5Sum: "d41d8cd98f00b204e9800998ecf8427e-2", Size: 10, ModTime: modTime}, ""},
+		{ObjectInfo{MD5Sum: "", Size: 10, ModTime: modTime}, ""},
+	}
+	for i, testCase := range testCases {
+		response := getObjectValidators(testCase.objInfo)
+		if response.Checksum != testCase.expectedChecksum {
+			t.Errorf("Test %d: expected checksum %s, got %s", i+1, testCase.expectedChecksum, response.Checksum)
+		}
+		if (response.ChecksumAlgorithm != "") != (testCase.expectedChecksum != "") {
+			t.Errorf("Test %d: unexpected checksum algorithm %s", i+1, response.ChecksumAlgorithm)
+		}
+		if response.ETag != `"`+testCase.objInfo.MD5Sum+`"` || response.Size != testCase.objInfo.Size ||
+			response.Generation != modTime.UnixNano() {
+			t.Errorf("Test %d: unexpected response %+v", i+1, response)
+		}
+	}
+}
+
+// Wrapper for calling GetObjectValidators handler tests for both XL and FS.
+func TestAPIGetObjectValidatorsHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIGetObjectValidatorsHandler, []string{"GetObjectValidators"})
+}
+
+func testAPIGetObjectValidatorsHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "test-object"
+	data := []byte("hello, world")
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set(objectValidatorsQuery, "")
+
+	testCases := []struct {
+		bucket         string
+		object         string
+		accessKey      string
+		expectedStatus int
+	}{
+		{bucketName, objectName, credentials.AccessKey, http.StatusOK},
+		// Non existent object.
+		{bucketName, "missing-object", credentials.AccessKey, http.StatusNotFound},
+		// Non existent bucket.
+		{"nonexistent-bucket", objectName, credentials.AccessKey, http.StatusNotFound},
+		// Invalid credentials.
+		{bucketName, objectName, "invalid-access-key", http.StatusForbidden},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", makeTestTargetURL("", testCase.bucket, testCase.object, queryValues),
+			0, nil, testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: failed to create request: %s", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: %s: expected status %d, got %d", i+1, instanceType, testCase.expectedStatus, rec.Code)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		response := ObjectValidatorsResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if response.ETag != `"`+objInfo.MD5Sum+`"` || response.Size != int64(len(data)) ||
+			response.ChecksumAlgorithm != objectChecksumMD5 || response.Generation != objInfo.ModTime.UnixNano() {
+			t.Errorf("Test %d: %s: unexpected response %+v", i+1, instanceType, response)
+		}
+		if rec.Header().Get("ETag") != response.ETag {
+			t.Errorf("Test %d: %s: expected ETag header %s, got %s", i+1, instanceType, response.ETag, rec.Header().Get("ETag"))
+		}
+	}
+}
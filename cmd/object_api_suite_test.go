@@ -114,7 +114,7 @@ func testMultipartObjectCreation(obj ObjectLayer, instanceType string, c TestErr
 		if calcPartInfo.ETag != expectedMD5Sumhex {
 			c.Errorf("MD5 Mismatch")
 		}
-		completedParts.Parts = append(completedParts.Parts, completePart{
+		completedParts.Parts = append(completedParts.Parts, CompletePart{
 			PartNumber: i,
 			ETag:       calcPartInfo.ETag,
 		})
@@ -0,0 +1,137 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objecttest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+)
+
+// Tests creating, listing and deleting buckets.
+func testBuckets(t *testing.T, obj cmd.ObjectLayer) {
+	buckets := []string{"bucket-b", "bucket-a", "bucket-c"}
+	for _, bucket := range buckets {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatalf("Unable to create bucket %s: %s", bucket, err)
+		}
+	}
+
+	bucketInfo, err := obj.GetBucketInfo("bucket-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bucketInfo.Name != "bucket-a" || bucketInfo.Created.IsZero() {
+		t.Errorf("Expected bucket-a with its creation time, got %+v", bucketInfo)
+	}
+
+	// Buckets are listed in lexical order.
+	bucketInfos, err := obj.ListBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"bucket-a", "bucket-b", "bucket-c"}
+	if len(bucketInfos) != len(expected) {
+		t.Fatalf("Expected %d buckets, got %d", len(expected), len(bucketInfos))
+	}
+	for i, bucketInfo := range bucketInfos {
+		if bucketInfo.Name != expected[i] {
+			t.Errorf("Expected bucket %d to be %s, got %s", i+1, expected[i], bucketInfo.Name)
+		}
+	}
+
+	if err = obj.DeleteBucket("bucket-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.GetBucketInfo("bucket-b"); !isBucketNotFound(err) {
+		t.Errorf("Expected BucketNotFound for deleted bucket, got %v", err)
+	}
+	if bucketInfos, err = obj.ListBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if len(bucketInfos) != 2 {
+		t.Errorf("Expected 2 buckets, got %d", len(bucketInfos))
+	}
+
+	// A deleted bucket can be created again.
+	if err = obj.MakeBucket("bucket-b"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests errors of bucket operations.
+func testBucketErrors(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.PutObject("bucket", "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		op      func() error
+		isError func(error) bool
+	}{
+		{"MakeBucket existing bucket", func() error { return obj.MakeBucket("bucket") }, isBucketExists},
+		{"MakeBucket invalid name", func() error { return obj.MakeBucket("a") }, isBucketNameInvalid},
+		{"MakeBucket upper case name", func() error { return obj.MakeBucket("Bucket") }, isBucketNameInvalid},
+		{"GetBucketInfo missing bucket", func() error {
+			_, err := obj.GetBucketInfo("missing-bucket")
+			return err
+		}, isBucketNotFound},
+		{"GetBucketInfo invalid name", func() error {
+			_, err := obj.GetBucketInfo("a")
+			return err
+		}, isBucketNameInvalid},
+		{"DeleteBucket missing bucket", func() error { return obj.DeleteBucket("missing-bucket") }, isBucketNotFound},
+		{"DeleteBucket bucket not empty", func() error { return obj.DeleteBucket("bucket") }, isBucketNotEmpty},
+		{"ListObjects missing bucket", func() error {
+			_, err := obj.ListObjects("missing-bucket", "", "", "", 1000)
+			return err
+		}, isBucketNotFound},
+	}
+	for _, testCase := range testCases {
+		if err := testCase.op(); !testCase.isError(err) {
+			t.Errorf("%s: Unexpected error %v", testCase.name, err)
+		}
+	}
+}
+
+func isBucketNotFound(err error) bool {
+	_, ok := errorCause(err).(cmd.BucketNotFound)
+	return ok
+}
+
+func isBucketExists(err error) bool {
+	switch errorCause(err).(type) {
+	case cmd.BucketExists, cmd.BucketAlreadyOwnedByYou:
+		return true
+	}
+	return false
+}
+
+func isBucketNameInvalid(err error) bool {
+	_, ok := errorCause(err).(cmd.BucketNameInvalid)
+	return ok
+}
+
+func isBucketNotEmpty(err error) bool {
+	_, ok := errorCause(err).(cmd.BucketNotEmpty)
+	return ok
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objecttest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+)
+
+// Tests listing objects with prefixes, delimiters and markers.
+func testListObjects(t *testing.T, obj cmd.ObjectLayer) {
+	for _, bucket := range []string{"bucket", "empty-bucket"} {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	objects := []string{
+		"Asia-maps.png",
+		"Asia/India/India-summer-photos-1",
+		"Asia/India/Karnataka/Bangalore/Koramangala/pics",
+		"newPrefix0",
+		"newPrefix1",
+		"newzen/zen/recurse/again/again/again/pics",
+		"obj0",
+		"obj1",
+		"obj2",
+	}
+	for _, object := range objects {
+		if _, err := obj.PutObject("bucket", object, int64(len(object)), bytes.NewReader([]byte(object)), nil, ""); err != nil {
+			t.Fatalf("Unable to create object %s: %s", object, err)
+		}
+	}
+
+	testCases := []struct {
+		bucket, prefix, marker, delimiter string
+		maxKeys                           int
+
+		objects     []string
+		prefixes    []string
+		isTruncated bool
+	}{
+		// Test case - 1.
+		// Listing all objects.
+		{"bucket", "", "", "", 1000, objects, nil, false},
+		// Test case - 2.
+		// Listing an empty bucket.
+		{"empty-bucket", "", "", "", 1000, nil, nil, false},
+		// Test case - 3.
+		// Listing the top level with a delimiter.
+		{"bucket", "", "", "/", 1000,
+			[]string{"Asia-maps.png", "newPrefix0", "newPrefix1", "obj0", "obj1", "obj2"},
+			[]string{"Asia/", "newzen/"}, false},
+		// Test case - 4.
+		// Listing objects with a prefix.
+		{"bucket", "new", "", "", 1000,
+			[]string{"newPrefix0", "newPrefix1", "newzen/zen/recurse/again/again/again/pics"}, nil, false},
+		// Test case - 5.
+		// Listing a directory with a delimiter.
+		{"bucket", "Asia/India/", "", "/", 1000,
+			[]string{"Asia/India/India-summer-photos-1"}, []string{"Asia/India/Karnataka/"}, false},
+		// Test case - 6.
+		// Listing objects after a marker.
+		{"bucket", "", "newPrefix1", "", 1000,
+			[]string{"newzen/zen/recurse/again/again/again/pics", "obj0", "obj1", "obj2"}, nil, false},
+		// Test case - 7.
+		// Listing objects with a prefix after a marker.
+		{"bucket", "obj", "obj0", "", 1000, []string{"obj1", "obj2"}, nil, false},
+		// Test case - 8.
+		// Listing fewer objects than there are.
+		{"bucket", "", "", "", 3, objects[:3], nil, true},
+		// Test case - 9.
+		// Listing the last objects.
+		{"bucket", "", "obj0", "", 2, []string{"obj1", "obj2"}, nil, false},
+		// Test case - 10.
+		// Listing a prefix no object has.
+		{"bucket", "europe", "", "", 1000, nil, nil, false},
+		// Test case - 11.
+		// Listing a full object name as prefix.
+		{"bucket", "obj1", "", "", 1000, []string{"obj1"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		result, err := obj.ListObjects(testCase.bucket, testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if names := objectNames(result.Objects); !reflect.DeepEqual(names, testCase.objects) {
+			t.Errorf("Test %d: Expected objects %v, got %v", i+1, testCase.objects, names)
+		}
+		if len(result.Prefixes) != 0 || len(testCase.prefixes) != 0 {
+			if !reflect.DeepEqual(result.Prefixes, testCase.prefixes) {
+				t.Errorf("Test %d: Expected prefixes %v, got %v", i+1, testCase.prefixes, result.Prefixes)
+			}
+		}
+		if result.IsTruncated != testCase.isTruncated {
+			t.Errorf("Test %d: Expected truncated %v, got %v", i+1, testCase.isTruncated, result.IsTruncated)
+		}
+		for _, objInfo := range result.Objects {
+			if objInfo.Size != int64(len(objInfo.Name)) || objInfo.MD5Sum != getMD5Hash([]byte(objInfo.Name)) {
+				t.Errorf("Test %d: Unexpected object info %+v", i+1, objInfo)
+			}
+		}
+	}
+}
+
+// Tests listing all objects of a bucket page by page.
+func testListObjectsPaging(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	var objects []string
+	for i := 0; i < 25; i++ {
+		object := fmt.Sprintf("dir%d/object%02d", i%2, i)
+		if _, err := obj.PutObject("bucket", object, 1, bytes.NewReader([]byte("a")), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+	}
+
+	for _, maxKeys := range []int{1, 4, 10, 25} {
+		var listed []string
+		marker := ""
+		for {
+			result, err := obj.ListObjects("bucket", "", marker, "", maxKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Objects) > maxKeys {
+				t.Fatalf("Expected at most %d objects, got %d", maxKeys, len(result.Objects))
+			}
+			listed = append(listed, objectNames(result.Objects)...)
+			if !result.IsTruncated {
+				break
+			}
+			if len(result.Objects) == 0 {
+				t.Fatalf("Expected a truncated listing to return objects")
+			}
+			marker = result.Objects[len(result.Objects)-1].Name
+		}
+		expected := append(append([]string{}, filterPrefix(objects, "dir0/")...), filterPrefix(objects, "dir1/")...)
+		if !reflect.DeepEqual(listed, expected) {
+			t.Errorf("Paging by %d: Expected %v, got %v", maxKeys, expected, listed)
+		}
+	}
+}
+
+// objectNames - returns names of objects.
+func objectNames(objInfos []cmd.ObjectInfo) []string {
+	var names []string
+	for _, objInfo := range objInfos {
+		names = append(names, objInfo.Name)
+	}
+	return names
+}
+
+// filterPrefix - returns names starting with prefix.
+func filterPrefix(names []string, prefix string) []string {
+	var filtered []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,265 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objecttest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+)
+
+// Minimum size of parts other than the last one, as in S3.
+const minPartSize = 5 * 1024 * 1024
+
+// getCompleteMultipartMD5 - returns ETag of a multipart object made
+// of parts with the given ETags, as S3 computes it.
+func getCompleteMultipartMD5(etags ...string) string {
+	var md5Bytes []byte
+	for _, etag := range etags {
+		sum, _ := hex.DecodeString(etag)
+		md5Bytes = append(md5Bytes, sum...)
+	}
+	return fmt.Sprintf("%s-%d", getMD5Hash(md5Bytes), len(etags))
+}
+
+// Tests uploading objects in parts.
+func testMultipart(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := map[string]string{"content-type": "video/mp4", "X-Amz-Meta-Color": "green"}
+	uploadID, err := obj.NewMultipartUpload("bucket", "dir/object", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads are listed until completed.
+	uploads, err := obj.ListMultipartUploads("bucket", "dir/", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads.Uploads) != 1 || uploads.Uploads[0].Object != "dir/object" || uploads.Uploads[0].UploadID != uploadID {
+		t.Fatalf("Expected upload %s of dir/object, got %+v", uploadID, uploads.Uploads)
+	}
+
+	parts := [][]byte{
+		bytes.Repeat([]byte("a"), minPartSize),
+		bytes.Repeat([]byte("b"), minPartSize),
+		[]byte("c"),
+	}
+	// Parts are uploaded out of order, and a part is uploaded again.
+	order := []int{2, 1, 3, 1}
+	for _, partID := range order {
+		data := parts[partID-1]
+		partInfo, perr := obj.PutObjectPart("bucket", "dir/object", uploadID, partID, int64(len(data)), bytes.NewReader(data), getMD5Hash(data), "")
+		if perr != nil {
+			t.Fatalf("Unable to upload part %d: %s", partID, perr)
+		}
+		if partInfo.PartNumber != partID || partInfo.ETag != getMD5Hash(data) || partInfo.Size != int64(len(data)) {
+			t.Errorf("Unexpected info of part %d: %+v", partID, partInfo)
+		}
+	}
+
+	listed, err := obj.ListObjectParts("bucket", "dir/object", uploadID, 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Parts) != len(parts) || listed.IsTruncated {
+		t.Fatalf("Expected %d parts, got %+v", len(parts), listed)
+	}
+	for i, partInfo := range listed.Parts {
+		if partInfo.PartNumber != i+1 || partInfo.ETag != getMD5Hash(parts[i]) || partInfo.Size != int64(len(parts[i])) {
+			t.Errorf("Unexpected info of part %d: %+v", i+1, partInfo)
+		}
+	}
+
+	// Parts are listed page by page.
+	listed, err = obj.ListObjectParts("bucket", "dir/object", uploadID, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Parts) != 1 || listed.Parts[0].PartNumber != 2 || !listed.IsTruncated || listed.NextPartNumberMarker != 2 {
+		t.Errorf("Expected part 2 with more parts to list, got %+v", listed)
+	}
+
+	var etags []string
+	var completeParts []cmd.CompletePart
+	var data []byte
+	for i, part := range parts {
+		etags = append(etags, getMD5Hash(part))
+		completeParts = append(completeParts, cmd.CompletePart{PartNumber: i + 1, ETag: getMD5Hash(part)})
+		data = append(data, part...)
+	}
+	objInfo, err := obj.CompleteMultipartUpload("bucket", "dir/object", uploadID, completeParts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Sum := getCompleteMultipartMD5(etags...)
+	if objInfo.Size != int64(len(data)) || objInfo.MD5Sum != md5Sum {
+		t.Errorf("Expected object of size %d with ETag %s, got %+v", len(data), md5Sum, objInfo)
+	}
+
+	if objInfo, err = obj.GetObjectInfo("bucket", "dir/object"); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(data)) || objInfo.MD5Sum != md5Sum || objInfo.ContentType != "video/mp4" ||
+		objInfo.UserDefined["X-Amz-Meta-Color"] != "green" {
+		t.Errorf("Unexpected object info %+v", objInfo)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject("bucket", "dir/object", 0, objInfo.Size, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Error("Object data doesn't match its parts")
+	}
+	// Ranges across parts.
+	buffer.Reset()
+	if err = obj.GetObject("bucket", "dir/object", minPartSize-1, 2, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != "ab" {
+		t.Errorf("Expected range across parts to be \"ab\", got %q", buffer.String())
+	}
+
+	if uploads, err = obj.ListMultipartUploads("bucket", "", "", "", "", 1000); err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads.Uploads) != 0 {
+		t.Errorf("Expected no uploads once completed, got %+v", uploads.Uploads)
+	}
+	if _, err = obj.ListObjectParts("bucket", "dir/object", uploadID, 0, 1000); !isInvalidUploadID(err) {
+		t.Errorf("Expected InvalidUploadID for completed upload, got %v", err)
+	}
+
+	// Aborted uploads are forgotten.
+	if uploadID, err = obj.NewMultipartUpload("bucket", "aborted", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObjectPart("bucket", "aborted", uploadID, 1, 1, bytes.NewReader([]byte("a")), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.AbortMultipartUpload("bucket", "aborted", uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.ListObjectParts("bucket", "aborted", uploadID, 0, 1000); !isInvalidUploadID(err) {
+		t.Errorf("Expected InvalidUploadID for aborted upload, got %v", err)
+	}
+	if _, err = obj.GetObjectInfo("bucket", "aborted"); !isObjectNotFound(err) {
+		t.Errorf("Expected ObjectNotFound for aborted upload, got %v", err)
+	}
+}
+
+// Tests errors of multipart operations.
+func testMultipartErrors(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload("bucket", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := []byte("small")
+	for partID := 1; partID <= 2; partID++ {
+		if _, err = obj.PutObjectPart("bucket", "object", uploadID, partID, int64(len(small)), bytes.NewReader(small), "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		op      func() error
+		isError func(error) bool
+	}{
+		{"NewMultipartUpload missing bucket", func() error {
+			_, err := obj.NewMultipartUpload("missing-bucket", "object", nil)
+			return err
+		}, isBucketNotFound},
+		{"PutObjectPart unknown upload", func() error {
+			_, err := obj.PutObjectPart("bucket", "object", "unknown-upload", 1, 5, bytes.NewReader(small), "", "")
+			return err
+		}, isInvalidUploadID},
+		{"PutObjectPart bad digest", func() error {
+			_, err := obj.PutObjectPart("bucket", "object", uploadID, 3, 5, bytes.NewReader(small), getMD5Hash([]byte("other")), "")
+			return err
+		}, isBadDigest},
+		{"ListObjectParts unknown upload", func() error {
+			_, err := obj.ListObjectParts("bucket", "object", "unknown-upload", 0, 1000)
+			return err
+		}, isInvalidUploadID},
+		{"AbortMultipartUpload unknown upload", func() error {
+			return obj.AbortMultipartUpload("bucket", "object", "unknown-upload")
+		}, isInvalidUploadID},
+		{"CompleteMultipartUpload unknown upload", func() error {
+			_, err := obj.CompleteMultipartUpload("bucket", "object", "unknown-upload",
+				[]cmd.CompletePart{{PartNumber: 1, ETag: getMD5Hash(small)}})
+			return err
+		}, isInvalidUploadID},
+		{"CompleteMultipartUpload missing part", func() error {
+			_, err := obj.CompleteMultipartUpload("bucket", "object", uploadID,
+				[]cmd.CompletePart{{PartNumber: 4, ETag: getMD5Hash(small)}})
+			return err
+		}, isInvalidPart},
+		{"CompleteMultipartUpload wrong ETag", func() error {
+			_, err := obj.CompleteMultipartUpload("bucket", "object", uploadID,
+				[]cmd.CompletePart{{PartNumber: 1, ETag: getMD5Hash([]byte("other"))}})
+			return err
+		}, isBadDigest},
+		{"CompleteMultipartUpload part too small", func() error {
+			_, err := obj.CompleteMultipartUpload("bucket", "object", uploadID,
+				[]cmd.CompletePart{{PartNumber: 1, ETag: getMD5Hash(small)}, {PartNumber: 2, ETag: getMD5Hash(small)}})
+			return err
+		}, isPartTooSmall},
+	}
+	for _, testCase := range testCases {
+		if err := testCase.op(); !testCase.isError(err) {
+			t.Errorf("%s: Unexpected error %v", testCase.name, err)
+		}
+	}
+
+	// A failed completion leaves the upload in place.
+	if _, err = obj.ListObjectParts("bucket", "object", uploadID, 0, 1000); err != nil {
+		t.Errorf("Expected upload to be kept, got %v", err)
+	}
+	if _, err = obj.GetObjectInfo("bucket", "object"); !isObjectNotFound(err) {
+		t.Errorf("Expected ObjectNotFound, got %v", err)
+	}
+}
+
+func isInvalidUploadID(err error) bool {
+	_, ok := errorCause(err).(cmd.InvalidUploadID)
+	return ok
+}
+
+func isInvalidPart(err error) bool {
+	_, ok := errorCause(err).(cmd.InvalidPart)
+	return ok
+}
+
+func isPartTooSmall(err error) bool {
+	_, ok := errorCause(err).(cmd.PartTooSmall)
+	return ok
+}
+
+func isBadDigest(err error) bool {
+	_, ok := errorCause(err).(cmd.BadDigest)
+	return ok
+}
@@ -0,0 +1,268 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objecttest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+)
+
+// getMD5Hash - returns md5sum of data, hex encoded like ETags.
+func getMD5Hash(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Tests writing, reading and deleting objects.
+func testObjects(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("0123456789"), 100*1024)
+	testCases := []struct {
+		object   string
+		data     []byte
+		metadata map[string]string
+	}{
+		{"object", data, map[string]string{"content-type": "application/octet-stream", "X-Amz-Meta-Color": "blue"}},
+		{"dir/sub/object", data[:10], nil},
+		{"empty", nil, nil},
+	}
+	for i, testCase := range testCases {
+		// Object layers may add to metadata they are given.
+		metadata := make(map[string]string)
+		for k, v := range testCase.metadata {
+			metadata[k] = v
+		}
+		objInfo, err := obj.PutObject("bucket", testCase.object, int64(len(testCase.data)), bytes.NewReader(testCase.data), metadata, "")
+		if err != nil {
+			t.Fatalf("Test %d: Unable to create object: %s", i+1, err)
+		}
+		if objInfo.Bucket != "bucket" || objInfo.Name != testCase.object || objInfo.Size != int64(len(testCase.data)) ||
+			objInfo.MD5Sum != getMD5Hash(testCase.data) {
+			t.Errorf("Test %d: Unexpected object info %+v", i+1, objInfo)
+		}
+
+		if objInfo, err = obj.GetObjectInfo("bucket", testCase.object); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if objInfo.Size != int64(len(testCase.data)) || objInfo.MD5Sum != getMD5Hash(testCase.data) || objInfo.ModTime.IsZero() {
+			t.Errorf("Test %d: Unexpected object info %+v", i+1, objInfo)
+		}
+		for k, v := range testCase.metadata {
+			if objInfo.UserDefined[k] != v {
+				t.Errorf("Test %d: Expected metadata %s to be %s, got %s", i+1, k, v, objInfo.UserDefined[k])
+			}
+		}
+
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", testCase.object, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), testCase.data) {
+			t.Errorf("Test %d: Object data doesn't match", i+1)
+		}
+	}
+
+	// Ranges of an object.
+	ranges := []struct {
+		offset, length int64
+	}{
+		{0, 1},
+		{1, 10},
+		{int64(len(data)) - 5, 5},
+		{12345, 512 * 1024},
+	}
+	for i, r := range ranges {
+		var buffer bytes.Buffer
+		if err := obj.GetObject("bucket", "object", r.offset, r.length, &buffer); err != nil {
+			t.Fatalf("Range %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data[r.offset:r.offset+r.length]) {
+			t.Errorf("Range %d: Object data doesn't match", i+1)
+		}
+	}
+
+	// Overwriting an object replaces its data and metadata.
+	objInfo, err := obj.PutObject("bucket", "object", 3, bytes.NewReader([]byte("new")), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != 3 || objInfo.UserDefined["X-Amz-Meta-Color"] != "" {
+		t.Errorf("Expected object to be replaced, got %+v", objInfo)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject("bucket", "object", 0, 3, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != "new" {
+		t.Errorf("Expected new data, got %q", buffer.String())
+	}
+
+	for _, testCase := range testCases {
+		if err = obj.DeleteObject("bucket", testCase.object); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = obj.GetObjectInfo("bucket", testCase.object); !isObjectNotFound(err) {
+			t.Errorf("Expected ObjectNotFound for deleted object %s, got %v", testCase.object, err)
+		}
+	}
+
+	// Bucket is empty and can be deleted.
+	if err = obj.DeleteBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests errors of object operations.
+func testObjectErrors(t *testing.T, obj cmd.ObjectLayer) {
+	if err := obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.PutObject("bucket", "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		op      func() error
+		isError func(error) bool
+	}{
+		{"PutObject missing bucket", func() error {
+			_, err := obj.PutObject("missing-bucket", "object", 4, bytes.NewReader([]byte("data")), nil, "")
+			return err
+		}, isBucketNotFound},
+		{"PutObject invalid bucket name", func() error {
+			_, err := obj.PutObject("a", "object", 4, bytes.NewReader([]byte("data")), nil, "")
+			return err
+		}, isBucketNameInvalid},
+		{"PutObject invalid object name", func() error {
+			_, err := obj.PutObject("bucket", "", 4, bytes.NewReader([]byte("data")), nil, "")
+			return err
+		}, isObjectNameInvalid},
+		{"PutObject incomplete body", func() error {
+			_, err := obj.PutObject("bucket", "incomplete", 8, bytes.NewReader([]byte("data")), nil, "")
+			return err
+		}, isIncompleteBody},
+		{"GetObject missing object", func() error {
+			return obj.GetObject("bucket", "missing", 0, 0, &bytes.Buffer{})
+		}, isObjectNotFound},
+		{"GetObject missing bucket", func() error {
+			return obj.GetObject("missing-bucket", "object", 0, 4, &bytes.Buffer{})
+		}, isBucketNotFound},
+		{"GetObject invalid range", func() error {
+			return obj.GetObject("bucket", "object", 2, 4, &bytes.Buffer{})
+		}, isInvalidRange},
+		{"GetObjectInfo missing object", func() error {
+			_, err := obj.GetObjectInfo("bucket", "missing")
+			return err
+		}, isObjectNotFound},
+		{"GetObjectInfo missing bucket", func() error {
+			_, err := obj.GetObjectInfo("missing-bucket", "object")
+			return err
+		}, isBucketNotFound},
+		{"DeleteObject missing object", func() error {
+			return obj.DeleteObject("bucket", "missing")
+		}, isObjectNotFound},
+	}
+	for _, testCase := range testCases {
+		if err := testCase.op(); !testCase.isError(err) {
+			t.Errorf("%s: Unexpected error %v", testCase.name, err)
+		}
+	}
+
+	// An incomplete object is not created.
+	if _, err := obj.GetObjectInfo("bucket", "incomplete"); !isObjectNotFound(err) {
+		t.Errorf("Expected ObjectNotFound for incomplete object, got %v", err)
+	}
+}
+
+// Tests copying objects, and replacing metadata of an object.
+func testCopyObject(t *testing.T, obj cmd.ObjectLayer) {
+	for _, bucket := range []string{"src", "dst"} {
+		if err := obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := bytes.Repeat([]byte("a"), 1024*1024)
+	srcInfo, err := obj.PutObject("src", "object", int64(len(data)), bytes.NewReader(data), map[string]string{"content-type": "text/plain"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := map[string]string{"content-type": "image/png", "X-Amz-Meta-Color": "red"}
+	objInfo, err := obj.CopyObject("src", "object", "dst", "dir/copy", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Bucket != "dst" || objInfo.Name != "dir/copy" || objInfo.Size != srcInfo.Size || objInfo.MD5Sum != srcInfo.MD5Sum {
+		t.Errorf("Unexpected copy info %+v", objInfo)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject("dst", "dir/copy", 0, objInfo.Size, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Error("Copied object data doesn't match")
+	}
+	if objInfo, err = obj.GetObjectInfo("dst", "dir/copy"); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "image/png" || objInfo.UserDefined["X-Amz-Meta-Color"] != "red" {
+		t.Errorf("Expected metadata of copy to be replaced, got %v", objInfo.UserDefined)
+	}
+
+	// Copying an object onto itself replaces only its metadata.
+	if _, err = obj.CopyObject("src", "object", "src", "object", metadata); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo, err = obj.GetObjectInfo("src", "object"); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ContentType != "image/png" || objInfo.Size != srcInfo.Size {
+		t.Errorf("Expected only metadata of object to be replaced, got %+v", objInfo)
+	}
+
+	if _, err = obj.CopyObject("src", "missing", "dst", "copy", nil); !isObjectNotFound(err) {
+		t.Errorf("Expected ObjectNotFound copying missing object, got %v", err)
+	}
+}
+
+func isObjectNotFound(err error) bool {
+	_, ok := errorCause(err).(cmd.ObjectNotFound)
+	return ok
+}
+
+func isObjectNameInvalid(err error) bool {
+	_, ok := errorCause(err).(cmd.ObjectNameInvalid)
+	return ok
+}
+
+func isIncompleteBody(err error) bool {
+	_, ok := errorCause(err).(cmd.IncompleteBody)
+	return ok
+}
+
+func isInvalidRange(err error) bool {
+	_, ok := errorCause(err).(cmd.InvalidRange)
+	return ok
+}
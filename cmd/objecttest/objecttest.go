@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package objecttest provides a conformance suite for implementations
+// of cmd.ObjectLayer, verifying they behave like the FS and XL object
+// layers of Minio do, for use from tests of out-of-tree object layers
+//
+//	func TestObjectLayer(t *testing.T) {
+//		objecttest.Run(t, func(dir string) (cmd.ObjectLayer, error) {
+//			return newMyObjectLayer(dir)
+//		})
+//	}
+package objecttest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/cmd"
+)
+
+// NewObjectLayerFunc - returns a new object layer keeping its data
+// under dir, an empty directory owned by the calling test.
+type NewObjectLayerFunc func(dir string) (cmd.ObjectLayer, error)
+
+// TestFunc - a test of the suite, run against a new object layer.
+type TestFunc func(t *testing.T, obj cmd.ObjectLayer)
+
+// Tests of the suite, by name.
+var tests = []struct {
+	name string
+	test TestFunc
+}{
+	{"Buckets", testBuckets},
+	{"BucketErrors", testBucketErrors},
+	{"Objects", testObjects},
+	{"ObjectErrors", testObjectErrors},
+	{"CopyObject", testCopyObject},
+	{"ListObjects", testListObjects},
+	{"ListObjectsPaging", testListObjectsPaging},
+	{"Multipart", testMultipart},
+	{"MultipartErrors", testMultipartErrors},
+}
+
+// Run - runs all tests of the suite as parallel subtests of t, each
+// of them against a new object layer in its own temporary directory.
+func Run(t *testing.T, newObjLayer NewObjectLayerFunc) {
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ExecObjectLayerTest(t, newObjLayer, test.test)
+		})
+	}
+}
+
+// ExecObjectLayerTest - runs objTest against a new object layer
+// returned by newObjLayer, its temporary directory is removed once
+// the test is done.
+func ExecObjectLayerTest(t *testing.T, newObjLayer NewObjectLayerFunc, objTest TestFunc) {
+	dir, err := ioutil.TempDir("", "objecttest-")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	obj, err := newObjLayer(dir)
+	if err != nil {
+		t.Fatalf("Initialization of object layer failed: %s", err)
+	}
+	defer obj.Shutdown()
+
+	objTest(t, obj)
+}
+
+// errorCause - returns the underlying cause of errors returned by
+// object layers of Minio, which keep a stack trace along with it.
+func errorCause(err error) error {
+	if e, ok := err.(interface {
+		Cause() error
+	}); ok {
+		return e.Cause()
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"runtime"
+	"strconv"
+
+	router "github.com/gorilla/mux"
+)
+
+// Part size hint is a Minio extension answering
+//
+//	GET /bucket/object?part-size-hint&object-size=<bytes>
+//
+// with the part size and number of parallel part uploads the server
+// recommends for uploading an object of the given size.
+const (
+	partSizeHintQuery  = "part-size-hint"
+	partSizeObjectSize = "object-size"
+
+	// Maximum recommended parallel part uploads for an idle server.
+	partUploadMaxParallelism = 8
+
+	// Requests a server can serve concurrently per CPU without
+	// being considered loaded.
+	partUploadRequestsPerCPU = 4
+)
+
+// PartSizeHintResponse - format for part size hint response.
+type PartSizeHintResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PartSizeHintResult" json:"-"`
+
+	ObjectSize int64
+	// Recommended part size, all parts except the last one should
+	// be of this size.
+	PartSize   int64
+	PartsCount int
+	// Recommended number of parts to upload concurrently.
+	Parallelism int
+}
+
+// ceilFrac - returns numerator divided by denominator rounded up.
+func ceilFrac(numerator, denominator int64) int64 {
+	return (numerator + denominator - 1) / denominator
+}
+
+// getRecommendedPartSize - returns the smallest multiple of erasure
+// block size which is at least the minimum part size and fits the
+// object in maximum number of parts. Parts aligned to block size are
+// erasure coded without a partial block per part.
+func getRecommendedPartSize(objectSize int64) (partSize int64, partsCount int, err error) {
+	minPartSize, maxParts := getMinPartSize(), int64(getMaxParts())
+	if objectSize > maxParts*globalMaxPartSize {
+		return 0, 0, traceError(errDataTooLarge)
+	}
+
+	partSize = ceilFrac(objectSize, maxParts)
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+	partSize = ceilFrac(partSize, blockSizeV1) * blockSizeV1
+	if partSize > globalMaxPartSize {
+		partSize = globalMaxPartSize
+	}
+
+	// Object fits in a single part.
+	if objectSize <= partSize {
+		return objectSize, 1, nil
+	}
+	return partSize, int(ceilFrac(objectSize, partSize)), nil
+}
+
+// getRecommendedParallelism - returns number of parallel part uploads
+// for the number of requests being served, parallelism is reduced as
+// the server gets loaded and never exceeds number of parts.
+func getRecommendedParallelism(partsCount int, currentRequests int64) int {
+	capacity := int64(runtime.NumCPU() * partUploadRequestsPerCPU)
+	parallelism := 1
+	if currentRequests < capacity {
+		parallelism = int(partUploadMaxParallelism * (capacity - currentRequests) / capacity)
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if parallelism > partsCount {
+		parallelism = partsCount
+	}
+	return parallelism
+}
+
+// PartSizeHintHandler - GET /bucket/object?part-size-hint&object-size=<bytes>
+// ----------
+// Returns recommended part size and parallelism to upload an object
+// of the given size.
+func (api objectAPIHandlers) PartSizeHintHandler(w http.ResponseWriter, r *http.Request) {
+	vars := router.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObject", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectSize, err := strconv.ParseInt(r.URL.Query().Get(partSizeObjectSize), 10, 64)
+	if err != nil || objectSize < 0 {
+		writeErrorResponse(w, ErrInvalidObjectSize, r.URL)
+		return
+	}
+
+	if _, err = objectAPI.GetBucketInfo(bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	partSize, partsCount, err := getRecommendedPartSize(objectSize)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Current request is excluded from load.
+	currentRequests := globalHTTPStats.getCurrentRequests() - 1
+	response := PartSizeHintResponse{
+		ObjectSize:  objectSize,
+		PartSize:    partSize,
+		PartsCount:  partsCount,
+		Parallelism: getRecommendedParallelism(partsCount, currentRequests),
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
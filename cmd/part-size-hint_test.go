@@ -0,0 +1,169 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Tests recommended part size for various object sizes.
+func TestGetRecommendedPartSize(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	testCases := []struct {
+		config             multipartConfig
+		objectSize         int64
+		expectedPartSize   int64
+		expectedPartsCount int
+		shouldPass         bool
+	}{
+		// Small objects fit in a single part.
+		{multipartConfig{}, 0, 0, 1, true},
+		{multipartConfig{}, humanize.MiByte, humanize.MiByte, 1, true},
+		{multipartConfig{}, blockSizeV1, blockSizeV1, 1, true},
+		// Minimum part size is rounded up to erasure block size.
+		{multipartConfig{}, blockSizeV1 + 1, blockSizeV1, 2, true},
+		{multipartConfig{}, humanize.GiByte, blockSizeV1, 103, true},
+		{multipartConfig{MinPartSize: 15 * humanize.MiByte}, 100 * humanize.MiByte, 2 * blockSizeV1, 5, true},
+		// Parts grow to fit in maximum number of parts.
+		{multipartConfig{}, 1000 * humanize.GiByte, 11 * blockSizeV1, 9310, true},
+		{multipartConfig{MaxParts: 10}, humanize.GiByte, 11 * blockSizeV1, 10, true},
+		// Maximum part size limits maximum object size.
+		{multipartConfig{MaxParts: 2}, 10 * humanize.GiByte, globalMaxPartSize, 2, true},
+		{multipartConfig{MaxParts: 2}, 10*humanize.GiByte + 1, 0, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		serverConfig.Multipart = testCase.config
+		partSize, partsCount, err := getRecommendedPartSize(testCase.objectSize)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+		if partSize != testCase.expectedPartSize || partsCount != testCase.expectedPartsCount {
+			t.Errorf("Test %d: expected %d parts of %d, got %d parts of %d", i+1,
+				testCase.expectedPartsCount, testCase.expectedPartSize, partsCount, partSize)
+		}
+	}
+}
+
+// Tests recommended parallelism under various loads.
+func TestGetRecommendedParallelism(t *testing.T) {
+	capacity := int64(runtime.NumCPU() * partUploadRequestsPerCPU)
+	testCases := []struct {
+		partsCount          int
+		currentRequests     int64
+		expectedParallelism int
+	}{
+		// Idle server.
+		{100, 0, partUploadMaxParallelism},
+		// Never more than number of parts.
+		{1, 0, 1},
+		{3, 0, 3},
+		// Half loaded server.
+		{100, capacity / 2, partUploadMaxParallelism * int(capacity-capacity/2) / int(capacity)},
+		// Fully loaded server.
+		{100, capacity, 1},
+		{100, 2 * capacity, 1},
+	}
+
+	for i, testCase := range testCases {
+		parallelism := getRecommendedParallelism(testCase.partsCount, testCase.currentRequests)
+		if parallelism != testCase.expectedParallelism {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expectedParallelism, parallelism)
+		}
+	}
+}
+
+// Wrapper for calling PartSizeHint handler tests for both XL and FS.
+func TestAPIPartSizeHintHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIPartSizeHintHandler, []string{"PartSizeHint"})
+}
+
+func testAPIPartSizeHintHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	getPartSizeHintURL := func(bucket, objectSize string) string {
+		queryValues := url.Values{}
+		queryValues.Set(partSizeHintQuery, "")
+		if objectSize != "" {
+			queryValues.Set(partSizeObjectSize, objectSize)
+		}
+		return makeTestTargetURL("", bucket, "object", queryValues)
+	}
+
+	testCases := []struct {
+		bucket             string
+		objectSize         string
+		accessKey          string
+		expectedStatus     int
+		expectedPartsCount int
+	}{
+		{bucketName, strconv.Itoa(humanize.GiByte), credentials.AccessKey, http.StatusOK, 103},
+		{bucketName, "0", credentials.AccessKey, http.StatusOK, 1},
+		// Invalid object sizes.
+		{bucketName, "", credentials.AccessKey, http.StatusBadRequest, 0},
+		{bucketName, "-1", credentials.AccessKey, http.StatusBadRequest, 0},
+		{bucketName, "1GB", credentials.AccessKey, http.StatusBadRequest, 0},
+		// Object too large.
+		{bucketName, strconv.FormatInt(globalMaxPartID*globalMaxPartSize+1, 10), credentials.AccessKey, http.StatusBadRequest, 0},
+		// Non existent bucket.
+		{"nonexistent-bucket", "1", credentials.AccessKey, http.StatusNotFound, 0},
+		// Invalid credentials.
+		{bucketName, "1", "invalid-access-key", http.StatusForbidden, 0},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getPartSizeHintURL(testCase.bucket, testCase.objectSize),
+			0, nil, testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: failed to create request: %s", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: %s: expected status %d, got %d", i+1, instanceType, testCase.expectedStatus, rec.Code)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		response := PartSizeHintResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		if response.PartsCount != testCase.expectedPartsCount || response.Parallelism < 1 ||
+			response.Parallelism > response.PartsCount {
+			t.Errorf("Test %d: %s: unexpected response %+v", i+1, instanceType, response)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Handling of peers running a different version, set through
+// MINIO_PEER_VERSION_CHECK.
+const (
+	// Peers are refused, the server waits for them to be upgraded.
+	peerVersionCheckRefuse = "refuse"
+	// Peers are accepted and a warning is logged.
+	peerVersionCheckWarn = "warn"
+)
+
+// peerVersionsCompatible - returns true if servers of given versions and
+// commit IDs can operate together, i.e. they run the same binary or
+// both are releases made within given window of each other. Commit ID
+// is not compared when empty, it is not sent by older servers.
+func peerVersionsCompatible(version, commitID, peerVersion, peerCommitID string, window time.Duration) bool {
+	if version == peerVersion && (commitID == "" || peerCommitID == "" || commitID == peerCommitID) {
+		return true
+	}
+	if window <= 0 {
+		return false
+	}
+	// Source builds don't have a release time.
+	releaseTime, err := time.Parse(time.RFC3339, version)
+	if err != nil {
+		return false
+	}
+	peerReleaseTime, err := time.Parse(time.RFC3339, peerVersion)
+	if err != nil {
+		return false
+	}
+	diff := releaseTime.Sub(peerReleaseTime)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= window
+}
+
+// checkPeerVersion - validates version and commit ID of a peer logging
+// in, returns errServerVersionMismatch if the peer is not compatible
+// with this server and MINIO_PEER_VERSION_CHECK is not "warn".
+func checkPeerVersion(peerVersion, peerCommitID string) error {
+	if peerVersionsCompatible(Version, CommitID, peerVersion, peerCommitID, globalPeerVersionWindow) {
+		return nil
+	}
+	if globalPeerVersionCheck == peerVersionCheckWarn {
+		errorIf(fmt.Errorf("peer version %s (commit %s) differs from %s (commit %s)", peerVersion, peerCommitID, Version, CommitID),
+			"Operating in a mixed version cluster, please upgrade all servers to the same version.")
+		return nil
+	}
+	return errServerVersionMismatch
+}
+
+// ServerNodeVersion - version of one server of the setup.
+type ServerNodeVersion struct {
+	Addr     string `json:"addr"`
+	Version  string `json:"version,omitempty"`
+	CommitID string `json:"commitID,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// getPeerVersions - returns versions of all servers of the setup,
+// servers which could not be reached are reported with an error.
+func getPeerVersions(peers adminPeers) []ServerNodeVersion {
+	versions := make([]ServerNodeVersion, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			versions[idx].Addr = peer.addr
+			serverInfoData, err := peer.cmdRunner.ServerInfoData()
+			if err != nil {
+				versions[idx].Error = err.Error()
+				return
+			}
+			versions[idx].Version = serverInfoData.Properties.Version
+			versions[idx].CommitID = serverInfoData.Properties.CommitID
+		}(i, peer)
+	}
+	wg.Wait()
+	return versions
+}
@@ -0,0 +1,73 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests compatibility of versions of peers.
+func TestPeerVersionsCompatible(t *testing.T) {
+	release := "2017-08-05T00:00:00Z"
+	testCases := []struct {
+		version      string
+		commitID     string
+		peerVersion  string
+		peerCommitID string
+		window       time.Duration
+		expected     bool
+	}{
+		{release, "a", release, "a", 0, true},
+		// Older peers don't send their commit ID.
+		{release, "a", release, "", 0, true},
+		{release, "a", release, "b", 0, false},
+		{release, "a", "2017-08-10T00:00:00Z", "b", 0, false},
+		{release, "a", "2017-08-10T00:00:00Z", "b", 5 * 24 * time.Hour, true},
+		{release, "a", "2017-07-31T00:00:00Z", "b", 5 * 24 * time.Hour, true},
+		{release, "a", "2017-08-10T00:00:01Z", "b", 5 * 24 * time.Hour, false},
+		// Source builds have no release time.
+		{goGetTag, "a", goGetTag, "a", 0, true},
+		{goGetTag, "a", goGetTag, "b", 0, false},
+		{release, "a", goGetTag, "b", 365 * 24 * time.Hour, false},
+		{goGetTag, "a", release, "b", 365 * 24 * time.Hour, false},
+	}
+	for i, testCase := range testCases {
+		compatible := peerVersionsCompatible(testCase.version, testCase.commitID, testCase.peerVersion, testCase.peerCommitID, testCase.window)
+		if compatible != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, compatible)
+		}
+	}
+}
+
+// Tests incompatible peers are refused or accepted as configured.
+func TestCheckPeerVersion(t *testing.T) {
+	defer func(check string) { globalPeerVersionCheck = check }(globalPeerVersionCheck)
+
+	globalPeerVersionCheck = peerVersionCheckRefuse
+	if err := checkPeerVersion(Version, CommitID); err != nil {
+		t.Fatalf("Expected same version to be accepted, got %v", err)
+	}
+	if err := checkPeerVersion("INVALID-"+Version, CommitID); err != errServerVersionMismatch {
+		t.Fatalf("Expected %v, got %v", errServerVersionMismatch, err)
+	}
+
+	globalPeerVersionCheck = peerVersionCheckWarn
+	if err := checkPeerVersion("INVALID-"+Version, CommitID); err != nil {
+		t.Fatalf("Expected different version to be accepted with a warning, got %v", err)
+	}
+}
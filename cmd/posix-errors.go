@@ -42,6 +42,14 @@ func isSysErrIO(err error) bool {
 	return err == syscall.EIO
 }
 
+// Check if the given error corresponds to EXDEV (cross-device link).
+func isSysErrCrossDevice(err error) bool {
+	if linkErr, ok := err.(*os.LinkError); ok {
+		return linkErr.Err == syscall.EXDEV
+	}
+	return false
+}
+
 // Check if the given error corresponds to EISDIR (is a directory).
 func isSysErrIsDir(err error) bool {
 	if pathErr, ok := err.(*os.PathError); ok {
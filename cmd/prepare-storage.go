@@ -227,6 +227,23 @@ func retryFormattingXLDisks(firstDisk bool, endpoints EndpointList, storageDisks
 				// actual errors for disks not being available.
 				printRetryMsg(sErrs, storageDisks)
 			}
+			// Fresh disks were added to the setup, the first server
+			// adds them to the format once all disks are online.
+			if isFormatXLExpansion(formatConfigs) {
+				if !firstDisk {
+					console.Printf("Adding disks. Waiting for first server to add them (elapsed %s)\n", getElapsedTime())
+					continue
+				}
+				if isErr(errDiskNotFound, sErrs...) {
+					console.Printf("Adding disks. Waiting for all servers to come online (elapsed %s)\n", getElapsedTime())
+					continue
+				}
+				console.Eraseline()
+				if err := expandFormatXL(storageDisks, formatConfigs, sErrs); err != nil {
+					return err
+				}
+				formatConfigs, sErrs = loadAllFormats(storageDisks)
+			}
 			// Pre-emptively check if one of the formatted disks
 			// is invalid. This function returns success for the
 			// most part unless one of the formats is not consistent
@@ -246,6 +263,11 @@ func retryFormattingXLDisks(firstDisk bool, endpoints EndpointList, storageDisks
 				}
 				return err
 			}
+			// Formatted disks carrying the same disk UUID are the same
+			// disk listed more than once, this is never recoverable.
+			if err := checkDuplicateDisks(storageDisks, formatConfigs); err != nil {
+				return err
+			}
 			// Check if this is a XL or distributed XL, anything > 1 is considered XL backend.
 			switch prepForInitXL(firstDisk, sErrs, len(storageDisks)) {
 			case Abort:
@@ -333,6 +355,10 @@ func waitForFormatXLDisks(firstDisk bool, endpoints EndpointList, storageDisks [
 		return nil, err
 	}
 
+	// Save identity of the formatted disks, disks which are offline
+	// right now are not verified when they join later.
+	formatConfigs, _ := loadAllFormats(storageDisks)
+
 	// Initialize the disk into a formatted disks wrapper.
 	formattedDisks = make([]StorageAPI, len(storageDisks))
 	for i, storage := range storageDisks {
@@ -343,6 +369,7 @@ func waitForFormatXLDisks(firstDisk bool, endpoints EndpointList, storageDisks [
 			maxRetryAttempts: globalStorageRetryThreshold,
 			retryUnit:        time.Millisecond,
 			retryCap:         time.Millisecond * 5, // 5 milliseconds.
+			identity:         newDiskIdentity(formatConfigs[i]),
 		}
 	}
 
@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Query parameters of presigned URLs constraining uploads, they are
+// signed along with the URL so they can't be removed or changed.
+const (
+	// Smallest and largest size of the request body, e.g. "1,1048576".
+	presignContentLengthRangeQueryKey = "X-Minio-Content-Length-Range"
+	// Comma separated media types allowed as Content-Type of the
+	// request, a type ending with "/" allows all its subtypes,
+	// e.g. "image/,application/pdf".
+	presignContentTypeQueryKey = "X-Minio-Content-Type"
+)
+
+// parseContentLengthRange - parses "min,max" of a content length range.
+func parseContentLengthRange(value string) (min, max int64, err error) {
+	tokens := strings.Split(value, ",")
+	if len(tokens) != 2 {
+		return 0, 0, errInvalidArgument
+	}
+	if min, err = strconv.ParseInt(strings.TrimSpace(tokens[0]), 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if max, err = strconv.ParseInt(strings.TrimSpace(tokens[1]), 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if min < 0 || max < min {
+		return 0, 0, errInvalidArgument
+	}
+	return min, max, nil
+}
+
+// isContentTypeAllowed - returns true when the media type of
+// contentType is one of the comma separated allowed types.
+func isContentTypeAllowed(contentType, allowed string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowedType := range strings.Split(allowed, ",") {
+		allowedType = strings.ToLower(strings.TrimSpace(allowedType))
+		if allowedType == "" {
+			continue
+		}
+		if strings.HasSuffix(allowedType, "/") {
+			if strings.HasPrefix(mediaType, allowedType) {
+				return true
+			}
+			continue
+		}
+		if mediaType == allowedType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPresignConditions - verifies the request satisfies upload
+// constraints signed along with its presigned URL.
+func checkPresignConditions(r *http.Request) APIErrorCode {
+	query := r.URL.Query()
+	if _, ok := query[presignContentLengthRangeQueryKey]; ok {
+		min, max, err := parseContentLengthRange(query.Get(presignContentLengthRangeQueryKey))
+		if err != nil {
+			return ErrMalformedPresignedCondition
+		}
+		if r.ContentLength < 0 {
+			return ErrMissingContentLength
+		}
+		if r.ContentLength < min {
+			return ErrEntityTooSmall
+		}
+		if r.ContentLength > max {
+			return ErrEntityTooLarge
+		}
+	}
+	if _, ok := query[presignContentTypeQueryKey]; ok {
+		if !isContentTypeAllowed(r.Header.Get("Content-Type"), query.Get(presignContentTypeQueryKey)) {
+			return ErrPresignedContentTypeNotAllowed
+		}
+	}
+	return ErrNone
+}
@@ -0,0 +1,145 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Tests parsing of content length ranges.
+func TestParseContentLengthRange(t *testing.T) {
+	testCases := []struct {
+		value      string
+		min, max   int64
+		shouldPass bool
+	}{
+		{"0,1024", 0, 1024, true},
+		{"1, 1", 1, 1, true},
+		{"", 0, 0, false},
+		{"1024", 0, 0, false},
+		{"1,2,3", 0, 0, false},
+		{"a,1024", 0, 0, false},
+		{"-1,1024", 0, 0, false},
+		{"1024,1", 0, 0, false},
+	}
+	for i, testCase := range testCases {
+		min, max, err := parseContentLengthRange(testCase.value)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: should pass but it failed with err = %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: should fail but it succeed.", i+1)
+		}
+		if min != testCase.min || max != testCase.max {
+			t.Errorf("Test %d: expected range %d,%d got %d,%d", i+1, testCase.min, testCase.max, min, max)
+		}
+	}
+}
+
+// Tests matching of content types with allowed media types.
+func TestIsContentTypeAllowed(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		allowed     string
+		expected    bool
+	}{
+		{"image/png", "image/png", true},
+		{"Image/PNG", "image/png", true},
+		{"text/plain; charset=utf-8", "text/plain", true},
+		{"image/jpeg", "image/", true},
+		{"image/jpeg", "application/pdf, image/", true},
+		{"application/pdf", "application/pdf,image/", true},
+		{"image/png", "image/jpeg", false},
+		{"imagex/png", "image/", false},
+		{"", "image/", false},
+		{"image/png", "", false},
+	}
+	for i, testCase := range testCases {
+		if allowed := isContentTypeAllowed(testCase.contentType, testCase.allowed); allowed != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, allowed)
+		}
+	}
+}
+
+// Tests presigned uploads are rejected when they violate constraints
+// signed along with their URL.
+func TestPresignedUploadConditions(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	region := serverConfig.GetRegion()
+	presign := func(query url.Values) string {
+		req, err := newTestRequest("PUT", "http://127.0.0.1:9000/bucket/object?"+query.Encode(), 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = preSignV4(req, cred.AccessKey, cred.SecretKey, 60); err != nil {
+			t.Fatal(err)
+		}
+		return req.URL.String()
+	}
+
+	constrained := presign(url.Values{
+		presignContentLengthRangeQueryKey: {"10,100"},
+		presignContentTypeQueryKey:        {"image/"},
+	})
+	malformed := presign(url.Values{presignContentLengthRangeQueryKey: {"100"}})
+
+	testCases := []struct {
+		url           string
+		contentLength int64
+		contentType   string
+		expected      APIErrorCode
+	}{
+		// Test 1 - upload within constraints.
+		{constrained, 50, "image/png", ErrNone},
+		// Test 2 - upload too small.
+		{constrained, 5, "image/png", ErrEntityTooSmall},
+		// Test 3 - upload too large.
+		{constrained, 101, "image/png", ErrEntityTooLarge},
+		// Test 4 - upload of unknown size.
+		{constrained, -1, "image/png", ErrMissingContentLength},
+		// Test 5 - content type not allowed.
+		{constrained, 50, "text/html", ErrPresignedContentTypeNotAllowed},
+		// Test 6 - missing content type.
+		{constrained, 50, "", ErrPresignedContentTypeNotAllowed},
+		// Test 7 - constraints can't be removed or changed.
+		{strings.Replace(constrained, "10%2C100", "10%2C1000", 1), 500, "image/png", ErrSignatureDoesNotMatch},
+		// Test 8 - malformed range.
+		{malformed, 50, "image/png", ErrMalformedPresignedCondition},
+		// Test 9 - unconstrained upload.
+		{presign(url.Values{}), 5000, "text/html", ErrNone},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest("PUT", testCase.url, testCase.contentLength, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		if errCode := doesPresignedSignatureMatch(unsignedPayload, req, region); errCode != testCase.expected {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expected, errCode)
+		}
+	}
+}
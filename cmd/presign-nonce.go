@@ -0,0 +1,133 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Query parameter of single-use presigned URLs, it is signed along
+// with the URL so it can't be removed. Nonces are unique per access
+// key, a URL carrying one is served only once until it expires.
+const presignNonceQueryKey = "X-Minio-Nonce"
+
+// Interval between purges of nonces of expired URLs.
+const presignNoncePurgeInterval = time.Minute
+
+// presignNonces - nonces of single-use presigned URLs already used,
+// along with the time their URL expires.
+type presignNonces struct {
+	sync.Mutex
+	nonces    map[string]time.Time
+	lastPurge time.Time
+}
+
+// newPresignNonces - returns an empty set of used nonces.
+func newPresignNonces() *presignNonces {
+	return &presignNonces{nonces: make(map[string]time.Time)}
+}
+
+// getPresignNonceKey - returns the key a nonce of accessKey is
+// recorded with.
+func getPresignNonceKey(accessKey, nonce string) string {
+	return accessKey + "/" + nonce
+}
+
+// purge - removes nonces of URLs expired before now, at most once
+// per presignNoncePurgeInterval. Must be called with the lock held.
+func (p *presignNonces) purge(now time.Time) {
+	if now.Sub(p.lastPurge) < presignNoncePurgeInterval {
+		return
+	}
+	p.lastPurge = now
+	for key, expiry := range p.nonces {
+		if expiry.Before(now) {
+			delete(p.nonces, key)
+		}
+	}
+}
+
+// use - records nonce of a URL expiring at expiry, returns false if
+// it was already used.
+func (p *presignNonces) use(key string, expiry, now time.Time) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	p.purge(now)
+	if _, ok := p.nonces[key]; ok {
+		return false
+	}
+	p.nonces[key] = expiry
+	return true
+}
+
+// record - records nonce used through another server.
+func (p *presignNonces) record(key string, expiry, now time.Time) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.purge(now)
+	p.nonces[key] = expiry
+}
+
+// PresignNoncePeerArgs - Arguments collection to PresignNoncePeer RPC
+// call.
+type PresignNoncePeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	// Access key and nonce of the used URL.
+	Key string
+
+	// Time the URL expires, the nonce is kept until then.
+	Expiry time.Time
+}
+
+// BucketUpdate - sends a used nonce to a peer.
+func (s *PresignNoncePeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.SendPresignNonce(s)
+}
+
+// usePresignNonce - verifies a presigned URL carrying a nonce was not
+// used before, on this server or any other. The nonce is sent to all
+// the other servers before the request is served.
+func usePresignNonce(query url.Values, accessKey string, expiry time.Time) APIErrorCode {
+	nonce := query.Get(presignNonceQueryKey)
+	if nonce == "" {
+		return ErrNone
+	}
+
+	key := getPresignNonceKey(accessKey, nonce)
+	if !globalPresignNonces.use(key, expiry, UTCNow()) {
+		return ErrPresignedURLAlreadyUsed
+	}
+
+	// First peer is always the local node.
+	if len(globalS3Peers) > 1 {
+		peerIndex := make([]int, 0, len(globalS3Peers)-1)
+		for idx := 1; idx < len(globalS3Peers); idx++ {
+			peerIndex = append(peerIndex, idx)
+		}
+		args := &PresignNoncePeerArgs{Key: key, Expiry: expiry}
+		for idx, err := range globalS3Peers.SendUpdate(peerIndex, args) {
+			errorIf(err, "Unable to send used presigned URL nonce to %s", globalS3Peers[idx].addr)
+		}
+	}
+	return ErrNone
+}
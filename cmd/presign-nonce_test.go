@@ -0,0 +1,125 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests recording and purging used nonces.
+func TestPresignNonces(t *testing.T) {
+	nonces := newPresignNonces()
+	now := UTCNow()
+
+	if !nonces.use("minio/a", now.Add(time.Hour), now) {
+		t.Fatal("Expected first use of a nonce to succeed")
+	}
+	if nonces.use("minio/a", now.Add(time.Hour), now) {
+		t.Fatal("Expected second use of a nonce to fail")
+	}
+	if !nonces.use("other/a", now.Add(time.Hour), now) {
+		t.Fatal("Expected nonces to be unique per access key")
+	}
+
+	// Nonces used through peers.
+	nonces.record("minio/b", now.Add(time.Minute), now)
+	if nonces.use("minio/b", now.Add(time.Minute), now) {
+		t.Fatal("Expected nonce used through a peer to fail")
+	}
+
+	// Nonces of expired URLs are purged.
+	later := now.Add(2 * presignNoncePurgeInterval)
+	if !nonces.use("minio/c", later.Add(time.Hour), later) {
+		t.Fatal("Expected first use of a nonce to succeed")
+	}
+	if _, ok := nonces.nonces["minio/b"]; ok {
+		t.Fatal("Expected nonce of an expired URL to be purged")
+	}
+	if _, ok := nonces.nonces["minio/a"]; !ok {
+		t.Fatal("Expected nonce of a valid URL to be kept")
+	}
+}
+
+// Tests presigned URLs carrying a nonce are served only once.
+func TestPresignedURLSingleUse(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() { globalPresignNonces = newPresignNonces() }()
+	globalPresignNonces = newPresignNonces()
+
+	cred := serverConfig.GetCredential()
+	region := serverConfig.GetRegion()
+	presign := func(urlStr string) string {
+		req, err := newTestRequest("GET", urlStr, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = preSignV4(req, cred.AccessKey, cred.SecretKey, 60); err != nil {
+			t.Fatal(err)
+		}
+		return req.URL.String()
+	}
+	verify := func(urlStr string) APIErrorCode {
+		req, err := newTestRequest("GET", urlStr, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return doesPresignedSignatureMatch(unsignedPayload, req, region)
+	}
+
+	singleUse := presign("http://127.0.0.1:9000/bucket/object?X-Minio-Nonce=nonce1")
+	otherNonce := presign("http://127.0.0.1:9000/bucket/object?X-Minio-Nonce=nonce2")
+	reusable := presign("http://127.0.0.1:9000/bucket/object")
+
+	testCases := []struct {
+		url      string
+		expected APIErrorCode
+	}{
+		{singleUse, ErrNone},
+		{singleUse, ErrPresignedURLAlreadyUsed},
+		// Nonce can't be removed or changed.
+		{strings.Replace(singleUse, "X-Minio-Nonce=nonce1&", "", 1), ErrSignatureDoesNotMatch},
+		{strings.Replace(singleUse, "nonce1", "nonce3", 1), ErrSignatureDoesNotMatch},
+		{otherNonce, ErrNone},
+		{otherNonce, ErrPresignedURLAlreadyUsed},
+		{reusable, ErrNone},
+		{reusable, ErrNone},
+	}
+	for i, testCase := range testCases {
+		if errCode := verify(testCase.url); errCode != testCase.expected {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expected, errCode)
+		}
+	}
+
+	// Nonces used through a peer.
+	lbms := &localBucketMetaState{ObjectAPI: newObjectLayerFn}
+	err = lbms.SendPresignNonce(&PresignNoncePeerArgs{
+		Key:    getPresignNonceKey(cred.AccessKey, "nonce4"),
+		Expiry: UTCNow().Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errCode := verify(presign("http://127.0.0.1:9000/bucket/object?X-Minio-Nonce=nonce4")); errCode != ErrPresignedURLAlreadyUsed {
+		t.Fatalf("Expected nonce used through a peer to be rejected, got %d", errCode)
+	}
+}
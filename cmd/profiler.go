@@ -0,0 +1,193 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+)
+
+// Types of profiles.
+const (
+	profileCPU       = "cpu"
+	profileMem       = "mem"
+	profileBlock     = "block"
+	profileMutex     = "mutex"
+	profileGoroutine = "goroutine"
+	profileTrace     = "trace"
+)
+
+// Sampling rate of memory allocations while a memory profile is
+// recorded, as used by `go test -memprofile`.
+const profileMemRate = 4096
+
+var (
+	errInvalidProfileType   = errors.New("Invalid profile type")
+	errProfilerNotSupported = errors.New("Profile type is not supported by the Go version the server is built with")
+	errProfilerRunning      = errors.New("A profile is already being recorded")
+	errProfilerNotRunning   = errors.New("No profile is being recorded")
+)
+
+// profiler - a profile being recorded, cpu and trace profiles are
+// written to a temporary file as they run, others are taken from the
+// runtime when stopped.
+type profiler struct {
+	profileType string
+	file        *os.File
+	memRate     int
+}
+
+// startProfiler - starts recording a profile of profileType.
+func startProfiler(profileType string) (p *profiler, err error) {
+	p = &profiler{profileType: profileType}
+	switch profileType {
+	case profileCPU, profileTrace:
+		if p.file, err = ioutil.TempFile("", "minio-profile-"); err != nil {
+			return nil, err
+		}
+		if profileType == profileCPU {
+			err = pprof.StartCPUProfile(p.file)
+		} else {
+			err = trace.Start(p.file)
+		}
+		if err != nil {
+			p.file.Close()
+			os.Remove(p.file.Name())
+			return nil, err
+		}
+	case profileMem:
+		p.memRate = runtime.MemProfileRate
+		runtime.MemProfileRate = profileMemRate
+	case profileBlock:
+		runtime.SetBlockProfileRate(1)
+	case profileMutex:
+		if err = setMutexProfileFraction(1); err != nil {
+			return nil, err
+		}
+	case profileGoroutine:
+		// Goroutines are always tracked by the runtime.
+	default:
+		return nil, errInvalidProfileType
+	}
+	return p, nil
+}
+
+// stop - stops recording and writes the profile in pprof format, or
+// execution trace format for trace profiles, to w.
+func (p *profiler) stop(w io.Writer) error {
+	switch p.profileType {
+	case profileCPU, profileTrace:
+		if p.profileType == profileCPU {
+			pprof.StopCPUProfile()
+		} else {
+			trace.Stop()
+		}
+		defer os.Remove(p.file.Name())
+		defer p.file.Close()
+		if _, err := p.file.Seek(0, 0); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, p.file)
+		return err
+	case profileMem:
+		defer func() { runtime.MemProfileRate = p.memRate }()
+		return pprof.Lookup("heap").WriteTo(w, 0)
+	case profileBlock:
+		defer runtime.SetBlockProfileRate(0)
+		return pprof.Lookup("block").WriteTo(w, 0)
+	case profileMutex:
+		defer setMutexProfileFraction(0)
+		return pprof.Lookup("mutex").WriteTo(w, 0)
+	default:
+		return pprof.Lookup("goroutine").WriteTo(w, 0)
+	}
+}
+
+var (
+	// Profile being recorded, started through _MINIO_PROFILER or
+	// admin API, at most one at a time.
+	globalProfiler   *profiler
+	globalProfilerMu sync.Mutex
+)
+
+// startGlobalProfiler - starts recording a profile of profileType,
+// unless one is already being recorded.
+func startGlobalProfiler(profileType string) error {
+	globalProfilerMu.Lock()
+	defer globalProfilerMu.Unlock()
+
+	if globalProfiler != nil {
+		return errProfilerRunning
+	}
+	p, err := startProfiler(profileType)
+	if err != nil {
+		return err
+	}
+	globalProfiler = p
+	return nil
+}
+
+// stopGlobalProfiler - stops recording the current profile and
+// writes it to w.
+func stopGlobalProfiler(w io.Writer) (profileType string, err error) {
+	globalProfilerMu.Lock()
+	defer globalProfilerMu.Unlock()
+
+	if globalProfiler == nil {
+		return "", errProfilerNotRunning
+	}
+	p := globalProfiler
+	globalProfiler = nil
+	return p.profileType, p.stop(w)
+}
+
+// saveGlobalProfiler - stops recording the current profile, if any,
+// and saves it in a temporary directory. Called on exit.
+func saveGlobalProfiler() {
+	globalProfilerMu.Lock()
+	defer globalProfilerMu.Unlock()
+
+	if globalProfiler == nil {
+		return
+	}
+	p := globalProfiler
+	globalProfiler = nil
+
+	dir, err := ioutil.TempDir("", "minio-profile-")
+	if err != nil {
+		errorIf(err, "Unable to save %s profile.", p.profileType)
+		return
+	}
+	profilePath := filepath.Join(dir, p.profileType+".pprof")
+	f, err := os.Create(profilePath)
+	if err != nil {
+		errorIf(err, "Unable to save %s profile.", p.profileType)
+		return
+	}
+	defer f.Close()
+	if err = p.stop(f); err != nil {
+		errorIf(err, "Unable to save %s profile.", p.profileType)
+		return
+	}
+	log.Println("Saved " + p.profileType + " profile at " + profilePath)
+}
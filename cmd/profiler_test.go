@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// Tests recording profiles of every type.
+func TestProfiler(t *testing.T) {
+	testCases := []struct {
+		profileType string
+		err         error
+	}{
+		{profileCPU, nil},
+		{profileMem, nil},
+		{profileBlock, nil},
+		{profileGoroutine, nil},
+		{profileTrace, nil},
+		{"", errInvalidProfileType},
+		{"heap", errInvalidProfileType},
+	}
+	for i, testCase := range testCases {
+		p, err := startProfiler(testCase.profileType)
+		if err != testCase.err {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, testCase.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err = p.stop(&buf); err != nil {
+			t.Fatalf("Test %d: Unable to stop profile: %v", i+1, err)
+		}
+		if buf.Len() == 0 {
+			t.Errorf("Test %d: Expected %s profile data", i+1, testCase.profileType)
+		}
+		if p.file != nil {
+			if _, err = os.Stat(p.file.Name()); !os.IsNotExist(err) {
+				t.Errorf("Test %d: Expected temporary file to be removed, got %v", i+1, err)
+			}
+		}
+	}
+}
+
+// Tests only one profile is recorded at a time.
+func TestGlobalProfiler(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := stopGlobalProfiler(&buf); err != errProfilerNotRunning {
+		t.Fatalf("Expected %v, got %v", errProfilerNotRunning, err)
+	}
+	if err := startGlobalProfiler(profileBlock); err != nil {
+		t.Fatal(err)
+	}
+	if err := startGlobalProfiler(profileMem); err != errProfilerRunning {
+		t.Fatalf("Expected %v, got %v", errProfilerRunning, err)
+	}
+	profileType, err := stopGlobalProfiler(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profileType != profileBlock {
+		t.Fatalf("Expected %s profile, got %s", profileBlock, profileType)
+	}
+	if globalProfiler != nil {
+		t.Fatal("Expected no profile to be recorded")
+	}
+}
@@ -0,0 +1,396 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interval at which nodes exchange quota usage with each other.
+const quotaUsageSyncInterval = 1 * time.Second
+
+// Seconds in a day, byte quotas are reset at 00:00 UTC.
+const secondsPerDay = 24 * 60 * 60
+
+// quotaLimit - limits on requests signed with an access key, a limit
+// with zero value is disabled.
+type quotaLimit struct {
+	RequestsPerSec int64 `json:"requestsPerSec"`
+	BytesPerDay    int64 `json:"bytesPerDay"`
+}
+
+// quotaConfig - quota limits per access key.
+type quotaConfig map[string]quotaLimit
+
+// Validate - validates quota config.
+func (q quotaConfig) Validate() error {
+	for accessKey, limit := range q {
+		if !isAccessKeyValid(accessKey) {
+			return fmt.Errorf("Invalid access key ‘%s’ in quota config", accessKey)
+		}
+		if limit.RequestsPerSec < 0 || limit.BytesPerDay < 0 {
+			return fmt.Errorf("Quota limits of access key ‘%s’ cannot be negative", accessKey)
+		}
+	}
+	return nil
+}
+
+// quotaUsage - requests made in the current second and bytes
+// transferred in the current day with an access key.
+type quotaUsage struct {
+	Requests int64 `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// quotaUsageSnapshot - usage of all access keys on a node, exchanged
+// between nodes to enforce quotas cluster wide.
+type quotaUsageSnapshot struct {
+	Second int64
+	Day    int64
+	Usage  map[string]quotaUsage
+}
+
+// quotaTracker - keeps usage of access keys on this node along with
+// the latest usage received from other nodes.
+type quotaTracker struct {
+	mu     sync.Mutex
+	second int64
+	day    int64
+	local  map[string]*quotaUsage
+	remote map[string]quotaUsageSnapshot // Indexed by node ID.
+}
+
+// newQuotaTracker - initialize a new quota tracker.
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{
+		local:  make(map[string]*quotaUsage),
+		remote: make(map[string]quotaUsageSnapshot),
+	}
+}
+
+// Global quota tracker.
+var globalQuotaTracker = newQuotaTracker()
+
+// Identifies this node when exchanging quota usage, server address
+// can't be used since it is usually the same on all the nodes.
+var globalQuotaNodeID = mustGetUUID()
+
+// rotate - resets counters whose window has elapsed, must be called
+// with mu held.
+func (q *quotaTracker) rotate(now time.Time) {
+	second := now.Unix()
+	day := second / secondsPerDay
+	if day != q.day {
+		q.day = day
+		for _, usage := range q.local {
+			usage.Bytes = 0
+		}
+	}
+	if second != q.second {
+		q.second = second
+		for _, usage := range q.local {
+			usage.Requests = 0
+		}
+	}
+}
+
+// usage - returns cluster wide usage of an access key, must be called
+// with mu held.
+func (q *quotaTracker) usage(accessKey string) quotaUsage {
+	var usage quotaUsage
+	if localUsage, ok := q.local[accessKey]; ok {
+		usage = *localUsage
+	}
+	for _, snapshot := range q.remote {
+		remoteUsage := snapshot.Usage[accessKey]
+		// Usage is exchanged every second, requests of the
+		// previous second on other nodes are still counted.
+		if snapshot.Second >= q.second-1 {
+			usage.Requests += remoteUsage.Requests
+		}
+		if snapshot.Day == q.day {
+			usage.Bytes += remoteUsage.Bytes
+		}
+	}
+	return usage
+}
+
+// admit - accounts a new request, returns false without accounting
+// it if any of the limits is already reached.
+func (q *quotaTracker) admit(accessKey string, limit quotaLimit, now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rotate(now)
+	usage := q.usage(accessKey)
+	if limit.RequestsPerSec > 0 && usage.Requests >= limit.RequestsPerSec {
+		return false
+	}
+	if limit.BytesPerDay > 0 && usage.Bytes >= limit.BytesPerDay {
+		return false
+	}
+
+	localUsage, ok := q.local[accessKey]
+	if !ok {
+		localUsage = &quotaUsage{}
+		q.local[accessKey] = localUsage
+	}
+	localUsage.Requests++
+	return true
+}
+
+// addBytes - accounts bytes uploaded and downloaded by a request.
+func (q *quotaTracker) addBytes(accessKey string, size int64, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rotate(now)
+	if localUsage, ok := q.local[accessKey]; ok {
+		localUsage.Bytes += size
+	}
+}
+
+// snapshot - returns usage on this node to be sent to other nodes.
+func (q *quotaTracker) snapshot(now time.Time) quotaUsageSnapshot {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rotate(now)
+	snapshot := quotaUsageSnapshot{
+		Second: q.second,
+		Day:    q.day,
+		Usage:  make(map[string]quotaUsage, len(q.local)),
+	}
+	for accessKey, usage := range q.local {
+		snapshot.Usage[accessKey] = *usage
+	}
+	return snapshot
+}
+
+// setRemoteUsage - saves usage received from another node.
+func (q *quotaTracker) setRemoteUsage(nodeID string, snapshot quotaUsageSnapshot) {
+	q.mu.Lock()
+	q.remote[nodeID] = snapshot
+	q.mu.Unlock()
+}
+
+// getUsage - returns cluster wide usage of all access keys.
+func (q *quotaTracker) getUsage(now time.Time) map[string]quotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rotate(now)
+	usage := make(map[string]quotaUsage)
+	for accessKey := range q.local {
+		usage[accessKey] = q.usage(accessKey)
+	}
+	for _, snapshot := range q.remote {
+		for accessKey := range snapshot.Usage {
+			usage[accessKey] = q.usage(accessKey)
+		}
+	}
+	return usage
+}
+
+// getRequestAccessKey - returns access key used to sign the request,
+// empty for anonymous requests.
+func getRequestAccessKey(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		if signV4Values, s3Err := parseSignV4(r.Header.Get("Authorization")); s3Err == ErrNone {
+			return signV4Values.Credential.accessKey
+		}
+	case authTypePresigned:
+		if preSignV4Values, s3Err := parsePreSignV4(r.URL.Query()); s3Err == ErrNone {
+			return preSignV4Values.Credential.accessKey
+		}
+	case authTypeSignedV2:
+		// Authorization = "AWS" + " " + AWSAccessKeyId + ":" + Signature
+		v2Auth := strings.TrimPrefix(r.Header.Get("Authorization"), signV2Algorithm+" ")
+		return strings.Split(v2Auth, ":")[0]
+	case authTypePresignedV2:
+		return r.URL.Query().Get("AWSAccessKeyId")
+	}
+	return ""
+}
+
+// getVerifiedAccessKey - returns access key of a request with a valid
+// signature, empty for anonymous requests and invalid signatures.
+// Only the signature is verified, payload checksums are left to the
+// handlers.
+func getVerifiedAccessKey(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypePresigned, authTypeStreamingSigned:
+		// Some APIs are signed for the default region as well.
+		if reqSignatureV4Verify(r, serverConfig.GetRegion()) != ErrNone &&
+			reqSignatureV4Verify(r, globalMinioDefaultRegion) != ErrNone {
+			return ""
+		}
+	case authTypeSignedV2, authTypePresignedV2:
+		if isReqAuthenticatedV2(r) != ErrNone {
+			return ""
+		}
+	default:
+		return ""
+	}
+	return getRequestAccessKey(r)
+}
+
+// quotaRequestReader counts bytes read from the client.
+type quotaRequestReader struct {
+	io.ReadCloser
+	read int64
+}
+
+// Wraps ReadCloser's Read()
+func (r *quotaRequestReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.read += int64(n)
+	return n, err
+}
+
+// quotaResponseWriter counts bytes written to the client.
+type quotaResponseWriter struct {
+	http.ResponseWriter
+	written int64
+}
+
+// Wraps ResponseWriter's Write()
+func (w *quotaResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+// Wraps ResponseWriter's ReadFrom()
+func (w *quotaResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	n, err := copyToResponseWriter(w.ResponseWriter, r)
+	w.written += n
+	return n, err
+}
+
+// Wraps ResponseWriter's Flush()
+func (w *quotaResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// quotaHandler enforces request and bandwidth quotas of access keys
+// configured in the "quota" section of server config.
+type quotaHandler struct {
+	handler http.Handler
+	tracker *quotaTracker
+}
+
+// setQuotaHandler - rejects S3 requests of access keys exceeding
+// their quota with SlowDown.
+func setQuotaHandler(h http.Handler) http.Handler {
+	return quotaHandler{handler: h, tracker: globalQuotaTracker}
+}
+
+func (h quotaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketName, _ := urlPath2BucketObjectName(r.URL)
+	// Admin, browser and inter-node requests are never limited, the
+	// admin operation header is only honored on admin API paths.
+	if (bucketName == "" && r.Header.Get(minioAdminOpHeader) != "") || isMinioReservedBucket(bucketName) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Only requests signed with the access key are accounted to it,
+	// so that nobody can use up the quota of another access key.
+	accessKey := getRequestAccessKey(r)
+	limit, ok := serverConfig.GetQuota()[accessKey]
+	if accessKey == "" || !ok || getVerifiedAccessKey(r) != accessKey {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	if !h.tracker.admit(accessKey, limit, UTCNow()) {
+		w.Header().Set("Retry-After", "1")
+		writeErrorResponse(w, ErrSlowDown, r.URL)
+		return
+	}
+
+	// Bytes actually transferred are accounted, not the declared
+	// content length.
+	qr := &quotaRequestReader{ReadCloser: r.Body}
+	if r.Body != nil {
+		r.Body = qr
+	}
+	qw := &quotaResponseWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(qw, r)
+	h.tracker.addBytes(accessKey, qr.read+qw.written, UTCNow())
+}
+
+// QuotaUsagePeerArgs - Arguments collection to QuotaUsagePeer RPC call.
+type QuotaUsagePeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	// Unique ID of the node sending its usage.
+	NodeID string
+
+	// Quota usage of the sending node.
+	Snapshot quotaUsageSnapshot
+}
+
+// BucketUpdate - sends quota usage of this node to a peer.
+func (s *QuotaUsagePeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.SendQuotaUsage(s)
+}
+
+// sendQuotaUsage - sends quota usage of this node to all the other
+// nodes every interval until doneCh is closed.
+func sendQuotaUsage(interval time.Duration, doneCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// First peer is always the local node.
+			peerIndex := make([]int, 0, len(globalS3Peers))
+			for idx := 1; idx < len(globalS3Peers); idx++ {
+				peerIndex = append(peerIndex, idx)
+			}
+			args := &QuotaUsagePeerArgs{
+				NodeID:   globalQuotaNodeID,
+				Snapshot: globalQuotaTracker.snapshot(UTCNow()),
+			}
+			for idx, err := range globalS3Peers.SendUpdate(peerIndex, args) {
+				errorIf(err, "Unable to send quota usage to %s", globalS3Peers[idx].addr)
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// startQuotaUsageSync - starts exchanging quota usage with other nodes
+// when quotas are configured in a distributed setup.
+func startQuotaUsageSync(doneCh <-chan struct{}) {
+	if len(serverConfig.GetQuota()) == 0 || len(globalS3Peers) < 2 {
+		return
+	}
+	go sendQuotaUsage(quotaUsageSyncInterval, doneCh)
+}
@@ -0,0 +1,281 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests validation of quota config.
+func TestQuotaConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config     quotaConfig
+		shouldPass bool
+	}{
+		{nil, true},
+		{quotaConfig{"minio": {RequestsPerSec: 10, BytesPerDay: 1024}}, true},
+		{quotaConfig{"minio": {}}, true},
+		{quotaConfig{"mi": {RequestsPerSec: 10}}, false},
+		{quotaConfig{"minio": {RequestsPerSec: -1}}, false},
+		{quotaConfig{"minio": {BytesPerDay: -1}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// Tests quota accounting and enforcement of the quota tracker.
+func TestQuotaTracker(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	tracker := newQuotaTracker()
+
+	// Requests per second.
+	limit := quotaLimit{RequestsPerSec: 2}
+	for i := 0; i < 2; i++ {
+		if !tracker.admit("minio", limit, now) {
+			t.Fatalf("Request %d should be admitted", i+1)
+		}
+	}
+	if tracker.admit("minio", limit, now) {
+		t.Fatal("Request exceeding requests per second should be rejected")
+	}
+	if !tracker.admit("minio", limit, now.Add(time.Second)) {
+		t.Fatal("Request in the next second should be admitted")
+	}
+
+	// Requests of other nodes in the previous second are counted.
+	tracker.setRemoteUsage("node2", quotaUsageSnapshot{
+		Second: now.Add(time.Second).Unix(),
+		Day:    now.Unix() / secondsPerDay,
+		Usage:  map[string]quotaUsage{"minio": {Requests: 2, Bytes: 100}},
+	})
+	if tracker.admit("minio", limit, now.Add(2*time.Second)) {
+		t.Fatal("Request exceeding cluster wide requests per second should be rejected")
+	}
+	if !tracker.admit("minio", limit, now.Add(3*time.Second)) {
+		t.Fatal("Stale requests of other nodes should not be counted")
+	}
+
+	// Bytes per day.
+	limit = quotaLimit{BytesPerDay: 1000}
+	if !tracker.admit("minio", limit, now.Add(4*time.Second)) {
+		t.Fatal("Upload within bytes per day should be admitted")
+	}
+	tracker.addBytes("minio", 900, now.Add(4*time.Second))
+	if tracker.admit("minio", limit, now.Add(5*time.Second)) {
+		t.Fatal("Request exceeding cluster wide bytes per day should be rejected")
+	}
+	usage := tracker.getUsage(now.Add(5 * time.Second))
+	if usage["minio"].Bytes != 1000 {
+		t.Fatalf("Expected 1000 bytes, got %d", usage["minio"].Bytes)
+	}
+	if !tracker.admit("minio", limit, now.Add(secondsPerDay*time.Second)) {
+		t.Fatal("Request on the next day should be admitted")
+	}
+
+	// Other access keys are not affected.
+	nextDay := now.Add(secondsPerDay * time.Second)
+	if !tracker.admit("other", quotaLimit{RequestsPerSec: 1}, nextDay) {
+		t.Fatal("Request of other access key should be admitted")
+	}
+
+	snapshot := tracker.snapshot(nextDay)
+	if snapshot.Usage["minio"].Requests != 1 || snapshot.Usage["other"].Requests != 1 {
+		t.Fatalf("Unexpected snapshot %#v", snapshot)
+	}
+}
+
+// Tests access key is extracted from signed requests.
+func TestGetRequestAccessKey(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	reqV4, err := newTestSignedRequestV4("GET", "http://localhost:9000/bucket", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqV2, err := newTestSignedRequestV2("GET", "http://localhost:9000/bucket", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqPresignedV2, err := newTestRequest("GET", "http://localhost:9000/bucket?AWSAccessKeyId="+cred.AccessKey+"&Signature=abc&Expires=1", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqAnonymous, err := newTestRequest("GET", "http://localhost:9000/bucket", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		r         *http.Request
+		accessKey string
+	}{
+		{reqV4, cred.AccessKey},
+		{reqV2, cred.AccessKey},
+		{reqPresignedV2, cred.AccessKey},
+		{reqAnonymous, ""},
+	}
+	for i, testCase := range testCases {
+		if accessKey := getRequestAccessKey(testCase.r); accessKey != testCase.accessKey {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.accessKey, accessKey)
+		}
+	}
+}
+
+// Tests requests exceeding quota are rejected with SlowDown.
+func TestQuotaHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	cred := serverConfig.GetCredential()
+	serverConfig.Quota = quotaConfig{cred.AccessKey: {RequestsPerSec: 1}}
+
+	handler := quotaHandler{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(ioutil.Discard, r.Body)
+			w.Write([]byte("data"))
+		}),
+		tracker: newQuotaTracker(),
+	}
+
+	body := "0123456789"
+	newRequest := func(urlStr, secretKey string) *http.Request {
+		var req *http.Request
+		if secretKey != "" {
+			req, err = newTestSignedRequestV4("PUT", urlStr, int64(len(body)), strings.NewReader(body), cred.AccessKey, secretKey)
+		} else {
+			req, err = newTestRequest("PUT", urlStr, int64(len(body)), strings.NewReader(body))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	withAdminOpHeader := func(req *http.Request) *http.Request {
+		req.Header.Set(minioAdminOpHeader, "get")
+		return req
+	}
+
+	testCases := []struct {
+		r          *http.Request
+		statusCode int
+	}{
+		// First request is admitted.
+		{newRequest("http://localhost:9000/bucket/object", cred.SecretKey), http.StatusOK},
+		// Second request in the same second is rejected.
+		{newRequest("http://localhost:9000/bucket/object", cred.SecretKey), http.StatusTooManyRequests},
+		// Even with the admin operation header.
+		{withAdminOpHeader(newRequest("http://localhost:9000/bucket/object", cred.SecretKey)), http.StatusTooManyRequests},
+		// Requests with invalid signatures are not accounted to the
+		// access key, they are rejected by the handlers.
+		{newRequest("http://localhost:9000/bucket/object", "invalid-secret-key"), http.StatusOK},
+		// Anonymous requests are not limited.
+		{newRequest("http://localhost:9000/bucket/object", ""), http.StatusOK},
+		// Browser requests are not limited.
+		{newRequest("http://localhost:9000"+minioReservedBucketPath+"/index.html", cred.SecretKey), http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		// Run all the requests in the same second.
+		if i == 0 {
+			for UTCNow().Nanosecond() > int(500*time.Millisecond) {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, testCase.r)
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("Test %d: expected %d, got %d", i+1, testCase.statusCode, rec.Code)
+		}
+	}
+
+	// Bytes read from the request and written to the response of the
+	// admitted request are accounted.
+	usage := handler.tracker.getUsage(UTCNow())
+	if expected := int64(len(body) + len("data")); usage[cred.AccessKey].Bytes != expected {
+		t.Fatalf("Expected %d bytes, got %d", expected, usage[cred.AccessKey].Bytes)
+	}
+}
+
+// Tests quota usage received from peers and the quota usage admin API.
+func TestQuotaUsageHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() { globalQuotaTracker = newQuotaTracker() }()
+
+	cred := serverConfig.GetCredential()
+	serverConfig.Quota = quotaConfig{cred.AccessKey: {BytesPerDay: 1024}}
+
+	// Usage received from a peer.
+	globalQuotaTracker = newQuotaTracker()
+	lbms := &localBucketMetaState{ObjectAPI: newObjectLayerFn}
+	err = lbms.SendQuotaUsage(&QuotaUsagePeerArgs{
+		NodeID: "node2",
+		Snapshot: quotaUsageSnapshot{
+			Second: UTCNow().Unix(),
+			Day:    UTCNow().Unix() / secondsPerDay,
+			Usage:  map[string]quotaUsage{cred.AccessKey: {Requests: 1, Bytes: 512}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := newTestSignedRequestV4("GET", "http://localhost:9000/?quota", 0, nil, cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	adminAPIHandlers{}.QuotaUsageHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var quotaUsageInfo map[string]QuotaUsageInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &quotaUsageInfo); err != nil {
+		t.Fatal(err)
+	}
+	info, ok := quotaUsageInfo[cred.AccessKey]
+	if !ok || info.Limit.BytesPerDay != 1024 || info.Usage.Bytes != 512 {
+		t.Fatalf("Unexpected quota usage %#v", quotaUsageInfo)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/atomic"
+)
+
+// Number of panics recovered while serving requests since the server
+// started, exported as minio_panics_total.
+var globalPanicCount atomic.Uint64
+
+// recoveryResponseWriter records if response headers were sent to
+// the client.
+type recoveryResponseWriter struct {
+	http.ResponseWriter
+	headerWritten bool
+}
+
+// Wraps ResponseWriter's Write()
+func (w *recoveryResponseWriter) Write(b []byte) (int, error) {
+	w.headerWritten = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Wraps ResponseWriter's WriteHeader()
+func (w *recoveryResponseWriter) WriteHeader(httpCode int) {
+	w.headerWritten = true
+	w.ResponseWriter.WriteHeader(httpCode)
+}
+
+// Wraps ResponseWriter's ReadFrom()
+func (w *recoveryResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.headerWritten = true
+	return copyToResponseWriter(w.ResponseWriter, r)
+}
+
+// Wraps ResponseWriter's Flush()
+func (w *recoveryResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// recoveryHandler keeps the server running when a handler panics.
+type recoveryHandler struct {
+	handler http.Handler
+}
+
+// setRecoveryHandler - recovers from panics of API handlers, logs
+// them with the request context and replies with InternalError.
+func setRecoveryHandler(h http.Handler) http.Handler {
+	return recoveryHandler{handler: h}
+}
+
+func (h recoveryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &recoveryResponseWriter{ResponseWriter: w}
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		globalPanicCount.Inc()
+
+		bucket, object := urlPath2BucketObjectName(r.URL)
+		reqErrorIf(r, fmt.Errorf("%v", rec),
+			"Recovered from panic in %s %s, bucket: %s, object: %s, access key: %s\n%s",
+			r.Method, r.URL.Path, bucket, object, getRequestAccessKey(r), debug.Stack())
+
+		// Nothing more can be sent once the response has started,
+		// client sees a truncated response.
+		if !rw.headerWritten {
+			writeErrorResponse(rw, ErrInternalError, r.URL)
+		}
+	}()
+	h.handler.ServeHTTP(rw, r)
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests panics of handlers are turned into InternalError responses.
+func TestRecoveryHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	testCases := []struct {
+		handler            http.HandlerFunc
+		isPanic            bool
+		expectedRespStatus int
+		expectedErrCode    string
+	}{
+		// Test case - 1.
+		// Handler which doesn't panic.
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeSuccessResponseHeadersOnly(w)
+			},
+			expectedRespStatus: http.StatusOK,
+		},
+		// Test case - 2.
+		// Handler panics before writing a response.
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var objInfo *ObjectInfo
+				w.Header().Set("ETag", objInfo.MD5Sum)
+			},
+			isPanic:            true,
+			expectedRespStatus: http.StatusInternalServerError,
+			expectedErrCode:    "InternalError",
+		},
+		// Test case - 3.
+		// Handler panics after response headers were sent,
+		// status is left untouched.
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusPartialContent)
+				panic("short read")
+			},
+			isPanic:            true,
+			expectedRespStatus: http.StatusPartialContent,
+		},
+	}
+
+	for i, testCase := range testCases {
+		handler := setRequestIDHandler(setRecoveryHandler(testCase.handler))
+		req, err := newTestRequest("GET", "http://localhost:9000/bucket/object", 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+
+		panicCount := globalPanicCount.Load()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+		expectedPanicCount := panicCount
+		if testCase.isPanic {
+			expectedPanicCount++
+		}
+		if count := globalPanicCount.Load(); count != expectedPanicCount {
+			t.Errorf("Test %d: Expected panic count %d, found %d", i+1, expectedPanicCount, count)
+		}
+		if testCase.expectedErrCode == "" {
+			continue
+		}
+
+		var errorResponse APIErrorResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if errorResponse.Code != testCase.expectedErrCode {
+			t.Errorf("Test %d: Expected error code %s, found %s", i+1, testCase.expectedErrCode, errorResponse.Code)
+		}
+		if requestID := rec.Header().Get(responseRequestIDKey); errorResponse.RequestID != requestID {
+			t.Errorf("Test %d: Expected request ID %s in error response, found %s", i+1, requestID, errorResponse.RequestID)
+		}
+	}
+}
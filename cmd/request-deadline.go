@@ -0,0 +1,55 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// defaultRequestLockDeadline - time a request waits for namespace
+// locks in a distributed setup before failing.
+const defaultRequestLockDeadline = time.Minute
+
+// requestDeadlineContextKey - context key of the deadline of a request.
+type requestDeadlineContextKey struct{}
+
+// getRequestDeadline - returns the time until which a request waits
+// for locks, zero when request didn't go through requestDeadlineHandler.
+func getRequestDeadline(r *http.Request) time.Time {
+	deadline, _ := r.Context().Value(requestDeadlineContextKey{}).(time.Time)
+	return deadline
+}
+
+// requestDeadlineHandler sets a deadline for lock acquisition of every
+// request.
+type requestDeadlineHandler struct {
+	handler http.Handler
+}
+
+// setRequestDeadlineHandler - saves the deadline of a request in the
+// request context, locks not acquired by then fail the request with
+// 503 Service Unavailable instead of blocking indefinitely.
+func setRequestDeadlineHandler(h http.Handler) http.Handler {
+	return requestDeadlineHandler{handler: h}
+}
+
+func (h requestDeadlineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	deadline := UTCNow().Add(globalRequestLockDeadline)
+	h.handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestDeadlineContextKey{}, deadline)))
+}
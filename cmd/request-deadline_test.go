@@ -0,0 +1,120 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Tests the deadline of a request is saved in the request context.
+func TestRequestDeadlineHandler(t *testing.T) {
+	var deadline time.Time
+	handler := setRequestDeadlineHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline = getRequestDeadline(r)
+	}))
+
+	req, err := newTestRequest("GET", "http://localhost:9000/bucket/object", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reqDeadline := getRequestDeadline(req); !reqDeadline.IsZero() {
+		t.Fatalf("Expected no deadline, got %s", reqDeadline)
+	}
+
+	start := UTCNow()
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if deadline.Before(start.Add(globalRequestLockDeadline)) || deadline.After(UTCNow().Add(globalRequestLockDeadline)) {
+		t.Fatalf("Unexpected deadline %s for a request started at %s", deadline, start)
+	}
+}
+
+// Tests locks taken with a deadline.
+func TestLockDeadline(t *testing.T) {
+	savedNSMutex, savedDistLocker := globalNSMutex, globalDistLocker
+	defer func() {
+		globalNSMutex, globalDistLocker = savedNSMutex, savedDistLocker
+	}()
+
+	// Distributed locks are granted by in-process lock servers.
+	var clnts []NetLocker
+	for i := 0; i < 4; i++ {
+		clnts = append(clnts, newMemNetLocker())
+	}
+	var err error
+	if globalDistLocker, err = newDistLocker(clnts, 0, lockSettings{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		isDistXL bool
+		deadline time.Time
+		readLock bool
+		err      error
+	}{
+		// Local locks don't honor deadlines.
+		{false, time.Time{}, false, nil},
+		{false, UTCNow().Add(-time.Second), false, nil},
+		{false, UTCNow().Add(-time.Second), true, nil},
+		// Distributed locks fail once the deadline has passed.
+		{true, UTCNow().Add(-time.Second), false, errLockTimedOut},
+		{true, UTCNow().Add(-time.Second), true, errLockTimedOut},
+		// Or are granted before it.
+		{true, UTCNow().Add(time.Minute), false, nil},
+		{true, UTCNow().Add(time.Minute), true, nil},
+	}
+	for i, testCase := range testCases {
+		initNSLock(testCase.isDistXL)
+		lk := globalNSMutex.NewNSLock("bucket", "object")
+		if testCase.readLock {
+			err = lk.GetRLock(testCase.deadline)
+		} else {
+			err = lk.GetLock(testCase.deadline)
+		}
+		if err != testCase.err {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.err, err)
+		}
+
+		lockState, sErr := getSystemLockState()
+		if sErr != nil {
+			t.Fatalf("Test %d: %v", i+1, sErr)
+		}
+		if testCase.err == nil {
+			if lockState.TotalAcquiredLocks != 1 || lockState.TotalTimedOutLocks != 0 {
+				t.Fatalf("Test %d: Unexpected lock state %#v", i+1, lockState)
+			}
+			if testCase.readLock {
+				lk.RUnlock()
+			} else {
+				lk.Unlock()
+			}
+			continue
+		}
+		// Timed out locks are forgotten and counted.
+		if lockState.TotalLocks != 0 || lockState.TotalBlockedLocks != 0 || lockState.TotalTimedOutLocks != 1 {
+			t.Fatalf("Test %d: Unexpected lock state %#v", i+1, lockState)
+		}
+		if len(globalNSMutex.lockMap) != 0 {
+			t.Fatalf("Test %d: Expected no locks in lock map, got %d", i+1, len(globalNSMutex.lockMap))
+		}
+		if toAPIErrorCode(err) != ErrOperationTimedOut {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, ErrOperationTimedOut, toAPIErrorCode(err))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/atomic"
+)
+
+// requestIDContextKey - context key of the ID assigned to a request.
+type requestIDContextKey struct{}
+
+// Incremented for every request, keeps IDs of requests received in
+// the same nanosecond unique.
+var globalRequestIDSeq atomic.Uint32
+
+// Sent as x-amz-id-2 with every response, lets support tell which
+// server handled a request. Generated once per process.
+var globalHostID = func() string {
+	sum := sha256.Sum256([]byte(mustGetUUID()))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}()
+
+// newRequestID - returns a new unique request ID.
+func newRequestID() string {
+	return fmt.Sprintf("%X%04X", UTCNow().UnixNano(), uint16(globalRequestIDSeq.Inc()))
+}
+
+// getRequestID - returns the ID assigned to a request, empty when
+// request didn't go through requestIDHandler.
+func getRequestID(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// requestIDHandler assigns a unique ID to every request.
+type requestIDHandler struct {
+	handler http.Handler
+}
+
+// setRequestIDHandler - sets x-amz-request-id and x-amz-id-2 response
+// headers and saves request ID in the request context for logging.
+func setRequestIDHandler(h http.Handler) http.Handler {
+	return requestIDHandler{handler: h}
+}
+
+func (h requestIDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := newRequestID()
+	w.Header().Set(responseRequestIDKey, requestID)
+	w.Header().Set(responseHostIDKey, globalHostID)
+	h.handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, requestID)))
+}
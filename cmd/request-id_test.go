@@ -0,0 +1,85 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests request IDs are unique.
+func TestNewRequestIDUnique(t *testing.T) {
+	requestIDs := make(map[string]struct{})
+	for i := 0; i < 10000; i++ {
+		requestID := newRequestID()
+		if _, ok := requestIDs[requestID]; ok {
+			t.Fatalf("Duplicate request ID %s", requestID)
+		}
+		requestIDs[requestID] = struct{}{}
+	}
+}
+
+// Tests request ID is sent in response headers and error responses.
+func TestRequestIDHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	var handlerRequestID string
+	handler := setRequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = getRequestID(r)
+		writeErrorResponse(w, ErrNoSuchKey, r.URL)
+	}))
+
+	req, err := newTestRequest("GET", "http://localhost:9000/bucket/object", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requestID := getRequestID(req); requestID != "" {
+		t.Fatalf("Expected no request ID, got %s", requestID)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	requestID := rec.Header().Get(responseRequestIDKey)
+	if requestID == "" || requestID != handlerRequestID {
+		t.Fatalf("Expected request ID %s in headers, got %s", handlerRequestID, requestID)
+	}
+	if hostID := rec.Header().Get(responseHostIDKey); hostID != globalHostID {
+		t.Fatalf("Expected host ID %s in headers, got %s", globalHostID, hostID)
+	}
+
+	var errorResponse APIErrorResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatal(err)
+	}
+	if errorResponse.RequestID != requestID || errorResponse.HostID != globalHostID {
+		t.Fatalf("Unexpected request/host ID %s/%s in error response", errorResponse.RequestID, errorResponse.HostID)
+	}
+
+	// Every request gets a new ID.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get(responseRequestIDKey) == requestID {
+		t.Fatal("Expected a new request ID")
+	}
+}
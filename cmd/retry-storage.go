@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio/pkg/disk"
@@ -41,6 +42,10 @@ type retryStorage struct {
 	maxRetryAttempts int
 	retryUnit        time.Duration
 	retryCap         time.Duration
+
+	// identity of the disk once formatted, reconnected disks are
+	// verified against it. nil if the disk need not be verified.
+	identity *diskIdentity
 }
 
 // String representation of remoteStorage.
@@ -60,6 +65,9 @@ func (f retryStorage) Close() (err error) {
 
 // DiskInfo - a retryable implementation of disk info.
 func (f retryStorage) DiskInfo() (info disk.Info, err error) {
+	if err = f.checkStale(); err != nil {
+		return info, err
+	}
 	info, err = f.remoteStorage.DiskInfo()
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -72,6 +80,9 @@ func (f retryStorage) DiskInfo() (info disk.Info, err error) {
 
 // MakeVol - a retryable implementation of creating a volume.
 func (f retryStorage) MakeVol(volume string) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.MakeVol(volume)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -84,6 +95,9 @@ func (f retryStorage) MakeVol(volume string) (err error) {
 
 // ListVols - a retryable implementation of listing all the volumes.
 func (f retryStorage) ListVols() (vols []VolInfo, err error) {
+	if err = f.checkStale(); err != nil {
+		return vols, err
+	}
 	vols, err = f.remoteStorage.ListVols()
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -96,6 +110,9 @@ func (f retryStorage) ListVols() (vols []VolInfo, err error) {
 
 // StatVol - a retryable implementation of stating a volume.
 func (f retryStorage) StatVol(volume string) (vol VolInfo, err error) {
+	if err = f.checkStale(); err != nil {
+		return vol, err
+	}
 	vol, err = f.remoteStorage.StatVol(volume)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -108,6 +125,9 @@ func (f retryStorage) StatVol(volume string) (vol VolInfo, err error) {
 
 // DeleteVol - a retryable implementation of deleting a volume.
 func (f retryStorage) DeleteVol(volume string) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.DeleteVol(volume)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -120,6 +140,9 @@ func (f retryStorage) DeleteVol(volume string) (err error) {
 
 // PrepareFile - a retryable implementation of preparing a file.
 func (f retryStorage) PrepareFile(volume, path string, length int64) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.PrepareFile(volume, path, length)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -132,6 +155,9 @@ func (f retryStorage) PrepareFile(volume, path string, length int64) (err error)
 
 // AppendFile - a retryable implementation of append to a file.
 func (f retryStorage) AppendFile(volume, path string, buffer []byte) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.AppendFile(volume, path, buffer)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -144,6 +170,9 @@ func (f retryStorage) AppendFile(volume, path string, buffer []byte) (err error)
 
 // StatFile - a retryable implementation of stating a file.
 func (f retryStorage) StatFile(volume, path string) (fileInfo FileInfo, err error) {
+	if err = f.checkStale(); err != nil {
+		return fileInfo, err
+	}
 	fileInfo, err = f.remoteStorage.StatFile(volume, path)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -156,6 +185,9 @@ func (f retryStorage) StatFile(volume, path string) (fileInfo FileInfo, err erro
 
 // ReadAll - a retryable implementation of reading all the content from a file.
 func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
+	if err = f.checkStale(); err != nil {
+		return buf, err
+	}
 	buf, err = f.remoteStorage.ReadAll(volume, path)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -168,6 +200,9 @@ func (f retryStorage) ReadAll(volume, path string) (buf []byte, err error) {
 
 // ReadFile - a retryable implementation of reading at offset from a file.
 func (f retryStorage) ReadFile(volume, path string, offset int64, buffer []byte) (m int64, err error) {
+	if err = f.checkStale(); err != nil {
+		return m, err
+	}
 	m, err = f.remoteStorage.ReadFile(volume, path, offset, buffer)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -180,6 +215,9 @@ func (f retryStorage) ReadFile(volume, path string, offset int64, buffer []byte)
 
 // ListDir - a retryable implementation of listing directory entries.
 func (f retryStorage) ListDir(volume, path string) (entries []string, err error) {
+	if err = f.checkStale(); err != nil {
+		return entries, err
+	}
 	entries, err = f.remoteStorage.ListDir(volume, path)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -192,6 +230,9 @@ func (f retryStorage) ListDir(volume, path string) (entries []string, err error)
 
 // DeleteFile - a retryable implementation of deleting a file.
 func (f retryStorage) DeleteFile(volume, path string) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.DeleteFile(volume, path)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -204,6 +245,9 @@ func (f retryStorage) DeleteFile(volume, path string) (err error) {
 
 // RenameFile - a retryable implementation of renaming a file.
 func (f retryStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
+	if err = f.checkStale(); err != nil {
+		return err
+	}
 	err = f.remoteStorage.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
 	if err == errDiskNotFound {
 		err = f.reInit()
@@ -239,7 +283,8 @@ func (f retryStorage) reInit() (err error) {
 
 		// Attempt to load format to see if the disk is really
 		// a formatted disk and part of the cluster.
-		_, err = loadFormat(f.remoteStorage)
+		var format *formatConfigV1
+		format, err = loadFormat(f.remoteStorage)
 		if err != nil {
 			// No need to return error until the retry count
 			// threshold has reached.
@@ -249,8 +294,70 @@ func (f retryStorage) reInit() (err error) {
 			return err
 		}
 
+		// Verify the reconnected disk is the same disk, a node
+		// rejoining with a different disk behind this endpoint
+		// must not be written to.
+		if err = f.identity.verify(f.String(), format); err != nil {
+			f.remoteStorage.Close()
+			return err
+		}
+
 		// Login and loading format was a success, break and proceed forward.
 		break
 	}
 	return err
 }
+
+// diskIdentity - identity of a formatted disk behind an endpoint.
+type diskIdentity struct {
+	// UUID of the disk saved in its `format.json`.
+	uuid string
+
+	// stale is set to 1 when a reconnected disk failed verification.
+	stale int32
+}
+
+// newDiskIdentity - returns identity of the disk from its format,
+// returns nil if the format is not known.
+func newDiskIdentity(format *formatConfigV1) *diskIdentity {
+	if format == nil || format.XL == nil || format.XL.Disk == "" {
+		return nil
+	}
+	return &diskIdentity{uuid: format.XL.Disk}
+}
+
+// isStale - returns true if the disk failed verification.
+func (d *diskIdentity) isStale() bool {
+	return d != nil && atomic.LoadInt32(&d.stale) == 1
+}
+
+// verify - verifies the format loaded from a reconnected disk carries
+// the same disk UUID, marks the disk stale otherwise.
+func (d *diskIdentity) verify(disk string, format *formatConfigV1) error {
+	if d == nil {
+		return nil
+	}
+	if format.XL == nil || format.XL.Disk != d.uuid {
+		var uuid string
+		if format.XL != nil {
+			uuid = format.XL.Disk
+		}
+		// Log only once when the disk turns stale.
+		if atomic.CompareAndSwapInt32(&d.stale, 0, 1) {
+			errorIf(errDiskMismatch, "Disk %s was expected to be disk %s, found disk %s instead. Disk is taken offline until it is fixed.", disk, d.uuid, uuid)
+		}
+		return errDiskNotFound
+	}
+	atomic.StoreInt32(&d.stale, 0)
+	return nil
+}
+
+// checkStale - a disk which failed verification is verified again
+// before every operation, errDiskNotFound is returned until the
+// expected disk is back behind the endpoint.
+func (f retryStorage) checkStale() error {
+	if !f.identity.isStale() {
+		return nil
+	}
+	return f.reInit()
+}
@@ -396,3 +396,76 @@ func TestRetryStorage(t *testing.T) {
 		}
 	}
 }
+
+// Tests a reconnected disk is verified against its saved identity.
+func TestRetryStorageDiskMismatch(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	originalStorageDisks, disks := prepareXLStorageDisks(t)
+	defer removeRoots(disks)
+
+	retryDisk, ok := originalStorageDisks[0].(*retryStorage)
+	if !ok {
+		t.Fatal("storage disk is not *retryStorage type")
+	}
+	format, err := loadFormat(retryDisk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Disk is expected to be some other disk.
+	identity := &diskIdentity{uuid: mustGetUUID()}
+	disk := &retryStorage{
+		remoteStorage: newNaughtyDisk(retryDisk, map[int]error{
+			1: errDiskNotFound,
+		}, nil),
+		maxRetryAttempts: 1,
+		retryUnit:        time.Millisecond,
+		retryCap:         time.Millisecond * 10,
+		identity:         identity,
+	}
+
+	// Disk reconnects with a different identity, it must stay offline.
+	for i := 0; i < 2; i++ {
+		if err = disk.MakeVol("existent"); err != errDiskNotFound {
+			t.Fatalf("Expected errDiskNotFound, got %v", err)
+		}
+		if !identity.isStale() {
+			t.Fatal("Expected disk to be stale")
+		}
+	}
+	if _, err = retryDisk.StatVol("existent"); err != errVolumeNotFound {
+		t.Fatalf("Expected errVolumeNotFound, got %v", err)
+	}
+
+	// Expected disk is back, disk is online again.
+	identity.uuid = format.XL.Disk
+	if err = disk.MakeVol("existent"); err != nil {
+		t.Fatal(err)
+	}
+	if identity.isStale() {
+		t.Fatal("Expected disk not to be stale")
+	}
+}
+
+// Tests disk identity is built only from valid formats.
+func TestNewDiskIdentity(t *testing.T) {
+	testCases := []struct {
+		format   *formatConfigV1
+		expected *diskIdentity
+	}{
+		{nil, nil},
+		{&formatConfigV1{Format: "fs", FS: &fsFormat{Version: "1"}}, nil},
+		{&formatConfigV1{Format: "xl", XL: &xlFormat{Version: "1"}}, nil},
+		{&formatConfigV1{Format: "xl", XL: &xlFormat{Version: "1", Disk: "uuid"}}, &diskIdentity{uuid: "uuid"}},
+	}
+	for i, testCase := range testCases {
+		if identity := newDiskIdentity(testCase.format); !reflect.DeepEqual(identity, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, identity)
+		}
+	}
+}
@@ -53,8 +53,9 @@ func registerDistXLRouters(mux *router.Router, endpoints EndpointList) error {
 	return registerBrowserPeerRPCRouter(mux)
 }
 
-// configureServer handler returns final handler for the http server.
-func configureServerHandler(endpoints EndpointList) (http.Handler, error) {
+// configureServer handler returns final handler for the http server,
+// S3 API and web handlers read their dependencies from ctx.
+func configureServerHandler(endpoints EndpointList, ctx serverContext) (http.Handler, error) {
 	// Initialize router. `SkipClean(true)` stops gorilla/mux from
 	// normalizing URL path minio/minio#3256
 	mux := router.NewRouter().SkipClean(true)
@@ -72,47 +73,138 @@ func configureServerHandler(endpoints EndpointList) (http.Handler, error) {
 
 	// Register web router when its enabled.
 	if globalIsBrowserEnabled {
-		if err := registerWebRouter(mux); err != nil {
+		if err := registerWebRouter(mux, ctx); err != nil {
 			return nil, err
 		}
 	}
 
-	// Add Admin router.
-	registerAdminRouter(mux)
+	// Add Admin router, unless served on a separate admin address.
+	if globalServerNetConfig.AdminAddress == "" {
+		registerAdminRouter(mux)
+	}
 
-	// Add API router.
-	registerAPIRouter(mux)
-
-	// List of some generic handlers which are applied for all incoming requests.
-	var handlerFns = []HandlerFunc{
-		// Validate all the incoming paths.
-		setPathValidityHandler,
-		// Network statistics
-		setHTTPStatsHandler,
-		// Limits all requests size to a maximum fixed limit
-		setRequestSizeLimitHandler,
-		// Adds 'crossdomain.xml' policy handler to serve legacy flash clients.
-		setCrossDomainPolicy,
-		// Redirect some pre-defined browser request paths to a static location prefix.
-		setBrowserRedirectHandler,
-		// Validates if incoming request is for restricted buckets.
-		setPrivateBucketHandler,
-		// Adds cache control for all browser requests.
-		setBrowserCacheControlHandler,
-		// Validates all incoming requests to have a valid date header.
-		setTimeValidityHandler,
-		// CORS setting for all browser API requests.
-		setCorsHandler,
-		// Validates all incoming URL resources, for invalid/unsupported
-		// resources client receives a HTTP error.
-		setIgnoreResourcesHandler,
-		// Auth handler verifies incoming authorization headers and
-		// routes them accordingly. Client receives a HTTP error for
-		// invalid/unsupported signatures.
-		setAuthHandler,
-		// Add new handlers here.
+	// Add debug router, only if lock debugging is enabled.
+	if globalIsLockDebug {
+		registerDebugRouter(mux)
+	}
+
+	// Add metrics router.
+	registerMetricsRouter(mux)
+
+	// Add health check router.
+	registerHealthCheckRouter(mux)
+
+	// Add ACME challenge router, only if certificates are obtained
+	// through ACME.
+	if globalACMEManager != nil {
+		registerACMERouter(mux, globalACMEManager)
+	}
+
+	// Add Swift TempURL router, only if a TempURL key is set.
+	if globalSwiftTempURLKey != "" {
+		registerSwiftTempURLRouter(mux, globalSwiftTempURLKey)
 	}
 
+	// Add STS router, issues temporary credentials.
+	registerSTSRouter(mux)
+
+	// Add API router.
+	registerAPIRouter(mux, ctx)
+
 	// Register rest of the handlers.
-	return registerHandlers(mux, handlerFns...), nil
+	return registerHandlers(mux, serverHandlerFns...), nil
+}
+
+// List of some generic handlers which are applied for all incoming requests.
+var serverHandlerFns = []HandlerFunc{
+	// Compresses listings and JSON/XML API responses.
+	setGzipHandler,
+	// Answers retries carrying an Idempotency-Key with the original response.
+	setIdempotencyHandler,
+	// Validate all the incoming paths.
+	setPathValidityHandler,
+	// Network statistics
+	setHTTPStatsHandler,
+	// Logs all requests to the audit log stream.
+	setAuditHandler,
+	// Tracks usage of access keys and reports anomalies.
+	setAccessKeyUsageHandler,
+	// Limits all requests size to a maximum fixed limit
+	setRequestSizeLimitHandler,
+	// Adds 'crossdomain.xml' policy handler to serve legacy flash clients.
+	setCrossDomainPolicy,
+	// Redirect some pre-defined browser request paths to a static location prefix.
+	setBrowserRedirectHandler,
+	// Validates if incoming request is for restricted buckets.
+	setPrivateBucketHandler,
+	// Adds cache control for all browser requests.
+	setBrowserCacheControlHandler,
+	// Validates all incoming requests to have a valid date header.
+	setTimeValidityHandler,
+	// CORS setting for all browser API requests.
+	setCorsHandler,
+	// Validates all incoming URL resources, for invalid/unsupported
+	// resources client receives a HTTP error.
+	setIgnoreResourcesHandler,
+	// Rejects requests of access keys exceeding their quota.
+	setQuotaHandler,
+	// Rejects all mutating requests in read-only mode.
+	setReadOnlyHandler,
+	// Serves requests of buckets owned by other servers of the
+	// federation from their owner, before they are authenticated
+	// or limited locally.
+	setFederationHandler,
+	// Auth handler verifies incoming authorization headers and
+	// routes them accordingly. Client receives a HTTP error for
+	// invalid/unsupported signatures.
+	setAuthHandler,
+	// Sets the deadline of locks taken by the request.
+	setRequestDeadlineHandler,
+	// Recovers from panics of handlers with an InternalError
+	// response, instead of crashing the server.
+	setRecoveryHandler,
+	// Reports slow requests along with their lock waits.
+	setSlowRequestHandler,
+	// Assigns a unique ID to every request, sent back in
+	// response headers and error responses.
+	setRequestIDHandler,
+	// Rewrites virtual-host style requests to path-style, must wrap
+	// all other S3 handlers for them to see path-style requests.
+	setVirtualHostHandler,
+	// Serves requests to the website domain as bucket websites,
+	// outside of all S3 handlers.
+	setWebsiteHostHandler,
+	// Add new handlers here.
+}
+
+// configureAdminHandler - returns handler of admin API alone, served
+// on a separate admin address when configured.
+func configureAdminHandler() http.Handler {
+	mux := router.NewRouter().SkipClean(true)
+	registerAdminRouter(mux)
+	return registerHandlers(mux, serverHandlerFns...)
+}
+
+// configureWebsiteHandler - returns handler of bucket websites, served
+// on the website address with anyHost set and to requests of the
+// website domain.
+func configureWebsiteHandler(anyHost bool) http.Handler {
+	mux := router.NewRouter().SkipClean(true)
+	mux.PathPrefix(slashSeparator).Handler(websiteHandler{anyHost: anyHost})
+	return registerHandlers(mux, websiteHandlerFns...)
+}
+
+// List of generic handlers applied to website requests, which are
+// anonymous and read-only.
+var websiteHandlerFns = []HandlerFunc{
+	// Network statistics
+	setHTTPStatsHandler,
+	// Logs all requests to the audit log stream.
+	setAuditHandler,
+	// Recovers from panics of handlers with an InternalError
+	// response, instead of crashing the server.
+	setRecoveryHandler,
+	// Assigns a unique ID to every request, sent back in
+	// response headers and error pages.
+	setRequestIDHandler,
 }
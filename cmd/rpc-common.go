@@ -16,11 +16,7 @@
 
 package cmd
 
-import (
-	"time"
-
-	"github.com/minio/dsync"
-)
+import "time"
 
 // Allow any RPC call request time should be no more/less than 3 seconds.
 // 3 seconds is chosen arbitrarily.
@@ -37,11 +33,16 @@ func isRequestTimeAllowed(requestTime time.Time) bool {
 type AuthRPCArgs struct {
 	// Authentication token to be verified by the server for every RPC call.
 	AuthToken string
+
+	// Hybrid logical clock of the caller when making the call.
+	Clock hlcTimestamp
 }
 
-// SetAuthToken - sets the token to the supplied value.
+// SetAuthToken - sets the token to the supplied value, along with the
+// clock of this server.
 func (args *AuthRPCArgs) SetAuthToken(authToken string) {
 	args.AuthToken = authToken
+	args.Clock = globalHLC.Now()
 }
 
 // IsAuthenticated - validated whether this auth RPC args are already authenticated or not.
@@ -51,6 +52,12 @@ func (args AuthRPCArgs) IsAuthenticated() error {
 		return errInvalidToken
 	}
 
+	// Calls of authenticated servers move the clock of this
+	// server past theirs.
+	if args.Clock.Wall != 0 {
+		globalHLC.Update(args.Clock)
+	}
+
 	// Good to go.
 	return nil
 }
@@ -63,14 +70,15 @@ type LoginRPCArgs struct {
 	Username    string
 	Password    string
 	Version     string
+	CommitID    string
 	RequestTime time.Time
 }
 
 // IsValid - validates whether this LoginRPCArgs are valid for authentication.
 func (args LoginRPCArgs) IsValid() error {
-	// Check if version matches.
-	if args.Version != Version {
-		return errServerVersionMismatch
+	// Check if version is compatible.
+	if err := checkPeerVersion(args.Version, args.CommitID); err != nil {
+		return err
 	}
 
 	if !isRequestTimeAllowed(args.RequestTime) {
@@ -89,9 +97,9 @@ type LoginRPCReply struct {
 // LockArgs represents arguments for any authenticated lock RPC call.
 type LockArgs struct {
 	AuthRPCArgs
-	LockArgs dsync.LockArgs
+	LockArgs NetLockArgs
 }
 
-func newLockArgs(args dsync.LockArgs) LockArgs {
+func newLockArgs(args NetLockArgs) LockArgs {
 	return LockArgs{LockArgs: args}
 }
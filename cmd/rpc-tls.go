@@ -0,0 +1,135 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+)
+
+// rpcConfig - inter-node RPC configuration.
+type rpcConfig struct {
+	// Require client certificates on storage and lock RPC
+	// connections between distributed nodes.
+	MutualTLS bool `json:"mutualTLS"`
+}
+
+// errRPCMutualTLSNoCerts - mutual TLS is enabled without server certificates.
+var errRPCMutualTLSNoCerts = errors.New("mutual TLS for RPC requires public.crt and private.key in certs directory")
+
+// getRPCClientCAs - returns CAs used to verify client certificates of
+// other nodes, includes all CAs in certs CA directory along with the
+// server's own certificate chain, so that clusters sharing a single
+// self-signed certificate work without additional setup.
+func getRPCClientCAs(publicCerts []*x509.Certificate, certsCAsDir string) (*x509.CertPool, error) {
+	clientCAs := x509.NewCertPool()
+	for _, cert := range publicCerts {
+		clientCAs.AddCert(cert)
+	}
+
+	fis, err := ioutil.ReadDir(certsCAsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		caCert, err := ioutil.ReadFile(filepath.Join(certsCAsDir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+		clientCAs.AppendCertsFromPEM(caCert)
+	}
+
+	return clientCAs, nil
+}
+
+// initRPCMutualTLS - loads client certificate and client CAs when
+// mutual TLS is enabled for inter-node RPC, must be called after
+// SSL config is loaded.
+func initRPCMutualTLS() error {
+	if !serverConfig.GetRPC().MutualTLS {
+		return nil
+	}
+
+	if !globalIsSSL {
+		return errRPCMutualTLSNoCerts
+	}
+
+	// Each node authenticates itself with its server certificate.
+	cert, err := tls.LoadX509KeyPair(getPublicCertFile(), getPrivateKeyFile())
+	if err != nil {
+		return err
+	}
+
+	clientCAs, err := getRPCClientCAs(globalPublicCerts, getCADir())
+	if err != nil {
+		return err
+	}
+
+	globalRPCClientCert = &cert
+	globalRPCClientCAs = clientCAs
+	globalIsRPCMutualTLS = true
+	return nil
+}
+
+// setRPCMutualTLSConfig - configures server TLS to verify client
+// certificates. S3 clients do not present certificates, hence they
+// are only verified if given and enforced per route by
+// setRPCMutualTLSHandler.
+func setRPCMutualTLSConfig(config *tls.Config) {
+	if !globalIsRPCMutualTLS {
+		return
+	}
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	config.ClientCAs = globalRPCClientCAs
+}
+
+// getRPCClientTLSConfig - returns TLS config used to dial other nodes.
+func getRPCClientTLSConfig(serverName string) *tls.Config {
+	config := &tls.Config{ServerName: serverName, RootCAs: globalRootCAs}
+	if globalRPCClientCert != nil {
+		if globalCertsManager != nil {
+			// Present certificate reloaded on updates.
+			config.GetClientCertificate = globalCertsManager.GetClientCertificate
+		} else {
+			config.Certificates = []tls.Certificate{*globalRPCClientCert}
+		}
+	}
+	return config
+}
+
+// rpcMutualTLSHandler - rejects RPC connections without a verified
+// client certificate when mutual TLS is enabled.
+type rpcMutualTLSHandler struct {
+	handler http.Handler
+}
+
+// setRPCMutualTLSHandler - wraps storage and lock RPC handlers.
+func setRPCMutualTLSHandler(h http.Handler) http.Handler {
+	return rpcMutualTLSHandler{h}
+}
+
+func (h rpcMutualTLSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if globalIsRPCMutualTLS && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
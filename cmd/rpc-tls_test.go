@@ -0,0 +1,146 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests mutual TLS can only be enabled with server certificates.
+func TestInitRPCMutualTLS(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() {
+		globalIsSSL = false
+		globalIsRPCMutualTLS = false
+		globalRPCClientCert = nil
+		globalRPCClientCAs = nil
+	}()
+
+	// Disabled by default.
+	if err = initRPCMutualTLS(); err != nil {
+		t.Fatal(err)
+	}
+	if globalIsRPCMutualTLS {
+		t.Fatal("Mutual TLS should be disabled by default")
+	}
+
+	// Enabled without certificates.
+	serverConfig.RPC.MutualTLS = true
+	if err = initRPCMutualTLS(); err != errRPCMutualTLSNoCerts {
+		t.Fatalf("Expected %s, got %v", errRPCMutualTLSNoCerts, err)
+	}
+
+	// Enabled with certificates.
+	if err = createConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	globalPublicCerts, _, globalIsSSL, err = getSSLConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = initRPCMutualTLS(); err != nil {
+		t.Fatal(err)
+	}
+	if !globalIsRPCMutualTLS || globalRPCClientCert == nil || globalRPCClientCAs == nil {
+		t.Fatal("Mutual TLS should be enabled")
+	}
+}
+
+// Tests RPC connections are only accepted with a verified client
+// certificate when mutual TLS is enabled.
+func TestRPCMutualTLSHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() {
+		globalIsSSL = false
+		globalRootCAs = nil
+		globalIsRPCMutualTLS = false
+		globalRPCClientCert = nil
+		globalRPCClientCAs = nil
+	}()
+
+	if err = createConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	globalPublicCerts, _, globalIsSSL, err = getSSLConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig.RPC.MutualTLS = true
+	if err = initRPCMutualTLS(); err != nil {
+		t.Fatal(err)
+	}
+	// Trust the self-signed test certificate.
+	globalRootCAs = globalRPCClientCAs
+
+	server := httptest.NewUnstartedServer(setRPCMutualTLSHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{*globalRPCClientCert}}
+	setRPCMutualTLSConfig(server.TLS)
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		tlsConfig  *tls.Config
+		statusCode int
+	}{
+		// Client presenting its certificate.
+		{getRPCClientTLSConfig("127.0.0.1"), http.StatusOK},
+		// Client without a certificate.
+		{&tls.Config{ServerName: "127.0.0.1", RootCAs: globalRootCAs}, http.StatusForbidden},
+	}
+
+	for i, testCase := range testCases {
+		client := http.Client{Transport: &http.Transport{TLSClientConfig: testCase.tlsConfig}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != testCase.statusCode {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.statusCode, resp.StatusCode)
+		}
+	}
+
+	// Plain connections are rejected.
+	rec := httptest.NewRecorder()
+	req, err := newTestRequest("GET", "http://127.0.0.1/", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setRPCMutualTLSHandler(http.NotFoundHandler()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
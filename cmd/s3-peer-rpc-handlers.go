@@ -116,3 +116,30 @@ func (s3 *s3PeerAPIHandlers) SetBucketPolicyPeer(args *SetBucketPolicyPeerArgs,
 
 	return s3.bms.UpdateBucketPolicy(args)
 }
+
+// save quota usage sent by another node
+func (s3 *s3PeerAPIHandlers) QuotaUsagePeer(args *QuotaUsagePeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.SendQuotaUsage(args)
+}
+
+// save nonce of a presigned URL used through another node
+func (s3 *s3PeerAPIHandlers) PresignNoncePeer(args *PresignNoncePeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.SendPresignNonce(args)
+}
+
+// forget a bucket configuration changed through another node
+func (s3 *s3PeerAPIHandlers) BucketConfigPeer(args *BucketConfigPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.UpdateBucketConfig(args)
+}
@@ -99,6 +99,22 @@ func (s *TestRPCS3PeerSuite) testS3PeerRPC(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Check bucket config change call forgets the cached config.
+	globalBucketConfigCache.get("bucket", bucketWebsiteConfig, func() (interface{}, error) {
+		return &websiteConfig{}, nil
+	})
+	BCPArgs := BucketConfigPeerArgs{Bucket: "bucket", Config: bucketWebsiteConfig}
+	err = client.Call("S3.BucketConfigPeer", &BCPArgs, &AuthRPCReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ := globalBucketConfigCache.get("bucket", bucketWebsiteConfig, func() (interface{}, error) {
+		return (*websiteConfig)(nil), nil
+	})
+	if value.(*websiteConfig) != nil {
+		t.Fatal("Expected cached config to be forgotten")
+	}
+
 	// Check event send event call works.
 	evArgs := EventArgs{Event: nil, Arn: "localhost:9000"}
 	err = client.Call("S3.Event", &evArgs, &AuthRPCReply{})
@@ -0,0 +1,57 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// serverContext - object layer, config and namespace locks used by S3
+// API and browser handlers, read through the context the handlers were
+// registered with. Servers started by `minio server` and StartServer
+// register handlers with the context of package globals, which admin,
+// RPC and background services use directly, so a process runs a single
+// server. Tests register handlers with contexts of their own.
+type serverContext struct {
+	// Object layer, nil until it is initialized.
+	ObjectAPI func() ObjectLayer
+
+	// Server configuration.
+	Config func() *serverConfigV36
+
+	// Namespace locks of buckets and objects.
+	NSMutex func() *nsLockMap
+}
+
+// newGlobalServerContext - returns the context of package globals, used
+// by handlers of `minio server`, StartServer and the gateway.
+func newGlobalServerContext() serverContext {
+	return serverContext{
+		ObjectAPI: newObjectLayerFn,
+		Config:    getServerConfig,
+		NSMutex:   getNSMutex,
+	}
+}
+
+// getServerConfig - returns the configuration loaded by this process.
+func getServerConfig() *serverConfigV36 {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return serverConfig
+}
+
+// getNSMutex - returns namespace locks of this process.
+func getNSMutex() *nsLockMap {
+	return globalNSMutex
+}
@@ -0,0 +1,181 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// newTestServerContext - returns a server context with its own FS
+// object layer and namespace locks, along with the FS directory.
+func newTestServerContext(t *testing.T) (serverContext, string) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nsMutex := newNSLockMap(false)
+	return serverContext{
+		ObjectAPI: func() ObjectLayer { return objLayer },
+		Config:    getServerConfig,
+		NSMutex:   func() *nsLockMap { return nsMutex },
+	}, fsDir
+}
+
+// newTestAPIHandler - returns S3 API handlers registered with ctx.
+func newTestAPIHandler(ctx serverContext) http.Handler {
+	mux := router.NewRouter().SkipClean(true)
+	registerAPIRouter(mux, ctx)
+	return mux
+}
+
+// Tests that handlers registered with different contexts serve
+// requests from their own object layer.
+func TestServerContextIsolation(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	var fsDirs []string
+	defer func() { removeRoots(fsDirs) }()
+	newContext := func() serverContext {
+		ctx, fsDir := newTestServerContext(t)
+		fsDirs = append(fsDirs, fsDir)
+		return ctx
+	}
+	serve := func(handler http.Handler, method string) int {
+		cred := serverConfig.GetCredential()
+		req, err := newTestSignedRequestV4(method, "http://127.0.0.1:9000/bucket", 0, nil, cred.AccessKey, cred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	handlerA := newTestAPIHandler(newContext())
+	handlerB := newTestAPIHandler(newContext())
+
+	if code := serve(handlerA, "PUT"); code != http.StatusOK {
+		t.Fatalf("Expected bucket to be created, got status %d", code)
+	}
+	if code := serve(handlerA, "HEAD"); code != http.StatusOK {
+		t.Errorf("Expected bucket to be found in its own object layer, got status %d", code)
+	}
+	if code := serve(handlerB, "HEAD"); code != http.StatusNotFound {
+		t.Errorf("Expected bucket not to be found in another object layer, got status %d", code)
+	}
+}
+
+// Tests handlers registered with two contexts side by side, anonymous
+// requests, object locks and presigned URLs of one context don't depend
+// on the other context or on package globals.
+func TestServerContextSideBySide(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	ctxA, fsDirA := newTestServerContext(t)
+	defer removeAll(fsDirA)
+	ctxB, fsDirB := newTestServerContext(t)
+	defer removeAll(fsDirB)
+	handlerA, handlerB := newTestAPIHandler(ctxA), newTestAPIHandler(ctxB)
+
+	// Bucket and object exist only in the object layer of A.
+	if err = ctxA.ObjectAPI().MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello, world")
+	if _, err = ctxA.ObjectAPI().PutObject("bucket", "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Anonymous requests are checked against buckets of their own
+	// object layer.
+	anonymousGet := func(handler http.Handler) int {
+		req, err := newTestRequest("GET", getGetObjectURL("", "bucket", "object"), 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := anonymousGet(handlerA); code != http.StatusForbidden {
+		t.Errorf("Expected anonymous access to be denied by instance A, got status %d", code)
+	}
+	if code := anonymousGet(handlerB); code != http.StatusNotFound {
+		t.Errorf("Expected bucket not to be found by instance B, got status %d", code)
+	}
+
+	// Tagging an object on A doesn't wait on locks of B or of
+	// package globals.
+	lockB := ctxB.NSMutex().NewNSLock("bucket", "object")
+	lockB.Lock()
+	defer lockB.Unlock()
+	if globalNSMutex != nil {
+		globalLock := globalNSMutex.NewNSLock("bucket", "object")
+		globalLock.Lock()
+		defer globalLock.Unlock()
+	}
+	tags := `<Tagging><TagSet><Tag><Key>project</Key><Value>minio</Value></Tag></TagSet></Tagging>`
+	cred := serverConfig.GetCredential()
+	req, err := newTestSignedRequestV4("PUT", getTaggingURL("", "bucket", "object"),
+		int64(len(tags)), bytes.NewReader([]byte(tags)), cred.AccessKey, cred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doneCh := make(chan int, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handlerA.ServeHTTP(rec, req)
+		doneCh <- rec.Code
+	}()
+	select {
+	case code := <-doneCh:
+		if code != http.StatusOK {
+			t.Errorf("Expected object to be tagged by instance A, got status %d", code)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Tagging on instance A waited on locks of another instance")
+	}
+
+	// Presigned URLs are signed with the credentials of the config of
+	// each instance.
+	configA, configB := newServerConfigV36(), newServerConfigV36()
+	configA.SetCredential(mustGetNewCredential())
+	configB.SetCredential(mustGetNewCredential())
+	urlA := presignedGet(configA, "localhost:9000", "bucket", "object", 0, "")
+	urlB := presignedGet(configB, "localhost:9000", "bucket", "object", 0, "")
+	if !strings.Contains(urlA, configA.GetCredential().AccessKey) {
+		t.Errorf("Expected URL of instance A to be signed by its access key, got %s", urlA)
+	}
+	if !strings.Contains(urlB, configB.GetCredential().AccessKey) {
+		t.Errorf("Expected URL of instance B to be signed by its access key, got %s", urlB)
+	}
+}
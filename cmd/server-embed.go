@@ -0,0 +1,256 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+)
+
+// errServerAlreadyStarted - returned by StartServer while a server
+// started earlier is still running.
+var errServerAlreadyStarted = errors.New("Server is already started in this process")
+
+// ServerConfig - configuration of a server embedded in a Go program,
+// the equivalent of `minio server` command line arguments.
+type ServerConfig struct {
+	// Address to serve requests on, "[HOST]:PORT", defaults to ":9000".
+	Address string
+	// Directories to export, a single directory for FS backend or
+	// more for erasure coded backend, as on command line.
+	Paths []string
+	// Directory holding config.json and certificates, defaults to
+	// "$HOME/.minio". Configuration is created when not found.
+	ConfigDir string
+	// Credentials of the server, those in config.json are used
+	// when not set.
+	AccessKey string
+	SecretKey string
+	// Disables web browser access.
+	NoBrowser bool
+	// Rejects all write operations.
+	ReadOnly bool
+	// Accepts write operations even if formatted disks are missing
+	// at startup.
+	ForceDegraded bool
+}
+
+// Server - a server running in the same process as the program which
+// started it with StartServer. Like `minio server`, the server keeps
+// its object layer, config, credentials and namespace locks in package
+// globals, only one server runs at a time in a process.
+type Server struct {
+	// Endpoints clients reach the server on, e.g. "http://127.0.0.1:9000".
+	Endpoints []string
+
+	mux    *ServerMux
+	doneCh chan struct{}
+}
+
+var (
+	embeddedServerMu sync.Mutex
+	embeddedServer   *Server
+)
+
+// StartServer - starts a server in this process and returns once it
+// serves requests, stop it with Shutdown. Signals are left to the
+// program, unlike `minio server` the server does not exit on SIGTERM.
+func StartServer(cfg ServerConfig) (server *Server, err error) {
+	embeddedServerMu.Lock()
+	defer embeddedServerMu.Unlock()
+
+	if embeddedServer != nil {
+		return nil, errServerAlreadyStarted
+	}
+	if len(cfg.Paths) == 0 {
+		return nil, errInvalidArgument
+	}
+
+	if cfg.ConfigDir != "" {
+		configDirAbs, err := filepath.Abs(cfg.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+		setConfigDir(configDirAbs)
+	}
+
+	netConfig := serverNetConfig{
+		Address:      cfg.Address,
+		DrainTimeout: defaultShutdownDrainTimeout,
+	}
+	if netConfig.Address == "" {
+		netConfig.Address = defaultServerAddress
+	}
+	if err = netConfig.validate(); err != nil {
+		return nil, err
+	}
+	var endpoints EndpointList
+	var setupType SetupType
+	netConfig.Address, endpoints, setupType, err = CreateEndpoints(netConfig.Address, cfg.Paths...)
+	if err != nil {
+		return nil, err
+	}
+	setGlobalEndpoints(netConfig, endpoints, setupType)
+
+	if cfg.AccessKey != "" || cfg.SecretKey != "" {
+		cred, err := createCredential(cfg.AccessKey, cfg.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		globalIsEnvCreds = true
+		globalActiveCred = cred
+	}
+	if cfg.NoBrowser {
+		globalIsEnvBrowser = true
+		globalIsBrowserEnabled = false
+	}
+	if cfg.ReadOnly {
+		globalIsReadOnly = true
+	}
+	globalIsForceDegraded = cfg.ForceDegraded
+
+	if err = createConfigDir(); err != nil {
+		return nil, err
+	}
+	if isFile(getConfigFile()) {
+		if err = migrateConfig(); err == nil {
+			err = loadConfig()
+		}
+	} else {
+		err = newConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	initError()
+
+	globalPublicCerts, globalRootCAs, globalIsSSL, err = getSSLConfig()
+	if err != nil {
+		return nil, err
+	}
+	if globalIsSSL {
+		globalServerNetConfig.CertFile, globalServerNetConfig.KeyFile = getPublicCertFile(), getPrivateKeyFile()
+	}
+	if err = initRPCMutualTLS(); err != nil {
+		return nil, err
+	}
+
+	if globalIsDistXL {
+		if err = initDsyncNodes(); err != nil {
+			return nil, err
+		}
+	}
+	initNSLock(globalIsDistXL)
+
+	server = &Server{doneCh: make(chan struct{})}
+	handler, err := configureServerHandler(globalEndpoints, newGlobalServerContext())
+	if err != nil {
+		return nil, err
+	}
+
+	initGlobalS3Peers(globalEndpoints)
+	initGlobalAdminPeers(globalEndpoints)
+	if err = initRemoteTier(); err != nil {
+		return nil, err
+	}
+	if err = initFederation(); err != nil {
+		return nil, err
+	}
+	initLDAPProvider()
+	initWorkerPools()
+
+	server.Endpoints = globalServerNetConfig.getAPIEndpoints()
+	server.mux = NewServerMux(globalMinioAddr, handler)
+	server.mux.embedded = true
+	if err = server.mux.listen(globalServerNetConfig.CertFile, globalServerNetConfig.KeyFile); err != nil {
+		return nil, err
+	}
+	go server.mux.serve()
+
+	newObject, err := newObjectLayer(globalEndpoints)
+	if err != nil {
+		server.mux.Close()
+		return nil, err
+	}
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = newObject
+	globalObjLayerMutex.Unlock()
+	globalBootTime = UTCNow()
+
+	go globalNSMutex.watchStaleLocks(globalStaleLockThreshold, server.doneCh)
+	go globalMultipartJanitor.run(staleUploadsCleanupInterval, server.doneCh)
+	if globalIsDegradedReadOnlyEnabled {
+		globalDegradedMode.check(newObject)
+		go globalDegradedMode.run(newObjectLayerFn, degradedModeCheckInterval, server.doneCh)
+	}
+	go globalMissingDisks.run(newObjectLayerFn, missingDisksCheckInterval, server.doneCh)
+	startQuotaUsageSync(server.doneCh)
+	startUsageCrawler(globalEndpoints, server.doneCh)
+	startHealthEvaluator(server.doneCh)
+	startLifecycleTransitioner(globalEndpoints, server.doneCh)
+	startAnalyticsExporter(globalEndpoints, server.doneCh)
+	go server.handleServiceSignals()
+
+	embeddedServer = server
+	return server, nil
+}
+
+// handleServiceSignals - handles service commands sent through admin
+// API, stop shuts the server down while restart is not supported.
+func (s *Server) handleServiceSignals() {
+	for {
+		select {
+		case signal := <-globalServiceSignalCh:
+			switch signal {
+			case serviceStop:
+				errorIf(s.Shutdown(), "Unable to shutdown the server.")
+				return
+			case serviceRestart:
+				errorIf(errors.New("not supported"), "Unable to restart a server embedded in another program.")
+			}
+		case <-s.doneCh:
+			return
+		}
+	}
+}
+
+// Shutdown - stops serving requests, waiting for in-flight requests
+// to finish, and shuts down the object layer.
+func (s *Server) Shutdown() error {
+	embeddedServerMu.Lock()
+	defer embeddedServerMu.Unlock()
+
+	if embeddedServer != s {
+		return errors.New("Server has been shut down")
+	}
+	embeddedServer = nil
+	close(s.doneCh)
+
+	err := s.mux.Close()
+
+	globalObjLayerMutex.Lock()
+	objAPI := globalObjectAPI
+	globalObjectAPI = nil
+	globalObjLayerMutex.Unlock()
+
+	if serr := objAPI.Shutdown(); err == nil {
+		err = serr
+	}
+	return err
+}
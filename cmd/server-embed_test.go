@@ -0,0 +1,115 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests starting and shutting down servers embedded in a program.
+func TestStartServer(t *testing.T) {
+	// Servers started here change the state of this package, restore
+	// it for other tests.
+	defer func(configDir string, srvConfig *serverConfigV36, isEnvCreds bool, cred credential, endpoints EndpointList,
+		netConfig serverNetConfig, addr, host, port string, isXL, isDistXL bool) {
+		setConfigDir(configDir)
+		serverConfig = srvConfig
+		globalIsEnvCreds, globalActiveCred = isEnvCreds, cred
+		globalEndpoints, globalServerNetConfig = endpoints, netConfig
+		globalMinioAddr, globalMinioHost, globalMinioPort = addr, host, port
+		globalIsXL, globalIsDistXL = isXL, isDistXL
+	}(getConfigDir(), serverConfig, globalIsEnvCreds, globalActiveCred, globalEndpoints,
+		globalServerNetConfig, globalMinioAddr, globalMinioHost, globalMinioPort, globalIsXL, globalIsDistXL)
+
+	root := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer os.RemoveAll(root)
+
+	address := net.JoinHostPort("127.0.0.1", getFreePort())
+	cfg := ServerConfig{
+		Address:   address,
+		Paths:     []string{filepath.Join(root, "export")},
+		ConfigDir: filepath.Join(root, "config"),
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	}
+
+	// Invalid configurations.
+	invalidConfigs := []ServerConfig{
+		{Address: address, ConfigDir: cfg.ConfigDir},
+		{Address: "127.0.0.1:0", Paths: cfg.Paths, ConfigDir: cfg.ConfigDir},
+		{Address: address, Paths: cfg.Paths, ConfigDir: cfg.ConfigDir, AccessKey: "minio", SecretKey: "short"},
+	}
+	for i, invalidConfig := range invalidConfigs {
+		if _, err := StartServer(invalidConfig); err == nil {
+			t.Fatalf("Test %d: Expected to fail", i+1)
+		}
+	}
+
+	client := http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	listBuckets := func(endpoint string) (int, error) {
+		req, err := newTestSignedRequestV4("GET", endpoint+"/", 0, nil, cfg.AccessKey, cfg.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	// Configuration is created by the first server and loaded by
+	// the second one.
+	for i := 0; i < 2; i++ {
+		server, err := StartServer(cfg)
+		if err != nil {
+			t.Fatalf("Server %d: Unable to start: %v", i+1, err)
+		}
+		if len(server.Endpoints) != 1 || server.Endpoints[0] != "http://"+address {
+			t.Fatalf("Server %d: Unexpected endpoints %v", i+1, server.Endpoints)
+		}
+		if _, err = StartServer(cfg); err != errServerAlreadyStarted {
+			t.Fatalf("Server %d: Expected %s, got %v", i+1, errServerAlreadyStarted, err)
+		}
+
+		statusCode, err := listBuckets(server.Endpoints[0])
+		if err != nil {
+			t.Fatalf("Server %d: Unable to list buckets: %v", i+1, err)
+		}
+		if statusCode != http.StatusOK {
+			t.Fatalf("Server %d: Expected status %d, got %d", i+1, http.StatusOK, statusCode)
+		}
+
+		if err = server.Shutdown(); err != nil {
+			t.Fatalf("Server %d: Unable to shutdown: %v", i+1, err)
+		}
+		if err = server.Shutdown(); err == nil {
+			t.Fatalf("Server %d: Expected second shutdown to fail", i+1)
+		}
+		if newObjectLayerFn() != nil {
+			t.Fatalf("Server %d: Expected object layer to be shut down", i+1)
+		}
+		if _, err = listBuckets(server.Endpoints[0]); err == nil {
+			t.Fatalf("Server %d: Expected requests to fail after shutdown", i+1)
+		}
+	}
+}
@@ -18,20 +18,51 @@ package cmd
 
 import (
 	"errors"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 )
 
 var serverFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "address",
-		Value: ":9000",
-		Usage: "Bind to a specific ADDRESS:PORT, ADDRESS can be an IP or hostname.",
+		Value: defaultServerAddress,
+		Usage: "Bind to a specific ADDRESS:PORT, ADDRESS can be an IP or hostname, more comma separated addresses are served as well, prefixed with http:// for plain HTTP only, overrides MINIO_ADDRESS environment variable.",
+	},
+	cli.BoolFlag{
+		Name:  "https-redirect",
+		Usage: "Redirect requests on plain HTTP only addresses to HTTPS, overrides MINIO_HTTPS_REDIRECT environment variable.",
+	},
+	cli.StringFlag{
+		Name:  "advertise-address",
+		Usage: "Report HOST:PORT to clients instead of the bound address, e.g. of a load balancer, overrides MINIO_ADVERTISE_ADDRESS environment variable.",
+	},
+	cli.StringFlag{
+		Name:  "admin-address",
+		Usage: "Serve admin API only on a separate ADDRESS:PORT, overrides MINIO_ADMIN_ADDRESS environment variable.",
+	},
+	cli.StringFlag{
+		Name:  "website-address",
+		Usage: "Serve bucket websites on a separate ADDRESS:PORT, prefixed with http:// for plain HTTP only, overrides MINIO_WEBSITE_ADDRESS environment variable.",
+	},
+	cli.BoolFlag{
+		Name:  "no-browser",
+		Usage: "Disable web browser access, overrides MINIO_BROWSER environment variable.",
+	},
+	cli.BoolFlag{
+		Name:  "read-only",
+		Usage: "Start server in read-only mode, all write operations are rejected.",
+	},
+	cli.BoolFlag{
+		Name:  "force-degraded",
+		Usage: "Accept write operations even if formatted disks are missing at startup.",
 	},
 }
 
@@ -53,10 +84,80 @@ ENVIRONMENT VARIABLES:
   ACCESS:
      MINIO_ACCESS_KEY: Custom username or access key of 5 to 20 characters in length.
      MINIO_SECRET_KEY: Custom password or secret key of 8 to 40 characters in length.
+     MINIO_ACCESS_KEY_ALERTS: To log alerts on unusual access key usage, set this value to "on".
+
+  NETWORK:
+     MINIO_ADDRESS: Bind to a specific ADDRESS:PORT, defaults to ":9000". More comma separated addresses are served as well, prefixed with "http://" for plain HTTP only.
+     MINIO_HTTPS_REDIRECT: To redirect requests on plain HTTP only addresses to HTTPS when TLS is configured, set this value to "on".
+     MINIO_ADVERTISE_ADDRESS: HOST:PORT reported to clients in startup message and event notifications, defaults to the bound address.
+     MINIO_ADMIN_ADDRESS: ADDRESS:PORT to serve admin API on, instead of the bound address.
+     MINIO_NET_BUFFER_TUNING: To tune socket buffers of client connections to their bandwidth-delay product, set this value to "on". Needs Linux.
+     MINIO_NET_BUFFER_MIN: Minimum size of tuned socket buffers, defaults to "64KiB".
+     MINIO_NET_BUFFER_MAX: Maximum size of tuned socket buffers, defaults to "4MiB".
+     MINIO_DOMAIN: Domain name to serve virtual-host style bucket requests on, e.g. "minio.example.com" for "bucket.minio.example.com".
+
+  WEBSITE:
+     MINIO_WEBSITE_ADDRESS: ADDRESS:PORT to serve bucket websites on, prefixed with "http://" for plain HTTP only. The bucket is named by the host of requests.
+     MINIO_WEBSITE_DOMAIN: Domain name to serve bucket websites on, e.g. "web.example.com" for "bucket.web.example.com".
 
   BROWSER:
      MINIO_BROWSER: To disable web browser access, set this value to "off".
 
+  CACHE:
+     MINIO_CACHE_EXCLUDE: Semicolon separated list of "bucket/object" patterns of objects never to cache, e.g. "videos/*;*.iso".
+     MINIO_CACHE_DISK: Local directory, preferably on SSD, to save objects evicted from memory cache.
+     MINIO_CACHE_DISK_SIZE: Maximum size of MINIO_CACHE_DISK, defaults to "10GiB".
+     MINIO_CACHE_MEMORY_PERCENT: Size memory cache to this percentage of free memory, e.g. "50", shrinking it under memory pressure. By default half of RAM is used on servers with at least 24GiB.
+
+  ERASURE:
+     MINIO_XL_INLINE_THRESHOLD: Objects up to this size, at most "1MiB", are stored inline in xl.json on erasure coded setups, e.g. "128KiB". Disabled by default.
+     MINIO_REBALANCE_BANDWIDTH: Maximum bytes per second read while rebalancing objects across disks added to the setup, defaults to "64MiB". Unlimited when "0".
+     MINIO_REBALANCE_OBJECTS: Maximum objects per second rebalanced across disks added to the setup. Unlimited by default.
+
+  COMPRESSION:
+     MINIO_COMPRESS_OBJECTS: To gzip object data for clients accepting it, set this value to "on".
+
+  FS:
+     MINIO_FS_INDEX: To serve listings from sorted indexes of objects and uploads on FS backend, set this value to "on".
+
+  READ-ONLY:
+     MINIO_READ_ONLY: To reject all write operations, set this value to "on".
+     MINIO_DEGRADED_READ_ONLY: To serve reads and reject writes while not enough disks are online for writes on erasure coded setups, set this value to "on".
+
+  USAGE:
+     MINIO_USAGE_CRAWL_INTERVAL: Interval between bucket usage crawls for usage alerts, defaults to "1h".
+
+  SHUTDOWN:
+     MINIO_SHUTDOWN_DRAIN_TIMEOUT: Time to wait for in-flight uploads to finish on shutdown, defaults to "1m".
+
+  LOCKS:
+     MINIO_LOCK_STALE_THRESHOLD: Duration after which held or blocked locks are reported as stale, defaults to "5m".
+     MINIO_LOCK_REQUEST_DEADLINE: Time a request waits for locks in a distributed setup before failing with 503, defaults to "1m".
+     MINIO_LOCK_FAIRNESS: To keep sustained reads from starving writers, set this value to "writer" to hold back new read locks while a write lock waits, or "fifo" to grant locks in their order of arrival. Defaults to "none".
+     MINIO_SLOW_REQUEST_THRESHOLD: Duration after which requests are logged along with the time they waited on locks, disabled by default.
+
+  DISTRIBUTED:
+     MINIO_PEER_VERSION_CHECK: To only log a warning when servers run different versions instead of refusing them, set this value to "warn".
+     MINIO_PEER_VERSION_WINDOW: Servers running releases made within this duration of each other are accepted, e.g. "720h". Defaults to identical versions.
+
+  CERTIFICATES:
+     MINIO_CERT_EXPIRY_WARN_DAYS: Number of days before expiry from which certificates are reported, defaults to "30".
+     MINIO_ACME: To obtain and renew the certificate of the configured domain from Let's Encrypt, set this value to "on". Port 80 must be served over plain HTTP.
+     MINIO_ACME_EMAIL: Contact email of the ACME account, optional.
+     MINIO_ACME_DIRECTORY: Directory URL of another ACME CA, e.g. the Let's Encrypt staging environment.
+
+  DEBUG:
+     MINIO_DEBUG: To enable deadlock detection and the /minio/debug/locks, /minio/debug/vars and /minio/debug/pprof/blocked-locks endpoints, set this value to "lock".
+
+  METRICS:
+     MINIO_PROMETHEUS_AUTH_TYPE: To serve /minio/prometheus/metrics without a JWT bearer token, set this value to "public".
+
+  SWIFT:
+     MINIO_SWIFT_TEMPURL_KEY: To allow object downloads with Swift TempURLs, set this value to the TempURL key.
+
+  KMS:
+     MINIO_KMS_MASTER_KEY: Master key each bucket key is derived from, in the form "<key-id>:<hex-encoded-256-bit-key>".
+
 EXAMPLES:
   1. Start minio server on "/home/shared" directory.
       $ {{.HelpName}} /home/shared
@@ -64,12 +165,21 @@ EXAMPLES:
   2. Start minio server bound to a specific ADDRESS:PORT.
       $ {{.HelpName}} --address 192.168.1.101:9000 /home/shared
 
-  3. Start erasure coded minio server on a 12 disks server.
+  3. Start minio server with admin API served only on localhost.
+      $ {{.HelpName}} --admin-address 127.0.0.1:9001 /home/shared
+
+  4. Start minio server serving HTTPS on the public interface and plain HTTP on an internal interface.
+      $ {{.HelpName}} --address 203.0.113.10:443,http://10.0.0.10:9000 /home/shared
+
+  5. Start minio server on "/home/shared" directory with web browser access disabled.
+      $ {{.HelpName}} --no-browser /home/shared
+
+  6. Start erasure coded minio server on a 12 disks server.
       $ {{.HelpName}} /mnt/export1/ /mnt/export2/ /mnt/export3/ /mnt/export4/ \
           /mnt/export5/ /mnt/export6/ /mnt/export7/ /mnt/export8/ /mnt/export9/ \
           /mnt/export10/ /mnt/export11/ /mnt/export12/
 
-  4. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
+  7. Start erasure coded distributed minio server on a 4 node setup with 1 drive each. Run following commands on all the 4 nodes.
       $ export MINIO_ACCESS_KEY=minio
       $ export MINIO_SECRET_KEY=miniostorage
       $ {{.HelpName}} http://192.168.1.11/mnt/export/ http://192.168.1.12/mnt/export/ \
@@ -101,6 +211,8 @@ func enableLoggers() {
 	}
 
 	log.SetConsoleTarget(consoleLogTarget)
+
+	fatalIf(log.SetStreams(serverConfig.Logger.GetStreams()), "Unable to initialize log streams")
 }
 
 func initConfig() {
@@ -133,22 +245,52 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 		setConfigDir(configDirAbs)
 	}
 
-	// Server address.
-	serverAddr := ctx.String("address")
-	fatalIf(CheckLocalServerAddr(serverAddr), "Invalid address ‘%s’ in command line argument.", serverAddr)
+	// Server addresses and listener options.
+	netConfig, err := newServerNetConfig(ctx)
+	fatalIf(err, "Invalid network configuration.")
 
+	serverAddr := netConfig.Address
+	var endpoints EndpointList
 	var setupType SetupType
-	var err error
-	globalMinioAddr, globalEndpoints, setupType, err = CreateEndpoints(serverAddr, ctx.Args()...)
+	netConfig.Address, endpoints, setupType, err = CreateEndpoints(serverAddr, ctx.Args()...)
 	fatalIf(err, "Invalid command line arguments server=‘%s’, args=%s", serverAddr, ctx.Args())
-	globalMinioHost, globalMinioPort = mustSplitHostPort(globalMinioAddr)
+	if netConfig.Address != serverAddr {
+		// Port is taken from endpoints of this server, validate again.
+		fatalIf(netConfig.validate(), "Invalid network configuration.")
+	}
+
+	setGlobalEndpoints(netConfig, endpoints, setupType)
 	if runtime.GOOS == "darwin" {
 		// On macOS, if a process already listens on LOCALIPADDR:PORT, net.Listen() falls back
 		// to IPv6 address ie minio will start listening on IPv6 address whereas another
 		// (non-)minio process is listening on IPv4 of given port.
 		// To avoid this error sutiation we check for port availability only for macOS.
 		fatalIf(checkPortAvailability(globalMinioPort), "Port %d already in use", globalMinioPort)
+		if netConfig.AdminAddress != "" {
+			_, adminPort := mustSplitHostPort(netConfig.AdminAddress)
+			fatalIf(checkPortAvailability(adminPort), "Port %s already in use", adminPort)
+		}
+		for _, address := range netConfig.ExtraAddresses {
+			addr, _ := splitExtraAddress(address)
+			_, extraPort := mustSplitHostPort(addr)
+			fatalIf(checkPortAvailability(extraPort), "Port %s already in use", extraPort)
+		}
+		if netConfig.WebsiteAddress != "" {
+			addr, _ := splitExtraAddress(netConfig.WebsiteAddress)
+			_, websitePort := mustSplitHostPort(addr)
+			fatalIf(checkPortAvailability(websitePort), "Port %s already in use", websitePort)
+		}
 	}
+}
+
+// setGlobalEndpoints - sets addresses, endpoints and setup type of
+// this server.
+func setGlobalEndpoints(netConfig serverNetConfig, endpoints EndpointList, setupType SetupType) {
+	globalServerNetConfig = netConfig
+	globalMinioAddr = netConfig.Address
+	globalMinioHost, globalMinioPort = mustSplitHostPort(globalMinioAddr)
+	globalShutdownDrainTimeout = netConfig.DrainTimeout
+	globalEndpoints = endpoints
 
 	globalIsXL = (setupType == XLSetupType)
 	globalIsDistXL = (setupType == DistXLSetupType)
@@ -160,12 +302,190 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 func serverHandleEnvVars() {
 	// Start profiler if env is set.
 	if profiler := os.Getenv("_MINIO_PROFILER"); profiler != "" {
-		globalProfiler = startProfiler(profiler)
+		fatalIf(startGlobalProfiler(profiler), "Unable to start ‘%s’ profile set in _MINIO_PROFILER environment variable.", profiler)
 	}
 
 	// Check if object cache is disabled.
 	globalXLObjCacheDisabled = strings.EqualFold(os.Getenv("_MINIO_CACHE"), "off")
 
+	// Objects matching these patterns are never cached.
+	globalObjCacheExcludes = parseObjCacheExcludes(os.Getenv("MINIO_CACHE_EXCLUDE"))
+
+	// Memory cache sizes itself to a percentage of free memory if set.
+	if memoryPercent := os.Getenv("MINIO_CACHE_MEMORY_PERCENT"); memoryPercent != "" {
+		var err error
+		globalObjCacheMemoryPercent, err = parseObjCacheMemoryPercent(memoryPercent)
+		fatalIf(err, "Unknown value ‘%s’ in MINIO_CACHE_MEMORY_PERCENT environment variable.", memoryPercent)
+	}
+
+	// Objects evicted from cache are saved in this directory if set.
+	if cacheDiskDir := os.Getenv("MINIO_CACHE_DISK"); cacheDiskDir != "" {
+		cacheDiskDirAbs, err := filepath.Abs(cacheDiskDir)
+		fatalIf(err, "Unable to fetch absolute path for cache directory %s", cacheDiskDir)
+		globalCacheDiskDir = cacheDiskDirAbs
+
+		globalCacheDiskMaxSize = globalDefaultCacheDiskSize
+		if cacheDiskSize := os.Getenv("MINIO_CACHE_DISK_SIZE"); cacheDiskSize != "" {
+			globalCacheDiskMaxSize, err = humanize.ParseBytes(cacheDiskSize)
+			if err != nil || globalCacheDiskMaxSize == 0 {
+				fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_CACHE_DISK_SIZE environment variable.", cacheDiskSize)
+			}
+		}
+	}
+
+	if inlineThreshold := os.Getenv("MINIO_XL_INLINE_THRESHOLD"); inlineThreshold != "" {
+		threshold, err := humanize.ParseBytes(inlineThreshold)
+		if err != nil || threshold > maxXLInlineThreshold {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_XL_INLINE_THRESHOLD environment variable.", inlineThreshold)
+		}
+		globalXLInlineThreshold = int64(threshold)
+	}
+
+	if bandwidth := os.Getenv("MINIO_REBALANCE_BANDWIDTH"); bandwidth != "" {
+		size, err := humanize.ParseBytes(bandwidth)
+		if err != nil {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_REBALANCE_BANDWIDTH environment variable.", bandwidth)
+		}
+		globalRebalanceBandwidth = int64(size)
+	}
+
+	if objects := os.Getenv("MINIO_REBALANCE_OBJECTS"); objects != "" {
+		count, err := strconv.ParseInt(objects, 10, 64)
+		if err != nil || count < 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_REBALANCE_OBJECTS environment variable.", objects)
+		}
+		globalRebalanceObjects = count
+	}
+
+	// Check if compression of object data is enabled.
+	globalIsObjectCompressionEnabled = strings.EqualFold(os.Getenv("MINIO_COMPRESS_OBJECTS"), "on")
+
+	// Check if FS listings should be served from sorted indexes.
+	globalIsFSIndexEnabled = strings.EqualFold(os.Getenv("MINIO_FS_INDEX"), "on")
+
+	// Check if server should be started in read-only mode.
+	globalIsReadOnly = strings.EqualFold(os.Getenv("MINIO_READ_ONLY"), "on")
+
+	// Check if writes should be rejected while write quorum is lost.
+	globalIsDegradedReadOnlyEnabled = strings.EqualFold(os.Getenv("MINIO_DEGRADED_READ_ONLY"), "on")
+
+	// Check if metrics endpoint should be served without authentication.
+	globalIsPrometheusPublic = strings.EqualFold(os.Getenv("MINIO_PROMETHEUS_AUTH_TYPE"), "public")
+
+	// Swift TempURL access is enabled only when a key is set.
+	globalSwiftTempURLKey = os.Getenv("MINIO_SWIFT_TEMPURL_KEY")
+
+	if masterKey := os.Getenv("MINIO_KMS_MASTER_KEY"); masterKey != "" {
+		var err error
+		globalKMS, err = parseKMSMasterKey(masterKey)
+		fatalIf(err, "Invalid value in MINIO_KMS_MASTER_KEY environment variable.")
+	}
+
+	// Check if anomalies in access key usage should be reported.
+	globalIsAccessKeyAlertsEnabled = strings.EqualFold(os.Getenv("MINIO_ACCESS_KEY_ALERTS"), "on")
+
+	if interval := os.Getenv("MINIO_USAGE_CRAWL_INTERVAL"); interval != "" {
+		crawlInterval, err := time.ParseDuration(interval)
+		if err != nil || crawlInterval <= 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_USAGE_CRAWL_INTERVAL environment variable.", interval)
+		}
+		globalUsageCrawlInterval = crawlInterval
+	}
+
+	// Check if lock debugging is enabled, MINIO_DEBUG is a comma
+	// separated list of subsystems to debug.
+	for _, subsystem := range strings.Split(os.Getenv("MINIO_DEBUG"), ",") {
+		if strings.EqualFold(strings.TrimSpace(subsystem), "lock") {
+			globalIsLockDebug = true
+		}
+	}
+
+	if threshold := os.Getenv("MINIO_LOCK_STALE_THRESHOLD"); threshold != "" {
+		staleThreshold, err := time.ParseDuration(threshold)
+		if err != nil || staleThreshold <= 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_LOCK_STALE_THRESHOLD environment variable.", threshold)
+		}
+		globalStaleLockThreshold = staleThreshold
+	}
+
+	if deadline := os.Getenv("MINIO_LOCK_REQUEST_DEADLINE"); deadline != "" {
+		lockDeadline, err := time.ParseDuration(deadline)
+		if err != nil || lockDeadline <= 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_LOCK_REQUEST_DEADLINE environment variable.", deadline)
+		}
+		globalRequestLockDeadline = lockDeadline
+	}
+
+	if fairness := os.Getenv("MINIO_LOCK_FAIRNESS"); fairness != "" {
+		lockFairness, err := parseLockFairness(fairness)
+		if err != nil {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_LOCK_FAIRNESS environment variable.", fairness)
+		}
+		globalLockFairness = lockFairness
+	}
+
+	if threshold := os.Getenv("MINIO_SLOW_REQUEST_THRESHOLD"); threshold != "" {
+		slowThreshold, err := time.ParseDuration(threshold)
+		if err != nil || slowThreshold <= 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_SLOW_REQUEST_THRESHOLD environment variable.", threshold)
+		}
+		globalSlowRequestThreshold = slowThreshold
+	}
+
+	if check := os.Getenv("MINIO_PEER_VERSION_CHECK"); check != "" {
+		if check != peerVersionCheckRefuse && check != peerVersionCheckWarn {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_PEER_VERSION_CHECK environment variable.", check)
+		}
+		globalPeerVersionCheck = check
+	}
+
+	if window := os.Getenv("MINIO_PEER_VERSION_WINDOW"); window != "" {
+		versionWindow, err := time.ParseDuration(window)
+		if err != nil || versionWindow < 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_PEER_VERSION_WINDOW environment variable.", window)
+		}
+		globalPeerVersionWindow = versionWindow
+	}
+
+	globalIsNetBufferTuningEnabled = strings.EqualFold(os.Getenv("MINIO_NET_BUFFER_TUNING"), "on")
+
+	if bufferMin := os.Getenv("MINIO_NET_BUFFER_MIN"); bufferMin != "" {
+		size, err := humanize.ParseBytes(bufferMin)
+		if err != nil || size == 0 || size > maxNetBufferSize {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_NET_BUFFER_MIN environment variable.", bufferMin)
+		}
+		globalNetBufferMin = int(size)
+	}
+
+	if bufferMax := os.Getenv("MINIO_NET_BUFFER_MAX"); bufferMax != "" {
+		size, err := humanize.ParseBytes(bufferMax)
+		if err != nil || size == 0 || size > maxNetBufferSize {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_NET_BUFFER_MAX environment variable.", bufferMax)
+		}
+		globalNetBufferMax = int(size)
+	}
+
+	if globalNetBufferMin > globalNetBufferMax {
+		fatalIf(errors.New("invalid value"), "MINIO_NET_BUFFER_MIN ‘%d’ is greater than MINIO_NET_BUFFER_MAX ‘%d’.", globalNetBufferMin, globalNetBufferMax)
+	}
+
+	if warnDays := os.Getenv("MINIO_CERT_EXPIRY_WARN_DAYS"); warnDays != "" {
+		days, err := strconv.Atoi(warnDays)
+		if err != nil || days <= 0 {
+			fatalIf(errors.New("invalid value"), "Unknown value ‘%s’ in MINIO_CERT_EXPIRY_WARN_DAYS environment variable.", warnDays)
+		}
+		globalCertExpiryWarn = time.Duration(days) * 24 * time.Hour
+	}
+
+	globalIsACMEEnabled = strings.EqualFold(os.Getenv("MINIO_ACME"), "on")
+	globalACMEEmail = os.Getenv("MINIO_ACME_EMAIL")
+	if directoryURL := os.Getenv("MINIO_ACME_DIRECTORY"); directoryURL != "" {
+		if u, err := url.Parse(directoryURL); err != nil || u.Scheme != httpsScheme || u.Host == "" {
+			fatalIf(errInvalidArgument, "Unknown value ‘%s’ in MINIO_ACME_DIRECTORY environment variable.", directoryURL)
+		}
+		globalACMEDirectoryURL = directoryURL
+	}
+
 	accessKey := os.Getenv("MINIO_ACCESS_KEY")
 	secretKey := os.Getenv("MINIO_SECRET_KEY")
 	if accessKey != "" && secretKey != "" {
@@ -195,6 +515,25 @@ func serverHandleEnvVars() {
 		globalServerRegion = serverRegion
 	}
 
+	if domain := os.Getenv("MINIO_DOMAIN"); domain != "" {
+		if !isValidDomain(domain) {
+			fatalIf(errInvalidArgument, "Invalid domain name ‘%s’ in MINIO_DOMAIN environment variable.", domain)
+		}
+		// domain Envs are set globally.
+		globalIsEnvDomain = true
+		globalDomainName = domain
+	}
+
+	if domain := os.Getenv("MINIO_WEBSITE_DOMAIN"); domain != "" {
+		if !isValidDomain(domain) {
+			fatalIf(errInvalidArgument, "Invalid domain name ‘%s’ in MINIO_WEBSITE_DOMAIN environment variable.", domain)
+		}
+		if strings.EqualFold(domain, globalDomainName) {
+			fatalIf(errInvalidArgument, "Domain name ‘%s’ in MINIO_WEBSITE_DOMAIN environment variable is already served by MINIO_DOMAIN.", domain)
+		}
+		globalWebsiteDomain = domain
+	}
+
 }
 
 // serverMain handler called for 'minio server' command.
@@ -212,6 +551,20 @@ func serverMain(ctx *cli.Context) {
 	serverHandleCmdArgs(ctx)
 	serverHandleEnvVars()
 
+	// Command line flag takes precedence over MINIO_BROWSER.
+	if ctx.Bool("no-browser") {
+		globalIsEnvBrowser = true
+		globalIsBrowserEnabled = false
+	}
+
+	// Read-only mode can be enabled either on command line or environment.
+	if ctx.Bool("read-only") {
+		globalIsReadOnly = true
+	}
+
+	// Writes with disks missing at startup are only accepted when forced.
+	globalIsForceDegraded = ctx.Bool("force-degraded")
+
 	// Create certs path.
 	fatalIf(createConfigDir(), "Unable to create configuration directories.")
 
@@ -228,6 +581,24 @@ func serverMain(ctx *cli.Context) {
 	globalPublicCerts, globalRootCAs, globalIsSSL, err = getSSLConfig()
 	fatalIf(err, "Invalid SSL key file")
 
+	// Reload certificates when updated and report nearing expiry.
+	if globalIsSSL {
+		globalServerNetConfig.CertFile, globalServerNetConfig.KeyFile = getPublicCertFile(), getPrivateKeyFile()
+		globalCertsManager, err = newCertsManager(getPublicCertFile(), getPrivateKeyFile())
+		fatalIf(err, "Unable to load certificates")
+		go globalCertsManager.watch(certsReloadInterval, nil)
+	}
+
+	// Obtain and renew the certificate from an ACME CA, TLS is served
+	// as soon as the first certificate is obtained.
+	if globalIsACMEEnabled {
+		fatalIf(initACME(), "Unable to initialize ACME")
+		go globalACMEManager.run(globalCertsManager, nil)
+	}
+
+	// Load client certificates for inter-node mutual TLS.
+	fatalIf(initRPCMutualTLS(), "Unable to initialize mutual TLS for RPC")
+
 	if !quietFlag {
 		// Check for new updates from dl.minio.io.
 		mode := globalMinioModeFS
@@ -250,26 +621,59 @@ func serverMain(ctx *cli.Context) {
 	// Initialize name space lock.
 	initNSLock(globalIsDistXL)
 
+	// Report locks held or blocked for too long.
+	go globalNSMutex.watchStaleLocks(globalStaleLockThreshold, nil)
+	if globalIsLockDebug {
+		go globalNSMutex.watchDeadlocks(defaultDeadlockCheckInterval, nil)
+	}
+
 	// Configure server.
-	handler, err := configureServerHandler(globalEndpoints)
+	handler, err := configureServerHandler(globalEndpoints, newGlobalServerContext())
 	fatalIf(err, "Unable to configure one of server's RPC services.")
 
 	// Initialize a new HTTP server.
 	apiServer := NewServerMux(globalMinioAddr, handler)
 
+	// Serve admin API on its own address, if configured.
+	if globalServerNetConfig.AdminAddress != "" {
+		apiServer.setAdminHandler(globalServerNetConfig.AdminAddress, configureAdminHandler())
+	}
+
+	// Serve on additional addresses as well, if configured.
+	apiServer.setExtraAddrs(globalServerNetConfig.ExtraAddresses, globalServerNetConfig.HTTPSRedirect)
+
+	// Serve bucket websites on their own address, if configured.
+	if globalServerNetConfig.WebsiteAddress != "" {
+		apiServer.setWebsiteHandler(globalServerNetConfig.WebsiteAddress, configureWebsiteHandler(true))
+	}
+
 	// Initialize S3 Peers inter-node communication only in distributed setup.
 	initGlobalS3Peers(globalEndpoints)
 
 	// Initialize Admin Peers inter-node communication only in distributed setup.
 	initGlobalAdminPeers(globalEndpoints)
 
+	// Exchange quota usage with other nodes only in distributed setup.
+	startQuotaUsageSync(nil)
+
+	// Initialize remote tier of lifecycle transitions, if enabled.
+	fatalIf(initRemoteTier(), "Unable to initialize remote tier")
+
+	// Initialize owners of buckets served by other servers, if any.
+	fatalIf(initFederation(), "Unable to initialize federation")
+
+	// Initialize LDAP identity provider, if enabled.
+	initLDAPProvider()
+
+	// Resize worker pools of background subsystems as configured.
+	initWorkerPools()
+
+	// Abort stale multipart uploads periodically.
+	go globalMultipartJanitor.run(staleUploadsCleanupInterval, nil)
+
 	// Start server, automatically configures TLS if certs are available.
 	go func() {
-		cert, key := "", ""
-		if globalIsSSL {
-			cert, key = getPublicCertFile(), getPrivateKeyFile()
-		}
-		fatalIf(apiServer.ListenAndServe(cert, key), "Failed to start minio server.")
+		fatalIf(apiServer.ListenAndServe(globalServerNetConfig.CertFile, globalServerNetConfig.KeyFile), "Failed to start minio server.")
 	}()
 
 	newObject, err := newObjectLayer(globalEndpoints)
@@ -279,13 +683,37 @@ func serverMain(ctx *cli.Context) {
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
 
+	// Reject writes while write quorum is lost, if enabled.
+	if globalIsDegradedReadOnlyEnabled {
+		globalDegradedMode.check(newObject)
+		go globalDegradedMode.run(newObjectLayerFn, degradedModeCheckInterval, globalServiceDoneCh)
+	}
+
+	// Accept writes once disks missing at startup are online.
+	go globalMissingDisks.run(newObjectLayerFn, missingDisksCheckInterval, globalServiceDoneCh)
+
 	// Prints the formatted startup message once object layer is initialized.
-	apiEndpoints := getAPIEndpoints(apiServer.Addr)
+	apiEndpoints := globalServerNetConfig.getAPIEndpoints()
 	printStartupMessage(apiEndpoints)
 
 	// Set uptime time after object layer has initialized.
 	globalBootTime = UTCNow()
 
+	// Start crawling bucket usage for configured usage alerts.
+	startUsageCrawler(globalEndpoints, nil)
+
+	// Start evaluating cluster state for metrics.
+	startHealthEvaluator(nil)
+
+	// Start transitioning objects to the remote tier.
+	startLifecycleTransitioner(globalEndpoints, nil)
+
+	// Start exporting bucket analytics reports.
+	startAnalyticsExporter(globalEndpoints, nil)
+
+	// Start rebalancing objects across disks added to the setup.
+	startRebalance(globalEndpoints, nil)
+
 	// Waits on the server.
 	<-globalServiceDoneCh
 }
@@ -315,6 +743,9 @@ func newObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err error) {
 		return nil, err
 	}
 
+	// Reject writes if formatted disks are missing, unless forced.
+	checkMissingDisksAtStart(endpoints, storageDisks)
+
 	// Cleanup objects that weren't successfully written into the namespace.
 	if err = houseKeeping(storageDisks); err != nil {
 		return nil, err
@@ -32,6 +32,10 @@ import (
 
 const (
 	serverShutdownPoll = 500 * time.Millisecond
+
+	// Default time to wait for in-flight uploads to finish during
+	// shutdown.
+	defaultShutdownDrainTimeout = 1 * time.Minute
 )
 
 // The value chosen below is longest word chosen
@@ -65,6 +69,8 @@ type ConnMux struct {
 	net.Conn
 	// To peek net.Conn incoming data
 	peeker *bufio.Reader
+	// Tuners of socket buffers, nil unless tuning is enabled.
+	tuners *connBufferTuners
 }
 
 // NewConnMux - creates a new ConnMux instance
@@ -126,6 +132,9 @@ func (c *ConnMux) Read(b []byte) (n int, err error) {
 	}()
 
 	n, err = c.peeker.Read(b)
+	if c.tuners != nil {
+		c.tuners.read.observe(n, UTCNow())
+	}
 	if err != nil {
 		return n, err
 	}
@@ -148,7 +157,31 @@ func (c *ConnMux) Write(b []byte) (n int, err error) {
 	}()
 
 	// Call the conn write wrapper.
-	return c.Conn.Write(b)
+	n, err = c.Conn.Write(b)
+	if c.tuners != nil {
+		c.tuners.write.observe(n, UTCNow())
+	}
+	return n, err
+}
+
+// ReadFrom sends data read from r to the client, net/http uses it
+// to send regular files with zero-copy sendfile() on platforms
+// supporting it. Also keeps track of the total bytes written by
+// the server.
+func (c *ConnMux) ReadFrom(r io.Reader) (n int64, err error) {
+	rf, ok := c.Conn.(io.ReaderFrom)
+	if !ok {
+		// Hide ReadFrom from io.Copy, Write() keeps track
+		// of the bytes written.
+		return io.Copy(struct{ io.Writer }{c}, r)
+	}
+
+	n, err = rf.ReadFrom(r)
+	globalConnStats.incOutputBytes(int(n))
+	if c.tuners != nil {
+		c.tuners.write.observe(int(n), UTCNow())
+	}
+	return n, err
 }
 
 // Close closes the underlying tcp connection.
@@ -248,6 +281,12 @@ func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
 			// Allocate new conn muxer.
 			connMux := NewConnMux(conn)
 
+			// Tune socket buffers to the bandwidth-delay product
+			// of the connection when enabled.
+			if globalIsNetBufferTuningEnabled {
+				connMux.tuners = newConnBufferTuners(conn, globalNetBufferMin, globalNetBufferMax)
+			}
+
 			// Wrap the connection with ConnMux to be able to peek the data in the incoming connection
 			// and decide if we need to wrap the connection itself with a TLS or not
 			go func(connMux *ConnMux) {
@@ -263,6 +302,12 @@ func newListenerMux(listener net.Listener, config *tls.Config) *ListenerMux {
 				}
 				switch protocol {
 				case protocolTLS:
+					// Listeners serving plain HTTP only have no TLS
+					// configuration.
+					if l.config == nil {
+						connMux.Close()
+						return
+					}
 					tlsConn := tls.Server(connMux, l.config)
 					// Make sure to handshake so that we know that this
 					// is a TLS connection, if not we should close and reject
@@ -344,11 +389,44 @@ type ServerMux struct {
 	handler   http.Handler
 	listeners []*ListenerMux
 
+	// Optional address serving adminHandler alone.
+	AdminAddr      string
+	adminHandler   http.Handler
+	adminListeners []*ListenerMux
+
+	// Optional additional addresses serving handler, plain HTTP only
+	// if prefixed with "http://". Requests on plain HTTP only
+	// listeners are redirected to HTTPS if httpsRedirect is set.
+	ExtraAddrs          []string
+	extraListeners      []*ListenerMux
+	plainExtraListeners []*ListenerMux
+	httpsRedirect       bool
+
+	// Optional address serving websiteHandler alone, plain HTTP only
+	// if prefixed with "http://".
+	WebsiteAddr      string
+	websiteHandler   http.Handler
+	websiteListeners []*ListenerMux
+	websitePlain     bool
+
+	// Set when TLS certificates are configured, plain HTTP requests
+	// are redirected to HTTPS.
+	tlsEnabled bool
+
+	// Set for servers embedded in other programs, which handle
+	// signals and service commands on their own.
+	embedded bool
+
 	// Current number of concurrent http requests
 	currentReqs int32
 	// Time to wait before forcing server shutdown
 	gracefulTimeout time.Duration
 
+	// In-flight uploads, waited upon for up to drainTimeout during
+	// shutdown before other requests are waited upon.
+	uploads      sync.WaitGroup
+	drainTimeout time.Duration
+
 	mu      sync.RWMutex // guards closing, and listeners
 	closing bool
 }
@@ -361,12 +439,55 @@ func NewServerMux(addr string, handler http.Handler) *ServerMux {
 		// Wait for 5 seconds for new incoming connnections, otherwise
 		// forcibly close them during graceful stop or restart.
 		gracefulTimeout: 5 * time.Second,
+		drainTimeout:    globalShutdownDrainTimeout,
 	}
 
 	// Returns configured HTTP server.
 	return m
 }
 
+// setAdminHandler - serves handler on its own listeners at addr.
+func (m *ServerMux) setAdminHandler(addr string, handler http.Handler) {
+	m.AdminAddr = addr
+	m.adminHandler = handler
+}
+
+// setWebsiteHandler - serves handler of bucket websites on its own
+// listeners at addr.
+func (m *ServerMux) setWebsiteHandler(addr string, handler http.Handler) {
+	m.WebsiteAddr = addr
+	m.websiteHandler = handler
+}
+
+// setExtraAddrs - serves handler on additional addrs as well.
+func (m *ServerMux) setExtraAddrs(addrs []string, httpsRedirect bool) {
+	m.ExtraAddrs = addrs
+	m.httpsRedirect = httpsRedirect
+}
+
+// getHTTPSRedirectURL - returns URL of r over HTTPS, on port if set
+// or on the port of the request otherwise.
+func getHTTPSRedirectURL(r *http.Request, port string) string {
+	host := r.Host
+	if port != "" {
+		hostname, _, err := net.SplitHostPort(host)
+		if err != nil {
+			hostname = strings.Trim(host, "[]")
+		}
+		host = net.JoinHostPort(hostname, port)
+	}
+	u := url.URL{
+		Scheme:   httpsScheme,
+		Opaque:   r.URL.Opaque,
+		User:     r.URL.User,
+		Host:     host,
+		Path:     r.URL.Path,
+		RawQuery: r.URL.RawQuery,
+		Fragment: r.URL.Fragment,
+	}
+	return u.String()
+}
+
 // Initialize listeners on all ports.
 func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 	host, port, err := net.SplitHostPort(serverAddr)
@@ -409,7 +530,15 @@ func initListeners(serverAddr string, tls *tls.Config) ([]*ListenerMux, error) {
 // ListenAndServe - serve HTTP requests with protocol multiplexing support
 // TLS is actived when certFile and keyFile parameters are not empty.
 func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
+	if err = m.listen(certFile, keyFile); err != nil {
+		return err
+	}
+	return m.serve()
+}
 
+// listen - listens on server and admin addresses, requests are
+// served by serve.
+func (m *ServerMux) listen(certFile, keyFile string) (err error) {
 	tlsEnabled := certFile != "" && keyFile != ""
 
 	config := &tls.Config{
@@ -425,75 +554,234 @@ func (m *ServerMux) ListenAndServe(certFile, keyFile string) (err error) {
 		if config.NextProtos == nil {
 			config.NextProtos = []string{"http/1.1", "h2"}
 		}
-		config.Certificates = make([]tls.Certificate, 1)
-		config.Certificates[0], err = tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			return err
+		if globalCertsManager != nil {
+			// Serve certificate reloaded on updates.
+			config.GetCertificate = globalCertsManager.GetCertificate
+		} else {
+			config.Certificates = make([]tls.Certificate, 1)
+			config.Certificates[0], err = tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return err
+			}
 		}
+		// Verify client certificates of other nodes if configured.
+		setRPCMutualTLSConfig(config)
 	}
 
-	go m.handleServiceSignals()
+	if !m.embedded {
+		go m.handleServiceSignals()
+	}
 
 	listeners, err := initListeners(m.Addr, config)
 	if err != nil {
 		return err
 	}
 
+	var adminListeners []*ListenerMux
+	if m.AdminAddr != "" {
+		adminListeners, err = initListeners(m.AdminAddr, config)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+	}
+
+	var extraListeners, plainExtraListeners []*ListenerMux
+	for _, extraAddr := range m.ExtraAddrs {
+		addr, plain := splitExtraAddress(extraAddr)
+		var addrListeners []*ListenerMux
+		if plain {
+			addrListeners, err = initListeners(addr, nil)
+			plainExtraListeners = append(plainExtraListeners, addrListeners...)
+		} else {
+			addrListeners, err = initListeners(addr, config)
+			extraListeners = append(extraListeners, addrListeners...)
+		}
+		if err != nil {
+			for _, listener := range concatListeners(listeners, adminListeners, extraListeners, plainExtraListeners) {
+				listener.Close()
+			}
+			return err
+		}
+	}
+
+	var websiteListeners []*ListenerMux
+	var websitePlain bool
+	if m.WebsiteAddr != "" {
+		var addr string
+		addr, websitePlain = splitExtraAddress(m.WebsiteAddr)
+		websiteTLS := config
+		if websitePlain {
+			websiteTLS = nil
+		}
+		websiteListeners, err = initListeners(addr, websiteTLS)
+		if err != nil {
+			for _, listener := range concatListeners(listeners, adminListeners, extraListeners, plainExtraListeners) {
+				listener.Close()
+			}
+			return err
+		}
+	}
+
 	m.mu.Lock()
 	m.listeners = listeners
+	m.adminListeners = adminListeners
+	m.extraListeners = extraListeners
+	m.plainExtraListeners = plainExtraListeners
+	m.websiteListeners = websiteListeners
+	m.websitePlain = websitePlain
+	m.tlsEnabled = tlsEnabled
 	m.mu.Unlock()
+	return nil
+}
 
-	// All http requests start to be processed by httpHandler
-	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if tlsEnabled && r.TLS == nil {
-			// TLS is enabled but Request is not TLS configured
-			u := url.URL{
-				Scheme:   httpsScheme,
-				Opaque:   r.URL.Opaque,
-				User:     r.URL.User,
-				Host:     r.Host,
-				Path:     r.URL.Path,
-				RawQuery: r.URL.RawQuery,
-				Fragment: r.URL.Fragment,
-			}
-			http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
-		} else {
+// concatListeners - returns all listeners of lists.
+func concatListeners(lists ...[]*ListenerMux) (listeners []*ListenerMux) {
+	for _, list := range lists {
+		listeners = append(listeners, list...)
+	}
+	return listeners
+}
 
-			// Return ServiceUnavailable for clients which are sending requests
-			// in shutdown phase
-			m.mu.RLock()
-			closing := m.closing
-			m.mu.RUnlock()
-			if closing {
-				w.WriteHeader(http.StatusServiceUnavailable)
-				return
-			}
+// serve - serves HTTP requests on listeners until they are closed.
+func (m *ServerMux) serve() error {
+	m.mu.RLock()
+	listeners, adminListeners := concatListeners(m.listeners, m.extraListeners), m.adminListeners
+	plainListeners := m.plainExtraListeners
+	websiteListeners, websitePlain := m.websiteListeners, m.websitePlain
+	tlsEnabled := m.tlsEnabled
+	m.mu.RUnlock()
+
+	// Requests on plain HTTP only listeners are redirected to the
+	// port of the server address.
+	_, port, _ := net.SplitHostPort(m.Addr)
+
+	// All http requests start to be processed by httpHandler, plain
+	// HTTP requests of plain HTTP only listeners are served unless
+	// httpsRedirect is set.
+	httpHandler := func(handler http.Handler, plain, httpsRedirect bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// ACME challenges are served over plain HTTP.
+			isChallenge := isACMEChallengeRequest(r)
+			if tlsEnabled && r.TLS == nil && !plain && !isChallenge {
+				// TLS is enabled but Request is not TLS configured
+				http.Redirect(w, r, getHTTPSRedirectURL(r, ""), http.StatusTemporaryRedirect)
+			} else if tlsEnabled && plain && httpsRedirect && !isChallenge {
+				http.Redirect(w, r, getHTTPSRedirectURL(r, port), http.StatusTemporaryRedirect)
+			} else {
+
+				// Return ServiceUnavailable for clients which are sending requests
+				// in shutdown phase
+				done, ok := m.trackRequest(r)
+				if !ok {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
 
-			// Execute registered handlers, update currentReqs to keep
-			// tracks of current requests currently processed by the server
-			atomic.AddInt32(&m.currentReqs, 1)
-			m.handler.ServeHTTP(w, r)
-			atomic.AddInt32(&m.currentReqs, -1)
-		}
-	})
+				// Execute registered handlers.
+				handler.ServeHTTP(w, r)
+				done()
+			}
+		})
+	}
 
 	var wg = &sync.WaitGroup{}
+	serve := func(listener *ListenerMux, handler http.Handler, plain, httpsRedirect bool) {
+		defer wg.Done()
+		serr := http.Serve(listener, httpHandler(handler, plain, httpsRedirect))
+		// Do not print the error if the listener is closed.
+		if !listener.IsClosed() {
+			errorIf(serr, "Unable to serve incoming requests.")
+		}
+	}
 	for _, listener := range listeners {
 		wg.Add(1)
-		go func(listener *ListenerMux) {
-			defer wg.Done()
-			serr := http.Serve(listener, httpHandler)
-			// Do not print the error if the listener is closed.
-			if !listener.IsClosed() {
-				errorIf(serr, "Unable to serve incoming requests.")
-			}
-		}(listener)
+		go serve(listener, m.handler, false, false)
+	}
+	for _, listener := range plainListeners {
+		wg.Add(1)
+		go serve(listener, m.handler, true, m.httpsRedirect)
+	}
+	for _, listener := range adminListeners {
+		wg.Add(1)
+		go serve(listener, m.adminHandler, false, false)
+	}
+	for _, listener := range websiteListeners {
+		wg.Add(1)
+		go serve(listener, m.websiteHandler, websitePlain, false)
 	}
 	// Wait for all http.Serve's to return.
 	wg.Wait()
 	return nil
 }
 
+// isUploadRequest - returns true if the request uploads object data,
+// i.e PutObject, CopyObject, PutObjectPart, CompleteMultipartUpload
+// and browser based POST uploads.
+func isUploadRequest(r *http.Request) bool {
+	bucketName, objectName := urlPath2BucketObjectName(r.URL)
+	if bucketName == "" || isMinioReservedBucket(bucketName) {
+		return false
+	}
+	switch r.Method {
+	case httpPUT:
+		return objectName != ""
+	case httpPOST:
+		if objectName != "" {
+			_, ok := r.URL.Query()["uploadId"]
+			return ok
+		}
+		return strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data")
+	}
+	return false
+}
+
+// trackRequest - counts the request as being processed until the
+// returned function is called, returns false if server is closing.
+func (m *ServerMux) trackRequest(r *http.Request) (done func(), ok bool) {
+	// Uploads are added under the same lock which guards closing,
+	// Close never waits upon uploads while new ones are being added.
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closing {
+		return nil, false
+	}
+
+	isUpload := isUploadRequest(r)
+	if isUpload {
+		m.uploads.Add(1)
+	}
+	atomic.AddInt32(&m.currentReqs, 1)
+
+	return func() {
+		atomic.AddInt32(&m.currentReqs, -1)
+		if isUpload {
+			m.uploads.Done()
+		}
+	}, true
+}
+
+// drainUploads - waits for in-flight uploads to finish, returns false
+// if they didn't finish within drain timeout.
+func (m *ServerMux) drainUploads() bool {
+	doneCh := make(chan struct{})
+	go func() {
+		m.uploads.Wait()
+		close(doneCh)
+	}()
+
+	timer := time.NewTimer(m.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-doneCh:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
 // Close initiates the graceful shutdown
 func (m *ServerMux) Close() error {
 	m.mu.Lock()
@@ -506,7 +794,7 @@ func (m *ServerMux) Close() error {
 	m.closing = true
 
 	// Close the listeners.
-	for _, listener := range m.listeners {
+	for _, listener := range concatListeners(m.listeners, m.adminListeners, m.extraListeners, m.plainExtraListeners, m.websiteListeners) {
 		if err := listener.Close(); err != nil {
 			m.mu.Unlock()
 			return err
@@ -514,13 +802,21 @@ func (m *ServerMux) Close() error {
 	}
 	m.mu.Unlock()
 
+	// No new connections are accepted from here on, let in-flight
+	// uploads finish instead of cutting them mid-stream.
+	if !m.drainUploads() {
+		errorIf(errors.New("drain timeout exceeded"), "Shutting down with uploads still in progress after %s.", m.drainTimeout)
+	}
+
 	// Starting graceful shutdown. Check if all requests are finished
 	// in regular interval or force the shutdown
 	ticker := time.NewTicker(serverShutdownPoll)
 	defer ticker.Stop()
+	timer := time.NewTimer(m.gracefulTimeout)
+	defer timer.Stop()
 	for {
 		select {
-		case <-time.After(m.gracefulTimeout):
+		case <-timer.C:
 			return nil
 		case <-ticker.C:
 			if atomic.LoadInt32(&m.currentReqs) <= 0 {
@@ -25,6 +25,7 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -179,6 +180,184 @@ func TestClose(t *testing.T) {
 	}
 }
 
+// Tests sending a file over ConnMux through its ReadFrom.
+func TestConnMuxReadFrom(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	f, err := ioutil.TempFile(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	data := []byte("hello, world")
+	if _, err = f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Seek(7, os.SEEK_SET); err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		conn, aerr := l.Accept()
+		if aerr != nil {
+			errCh <- aerr
+			return
+		}
+		connMux := NewConnMux(conn)
+		defer connMux.Close()
+		n, rerr := connMux.ReadFrom(io.LimitReader(f, 3))
+		if rerr == nil && n != 3 {
+			rerr = fmt.Errorf("expected 3 bytes to be sent, sent %d", n)
+		}
+		errCh <- rerr
+	}()
+
+	outputBytes := globalConnStats.getTotalOutputBytes()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	received, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	if string(received) != "wor" {
+		t.Fatalf("expected \"wor\", got %q", received)
+	}
+	if sent := globalConnStats.getTotalOutputBytes() - outputBytes; sent < 3 {
+		t.Fatalf("expected at least 3 output bytes to be counted, got %d", sent)
+	}
+}
+
+func TestIsUploadRequest(t *testing.T) {
+	testCases := []struct {
+		method      string
+		url         string
+		contentType string
+		isUpload    bool
+	}{
+		// PutObject and CopyObject.
+		{httpPUT, "/bucket/object", "", true},
+		// PutObjectPart.
+		{httpPUT, "/bucket/object?partNumber=1&uploadId=id", "", true},
+		// CompleteMultipartUpload.
+		{httpPOST, "/bucket/object?uploadId=id", "", true},
+		// NewMultipartUpload.
+		{httpPOST, "/bucket/object?uploads", "", false},
+		// Browser based POST upload.
+		{httpPOST, "/bucket", "multipart/form-data; boundary=xyz", true},
+		// DeleteMultipleObjects.
+		{httpPOST, "/bucket?delete", "application/xml", false},
+		// PutBucket.
+		{httpPUT, "/bucket", "", false},
+		// GetObject.
+		{httpGET, "/bucket/object", "", false},
+		// Browser uploads are not tracked.
+		{httpPUT, "/minio/upload/bucket/object", "", false},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.contentType != "" {
+			req.Header.Set("Content-Type", testCase.contentType)
+		}
+		if isUpload := isUploadRequest(req); isUpload != testCase.isUpload {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.isUpload, isUpload)
+		}
+	}
+}
+
+func TestServerMuxDrainUploads(t *testing.T) {
+	m := NewServerMux("", nil)
+	m.gracefulTimeout = 0
+
+	upload, err := http.NewRequest(httpPUT, "/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploadDone, ok := m.trackRequest(upload)
+	if !ok {
+		t.Fatal("Expected upload to be accepted")
+	}
+
+	closedCh := make(chan struct{})
+	go func() {
+		m.Close()
+		close(closedCh)
+	}()
+
+	// Requests are rejected once server is closing.
+	for {
+		m.mu.RLock()
+		closing := m.closing
+		m.mu.RUnlock()
+		if closing {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok = m.trackRequest(upload); ok {
+		t.Fatal("Expected request to be rejected while closing")
+	}
+
+	// Close waits for in-flight uploads.
+	select {
+	case <-closedCh:
+		t.Fatal("Expected close to wait for in-flight upload")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	uploadDone()
+	select {
+	case <-closedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected close to return once upload is done")
+	}
+}
+
+func TestServerMuxDrainTimeout(t *testing.T) {
+	m := NewServerMux("", nil)
+	m.gracefulTimeout = 0
+	m.drainTimeout = 100 * time.Millisecond
+
+	upload, err := http.NewRequest(httpPUT, "/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploadDone, ok := m.trackRequest(upload)
+	if !ok {
+		t.Fatal("Expected upload to be accepted")
+	}
+	defer uploadDone()
+
+	closedCh := make(chan struct{})
+	go func() {
+		m.Close()
+		close(closedCh)
+	}()
+
+	select {
+	case <-closedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected close to return after drain timeout")
+	}
+}
+
 func TestServerMux(t *testing.T) {
 	var err error
 	var got []byte
@@ -338,6 +517,158 @@ func TestServerListenAndServePlain(t *testing.T) {
 	}
 }
 
+// Tests admin handler is served alone on the admin address.
+func TestServerListenAndServeAdmin(t *testing.T) {
+	addr := net.JoinHostPort("127.0.0.1", getFreePort())
+	adminAddr := net.JoinHostPort("127.0.0.1", getFreePort())
+
+	// Initialize done channel specifically for each tests.
+	globalServiceDoneCh = make(chan struct{}, 1)
+
+	m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "api")
+	}))
+	m.setAdminHandler(adminAddr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "admin")
+	}))
+	errc := make(chan error, 1)
+	go func() { errc <- m.ListenAndServe("", "") }()
+	defer m.Close()
+
+	get := func(addr string) string {
+		client := http.Client{Timeout: time.Second}
+		for i := 0; i < 100; i++ {
+			select {
+			case err := <-errc:
+				t.Fatalf("Unexpected error %v", err)
+			default:
+			}
+			res, err := client.Get("http://" + addr)
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			body, err := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(body)
+		}
+		t.Fatalf("Server at %s is not ready", addr)
+		return ""
+	}
+
+	if body := get(addr); body != "api" {
+		t.Errorf("Expected api handler at %s, got %s", addr, body)
+	}
+	if body := get(adminAddr); body != "admin" {
+		t.Errorf("Expected admin handler at %s, got %s", adminAddr, body)
+	}
+}
+
+// Tests requests on plain HTTP only addresses are served over plain
+// HTTP along with TLS on the server address, or redirected to it.
+func TestServerListenAndServeExtraAddrs(t *testing.T) {
+	err := createConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := getPublicCertFile()
+	keyFile := getPrivateKeyFile()
+	defer os.RemoveAll(certFile)
+	defer os.RemoveAll(keyFile)
+	if err = generateTestCert("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, httpsRedirect := range []bool{false, true} {
+		addr := net.JoinHostPort("127.0.0.1", getFreePort())
+		plainAddr := net.JoinHostPort("127.0.0.1", getFreePort())
+
+		// Initialize done channel specifically for each tests.
+		globalServiceDoneCh = make(chan struct{}, 1)
+
+		m := NewServerMux(addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "hello")
+		}))
+		m.setExtraAddrs([]string{plainHTTPAddressPrefix + plainAddr}, httpsRedirect)
+		if err = m.listen(certFile, keyFile); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		go m.serve()
+
+		client := http.Client{
+			Timeout: time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		// TLS is not served on plain HTTP only addresses.
+		if res, gerr := client.Get("https://" + plainAddr); gerr == nil {
+			res.Body.Close()
+			t.Errorf("Test %d: Expected TLS request on %s to fail", i+1, plainAddr)
+		}
+
+		res, err := client.Get("http://" + plainAddr + "/bucket?prefix=a")
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		res.Body.Close()
+		if httpsRedirect {
+			expected := "https://" + addr + "/bucket?prefix=a"
+			if res.StatusCode != http.StatusTemporaryRedirect || res.Header.Get("Location") != expected {
+				t.Errorf("Test %d: Expected redirect to %s, got %d %s", i+1, expected, res.StatusCode, res.Header.Get("Location"))
+			}
+		} else if res.StatusCode != http.StatusOK {
+			t.Errorf("Test %d: Expected plain HTTP request to be served, got %d", i+1, res.StatusCode)
+		}
+
+		// Server address still serves TLS.
+		if res, err = client.Get("https://" + addr); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("Test %d: Expected TLS request to be served, got %d", i+1, res.StatusCode)
+		}
+		m.Close()
+	}
+}
+
+// Tests URLs plain HTTP requests are redirected to.
+func TestGetHTTPSRedirectURL(t *testing.T) {
+	testCases := []struct {
+		host     string
+		url      string
+		port     string
+		expected string
+	}{
+		// Test 1: same port.
+		{"minio.example.com:9000", "/bucket/object?uploads", "", "https://minio.example.com:9000/bucket/object?uploads"},
+		// Test 2: port of the server address.
+		{"minio.example.com:9080", "/bucket", "9000", "https://minio.example.com:9000/bucket"},
+		// Test 3: request without port.
+		{"minio.example.com", "/", "443", "https://minio.example.com:443/"},
+		// Test 4: IPv6 hosts.
+		{"[::1]:9080", "/", "9000", "https://[::1]:9000/"},
+		{"[::1]", "/", "9000", "https://[::1]:9000/"},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("GET", "http://"+testCase.host+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if u := getHTTPSRedirectURL(req, testCase.port); u != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, u)
+		}
+	}
+}
+
 func TestServerListenAndServeTLS(t *testing.T) {
 	wait := make(chan struct{})
 	addr := net.JoinHostPort("127.0.0.1", getFreePort())
@@ -442,7 +773,7 @@ func generateTestCert(host string) error {
 		NotAfter:  UTCNow().Add(time.Minute * 1),
 
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
 	}
 
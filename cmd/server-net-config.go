@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+// Address the server listens on unless configured otherwise.
+const defaultServerAddress = ":9000"
+
+// Prefix of additional addresses serving plain HTTP only.
+const plainHTTPAddressPrefix = "http://"
+
+// serverNetConfig - addresses, TLS and listener options of the
+// server. Gathered from command line flags and environment variables,
+// flags take precedence, and validated in one place.
+type serverNetConfig struct {
+	// Address to serve S3, browser and RPC requests on, "[HOST]:PORT".
+	Address string
+	// Additional addresses to serve S3 and browser requests on,
+	// "[HOST]:PORT" or "http://[HOST]:PORT" for plain HTTP only, e.g.
+	// on an internal interface while Address serves HTTPS.
+	ExtraAddresses []string
+	// Redirect requests on plain HTTP only addresses to HTTPS on the
+	// port of Address, if TLS is configured.
+	HTTPSRedirect bool
+	// Address reported to clients in startup message and event
+	// notifications, e.g. of a load balancer. Defaults to Address.
+	AdvertiseAddress string
+	// Optional address to serve admin API on, admin API is then not
+	// served on Address.
+	AdminAddress string
+	// Optional address to serve bucket websites on, "[HOST]:PORT" or
+	// "http://[HOST]:PORT" for plain HTTP only.
+	WebsiteAddress string
+
+	// TLS certificate and private key, set only when certificates
+	// are found in the certs directory.
+	CertFile string
+	KeyFile  string
+
+	// Time to wait for in-flight uploads to finish on shutdown.
+	DrainTimeout time.Duration
+}
+
+// newServerNetConfig - returns validated network configuration from
+// command line flags of ctx and environment variables.
+func newServerNetConfig(ctx *cli.Context) (cfg serverNetConfig, err error) {
+	lookup := func(flag, envVar, defaultValue string) string {
+		if ctx.IsSet(flag) {
+			return ctx.String(flag)
+		}
+		if value := os.Getenv(envVar); value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
+	// First of comma separated addresses is the server address.
+	addresses := strings.Split(lookup("address", "MINIO_ADDRESS", defaultServerAddress), ",")
+	cfg = serverNetConfig{
+		Address:          strings.TrimSpace(addresses[0]),
+		AdvertiseAddress: lookup("advertise-address", "MINIO_ADVERTISE_ADDRESS", ""),
+		AdminAddress:     lookup("admin-address", "MINIO_ADMIN_ADDRESS", ""),
+		WebsiteAddress:   lookup("website-address", "MINIO_WEBSITE_ADDRESS", ""),
+		HTTPSRedirect:    ctx.Bool("https-redirect") || strings.EqualFold(os.Getenv("MINIO_HTTPS_REDIRECT"), "on"),
+		DrainTimeout:     defaultShutdownDrainTimeout,
+	}
+	for _, address := range addresses[1:] {
+		cfg.ExtraAddresses = append(cfg.ExtraAddresses, strings.TrimSpace(address))
+	}
+
+	if timeout := os.Getenv("MINIO_SHUTDOWN_DRAIN_TIMEOUT"); timeout != "" {
+		cfg.DrainTimeout, err = time.ParseDuration(timeout)
+		if err != nil || cfg.DrainTimeout < 0 {
+			return cfg, fmt.Errorf("unknown value ‘%s’ in MINIO_SHUTDOWN_DRAIN_TIMEOUT environment variable", timeout)
+		}
+	}
+
+	return cfg, cfg.validate()
+}
+
+// splitExtraAddress - returns listen address of an additional address
+// and whether it serves plain HTTP only.
+func splitExtraAddress(address string) (addr string, plain bool) {
+	if hasPrefix(address, plainHTTPAddressPrefix) {
+		return strings.TrimPrefix(address, plainHTTPAddressPrefix), true
+	}
+	return address, false
+}
+
+// validate - returns error if an address is malformed, a listen
+// address is not of this server or two listen addresses would share
+// a port.
+func (cfg serverNetConfig) validate() error {
+	if err := CheckLocalServerAddr(cfg.Address); err != nil {
+		return fmt.Errorf("invalid address ‘%s’: %v", cfg.Address, err)
+	}
+
+	if cfg.AdvertiseAddress != "" {
+		host, port, err := net.SplitHostPort(cfg.AdvertiseAddress)
+		if err == nil && host == "" {
+			err = fmt.Errorf("missing host")
+		}
+		if err == nil {
+			err = checkPortNumber(port)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid advertise address ‘%s’: %v", cfg.AdvertiseAddress, err)
+		}
+	}
+
+	if cfg.AdminAddress != "" {
+		if err := CheckLocalServerAddr(cfg.AdminAddress); err != nil {
+			return fmt.Errorf("invalid admin address ‘%s’: %v", cfg.AdminAddress, err)
+		}
+		_, port := mustSplitHostPort(cfg.Address)
+		if _, adminPort := mustSplitHostPort(cfg.AdminAddress); adminPort == port {
+			return fmt.Errorf("invalid admin address ‘%s’: port %s is already used by address ‘%s’", cfg.AdminAddress, port, cfg.Address)
+		}
+	}
+
+	_, port := mustSplitHostPort(cfg.Address)
+	usedPorts := map[string]string{port: cfg.Address}
+	if cfg.AdminAddress != "" {
+		_, adminPort := mustSplitHostPort(cfg.AdminAddress)
+		usedPorts[adminPort] = cfg.AdminAddress
+	}
+	hasPlainAddress := false
+	for _, address := range cfg.ExtraAddresses {
+		addr, plain := splitExtraAddress(address)
+		if err := CheckLocalServerAddr(addr); err != nil {
+			return fmt.Errorf("invalid address ‘%s’: %v", address, err)
+		}
+		_, extraPort := mustSplitHostPort(addr)
+		if usedBy, ok := usedPorts[extraPort]; ok {
+			return fmt.Errorf("invalid address ‘%s’: port %s is already used by address ‘%s’", address, extraPort, usedBy)
+		}
+		usedPorts[extraPort] = address
+		hasPlainAddress = hasPlainAddress || plain
+	}
+	if cfg.WebsiteAddress != "" {
+		addr, _ := splitExtraAddress(cfg.WebsiteAddress)
+		if err := CheckLocalServerAddr(addr); err != nil {
+			return fmt.Errorf("invalid website address ‘%s’: %v", cfg.WebsiteAddress, err)
+		}
+		_, websitePort := mustSplitHostPort(addr)
+		if usedBy, ok := usedPorts[websitePort]; ok {
+			return fmt.Errorf("invalid website address ‘%s’: port %s is already used by address ‘%s’", cfg.WebsiteAddress, websitePort, usedBy)
+		}
+	}
+	if cfg.HTTPSRedirect && !hasPlainAddress {
+		return fmt.Errorf("HTTPS redirect needs an address prefixed with %s", plainHTTPAddressPrefix)
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("TLS certificate and private key must be set together")
+	}
+
+	if cfg.DrainTimeout < 0 {
+		return fmt.Errorf("invalid shutdown drain timeout %s", cfg.DrainTimeout)
+	}
+
+	return nil
+}
+
+// isTLS - returns true if requests are served over TLS.
+func (cfg serverNetConfig) isTLS() bool {
+	return cfg.CertFile != ""
+}
+
+// getAPIEndpoints - returns endpoints clients reach the server on,
+// the advertise address if set, otherwise all endpoints of Address
+// followed by those of additional addresses.
+func (cfg serverNetConfig) getAPIEndpoints() []string {
+	if cfg.AdvertiseAddress == "" {
+		endpoints := getAPIEndpoints(cfg.Address)
+		for _, address := range cfg.ExtraAddresses {
+			endpoints = append(endpoints, getExtraAddressEndpoints(address)...)
+		}
+		return endpoints
+	}
+
+	scheme := httpScheme
+	if cfg.isTLS() {
+		scheme = httpsScheme
+	}
+	return []string{scheme + "://" + cfg.AdvertiseAddress}
+}
+
+// getExtraAddressEndpoints - returns endpoints of an additional
+// address, over plain HTTP if it serves plain HTTP only.
+func getExtraAddressEndpoints(address string) (endpoints []string) {
+	addr, plain := splitExtraAddress(address)
+	for _, endpoint := range getAPIEndpoints(addr) {
+		if plain {
+			endpoint = httpScheme + "://" + strings.SplitN(endpoint, "://", 2)[1]
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
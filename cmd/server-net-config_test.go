@@ -0,0 +1,257 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+// Returns context of server command with args parsed as flags.
+func newServerCmdContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet(serverCmd.Name, flag.ContinueOnError)
+	for _, f := range serverFlags {
+		f.Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+// Tests network configuration from flags and environment variables.
+func TestNewServerNetConfig(t *testing.T) {
+	envVars := []string{"MINIO_ADDRESS", "MINIO_ADVERTISE_ADDRESS", "MINIO_ADMIN_ADDRESS", "MINIO_HTTPS_REDIRECT", "MINIO_SHUTDOWN_DRAIN_TIMEOUT"}
+	defer func(values []string) {
+		for i, envVar := range envVars {
+			os.Setenv(envVar, values[i])
+		}
+	}(func() (values []string) {
+		for _, envVar := range envVars {
+			values = append(values, os.Getenv(envVar))
+		}
+		return values
+	}())
+
+	testCases := []struct {
+		args       []string
+		env        map[string]string
+		expected   serverNetConfig
+		shouldPass bool
+	}{
+		// Defaults.
+		{nil, nil, serverNetConfig{Address: ":9000", DrainTimeout: defaultShutdownDrainTimeout}, true},
+		// Environment variables only.
+		{
+			nil,
+			map[string]string{
+				"MINIO_ADDRESS":                ":9100",
+				"MINIO_ADVERTISE_ADDRESS":      "minio.example.com:443",
+				"MINIO_ADMIN_ADDRESS":          "localhost:9101",
+				"MINIO_SHUTDOWN_DRAIN_TIMEOUT": "10s",
+			},
+			serverNetConfig{
+				Address:          ":9100",
+				AdvertiseAddress: "minio.example.com:443",
+				AdminAddress:     "localhost:9101",
+				DrainTimeout:     10 * time.Second,
+			},
+			true,
+		},
+		// Flags take precedence over environment variables.
+		{
+			[]string{"--address", ":9200", "--advertise-address", "10.0.0.1:80", "--admin-address", "127.0.0.1:9201"},
+			map[string]string{
+				"MINIO_ADDRESS":           ":9100",
+				"MINIO_ADVERTISE_ADDRESS": "minio.example.com:443",
+				"MINIO_ADMIN_ADDRESS":     "localhost:9101",
+			},
+			serverNetConfig{
+				Address:          ":9200",
+				AdvertiseAddress: "10.0.0.1:80",
+				AdminAddress:     "127.0.0.1:9201",
+				DrainTimeout:     defaultShutdownDrainTimeout,
+			},
+			true,
+		},
+		// Flag set to its default value still takes precedence.
+		{
+			[]string{"--address", ":9000"},
+			map[string]string{"MINIO_ADDRESS": ":9100"},
+			serverNetConfig{Address: ":9000", DrainTimeout: defaultShutdownDrainTimeout},
+			true,
+		},
+		// Drain timeout may be disabled.
+		{
+			nil,
+			map[string]string{"MINIO_SHUTDOWN_DRAIN_TIMEOUT": "0s"},
+			serverNetConfig{Address: ":9000"},
+			true,
+		},
+		// Additional addresses.
+		{
+			[]string{"--address", ":9443, http://127.0.0.1:9080", "--https-redirect"},
+			nil,
+			serverNetConfig{
+				Address:        ":9443",
+				ExtraAddresses: []string{"http://127.0.0.1:9080"},
+				HTTPSRedirect:  true,
+				DrainTimeout:   defaultShutdownDrainTimeout,
+			},
+			true,
+		},
+		{
+			nil,
+			map[string]string{"MINIO_ADDRESS": ":9443,:9444,http://:9080", "MINIO_HTTPS_REDIRECT": "on"},
+			serverNetConfig{
+				Address:        ":9443",
+				ExtraAddresses: []string{":9444", "http://:9080"},
+				HTTPSRedirect:  true,
+				DrainTimeout:   defaultShutdownDrainTimeout,
+			},
+			true,
+		},
+		// Invalid values.
+		{[]string{"--address", "9000"}, nil, serverNetConfig{}, false},
+		{[]string{"--address", ":9000,:9000"}, nil, serverNetConfig{}, false},
+		{nil, map[string]string{"MINIO_HTTPS_REDIRECT": "on"}, serverNetConfig{}, false},
+		{nil, map[string]string{"MINIO_ADDRESS": ":0"}, serverNetConfig{}, false},
+		{nil, map[string]string{"MINIO_ADVERTISE_ADDRESS": ":443"}, serverNetConfig{}, false},
+		{[]string{"--admin-address", ":9000"}, nil, serverNetConfig{}, false},
+		{nil, map[string]string{"MINIO_SHUTDOWN_DRAIN_TIMEOUT": "-1s"}, serverNetConfig{}, false},
+		{nil, map[string]string{"MINIO_SHUTDOWN_DRAIN_TIMEOUT": "1 minute"}, serverNetConfig{}, false},
+	}
+
+	for i, testCase := range testCases {
+		for _, envVar := range envVars {
+			os.Setenv(envVar, testCase.env[envVar])
+		}
+		cfg, err := newServerNetConfig(newServerCmdContext(t, testCase.args...))
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, passed with %+v", i+1, cfg)
+		}
+		if testCase.shouldPass && !reflect.DeepEqual(cfg, testCase.expected) {
+			t.Errorf("Test %d: Expected %+v, got %+v", i+1, testCase.expected, cfg)
+		}
+	}
+}
+
+// Tests validation of network configuration.
+func TestServerNetConfigValidate(t *testing.T) {
+	testCases := []struct {
+		cfg        serverNetConfig
+		shouldPass bool
+	}{
+		{serverNetConfig{Address: ":9000"}, true},
+		{serverNetConfig{Address: "localhost:9000"}, true},
+		{serverNetConfig{Address: "127.0.0.1:9000", AdvertiseAddress: "minio.example.com:443"}, true},
+		{serverNetConfig{Address: ":9000", AdvertiseAddress: "[::1]:9000"}, true},
+		{serverNetConfig{Address: ":9000", AdminAddress: ":9001"}, true},
+		{serverNetConfig{Address: ":9000", AdminAddress: "localhost:9001"}, true},
+		{serverNetConfig{Address: ":9000", CertFile: "public.crt", KeyFile: "private.key"}, true},
+		{serverNetConfig{Address: ":9000", DrainTimeout: time.Minute}, true},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{":9001", "http://localhost:9002"}}, true},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{"http://:9080"}, HTTPSRedirect: true}, true},
+		{serverNetConfig{Address: ":9000", WebsiteAddress: "http://:8080"}, true},
+
+		// Malformed or foreign addresses.
+		{serverNetConfig{}, false},
+		{serverNetConfig{Address: "9000"}, false},
+		{serverNetConfig{Address: ":port"}, false},
+		{serverNetConfig{Address: ":65536"}, false},
+		{serverNetConfig{Address: "8.8.8.8:9000"}, false},
+		{serverNetConfig{Address: ":9000", AdvertiseAddress: "minio.example.com"}, false},
+		{serverNetConfig{Address: ":9000", AdvertiseAddress: ":443"}, false},
+		{serverNetConfig{Address: ":9000", AdvertiseAddress: "minio.example.com:0"}, false},
+		{serverNetConfig{Address: ":9000", AdminAddress: "9001"}, false},
+		{serverNetConfig{Address: ":9000", AdminAddress: ":0"}, false},
+		{serverNetConfig{Address: ":9000", AdminAddress: "8.8.8.8:9001"}, false},
+
+		// Admin API can't share the port of the server.
+		{serverNetConfig{Address: ":9000", AdminAddress: ":9000"}, false},
+		{serverNetConfig{Address: ":9000", AdminAddress: "localhost:9000"}, false},
+
+		// Certificate without key and the other way round.
+		{serverNetConfig{Address: ":9000", CertFile: "public.crt"}, false},
+		{serverNetConfig{Address: ":9000", KeyFile: "private.key"}, false},
+
+		{serverNetConfig{Address: ":9000", DrainTimeout: -time.Second}, false},
+
+		// Additional addresses must be local and use their own port.
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{"https://:9001"}}, false},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{"http://8.8.8.8:9001"}}, false},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{"http://localhost:9000"}}, false},
+		{serverNetConfig{Address: ":9000", AdminAddress: ":9001", ExtraAddresses: []string{":9001"}}, false},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{":9001", "http://:9001"}}, false},
+
+		// Website address must be local and use its own port.
+		{serverNetConfig{Address: ":9000", WebsiteAddress: "http://8.8.8.8:8080"}, false},
+		{serverNetConfig{Address: ":9000", WebsiteAddress: "http://:9000"}, false},
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{":8080"}, WebsiteAddress: ":8080"}, false},
+
+		// HTTPS redirect needs a plain HTTP only address.
+		{serverNetConfig{Address: ":9000", ExtraAddresses: []string{":9001"}, HTTPSRedirect: true}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.cfg.validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail, passed", i+1)
+		}
+	}
+}
+
+// Tests endpoints reported to clients.
+func TestServerNetConfigGetAPIEndpoints(t *testing.T) {
+	testCases := []struct {
+		cfg      serverNetConfig
+		expected []string
+	}{
+		{serverNetConfig{Address: "127.0.0.1:9000"}, []string{"http://127.0.0.1:9000"}},
+		{serverNetConfig{Address: ":9000", AdvertiseAddress: "minio.example.com:80"}, []string{"http://minio.example.com:80"}},
+		{
+			serverNetConfig{Address: ":9000", AdvertiseAddress: "minio.example.com:443", CertFile: "public.crt", KeyFile: "private.key"},
+			[]string{"https://minio.example.com:443"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if endpoints := testCase.cfg.getAPIEndpoints(); !reflect.DeepEqual(endpoints, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, endpoints)
+		}
+	}
+
+	// Plain HTTP only addresses are reported over HTTP.
+	defer func(isSSL bool) { globalIsSSL = isSSL }(globalIsSSL)
+	globalIsSSL = true
+	cfg := serverNetConfig{Address: "127.0.0.1:9443", ExtraAddresses: []string{"127.0.0.1:9444", "http://10.0.0.1:9080"}}
+	expected := []string{"https://127.0.0.1:9443", "https://127.0.0.1:9444", "http://10.0.0.1:9080"}
+	if endpoints := cfg.getAPIEndpoints(); !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("Expected %v, got %v", expected, endpoints)
+	}
+}
@@ -55,18 +55,18 @@ func getMaxCacheSize(curLimit, totalRAM uint64) (cacheSize uint64) {
 	return cacheSize
 }
 
-// GetMaxCacheSize returns maximum cache size based on current RAM size and memory limit.
-func GetMaxCacheSize() (cacheSize uint64, err error) {
+// getMemoryLimits returns current memory limit of the process and
+// total RAM, which is the memory limit of the cgroup if lower.
+func getMemoryLimits() (curLimit, totalRAM uint64, err error) {
 	// Get max memory limit
-	var curLimit uint64
 	if curLimit, _, err = sys.GetMaxMemoryLimit(); err != nil {
-		return cacheSize, err
+		return 0, 0, err
 	}
 
 	// Get total RAM.
 	var stats sys.Stats
 	if stats, err = sys.GetStats(); err != nil {
-		return cacheSize, err
+		return 0, 0, err
 	}
 
 	// In some OS like windows, maxLimit is zero.  Set total RAM as maxLimit.
@@ -74,6 +74,16 @@ func GetMaxCacheSize() (cacheSize uint64, err error) {
 		curLimit = stats.TotalRAM
 	}
 
-	cacheSize = getMaxCacheSize(curLimit, stats.TotalRAM)
+	return curLimit, stats.TotalRAM, nil
+}
+
+// GetMaxCacheSize returns maximum cache size based on current RAM size and memory limit.
+func GetMaxCacheSize() (cacheSize uint64, err error) {
+	curLimit, totalRAM, err := getMemoryLimits()
+	if err != nil {
+		return cacheSize, err
+	}
+
+	cacheSize = getMaxCacheSize(curLimit, totalRAM)
 	return cacheSize, err
 }
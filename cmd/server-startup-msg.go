@@ -80,6 +80,17 @@ func printServerCommonMsg(apiEndpoints []string) {
 	log.Println(colorBlue("AccessKey: ") + colorBold(fmt.Sprintf("%s ", cred.AccessKey)))
 	log.Println(colorBlue("SecretKey: ") + colorBold(fmt.Sprintf("%s ", cred.SecretKey)))
 	log.Println(colorBlue("Region: ") + colorBold(fmt.Sprintf(getFormatStr(len(region), 3), region)))
+	if mode := getServerMode(); mode != serverModeReadWrite {
+		log.Println(colorBlue("Mode: ") + colorBold(fmt.Sprintf(getFormatStr(len(mode), 5), mode)))
+	}
+	if adminAddr := globalServerNetConfig.AdminAddress; adminAddr != "" {
+		adminEndpointStr := strings.Join(getAPIEndpoints(adminAddr), "  ")
+		log.Println(colorBlue("Admin: ") + colorBold(fmt.Sprintf(getFormatStr(len(adminEndpointStr), 4), adminEndpointStr)))
+	}
+	if websiteAddr := globalServerNetConfig.WebsiteAddress; websiteAddr != "" {
+		websiteEndpointStr := strings.Join(getExtraAddressEndpoints(websiteAddr), "  ")
+		log.Println(colorBlue("Website: ") + colorBold(fmt.Sprintf(getFormatStr(len(websiteEndpointStr), 2), websiteEndpointStr)))
+	}
 	printEventNotifiers()
 
 	log.Println(colorBlue("\nBrowser Access:"))
@@ -140,6 +151,9 @@ func getStorageInfoMsg(storageInfo StorageInfo) string {
 		if maxDiskFailures := storageInfo.Backend.ReadQuorum - storageInfo.Backend.OfflineDisks; maxDiskFailures >= 0 {
 			diskInfo += fmt.Sprintf("We can withstand [%d] more drive failure(s).", maxDiskFailures)
 		}
+		if storageInfo.Backend.Degraded {
+			diskInfo += " Degraded, reading from the remaining drives."
+		}
 		msg += colorBlue("\nStatus:") + fmt.Sprintf(getFormatStr(len(diskInfo), 8), diskInfo)
 	}
 	return msg
@@ -158,7 +172,7 @@ func getCertificateChainMsg(certs []*x509.Certificate) string {
 	var expiringCerts int
 	for i := totalCerts - 1; i >= 0; i-- {
 		cert := certs[i]
-		if cert.NotAfter.Before(UTCNow().Add(globalMinioCertExpireWarnDays)) {
+		if cert.NotAfter.Before(UTCNow().Add(globalCertExpiryWarn)) {
 			expiringCerts++
 			msg += fmt.Sprintf(colorBold("#%d %s will expire on %s\n"), expiringCerts, cert.Subject.CommonName, cert.NotAfter)
 		}
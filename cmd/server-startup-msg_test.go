@@ -38,10 +38,11 @@ func TestStorageInfoMsg(t *testing.T) {
 			OfflineDisks int
 			ReadQuorum   int
 			WriteQuorum  int
-		}{Erasure, 7, 1, 4, 5},
+			Degraded     bool
+		}{Erasure, 7, 1, 4, 5, true},
 	}
 
-	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "2.0 GiB Free, 10 GiB Total") || !strings.Contains(msg, "7 Online, 1 Offline") {
+	if msg := getStorageInfoMsg(infoStorage); !strings.Contains(msg, "2.0 GiB Free, 10 GiB Total") || !strings.Contains(msg, "7 Online, 1 Offline") || !strings.Contains(msg, "Degraded") {
 		t.Fatal("Unexpected storage info message, found:", msg)
 	}
 }
@@ -1577,7 +1577,7 @@ func (s *TestSuiteCommon) TestListObjectsHandler(c *C) {
 	getContent, err = ioutil.ReadAll(response.Body)
 	c.Assert(err, IsNil)
 	c.Assert(strings.Contains(string(getContent), "<Key>bar</Key>"), Equals, true)
-	c.Assert(strings.Contains(string(getContent), "<Owner><ID></ID><DisplayName></DisplayName></Owner>"), Equals, true)
+	c.Assert(strings.Contains(string(getContent), "<Owner>"), Equals, false)
 
 	// create listObjectsV2 request with valid parameters and fetch-owner activated
 	request, err = newTestSignedRequest("GET", getListObjectsV2URL(s.endPoint, bucketName, "1000", "true"),
@@ -1674,15 +1674,16 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *C) {
 	verifyError(c, response, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.",
 		http.StatusConflict)
 
-	// request for ACL.
-	// Since Minio server doesn't support ACL's the request is expected to fail with  "NotImplemented" error message.
+	// request for ACL granting permissions to another user.
+	// Since Minio server only supports ACLs equivalent to canned ACLs the request is expected to fail with "InvalidArgument" error message.
+	acl := `<AccessControlPolicy><AccessControlList><Grant><Grantee><ID>other</ID></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`
 	request, err = newTestSignedRequest("PUT", s.endPoint+"/"+bucketName+"?acl",
-		0, nil, s.accessKey, s.secretKey, s.signer)
+		int64(len(acl)), bytes.NewReader([]byte(acl)), s.accessKey, s.secretKey, s.signer)
 	c.Assert(err, IsNil)
 
 	response, err = client.Do(request)
 	c.Assert(err, IsNil)
-	verifyError(c, response, "NotImplemented", "A header you provided implies functionality that is not implemented", http.StatusNotImplemented)
+	verifyError(c, response, "InvalidArgument", "Only private, public-read and public-read-write ACLs granting permissions to the owner and AllUsers group are supported.", http.StatusBadRequest)
 }
 
 func (s *TestSuiteCommon) TestGetObjectLarge10MiB(c *C) {
@@ -2586,7 +2587,7 @@ func (s *TestSuiteCommon) TestObjectMultipart(c *C) {
 
 	// Complete multipart upload
 	completeUploads := &completeMultipartUpload{
-		Parts: []completePart{
+		Parts: []CompletePart{
 			{
 				PartNumber: 1,
 				ETag:       response1.Header.Get("ETag"),
@@ -2609,7 +2610,7 @@ func (s *TestSuiteCommon) TestObjectMultipart(c *C) {
 	c.Assert(err, IsNil)
 	// verify whether complete multipart was successful.
 	c.Assert(response.StatusCode, Equals, http.StatusOK)
-	var parts []completePart
+	var parts []CompletePart
 	for _, part := range completeUploads.Parts {
 		part.ETag = canonicalizeETag(part.ETag)
 		parts = append(parts, part)
@@ -68,10 +68,8 @@ func restartProcess() error {
 func (m *ServerMux) handleServiceSignals() error {
 	// Custom exit function
 	runExitFn := func(err error) {
-		// If global profiler is set stop before we exit.
-		if globalProfiler != nil {
-			globalProfiler.Stop()
-		}
+		// If a profile is being recorded save it before we exit.
+		saveGlobalProfiler()
 
 		// Call user supplied user exit function
 		fatalIf(err, "Unable to gracefully complete service operation.")
@@ -0,0 +1,237 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"time"
+)
+
+const (
+	// Share links config file name, saved alongside other bucket
+	// configs in minioMetaBucket.
+	bucketShareLinksConfig = "share-links.json"
+
+	// Query parameter carrying the ID of a share link in its
+	// presigned URL, covered by the signature of the URL.
+	shareLinkIDQueryKey = "X-Minio-Share-Id"
+
+	// Maximum number of unexpired share links of a bucket.
+	maxBucketShareLinks = 1000
+
+	// Maximum and default expiry of share links, same as presigned
+	// URLs.
+	maxShareLinkExpiry = 7 * 24 * time.Hour
+)
+
+var (
+	// Internal error used to signal a share link is not found.
+	errNoSuchShareLink = errors.New("The specified share link does not exist")
+
+	// Internal error used to signal a bucket has too many share links.
+	errTooManyShareLinks = errors.New("The bucket has too many unexpired share links, revoke some of them or retry once they expire")
+)
+
+// ShareLinkInfo - a share link of an object, revoked links are kept
+// until they expire for auditing.
+type ShareLinkInfo struct {
+	ID        string    `json:"id"`
+	Object    string    `json:"object"`
+	Created   time.Time `json:"created"`
+	Expiry    time.Time `json:"expiry"`
+	Revoked   bool      `json:"revoked"`
+	RevokedAt time.Time `json:"revokedAt,omitempty"`
+}
+
+// bucketShareLinks - share links of a bucket.
+type bucketShareLinks struct {
+	Version string          `json:"version"`
+	Links   []ShareLinkInfo `json:"links"`
+}
+
+// find - returns the share link with the given ID, nil if not found.
+func (s *bucketShareLinks) find(id string) *ShareLinkInfo {
+	for i := range s.Links {
+		if s.Links[i].ID == id {
+			return &s.Links[i]
+		}
+	}
+	return nil
+}
+
+// removeExpired - forgets share links expired before now, their
+// presigned URLs are not accepted anymore anyways.
+func (s *bucketShareLinks) removeExpired(now time.Time) {
+	links := s.Links[:0]
+	for _, link := range s.Links {
+		if link.Expiry.After(now) {
+			links = append(links, link)
+		}
+	}
+	s.Links = links
+}
+
+// getShareLinkExpiry - returns expiry of a share link valid for the
+// given number of seconds, defaults to the maximum expiry.
+func getShareLinkExpiry(expiry int64) time.Duration {
+	if expiry <= 0 || time.Duration(expiry)*time.Second > maxShareLinkExpiry {
+		return maxShareLinkExpiry
+	}
+	return time.Duration(expiry) * time.Second
+}
+
+// readBucketShareLinks - reads share links of a bucket, must be called
+// with the share links config locked.
+func readBucketShareLinks(bucket string, objAPI ObjectLayer) (*bucketShareLinks, error) {
+	slPath := path.Join(bucketConfigPrefix, bucket, bucketShareLinksConfig)
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, slPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return &bucketShareLinks{Version: "1"}, nil
+		}
+		errorIf(err, "Unable to load share links for bucket %s", bucket)
+		return nil, err
+	}
+
+	links := &bucketShareLinks{}
+	if err = json.Unmarshal(buffer.Bytes(), links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// loadBucketShareLinks - loads share links of a bucket, a bucket
+// without share links has an empty list.
+func loadBucketShareLinks(bucket string, objAPI ObjectLayer) (*bucketShareLinks, error) {
+	slPath := path.Join(bucketConfigPrefix, bucket, bucketShareLinksConfig)
+
+	// Acquire a read lock on share links config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, slPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	return readBucketShareLinks(bucket, objAPI)
+}
+
+// updateBucketShareLinks - applies update on share links of a bucket
+// and saves them, expired links are removed on every update.
+func updateBucketShareLinks(bucket string, objAPI ObjectLayer, update func(*bucketShareLinks) error) error {
+	slPath := path.Join(bucketConfigPrefix, bucket, bucketShareLinksConfig)
+
+	// Acquire a write lock on share links config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, slPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	links, err := readBucketShareLinks(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	links.removeExpired(UTCNow())
+	if err = update(links); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(links)
+	if err != nil {
+		return err
+	}
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, slPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write share links for bucket %s", bucket)
+	}
+	return err
+}
+
+// newShareLink - tracks a new share link of an object valid for the
+// given number of seconds.
+func newShareLink(bucket, object string, expiry int64, objAPI ObjectLayer) (link ShareLinkInfo, err error) {
+	now := UTCNow()
+	link = ShareLinkInfo{
+		ID:      mustGetUUID(),
+		Object:  object,
+		Created: now,
+		Expiry:  now.Add(getShareLinkExpiry(expiry)),
+	}
+	err = updateBucketShareLinks(bucket, objAPI, func(links *bucketShareLinks) error {
+		if len(links.Links) >= maxBucketShareLinks {
+			return errTooManyShareLinks
+		}
+		links.Links = append(links.Links, link)
+		return nil
+	})
+	return link, err
+}
+
+// revokeShareLink - revokes a share link, its presigned URL is denied
+// from now on.
+func revokeShareLink(bucket, id string, objAPI ObjectLayer) error {
+	return updateBucketShareLinks(bucket, objAPI, func(links *bucketShareLinks) error {
+		link := links.find(id)
+		if link == nil {
+			return errNoSuchShareLink
+		}
+		if !link.Revoked {
+			link.Revoked = true
+			link.RevokedAt = UTCNow()
+		}
+		return nil
+	})
+}
+
+// removeBucketShareLinks - removes share links of a bucket, only used
+// during DeleteBucket.
+func removeBucketShareLinks(bucket string, objAPI ObjectLayer) error {
+	slPath := path.Join(bucketConfigPrefix, bucket, bucketShareLinksConfig)
+
+	// Acquire a write lock on share links config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, slPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, slPath)
+	objLock.Unlock()
+	return err
+}
+
+// checkShareLink - denies presigned requests of share links which are
+// revoked or not tracked anymore, other requests are not affected.
+func checkShareLink(r *http.Request, bucket string) APIErrorCode {
+	id := r.URL.Query().Get(shareLinkIDQueryKey)
+	if id == "" {
+		return ErrNone
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return ErrServerNotInitialized
+	}
+
+	links, err := loadBucketShareLinks(bucket, objAPI)
+	if err != nil {
+		return toAPIErrorCode(err)
+	}
+	if link := links.find(id); link == nil || link.Revoked {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Tests expiry of share links.
+func TestGetShareLinkExpiry(t *testing.T) {
+	testCases := []struct {
+		expiry   int64
+		expected time.Duration
+	}{
+		{0, maxShareLinkExpiry},
+		{-1, maxShareLinkExpiry},
+		{1000, 1000 * time.Second},
+		{604800, maxShareLinkExpiry},
+		{604801, maxShareLinkExpiry},
+	}
+	for i, testCase := range testCases {
+		if expiry := getShareLinkExpiry(testCase.expiry); expiry != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, expiry)
+		}
+	}
+}
+
+// Tests tracking, revocation and removal of share links.
+func TestBucketShareLinks(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	links, err := loadBucketShareLinks(bucket, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links.Links) != 0 {
+		t.Fatalf("Expected no share links, got %d", len(links.Links))
+	}
+
+	link, err := newShareLink(bucket, "object", 10, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = revokeShareLink(bucket, link.ID, obj); err != nil {
+		t.Fatal(err)
+	}
+	if err = revokeShareLink(bucket, "unknown", obj); err != errNoSuchShareLink {
+		t.Fatalf("Expected %v, got %v", errNoSuchShareLink, err)
+	}
+	links, err = loadBucketShareLinks(bucket, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found := links.find(link.ID); found == nil || !found.Revoked {
+		t.Fatalf("Expected revoked share link, got %#v", found)
+	}
+
+	// Expired links are forgotten.
+	links.removeExpired(link.Expiry)
+	if len(links.Links) != 0 {
+		t.Fatalf("Expected no share links after expiry, got %d", len(links.Links))
+	}
+
+	if err = removeBucketShareLinks(bucket, obj); err != nil {
+		t.Fatal(err)
+	}
+	links, err = loadBucketShareLinks(bucket, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links.Links) != 0 {
+		t.Fatalf("Expected no share links, got %d", len(links.Links))
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Maximum size of a request dump sent to be debugged.
+const maxRequestDumpSize = 64 * 1024
+
+// errInvalidRequestDump - returned for request dumps which can't be
+// parsed as an HTTP request.
+var errInvalidRequestDump = errors.New("Request dump is not a valid HTTP request")
+
+// signatureDebugInfo - components of the signature V4 the server
+// computes for a request, reported by admin API so that clients can
+// compare them with their own. The signature itself is not reported,
+// only whether the request carries the same one.
+type signatureDebugInfo struct {
+	AccessKey string `json:"accessKey,omitempty"`
+	Region    string `json:"region,omitempty"`
+
+	// Time the request was signed at, and difference with the time
+	// of the server.
+	RequestTime time.Time     `json:"requestTime"`
+	ServerTime  time.Time     `json:"serverTime"`
+	ClockSkew   time.Duration `json:"clockSkew"`
+
+	SignedHeaders    string `json:"signedHeaders,omitempty"`
+	CanonicalRequest string `json:"canonicalRequest,omitempty"`
+	StringToSign     string `json:"stringToSign,omitempty"`
+	SignatureMatch   bool   `json:"signatureMatch"`
+
+	// Error the request gets from the server, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// setError - records error code the request gets.
+func (info *signatureDebugInfo) setError(errCode APIErrorCode) {
+	apiErr := getAPIError(errCode)
+	info.Error = fmt.Sprintf("%s: %s", apiErr.Code, apiErr.Description)
+}
+
+// parseRequestDump - parses a dump of a request, its request line
+// followed by its headers, as printed by clients in debug mode. The
+// body, if any, is ignored.
+func parseRequestDump(dump io.Reader) (*http.Request, error) {
+	var buffer bytes.Buffer
+	if _, err := io.Copy(&buffer, io.LimitReader(dump, maxRequestDumpSize)); err != nil {
+		return nil, err
+	}
+	// Dumps pasted without the empty line ending headers are
+	// accepted as well.
+	buffer.WriteString("\r\n\r\n")
+
+	r, err := http.ReadRequest(bufio.NewReader(&buffer))
+	if err != nil {
+		return nil, errInvalidRequestDump
+	}
+	return r, nil
+}
+
+// debugSignatureV4 - computes the signature V4 of a request signed
+// with the Authorization header, the way doesSignatureMatch does,
+// reporting each of its components.
+func debugSignatureV4(r *http.Request, region string) signatureDebugInfo {
+	info := signatureDebugInfo{
+		ServerTime: UTCNow(),
+	}
+
+	if !isRequestSignatureV4(r) {
+		info.setError(ErrSignatureVersionNotSupported)
+		return info
+	}
+	signV4Values, errCode := parseSignV4(r.Header.Get("Authorization"))
+	if errCode != ErrNone {
+		info.setError(errCode)
+		return info
+	}
+	info.AccessKey = signV4Values.Credential.accessKey
+	info.Region = signV4Values.Credential.scope.region
+
+	// Extract date, if not present throw error.
+	date := r.Header.Get(http.CanonicalHeaderKey("x-amz-date"))
+	if date == "" {
+		if date = r.Header.Get("Date"); date == "" {
+			info.setError(ErrMissingDateHeader)
+			return info
+		}
+	}
+	t, err := time.Parse(iso8601Format, date)
+	if err != nil {
+		info.setError(ErrMalformedDate)
+		return info
+	}
+	info.RequestTime = t
+	info.ClockSkew = info.ServerTime.Sub(t)
+
+	extractedSignedHeaders, errCode := extractSignedHeaders(signV4Values.SignedHeaders, r)
+	if errCode != ErrNone {
+		info.setError(errCode)
+		return info
+	}
+	info.SignedHeaders = getSignedHeaders(extractedSignedHeaders)
+	info.CanonicalRequest = getCanonicalRequest(extractedSignedHeaders, getContentSha256Cksum(r),
+		r.URL.Query().Encode(), r.URL.Path, r.Method)
+	info.StringToSign = getStringToSign(info.CanonicalRequest, t, signV4Values.Credential.getScope())
+
+	cred, errCode := getRequestCredential(signV4Values.Credential.accessKey, r.Header.Get(amzSecurityToken))
+	if errCode != ErrNone {
+		info.setError(errCode)
+		return info
+	}
+	if region == "" {
+		region = info.Region
+	}
+	if !isValidRegion(info.Region, region) {
+		info.setError(ErrAuthorizationHeaderMalformed)
+		return info
+	}
+
+	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, region)
+	info.SignatureMatch = getSignature(signingKey, info.StringToSign) == signV4Values.Signature
+	switch {
+	case info.ClockSkew > globalMaxSkewTime || -info.ClockSkew > globalMaxSkewTime:
+		info.setError(ErrRequestTimeTooSkewed)
+	case !info.SignatureMatch:
+		info.setError(ErrSignatureDoesNotMatch)
+	}
+	return info
+}
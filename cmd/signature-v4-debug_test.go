@@ -0,0 +1,114 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests reporting components of the signature of dumped requests.
+func TestDebugSignatureV4(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	cred := serverConfig.GetCredential()
+
+	dumpRequest := func(accessKey, secretKey string, modify func(*http.Request)) []byte {
+		req, err := newTestSignedRequestV4("PUT", "http://127.0.0.1:9000/bucket/object?uploads", 4,
+			bytes.NewReader([]byte("data")), accessKey, secretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if modify != nil {
+			modify(req)
+		}
+		dump, err := httputil.DumpRequest(req, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dump
+	}
+
+	testCases := []struct {
+		dump           []byte
+		signatureMatch bool
+		errCode        APIErrorCode
+	}{
+		// Test case - 1.
+		// Request signed with server credentials.
+		{dumpRequest(cred.AccessKey, cred.SecretKey, nil), true, ErrNone},
+		// Test case - 2.
+		// Request signed with a wrong secret key.
+		{dumpRequest(cred.AccessKey, "wrongsecretkey", nil), false, ErrSignatureDoesNotMatch},
+		// Test case - 3.
+		// Request signed an hour ago.
+		{dumpRequest(cred.AccessKey, cred.SecretKey, func(req *http.Request) {
+			req.Header.Set("X-Amz-Date", UTCNow().Add(-time.Hour).Format(iso8601Format))
+		}), false, ErrRequestTimeTooSkewed},
+		// Test case - 4.
+		// Request signed with an unknown access key.
+		{dumpRequest("unknownaccesskey", cred.SecretKey, nil), false, ErrInvalidAccessKeyID},
+		// Test case - 5.
+		// Unsigned request.
+		{[]byte("GET /bucket HTTP/1.1\nHost: 127.0.0.1:9000"), false, ErrSignatureVersionNotSupported},
+	}
+	for i, testCase := range testCases {
+		req, err := parseRequestDump(bytes.NewReader(testCase.dump))
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		info := debugSignatureV4(req, serverConfig.GetRegion())
+		if info.SignatureMatch != testCase.signatureMatch {
+			t.Errorf("Test %d: Expected signature match %v, got %v", i+1, testCase.signatureMatch, info.SignatureMatch)
+		}
+		expectedErr := ""
+		if testCase.errCode != ErrNone {
+			expectedErr = getAPIError(testCase.errCode).Code
+		}
+		if !strings.HasPrefix(info.Error, expectedErr) || (expectedErr == "") != (info.Error == "") {
+			t.Errorf("Test %d: Expected error %q, got %q", i+1, expectedErr, info.Error)
+		}
+		if testCase.errCode == ErrSignatureVersionNotSupported {
+			continue
+		}
+		if !strings.HasPrefix(info.CanonicalRequest, "PUT\n/bucket/object\nuploads=\n") ||
+			!strings.HasPrefix(info.StringToSign, signV4Algorithm+"\n") {
+			t.Errorf("Test %d: Unexpected canonical request %q, string to sign %q", i+1, info.CanonicalRequest, info.StringToSign)
+		}
+	}
+
+	// Requests signed with different secret keys have the same
+	// string to sign.
+	req1, _ := parseRequestDump(bytes.NewReader(testCases[0].dump))
+	req2, _ := parseRequestDump(bytes.NewReader(testCases[1].dump))
+	info1 := debugSignatureV4(req1, serverConfig.GetRegion())
+	info2 := debugSignatureV4(req2, serverConfig.GetRegion())
+	if info1.RequestTime.Equal(info2.RequestTime) && info1.StringToSign != info2.StringToSign {
+		t.Errorf("Expected the same string to sign, got %q and %q", info1.StringToSign, info2.StringToSign)
+	}
+
+	if _, err = parseRequestDump(strings.NewReader("not a request")); err != errInvalidRequestDump {
+		t.Errorf("Expected %s, got %v", errInvalidRequestDump, err)
+	}
+}
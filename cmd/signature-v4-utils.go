@@ -79,6 +79,13 @@ func isValidRegion(reqRegion string, confRegion string) bool {
 	return reqRegion == confRegion
 }
 
+// isErrInvalidRegion - returns true if the request was signed with a
+// region other than the expected one, header signed requests are
+// rejected with AuthorizationHeaderMalformed like AWS S3.
+func isErrInvalidRegion(s3Error APIErrorCode) bool {
+	return s3Error == ErrInvalidRegion || s3Error == ErrAuthorizationHeaderMalformed
+}
+
 // sumHMAC calculate hmac between two input byte array.
 func sumHMAC(key []byte, data []byte) []byte {
 	hash := hmac.New(sha256.New, key)
@@ -193,9 +193,6 @@ func doesPolicySignatureV4Match(formValues http.Header) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html
 // returns ErrNone if the signature matches.
 func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request
 	req := *r
 
@@ -205,9 +202,10 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		return err
 	}
 
-	// Verify if the access key id matches.
-	if pSignValues.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	// Access credentials of the access key.
+	cred, err := getRequestCredential(pSignValues.Credential.accessKey, req.URL.Query().Get(amzSecurityToken))
+	if err != ErrNone {
+		return err
 	}
 
 	// Verify if region is valid.
@@ -252,6 +250,9 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	query.Set("X-Amz-Expires", strconv.Itoa(expireSeconds))
 	query.Set("X-Amz-SignedHeaders", getSignedHeaders(extractedSignedHeaders))
 	query.Set("X-Amz-Credential", cred.AccessKey+"/"+getScope(t, sRegion))
+	if sessionToken := req.URL.Query().Get(amzSecurityToken); sessionToken != "" {
+		query.Set(amzSecurityToken, sessionToken)
+	}
 
 	// Save other headers available in the request parameters.
 	for k, v := range req.URL.Query() {
@@ -290,7 +291,7 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	/// Verify finally if signature is same.
 
 	// Get canonical request.
-	presignedCanonicalReq := getCanonicalRequest(extractedSignedHeaders, hashedPayload, encodedQuery, req.URL.Path, req.Method)
+	presignedCanonicalReq := getCanonicalRequest(extractedSignedHeaders, hashedPayload, encodedQuery, getSignedPath(&req), req.Method)
 
 	// Get string to sign from canonical request.
 	presignedStringToSign := getStringToSign(presignedCanonicalReq, t, pSignValues.Credential.getScope())
@@ -305,16 +306,20 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	if req.URL.Query().Get("X-Amz-Signature") != newSignature {
 		return ErrSignatureDoesNotMatch
 	}
-	return ErrNone
+
+	// Verify upload constraints of the URL.
+	if errCode := checkPresignConditions(r); errCode != ErrNone {
+		return errCode
+	}
+
+	// Verify single-use URL was not used before.
+	return usePresignNonce(req.URL.Query(), cred.AccessKey, t.Add(pSignValues.Expires))
 }
 
 // doesSignatureMatch - Verify authorization header with calculated header in accordance with
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
 // returns ErrNone if signature matches.
 func doesSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request.
 	req := *r
 
@@ -333,9 +338,10 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 		return errCode
 	}
 
-	// Verify if the access key id matches.
-	if signV4Values.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	// Access credentials of the access key.
+	cred, errCode := getRequestCredential(signV4Values.Credential.accessKey, req.Header.Get(amzSecurityToken))
+	if errCode != ErrNone {
+		return errCode
 	}
 
 	// Verify if region is valid.
@@ -349,7 +355,7 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	}
 	// Should validate region, only if region is set.
 	if !isValidRegion(sRegion, region) {
-		return ErrInvalidRegion
+		return ErrAuthorizationHeaderMalformed
 	}
 
 	// Extract date, if not present throw error.
@@ -369,7 +375,7 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 	queryStr := req.URL.Query().Encode()
 
 	// Get canonical request.
-	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, hashedPayload, queryStr, req.URL.Path, req.Method)
+	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, hashedPayload, queryStr, getSignedPath(&req), req.Method)
 
 	// Get string to sign from canonical request.
 	stringToSign := getStringToSign(canonicalRequest, t, signV4Values.Credential.getScope())
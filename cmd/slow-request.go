@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Number of most recent slow requests kept for the admin API.
+const slowRequestsMaxReports = 100
+
+// LockWaitInfo - time a request waited on a namespace lock.
+type LockWaitInfo struct {
+	Bucket   string        `json:"bucket"`
+	Object   string        `json:"object"`
+	LockType string        `json:"lockType"`
+	Waited   time.Duration `json:"waited"`
+	// Locks held on the same (bucket, object) by other operations
+	// when the request started waiting, along with their origin.
+	HeldBy []string `json:"heldBy,omitempty"`
+}
+
+// SlowRequestInfo - a request which took longer than the slow
+// request threshold, along with its namespace lock waits.
+type SlowRequestInfo struct {
+	RequestID string         `json:"requestID"`
+	Method    string         `json:"method"`
+	Path      string         `json:"path"`
+	Started   time.Time      `json:"started"`
+	Duration  time.Duration  `json:"duration"`
+	LockWait  time.Duration  `json:"lockWait"`
+	LockWaits []LockWaitInfo `json:"lockWaits,omitempty"`
+}
+
+// slowRequestError - logged for every slow request.
+type slowRequestError SlowRequestInfo
+
+func (s slowRequestError) Error() string {
+	waits := make([]string, len(s.LockWaits))
+	for i, wait := range s.LockWaits {
+		waits[i] = fmt.Sprintf("%s on <volume> %s, <path> %s for %s", wait.LockType, wait.Bucket, wait.Object, wait.Waited)
+		if len(wait.HeldBy) > 0 {
+			waits[i] += " held by " + strings.Join(wait.HeldBy, ", ")
+		}
+	}
+	msg := fmt.Sprintf("Slow request %s %s <request-id> %s took %s, waited %s on locks", s.Method, s.Path, s.RequestID, s.Duration, s.LockWait)
+	if len(waits) > 0 {
+		msg += ": " + strings.Join(waits, "; ")
+	}
+	return msg
+}
+
+// slowRequests - tracks lock waits of requests being served and keeps
+// the most recent slow requests. Lock waits are attributed to requests
+// by the goroutine serving them, so locks taken by goroutines spawned
+// by a request are not accounted.
+type slowRequests struct {
+	mu sync.Mutex
+	// Lock waits of requests being served, by goroutine ID.
+	active map[uint64][]LockWaitInfo
+	// Most recent slow requests, oldest first.
+	reports []SlowRequestInfo
+}
+
+func newSlowRequests() *slowRequests {
+	return &slowRequests{active: make(map[uint64][]LockWaitInfo)}
+}
+
+// isEnabled - returns true if slow requests are reported.
+func (s *slowRequests) isEnabled() bool {
+	return globalSlowRequestThreshold > 0
+}
+
+// addLockWait - accounts lock wait to the request served by the
+// calling goroutine, if any.
+func (s *slowRequests) addLockWait(wait LockWaitInfo) {
+	if !s.isEnabled() {
+		return
+	}
+	goroutineID := getGoroutineID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if waits, ok := s.active[goroutineID]; ok {
+		s.active[goroutineID] = append(waits, wait)
+	}
+}
+
+// begin - starts accounting lock waits of a request served by the
+// calling goroutine, returns its goroutine ID.
+func (s *slowRequests) begin() uint64 {
+	goroutineID := getGoroutineID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active[goroutineID] = []LockWaitInfo{}
+	return goroutineID
+}
+
+// end - stops accounting lock waits of a request, it is reported if
+// it took longer than the threshold.
+func (s *slowRequests) end(goroutineID uint64, info SlowRequestInfo) {
+	s.mu.Lock()
+	waits := s.active[goroutineID]
+	delete(s.active, goroutineID)
+	if info.Duration < globalSlowRequestThreshold {
+		s.mu.Unlock()
+		return
+	}
+	for _, wait := range waits {
+		info.LockWait += wait.Waited
+	}
+	if len(waits) > 0 {
+		info.LockWaits = waits
+	}
+	if len(s.reports) == slowRequestsMaxReports {
+		s.reports = s.reports[1:]
+	}
+	s.reports = append(s.reports, info)
+	s.mu.Unlock()
+
+	errorIf(slowRequestError(info), "Request took longer than %s", globalSlowRequestThreshold)
+}
+
+// list - returns the most recent slow requests, oldest first.
+func (s *slowRequests) list() []SlowRequestInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := make([]SlowRequestInfo, len(s.reports))
+	copy(reports, s.reports)
+	return reports
+}
+
+// slowRequestHandler reports requests taking longer than the slow
+// request threshold.
+type slowRequestHandler struct {
+	handler http.Handler
+}
+
+// setSlowRequestHandler - logs and keeps requests taking longer than
+// MINIO_SLOW_REQUEST_THRESHOLD along with the time they waited on
+// namespace locks and the operations holding them.
+func setSlowRequestHandler(h http.Handler) http.Handler {
+	return slowRequestHandler{handler: h}
+}
+
+func (h slowRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !globalSlowRequests.isEnabled() {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	goroutineID := globalSlowRequests.begin()
+	started := UTCNow()
+	defer func() {
+		globalSlowRequests.end(goroutineID, SlowRequestInfo{
+			RequestID: getRequestID(r),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Started:   started,
+			Duration:  UTCNow().Sub(started),
+		})
+	}()
+	h.handler.ServeHTTP(w, r)
+}
+
+// lockHolders - returns the locks on param the lock of opsID waits
+// for.
+func (n *nsLockMap) lockHolders(param nsParam, opsID string) (holders []string) {
+	shard := n.getDebugLockShard(param)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	volumePathLocks, ok := shard.lockInfo[param]
+	if !ok {
+		return nil
+	}
+	waiter := volumePathLocks.lockInfo[opsID]
+	for holderOpsID, holder := range volumePathLocks.lockInfo {
+		if holderOpsID != opsID && waitsOn(waiter, holder) {
+			holderStr := fmt.Sprintf("%s at %s", holder.lType, holder.lockSource)
+			if holder.requestID != "" {
+				holderStr += fmt.Sprintf(" by request %s", holder.requestID)
+			}
+			holders = append(holders, holderStr)
+		}
+	}
+	return holders
+}
@@ -0,0 +1,151 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests slow requests are reported along with their lock waits.
+func TestSlowRequestHandler(t *testing.T) {
+	savedNSMutex := globalNSMutex
+	defer func(threshold time.Duration, slow *slowRequests) {
+		globalNSMutex = savedNSMutex
+		globalSlowRequestThreshold = threshold
+		globalSlowRequests = slow
+	}(globalSlowRequestThreshold, globalSlowRequests)
+	initNSLock(false)
+
+	// Takes a read lock held by another operation for holdTime.
+	holdTime := 50 * time.Millisecond
+	handler := setSlowRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		holder := globalNSMutex.NewNSLock("bucket", "object")
+		holder.Lock()
+		go func() {
+			time.Sleep(holdTime)
+			holder.Unlock()
+		}()
+		lk := globalNSMutex.NewNSLock("bucket", "object")
+		lk.RLock()
+		lk.RUnlock()
+	}))
+
+	testCases := []struct {
+		threshold time.Duration
+		reported  bool
+	}{
+		// Disabled.
+		{0, false},
+		// Request faster than threshold.
+		{time.Hour, false},
+		// Slow request.
+		{time.Nanosecond, true},
+	}
+	for i, testCase := range testCases {
+		globalSlowRequestThreshold = testCase.threshold
+		globalSlowRequests = newSlowRequests()
+
+		req, err := newTestRequest("PUT", "http://localhost:9000/bucket/object", 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		reports := globalSlowRequests.list()
+		if len(globalSlowRequests.active) != 0 {
+			t.Errorf("Test %d: Expected no request to be tracked after it was served", i+1)
+		}
+		if !testCase.reported {
+			if len(reports) != 0 {
+				t.Errorf("Test %d: Expected no slow request, got %v", i+1, reports)
+			}
+			continue
+		}
+		if len(reports) != 1 {
+			t.Fatalf("Test %d: Expected one slow request, got %v", i+1, reports)
+		}
+		report := reports[0]
+		if report.Method != "PUT" || report.Path != "/bucket/object" || report.Duration < holdTime {
+			t.Errorf("Test %d: Unexpected slow request %+v", i+1, report)
+		}
+		// The holder didn't wait, the reader waited on it.
+		if len(report.LockWaits) != 2 {
+			t.Fatalf("Test %d: Expected two lock waits, got %+v", i+1, report.LockWaits)
+		}
+		wait := report.LockWaits[1]
+		if wait.Bucket != "bucket" || wait.Object != "object" || wait.LockType != string(debugRLockStr) || wait.Waited < holdTime/2 {
+			t.Errorf("Test %d: Unexpected lock wait %+v", i+1, wait)
+		}
+		if len(wait.HeldBy) != 1 || !strings.HasPrefix(wait.HeldBy[0], string(debugWLockStr)+" at ") {
+			t.Errorf("Test %d: Expected lock wait to be held by a write lock, got %v", i+1, wait.HeldBy)
+		}
+		if report.LockWait < wait.Waited {
+			t.Errorf("Test %d: Expected total lock wait of at least %s, got %s", i+1, wait.Waited, report.LockWait)
+		}
+	}
+}
+
+// Tests only the most recent slow requests are kept.
+func TestSlowRequestsList(t *testing.T) {
+	defer func(threshold time.Duration) { globalSlowRequestThreshold = threshold }(globalSlowRequestThreshold)
+	globalSlowRequestThreshold = time.Second
+
+	slow := newSlowRequests()
+	for i := 0; i < slowRequestsMaxReports+10; i++ {
+		slow.end(slow.begin(), SlowRequestInfo{Path: "/bucket/" + string(rune('a'+i%26)), Duration: time.Duration(i+1) * time.Second})
+	}
+	reports := slow.list()
+	if len(reports) != slowRequestsMaxReports {
+		t.Fatalf("Expected %d slow requests, got %d", slowRequestsMaxReports, len(reports))
+	}
+	if reports[0].Duration != 11*time.Second || reports[len(reports)-1].Duration != time.Duration(slowRequestsMaxReports+10)*time.Second {
+		t.Fatalf("Expected oldest slow requests to be dropped, got %s to %s", reports[0].Duration, reports[len(reports)-1].Duration)
+	}
+
+	// Lock waits outside of requests are not accounted.
+	slow.addLockWait(LockWaitInfo{Bucket: "bucket", Object: "object", Waited: time.Second})
+	if len(slow.active) != 0 {
+		t.Fatalf("Expected no request to be tracked, got %v", slow.active)
+	}
+}
+
+// Tests the message logged for a slow request.
+func TestSlowRequestError(t *testing.T) {
+	err := slowRequestError{
+		RequestID: "14D4B2A3F2E2C0010001",
+		Method:    "GET",
+		Path:      "/bucket/object",
+		Duration:  3 * time.Second,
+		LockWait:  2 * time.Second,
+		LockWaits: []LockWaitInfo{{
+			Bucket:   "bucket",
+			Object:   "object",
+			LockType: "RLock",
+			Waited:   2 * time.Second,
+			HeldBy:   []string{"WLock at [object-handlers.go:542:objectAPIHandlers.PutObjectHandler()]"},
+		}},
+	}
+	expected := "Slow request GET /bucket/object <request-id> 14D4B2A3F2E2C0010001 took 3s, waited 2s on locks: " +
+		"RLock on <volume> bucket, <path> object for 2s held by WLock at [object-handlers.go:542:objectAPIHandlers.PutObjectHandler()]"
+	if err.Error() != expected {
+		t.Fatalf("Expected %s, got %s", expected, err.Error())
+	}
+}
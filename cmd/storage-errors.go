@@ -33,6 +33,9 @@ var errDiskFull = errors.New("disk path full")
 // errDiskNotFount - cannot find the underlying configured disk anymore.
 var errDiskNotFound = errors.New("disk not found")
 
+// errDiskMismatch - reconnected disk is not the disk it was before.
+var errDiskMismatch = errors.New("disk does not match the expected disk")
+
 // errFaultyRemoteDisk - remote disk is faulty.
 var errFaultyRemoteDisk = errors.New("remote disk is faulty")
 
@@ -42,6 +45,10 @@ var errFaultyDisk = errors.New("disk is faulty")
 // errDiskAccessDenied - we don't have write permissions on disk.
 var errDiskAccessDenied = errors.New("disk access denied")
 
+// errBitRotHashMismatch - checksum of a file does not match the one
+// recorded when it was written.
+var errBitRotHashMismatch = errors.New("bit-rot hash mismatch")
+
 // errFileNotFound - cannot find the file.
 var errFileNotFound = errors.New("file not found")
 
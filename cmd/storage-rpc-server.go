@@ -232,7 +232,7 @@ func registerStorageRPCRouters(mux *router.Router, endpoints EndpointList) error
 		}
 		// Add minio storage routes.
 		storageRouter := mux.PathPrefix(minioReservedBucketPath).Subrouter()
-		storageRouter.Path(path.Join(storageRPCPath, stServer.path)).Handler(storageRPCServer)
+		storageRouter.Path(path.Join(storageRPCPath, stServer.path)).Handler(setRPCMutualTLSHandler(storageRPCServer))
 	}
 	return nil
 }
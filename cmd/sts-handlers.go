@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// STS actions, sent in the Action query parameter.
+const (
+	stsAssumeRole                 = "AssumeRole"
+	stsAssumeRoleWithLDAPIdentity = "AssumeRoleWithLDAPIdentity"
+)
+
+// assumeRoleResponse - response of AssumeRole, in the format of AWS
+// STS responses.
+type assumeRoleResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse" json:"-"`
+	Result  struct {
+		Credentials stsCredentials `xml:"Credentials"`
+	} `xml:"AssumeRoleResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId"`
+	} `xml:"ResponseMetadata"`
+}
+
+// assumeRoleWithLDAPIdentityResponse - response of
+// AssumeRoleWithLDAPIdentity, in the format of AWS STS responses.
+type assumeRoleWithLDAPIdentityResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithLDAPIdentityResponse" json:"-"`
+	Result  struct {
+		Credentials stsCredentials `xml:"Credentials"`
+	} `xml:"AssumeRoleWithLDAPIdentityResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId"`
+	} `xml:"ResponseMetadata"`
+}
+
+// stsCredentials - temporary credentials issued by STS actions.
+type stsCredentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+// registerSTSRouter - registers STS endpoints issuing temporary
+// credentials.
+func registerSTSRouter(mux *router.Router) {
+	mux.Methods(httpPOST).Path("/").Queries("Action", stsAssumeRole).
+		HandlerFunc(assumeRoleHandler)
+	mux.Methods(httpPOST).Path("/").Queries("Action", stsAssumeRoleWithLDAPIdentity).
+		HandlerFunc(assumeRoleWithLDAPIdentityHandler)
+}
+
+// isSTSRequest - returns true if request is for an STS action.
+func isSTSRequest(r *http.Request) bool {
+	if r.Method != httpPOST || r.URL.Path != "/" {
+		return false
+	}
+	switch r.URL.Query().Get("Action") {
+	case stsAssumeRole, stsAssumeRoleWithLDAPIdentity:
+		return true
+	}
+	return false
+}
+
+// getSTSDuration - returns the validity of temporary credentials
+// requested in DurationSeconds form value, defaultDuration if not set.
+func getSTSDuration(r *http.Request, defaultDuration, maxDuration time.Duration) (time.Duration, APIErrorCode) {
+	durationStr := r.Form.Get("DurationSeconds")
+	if durationStr == "" {
+		return defaultDuration, ErrNone
+	}
+	seconds, err := strconv.Atoi(durationStr)
+	duration := time.Duration(seconds) * time.Second
+	if err != nil || duration < minSTSDuration || duration > maxDuration {
+		return 0, ErrInvalidDuration
+	}
+	return duration, ErrNone
+}
+
+// getSTSPolicies - returns the session policies of temporary
+// credentials requested in Policy form value, none if not set.
+func getSTSPolicies(r *http.Request) ([]string, APIErrorCode) {
+	policy := r.Form.Get("Policy")
+	if policy == "" {
+		return nil, ErrNone
+	}
+	if len(policy) > maxSessionPolicySize {
+		return nil, ErrPackedPolicyTooLarge
+	}
+	if _, err := parseSessionPolicy(policy); err != nil {
+		return nil, ErrMalformedPolicyDocument
+	}
+	return []string{policy}, ErrNone
+}
+
+// assumeRoleHandler - POST /?Action=AssumeRole
+// ----------
+// Issues temporary credentials to requests signed with the server
+// credential. Credentials are valid for DurationSeconds when set, an
+// hour otherwise, and limited to the session policy in Policy, only
+// allowed to read otherwise. Temporary credentials can't be used to
+// get new ones, they would never expire otherwise.
+func assumeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Err := checkAdminRequestAuthType(r, ""); s3Err != ErrNone {
+		writeErrorResponse(w, s3Err, r.URL)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		return
+	}
+
+	duration, s3Err := getSTSDuration(r, defaultSTSDuration, maxSTSDuration)
+	if s3Err != ErrNone {
+		writeErrorResponse(w, s3Err, r.URL)
+		return
+	}
+	policies, s3Err := getSTSPolicies(r)
+	if s3Err != ErrNone {
+		writeErrorResponse(w, s3Err, r.URL)
+		return
+	}
+
+	cred, sessionToken, expiration, err := newTemporaryCredential(serverConfig.GetCredential().AccessKey, policies, duration)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to generate temporary credentials")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	response := assumeRoleResponse{}
+	response.Result.Credentials = stsCredentials{
+		AccessKeyID:     cred.AccessKey,
+		SecretAccessKey: cred.SecretKey,
+		SessionToken:    sessionToken,
+		Expiration:      expiration,
+	}
+	response.ResponseMetadata.RequestID = getRequestID(r)
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// assumeRoleWithLDAPIdentityHandler - POST /?Action=AssumeRoleWithLDAPIdentity
+// ----------
+// Exchanges username and password of an LDAP user, sent as
+// LDAPUsername and LDAPPassword form values, for temporary
+// credentials. Credentials are valid for DurationSeconds when set,
+// LDAP credentialExpiry otherwise. They are limited to the policies
+// of the groups of the user in config, and to the session policy in
+// Policy when set. Users who aren't members of any of these groups
+// get no credentials.
+func assumeRoleWithLDAPIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	if globalLDAPProvider == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		return
+	}
+	username := r.Form.Get("LDAPUsername")
+	password := r.Form.Get("LDAPPassword")
+	if username == "" || password == "" {
+		writeErrorResponse(w, ErrMissingFields, r.URL)
+		return
+	}
+
+	expiry, err := serverConfig.GetLDAP().getCredentialExpiry()
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	duration, s3Err := getSTSDuration(r, expiry, expiry)
+	if s3Err != ErrNone {
+		writeErrorResponse(w, s3Err, r.URL)
+		return
+	}
+	policies, s3Err := getSTSPolicies(r)
+	if s3Err != ErrNone {
+		writeErrorResponse(w, s3Err, r.URL)
+		return
+	}
+
+	groups, err := globalLDAPProvider.Authenticate(username, password)
+	if err != nil {
+		if err != errAuthentication {
+			reqErrorIf(r, err, "Unable to authenticate LDAP user %s", username)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+	if len(groups) == 0 {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+	groupsPolicy, err := globalLDAPProvider.config.getGroupsPolicy(groups)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to get policy of LDAP user %s", username)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	policies = append([]string{groupsPolicy}, policies...)
+
+	cred, sessionToken, expiration, err := newTemporaryCredential(username, policies, duration)
+	if err != nil {
+		reqErrorIf(r, err, "Unable to generate temporary credentials")
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	response := assumeRoleWithLDAPIdentityResponse{}
+	response.Result.Credentials = stsCredentials{
+		AccessKeyID:     cred.AccessKey,
+		SecretAccessKey: cred.SecretKey,
+		SessionToken:    sessionToken,
+		Expiration:      expiration,
+	}
+	response.ResponseMetadata.RequestID = getRequestID(r)
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
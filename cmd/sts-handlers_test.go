@@ -0,0 +1,298 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests exchange of LDAP credentials for temporary credentials.
+func TestAssumeRoleWithLDAPIdentityHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	tlsConfig, rootCAs := newTestLDAPTLSConfig(t)
+	globalRootCAs = rootCAs
+	defer func() { globalRootCAs = nil }()
+
+	alice := "uid=alice,ou=people,dc=example,dc=com"
+	bob := "uid=bob,ou=people,dc=example,dc=com"
+	addr, stop := startTestLDAPServer(t, testLDAPServer{
+		users:     map[string]string{alice: "alice123", bob: "bob123"},
+		groups:    map[string][]string{"cn=writers,ou=groups,dc=example,dc=com": {alice}},
+		tls:       ldapTLSOn,
+		tlsConfig: tlsConfig,
+	})
+	defer stop()
+
+	serverConfig.LDAP = ldapConfig{
+		Enable:       true,
+		ServerAddr:   addr,
+		UserDNFormat: "uid=%s,ou=people,dc=example,dc=com",
+		GroupPolicies: map[string]json.RawMessage{
+			"cn=writers,ou=groups,dc=example,dc=com": json.RawMessage(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::bucket/*"]}]}`),
+		},
+		CredentialExpiry: "2h",
+	}
+	defer func() { globalLDAPProvider = nil }()
+	readOnlyPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::*"]}]}`
+
+	mux := router.NewRouter()
+	registerSTSRouter(mux)
+
+	testCases := []struct {
+		isEnabled          bool
+		form               url.Values
+		expectedRespStatus int
+		expectedDuration   time.Duration
+		expectedPutErrCode APIErrorCode
+	}{
+		// Test case - 1.
+		// LDAP is not enabled.
+		{false, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}}, http.StatusNotImplemented, 0, ErrNone},
+		// Test case - 2.
+		// Password is missing.
+		{true, url.Values{"LDAPUsername": {"alice"}}, http.StatusBadRequest, 0, ErrNone},
+		// Test case - 3.
+		// Invalid password.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"bob123"}}, http.StatusForbidden, 0, ErrNone},
+		// Test case - 4.
+		// Duration longer than credentialExpiry.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}, "DurationSeconds": {"86400"}}, http.StatusBadRequest, 0, ErrNone},
+		// Test case - 5.
+		// Invalid duration.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}, "DurationSeconds": {"1h"}}, http.StatusBadRequest, 0, ErrNone},
+		// Test case - 6.
+		// Valid password, credentials valid for credentialExpiry
+		// limited to the policy of the group.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}}, http.StatusOK, 2 * time.Hour, ErrNone},
+		// Test case - 7.
+		// Valid password with duration.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}, "DurationSeconds": {"900"}}, http.StatusOK, 15 * time.Minute, ErrNone},
+		// Test case - 8.
+		// Session policy further limits the policy of the group.
+		{true, url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}, "Policy": {readOnlyPolicy}}, http.StatusOK, 2 * time.Hour, ErrAccessDenied},
+		// Test case - 9.
+		// Valid password of a user in no group with a policy.
+		{true, url.Values{"LDAPUsername": {"bob"}, "LDAPPassword": {"bob123"}}, http.StatusForbidden, 0, ErrNone},
+	}
+
+	for i, testCase := range testCases {
+		globalLDAPProvider = nil
+		if testCase.isEnabled {
+			initLDAPProvider()
+		}
+
+		req, err := newTestRequest(httpPOST, "/?Action="+stsAssumeRoleWithLDAPIdentity,
+			0, strings.NewReader(testCase.form.Encode()))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		req.ContentLength = int64(len(testCase.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		response := assumeRoleWithLDAPIdentityResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: Unable to parse response: <ERROR> %v", i+1, err)
+		}
+		creds := response.Result.Credentials
+		if d := creds.Expiration.Sub(UTCNow()); d <= testCase.expectedDuration-time.Minute || d > testCase.expectedDuration {
+			t.Errorf("Test %d: Expected credentials valid for %s, found %s", i+1, testCase.expectedDuration, d)
+		}
+
+		// Requests signed with temporary credentials are
+		// authenticated, but can't use admin APIs.
+		signedReq, err := newTestRequest(httpGET, "/", 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		signedReq.Header.Set(amzSecurityToken, creds.SessionToken)
+		if err = signRequestV4(signedReq, creds.AccessKeyID, creds.SecretAccessKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign HTTP request: <ERROR> %v", i+1, err)
+		}
+		if s3Err := isReqAuthenticated(signedReq, serverConfig.GetRegion()); s3Err != ErrNone {
+			t.Errorf("Test %d: Expected request signed with temporary credentials to be authenticated, found error code %d", i+1, s3Err)
+		}
+		if s3Err := checkAdminRequestAuthType(signedReq, serverConfig.GetRegion()); s3Err != ErrAccessDenied {
+			t.Errorf("Test %d: Expected admin request signed with temporary credentials to be denied, found error code %d", i+1, s3Err)
+		}
+		if s3Err := checkSessionPolicy(signedReq, "s3:PutObject", "/bucket/object"); s3Err != testCase.expectedPutErrCode {
+			t.Errorf("Test %d: Expected error code %d of PutObject in bucket, found %d", i+1, testCase.expectedPutErrCode, s3Err)
+		}
+		if s3Err := checkSessionPolicy(signedReq, "s3:GetObject", "/other/object"); s3Err != ErrAccessDenied {
+			t.Errorf("Test %d: Expected GetObject outside of bucket to be denied, found error code %d", i+1, s3Err)
+		}
+
+		presignedReq, err := newTestRequest(httpGET, "/?"+amzSecurityToken+"="+url.QueryEscape(creds.SessionToken), 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		if err = preSignV4(presignedReq, creds.AccessKeyID, creds.SecretAccessKey, 60); err != nil {
+			t.Fatalf("Test %d: Failed to presign HTTP request: <ERROR> %v", i+1, err)
+		}
+		if s3Err := isReqAuthenticated(presignedReq, serverConfig.GetRegion()); s3Err != ErrNone {
+			t.Errorf("Test %d: Expected request presigned with temporary credentials to be authenticated, found error code %d", i+1, s3Err)
+		}
+
+		// Session token is required.
+		signedReq, err = newTestSignedRequestV4(httpGET, "/", 0, nil, creds.AccessKeyID, creds.SecretAccessKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		if s3Err := isReqAuthenticated(signedReq, serverConfig.GetRegion()); s3Err != ErrInvalidAccessKeyID {
+			t.Errorf("Test %d: Expected error code %d without session token, found %d", i+1, ErrInvalidAccessKeyID, s3Err)
+		}
+	}
+}
+
+// Tests issuing temporary credentials to requests signed with server
+// credential.
+func TestAssumeRoleHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	mux := router.NewRouter()
+	registerSTSRouter(mux)
+
+	serverCred := serverConfig.GetCredential()
+	tempCred, sessionToken, _, err := newTemporaryCredential("minio", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	putPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`
+
+	testCases := []struct {
+		accessKey          string
+		secretKey          string
+		sessionToken       string
+		form               url.Values
+		expectedRespStatus int
+		expectedDuration   time.Duration
+	}{
+		// Test case - 1.
+		// Anonymous request.
+		{"", "", "", url.Values{}, http.StatusForbidden, 0},
+		// Test case - 2.
+		// Invalid secret key.
+		{serverCred.AccessKey, "invalid-secret", "", url.Values{}, http.StatusForbidden, 0},
+		// Test case - 3.
+		// Request signed with temporary credentials.
+		{tempCred.AccessKey, tempCred.SecretKey, sessionToken, url.Values{}, http.StatusForbidden, 0},
+		// Test case - 4.
+		// Duration longer than maximum.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"DurationSeconds": {"86400"}}, http.StatusBadRequest, 0},
+		// Test case - 5.
+		// Duration shorter than minimum.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"DurationSeconds": {"60"}}, http.StatusBadRequest, 0},
+		// Test case - 6.
+		// Credentials valid for default duration.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{}, http.StatusOK, defaultSTSDuration},
+		// Test case - 7.
+		// Credentials valid for requested duration.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"DurationSeconds": {"43200"}}, http.StatusOK, maxSTSDuration},
+		// Test case - 8.
+		// Malformed session policy.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"Policy": {`{"Version":`}}, http.StatusBadRequest, 0},
+		// Test case - 9.
+		// Session policy too large.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"Policy": {putPolicy + strings.Repeat(" ", maxSessionPolicySize)}}, http.StatusBadRequest, 0},
+		// Test case - 10.
+		// Credentials limited to session policy.
+		{serverCred.AccessKey, serverCred.SecretKey, "", url.Values{"Policy": {putPolicy}}, http.StatusOK, defaultSTSDuration},
+	}
+
+	for i, testCase := range testCases {
+		body := testCase.form.Encode()
+		req, err := newTestRequest(httpPOST, "/?Action="+stsAssumeRole, int64(len(body)), strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if testCase.sessionToken != "" {
+			req.Header.Set(amzSecurityToken, testCase.sessionToken)
+		}
+		if testCase.accessKey != "" {
+			if err = signRequestV4(req, testCase.accessKey, testCase.secretKey); err != nil {
+				t.Fatalf("Test %d: Failed to sign HTTP request: <ERROR> %v", i+1, err)
+			}
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		response := assumeRoleResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: Unable to parse response: <ERROR> %v", i+1, err)
+		}
+		creds := response.Result.Credentials
+		if d := creds.Expiration.Sub(UTCNow()); d <= testCase.expectedDuration-time.Minute || d > testCase.expectedDuration {
+			t.Errorf("Test %d: Expected credentials valid for %s, found %s", i+1, testCase.expectedDuration, d)
+		}
+
+		// Issued credentials authenticate requests.
+		signedReq, err := newTestRequest(httpGET, "/", 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		signedReq.Header.Set(amzSecurityToken, creds.SessionToken)
+		if err = signRequestV4(signedReq, creds.AccessKeyID, creds.SecretAccessKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign HTTP request: <ERROR> %v", i+1, err)
+		}
+		if s3Err := isReqAuthenticated(signedReq, serverConfig.GetRegion()); s3Err != ErrNone {
+			t.Errorf("Test %d: Expected request signed with temporary credentials to be authenticated, found error code %d", i+1, s3Err)
+		}
+
+		// Credentials are only allowed to read without session policy.
+		expectedErrCode := ErrAccessDenied
+		if testCase.form.Get("Policy") != "" {
+			expectedErrCode = ErrNone
+		}
+		if s3Err := checkSessionPolicy(signedReq, "s3:PutObject", "/bucket/object"); s3Err != expectedErrCode {
+			t.Errorf("Test %d: Expected error code %d for PutObject, found %d", i+1, expectedErrCode, s3Err)
+		}
+	}
+}
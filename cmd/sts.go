@@ -0,0 +1,271 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Bounds of the validity of temporary credentials.
+const (
+	minSTSDuration     = 15 * time.Minute
+	maxSTSDuration     = 12 * time.Hour
+	defaultSTSDuration = time.Hour
+)
+
+// Header and presigned query parameter carrying the session token of
+// temporary credentials.
+const amzSecurityToken = "X-Amz-Security-Token"
+
+// Maximum size of the session policy of temporary credentials.
+const maxSessionPolicySize = 2048
+
+// stsClaims - claims of the session token of temporary credentials.
+type stsClaims struct {
+	AccessKey string `json:"accessKey"`
+	// Session policies limiting what the temporary credentials are
+	// allowed to do, all of them must allow a request. Only
+	// readOnlySessionPolicy applies when empty.
+	Policies []string `json:"policies,omitempty"`
+	jwtgo.StandardClaims
+}
+
+// sessionPolicyActions - actions allowed in session policies, those of
+// bucket policies and actions of requests only signed requests can
+// send.
+var sessionPolicyActions = supportedActionMap.Union(set.CreateStringSet("s3:ListAllMyBuckets",
+	"s3:GetObjectRetention", "s3:PutObjectRetention", "s3:GetObjectLegalHold", "s3:PutObjectLegalHold"))
+
+// readOnlySessionPolicy - session policy of temporary credentials
+// issued without one, only allows reading buckets and objects.
+var readOnlySessionPolicy = bucketPolicy{
+	Version: "2012-10-17",
+	Statements: []policyStatement{{
+		Actions: set.CreateStringSet("s3:ListAllMyBuckets", "s3:GetBucketLocation",
+			"s3:ListBucket", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts",
+			"s3:GetObject", "s3:GetObjectTagging", "s3:GetObjectRetention", "s3:GetObjectLegalHold"),
+		Effect:    "Allow",
+		Resources: set.CreateStringSet(bucketARNPrefix + "*"),
+	}},
+}
+
+// parseSessionPolicy - parses and validates a session policy, a bucket
+// policy without principals and conditions. Deny statements are moved
+// first like in bucket policies.
+func parseSessionPolicy(policyStr string) (*bucketPolicy, error) {
+	policy := &bucketPolicy{}
+	if err := json.Unmarshal([]byte(policyStr), policy); err != nil {
+		return nil, err
+	}
+	if policy.Version == "" {
+		return nil, errors.New("Policy version cannot be empty")
+	}
+	if len(policy.Statements) == 0 {
+		return nil, errors.New("Policy statement cannot be empty")
+	}
+
+	var denyStatements, allowStatements []policyStatement
+	for _, statement := range policy.Statements {
+		if statement.Principal != nil || len(statement.Conditions) != 0 {
+			return nil, errors.New("Session policy can't have principals or conditions")
+		}
+		if err := isValidEffect(statement.Effect); err != nil {
+			return nil, err
+		}
+		if len(statement.Actions) == 0 {
+			return nil, errors.New("Action list cannot be empty")
+		}
+		if unsupportedActions := statement.Actions.Difference(sessionPolicyActions); !unsupportedActions.IsEmpty() {
+			return nil, fmt.Errorf("Unsupported actions found: %v", unsupportedActions.ToSlice())
+		}
+		if err := isValidResources(statement.Resources); err != nil {
+			return nil, err
+		}
+		if statement.Effect == "Deny" {
+			denyStatements = append(denyStatements, statement)
+		} else {
+			allowStatements = append(allowStatements, statement)
+		}
+	}
+	policy.Statements = append(denyStatements, allowStatements...)
+	return policy, nil
+}
+
+// getSTSKey - returns an HMAC-SHA256 of data keyed with the server
+// secret key. Temporary credentials need no state on the server, any
+// server can verify them, they are valid until server credential
+// changes or they expire.
+func getSTSKey(data string) []byte {
+	mac := hmac.New(sha256.New, []byte(serverConfig.GetCredential().SecretKey))
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// getSTSSigningKey - returns the key session tokens are signed with,
+// distinct from the server secret key so that session tokens are
+// never accepted as browser or inter-node tokens.
+func getSTSSigningKey() []byte {
+	return getSTSKey("minio-sts-session-token")
+}
+
+// getTemporarySecretKey - returns the secret key of temporary
+// credentials derived from their access key.
+func getTemporarySecretKey(accessKey string) string {
+	return base64.RawURLEncoding.EncodeToString(getSTSKey(accessKey))[:secretKeyMaxLenAmazon]
+}
+
+// newTemporaryCredential - returns new temporary credentials of a
+// user valid for the given duration, along with their session token.
+// They are limited to the session policies, read-only without any.
+func newTemporaryCredential(username string, policies []string, duration time.Duration) (cred credential, sessionToken string, expiration time.Time, err error) {
+	keyBytes := make([]byte, accessKeyMaxLen)
+	if _, err = rand.Read(keyBytes); err != nil {
+		return cred, "", expiration, err
+	}
+	for i := range keyBytes {
+		keyBytes[i] = alphaNumericTable[keyBytes[i]%alphaNumericTableLen]
+	}
+	cred.AccessKey = string(keyBytes)
+	cred.SecretKey = getTemporarySecretKey(cred.AccessKey)
+
+	utcNow := UTCNow()
+	expiration = utcNow.Add(duration)
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, stsClaims{
+		AccessKey: cred.AccessKey,
+		Policies:  policies,
+		StandardClaims: jwtgo.StandardClaims{
+			ExpiresAt: expiration.Unix(),
+			IssuedAt:  utcNow.Unix(),
+			Subject:   username,
+		},
+	})
+	sessionToken, err = token.SignedString(getSTSSigningKey())
+	return cred, sessionToken, expiration, err
+}
+
+// stsKeyFunc - returns the key to verify session tokens with.
+func stsKeyFunc(jwtToken *jwtgo.Token) (interface{}, error) {
+	if _, ok := jwtToken.Method.(*jwtgo.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("Unexpected signing method: %v", jwtToken.Header["alg"])
+	}
+	return getSTSSigningKey(), nil
+}
+
+// parseSessionToken - verifies a session token and returns its claims.
+func parseSessionToken(sessionToken string) (stsClaims, APIErrorCode) {
+	claims := stsClaims{}
+	if _, err := jwtgo.ParseWithClaims(sessionToken, &claims, stsKeyFunc); err != nil {
+		if verr, ok := err.(*jwtgo.ValidationError); ok && verr.Errors == jwtgo.ValidationErrorExpired {
+			return claims, ErrExpiredToken
+		}
+		return claims, ErrInvalidToken
+	}
+	return claims, ErrNone
+}
+
+// getRequestCredential - returns the credential a request signed with
+// the access key should be verified with, the server credential or
+// temporary credentials of the session token.
+func getRequestCredential(accessKey, sessionToken string) (credential, APIErrorCode) {
+	serverCred := serverConfig.GetCredential()
+	if accessKey == serverCred.AccessKey {
+		return serverCred, ErrNone
+	}
+	if sessionToken == "" {
+		return credential{}, ErrInvalidAccessKeyID
+	}
+
+	claims, s3Err := parseSessionToken(sessionToken)
+	if s3Err != ErrNone {
+		return credential{}, s3Err
+	}
+	if claims.AccessKey != accessKey {
+		return credential{}, ErrInvalidAccessKeyID
+	}
+	return credential{
+		AccessKey: accessKey,
+		SecretKey: getTemporarySecretKey(accessKey),
+	}, ErrNone
+}
+
+// isRequestTemporaryCredential - returns true if request is signed
+// with temporary credentials, not with the server credential.
+func isRequestTemporaryCredential(r *http.Request) bool {
+	accessKey := getRequestAccessKey(r)
+	return accessKey != "" && accessKey != serverConfig.GetCredential().AccessKey
+}
+
+// checkSessionPolicy - checks that the session policies of temporary
+// credentials a request is signed with allow action on resource, a
+// path like "/bucket/object". Requests without an action, bucket
+// configuration and service APIs, need policies allowing all actions
+// on all buckets without any Deny statement. Requests signed with the
+// server credential are always allowed.
+func checkSessionPolicy(r *http.Request, action, resource string) APIErrorCode {
+	if !isRequestTemporaryCredential(r) {
+		return ErrNone
+	}
+	sessionToken := r.Header.Get(amzSecurityToken)
+	if isRequestPresignedSignatureV4(r) {
+		sessionToken = r.URL.Query().Get(amzSecurityToken)
+	}
+	claims, s3Err := parseSessionToken(sessionToken)
+	if s3Err != ErrNone {
+		return s3Err
+	}
+
+	policies := []*bucketPolicy{&readOnlySessionPolicy}
+	if len(claims.Policies) != 0 {
+		policies = nil
+		for _, policyStr := range claims.Policies {
+			policy, err := parseSessionPolicy(policyStr)
+			if err != nil {
+				return ErrInvalidToken
+			}
+			policies = append(policies, policy)
+		}
+	}
+
+	allActions := action == ""
+	if allActions {
+		action, resource = "s3:*", "*"
+	}
+	arn := bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(resource, "/"), "/")
+	for _, policy := range policies {
+		for _, statement := range policy.Statements {
+			if allActions && statement.Effect == "Deny" {
+				return ErrAccessDenied
+			}
+		}
+		if !bucketPolicyEvalStatements(action, arn, nil, policy.Statements) {
+			return ErrAccessDenied
+		}
+	}
+	return ErrNone
+}
@@ -0,0 +1,258 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// Tests verification of temporary credentials.
+func TestGetRequestCredential(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	serverCred := serverConfig.GetCredential()
+	tempCred, sessionToken, expiration, err := newTemporaryCredential("alice", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tempCred.IsValid() || tempCred.AccessKey == serverCred.AccessKey {
+		t.Fatalf("Invalid temporary credential %#v", tempCred)
+	}
+	if d := expiration.Sub(UTCNow()); d <= 59*time.Minute || d > time.Hour {
+		t.Fatalf("Expected temporary credential valid for an hour, found %s", d)
+	}
+
+	newToken := func(accessKey string, expiresAt time.Time, key []byte) string {
+		token, terr := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, stsClaims{
+			AccessKey:      accessKey,
+			StandardClaims: jwtgo.StandardClaims{ExpiresAt: expiresAt.Unix()},
+		}).SignedString(key)
+		if terr != nil {
+			t.Fatal(terr)
+		}
+		return token
+	}
+	expiredToken := newToken(tempCred.AccessKey, UTCNow().Add(-time.Minute), getSTSSigningKey())
+	serverKeyToken := newToken(tempCred.AccessKey, UTCNow().Add(time.Hour), []byte(serverCred.SecretKey))
+	webToken, err := authenticateWeb(serverCred.AccessKey, serverCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		accessKey       string
+		sessionToken    string
+		expectedCred    credential
+		expectedErrCode APIErrorCode
+	}{
+		// Test case - 1.
+		// Server credential needs no session token.
+		{serverCred.AccessKey, "", serverCred, ErrNone},
+		// Test case - 2.
+		// Temporary credential with its session token.
+		{tempCred.AccessKey, sessionToken, tempCred, ErrNone},
+		// Test case - 3.
+		// Temporary credential without session token.
+		{tempCred.AccessKey, "", credential{}, ErrInvalidAccessKeyID},
+		// Test case - 4.
+		// Session token of another access key.
+		{"ABCDEFGHIJKLMNOPQRST", sessionToken, credential{}, ErrInvalidAccessKeyID},
+		// Test case - 5.
+		// Expired session token.
+		{tempCred.AccessKey, expiredToken, credential{}, ErrExpiredToken},
+		// Test case - 6.
+		// Malformed session token.
+		{tempCred.AccessKey, "token", credential{}, ErrInvalidToken},
+		// Test case - 7.
+		// Session token signed with the server secret key.
+		{tempCred.AccessKey, serverKeyToken, credential{}, ErrInvalidToken},
+		// Test case - 8.
+		// Browser token is no session token.
+		{tempCred.AccessKey, webToken, credential{}, ErrInvalidToken},
+	}
+
+	for i, testCase := range testCases {
+		cred, errCode := getRequestCredential(testCase.accessKey, testCase.sessionToken)
+		if errCode != testCase.expectedErrCode {
+			t.Errorf("Test %d: Expected error code %d, found %d", i+1, testCase.expectedErrCode, errCode)
+			continue
+		}
+		if cred.AccessKey != testCase.expectedCred.AccessKey || cred.SecretKey != testCase.expectedCred.SecretKey {
+			t.Errorf("Test %d: Expected credential %s, found %s", i+1, testCase.expectedCred.AccessKey, cred.AccessKey)
+		}
+	}
+
+	// Session token is not accepted as browser token.
+	if isAuthTokenValid(sessionToken) {
+		t.Error("Expected session token to be rejected as browser token")
+	}
+
+	// Temporary credentials are revoked when server credential changes.
+	serverConfig.SetCredential(mustGetNewCredential())
+	if _, errCode := getRequestCredential(tempCred.AccessKey, sessionToken); errCode != ErrInvalidToken {
+		t.Errorf("Expected error code %d after credential change, found %d", ErrInvalidToken, errCode)
+	}
+}
+
+// Tests parsing of session policies.
+func TestParseSessionPolicy(t *testing.T) {
+	testCases := []struct {
+		policy     string
+		shouldPass bool
+	}{
+		// Test case - 1.
+		// Allow and Deny statements.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]},{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"arn:aws:s3:::bucket/*"}]}`, true},
+		// Test case - 2.
+		// Actions only signed requests send.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:ListAllMyBuckets","s3:PutObjectRetention"],"Resource":["arn:aws:s3:::*"]}]}`, true},
+		// Test case - 3.
+		// Malformed JSON.
+		{`{"Version":"2012-10-17","Statement":[`, false},
+		// Test case - 4.
+		// Version is missing.
+		{`{"Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`, false},
+		// Test case - 5.
+		// Statements are missing.
+		{`{"Version":"2012-10-17"}`, false},
+		// Test case - 6.
+		// Principals are not allowed.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`, false},
+		// Test case - 7.
+		// Conditions are not allowed.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:ListBucket"],"Resource":["arn:aws:s3:::bucket"],"Condition":{"StringEquals":{"s3:prefix":["logs"]}}}]}`, false},
+		// Test case - 8.
+		// Unsupported action.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:PutBucketPolicy"],"Resource":["arn:aws:s3:::*"]}]}`, false},
+		// Test case - 9.
+		// Invalid resource.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["*"]}]}`, false},
+		// Test case - 10.
+		// Invalid effect.
+		{`{"Version":"2012-10-17","Statement":[{"Effect":"Permit","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`, false},
+	}
+
+	for i, testCase := range testCases {
+		policy, err := parseSessionPolicy(testCase.policy)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected to pass, failed with %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected to fail", i+1)
+		}
+		if err == nil && len(policy.Statements) > 1 && policy.Statements[0].Effect != "Deny" {
+			t.Errorf("Test %d: Expected Deny statements first", i+1)
+		}
+	}
+}
+
+// Tests that requests signed with temporary credentials are limited
+// to their session policy.
+func TestCheckSessionPolicy(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+
+	newRequest := func(policies ...string) *http.Request {
+		cred, sessionToken, _, err := newTemporaryCredential("alice", policies, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := newTestRequest(httpGET, "/bucket/object", 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(amzSecurityToken, sessionToken)
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	serverCred := serverConfig.GetCredential()
+	serverReq, err := newTestSignedRequestV4(httpGET, "/bucket/object", 0, nil, serverCred.AccessKey, serverCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnlyReq := newRequest()
+	bucketReq := newRequest(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::bucket/*"]}]}`)
+	fullReq := newRequest(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`)
+	denyReq := newRequest(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]},{"Effect":"Deny","Action":["s3:DeleteObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`)
+	bothReq := newRequest(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::bucket/*"]}]}`,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::*"]}]}`)
+
+	testCases := []struct {
+		req             *http.Request
+		action          string
+		resource        string
+		expectedErrCode APIErrorCode
+	}{
+		// Test case - 1.
+		// Server credential is allowed everything.
+		{serverReq, "", "", ErrNone},
+		// Test case - 2.
+		// Temporary credentials without policy only read.
+		{readOnlyReq, "s3:GetObject", "/bucket/object", ErrNone},
+		// Test case - 3.
+		{readOnlyReq, "s3:ListAllMyBuckets", "/", ErrNone},
+		// Test case - 4.
+		{readOnlyReq, "s3:PutObject", "/bucket/object", ErrAccessDenied},
+		// Test case - 5.
+		{readOnlyReq, "", "/bucket", ErrAccessDenied},
+		// Test case - 6.
+		// Policy limited to objects of a bucket.
+		{bucketReq, "s3:PutObject", "/bucket/object", ErrNone},
+		// Test case - 7.
+		{bucketReq, "s3:PutObject", "/other/object", ErrAccessDenied},
+		// Test case - 8.
+		{bucketReq, "s3:ListAllMyBuckets", "/", ErrAccessDenied},
+		// Test case - 9.
+		{bucketReq, "", "/bucket", ErrAccessDenied},
+		// Test case - 10.
+		// Requests without action need all actions on all buckets.
+		{fullReq, "", "/bucket", ErrNone},
+		// Test case - 11.
+		// Deny statements are enforced first.
+		{denyReq, "s3:DeleteObject", "/bucket/object", ErrAccessDenied},
+		// Test case - 12.
+		{denyReq, "s3:DeleteObject", "/other/object", ErrNone},
+		// Test case - 13.
+		{denyReq, "", "/bucket", ErrAccessDenied},
+		// Test case - 14.
+		// All policies must allow the request.
+		{bothReq, "s3:GetObject", "/bucket/object", ErrNone},
+		// Test case - 15.
+		{bothReq, "s3:PutObject", "/bucket/object", ErrAccessDenied},
+		// Test case - 16.
+		{bothReq, "s3:GetObject", "/other/object", ErrAccessDenied},
+	}
+
+	for i, testCase := range testCases {
+		if errCode := checkSessionPolicy(testCase.req, testCase.action, testCase.resource); errCode != testCase.expectedErrCode {
+			t.Errorf("Test %d: Expected error code %d, found %d", i+1, testCase.expectedErrCode, errCode)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Swift TempURL compatible URLs look like
+//
+//	/v1/AUTH_account/container/object?temp_url_sig=<sig>&temp_url_expires=<unix-time>
+//
+// where signature is hex encoded HMAC of "METHOD\nEXPIRES\nPATH"
+// computed with the account's TempURL key. Container maps to a
+// bucket and object maps to an object of the same name.
+const (
+	swiftTempURLPrefix       = "/v1"
+	swiftTempURLSigQuery     = "temp_url_sig"
+	swiftTempURLExpiresQuery = "temp_url_expires"
+	swiftTempURLFilename     = "filename"
+)
+
+// swiftTempURLHandlers implements http handlers for Swift TempURL access.
+type swiftTempURLHandlers struct {
+	ObjectAPI func() ObjectLayer
+	Key       string
+}
+
+// registerSwiftTempURLRouter - registers Swift TempURL compatible
+// object download routes, must be registered before the API router.
+func registerSwiftTempURLRouter(mux *router.Router, key string) {
+	api := swiftTempURLHandlers{
+		ObjectAPI: newObjectLayerFn,
+		Key:       key,
+	}
+
+	swiftRouter := mux.NewRoute().PathPrefix(swiftTempURLPrefix).Subrouter()
+	for _, method := range []string{httpGET, httpHEAD} {
+		swiftRouter.Methods(method).Path("/{account}/{bucket}/{object:.+}").
+			Queries(swiftTempURLSigQuery, "{sig:.*}", swiftTempURLExpiresQuery, "{expires:.*}").
+			HandlerFunc(api.GetObjectHandler)
+	}
+}
+
+// getSwiftTempURLSignature - computes Swift TempURL signature, hash
+// function is chosen by the caller based on the length of signature.
+func getSwiftTempURLSignature(newHash func() hash.Hash, key, method, expires, urlPath string) string {
+	mac := hmac.New(newHash, []byte(key))
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%s", method, expires, urlPath)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// isValidSwiftTempURL - validates TempURL signature and expiry of the
+// incoming request. HMAC-SHA1 and HMAC-SHA256 signatures are supported,
+// a signature generated for GET is also valid for HEAD.
+func isValidSwiftTempURL(r *http.Request, key string, now time.Time) bool {
+	query := r.URL.Query()
+	sig := query.Get(swiftTempURLSigQuery)
+	expires := query.Get(swiftTempURLExpiresQuery)
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch len(sig) {
+	case sha1.Size * 2:
+		newHash = sha1.New
+	case sha256.Size * 2:
+		newHash = sha256.New
+	default:
+		return false
+	}
+
+	methods := []string{r.Method}
+	if r.Method == httpHEAD {
+		methods = append(methods, httpGET)
+	}
+	for _, method := range methods {
+		expectedSig := getSwiftTempURLSignature(newHash, key, method, expires, r.URL.Path)
+		if hmac.Equal([]byte(sig), []byte(expectedSig)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSwiftErrorResponse - writes plain text error response similar
+// to Swift proxy server.
+func writeSwiftErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	if r.Method != httpHEAD {
+		w.Write([]byte(message))
+	}
+}
+
+// GetObjectHandler - GET/HEAD /v1/{account}/{bucket}/{object}
+// ----------
+// Serves object data for a valid Swift TempURL.
+func (api swiftTempURLHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
+	vars := router.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeSwiftErrorResponse(w, r, http.StatusServiceUnavailable, "503 Service Unavailable")
+		return
+	}
+
+	if !isValidSwiftTempURL(r, api.Key, UTCNow()) {
+		writeSwiftErrorResponse(w, r, http.StatusUnauthorized, "401 Unauthorized: Temp URL invalid")
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewRequestNSLock(r, bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		switch toAPIErrorCode(err) {
+		case ErrNoSuchBucket, ErrNoSuchKey, ErrInvalidBucketName, ErrInvalidObjectName:
+			writeSwiftErrorResponse(w, r, http.StatusNotFound, "404 Not Found")
+		default:
+			errorIf(err, "Unable to fetch object info.")
+			writeSwiftErrorResponse(w, r, http.StatusInternalServerError, "500 Internal Server Error")
+		}
+		return
+	}
+
+	// Get request range.
+	var hrange *httpRange
+	if rangeHeader := getRangeHeader(r, objInfo); rangeHeader != "" && r.Method == httpGET {
+		if hrange, err = parseRequestRange(rangeHeader, objInfo.Size); err == errInvalidRange {
+			writeSwiftErrorResponse(w, r, http.StatusRequestedRangeNotSatisfiable, "416 Requested Range Not Satisfiable")
+			return
+		}
+	}
+
+	if filename := r.URL.Query().Get(swiftTempURLFilename); filename != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	}
+
+	// Set standard object headers, writes 206 for range requests.
+	setObjectHeaders(w, objInfo, hrange)
+	if r.Method == httpHEAD {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var startOffset int64
+	length := objInfo.Size
+	if hrange != nil {
+		startOffset = hrange.offsetBegin
+		length = hrange.getLength()
+	}
+
+	// Headers are already written, no need to write error response.
+	if err = objectAPI.GetObject(bucket, object, startOffset, length, w); err != nil {
+		errorIf(err, "Unable to write to client.")
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests Swift TempURL signature and expiry validation.
+func TestIsValidSwiftTempURL(t *testing.T) {
+	key := "mykey"
+	now := time.Unix(1500000000, 0)
+	urlPath := "/v1/AUTH_account/bucket/object"
+	expires := fmt.Sprintf("%d", now.Add(time.Hour).Unix())
+	expired := fmt.Sprintf("%d", now.Add(-time.Hour).Unix())
+
+	getSig := getSwiftTempURLSignature(sha1.New, key, httpGET, expires, urlPath)
+	testCases := []struct {
+		method  string
+		sig     string
+		expires string
+		valid   bool
+	}{
+		// Valid HMAC-SHA1 signature.
+		{httpGET, getSig, expires, true},
+		// Valid HMAC-SHA256 signature.
+		{httpGET, getSwiftTempURLSignature(sha256.New, key, httpGET, expires, urlPath), expires, true},
+		// GET signature is valid for HEAD.
+		{httpHEAD, getSig, expires, true},
+		// GET signature is not valid for PUT.
+		{httpPUT, getSig, expires, false},
+		// Expired URL.
+		{httpGET, getSwiftTempURLSignature(sha1.New, key, httpGET, expired, urlPath), expired, false},
+		// Signature with a different key.
+		{httpGET, getSwiftTempURLSignature(sha1.New, "otherkey", httpGET, expires, urlPath), expires, false},
+		// Invalid expiry.
+		{httpGET, getSig, "abc", false},
+		// Signature of unsupported length.
+		{httpGET, "abcd", expires, false},
+	}
+
+	for i, testCase := range testCases {
+		reqURL := fmt.Sprintf("http://localhost:9000%s?temp_url_sig=%s&temp_url_expires=%s", urlPath, testCase.sig, testCase.expires)
+		r, err := http.NewRequest(testCase.method, reqURL, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if valid := isValidSwiftTempURL(r, key, now); valid != testCase.valid {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.valid, valid)
+		}
+	}
+}
+
+// Tests downloading objects with Swift TempURLs.
+func TestSwiftTempURLGetObjectHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testSwiftTempURLGetObjectHandler)
+}
+
+func testSwiftTempURLGetObjectHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	initNSLock(false)
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	bucket, object := "swift-bucket", "dir/object"
+	data := []byte("hello, swift")
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	key := "mykey"
+	mux := router.NewRouter().SkipClean(true)
+	registerSwiftTempURLRouter(mux, key)
+
+	expires := fmt.Sprintf("%d", UTCNow().Add(time.Hour).Unix())
+	testCases := []struct {
+		method     string
+		urlPath    string
+		sigKey     string
+		rangeHdr   string
+		statusCode int
+		body       []byte
+	}{
+		// Valid download.
+		{httpGET, "/v1/AUTH_test/" + bucket + "/" + object, key, "", http.StatusOK, data},
+		// Valid range download.
+		{httpGET, "/v1/AUTH_test/" + bucket + "/" + object, key, "bytes=0-4", http.StatusPartialContent, data[:5]},
+		// Valid HEAD request.
+		{httpHEAD, "/v1/AUTH_test/" + bucket + "/" + object, key, "", http.StatusOK, nil},
+		// Invalid signature.
+		{httpGET, "/v1/AUTH_test/" + bucket + "/" + object, "badkey", "", http.StatusUnauthorized, nil},
+		// Object not found.
+		{httpGET, "/v1/AUTH_test/" + bucket + "/nosuchobject", key, "", http.StatusNotFound, nil},
+	}
+
+	for i, testCase := range testCases {
+		sig := getSwiftTempURLSignature(sha1.New, testCase.sigKey, httpGET, expires, testCase.urlPath)
+		reqURL := fmt.Sprintf("http://localhost:9000%s?temp_url_sig=%s&temp_url_expires=%s", testCase.urlPath, sig, expires)
+		r, err := http.NewRequest(testCase.method, reqURL, nil)
+		if err != nil {
+			t.Fatalf("%s: Test %d: %s", instanceType, i+1, err)
+		}
+		if testCase.rangeHdr != "" {
+			r.Header.Set("Range", testCase.rangeHdr)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("%s: Test %d: expected %d, got %d", instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+		if testCase.body != nil {
+			body, _ := ioutil.ReadAll(rec.Body)
+			if !bytes.Equal(body, testCase.body) {
+				t.Fatalf("%s: Test %d: expected %s, got %s", instanceType, i+1, testCase.body, body)
+			}
+		}
+	}
+}
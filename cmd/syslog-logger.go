@@ -0,0 +1,237 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Syslog target sends log stream entries formatted as per RFC5424 to
+// a syslog server over UDP, TCP or unix socket. Entry fields such as
+// bucket, object, api and requestID are sent as structured data.
+const (
+	// Application name sent in every syslog message.
+	syslogAppName = "minio"
+
+	// Structured data ID of entry fields, 32473 is the private
+	// enterprise number reserved for documentation by RFC5612.
+	syslogStructuredDataID = "minio@32473"
+
+	// Default facility when none is configured.
+	syslogDefaultFacility = "local0"
+
+	// Timeout to connect to syslog server.
+	syslogDialTimeout = 5 * time.Second
+)
+
+// Supported syslog facilities and their numerical codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// validateSyslogConfig - validates syslog target configuration of a
+// log stream.
+func validateSyslogConfig(network, address, facility string) error {
+	switch network {
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return fmt.Errorf("Unknown syslog network ‘%s’", network)
+	}
+	if address == "" {
+		return fmt.Errorf("Missing syslog address")
+	}
+	if _, ok := syslogFacilities[facility]; facility != "" && !ok {
+		return fmt.Errorf("Unknown syslog facility ‘%s’", facility)
+	}
+	return nil
+}
+
+// getSyslogSeverity - maps log level to syslog severity.
+func getSyslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		// Emergency
+		return 0
+	case logrus.FatalLevel:
+		// Critical
+		return 2
+	case logrus.ErrorLevel:
+		// Error
+		return 3
+	case logrus.WarnLevel:
+		// Warning
+		return 4
+	case logrus.InfoLevel:
+		// Informational
+		return 6
+	default:
+		// Debug
+		return 7
+	}
+}
+
+// syslogFormatter - formats log entries as RFC5424 syslog messages.
+type syslogFormatter struct {
+	facility int
+	hostname string
+	procID   string
+}
+
+// newSyslogFormatter - returns a new syslog formatter for facility.
+func newSyslogFormatter(facility string) *syslogFormatter {
+	if facility == "" {
+		facility = syslogDefaultFacility
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogFormatter{
+		facility: syslogFacilities[facility],
+		hostname: hostname,
+		procID:   fmt.Sprintf("%d", os.Getpid()),
+	}
+}
+
+// escapeSyslogParamValue - escapes '"', '\' and ']' in structured
+// data parameter values as required by RFC5424.
+func escapeSyslogParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// Format - formats entry as
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID PARAMS...] MSG
+//
+// where message ID is the log stream name of the entry.
+func (f *syslogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s %s ",
+		f.facility*8+getSyslogSeverity(entry.Level),
+		entry.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		f.hostname, syslogAppName, f.procID, getEntryStream(entry))
+
+	var keys []string
+	for key := range entry.Data {
+		if key != logStreamField {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		buf.WriteString("-")
+	} else {
+		buf.WriteString("[" + syslogStructuredDataID)
+		for _, key := range keys {
+			fmt.Fprintf(buf, ` %s="%s"`, key, escapeSyslogParamValue(fmt.Sprint(entry.Data[key])))
+		}
+		buf.WriteString("]")
+	}
+
+	if entry.Message != "" {
+		buf.WriteString(" " + entry.Message)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// syslogWriter - writes syslog messages to a syslog server, connects
+// lazily and reconnects once on write failures.
+type syslogWriter struct {
+	sync.Mutex
+	network string
+	address string
+	conn    net.Conn
+}
+
+// newSyslogWriter - returns a new syslog writer.
+func newSyslogWriter(network, address string) *syslogWriter {
+	return &syslogWriter{
+		network: network,
+		address: address,
+	}
+}
+
+// isStream - returns if messages need framing on this network.
+func (w *syslogWriter) isStream() bool {
+	return w.network == "tcp" || w.network == "unix"
+}
+
+// write - writes a single message to the current connection, stream
+// connections use octet counting framing as per RFC6587.
+func (w *syslogWriter) write(msg []byte) (err error) {
+	if w.conn == nil {
+		if w.conn, err = net.DialTimeout(w.network, w.address, syslogDialTimeout); err != nil {
+			return err
+		}
+	}
+
+	if w.isStream() {
+		_, err = fmt.Fprintf(w.conn, "%d %s", len(msg), msg)
+	} else {
+		_, err = w.conn.Write(msg)
+	}
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+// Write - writes a syslog message, trailing newline is not sent.
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	msg := bytes.TrimSuffix(p, []byte("\n"))
+
+	w.Lock()
+	defer w.Unlock()
+	if err := w.write(msg); err != nil {
+		// Retry once on a new connection.
+		if err = w.write(msg); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,226 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Tests validating syslog target configuration.
+func TestValidateSyslogConfig(t *testing.T) {
+	testCases := []struct {
+		network, address, facility string
+		shouldPass                 bool
+	}{
+		{"udp", "localhost:514", "", true},
+		{"tcp", "localhost:514", "daemon", true},
+		{"unix", "/dev/log", "local7", true},
+		{"unixgram", "/dev/log", "user", true},
+		{"", "localhost:514", "", false},
+		{"http", "localhost:514", "", false},
+		{"udp", "", "", false},
+		{"udp", "localhost:514", "local8", false},
+	}
+
+	for i, testCase := range testCases {
+		err := validateSyslogConfig(testCase.network, testCase.address, testCase.facility)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+
+	// Syslog target is validated as part of log streams.
+	streams := logStreams{logStreamAudit: {Target: logStreamTargetSyslog, Network: "udp"}}
+	if err := streams.Validate(); err == nil {
+		t.Error("Expected syslog stream without address to fail")
+	}
+}
+
+// Tests RFC5424 formatting of log entries.
+func TestSyslogFormatter(t *testing.T) {
+	f := &syslogFormatter{facility: 16, hostname: "node1", procID: "42"}
+	entryTime := time.Date(2017, 3, 4, 5, 6, 7, 8000, time.UTC)
+
+	testCases := []struct {
+		entry    *logrus.Entry
+		expected string
+	}{
+		// Error without fields.
+		{
+			&logrus.Entry{Time: entryTime, Level: logrus.ErrorLevel, Message: "Disk full", Data: logrus.Fields{}},
+			"<131>1 2017-03-04T05:06:07.000008Z node1 minio 42 warning - Disk full\n",
+		},
+		// Audit entry with structured data.
+		{
+			&logrus.Entry{Time: entryTime, Level: logrus.InfoLevel, Message: "GET /bucket/object", Data: logrus.Fields{
+				logStreamField: logStreamAudit,
+				"api":          "GET",
+				"bucket":       "bucket",
+				"object":       "object",
+				"requestID":    "3L137",
+			}},
+			`<134>1 2017-03-04T05:06:07.000008Z node1 minio 42 audit [minio@32473 api="GET" bucket="bucket" object="object" requestID="3L137"] GET /bucket/object` + "\n",
+		},
+		// Debug entry with escaped values.
+		{
+			&logrus.Entry{Time: entryTime, Level: logrus.DebugLevel, Data: logrus.Fields{"object": `a"b\c]`}},
+			`<135>1 2017-03-04T05:06:07.000008Z node1 minio 42 debug [minio@32473 object="a\"b\\c\]"]` + "\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		msg, err := f.Format(testCase.entry)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		if string(msg) != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, string(msg))
+		}
+	}
+
+	if f = newSyslogFormatter(""); f.facility != syslogFacilities[syslogDefaultFacility] {
+		t.Errorf("Expected default facility %d, got %d", syslogFacilities[syslogDefaultFacility], f.facility)
+	}
+}
+
+// Tests sending syslog messages over UDP.
+func TestSyslogWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w := newSyslogWriter("udp", conn.LocalAddr().String())
+	if _, err = w.Write([]byte("<134>1 - - minio - audit - hello\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(buf[:n]); msg != "<134>1 - - minio - audit - hello" {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
+
+// Tests sending syslog messages over TCP with octet counting framing
+// and reconnecting after the connection breaks.
+func TestSyslogWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	msgCh := make(chan string)
+	go func() {
+		for {
+			conn, aerr := listener.Accept()
+			if aerr != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					var length int
+					if _, rerr := fmt.Fscanf(reader, "%d ", &length); rerr != nil {
+						return
+					}
+					msg := make([]byte, length)
+					if _, rerr := io.ReadFull(reader, msg); rerr != nil {
+						return
+					}
+					msgCh <- string(msg)
+				}
+			}(conn)
+		}
+	}()
+
+	w := newSyslogWriter("tcp", listener.Addr().String())
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			// Break the connection, writer has to reconnect.
+			w.conn.Close()
+		}
+		if _, err = w.Write([]byte(fmt.Sprintf("message %d\n", i))); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case msg := <-msgCh:
+			if msg != fmt.Sprintf("message %d", i) {
+				t.Errorf("Unexpected message %q", msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for syslog message")
+		}
+	}
+
+	// Unreachable server fails to write.
+	w = newSyslogWriter("unix", "/nonexistent/minio-syslog.sock")
+	if _, err = w.Write([]byte("message")); err == nil {
+		t.Error("Expected write to unreachable server to fail")
+	}
+}
+
+// Tests startup stream text is sent as syslog entries.
+func TestLogStreamSyslogPrint(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := newLogStream(logStreamStartup, logStreamConfig{
+		Target:  logStreamTargetSyslog,
+		Network: "udp",
+		Address: conn.LocalAddr().String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty lines are not sent.
+	stream.Print("\n")
+	stream.Print("Endpoint: http://127.0.0.1:9000\n")
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<134>1 ") || !strings.HasSuffix(msg, " startup - Endpoint: http://127.0.0.1:9000") {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
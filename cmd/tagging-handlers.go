@@ -0,0 +1,316 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	mux "github.com/gorilla/mux"
+)
+
+// readTaggingBody - reads tagging XML from request body.
+func readTaggingBody(r *http.Request) ([]byte, APIErrorCode) {
+	// Tagging always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxTaggingBodySize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIf(err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+	return buffer.Bytes(), ErrNone
+}
+
+// updateObjectTagging - replaces tags saved in object metadata, all
+// tags are removed for an empty tag set.
+func updateObjectTagging(r *http.Request, bucket, object string, t tagging, objAPI ObjectLayer, nsMutex *nsLockMap) error {
+	// Hold write lock on the object, metadata is replaced.
+	objectLock := nsMutex.NewRequestNSLock(r, bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined)+2)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	// Preserve ETag of the object.
+	metadata["md5Sum"] = objInfo.MD5Sum
+	if len(t.TagSet) == 0 {
+		delete(metadata, objectTaggingKey)
+	} else {
+		metadata[objectTaggingKey] = t.toMetadata()
+	}
+
+	// Source and destination are same, only metadata is updated.
+	_, err = objAPI.CopyObject(bucket, object, bucket, object, metadata)
+	return err
+}
+
+// GetBucketTaggingHandler - GET Bucket tagging
+// -----------------
+// Returns the tag set associated with the bucket.
+func (api objectAPIHandlers) GetBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	t, err := loadBucketTagging(bucket, objAPI)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	taggingBytes, err := xml.Marshal(t)
+	if err != nil {
+		errorIf(err, "Unable to marshal tagging into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, taggingBytes)
+}
+
+// PutBucketTaggingHandler - PUT Bucket tagging
+// -----------------
+// Replaces the tag set associated with the bucket.
+func (api objectAPIHandlers) PutBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	taggingBytes, s3Error := readTaggingBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	t, s3Error := parseTagging(taggingBytes, maxBucketTags)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = persistBucketTagging(bucket, t, objAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// DeleteBucketTaggingHandler - DELETE Bucket tagging
+// -----------------
+// Removes the tag set associated with the bucket.
+func (api objectAPIHandlers) DeleteBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, "", "", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(bucket)
+	if err != nil {
+		errorIf(err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing tagging which is not set is not an error.
+	if err = removeBucketTagging(bucket, objAPI); err != nil && !isErrObjectNotFound(err) {
+		errorIf(err, "Unable to remove bucket tagging.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// GetObjectTaggingHandler - GET Object tagging
+// -----------------
+// Returns the tag set associated with the object.
+func (api objectAPIHandlers) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:GetObjectTagging", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := api.NSMutex().NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		errorIf(err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	taggingBytes, err := xml.Marshal(getObjectTagging(objInfo.UserDefined))
+	if err != nil {
+		errorIf(err, "Unable to marshal tagging into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, taggingBytes)
+}
+
+// PutObjectTaggingHandler - PUT Object tagging
+// -----------------
+// Replaces the tag set associated with the object.
+func (api objectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:PutObjectTagging", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	taggingBytes, s3Error := readTaggingBody(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	t, s3Error := parseTagging(taggingBytes, maxObjectTags)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := updateObjectTagging(r, bucket, object, t, objAPI, api.NSMutex()); err != nil {
+		errorIf(err, "Unable to update object tagging.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DeleteObjectTaggingHandler - DELETE Object tagging
+// -----------------
+// Removes the tag set associated with the object.
+func (api objectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := api.checkRequestAuthType(r, bucket, "s3:DeleteObjectTagging", api.Config().GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := updateObjectTagging(r, bucket, object, tagging{}, objAPI, api.NSMutex()); err != nil {
+		errorIf(err, "Unable to update object tagging.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests PUT, GET and DELETE bucket tagging.
+func TestBucketTaggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketTaggingHandlers, []string{"GetBucketTagging", "PutBucketTagging", "DeleteBucketTagging"})
+}
+
+func testBucketTaggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	validTags := `<Tagging><TagSet><Tag><Key>project</Key><Value>minio</Value></Tag></TagSet></Tagging>`
+	invalidTags := `<Tagging><TagSet><Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>a</Key><Value>2</Value></Tag></TagSet></Tagging>`
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		body               string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+	}{
+		// No tags set yet.
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid tags.
+		{"PUT", bucketName, invalidTags, credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Malformed XML.
+		{"PUT", bucketName, "<Tagging>", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Non-existent bucket.
+		{"PUT", "non-existent-bucket", validTags, credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Invalid credentials.
+		{"PUT", bucketName, validTags, "abcd1234", credentials.SecretKey, http.StatusForbidden},
+		// Valid tags.
+		{"PUT", bucketName, validTags, credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Remove tags.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		{"GET", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Removing again is not an error.
+		{"DELETE", bucketName, "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getTaggingURL("", testCase.bucketName, ""),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" && rec.Code == http.StatusOK {
+			var tags tagging
+			if err = xml.Unmarshal(rec.Body.Bytes(), &tags); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if len(tags.TagSet) != 1 || tags.TagSet[0].Key != "project" || tags.TagSet[0].Value != "minio" {
+				t.Fatalf("Test %d: %s: unexpected tags %v", i+1, instanceType, tags.TagSet)
+			}
+		}
+	}
+}
+
+// Tests PUT, GET and DELETE object tagging along with policy
+// conditions on object tags.
+func TestObjectTaggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectTaggingHandlers, []string{"GetObjectTagging", "PutObjectTagging",
+		"DeleteObjectTagging", "HeadObject", "GetObject"})
+}
+
+func testObjectTaggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	objectName := "object"
+	data := []byte("hello, world")
+	objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data),
+		map[string]string{"content-type": "text/plain"}, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	validTags := `<Tagging><TagSet><Tag><Key>public</Key><Value>yes</Value></Tag><Tag><Key>project</Key><Value>minio</Value></Tag></TagSet></Tagging>`
+	testCases := []struct {
+		method             string
+		objectName         string
+		body               string
+		expectedRespStatus int
+		expectedTags       int
+	}{
+		// No tags set yet.
+		{"GET", objectName, "", http.StatusOK, 0},
+		// Non-existent object.
+		{"PUT", "non-existent-object", validTags, http.StatusNotFound, 0},
+		// Malformed XML.
+		{"PUT", objectName, "<Tagging>", http.StatusBadRequest, 0},
+		// Valid tags.
+		{"PUT", objectName, validTags, http.StatusOK, 0},
+		{"GET", objectName, "", http.StatusOK, 2},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getTaggingURL("", bucketName, testCase.objectName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+
+		if testCase.method == "GET" {
+			var tags tagging
+			if err = xml.Unmarshal(rec.Body.Bytes(), &tags); err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+			}
+			if len(tags.TagSet) != testCase.expectedTags {
+				t.Fatalf("Test %d: %s: expected %d tags, got %v", i+1, instanceType, testCase.expectedTags, tags.TagSet)
+			}
+		}
+	}
+
+	// Object data, ETag and metadata are preserved, number of tags is
+	// sent instead of the tags.
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("HEAD", getHeadObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected %d, got %d", instanceType, http.StatusOK, rec.Code)
+	}
+	if etag := rec.Header().Get("ETag"); etag != "\""+objInfo.MD5Sum+"\"" {
+		t.Fatalf("%s: expected ETag %s, got %s", instanceType, objInfo.MD5Sum, etag)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/plain" {
+		t.Fatalf("%s: expected Content-Type text/plain, got %s", instanceType, contentType)
+	}
+	if count := rec.Header().Get(objectTaggingCountHeader); count != "2" {
+		t.Fatalf("%s: expected 2 tags, got %s", instanceType, count)
+	}
+	if tags := rec.Header().Get(objectTaggingKey); tags != "" {
+		t.Fatalf("%s: tags should not be sent, got %s", instanceType, tags)
+	}
+
+	// Anonymous access is only allowed to objects tagged public.
+	policy := `{"Version": "2012-10-17", "Statement": [{"Action": ["s3:GetObject"], "Effect": "Allow",
+		"Principal": {"AWS": ["*"]}, "Resource": ["arn:aws:s3:::%s/*"],
+		"Condition": {"StringEquals": {"s3:ExistingObjectTag/public": ["yes"]}}}]}`
+	if s3Error := parseAndPersistBucketPolicy(bucketName, []byte(fmt.Sprintf(policy, bucketName)), obj); s3Error != ErrNone {
+		t.Fatalf("%s: unable to set policy %d", instanceType, s3Error)
+	}
+	if err = initBucketPolicies(obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	anonGet := func() int {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, objectName), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := anonGet(); code != http.StatusOK {
+		t.Fatalf("%s: expected anonymous access to tagged object, got %d", instanceType, code)
+	}
+
+	// Access is denied once tags are removed.
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("DELETE", getTaggingURL("", bucketName, objectName),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: expected %d, got %d", instanceType, http.StatusNoContent, rec.Code)
+	}
+	if code := anonGet(); code != http.StatusForbidden {
+		t.Fatalf("%s: expected anonymous access to be denied, got %d", instanceType, code)
+	}
+
+	// Spoofing tags in query parameters has no effect.
+	rec = httptest.NewRecorder()
+	req, err = newTestRequest("GET", getGetObjectURL("", bucketName, objectName)+"?ExistingObjectTag/public=yes", 0, nil)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: expected anonymous access to be denied, got %d", instanceType, rec.Code)
+	}
+}
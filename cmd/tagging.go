@@ -0,0 +1,267 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+const (
+	// Bucket tagging config file name, saved alongside other
+	// bucket configs in minioMetaBucket.
+	bucketTaggingConfig = "tagging.xml"
+
+	// Object tags are saved url encoded in object metadata under
+	// this key, like x-amz-tagging header of PutObject.
+	objectTaggingKey = "X-Amz-Tagging"
+
+	// Number of tags on an object is sent in this header.
+	objectTaggingCountHeader = "X-Amz-Tagging-Count"
+
+	// Limits on tags as documented by S3.
+	maxBucketTags      = 50
+	maxObjectTags      = 10
+	maxTagKeyLength    = 128
+	maxTagValueLength  = 256
+	maxTaggingBodySize = 1 * 1024 * 1024
+
+	// Policy condition key prefix to match existing object tags,
+	// e.g. "s3:ExistingObjectTag/project".
+	existingObjectTagConditionPrefix = "s3:" + existingObjectTagKeyPrefix
+
+	// Object tags are passed for policy evaluation with this prefix.
+	existingObjectTagKeyPrefix = "ExistingObjectTag/"
+)
+
+// Internal error used to signal tagging is not set.
+var errNoSuchTagSet = errors.New("The TagSet does not exist")
+
+// objectTag - a single key value tag.
+type objectTag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// tagging - tag set of a bucket or an object, sent and received as
+//
+//	<Tagging><TagSet><Tag><Key>k</Key><Value>v</Value></Tag></TagSet></Tagging>
+type tagging struct {
+	XMLName xml.Name    `xml:"Tagging"`
+	TagSet  []objectTag `xml:"TagSet>Tag"`
+}
+
+// isValid - returns true if tags are unique and within S3 limits.
+func (t tagging) isValid(maxTags int) bool {
+	if len(t.TagSet) > maxTags {
+		return false
+	}
+	keys := set.NewStringSet()
+	for _, tag := range t.TagSet {
+		if tag.Key == "" || keys.Contains(tag.Key) {
+			return false
+		}
+		if utf8.RuneCountInString(tag.Key) > maxTagKeyLength ||
+			utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+			return false
+		}
+		keys.Add(tag.Key)
+	}
+	return true
+}
+
+// parseTagging - parses and validates tagging XML, returns ErrNone
+// on success.
+func parseTagging(data []byte, maxTags int) (tagging, APIErrorCode) {
+	var t tagging
+	if err := xml.Unmarshal(data, &t); err != nil {
+		return t, ErrMalformedXML
+	}
+	if !t.isValid(maxTags) {
+		return t, ErrInvalidTag
+	}
+	return t, ErrNone
+}
+
+// toMetadata - encodes tags to be saved in object metadata.
+func (t tagging) toMetadata() string {
+	values := make(url.Values)
+	for _, tag := range t.TagSet {
+		values.Set(tag.Key, tag.Value)
+	}
+	return values.Encode()
+}
+
+// getObjectTagging - returns tags saved in object metadata.
+func getObjectTagging(metadata map[string]string) tagging {
+	t := tagging{TagSet: []objectTag{}}
+	values, err := url.ParseQuery(metadata[objectTaggingKey])
+	if err != nil {
+		return t
+	}
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t.TagSet = append(t.TagSet, objectTag{Key: key, Value: values.Get(key)})
+	}
+	return t
+}
+
+// hasObjectTagConditions - returns true if any statement of the
+// policy has a condition on existing object tags.
+func hasObjectTagConditions(policy *bucketPolicy) bool {
+	for _, statement := range policy.Statements {
+		for _, conditionKeyVal := range statement.Conditions {
+			for key := range conditionKeyVal {
+				if isObjectTagConditionKey(key) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isObjectTagConditionKey - returns true for "s3:ExistingObjectTag/<key>".
+func isObjectTagConditionKey(key string) bool {
+	return strings.HasPrefix(key, existingObjectTagConditionPrefix) && key != existingObjectTagConditionPrefix
+}
+
+// objectTagConditionMatch - evaluates conditions on existing object
+// tags of a statement for the given condition type, an object without
+// the tag never satisfies StringEquals and StringLike.
+func objectTagConditionMatch(condition string, conditionKeyVal map[string]set.StringSet, conditions map[string]set.StringSet) bool {
+	for key, values := range conditionKeyVal {
+		if !isObjectTagConditionKey(key) {
+			continue
+		}
+
+		matched := false
+		for tagValue := range conditions[strings.TrimPrefix(key, "s3:")] {
+			switch condition {
+			case "StringEquals", "StringNotEquals":
+				matched = values.Contains(tagValue)
+			case "StringLike", "StringNotLike":
+				matched = !values.FuncMatch(tagValueMatch, tagValue).IsEmpty()
+			}
+		}
+
+		switch condition {
+		case "StringEquals", "StringLike":
+			if !matched {
+				return false
+			}
+		case "StringNotEquals", "StringNotLike":
+			if matched {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Match function matches wild cards in 'pattern' for tag value.
+func tagValueMatch(pattern, value string) bool {
+	return wildcard.MatchSimple(pattern, value)
+}
+
+// getObjectTagConditions - returns tags of an object to evaluate
+// policy conditions against.
+func getObjectTagConditions(bucket, object string, objAPI ObjectLayer) map[string]set.StringSet {
+	conditions := make(map[string]set.StringSet)
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return conditions
+	}
+	for _, tag := range getObjectTagging(objInfo.UserDefined).TagSet {
+		conditions[existingObjectTagKeyPrefix+tag.Key] = set.CreateStringSet(tag.Value)
+	}
+	return conditions
+}
+
+// loadBucketTagging - loads tagging of a bucket, returns
+// errNoSuchTagSet if none is set.
+func loadBucketTagging(bucket string, objAPI ObjectLayer) (*tagging, error) {
+	tPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+
+	// Acquire a read lock on tagging config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, tPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, tPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchTagSet
+		}
+		errorIf(err, "Unable to load tagging for bucket %s", bucket)
+		return nil, err
+	}
+
+	t := &tagging{}
+	if err = xml.Unmarshal(buffer.Bytes(), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// persistBucketTagging - saves tagging of a bucket.
+func persistBucketTagging(bucket string, t tagging, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	tPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+
+	// Acquire a write lock on tagging config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, tPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, tPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write tagging for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketTagging - removes tagging of a bucket.
+func removeBucketTagging(bucket string, objAPI ObjectLayer) error {
+	tPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+
+	// Acquire a write lock on tagging config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, tPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, tPath)
+	objLock.Unlock()
+	return err
+}
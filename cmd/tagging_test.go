@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Tests parsing and validation of tagging XML.
+func TestParseTagging(t *testing.T) {
+	tagXML := func(tags ...string) string {
+		var buf string
+		for i := 0; i+1 < len(tags); i += 2 {
+			buf += "<Tag><Key>" + tags[i] + "</Key><Value>" + tags[i+1] + "</Value></Tag>"
+		}
+		return "<Tagging><TagSet>" + buf + "</TagSet></Tagging>"
+	}
+	var manyTags []string
+	for i := 0; i < maxObjectTags+1; i++ {
+		manyTags = append(manyTags, string(rune('a'+i)), "v")
+	}
+
+	testCases := []struct {
+		data        string
+		maxTags     int
+		expectedErr APIErrorCode
+		expectedLen int
+	}{
+		// Valid tags.
+		{tagXML("project", "minio", "env", "prod"), maxObjectTags, ErrNone, 2},
+		// Empty tag set.
+		{tagXML(), maxObjectTags, ErrNone, 0},
+		// Malformed XML.
+		{"<Tagging><TagSet>", maxObjectTags, ErrMalformedXML, 0},
+		// Duplicate keys.
+		{tagXML("project", "a", "project", "b"), maxObjectTags, ErrInvalidTag, 0},
+		// Empty key.
+		{tagXML("", "a"), maxObjectTags, ErrInvalidTag, 0},
+		// Key too long.
+		{tagXML(strings.Repeat("k", maxTagKeyLength+1), "a"), maxObjectTags, ErrInvalidTag, 0},
+		// Value too long.
+		{tagXML("k", strings.Repeat("v", maxTagValueLength+1)), maxObjectTags, ErrInvalidTag, 0},
+		// Too many tags for an object but not for a bucket.
+		{tagXML(manyTags...), maxObjectTags, ErrInvalidTag, 0},
+		{tagXML(manyTags...), maxBucketTags, ErrNone, maxObjectTags + 1},
+	}
+
+	for i, testCase := range testCases {
+		tags, s3Error := parseTagging([]byte(testCase.data), testCase.maxTags)
+		if s3Error != testCase.expectedErr {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expectedErr, s3Error)
+			continue
+		}
+		if s3Error == ErrNone && len(tags.TagSet) != testCase.expectedLen {
+			t.Errorf("Test %d: expected %d tags, got %d", i+1, testCase.expectedLen, len(tags.TagSet))
+		}
+	}
+}
+
+// Tests tags are saved to and loaded from object metadata.
+func TestObjectTaggingMetadata(t *testing.T) {
+	tags := tagging{TagSet: []objectTag{
+		{Key: "project", Value: "minio"},
+		{Key: "cost center", Value: "a&b=c"},
+	}}
+	metadata := map[string]string{objectTaggingKey: tags.toMetadata()}
+
+	savedTags := getObjectTagging(metadata)
+	if len(savedTags.TagSet) != 2 {
+		t.Fatalf("Expected 2 tags, got %v", savedTags.TagSet)
+	}
+	// Tags are sorted by key.
+	if savedTags.TagSet[0] != tags.TagSet[1] || savedTags.TagSet[1] != tags.TagSet[0] {
+		t.Fatalf("Unexpected tags %v", savedTags.TagSet)
+	}
+
+	if emptyTags := getObjectTagging(nil); len(emptyTags.TagSet) != 0 {
+		t.Fatalf("Expected no tags, got %v", emptyTags.TagSet)
+	}
+}
+
+// Tests evaluation of policy conditions on existing object tags.
+func TestObjectTagConditionMatch(t *testing.T) {
+	conditionKeyVal := map[string]set.StringSet{
+		"s3:ExistingObjectTag/project": set.CreateStringSet("minio", "mc*"),
+	}
+	tagged := func(value string) map[string]set.StringSet {
+		return map[string]set.StringSet{"ExistingObjectTag/project": set.CreateStringSet(value)}
+	}
+
+	testCases := []struct {
+		condition  string
+		conditions map[string]set.StringSet
+		expected   bool
+	}{
+		{"StringEquals", tagged("minio"), true},
+		{"StringEquals", tagged("other"), false},
+		{"StringEquals", nil, false},
+		{"StringNotEquals", tagged("minio"), false},
+		{"StringNotEquals", tagged("other"), true},
+		{"StringNotEquals", nil, true},
+		{"StringLike", tagged("mc-admin"), true},
+		{"StringLike", tagged("other"), false},
+		{"StringLike", nil, false},
+		{"StringNotLike", tagged("mc-admin"), false},
+		{"StringNotLike", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		if matched := objectTagConditionMatch(testCase.condition, conditionKeyVal, testCase.conditions); matched != testCase.expected {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.expected, matched)
+		}
+	}
+
+	// Statements without tag conditions are not affected.
+	prefixCondition := map[string]set.StringSet{"s3:prefix": set.CreateStringSet("a")}
+	if !objectTagConditionMatch("StringEquals", prefixCondition, nil) {
+		t.Fatal("Expected statement without tag conditions to match")
+	}
+}
+
+// Tests persisting, loading and removing bucket tagging.
+func TestBucketTaggingConfig(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testBucketTaggingConfig)
+}
+
+func testBucketTaggingConfig(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "tagging-bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	if _, err := loadBucketTagging(bucket, obj); err != errNoSuchTagSet {
+		t.Fatalf("%s: expected %s, got %s", instanceType, errNoSuchTagSet, err)
+	}
+
+	tags := tagging{TagSet: []objectTag{{Key: "project", Value: "minio"}}}
+	if err := persistBucketTagging(bucket, tags, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	savedTags, err := loadBucketTagging(bucket, obj)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(savedTags.TagSet) != 1 || savedTags.TagSet[0] != tags.TagSet[0] {
+		t.Fatalf("%s: expected %v, got %v", instanceType, tags.TagSet, savedTags.TagSet)
+	}
+
+	if err = removeBucketTagging(bucket, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, err = loadBucketTagging(bucket, obj); err != errNoSuchTagSet {
+		t.Fatalf("%s: expected %s, got %s", instanceType, errNoSuchTagSet, err)
+	}
+}
+
+// Tests validation of policy conditions on existing object tags.
+func TestIsValidObjectTagConditions(t *testing.T) {
+	tagCondition := map[string]map[string]set.StringSet{
+		"StringEquals": {"s3:ExistingObjectTag/project": set.CreateStringSet("minio")},
+	}
+	emptyTagCondition := map[string]map[string]set.StringSet{
+		"StringEquals": {"s3:ExistingObjectTag/": set.CreateStringSet("minio")},
+	}
+
+	testCases := []struct {
+		actions    set.StringSet
+		conditions map[string]map[string]set.StringSet
+		shouldPass bool
+	}{
+		{set.CreateStringSet("s3:GetObject"), tagCondition, true},
+		{set.CreateStringSet("s3:PutObjectTagging", "s3:GetObjectTagging"), tagCondition, true},
+		// Tag conditions are not supported on bucket actions.
+		{set.CreateStringSet("s3:ListBucket"), tagCondition, false},
+		// Tag key is required.
+		{set.CreateStringSet("s3:GetObject"), emptyTagCondition, false},
+	}
+
+	for i, testCase := range testCases {
+		err := isValidConditions(testCase.actions, testCase.conditions)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
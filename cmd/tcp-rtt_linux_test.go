@@ -0,0 +1,59 @@
+// +build go1.9,!386
+
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"testing"
+)
+
+// Tests round trip time of a loopback connection is read from the kernel.
+func TestGetTCPRTT(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, aerr := l.Accept()
+		if aerr != nil {
+			return
+		}
+		defer conn.Close()
+		b := make([]byte, 1)
+		conn.Read(b)
+		conn.Write(b)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = conn.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if rtt, ok := getTCPRTT(conn.(*net.TCPConn)); !ok || rtt <= 0 {
+		t.Fatalf("Expected round trip time, got %v, %v", rtt, ok)
+	}
+}
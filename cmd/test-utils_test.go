@@ -211,7 +211,7 @@ func UnstartedTestServer(t TestErrHandler, instanceType string) TestServer {
 	testServer.AccessKey = credentials.AccessKey
 	testServer.SecretKey = credentials.SecretKey
 
-	httpHandler, err := configureServerHandler(testServer.Disks)
+	httpHandler, err := configureServerHandler(testServer.Disks, newGlobalServerContext())
 	if err != nil {
 		t.Fatalf("Failed to configure one of the RPC services <ERROR> %s", err)
 	}
@@ -393,7 +393,7 @@ func StartTestPeersRPCServer(t TestErrHandler, instanceType string) TestServer {
 	// need storage layer for bucket config storage.
 	registerStorageRPCRouters(mux, endpoints)
 	// need API layer to send requests, etc.
-	registerAPIRouter(mux)
+	registerAPIRouter(mux, newGlobalServerContext())
 	// module being tested is Peer RPCs router.
 	registerS3PeerRPCRouter(mux)
 
@@ -457,6 +457,7 @@ func resetGlobalIsEnvs() {
 	globalIsEnvCreds = false
 	globalIsEnvBrowser = false
 	globalIsEnvRegion = false
+	globalIsEnvDomain = false
 }
 
 // Resets all the globals used modified in tests.
@@ -1444,6 +1445,60 @@ func getGetNotificationURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for bucket or object tagging.
+func getBucketACLURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("acl", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+func getTaggingURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("tagging", "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for bucket lifecycle.
+func getLifecycleURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("lifecycle", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket default metadata.
+func getDefaultMetadataURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set(defaultMetadataQuery, "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket website configuration.
+func getBucketWebsiteURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("website", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for object lock operations, the object lock
+// configuration of the bucket when objectName is empty, otherwise
+// retention or legal-hold of the object.
+func getObjectLockURL(endPoint, bucketName, objectName, subResource string) string {
+	queryValue := url.Values{}
+	queryValue.Set(subResource, "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for bucket analytics operations, all configurations are
+// listed when id is empty.
+func getAnalyticsURL(endPoint, bucketName, id string) string {
+	queryValue := url.Values{}
+	queryValue.Set("analytics", "")
+	if id != "" {
+		queryValue.Set("id", id)
+	}
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for inserting bucket policy.
 func getPutPolicyURL(endPoint, bucketName string) string {
 	queryValue := url.Values{}
@@ -2080,6 +2135,9 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "GetBucketLocation":
 			// Register GetBucketLocation handler.
 			bucket.Methods("GET").HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
+		case "ListObjectsV2":
+			// Register ListObjectsV2 handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectsV2Handler).Queries("list-type", "2")
 		case "HeadBucket":
 			// Register HeadBucket handler.
 			bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
@@ -2101,6 +2159,18 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "ListMultipartUploads":
 			// Register ListMultipartUploads handler.
 			bucket.Methods("GET").HandlerFunc(api.ListMultipartUploadsHandler).Queries("uploads", "")
+		case "PartSizeHint":
+			// Register PartSizeHint handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.PartSizeHintHandler).Queries(partSizeHintQuery, "")
+		case "GetObjectValidators":
+			// Register GetObjectValidators handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectValidatorsHandler).Queries(objectValidatorsQuery, "")
+		case "ComposeObject":
+			// Register ComposeObject handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.ComposeObjectHandler).Queries(composeQuery, "")
+		case "MoveObject":
+			// Register MoveObject handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.MoveObjectHandler).Queries(moveQuery, "")
 		case "CompleteMultipart":
 			// Register Complete Multipart Upload handler.
 			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.CompleteMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
@@ -2116,6 +2186,87 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "ListenBucketNotification":
 			// Register ListenBucketNotification Handler.
 			bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+		case "GetBucketACL":
+			// Register GetBucketACL Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketACLHandler).Queries("acl", "")
+		case "PutBucketACL":
+			// Register PutBucketACL Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketACLHandler).Queries("acl", "")
+		case "GetBucketTagging":
+			// Register GetBucketTagging Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketTaggingHandler).Queries("tagging", "")
+		case "PutBucketTagging":
+			// Register PutBucketTagging Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketTaggingHandler).Queries("tagging", "")
+		case "DeleteBucketTagging":
+			// Register DeleteBucketTagging Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketTaggingHandler).Queries("tagging", "")
+		case "GetObjectTagging":
+			// Register GetObjectTagging Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectTaggingHandler).Queries("tagging", "")
+		case "PutObjectTagging":
+			// Register PutObjectTagging Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectTaggingHandler).Queries("tagging", "")
+		case "DeleteObjectTagging":
+			// Register DeleteObjectTagging Handler.
+			bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectTaggingHandler).Queries("tagging", "")
+		case "GetBucketLifecycle":
+			// Register GetBucketLifecycle Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
+		case "PutBucketLifecycle":
+			// Register PutBucketLifecycle Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+		case "DeleteBucketLifecycle":
+			// Register DeleteBucketLifecycle Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+		case "GetBucketDefaultMetadata":
+			// Register GetBucketDefaultMetadata Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketDefaultMetadataHandler).Queries(defaultMetadataQuery, "")
+		case "PutBucketDefaultMetadata":
+			// Register PutBucketDefaultMetadata Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketDefaultMetadataHandler).Queries(defaultMetadataQuery, "")
+		case "DeleteBucketDefaultMetadata":
+			// Register DeleteBucketDefaultMetadata Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketDefaultMetadataHandler).Queries(defaultMetadataQuery, "")
+		case "GetBucketWebsite":
+			// Register GetBucketWebsite Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketWebsiteHandler).Queries("website", "")
+		case "PutBucketWebsite":
+			// Register PutBucketWebsite Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketWebsiteHandler).Queries("website", "")
+		case "DeleteBucketWebsite":
+			// Register DeleteBucketWebsite Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketWebsiteHandler).Queries("website", "")
+		case "GetObjectLockConfiguration":
+			// Register GetObjectLockConfiguration Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "PutObjectLockConfiguration":
+			// Register PutObjectLockConfiguration Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "GetObjectRetention":
+			// Register GetObjectRetention Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectRetentionHandler).Queries("retention", "")
+		case "PutObjectRetention":
+			// Register PutObjectRetention Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectRetentionHandler).Queries("retention", "")
+		case "GetObjectLegalHold":
+			// Register GetObjectLegalHold Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectLegalHoldHandler).Queries("legal-hold", "")
+		case "PutObjectLegalHold":
+			// Register PutObjectLegalHold Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectLegalHoldHandler).Queries("legal-hold", "")
+		case "GetBucketAnalytics":
+			// Register GetBucketAnalytics Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketAnalyticsHandler).Queries("analytics", "", "id", "{id:.+}")
+		case "ListBucketAnalytics":
+			// Register ListBucketAnalytics Handler.
+			bucket.Methods("GET").HandlerFunc(api.ListBucketAnalyticsHandler).Queries("analytics", "")
+		case "PutBucketAnalytics":
+			// Register PutBucketAnalytics Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketAnalyticsHandler).Queries("analytics", "")
+		case "DeleteBucketAnalytics":
+			// Register DeleteBucketAnalytics Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketAnalyticsHandler).Queries("analytics", "")
 		}
 	}
 }
@@ -2124,7 +2275,7 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 func registerAPIFunctions(muxRouter *router.Router, objLayer ObjectLayer, apiFunctions ...string) {
 	if len(apiFunctions) == 0 {
 		// Register all api endpoints by default.
-		registerAPIRouter(muxRouter)
+		registerAPIRouter(muxRouter, newGlobalServerContext())
 		return
 	}
 	// API Router.
@@ -2138,9 +2289,7 @@ func registerAPIFunctions(muxRouter *router.Router, objLayer ObjectLayer, apiFun
 	globalObjectAPI = objLayer
 	globalObjLayerMutex.Unlock()
 
-	api := objectAPIHandlers{
-		ObjectAPI: newObjectLayerFn,
-	}
+	api := objectAPIHandlers{newGlobalServerContext()}
 
 	// Register ListBuckets	handler.
 	apiRouter.Methods("GET").HandlerFunc(api.ListBucketsHandler)
@@ -2160,7 +2309,7 @@ func initTestAPIEndPoints(objLayer ObjectLayer, apiFunctions []string) http.Hand
 		registerAPIFunctions(muxRouter, objLayer, apiFunctions...)
 		return muxRouter
 	}
-	registerAPIRouter(muxRouter)
+	registerAPIRouter(muxRouter, newGlobalServerContext())
 	return muxRouter
 }
 
@@ -2172,7 +2321,7 @@ func initTestWebRPCEndPoint(objLayer ObjectLayer) http.Handler {
 
 	// Initialize router.
 	muxRouter := router.NewRouter().SkipClean(true)
-	registerWebRouter(muxRouter)
+	registerWebRouter(muxRouter, newGlobalServerContext())
 	return muxRouter
 }
 
@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	minio "github.com/minio/minio-go"
+)
+
+// Modes of serving GET of an object transitioned to the remote tier.
+const (
+	// Redirect to a presigned URL of the remote copy.
+	tierGetModeRedirect = "redirect"
+	// Stream the remote copy and restore it locally.
+	tierGetModeRestore = "restore"
+	// Fail with InvalidObjectState, like S3 does for GLACIER objects.
+	tierGetModeError = "error"
+)
+
+// tierConfig - remote S3 compatible tier where lifecycle transitions
+// move object data to, disabled by default.
+type tierConfig struct {
+	Enable    bool   `json:"enable"`
+	Endpoint  string `json:"endpoint"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Secure    bool   `json:"secure"`
+	Bucket    string `json:"bucket"`
+	// Serving mode of GET on transitioned objects, one of "redirect",
+	// "restore" or "error". Defaults to "error" when empty.
+	GetMode string `json:"getMode"`
+}
+
+// Validate - validates remote tier config.
+func (t tierConfig) Validate() error {
+	if !t.Enable {
+		return nil
+	}
+	if t.Endpoint == "" {
+		return errors.New("Tier endpoint cannot be empty")
+	}
+	if t.Bucket == "" {
+		return errors.New("Tier bucket cannot be empty")
+	}
+	switch t.GetMode {
+	case "", tierGetModeRedirect, tierGetModeRestore, tierGetModeError:
+	default:
+		return fmt.Errorf("Invalid tier getMode value ‘%s’", t.GetMode)
+	}
+	return nil
+}
+
+// getGetMode - returns configured GET serving mode.
+func (t tierConfig) getGetMode() string {
+	if t.GetMode == "" {
+		return tierGetModeError
+	}
+	return t.GetMode
+}
+
+// remoteTier - storage of transitioned object data, keys are
+// "bucket/object" of the local object.
+type remoteTier interface {
+	Put(key string, reader io.Reader, contentType string) error
+	Get(key string, offset, length int64) (io.ReadCloser, error)
+	Remove(key string) error
+	PresignedGet(key string, expiry time.Duration) (string, error)
+}
+
+// s3Tier - remote tier backed by a bucket on an S3 compatible server.
+type s3Tier struct {
+	client *minio.Client
+	bucket string
+}
+
+// newS3Tier - returns remote tier of the given config.
+func newS3Tier(cfg tierConfig) (remoteTier, error) {
+	client, err := minio.New(cfg.Endpoint, cfg.AccessKey, cfg.SecretKey, cfg.Secure)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Tier{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (t *s3Tier) Put(key string, reader io.Reader, contentType string) error {
+	_, err := t.client.PutObject(t.bucket, key, reader, contentType)
+	return err
+}
+
+func (t *s3Tier) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	object, err := t.client.GetObject(t.bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = object.Seek(offset, 0); err != nil {
+		object.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(object, length), object}, nil
+}
+
+func (t *s3Tier) Remove(key string) error {
+	return t.client.RemoveObject(t.bucket, key)
+}
+
+func (t *s3Tier) PresignedGet(key string, expiry time.Duration) (string, error) {
+	u, err := t.client.PresignedGetObject(t.bucket, key, expiry, url.Values{})
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// initRemoteTier - sets globalRemoteTier from config, remains nil
+// when the tier is disabled.
+func initRemoteTier() error {
+	cfg := serverConfig.GetTier()
+	if !cfg.Enable {
+		return nil
+	}
+	tier, err := newS3Tier(cfg)
+	if err != nil {
+		return err
+	}
+	globalRemoteTier = tier
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+// Tests validating remote tier config.
+func TestTierConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config          tierConfig
+		expectedGetMode string
+		shouldPass      bool
+	}{
+		// Disabled tier is not validated.
+		{tierConfig{}, tierGetModeError, true},
+		{tierConfig{GetMode: "invalid"}, "invalid", true},
+		// Enabled tier needs an endpoint and a bucket.
+		{tierConfig{Enable: true, Bucket: "archive"}, tierGetModeError, false},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com"}, tierGetModeError, false},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com", Bucket: "archive"}, tierGetModeError, true},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com", Bucket: "archive", GetMode: "redirect"}, tierGetModeRedirect, true},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com", Bucket: "archive", GetMode: "restore"}, tierGetModeRestore, true},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com", Bucket: "archive", GetMode: "error"}, tierGetModeError, true},
+		{tierConfig{Enable: true, Endpoint: "s3.amazonaws.com", Bucket: "archive", GetMode: "proxy"}, "proxy", false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+		if getMode := testCase.config.getGetMode(); getMode != testCase.expectedGetMode {
+			t.Errorf("Test %d: expected get mode %s, got %s", i+1, testCase.expectedGetMode, getMode)
+		}
+	}
+}
+
+// Tests remote tier is initialized only when enabled.
+func TestInitRemoteTier(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func() { globalRemoteTier = nil }()
+
+	if err = initRemoteTier(); err != nil {
+		t.Fatal(err)
+	}
+	if globalRemoteTier != nil {
+		t.Fatal("Expected no remote tier when disabled")
+	}
+
+	serverConfig.Tier = tierConfig{Enable: true, Endpoint: "localhost:9000", Bucket: "archive"}
+	if err = initRemoteTier(); err != nil {
+		t.Fatal(err)
+	}
+	if globalRemoteTier == nil {
+		t.Fatal("Expected remote tier when enabled")
+	}
+}
@@ -0,0 +1,248 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Metadata of a transitioned object's local stub, saved under
+	// internal keys which can't be set by clients.
+	transitionMetaPrefix      = "X-Minio-Internal-Transition-"
+	transitionStorageClassKey = transitionMetaPrefix + "Storage-Class"
+	transitionSizeKey         = transitionMetaPrefix + "Size"
+	transitionETagKey         = transitionMetaPrefix + "Etag"
+	transitionModTimeKey      = transitionMetaPrefix + "Mod-Time"
+
+	// Storage class of transitioned objects is sent in this header.
+	amzStorageClassHeader = "X-Amz-Storage-Class"
+
+	// Validity of presigned URLs GETs are redirected to.
+	transitionRedirectValidity = 15 * time.Minute
+)
+
+// getTransitionKey - returns key of the remote copy of an object.
+func getTransitionKey(bucket, object string) string {
+	return bucket + slashSeparator + object
+}
+
+// isObjectTransitioned - returns true if object is a stub of an
+// object transitioned to the remote tier.
+func isObjectTransitioned(objInfo ObjectInfo) bool {
+	_, ok := objInfo.UserDefined[transitionStorageClassKey]
+	return ok
+}
+
+// getTransitionedObjectInfo - returns object info of a transitioned
+// object as it was before transition, along with its storage class.
+func getTransitionedObjectInfo(objInfo ObjectInfo) ObjectInfo {
+	info := objInfo
+	info.UserDefined = make(map[string]string)
+	for k, v := range objInfo.UserDefined {
+		if !strings.HasPrefix(k, transitionMetaPrefix) {
+			info.UserDefined[k] = v
+		}
+	}
+	info.UserDefined[amzStorageClassHeader] = objInfo.UserDefined[transitionStorageClassKey]
+	info.Size, _ = strconv.ParseInt(objInfo.UserDefined[transitionSizeKey], 10, 64)
+	info.MD5Sum = objInfo.UserDefined[transitionETagKey]
+	if modTime, err := time.Parse(time.RFC3339Nano, objInfo.UserDefined[transitionModTimeKey]); err == nil {
+		info.ModTime = modTime
+	}
+	return info
+}
+
+// getObjectStorageClass - returns storage class of an object, set
+// only on transitioned objects.
+func getObjectStorageClass(objInfo ObjectInfo) string {
+	if storageClass := objInfo.UserDefined[amzStorageClassHeader]; storageClass != "" {
+		return storageClass
+	}
+	return globalMinioDefaultStorageClass
+}
+
+// transitionObject - copies object data to the remote tier and
+// replaces the object with an empty stub. Objects modified after
+// olderThan, while being copied or already transitioned are skipped.
+func transitionObject(objAPI ObjectLayer, tier remoteTier, bucket, object, storageClass string, olderThan time.Time) error {
+	// Copy under a read lock, object stays readable meanwhile.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil || isObjectTransitioned(objInfo) || !objInfo.ModTime.Before(olderThan) {
+		objectLock.RUnlock()
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(objAPI.GetObject(bucket, object, 0, objInfo.Size, pw))
+	}()
+	err = tier.Put(getTransitionKey(bucket, object), pr, objInfo.ContentType)
+	pr.CloseWithError(err)
+	objectLock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	curInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+	// Overwritten while being copied, leave it for the next pass.
+	if curInfo.MD5Sum != objInfo.MD5Sum || !curInfo.ModTime.Equal(objInfo.ModTime) {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	metadata[transitionStorageClassKey] = storageClass
+	metadata[transitionSizeKey] = strconv.FormatInt(objInfo.Size, 10)
+	metadata[transitionETagKey] = objInfo.MD5Sum
+	metadata[transitionModTimeKey] = objInfo.ModTime.UTC().Format(time.RFC3339Nano)
+	_, err = objAPI.PutObject(bucket, object, 0, bytes.NewReader(nil), metadata, "")
+	return err
+}
+
+// restoreTransitionedObject - replaces the stub of a transitioned
+// object with data of its remote copy, which is then removed.
+func restoreTransitionedObject(objAPI ObjectLayer, tier remoteTier, bucket, object string) error {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return err
+	}
+	// Already restored, possibly by a concurrent GET.
+	if !isObjectTransitioned(objInfo) {
+		return nil
+	}
+
+	info := getTransitionedObjectInfo(objInfo)
+	metadata := make(map[string]string)
+	for k, v := range objInfo.UserDefined {
+		if !strings.HasPrefix(k, transitionMetaPrefix) {
+			metadata[k] = v
+		}
+	}
+	// ETags of multipart objects are not md5sums of their data,
+	// restored data is verified only for objects uploaded at once.
+	if !strings.Contains(info.MD5Sum, "-") {
+		metadata["md5Sum"] = info.MD5Sum
+	}
+
+	key := getTransitionKey(bucket, object)
+	reader, err := tier.Get(key, 0, info.Size)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if _, err = objAPI.PutObject(bucket, object, info.Size, reader, metadata, ""); err != nil {
+		return err
+	}
+	errorIf(tier.Remove(key), "Unable to remove %s from the remote tier after restore.", key)
+	return nil
+}
+
+// removeTransitionedObject - removes remote copy of a deleted
+// transitioned object, failures leave an unreferenced remote copy.
+func removeTransitionedObject(tier remoteTier, bucket, object string) {
+	key := getTransitionKey(bucket, object)
+	errorIf(tier.Remove(key), "Unable to remove %s from the remote tier.", key)
+}
+
+// getTransitionedObject - serves GET of a transitioned object as
+// configured, either redirects to the remote copy, streams it while
+// restoring the object in background or fails with InvalidObjectState.
+func getTransitionedObject(w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, bucket, object string, objInfo ObjectInfo) {
+	tier := globalRemoteTier
+	if tier == nil {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
+	info := getTransitionedObjectInfo(objInfo)
+
+	// Validate pre-conditions on the transitioned object.
+	if checkPreconditions(w, r, info) {
+		return
+	}
+
+	key := getTransitionKey(bucket, object)
+	switch serverConfig.GetTier().getGetMode() {
+	case tierGetModeRedirect:
+		location, err := tier.PresignedGet(key, transitionRedirectValidity)
+		if err != nil {
+			reqErrorIf(r, err, "Unable to presign %s of the remote tier.", key)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+		http.Redirect(w, r, location, http.StatusTemporaryRedirect)
+	case tierGetModeRestore:
+		var hrange *httpRange
+		if rangeHeader := getRangeHeader(r, info); rangeHeader != "" {
+			var err error
+			if hrange, err = parseRequestRange(rangeHeader, info.Size); err == errInvalidRange {
+				writeErrorResponse(w, ErrInvalidRange, r.URL)
+				return
+			}
+		}
+		var startOffset int64
+		length := info.Size
+		if hrange != nil {
+			startOffset = hrange.offsetBegin
+			length = hrange.getLength()
+		}
+
+		reader, err := tier.Get(key, startOffset, length)
+		if err != nil {
+			reqErrorIf(r, err, "Unable to read %s from the remote tier.", key)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+		defer reader.Close()
+
+		// Restore waits for the read lock held by the caller, it is
+		// skipped when the queue is full and retried by the next read.
+		globalLifecyclePool.submit(func() {
+			errorIf(restoreTransitionedObject(objAPI, tier, bucket, object),
+				"Unable to restore %s/%s from the remote tier.", bucket, object)
+		})
+
+		setObjectHeaders(w, info, hrange)
+		setGetRespHeaders(w, r.URL.Query())
+		if _, err = io.Copy(w, reader); err != nil {
+			// Headers are already written, no need to write error response.
+			reqErrorIf(r, err, "Unable to write to client.")
+		}
+	default:
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+	}
+}
@@ -0,0 +1,335 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memTier - in-memory remote tier for tests.
+type memTier struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemTier() *memTier {
+	return &memTier{objects: make(map[string][]byte)}
+}
+
+func (t *memTier) Put(key string, reader io.Reader, contentType string) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.objects[key] = data
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *memTier) Get(key string, offset, length int64) (io.ReadCloser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, ok := t.objects[key]
+	if !ok {
+		return nil, errFileNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+}
+
+func (t *memTier) Remove(key string) error {
+	t.mu.Lock()
+	delete(t.objects, key)
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *memTier) PresignedGet(key string, expiry time.Duration) (string, error) {
+	return "http://tier.example.com/" + key + "?expiry=" + strconv.Itoa(int(expiry.Seconds())), nil
+}
+
+// get - returns data saved under key, if any.
+func (t *memTier) get(key string) ([]byte, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, ok := t.objects[key]
+	return data, ok
+}
+
+// Tests object info of transitioned objects.
+func TestGetTransitionedObjectInfo(t *testing.T) {
+	modTime := time.Date(2017, time.March, 1, 10, 0, 0, 123, time.UTC)
+	stub := ObjectInfo{
+		Name: "object",
+		Size: 0,
+		UserDefined: map[string]string{
+			"content-type":            "text/plain",
+			"X-Amz-Meta-Project":      "minio",
+			transitionStorageClassKey: "GLACIER",
+			transitionSizeKey:         "12",
+			transitionETagKey:         "abcd",
+			transitionModTimeKey:      modTime.Format(time.RFC3339Nano),
+		},
+	}
+	if !isObjectTransitioned(stub) {
+		t.Fatal("Expected object to be transitioned")
+	}
+	if isObjectTransitioned(ObjectInfo{UserDefined: map[string]string{"content-type": "text/plain"}}) {
+		t.Fatal("Expected object not to be transitioned")
+	}
+
+	info := getTransitionedObjectInfo(stub)
+	if info.Size != 12 || info.MD5Sum != "abcd" || !info.ModTime.Equal(modTime) {
+		t.Fatalf("Unexpected object info %+v", info)
+	}
+	for k := range info.UserDefined {
+		if strings.HasPrefix(k, transitionMetaPrefix) {
+			t.Fatalf("Internal metadata %s not removed", k)
+		}
+	}
+	if info.UserDefined["X-Amz-Meta-Project"] != "minio" {
+		t.Fatal("User metadata not preserved")
+	}
+	if storageClass := getObjectStorageClass(info); storageClass != "GLACIER" {
+		t.Fatalf("Expected storage class GLACIER, got %s", storageClass)
+	}
+	if storageClass := getObjectStorageClass(ObjectInfo{}); storageClass != globalMinioDefaultStorageClass {
+		t.Fatalf("Expected storage class %s, got %s", globalMinioDefaultStorageClass, storageClass)
+	}
+	// Stub metadata must not be modified.
+	if _, ok := stub.UserDefined[transitionSizeKey]; !ok {
+		t.Fatal("Stub metadata modified")
+	}
+}
+
+// Wrapper for calling transition and restore tests for both XL and FS.
+func TestTransitionObject(t *testing.T) {
+	ExecObjectLayerTest(t, testTransitionObject)
+}
+
+func testTransitionObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket, object := "bucket", "dir/object"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := []byte("hello, world")
+	objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data),
+		map[string]string{"content-type": "text/plain", "X-Amz-Meta-Project": "minio"}, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	tier := newMemTier()
+	key := getTransitionKey(bucket, object)
+
+	// Objects modified after the given time are not transitioned.
+	if err = transitionObject(obj, tier, bucket, object, "GLACIER", objInfo.ModTime); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if _, ok := tier.get(key); ok {
+		t.Fatalf("%s: recent object should not be transitioned", instanceType)
+	}
+
+	olderThan := UTCNow().Add(time.Hour)
+	if err = transitionObject(obj, tier, bucket, object, "GLACIER", olderThan); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if remote, ok := tier.get(key); !ok || !bytes.Equal(remote, data) {
+		t.Fatalf("%s: unexpected remote copy %q", instanceType, remote)
+	}
+
+	stub, err := obj.GetObjectInfo(bucket, object)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if stub.Size != 0 || !isObjectTransitioned(stub) {
+		t.Fatalf("%s: expected an empty stub, got %+v", instanceType, stub)
+	}
+	info := getTransitionedObjectInfo(stub)
+	if info.Size != objInfo.Size || info.MD5Sum != objInfo.MD5Sum || !info.ModTime.Equal(objInfo.ModTime) {
+		t.Fatalf("%s: expected %+v, got %+v", instanceType, objInfo, info)
+	}
+	if info.ContentType != "text/plain" || info.UserDefined["X-Amz-Meta-Project"] != "minio" {
+		t.Fatalf("%s: metadata not preserved %+v", instanceType, info.UserDefined)
+	}
+
+	// Transitioning again is a no-op.
+	if err = transitionObject(obj, tier, bucket, object, "GLACIER", olderThan); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if remote, _ := tier.get(key); !bytes.Equal(remote, data) {
+		t.Fatalf("%s: remote copy overwritten by stub", instanceType)
+	}
+
+	if err = restoreTransitionedObject(obj, tier, bucket, object); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	restored, err := obj.GetObjectInfo(bucket, object)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if isObjectTransitioned(restored) || restored.Size != objInfo.Size || restored.MD5Sum != objInfo.MD5Sum {
+		t.Fatalf("%s: unexpected restored object %+v", instanceType, restored)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(bucket, object, 0, restored.Size, &buffer); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatalf("%s: expected %q, got %q", instanceType, data, buffer.Bytes())
+	}
+	if _, ok := tier.get(key); ok {
+		t.Fatalf("%s: remote copy not removed after restore", instanceType)
+	}
+
+	// Restoring an object which is not transitioned is a no-op.
+	if err = restoreTransitionedObject(obj, tier, bucket, object); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+}
+
+// Tests GET, HEAD, copy and delete of transitioned objects.
+func TestTransitionedObjectHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testTransitionedObjectHandlers, []string{"GetObject", "HeadObject", "CopyObject", "DeleteObject"})
+}
+
+func testTransitionedObjectHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	tier := newMemTier()
+	globalRemoteTier = tier
+	defer func() { globalRemoteTier = nil }()
+
+	objectName := "object"
+	data := []byte("hello, world")
+	transition := func() ObjectInfo {
+		objInfo, err := obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data),
+			map[string]string{"content-type": "text/plain"}, "")
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		if err = transitionObject(obj, tier, bucketName, objectName, "GLACIER", UTCNow().Add(time.Hour)); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		return objInfo
+	}
+	serve := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getGetObjectURL("", bucketName, objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	objInfo := transition()
+
+	// HEAD reports the object as it was before transition.
+	rec := serve("HEAD", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected HEAD status 200, got %d", instanceType, rec.Code)
+	}
+	if rec.Header().Get("Content-Length") != strconv.Itoa(len(data)) ||
+		rec.Header().Get("ETag") != "\""+objInfo.MD5Sum+"\"" ||
+		rec.Header().Get(amzStorageClassHeader) != "GLACIER" {
+		t.Fatalf("%s: unexpected HEAD headers %v", instanceType, rec.Header())
+	}
+	for k := range rec.Header() {
+		if strings.HasPrefix(k, transitionMetaPrefix) {
+			t.Fatalf("%s: internal metadata %s sent", instanceType, k)
+		}
+	}
+
+	// GET fails by default.
+	serverConfig.Tier = tierConfig{}
+	if rec = serve("GET", nil); rec.Code != http.StatusForbidden ||
+		!strings.Contains(rec.Body.String(), "InvalidObjectState") {
+		t.Fatalf("%s: expected InvalidObjectState, got %d %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// GET redirects to the remote copy.
+	serverConfig.Tier = tierConfig{GetMode: tierGetModeRedirect}
+	if rec = serve("GET", nil); rec.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("%s: expected redirect, got %d", instanceType, rec.Code)
+	}
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || location.Path != "/"+getTransitionKey(bucketName, objectName) {
+		t.Fatalf("%s: unexpected redirect location %s", instanceType, rec.Header().Get("Location"))
+	}
+
+	// Preconditions are validated against the transitioned object.
+	if rec = serve("GET", map[string]string{"If-None-Match": "\"" + objInfo.MD5Sum + "\""}); rec.Code != http.StatusNotModified {
+		t.Fatalf("%s: expected not modified, got %d", instanceType, rec.Code)
+	}
+
+	// Copying a transitioned object is not allowed.
+	rec = httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("PUT", getCopyObjectURL("", bucketName, "copy"), 0, nil,
+		credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	req.Header.Set("X-Amz-Copy-Source", url.QueryEscape(pathJoin(bucketName, objectName)))
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "InvalidObjectState") {
+		t.Fatalf("%s: expected InvalidObjectState on copy, got %d %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// GET streams the remote copy and restores the object.
+	serverConfig.Tier = tierConfig{GetMode: tierGetModeRestore}
+	rec = serve("GET", map[string]string{"Range": "bytes=0-4"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "hello" {
+		t.Fatalf("%s: expected partial content, got %d %q", instanceType, rec.Code, rec.Body.String())
+	}
+	restored := false
+	for i := 0; i < 100 && !restored; i++ {
+		if _, ok := tier.get(getTransitionKey(bucketName, objectName)); !ok {
+			restored = true
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !restored {
+		t.Fatalf("%s: object not restored", instanceType)
+	}
+	if rec = serve("GET", nil); rec.Code != http.StatusOK || rec.Body.String() != string(data) {
+		t.Fatalf("%s: expected restored object, got %d %q", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// Deleting a transitioned object removes its remote copy.
+	transition()
+	if rec = serve("DELETE", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: expected DELETE status 204, got %d", instanceType, rec.Code)
+	}
+	if _, ok := tier.get(getTransitionKey(bucketName, objectName)); ok {
+		t.Fatalf("%s: remote copy not removed on delete", instanceType)
+	}
+}
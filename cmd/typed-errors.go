@@ -55,9 +55,24 @@ var errServerTimeMismatch = errors.New("Server times are too far apart")
 // returned for 'minio', '.minio.sys'
 var errReservedBucket = errors.New("All access to this bucket is disabled")
 
+// errServerReadOnly - returned for write operations when the server
+// is started in read-only mode.
+var errServerReadOnly = errors.New("Server is in read-only mode, write operations are disabled")
+
+// errServerDegraded - returned for write operations when the server
+// is in degraded read-only mode.
+var errServerDegraded = errors.New("Server is in degraded read-only mode, not enough disks are online for write operations")
+
+// errServerMissingDisks - returned for write operations when the
+// server started with disks missing and the start is not confirmed.
+var errServerMissingDisks = errors.New("Server started with disks missing, write operations are disabled until they are online or the start is confirmed")
+
 // errInvalidRange - returned when given range value is not valid.
 var errInvalidRange = errors.New("Invalid range")
 
 // errInvalidRangeSource - returned when given range value exceeds
 // the source object size.
 var errInvalidRangeSource = errors.New("Range specified exceeds source object size")
+
+// errCertExpiring - returned when a served certificate is about to expire.
+var errCertExpiring = errors.New("Certificate is about to expire")
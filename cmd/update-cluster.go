@@ -0,0 +1,187 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+// Maximum time to wait for the cluster to be healthy before a node is
+// restarted, and for the node to come back online.
+const clusterUpdateTimeout = 10 * time.Minute
+
+// Interval between two checks of nodes, reduced by tests.
+var clusterUpdatePollInterval = time.Second
+
+// errClusterUpdateTimeout - returned if a node is not back online
+// within clusterUpdateTimeout.
+var errClusterUpdateTimeout = errors.New("Timed out waiting for nodes to be online")
+
+// newClusterAdminPeer - returns an admin peer of the server at addr.
+// Reconnection is left to the caller, a restart command must not be
+// sent twice.
+func newClusterAdminPeer(addr string, cred credential, secure bool) adminPeer {
+	return adminPeer{
+		addr: addr,
+		cmdRunner: &remoteAdminClient{newAuthRPCClient(authConfig{
+			accessKey:        cred.AccessKey,
+			secretKey:        cred.SecretKey,
+			serverAddr:       addr,
+			serviceEndpoint:  path.Join(minioReservedBucketPath, adminPath),
+			secureConn:       secure,
+			serviceName:      "Admin",
+			disableReconnect: true,
+		})},
+	}
+}
+
+// getClusterAdminPeers - returns admin peers of all servers of the
+// setup addr belongs to, the server at addr comes last so that it is
+// restarted after all others.
+func getClusterAdminPeers(addr string, cred credential, secure bool) (adminPeers, error) {
+	peer := newClusterAdminPeer(addr, cred, secure)
+	addrs, err := peer.cmdRunner.ClusterPeers()
+	if err != nil {
+		return nil, err
+	}
+	var peers adminPeers
+	for _, peerAddr := range addrs {
+		peers = append(peers, newClusterAdminPeer(peerAddr, cred, secure))
+	}
+	return append(peers, peer), nil
+}
+
+// waitForClusterOnline - waits until all nodes are initialized, so
+// that the cluster keeps its quorum while one of them is restarted.
+func waitForClusterOnline(peers adminPeers, timeout time.Duration) error {
+	deadline := UTCNow().Add(timeout)
+	for _, peer := range peers {
+		for {
+			if _, err := peer.cmdRunner.ServerInfoData(); err == nil {
+				break
+			}
+			if UTCNow().After(deadline) {
+				return fmt.Errorf("%s is offline: %v", peer.addr, errClusterUpdateTimeout)
+			}
+			time.Sleep(clusterUpdatePollInterval)
+		}
+	}
+	return nil
+}
+
+// restartClusterPeer - restarts a node and waits until it is back
+// online, i.e. it was initialized after the restart command was sent.
+func restartClusterPeer(peer adminPeer, timeout time.Duration) (ServerInfoData, error) {
+	restartTime := UTCNow()
+	// The connection may be closed before the reply is sent.
+	if err := peer.cmdRunner.Restart(); err != nil && err != rpc.ErrShutdown && err != io.ErrUnexpectedEOF {
+		return ServerInfoData{}, err
+	}
+
+	deadline := restartTime.Add(timeout)
+	for {
+		serverInfoData, err := peer.cmdRunner.ServerInfoData()
+		if err == nil && serverInfoData.Properties.Uptime < UTCNow().Sub(restartTime) {
+			return serverInfoData, nil
+		}
+		if UTCNow().After(deadline) {
+			return ServerInfoData{}, errClusterUpdateTimeout
+		}
+		time.Sleep(clusterUpdatePollInterval)
+	}
+}
+
+// parseClusterEndpoint - parses the URL of a server of the cluster,
+// port defaults to the one of the scheme.
+func parseClusterEndpoint(arg string) (addr string, secure bool, err error) {
+	addr, secure, err = parseGatewayEndpoint(arg)
+	if err != nil {
+		return "", false, err
+	}
+	if addr == "" {
+		return "", false, fmt.Errorf("Missing host in %s", arg)
+	}
+	if _, _, err = net.SplitHostPort(addr); err != nil {
+		port := "80"
+		if secure {
+			port = "443"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+	return addr, secure, nil
+}
+
+// rollingRestart - restarts nodes one at a time, each node is restarted
+// once all nodes are online and the previous one is back, so that the
+// rest of the cluster maintains quorum. Restarted nodes run the minio
+// binary installed in place of the previous one.
+func rollingRestart(peers adminPeers, timeout time.Duration) error {
+	for i, peer := range peers {
+		if err := waitForClusterOnline(peers, timeout); err != nil {
+			return err
+		}
+		log.Printf("Restarting %s (%d/%d).\n", peer.addr, i+1, len(peers))
+		serverInfoData, err := restartClusterPeer(peer, timeout)
+		if err != nil {
+			return fmt.Errorf("Unable to restart %s: %v", peer.addr, err)
+		}
+		log.Printf("%s is online, running version %s.\n", peer.addr, serverInfoData.Properties.Version)
+	}
+	return nil
+}
+
+// mainUpdateCluster - restarts all servers of the distributed setup of
+// the given server one at a time, once the new minio binary is
+// installed on each of them.
+func mainUpdateCluster(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "update", -1)
+	}
+
+	addr, secure, err := parseClusterEndpoint(ctx.Args().First())
+	fatalIf(err, "Unable to parse endpoint %s", ctx.Args().First())
+
+	cred, err := createCredential(os.Getenv("MINIO_ACCESS_KEY"), os.Getenv("MINIO_SECRET_KEY"))
+	fatalIf(err, "Access and secret keys of the servers are mandatory to update a cluster.")
+
+	// Server certificates may be signed by CAs installed in the config
+	// directory.
+	if ctx.GlobalIsSet("config-dir") {
+		configDirAbs, err := filepath.Abs(ctx.GlobalString("config-dir"))
+		fatalIf(err, "Unable to fetch absolute path for config directory %s", ctx.GlobalString("config-dir"))
+		setConfigDir(configDirAbs)
+	}
+	if rootCAs, err := getRootCAs(getCADir()); err == nil {
+		globalRootCAs = rootCAs
+	}
+
+	peers, err := getClusterAdminPeers(addr, cred, secure)
+	fatalIf(err, "Unable to get the servers of the setup of %s.", addr)
+
+	fatalIf(rollingRestart(peers, clusterUpdateTimeout), "Unable to update the cluster.")
+	log.Printf("All %d servers restarted.\n", len(peers))
+}
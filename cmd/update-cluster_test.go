@@ -0,0 +1,180 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCluster - state of fake nodes restarted by rollingRestart.
+type testCluster struct {
+	sync.Mutex
+	// Number of nodes offline, and the maximum reached.
+	offline    int
+	maxOffline int
+	restarts   []string
+}
+
+// testClusterNode - fake node, offline for a few checks after a
+// restart, or for good if it never comes back.
+type testClusterNode struct {
+	localAdminClient
+	cluster    *testCluster
+	addr       string
+	bootTime   time.Time
+	downChecks int
+	neverBack  bool
+}
+
+func (n *testClusterNode) Restart() error {
+	n.cluster.Lock()
+	defer n.cluster.Unlock()
+	n.cluster.restarts = append(n.cluster.restarts, n.addr)
+	n.cluster.offline++
+	if n.cluster.offline > n.cluster.maxOffline {
+		n.cluster.maxOffline = n.cluster.offline
+	}
+	n.downChecks = 3
+	return nil
+}
+
+func (n *testClusterNode) ServerInfoData() (ServerInfoData, error) {
+	n.cluster.Lock()
+	defer n.cluster.Unlock()
+	if n.downChecks > 0 {
+		if !n.neverBack {
+			n.downChecks--
+		}
+		if n.downChecks == 0 {
+			n.cluster.offline--
+			n.bootTime = UTCNow()
+		}
+		return ServerInfoData{}, errServerNotInitialized
+	}
+	return ServerInfoData{Properties: ServerProperties{
+		Uptime:  UTCNow().Sub(n.bootTime),
+		Version: Version,
+	}}, nil
+}
+
+func newTestClusterPeers(cluster *testCluster, addrs ...string) (peers adminPeers) {
+	for _, addr := range addrs {
+		peers = append(peers, adminPeer{addr, &testClusterNode{
+			cluster:  cluster,
+			addr:     addr,
+			bootTime: UTCNow().Add(-time.Hour),
+		}})
+	}
+	return peers
+}
+
+// Tests nodes are restarted one at a time.
+func TestRollingRestart(t *testing.T) {
+	defer func(interval time.Duration) { clusterUpdatePollInterval = interval }(clusterUpdatePollInterval)
+	clusterUpdatePollInterval = time.Millisecond
+
+	cluster := &testCluster{}
+	peers := newTestClusterPeers(cluster, "node2:9000", "node3:9000", "node4:9000", "node1:9000")
+	if err := rollingRestart(peers, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if cluster.maxOffline != 1 {
+		t.Fatalf("Expected one node offline at a time, got %d", cluster.maxOffline)
+	}
+	if len(cluster.restarts) != len(peers) {
+		t.Fatalf("Expected %d restarts, got %v", len(peers), cluster.restarts)
+	}
+	for i, peer := range peers {
+		if cluster.restarts[i] != peer.addr {
+			t.Fatalf("Expected %s to be restarted at %d, got %s", peer.addr, i+1, cluster.restarts[i])
+		}
+	}
+
+	// A node which does not come back stops the update.
+	cluster = &testCluster{}
+	peers = newTestClusterPeers(cluster, "node2:9000", "node3:9000", "node1:9000")
+	peers[0].cmdRunner.(*testClusterNode).neverBack = true
+	if err := rollingRestart(peers, 50*time.Millisecond); err == nil {
+		t.Fatal("Expected update to fail")
+	}
+	if len(cluster.restarts) != 1 {
+		t.Fatalf("Expected other nodes not to be restarted, got %v", cluster.restarts)
+	}
+
+	// An offline node stops the update before any restart.
+	cluster = &testCluster{}
+	peers = newTestClusterPeers(cluster, "node2:9000", "node1:9000")
+	peers[1].cmdRunner.(*testClusterNode).downChecks = 1
+	peers[1].cmdRunner.(*testClusterNode).neverBack = true
+	if err := rollingRestart(peers, 50*time.Millisecond); err == nil {
+		t.Fatal("Expected update to fail")
+	}
+	if len(cluster.restarts) != 0 {
+		t.Fatalf("Expected no restart, got %v", cluster.restarts)
+	}
+}
+
+// testFailingNode - fake node refusing restart commands.
+type testFailingNode struct {
+	testClusterNode
+}
+
+func (n *testFailingNode) Restart() error {
+	return errAuthentication
+}
+
+// Tests a restart command failing on the node is reported.
+func TestRestartClusterPeer(t *testing.T) {
+	peer := adminPeer{"node1:9000", &testFailingNode{}}
+	if _, err := restartClusterPeer(peer, time.Minute); err != errAuthentication {
+		t.Fatalf("Expected %v, got %v", errAuthentication, err)
+	}
+}
+
+// Tests parsing of the endpoint of a server of the cluster.
+func TestParseClusterEndpoint(t *testing.T) {
+	testCases := []struct {
+		arg         string
+		addr        string
+		secure      bool
+		expectedErr bool
+	}{
+		// Test 1: https by default.
+		{"node1:9000", "node1:9000", true, false},
+		// Test 2: plain HTTP.
+		{"http://node1:9000", "node1:9000", false, false},
+		// Test 3: default HTTPS port.
+		{"https://node1", "node1:443", true, false},
+		// Test 4: default HTTP port.
+		{"http://node1", "node1:80", false, false},
+		// Test 5: unknown scheme.
+		{"ftp://node1:9000", "", false, true},
+		// Test 6: missing host.
+		{"http://", "", false, true},
+	}
+	for i, testCase := range testCases {
+		addr, secure, err := parseClusterEndpoint(testCase.arg)
+		if (err != nil) != testCase.expectedErr {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if addr != testCase.addr || secure != testCase.secure {
+			t.Errorf("Test %d: expected %s, %v, got %s, %v", i+1, testCase.addr, testCase.secure, addr, secure)
+		}
+	}
+}
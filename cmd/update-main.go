@@ -42,12 +42,16 @@ var updateCmd = cli.Command{
 			Name:  "quiet",
 			Usage: "Disable any update messages.",
 		},
+		cli.BoolFlag{
+			Name:  "cluster",
+			Usage: "Restart servers of a distributed setup one at a time to run the installed binary.",
+		},
 	},
 	CustomHelpTemplate: `Name:
    {{.HelpName}} - {{.Usage}}
 
 USAGE:
-   {{.HelpName}}{{if .VisibleFlags}} [FLAGS]{{end}}
+   {{.HelpName}}{{if .VisibleFlags}} [FLAGS]{{end}} [SERVER-ENDPOINT]
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -60,6 +64,11 @@ EXIT STATUS:
 EXAMPLES:
    1. Check if there is a new update available:
        $ {{.HelpName}}
+
+   2. Restart servers of a distributed setup one at a time, once the new binary is installed on all of them:
+       $ export MINIO_ACCESS_KEY=minio
+       $ export MINIO_SECRET_KEY=miniostorage
+       $ {{.HelpName}} --cluster https://192.168.1.11:9000
 `,
 }
 
@@ -255,6 +264,11 @@ func getUpdateInfo(timeout time.Duration, mode string) (older time.Duration, dow
 }
 
 func mainUpdate(ctx *cli.Context) {
+	if ctx.Bool("cluster") {
+		mainUpdateCluster(ctx)
+		return
+	}
+
 	if len(ctx.Args()) != 0 {
 		cli.ShowCommandHelpAndExit(ctx, "update", -1)
 	}
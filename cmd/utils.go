@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
@@ -30,7 +31,6 @@ import (
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
-	"github.com/pkg/profile"
 )
 
 // make a copy of http.Header
@@ -86,8 +86,15 @@ func xmlDecoder(body io.Reader, v interface{}, size int64) error {
 }
 
 // checkValidMD5 - verify if valid md5, returns md5 in bytes.
-func checkValidMD5(md5 string) ([]byte, error) {
-	return base64.StdEncoding.DecodeString(strings.TrimSpace(md5))
+func checkValidMD5(contentMD5 string) ([]byte, error) {
+	md5Bytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(contentMD5))
+	if err != nil {
+		return nil, err
+	}
+	if len(md5Bytes) != md5.Size {
+		return nil, errInvalidArgument
+	}
+	return md5Bytes, nil
 }
 
 /// http://docs.aws.amazon.com/AmazonS3/latest/dev/UploadingObjects.html
@@ -98,14 +105,16 @@ const (
 	// using 'curl' and presigned URL.
 	globalMaxObjectSize = 16 * humanize.GiByte
 
-	// Minimum Part size for multipart upload is 5MiB
+	// Default minimum Part size for multipart upload is 5MiB,
+	// can be lowered or raised in config.
 	globalMinPartSize = 5 * humanize.MiByte
 
 	// Maximum Part size for multipart upload is 5GiB
 	globalMaxPartSize = 5 * humanize.GiByte
 
 	// Maximum Part ID for multipart upload is 10000
-	// (Acceptable values range from 1 to 10000 inclusive)
+	// (Acceptable values range from 1 to 10000 inclusive),
+	// can be lowered in config.
 	globalMaxPartID = 10000
 )
 
@@ -121,12 +130,12 @@ func isMaxAllowedPartSize(size int64) bool {
 
 // Check if part size is more than or equal to minimum allowed size.
 func isMinAllowedPartSize(size int64) bool {
-	return size >= globalMinPartSize
+	return size >= getMinPartSize()
 }
 
 // isMaxPartNumber - Check if part ID is greater than the maximum allowed ID.
 func isMaxPartID(partID int) bool {
-	return partID > globalMaxPartID
+	return partID > getMaxParts()
 }
 
 func contains(stringList []string, element string) bool {
@@ -138,28 +147,6 @@ func contains(stringList []string, element string) bool {
 	return false
 }
 
-// Starts a profiler returns nil if profiler is not enabled, caller needs to handle this.
-func startProfiler(profiler string) interface {
-	Stop()
-} {
-	// Enable profiler if ``_MINIO_PROFILER`` is set. Supported options are [cpu, mem, block].
-	switch profiler {
-	case "cpu":
-		return profile.Start(profile.CPUProfile, profile.NoShutdownHook)
-	case "mem":
-		return profile.Start(profile.MemProfile, profile.NoShutdownHook)
-	case "block":
-		return profile.Start(profile.BlockProfile, profile.NoShutdownHook)
-	default:
-		return nil
-	}
-}
-
-// Global profiler to be used by service go-routine.
-var globalProfiler interface {
-	Stop()
-}
-
 // dump the request into a string in JSON format.
 func dumpRequest(r *http.Request) string {
 	header := cloneHeader(r.Header)
@@ -216,7 +216,7 @@ func TestURL2BucketObjectName(t *testing.T) {
 
 // Add tests for starting and stopping different profilers.
 func TestStartProfiler(t *testing.T) {
-	if startProfiler("") != nil {
+	if p, err := startProfiler(""); p != nil || err != errInvalidProfileType {
 		t.Fatal("Expected nil, but non-nil value returned for invalid profiler.")
 	}
 }
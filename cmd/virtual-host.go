@@ -0,0 +1,109 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// virtualHostContextKey - context key of the path of a virtual-host
+// style request as sent by the client.
+type virtualHostContextKey struct{}
+
+// isValidDomain - returns true if domain is a valid DNS name, e.g
+// "minio.example.com".
+func isValidDomain(domain string) bool {
+	if domain == "" || len(domain) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getVirtualHostBucket - returns the bucket of a virtual-host style
+// request to host, i.e "bucket.minio.example.com" for domain
+// "minio.example.com". Returns empty string for path-style requests.
+func getVirtualHostBucket(host, domain string) string {
+	if domain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	if !strings.HasSuffix(host, "."+domain) {
+		return ""
+	}
+	bucket := strings.TrimSuffix(host, "."+domain)
+	if !IsValidBucketName(bucket) || isMinioMetaBucketName(bucket) {
+		return ""
+	}
+	return bucket
+}
+
+// getSignedPath - returns the path of a request signed by the client,
+// which is not prefixed with the bucket for virtual-host style
+// requests.
+func getSignedPath(r *http.Request) string {
+	if path, ok := r.Context().Value(virtualHostContextKey{}).(string); ok {
+		return path
+	}
+	return r.URL.Path
+}
+
+// virtualHostHandler rewrites virtual-host style requests to path-style.
+type virtualHostHandler struct {
+	handler http.Handler
+}
+
+// setVirtualHostHandler - rewrites requests to "bucket.<domain>/object"
+// to "<domain>/bucket/object" when a domain name is configured, so
+// that handlers and routers only deal with path-style requests.
+func setVirtualHostHandler(h http.Handler) http.Handler {
+	return virtualHostHandler{handler: h}
+}
+
+func (h virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket := getVirtualHostBucket(r.Host, globalDomainName)
+	if bucket == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Save the path signed by the client for signature verification.
+	r = r.WithContext(context.WithValue(r.Context(), virtualHostContextKey{}, r.URL.Path))
+	r.URL.Path = slashSeparator + bucket + r.URL.Path
+	if r.URL.RawPath != "" {
+		r.URL.RawPath = slashSeparator + bucket + r.URL.RawPath
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,171 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Tests validating domain names.
+func TestIsValidDomain(t *testing.T) {
+	testCases := []struct {
+		domain string
+		valid  bool
+	}{
+		// Test 1: empty domain.
+		{"", false},
+		// Test 2: single label.
+		{"localhost", true},
+		// Test 3: multiple labels.
+		{"minio.example.com", true},
+		// Test 4: labels with digits and hyphens.
+		{"s3-1.Example-2.com", true},
+		// Test 5: with scheme.
+		{"http://minio.example.com", false},
+		// Test 6: with port.
+		{"minio.example.com:9000", false},
+		// Test 7: empty label.
+		{"minio..example.com", false},
+		// Test 8: label starting with hyphen.
+		{"-minio.example.com", false},
+		// Test 9: label ending with hyphen.
+		{"minio-.example.com", false},
+		// Test 10: label longer than 63 characters.
+		{string(bytes.Repeat([]byte("a"), 64)) + ".com", false},
+	}
+	for i, testCase := range testCases {
+		if valid := isValidDomain(testCase.domain); valid != testCase.valid {
+			t.Errorf("Test %d: expected %v for %q, got %v", i+1, testCase.valid, testCase.domain, valid)
+		}
+	}
+}
+
+// Tests extracting the bucket of virtual-host style requests.
+func TestGetVirtualHostBucket(t *testing.T) {
+	testCases := []struct {
+		host   string
+		domain string
+		bucket string
+	}{
+		// Test 1: no domain configured.
+		{"bucket.minio.example.com", "", ""},
+		// Test 2: path-style request.
+		{"minio.example.com", "minio.example.com", ""},
+		// Test 3: virtual-host style request.
+		{"bucket.minio.example.com", "minio.example.com", "bucket"},
+		// Test 4: virtual-host style request with port.
+		{"bucket.minio.example.com:9000", "minio.example.com", "bucket"},
+		// Test 5: host is case insensitive.
+		{"Bucket.MINIO.example.com", "minio.example.com", "bucket"},
+		// Test 6: bucket with dots.
+		{"my.bucket.minio.example.com", "minio.example.com", "my.bucket"},
+		// Test 7: other domain.
+		{"bucket.example.com", "minio.example.com", ""},
+		// Test 8: domain only as a suffix of a label.
+		{"bucketminio.example.com", "minio.example.com", ""},
+		// Test 9: invalid bucket name.
+		{"b.minio.example.com", "minio.example.com", ""},
+		// Test 10: IP address.
+		{"127.0.0.1:9000", "minio.example.com", ""},
+	}
+	for i, testCase := range testCases {
+		if bucket := getVirtualHostBucket(testCase.host, testCase.domain); bucket != testCase.bucket {
+			t.Errorf("Test %d: expected bucket %q, got %q", i+1, testCase.bucket, bucket)
+		}
+	}
+}
+
+// Tests virtual-host style requests signed with signature V4 are
+// served as path-style requests.
+func TestVirtualHostRequests(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	initNSLock(false)
+
+	globalDomainName = "minio.example.com"
+	defer func() { globalDomainName = "" }()
+
+	testServer := StartTestServer(t, "FS")
+	defer testServer.Stop()
+
+	u, err := url.Parse(testServer.Server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := http.Client{}
+	bucket, object := "bucket", "dir/object"
+	data := []byte("hello, world")
+
+	// Sends a request signed for host, to the test server.
+	do := func(method, host, path string, body []byte) *http.Response {
+		req, rerr := newTestSignedRequestV4(method, "http://"+host+path, int64(len(body)), bytes.NewReader(body),
+			testServer.AccessKey, testServer.SecretKey)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		req.URL.Host = u.Host
+		resp, rerr := client.Do(req)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		return resp
+	}
+
+	testCases := []struct {
+		method       string
+		host         string
+		path         string
+		body         []byte
+		expectedCode int
+		expectedBody []byte
+	}{
+		// Test 1: path-style make bucket on domain.
+		{"PUT", "minio.example.com", "/" + bucket, nil, http.StatusOK, nil},
+		// Test 2: virtual-host style put object.
+		{"PUT", bucket + ".minio.example.com", "/" + object, data, http.StatusOK, nil},
+		// Test 3: virtual-host style get object.
+		{"GET", bucket + ".minio.example.com:9000", "/" + object, nil, http.StatusOK, data},
+		// Test 4: path-style get of the same object.
+		{"GET", u.Host, "/" + bucket + "/" + object, nil, http.StatusOK, data},
+		// Test 5: virtual-host style head bucket.
+		{"HEAD", bucket + ".minio.example.com", "/", nil, http.StatusOK, nil},
+		// Test 6: virtual-host style request of a missing bucket.
+		{"GET", "missing-bucket.minio.example.com", "/" + object, nil, http.StatusNotFound, nil},
+	}
+	for i, testCase := range testCases {
+		resp := do(testCase.method, testCase.host, testCase.path, testCase.body)
+		body, rerr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i+1, testCase.expectedCode, resp.StatusCode, body)
+		}
+		if testCase.expectedBody != nil && !bytes.Equal(body, testCase.expectedBody) {
+			t.Fatalf("Test %d: expected body %q, got %q", i+1, testCase.expectedBody, body)
+		}
+	}
+}
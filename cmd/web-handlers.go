@@ -122,13 +122,16 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerWriteable(); err != nil {
+		return toJSONError(err)
+	}
 
 	// Check if bucket is a reserved bucket name.
 	if isMinioMetaBucket(args.BucketName) || isMinioReservedBucket(args.BucketName) {
 		return toJSONError(errReservedBucket)
 	}
 
-	bucketLock := globalNSMutex.NewNSLock(args.BucketName, "")
+	bucketLock := web.NSMutex().NewRequestNSLock(r, args.BucketName, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 	if err := objectAPI.MakeBucket(args.BucketName); err != nil {
@@ -279,6 +282,9 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerWriteable(); err != nil {
+		return toJSONError(err)
+	}
 
 	if args.BucketName == "" || len(args.Objects) == 0 {
 		return toJSONError(errInvalidArgument)
@@ -289,7 +295,7 @@ next:
 	for _, objectName := range args.Objects {
 		// If not a directory, remove the object.
 		if !hasSuffix(objectName, slashSeparator) && objectName != "" {
-			if err = deleteObject(objectAPI, args.BucketName, objectName, r); err != nil {
+			if err = deleteObject(objectAPI, web.NSMutex(), args.BucketName, objectName, r); err != nil {
 				break next
 			}
 			continue
@@ -305,7 +311,7 @@ next:
 			}
 			marker = lo.NextMarker
 			for _, obj := range lo.Objects {
-				err = deleteObject(objectAPI, args.BucketName, obj.Name, r)
+				err = deleteObject(objectAPI, web.NSMutex(), args.BucketName, obj.Name, r)
 				if err != nil {
 					break next
 				}
@@ -403,10 +409,10 @@ func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *Se
 	errsMap := updateCredsOnPeers(creds)
 
 	// Update local credentials
-	serverConfig.SetCredential(creds)
+	web.Config().SetCredential(creds)
 
 	// Persist updated credentials.
-	if err = serverConfig.Save(); err != nil {
+	if err = web.Config().Save(); err != nil {
 		errsMap[globalMinioAddr] = err
 	}
 
@@ -458,7 +464,7 @@ func (web *webAPIHandlers) GetAuth(r *http.Request, args *WebGenericArgs, reply
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
-	creds := serverConfig.GetCredential()
+	creds := web.Config().GetCredential()
 	reply.AccessKey = creds.AccessKey
 	reply.SecretKey = creds.SecretKey
 	reply.UIVersion = browser.UIVersion
@@ -486,6 +492,10 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
+	if err := checkServerWriteable(); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
 
 	// Require Content-Length to be set in the request
 	size := r.ContentLength
@@ -496,12 +506,23 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 
 	// Extract incoming metadata if any.
 	metadata := extractMetadataFromHeader(r.Header)
+	applyDefaultMetadata(objectAPI, bucket, object, metadata)
+	if s3Error := setObjectLockMetadata(objectAPI, bucket, r.Header, metadata); s3Error != ErrNone {
+		writeWebErrorResponse(w, errors.New(getAPIError(s3Error).Description))
+		return
+	}
 
 	// Lock the object.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := web.NSMutex().NewRequestNSLock(r, bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
+	// Objects of WORM buckets can't be overwritten.
+	if err := checkWormOverwrite(objectAPI, bucket, object); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
 	sha256sum := ""
 	objInfo, err := objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
 	if err != nil {
@@ -540,7 +561,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", path.Base(object)))
 
 	// Lock the object before reading.
-	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock := web.NSMutex().NewRequestNSLock(r, bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
@@ -753,6 +774,9 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 	if !isHTTPRequestValid(r) {
 		return toJSONError(errAuthentication)
 	}
+	if err := checkServerWriteable(); err != nil {
+		return toJSONError(err)
+	}
 
 	bucketP := policy.BucketPolicy(args.Policy)
 	if !bucketP.IsValidBucketPolicy() {
@@ -828,14 +852,135 @@ func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs,
 		}
 	}
 	reply.UIVersion = browser.UIVersion
-	reply.URL = presignedGet(args.HostName, args.BucketName, args.ObjectName, args.Expiry)
+	reply.URL = presignedGet(web.Config(), args.HostName, args.BucketName, args.ObjectName, args.Expiry, "")
+	return nil
+}
+
+// CreateShareLinkArgs - create share link API args.
+type CreateShareLinkArgs struct {
+	// Host header required for signed headers.
+	HostName string `json:"host"`
+
+	// Bucket name of the object to be shared.
+	BucketName string `json:"bucket"`
+
+	// Object name to be shared.
+	ObjectName string `json:"object"`
+
+	// Expiry in seconds.
+	Expiry int64 `json:"expiry"`
+}
+
+// CreateShareLinkRep - create share link reply.
+type CreateShareLinkRep struct {
+	UIVersion string `json:"uiVersion"`
+	// ID of the share link, used to revoke it.
+	ID string `json:"id"`
+	// Presigned URL of the object.
+	URL string `json:"url"`
+	// Time after which the URL is not valid.
+	Expiry time.Time `json:"expiry"`
+}
+
+// CreateShareLink - returns a presigned GET url of an object tracked
+// by the server, which can be revoked before it expires.
+func (web *webAPIHandlers) CreateShareLink(r *http.Request, args *CreateShareLinkArgs, reply *CreateShareLinkRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+
+	if args.BucketName == "" || args.ObjectName == "" {
+		return &json2.Error{
+			Message: "Bucket and Object are mandatory arguments.",
+		}
+	}
+	if _, err := objectAPI.GetObjectInfo(args.BucketName, args.ObjectName); err != nil {
+		return toJSONError(err, args.BucketName, args.ObjectName)
+	}
+
+	link, err := newShareLink(args.BucketName, args.ObjectName, args.Expiry, objectAPI)
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = browser.UIVersion
+	reply.ID = link.ID
+	reply.URL = presignedGet(web.Config(), args.HostName, args.BucketName, args.ObjectName, int64(link.Expiry.Sub(link.Created)/time.Second), link.ID)
+	reply.Expiry = link.Expiry
 	return nil
 }
 
-// Returns presigned url for GET method.
-func presignedGet(host, bucket, object string, expiry int64) string {
-	cred := serverConfig.GetCredential()
-	region := serverConfig.GetRegion()
+// ListShareLinksArgs - list share links API args.
+type ListShareLinksArgs struct {
+	BucketName string `json:"bucketName"`
+}
+
+// ListShareLinksRep - list share links reply.
+type ListShareLinksRep struct {
+	UIVersion string `json:"uiVersion"`
+	// Unexpired share links of the bucket, including revoked ones.
+	Links []ShareLinkInfo `json:"links"`
+}
+
+// ListShareLinks - lists unexpired share links of a bucket.
+func (web *webAPIHandlers) ListShareLinks(r *http.Request, args *ListShareLinksArgs, reply *ListShareLinksRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(args.BucketName); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+
+	links, err := loadBucketShareLinks(args.BucketName, objectAPI)
+	if err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	links.removeExpired(UTCNow())
+	reply.UIVersion = browser.UIVersion
+	reply.Links = links.Links
+	return nil
+}
+
+// RevokeShareLinkArgs - revoke share link API args.
+type RevokeShareLinkArgs struct {
+	BucketName string `json:"bucketName"`
+	ID         string `json:"id"`
+}
+
+// RevokeShareLink - revokes a share link, its URL is denied from now on.
+func (web *webAPIHandlers) RevokeShareLink(r *http.Request, args *RevokeShareLinkArgs, reply *WebGenericRep) error {
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil {
+		return toJSONError(errServerNotInitialized)
+	}
+	if !isHTTPRequestValid(r) {
+		return toJSONError(errAuthentication)
+	}
+
+	if _, err := objectAPI.GetBucketInfo(args.BucketName); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+
+	if err := revokeShareLink(args.BucketName, args.ID, objectAPI); err != nil {
+		return toJSONError(err, args.BucketName)
+	}
+	reply.UIVersion = browser.UIVersion
+	return nil
+}
+
+// Returns presigned url for GET method, URLs of share links carry the
+// ID of the link.
+func presignedGet(config *serverConfigV36, host, bucket, object string, expiry int64, shareID string) string {
+	cred := config.GetCredential()
+	region := config.GetRegion()
 
 	accessKey := cred.AccessKey
 	secretKey := cred.SecretKey
@@ -848,13 +993,19 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	if expiry < 604800 && expiry > 0 {
 		expiryStr = strconv.FormatInt(expiry, 10)
 	}
-	query := strings.Join([]string{
+	queryParams := []string{
 		"X-Amz-Algorithm=" + signV4Algorithm,
 		"X-Amz-Credential=" + strings.Replace(credential, "/", "%2F", -1),
 		"X-Amz-Date=" + dateStr,
 		"X-Amz-Expires=" + expiryStr,
 		"X-Amz-SignedHeaders=host",
-	}, "&")
+	}
+	// Sorts after all the X-Amz params as the canonical query string
+	// needs sorted params.
+	if shareID != "" {
+		queryParams = append(queryParams, shareLinkIDQueryKey+"="+shareID)
+	}
+	query := strings.Join(queryParams, "&")
 
 	path := "/" + path.Join(bucket, object)
 
@@ -962,47 +1113,66 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errServerReadOnly {
+		return APIError{
+			Code:           "AccessDenied",
+			HTTPStatusCode: http.StatusForbidden,
+			Description:    err.Error(),
+		}
+	} else if err == errServerDegraded {
+		return APIError{
+			Code:           "XMinioServerDegraded",
+			HTTPStatusCode: http.StatusServiceUnavailable,
+			Description:    err.Error(),
+		}
+	} else if err == errServerMissingDisks {
+		return APIError{
+			Code:           "XMinioServerMissingDisks",
+			HTTPStatusCode: http.StatusServiceUnavailable,
+			Description:    err.Error(),
+		}
+	} else if err == errObjectWormProtected {
+		return APIError{
+			Code:           "MethodNotAllowed",
+			HTTPStatusCode: http.StatusMethodNotAllowed,
+			Description:    err.Error(),
+		}
+	} else if err == errObjectLocked {
+		return APIError{
+			Code:           "AccessDenied",
+			HTTPStatusCode: http.StatusForbidden,
+			Description:    err.Error(),
+		}
 	} else if err == errInvalidArgument {
 		return APIError{
 			Code:           "InvalidArgument",
 			HTTPStatusCode: http.StatusBadRequest,
 			Description:    err.Error(),
 		}
+	} else if err == errNoSuchShareLink {
+		return APIError{
+			Code:           "XMinioNoSuchShareLink",
+			HTTPStatusCode: http.StatusNotFound,
+			Description:    err.Error(),
+		}
+	} else if err == errTooManyShareLinks {
+		return APIError{
+			Code:           "XMinioTooManyShareLinks",
+			HTTPStatusCode: http.StatusBadRequest,
+			Description:    err.Error(),
+		}
+	}
+	// Browser reports invalid object names as missing objects.
+	if _, ok := err.(ObjectNameInvalid); ok {
+		return getAPIError(ErrNoSuchKey)
 	}
 	// Convert error type to api error code.
-	var apiErrCode APIErrorCode
-	switch err.(type) {
-	case StorageFull:
-		apiErrCode = ErrStorageFull
-	case BucketNotFound:
-		apiErrCode = ErrNoSuchBucket
-	case BucketExists:
-		apiErrCode = ErrBucketAlreadyOwnedByYou
-	case BucketNameInvalid:
-		apiErrCode = ErrInvalidBucketName
-	case BadDigest:
-		apiErrCode = ErrBadDigest
-	case IncompleteBody:
-		apiErrCode = ErrIncompleteBody
-	case ObjectExistsAsDirectory:
-		apiErrCode = ErrObjectExistsAsDirectory
-	case ObjectNotFound:
-		apiErrCode = ErrNoSuchKey
-	case ObjectNameInvalid:
-		apiErrCode = ErrNoSuchKey
-	case InsufficientWriteQuorum:
-		apiErrCode = ErrWriteQuorum
-	case InsufficientReadQuorum:
-		apiErrCode = ErrReadQuorum
-	case PolicyNesting:
-		apiErrCode = ErrPolicyNesting
-	default:
+	apiErrCode := toAPIErrorCode(err)
+	if apiErrCode == ErrInternalError {
 		// Log unexpected and unhandled errors.
 		errorIf(err, errUnexpected.Error())
-		apiErrCode = ErrInternalError
 	}
-	apiErr := getAPIError(apiErrCode)
-	return apiErr
+	return getAPIError(apiErrCode)
 }
 
 // writeWebErrorResponse - set HTTP status code and write error description to the body.
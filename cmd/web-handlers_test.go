@@ -32,6 +32,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio-go/pkg/policy"
@@ -1014,6 +1015,128 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 	}
 }
 
+// Wrapper for calling share links handlers
+func TestWebHandlerShareLinksHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testWebShareLinksHandler)
+}
+
+// testWebShareLinksHandler - Test CreateShareLink, ListShareLinks and
+// RevokeShareLink web handlers.
+func testWebShareLinksHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	webRouter := initTestWebRPCEndPoint(obj)
+	credentials := serverConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(webRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	objectName := "object"
+	if err = obj.MakeBucket(bucketName); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	data := bytes.Repeat([]byte("a"), 1*humanize.KiByte)
+	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+
+	callWeb := func(method string, args interface{}, reply interface{}) error {
+		req, rerr := newTestWebRPCRequest(method, authorization, args)
+		if rerr != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", rerr)
+		}
+		rec := httptest.NewRecorder()
+		webRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
+	}
+
+	apiRouter := initTestAPIEndPoints(obj, []string{"GetObject"})
+	getURL := func(url string) int {
+		req, rerr := newTestRequest("GET", url, 0, nil)
+		if rerr != nil {
+			t.Fatal("Failed to initialized a new request", rerr)
+		}
+		req.Header.Del("x-amz-content-sha256")
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Links can't be created for missing objects.
+	createRep := &CreateShareLinkRep{}
+	err = callWeb("Web.CreateShareLink", CreateShareLinkArgs{BucketName: bucketName, ObjectName: "missing"}, createRep)
+	if err == nil {
+		t.Fatalf("Expected share link of a missing object to fail")
+	}
+
+	createRep = &CreateShareLinkRep{}
+	err = callWeb("Web.CreateShareLink", CreateShareLinkArgs{BucketName: bucketName, ObjectName: objectName, Expiry: 1000}, createRep)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if createRep.ID == "" || !strings.Contains(createRep.URL, shareLinkIDQueryKey+"="+createRep.ID) {
+		t.Fatalf("Expected share link ID in URL %s", createRep.URL)
+	}
+	if code := getURL(createRep.URL); code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+
+	listRep := &ListShareLinksRep{}
+	if err = callWeb("Web.ListShareLinks", ListShareLinksArgs{BucketName: bucketName}, listRep); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(listRep.Links) != 1 || listRep.Links[0].ID != createRep.ID || listRep.Links[0].Object != objectName || listRep.Links[0].Revoked {
+		t.Fatalf("Unexpected share links %#v", listRep.Links)
+	}
+	if expiry := listRep.Links[0].Expiry.Sub(listRep.Links[0].Created); expiry != 1000*time.Second {
+		t.Fatalf("Expected share link to expire in 1000s, got %s", expiry)
+	}
+
+	// Unknown links can't be revoked.
+	err = callWeb("Web.RevokeShareLink", RevokeShareLinkArgs{BucketName: bucketName, ID: "unknown"}, &WebGenericRep{})
+	if err == nil || err.Error() != errNoSuchShareLink.Error() {
+		t.Fatalf("Expected %v, got %v", errNoSuchShareLink, err)
+	}
+
+	err = callWeb("Web.RevokeShareLink", RevokeShareLinkArgs{BucketName: bucketName, ID: createRep.ID}, &WebGenericRep{})
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if code := getURL(createRep.URL); code != http.StatusForbidden {
+		t.Fatalf("Expected the response status to be 403, but instead found `%d`", code)
+	}
+
+	// Revoked links are listed until they expire.
+	listRep = &ListShareLinksRep{}
+	if err = callWeb("Web.ListShareLinks", ListShareLinksArgs{BucketName: bucketName}, listRep); err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(listRep.Links) != 1 || !listRep.Links[0].Revoked || listRep.Links[0].RevokedAt.IsZero() {
+		t.Fatalf("Expected revoked share link, got %#v", listRep.Links)
+	}
+
+	// Presigned URLs without share link ID are not affected.
+	presignGetRep := &PresignedGetRep{}
+	err = callWeb("Web.PresignedGet", PresignedGetArgs{BucketName: bucketName, ObjectName: objectName, Expiry: 1000}, presignGetRep)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if code := getURL(presignGetRep.URL); code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+
+	// Share links of missing buckets can't be listed.
+	if err = callWeb("Web.ListShareLinks", ListShareLinksArgs{BucketName: "missing-bucket"}, &ListShareLinksRep{}); err == nil {
+		t.Fatalf("Expected listing share links of a missing bucket to fail")
+	}
+}
+
 // Wrapper for calling GetBucketPolicy Handler
 func TestWebHandlerGetBucketPolicyHandler(t *testing.T) {
 	ExecObjectLayerTest(t, testWebGetBucketPolicyHandler)
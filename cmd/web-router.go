@@ -30,7 +30,7 @@ import (
 
 // webAPI container for Web API.
 type webAPIHandlers struct {
-	ObjectAPI func() ObjectLayer
+	serverContext
 }
 
 // indexHandler - Handler to serve index.html
@@ -58,11 +58,9 @@ func assetFS() *assetfs.AssetFS {
 const specialAssets = "loader.css|logo.svg|firefox.png|safari.png|chrome.png|favicon.ico"
 
 // registerWebRouter - registers web router for serving minio browser.
-func registerWebRouter(mux *router.Router) error {
+func registerWebRouter(mux *router.Router, ctx serverContext) error {
 	// Initialize Web.
-	web := &webAPIHandlers{
-		ObjectAPI: newObjectLayerFn,
-	}
+	web := &webAPIHandlers{ctx}
 
 	// Initialize a new json2 codec.
 	codec := json2.NewCodec()
@@ -0,0 +1,291 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Page sent for website requests failing without an error document.
+const websiteErrorPage = `<html>
+<head><title>%[1]s</title></head>
+<body>
+<h1>%[1]s</h1>
+<ul>
+<li>Code: %[2]s</li>
+<li>Message: %[3]s</li>
+<li>RequestId: %[4]s</li>
+</ul>
+</body>
+</html>
+`
+
+// getWebsiteBucket - returns the bucket whose website is requested
+// on host, "bucket.<website domain>" or, if anyHost is set, the bucket
+// named like the host, e.g. "www.example.com" for a CNAME record of
+// the website address. Returns empty string if none is.
+func getWebsiteBucket(host string, anyHost bool) string {
+	if bucket := getVirtualHostBucket(host, globalWebsiteDomain); bucket != "" {
+		return bucket
+	}
+	if !anyHost {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	bucket := strings.ToLower(host)
+	if !IsValidBucketName(bucket) || isMinioMetaBucketName(bucket) || isMinioReservedBucket(bucket) {
+		return ""
+	}
+	return bucket
+}
+
+// websiteHandler - serves buckets with a website configuration as
+// static websites. Requests are anonymous, objects are served only
+// if the bucket policy allows anonymous reads of them.
+type websiteHandler struct {
+	// Serve buckets named like the host of requests as well, not
+	// only requests to the website domain.
+	anyHost bool
+}
+
+func (h websiteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != httpGET && r.Method != httpHEAD {
+		writeWebsiteErrorPage(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		writeWebsiteErrorPage(w, r, ErrServerNotInitialized)
+		return
+	}
+
+	bucket := getWebsiteBucket(r.Host, h.anyHost)
+	if bucket == "" {
+		writeWebsiteErrorPage(w, r, ErrNoSuchBucket)
+		return
+	}
+	if _, err := objAPI.GetBucketInfo(bucket); err != nil {
+		writeWebsiteErrorPage(w, r, toAPIErrorCode(err))
+		return
+	}
+	website, err := getCachedBucketWebsite(bucket, objAPI)
+	if err != nil {
+		writeWebsiteErrorPage(w, r, toAPIErrorCode(err))
+		return
+	}
+	if website == nil {
+		writeWebsiteErrorPage(w, r, ErrNoSuchWebsiteConfiguration)
+		return
+	}
+
+	if redirect := website.RedirectAllRequestsTo; redirect != nil {
+		scheme := redirect.Protocol
+		if scheme == "" {
+			scheme = httpScheme
+			if r.TLS != nil {
+				scheme = httpsScheme
+			}
+		}
+		u := url.URL{Scheme: scheme, Host: redirect.HostName, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, slashSeparator)
+	if rule := website.getRoutingRule(key, 0); rule != nil {
+		location, code := rule.getRedirect(r, key)
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	// Directories are served by their index document.
+	object := key
+	if object == "" || hasSuffix(object, slashSeparator) {
+		object += website.IndexDocument.Suffix
+	}
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewRequestNSLock(r, bucket, object)
+	if err = objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		writeWebsiteErrorPage(w, r, toAPIErrorCode(err))
+		return
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, s3Error := getWebsiteObjectInfo(objAPI, r, bucket, object)
+	if s3Error == ErrNoSuchKey && object == key {
+		// Requests of a directory without trailing slash are
+		// redirected to the directory, if it has an index document.
+		indexObject := key + slashSeparator + website.IndexDocument.Suffix
+		if _, indexErr := getWebsiteObjectInfo(objAPI, r, bucket, indexObject); indexErr == ErrNone {
+			http.Redirect(w, r, slashSeparator+key+slashSeparator, http.StatusFound)
+			return
+		}
+	}
+	if s3Error != ErrNone {
+		writeWebsiteError(w, r, objAPI, bucket, key, *website, s3Error)
+		return
+	}
+
+	serveWebsiteObject(w, r, objAPI, bucket, object, objInfo)
+}
+
+// getWebsiteObjectInfo - returns info of an object of a website, if
+// the bucket policy allows anonymous reads of it.
+func getWebsiteObjectInfo(objAPI ObjectLayer, r *http.Request, bucket, object string) (ObjectInfo, APIErrorCode) {
+	resource := slashSeparator + bucket + slashSeparator + object
+	if s3Error := enforceBucketPolicy(objAPI, bucket, "s3:GetObject", resource, r.Referer(), getSourceIP(r), nil); s3Error != ErrNone {
+		return ObjectInfo{}, s3Error
+	}
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		return objInfo, toAPIErrorCode(err)
+	}
+	return objInfo, ErrNone
+}
+
+// serveWebsiteObject - sends an object of a website, like GetObject
+// and HeadObject.
+func serveWebsiteObject(w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, bucket, object string, objInfo ObjectInfo) {
+	if r.Method == httpHEAD {
+		if isObjectTransitioned(objInfo) {
+			objInfo = getTransitionedObjectInfo(objInfo)
+		}
+		if checkPreconditions(w, r, objInfo) {
+			return
+		}
+		setObjectHeaders(w, objInfo, nil)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Data of transitioned objects is in the remote tier.
+	if isObjectTransitioned(objInfo) {
+		getTransitionedObject(w, r, objAPI, bucket, object, objInfo)
+		return
+	}
+
+	var hranges []*httpRange
+	if rangeHeader := getRangeHeader(r, objInfo); rangeHeader != "" {
+		var err error
+		if hranges, err = parseRequestRanges(rangeHeader, objInfo.Size); err == errInvalidRange {
+			writeWebsiteErrorPage(w, r, ErrInvalidRange)
+			return
+		}
+	}
+
+	// Validate pre-conditions if any.
+	if checkPreconditions(w, r, objInfo) {
+		return
+	}
+
+	if len(hranges) > 1 {
+		if err := writeObjectRanges(w, r, objAPI, bucket, object, objInfo, hranges); err != nil {
+			// Headers are already written, no need to write error response.
+			reqErrorIf(r, err, "Unable to write to client.")
+		}
+		return
+	}
+	getObject(w, r, objAPI, bucket, object, objInfo, hranges)
+}
+
+// writeWebsiteError - redirects a failed website request of key if a
+// routing rule applies to its error, otherwise sends the error
+// document of client errors along with their status.
+func writeWebsiteError(w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, bucket, key string, website websiteConfig, s3Error APIErrorCode) {
+	apiErr := getAPIError(s3Error)
+	if rule := website.getRoutingRule(key, apiErr.HTTPStatusCode); rule != nil {
+		location, code := rule.getRedirect(r, key)
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	// Like on AWS S3, the error document is only sent for 4XX errors.
+	isClientError := apiErr.HTTPStatusCode >= 400 && apiErr.HTTPStatusCode < 500
+	if website.ErrorDocument != nil && isClientError {
+		if writeWebsiteErrorDocument(w, r, objAPI, bucket, website.ErrorDocument.Key, apiErr.HTTPStatusCode) {
+			return
+		}
+	}
+	writeWebsiteErrorPage(w, r, s3Error)
+}
+
+// writeWebsiteErrorDocument - sends the error document of a website
+// with status, returns false if it is missing or not readable.
+func writeWebsiteErrorDocument(w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, bucket, object string, status int) bool {
+	objectLock := globalNSMutex.NewRequestNSLock(r, bucket, object)
+	if err := objectLock.GetRLock(getRequestDeadline(r)); err != nil {
+		return false
+	}
+	defer objectLock.RUnlock()
+
+	objInfo, s3Error := getWebsiteObjectInfo(objAPI, r, bucket, object)
+	if s3Error != ErrNone || isObjectTransitioned(objInfo) {
+		return false
+	}
+
+	setObjectHeaders(w, objInfo, nil)
+	w.WriteHeader(status)
+	if r.Method == httpHEAD {
+		return true
+	}
+	if err := objAPI.GetObject(bucket, object, 0, objInfo.Size, w); err != nil {
+		reqErrorIf(r, err, "Unable to write to client.")
+	}
+	return true
+}
+
+// writeWebsiteErrorPage - sends an HTML page describing the error.
+func writeWebsiteErrorPage(w http.ResponseWriter, r *http.Request, s3Error APIErrorCode) {
+	apiErr := getAPIError(s3Error)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(apiErr.HTTPStatusCode)
+	if r.Method == httpHEAD {
+		return
+	}
+	status := fmt.Sprintf("%d %s", apiErr.HTTPStatusCode, http.StatusText(apiErr.HTTPStatusCode))
+	fmt.Fprintf(w, websiteErrorPage, status, html.EscapeString(apiErr.Code),
+		html.EscapeString(apiErr.Description), html.EscapeString(w.Header().Get(responseRequestIDKey)))
+}
+
+// websiteHostHandler serves requests to the website domain.
+type websiteHostHandler struct {
+	handler http.Handler
+	website http.Handler
+}
+
+// setWebsiteHostHandler - serves requests to "bucket.<website domain>"
+// as the website of the bucket, instead of S3 API requests.
+func setWebsiteHostHandler(h http.Handler) http.Handler {
+	return websiteHostHandler{handler: h, website: configureWebsiteHandler(false)}
+}
+
+func (h websiteHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if getVirtualHostBucket(r.Host, globalWebsiteDomain) != "" {
+		h.website.ServeHTTP(w, r)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
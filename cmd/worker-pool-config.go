@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "fmt"
+
+// Default sizes of the worker pools of background subsystems.
+const (
+	defaultNotificationWorkers   = 8
+	defaultNotificationQueueSize = 10000
+	defaultHealWorkers           = 2
+	defaultHealQueueSize         = 1000
+	defaultLifecycleWorkers      = 4
+	defaultLifecycleQueueSize    = 1000
+)
+
+// Names of the worker pools reported by admin API.
+const (
+	notificationPoolName = "notification"
+	healPoolName         = "heal"
+	lifecyclePoolName    = "lifecycle"
+)
+
+// workerPoolConfig - size of the worker pool of a background
+// subsystem, zero values use the defaults.
+type workerPoolConfig struct {
+	// Number of tasks run at once.
+	Workers int `json:"workers"`
+	// Number of tasks waiting for a free worker, further tasks are
+	// handled as described for each pool.
+	QueueSize int `json:"queueSize"`
+}
+
+// Validate - validates worker pool config.
+func (c workerPoolConfig) Validate(name string) error {
+	if c.Workers < 0 {
+		return fmt.Errorf("Invalid workers.%s.workers value ‘%d’, must not be negative", name, c.Workers)
+	}
+	if c.QueueSize < 0 {
+		return fmt.Errorf("Invalid workers.%s.queueSize value ‘%d’, must not be negative", name, c.QueueSize)
+	}
+	return nil
+}
+
+// workersConfig - worker pools of background subsystems.
+type workersConfig struct {
+	// Delivery of bucket notifications, events are delivered by
+	// the request when the queue is full.
+	Notification workerPoolConfig `json:"notification"`
+	// Healing of objects with bit-rot found by GetObject, heals
+	// are skipped when the queue is full.
+	Heal workerPoolConfig `json:"heal"`
+	// Restores of transitioned objects read by GetObject, restores
+	// are skipped when the queue is full and retried by the next read.
+	Lifecycle workerPoolConfig `json:"lifecycle"`
+}
+
+// Validate - validates worker pools config.
+func (c workersConfig) Validate() error {
+	if err := c.Notification.Validate(notificationPoolName); err != nil {
+		return err
+	}
+	if err := c.Heal.Validate(healPoolName); err != nil {
+		return err
+	}
+	return c.Lifecycle.Validate(lifecyclePoolName)
+}
+
+// resizeWorkerPools - resizes worker pools as configured, zero values
+// keep the current size.
+func resizeWorkerPools(c workersConfig) {
+	globalNotificationPool.resize(c.Notification.Workers, c.Notification.QueueSize)
+	globalHealPool.resize(c.Heal.Workers, c.Heal.QueueSize)
+	globalLifecyclePool.resize(c.Lifecycle.Workers, c.Lifecycle.QueueSize)
+}
+
+// initWorkerPools - resizes worker pools as set in config.
+func initWorkerPools() {
+	resizeWorkerPools(serverConfig.GetWorkers())
+}
+
+// getWorkerPoolsStats - returns size and saturation of all worker
+// pools by name.
+func getWorkerPoolsStats() map[string]workerPoolStats {
+	return map[string]workerPoolStats{
+		notificationPoolName: globalNotificationPool.stats(),
+		healPoolName:         globalHealPool.stats(),
+		lifecyclePoolName:    globalLifecyclePool.stats(),
+	}
+}
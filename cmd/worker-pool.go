@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+// workerPool - runs tasks of a background subsystem on a fixed number
+// of workers, tasks wait in a bounded queue for a free worker.
+type workerPool struct {
+	mu        sync.RWMutex
+	workers   int
+	queue     chan func()
+	busy      atomic.Int64
+	completed atomic.Uint64
+	rejected  atomic.Uint64
+}
+
+// workerPoolStats - size and saturation of a worker pool.
+type workerPoolStats struct {
+	Workers   int    `json:"workers"`
+	Busy      int64  `json:"busy"`
+	QueueSize int    `json:"queueSize"`
+	Queued    int    `json:"queued"`
+	Completed uint64 `json:"completed"`
+	Rejected  uint64 `json:"rejected"`
+}
+
+// newWorkerPool - starts a worker pool of the given size.
+func newWorkerPool(workers, queueSize int) *workerPool {
+	p := &workerPool{}
+	p.resize(workers, queueSize)
+	return p
+}
+
+// resize - replaces the workers and queue of the pool, zero values
+// keep the current size. Tasks already queued are run by the
+// previous workers which exit afterwards.
+func (p *workerPool) resize(workers, queueSize int) {
+	p.mu.Lock()
+	if workers <= 0 {
+		workers = p.workers
+	}
+	if queueSize <= 0 {
+		queueSize = cap(p.queue)
+	}
+	prevQueue := p.queue
+	p.workers = workers
+	p.queue = make(chan func(), queueSize)
+	for i := 0; i < workers; i++ {
+		go p.work(p.queue)
+	}
+	p.mu.Unlock()
+
+	// No task is sent to the previous queue once the write lock is
+	// released, see submit().
+	if prevQueue != nil {
+		close(prevQueue)
+	}
+}
+
+// work - runs tasks from the queue until it is closed.
+func (p *workerPool) work(queue <-chan func()) {
+	for task := range queue {
+		p.busy.Inc()
+		task()
+		p.busy.Dec()
+		p.completed.Inc()
+	}
+}
+
+// submit - queues a task for the workers, returns false without
+// running it if the queue is full.
+func (p *workerPool) submit(task func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	select {
+	case p.queue <- task:
+		return true
+	default:
+		p.rejected.Inc()
+		return false
+	}
+}
+
+// stats - returns the current size and saturation of the pool.
+func (p *workerPool) stats() workerPoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return workerPoolStats{
+		Workers:   p.workers,
+		Busy:      p.busy.Load(),
+		QueueSize: cap(p.queue),
+		Queued:    len(p.queue),
+		Completed: p.completed.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test tasks are queued until the queue is full.
+func TestWorkerPoolSubmit(t *testing.T) {
+	testCases := []struct {
+		workers   int
+		queueSize int
+		tasks     int
+		accepted  int
+	}{
+		// All tasks fit in the queue.
+		{1, 4, 3, 3},
+		// Workers take one task each, the queue holds the rest.
+		{2, 2, 6, 4},
+		// Tasks beyond the queue are rejected.
+		{1, 1, 5, 2},
+	}
+
+	for i, testCase := range testCases {
+		pool := newWorkerPool(testCase.workers, testCase.queueSize)
+
+		// Block workers until all tasks are submitted.
+		var started sync.WaitGroup
+		release := make(chan struct{})
+		task := func() {
+			started.Done()
+			<-release
+		}
+
+		var accepted int
+		for j := 0; j < testCase.tasks; j++ {
+			started.Add(1)
+			if pool.submit(task) {
+				accepted++
+			} else {
+				started.Done()
+			}
+			// Let a free worker take the task before submitting the next one.
+			if j < testCase.workers {
+				started.Wait()
+			}
+		}
+		if accepted != testCase.accepted {
+			t.Errorf("Test %d: expected %d accepted tasks, got %d", i+1, testCase.accepted, accepted)
+		}
+
+		stats := pool.stats()
+		if stats.Busy != int64(testCase.workers) {
+			t.Errorf("Test %d: expected %d busy workers, got %d", i+1, testCase.workers, stats.Busy)
+		}
+		if stats.Rejected != uint64(testCase.tasks-testCase.accepted) {
+			t.Errorf("Test %d: expected %d rejected tasks, got %d", i+1, testCase.tasks-testCase.accepted, stats.Rejected)
+		}
+
+		close(release)
+		started.Wait()
+	}
+}
+
+// Test resizing keeps tasks already queued and zero values keep the
+// current size.
+func TestWorkerPoolResize(t *testing.T) {
+	pool := newWorkerPool(1, 2)
+
+	var done sync.WaitGroup
+	release := make(chan struct{})
+	task := func() {
+		<-release
+		done.Done()
+	}
+	done.Add(2)
+	if !pool.submit(task) || !pool.submit(task) {
+		t.Fatal("Expected tasks to be accepted")
+	}
+
+	pool.resize(4, 0)
+	stats := pool.stats()
+	if stats.Workers != 4 || stats.QueueSize != 2 {
+		t.Fatalf("Expected 4 workers and a queue of 2, got %+v", stats)
+	}
+
+	pool.resize(0, 8)
+	stats = pool.stats()
+	if stats.Workers != 4 || stats.QueueSize != 8 {
+		t.Fatalf("Expected 4 workers and a queue of 8, got %+v", stats)
+	}
+
+	// Tasks queued before resizing are still run.
+	close(release)
+	done.Wait()
+}
@@ -0,0 +1,176 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+)
+
+const (
+	// Bucket WORM config file name, saved alongside other bucket
+	// configs in minioMetaBucket.
+	bucketWormConfig = "worm.json"
+)
+
+// errObjectWormProtected - returned for overwrites and deletes of
+// existing objects in WORM buckets.
+var errObjectWormProtected = errors.New("Objects of this bucket can't be overwritten or deleted")
+
+// wormFlag - Write-Once-Read-Many mode of all buckets, "on" or "off".
+type wormFlag string
+
+const (
+	wormOn  wormFlag = "on"
+	wormOff wormFlag = "off"
+)
+
+// Validate - validates worm flag.
+func (f wormFlag) Validate() error {
+	switch f {
+	case "", wormOn, wormOff:
+		return nil
+	}
+	return fmt.Errorf("Invalid worm value ‘%s’, must be %s or %s", f, wormOn, wormOff)
+}
+
+// bucketWorm - WORM mode of a bucket, it can't be disabled once
+// enabled.
+type bucketWorm struct {
+	Enabled bool `json:"enabled"`
+}
+
+// loadBucketWorm - loads WORM mode of a bucket, disabled if not
+// configured.
+func loadBucketWorm(bucket string, objAPI ObjectLayer) (bool, error) {
+	wPath := path.Join(bucketConfigPrefix, bucket, bucketWormConfig)
+
+	// Acquire a read lock on WORM config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, wPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(minioMetaBucket, wPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return false, nil
+		}
+		errorIf(err, "Unable to load WORM config for bucket %s", bucket)
+		return false, err
+	}
+
+	var worm bucketWorm
+	if err = json.Unmarshal(buffer.Bytes(), &worm); err != nil {
+		return false, err
+	}
+	return worm.Enabled, nil
+}
+
+// persistBucketWorm - enables WORM mode of a bucket.
+func persistBucketWorm(bucket string, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(bucketWorm{Enabled: true})
+	if err != nil {
+		return err
+	}
+
+	wPath := path.Join(bucketConfigPrefix, bucket, bucketWormConfig)
+
+	// Acquire a write lock on WORM config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, wPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	_, err = objAPI.PutObject(minioMetaBucket, wPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum)
+	if err != nil {
+		errorIf(err, "Unable to write WORM config for bucket %s", bucket)
+	}
+	return err
+}
+
+// removeBucketWorm - removes WORM config of a bucket, only used
+// during DeleteBucket.
+func removeBucketWorm(bucket string, objAPI ObjectLayer) error {
+	wPath := path.Join(bucketConfigPrefix, bucket, bucketWormConfig)
+
+	// Acquire a write lock on WORM config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, wPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(minioMetaBucket, wPath)
+	objLock.Unlock()
+	return err
+}
+
+// getCachedBucketWorm - returns true if WORM mode of bucket is
+// enabled, WORM mode is cached in globalBucketConfigCache.
+func getCachedBucketWorm(bucket string, objAPI ObjectLayer) (bool, error) {
+	value, err := globalBucketConfigCache.get(bucket, bucketWormConfig, func() (interface{}, error) {
+		return loadBucketWorm(bucket, objAPI)
+	})
+	if err != nil {
+		return false, err
+	}
+	return value.(bool), nil
+}
+
+// isWormEnabled - returns true if objects of bucket can't be
+// overwritten or deleted, either because WORM mode is enabled for all
+// buckets or for this bucket.
+func isWormEnabled(bucket string, objAPI ObjectLayer) (bool, error) {
+	if serverConfig.GetWorm() {
+		return true, nil
+	}
+	return getCachedBucketWorm(bucket, objAPI)
+}
+
+// checkWormOverwrite - returns errObjectWormProtected if object exists
+// in a WORM bucket, it can then neither be overwritten nor deleted.
+// Objects protected by object lock return errObjectLocked.
+func checkWormOverwrite(objAPI ObjectLayer, bucket, object string) error {
+	return checkObjectOverwrite(objAPI, bucket, object, false)
+}
+
+// checkObjectOverwrite - same as checkWormOverwrite, governance
+// retention of the object is bypassed if bypassGovernance is set.
+func checkObjectOverwrite(objAPI ObjectLayer, bucket, object string, bypassGovernance bool) error {
+	enabled, err := isWormEnabled(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	lockConfig, err := getCachedBucketObjectLock(bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	if !enabled && lockConfig == nil {
+		return nil
+	}
+	objInfo, err := objAPI.GetObjectInfo(bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if enabled {
+		return traceError(errObjectWormProtected)
+	}
+	return checkObjectLock(objInfo, bypassGovernance)
+}
@@ -0,0 +1,174 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validating worm flag.
+func TestWormFlagValidate(t *testing.T) {
+	testCases := []struct {
+		flag    wormFlag
+		success bool
+	}{
+		{"", true},
+		{wormOn, true},
+		{wormOff, true},
+		{"yes", false},
+		{"ON", false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.flag.Validate()
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+// Tests enabling WORM mode of a bucket and rejecting overwrites of
+// its objects.
+func TestBucketWorm(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testBucketWorm)
+}
+
+func testBucketWorm(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	globalBucketConfigCache = newBucketConfigCache()
+
+	bucket := "worm-bucket"
+	if err := obj.MakeBucket(bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	// WORM mode is disabled by default.
+	if enabled, err := loadBucketWorm(bucket, obj); err != nil || enabled {
+		t.Fatalf("%s: expected WORM mode disabled, got %t, %v", instanceType, enabled, err)
+	}
+	if err := checkWormOverwrite(obj, bucket, "object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	if err := persistBucketWorm(bucket, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if enabled, err := loadBucketWorm(bucket, obj); err != nil || !enabled {
+		t.Fatalf("%s: expected WORM mode enabled, got %t, %v", instanceType, enabled, err)
+	}
+
+	// Disabled WORM mode stays cached until the change is notified.
+	if err := checkWormOverwrite(obj, bucket, "object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	notifyBucketConfigChange(bucket, bucketWormConfig)
+
+	// Existing objects are protected, new objects can be written.
+	if err := checkWormOverwrite(obj, bucket, "object"); errorCause(err) != errObjectWormProtected {
+		t.Fatalf("%s: expected %s, got %v", instanceType, errObjectWormProtected, err)
+	}
+	if err := checkWormOverwrite(obj, bucket, "new-object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	if err := removeBucketWorm(bucket, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if enabled, err := loadBucketWorm(bucket, obj); err != nil || enabled {
+		t.Fatalf("%s: expected WORM mode disabled, got %t, %v", instanceType, enabled, err)
+	}
+	notifyBucketConfigChange(bucket, "")
+
+	// WORM mode enabled for all buckets in server config.
+	defer func(worm wormFlag) { serverConfig.Worm = worm }(serverConfig.Worm)
+	serverConfig.Worm = wormOn
+	if err := checkWormOverwrite(obj, bucket, "object"); errorCause(err) != errObjectWormProtected {
+		t.Fatalf("%s: expected %s, got %v", instanceType, errObjectWormProtected, err)
+	}
+}
+
+// Tests overwrites and deletes of objects of a WORM bucket are
+// rejected by object handlers.
+func TestAPIWormHandlers(t *testing.T) {
+	defer func(cache *bucketConfigCache) { globalBucketConfigCache = cache }(globalBucketConfigCache)
+	ExecObjectLayerAPITest(t, testAPIWormHandlers, []string{"PutObject", "DeleteObject"})
+}
+
+func testAPIWormHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	globalBucketConfigCache = newBucketConfigCache()
+
+	data := []byte("hello, world")
+	if _, err := obj.PutObject(bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if err := persistBucketWorm(bucketName, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	testCases := []struct {
+		method             string
+		objectName         string
+		expectedRespStatus int
+	}{
+		// 1. Overwriting an existing object.
+		{"PUT", "object", http.StatusMethodNotAllowed},
+		// 2. Deleting an existing object.
+		{"DELETE", "object", http.StatusMethodNotAllowed},
+		// 3. Writing a new object.
+		{"PUT", "new-object", http.StatusOK},
+		// 4. Deleting an object which doesn't exist.
+		{"DELETE", "no-object", http.StatusNoContent},
+	}
+
+	for i, testCase := range testCases {
+		var body []byte
+		if testCase.method == "PUT" {
+			body = data
+		}
+		req, err := newTestSignedRequestV4(testCase.method, getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Object is left untouched.
+	var buffer bytes.Buffer
+	if err := obj.GetObject(bucketName, "object", 0, int64(len(data)), &buffer); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatalf("%s: expected object to be left untouched", instanceType)
+	}
+}
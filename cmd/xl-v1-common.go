@@ -20,9 +20,15 @@ import "path"
 
 // getLoadBalancedDisks - fetches load balanced (sufficiently randomized) disk slice.
 func (xl xlObjects) getLoadBalancedDisks() (disks []StorageAPI) {
+	storageDisks := xl.storageDisks
+	// Objects which are not rebalanced yet are found on the
+	// previous disks only, and so are all other objects.
+	if xl.expansion.isPending() {
+		storageDisks = xl.expansion.disks
+	}
 	// Based on the random shuffling return back randomized disks.
-	for _, i := range hashOrder(UTCNow().String(), len(xl.storageDisks)) {
-		disks = append(disks, xl.storageDisks[i-1])
+	for _, i := range hashOrder(UTCNow().String(), len(storageDisks)) {
+		disks = append(disks, storageDisks[i-1])
 	}
 	return disks
 }
@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path"
+	"strconv"
+	"sync"
+)
+
+// Size of the buffer shards of parts are copied with.
+const composeCopyBufferSize = 1024 * 1024
+
+// copyShardFile - copies a shard file of a part from a disk to
+// another one as it is.
+func copyShardFile(srcDisk StorageAPI, srcBucket, srcPath string, dstDisk StorageAPI, dstBucket, dstPath string) error {
+	fi, err := srcDisk.StatFile(srcBucket, srcPath)
+	if err != nil {
+		return traceError(err)
+	}
+	if fi.Size == 0 {
+		return traceError(dstDisk.AppendFile(dstBucket, dstPath, nil))
+	}
+
+	buf := make([]byte, composeCopyBufferSize)
+	for offset := int64(0); offset < fi.Size; {
+		if remaining := fi.Size - offset; remaining < int64(len(buf)) {
+			buf = buf[:remaining]
+		}
+		n, err := srcDisk.ReadFile(srcBucket, srcPath, offset, buf)
+		if err != nil {
+			return traceError(err)
+		}
+		if err = dstDisk.AppendFile(dstBucket, dstPath, buf[:n]); err != nil {
+			return traceError(err)
+		}
+		offset += n
+	}
+	return nil
+}
+
+// copyPartShards - copies every shard of a part to the disk holding
+// the same shard of the new object, disks are in shard order. Disks
+// which fail are set to nil in dstDisks.
+func copyPartShards(srcDisks []StorageAPI, bucket, srcPath string, dstDisks []StorageAPI, dstPath string) {
+	var wg = &sync.WaitGroup{}
+	for index := range dstDisks {
+		if dstDisks[index] == nil {
+			continue
+		}
+		if srcDisks[index] == nil {
+			dstDisks[index] = nil
+			continue
+		}
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			err := copyShardFile(srcDisks[index], bucket, srcPath, dstDisks[index], minioMetaTmpBucket, dstPath)
+			if err != nil {
+				errorIf(err, "Unable to copy shard %s/%s to %s.", bucket, srcPath, dstDisks[index])
+				dstDisks[index] = nil
+			}
+		}(index)
+	}
+	wg.Wait()
+}
+
+// ComposeObject - composes an object from objects of the same bucket.
+// Parts of the sources become parts of the object, their shards are
+// copied as they are to the disks holding the same shards of the new
+// object along with their checksums, without erasure decoding and
+// encoding their data again. Returns errObjectNotComposable when the
+// sources are stored inline or erasure coded differently, e.g. with
+// another storage class.
+func (xl xlObjects) ComposeObject(bucket string, srcObjects []string, object string, metadata map[string]string) (ObjectInfo, error) {
+	xl = xl.forBucket(bucket).forStorageClass(metadata)
+
+	if err := checkPutObjectArgs(bucket, object, xl); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	// Check if an object is present as one of the parent dir.
+	// -- FIXME. (needs a new kind of lock).
+	if xl.parentDirIsObject(bucket, path.Dir(object)) {
+		return ObjectInfo{}, toObjectErr(traceError(errFileAccessDenied), bucket, object)
+	}
+
+	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
+	partsMetadata := make([]xlMetaV1, len(xl.storageDisks))
+	for index := range partsMetadata {
+		partsMetadata[index] = xlMeta
+	}
+
+	// Order disks according to erasure distribution.
+	onlineDisks := shuffleDisks(xl.storageDisks, xlMeta.Erasure.Distribution)
+
+	tempObj := mustGetUUID()
+	defer xl.deleteObject(minioMetaTmpBucket, tempObj)
+
+	var size int64
+	var partNumber int
+	etags := make([]string, len(srcObjects))
+	for i, srcObject := range srcObjects {
+		// Read metadata associated with the source from all disks.
+		metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, srcObject)
+		if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+			return ObjectInfo{}, toObjectErr(reducedErr, bucket, srcObject)
+		}
+		srcDisks, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+		srcMeta, err := pickValidXLMeta(metaArr, modTime)
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		if srcMeta.Inline || srcMeta.Erasure.DataBlocks != xlMeta.Erasure.DataBlocks ||
+			srcMeta.Erasure.ParityBlocks != xlMeta.Erasure.ParityBlocks ||
+			srcMeta.Erasure.BlockSize != xlMeta.Erasure.BlockSize ||
+			partNumber+len(srcMeta.Parts) > globalMaxPartID {
+			return ObjectInfo{}, traceError(errObjectNotComposable)
+		}
+		srcDisks = shuffleDisks(srcDisks, srcMeta.Erasure.Distribution)
+		metaArr = shufflePartsMetadata(metaArr, srcMeta.Erasure.Distribution)
+
+		for _, part := range srcMeta.Parts {
+			// Empty parts are left out.
+			if part.Size == 0 {
+				continue
+			}
+			partNumber++
+			partName := "part." + strconv.Itoa(partNumber)
+			copyPartShards(srcDisks, bucket, srcMeta.PartPath(srcObject, part.Name), onlineDisks, pathJoin(tempObj, partName))
+
+			var online int
+			for index, disk := range onlineDisks {
+				if disk == nil {
+					continue
+				}
+				online++
+				checkSum := metaArr[index].Erasure.GetCheckSumInfo(part.Name)
+				checkSum.Name = partName
+				partsMetadata[index].AddObjectPart(partNumber, partName, part.ETag, part.Size)
+				partsMetadata[index].Erasure.AddCheckSumInfo(checkSum)
+			}
+			if online < xl.writeQuorum {
+				return ObjectInfo{}, toObjectErr(traceError(errXLWriteQuorum), bucket, object)
+			}
+		}
+		size += srcMeta.Stat.Size
+		etags[i] = srcMeta.Meta["md5Sum"]
+	}
+
+	// Objects without data are stored inline.
+	if partNumber == 0 {
+		return ObjectInfo{}, traceError(errObjectNotComposable)
+	}
+
+	md5Sum, err := getComposedObjectMD5(etags)
+	if err != nil {
+		return ObjectInfo{}, traceError(errObjectNotComposable)
+	}
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata["md5Sum"] = md5Sum
+
+	// An existing object is replaced by a new generation in a new
+	// data directory, readers keep reading the previous generation
+	// until xl.json of the new one is in place.
+	var dataDir string
+	prevMetaArr, _ := readAllXLMetadata(xl.storageDisks, bucket, object)
+	isOverwrite := xl.isObject(bucket, object)
+	if isOverwrite {
+		dataDir = mustGetUUID()
+	}
+
+	modTime := UTCNow()
+	for index := range partsMetadata {
+		partsMetadata[index].Meta = metadata
+		partsMetadata[index].Stat.Size = size
+		partsMetadata[index].Stat.ModTime = modTime
+		partsMetadata[index].DataDir = dataDir
+	}
+
+	// Write unique `xl.json` for each disk.
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	if isOverwrite {
+		// Switch the object to the new generation, then delete the
+		// previous one.
+		if err = swapObjectGeneration(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, dataDir, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		retireObjectGeneration(xl.storageDisks, bucket, object, prevMetaArr)
+	} else {
+		if err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	}
+
+	if xl.objCacheEnabled {
+		// A composed object invalidates any previously cached
+		// object in memory.
+		xl.objCache.Delete(path.Join(bucket, object))
+	}
+
+	return ObjectInfo{
+		IsDir:           false,
+		Bucket:          bucket,
+		Name:            object,
+		Size:            size,
+		ModTime:         modTime,
+		MD5Sum:          metadata["md5Sum"],
+		ContentType:     metadata["content-type"],
+		ContentEncoding: metadata["content-encoding"],
+		UserDefined:     metadata,
+	}, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Tests composing objects of XL from the shards of their sources.
+func TestXLComposeObject(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	// Disable object cache so that all reads are served from disks.
+	xl.objCacheEnabled = false
+
+	globalXLInlineThreshold = 1024
+	defer func() { globalXLInlineThreshold = 0 }()
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string][]byte{
+		"a":     bytes.Repeat([]byte("a"), 3*blockSizeV1+100),
+		"b":     bytes.Repeat([]byte("b"), 2000),
+		"empty": nil,
+		"small": bytes.Repeat([]byte("c"), 100),
+	}
+	for name, data := range objects {
+		if _, err = obj.PutObject("bucket", name, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An offline disk doesn't prevent composing objects.
+	xl.storageDisks[3] = nil
+
+	testCases := []struct {
+		sources  []string
+		object   string
+		parts    int
+		expected []byte
+		err      error
+	}{
+		// Test 1: parts of sources become parts of the object.
+		{[]string{"a", "empty", "b"}, "ab", 2, append(append([]byte{}, objects["a"]...), objects["b"]...), nil},
+		// Test 2: the object is one of its sources.
+		{[]string{"ab", "a"}, "ab", 3, append(append(append([]byte{}, objects["a"]...), objects["b"]...), objects["a"]...), nil},
+		// Test 3: inline sources can't be composed.
+		{[]string{"a", "small"}, "inline", 0, nil, errObjectNotComposable},
+		// Test 4: objects without data can't be composed.
+		{[]string{"empty"}, "empty-composed", 0, nil, errObjectNotComposable},
+		// Test 5: missing source.
+		{[]string{"a", "missing"}, "missing-composed", 0, nil, ObjectNotFound{Bucket: "bucket", Object: "missing"}},
+	}
+	for i, testCase := range testCases {
+		objInfo, err := xl.ComposeObject("bucket", testCase.sources, testCase.object, nil)
+		if errorCause(err) != testCase.err {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if objInfo.Size != int64(len(testCase.expected)) {
+			t.Errorf("Test %d: Expected size %d, got %d", i+1, len(testCase.expected), objInfo.Size)
+		}
+		if !strings.HasSuffix(objInfo.MD5Sum, "-"+strconv.Itoa(len(testCase.sources))) {
+			t.Errorf("Test %d: Expected ETag of %d sources, got %s", i+1, len(testCase.sources), objInfo.MD5Sum)
+		}
+		xlMeta, rErr := readXLMeta(xl.storageDisks[0], "bucket", testCase.object)
+		if rErr != nil {
+			t.Fatalf("Test %d: %s", i+1, rErr)
+		}
+		if len(xlMeta.Parts) != testCase.parts || len(xlMeta.Erasure.Checksum) != testCase.parts {
+			t.Errorf("Test %d: Expected %d parts, got %d", i+1, testCase.parts, len(xlMeta.Parts))
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", testCase.object, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), testCase.expected) {
+			t.Errorf("Test %d: Expected composed object to have the data of its sources", i+1)
+		}
+	}
+}
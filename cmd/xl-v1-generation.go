@@ -0,0 +1,163 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+)
+
+// Overwriting an existing object in XL never leaves a window where
+// the object is missing or partially written. The new generation of
+// the object is moved to a new data directory of the object and its
+// xl.json is then renamed over the previous one, which atomically
+// switches readers to the new generation. The previous generation is
+// deleted once no reader on this node is still using it.
+//
+//	bucket/object/xl.json            - points to the current data directory
+//	bucket/object/<dataDir>/part.N   - parts of the current generation
+
+// generationPins - counts readers of object generations on this node,
+// a retired generation is deleted once its last reader is done.
+type generationPins struct {
+	mu      sync.Mutex
+	readers map[string]int
+	retired map[string][]func()
+}
+
+// newGenerationPins - initialize new generation pins.
+func newGenerationPins() *generationPins {
+	return &generationPins{
+		readers: make(map[string]int),
+		retired: make(map[string][]func()),
+	}
+}
+
+// Readers of object generations on this node.
+var globalGenerationPins = newGenerationPins()
+
+// generationKey - identifies a generation of an object.
+func generationKey(bucket, object, dataDir string) string {
+	return bucket + slashSeparator + object + slashSeparator + dataDir
+}
+
+// pin - marks a generation as being read until the returned function
+// is called.
+func (g *generationPins) pin(bucket, object, dataDir string) (unpin func()) {
+	key := generationKey(bucket, object, dataDir)
+
+	g.mu.Lock()
+	g.readers[key]++
+	g.mu.Unlock()
+
+	return func() {
+		g.mu.Lock()
+		g.readers[key]--
+		if g.readers[key] > 0 {
+			g.mu.Unlock()
+			return
+		}
+		delete(g.readers, key)
+		deleteFns := g.retired[key]
+		delete(g.retired, key)
+		g.mu.Unlock()
+
+		for _, deleteFn := range deleteFns {
+			deleteFn()
+		}
+	}
+}
+
+// retire - calls deleteFn right away if the generation is not being
+// read, otherwise once its last reader is done.
+func (g *generationPins) retire(bucket, object, dataDir string, deleteFn func()) {
+	key := generationKey(bucket, object, dataDir)
+
+	g.mu.Lock()
+	if g.readers[key] > 0 {
+		g.retired[key] = append(g.retired[key], deleteFn)
+		g.mu.Unlock()
+		return
+	}
+	g.mu.Unlock()
+
+	deleteFn()
+}
+
+// swapObjectGeneration - replaces an existing object with the object
+// staged at srcBucket/srcEntry, along with its xl.json. The staged
+// directory becomes the dataDir of the object and its xl.json is then
+// renamed over the xl.json of the object.
+func swapObjectGeneration(disks []StorageAPI, srcBucket, srcEntry, bucket, object, dataDir string, quorum int) error {
+	var wg = &sync.WaitGroup{}
+	var errs = make([]error, len(disks))
+
+	for index, disk := range disks {
+		if disk == nil {
+			errs[index] = traceError(errDiskNotFound)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			dataPath := pathJoin(object, dataDir)
+			if err := disk.RenameFile(srcBucket, retainSlash(srcEntry), bucket, retainSlash(dataPath)); err != nil {
+				errs[index] = traceError(err)
+				return
+			}
+			if err := disk.RenameFile(bucket, pathJoin(dataPath, xlMetaJSONFile), bucket, pathJoin(object, xlMetaJSONFile)); err != nil {
+				errs[index] = traceError(err)
+			}
+		}(index, disk)
+	}
+
+	// Wait for all swaps to finish.
+	wg.Wait()
+
+	return reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, quorum)
+}
+
+// deleteObjectGeneration - deletes parts of a generation of an object
+// on a disk.
+func deleteObjectGeneration(disk StorageAPI, bucket, object string, xlMeta xlMetaV1) {
+	if xlMeta.DataDir != "" {
+		errorIf(cleanupDir(disk, bucket, pathJoin(object, xlMeta.DataDir)),
+			"Unable to delete previous generation of %s/%s.", bucket, object)
+		return
+	}
+	// Parts of objects written before data directories were
+	// introduced are directly under the object.
+	for _, part := range xlMeta.Parts {
+		if err := disk.DeleteFile(bucket, pathJoin(object, part.Name)); err != nil && err != errFileNotFound {
+			errorIf(err, "Unable to delete previous generation of %s/%s.", bucket, object)
+		}
+	}
+}
+
+// retireObjectGeneration - deletes parts of the previous generation of
+// an object on each disk, described by xl.json read from the disk
+// before the swap, once the generation is not being read.
+func retireObjectGeneration(disks []StorageAPI, bucket, object string, metaArr []xlMetaV1) {
+	for index, disk := range disks {
+		if disk == nil || !metaArr[index].IsValid() {
+			continue
+		}
+		disk, xlMeta := disk, metaArr[index]
+		globalGenerationPins.retire(bucket, object, xlMeta.DataDir, func() {
+			deleteObjectGeneration(disk, bucket, object, xlMeta)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests deferred deletion of retired generations.
+func TestGenerationPins(t *testing.T) {
+	pins := newGenerationPins()
+
+	// Generation not being read is deleted right away.
+	deleted := 0
+	pins.retire("bucket", "object", "gen1", func() { deleted++ })
+	if deleted != 1 {
+		t.Fatalf("Expected retired generation to be deleted, deleted %d times", deleted)
+	}
+
+	// Generation being read is deleted after the last reader is done.
+	deleted = 0
+	unpin1 := pins.pin("bucket", "object", "gen2")
+	unpin2 := pins.pin("bucket", "object", "gen2")
+	pins.retire("bucket", "object", "gen2", func() { deleted++ })
+	unpin1()
+	if deleted != 0 {
+		t.Fatal("Expected retired generation to be kept while being read")
+	}
+	unpin2()
+	if deleted != 1 {
+		t.Fatalf("Expected retired generation to be deleted, deleted %d times", deleted)
+	}
+
+	// Pins of other generations don't defer deletion.
+	deleted = 0
+	unpin3 := pins.pin("bucket", "object", "gen3")
+	pins.retire("bucket", "object", "gen4", func() { deleted++ })
+	if deleted != 1 {
+		t.Fatalf("Expected retired generation to be deleted, deleted %d times", deleted)
+	}
+	unpin3()
+
+	if len(pins.readers) != 0 || len(pins.retired) != 0 {
+		t.Fatalf("Expected no pinned generations, found %v %v", pins.readers, pins.retired)
+	}
+}
+
+// Tests that overwriting an object in XL switches to a new generation
+// and removes the previous one.
+func TestXLObjectOverwriteGeneration(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	// countParts - returns number of part files of the object on the first disk.
+	countParts := func() int {
+		count := 0
+		filepath.Walk(filepath.Join(fsDirs[0], "bucket", "object"), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && info.Name() != xlMetaJSONFile {
+				count++
+			}
+			return nil
+		})
+		return count
+	}
+
+	testCases := []string{"first", "second", "third"}
+	for i, data := range testCases {
+		if _, err = obj.PutObject("bucket", "object", int64(len(data)), bytes.NewReader([]byte(data)), nil, ""); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", "object", 0, int64(len(data)), &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if buffer.String() != data {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, data, buffer.String())
+		}
+
+		// Only parts of the current generation are left.
+		if n := countParts(); n != 1 {
+			t.Errorf("Test %d: Expected 1 part on disk, found %d", i+1, n)
+		}
+	}
+
+	// Heal the current generation on a disk it is missing from.
+	if err = os.RemoveAll(filepath.Join(fsDirs[0], "bucket", "object")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = obj.HealObject("bucket", "object"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countParts(); n != 1 {
+		t.Errorf("Expected 1 part on disk after heal, found %d", n)
+	}
+
+	// Listing doesn't expose data directories.
+	result, err := obj.ListObjects("bucket", "object/", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 0 || len(result.Prefixes) != 0 {
+		t.Errorf("Expected no entries under object, found %v %v", result.Objects, result.Prefixes)
+	}
+}
+
+// Tests that a generation being read is kept until the read is done.
+func TestXLObjectOverwritePinnedGeneration(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject("bucket", "object", 5, bytes.NewReader([]byte("first")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a reader of the first generation.
+	xlMeta, err := readXLMeta(xl.storageDisks[0], "bucket", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unpin := globalGenerationPins.pin("bucket", "object", xlMeta.DataDir)
+
+	if _, err = obj.PutObject("bucket", "object", 6, bytes.NewReader([]byte("second")), nil, ""); err != nil {
+		unpin()
+		t.Fatal(err)
+	}
+
+	partPath := filepath.Join(fsDirs[0], "bucket", xlMeta.PartPath("object", "part.1"))
+	if _, err = os.Stat(partPath); err != nil {
+		t.Errorf("Expected previous generation to be kept while being read, %s", err)
+	}
+
+	unpin()
+	if _, err = os.Stat(partPath); !os.IsNotExist(err) {
+		t.Errorf("Expected previous generation to be deleted after read, %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject("bucket", "object", 0, 6, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != "second" {
+		t.Errorf("Expected %q, got %q", "second", buffer.String())
+	}
+}
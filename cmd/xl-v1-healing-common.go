@@ -18,7 +18,6 @@ package cmd
 
 import (
 	"encoding/hex"
-	"path/filepath"
 	"time"
 )
 
@@ -256,13 +255,24 @@ func disksWithAllParts(onlineDisks []StorageAPI, partsMetadata []xlMetaV1, errs
 		if onlineDisk == nil {
 			continue
 		}
+		// Inline objects have no part files, their data in
+		// xl.json is verified instead.
+		if partsMetadata[index].Inline {
+			if isInlineDataValid(partsMetadata[index]) {
+				availableDisks[index] = onlineDisk
+			} else {
+				errs[index] = errFileNotFound
+			}
+			continue
+		}
 		// disk has a valid xl.json but may not have all the
 		// parts. This is considered an outdated disk, since
 		// it needs healing too.
 		for _, part := range partsMetadata[index].Parts {
-			// compute blake2b sum of part.
-			partPath := filepath.Join(object, part.Name)
-			hash := newHash(partsMetadata[index].Erasure.Algorithm)
+			// compute checksum of part.
+			partPath := partsMetadata[index].PartPath(object, part.Name)
+			ckSumInfo := partsMetadata[index].Erasure.GetCheckSumInfo(part.Name)
+			hash := newHash(ckSumInfo.Algorithm)
 			blakeBytes, hErr := hashSum(onlineDisk, bucket, partPath, hash)
 			if hErr == errFileNotFound {
 				errs[index] = errFileNotFound
@@ -274,7 +284,7 @@ func disksWithAllParts(onlineDisks []StorageAPI, partsMetadata []xlMetaV1, errs
 				return nil, nil, traceError(hErr)
 			}
 
-			partChecksum := partsMetadata[index].Erasure.GetCheckSumInfo(part.Name).Hash
+			partChecksum := ckSumInfo.Hash
 			blakeSum := hex.EncodeToString(blakeBytes)
 			// if blake2b sum doesn't match for a part
 			// then this disk is outdated and needs
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
 	"path"
 	"sort"
@@ -118,7 +119,7 @@ func healBucket(storageDisks []StorageAPI, bucket string, writeQuorum int) error
 }
 
 // Heals all the metadata associated for a given bucket, this function
-// heals `policy.json`, `notification.xml` and `listeners.json`.
+// heals all bucket configurations and `listener.json`.
 func healBucketMetadata(storageDisks []StorageAPI, bucket string, readQuorum int) error {
 	healBucketMetaFn := func(metaPath string) error {
 		metaLock := globalNSMutex.NewNSLock(minioMetaBucket, metaPath)
@@ -131,21 +132,14 @@ func healBucketMetadata(storageDisks []StorageAPI, bucket string, readQuorum int
 		return nil
 	}
 
-	// Heal `policy.json` for missing entries, ignores if `policy.json` is not found.
-	policyPath := pathJoin(bucketConfigPrefix, bucket, bucketPolicyConfig)
-	if err := healBucketMetaFn(policyPath); err != nil {
-		return err
-	}
-
-	// Heal `notification.xml` for missing entries, ignores if `notification.xml` is not found.
-	nConfigPath := path.Join(bucketConfigPrefix, bucket, bucketNotificationConfig)
-	if err := healBucketMetaFn(nConfigPath); err != nil {
-		return err
+	// Heal each configuration for missing entries, ignores configurations
+	// which are not found.
+	for _, config := range append([]string{bucketListenerConfig}, bucketConfigs...) {
+		if err := healBucketMetaFn(path.Join(bucketConfigPrefix, bucket, config)); err != nil {
+			return err
+		}
 	}
-
-	// Heal `listeners.json` for missing entries, ignores if `listeners.json` is not found.
-	lConfigPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
-	return healBucketMetaFn(lConfigPath)
+	return nil
 }
 
 // listAllBuckets lists all buckets from all disks. It also
@@ -202,7 +196,7 @@ func reduceHealStatus(status []healStatus) healStatus {
 // directory and returns the worst heal status that can be found
 func (xl xlObjects) bucketHealStatus(bucketName string) (healStatus, error) {
 	// A list of all the bucket config files
-	configFiles := []string{bucketPolicyConfig, bucketNotificationConfig, bucketListenerConfig}
+	configFiles := []string{bucketPolicyConfig, bucketNotificationConfig, bucketListenerConfig, bucketUsageAlertConfig}
 	// The status of buckets config files
 	configsHealStatus := make([]healStatus, len(configFiles))
 	// The list of errors found during checking heal status of each config file
@@ -380,6 +374,13 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 		return 0, 0, toObjectErr(pErr, bucket, object)
 	}
 
+	// Objects written before disks were added to the setup are
+	// erasure coded across the previous disks, they are healed on
+	// these disks only.
+	if len(latestMeta.Erasure.Distribution) != len(storageDisks) {
+		return 0, 0, nil
+	}
+
 	for index, disk := range outDatedDisks {
 		// Before healing outdated disks, we need to remove xl.json
 		// and part files from "bucket/object/" so that
@@ -407,7 +408,7 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 
 		// Delete all the parts. Ignore if parts are not found.
 		for _, part := range outDatedMeta.Parts {
-			dErr := disk.DeleteFile(bucket, pathJoin(object, part.Name))
+			dErr := disk.DeleteFile(bucket, outDatedMeta.PartPath(object, part.Name))
 			if dErr != nil && !isErr(dErr, errFileNotFound) {
 				return 0, 0, toObjectErr(traceError(dErr), bucket, object)
 			}
@@ -432,28 +433,54 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 	// of all the part files in the outDatedDisks[index]
 	checkSumInfos := make([][]checkSumInfo, len(outDatedDisks))
 
-	// Heal each part. erasureHealFile() will write the healed part to
-	// .minio/tmp/uuid/ which needs to be renamed later to the final location.
-	for partIndex := 0; partIndex < len(latestMeta.Parts); partIndex++ {
-		partName := latestMeta.Parts[partIndex].Name
-		partSize := latestMeta.Parts[partIndex].Size
-		erasure := latestMeta.Erasure
-		sumInfo := latestMeta.Erasure.GetCheckSumInfo(partName)
-		// Heal the part file.
-		checkSums, hErr := erasureHealFile(latestDisks, outDatedDisks,
-			bucket, pathJoin(object, partName),
-			minioMetaTmpBucket, pathJoin(tmpID, partName),
-			partSize, erasure.BlockSize, erasure.DataBlocks, erasure.ParityBlocks, sumInfo.Algorithm)
+	// Encoded blocks of an inline object for the outdated disks.
+	inlineData := make([][]byte, len(outDatedDisks))
+
+	if latestMeta.Inline {
+		// Inline objects are healed by reconstructing the encoded
+		// blocks saved in xl.json of the latest disks.
+		enBlocks, hErr := getInlineObjectData(latestDisks, partsMetadata, latestMeta.Erasure)
 		if hErr != nil {
 			return 0, 0, toObjectErr(hErr, bucket, object)
 		}
-		for index, sum := range checkSums {
-			if outDatedDisks[index] != nil {
-				checkSumInfos[index] = append(checkSumInfos[index], checkSumInfo{
-					Name:      partName,
-					Algorithm: sumInfo.Algorithm,
-					Hash:      sum,
-				})
+		sumInfo := latestMeta.Erasure.GetCheckSumInfo(xlInlinePartName)
+		for index, disk := range outDatedDisks {
+			if disk == nil {
+				continue
+			}
+			hash := newHash(sumInfo.Algorithm)
+			hash.Write(enBlocks[index])
+			inlineData[index] = enBlocks[index]
+			checkSumInfos[index] = []checkSumInfo{{
+				Name:      xlInlinePartName,
+				Algorithm: sumInfo.Algorithm,
+				Hash:      hex.EncodeToString(hash.Sum(nil)),
+			}}
+		}
+	} else {
+		// Heal each part. erasureHealFile() will write the healed part to
+		// .minio/tmp/uuid/ which needs to be renamed later to the final location.
+		for partIndex := 0; partIndex < len(latestMeta.Parts); partIndex++ {
+			partName := latestMeta.Parts[partIndex].Name
+			partSize := latestMeta.Parts[partIndex].Size
+			erasure := latestMeta.Erasure
+			sumInfo := latestMeta.Erasure.GetCheckSumInfo(partName)
+			// Heal the part file.
+			checkSums, hErr := erasureHealFile(latestDisks, outDatedDisks,
+				bucket, latestMeta.PartPath(object, partName),
+				minioMetaTmpBucket, latestMeta.PartPath(tmpID, partName),
+				partSize, erasure.BlockSize, erasure.DataBlocks, erasure.ParityBlocks, sumInfo.Algorithm)
+			if hErr != nil {
+				return 0, 0, toObjectErr(hErr, bucket, object)
+			}
+			for index, sum := range checkSums {
+				if outDatedDisks[index] != nil {
+					checkSumInfos[index] = append(checkSumInfos[index], checkSumInfo{
+						Name:      partName,
+						Algorithm: sumInfo.Algorithm,
+						Hash:      sum,
+					})
+				}
 			}
 		}
 	}
@@ -465,6 +492,7 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 		}
 		partsMetadata[index] = latestMeta
 		partsMetadata[index].Erasure.Checksum = checkSumInfos[index]
+		partsMetadata[index].Data = inlineData[index]
 	}
 
 	// Generate and write `xl.json` generated from other disks.
@@ -497,11 +525,45 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 // and later the disk comes back up again, heal on the object
 // should delete it.
 func (xl xlObjects) HealObject(bucket, object string) (int, int, error) {
+	xl = xl.forBucket(bucket)
+
 	// Lock the object before healing.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
+	// Objects not rebalanced yet are healed on the previous disks.
+	xl = xl.forObject(bucket, object)
+
 	// Heal the object.
 	return healObject(xl.storageDisks, bucket, object, xl.readQuorum)
 }
+
+// Objects being healed after bit-rot was detected while reading them,
+// so that concurrent reads heal an object only once.
+var (
+	bitRotHealsMu sync.Mutex
+	bitRotHeals   = make(map[string]struct{})
+)
+
+// healObjectOnRead - heals an object found with bit-rot while being
+// read, unless it is already being healed.
+func (xl xlObjects) healObjectOnRead(bucket, object string) {
+	key := pathJoin(bucket, object)
+	bitRotHealsMu.Lock()
+	if _, ok := bitRotHeals[key]; ok {
+		bitRotHealsMu.Unlock()
+		return
+	}
+	bitRotHeals[key] = struct{}{}
+	bitRotHealsMu.Unlock()
+
+	defer func() {
+		bitRotHealsMu.Lock()
+		delete(bitRotHeals, key)
+		bitRotHealsMu.Unlock()
+	}()
+
+	_, _, err := xl.HealObject(bucket, object)
+	errorIf(err, "Unable to heal `%s/%s` after bit-rot was detected.", bucket, object)
+}
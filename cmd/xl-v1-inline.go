@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// maxXLInlineThreshold - largest object size which can be stored
+// inline in `xl.json`, bigger objects would make `xl.json` too
+// expensive to read for every metadata operation.
+const maxXLInlineThreshold = 1024 * 1024 // 1MiB.
+
+// Name of the only part of an inline object.
+const xlInlinePartName = "part.1"
+
+// isXLInlineObject - returns true if an object of the given size is
+// to be stored inline in `xl.json` instead of in part files.
+func isXLInlineObject(size int64) bool {
+	return size > 0 && size <= globalXLInlineThreshold
+}
+
+// putInlineObjectData - reads size bytes of object data, erasure codes
+// them and saves each encoded block in the `xl.json` of its disk along
+// with its bit-rot checksum.
+func putInlineObjectData(reader io.Reader, size int64, partsMetadata []xlMetaV1) (int64, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return 0, traceError(IncompleteBody{})
+		}
+		return 0, traceError(err)
+	}
+
+	erasure := partsMetadata[0].Erasure
+	blocks, err := encodeData(buf, erasure.DataBlocks, erasure.ParityBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	algo := getBitRotAlgo()
+	for index := range partsMetadata {
+		hash := newHash(algo)
+		hash.Write(blocks[index])
+		partsMetadata[index].Version = xlMetaVersionInline
+		partsMetadata[index].Inline = true
+		partsMetadata[index].Data = blocks[index]
+		partsMetadata[index].AddObjectPart(1, xlInlinePartName, "", size)
+		partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
+			Name:      xlInlinePartName,
+			Hash:      hex.EncodeToString(hash.Sum(nil)),
+			Algorithm: algo,
+		})
+	}
+	return size, nil
+}
+
+// isInlineDataValid - returns true if the encoded block saved in
+// `xl.json` matches its bit-rot checksum.
+func isInlineDataValid(xlMeta xlMetaV1) bool {
+	if !xlMeta.Inline || len(xlMeta.Data) == 0 {
+		return false
+	}
+	ckSumInfo := xlMeta.Erasure.GetCheckSumInfo(xlInlinePartName)
+	hash := newHash(ckSumInfo.Algorithm)
+	hash.Write(xlMeta.Data)
+	return hex.EncodeToString(hash.Sum(nil)) == ckSumInfo.Hash
+}
+
+// getInlineObjectData - returns all the encoded blocks of an inline
+// object, missing or corrupted blocks are reconstructed from the valid
+// ones. Disks and metadata are expected in erasure distribution order.
+func getInlineObjectData(disks []StorageAPI, metaArr []xlMetaV1, erasure erasureInfo) ([][]byte, error) {
+	enBlocks := make([][]byte, len(disks))
+	for index, disk := range disks {
+		if disk == nil || !isInlineDataValid(metaArr[index]) {
+			continue
+		}
+		enBlocks[index] = metaArr[index].Data
+	}
+
+	if !isSuccessDecodeBlocks(enBlocks, erasure.DataBlocks) {
+		return nil, traceError(errXLReadQuorum)
+	}
+
+	for _, block := range enBlocks {
+		if block == nil {
+			if err := decodeData(enBlocks, erasure.DataBlocks, erasure.ParityBlocks); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return enBlocks, nil
+}
+
+// readInlineObject - writes the requested range of an inline object
+// to the writer.
+func readInlineObject(writer io.Writer, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, startOffset, length int64) error {
+	enBlocks, err := getInlineObjectData(onlineDisks, metaArr, xlMeta.Erasure)
+	if err != nil {
+		return err
+	}
+	_, err = writeDataBlocks(writer, enBlocks, xlMeta.Erasure.DataBlocks, startOffset, length)
+	return err
+}
@@ -0,0 +1,184 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests storing, reading and healing objects inline in `xl.json`.
+func TestXLInlineObject(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	// Disable object cache so that all reads are served from disks.
+	xl.objCacheEnabled = false
+
+	globalXLInlineThreshold = 1024
+	defer func() { globalXLInlineThreshold = 0 }()
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	// countParts - returns number of part files of the object on all disks.
+	countParts := func(object string) int {
+		count := 0
+		for _, dir := range fsDirs {
+			filepath.Walk(filepath.Join(dir, "bucket", object), func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() && info.Name() != xlMetaJSONFile {
+					count++
+				}
+				return nil
+			})
+		}
+		return count
+	}
+
+	small := bytes.Repeat([]byte("a"), 1000)
+	large := bytes.Repeat([]byte("b"), 2000)
+
+	putTestCases := []struct {
+		object  string
+		data    []byte
+		inline  bool
+		isParts bool
+	}{
+		// Test 1: object below threshold is inline.
+		{"small", small, true, false},
+		// Test 2: object above threshold is saved in part files.
+		{"large", large, false, true},
+		// Test 3: empty object is saved in part files.
+		{"empty", []byte{}, false, true},
+		// Test 4: overwrite of an object in part files with a small one.
+		{"large", small, true, false},
+		// Test 5: overwrite of an inline object with a large one.
+		{"large", large, false, true},
+	}
+	for i, testCase := range putTestCases {
+		if _, err = obj.PutObject("bucket", testCase.object, int64(len(testCase.data)), bytes.NewReader(testCase.data), nil, ""); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		xlMeta, rErr := readXLMeta(xl.storageDisks[0], "bucket", testCase.object)
+		if rErr != nil {
+			t.Fatalf("Test %d: %s", i+1, rErr)
+		}
+		if xlMeta.Inline != testCase.inline {
+			t.Errorf("Test %d: Expected inline to be %v", i+1, testCase.inline)
+		}
+		if expected := map[bool]string{true: xlMetaVersionInline, false: xlMetaVersion}[testCase.inline]; xlMeta.Version != expected {
+			t.Errorf("Test %d: Expected version %s, got %s", i+1, expected, xlMeta.Version)
+		}
+		if n := countParts(testCase.object); (n > 0) != testCase.isParts {
+			t.Errorf("Test %d: Expected part files to be %v, found %d", i+1, testCase.isParts, n)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", testCase.object, 0, int64(len(testCase.data)), &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), testCase.data) {
+			t.Errorf("Test %d: Expected object data to match", i+1)
+		}
+	}
+
+	// Incomplete body is rejected.
+	if _, err = obj.PutObject("bucket", "incomplete", 100, bytes.NewReader(small[:50]), nil, ""); !isErrIncompleteBody(err) {
+		t.Errorf("Expected IncompleteBody error, got %v", err)
+	}
+
+	// corrupt - modifies the inline data of the object on a disk.
+	corrupt := func(index int) {
+		xlMeta, rErr := readXLMeta(xl.storageDisks[index], "bucket", "small")
+		if rErr != nil {
+			t.Fatal(rErr)
+		}
+		xlMeta.Data[0]++
+		if rErr = xl.storageDisks[index].DeleteFile("bucket", pathJoin("small", xlMetaJSONFile)); rErr != nil {
+			t.Fatal(rErr)
+		}
+		if rErr = writeXLMetadata(xl.storageDisks[index], "bucket", "small", xlMeta); rErr != nil {
+			t.Fatal(rErr)
+		}
+	}
+
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	if _, err = obj.PutObject("bucket", "small", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	corrupt(0)
+	corrupt(5)
+	if err = os.RemoveAll(filepath.Join(fsDirs[10], "bucket", "small")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ranges are served from valid disks.
+	getTestCases := []struct {
+		offset, length int64
+	}{
+		{0, int64(len(data))},
+		{0, 1},
+		{10, 15},
+		{int64(len(data)) - 1, 1},
+	}
+	for i, testCase := range getTestCases {
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", "small", testCase.offset, testCase.length, &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		expected := data[testCase.offset : testCase.offset+testCase.length]
+		if !bytes.Equal(buffer.Bytes(), expected) {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, expected, buffer.String())
+		}
+	}
+
+	// Corrupted and missing disks are healed.
+	if _, _, err = obj.HealObject("bucket", "small"); err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []int{0, 5, 10} {
+		xlMeta, rErr := readXLMeta(xl.storageDisks[index], "bucket", "small")
+		if rErr != nil {
+			t.Fatalf("Disk %d: %s", index, rErr)
+		}
+		if !isInlineDataValid(xlMeta) {
+			t.Errorf("Disk %d: Expected inline data to be healed", index)
+		}
+	}
+	if n := countParts("small"); n != 0 {
+		t.Errorf("Expected no part files after heal, found %d", n)
+	}
+
+	// Object can't be read without enough valid disks.
+	for index := 0; index <= xl.parityBlocks; index++ {
+		corrupt(index)
+	}
+	if err = obj.GetObject("bucket", "small", 0, int64(len(data)), &bytes.Buffer{}); err == nil {
+		t.Error("Expected read of object without enough valid disks to fail")
+	}
+}
@@ -143,9 +143,10 @@ func (xl xlObjects) listObjectsHeal(bucket, prefix, marker, delimiter string, ma
 		// Check if the current object needs healing
 		objectLock := globalNSMutex.NewNSLock(bucket, objInfo.Name)
 		objectLock.RLock()
-		partsMetadata, errs := readAllXLMetadata(xl.storageDisks, bucket, objInfo.Name)
-		if xlShouldHeal(xl.storageDisks, partsMetadata, errs, bucket, objInfo.Name) {
-			healStat := xlHealStat(xl, partsMetadata, errs)
+		objXL := xl.forObject(bucket, objInfo.Name)
+		partsMetadata, errs := readAllXLMetadata(objXL.storageDisks, bucket, objInfo.Name)
+		if xlShouldHeal(objXL.storageDisks, partsMetadata, errs, bucket, objInfo.Name) {
+			healStat := xlHealStat(objXL, partsMetadata, errs)
 			result.Objects = append(result.Objects, ObjectInfo{
 				Name:           objInfo.Name,
 				ModTime:        objInfo.ModTime,
@@ -161,6 +162,8 @@ func (xl xlObjects) listObjectsHeal(bucket, prefix, marker, delimiter string, ma
 
 // ListObjects - list all objects at prefix, delimited by '/'.
 func (xl xlObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, xl); err != nil {
 		return ListObjectsInfo{}, err
 	}
@@ -198,6 +201,7 @@ func (xl xlObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, ma
 // healing in one or more disks.
 func (xl xlObjects) ListUploadsHeal(bucket, prefix, marker, uploadIDMarker,
 	delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	xl = xl.forBucket(bucket)
 
 	// For delimiter and prefix as '/' we do not list anything at all
 	// since according to s3 spec we stop at the 'delimiter' along
@@ -220,7 +224,7 @@ func (xl xlObjects) ListUploadsHeal(bucket, prefix, marker, uploadIDMarker,
 
 // Fetches list of multipart uploadIDs given bucket, keyMarker, uploadIDMarker.
 func fetchMultipartUploadIDs(bucket, keyMarker, uploadIDMarker string,
-	maxUploads int, disks []StorageAPI) (uploads []uploadMetadata, end bool,
+	maxUploads int, disks []StorageAPI) (uploads []UploadMetadata, end bool,
 	err error) {
 
 	// Hold a read lock on keyMarker path.
@@ -257,7 +261,7 @@ func (xl xlObjects) listMultipartUploadsHeal(bucket, prefix, keyMarker,
 
 	recursive := delimiter != slashSeparator
 
-	var uploads []uploadMetadata
+	var uploads []UploadMetadata
 	var err error
 	// List all upload ids for the given keyMarker, starting from
 	// uploadIDMarker.
@@ -328,7 +332,7 @@ func (xl xlObjects) listMultipartUploadsHeal(bucket, prefix, keyMarker,
 				retainSlash(bucket))
 			// Skip entries that are not object directory.
 			if hasSuffix(walkResult.entry, slashSeparator) {
-				uploads = append(uploads, uploadMetadata{
+				uploads = append(uploads, UploadMetadata{
 					Object: entry,
 				})
 				uploadsLeft--
@@ -340,7 +344,7 @@ func (xl xlObjects) listMultipartUploadsHeal(bucket, prefix, keyMarker,
 
 			// For an object entry we get all its pending
 			// uploadIDs.
-			var newUploads []uploadMetadata
+			var newUploads []UploadMetadata
 			var end bool
 			uploadIDMarker = ""
 			newUploads, end, err = fetchMultipartUploadIDs(bucket, entry, uploadIDMarker,
@@ -375,12 +379,13 @@ func (xl xlObjects) listMultipartUploadsHeal(bucket, prefix, keyMarker,
 		} else {
 			// Check if upload needs healing.
 			uploadIDPath := filepath.Join(bucket, upload.Object, upload.UploadID)
-			partsMetadata, errs := readAllXLMetadata(xl.storageDisks,
+			uploadXL := xl.forObject(minioMetaMultipartBucket, uploadIDPath)
+			partsMetadata, errs := readAllXLMetadata(uploadXL.storageDisks,
 				minioMetaMultipartBucket, uploadIDPath)
-			if xlShouldHeal(xl.storageDisks, partsMetadata, errs,
+			if xlShouldHeal(uploadXL.storageDisks, partsMetadata, errs,
 				minioMetaMultipartBucket, uploadIDPath) {
 
-				healUploadInfo := xlHealStat(xl, partsMetadata, errs)
+				healUploadInfo := xlHealStat(uploadXL, partsMetadata, errs)
 				upload.HealUploadInfo = &healUploadInfo
 				result.Uploads = append(result.Uploads, upload)
 			}
@@ -60,10 +60,13 @@ const (
 	sha256Algo = "sha256"
 	// Rest of the platforms default to blake2b.
 	blake2bAlgo = "blake2b"
+	// "highwayhash" is a faster alternative, set in config.
+	highwayHashAlgo = "highwayhash"
 )
 
-// Constant indicates current bit-rot algo used when creating objects.
-// Depending on the architecture we are choosing a different checksum.
+// Default bit-rot algo used when creating objects, unless set in
+// config. Depending on the architecture we are choosing a different
+// checksum.
 var bitRotAlgo = getDefaultBitRotAlgo()
 
 // Get the default bit-rot algo depending on the architecture.
@@ -141,6 +144,14 @@ type xlMetaV1 struct {
 	Meta map[string]string `json:"meta,omitempty"`
 	// Captures all the individual object `xl.json`.
 	Parts []objectPartInfo `json:"parts,omitempty"`
+	// Directory holding the parts of the current generation of the
+	// object, parts are directly under the object when empty.
+	DataDir string `json:"dataDir,omitempty"`
+	// Set for small objects stored inline, Data then holds the
+	// erasure coded block of the object for this disk and there
+	// are no part files. Inline objects are xlMetaVersionInline.
+	Inline bool   `json:"inline,omitempty"`
+	Data   []byte `json:"data,omitempty"`
 }
 
 // XL metadata constants.
@@ -148,6 +159,10 @@ const (
 	// XL meta version.
 	xlMetaVersion = "1.0.0"
 
+	// XL meta version of objects stored inline, servers only knowing
+	// xlMetaVersion reject them instead of reading missing parts.
+	xlMetaVersionInline = "1.0.1"
+
 	// XL meta format string.
 	xlMetaFormat = "xl"
 
@@ -171,9 +186,25 @@ func newXLMetaV1(object string, dataBlocks, parityBlocks int) (xlMeta xlMetaV1)
 }
 
 // IsValid - tells if the format is sane by validating the version
-// string and format style.
+// string and format style. Objects stored inline are only valid with
+// xlMetaVersionInline.
 func (m xlMetaV1) IsValid() bool {
-	return m.Version == xlMetaVersion && m.Format == xlMetaFormat
+	if m.Format != xlMetaFormat {
+		return false
+	}
+	switch m.Version {
+	case xlMetaVersion:
+		return !m.Inline
+	case xlMetaVersionInline:
+		return true
+	}
+	return false
+}
+
+// PartPath - returns the path of a part of the object relative to
+// its bucket, parts are in the data directory of the generation.
+func (m xlMetaV1) PartPath(object, partName string) string {
+	return pathJoin(object, m.DataDir, partName)
 }
 
 // objectPartIndex - returns the index of matching object part number.
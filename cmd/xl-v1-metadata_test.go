@@ -324,6 +324,31 @@ func isXLMetaSimilar(m, n xlMetaV1) bool {
 	return true
 }
 
+// Tests versions of xl.json accepted with and without inline data.
+func TestXLMetaIsValid(t *testing.T) {
+	testCases := []struct {
+		version string
+		format  string
+		inline  bool
+		valid   bool
+	}{
+		{xlMetaVersion, xlMetaFormat, false, true},
+		// Inline data needs xlMetaVersionInline.
+		{xlMetaVersion, xlMetaFormat, true, false},
+		{xlMetaVersionInline, xlMetaFormat, true, true},
+		{xlMetaVersionInline, xlMetaFormat, false, true},
+		{"invalid-version", xlMetaFormat, false, false},
+		{xlMetaVersion, "fs", false, false},
+	}
+	for i, testCase := range testCases {
+		xlMeta := newXLMetaV1("object", 4, 4)
+		xlMeta.Version, xlMeta.Format, xlMeta.Inline = testCase.version, testCase.format, testCase.inline
+		if valid := xlMeta.IsValid(); valid != testCase.valid {
+			t.Errorf("Test %d: Expected valid to be %v, got %v", i+1, testCase.valid, valid)
+		}
+	}
+}
+
 func TestPickValidXLMeta(t *testing.T) {
 	obj := "object"
 	x1 := newXLMetaV1(obj, 4, 4)
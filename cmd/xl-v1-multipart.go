@@ -301,7 +301,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 	if keyMarker != "" {
 		multipartMarkerPath = pathJoin(bucket, keyMarker)
 	}
-	var uploads []uploadMetadata
+	var uploads []UploadMetadata
 	var err error
 	var eof bool
 	// List all upload ids for the keyMarker starting from
@@ -358,7 +358,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 			// For an entry looking like a directory, store and
 			// continue the loop not need to fetch uploads.
 			if hasSuffix(walkResult.entry, slashSeparator) {
-				uploads = append(uploads, uploadMetadata{
+				uploads = append(uploads, UploadMetadata{
 					Object: entry,
 				})
 				maxUploads--
@@ -368,7 +368,7 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 				}
 				continue
 			}
-			var newUploads []uploadMetadata
+			var newUploads []UploadMetadata
 			var end bool
 			uploadIDMarker = ""
 
@@ -449,6 +449,8 @@ func (xl xlObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // ListMultipartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
 func (xl xlObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	if err := checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter, xl); err != nil {
 		return ListMultipartsInfo{}, err
 	}
@@ -521,6 +523,8 @@ func (xl xlObjects) newMultipartUpload(bucket string, object string, meta map[st
 //
 // Implements S3 compatible initiate multipart API.
 func (xl xlObjects) NewMultipartUpload(bucket, object string, meta map[string]string) (string, error) {
+	xl = xl.forBucket(bucket).forStorageClass(meta)
+
 	if err := checkNewMultipartArgs(bucket, object, xl); err != nil {
 		return "", err
 	}
@@ -571,6 +575,8 @@ func (xl xlObjects) CopyObjectPart(srcBucket, srcObject, dstBucket, dstObject, u
 //
 // Implements S3 compatible Upload Part API.
 func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (PartInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	if err := checkPutObjectPartArgs(bucket, object, xl); err != nil {
 		return PartInfo{}, err
 	}
@@ -579,8 +585,12 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	var errs []error
 	uploadIDPath := pathJoin(bucket, object, uploadID)
 
+	// Uploads started before disks were added to the setup are
+	// written to the previous disks.
+	xl = xl.forObject(minioMetaMultipartBucket, uploadIDPath)
+
 	// pre-check upload id lock.
-	preUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+	preUploadIDLock := globalNSMutex.NewUploadIDLock(bucket, object, uploadID)
 	preUploadIDLock.RLock()
 	// Validates if upload ID exists.
 	if !xl.isUploadIDExists(bucket, object, uploadID) {
@@ -609,6 +619,10 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 
 	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
 
+	// Parts are erasure coded like the upload was initiated, according
+	// to its storage class.
+	xl = xl.forErasure(xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks)
+
 	// Need a unique name for the part being written in minioMetaBucket to
 	// accommodate concurrent PutObjectPart requests
 
@@ -657,7 +671,8 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 	allowEmpty := true
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, allowEmpty, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, bitRotAlgo, xl.writeQuorum)
+	algo := getBitRotAlgo()
+	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tmpPartPath, teeReader, allowEmpty, xlMeta.Erasure.BlockSize, xl.dataBlocks, xl.parityBlocks, algo, xl.writeQuorum)
 	if err != nil {
 		return PartInfo{}, toObjectErr(err, bucket, object)
 	}
@@ -690,23 +705,37 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		}
 	}
 
-	// post-upload check (write) lock
-	postUploadIDLock := globalNSMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
-	postUploadIDLock.Lock()
-	defer postUploadIDLock.Unlock()
+	// Lock the part so that another upload of the same part number
+	// does not interleave with the commit of this part.
+	partLock := globalNSMutex.NewPartLock(bucket, object, uploadID, partID)
+	partLock.Lock()
+	defer partLock.Unlock()
 
-	// Validate again if upload ID still exists.
+	// Rename temporary part file to its final location, only the
+	// read lock is held so that other parts are renamed in parallel.
+	renameUploadIDLock := globalNSMutex.NewUploadIDLock(bucket, object, uploadID)
+	renameUploadIDLock.RLock()
 	if !xl.isUploadIDExists(bucket, object, uploadID) {
+		renameUploadIDLock.RUnlock()
 		return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
 	}
-
-	// Rename temporary part file to its final location.
 	partPath := path.Join(uploadIDPath, partSuffix)
 	err = renamePart(onlineDisks, minioMetaTmpBucket, tmpPartPath, minioMetaMultipartBucket, partPath, xl.writeQuorum)
+	renameUploadIDLock.RUnlock()
 	if err != nil {
 		return PartInfo{}, toObjectErr(err, minioMetaMultipartBucket, partPath)
 	}
 
+	// post-upload check (write) lock
+	postUploadIDLock := globalNSMutex.NewUploadIDLock(bucket, object, uploadID)
+	postUploadIDLock.Lock()
+	defer postUploadIDLock.Unlock()
+
+	// Validate again if upload ID still exists.
+	if !xl.isUploadIDExists(bucket, object, uploadID) {
+		return PartInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
+	}
+
 	// Read metadata again because it might be updated with parallel upload of another part.
 	partsMetadata, errs = readAllXLMetadata(onlineDisks, minioMetaMultipartBucket, uploadIDPath)
 	reducedErr = reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, xl.writeQuorum)
@@ -737,7 +766,7 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
 			Name:      partSuffix,
 			Hash:      checkSums[index],
-			Algorithm: bitRotAlgo,
+			Algorithm: algo,
 		})
 	}
 
@@ -759,12 +788,12 @@ func (xl xlObjects) PutObjectPart(bucket, object, uploadID string, partID int, s
 		return PartInfo{}, toObjectErr(rErr, minioMetaMultipartBucket, partSuffix)
 	}
 
-	// Return success.
+	// Return success, parts on disk hold a shard of the data only.
 	return PartInfo{
 		PartNumber:   partID,
 		LastModified: fi.ModTime,
 		ETag:         newMD5Hex,
-		Size:         fi.Size,
+		Size:         size,
 	}, nil
 }
 
@@ -839,6 +868,8 @@ func (xl xlObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 // ListPartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
 func (xl xlObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	if err := checkListPartsArgs(bucket, object, xl); err != nil {
 		return ListPartsInfo{}, err
 	}
@@ -863,7 +894,9 @@ func (xl xlObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []CompletePart) (ObjectInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	if err := checkCompleteMultipartArgs(bucket, object, xl); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -883,6 +916,11 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 		return ObjectInfo{}, traceError(InvalidUploadID{UploadID: uploadID})
 	}
 
+	// Uploads started before disks were added to the setup are
+	// completed on the previous disks, the object is rebalanced
+	// later on.
+	xl = xl.forObject(minioMetaMultipartBucket, pathJoin(bucket, object, uploadID))
+
 	// Check if an object is present as one of the parent dir.
 	// -- FIXME. (needs a new kind of lock).
 	if xl.parentDirIsObject(bucket, path.Dir(object)) {
@@ -921,6 +959,9 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	// Order parts metadata in accordance with distribution order.
 	partsMetadata = shufflePartsMetadata(partsMetadata, xlMeta.Erasure.Distribution)
 
+	// Write quorum of the object depends on its storage class.
+	xl = xl.forErasure(xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks)
+
 	// Save current xl meta for validation.
 	var currentXLMeta = xlMeta
 
@@ -940,12 +981,13 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 			return ObjectInfo{}, traceError(BadDigest{})
 		}
 
-		// All parts except the last part has to be atleast 5MB.
+		// All parts except the last part has to be atleast minimum part size.
 		if (i < len(parts)-1) && !isMinAllowedPartSize(currentXLMeta.Parts[partIdx].Size) {
 			return ObjectInfo{}, traceError(PartTooSmall{
-				PartNumber: part.PartNumber,
-				PartSize:   currentXLMeta.Parts[partIdx].Size,
-				PartETag:   part.ETag,
+				PartNumber:     part.PartNumber,
+				PartSize:       currentXLMeta.Parts[partIdx].Size,
+				PartETag:       part.ETag,
+				MinSizeAllowed: getMinPartSize(),
 			})
 		}
 
@@ -977,12 +1019,23 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 	uploadIDPath = path.Join(bucket, object, uploadID)
 	tempUploadIDPath := uploadID
 
+	// An existing object is replaced by a new generation in a new
+	// data directory, readers keep reading the previous generation
+	// until xl.json of the new one is in place.
+	var dataDir string
+	prevMetaArr, _ := readAllXLMetadata(xl.storageDisks, bucket, object)
+	isOverwrite := xl.isObject(bucket, object)
+	if isOverwrite {
+		dataDir = mustGetUUID()
+	}
+
 	// Update all xl metadata, make sure to not modify fields like
 	// checksum which are different on each disks.
 	for index := range partsMetadata {
 		partsMetadata[index].Stat = xlMeta.Stat
 		partsMetadata[index].Meta = xlMeta.Meta
 		partsMetadata[index].Parts = xlMeta.Parts
+		partsMetadata[index].DataDir = dataDir
 	}
 
 	// Write unique `xl.json` for each disk.
@@ -1008,22 +1061,6 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 		}
 	}()
 
-	if xl.isObject(bucket, object) {
-		// Rename if an object already exists to temporary location.
-		newUniqueID := mustGetUUID()
-
-		// Delete success renamed object.
-		defer xl.deleteObject(minioMetaTmpBucket, newUniqueID)
-
-		// NOTE: Do not use online disks slice here.
-		// The reason is that existing object should be purged
-		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(err, bucket, object)
-		}
-	}
-
 	// Remove parts that weren't present in CompleteMultipartUpload request.
 	for _, curpart := range currentXLMeta.Parts {
 		if objectPartIndex(xlMeta.Parts, curpart.Number) == -1 {
@@ -1037,9 +1074,18 @@ func (xl xlObjects) CompleteMultipartUpload(bucket string, object string, upload
 		}
 	}
 
-	// Rename the multipart object to final location.
-	if err = renameObject(onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, xl.writeQuorum); err != nil {
-		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	if isOverwrite {
+		// Switch the object to the new generation, then delete the
+		// previous one.
+		if err = swapObjectGeneration(onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, dataDir, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		retireObjectGeneration(xl.storageDisks, bucket, object, prevMetaArr)
+	} else {
+		// Rename the multipart object to final location.
+		if err = renameObject(onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
 	}
 
 	// Hold the lock so that two parallel
@@ -1140,6 +1186,8 @@ func (xl xlObjects) abortMultipartUpload(bucket, object, uploadID string) (err e
 // that this is an atomic idempotent operation. Subsequent calls have
 // no affect and further requests to the same uploadID would not be honored.
 func (xl xlObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+	xl = xl.forBucket(bucket)
+
 	if err := checkAbortMultipartArgs(bucket, object, xl); err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"hash"
@@ -25,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/minio/minio/pkg/bpool"
 	"github.com/minio/minio/pkg/mimedb"
@@ -60,14 +62,20 @@ func (xl xlObjects) prepareFile(bucket, object string, size int64, onlineDisks [
 // if source object and destination object are same we only
 // update metadata.
 func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+	xl = xl.forBucket(srcBucket)
+
+	// Objects not rebalanced yet are read from the previous disks,
+	// their metadata is updated there.
+	srcXL := xl.forObject(srcBucket, srcObject)
+
 	// Read metadata associated with the object from all disks.
-	metaArr, errs := readAllXLMetadata(xl.storageDisks, srcBucket, srcObject)
-	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+	metaArr, errs := readAllXLMetadata(srcXL.storageDisks, srcBucket, srcObject)
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, srcXL.readQuorum); reducedErr != nil {
 		return ObjectInfo{}, toObjectErr(reducedErr, srcBucket, srcObject)
 	}
 
 	// List all online disks.
-	onlineDisks, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+	onlineDisks, modTime := listOnlineDisks(srcXL.storageDisks, metaArr, errs)
 
 	// Pick latest valid metadata.
 	xlMeta, err := pickValidXLMeta(metaArr, modTime)
@@ -84,21 +92,29 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	// Check if this request is only metadata update.
 	cpMetadataOnly := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	if cpMetadataOnly {
+		// Data isn't erasure coded again, the object keeps its
+		// storage class.
+		if storageClass, ok := xlMeta.Meta[amzStorageClassHeader]; ok {
+			metadata[amzStorageClassHeader] = storageClass
+		} else {
+			delete(metadata, amzStorageClassHeader)
+		}
 		xlMeta.Meta = metadata
-		partsMetadata := make([]xlMetaV1, len(xl.storageDisks))
-		// Update `xl.json` content on each disks.
+		// Update `xl.json` content on each disks, erasure index and
+		// checksums differ across disks hence are preserved.
+		partsMetadata := shufflePartsMetadata(metaArr, xlMeta.Erasure.Distribution)
 		for index := range partsMetadata {
-			partsMetadata[index] = xlMeta
+			partsMetadata[index].Meta = metadata
 		}
 
 		tempObj := mustGetUUID()
 
 		// Write unique `xl.json` for each disk.
-		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, srcXL.writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 		// Rename atomically `xl.json` from tmp location to destination for each disk.
-		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
+		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, srcXL.writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 
@@ -151,6 +167,8 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
 func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+	xl = xl.forBucket(bucket)
+
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -165,6 +183,9 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		return traceError(errUnexpected)
 	}
 
+	// Objects not rebalanced yet are read from the previous disks.
+	xl = xl.forObject(bucket, object)
+
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
 	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
@@ -186,6 +207,11 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 	// Reorder parts metadata based on erasure distribution order.
 	metaArr = shufflePartsMetadata(metaArr, xlMeta.Erasure.Distribution)
 
+	// Keep the generation being read until done, even if the object
+	// is overwritten meanwhile.
+	unpin := globalGenerationPins.pin(bucket, object, xlMeta.DataDir)
+	defer unpin()
+
 	// For negative length read everything.
 	if length < 0 {
 		length = xlMeta.Stat.Size - startOffset
@@ -196,29 +222,12 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		return traceError(InvalidRange{startOffset, length, xlMeta.Stat.Size})
 	}
 
-	// Get start part index and offset.
-	partIndex, partOffset, err := xlMeta.ObjectToPartOffset(startOffset)
-	if err != nil {
-		return traceError(InvalidRange{startOffset, length, xlMeta.Stat.Size})
-	}
-
-	// Calculate endOffset according to length
-	endOffset := startOffset
-	if length > 0 {
-		endOffset += length - 1
-	}
-
-	// Get last part index to read given length.
-	lastPartIndex, _, err := xlMeta.ObjectToPartOffset(endOffset)
-	if err != nil {
-		return traceError(InvalidRange{startOffset, length, xlMeta.Stat.Size})
-	}
-
 	// Save the writer.
 	mw := writer
 
-	// Object cache enabled block.
-	if xlMeta.Stat.Size > 0 && xl.objCacheEnabled {
+	// Object cache enabled block, excluded objects are read
+	// directly from disks.
+	if xlMeta.Stat.Size > 0 && xl.objCacheEnabled && !isObjCacheExcluded(bucket, object) {
 		// Validate if we have previous cache.
 		var cachedBuffer io.ReaderAt
 		cachedBuffer, err = xl.objCache.Open(path.Join(bucket, object), modTime)
@@ -257,14 +266,132 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 				// For any other error return here.
 				return toObjectErr(traceError(err), bucket, object)
 			}
+		} else if xlMeta.Erasure.BlockSize > 0 {
+			// Range reads are served segment by segment, only
+			// segments missing in cache are read from disks.
+			if err = xl.getObjectSegments(writer, bucket, object, xlMeta, metaArr, onlineDisks, modTime, startOffset, length); err != nil {
+				return err
+			}
+
+			// Prefetch the segments following sequential range
+			// reads, i.e streaming clients, in the background.
+			if xl.readAhead.observe(path.Join(bucket, object), startOffset, startOffset+length) {
+				go xl.readAheadObject(bucket, object, modTime, startOffset+length)
+			}
+			return nil
+		}
+	}
+
+	return xl.readObjectFromDisks(mw, bucket, object, xlMeta, metaArr, onlineDisks, startOffset, length)
+}
+
+// getObjectSegments - writes the requested range of an object to the
+// writer one segment at a time, a segment being an erasure block sized
+// range of the object starting at a multiple of the block size. Cached
+// segments are served from object cache, missing segments are read
+// from disks and saved to object cache for subsequent range reads.
+func (xl xlObjects) getObjectSegments(writer io.Writer, bucket, object string, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, modTime time.Time, startOffset, length int64) error {
+	key := path.Join(bucket, object)
+	segmentSize := xlMeta.Erasure.BlockSize
+	endOffset := startOffset + length
+	for index := startOffset / segmentSize; index*segmentSize < endOffset; index++ {
+		segmentStart := index * segmentSize
+		segmentEnd := segmentStart + segmentSize
+		if segmentEnd > xlMeta.Stat.Size {
+			segmentEnd = xlMeta.Stat.Size
+		}
+
+		segment, err := xl.objCache.GetSegment(key, modTime, index)
+		if err != nil {
+			// Segment not cached, read the whole segment from disks.
+			if segment, err = xl.readObjectSegment(bucket, object, xlMeta, metaArr, onlineDisks, index); err != nil {
+				return err
+			}
+			// Ignore error if cache is full, the segment is served anyway.
+			xl.objCache.PutSegment(key, index, segment)
+		}
+
+		// Write only the requested portion of the segment.
+		from, to := int64(0), segmentEnd-segmentStart
+		if startOffset > segmentStart {
+			from = startOffset - segmentStart
+		}
+		if endOffset < segmentEnd {
+			to = endOffset - segmentStart
+		}
+		if _, err = writer.Write(segment[from:to]); err != nil {
+			return traceError(err)
 		}
 	}
 
+	// Success.
+	return nil
+}
+
+// readObjectSegment - reads the segment of an object at the given
+// index from disks.
+func (xl xlObjects) readObjectSegment(bucket, object string, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, index int64) ([]byte, error) {
+	segmentStart := index * xlMeta.Erasure.BlockSize
+	segmentEnd := segmentStart + xlMeta.Erasure.BlockSize
+	if segmentEnd > xlMeta.Stat.Size {
+		segmentEnd = xlMeta.Stat.Size
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, segmentEnd-segmentStart))
+	if err := xl.readObjectFromDisks(buf, bucket, object, xlMeta, metaArr, onlineDisks, segmentStart, segmentEnd-segmentStart); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readObjectFromDisks - erasure decodes the requested range of an
+// object from online disks and writes it to the writer.
+func (xl xlObjects) readObjectFromDisks(writer io.Writer, bucket, object string, xlMeta xlMetaV1, metaArr []xlMetaV1, onlineDisks []StorageAPI, startOffset, length int64) error {
+	// Inline objects are read from `xl.json` already read.
+	if xlMeta.Inline {
+		if err := readInlineObject(writer, xlMeta, metaArr, onlineDisks, startOffset, length); err != nil {
+			errorIf(err, "Unable to read inline object `%s/%s`.", bucket, object)
+			return toObjectErr(err, bucket, object)
+		}
+		return nil
+	}
+
+	// Get start part index and offset.
+	partIndex, partOffset, err := xlMeta.ObjectToPartOffset(startOffset)
+	if err != nil {
+		return traceError(InvalidRange{startOffset, length, xlMeta.Stat.Size})
+	}
+
+	// Calculate endOffset according to length
+	endOffset := startOffset
+	if length > 0 {
+		endOffset += length - 1
+	}
+
+	// Get last part index to read given length.
+	lastPartIndex, _, err := xlMeta.ObjectToPartOffset(endOffset)
+	if err != nil {
+		return traceError(InvalidRange{startOffset, length, xlMeta.Stat.Size})
+	}
+
 	var totalBytesRead int64
 
 	chunkSize := getChunkSize(xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
 	pool := bpool.NewBytePool(chunkSize, len(onlineDisks))
 
+	// Heal the object in background once read, if any of its parts
+	// has bit-rot and healing on read is enabled. The heal is skipped
+	// when the heal queue is full.
+	verify := getBitRotVerify()
+	var bitRotDetected bool
+	defer func() {
+		if bitRotDetected && verify == bitrotVerifyHeal {
+			globalHealPool.submit(func() {
+				xl.healObjectOnRead(bucket, object)
+			})
+		}
+	}()
+
 	// Read from all parts.
 	for ; partIndex <= lastPartIndex; partIndex++ {
 		if length == totalBytesRead {
@@ -299,8 +426,17 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 			}
 		}
 
+		// Shards are not verified when verification is disabled.
+		if verify == bitrotVerifyOff {
+			checkSums = nil
+		}
+
 		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		n, partBitRot, err := erasureReadFile(writer, onlineDisks, bucket, xlMeta.PartPath(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		if partBitRot {
+			errorIf(errBitRotHashMismatch, "Bit-rot detected in %s of the object `%s/%s`.", partName, bucket, object)
+			bitRotDetected = true
+		}
 		if err != nil {
 			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
@@ -320,6 +456,8 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
 func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+	xl = xl.forBucket(bucket)
+
 	// This is a special case with object whose name ends with
 	// a slash separator, we always return object not found here.
 	if hasSuffix(object, slashSeparator) {
@@ -461,6 +599,8 @@ func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject
 // writes `xl.json` which carries the necessary metadata for future
 // object operations.
 func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+	xl = xl.forBucket(bucket).forStorageClass(metadata)
+
 	// This is a special case with size as '0' and object ends with
 	// a slash separator, we treat it like a valid operation and
 	// return success.
@@ -506,8 +646,9 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// Proceed to set the cache.
 	var newBuffer io.WriteCloser
 
-	// If caching is enabled, proceed to set the cache.
-	if size > 0 && xl.objCacheEnabled {
+	// If caching is enabled, proceed to set the cache, excluded
+	// objects are never cached.
+	if size > 0 && xl.objCacheEnabled && !isObjCacheExcluded(bucket, object) {
 		// PutObject invalidates any previously cached object in memory.
 		xl.objCache.Delete(path.Join(bucket, object))
 
@@ -561,80 +702,90 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// Total size of the written object
 	var sizeWritten int64
 
-	// Read data and split into parts - similar to multipart mechanism
-	for partIdx := 1; ; partIdx++ {
-		// Compute part name
-		partName := "part." + strconv.Itoa(partIdx)
-		// Compute the path of current part
-		tempErasureObj := pathJoin(uniqueID, partName)
-
-		// Calculate the size of the current part, if size is unknown, curPartSize wil be unknown too.
-		// allowEmptyPart will always be true if this is the first part and false otherwise.
-		var curPartSize int64
-		curPartSize, err = getPartSizeFromIdx(size, globalPutPartSize, partIdx)
+	if isXLInlineObject(size) {
+		// Small objects are stored inline in `xl.json`, no part
+		// files are written.
+		sizeWritten, err = putInlineObjectData(teeReader, size, partsMetadata)
 		if err != nil {
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
+	} else {
+		// Read data and split into parts - similar to multipart mechanism
+		for partIdx := 1; ; partIdx++ {
+			// Compute part name
+			partName := "part." + strconv.Itoa(partIdx)
+			// Compute the path of current part
+			tempErasureObj := pathJoin(uniqueID, partName)
+
+			// Calculate the size of the current part, if size is unknown, curPartSize wil be unknown too.
+			// allowEmptyPart will always be true if this is the first part and false otherwise.
+			var curPartSize int64
+			curPartSize, err = getPartSizeFromIdx(size, globalPutPartSize, partIdx)
+			if err != nil {
+				return ObjectInfo{}, toObjectErr(err, bucket, object)
+			}
 
-		// Hint the filesystem to pre-allocate one continuous large block.
-		// This is only an optimization.
-		if curPartSize > 0 {
-			pErr := xl.prepareFile(minioMetaTmpBucket, tempErasureObj, curPartSize, onlineDisks, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
-			if pErr != nil {
-				return ObjectInfo{}, toObjectErr(pErr, bucket, object)
+			// Hint the filesystem to pre-allocate one continuous large block.
+			// This is only an optimization.
+			if curPartSize > 0 {
+				pErr := xl.prepareFile(minioMetaTmpBucket, tempErasureObj, curPartSize, onlineDisks, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks)
+				if pErr != nil {
+					return ObjectInfo{}, toObjectErr(pErr, bucket, object)
+				}
 			}
-		}
 
-		// partReader streams at most maximum part size
-		partReader := io.LimitReader(teeReader, globalPutPartSize)
+			// partReader streams at most maximum part size
+			partReader := io.LimitReader(teeReader, globalPutPartSize)
 
-		// Allow creating empty earsure file only when this is the first part. This flag is useful
-		// when size == -1 because in this case, we are not able to predict how many parts we will have.
-		allowEmptyPart := partIdx == 1
+			// Allow creating empty earsure file only when this is the first part. This flag is useful
+			// when size == -1 because in this case, we are not able to predict how many parts we will have.
+			allowEmptyPart := partIdx == 1
 
-		// Erasure code data and write across all disks.
-		partSizeWritten, checkSums, erasureErr := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, partReader, allowEmptyPart, partsMetadata[0].Erasure.BlockSize, partsMetadata[0].Erasure.DataBlocks, partsMetadata[0].Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
-		if erasureErr != nil {
-			return ObjectInfo{}, toObjectErr(erasureErr, minioMetaTmpBucket, tempErasureObj)
-		}
+			// Erasure code data and write across all disks.
+			algo := getBitRotAlgo()
+			partSizeWritten, checkSums, erasureErr := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, partReader, allowEmptyPart, partsMetadata[0].Erasure.BlockSize, partsMetadata[0].Erasure.DataBlocks, partsMetadata[0].Erasure.ParityBlocks, algo, xl.writeQuorum)
+			if erasureErr != nil {
+				return ObjectInfo{}, toObjectErr(erasureErr, minioMetaTmpBucket, tempErasureObj)
+			}
 
-		// Should return IncompleteBody{} error when reader has fewer bytes
-		// than specified in request header.
-		if partSizeWritten < int64(curPartSize) {
-			return ObjectInfo{}, traceError(IncompleteBody{})
-		}
+			// Should return IncompleteBody{} error when reader has fewer bytes
+			// than specified in request header.
+			if partSizeWritten < int64(curPartSize) {
+				return ObjectInfo{}, traceError(IncompleteBody{})
+			}
 
-		// Update the total written size
-		sizeWritten += partSizeWritten
-
-		// If erasure stored some data in the loop or created an empty file
-		if partSizeWritten > 0 || allowEmptyPart {
-			for index := range partsMetadata {
-				// Add the part to xl.json.
-				partsMetadata[index].AddObjectPart(partIdx, partName, "", partSizeWritten)
-				// Add part checksum info to xl.json.
-				partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
-					Name:      partName,
-					Hash:      checkSums[index],
-					Algorithm: bitRotAlgo,
-				})
+			// Update the total written size
+			sizeWritten += partSizeWritten
+
+			// If erasure stored some data in the loop or created an empty file
+			if partSizeWritten > 0 || allowEmptyPart {
+				for index := range partsMetadata {
+					// Add the part to xl.json.
+					partsMetadata[index].AddObjectPart(partIdx, partName, "", partSizeWritten)
+					// Add part checksum info to xl.json.
+					partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
+						Name:      partName,
+						Hash:      checkSums[index],
+						Algorithm: algo,
+					})
+				}
 			}
-		}
 
-		// If we didn't write anything or we know that the next part doesn't have any
-		// data to write, we should quit this loop immediately
-		if partSizeWritten == 0 {
-			break
-		}
+			// If we didn't write anything or we know that the next part doesn't have any
+			// data to write, we should quit this loop immediately
+			if partSizeWritten == 0 {
+				break
+			}
 
-		// Check part size for the next index.
-		var partSize int64
-		partSize, err = getPartSizeFromIdx(size, globalPutPartSize, partIdx+1)
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(err, bucket, object)
-		}
-		if partSize == 0 {
-			break
+			// Check part size for the next index.
+			var partSize int64
+			partSize, err = getPartSizeFromIdx(size, globalPutPartSize, partIdx+1)
+			if err != nil {
+				return ObjectInfo{}, toObjectErr(err, bucket, object)
+			}
+			if partSize == 0 {
+				break
+			}
 		}
 	}
 
@@ -683,20 +834,14 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
-	if xl.isObject(bucket, object) {
-		// Rename if an object already exists to temporary location.
-		newUniqueID := mustGetUUID()
-
-		// Delete successfully renamed object.
-		defer xl.deleteObject(minioMetaTmpBucket, newUniqueID)
-
-		// NOTE: Do not use online disks slice here.
-		// The reason is that existing object should be purged
-		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(err, bucket, object)
-		}
+	// An existing object is replaced by a new generation in a new
+	// data directory, readers keep reading the previous generation
+	// until xl.json of the new one is in place.
+	var dataDir string
+	prevMetaArr, _ := readAllXLMetadata(xl.storageDisks, bucket, object)
+	isOverwrite := xl.isObject(bucket, object)
+	if isOverwrite {
+		dataDir = mustGetUUID()
 	}
 
 	// Fill all the necessary metadata.
@@ -705,6 +850,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		partsMetadata[index].Meta = metadata
 		partsMetadata[index].Stat.Size = size
 		partsMetadata[index].Stat.ModTime = modTime
+		partsMetadata[index].DataDir = dataDir
 	}
 
 	// Write unique `xl.json` for each disk.
@@ -712,10 +858,19 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
-	// Rename the successfully written temporary object to final location.
-	err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
-	if err != nil {
-		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	if isOverwrite {
+		// Switch the object to the new generation, then delete the
+		// previous one.
+		if err = swapObjectGeneration(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, dataDir, xl.writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		retireObjectGeneration(xl.storageDisks, bucket, object, prevMetaArr)
+	} else {
+		// Rename the successfully written temporary object to final location.
+		err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
 	}
 
 	// Once we have successfully renamed the object, Close the buffer which would
@@ -779,6 +934,8 @@ func (xl xlObjects) deleteObject(bucket, object string) error {
 // any error as it is not necessary for the handler to reply back a
 // response to the client request.
 func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
+	xl = xl.forBucket(bucket)
+
 	if err = checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
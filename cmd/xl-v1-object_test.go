@@ -27,6 +27,7 @@ import (
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/objcache"
 )
 
 func TestRepeatPutObjectPart(t *testing.T) {
@@ -368,3 +369,88 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// Tests range reads are served from cached segments of the object.
+func TestXLGetObjectSegments(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	// Cache entries are limited to 11MiB, large enough to hold a
+	// segment but not the whole object.
+	xl := obj.(*xlObjects)
+	if xl.objCache, err = objcache.New(110*humanize.MiByte, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	xl.objCacheEnabled = true
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Object spanning two segments, the last one being partial.
+	data := make([]byte, blockSizeV1+2*humanize.MiByte)
+	rand.Read(data)
+	objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		startOffset int64
+		length      int64
+	}{
+		// Range spanning both segments, fills the cache.
+		{blockSizeV1 - humanize.MiByte, 2 * humanize.MiByte},
+		// Range within first segment.
+		{1, humanize.MiByte},
+		// Range within last segment up to the end of the object.
+		{blockSizeV1 + 1, humanize.MiByte*2 - 1},
+		// Range covering all but the first byte.
+		{1, objInfo.Size - 1},
+	}
+
+	for i, testCase := range testCases {
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, testCase.startOffset, testCase.length, &buf); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data[testCase.startOffset:testCase.startOffset+testCase.length]) {
+			t.Fatalf("Test %d: range content mismatch", i+1)
+		}
+
+		// First range read would have saved both segments.
+		if i == 0 {
+			for index := int64(0); index < 2; index++ {
+				if _, err = xl.objCache.GetSegment(pathJoin(bucket, object), objInfo.ModTime, index); err != nil {
+					t.Fatalf("Expected segment %d to be cached, got %v", index, err)
+				}
+			}
+
+			// Remove object data from disks, subsequent reads must be
+			// served entirely from cached segments.
+			for _, dir := range fsDirs {
+				if err = os.RemoveAll(path.Join(dir, bucket, object, "part.1")); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	// Overwriting the object drops its cached segments.
+	if _, err = obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = xl.objCache.GetSegment(pathJoin(bucket, object), time.Time{}, 0); err != objcache.ErrKeyNotFoundInCache {
+		t.Fatalf("Expected ErrKeyNotFoundInCache, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+)
+
+// placementConfig - buckets pinned to groups of XL disks, objects of
+// a pinned bucket are erasure coded across the disks of its group
+// only, e.g. to keep frequently accessed buckets on SSDs.
+type placementConfig struct {
+	// Groups of disks by name, disks are listed as their endpoints
+	// on the command line.
+	Groups map[string][]string `json:"groups"`
+
+	// Group of every pinned bucket, other buckets use all disks.
+	Buckets map[string]string `json:"buckets"`
+}
+
+// Validate - validates placement config.
+func (c placementConfig) Validate() error {
+	for name, endpoints := range c.Groups {
+		if name == "" {
+			return fmt.Errorf("Placement group name cannot be empty")
+		}
+		count := len(endpoints)
+		if count < minErasureBlocks || count > maxErasureBlocks || count%2 != 0 {
+			return fmt.Errorf("Placement group ‘%s’ has %d disks, it should be an even number between %d and %d",
+				name, count, minErasureBlocks, maxErasureBlocks)
+		}
+		seen := make(map[string]struct{})
+		for _, endpoint := range endpoints {
+			if _, ok := seen[endpoint]; ok {
+				return fmt.Errorf("Disk ‘%s’ is listed twice in placement group ‘%s’", endpoint, name)
+			}
+			seen[endpoint] = struct{}{}
+		}
+	}
+	for bucket, name := range c.Buckets {
+		if !IsValidBucketName(bucket) {
+			return fmt.Errorf("Invalid bucket name ‘%s’ in placement", bucket)
+		}
+		if _, ok := c.Groups[name]; !ok {
+			return fmt.Errorf("Unknown placement group ‘%s’ of bucket ‘%s’", name, bucket)
+		}
+	}
+	return nil
+}
+
+// xlPlacement - disks of XL setup and of every pinned bucket, in the
+// order of format.json.
+type xlPlacement struct {
+	disks   []StorageAPI
+	buckets map[string][]StorageAPI
+}
+
+// newXLPlacement - resolves disks of pinned buckets. bootstrapDisks
+// are ordered like endpoints, disks like format.json of the setup,
+// the position of a disk offline at startup is taken from its
+// endpoint.
+func newXLPlacement(config placementConfig, endpoints EndpointList, bootstrapDisks, disks []StorageAPI) (*xlPlacement, error) {
+	if len(config.Buckets) == 0 {
+		return nil, nil
+	}
+	if len(endpoints) != len(bootstrapDisks) || len(disks) != len(bootstrapDisks) {
+		return nil, fmt.Errorf("Bucket placement needs the endpoints of all %d disks", len(disks))
+	}
+
+	positions := make(map[string]int)
+	taken := make(map[int]string)
+	for i, endpoint := range endpoints {
+		position := i
+		for j, disk := range disks {
+			if disk != nil && disk == bootstrapDisks[i] {
+				position = j
+				break
+			}
+		}
+		if other, ok := taken[position]; ok {
+			return nil, fmt.Errorf("Unable to locate disks ‘%s’ and ‘%s’, they are offline or out of order", other, endpoint)
+		}
+		taken[position] = endpoint.String()
+		positions[endpoint.String()] = position
+	}
+
+	groups := make(map[string][]StorageAPI)
+	for name, groupEndpoints := range config.Groups {
+		var groupPositions []int
+		for _, endpoint := range groupEndpoints {
+			position, ok := positions[endpoint]
+			if !ok {
+				return nil, fmt.Errorf("Disk ‘%s’ of placement group ‘%s’ is not a disk of this setup", endpoint, name)
+			}
+			groupPositions = append(groupPositions, position)
+		}
+		// Disks are kept in format order, so that objects are
+		// erasure coded the same way on every server.
+		sort.Ints(groupPositions)
+		groupDisks := make([]StorageAPI, len(groupPositions))
+		for i, position := range groupPositions {
+			groupDisks[i] = disks[position]
+		}
+		groups[name] = groupDisks
+	}
+
+	placement := &xlPlacement{
+		disks:   disks,
+		buckets: make(map[string][]StorageAPI),
+	}
+	for bucket, name := range config.Buckets {
+		placement.buckets[bucket] = groups[name]
+	}
+	return placement, nil
+}
+
+// forBucket - returns XL object layer storing objects of bucket, its
+// disks are restricted to the group the bucket is pinned to if any.
+func (xl xlObjects) forBucket(bucket string) xlObjects {
+	if xl.placement == nil {
+		return xl
+	}
+	disks, ok := xl.placement.buckets[bucket]
+	if !ok {
+		disks = xl.placement.disks
+	} else {
+		// Disks added to the setup are not part of any group.
+		xl.expansion = nil
+	}
+	xl.storageDisks = disks
+	xl.dataBlocks, xl.parityBlocks = len(disks)/2, len(disks)/2
+	xl.readQuorum, xl.writeQuorum = len(disks)/2, len(disks)/2+1
+	return xl
+}
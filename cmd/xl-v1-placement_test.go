@@ -0,0 +1,194 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests validating placement config.
+func TestPlacementConfigValidate(t *testing.T) {
+	disks := []string{"/mnt/ssd1", "/mnt/ssd2", "/mnt/ssd3", "/mnt/ssd4"}
+	testCases := []struct {
+		config  placementConfig
+		success bool
+	}{
+		// No bucket pinned.
+		{placementConfig{}, true},
+		// Valid placement.
+		{placementConfig{Groups: map[string][]string{"ssd": disks}, Buckets: map[string]string{"hot": "ssd"}}, true},
+		// Empty group name.
+		{placementConfig{Groups: map[string][]string{"": disks}}, false},
+		// Too few disks.
+		{placementConfig{Groups: map[string][]string{"ssd": disks[:2]}}, false},
+		// Odd number of disks.
+		{placementConfig{Groups: map[string][]string{"ssd": append([]string{"/mnt/ssd5"}, disks...)}}, false},
+		// Disk listed twice.
+		{placementConfig{Groups: map[string][]string{"ssd": append([]string{"/mnt/ssd1"}, disks[:3]...)}}, false},
+		// Invalid bucket name.
+		{placementConfig{Groups: map[string][]string{"ssd": disks}, Buckets: map[string]string{"h": "ssd"}}, false},
+		// Unknown group.
+		{placementConfig{Groups: map[string][]string{"ssd": disks}, Buckets: map[string]string{"hot": "hdd"}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+// Tests objects of pinned buckets are stored on the disks of their
+// group only.
+func TestXLPlacement(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(rootPath)
+	defer func(endpoints EndpointList) { globalEndpoints = endpoints }(globalEndpoints)
+
+	fsDirs, err := getRandomDisks(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	endpoints := mustGetNewEndpointList(fsDirs...)
+	globalEndpoints = endpoints
+	var group []string
+	for _, endpoint := range endpoints[4:] {
+		group = append(group, endpoint.String())
+	}
+	defer func() { serverConfig.Placement = placementConfig{} }()
+	serverConfig.Placement = placementConfig{
+		Groups:  map[string][]string{"ssd": group},
+		Buckets: map[string]string{"hot": "ssd"},
+	}
+
+	obj, _, err := initObjectLayer(endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+	if xl.placement == nil || len(xl.forBucket("hot").storageDisks) != 4 || len(xl.forBucket("cold").storageDisks) != 8 {
+		t.Fatal("Expected hot bucket to be pinned to 4 disks")
+	}
+
+	data := []byte("hello, world")
+	for _, bucket := range []string{"hot", "cold"} {
+		if err = obj.MakeBucket(bucket); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = obj.PutObject(bucket, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		uploadID, err := obj.NewMultipartUpload(bucket, "multipart", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part, err := obj.PutObjectPart(bucket, "multipart", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = obj.CompleteMultipartUpload(bucket, "multipart", uploadID, []CompletePart{{PartNumber: 1, ETag: part.ETag}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Objects of the pinned bucket are on its group only.
+	for i, endpoint := range endpoints {
+		for _, object := range []string{"object", "multipart"} {
+			_, err = os.Stat(filepath.Join(endpoint.Path, "hot", object, xlMetaJSONFile))
+			if inGroup := i >= 4; inGroup != (err == nil) {
+				t.Errorf("Disk %d: expected hot/%s on disk %t, got %v", i+1, object, inGroup, err)
+			}
+			if _, err = os.Stat(filepath.Join(endpoint.Path, "cold", object, xlMetaJSONFile)); err != nil {
+				t.Errorf("Disk %d: expected cold/%s on disk, got %v", i+1, object, err)
+			}
+		}
+	}
+
+	// Objects are read, copied, listed and deleted through their group.
+	if _, err = obj.CopyObject("hot", "object", "cold", "copy", nil); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject("cold", "copy", 0, int64(len(data)), &buffer); err != nil || !bytes.Equal(buffer.Bytes(), data) {
+		t.Fatalf("Unexpected copied object %q, %v", buffer.Bytes(), err)
+	}
+	result, err := obj.ListObjects("hot", "", "", "", 10)
+	if err != nil || len(result.Objects) != 2 {
+		t.Fatalf("Unexpected listing %v, %v", result, err)
+	}
+	if err = obj.DeleteObject("hot", "object"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.GetObjectInfo("hot", "object"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected object to be deleted, got %v", err)
+	}
+}
+
+// Tests resolving disks of placement groups.
+func TestNewXLPlacement(t *testing.T) {
+	fsDirs := []string{"/mnt/disk1", "/mnt/disk2", "/mnt/disk3", "/mnt/disk4"}
+	endpoints := mustGetNewEndpointList(fsDirs...)
+	disks := make([]StorageAPI, len(fsDirs))
+	for i := range disks {
+		disks[i] = &retryStorage{}
+	}
+	config := placementConfig{
+		Groups:  map[string][]string{"ssd": {"/mnt/disk4", "/mnt/disk2", "/mnt/disk3", "/mnt/disk1"}},
+		Buckets: map[string]string{"hot": "ssd"},
+	}
+
+	// Nothing to resolve without pinned buckets.
+	if placement, err := newXLPlacement(placementConfig{}, endpoints, disks, disks); placement != nil || err != nil {
+		t.Fatalf("Expected no placement, got %v, %v", placement, err)
+	}
+
+	// Disks are reordered like format.json, offline disks keep
+	// their position.
+	reordered := []StorageAPI{disks[1], disks[0], disks[2], nil}
+	placement, err := newXLPlacement(config, endpoints, disks, reordered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, disk := range placement.buckets["hot"] {
+		if disk != reordered[i] {
+			t.Fatalf("Disk %d: expected disks in format order", i+1)
+		}
+	}
+
+	// Offline disk whose position is taken by another one.
+	if _, err = newXLPlacement(config, endpoints, disks, []StorageAPI{disks[0], disks[2], nil, disks[3]}); err == nil {
+		t.Fatal("Expected an error for an offline disk out of order")
+	}
+
+	// Unknown disk.
+	config.Groups["ssd"][0] = "/mnt/disk5"
+	if _, err = newXLPlacement(config, endpoints, disks, disks); err == nil {
+		t.Fatal("Expected an error for an unknown disk")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// Time a server is reported degraded after a read failed over from
+// a disk to the remaining ones.
+const readFailoverDegradedWindow = 5 * time.Minute
+
+// readFailovers - tracks reads of erasure coded files which failed on
+// some disks mid-read and were served from the remaining disks.
+type readFailovers struct {
+	count atomic.Uint64
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// isReadFailoverErr - returns true if a read failed because of the
+// disk, missing and corrupted files are left to healing.
+func isReadFailoverErr(err error) bool {
+	switch errorCause(err) {
+	case nil, errFileNotFound, errVolumeNotFound, errBitRotHashMismatch:
+		return false
+	}
+	return true
+}
+
+// record - records a read which failed on disk, the first failover
+// of a healthy server is logged.
+func (r *readFailovers) record(disk StorageAPI, volume, path string, err error) {
+	r.count.Inc()
+
+	r.mu.Lock()
+	wasDegraded := UTCNow().Sub(r.last) < readFailoverDegradedWindow
+	r.last = UTCNow()
+	r.mu.Unlock()
+
+	if !wasDegraded {
+		errorIf(err, "Unable to read %s/%s from %s, reading from the remaining disks. Server is degraded.", volume, path, disk)
+	}
+}
+
+// isDegraded - returns true if a read failed over from a disk within
+// readFailoverDegradedWindow.
+func (r *readFailovers) isDegraded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.last.IsZero() && UTCNow().Sub(r.last) < readFailoverDegradedWindow
+}
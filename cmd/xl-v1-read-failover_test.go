@@ -0,0 +1,123 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/bpool"
+)
+
+// midReadFailDisk - fails all reads after the first one, like a disk
+// going offline in the middle of a read.
+type midReadFailDisk struct {
+	*posix
+	reads int
+}
+
+func (d *midReadFailDisk) ReadFile(volume string, path string, offset int64, buf []byte) (n int64, err error) {
+	d.reads++
+	if d.reads > 1 {
+		return 0, errFaultyDisk
+	}
+	return d.posix.ReadFile(volume, path, offset, buf)
+}
+
+// Test reads failing on disks mid-read are served from the remaining
+// disks and mark the server degraded.
+func TestReadFailoverMidRead(t *testing.T) {
+	defer func(failovers *readFailovers) {
+		globalReadFailovers = failovers
+	}(globalReadFailovers)
+	globalReadFailovers = &readFailovers{}
+
+	dataBlocks, parityBlocks := 4, 4
+	blockSize := int64(64 * humanize.KiByte)
+	setup, err := newErasureTestSetup(dataBlocks, parityBlocks, blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setup.Remove()
+	disks := setup.disks
+
+	// Four blocks, every disk is read once per block.
+	data := make([]byte, 4*blockSize)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	length := int64(len(data))
+	_, _, err = erasureCreateFile(disks, "testbucket", "testobject", bytes.NewReader(data), true, blockSize, dataBlocks, parityBlocks, bitRotAlgo, dataBlocks+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if globalReadFailovers.isDegraded() {
+		t.Fatal("Expected server not to be degraded before any failover")
+	}
+
+	// Two data disks fail after the first block.
+	disks[0] = &midReadFailDisk{posix: disks[0].(*posix)}
+	disks[2] = &midReadFailDisk{posix: disks[2].(*posix)}
+
+	pool := bpool.NewBytePool(getChunkSize(blockSize, dataBlocks), len(disks))
+	buf := &bytes.Buffer{}
+	// Checksums are not verified so every block is read from disks.
+	if _, _, err = erasureReadFile(buf, disks, "testbucket", "testobject", 0, length, length, blockSize, dataBlocks, parityBlocks, nil, bitRotAlgo, pool); err != nil {
+		t.Fatalf("Expected read to succeed on the remaining disks, got %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Contents of the erasure coded file differs")
+	}
+
+	if count := globalReadFailovers.count.Load(); count != 2 {
+		t.Fatalf("Expected 2 read failovers, got %d", count)
+	}
+	if !globalReadFailovers.isDegraded() {
+		t.Fatal("Expected server to be degraded after a read failover")
+	}
+
+	// Server is no longer degraded once the window is over.
+	globalReadFailovers.last = UTCNow().Add(-readFailoverDegradedWindow - time.Second)
+	if globalReadFailovers.isDegraded() {
+		t.Fatal("Expected server not to be degraded after the window")
+	}
+}
+
+// Test errors counted as read failovers.
+func TestIsReadFailoverErr(t *testing.T) {
+	testCases := []struct {
+		err      error
+		failover bool
+	}{
+		{nil, false},
+		{errFileNotFound, false},
+		{traceError(errVolumeNotFound), false},
+		{errBitRotHashMismatch, false},
+		{errFaultyDisk, true},
+		{traceError(errDiskNotFound), true},
+		{errFaultyRemoteDisk, true},
+	}
+	for i, testCase := range testCases {
+		if failover := isReadFailoverErr(testCase.err); failover != testCase.failover {
+			t.Errorf("Test %d: expected %v for %v, got %v", i+1, testCase.failover, testCase.err, failover)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	// Number of segments prefetched into object cache ahead of
+	// sequential range reads of an object.
+	readAheadSegments = 2
+
+	// Maximum number of objects whose range reads are tracked.
+	readAheadMaxObjects = 10000
+)
+
+// readAheadTracker - detects sequential range reads of objects, such
+// as those of video streaming clients, for their next segments to be
+// prefetched into object cache.
+type readAheadTracker struct {
+	mutex *sync.Mutex
+
+	// Offset where the last range read of an object ended.
+	lastEnd map[string]int64
+
+	// Objects being prefetched.
+	inFlight map[string]struct{}
+}
+
+// newReadAheadTracker - initializes a new read-ahead tracker.
+func newReadAheadTracker() *readAheadTracker {
+	return &readAheadTracker{
+		mutex:    &sync.Mutex{},
+		lastEnd:  make(map[string]int64),
+		inFlight: make(map[string]struct{}),
+	}
+}
+
+// observe - records a range read of an object, returns true if it
+// continues the previous range read of the object and the object is
+// not already being prefetched, the caller must then call done()
+// once the prefetch finishes.
+func (t *readAheadTracker) observe(key string, startOffset, endOffset int64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lastEnd, ok := t.lastEnd[key]
+	if !ok && len(t.lastEnd) >= readAheadMaxObjects {
+		// Forget all tracked objects, sequential readers are
+		// detected again on their next range read.
+		t.lastEnd = make(map[string]int64)
+	}
+	t.lastEnd[key] = endOffset
+
+	if !ok || startOffset != lastEnd {
+		return false
+	}
+	if _, ok = t.inFlight[key]; ok {
+		return false
+	}
+	t.inFlight[key] = struct{}{}
+	return true
+}
+
+// done - marks the prefetch of an object finished.
+func (t *readAheadTracker) done(key string) {
+	t.mutex.Lock()
+	delete(t.inFlight, key)
+	t.mutex.Unlock()
+}
+
+// readAheadObject - saves readAheadSegments segments of an object
+// starting at offset into object cache, unless already cached. The
+// object is not prefetched if it was modified after modTime.
+func (xl xlObjects) readAheadObject(bucket, object string, modTime time.Time, offset int64) {
+	key := path.Join(bucket, object)
+	defer xl.readAhead.done(key)
+
+	// Lock the object while reading, segments of an object being
+	// overwritten must not be cached.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	// Objects not rebalanced yet are read from the previous disks.
+	xl = xl.forObject(bucket, object)
+
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+		return
+	}
+	onlineDisks, latestModTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+	if !latestModTime.Equal(modTime) {
+		// Object was modified since it was read.
+		return
+	}
+	xlMeta, err := pickValidXLMeta(metaArr, latestModTime)
+	if err != nil {
+		return
+	}
+	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
+	metaArr = shufflePartsMetadata(metaArr, xlMeta.Erasure.Distribution)
+
+	unpin := globalGenerationPins.pin(bucket, object, xlMeta.DataDir)
+	defer unpin()
+
+	segmentSize := xlMeta.Erasure.BlockSize
+	endOffset := offset + readAheadSegments*segmentSize
+	for index := offset / segmentSize; index*segmentSize < endOffset && index*segmentSize < xlMeta.Stat.Size; index++ {
+		if _, err = xl.objCache.GetSegment(key, modTime, index); err == nil {
+			continue
+		}
+		var segment []byte
+		segment, err = xl.readObjectSegment(bucket, object, xlMeta, metaArr, onlineDisks, index)
+		if err != nil {
+			errorIf(err, "Unable to prefetch `%s/%s`.", bucket, object)
+			return
+		}
+		if err = xl.objCache.PutSegment(key, index, segment); err != nil {
+			// Cache is full.
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/objcache"
+)
+
+// Tests detection of sequential range reads.
+func TestReadAheadTracker(t *testing.T) {
+	tracker := newReadAheadTracker()
+	testCases := []struct {
+		key         string
+		startOffset int64
+		endOffset   int64
+		done        bool
+		expected    bool
+	}{
+		// Test 1: first range read of an object.
+		{"bucket/object", 0, 100, false, false},
+		// Test 2: range read continuing the previous one.
+		{"bucket/object", 100, 200, false, true},
+		// Test 3: sequential, but object is being prefetched.
+		{"bucket/object", 200, 300, true, false},
+		// Test 4: sequential once the prefetch is done.
+		{"bucket/object", 300, 400, false, true},
+		// Test 5: range read of another object.
+		{"bucket/other", 400, 500, false, false},
+		// Test 6: non sequential range read.
+		{"bucket/other", 600, 700, false, false},
+	}
+	for i, testCase := range testCases {
+		if got := tracker.observe(testCase.key, testCase.startOffset, testCase.endOffset); got != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+		if testCase.done {
+			tracker.done(testCase.key)
+		}
+	}
+}
+
+// Tests segments following sequential range reads are prefetched
+// into object cache.
+func TestXLGetObjectReadAhead(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+	if xl.objCache, err = objcache.New(110*humanize.MiByte, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	xl.objCacheEnabled = true
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucket(bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Object spanning three segments, the last one being partial.
+	data := make([]byte, 2*blockSizeV1+humanize.MiByte)
+	rand.Read(data)
+	objInfo, err := obj.PutObject(bucket, object, int64(len(data)), bytes.NewReader(data), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two sequential range reads within the first segment.
+	for _, offset := range []int64{0, humanize.MiByte} {
+		var buf bytes.Buffer
+		if err = obj.GetObject(bucket, object, offset, humanize.MiByte, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data[offset:offset+humanize.MiByte]) {
+			t.Fatal("range content mismatch")
+		}
+	}
+
+	// Wait for the prefetch to finish.
+	for i := 0; ; i++ {
+		xl.readAhead.mutex.Lock()
+		n := len(xl.readAhead.inFlight)
+		xl.readAhead.mutex.Unlock()
+		if n == 0 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("prefetch did not finish in time")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Following segments were prefetched.
+	for index := int64(1); index < 3; index++ {
+		segment, err := xl.objCache.GetSegment(pathJoin(bucket, object), objInfo.ModTime, index)
+		if err != nil {
+			t.Fatalf("Expected segment %d to be prefetched, got %v", index, err)
+		}
+		segmentStart := index * blockSizeV1
+		if !bytes.Equal(segment, data[segmentStart:segmentStart+int64(len(segment))]) {
+			t.Fatalf("Prefetched segment %d content mismatch", index)
+		}
+	}
+}
@@ -0,0 +1,530 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Disks can be added to an XL setup by restarting its servers with
+// fresh disks appended to the command line. They are appended to the
+// JBOD of format.json, which keeps the number of disks of the setup
+// before, and objects are then written across all disks. Objects
+// written before stay on the previous disks until the first server
+// rebalances them across all disks in background.
+
+// Interval between passes over the objects of an expanded setup,
+// while some objects could not be rebalanced yet.
+const rebalanceRetryInterval = 5 * time.Minute
+
+// Default throughput caps of rebalancing, 0 for unlimited.
+const (
+	defaultRebalanceBandwidth = 64 * humanize.MiByte
+	defaultRebalanceObjects   = 0
+)
+
+// errNoRebalance - returned when no rebalance was started on this
+// server.
+var errNoRebalance = errors.New("No rebalance was started on this server")
+
+// xlExpansion - disks of the setup before disks were added to it, in
+// format order. Objects written before are found on these disks only.
+type xlExpansion struct {
+	disks []StorageAPI
+
+	// Set once all objects are rebalanced across all disks.
+	rebalanced int32
+}
+
+// newXLExpansion - returns the previous disks of the setup if disks
+// were added to it and objects are not rebalanced yet, nil otherwise.
+// disks are ordered like format.json.
+func newXLExpansion(disks []StorageAPI) *xlExpansion {
+	formatConfigs, _ := loadAllFormats(disks)
+	for _, formatConfig := range formatConfigs {
+		if formatConfig == nil || formatConfig.XL == nil {
+			continue
+		}
+		expandedFrom := formatConfig.XL.ExpandedFrom
+		if expandedFrom == 0 || expandedFrom >= len(disks) {
+			return nil
+		}
+		return &xlExpansion{disks: disks[:expandedFrom]}
+	}
+	return nil
+}
+
+// isPending - returns true if some objects may not be rebalanced yet.
+func (e *xlExpansion) isPending() bool {
+	return e != nil && atomic.LoadInt32(&e.rebalanced) == 0
+}
+
+// forObject - returns XL object layer storing object, its disks are
+// restricted to the disks of the setup before disks were added to it
+// while the object is not rebalanced.
+func (xl xlObjects) forObject(bucket, object string) xlObjects {
+	if !xl.expansion.isPending() {
+		return xl
+	}
+	for _, disk := range xl.getLoadBalancedDisks() {
+		if disk == nil {
+			continue
+		}
+		xlMeta, err := readXLMeta(disk, bucket, object)
+		if err != nil {
+			continue
+		}
+		if len(xlMeta.Erasure.Distribution) == len(xl.expansion.disks) {
+			disks := xl.expansion.disks
+			xl.storageDisks = disks
+			xl.dataBlocks, xl.parityBlocks = len(disks)/2, len(disks)/2
+			xl.readQuorum, xl.writeQuorum = len(disks)/2, len(disks)/2+1
+		}
+		return xl
+	}
+	return xl
+}
+
+// finishExpansion - records in format.json of all disks that objects
+// are rebalanced across all disks.
+func (xl xlObjects) finishExpansion() error {
+	formatConfigs, sErrs := loadAllFormats(xl.storageDisks)
+	for _, err := range sErrs {
+		if err != nil {
+			return fmt.Errorf("Unable to read format of all disks, %s", err)
+		}
+	}
+	for _, formatConfig := range formatConfigs {
+		formatConfig.XL.ExpandedFrom = 0
+	}
+	if err := saveFormatXL(xl.storageDisks, formatConfigs); err != nil {
+		return err
+	}
+	atomic.StoreInt32(&xl.expansion.rebalanced, 1)
+	return nil
+}
+
+// rebalanceObject - erasure codes an object written before disks were
+// added across all disks, keeping its metadata, parts and storage
+// class. Returns size of the object, -1 if it is rebalanced already
+// or was removed.
+func (xl xlObjects) rebalanceObject(bucket, object string, throttle *rebalanceThrottle) (int64, error) {
+	xl = xl.forBucket(bucket)
+
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	src := xl.forObject(bucket, object)
+	if len(src.storageDisks) == len(xl.storageDisks) {
+		return -1, nil
+	}
+
+	// Read metadata associated with the object from previous disks.
+	metaArr, errs := readAllXLMetadata(src.storageDisks, bucket, object)
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, src.readQuorum); reducedErr != nil {
+		if isErrObjectNotFound(toObjectErr(reducedErr, bucket, object)) {
+			return -1, nil
+		}
+		return 0, toObjectErr(reducedErr, bucket, object)
+	}
+	_, modTime := listOnlineDisks(src.storageDisks, metaArr, errs)
+	srcMeta, err := pickValidXLMeta(metaArr, modTime)
+	if err != nil {
+		return 0, toObjectErr(err, bucket, object)
+	}
+
+	dst := xl.forStorageClass(srcMeta.Meta)
+	xlMeta := newXLMetaV1(object, dst.dataBlocks, dst.parityBlocks)
+	xlMeta.Stat = srcMeta.Stat
+	xlMeta.Meta = srcMeta.Meta
+	xlMeta.DataDir = mustGetUUID()
+	partsMetadata := make([]xlMetaV1, len(dst.storageDisks))
+	for index := range partsMetadata {
+		partsMetadata[index] = xlMeta
+	}
+	onlineDisks := shuffleDisks(dst.storageDisks, xlMeta.Erasure.Distribution)
+
+	tempObj := mustGetUUID()
+	defer dst.deleteObject(minioMetaTmpBucket, tempObj)
+
+	// Object is read from previous disks by GetObject.
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(xl.GetObject(bucket, object, 0, srcMeta.Stat.Size, pipeWriter))
+	}()
+	defer pipeReader.Close()
+	throttle.addObject()
+	reader := throttle.reader(pipeReader)
+
+	if srcMeta.Inline {
+		if _, err = putInlineObjectData(reader, srcMeta.Stat.Size, partsMetadata); err != nil {
+			return 0, toObjectErr(err, bucket, object)
+		}
+	} else {
+		for _, part := range srcMeta.Parts {
+			algo := getBitRotAlgo()
+			partSize, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, pathJoin(tempObj, part.Name),
+				io.LimitReader(reader, part.Size), true, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks,
+				xlMeta.Erasure.ParityBlocks, algo, dst.writeQuorum)
+			if err != nil {
+				return 0, toObjectErr(err, bucket, object)
+			}
+			if partSize < part.Size {
+				return 0, traceError(IncompleteBody{})
+			}
+			for index := range partsMetadata {
+				partsMetadata[index].AddObjectPart(part.Number, part.Name, part.ETag, part.Size)
+				partsMetadata[index].Erasure.AddCheckSumInfo(checkSumInfo{
+					Name:      part.Name,
+					Hash:      checkSums[index],
+					Algorithm: algo,
+				})
+			}
+		}
+	}
+
+	// Write unique `xl.json` for each disk.
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, dst.writeQuorum); err != nil {
+		return 0, toObjectErr(err, bucket, object)
+	}
+
+	// Switch the object to its generation on all disks, then delete
+	// the previous one.
+	if err = swapObjectGeneration(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xlMeta.DataDir, dst.writeQuorum); err != nil {
+		return 0, toObjectErr(err, bucket, object)
+	}
+	retireObjectGeneration(src.storageDisks, bucket, object, metaArr)
+
+	if xl.objCacheEnabled {
+		xl.objCache.Delete(pathJoin(bucket, object))
+	}
+	return srcMeta.Stat.Size, nil
+}
+
+// pendingUploads - returns the number of multipart uploads of bucket
+// started before disks were added, they are completed on the previous
+// disks.
+func (xl xlObjects) pendingUploads(bucket string) (int64, error) {
+	var pending int64
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := xl.ListMultipartUploads(bucket, "", keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			return pending, err
+		}
+		for _, upload := range result.Uploads {
+			uploadIDPath := pathJoin(bucket, upload.Object, upload.UploadID)
+			if len(xl.forObject(minioMetaMultipartBucket, uploadIDPath).storageDisks) != len(xl.storageDisks) {
+				pending++
+			}
+		}
+		if !result.IsTruncated {
+			return pending, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// rebalanceThrottle - caps throughput of rebalancing, objects are read
+// no faster than the given bytes and objects per second on average.
+type rebalanceThrottle struct {
+	bytesPerSec   int64
+	objectsPerSec int64
+
+	started time.Time
+	bytes   int64
+	objects int64
+}
+
+// newRebalanceThrottle - returns a throttle with the given caps, 0 for
+// unlimited.
+func newRebalanceThrottle(bytesPerSec, objectsPerSec int64) *rebalanceThrottle {
+	return &rebalanceThrottle{
+		bytesPerSec:   bytesPerSec,
+		objectsPerSec: objectsPerSec,
+		started:       UTCNow(),
+	}
+}
+
+// reset - measures throughput from now on, e.g. after waiting between
+// passes.
+func (t *rebalanceThrottle) reset() {
+	t.started = UTCNow()
+	t.bytes, t.objects = 0, 0
+}
+
+// delay - returns how long to wait at now for throughput to be back
+// under its caps.
+func (t *rebalanceThrottle) delay(now time.Time) time.Duration {
+	var expected time.Duration
+	if t.bytesPerSec > 0 {
+		expected = time.Duration(float64(t.bytes) / float64(t.bytesPerSec) * float64(time.Second))
+	}
+	if t.objectsPerSec > 0 {
+		if d := time.Duration(float64(t.objects) / float64(t.objectsPerSec) * float64(time.Second)); d > expected {
+			expected = d
+		}
+	}
+	if d := expected - now.Sub(t.started); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// addObject - accounts for an object about to be read, waits while
+// too many objects were read.
+func (t *rebalanceThrottle) addObject() {
+	t.objects++
+	time.Sleep(t.delay(UTCNow()))
+}
+
+// reader - returns a reader waiting while too many bytes were read.
+func (t *rebalanceThrottle) reader(reader io.Reader) io.Reader {
+	return &rebalanceThrottledReader{reader, t}
+}
+
+// rebalanceThrottledReader - reader accounting for bytes read by the
+// throttle.
+type rebalanceThrottledReader struct {
+	reader   io.Reader
+	throttle *rebalanceThrottle
+}
+
+func (r *rebalanceThrottledReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.throttle.bytes += int64(n)
+	time.Sleep(r.throttle.delay(UTCNow()))
+	return n, err
+}
+
+// States of rebalancing objects.
+const (
+	rebalanceRunning = "running"
+	rebalanceDone    = "done"
+)
+
+// rebalanceStatus - progress of rebalancing objects across disks added
+// to the setup, reported by admin API.
+type rebalanceStatus struct {
+	State string `json:"state"`
+
+	// Disks of the setup before and after disks were added.
+	PreviousDisks int `json:"previousDisks"`
+	Disks         int `json:"disks"`
+
+	// Passes over all objects, objects which could not be
+	// rebalanced are retried by the next pass.
+	Passes int `json:"passes"`
+	// Rebalanced objects and their total size.
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+	// Objects which could not be rebalanced, along with the last
+	// error.
+	Failed    int64  `json:"failed"`
+	LastError string `json:"lastError,omitempty"`
+	// Multipart uploads started before disks were added, found by
+	// the last pass. Rebalancing completes once they are completed
+	// or aborted.
+	PendingUploads int64 `json:"pendingUploads"`
+
+	// Throughput caps in bytes and objects per second, 0 when
+	// unlimited.
+	MaxBandwidth int64 `json:"maxBandwidth"`
+	MaxObjects   int64 `json:"maxObjects"`
+
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// xlRebalancer - rebalances objects of an expanded XL setup across all
+// its disks in background.
+type xlRebalancer struct {
+	mu     sync.Mutex
+	status *rebalanceStatus
+}
+
+// get - returns progress of rebalancing objects.
+func (r *xlRebalancer) get() (rebalanceStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status == nil {
+		return rebalanceStatus{}, errNoRebalance
+	}
+	return *r.status, nil
+}
+
+// run - rebalances objects in passes over all objects until a pass
+// finds nothing left to rebalance, then records it in format.json.
+func (r *xlRebalancer) run(xl xlObjects, throttle *rebalanceThrottle, retryInterval time.Duration, doneCh <-chan struct{}) {
+	status := &rebalanceStatus{
+		State:         rebalanceRunning,
+		PreviousDisks: len(xl.expansion.disks),
+		Disks:         len(xl.storageDisks),
+		MaxBandwidth:  throttle.bytesPerSec,
+		MaxObjects:    throttle.objectsPerSec,
+		Started:       UTCNow(),
+	}
+	r.mu.Lock()
+	r.status = status
+	r.mu.Unlock()
+
+	for {
+		throttle.reset()
+		rebalanced, complete := r.pass(xl, status, throttle)
+		if complete && rebalanced == 0 {
+			err := xl.finishExpansion()
+			r.mu.Lock()
+			if err == nil {
+				finished := UTCNow()
+				status.State = rebalanceDone
+				status.Finished = &finished
+				r.mu.Unlock()
+				return
+			}
+			status.LastError = err.Error()
+			r.mu.Unlock()
+			errorIf(err, "Unable to complete rebalancing objects.")
+		} else if complete {
+			// Objects created meanwhile from multipart uploads
+			// started before disks were added are looked for
+			// right away.
+			continue
+		}
+
+		select {
+		case <-time.After(retryInterval):
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// pass - rebalances all objects and bucket metadata once, returns the
+// number of rebalanced objects and false if some objects could not be
+// rebalanced or multipart uploads started before disks were added
+// are still pending.
+func (r *xlRebalancer) pass(xl xlObjects, status *rebalanceStatus, throttle *rebalanceThrottle) (rebalanced int64, complete bool) {
+	r.mu.Lock()
+	status.Passes++
+	r.mu.Unlock()
+
+	fail := func(err error) {
+		r.mu.Lock()
+		status.LastError = err.Error()
+		r.mu.Unlock()
+		complete = false
+	}
+
+	buckets, err := xl.ListBuckets()
+	if err != nil {
+		fail(err)
+		errorIf(err, "Unable to list buckets to rebalance.")
+		return 0, false
+	}
+
+	// Bucket metadata, e.g. bucket policies, is stored as objects of
+	// the meta bucket.
+	complete = true
+	rebalanced += r.rebalancePrefix(xl, minioMetaBucket, bucketConfigPrefix+slashSeparator, status, throttle, fail)
+
+	var pending int64
+	for _, bucket := range buckets {
+		// Buckets pinned to a group of disks are left out, their
+		// disks didn't change.
+		if !xl.forBucket(bucket.Name).expansion.isPending() {
+			continue
+		}
+		rebalanced += r.rebalancePrefix(xl, bucket.Name, "", status, throttle, fail)
+
+		uploads, err := xl.pendingUploads(bucket.Name)
+		if err != nil {
+			fail(err)
+			errorIf(err, "Unable to list multipart uploads of %s to rebalance.", bucket.Name)
+		}
+		pending += uploads
+	}
+
+	r.mu.Lock()
+	status.PendingUploads = pending
+	r.mu.Unlock()
+	return rebalanced, complete && pending == 0
+}
+
+// rebalancePrefix - rebalances objects of bucket under prefix, returns
+// the number of rebalanced objects.
+func (r *xlRebalancer) rebalancePrefix(xl xlObjects, bucket, prefix string, status *rebalanceStatus, throttle *rebalanceThrottle, fail func(error)) (rebalanced int64) {
+	marker := ""
+	for {
+		result, err := xl.listObjects(bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			fail(err)
+			errorIf(err, "Unable to list objects of %s to rebalance.", bucket)
+			return rebalanced
+		}
+
+		for _, objInfo := range result.Objects {
+			if objInfo.IsDir {
+				continue
+			}
+			size, err := xl.rebalanceObject(bucket, objInfo.Name, throttle)
+			if err != nil {
+				fail(err)
+				errorIf(err, "Unable to rebalance %s/%s.", bucket, objInfo.Name)
+				r.mu.Lock()
+				status.Failed++
+				r.mu.Unlock()
+				continue
+			}
+			if size >= 0 {
+				rebalanced++
+				r.mu.Lock()
+				status.Objects++
+				status.Size += size
+				r.mu.Unlock()
+			}
+		}
+
+		if !result.IsTruncated {
+			return rebalanced
+		}
+		marker = result.NextMarker
+	}
+}
+
+// startRebalance - starts rebalancing objects in background when disks
+// were added to an XL setup, only one server in a distributed setup
+// rebalances objects.
+func startRebalance(endpoints EndpointList, doneCh <-chan struct{}) {
+	if len(endpoints) == 0 || !endpoints[0].IsLocal {
+		return
+	}
+	xl, ok := newObjectLayerFn().(*xlObjects)
+	if !ok || !xl.expansion.isPending() {
+		return
+	}
+	throttle := newRebalanceThrottle(globalRebalanceBandwidth, globalRebalanceObjects)
+	go globalRebalancer.run(*xl, throttle, rebalanceRetryInterval, doneCh)
+}
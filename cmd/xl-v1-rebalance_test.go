@@ -0,0 +1,213 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Tests delays of the rebalance throttle.
+func TestRebalanceThrottleDelay(t *testing.T) {
+	started := UTCNow()
+	testCases := []struct {
+		bytesPerSec, objectsPerSec int64
+		bytes, objects             int64
+		elapsed                    time.Duration
+		delay                      time.Duration
+	}{
+		// Test 1: no caps.
+		{0, 0, 1 << 30, 1000, 0, 0},
+		// Test 2: under the bandwidth cap.
+		{1 << 20, 0, 1 << 20, 1, 2 * time.Second, 0},
+		// Test 3: over the bandwidth cap.
+		{1 << 20, 0, 4 << 20, 1, time.Second, 3 * time.Second},
+		// Test 4: over the objects cap.
+		{0, 10, 1 << 30, 50, 2 * time.Second, 3 * time.Second},
+		// Test 5: the most restrictive cap applies.
+		{1 << 20, 10, 2 << 20, 50, time.Second, 4 * time.Second},
+	}
+	for i, testCase := range testCases {
+		throttle := newRebalanceThrottle(testCase.bytesPerSec, testCase.objectsPerSec)
+		throttle.started = started
+		throttle.bytes, throttle.objects = testCase.bytes, testCase.objects
+		if delay := throttle.delay(started.Add(testCase.elapsed)); delay != testCase.delay {
+			t.Errorf("Test %d: expected delay %s, got %s", i+1, testCase.delay, delay)
+		}
+	}
+}
+
+// Tests adding disks to an XL setup and rebalancing objects across
+// them.
+func TestXLExpandAndRebalance(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	fsDirs, err := getRandomDisks(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	globalXLInlineThreshold = 1024
+	defer func() { globalXLInlineThreshold = 0 }()
+
+	obj, _, err := initObjectLayer(mustGetNewEndpointList(fsDirs[:8]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string][]byte{
+		"a":     bytes.Repeat([]byte("a"), 3*blockSizeV1+100),
+		"small": bytes.Repeat([]byte("b"), 100),
+		"empty": nil,
+		"multi": bytes.Repeat([]byte("c"), globalMinPartSize+100),
+	}
+	for name, data := range objects {
+		if name == "multi" {
+			continue
+		}
+		if _, err = obj.PutObject("bucket", name, int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	uploadID, err := obj.NewMultipartUpload("bucket", "multi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []CompletePart
+	for i, data := range [][]byte{objects["multi"][:globalMinPartSize], objects["multi"][globalMinPartSize:]} {
+		partInfo, perr := obj.PutObjectPart("bucket", "multi", uploadID, i+1, int64(len(data)), bytes.NewReader(data), "", "")
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		parts = append(parts, CompletePart{PartNumber: i + 1, ETag: partInfo.ETag})
+	}
+	multiInfo, err := obj.CompleteMultipartUpload("bucket", "multi", uploadID, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bucket metadata is rebalanced as well.
+	metaPath := pathJoin(bucketConfigPrefix, "bucket", "metadata.json")
+	if _, err = obj.PutObject(minioMetaBucket, metaPath, 2, bytes.NewReader([]byte("{}")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	pendingID, err := obj.NewMultipartUpload("bucket", "pending", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart with 4 fresh disks.
+	obj, _, err = initObjectLayer(mustGetNewEndpointList(fsDirs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+	if !xl.expansion.isPending() || len(xl.expansion.disks) != 8 {
+		t.Fatal("Expected objects to be on the previous 8 disks")
+	}
+
+	// Objects written before are served by the previous disks.
+	checkObjects := func() {
+		for name, data := range objects {
+			var buf bytes.Buffer
+			if err = obj.GetObject("bucket", name, 0, int64(len(data)), &buf); err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+			if !bytes.Equal(buf.Bytes(), data) {
+				t.Fatalf("%s: unexpected data", name)
+			}
+		}
+		objInfo, gerr := obj.GetObjectInfo("bucket", "multi")
+		if gerr != nil {
+			t.Fatal(gerr)
+		}
+		if objInfo.MD5Sum != multiInfo.MD5Sum {
+			t.Fatalf("Expected ETag %s, got %s", multiInfo.MD5Sum, objInfo.MD5Sum)
+		}
+	}
+	checkObjects()
+	result, err := obj.ListObjects("bucket", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != len(objects) {
+		t.Fatalf("Expected %d objects, got %d", len(objects), len(result.Objects))
+	}
+
+	// New objects are written across all disks.
+	if _, err = obj.PutObject("bucket", "new", 4, bytes.NewReader([]byte("new!")), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readXLMeta(xl.storageDisks[11], "bucket", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readXLMeta(xl.storageDisks[11], "bucket", "a"); errorCause(err) != errFileNotFound {
+		t.Fatalf("Expected %s, got %v", errFileNotFound, err)
+	}
+
+	// Rebalancing doesn't complete while an upload started before
+	// is pending.
+	rebalancer := &xlRebalancer{}
+	status := &rebalanceStatus{}
+	throttle := newRebalanceThrottle(0, 0)
+	rebalanced, complete := rebalancer.pass(*xl, status, throttle)
+	if rebalanced != int64(len(objects))+1 || complete || status.PendingUploads != 1 {
+		t.Fatalf("Unexpected pass, %d rebalanced, complete %t, %+v", rebalanced, complete, status)
+	}
+	checkObjects()
+	if err = obj.AbortMultipartUpload("bucket", "pending", pendingID); err != nil {
+		t.Fatal(err)
+	}
+	if rebalanced, complete = rebalancer.pass(*xl, status, throttle); rebalanced != 0 || !complete {
+		t.Fatalf("Unexpected pass, %d rebalanced, complete %t, %+v", rebalanced, complete, status)
+	}
+	if err = xl.finishExpansion(); err != nil {
+		t.Fatal(err)
+	}
+
+	// All objects are across all disks now.
+	for _, name := range []string{"a", "small", "empty", "multi"} {
+		xlMeta, rerr := readXLMeta(xl.storageDisks[11], "bucket", name)
+		if rerr != nil {
+			t.Fatalf("%s: %s", name, rerr)
+		}
+		if len(xlMeta.Erasure.Distribution) != 12 {
+			t.Fatalf("%s: expected 12 disks, got %d", name, len(xlMeta.Erasure.Distribution))
+		}
+	}
+	if _, err = readXLMeta(xl.storageDisks[11], minioMetaBucket, metaPath); err != nil {
+		t.Fatal(err)
+	}
+	checkObjects()
+
+	// Rebalancing is recorded in format.json.
+	obj, _, err = initObjectLayer(mustGetNewEndpointList(fsDirs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.(*xlObjects).expansion != nil {
+		t.Fatal("Expected objects to be rebalanced")
+	}
+	checkObjects()
+}
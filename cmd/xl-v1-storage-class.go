@@ -0,0 +1,134 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Storage class of objects stored with less parity.
+	reducedRedundancyStorageClass = "REDUCED_REDUNDANCY"
+
+	// Parity of reduced redundancy objects unless configured.
+	defaultRRSParity = 2
+
+	// Minimum parity of objects of any storage class.
+	minStorageClassParity = 2
+
+	// Prefix of parity of a storage class in config, e.g. "EC:4".
+	storageClassParityPrefix = "EC:"
+)
+
+// storageClassConfig - parity of objects of every storage class,
+// only used by the XL backend. Standard objects are stored with as
+// many parity shards as data shards unless configured.
+type storageClassConfig struct {
+	Standard string `json:"standard"`
+	RRS      string `json:"rrs"`
+}
+
+// parseStorageClassParity - parses parity of a storage class in the
+// form "EC:parity", returns 0 if none is set.
+func parseStorageClassParity(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if !strings.HasPrefix(value, storageClassParityPrefix) {
+		return 0, fmt.Errorf("Invalid storage class parity ‘%s’, it should be of the form ‘EC:parity’", value)
+	}
+	parity, err := strconv.Atoi(strings.TrimPrefix(value, storageClassParityPrefix))
+	if err != nil {
+		return 0, fmt.Errorf("Invalid storage class parity ‘%s’, it should be of the form ‘EC:parity’", value)
+	}
+	if parity < minStorageClassParity || parity > maxErasureBlocks/2 {
+		return 0, fmt.Errorf("Invalid storage class parity ‘%s’, it should be between %d and %d",
+			value, minStorageClassParity, maxErasureBlocks/2)
+	}
+	return parity, nil
+}
+
+// Validate - validates storage class config.
+func (c storageClassConfig) Validate() error {
+	standardParity, err := parseStorageClassParity(c.Standard)
+	if err != nil {
+		return err
+	}
+	rrsParity, err := parseStorageClassParity(c.RRS)
+	if err != nil {
+		return err
+	}
+	if standardParity != 0 && rrsParity > standardParity {
+		return fmt.Errorf("Reduced redundancy parity %d should not be more than standard parity %d", rrsParity, standardParity)
+	}
+	return nil
+}
+
+// isValidStorageClassHeader - returns true if storage class sent
+// in request headers, if any, is supported.
+func isValidStorageClassHeader(header http.Header) bool {
+	storageClass := header.Get(amzStorageClassHeader)
+	return storageClass == "" || storageClass == globalMinioDefaultStorageClass || storageClass == reducedRedundancyStorageClass
+}
+
+// newXLStorageClass - returns parity of standard and reduced
+// redundancy objects of XL setup with the given number of disks.
+func newXLStorageClass(config storageClassConfig, disks int) (standardParity, rrsParity int, err error) {
+	if standardParity, err = parseStorageClassParity(config.Standard); err != nil {
+		return 0, 0, err
+	}
+	if rrsParity, err = parseStorageClassParity(config.RRS); err != nil {
+		return 0, 0, err
+	}
+	if standardParity > disks/2 || rrsParity > disks/2 {
+		return 0, 0, fmt.Errorf("Storage class parity should not be more than %d with %d disks", disks/2, disks)
+	}
+	return standardParity, rrsParity, nil
+}
+
+// forStorageClass - returns XL object layer storing objects with the
+// storage class set in their metadata. Parity is capped to half of
+// the disks, e.g. of a smaller group a bucket is pinned to.
+func (xl xlObjects) forStorageClass(metadata map[string]string) xlObjects {
+	disks := len(xl.storageDisks)
+	parity := xl.standardParity
+	if metadata[amzStorageClassHeader] == reducedRedundancyStorageClass {
+		parity = xl.rrsParity
+		if parity == 0 {
+			parity = defaultRRSParity
+		}
+	}
+	if parity == 0 || parity > disks/2 {
+		parity = disks / 2
+	}
+	return xl.forErasure(disks-parity, parity)
+}
+
+// forErasure - returns XL object layer storing objects with the given
+// number of data and parity shards. Writes need as many disks as data
+// shards, one more when there are as many parity shards.
+func (xl xlObjects) forErasure(dataBlocks, parityBlocks int) xlObjects {
+	xl.dataBlocks, xl.parityBlocks = dataBlocks, parityBlocks
+	xl.writeQuorum = dataBlocks
+	if dataBlocks == parityBlocks {
+		xl.writeQuorum++
+	}
+	return xl
+}
@@ -0,0 +1,227 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Tests validating storage class config.
+func TestStorageClassConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config  storageClassConfig
+		success bool
+	}{
+		{storageClassConfig{}, true},
+		{storageClassConfig{Standard: "EC:8", RRS: "EC:4"}, true},
+		{storageClassConfig{RRS: "EC:6"}, true},
+		{storageClassConfig{Standard: "EC:4", RRS: "EC:4"}, true},
+		{storageClassConfig{Standard: "8"}, false},
+		{storageClassConfig{Standard: "EC:x"}, false},
+		{storageClassConfig{Standard: "EC:1"}, false},
+		{storageClassConfig{RRS: "EC:9"}, false},
+		{storageClassConfig{Standard: "EC:4", RRS: "EC:6"}, false},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.config.Validate(); (err == nil) != testCase.success {
+			t.Errorf("Test %d: Expected success %v, got %v", i+1, testCase.success, err)
+		}
+	}
+
+	// Parity can't be more than half of the disks.
+	if _, _, err := newXLStorageClass(storageClassConfig{Standard: "EC:4"}, 6); err == nil {
+		t.Error("Expected parity above half of the disks to fail")
+	}
+	standardParity, rrsParity, err := newXLStorageClass(storageClassConfig{Standard: "EC:4", RRS: "EC:2"}, 8)
+	if err != nil || standardParity != 4 || rrsParity != 2 {
+		t.Errorf("Expected parities 4 and 2, got %d, %d, %v", standardParity, rrsParity, err)
+	}
+}
+
+// Tests erasure coding of objects of every storage class.
+func TestXLForStorageClass(t *testing.T) {
+	rrs := map[string]string{amzStorageClassHeader: reducedRedundancyStorageClass}
+	testCases := []struct {
+		disks          int
+		standardParity int
+		rrsParity      int
+		metadata       map[string]string
+		dataBlocks     int
+		parityBlocks   int
+		writeQuorum    int
+	}{
+		{16, 0, 0, nil, 8, 8, 9},
+		{16, 0, 0, rrs, 14, 2, 14},
+		{16, 6, 4, nil, 10, 6, 10},
+		{16, 6, 4, rrs, 12, 4, 12},
+		// Parity is capped to half of the disks.
+		{4, 6, 4, nil, 2, 2, 3},
+		{4, 0, 0, rrs, 2, 2, 3},
+	}
+	for i, testCase := range testCases {
+		xl := xlObjects{
+			storageDisks:   make([]StorageAPI, testCase.disks),
+			standardParity: testCase.standardParity,
+			rrsParity:      testCase.rrsParity,
+		}
+		xl = xl.forStorageClass(testCase.metadata)
+		if xl.dataBlocks != testCase.dataBlocks || xl.parityBlocks != testCase.parityBlocks || xl.writeQuorum != testCase.writeQuorum {
+			t.Errorf("Test %d: Expected %d+%d with write quorum %d, got %d+%d with write quorum %d", i+1,
+				testCase.dataBlocks, testCase.parityBlocks, testCase.writeQuorum, xl.dataBlocks, xl.parityBlocks, xl.writeQuorum)
+		}
+	}
+}
+
+// Tests objects and multipart uploads of XL are erasure coded
+// according to their storage class.
+func TestXLStorageClassObjects(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(root)
+
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	xl.rrsParity = 4
+
+	if err = obj.MakeBucket("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1000)
+	rrs := map[string]string{amzStorageClassHeader: reducedRedundancyStorageClass}
+	if _, err = obj.PutObject("bucket", "standard", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject("bucket", "rrs", int64(len(data)), bytes.NewReader(data), rrs, ""); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload("bucket", "rrs-multipart", rrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part, err := obj.PutObjectPart("bucket", "rrs-multipart", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.CompleteMultipartUpload("bucket", "rrs-multipart", uploadID, []CompletePart{{PartNumber: 1, ETag: part.ETag}}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object       string
+		storageClass string
+		dataBlocks   int
+		parityBlocks int
+	}{
+		{"standard", globalMinioDefaultStorageClass, 8, 8},
+		{"rrs", reducedRedundancyStorageClass, 12, 4},
+		{"rrs-multipart", reducedRedundancyStorageClass, 12, 4},
+	}
+	for i, testCase := range testCases {
+		xlMeta, rErr := readXLMeta(xl.storageDisks[0], "bucket", testCase.object)
+		if rErr != nil {
+			t.Fatalf("Test %d: %s", i+1, rErr)
+		}
+		if xlMeta.Erasure.DataBlocks != testCase.dataBlocks || xlMeta.Erasure.ParityBlocks != testCase.parityBlocks {
+			t.Errorf("Test %d: Expected %d+%d, got %d+%d", i+1, testCase.dataBlocks, testCase.parityBlocks,
+				xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks)
+		}
+		objInfo, rErr := obj.GetObjectInfo("bucket", testCase.object)
+		if rErr != nil {
+			t.Fatalf("Test %d: %s", i+1, rErr)
+		}
+		if storageClass := getObjectStorageClass(objInfo); storageClass != testCase.storageClass {
+			t.Errorf("Test %d: Expected storage class %s, got %s", i+1, testCase.storageClass, storageClass)
+		}
+		var buffer bytes.Buffer
+		if err = obj.GetObject("bucket", testCase.object, 0, objInfo.Size, &buffer); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data) {
+			t.Errorf("Test %d: Expected object data to match", i+1)
+		}
+	}
+}
+
+// Wrapper for calling storage class HTTP handler tests for both XL multiple disks and single node setup.
+func TestAPIStorageClassHandlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIStorageClassHandlers, []string{"PutObject", "HeadObject"})
+}
+
+func testAPIStorageClassHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// register event notifier.
+	if err := initEventNotifier(obj); err != nil {
+		t.Fatal("Notifier initialization failed.")
+	}
+
+	testCases := []struct {
+		objectName           string
+		storageClass         string
+		expectedRespStatus   int
+		expectedStorageClass string
+	}{
+		{"standard", "", http.StatusOK, ""},
+		{"standard-explicit", globalMinioDefaultStorageClass, http.StatusOK, ""},
+		{"rrs", reducedRedundancyStorageClass, http.StatusOK, reducedRedundancyStorageClass},
+		{"glacier", "GLACIER", http.StatusBadRequest, ""},
+	}
+	data := []byte("hello")
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, testCase.objectName),
+			int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		if testCase.storageClass != "" {
+			req.Header.Set(amzStorageClassHeader, testCase.storageClass)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if testCase.expectedRespStatus != http.StatusOK {
+			continue
+		}
+
+		rec = httptest.NewRecorder()
+		req, err = newTestSignedRequestV4("HEAD", getHeadObjectURL("", bucketName, testCase.objectName),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: %s: Expected HeadObject to succeed, got `%d`", i+1, instanceType, rec.Code)
+		}
+		if storageClass := rec.Header().Get(amzStorageClassHeader); storageClass != testCase.expectedStorageClass {
+			t.Errorf("Test %d: %s: Expected storage class `%s`, got `%s`", i+1, instanceType, testCase.expectedStorageClass, storageClass)
+		}
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"errors"
 	"hash/crc32"
 	"path"
@@ -145,6 +146,25 @@ func parseXLFormat(xlMetaBuf []byte) string {
 	return gjson.GetBytes(xlMetaBuf, "format").String()
 }
 
+func parseXLDataDir(xlMetaBuf []byte) string {
+	// Objects written before data directories were introduced have
+	// no dataDir, their parts are directly under the object.
+	return gjson.GetBytes(xlMetaBuf, "dataDir").Str
+}
+
+func parseXLInlineData(xlMetaBuf []byte) (bool, []byte, error) {
+	// Encoded block of objects stored inline, saved as base64 by
+	// encoding/json.
+	if !gjson.GetBytes(xlMetaBuf, "inline").Bool() {
+		return false, nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(gjson.GetBytes(xlMetaBuf, "data").Str)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, data, nil
+}
+
 func parseXLRelease(xlMetaBuf []byte) string {
 	return gjson.GetBytes(xlMetaBuf, "minio.release").String()
 }
@@ -225,6 +245,13 @@ func xlMetaV1UnmarshalJSON(xlMetaBuf []byte) (xlMetaV1, error) {
 
 	// Parse the XL Parts.
 	xlMeta.Parts = parseXLParts(xlMetaBuf)
+	// Parse the data directory of current generation.
+	xlMeta.DataDir = parseXLDataDir(xlMetaBuf)
+	// Parse the data of objects stored inline.
+	xlMeta.Inline, xlMeta.Data, err = parseXLInlineData(xlMetaBuf)
+	if err != nil {
+		return xlMetaV1{}, err
+	}
 	// Get the xlMetaV1.Realse field.
 	xlMeta.Minio.Release = parseXLRelease(xlMetaBuf)
 	// parse xlMetaV1.
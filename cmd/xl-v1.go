@@ -68,6 +68,21 @@ type xlObjects struct {
 
 	// Object cache enabled.
 	objCacheEnabled bool
+
+	// Detects sequential range reads to prefetch into object cache.
+	readAhead *readAheadTracker
+
+	// Disks of buckets pinned to a group of disks, nil if none.
+	placement *xlPlacement
+
+	// Parity of standard and reduced redundancy objects, 0 for the
+	// default parity.
+	standardParity int
+	rrsParity      int
+
+	// Disks of the setup before disks were added to it, nil if
+	// none were added.
+	expansion *xlExpansion
 }
 
 // list of all errors that can be ignored in tree walk operation in XL
@@ -79,6 +94,19 @@ func newXLObjectLayer(storageDisks []StorageAPI) (ObjectLayer, error) {
 	objAPI, err := newXLObjects(storageDisks)
 	fatalIf(err, "Unable to initialize XL object layer.")
 
+	// Pin buckets to their groups of disks.
+	xl := objAPI.(*xlObjects)
+	xl.placement, err = newXLPlacement(serverConfig.GetPlacement(), globalEndpoints, storageDisks, xl.storageDisks)
+	fatalIf(err, "Unable to initialize bucket placement.")
+
+	// Objects written before disks were added stay on the previous
+	// disks until rebalanced.
+	xl.expansion = newXLExpansion(xl.storageDisks)
+
+	// Parity of objects of every storage class.
+	xl.standardParity, xl.rrsParity, err = newXLStorageClass(serverConfig.GetStorageClass(), len(xl.storageDisks))
+	fatalIf(err, "Unable to initialize storage classes.")
+
 	// Initialize and load bucket policies.
 	err = initBucketPolicies(objAPI)
 	fatalIf(err, "Unable to load all bucket policies.")
@@ -119,12 +147,17 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		dataBlocks:   dataBlocks,
 		parityBlocks: parityBlocks,
 		listPool:     listPool,
+		readAhead:    newReadAheadTracker(),
 	}
 
 	// Get cache size if _MINIO_CACHE environment variable is set.
 	var maxCacheSize uint64
 	if !globalXLObjCacheDisabled {
-		maxCacheSize, err = GetMaxCacheSize()
+		if globalObjCacheMemoryPercent > 0 {
+			maxCacheSize, err = getAdaptiveObjCacheSize(0)
+		} else {
+			maxCacheSize, err = GetMaxCacheSize()
+		}
 		errorIf(err, "Unable to get maximum cache size")
 
 		// Enable object cache if cache size is more than zero
@@ -141,7 +174,18 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		objCache.OnEviction = func(key string) {
 			debug.FreeOSMemory()
 		}
+		// Enable disk tier for objects evicted from memory.
+		if globalCacheDiskDir != "" {
+			if oerr = objCache.EnableDiskTier(globalCacheDiskDir, globalCacheDiskMaxSize); oerr != nil {
+				return nil, fmt.Errorf("Unable to initialize cache directory '%s', %s", globalCacheDiskDir, oerr)
+			}
+		}
 		xl.objCache = objCache
+
+		// Resize the cache as memory used by the server changes.
+		if globalObjCacheMemoryPercent > 0 {
+			go resizeObjCache(objCache)
+		}
 	}
 
 	// Initialize meta volume, if volume already exists ignores it.
@@ -238,10 +282,15 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 	// Sort so that the first element is the smallest.
 	validDisksInfo := sortValidDisksInfo(disksInfo)
 	if len(validDisksInfo) == 0 {
-		return StorageInfo{
+		storageInfo := StorageInfo{
 			Total: -1,
 			Free:  -1,
 		}
+		// Report disks even if none of them are online.
+		storageInfo.Backend.Type = Erasure
+		storageInfo.Backend.OnlineDisks = onlineDisks
+		storageInfo.Backend.OfflineDisks = offlineDisks
+		return storageInfo
 	}
 
 	// Return calculated storage info, choose the lowest Total and
@@ -263,5 +312,6 @@ func (xl xlObjects) StorageInfo() StorageInfo {
 	storageInfo := getStorageInfo(xl.storageDisks)
 	storageInfo.Backend.ReadQuorum = xl.readQuorum
 	storageInfo.Backend.WriteQuorum = xl.writeQuorum
+	storageInfo.Backend.Degraded = storageInfo.Backend.OfflineDisks > 0 || globalReadFailovers.isDegraded()
 	return storageInfo
 }
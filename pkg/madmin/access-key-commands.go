@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	accessKeyQueryParam = "access-key"
+)
+
+// AccessKeyStats - requests made with an access key across all
+// servers since they started.
+type AccessKeyStats struct {
+	// Number of requests indexed by S3 API name.
+	Requests map[string]int64 `json:"requests"`
+	// Number of requests which failed with a 4xx or 5xx status.
+	Errors int64 `json:"errors"`
+	// Number of requests indexed by source IP.
+	SourceIPs map[string]int64 `json:"sourceIPs"`
+	FirstSeen time.Time        `json:"firstSeen"`
+	LastSeen  time.Time        `json:"lastSeen"`
+}
+
+// AccessKeyAlert - unusual usage of an access key detected by a
+// server, Type is either "new-network" or "delete-spike".
+type AccessKeyAlert struct {
+	Time      time.Time `json:"time"`
+	Node      string    `json:"node"`
+	AccessKey string    `json:"accessKey"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+}
+
+// AccessKeyUsageInfo - stats of all access keys indexed by access key
+// along with the most recent alerts sorted by time.
+type AccessKeyUsageInfo struct {
+	Keys   map[string]AccessKeyStats `json:"keys"`
+	Alerts []AccessKeyAlert          `json:"alerts"`
+}
+
+// GetAccessKeyUsage - returns requests per API, source IPs and errors
+// of all the access keys along with the most recent usage alerts.
+func (adm *AdminClient) GetAccessKeyUsage() (AccessKeyUsageInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(accessKeyQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "usage")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?access-key to get access key usage.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return AccessKeyUsageInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return AccessKeyUsageInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return AccessKeyUsageInfo{}, err
+	}
+
+	var usage AccessKeyUsageInfo
+	if err = json.Unmarshal(jsonBytes, &usage); err != nil {
+		return AccessKeyUsageInfo{}, err
+	}
+
+	return usage, nil
+}
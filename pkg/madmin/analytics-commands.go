@@ -0,0 +1,103 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	analyticsQueryParam = "analytics"
+)
+
+// AnalyticsStorage - objects selected by an analytics configuration,
+// of the same storage class and age group. Age groups are named after
+// the number of days since objects were last modified, e.g. "030-044"
+// or "365+".
+type AnalyticsStorage struct {
+	ConfigID     string `json:"configId"`
+	StorageClass string `json:"storageClass"`
+	ObjectAge    string `json:"objectAge"`
+	ObjectCount  int64  `json:"objectCount"`
+	Size         int64  `json:"size"`
+}
+
+// AnalyticsUsage - reads of objects selected by an analytics
+// configuration, of the same storage class and age group, on a day
+// formatted as "2006-01-02" in UTC.
+type AnalyticsUsage struct {
+	ConfigID       string `json:"configId"`
+	Date           string `json:"date"`
+	StorageClass   string `json:"storageClass"`
+	ObjectAge      string `json:"objectAge"`
+	GetRequests    uint64 `json:"getRequests"`
+	BytesRetrieved uint64 `json:"bytesRetrieved"`
+}
+
+// BucketAnalyticsReport - objects stored at Time and reads counted by
+// all servers for the last 31 days, per analytics configuration.
+type BucketAnalyticsReport struct {
+	Bucket  string             `json:"bucket"`
+	Time    time.Time          `json:"time"`
+	Storage []AnalyticsStorage `json:"storage"`
+	Usage   []AnalyticsUsage   `json:"usage"`
+}
+
+// GetBucketAnalytics - returns objects stored per storage class and
+// age group, and reads per day, for each analytics configuration of
+// bucket.
+func (adm *AdminClient) GetBucketAnalytics(bucket string) (BucketAnalyticsReport, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(analyticsQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "report")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?analytics&bucket=bucket to get the report.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketAnalyticsReport{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketAnalyticsReport{}, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketAnalyticsReport{}, err
+	}
+
+	var report BucketAnalyticsReport
+	if err = json.Unmarshal(jsonBytes, &report); err != nil {
+		return BucketAnalyticsReport{}, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	bucketCloneQueryParam = "bucket-clone"
+)
+
+// Methods of cloning a bucket.
+const (
+	// BucketCloneMethodLink - object data is shared with the source
+	// bucket until either object is overwritten.
+	BucketCloneMethodLink = "link"
+	// BucketCloneMethodCopy - object data is copied.
+	BucketCloneMethodCopy = "copy"
+)
+
+// States of cloning a bucket.
+const (
+	BucketCloneRunning = "running"
+	BucketCloneDone    = "done"
+)
+
+// BucketCloneStatus - progress of cloning a bucket.
+type BucketCloneStatus struct {
+	Source    string     `json:"source"`
+	Target    string     `json:"target"`
+	Method    string     `json:"method"`
+	State     string     `json:"state"`
+	Objects   int64      `json:"objects"`
+	Size      int64      `json:"size"`
+	Failed    int64      `json:"failed"`
+	LastError string     `json:"lastError,omitempty"`
+	Started   time.Time  `json:"started"`
+	Finished  *time.Time `json:"finished,omitempty"`
+}
+
+// StartBucketClone - creates bucket and clones objects of source
+// bucket into it in background, returns the initial progress.
+func (adm *AdminClient) StartBucketClone(bucket, source string) (BucketCloneStatus, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(bucketCloneQueryParam, "")
+	queryVal.Set("bucket", bucket)
+	queryVal.Set("source", source)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "start")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?bucket-clone to start cloning a bucket.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketCloneStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketCloneStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	return decodeBucketCloneStatus(resp)
+}
+
+// GetBucketCloneStatus - returns progress of cloning into bucket,
+// clones are tracked by the server they were started on.
+func (adm *AdminClient) GetBucketCloneStatus(bucket string) (BucketCloneStatus, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(bucketCloneQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "status")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?bucket-clone to get progress of cloning a bucket.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketCloneStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketCloneStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	return decodeBucketCloneStatus(resp)
+}
+
+// decodeBucketCloneStatus - decodes progress of cloning a bucket from
+// response body.
+func decodeBucketCloneStatus(resp *http.Response) (BucketCloneStatus, error) {
+	var status BucketCloneStatus
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketCloneStatus{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &status); err != nil {
+		return BucketCloneStatus{}, err
+	}
+
+	return status, nil
+}
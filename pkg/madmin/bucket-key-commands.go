@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	bucketKeyQueryParam = "bucket-key"
+)
+
+// BucketKeyInfo - key of a bucket, derived from the KMS master key of
+// the server.
+type BucketKeyInfo struct {
+	// ID of the KMS master key the bucket key is derived from.
+	KeyID string `json:"keyId"`
+	// Current version of the bucket key, incremented on rotation.
+	Version int `json:"version"`
+	// Time the current version was created.
+	Rotated time.Time `json:"rotated"`
+}
+
+// bucketKeyOp - executes a bucket key operation and returns the
+// resulting key info.
+func (adm *AdminClient) bucketKeyOp(method, op, bucket string) (BucketKeyInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(bucketKeyQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, op)
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	resp, err := adm.executeMethod(method, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketKeyInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketKeyInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	var info BucketKeyInfo
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketKeyInfo{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &info); err != nil {
+		return BucketKeyInfo{}, err
+	}
+
+	return info, nil
+}
+
+// GetBucketKey - returns key info of a bucket, version 1 if the
+// bucket key was never rotated.
+func (adm *AdminClient) GetBucketKey(bucket string) (BucketKeyInfo, error) {
+	// Execute GET on /?bucket-key to get key info of a bucket.
+	return adm.bucketKeyOp("GET", "get", bucket)
+}
+
+// RotateBucketKey - moves the key of a bucket to its next version.
+func (adm *AdminClient) RotateBucketKey(bucket string) (BucketKeyInfo, error) {
+	// Execute POST on /?bucket-key to rotate the key of a bucket.
+	return adm.bucketKeyOp("POST", "rotate", bucket)
+}
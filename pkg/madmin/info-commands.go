@@ -55,6 +55,10 @@ type StorageInfo struct {
 		OfflineDisks int // Offline disks during server startup.
 		ReadQuorum   int // Minimum disks required for successful read operations.
 		WriteQuorum  int // Minimum disks required for successful write operations.
+
+		// Set when disks are offline or reads recently failed on
+		// some disks, objects are then read from the remaining disks.
+		Degraded bool
 	}
 }
 
@@ -66,6 +70,28 @@ type ServerProperties struct {
 	CommitID string        `json:"commitID"`
 	Region   string        `json:"region"`
 	SQSARN   []string      `json:"sqsARN"`
+	Mode     string        `json:"mode"`
+
+	// Disks missing since startup while writes are rejected.
+	MissingDisks []string `json:"missingDisks,omitempty"`
+}
+
+// ServerCertInfo holds expiry information of a certificate
+// served by the server
+type ServerCertInfo struct {
+	Subject      string    `json:"subject"`
+	NotAfter     time.Time `json:"notAfter"`
+	DaysToExpiry int64     `json:"daysToExpiry"`
+	Expiring     bool      `json:"expiring"`
+}
+
+// ServerMultipartCleanupStats holds statistics of stale
+// multipart uploads cleanup since server start
+type ServerMultipartCleanupStats struct {
+	LastRun time.Time `json:"lastRun"`
+	Aborted int64     `json:"aborted"`
+	DryRun  int64     `json:"dryRun"`
+	Failed  int64     `json:"failed"`
 }
 
 // ServerConnStats holds network information
@@ -80,6 +106,9 @@ type ServerInfoData struct {
 	StorageInfo StorageInfo      `json:"storage"`
 	ConnStats   ServerConnStats  `json:"network"`
 	Properties  ServerProperties `json:"server"`
+	CertsInfo   []ServerCertInfo `json:"certs,omitempty"`
+
+	MultipartCleanup ServerMultipartCleanupStats `json:"multipartCleanup"`
 }
 
 // ServerInfo holds server information result of one node
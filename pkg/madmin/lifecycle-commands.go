@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	lifecycleQueryParam = "lifecycle"
+)
+
+// LifecycleDryRunRule - number and size of objects a lifecycle rule
+// would act on.
+type LifecycleDryRunRule struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Prefix  string `json:"prefix"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// LifecycleDryRunObject - an object a lifecycle rule would
+// transition.
+type LifecycleDryRunObject struct {
+	Rule         string    `json:"rule"`
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"modTime"`
+	StorageClass string    `json:"storageClass"`
+}
+
+// LifecycleDryRunResult - objects of a bucket lifecycle rules would
+// act on at Time.
+type LifecycleDryRunResult struct {
+	Bucket      string                  `json:"bucket"`
+	Time        time.Time               `json:"time"`
+	Rules       []LifecycleDryRunRule   `json:"rules"`
+	Transitions []LifecycleDryRunObject `json:"transitions"`
+	IsTruncated bool                    `json:"isTruncated"`
+}
+
+// LifecycleDryRun - reports objects of a bucket which lifecycle rules
+// would transition now, without transitioning them. Rules are read
+// from lifecycleXML, in the format of S3 PutBucketLifecycle, or from
+// the lifecycle of the bucket when it is empty.
+func (adm *AdminClient) LifecycleDryRun(bucket string, lifecycleXML []byte) (LifecycleDryRunResult, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(lifecycleQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "dry-run")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+	if len(lifecycleXML) > 0 {
+		reqData.contentBody = bytes.NewReader(lifecycleXML)
+		reqData.contentLength = int64(len(lifecycleXML))
+		reqData.contentMD5Bytes = sumMD5(lifecycleXML)
+		reqData.contentSHA256Bytes = sum256(lifecycleXML)
+	}
+
+	// Execute POST on /?lifecycle to evaluate lifecycle of a bucket.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return LifecycleDryRunResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return LifecycleDryRunResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result LifecycleDryRunResult
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return LifecycleDryRunResult{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return LifecycleDryRunResult{}, err
+	}
+
+	return result, nil
+}
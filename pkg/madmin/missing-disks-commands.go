@@ -0,0 +1,69 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	missingDisksQueryParam = "missing-disks"
+)
+
+// ConfirmMissingDisks - accepts write operations on all servers which
+// started with formatted disks missing, returns the disks which were
+// missing indexed by server. Confirm only after making sure no other
+// servers are serving the same setup without these disks.
+func (adm *AdminClient) ConfirmMissingDisks() (map[string][]string, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(missingDisksQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "confirm")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?missing-disks to confirm the start.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing map[string][]string
+	if err = json.Unmarshal(jsonBytes, &missing); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
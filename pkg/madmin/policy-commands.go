@@ -0,0 +1,91 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	policyQueryParam = "policy"
+)
+
+// PolicyValidationError - error found in a bucket policy. Line and
+// Column, starting at 1, point to the syntax error or to the start of
+// the statement the error is found in, Statement is the index of the
+// statement starting at 1, 0 when the error is not specific to one.
+type PolicyValidationError struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Statement int    `json:"statement,omitempty"`
+	Message   string `json:"message"`
+}
+
+// PolicyValidationResult - result of the validation of a bucket
+// policy.
+type PolicyValidationResult struct {
+	Valid  bool                    `json:"valid"`
+	Errors []PolicyValidationError `json:"errors,omitempty"`
+}
+
+// ValidateBucketPolicy - validates the bucket policy document against
+// the grammar and maximum size supported by the server, without
+// setting it on any bucket.
+func (adm *AdminClient) ValidateBucketPolicy(policy []byte) (PolicyValidationResult, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(policyQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "validate")
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(policy),
+		contentLength:      int64(len(policy)),
+		contentMD5Bytes:    sumMD5(policy),
+		contentSHA256Bytes: sum256(policy),
+	}
+
+	// Execute POST on /?policy to validate the policy.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return PolicyValidationResult{}, httpRespToErrorResponse(resp)
+	}
+
+	var result PolicyValidationResult
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return PolicyValidationResult{}, err
+	}
+
+	return result, nil
+}
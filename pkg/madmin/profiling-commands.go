@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	profileQueryParam = "profile"
+)
+
+// Types of profiles.
+const (
+	ProfileCPU       = "cpu"
+	ProfileMem       = "mem"
+	ProfileBlock     = "block"
+	ProfileMutex     = "mutex"
+	ProfileGoroutine = "goroutine"
+	ProfileTrace     = "trace"
+)
+
+// StartProfiling - starts recording a profile of profileType on the
+// server, one profile can be recorded at a time.
+func (adm *AdminClient) StartProfiling(profileType string) error {
+	queryVal := make(url.Values)
+	queryVal.Set(profileQueryParam, "")
+	queryVal.Set("type", profileType)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "start")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?profile to start recording a profile.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// DownloadProfilingData - stops recording the current profile and
+// returns it in pprof format, or execution trace format for trace
+// profiles. Caller must close the returned reader.
+func (adm *AdminClient) DownloadProfilingData() (io.ReadCloser, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(profileQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "stop")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute POST on /?profile to stop recording and download the
+	// profile.
+	resp, err := adm.executeMethod("POST", reqData)
+	if err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer closeResponse(resp)
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	quotaQueryParam = "quota"
+)
+
+// QuotaLimit - limits on requests signed with an access key, a limit
+// with zero value is disabled.
+type QuotaLimit struct {
+	RequestsPerSec int64 `json:"requestsPerSec"`
+	BytesPerDay    int64 `json:"bytesPerDay"`
+}
+
+// QuotaUsage - requests made in the current second and bytes
+// transferred in the current day (UTC) across all servers.
+type QuotaUsage struct {
+	Requests int64 `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// QuotaUsageInfo - quota limits of an access key along with its usage.
+type QuotaUsageInfo struct {
+	Limit QuotaLimit `json:"limit"`
+	Usage QuotaUsage `json:"usage"`
+}
+
+// GetQuotaUsage - returns quota limits and current usage of all the
+// access keys with a quota, indexed by access key.
+func (adm *AdminClient) GetQuotaUsage() (map[string]QuotaUsageInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(quotaQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "usage")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?quota to get quota usage.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsage := make(map[string]QuotaUsageInfo)
+	if err = json.Unmarshal(jsonBytes, &quotaUsage); err != nil {
+		return nil, err
+	}
+
+	return quotaUsage, nil
+}
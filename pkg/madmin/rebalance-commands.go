@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	rebalanceQueryParam = "rebalance"
+)
+
+// States of rebalancing objects.
+const (
+	RebalanceRunning = "running"
+	RebalanceDone    = "done"
+)
+
+// RebalanceStatus - progress of rebalancing objects across disks added
+// to an XL setup.
+type RebalanceStatus struct {
+	State          string     `json:"state"`
+	PreviousDisks  int        `json:"previousDisks"`
+	Disks          int        `json:"disks"`
+	Passes         int        `json:"passes"`
+	Objects        int64      `json:"objects"`
+	Size           int64      `json:"size"`
+	Failed         int64      `json:"failed"`
+	LastError      string     `json:"lastError,omitempty"`
+	PendingUploads int64      `json:"pendingUploads"`
+	MaxBandwidth   int64      `json:"maxBandwidth"`
+	MaxObjects     int64      `json:"maxObjects"`
+	Started        time.Time  `json:"started"`
+	Finished       *time.Time `json:"finished,omitempty"`
+}
+
+// GetRebalanceStatus - returns progress of rebalancing objects across
+// disks added to the setup, reported by the first server of the setup.
+func (adm *AdminClient) GetRebalanceStatus() (RebalanceStatus, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(rebalanceQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "status")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?rebalance to get progress of rebalancing.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return RebalanceStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return RebalanceStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	var status RebalanceStatus
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return RebalanceStatus{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &status); err != nil {
+		return RebalanceStatus{}, err
+	}
+
+	return status, nil
+}
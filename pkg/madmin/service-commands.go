@@ -25,9 +25,43 @@ import (
 	"time"
 )
 
+// ServerVersion - server version
+type ServerVersion struct {
+	Version  string `json:"version"`
+	CommitID string `json:"commitID"`
+}
+
+// NodeVersion - version of one server of the setup, Error is set when
+// the server could not be reached.
+type NodeVersion struct {
+	Addr     string `json:"addr"`
+	Version  string `json:"version,omitempty"`
+	CommitID string `json:"commitID,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// APILatencyBucket - number of requests served within the bucket
+// bound and above the bound of the previous bucket, the bound of
+// the last bucket is "+Inf".
+type APILatencyBucket struct {
+	LE    string `json:"le"`
+	Count uint64 `json:"count"`
+}
+
+// APIStats - latency histogram and in-flight count of an S3 API.
+type APIStats struct {
+	InFlight    int64              `json:"inFlight"`
+	Count       uint64             `json:"count"`
+	AvgDuration string             `json:"avgDuration"`
+	Histogram   []APILatencyBucket `json:"histogram"`
+}
+
 // ServiceStatusMetadata - contains the response of service status API
 type ServiceStatusMetadata struct {
-	Uptime time.Duration `json:"uptime"`
+	ServerVersion ServerVersion       `json:"serverVersion"`
+	Uptime        time.Duration       `json:"uptime"`
+	Versions      []NodeVersion       `json:"versions"`
+	APIStats      map[string]APIStats `json:"apiStats"`
 }
 
 // ServiceStatus - Connect to a minio server and call Service Status Management API
@@ -69,6 +103,27 @@ func (adm *AdminClient) ServiceStatus() (ServiceStatusMetadata, error) {
 	return serviceStatus, nil
 }
 
+// ServiceStop - Call Service Stop API to shut down a specified Minio server
+func (adm *AdminClient) ServiceStop() error {
+	reqData := requestData{}
+	reqData.queryValues = make(url.Values)
+	reqData.queryValues.Set("service", "")
+	reqData.customHeaders = make(http.Header)
+	reqData.customHeaders.Set(minioAdminOpHeader, "stop")
+
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
 // ServiceRestart - Call Service Restart API to restart a specified Minio server
 func (adm *AdminClient) ServiceRestart() error {
 	//
@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	signatureQueryParam = "signature"
+)
+
+// SignatureDebugInfo - components of the signature V4 the server
+// computes for a request, to compare with the ones of the client.
+type SignatureDebugInfo struct {
+	AccessKey        string        `json:"accessKey,omitempty"`
+	Region           string        `json:"region,omitempty"`
+	RequestTime      time.Time     `json:"requestTime"`
+	ServerTime       time.Time     `json:"serverTime"`
+	ClockSkew        time.Duration `json:"clockSkew"`
+	SignedHeaders    string        `json:"signedHeaders,omitempty"`
+	CanonicalRequest string        `json:"canonicalRequest,omitempty"`
+	StringToSign     string        `json:"stringToSign,omitempty"`
+	SignatureMatch   bool          `json:"signatureMatch"`
+	Error            string        `json:"error,omitempty"`
+}
+
+// DebugSignature - returns the canonical request and string to sign
+// the server computes for requestDump, the request line and headers
+// of a request signed with AWS signature V4 as printed by clients in
+// debug mode, and whether its signature matches.
+func (adm *AdminClient) DebugSignature(requestDump []byte) (SignatureDebugInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(signatureQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "debug")
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(requestDump),
+		contentLength:      int64(len(requestDump)),
+		contentMD5Bytes:    sumMD5(requestDump),
+		contentSHA256Bytes: sum256(requestDump),
+	}
+
+	// Execute POST on /?signature to debug the signature of a request.
+	resp, err := adm.executeMethod("POST", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return SignatureDebugInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return SignatureDebugInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	var info SignatureDebugInfo
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return SignatureDebugInfo{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &info); err != nil {
+		return SignatureDebugInfo{}, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	slowRequestQueryParam = "slow-request"
+)
+
+// LockWaitInfo - time a request waited on a namespace lock, along
+// with the locks held by other operations when it started waiting.
+type LockWaitInfo struct {
+	Bucket   string        `json:"bucket"`
+	Object   string        `json:"object"`
+	LockType string        `json:"lockType"`
+	Waited   time.Duration `json:"waited"`
+	HeldBy   []string      `json:"heldBy,omitempty"`
+}
+
+// SlowRequestInfo - a request which took longer than the slow request
+// threshold of the server, along with its namespace lock waits.
+type SlowRequestInfo struct {
+	RequestID string         `json:"requestID"`
+	Method    string         `json:"method"`
+	Path      string         `json:"path"`
+	Started   time.Time      `json:"started"`
+	Duration  time.Duration  `json:"duration"`
+	LockWait  time.Duration  `json:"lockWait"`
+	LockWaits []LockWaitInfo `json:"lockWaits,omitempty"`
+}
+
+// ListSlowRequests - returns the most recent slow requests served by
+// the server, oldest first.
+func (adm *AdminClient) ListSlowRequests() ([]SlowRequestInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(slowRequestQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "list")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?slow-request to list slow requests.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var slowRequests []SlowRequestInfo
+	if err = json.Unmarshal(jsonBytes, &slowRequests); err != nil {
+		return nil, err
+	}
+
+	return slowRequests, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	usageAlertQueryParam = "usage-alert"
+)
+
+// BucketUsage - total size and number of objects in a bucket.
+type BucketUsage struct {
+	Size    int64 `json:"size"`
+	Objects int64 `json:"objects"`
+}
+
+// BucketUsageAlert - usage thresholds of a bucket, a threshold with
+// zero value is disabled.
+type BucketUsageAlert struct {
+	MaxSize    int64 `json:"maxSize"`
+	MaxObjects int64 `json:"maxObjects"`
+}
+
+// BucketUsageAlertInfo - usage thresholds of a bucket along with
+// its current usage.
+type BucketUsageAlertInfo struct {
+	BucketUsageAlert
+	Usage BucketUsage `json:"usage"`
+}
+
+// GetBucketUsageAlert - returns the usage alert thresholds and the
+// current usage of a bucket.
+func (adm *AdminClient) GetBucketUsageAlert(bucket string) (BucketUsageAlertInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(usageAlertQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "get")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?usage-alert to get usage alert of a bucket.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketUsageAlertInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketUsageAlertInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	var info BucketUsageAlertInfo
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketUsageAlertInfo{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &info); err != nil {
+		return BucketUsageAlertInfo{}, err
+	}
+
+	return info, nil
+}
+
+// SetBucketUsageAlert - sets usage alert thresholds of a bucket.
+func (adm *AdminClient) SetBucketUsageAlert(bucket string, alert BucketUsageAlert) error {
+	queryVal := make(url.Values)
+	queryVal.Set(usageAlertQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	alertBytes, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(alertBytes),
+		contentMD5Bytes:    sumMD5(alertBytes),
+		contentSHA256Bytes: sum256(alertBytes),
+	}
+
+	// Execute PUT on /?usage-alert to set usage alert of a bucket.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	workerPoolQueryParam = "worker-pool"
+)
+
+// WorkerPoolStatus - size and saturation of the worker pool of a
+// background subsystem.
+type WorkerPoolStatus struct {
+	Workers   int    `json:"workers"`
+	Busy      int64  `json:"busy"`
+	QueueSize int    `json:"queueSize"`
+	Queued    int    `json:"queued"`
+	Completed uint64 `json:"completed"`
+	Rejected  uint64 `json:"rejected"`
+}
+
+// WorkerPoolConfig - size of the worker pool of a background
+// subsystem, zero values keep the current size.
+type WorkerPoolConfig struct {
+	Workers   int `json:"workers"`
+	QueueSize int `json:"queueSize"`
+}
+
+// WorkerPoolsConfig - sizes of the worker pools of background
+// subsystems.
+type WorkerPoolsConfig struct {
+	Notification WorkerPoolConfig `json:"notification"`
+	Heal         WorkerPoolConfig `json:"heal"`
+	Lifecycle    WorkerPoolConfig `json:"lifecycle"`
+}
+
+// WorkerPoolsStatus - returns size and saturation of the worker pools
+// of the server by name.
+func (adm *AdminClient) WorkerPoolsStatus() (map[string]WorkerPoolStatus, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(workerPoolQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "status")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?worker-pool to get worker pools status.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var status map[string]WorkerPoolStatus
+	if err = json.Unmarshal(jsonBytes, &status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// SetWorkerPools - resizes the worker pools of the server until it
+// restarts.
+func (adm *AdminClient) SetWorkerPools(cfg WorkerPoolsConfig) error {
+	queryVal := make(url.Values)
+	queryVal.Set(workerPoolQueryParam, "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "set")
+
+	cfgBytes, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	reqData := requestData{
+		queryValues:        queryVal,
+		customHeaders:      hdrs,
+		contentBody:        bytes.NewReader(cfgBytes),
+		contentMD5Bytes:    sumMD5(cfgBytes),
+		contentSHA256Bytes: sum256(cfgBytes),
+	}
+
+	// Execute PUT on /?worker-pool to resize worker pools.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	wormQueryParam = "worm"
+)
+
+// BucketWormInfo - WORM mode of a bucket, objects of a bucket in WORM
+// mode can neither be overwritten nor deleted.
+type BucketWormInfo struct {
+	// WORM mode is enabled for this bucket.
+	Enabled bool `json:"enabled"`
+	// WORM mode is enabled for all buckets in server config.
+	ServerWide bool `json:"serverWide"`
+}
+
+// GetBucketWorm - returns WORM mode of a bucket.
+func (adm *AdminClient) GetBucketWorm(bucket string) (BucketWormInfo, error) {
+	queryVal := make(url.Values)
+	queryVal.Set(wormQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "get")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute GET on /?worm to get WORM mode of a bucket.
+	resp, err := adm.executeMethod("GET", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketWormInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BucketWormInfo{}, httpRespToErrorResponse(resp)
+	}
+
+	var info BucketWormInfo
+	jsonBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BucketWormInfo{}, err
+	}
+
+	if err = json.Unmarshal(jsonBytes, &info); err != nil {
+		return BucketWormInfo{}, err
+	}
+
+	return info, nil
+}
+
+// EnableBucketWorm - enables WORM mode of a bucket, it can't be
+// disabled afterwards.
+func (adm *AdminClient) EnableBucketWorm(bucket string) error {
+	queryVal := make(url.Values)
+	queryVal.Set(wormQueryParam, "")
+	queryVal.Set("bucket", bucket)
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, "enable")
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+
+	// Execute PUT on /?worm to enable WORM mode of a bucket.
+	resp, err := adm.executeMethod("PUT", reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
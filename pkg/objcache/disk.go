@@ -0,0 +1,292 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package objcache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// diskEntrySuffix - suffix of entry files saved on disk tier.
+	diskEntrySuffix = ".cache"
+
+	// diskEntryTmpPrefix - prefix of entry files being written, such
+	// files left behind by a crash are purged when disk tier is loaded.
+	diskEntryTmpPrefix = "tmp-"
+
+	// diskEntryHeaderLen - size of the header length preceding the
+	// header of an entry file.
+	diskEntryHeaderLen = 4
+)
+
+// errInvalidDiskEntry - entry file on disk tier is corrupted.
+var errInvalidDiskEntry = errors.New("invalid disk cache entry")
+
+// diskEntryHeader - header saved ahead of the value in every entry file.
+// Entry files are written to a temporary file and renamed, hence the
+// index of disk tier can always be rebuilt from headers after a crash.
+type diskEntryHeader struct {
+	Key          string    `json:"key"`
+	LastAccessed time.Time `json:"lastAccessed"`
+}
+
+// diskEntry - represents an entry saved on disk tier.
+type diskEntry struct {
+	size         uint64    // Size of the value.
+	lastAccessed time.Time // Represents time when value was last accessed in memory.
+}
+
+// diskTier holds the entries evicted from memory on a local
+// directory, entries are promoted back to memory when accessed.
+type diskTier struct {
+	// Mutex is used for handling the concurrent
+	// read/write requests for disk tier.
+	mutex sync.Mutex
+
+	// dir is the directory entry files are saved in.
+	dir string
+
+	// maxSize is a total size for all entries on disk.
+	maxSize uint64
+
+	// currentSize is a current size of all entries on disk.
+	currentSize uint64
+
+	// map of objectName and its entry on disk.
+	entries map[string]*diskEntry
+}
+
+// newDiskTier - returns a new disk tier saving entries in dir, entries
+// already saved in dir are loaded.
+func newDiskTier(dir string, maxSize uint64) (*diskTier, error) {
+	if dir == "" || maxSize == 0 {
+		return nil, errors.New("invalid disk cache directory or size")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	d := &diskTier{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[string]*diskEntry),
+	}
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// getEntryPath - returns path of the entry file of a key.
+func (d *diskTier) getEntryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskEntrySuffix)
+}
+
+// load - rebuilds the index from all entry files, left over temporary
+// and corrupted entry files are purged.
+func (d *diskTier) load() error {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, diskEntryTmpPrefix) {
+			os.Remove(filepath.Join(d.dir, name))
+			continue
+		}
+		if !file.Mode().IsRegular() || !strings.HasSuffix(name, diskEntrySuffix) {
+			continue
+		}
+
+		entryPath := filepath.Join(d.dir, name)
+		header, headerSize, err := readDiskEntryHeader(entryPath)
+		if err != nil || d.getEntryPath(header.Key) != entryPath || file.Size() < headerSize {
+			os.Remove(entryPath)
+			continue
+		}
+
+		size := uint64(file.Size() - headerSize)
+		d.entries[header.Key] = &diskEntry{
+			size:         size,
+			lastAccessed: header.LastAccessed,
+		}
+		d.currentSize += size
+	}
+
+	// Maximum size could have been lowered since the entries were saved.
+	for d.currentSize > d.maxSize {
+		d.evictOldest()
+	}
+	return nil
+}
+
+// readDiskEntryHeader - reads the header of an entry file, returns the
+// header along with the number of bytes preceding the value.
+func readDiskEntryHeader(entryPath string) (header diskEntryHeader, headerSize int64, err error) {
+	f, err := os.Open(entryPath)
+	if err != nil {
+		return header, 0, err
+	}
+	defer f.Close()
+
+	headerLen := make([]byte, diskEntryHeaderLen)
+	if _, err = io.ReadFull(f, headerLen); err != nil {
+		return header, 0, errInvalidDiskEntry
+	}
+	headerBytes := make([]byte, binary.BigEndian.Uint32(headerLen))
+	if _, err = io.ReadFull(f, headerBytes); err != nil {
+		return header, 0, errInvalidDiskEntry
+	}
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return header, 0, errInvalidDiskEntry
+	}
+	return header, int64(diskEntryHeaderLen + len(headerBytes)), nil
+}
+
+// put - saves the value of a key on disk, evicting least recently
+// accessed entries if needed. Returns ErrCacheFull if the value is
+// larger than the disk tier.
+func (d *diskTier) put(key string, value []byte, lastAccessed time.Time) error {
+	valueLen := uint64(len(value))
+	if valueLen > d.maxSize {
+		return ErrCacheFull
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.remove(key)
+	for d.currentSize+valueLen > d.maxSize {
+		d.evictOldest()
+	}
+
+	headerBytes, err := json.Marshal(diskEntryHeader{Key: key, LastAccessed: lastAccessed})
+	if err != nil {
+		return err
+	}
+	headerLen := make([]byte, diskEntryHeaderLen)
+	binary.BigEndian.PutUint32(headerLen, uint32(len(headerBytes)))
+
+	// Write to a temporary file and rename, so that entry files
+	// are always complete.
+	tmpFile, err := ioutil.TempFile(d.dir, diskEntryTmpPrefix)
+	if err != nil {
+		return err
+	}
+	for _, b := range [][]byte{headerLen, headerBytes, value} {
+		if _, err = tmpFile.Write(b); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if cerr := tmpFile.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), d.getEntryPath(key))
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+
+	d.entries[key] = &diskEntry{
+		size:         valueLen,
+		lastAccessed: lastAccessed,
+	}
+	d.currentSize += valueLen
+	return nil
+}
+
+// get - reads the value of a key saved on disk along with the time it
+// was last accessed. Returns ErrKeyNotFoundInCache if the key does not
+// exist or its entry file is no longer readable.
+func (d *diskTier) get(key string) (value []byte, lastAccessed time.Time, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	entry, ok := d.entries[key]
+	if !ok {
+		return nil, lastAccessed, ErrKeyNotFoundInCache
+	}
+
+	data, err := ioutil.ReadFile(d.getEntryPath(key))
+	if err != nil || len(data) < diskEntryHeaderLen {
+		d.remove(key)
+		return nil, lastAccessed, ErrKeyNotFoundInCache
+	}
+	headerLen := uint64(binary.BigEndian.Uint32(data[:diskEntryHeaderLen]))
+	if uint64(len(data)) != diskEntryHeaderLen+headerLen+entry.size {
+		d.remove(key)
+		return nil, lastAccessed, ErrKeyNotFoundInCache
+	}
+
+	return data[diskEntryHeaderLen+headerLen:], entry.lastAccessed, nil
+}
+
+// delete - deletes the entry of a key from disk.
+func (d *diskTier) delete(key string) {
+	d.mutex.Lock()
+	d.remove(key)
+	d.mutex.Unlock()
+}
+
+// Deletes the entry of a key from disk, must be called with mutex held.
+func (d *diskTier) remove(key string) {
+	entry, ok := d.entries[key]
+	if !ok {
+		return
+	}
+	os.Remove(d.getEntryPath(key))
+	delete(d.entries, key)
+	d.currentSize -= entry.size
+}
+
+// Evicts the least recently accessed entry from disk, must be called
+// with mutex held.
+func (d *diskTier) evictOldest() {
+	var oldestKey string
+	var oldest *diskEntry
+	for key, entry := range d.entries {
+		if oldest == nil || entry.lastAccessed.Before(oldest.lastAccessed) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if oldest == nil {
+		// Nothing to evict, should never happen.
+		d.currentSize = 0
+		return
+	}
+	d.remove(oldestKey)
+}
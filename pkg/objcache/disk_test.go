@@ -0,0 +1,282 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package objcache
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests disk tier index is rebuilt from entry files.
+func TestDiskTierLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objcache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := newDiskTier(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		if err = d.put(fmt.Sprintf("bucket/object%d", i), []byte("hello"), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Temporary file left behind by a crash.
+	tmpPath := filepath.Join(dir, diskEntryTmpPrefix+"123")
+	if err = ioutil.WriteFile(tmpPath, []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Corrupted entry file.
+	corruptedPath := d.getEntryPath("bucket/object1")
+	if err = ioutil.WriteFile(corruptedPath, []byte("corrupted"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Entry file saved under a different name.
+	if err = os.Rename(d.getEntryPath("bucket/object2"), d.getEntryPath("bucket/other")); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = newDiskTier(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.entries) != 1 || d.currentSize != 5 {
+		t.Fatalf("Expected 1 entry of 5 bytes, found %d entries of %d bytes", len(d.entries), d.currentSize)
+	}
+	value, lastAccessed, err := d.get("bucket/object0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, []byte("hello")) || !lastAccessed.Equal(now) {
+		t.Fatalf("Unexpected entry %q last accessed at %s", value, lastAccessed)
+	}
+	for _, path := range []string{tmpPath, corruptedPath, d.getEntryPath("bucket/other")} {
+		if _, err = os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s to be purged, got %v", path, err)
+		}
+	}
+
+	// Maximum size lowered, entries beyond it are evicted.
+	if d, err = newDiskTier(dir, 4); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.entries) != 0 || d.currentSize != 0 {
+		t.Fatalf("Expected no entries, found %d entries of %d bytes", len(d.entries), d.currentSize)
+	}
+}
+
+// Tests least recently accessed entries are evicted from disk tier.
+func TestDiskTierEviction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objcache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := newDiskTier(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	testCases := []struct {
+		key          string
+		value        []byte
+		lastAccessed time.Time
+		expectedErr  error
+		evicted      []string
+	}{
+		{"a", []byte("1234"), now.Add(-time.Hour), nil, nil},
+		{"b", []byte("1234"), now.Add(-2 * time.Hour), nil, nil},
+		// Least recently accessed "b" is evicted.
+		{"c", []byte("1234"), now, nil, []string{"b"}},
+		// Larger than the disk tier.
+		{"d", []byte("12345678901"), now, ErrCacheFull, nil},
+		// Replacing an entry evicts nothing else.
+		{"c", []byte("123456"), now, nil, nil},
+		// Both remaining entries are evicted.
+		{"e", []byte("1234567890"), now, nil, []string{"a", "c"}},
+	}
+	for i, testCase := range testCases {
+		err = d.put(testCase.key, testCase.value, testCase.lastAccessed)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		for _, key := range testCase.evicted {
+			if _, _, err = d.get(key); err != ErrKeyNotFoundInCache {
+				t.Fatalf("Test %d: expected %s to be evicted, got %v", i+1, key, err)
+			}
+		}
+		if d.currentSize > d.maxSize {
+			t.Fatalf("Test %d: current size %d exceeds %d", i+1, d.currentSize, d.maxSize)
+		}
+	}
+}
+
+// Tests entries are demoted to and promoted from disk tier.
+func TestCacheDiskTier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objcache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Cache holds 10 entries of 10 bytes each.
+	cache, err := New(100, NoExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(key string) error {
+		w, err := cache.Create(key, 10)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write([]byte(fmt.Sprintf("%-10s", key))); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+	for i := 0; i < 10; i++ {
+		if err = create(fmt.Sprintf("key%d", i)); err != nil {
+			t.Fatal(err)
+		}
+		// Keep access times apart.
+		cache.entries[fmt.Sprintf("key%d", i)].lastAccessed = time.Now().UTC().Add(time.Duration(i-10) * time.Minute)
+	}
+
+	// Cache is full without disk tier.
+	if err = create("key10"); err != ErrCacheFull {
+		t.Fatalf("Expected ErrCacheFull, got %v", err)
+	}
+
+	if err = cache.EnableDiskTier(dir, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// Least recently accessed entry is demoted to disk.
+	if err = create("key10"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.entries["key0"]; ok {
+		t.Fatal("Expected key0 to be demoted")
+	}
+	if _, ok := cache.disk.entries["key0"]; !ok {
+		t.Fatal("Expected key0 to be on disk")
+	}
+
+	// Accessing demoted entry promotes it back to memory.
+	r, err := cache.Open("key0", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 10)
+	if _, err = r.ReadAt(buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != fmt.Sprintf("%-10s", "key0") {
+		t.Fatalf("Unexpected value %q", buf)
+	}
+	if _, ok := cache.entries["key0"]; !ok {
+		t.Fatal("Expected key0 to be promoted")
+	}
+	if _, ok := cache.disk.entries["key0"]; ok {
+		t.Fatal("Expected key0 to be removed from disk")
+	}
+	if cache.currentSize != 100 {
+		t.Fatalf("Expected current size 100, got %d", cache.currentSize)
+	}
+
+	// key1 was demoted to make room, stale entry on disk is purged.
+	if _, err = cache.Open("key1", time.Now().UTC().Add(time.Hour)); err != ErrKeyNotFoundInCache {
+		t.Fatalf("Expected ErrKeyNotFoundInCache, got %v", err)
+	}
+	if _, ok := cache.disk.entries["key1"]; ok {
+		t.Fatal("Expected stale key1 to be removed from disk")
+	}
+
+	// Deleting an entry deletes it from disk as well.
+	if err = create("key11"); err != nil {
+		t.Fatal(err)
+	}
+	cache.Delete("key2")
+	if _, err = cache.Open("key2", time.Time{}); err != ErrKeyNotFoundInCache {
+		t.Fatalf("Expected ErrKeyNotFoundInCache, got %v", err)
+	}
+}
+
+// Tests expired entries are demoted to disk tier.
+func TestCacheDiskTierExpiry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objcache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := New(100, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.StopGC()
+	if err = cache.EnableDiskTier(dir, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := cache.Create("key", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("hello"))
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.entries["key"].lastAccessed = time.Now().UTC().Add(-2 * time.Hour)
+	cache.gc()
+	if _, ok := cache.entries["key"]; ok {
+		t.Fatal("Expected key to be expired")
+	}
+
+	// Entry survives a restart on disk.
+	if cache, err = New(100, NoExpiry); err != nil {
+		t.Fatal(err)
+	}
+	if err = cache.EnableDiskTier(dir, 100); err != nil {
+		t.Fatal(err)
+	}
+	r, err := cache.Open("key", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err = r.ReadAt(buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "hello" {
+		t.Fatalf("Unexpected value %q", buf)
+	}
+}
@@ -89,9 +89,16 @@ type Cache struct {
 	// map of objectName and its contents
 	entries map[string]*buffer
 
+	// map of objectName and its cached segments
+	segments map[string]*segments
+
 	// Expiry in time duration.
 	expiry time.Duration
 
+	// Optional disk tier, entries evicted from memory are
+	// saved here and promoted back to memory on access.
+	disk *diskTier
+
 	// Stop garbage collection routine, stops any running GC routine.
 	stopGC chan struct{}
 }
@@ -122,23 +129,12 @@ func New(maxSize uint64, expiry time.Duration) (c *Cache, err error) {
 	// Set this value to 40% if caching is enabled.
 	debug.SetGCPercent(defaultGCPercent)
 
-	// Max cache entry size - indicates the
-	// maximum buffer per key that can be held in
-	// memory. Currently this value is 1/10th
-	// the size of requested cache size.
-	maxCacheEntrySize := func() uint64 {
-		i := maxSize / defaultBufferRatio
-		if i == 0 {
-			i = maxSize
-		}
-		return i
-	}()
-
 	c = &Cache{
 		onceGC:            sync.Once{},
 		maxSize:           maxSize,
-		maxCacheEntrySize: maxCacheEntrySize,
+		maxCacheEntrySize: getMaxCacheEntrySize(maxSize),
 		entries:           make(map[string]*buffer),
+		segments:          make(map[string]*segments),
 		expiry:            expiry,
 	}
 
@@ -154,6 +150,84 @@ func New(maxSize uint64, expiry time.Duration) (c *Cache, err error) {
 	return c, nil
 }
 
+// Max cache entry size - indicates the maximum buffer
+// per key that can be held in memory. Currently this
+// value is 1/10th the size of requested cache size.
+func getMaxCacheEntrySize(maxSize uint64) uint64 {
+	i := maxSize / defaultBufferRatio
+	if i == 0 {
+		i = maxSize
+	}
+	return i
+}
+
+// SetMaxSize - resizes the cache, least recently accessed entries
+// are demoted to disk, if enabled, or evicted until the cache fits
+// in maxSize.
+func (c *Cache) SetMaxSize(maxSize uint64) {
+	var evictedEntries []string
+	c.mutex.Lock()
+	c.maxSize = maxSize
+	c.maxCacheEntrySize = getMaxCacheEntrySize(maxSize)
+	for c.currentSize > c.maxSize {
+		if oldestKey, oldest := c.oldest(); oldest != nil {
+			// Ignore error, entry is evicted from memory anyway.
+			if c.disk != nil {
+				c.disk.put(oldestKey, oldest.value, oldest.lastAccessed)
+			}
+			c.delete(oldestKey)
+			evictedEntries = append(evictedEntries, oldestKey)
+			continue
+		}
+		// Only segments of objects are left.
+		var oldestKey string
+		var oldest *segments
+		for k, v := range c.segments {
+			if oldest == nil || v.lastAccessed.Before(oldest.lastAccessed) {
+				oldestKey, oldest = k, v
+			}
+		}
+		if oldest == nil {
+			break
+		}
+		c.deleteSegments(oldestKey)
+	}
+	c.mutex.Unlock()
+	for _, k := range evictedEntries {
+		if c.OnEviction != nil {
+			c.OnEviction(k)
+		}
+	}
+}
+
+// MaxSize - returns the maximum size of the cache.
+func (c *Cache) MaxSize() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.maxSize
+}
+
+// Size - returns the size of the entries held in memory.
+func (c *Cache) Size() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.currentSize
+}
+
+// EnableDiskTier - enables a secondary cache tier saving entries evicted
+// from memory in dir, up to maxSize bytes. Entries saved in dir earlier
+// are loaded and can be promoted back to memory.
+func (c *Cache) EnableDiskTier(dir string, maxSize uint64) error {
+	disk, err := newDiskTier(dir, maxSize)
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	c.disk = disk
+	c.mutex.Unlock()
+	return nil
+}
+
 // Create - validates if object size fits with in cache size limit and returns a io.WriteCloser
 // to which object contents can be written and finally Close()'d. During Close() we
 // checks if the amount of data written is equal to the size of the object, in which
@@ -176,8 +250,9 @@ func (c *Cache) Create(key string, size int64) (w io.WriteCloser, err error) {
 
 	c.mutex.Lock()
 	// Check if the incoming size is going to exceed the
-	// effective cache size, if yes return error instead.
-	if c.currentSize+valueLen > c.maxSize {
+	// effective cache size, if yes and entries cannot be
+	// demoted to disk return error instead.
+	if c.currentSize+valueLen > c.maxSize && !c.demote(valueLen) {
 		c.mutex.Unlock()
 		return nil, ErrCacheFull
 	}
@@ -215,6 +290,11 @@ func (c *Cache) Create(key string, size int64) (w io.WriteCloser, err error) {
 
 		// Account for the memory allocated above.
 		c.currentSize += uint64(size)
+
+		// Older copy of the entry on disk is no longer valid.
+		if c.disk != nil {
+			c.disk.delete(key)
+		}
 		return nil
 	}
 
@@ -236,7 +316,7 @@ func (c *Cache) Open(key string, objModTime time.Time) (io.ReaderAt, error) {
 	defer c.mutex.Unlock()
 	buf, ok := c.entries[key]
 	if !ok {
-		return nil, ErrKeyNotFoundInCache
+		return c.promote(key, objModTime)
 	}
 
 	// Check if buf is recent copy of the object on disk.
@@ -253,6 +333,9 @@ func (c *Cache) Open(key string, objModTime time.Time) (io.ReaderAt, error) {
 func (c *Cache) Delete(key string) {
 	c.mutex.Lock()
 	c.delete(key)
+	if c.disk != nil {
+		c.disk.delete(key)
+	}
 	c.mutex.Unlock()
 	if c.OnEviction != nil {
 		c.OnEviction(key)
@@ -265,10 +348,20 @@ func (c *Cache) gc() {
 	c.mutex.Lock()
 	for k, v := range c.entries {
 		if c.expiry > 0 && time.Now().UTC().Sub(v.lastAccessed) > c.expiry {
+			// Expired entries are demoted to disk if enabled,
+			// ignore errors as entries are anyway evicted.
+			if c.disk != nil {
+				c.disk.put(k, v.value, v.lastAccessed)
+			}
 			c.delete(k)
 			evictedEntries = append(evictedEntries, k)
 		}
 	}
+	for k, v := range c.segments {
+		if c.expiry > 0 && time.Now().UTC().Sub(v.lastAccessed) > c.expiry {
+			c.deleteSegments(k)
+		}
+	}
 	c.mutex.Unlock()
 	for _, k := range evictedEntries {
 		if c.OnEviction != nil {
@@ -304,8 +397,9 @@ func (c *Cache) StartGC() {
 	}()
 }
 
-// Deletes a requested entry from the cache.
+// Deletes a requested entry along with its segments from the cache.
 func (c *Cache) delete(key string) {
+	c.deleteSegments(key)
 	if _, ok := c.entries[key]; ok {
 		deletedSize := uint64(len(c.entries[key].value))
 		delete(c.entries, key)
@@ -313,3 +407,64 @@ func (c *Cache) delete(key string) {
 		c.totalEvicted++
 	}
 }
+
+// Returns the least recently accessed entry, nil if the cache has
+// no entries. Must be called with mutex held.
+func (c *Cache) oldest() (oldestKey string, oldest *buffer) {
+	for k, v := range c.entries {
+		if oldest == nil || v.lastAccessed.Before(oldest.lastAccessed) {
+			oldestKey, oldest = k, v
+		}
+	}
+	return oldestKey, oldest
+}
+
+// Demotes least recently accessed entries to disk until size bytes
+// fit in memory, returns false if disk tier is not enabled or enough
+// memory cannot be freed. Must be called with mutex held.
+func (c *Cache) demote(size uint64) bool {
+	if c.disk == nil {
+		return false
+	}
+	for c.currentSize+size > c.maxSize {
+		oldestKey, oldest := c.oldest()
+		if oldest == nil {
+			return false
+		}
+		// Ignore error, entry is evicted from memory anyway.
+		c.disk.put(oldestKey, oldest.value, oldest.lastAccessed)
+		c.delete(oldestKey)
+	}
+	return true
+}
+
+// Promotes an entry saved on disk back to memory, the entry is served
+// from disk if it does not fit in memory. Returns ErrKeyNotFoundInCache
+// if entry is not on disk or is older than objModTime. Must be called
+// with mutex held.
+func (c *Cache) promote(key string, objModTime time.Time) (io.ReaderAt, error) {
+	if c.disk == nil {
+		return nil, ErrKeyNotFoundInCache
+	}
+	value, lastAccessed, err := c.disk.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if value is recent copy of the object on disk.
+	if lastAccessed.Before(objModTime) {
+		c.disk.delete(key)
+		return nil, ErrKeyNotFoundInCache
+	}
+
+	valueLen := uint64(len(value))
+	if valueLen <= c.maxCacheEntrySize && (c.currentSize+valueLen <= c.maxSize || c.demote(valueLen)) {
+		c.disk.delete(key)
+		c.entries[key] = &buffer{
+			value:        value,
+			lastAccessed: time.Now().UTC(),
+		}
+		c.currentSize += valueLen
+	}
+	return bytes.NewReader(value), nil
+}
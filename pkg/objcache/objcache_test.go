@@ -324,3 +324,51 @@ func TestStaleEntryPurge(t *testing.T) {
 		t.Errorf("Test case expected to return ErrKeyNotFoundInCache, instead returned %s", err)
 	}
 }
+
+// TestSetMaxSize - tests resizing the cache evicts least recently
+// accessed entries.
+func TestSetMaxSize(t *testing.T) {
+	cache, err := New(1024, NoExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evicted []string
+	cache.OnEviction = func(key string) {
+		evicted = append(evicted, key)
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		w, err := cache.Create(key, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(bytes.Repeat([]byte("x"), 100)); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Access "a" so that "b" is the least recently accessed entry.
+	if _, err = cache.Open("a", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.SetMaxSize(250)
+	if cache.MaxSize() != 250 || cache.Size() != 200 {
+		t.Errorf("Expected cache of 200 bytes out of 250, got %d out of %d", cache.Size(), cache.MaxSize())
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("Expected entry b to be evicted, got %v", evicted)
+	}
+	// Entries larger than 1/10th of the cache are not cached anymore.
+	if _, err = cache.Create("d", 30); err != ErrCacheFull {
+		t.Errorf("Expected %s, got %v", ErrCacheFull, err)
+	}
+
+	cache.SetMaxSize(2048)
+	if cache.Size() != 200 || len(evicted) != 1 {
+		t.Errorf("Expected no eviction when growing the cache, got %v", evicted)
+	}
+}
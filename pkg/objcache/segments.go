@@ -0,0 +1,101 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package objcache
+
+import "time"
+
+// segments represents the in memory cache of a subset of fixed size
+// segments of a single entry, indexed by the segment number.
+type segments struct {
+	values       map[int64][]byte // Cached segments of the entry.
+	lastAccessed time.Time        // Represents time when any segment was last accessed.
+}
+
+// size - returns total size of all cached segments.
+func (s *segments) size() (size uint64) {
+	for _, value := range s.values {
+		size += uint64(len(value))
+	}
+	return size
+}
+
+// PutSegment - saves a segment of an entry at the given index, segments
+// allow range reads to be served partially from the cache. Returns
+// ErrCacheFull if the segment does not fit in the cache. The data must
+// not be modified by the caller after it is saved.
+func (c *Cache) PutSegment(key string, index int64, data []byte) error {
+	valueLen := uint64(len(data))
+	if valueLen > c.maxCacheEntrySize {
+		return ErrCacheFull
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, ok := c.segments[key]
+	if !ok {
+		s = &segments{values: make(map[int64][]byte)}
+		c.segments[key] = s
+	}
+
+	// Account for the segment being replaced, if any.
+	oldLen := uint64(len(s.values[index]))
+	if c.currentSize-oldLen+valueLen > c.maxSize {
+		return ErrCacheFull
+	}
+
+	s.values[index] = data
+	s.lastAccessed = time.Now().UTC()
+	c.currentSize = c.currentSize - oldLen + valueLen
+	return nil
+}
+
+// GetSegment - returns a previously saved segment of an entry at the
+// given index. Returns ErrKeyNotFoundInCache if the segment does not
+// exist or if the segments were saved before objModTime, in which
+// case all stale segments of the entry are dropped.
+func (c *Cache) GetSegment(key string, objModTime time.Time, index int64) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.segments[key]
+	if !ok {
+		return nil, ErrKeyNotFoundInCache
+	}
+
+	// Check if segments are recent copy of the object on disk.
+	if s.lastAccessed.Before(objModTime) {
+		c.deleteSegments(key)
+		return nil, ErrKeyNotFoundInCache
+	}
+
+	value, ok := s.values[index]
+	if !ok {
+		return nil, ErrKeyNotFoundInCache
+	}
+
+	s.lastAccessed = time.Now().UTC()
+	return value, nil
+}
+
+// Deletes all segments of a requested entry from the cache.
+func (c *Cache) deleteSegments(key string) {
+	if s, ok := c.segments[key]; ok {
+		c.currentSize -= s.size()
+		delete(c.segments, key)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package objcache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Tests saving and reading segments of an entry.
+func TestSegments(t *testing.T) {
+	cache, err := New(100, NoExpiry)
+	if err != nil {
+		t.Fatalf("Unable to create new objcache")
+	}
+
+	testCases := []struct {
+		key         string
+		index       int64
+		data        []byte
+		expectedErr error
+		currentSize uint64
+	}{
+		// Test case - 1, save first segment.
+		{"test", 0, []byte("Hello"), nil, 5},
+		// Test case - 2, save another segment.
+		{"test", 2, []byte("World"), nil, 10},
+		// Test case - 3, replace an existing segment.
+		{"test", 2, []byte("Worlds"), nil, 11},
+		// Test case - 4, segment larger than max entry size.
+		{"test", 3, bytes.Repeat([]byte("a"), 11), ErrCacheFull, 11},
+		// Test case - 5, segment of a different entry.
+		{"other", 0, []byte("Minio"), nil, 16},
+	}
+	for i, testCase := range testCases {
+		err = cache.PutSegment(testCase.key, testCase.index, testCase.data)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if cache.currentSize != testCase.currentSize {
+			t.Fatalf("Test %d: expected current size %d, got %d", i+1, testCase.currentSize, cache.currentSize)
+		}
+	}
+
+	readCases := []struct {
+		key         string
+		index       int64
+		expected    []byte
+		expectedErr error
+	}{
+		{"test", 0, []byte("Hello"), nil},
+		{"test", 1, nil, ErrKeyNotFoundInCache},
+		{"test", 2, []byte("Worlds"), nil},
+		{"other", 0, []byte("Minio"), nil},
+		{"missing", 0, nil, ErrKeyNotFoundInCache},
+	}
+	for i, testCase := range readCases {
+		data, err := cache.GetSegment(testCase.key, time.Time{}, testCase.index)
+		if err != testCase.expectedErr {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if !bytes.Equal(data, testCase.expected) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, data)
+		}
+	}
+
+	// Deleting the entry drops all its segments.
+	cache.Delete("test")
+	if _, err = cache.GetSegment("test", time.Time{}, 0); err != ErrKeyNotFoundInCache {
+		t.Fatalf("Expected ErrKeyNotFoundInCache, got %v", err)
+	}
+	if cache.currentSize != 5 {
+		t.Fatalf("Expected current size 5, got %d", cache.currentSize)
+	}
+}
+
+// Tests segments are not saved beyond the maximum cache size.
+func TestSegmentsCacheFull(t *testing.T) {
+	cache, err := New(20, NoExpiry)
+	if err != nil {
+		t.Fatalf("Unable to create new objcache")
+	}
+	// Max entry size is 2 bytes, fill up the cache.
+	for i := int64(0); i < 10; i++ {
+		if err = cache.PutSegment("test", i, []byte("ab")); err != nil {
+			t.Fatalf("Segment %d: expected to pass, failed with %s", i, err)
+		}
+	}
+	if err = cache.PutSegment("test", 10, []byte("ab")); err != ErrCacheFull {
+		t.Fatalf("Expected ErrCacheFull, got %v", err)
+	}
+	// Replacing an existing segment of same size still fits.
+	if err = cache.PutSegment("test", 0, []byte("cd")); err != nil {
+		t.Fatalf("Expected to pass, failed with %s", err)
+	}
+}
+
+// Tests stale segments are purged.
+func TestStaleSegmentsPurge(t *testing.T) {
+	cache, err := New(1024, NoExpiry)
+	if err != nil {
+		t.Fatalf("Unable to create new objcache")
+	}
+	if err = cache.PutSegment("test", 0, []byte("Hello")); err != nil {
+		t.Fatalf("Expected to pass, failed with %s", err)
+	}
+	_, err = cache.GetSegment("test", time.Now().AddDate(0, 0, 1).UTC(), 0)
+	if err != ErrKeyNotFoundInCache {
+		t.Fatalf("Expected ErrKeyNotFoundInCache, got %v", err)
+	}
+	if cache.currentSize != 0 {
+		t.Fatalf("Expected current size 0, got %d", cache.currentSize)
+	}
+}